@@ -15,11 +15,13 @@ import (
 	"github.com/netdata/go.d.plugin/agent/confgroup"
 	"github.com/netdata/go.d.plugin/agent/discovery"
 	"github.com/netdata/go.d.plugin/agent/filelock"
+	"github.com/netdata/go.d.plugin/agent/filestate"
 	"github.com/netdata/go.d.plugin/agent/filestatus"
 	"github.com/netdata/go.d.plugin/agent/functions"
 	"github.com/netdata/go.d.plugin/agent/jobmgr"
 	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/netdata/go.d.plugin/agent/netdataapi"
+	"github.com/netdata/go.d.plugin/agent/pluginstats"
 	"github.com/netdata/go.d.plugin/agent/safewriter"
 	"github.com/netdata/go.d.plugin/agent/vnodes"
 	"github.com/netdata/go.d.plugin/logger"
@@ -38,9 +40,12 @@ type Config struct {
 	ModulesSDConfPath []string
 	VnodesConfDir     []string
 	StateFile         string
+	JobStateFile      string
 	LockDir           string
 	ModuleRegistry    module.Registry
 	RunModule         string
+	RunJob            string
+	DumpCharts        bool
 	MinUpdateEvery    int
 }
 
@@ -54,13 +59,18 @@ type Agent struct {
 	ModulesSDConfPath []string
 	VnodesConfDir     multipath.MultiPath
 	StateFile         string
+	JobStateFile      string
 	LockDir           string
 	RunModule         string
+	RunJob            string
+	DumpCharts        bool
 	MinUpdateEvery    int
 	ModuleRegistry    module.Registry
 	Out               io.Writer
 
 	api *netdataapi.API
+	// reloadCh triggers re-reading of the static job configs of the running instance
+	reloadCh chan struct{}
 }
 
 // New creates a new Agent.
@@ -75,12 +85,16 @@ func New(cfg Config) *Agent {
 		ModulesSDConfPath: cfg.ModulesSDConfPath,
 		VnodesConfDir:     cfg.VnodesConfDir,
 		StateFile:         cfg.StateFile,
+		JobStateFile:      cfg.JobStateFile,
 		LockDir:           cfg.LockDir,
 		RunModule:         cfg.RunModule,
+		RunJob:            cfg.RunJob,
+		DumpCharts:        cfg.DumpCharts,
 		MinUpdateEvery:    cfg.MinUpdateEvery,
 		ModuleRegistry:    module.DefaultRegistry,
 		Out:               safewriter.Stdout,
 		api:               netdataapi.New(safewriter.Stdout),
+		reloadCh:          make(chan struct{}, 1),
 	}
 }
 
@@ -95,49 +109,72 @@ func serve(a *Agent) {
 	signal.Notify(ch, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
 	var wg sync.WaitGroup
 
-	var exit bool
-	var reload bool
+	ctx, cancel := context.WithCancel(context.Background())
 
-	for {
-		ctx, cancel := context.WithCancel(context.Background())
-		ctx = context.WithValue(ctx, "reload", reload)
+	wg.Add(1)
+	go func() { defer wg.Done(); a.run(ctx) }()
 
-		wg.Add(1)
-		go func() { defer wg.Done(); a.run(ctx) }()
-
-		switch sig := <-ch; sig {
-		case syscall.SIGHUP:
-			a.Infof("received %s signal (%d). Restarting running instance", sig, sig)
-		default:
-			a.Infof("received %s signal (%d). Terminating...", sig, sig)
-			module.DontObsoleteCharts()
-			exit = true
+	for sig := range ch {
+		if sig == syscall.SIGHUP {
+			a.Infof("received %s signal (%d). Reloading configs", sig, sig)
+			a.triggerReload()
+			continue
 		}
+		a.Infof("received %s signal (%d). Terminating...", sig, sig)
+		module.DontObsoleteCharts()
+		break
+	}
+
+	cancel()
+
+	timeout := time.Second * 10
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	done := make(chan struct{})
+
+	go func() { wg.Wait(); close(done) }()
 
-		cancel()
+	select {
+	case <-t.C:
+		a.Errorf("stopping all goroutines timed out after %s. Exiting...", timeout)
+	case <-done:
+	}
 
-		func() {
-			timeout := time.Second * 10
-			t := time.NewTimer(timeout)
-			defer t.Stop()
-			done := make(chan struct{})
+	os.Exit(0)
+}
 
-			go func() { wg.Wait(); close(done) }()
+func (a *Agent) triggerReload() {
+	select {
+	case a.reloadCh <- struct{}{}:
+	default:
+	}
+}
 
-			select {
-			case <-t.C:
-				a.Errorf("stopping all goroutines timed out after %s. Exiting...", timeout)
-				os.Exit(0)
-			case <-done:
+// runReloads re-reads the plugin config and the static job configs on every reload request, the running jobs
+// with unchanged configs are not affected. Only the enabled modules set is reloaded from the plugin config,
+// the rest of the plugin options need a restart.
+func (a *Agent) runReloads(ctx context.Context, discoveryManager *discovery.Manager, startCfg config) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.reloadCh:
+			a.Info("reloading plugin config and static job configs")
+
+			cfg := a.loadPluginConfig()
+			if opts := restartRequiredOptions(startCfg, cfg); len(opts) > 0 {
+				a.Warningf("reload: changed plugin options %v are applied after the plugin restart", opts)
+			}
+			if !cfg.Enabled {
+				a.Warning("reload: the plugin is disabled in the configuration file, keeping the running jobs until restart")
+				continue
 			}
-		}()
 
-		if exit {
-			os.Exit(0)
+			enabled := a.loadEnabledModules(cfg)
+			if err := discoveryManager.Reload(ctx, a.buildDiscoveryConf(enabled)); err != nil {
+				a.Errorf("reload: %v", err)
+			}
 		}
-
-		reload = true
-		time.Sleep(time.Second)
 	}
 }
 
@@ -183,7 +220,24 @@ func (a *Agent) run(ctx context.Context) {
 	jobsManager := jobmgr.NewManager()
 	jobsManager.PluginName = a.Name
 	jobsManager.Out = a.Out
-	jobsManager.Modules = enabledModules
+	// the configs come from the enabled modules only, any runnable module is allowed so a reload can enable modules
+	jobsManager.Modules = a.loadRunnableModules()
+	jobsManager.CheckWorkers = cfg.CheckWorkers
+	jobsManager.StrictConfig = cfg.StrictConfig
+	jobsManager.MinUpdateEvery = a.MinUpdateEvery
+	jobsManager.API = a.api
+	jobsManager.RegisterFunctions(functionsManager)
+	if cfg.PluginStats.Enabled {
+		jobsManager.Stats = pluginstats.New(pluginstats.Config{
+			DimensionIdleTimeout: time.Duration(cfg.PluginStats.DimensionIdleTimeout) * time.Second,
+		})
+	}
+
+	if sdDiscovery := a.setupServiceDiscovery(); sdDiscovery != nil {
+		sdDiscovery.API = a.api
+		sdDiscovery.RegisterFunctions(functionsManager)
+		discoveryManager.Add(sdDiscovery)
+	}
 
 	// TODO: API will be changed in https://github.com/netdata/netdata/pull/16702
 	//if logger.Level.Enabled(slog.LevelDebug) {
@@ -221,6 +275,18 @@ func (a *Agent) run(ctx context.Context) {
 		}
 	}
 
+	var jobStateManager *filestate.Manager
+	if !isTerminal && a.JobStateFile != "" && cfg.JobState.Enabled {
+		jobStateManager = filestate.NewManager(a.JobStateFile)
+		jobsManager.JobStateSaver = jobStateManager
+		maxAge := time.Duration(cfg.JobState.MaxAge) * time.Hour
+		if store, err := filestate.LoadStore(a.JobStateFile, maxAge); err != nil {
+			a.Warningf("couldn't load job state file: %v", err)
+		} else {
+			jobsManager.JobStateStore = store
+		}
+	}
+
 	in := make(chan []*confgroup.Group)
 	var wg sync.WaitGroup
 
@@ -233,11 +299,19 @@ func (a *Agent) run(ctx context.Context) {
 	wg.Add(1)
 	go func() { defer wg.Done(); discoveryManager.Run(ctx, in) }()
 
+	wg.Add(1)
+	go func() { defer wg.Done(); a.runReloads(ctx, discoveryManager, cfg) }()
+
 	if statusSaveManager != nil {
 		wg.Add(1)
 		go func() { defer wg.Done(); statusSaveManager.Run(ctx) }()
 	}
 
+	if jobStateManager != nil {
+		wg.Add(1)
+		go func() { defer wg.Done(); jobStateManager.Run(ctx) }()
+	}
+
 	wg.Wait()
 	<-ctx.Done()
 }
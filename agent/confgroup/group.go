@@ -39,6 +39,9 @@ func (c Config) Source() string          { v, _ := c.get("__source__").(string);
 func (c Config) Provider() string        { v, _ := c.get("__provider__").(string); return v }
 func (c Config) Vnode() string           { v, _ := c.get("vnode").(string); return v }
 
+func (c Config) VnodeGUID() string        { v, _ := c.get("vnode_guid").(string); return v }
+func (c Config) VnodeLabels() map[any]any { v, _ := c.get("vnode_labels").(map[any]any); return v }
+
 func (c Config) SetName(v string)     { c.set("name", v) }
 func (c Config) SetModule(v string)   { c.set("module", v) }
 func (c Config) SetSource(v string)   { c.set("__source__", v) }
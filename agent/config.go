@@ -10,23 +10,70 @@ import (
 
 func defaultConfig() config {
 	return config{
-		Enabled:    true,
-		DefaultRun: true,
-		MaxProcs:   0,
-		Modules:    nil,
+		Enabled:      true,
+		DefaultRun:   true,
+		MaxProcs:     0,
+		StrictConfig: true,
+		Modules:      nil,
+		PluginStats: pluginStatsConfig{
+			Enabled:              true,
+			DimensionIdleTimeout: 600,
+		},
+		JobState: jobStateConfig{
+			Enabled: false,
+			MaxAge:  24,
+		},
 	}
 }
 
-type config struct {
-	Enabled    bool            `yaml:"enabled"`
-	DefaultRun bool            `yaml:"default_run"`
-	MaxProcs   int             `yaml:"max_procs"`
-	Modules    map[string]bool `yaml:"modules"`
-}
+type (
+	config struct {
+		Enabled      bool              `yaml:"enabled"`
+		DefaultRun   bool              `yaml:"default_run"`
+		MaxProcs     int               `yaml:"max_procs"`
+		CheckWorkers int               `yaml:"check_workers"`
+		StrictConfig bool              `yaml:"strict_config"`
+		Modules      map[string]bool   `yaml:"modules"`
+		PluginStats  pluginStatsConfig `yaml:"plugin_stats"`
+		JobState     jobStateConfig    `yaml:"job_state"`
+	}
+	pluginStatsConfig struct {
+		Enabled              bool `yaml:"enabled"`
+		DimensionIdleTimeout int  `yaml:"dimension_idle_timeout"` // seconds
+	}
+	jobStateConfig struct {
+		Enabled bool `yaml:"enabled"`
+		MaxAge  int  `yaml:"max_age"` // hours
+	}
+)
 
 func (c *config) String() string {
-	return fmt.Sprintf("enabled '%v', default_run '%v', max_procs '%d'",
-		c.Enabled, c.DefaultRun, c.MaxProcs)
+	return fmt.Sprintf("enabled '%v', default_run '%v', max_procs '%d', check_workers '%d', strict_config '%v'",
+		c.Enabled, c.DefaultRun, c.MaxProcs, c.CheckWorkers, c.StrictConfig)
+}
+
+// restartRequiredOptions returns the changed plugin options a reload doesn't apply.
+func restartRequiredOptions(prev, cur config) []string {
+	var opts []string
+	if prev.Enabled != cur.Enabled {
+		opts = append(opts, "enabled")
+	}
+	if prev.MaxProcs != cur.MaxProcs {
+		opts = append(opts, "max_procs")
+	}
+	if prev.CheckWorkers != cur.CheckWorkers {
+		opts = append(opts, "check_workers")
+	}
+	if prev.StrictConfig != cur.StrictConfig {
+		opts = append(opts, "strict_config")
+	}
+	if prev.PluginStats != cur.PluginStats {
+		opts = append(opts, "plugin_stats")
+	}
+	if prev.JobState != cur.JobState {
+		opts = append(opts, "job_state")
+	}
+	return opts
 }
 
 func (c *config) isExplicitlyEnabled(moduleName string) bool {
@@ -60,7 +107,7 @@ func (c *config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	for key, value := range m {
 		switch key {
-		case "enabled", "default_run", "max_procs", "modules":
+		case "enabled", "default_run", "max_procs", "check_workers", "strict_config", "modules", "plugin_stats", "job_state":
 			continue
 		}
 		var b bool
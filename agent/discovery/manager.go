@@ -30,6 +30,7 @@ func NewManager(cfg Config) (*Manager, error) {
 		discoverers: make([]discoverer, 0),
 		mux:         &sync.RWMutex{},
 		cache:       newCache(),
+		static:      make(map[string]*confgroup.Group),
 	}
 
 	if err := mgr.registerDiscoverers(cfg); err != nil {
@@ -46,10 +47,13 @@ type discoverer interface {
 type Manager struct {
 	*logger.Logger
 	discoverers []discoverer
-	send        chan struct{}
-	sendEvery   time.Duration
-	mux         *sync.RWMutex
-	cache       *cache
+	// staticDiscoverers read the job configs once (the file reader and the dummy discovery), they are re-run on reload
+	staticDiscoverers []discoverer
+	send              chan struct{}
+	sendEvery         time.Duration
+	mux               *sync.RWMutex
+	cache             *cache
+	static            map[string]*confgroup.Group // [source], the last sent groups of the static discoverers
 }
 
 func (m *Manager) String() string {
@@ -70,7 +74,14 @@ func (m *Manager) Run(ctx context.Context, in chan<- []*confgroup.Group) {
 		wg.Add(1)
 		go func(d discoverer) {
 			defer wg.Done()
-			m.runDiscoverer(ctx, d)
+			m.runDiscoverer(ctx, d, false)
+		}(d)
+	}
+	for _, d := range m.staticDiscoverers {
+		wg.Add(1)
+		go func(d discoverer) {
+			defer wg.Done()
+			m.runDiscoverer(ctx, d, true)
 		}(d)
 	}
 
@@ -85,8 +96,9 @@ func (m *Manager) Run(ctx context.Context, in chan<- []*confgroup.Group) {
 }
 
 func (m *Manager) registerDiscoverers(cfg Config) error {
-	if len(cfg.File.Read) > 0 || len(cfg.File.Watch) > 0 {
+	if len(cfg.File.Watch) > 0 {
 		cfg.File.Registry = cfg.Registry
+		cfg.File.Read = nil
 		d, err := file.NewDiscovery(cfg.File)
 		if err != nil {
 			return err
@@ -94,24 +106,40 @@ func (m *Manager) registerDiscoverers(cfg Config) error {
 		m.Add(d)
 	}
 
+	static, err := newStaticDiscoverers(cfg)
+	if err != nil {
+		return err
+	}
+	m.staticDiscoverers = static
+
+	if len(m.discoverers)+len(m.staticDiscoverers) == 0 {
+		return errors.New("zero registered discoverers")
+	}
+
+	m.Infof("registered discoverers: %v %v", m.staticDiscoverers, m.discoverers)
+	return nil
+}
+
+func newStaticDiscoverers(cfg Config) ([]discoverer, error) {
+	var static []discoverer
+
+	if len(cfg.File.Read) > 0 {
+		static = append(static, file.NewReader(cfg.Registry, cfg.File.Read))
+	}
+
 	if len(cfg.Dummy.Names) > 0 {
 		cfg.Dummy.Registry = cfg.Registry
 		d, err := dummy.NewDiscovery(cfg.Dummy)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		m.Add(d)
+		static = append(static, d)
 	}
 
-	if len(m.discoverers) == 0 {
-		return errors.New("zero registered discoverers")
-	}
-
-	m.Infof("registered discoverers: %v", m.discoverers)
-	return nil
+	return static, nil
 }
 
-func (m *Manager) runDiscoverer(ctx context.Context, d discoverer) {
+func (m *Manager) runDiscoverer(ctx context.Context, d discoverer, static bool) {
 	updates := make(chan []*confgroup.Group)
 	go d.Run(ctx, updates)
 
@@ -128,6 +156,9 @@ func (m *Manager) runDiscoverer(ctx context.Context, d discoverer) {
 				defer m.mux.Unlock()
 
 				m.cache.update(groups)
+				if static {
+					m.updateStatic(groups)
+				}
 				m.triggerSend()
 			}()
 		}
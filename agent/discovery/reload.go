@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+)
+
+// Reload re-reads the static job configs (the file reader and the dummy discovery) and sends the changed groups.
+// The job manager diffs the groups by the config hash: unchanged jobs keep running, changed jobs are restarted,
+// removed jobs are stopped and new jobs are started. The configs of the watched files are not affected.
+// A config file that fails to parse keeps its previous jobs.
+func (m *Manager) Reload(ctx context.Context, cfg Config) error {
+	if err := validateConfig(cfg); err != nil {
+		return fmt.Errorf("discovery manager config validation: %v", err)
+	}
+
+	static, err := newStaticDiscoverers(cfg)
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[string]*confgroup.Group)
+	for _, d := range static {
+		for _, group := range readGroups(ctx, d) {
+			if group != nil {
+				groups[group.Source] = group
+			}
+		}
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	var changed []*confgroup.Group
+	var stats reloadStats
+
+	for source, prev := range m.static {
+		if _, ok := groups[source]; ok {
+			continue
+		}
+		if matchesAny(source, cfg.File.Read) {
+			m.Warningf("reload: couldn't read '%s', keeping its jobs", source)
+			groups[source] = prev
+			continue
+		}
+		stats.removed += len(prev.Configs)
+		changed = append(changed, &confgroup.Group{Source: source})
+	}
+
+	for source, group := range groups {
+		prev, ok := m.static[source]
+		if ok && prev == group {
+			continue
+		}
+		if stats.update(prev, group) {
+			changed = append(changed, group)
+		}
+	}
+
+	m.Infof("reload: static job configs added %d, changed %d, removed %d, unchanged %d",
+		stats.added, stats.changed, stats.removed, stats.unchanged)
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	m.cache.update(changed)
+	m.updateStatic(changed)
+	m.triggerSend()
+
+	return nil
+}
+
+// updateStatic remembers the groups sent by the static discoverers, must be called with the mux locked.
+func (m *Manager) updateStatic(groups []*confgroup.Group) {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		if len(group.Configs) == 0 {
+			delete(m.static, group.Source)
+		} else {
+			m.static[group.Source] = group
+		}
+	}
+}
+
+type reloadStats struct {
+	added, changed, removed, unchanged int
+}
+
+// update counts the jobs difference of the group revisions, it reports whether the group has changed.
+func (s *reloadStats) update(prev, group *confgroup.Group) bool {
+	prevJobs := make(map[string]uint64)
+	if prev != nil {
+		for _, cfg := range prev.Configs {
+			prevJobs[jobKey(cfg)] = cfg.Hash()
+		}
+	}
+
+	var changed bool
+	for _, cfg := range group.Configs {
+		hash, ok := prevJobs[jobKey(cfg)]
+		delete(prevJobs, jobKey(cfg))
+		switch {
+		case !ok:
+			s.added++
+			changed = true
+		case hash != cfg.Hash():
+			s.changed++
+			changed = true
+		default:
+			s.unchanged++
+		}
+	}
+
+	if len(prevJobs) > 0 {
+		s.removed += len(prevJobs)
+		changed = true
+	}
+
+	return changed
+}
+
+func readGroups(ctx context.Context, d discoverer) []*confgroup.Group {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan []*confgroup.Group)
+	go d.Run(ctx, updates)
+
+	var groups []*confgroup.Group
+	for {
+		select {
+		case <-ctx.Done():
+			return groups
+		case v, ok := <-updates:
+			if !ok {
+				return groups
+			}
+			groups = append(groups, v...)
+		}
+	}
+}
+
+func matchesAny(path string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func jobKey(cfg confgroup.Config) string {
+	return cfg.Module() + "/" + cfg.Name()
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/discovery/dummy"
+	"github.com/netdata/go.d.plugin/agent/discovery/file"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Reload(t *testing.T) {
+	const (
+		module1Conf = "jobs:\n  - name: job1\n    url: http://127.0.0.1:80\n  - name: job2\n    url: http://127.0.0.1:81\n"
+		module2Conf = "jobs:\n  - name: job1\n    url: http://127.0.0.1:82\n"
+	)
+
+	tests := map[string]struct {
+		update     func(t *testing.T, dir string) Config
+		wantGroups map[string][]string // [source base name][]job url
+	}{
+		"nothing changed": {
+			update: func(t *testing.T, dir string) Config { return prepareReloadConfig(dir) },
+		},
+		"job target changed": {
+			update: func(t *testing.T, dir string) Config {
+				writeConf(t, dir, "module1.conf", "jobs:\n  - name: job1\n    url: http://127.0.0.1:80\n  - name: job2\n    url: http://127.0.0.1:8081\n")
+				return prepareReloadConfig(dir)
+			},
+			wantGroups: map[string][]string{
+				"module1.conf": {"http://127.0.0.1:80", "http://127.0.0.1:8081"},
+			},
+		},
+		"job removed": {
+			update: func(t *testing.T, dir string) Config {
+				writeConf(t, dir, "module2.conf", "jobs: []\n")
+				return prepareReloadConfig(dir)
+			},
+			wantGroups: map[string][]string{
+				"module2.conf": nil,
+			},
+		},
+		"config file removed": {
+			update: func(t *testing.T, dir string) Config {
+				require.NoError(t, os.Remove(filepath.Join(dir, "module2.conf")))
+				cfg := prepareReloadConfig(dir)
+				cfg.File.Read = cfg.File.Read[:1]
+				cfg.Dummy = dummy.Config{Names: []string{"module2"}}
+				return cfg
+			},
+			wantGroups: map[string][]string{
+				"module2.conf": nil,
+				"module2":      {""},
+			},
+		},
+		"config file fails to parse": {
+			update: func(t *testing.T, dir string) Config {
+				writeConf(t, dir, "module2.conf", "jobs:\n  - name: [job1\n")
+				return prepareReloadConfig(dir)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConf(t, dir, "module1.conf", module1Conf)
+			writeConf(t, dir, "module2.conf", module2Conf)
+
+			mgr, err := NewManager(prepareReloadConfig(dir))
+			require.NoError(t, err)
+			mgr.sendEvery = time.Millisecond * 100
+
+			in := make(chan []*confgroup.Group)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go mgr.Run(ctx, in)
+
+			groups := <-in
+			require.Len(t, groups, 2)
+
+			require.NoError(t, mgr.Reload(ctx, test.update(t, dir)))
+
+			got := make(map[string][]string)
+			select {
+			case groups := <-in:
+				for _, group := range groups {
+					var urls []string
+					for _, cfg := range group.Configs {
+						url, _ := cfg["url"].(string)
+						urls = append(urls, url)
+					}
+					got[filepath.Base(group.Source)] = urls
+				}
+			case <-time.After(time.Second):
+			}
+
+			if len(test.wantGroups) == 0 {
+				assert.Empty(t, got)
+			} else {
+				assert.Equal(t, test.wantGroups, got)
+			}
+		})
+	}
+}
+
+func prepareReloadConfig(dir string) Config {
+	return Config{
+		Registry: confgroup.Registry{"module1": confgroup.Default{}, "module2": confgroup.Default{}},
+		File: file.Config{
+			Read: []string{filepath.Join(dir, "module1.conf"), filepath.Join(dir, "module2.conf")},
+		},
+	}
+}
+
+func writeConf(t *testing.T, dir, name, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
@@ -4,7 +4,14 @@ package sd
 
 import (
 	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/netdata/go.d.plugin/logger"
+
+	"github.com/fsnotify/fsnotify"
 	"github.com/ilyam8/hashstructure"
 )
 
@@ -22,3 +29,143 @@ func (c *ConfigFile) Hash() uint64 {
 	h, _ := hashstructure.Hash(c, nil)
 	return h
 }
+
+var confFilePatterns = []string{"*.conf", "*.yaml", "*.yml"}
+
+func NewDirConfigFileProvider(dir string) *DirConfigFileProvider {
+	return &DirConfigFileProvider{
+		Logger: logger.New().With(
+			slog.String("component", "discovery sd conf files"),
+		),
+		dir:      dir,
+		debounce: time.Second,
+		ch:       make(chan ConfigFile),
+		cache:    make(map[string]uint64),
+	}
+}
+
+// DirConfigFileProvider sends the pipeline config files of the directory: all on start, then the changed and
+// the removed ones (with empty Data). Events are coalesced, editors write a file several times (temp files, renames).
+type DirConfigFileProvider struct {
+	*logger.Logger
+
+	dir      string
+	debounce time.Duration
+	ch       chan ConfigFile
+	cache    map[string]uint64 // [path]ConfigFile hash
+}
+
+func (p *DirConfigFileProvider) Configs() chan ConfigFile {
+	return p.ch
+}
+
+func (p *DirConfigFileProvider) Run(ctx context.Context) {
+	p.Info("instance is started")
+	defer p.Info("instance is stopped")
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		p.Errorf("fsnotify watcher initialization: %v", err)
+		p.refresh(ctx)
+		return
+	}
+	defer func() { _ = watcher.Close() }()
+
+	if err := watcher.Add(p.dir); err != nil {
+		p.Errorf("start watching '%s': %v", p.dir, err)
+	}
+
+	p.refresh(ctx)
+
+	var debounce <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Name == "" || event.Op == fsnotify.Chmod || !isConfFile(event.Name) {
+				continue
+			}
+			debounce = time.After(p.debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			p.Warningf("watch: %v", err)
+		case <-debounce:
+			debounce = nil
+			p.refresh(ctx)
+		}
+	}
+}
+
+func (p *DirConfigFileProvider) refresh(ctx context.Context) {
+	seen := make(map[string]bool)
+
+	for _, path := range p.listFiles() {
+		fi, err := os.Stat(path)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			p.Warningf("read '%s': %v", path, err)
+			continue
+		}
+		seen[path] = true
+
+		cf := ConfigFile{Source: path, Data: bs}
+		if hash, ok := p.cache[path]; ok && hash == cf.Hash() {
+			continue
+		}
+		p.cache[path] = cf.Hash()
+
+		if !p.send(ctx, cf) {
+			return
+		}
+	}
+
+	for path := range p.cache {
+		if seen[path] {
+			continue
+		}
+		delete(p.cache, path)
+
+		if !p.send(ctx, ConfigFile{Source: path}) {
+			return
+		}
+	}
+}
+
+func (p *DirConfigFileProvider) listFiles() (files []string) {
+	for _, pattern := range confFilePatterns {
+		if matches, err := filepath.Glob(filepath.Join(p.dir, pattern)); err == nil {
+			files = append(files, matches...)
+		}
+	}
+	return files
+}
+
+func (p *DirConfigFileProvider) send(ctx context.Context, cf ConfigFile) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case p.ch <- cf:
+		return true
+	}
+}
+
+func isConfFile(path string) bool {
+	name := filepath.Base(path)
+	for _, pattern := range confFilePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package sd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirConfigFileProvider_Run(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "k8s.conf")
+	require.NoError(t, os.WriteFile(file, []byte("name: v1"), 0644))
+
+	prov := NewDirConfigFileProvider(dir)
+	prov.debounce = time.Millisecond * 200
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() { defer close(done); prov.Run(ctx) }()
+
+	assert.Equal(t, ConfigFile{Source: file, Data: []byte("name: v1")}, receiveConfigFile(t, prov))
+
+	// an editor saving the file several times
+	for _, data := range []string{"name: v2", "name: v3", "name: v4"} {
+		require.NoError(t, os.WriteFile(file, []byte(data), 0644))
+		time.Sleep(time.Millisecond * 20)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".k8s.conf.swp"), []byte("swap"), 0644))
+
+	assert.Equal(t, ConfigFile{Source: file, Data: []byte("name: v4")}, receiveConfigFile(t, prov))
+	assertNoConfigFile(t, prov)
+
+	require.NoError(t, os.Remove(file))
+
+	assert.Equal(t, ConfigFile{Source: file}, receiveConfigFile(t, prov))
+	assertNoConfigFile(t, prov)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Error("provider failed to exit")
+	}
+}
+
+func receiveConfigFile(t *testing.T, prov *DirConfigFileProvider) ConfigFile {
+	t.Helper()
+	select {
+	case cf := <-prov.Configs():
+		return cf
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for a config file")
+		return ConfigFile{}
+	}
+}
+
+func assertNoConfigFile(t *testing.T, prov *DirConfigFileProvider) {
+	t.Helper()
+	select {
+	case cf := <-prov.Configs():
+		t.Errorf("unexpected config file '%s'", cf.Source)
+	case <-time.After(prov.debounce * 3):
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package dnssrv
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+)
+
+type Config struct {
+	Tags string `yaml:"tags"`
+	// Names are SRV record names: '_postgres._tcp.prod.internal'.
+	Names []string `yaml:"names"`
+	// Resolver is the DNS server address (host:port), the system resolver is used if not set.
+	Resolver     string       `yaml:"resolver"`
+	Timeout      web.Duration `yaml:"timeout"`
+	RefreshEvery web.Duration `yaml:"refresh_every"`
+	// GracePeriod is how long the last known good targets of a name are kept when its resolution fails.
+	GracePeriod web.Duration `yaml:"grace_period"`
+}
+
+func validateConfig(cfg Config) error {
+	if cfg.Tags == "" {
+		return errors.New("'tags' not set")
+	}
+	if len(cfg.Names) == 0 {
+		return errors.New("'names' not set")
+	}
+	for i, name := range cfg.Names {
+		if name == "" {
+			return fmt.Errorf("'names[%d]' is empty", i+1)
+		}
+	}
+	if cfg.Timeout.Duration < 0 {
+		return errors.New("'timeout' can not be negative")
+	}
+	if cfg.RefreshEvery.Duration < 0 {
+		return errors.New("'refresh_every' can not be negative")
+	}
+	if cfg.GracePeriod.Duration < 0 {
+		return errors.New("'grace_period' can not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package dnssrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/logger"
+
+	"github.com/ilyam8/hashstructure"
+)
+
+type targetGroup struct {
+	source  string
+	targets []model.Target
+}
+
+func (g *targetGroup) Provider() string        { return "sd:dnssrv" }
+func (g *targetGroup) Source() string          { return g.source }
+func (g *targetGroup) Targets() []model.Target { return g.targets }
+
+type Target struct {
+	model.Base `hash:"ignore"`
+
+	hash uint64
+	tuid string
+
+	SRVName   string
+	Host      string
+	Port      string
+	Priority  int
+	Weight    int
+	IPAddress string
+	Address   string
+}
+
+func (t *Target) Hash() uint64 { return t.hash }
+func (t *Target) TUID() string { return t.tuid }
+
+type resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+func NewDiscoverer(cfg Config) (*Discoverer, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("config validation: %v", err)
+	}
+
+	tags, err := model.ParseTags(cfg.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("parse tags: %v", err)
+	}
+
+	d := &Discoverer{
+		Logger: logger.New().With(
+			slog.String("component", "discovery sd dnssrv"),
+		),
+		resolver:    net.DefaultResolver,
+		names:       cfg.Names,
+		timeout:     cfg.Timeout.Duration,
+		interval:    cfg.RefreshEvery.Duration,
+		gracePeriod: cfg.GracePeriod.Duration,
+		state:       make(map[string]*nameState),
+	}
+	if cfg.Resolver != "" {
+		d.resolver = newResolver(cfg.Resolver)
+	}
+	if d.timeout == 0 {
+		d.timeout = time.Second * 5
+	}
+	if d.interval == 0 {
+		d.interval = time.Minute
+	}
+	if d.gracePeriod == 0 {
+		d.gracePeriod = time.Minute * 5
+	}
+	d.Tags().Merge(tags)
+
+	return d, nil
+}
+
+type (
+	Discoverer struct {
+		*logger.Logger
+		model.Base
+
+		resolver    resolver
+		names       []string
+		timeout     time.Duration
+		interval    time.Duration
+		gracePeriod time.Duration
+
+		state map[string]*nameState
+	}
+	nameState struct {
+		hash        uint64    // hash of the last sent targets, 0 if nothing or an empty group was sent
+		lastSuccess time.Time // the last time the name was resolved
+	}
+)
+
+func (d *Discoverer) String() string {
+	return "sd:dnssrv"
+}
+
+func (d *Discoverer) Discover(ctx context.Context, in chan<- []model.TargetGroup) {
+	d.Info("instance is started")
+	defer d.Info("instance is stopped")
+
+	d.refresh(ctx, in)
+
+	tk := time.NewTicker(d.interval)
+	defer tk.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			d.refresh(ctx, in)
+		}
+	}
+}
+
+func (d *Discoverer) refresh(ctx context.Context, in chan<- []model.TargetGroup) {
+	var tggs []model.TargetGroup
+	now := time.Now()
+
+	for _, name := range d.names {
+		st, ok := d.state[name]
+		if !ok {
+			st = &nameState{lastSuccess: now}
+			d.state[name] = st
+		}
+
+		tgg, err := d.resolve(ctx, name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if st.hash == 0 || now.Sub(st.lastSuccess) < d.gracePeriod {
+				d.Warningf("resolve '%s': %v", name, err)
+				continue
+			}
+			d.Warningf("resolve '%s': %v, removing targets (no successful resolution for %s)", name, err, now.Sub(st.lastSuccess))
+			tgg = &targetGroup{source: srvSource(name)}
+		} else {
+			st.lastSuccess = now
+		}
+
+		if hash := groupHash(tgg); hash != st.hash {
+			st.hash = hash
+			tggs = append(tggs, tgg)
+		}
+	}
+
+	if len(tggs) == 0 {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case in <- tggs:
+	}
+}
+
+func (d *Discoverer) resolve(ctx context.Context, name string) (*targetGroup, error) {
+	lookupCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	tgg := &targetGroup{source: srvSource(name)}
+
+	_, srvs, err := d.resolver.LookupSRV(lookupCtx, "", "", name)
+	if err != nil {
+		// the record doesn't exist, that is an authoritative answer, not a failure
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return tgg, nil
+		}
+		return nil, err
+	}
+
+	// records are shuffled by weight, the order must be stable
+	sort.Slice(srvs, func(i, j int) bool {
+		if srvs[i].Priority != srvs[j].Priority {
+			return srvs[i].Priority < srvs[j].Priority
+		}
+		if srvs[i].Target != srvs[j].Target {
+			return srvs[i].Target < srvs[j].Target
+		}
+		return srvs[i].Port < srvs[j].Port
+	})
+
+	for _, srv := range srvs {
+		host := strings.TrimSuffix(srv.Target, ".")
+		port := strconv.Itoa(int(srv.Port))
+
+		ip, err := d.lookupIP(lookupCtx, srv.Target)
+		if err != nil {
+			return nil, fmt.Errorf("resolve '%s': %v", host, err)
+		}
+
+		tgt := &Target{
+			tuid:      fmt.Sprintf("%s_%s_%s", strings.TrimSuffix(name, "."), host, port),
+			SRVName:   name,
+			Host:      host,
+			Port:      port,
+			Priority:  int(srv.Priority),
+			Weight:    int(srv.Weight),
+			IPAddress: ip,
+			Address:   net.JoinHostPort(ip, port),
+		}
+
+		hash, err := calcHash(tgt)
+		if err != nil {
+			continue
+		}
+		tgt.hash = hash
+		tgt.Tags().Merge(d.Tags())
+
+		tgg.targets = append(tgg.targets, tgt)
+	}
+
+	return tgg, nil
+}
+
+// lookupIP returns the first IPv4 address of the host, the first IPv6 address if there is no IPv4.
+func (d *Discoverer) lookupIP(ctx context.Context, host string) (string, error) {
+	addrs, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	if len(addrs) == 0 {
+		return "", errors.New("no addresses")
+	}
+
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].String() < addrs[j].String() })
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			return addr.IP.String(), nil
+		}
+	}
+	return addrs[0].IP.String(), nil
+}
+
+func newResolver(address string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+func groupHash(tgg *targetGroup) uint64 {
+	if len(tgg.targets) == 0 {
+		return 0
+	}
+	hashes := make([]uint64, 0, len(tgg.targets))
+	for _, tgt := range tgg.targets {
+		hashes = append(hashes, tgt.Hash())
+	}
+	hash, _ := calcHash(hashes)
+	return hash
+}
+
+func srvSource(name string) string {
+	return fmt.Sprintf("sd:dnssrv(%s)", name)
+}
+
+func calcHash(obj any) (uint64, error) {
+	return hashstructure.Hash(obj, nil)
+}
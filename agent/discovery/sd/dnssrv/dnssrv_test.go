@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package dnssrv
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/pkg/web"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const srvPostgres = "_postgres._tcp.prod.internal"
+
+var (
+	recordsPostgres = []string{
+		srvPostgres + ". 60 IN SRV 10 60 5432 db1.prod.internal.",
+		srvPostgres + ". 60 IN SRV 10 40 5433 db2.prod.internal.",
+		srvPostgres + ". 60 IN SRV 20 0 5432 db-backup.prod.internal.",
+		"db1.prod.internal. 60 IN A 10.0.0.11",
+		"db1.prod.internal. 60 IN AAAA 2001:db8::11",
+		"db2.prod.internal. 60 IN AAAA 2001:db8::12",
+		"db-backup.prod.internal. 60 IN A 10.0.1.10",
+	}
+	recordsPostgresScaledDown = []string{
+		srvPostgres + ". 60 IN SRV 10 100 5432 db1.prod.internal.",
+		"db1.prod.internal. 60 IN A 10.0.0.11",
+	}
+)
+
+func TestNewDiscoverer(t *testing.T) {
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"valid config": {
+			cfg: Config{Tags: "dns", Names: []string{srvPostgres}},
+		},
+		"valid config with resolver": {
+			cfg: Config{Tags: "dns", Names: []string{srvPostgres}, Resolver: "127.0.0.1:53"},
+		},
+		"tags not set": {
+			cfg:     Config{Names: []string{srvPostgres}},
+			wantErr: true,
+		},
+		"names not set": {
+			cfg:     Config{Tags: "dns"},
+			wantErr: true,
+		},
+		"empty name": {
+			cfg:     Config{Tags: "dns", Names: []string{srvPostgres, ""}},
+			wantErr: true,
+		},
+		"negative grace period": {
+			cfg:     Config{Tags: "dns", Names: []string{srvPostgres}, GracePeriod: web.Duration{Duration: -time.Second}},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewDiscoverer(test.cfg)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, d)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, d)
+			}
+		})
+	}
+}
+
+func TestDiscoverer_Discover(t *testing.T) {
+	tests := map[string]discoverySim{
+		"targets per host and port": {
+			cfg:     Config{Tags: "dns", Names: []string{srvPostgres}},
+			records: recordsPostgres,
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareTargetGroup(srvPostgres,
+					prepareTarget(srvPostgres, "db1.prod.internal", 5432, 10, 60, "10.0.0.11"),
+					prepareTarget(srvPostgres, "db2.prod.internal", 5433, 10, 40, "2001:db8::12"),
+					prepareTarget(srvPostgres, "db-backup.prod.internal", 5432, 20, 0, "10.0.1.10"),
+				),
+			}},
+		},
+		"records change": {
+			cfg:     Config{Tags: "dns", Names: []string{srvPostgres, "_redis._tcp.prod.internal"}},
+			records: recordsPostgres,
+			updates: []func(s *stubServer){
+				func(s *stubServer) { s.setRecords(t, recordsPostgresScaledDown...) },
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{
+					prepareTargetGroup(srvPostgres,
+						prepareTarget(srvPostgres, "db1.prod.internal", 5432, 10, 60, "10.0.0.11"),
+						prepareTarget(srvPostgres, "db2.prod.internal", 5433, 10, 40, "2001:db8::12"),
+						prepareTarget(srvPostgres, "db-backup.prod.internal", 5432, 20, 0, "10.0.1.10"),
+					),
+				},
+				{
+					prepareTargetGroup(srvPostgres,
+						prepareTarget(srvPostgres, "db1.prod.internal", 5432, 10, 100, "10.0.0.11"),
+					),
+				},
+			},
+		},
+		"record removed": {
+			cfg:     Config{Tags: "dns", Names: []string{srvPostgres}},
+			records: recordsPostgresScaledDown,
+			updates: []func(s *stubServer){
+				func(s *stubServer) { s.setRecords(t) },
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{
+					prepareTargetGroup(srvPostgres,
+						prepareTarget(srvPostgres, "db1.prod.internal", 5432, 10, 100, "10.0.0.11"),
+					),
+				},
+				{
+					prepareTargetGroup(srvPostgres),
+				},
+			},
+		},
+		"resolution failure retracts targets after grace period": {
+			cfg: Config{
+				Tags:        "dns",
+				Names:       []string{srvPostgres},
+				Timeout:     web.Duration{Duration: time.Millisecond * 200},
+				GracePeriod: web.Duration{Duration: time.Millisecond * 500},
+			},
+			records: recordsPostgresScaledDown,
+			updates: []func(s *stubServer){
+				func(s *stubServer) { s.setFailing(true) },
+				func(s *stubServer) { s.setFailing(false) },
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{
+					prepareTargetGroup(srvPostgres,
+						prepareTarget(srvPostgres, "db1.prod.internal", 5432, 10, 100, "10.0.0.11"),
+					),
+				},
+				{
+					prepareTargetGroup(srvPostgres),
+				},
+				{
+					prepareTargetGroup(srvPostgres,
+						prepareTarget(srvPostgres, "db1.prod.internal", 5432, 10, 100, "10.0.0.11"),
+					),
+				},
+			},
+		},
+	}
+
+	for name, sim := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim.run(t)
+		})
+	}
+}
+
+func prepareTargetGroup(name string, targets ...model.Target) *targetGroup {
+	return &targetGroup{source: srvSource(name), targets: targets}
+}
+
+func prepareTarget(name, host string, port, priority, weight int, ip string) *Target {
+	tgt := &Target{
+		tuid:      name + "_" + host + "_" + strconv.Itoa(port),
+		SRVName:   name,
+		Host:      host,
+		Port:      strconv.Itoa(port),
+		Priority:  priority,
+		Weight:    weight,
+		IPAddress: ip,
+		Address:   net.JoinHostPort(ip, strconv.Itoa(port)),
+	}
+	tgt.hash, _ = calcHash(tgt)
+	tgt.Tags().Merge(model.Tags{"dns": {}})
+	return tgt
+}
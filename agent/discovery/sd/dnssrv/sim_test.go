@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package dnssrv
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/pkg/web"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type discoverySim struct {
+	cfg              Config
+	records          []string
+	updates          []func(s *stubServer)
+	wantTargetGroups [][]model.TargetGroup
+}
+
+func (sim *discoverySim) run(t *testing.T) {
+	srv := newStubServer(t, sim.records...)
+
+	cfg := sim.cfg
+	cfg.Resolver = srv.addr
+	cfg.RefreshEvery = web.Duration{Duration: time.Millisecond * 100}
+
+	d, err := NewDiscoverer(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan []model.TargetGroup)
+	done := make(chan struct{})
+	go func() { defer close(done); d.Discover(ctx, in) }()
+
+	for i, want := range sim.wantTargetGroups {
+		if i > 0 {
+			sim.updates[i-1](srv)
+		}
+
+		select {
+		case tggs := <-in:
+			assert.Equalf(t, want, tggs, "update %d", i+1)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("update %d: timed out waiting for target groups", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 3):
+		t.Error("discovery hasn't finished after cancel")
+	}
+}
+
+// stubServer is a DNS server that answers from a set of records in zone file format.
+type stubServer struct {
+	addr string
+
+	mux     sync.Mutex
+	records []dns.RR
+	failing bool
+}
+
+func newStubServer(t *testing.T, records ...string) *stubServer {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &stubServer{addr: pc.LocalAddr().String()}
+	s.setRecords(t, records...)
+
+	srv := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(s.serveDNS)}
+	go func() { _ = srv.ActivateAndServe() }()
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	return s
+}
+
+func (s *stubServer) setRecords(t *testing.T, records ...string) {
+	var rrs []dns.RR
+	for _, v := range records {
+		rr, err := dns.NewRR(v)
+		require.NoError(t, err)
+		rrs = append(rrs, rr)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.records = rrs
+}
+
+func (s *stubServer) setFailing(v bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.failing = v
+}
+
+func (s *stubServer) serveDNS(w dns.ResponseWriter, req *dns.Msg) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+
+	if s.failing {
+		resp.SetRcode(req, dns.RcodeServerFailure)
+		_ = w.WriteMsg(resp)
+		return
+	}
+
+	q := req.Question[0]
+	var known bool
+	for _, rr := range s.records {
+		if dns.CanonicalName(rr.Header().Name) != dns.CanonicalName(q.Name) {
+			continue
+		}
+		known = true
+		if rr.Header().Rrtype == q.Qtype {
+			resp.Answer = append(resp.Answer, rr)
+		}
+	}
+	if !known {
+		resp.SetRcode(req, dns.RcodeNameError)
+	}
+
+	_ = w.WriteMsg(resp)
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker
+
+import (
+	"fmt"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+)
+
+type Config struct {
+	Tags    string       `yaml:"tags"`
+	Address string       `yaml:"address"`
+	Timeout web.Duration `yaml:"timeout"`
+	// RefreshEvery is the containers list polling interval.
+	RefreshEvery web.Duration `yaml:"refresh_every"`
+	Selector     Selector     `yaml:"selector"`
+	// PreferredNetwork is the network the target address is taken from if a container is attached to several.
+	// The first network (sorted by name) is used if not set or the container is not attached to it.
+	PreferredNetwork string `yaml:"preferred_network"`
+	// Swarm switches to discovering running tasks of Swarm services (cluster-wide, requires a manager node)
+	// instead of local containers. The selector is applied to service labels.
+	Swarm bool `yaml:"swarm"`
+}
+
+type Selector struct {
+	// Labels restricts discovery to containers with all the labels, values are glob patterns:
+	// 'com.docker.compose.project: myapp', 'app: web-*'.
+	Labels map[string]string `yaml:"labels"`
+}
+
+func validateConfig(cfg Config) error {
+	if cfg.Tags == "" {
+		return fmt.Errorf("'tags' not set")
+	}
+	if cfg.Timeout.Duration < 0 {
+		return fmt.Errorf("'timeout' can not be negative")
+	}
+	if cfg.RefreshEvery.Duration < 0 {
+		return fmt.Errorf("'refresh_every' can not be negative")
+	}
+	return nil
+}
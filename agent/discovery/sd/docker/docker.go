@@ -0,0 +1,406 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/logger"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	docker "github.com/docker/docker/client"
+	"github.com/ilyam8/hashstructure"
+)
+
+const (
+	labelComposeProject = "com.docker.compose.project"
+	labelComposeService = "com.docker.compose.service"
+)
+
+type targetGroup struct {
+	source  string
+	targets []model.Target
+}
+
+func (g *targetGroup) Provider() string        { return "sd:docker" }
+func (g *targetGroup) Source() string          { return g.source }
+func (g *targetGroup) Targets() []model.Target { return g.targets }
+
+type Target struct {
+	model.Base `hash:"ignore"`
+
+	hash uint64
+	tuid string
+
+	ID             string
+	Name           string
+	Image          string
+	Command        string
+	Labels         map[string]any
+	NetworkMode    string
+	Network        string
+	NetworkAliases []string
+	IPAddress      string
+	Address        string
+	Port           string
+	PortProtocol   string
+	ComposeProject string
+	ComposeService string
+}
+
+func (t *Target) Hash() uint64 { return t.hash }
+func (t *Target) TUID() string { return t.tuid }
+
+func NewDiscoverer(cfg Config) (*Discoverer, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("config validation: %v", err)
+	}
+
+	tags, err := model.ParseTags(cfg.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("parse tags: %v", err)
+	}
+
+	selector := make(map[string]matcher.Matcher, len(cfg.Selector.Labels))
+	for name, pattern := range cfg.Selector.Labels {
+		m, err := matcher.NewGlobMatcher(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("parse selector label '%s' pattern '%s': %v", name, pattern, err)
+		}
+		selector[name] = m
+	}
+
+	d := &Discoverer{
+		Logger: logger.New().With(
+			slog.String("component", "discovery sd docker"),
+		),
+		newClient: func(address string) (dockerClient, error) {
+			return docker.NewClientWithOpts(docker.WithHost(address))
+		},
+		address:          cfg.Address,
+		timeout:          cfg.Timeout.Duration,
+		interval:         cfg.RefreshEvery.Duration,
+		selector:         selector,
+		preferredNetwork: cfg.PreferredNetwork,
+		swarm:            cfg.Swarm,
+		sent:             make(map[string]bool),
+		aliases:          make(map[string]map[string][]string),
+	}
+	if d.address == "" {
+		d.address = docker.DefaultDockerHost
+	}
+	if d.timeout == 0 {
+		d.timeout = time.Second * 2
+	}
+	if d.interval == 0 {
+		d.interval = time.Second * 30
+	}
+	d.Tags().Merge(tags)
+
+	return d, nil
+}
+
+type (
+	Discoverer struct {
+		*logger.Logger
+		model.Base
+
+		newClient func(address string) (dockerClient, error)
+		client    dockerClient
+
+		address          string
+		timeout          time.Duration
+		interval         time.Duration
+		selector         map[string]matcher.Matcher
+		preferredNetwork string
+		swarm            bool
+
+		sent    map[string]bool                // target group sources sent on the previous refresh
+		aliases map[string]map[string][]string // [containerID][network]aliases
+	}
+	dockerClient interface {
+		NegotiateAPIVersion(context.Context)
+		ContainerList(context.Context, types.ContainerListOptions) ([]types.Container, error)
+		ContainerInspect(context.Context, string) (types.ContainerJSON, error)
+		ServiceList(context.Context, types.ServiceListOptions) ([]swarm.Service, error)
+		TaskList(context.Context, types.TaskListOptions) ([]swarm.Task, error)
+		Close() error
+	}
+)
+
+func (d *Discoverer) String() string {
+	return "sd:docker"
+}
+
+func (d *Discoverer) Discover(ctx context.Context, in chan<- []model.TargetGroup) {
+	d.Info("instance is started")
+	defer d.Info("instance is stopped")
+
+	client, err := d.newClient(d.address)
+	if err != nil {
+		d.Errorf("create docker client: %v", err)
+		return
+	}
+	d.client = client
+	defer func() { _ = d.client.Close() }()
+
+	d.client.NegotiateAPIVersion(ctx)
+
+	d.refresh(ctx, in)
+
+	tk := time.NewTicker(d.interval)
+	defer tk.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			d.refresh(ctx, in)
+		}
+	}
+}
+
+func (d *Discoverer) refresh(ctx context.Context, in chan<- []model.TargetGroup) {
+	var groups []*targetGroup
+	var err error
+
+	if d.swarm {
+		groups, err = d.swarmTargetGroups(ctx)
+	} else {
+		groups, err = d.containerTargetGroups(ctx)
+	}
+	if err != nil {
+		// docker may be restarting, the next refresh will try again
+		d.Warning(err)
+		return
+	}
+
+	var tggs []model.TargetGroup
+	seen := make(map[string]bool)
+
+	for _, tgg := range groups {
+		seen[tgg.source] = true
+		tggs = append(tggs, tgg)
+	}
+
+	for source := range d.sent {
+		if !seen[source] {
+			tggs = append(tggs, &targetGroup{source: source})
+		}
+	}
+	d.sent = seen
+
+	for id := range d.aliases {
+		if !seen[containerSource(id)] {
+			delete(d.aliases, id)
+		}
+	}
+
+	if len(tggs) == 0 {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case in <- tggs:
+	}
+}
+
+func (d *Discoverer) containerTargetGroups(ctx context.Context) ([]*targetGroup, error) {
+	listCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	containers, err := d.client.ContainerList(listCtx, types.ContainerListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %v", err)
+	}
+
+	var groups []*targetGroup
+	for _, cntr := range containers {
+		if d.selectorMatches(cntr.Labels) {
+			groups = append(groups, d.buildTargetGroup(ctx, cntr))
+		}
+	}
+	return groups, nil
+}
+
+func (d *Discoverer) selectorMatches(labels map[string]string) bool {
+	for name, m := range d.selector {
+		v, ok := labels[name]
+		if !ok || !m.MatchString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *Discoverer) buildTargetGroup(ctx context.Context, cntr types.Container) *targetGroup {
+	tgg := &targetGroup{source: containerSource(cntr.ID)}
+
+	network, ip := d.containerNetwork(cntr)
+	if ip == "" && cntr.HostConfig.NetworkMode == "host" {
+		ip = "127.0.0.1"
+	}
+
+	base := Target{
+		ID:             cntr.ID,
+		Name:           containerName(cntr),
+		Image:          cntr.Image,
+		Command:        cntr.Command,
+		Labels:         mapAny(cntr.Labels),
+		NetworkMode:    cntr.HostConfig.NetworkMode,
+		Network:        network,
+		NetworkAliases: d.containerAliases(ctx, cntr.ID, network),
+		IPAddress:      ip,
+		ComposeProject: cntr.Labels[labelComposeProject],
+		ComposeService: cntr.Labels[labelComposeService],
+	}
+
+	ports := containerPorts(cntr)
+	if len(ports) == 0 {
+		// a single target without a port, the address is the container IP
+		ports = []types.Port{{}}
+	}
+
+	for _, port := range ports {
+		tgt := base
+		tgt.tuid = base.Name
+		tgt.Address = ip
+		if port.PrivatePort != 0 {
+			tgt.Port = strconv.Itoa(int(port.PrivatePort))
+			tgt.PortProtocol = port.Type
+			tgt.tuid = fmt.Sprintf("%s_%s_%s", base.Name, strings.ToLower(port.Type), tgt.Port)
+			if ip != "" {
+				tgt.Address = net.JoinHostPort(ip, tgt.Port)
+			}
+		}
+
+		hash, err := calcHash(tgt)
+		if err != nil {
+			continue
+		}
+		tgt.hash = hash
+		tgt.Tags().Merge(d.Tags())
+
+		tgg.targets = append(tgg.targets, &tgt)
+	}
+
+	return tgg
+}
+
+// containerNetwork returns the preferred network if the container is attached to it, otherwise the first one.
+func (d *Discoverer) containerNetwork(cntr types.Container) (name, ip string) {
+	if cntr.NetworkSettings == nil || len(cntr.NetworkSettings.Networks) == 0 {
+		return "", ""
+	}
+	networks := cntr.NetworkSettings.Networks
+
+	if v, ok := networks[d.preferredNetwork]; ok && v != nil && d.preferredNetwork != "" {
+		return d.preferredNetwork, v.IPAddress
+	}
+
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if v := networks[name]; v != nil {
+			return name, v.IPAddress
+		}
+	}
+	return "", ""
+}
+
+// containerAliases returns the network aliases, they are not a part of the containers list response.
+func (d *Discoverer) containerAliases(ctx context.Context, id, network string) []string {
+	if network == "" {
+		return nil
+	}
+
+	aliases, ok := d.aliases[id]
+	if !ok {
+		inspectCtx, cancel := context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+
+		cntr, err := d.client.ContainerInspect(inspectCtx, id)
+		if err != nil {
+			d.Warningf("inspect container '%s': %v", id, err)
+			return nil
+		}
+
+		aliases = make(map[string][]string)
+		if cntr.NetworkSettings != nil {
+			for name, v := range cntr.NetworkSettings.Networks {
+				if v != nil {
+					aliases[name] = v.Aliases
+				}
+			}
+		}
+		d.aliases[id] = aliases
+	}
+
+	return aliases[network]
+}
+
+func containerSource(id string) string {
+	return fmt.Sprintf("sd:docker(%s)", id)
+}
+
+func containerName(cntr types.Container) string {
+	if len(cntr.Names) == 0 {
+		return cntr.ID
+	}
+	return strings.TrimPrefix(cntr.Names[0], "/")
+}
+
+// containerPorts returns unique private ports, the list has an entry per host binding (e.g. IPv4 and IPv6).
+func containerPorts(cntr types.Container) []types.Port {
+	var ports []types.Port
+	seen := make(map[string]bool)
+
+	for _, p := range cntr.Ports {
+		key := fmt.Sprintf("%s/%d", p.Type, p.PrivatePort)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ports = append(ports, types.Port{PrivatePort: p.PrivatePort, Type: p.Type})
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].PrivatePort == ports[j].PrivatePort {
+			return ports[i].Type < ports[j].Type
+		}
+		return ports[i].PrivatePort < ports[j].PrivatePort
+	})
+
+	return ports
+}
+
+func mapAny(src map[string]string) map[string]any {
+	if src == nil {
+		return nil
+	}
+	m := make(map[string]any, len(src))
+	for k, v := range src {
+		m[k] = v
+	}
+	return m
+}
+
+func calcHash(obj any) (uint64, error) {
+	return hashstructure.Hash(obj, nil)
+}
@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDiscoverer(t *testing.T) {
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"valid config": {
+			cfg: Config{Tags: "docker", Selector: Selector{Labels: map[string]string{labelComposeProject: "my*"}}},
+		},
+		"tags not set": {
+			cfg:     Config{},
+			wantErr: true,
+		},
+		"invalid selector label pattern": {
+			cfg:     Config{Tags: "docker", Selector: Selector{Labels: map[string]string{"app": "[web"}}},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewDiscoverer(test.cfg)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, d)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, d)
+			}
+		})
+	}
+}
+
+func TestDiscoverer_Discover(t *testing.T) {
+	web := prepareWebContainer()
+	db := prepareDBContainer()
+	inspect := map[string]types.ContainerJSON{
+		web.ID: prepareContainerJSON(map[string][]string{
+			"myapp_frontend": {"web", "myapp-web-1"},
+			"myapp_backend":  {"web", "myapp-web-1", "api"},
+		}),
+		db.ID: prepareContainerJSON(map[string][]string{
+			"other_default": {"db"},
+		}),
+	}
+
+	tests := map[string]discoverySim{
+		"multi-network container without preferred network uses the first one": {
+			cfg:    Config{Tags: "docker"},
+			client: &mockClient{containers: []types.Container{web}, inspect: inspect},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareWebTargetGroup("myapp_backend", "172.19.0.2", []string{"web", "myapp-web-1", "api"}),
+			}},
+		},
+		"multi-network container with preferred network": {
+			cfg:    Config{Tags: "docker", PreferredNetwork: "myapp_frontend"},
+			client: &mockClient{containers: []types.Container{web}, inspect: inspect},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareWebTargetGroup("myapp_frontend", "172.18.0.2", []string{"web", "myapp-web-1"}),
+			}},
+		},
+		"multi-network container not attached to preferred network": {
+			cfg:    Config{Tags: "docker", PreferredNetwork: "bridge"},
+			client: &mockClient{containers: []types.Container{web}, inspect: inspect},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareWebTargetGroup("myapp_backend", "172.19.0.2", []string{"web", "myapp-web-1", "api"}),
+			}},
+		},
+		"label selector with glob": {
+			cfg: Config{
+				Tags:     "docker",
+				Selector: Selector{Labels: map[string]string{labelComposeProject: "my*", labelComposeService: "web"}},
+			},
+			client: &mockClient{containers: []types.Container{web, db}, inspect: inspect},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareWebTargetGroup("myapp_backend", "172.19.0.2", []string{"web", "myapp-web-1", "api"}),
+			}},
+		},
+		"label selector requires label presence": {
+			cfg: Config{Tags: "docker", Selector: Selector{Labels: map[string]string{"monitored": "*"}}},
+			client: &mockClient{
+				containers: []types.Container{web, db, prepareLabeledContainer(db, "monitored", "yes")},
+				inspect:    inspect,
+			},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareDBTargetGroup(prepareLabeledContainer(db, "monitored", "yes")),
+			}},
+		},
+		"container without ports": {
+			cfg:    Config{Tags: "docker"},
+			client: &mockClient{containers: []types.Container{db}, inspect: inspect},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareDBTargetGroup(db),
+			}},
+		},
+		"container removed": {
+			cfg:    Config{Tags: "docker"},
+			client: &mockClient{containers: []types.Container{web, db}, inspect: inspect},
+			updates: []func(m *mockClient){
+				func(m *mockClient) { m.containers = []types.Container{db} },
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{
+					prepareWebTargetGroup("myapp_backend", "172.19.0.2", []string{"web", "myapp-web-1", "api"}),
+					prepareDBTargetGroup(db),
+				},
+				{
+					&targetGroup{source: containerSource(web.ID)},
+					prepareDBTargetGroup(db),
+				},
+			},
+		},
+		"inspect error": {
+			cfg:    Config{Tags: "docker"},
+			client: &mockClient{containers: []types.Container{web}},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareWebTargetGroup("myapp_backend", "172.19.0.2", nil),
+			}},
+		},
+	}
+
+	for name, sim := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim.run(t)
+		})
+	}
+}
+
+func prepareWebContainer() types.Container {
+	return types.Container{
+		ID:      "c1f0a9d37c54",
+		Names:   []string{"/myapp-web-1"},
+		Image:   "nginx:latest",
+		Command: "/docker-entrypoint.sh nginx -g 'daemon off;'",
+		Ports: []types.Port{
+			{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+			{IP: "::", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+			{PrivatePort: 443, Type: "tcp"},
+		},
+		Labels: map[string]string{
+			labelComposeProject: "myapp",
+			labelComposeService: "web",
+		},
+		HostConfig: struct {
+			NetworkMode string `json:",omitempty"`
+		}{NetworkMode: "myapp_frontend"},
+		NetworkSettings: &types.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{
+			"myapp_frontend": {IPAddress: "172.18.0.2"},
+			"myapp_backend":  {IPAddress: "172.19.0.2"},
+		}},
+	}
+}
+
+func prepareDBContainer() types.Container {
+	return types.Container{
+		ID:     "b7e2f1c0a111",
+		Names:  []string{"/other-db-1"},
+		Image:  "postgres:16",
+		Labels: map[string]string{labelComposeProject: "other", labelComposeService: "db"},
+		HostConfig: struct {
+			NetworkMode string `json:",omitempty"`
+		}{NetworkMode: "other_default"},
+		NetworkSettings: &types.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{
+			"other_default": {IPAddress: "172.20.0.2"},
+		}},
+	}
+}
+
+func prepareLabeledContainer(cntr types.Container, name, value string) types.Container {
+	labels := map[string]string{name: value}
+	for k, v := range cntr.Labels {
+		labels[k] = v
+	}
+	cntr.ID += "-labeled"
+	cntr.Labels = labels
+	return cntr
+}
+
+func prepareContainerJSON(aliases map[string][]string) types.ContainerJSON {
+	networks := make(map[string]*network.EndpointSettings)
+	for name, v := range aliases {
+		networks[name] = &network.EndpointSettings{Aliases: v}
+	}
+	return types.ContainerJSON{NetworkSettings: &types.NetworkSettings{Networks: networks}}
+}
+
+func prepareWebTargetGroup(networkName, ip string, aliases []string) *targetGroup {
+	cntr := prepareWebContainer()
+	newTarget := func(port, tuid string) model.Target {
+		return withHash(&Target{
+			tuid:           tuid,
+			ID:             cntr.ID,
+			Name:           "myapp-web-1",
+			Image:          cntr.Image,
+			Command:        cntr.Command,
+			Labels:         mapAny(cntr.Labels),
+			NetworkMode:    "myapp_frontend",
+			Network:        networkName,
+			NetworkAliases: aliases,
+			IPAddress:      ip,
+			Address:        ip + ":" + port,
+			Port:           port,
+			PortProtocol:   "tcp",
+			ComposeProject: "myapp",
+			ComposeService: "web",
+		})
+	}
+
+	return &targetGroup{
+		source: containerSource(cntr.ID),
+		targets: []model.Target{
+			newTarget("80", "myapp-web-1_tcp_80"),
+			newTarget("443", "myapp-web-1_tcp_443"),
+		},
+	}
+}
+
+func prepareDBTargetGroup(cntr types.Container) *targetGroup {
+	var aliases []string
+	if cntr.ID == prepareDBContainer().ID {
+		aliases = []string{"db"}
+	}
+	return &targetGroup{
+		source: containerSource(cntr.ID),
+		targets: []model.Target{
+			withHash(&Target{
+				tuid:           "other-db-1",
+				ID:             cntr.ID,
+				Name:           "other-db-1",
+				Image:          "postgres:16",
+				Labels:         mapAny(cntr.Labels),
+				NetworkMode:    "other_default",
+				Network:        "other_default",
+				NetworkAliases: aliases,
+				IPAddress:      "172.20.0.2",
+				Address:        "172.20.0.2",
+				ComposeProject: "other",
+				ComposeService: "db",
+			}),
+		},
+	}
+}
+
+func withHash(tgt *Target) *Target {
+	tgt.hash, _ = calcHash(tgt)
+	tgt.Tags().Merge(model.Tags{"docker": {}})
+	return tgt
+}
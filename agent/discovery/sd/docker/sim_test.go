@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type discoverySim struct {
+	cfg              Config
+	client           *mockClient
+	updates          []func(m *mockClient)
+	wantTargetGroups [][]model.TargetGroup
+}
+
+func (sim *discoverySim) run(t *testing.T) {
+	d, err := NewDiscoverer(sim.cfg)
+	require.NoError(t, err)
+
+	d.interval = time.Millisecond * 100
+	d.newClient = func(string) (dockerClient, error) { return sim.client, nil }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan []model.TargetGroup)
+	done := make(chan struct{})
+	go func() { defer close(done); d.Discover(ctx, in) }()
+
+	for i, want := range sim.wantTargetGroups {
+		if i > 0 {
+			sim.client.update(sim.updates[i-1])
+		}
+
+		select {
+		case tggs := <-in:
+			sortTargetGroups(tggs)
+			sortTargetGroups(want)
+			assert.Equalf(t, want, tggs, "update %d", i+1)
+		case <-time.After(time.Second * 3):
+			t.Fatalf("update %d: timed out waiting for target groups", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+		assert.True(t, sim.client.closed, "client is not closed")
+	case <-time.After(time.Second * 3):
+		t.Error("discovery hasn't finished after cancel")
+	}
+}
+
+func sortTargetGroups(tggs []model.TargetGroup) {
+	sort.Slice(tggs, func(i, j int) bool { return tggs[i].Source() < tggs[j].Source() })
+}
+
+type mockClient struct {
+	mux        sync.Mutex
+	containers []types.Container
+	inspect    map[string]types.ContainerJSON
+	services   []swarm.Service
+	tasks      []swarm.Task
+	errOnList  bool
+	closed     bool
+}
+
+func (m *mockClient) update(fn func(m *mockClient)) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	fn(m)
+}
+
+func (m *mockClient) NegotiateAPIVersion(context.Context) {}
+
+func (m *mockClient) ContainerList(context.Context, types.ContainerListOptions) ([]types.Container, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.errOnList {
+		return nil, errors.New("mock.ContainerList() error")
+	}
+	return m.containers, nil
+}
+
+func (m *mockClient) ContainerInspect(_ context.Context, id string) (types.ContainerJSON, error) {
+	v, ok := m.inspect[id]
+	if !ok {
+		return types.ContainerJSON{}, errors.New("mock.ContainerInspect() error: no such container")
+	}
+	return v, nil
+}
+
+func (m *mockClient) ServiceList(context.Context, types.ServiceListOptions) ([]swarm.Service, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.errOnList {
+		return nil, errors.New("mock.ServiceList() error")
+	}
+	return m.services, nil
+}
+
+func (m *mockClient) TaskList(context.Context, types.TaskListOptions) ([]swarm.Task, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.errOnList {
+		return nil, errors.New("mock.TaskList() error")
+	}
+	return m.tasks, nil
+}
+
+func (m *mockClient) Close() error {
+	m.closed = true
+	return nil
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+type SwarmTarget struct {
+	model.Base `hash:"ignore"`
+
+	hash uint64
+	tuid string
+
+	ServiceID    string
+	ServiceName  string
+	TaskID       string
+	TaskSlot     int
+	NodeID       string
+	DesiredState string
+	CurrentState string
+	Image        string
+	Labels       map[string]any
+	Network      string
+	IPAddress    string
+	Ports        []SwarmPort
+}
+
+// SwarmPort is a published port, ingress ports are reachable on any node, host ports only on the task node.
+type SwarmPort struct {
+	Protocol      string
+	TargetPort    int
+	PublishedPort int
+	PublishMode   string
+}
+
+func (t *SwarmTarget) Hash() uint64 { return t.hash }
+func (t *SwarmTarget) TUID() string { return t.tuid }
+
+func (d *Discoverer) swarmTargetGroups(ctx context.Context) ([]*targetGroup, error) {
+	listCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	services, err := d.client.ServiceList(listCtx, types.ServiceListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list services: %v", err)
+	}
+
+	tasks, err := d.client.TaskList(listCtx, types.TaskListOptions{
+		Filters: filters.NewArgs(filters.Arg("desired-state", string(swarm.TaskStateRunning))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %v", err)
+	}
+
+	byService := make(map[string][]swarm.Task)
+	for _, task := range tasks {
+		// tasks that are starting or shutting down are not reachable
+		if task.DesiredState != swarm.TaskStateRunning || task.Status.State != swarm.TaskStateRunning {
+			continue
+		}
+		byService[task.ServiceID] = append(byService[task.ServiceID], task)
+	}
+
+	var groups []*targetGroup
+	for _, svc := range services {
+		if d.selectorMatches(svc.Spec.Labels) {
+			groups = append(groups, d.buildSwarmTargetGroup(svc, byService[svc.ID]))
+		}
+	}
+	return groups, nil
+}
+
+func (d *Discoverer) buildSwarmTargetGroup(svc swarm.Service, tasks []swarm.Task) *targetGroup {
+	tgg := &targetGroup{source: serviceSource(svc.ID)}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Slot == tasks[j].Slot {
+			return tasks[i].NodeID < tasks[j].NodeID
+		}
+		return tasks[i].Slot < tasks[j].Slot
+	})
+
+	var ingress []SwarmPort
+	for _, p := range svc.Endpoint.Ports {
+		if p.PublishMode != swarm.PortConfigPublishModeHost {
+			ingress = append(ingress, newSwarmPort(p))
+		}
+	}
+
+	for _, task := range tasks {
+		network, ip := d.taskNetwork(task)
+
+		tgt := &SwarmTarget{
+			ServiceID:    svc.ID,
+			ServiceName:  svc.Spec.Name,
+			TaskID:       task.ID,
+			TaskSlot:     task.Slot,
+			NodeID:       task.NodeID,
+			DesiredState: string(task.DesiredState),
+			CurrentState: string(task.Status.State),
+			Labels:       mapAny(svc.Spec.Labels),
+			Network:      network,
+			IPAddress:    ip,
+			Ports:        append([]SwarmPort(nil), ingress...),
+		}
+		if spec := task.Spec.ContainerSpec; spec != nil {
+			tgt.Image = spec.Image
+		}
+		for _, p := range task.Status.PortStatus.Ports {
+			tgt.Ports = append(tgt.Ports, newSwarmPort(p))
+		}
+
+		// global services tasks have no slot, there is a task per node
+		if task.Slot != 0 {
+			tgt.tuid = fmt.Sprintf("%s_%d", svc.Spec.Name, task.Slot)
+		} else {
+			tgt.tuid = fmt.Sprintf("%s_%s", svc.Spec.Name, task.NodeID)
+		}
+
+		hash, err := calcHash(tgt)
+		if err != nil {
+			continue
+		}
+		tgt.hash = hash
+		tgt.Tags().Merge(d.Tags())
+
+		tgg.targets = append(tgg.targets, tgt)
+	}
+
+	return tgg
+}
+
+// taskNetwork returns the preferred network if the task is attached to it, otherwise the first one.
+func (d *Discoverer) taskNetwork(task swarm.Task) (name, ip string) {
+	attachments := make(map[string]string)
+	var names []string
+
+	for _, na := range task.NetworksAttachments {
+		if len(na.Addresses) == 0 {
+			continue
+		}
+		name := na.Network.Spec.Name
+		// addresses are in CIDR notation
+		addr, _, _ := strings.Cut(na.Addresses[0], "/")
+		attachments[name] = addr
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", ""
+	}
+
+	if addr, ok := attachments[d.preferredNetwork]; ok && d.preferredNetwork != "" {
+		return d.preferredNetwork, addr
+	}
+
+	sort.Strings(names)
+	return names[0], attachments[names[0]]
+}
+
+func newSwarmPort(p swarm.PortConfig) SwarmPort {
+	mode := p.PublishMode
+	if mode == "" {
+		mode = swarm.PortConfigPublishModeIngress
+	}
+	return SwarmPort{
+		Protocol:      string(p.Protocol),
+		TargetPort:    int(p.TargetPort),
+		PublishedPort: int(p.PublishedPort),
+		PublishMode:   string(mode),
+	}
+}
+
+func serviceSource(id string) string {
+	return fmt.Sprintf("sd:docker(swarm/%s)", id)
+}
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoverer_Discover_Swarm(t *testing.T) {
+	web := prepareSwarmService("svc-web", "myapp_web", map[string]string{"com.docker.stack.namespace": "myapp"},
+		swarm.PortConfig{Protocol: "tcp", TargetPort: 80, PublishedPort: 8080, PublishMode: swarm.PortConfigPublishModeIngress},
+	)
+	agent := prepareSwarmService("svc-agent", "monitoring_agent", map[string]string{"com.docker.stack.namespace": "monitoring"})
+
+	webTask1 := prepareSwarmTask("task-web-1", web.ID, 1, "node1", swarm.TaskStateRunning, "10.0.1.3")
+	webTask2 := prepareSwarmTask("task-web-2", web.ID, 2, "node2", swarm.TaskStateRunning, "10.0.1.4")
+	webTask3Starting := prepareSwarmTask("task-web-3", web.ID, 3, "node3", swarm.TaskStateStarting, "10.0.1.5")
+	webTask2Old := prepareSwarmTask("task-web-2-old", web.ID, 2, "node1", swarm.TaskStateRunning, "10.0.1.2")
+	webTask2Old.DesiredState = swarm.TaskStateShutdown
+
+	agentTask := prepareSwarmTask("task-agent-node1", agent.ID, 0, "node1", swarm.TaskStateRunning, "10.0.2.3")
+	agentTask.Status.PortStatus.Ports = []swarm.PortConfig{
+		{Protocol: "udp", TargetPort: 8125, PublishedPort: 8125, PublishMode: swarm.PortConfigPublishModeHost},
+	}
+
+	webPorts := []SwarmPort{{Protocol: "tcp", TargetPort: 80, PublishedPort: 8080, PublishMode: "ingress"}}
+	agentPorts := []SwarmPort{{Protocol: "udp", TargetPort: 8125, PublishedPort: 8125, PublishMode: "host"}}
+
+	tests := map[string]discoverySim{
+		"only running tasks": {
+			cfg: Config{Tags: "docker", Swarm: true},
+			client: &mockClient{
+				services: []swarm.Service{web, agent},
+				tasks:    []swarm.Task{webTask3Starting, webTask2, webTask1, webTask2Old, agentTask},
+			},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareSwarmTargetGroup(web, webPorts, webTask1, webTask2),
+				prepareSwarmTargetGroup(agent, agentPorts, agentTask),
+			}},
+		},
+		"service label selector": {
+			cfg: Config{
+				Tags:     "docker",
+				Swarm:    true,
+				Selector: Selector{Labels: map[string]string{"com.docker.stack.namespace": "mon*"}},
+			},
+			client: &mockClient{
+				services: []swarm.Service{web, agent},
+				tasks:    []swarm.Task{webTask1, agentTask},
+			},
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareSwarmTargetGroup(agent, agentPorts, agentTask),
+			}},
+		},
+		"scale down and service removal": {
+			cfg: Config{Tags: "docker", Swarm: true},
+			client: &mockClient{
+				services: []swarm.Service{web, agent},
+				tasks:    []swarm.Task{webTask1, webTask2, agentTask},
+			},
+			updates: []func(m *mockClient){
+				func(m *mockClient) { m.tasks = []swarm.Task{webTask1, agentTask} },
+				func(m *mockClient) { m.services, m.tasks = []swarm.Service{web}, []swarm.Task{webTask1} },
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{
+					prepareSwarmTargetGroup(web, webPorts, webTask1, webTask2),
+					prepareSwarmTargetGroup(agent, agentPorts, agentTask),
+				},
+				{
+					prepareSwarmTargetGroup(web, webPorts, webTask1),
+					prepareSwarmTargetGroup(agent, agentPorts, agentTask),
+				},
+				{
+					prepareSwarmTargetGroup(web, webPorts, webTask1),
+					&targetGroup{source: serviceSource(agent.ID)},
+				},
+			},
+		},
+		"scale to zero": {
+			cfg: Config{Tags: "docker", Swarm: true},
+			client: &mockClient{
+				services: []swarm.Service{web},
+				tasks:    []swarm.Task{webTask1},
+			},
+			updates: []func(m *mockClient){
+				func(m *mockClient) { m.tasks = nil },
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{prepareSwarmTargetGroup(web, webPorts, webTask1)},
+				{prepareSwarmTargetGroup(web, webPorts)},
+			},
+		},
+	}
+
+	for name, sim := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim.run(t)
+		})
+	}
+}
+
+func TestDiscoverer_taskNetwork(t *testing.T) {
+	task := prepareSwarmTask("task", "svc", 1, "node1", swarm.TaskStateRunning, "10.0.1.3")
+	task.NetworksAttachments = append(task.NetworksAttachments,
+		prepareNetworkAttachment("ingress", "10.0.0.7/24"),
+		prepareNetworkAttachment("empty"),
+	)
+
+	tests := map[string]struct {
+		preferred   string
+		wantNetwork string
+		wantIP      string
+	}{
+		"first network": {
+			wantNetwork: "ingress",
+			wantIP:      "10.0.0.7",
+		},
+		"preferred network": {
+			preferred:   "overlay",
+			wantNetwork: "overlay",
+			wantIP:      "10.0.1.3",
+		},
+		"not attached to preferred network": {
+			preferred:   "empty",
+			wantNetwork: "ingress",
+			wantIP:      "10.0.0.7",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := &Discoverer{preferredNetwork: test.preferred}
+
+			network, ip := d.taskNetwork(task)
+
+			assert.Equal(t, test.wantNetwork, network)
+			assert.Equal(t, test.wantIP, ip)
+		})
+	}
+}
+
+func prepareSwarmService(id, name string, labels map[string]string, ports ...swarm.PortConfig) swarm.Service {
+	svc := swarm.Service{ID: id}
+	svc.Spec.Name = name
+	svc.Spec.Labels = labels
+	svc.Endpoint.Ports = ports
+	return svc
+}
+
+func prepareSwarmTask(id, serviceID string, slot int, nodeID string, state swarm.TaskState, ip string) swarm.Task {
+	return swarm.Task{
+		ID:                  id,
+		ServiceID:           serviceID,
+		Slot:                slot,
+		NodeID:              nodeID,
+		DesiredState:        swarm.TaskStateRunning,
+		Status:              swarm.TaskStatus{State: state},
+		Spec:                swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "nginx:latest"}},
+		NetworksAttachments: []swarm.NetworkAttachment{prepareNetworkAttachment("overlay", ip+"/24")},
+	}
+}
+
+func prepareNetworkAttachment(name string, addresses ...string) swarm.NetworkAttachment {
+	var na swarm.NetworkAttachment
+	na.Network.Spec.Name = name
+	na.Addresses = addresses
+	return na
+}
+
+func prepareSwarmTargetGroup(svc swarm.Service, ports []SwarmPort, tasks ...swarm.Task) *targetGroup {
+	tgg := &targetGroup{source: serviceSource(svc.ID)}
+
+	for _, task := range tasks {
+		ip, _, _ := strings.Cut(task.NetworksAttachments[0].Addresses[0], "/")
+		tgt := &SwarmTarget{
+			ServiceID:    svc.ID,
+			ServiceName:  svc.Spec.Name,
+			TaskID:       task.ID,
+			TaskSlot:     task.Slot,
+			NodeID:       task.NodeID,
+			DesiredState: "running",
+			CurrentState: "running",
+			Image:        "nginx:latest",
+			Labels:       mapAny(svc.Spec.Labels),
+			Network:      "overlay",
+			IPAddress:    ip,
+			Ports:        ports,
+		}
+		if task.Slot != 0 {
+			tgt.tuid = fmt.Sprintf("%s_%d", svc.Spec.Name, task.Slot)
+		} else {
+			tgt.tuid = svc.Spec.Name + "_" + task.NodeID
+		}
+		tgt.hash, _ = calcHash(tgt)
+		tgt.Tags().Merge(model.Tags{"docker": {}})
+
+		tgg.targets = append(tgg.targets, tgt)
+	}
+
+	return tgg
+}
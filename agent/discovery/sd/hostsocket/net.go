@@ -3,15 +3,11 @@
 package hostsocket
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,11 +32,13 @@ type NetSocketTarget struct {
 
 	hash uint64
 
-	Protocol string
-	Address  string
-	Port     string
-	Comm     string
-	Cmdline  string
+	Protocol    string
+	Address     string
+	Port        string
+	PID         int `hash:"ignore"`
+	Comm        string
+	Cmdline     string
+	ContainerID string
 }
 
 func (t *NetSocketTarget) TUID() string { return t.tuid() }
@@ -55,20 +53,12 @@ func NewNetSocketDiscoverer(cfg NetworkSocketConfig) (*NetDiscoverer, error) {
 		return nil, fmt.Errorf("parse tags: %v", err)
 	}
 
-	dir := os.Getenv("NETDATA_PLUGINS_DIR")
-	if dir == "" {
-		dir, _ = os.Getwd()
-	}
-
 	d := &NetDiscoverer{
 		Logger: logger.New().With(
 			slog.String("component", "discovery sd hostsocket"),
 		),
 		interval: time.Second * 60,
-		ll: &localListenersExec{
-			binPath: filepath.Join(dir, "local-listeners"),
-			timeout: time.Second * 5,
-		},
+		ll:       &procNetListeners{procRoot: "/proc"},
 	}
 	d.Tags().Merge(tags)
 
@@ -84,7 +74,7 @@ type (
 		ll       localListeners
 	}
 	localListeners interface {
-		discover(ctx context.Context) ([]byte, error)
+		discover(ctx context.Context) ([]netListener, error)
 	}
 )
 
@@ -111,7 +101,7 @@ func (d *NetDiscoverer) Discover(ctx context.Context, in chan<- []model.TargetGr
 }
 
 func (d *NetDiscoverer) discoverLocalListeners(ctx context.Context, in chan<- []model.TargetGroup) error {
-	bs, err := d.ll.discover(ctx)
+	listeners, err := d.ll.discover(ctx)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			return nil
@@ -119,10 +109,7 @@ func (d *NetDiscoverer) discoverLocalListeners(ctx context.Context, in chan<- []
 		return err
 	}
 
-	tggs, err := d.parseLocalListeners(bs)
-	if err != nil {
-		return err
-	}
+	tggs := d.buildTargetGroups(listeners)
 
 	select {
 	case <-ctx.Done():
@@ -131,28 +118,41 @@ func (d *NetDiscoverer) discoverLocalListeners(ctx context.Context, in chan<- []
 	return nil
 }
 
-func (d *NetDiscoverer) parseLocalListeners(bs []byte) ([]model.TargetGroup, error) {
+func (d *NetDiscoverer) buildTargetGroups(listeners []netListener) []model.TargetGroup {
+	// wildcard IPv4 sorts before wildcard IPv6, the latter is dropped if both are bound by the same process
+	sort.SliceStable(listeners, func(i, j int) bool {
+		a, b := listeners[i], listeners[j]
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.Address < b.Address
+	})
+
 	var tgts []model.Target
+	seen := make(map[string]bool)
 
-	sc := bufio.NewScanner(bytes.NewReader(bs))
-	for sc.Scan() {
-		text := strings.TrimSpace(sc.Text())
-		if text == "" {
-			continue
+	for _, l := range listeners {
+		addr := l.Address
+		if addr == "::" {
+			addr = "0.0.0.0"
 		}
-
-		// Protocol|Address|Port|Cmdline
-		parts := strings.SplitN(text, "|", 4)
-		if len(parts) != 4 {
-			return nil, fmt.Errorf("unexpected data: '%s'", text)
+		key := fmt.Sprintf("%s|%s|%s|%d", l.Protocol, addr, l.Port, l.PID)
+		if seen[key] {
+			continue
 		}
+		seen[key] = true
 
 		tgt := NetSocketTarget{
-			Protocol: parts[0],
-			Address:  parts[1],
-			Port:     parts[2],
-			Comm:     extractComm(parts[3]),
-			Cmdline:  parts[3],
+			Protocol:    l.Protocol,
+			Address:     l.Address,
+			Port:        l.Port,
+			PID:         l.PID,
+			Comm:        l.Comm,
+			Cmdline:     l.Cmdline,
+			ContainerID: l.ContainerID,
 		}
 
 		hash, err := calcHash(tgt)
@@ -172,35 +172,7 @@ func (d *NetDiscoverer) parseLocalListeners(bs []byte) ([]model.TargetGroup, err
 		targets:  tgts,
 	}
 
-	return []model.TargetGroup{tgg}, nil
-}
-
-type localListenersExec struct {
-	binPath string
-	timeout time.Duration
-}
-
-func (e *localListenersExec) discover(ctx context.Context) ([]byte, error) {
-	execCtx, cancel := context.WithTimeout(ctx, e.timeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(execCtx, e.binPath, "tcp") // TODO: tcp6?
-
-	bs, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("error on '%s': %v", cmd, err)
-	}
-
-	return bs, nil
-}
-
-func extractComm(s string) string {
-	i := strings.IndexByte(s, ' ')
-	if i <= 0 {
-		return ""
-	}
-	_, comm := filepath.Split(s[:i])
-	return comm
+	return []model.TargetGroup{tgg}
 }
 
 func calcHash(obj any) (uint64, error) {
@@ -10,70 +10,88 @@ import (
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
 )
 
-var (
-	localListenersOutputSample = []byte(`
-UDP6|::1|8125|/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D
-TCP6|::1|8125|/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D
-TCP|127.0.0.1|8125|/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D
-UDP|127.0.0.1|53768|/opt/netdata/usr/libexec/netdata/plugins.d/go.d.plugin 1
-`)
-)
+var localListenersSample = []netListener{
+	{Protocol: "udp", Address: "::1", Port: "8125", PID: 100, Comm: "netdata", Cmdline: "/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D"},
+	{Protocol: "tcp", Address: "::1", Port: "8125", PID: 100, Comm: "netdata", Cmdline: "/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D"},
+	{Protocol: "tcp", Address: "127.0.0.1", Port: "8125", PID: 100, Comm: "netdata", Cmdline: "/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D"},
+	{Protocol: "udp", Address: "::", Port: "53", PID: 200, Comm: "dnsmasq", Cmdline: "dnsmasq -k", ContainerID: "3f4e8a"},
+	{Protocol: "udp", Address: "0.0.0.0", Port: "53", PID: 200, Comm: "dnsmasq", Cmdline: "dnsmasq -k", ContainerID: "3f4e8a"},
+	{Protocol: "tcp", Address: "::", Port: "53", PID: 201, Comm: "dnsmasq", Cmdline: "dnsmasq -k"},
+	{Protocol: "tcp", Address: "0.0.0.0", Port: "53", PID: 200, Comm: "dnsmasq", Cmdline: "dnsmasq -k", ContainerID: "3f4e8a"},
+}
 
 func TestNetSocketDiscoverer_Discover(t *testing.T) {
 	tests := map[string]discoverySim{
 		"valid response": {
-			mock:                 &mockLocalListenersExec{},
+			mock:                 &mockLocalListeners{},
 			wantDoneBeforeCancel: false,
 			wantTargetGroups: []model.TargetGroup{&netSocketTargetGroup{
 				provider: "hostsocket",
 				source:   "net",
 				targets: []model.Target{
 					withHash(&NetSocketTarget{
-						Protocol: "UDP6",
-						Address:  "::1",
+						Protocol:    "tcp",
+						Address:     "0.0.0.0",
+						Port:        "53",
+						PID:         200,
+						Comm:        "dnsmasq",
+						Cmdline:     "dnsmasq -k",
+						ContainerID: "3f4e8a",
+					}),
+					withHash(&NetSocketTarget{
+						Protocol: "tcp",
+						Address:  "::",
+						Port:     "53",
+						PID:      201,
+						Comm:     "dnsmasq",
+						Cmdline:  "dnsmasq -k",
+					}),
+					withHash(&NetSocketTarget{
+						Protocol: "tcp",
+						Address:  "127.0.0.1",
 						Port:     "8125",
+						PID:      100,
 						Comm:     "netdata",
 						Cmdline:  "/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D",
 					}),
 					withHash(&NetSocketTarget{
-						Protocol: "TCP6",
+						Protocol: "tcp",
 						Address:  "::1",
 						Port:     "8125",
+						PID:      100,
 						Comm:     "netdata",
 						Cmdline:  "/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D",
 					}),
 					withHash(&NetSocketTarget{
-						Protocol: "TCP",
-						Address:  "127.0.0.1",
+						Protocol:    "udp",
+						Address:     "0.0.0.0",
+						Port:        "53",
+						PID:         200,
+						Comm:        "dnsmasq",
+						Cmdline:     "dnsmasq -k",
+						ContainerID: "3f4e8a",
+					}),
+					withHash(&NetSocketTarget{
+						Protocol: "udp",
+						Address:  "::1",
 						Port:     "8125",
+						PID:      100,
 						Comm:     "netdata",
 						Cmdline:  "/opt/netdata/usr/sbin/netdata -P /run/netdata/netdata.pid -D",
 					}),
-					withHash(&NetSocketTarget{
-						Protocol: "UDP",
-						Address:  "127.0.0.1",
-						Port:     "53768",
-						Comm:     "go.d.plugin",
-						Cmdline:  "/opt/netdata/usr/libexec/netdata/plugins.d/go.d.plugin 1",
-					}),
 				},
 			}},
 		},
 		"empty response": {
-			mock:                 &mockLocalListenersExec{emptyResponse: true},
+			mock:                 &mockLocalListeners{emptyResponse: true},
 			wantDoneBeforeCancel: false,
 			wantTargetGroups: []model.TargetGroup{&netSocketTargetGroup{
 				provider: "hostsocket",
 				source:   "net",
 			}},
 		},
-		"error on exec": {
-			mock:                 &mockLocalListenersExec{err: true},
-			wantDoneBeforeCancel: true,
-			wantTargetGroups:     nil,
-		},
-		"invalid data": {
-			mock:                 &mockLocalListenersExec{invalidResponse: true},
+		"error on discover": {
+			mock:                 &mockLocalListeners{err: true},
 			wantDoneBeforeCancel: true,
 			wantTargetGroups:     nil,
 		},
@@ -93,21 +111,17 @@ func withHash(l *NetSocketTarget) *NetSocketTarget {
 	return l
 }
 
-type mockLocalListenersExec struct {
-	err             bool
-	emptyResponse   bool
-	invalidResponse bool
+type mockLocalListeners struct {
+	err           bool
+	emptyResponse bool
 }
 
-func (m *mockLocalListenersExec) discover(context.Context) ([]byte, error) {
+func (m *mockLocalListeners) discover(context.Context) ([]netListener, error) {
 	if m.err {
 		return nil, errors.New("mock discover() error")
 	}
 	if m.emptyResponse {
 		return nil, nil
 	}
-	if m.invalidResponse {
-		return []byte("this is very incorrect data"), nil
-	}
-	return localListenersOutputSample, nil
+	return append([]netListener(nil), localListenersSample...), nil
 }
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package hostsocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type netListener struct {
+	Protocol    string // tcp, udp
+	Address     string
+	Port        string
+	PID         int
+	Comm        string
+	Cmdline     string
+	ContainerID string
+}
+
+const (
+	tcpStateListen = "0A"
+	udpStateClose  = "07" // unconnected UDP sockets, the only ones that receive from any peer
+)
+
+// procNetListeners finds listening sockets in /proc/net/{tcp,tcp6,udp,udp6}
+// and their owners by matching socket inodes against /proc/<pid>/fd links.
+type procNetListeners struct {
+	procRoot string
+}
+
+func (p *procNetListeners) discover(ctx context.Context) ([]netListener, error) {
+	var listeners []netListener
+	inodes := make(map[string]int) // socket inode => listener index
+
+	for _, file := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		bs, err := os.ReadFile(filepath.Join(p.procRoot, "net", file))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// ipv6 is disabled
+				continue
+			}
+			return nil, err
+		}
+
+		socks, err := parseProcNet(bs, file)
+		if err != nil {
+			return nil, fmt.Errorf("parse '%s': %v", file, err)
+		}
+
+		for _, s := range socks {
+			inodes[s.inode] = len(listeners)
+			listeners = append(listeners, s.netListener)
+		}
+	}
+
+	if len(listeners) == 0 {
+		return nil, nil
+	}
+
+	if err := p.findOwners(ctx, listeners, inodes); err != nil {
+		return nil, err
+	}
+
+	return listeners, nil
+}
+
+func (p *procNetListeners) findOwners(ctx context.Context, listeners []netListener, inodes map[string]int) error {
+	entries, err := os.ReadDir(p.procRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// processes can exit and fds be unreadable (no permissions), it is fine to skip them
+		fds, err := os.ReadDir(filepath.Join(p.procRoot, entry.Name(), "fd"))
+		if err != nil {
+			continue
+		}
+
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(p.procRoot, entry.Name(), "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+
+			idx, ok := inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")]
+			if !ok {
+				continue
+			}
+
+			// a socket is shared by forked processes (e.g. nginx workers), the parent has the lowest pid
+			if l := &listeners[idx]; l.PID == 0 || pid < l.PID {
+				l.PID = pid
+				p.readProcess(l)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *procNetListeners) readProcess(l *netListener) {
+	dir := filepath.Join(p.procRoot, strconv.Itoa(l.PID))
+
+	if bs, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		l.Comm = strings.TrimSpace(string(bs))
+	}
+	if bs, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		l.Cmdline = strings.Join(strings.Fields(string(bytes.ReplaceAll(bs, []byte{0}, []byte{' '}))), " ")
+	}
+	if bs, err := os.ReadFile(filepath.Join(dir, "cgroup")); err == nil {
+		l.ContainerID = extractContainerID(bs)
+	}
+}
+
+type procNetSocket struct {
+	netListener
+	inode string
+}
+
+func parseProcNet(bs []byte, file string) ([]procNetSocket, error) {
+	proto := strings.TrimSuffix(file, "6")
+	state := tcpStateListen
+	if proto == "udp" {
+		state = udpStateClose
+	}
+
+	var socks []procNetSocket
+
+	sc := bufio.NewScanner(bytes.NewReader(bs))
+	for sc.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode ...
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || fields[0] == "sl" {
+			continue
+		}
+		if len(fields) < 10 {
+			return nil, fmt.Errorf("unexpected line: '%s'", sc.Text())
+		}
+		if fields[3] != state {
+			continue
+		}
+
+		addr, port, err := parseProcNetAddress(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		socks = append(socks, procNetSocket{
+			netListener: netListener{Protocol: proto, Address: addr, Port: port},
+			inode:       fields[9],
+		})
+	}
+
+	return socks, nil
+}
+
+// parseProcNetAddress parses 'hex_ip:hex_port', the IP is a sequence of 32-bit words in host (little endian) byte order.
+func parseProcNetAddress(s string) (string, string, error) {
+	ipHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", "", fmt.Errorf("invalid address '%s'", s)
+	}
+
+	ip, err := hex.DecodeString(ipHex)
+	if err != nil || (len(ip) != net.IPv4len && len(ip) != net.IPv6len) {
+		return "", "", fmt.Errorf("invalid address '%s'", s)
+	}
+	for i := 0; i < len(ip); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = ip[i+3], ip[i+2], ip[i+1], ip[i]
+	}
+
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid address '%s'", s)
+	}
+
+	return net.IP(ip).String(), strconv.FormatUint(port, 10), nil
+}
+
+var reContainerID = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// extractContainerID returns the container ID from /proc/<pid>/cgroup, docker, containerd, cri-o and podman
+// use the full ID in the cgroup path: '/docker/<id>', '/system.slice/docker-<id>.scope', '/kubepods/.../cri-containerd-<id>.scope'.
+func extractContainerID(cgroup []byte) string {
+	ids := reContainerID.FindAll(cgroup, -1)
+	if len(ids) == 0 {
+		return ""
+	}
+	return string(ids[len(ids)-1])
+}
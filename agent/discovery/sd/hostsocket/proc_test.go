@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package hostsocket
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	procNetHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	containerID   = "3f4e8a1b2c3d4e5f60718293a4b5c6d7e8f90123456789abcdef0123456789ab"
+)
+
+var procFixture = map[string]string{
+	"net/tcp": procNetHeader +
+		"   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0\n" +
+		"   1: 0100007F:1FBD 00000000:0000 0A 00000000:00000000 00:00000000 00000000   201        0 1002 1 0000000000000000 100 0 0 10 0\n" +
+		"   2: 0100007F:1FBD 0100007F:D2F0 01 00000000:00000000 00:00000000 00000000   201        0 1003 1 0000000000000000 20 4 30 10 -1\n",
+	"net/tcp6": procNetHeader +
+		"   0: 00000000000000000000000000000000:0016 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1004 1 0000000000000000 100 0 0 10 0\n" +
+		"   1: 00000000000000000000000001000000:1FBD 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000   201        0 1005 1 0000000000000000 100 0 0 10 0\n",
+	"net/udp": procNetHeader +
+		"  10: 00000000:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 1006 2 0000000000000000 0\n" +
+		"  11: 0100007F:D1F0 0100007F:0035 01 00000000:00000000 00:00000000 00000000     0        0 1007 2 0000000000000000 0\n",
+
+	"100/comm":    "sshd\n",
+	"100/cmdline": "/usr/sbin/sshd\x00-D\x00",
+	"100/cgroup":  "0::/system.slice/ssh.service\n",
+	"200/comm":    "netdata\n",
+	"200/cmdline": "/usr/sbin/netdata\x00-P\x00/run/netdata/netdata.pid\x00-D\x00",
+	"200/cgroup":  "0::/system.slice/netdata.service\n",
+	"300/comm":    "dnsmasq\n",
+	"300/cmdline": "dnsmasq\x00-k\x00",
+	"300/cgroup":  "0::/system.slice/docker-" + containerID + ".scope\n",
+	"301/comm":    "dnsmasq\n",
+	"301/cmdline": "dnsmasq\x00-k\x00",
+	"301/cgroup":  "0::/system.slice/docker-" + containerID + ".scope\n",
+	"self/comm":   "go.d.plugin\n",
+}
+
+var procFixtureFds = map[string]string{
+	"100/fd/0": "/dev/null",
+	"100/fd/3": "socket:[1001]",
+	"100/fd/4": "socket:[1004]",
+	"200/fd/5": "socket:[1002]",
+	"200/fd/6": "socket:[1005]",
+	"200/fd/7": "socket:[1003]",
+	"301/fd/3": "socket:[1006]",
+	"300/fd/3": "socket:[1006]",
+}
+
+func TestProcNetListeners_discover(t *testing.T) {
+	tests := map[string]struct {
+		prepare       func(t *testing.T, dir string)
+		wantListeners []netListener
+		wantErr       bool
+	}{
+		"listening sockets and owners": {
+			prepare: func(t *testing.T, dir string) {},
+			wantListeners: []netListener{
+				{Protocol: "tcp", Address: "0.0.0.0", Port: "22", PID: 100, Comm: "sshd", Cmdline: "/usr/sbin/sshd -D"},
+				{Protocol: "tcp", Address: "127.0.0.1", Port: "8125", PID: 200, Comm: "netdata", Cmdline: "/usr/sbin/netdata -P /run/netdata/netdata.pid -D"},
+				{Protocol: "tcp", Address: "::", Port: "22", PID: 100, Comm: "sshd", Cmdline: "/usr/sbin/sshd -D"},
+				{Protocol: "tcp", Address: "::1", Port: "8125", PID: 200, Comm: "netdata", Cmdline: "/usr/sbin/netdata -P /run/netdata/netdata.pid -D"},
+				{Protocol: "udp", Address: "0.0.0.0", Port: "53", PID: 300, Comm: "dnsmasq", Cmdline: "dnsmasq -k", ContainerID: containerID},
+			},
+		},
+		"socket without owner": {
+			prepare: func(t *testing.T, dir string) {
+				require.NoError(t, os.RemoveAll(filepath.Join(dir, "100")))
+			},
+			wantListeners: []netListener{
+				{Protocol: "tcp", Address: "0.0.0.0", Port: "22"},
+				{Protocol: "tcp", Address: "127.0.0.1", Port: "8125", PID: 200, Comm: "netdata", Cmdline: "/usr/sbin/netdata -P /run/netdata/netdata.pid -D"},
+				{Protocol: "tcp", Address: "::", Port: "22"},
+				{Protocol: "tcp", Address: "::1", Port: "8125", PID: 200, Comm: "netdata", Cmdline: "/usr/sbin/netdata -P /run/netdata/netdata.pid -D"},
+				{Protocol: "udp", Address: "0.0.0.0", Port: "53", PID: 300, Comm: "dnsmasq", Cmdline: "dnsmasq -k", ContainerID: containerID},
+			},
+		},
+		"no listening sockets": {
+			prepare: func(t *testing.T, dir string) {
+				for _, name := range []string{"tcp", "tcp6", "udp"} {
+					require.NoError(t, os.WriteFile(filepath.Join(dir, "net", name), []byte(procNetHeader), 0644))
+				}
+			},
+		},
+		"invalid net file": {
+			prepare: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "udp"), []byte(procNetHeader+"   0: 0035 07\n"), 0644))
+			},
+			wantErr: true,
+		},
+		"invalid address": {
+			prepare: func(t *testing.T, dir string) {
+				data := procNetHeader + "   0: 0000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1\n"
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "tcp"), []byte(data), 0644))
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := prepareProcFixture(t)
+			test.prepare(t, dir)
+
+			ll := &procNetListeners{procRoot: dir}
+			listeners, err := ll.discover(context.Background())
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.wantListeners, listeners)
+			}
+		})
+	}
+}
+
+func Test_parseProcNetAddress(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		wantAddr string
+		wantPort string
+		wantErr  bool
+	}{
+		"ipv4 loopback":  {input: "0100007F:1FBD", wantAddr: "127.0.0.1", wantPort: "8125"},
+		"ipv4 any":       {input: "00000000:0035", wantAddr: "0.0.0.0", wantPort: "53"},
+		"ipv4":           {input: "0A01A8C0:01BB", wantAddr: "192.168.1.10", wantPort: "443"},
+		"ipv6 loopback":  {input: "00000000000000000000000001000000:1FBD", wantAddr: "::1", wantPort: "8125"},
+		"ipv6 any":       {input: "00000000000000000000000000000000:0016", wantAddr: "::", wantPort: "22"},
+		"ipv6":           {input: "B80D01200000000000000000020000FE:0050", wantAddr: "2001:db8::fe00:2", wantPort: "80"},
+		"no port":        {input: "0100007F", wantErr: true},
+		"bad ip length":  {input: "0100:1FBD", wantErr: true},
+		"bad ip hex":     {input: "0100007G:1FBD", wantErr: true},
+		"bad port value": {input: "0100007F:XXXX", wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			addr, port, err := parseProcNetAddress(test.input)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.wantAddr, addr)
+				assert.Equal(t, test.wantPort, port)
+			}
+		})
+	}
+}
+
+func Test_extractContainerID(t *testing.T) {
+	tests := map[string]struct {
+		cgroup string
+		wantID string
+	}{
+		"cgroup v2 docker":     {cgroup: "0::/system.slice/docker-" + containerID + ".scope\n", wantID: containerID},
+		"cgroup v1 docker":     {cgroup: "12:memory:/docker/" + containerID + "\n11:cpu:/docker/" + containerID + "\n", wantID: containerID},
+		"kubernetes":           {cgroup: "0::/kubepods.slice/kubepods-pod1.slice/cri-containerd-" + containerID + ".scope\n", wantID: containerID},
+		"podman":               {cgroup: "0::/machine.slice/libpod-" + containerID + ".scope/container\n", wantID: containerID},
+		"systemd service":      {cgroup: "0::/system.slice/ssh.service\n"},
+		"short hex is ignored": {cgroup: "0::/user.slice/user-1000.slice/session-3f4e8a.scope\n"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.wantID, extractContainerID([]byte(test.cgroup)))
+		})
+	}
+}
+
+func prepareProcFixture(t *testing.T) string {
+	dir := t.TempDir()
+
+	for name, data := range procFixture {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	}
+	for name, link := range procFixtureFds {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.Symlink(link, path))
+	}
+
+	return dir
+}
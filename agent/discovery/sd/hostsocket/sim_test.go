@@ -14,7 +14,7 @@ import (
 )
 
 type discoverySim struct {
-	mock                 *mockLocalListenersExec
+	mock                 *mockLocalListeners
 	wantDoneBeforeCancel bool
 	wantTargetGroups     []model.TargetGroup
 }
@@ -2,19 +2,40 @@
 
 package kubernetes
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+)
 
 type Config struct {
-	APIServer  string         `yaml:"api_server"` // TODO: not used
-	Namespaces []string       `yaml:"namespaces"`
-	Pod        *PodConfig     `yaml:"pod"`
-	Service    *ServiceConfig `yaml:"service"`
+	APIServer         string               `yaml:"api_server"` // TODO: not used
+	Namespaces        []string             `yaml:"namespaces"`
+	NamespaceSelector string               `yaml:"namespace_selector"`
+	ResyncPeriod      *web.Duration        `yaml:"resync_period"`
+	ClientQPS         float32              `yaml:"client_qps"`
+	ClientBurst       int                  `yaml:"client_burst"`
+	Pod               *PodConfig           `yaml:"pod"`
+	Service           *ServiceConfig       `yaml:"service"`
+	EndpointSlice     *EndpointSliceConfig `yaml:"endpointslice"`
 }
 
 type PodConfig struct {
-	Tags      string `yaml:"tags"`
-	LocalMode bool   `yaml:"local_mode"`
-	Selector  struct {
+	Tags                  string   `yaml:"tags"`
+	LocalMode             bool     `yaml:"local_mode"`
+	NodeNameEnv           string   `yaml:"node_name_env"`
+	AddressMode           string   `yaml:"address_mode"`
+	IPFamily              string   `yaml:"ip_family"`
+	IncludeInitContainers bool     `yaml:"include_init_containers"`
+	OnlyRunning           bool     `yaml:"only_running"`
+	OnlyReady             bool     `yaml:"only_ready"`
+	ResolveSecretEnv      *bool    `yaml:"resolve_secret_env"`
+	AnnotationPrefix      string   `yaml:"annotation_prefix"`
+	StripAnnotations      []string `yaml:"strip_annotations"`
+	MaxAnnotationSize     int      `yaml:"max_annotation_size"`
+	Selector              struct {
 		Label string `yaml:"label"`
 		Field string `yaml:"field"`
 	} `yaml:"selector"`
@@ -28,10 +49,48 @@ type ServiceConfig struct {
 	} `yaml:"selector"`
 }
 
+type EndpointSliceConfig struct {
+	Tags               string `yaml:"tags"`
+	IncludeTerminating bool   `yaml:"include_terminating"`
+	Selector           struct {
+		Label string `yaml:"label"`
+		Field string `yaml:"field"`
+	} `yaml:"selector"`
+}
+
 func validateConfig(cfg Config) error {
-	if cfg.Pod == nil && cfg.Service == nil {
+	if cfg.Pod == nil && cfg.Service == nil && cfg.EndpointSlice == nil {
 		return errors.New("no discoverers configured")
 	}
+	if len(cfg.Namespaces) > 0 && cfg.NamespaceSelector != "" {
+		return errors.New("'namespaces' and 'namespace_selector' are mutually exclusive")
+	}
+	if v := cfg.ResyncPeriod; v != nil && v.Duration != 0 && v.Duration < time.Minute {
+		return fmt.Errorf("'resync_period' must be at least 1m or 0 to disable resync, got '%s'", v)
+	}
+	if cfg.ClientQPS < 0 {
+		return fmt.Errorf("'client_qps' must not be negative, got '%v'", cfg.ClientQPS)
+	}
+	if cfg.ClientBurst < 0 {
+		return fmt.Errorf("'client_burst' must not be negative, got '%d'", cfg.ClientBurst)
+	}
+	if cfg.Pod != nil {
+		switch cfg.Pod.AddressMode {
+		case "", addressModePodIP, addressModeHost:
+		default:
+			return fmt.Errorf("'pod->address_mode' has unknown value '%s', expected '%s' or '%s'",
+				cfg.Pod.AddressMode, addressModePodIP, addressModeHost)
+		}
+		if cfg.Pod.MaxAnnotationSize < 0 {
+			return fmt.Errorf("'pod->max_annotation_size' must not be negative, got '%d'", cfg.Pod.MaxAnnotationSize)
+		}
+		switch cfg.Pod.IPFamily {
+		case "", ipFamilyIPv4, ipFamilyIPv6, ipFamilyAll:
+		default:
+			return fmt.Errorf("'pod->ip_family' has unknown value '%s', expected '%s', '%s' or '%s'",
+				cfg.Pod.IPFamily, ipFamilyIPv4, ipFamilyIPv6, ipFamilyAll)
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/logger"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+type endpointSliceTargetGroup struct {
+	targets []model.Target
+	source  string
+}
+
+func (e endpointSliceTargetGroup) Provider() string { return "sd:k8s:endpointslice" }
+func (e endpointSliceTargetGroup) Source() string {
+	return fmt.Sprintf("%s(%s)", e.Provider(), e.source)
+}
+func (e endpointSliceTargetGroup) Targets() []model.Target { return e.targets }
+
+type EndpointSliceTarget struct {
+	model.Base `hash:"ignore"`
+
+	hash uint64
+	tuid string
+
+	Address      string
+	Namespace    string
+	Name         string
+	ServiceName  string
+	Annotations  map[string]any
+	Labels       map[string]any
+	AddressType  string
+	Port         string
+	PortName     string
+	PortProtocol string
+	PodName      string
+	NodeName     string
+	Zone         string
+	Hostname     string
+	Ready        bool
+	Serving      bool
+	Terminating  bool
+}
+
+func (e EndpointSliceTarget) Hash() uint64 { return e.hash }
+func (e EndpointSliceTarget) TUID() string { return e.tuid }
+
+type endpointSliceDiscoverer struct {
+	*logger.Logger
+	model.Base
+
+	informer cache.SharedInformer
+	queue    *workqueue.Type
+
+	includeTerminating bool
+}
+
+func newEndpointSliceDiscoverer(inf cache.SharedInformer) *endpointSliceDiscoverer {
+	if inf == nil {
+		panic("nil endpointslice informer")
+	}
+
+	queue := workqueue.NewWithConfig(workqueue.QueueConfig{Name: "endpointslice"})
+	_, _ = inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj any) { enqueue(queue, obj) },
+		UpdateFunc: func(_, obj any) { enqueue(queue, obj) },
+		DeleteFunc: func(obj any) { enqueue(queue, obj) },
+	})
+
+	return &endpointSliceDiscoverer{
+		Logger:   log,
+		informer: inf,
+		queue:    queue,
+	}
+}
+
+func (e *endpointSliceDiscoverer) String() string {
+	return "k8s endpointslice"
+}
+
+func (e *endpointSliceDiscoverer) Discover(ctx context.Context, ch chan<- []model.TargetGroup) {
+	e.Info("instance is started")
+	defer e.Info("instance is stopped")
+	defer e.queue.ShutDown()
+
+	go e.informer.Run(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), e.informer.HasSynced) {
+		e.Error("failed to sync caches")
+		return
+	}
+
+	go e.run(ctx, ch)
+
+	<-ctx.Done()
+}
+
+func (e *endpointSliceDiscoverer) run(ctx context.Context, in chan<- []model.TargetGroup) {
+	for {
+		item, shutdown := e.queue.Get()
+		if shutdown {
+			return
+		}
+
+		e.handleQueueItem(ctx, in, item)
+	}
+}
+
+func (e *endpointSliceDiscoverer) handleQueueItem(ctx context.Context, in chan<- []model.TargetGroup, item any) {
+	defer e.queue.Done(item)
+
+	key := item.(string)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+
+	obj, exists, err := e.informer.GetStore().GetByKey(key)
+	if err != nil {
+		return
+	}
+
+	if !exists {
+		tgg := &endpointSliceTargetGroup{source: endpointSliceSourceFromNsName(namespace, name)}
+		send(ctx, in, tgg)
+		return
+	}
+
+	eps, err := toEndpointSlice(obj)
+	if err != nil {
+		return
+	}
+
+	tgg := e.buildTargetGroup(eps)
+
+	for _, tgt := range tgg.Targets() {
+		tgt.Tags().Merge(e.Tags())
+	}
+
+	send(ctx, in, tgg)
+}
+
+func (e *endpointSliceDiscoverer) buildTargetGroup(eps *discoveryv1.EndpointSlice) model.TargetGroup {
+	if len(eps.Endpoints) == 0 {
+		return &endpointSliceTargetGroup{
+			source: endpointSliceSource(eps),
+		}
+	}
+	return &endpointSliceTargetGroup{
+		source:  endpointSliceSource(eps),
+		targets: e.buildTargets(eps),
+	}
+}
+
+func (e *endpointSliceDiscoverer) buildTargets(eps *discoveryv1.EndpointSlice) (targets []model.Target) {
+	for _, ep := range eps.Endpoints {
+		// https://kubernetes.io/docs/concepts/services-networking/endpoint-slices/#conditions
+		// nil "ready" and "serving" conditions should be interpreted as "true", nil "terminating" as "false".
+		ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+		serving := ep.Conditions.Serving == nil || *ep.Conditions.Serving
+		terminating := ep.Conditions.Terminating != nil && *ep.Conditions.Terminating
+
+		if terminating && !e.includeTerminating {
+			continue
+		}
+
+		var podName string
+		if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+			podName = ep.TargetRef.Name
+		}
+
+		for _, addr := range ep.Addresses {
+			for _, port := range eps.Ports {
+				if port.Port == nil {
+					continue
+				}
+				portNum := strconv.FormatInt(int64(*port.Port), 10)
+				tgt := &EndpointSliceTarget{
+					tuid:         endpointSliceTUID(eps, addr, port),
+					Address:      joinHostPort(addr, portNum),
+					Namespace:    eps.Namespace,
+					Name:         eps.Name,
+					ServiceName:  eps.Labels[discoveryv1.LabelServiceName],
+					Annotations:  mapAny(eps.Annotations),
+					Labels:       mapAny(eps.Labels),
+					AddressType:  string(eps.AddressType),
+					Port:         portNum,
+					PortName:     derefString(port.Name),
+					PortProtocol: endpointPortProtocol(port),
+					PodName:      podName,
+					NodeName:     derefString(ep.NodeName),
+					Zone:         derefString(ep.Zone),
+					Hostname:     derefString(ep.Hostname),
+					Ready:        ready,
+					Serving:      serving,
+					Terminating:  terminating,
+				}
+				hash, err := calcHash(tgt)
+				if err != nil {
+					continue
+				}
+				tgt.hash = hash
+
+				targets = append(targets, tgt)
+			}
+		}
+	}
+
+	return targets
+}
+
+func endpointPortProtocol(port discoveryv1.EndpointPort) string {
+	// the protocol defaults to TCP if not set
+	if port.Protocol == nil {
+		return "TCP"
+	}
+	return string(*port.Protocol)
+}
+
+func endpointSliceTUID(eps *discoveryv1.EndpointSlice, addr string, port discoveryv1.EndpointPort) string {
+	return fmt.Sprintf("%s_%s_%s_%s_%s",
+		eps.Namespace,
+		eps.Name,
+		strings.ReplaceAll(addr, ":", "-"),
+		strings.ToLower(endpointPortProtocol(port)),
+		strconv.FormatInt(int64(*port.Port), 10),
+	)
+}
+
+func endpointSliceSourceFromNsName(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func endpointSliceSource(eps *discoveryv1.EndpointSlice) string {
+	return endpointSliceSourceFromNsName(eps.Namespace, eps.Name)
+}
+
+func toEndpointSlice(obj any) (*discoveryv1.EndpointSlice, error) {
+	eps, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		return nil, fmt.Errorf("received unexpected object type: %T", obj)
+	}
+	return eps, nil
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
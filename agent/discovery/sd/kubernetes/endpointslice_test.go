@@ -0,0 +1,443 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package kubernetes
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/sdtest"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestEndpointSliceTargetGroup_Provider(t *testing.T) {
+	var e endpointSliceTargetGroup
+	assert.NotEmpty(t, e.Provider())
+}
+
+func TestEndpointSliceTargetGroup_Source(t *testing.T) {
+	tests := map[string]struct {
+		createSim   func() sdtest.Sim
+		wantSources []string
+	}{
+		"slices with multiple endpoints": {
+			createSim: func() sdtest.Sim {
+				httpd, nginx := newHTTPDEndpointSlice(), newNGINXEndpointSlice()
+				disc, _ := prepareAllNsEndpointSliceDiscoverer(httpd, nginx)
+
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
+						prepareEndpointSliceTargetGroup(httpd, false),
+						prepareEndpointSliceTargetGroup(nginx, false),
+					},
+				}
+			},
+			wantSources: []string{
+				"sd:k8s:endpointslice(default/httpd-service-abc12)",
+				"sd:k8s:endpointslice(default/nginx-service-def34)",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim := test.createSim()
+
+			var sources []string
+			for _, tgg := range sim.Run(t) {
+				sources = append(sources, tgg.Source())
+			}
+
+			assert.Equal(t, test.wantSources, sources)
+		})
+	}
+}
+
+func TestEndpointSliceTargetGroup_Targets(t *testing.T) {
+	tests := map[string]struct {
+		createSim   func() sdtest.Sim
+		wantTargets int
+	}{
+		"slices with multiple endpoints": {
+			createSim: func() sdtest.Sim {
+				httpd, nginx := newHTTPDEndpointSlice(), newNGINXEndpointSlice()
+				disc, _ := prepareAllNsEndpointSliceDiscoverer(httpd, nginx)
+
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
+						prepareEndpointSliceTargetGroup(httpd, false),
+						prepareEndpointSliceTargetGroup(nginx, false),
+					},
+				}
+			},
+			wantTargets: 6,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim := test.createSim()
+
+			var targets int
+			for _, tgg := range sim.Run(t) {
+				targets += len(tgg.Targets())
+			}
+
+			assert.Equal(t, test.wantTargets, targets)
+		})
+	}
+}
+
+func TestEndpointSliceTarget_TUID(t *testing.T) {
+	tests := map[string]struct {
+		createSim func() sdtest.Sim
+		wantTUID  []string
+	}{
+		"slices with multiple endpoints": {
+			createSim: func() sdtest.Sim {
+				httpd, nginx := newHTTPDEndpointSlice(), newNGINXEndpointSlice()
+				disc, _ := prepareAllNsEndpointSliceDiscoverer(httpd, nginx)
+
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
+						prepareEndpointSliceTargetGroup(httpd, false),
+						prepareEndpointSliceTargetGroup(nginx, false),
+					},
+				}
+			},
+			wantTUID: []string{
+				"default_httpd-service-abc12_172.17.0.1_tcp_80",
+				"default_httpd-service-abc12_172.17.0.1_tcp_443",
+				"default_httpd-service-abc12_172.17.0.2_tcp_80",
+				"default_httpd-service-abc12_172.17.0.2_tcp_443",
+				"default_nginx-service-def34_172.17.0.3_tcp_80",
+				"default_nginx-service-def34_172.17.0.3_tcp_443",
+			},
+		},
+		"IPv6 slice": {
+			createSim: func() sdtest.Sim {
+				nginx := newNGINXEndpointSlice()
+				nginx.AddressType = discoveryv1.AddressTypeIPv6
+				nginx.Endpoints[0].Addresses = []string{"fd00:10:244::3"}
+				disc, _ := prepareAllNsEndpointSliceDiscoverer(nginx)
+
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
+						prepareEndpointSliceTargetGroup(nginx, false),
+					},
+				}
+			},
+			wantTUID: []string{
+				"default_nginx-service-def34_fd00-10-244--3_tcp_80",
+				"default_nginx-service-def34_fd00-10-244--3_tcp_443",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim := test.createSim()
+
+			var tuid []string
+			for _, tgg := range sim.Run(t) {
+				for _, tgt := range tgg.Targets() {
+					tuid = append(tuid, tgt.TUID())
+				}
+			}
+
+			assert.Equal(t, test.wantTUID, tuid)
+		})
+	}
+}
+
+func TestNewEndpointSliceDiscoverer(t *testing.T) {
+	tests := map[string]struct {
+		informer  cache.SharedInformer
+		wantPanic bool
+	}{
+		"valid informer": {
+			wantPanic: false,
+			informer:  cache.NewSharedInformer(nil, &discoveryv1.EndpointSlice{}, defaultResyncPeriod),
+		},
+		"nil informer": {
+			wantPanic: true,
+			informer:  nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := func() { newEndpointSliceDiscoverer(test.informer) }
+
+			if test.wantPanic {
+				assert.Panics(t, f)
+			} else {
+				assert.NotPanics(t, f)
+			}
+		})
+	}
+}
+
+func TestEndpointSliceDiscoverer_String(t *testing.T) {
+	var e endpointSliceDiscoverer
+	assert.NotEmpty(t, e.String())
+}
+
+func TestEndpointSliceDiscoverer_Discover(t *testing.T) {
+	tests := map[string]func() sdtest.Sim{
+		"ADD: slices exist before run": func() sdtest.Sim {
+			httpd, nginx := newHTTPDEndpointSlice(), newNGINXEndpointSlice()
+			disc, _ := prepareAllNsEndpointSliceDiscoverer(httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					prepareEndpointSliceTargetGroup(httpd, false),
+					prepareEndpointSliceTargetGroup(nginx, false),
+				},
+			}
+		},
+		"ADD: slices exist before run and add after sync": func() sdtest.Sim {
+			httpd, nginx := newHTTPDEndpointSlice(), newNGINXEndpointSlice()
+			disc, client := prepareAllNsEndpointSliceDiscoverer(httpd)
+			epsClient := client.DiscoveryV1().EndpointSlices("default")
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					_, _ = epsClient.Create(ctx, nginx, metav1.CreateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					prepareEndpointSliceTargetGroup(httpd, false),
+					prepareEndpointSliceTargetGroup(nginx, false),
+				},
+			}
+		},
+		"DELETE: slices remove after sync": func() sdtest.Sim {
+			httpd, nginx := newHTTPDEndpointSlice(), newNGINXEndpointSlice()
+			disc, client := prepareAllNsEndpointSliceDiscoverer(httpd, nginx)
+			epsClient := client.DiscoveryV1().EndpointSlices("default")
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					time.Sleep(time.Millisecond * 50)
+					_ = epsClient.Delete(ctx, httpd.Name, metav1.DeleteOptions{})
+					_ = epsClient.Delete(ctx, nginx.Name, metav1.DeleteOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					prepareEndpointSliceTargetGroup(httpd, false),
+					prepareEndpointSliceTargetGroup(nginx, false),
+					prepareEmptyEndpointSliceTargetGroup(httpd),
+					prepareEmptyEndpointSliceTargetGroup(nginx),
+				},
+			}
+		},
+		"UPDATE: only the updated slice is re-emitted": func() sdtest.Sim {
+			httpd, nginx := newHTTPDEndpointSlice(), newNGINXEndpointSlice()
+			httpdUpd := httpd.DeepCopy()
+			httpdUpd.Endpoints = httpdUpd.Endpoints[:1]
+			disc, client := prepareAllNsEndpointSliceDiscoverer(httpd, nginx)
+			epsClient := client.DiscoveryV1().EndpointSlices("default")
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					time.Sleep(time.Millisecond * 50)
+					_, _ = epsClient.Update(ctx, httpdUpd, metav1.UpdateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					prepareEndpointSliceTargetGroup(httpd, false),
+					prepareEndpointSliceTargetGroup(nginx, false),
+					prepareEndpointSliceTargetGroup(httpdUpd, false),
+				},
+			}
+		},
+		"ADD: slices without endpoints": func() sdtest.Sim {
+			httpd, nginx := newHTTPDEndpointSlice(), newNGINXEndpointSlice()
+			httpd.Endpoints = nil
+			nginx.Endpoints = nil
+			disc, _ := prepareAllNsEndpointSliceDiscoverer(httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					prepareEmptyEndpointSliceTargetGroup(httpd),
+					prepareEmptyEndpointSliceTargetGroup(nginx),
+				},
+			}
+		},
+		"ADD: terminating endpoints are excluded": func() sdtest.Sim {
+			httpd := newHTTPDEndpointSlice()
+			setEndpointTerminating(&httpd.Endpoints[1])
+			disc, _ := prepareAllNsEndpointSliceDiscoverer(httpd)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					prepareEndpointSliceTargetGroup(httpd, false),
+				},
+			}
+		},
+		"ADD: terminating endpoints with include_terminating": func() sdtest.Sim {
+			httpd := newHTTPDEndpointSlice()
+			setEndpointTerminating(&httpd.Endpoints[1])
+			disc, _ := prepareAllNsEndpointSliceDiscoverer(httpd)
+			disc.epsConf.IncludeTerminating = true
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					prepareEndpointSliceTargetGroup(httpd, true),
+				},
+			}
+		},
+	}
+
+	for name, createSim := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim := createSim()
+			sim.Run(t)
+		})
+	}
+}
+
+func prepareAllNsEndpointSliceDiscoverer(objects ...runtime.Object) (*KubeDiscoverer, kubernetes.Interface) {
+	return prepareDiscoverer("endpointslice", []string{corev1.NamespaceAll}, objects...)
+}
+
+func newHTTPDEndpointSlice() *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "httpd-service-abc12",
+			Namespace:   "default",
+			Annotations: map[string]string{"phase": "prod"},
+			Labels:      map[string]string{discoveryv1.LabelServiceName: "httpd-service"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			newEndpoint("172.17.0.1", "httpd-dd95c4d68-5bkwl"),
+			newEndpoint("172.17.0.2", "httpd-dd95c4d68-8xzq2"),
+		},
+		Ports: newEndpointPorts(),
+	}
+}
+
+func newNGINXEndpointSlice() *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nginx-service-def34",
+			Namespace:   "default",
+			Annotations: map[string]string{"phase": "prod"},
+			Labels:      map[string]string{discoveryv1.LabelServiceName: "nginx-service"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			newEndpoint("172.17.0.3", "nginx-7cfd77469b-q6kxj"),
+		},
+		Ports: newEndpointPorts(),
+	}
+}
+
+func newEndpoint(addr, podName string) discoveryv1.Endpoint {
+	ready, nodeName, zone := true, "m01", "zone-a"
+	return discoveryv1.Endpoint{
+		Addresses:  []string{addr},
+		Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+		TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: podName, Namespace: "default"},
+		NodeName:   &nodeName,
+		Zone:       &zone,
+	}
+}
+
+func newEndpointPorts() []discoveryv1.EndpointPort {
+	http, https := "http", "https"
+	tcp := corev1.ProtocolTCP
+	httpPort, httpsPort := int32(80), int32(443)
+	return []discoveryv1.EndpointPort{
+		{Name: &http, Protocol: &tcp, Port: &httpPort},
+		{Name: &https, Protocol: &tcp, Port: &httpsPort},
+	}
+}
+
+func setEndpointTerminating(ep *discoveryv1.Endpoint) {
+	ready, serving, terminating := false, true, true
+	ep.Conditions = discoveryv1.EndpointConditions{Ready: &ready, Serving: &serving, Terminating: &terminating}
+}
+
+func prepareEmptyEndpointSliceTargetGroup(eps *discoveryv1.EndpointSlice) *endpointSliceTargetGroup {
+	return &endpointSliceTargetGroup{source: endpointSliceSource(eps)}
+}
+
+func prepareEndpointSliceTargetGroup(eps *discoveryv1.EndpointSlice, includeTerminating bool) *endpointSliceTargetGroup {
+	tgg := prepareEmptyEndpointSliceTargetGroup(eps)
+
+	isTrue := func(v *bool, def bool) bool {
+		if v == nil {
+			return def
+		}
+		return *v
+	}
+
+	for _, ep := range eps.Endpoints {
+		terminating := isTrue(ep.Conditions.Terminating, false)
+		if terminating && !includeTerminating {
+			continue
+		}
+		for _, addr := range ep.Addresses {
+			for _, port := range eps.Ports {
+				portNum := strconv.FormatInt(int64(*port.Port), 10)
+				tgt := &EndpointSliceTarget{
+					tuid:         endpointSliceTUID(eps, addr, port),
+					Address:      net.JoinHostPort(addr, portNum),
+					Namespace:    eps.Namespace,
+					Name:         eps.Name,
+					ServiceName:  eps.Labels[discoveryv1.LabelServiceName],
+					Annotations:  mapAny(eps.Annotations),
+					Labels:       mapAny(eps.Labels),
+					AddressType:  string(eps.AddressType),
+					Port:         portNum,
+					PortName:     *port.Name,
+					PortProtocol: string(*port.Protocol),
+					PodName:      ep.TargetRef.Name,
+					NodeName:     *ep.NodeName,
+					Zone:         *ep.Zone,
+					Ready:        isTrue(ep.Conditions.Ready, true),
+					Serving:      isTrue(ep.Conditions.Serving, true),
+					Terminating:  terminating,
+				}
+				tgt.hash = mustCalcHash(tgt)
+				tgt.Tags().Merge(discoveryTags)
+				tgg.targets = append(tgg.targets, tgt)
+			}
+		}
+	}
+
+	return tgg
+}
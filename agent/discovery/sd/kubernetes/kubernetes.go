@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"strings"
 	"sync"
@@ -16,8 +17,13 @@ import (
 	"github.com/netdata/go.d.plugin/pkg/k8sclient"
 
 	"github.com/ilyam8/hashstructure"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
@@ -38,7 +44,8 @@ func NewKubeDiscoverer(cfg Config) (*KubeDiscoverer, error) {
 		return nil, fmt.Errorf("config validation: %v", err)
 	}
 
-	client, err := k8sclient.New("Netdata/service-td")
+	limits := k8sclient.RateLimits{QPS: cfg.ClientQPS, Burst: cfg.ClientBurst}
+	client, err := k8sclient.NewWithRateLimits("Netdata/service-td", limits)
 	if err != nil {
 		return nil, fmt.Errorf("create clientset: %v", err)
 	}
@@ -48,14 +55,30 @@ func NewKubeDiscoverer(cfg Config) (*KubeDiscoverer, error) {
 		ns = []string{corev1.NamespaceAll}
 	}
 
+	var nsSelector labels.Selector
+	if cfg.NamespaceSelector != "" {
+		if nsSelector, err = labels.Parse(cfg.NamespaceSelector); err != nil {
+			return nil, fmt.Errorf("parse namespace selector: %v", err)
+		}
+	}
+
+	resync := defaultResyncPeriod
+	if cfg.ResyncPeriod != nil {
+		resync = cfg.ResyncPeriod.Duration
+	}
+
 	d := &KubeDiscoverer{
-		Logger:      log,
-		namespaces:  ns,
-		podConf:     cfg.Pod,
-		svcConf:     cfg.Service,
-		client:      client,
-		discoverers: make([]model.Discoverer, 0, len(ns)),
-		started:     make(chan struct{}),
+		Logger:        log,
+		resyncPeriod:  resync,
+		namespaces:    ns,
+		nsSelector:    nsSelector,
+		podConf:       cfg.Pod,
+		svcConf:       cfg.Service,
+		epsConf:       cfg.EndpointSlice,
+		client:        client,
+		discoverers:   make([]model.Discoverer, 0, len(ns)),
+		nsDiscoverers: make(map[string]*namespaceDiscoverer),
+		started:       make(chan struct{}),
 	}
 
 	return d, nil
@@ -66,32 +89,41 @@ type KubeDiscoverer struct {
 
 	podConf *PodConfig
 	svcConf *ServiceConfig
+	epsConf *EndpointSliceConfig
 
-	namespaces  []string
-	client      kubernetes.Interface
-	discoverers []model.Discoverer
-	started     chan struct{}
+	resyncPeriod time.Duration
+	namespaces   []string
+	nsSelector   labels.Selector
+	client       kubernetes.Interface
+	discoverers  []model.Discoverer
+	started      chan struct{}
+
+	nsMux         sync.Mutex
+	nsDiscoverers map[string]*namespaceDiscoverer
 }
 
 func (d *KubeDiscoverer) String() string {
 	return "k8s td manager"
 }
 
-const resyncPeriod = 10 * time.Minute
+const defaultResyncPeriod = 10 * time.Minute
 
 func (d *KubeDiscoverer) Discover(ctx context.Context, in chan<- []model.TargetGroup) {
 	d.Info("instance is started")
 	defer d.Info("instance is stopped")
 
+	if d.nsSelector != nil {
+		d.discoverSelectedNamespaces(ctx, in)
+		return
+	}
+
 	for _, namespace := range d.namespaces {
-		if err := d.setupPodDiscoverer(ctx, d.podConf, namespace); err != nil {
-			d.Errorf("create pod discoverer: %v", err)
-			return
-		}
-		if err := d.setupServiceDiscoverer(ctx, d.svcConf, namespace); err != nil {
-			d.Errorf("create service discoverer: %v", err)
+		discs, err := d.createDiscoverers(ctx, namespace)
+		if err != nil {
+			d.Error(err)
 			return
 		}
+		d.discoverers = append(d.discoverers, discs...)
 	}
 
 	if len(d.discoverers) == 0 {
@@ -136,33 +168,63 @@ func (d *KubeDiscoverer) Discover(ctx context.Context, in chan<- []model.TargetG
 	}
 }
 
-func (d *KubeDiscoverer) setupPodDiscoverer(ctx context.Context, conf *PodConfig, namespace string) error {
-	if conf == nil {
-		return nil
+func (d *KubeDiscoverer) createDiscoverers(ctx context.Context, namespace string) ([]model.Discoverer, error) {
+	var discs []model.Discoverer
+
+	if d.podConf != nil {
+		disc, err := d.createPodDiscoverer(ctx, d.podConf, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("create pod discoverer: %v", err)
+		}
+		discs = append(discs, disc)
+	}
+	if d.svcConf != nil {
+		disc, err := d.createServiceDiscoverer(ctx, d.svcConf, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("create service discoverer: %v", err)
+		}
+		discs = append(discs, disc)
+	}
+	if d.epsConf != nil {
+		disc, err := d.createEndpointSliceDiscoverer(ctx, d.epsConf, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("create endpointslice discoverer: %v", err)
+		}
+		discs = append(discs, disc)
 	}
 
+	return discs, nil
+}
+
+func (d *KubeDiscoverer) createPodDiscoverer(ctx context.Context, conf *PodConfig, namespace string) (model.Discoverer, error) {
+
+	fieldSelector := conf.Selector.Field
 	if conf.LocalMode {
-		name := os.Getenv(envNodeName)
-		if name == "" {
-			return fmt.Errorf("local_mode is enabled, but env '%s' not set", envNodeName)
+		env := conf.NodeNameEnv
+		if env == "" {
+			env = envNodeName
+		}
+		if name := os.Getenv(env); name != "" {
+			fieldSelector = joinSelectors(fieldSelector, "spec.nodeName="+name)
+		} else {
+			d.Warningf("local_mode is enabled, but env '%s' not set, watching pods on all nodes", env)
 		}
-		conf.Selector.Field = joinSelectors(conf.Selector.Field, "spec.nodeName="+name)
 	}
 
 	tags, err := model.ParseTags(conf.Tags)
 	if err != nil {
-		return fmt.Errorf("parse tags: %v", err)
+		return nil, fmt.Errorf("parse tags: %v", err)
 	}
 
 	pod := d.client.CoreV1().Pods(namespace)
 	podLW := &cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-			options.FieldSelector = conf.Selector.Field
+			options.FieldSelector = fieldSelector
 			options.LabelSelector = conf.Selector.Label
 			return pod.List(ctx, options)
 		},
 		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-			options.FieldSelector = conf.Selector.Field
+			options.FieldSelector = fieldSelector
 			options.LabelSelector = conf.Selector.Label
 			return pod.Watch(ctx, options)
 		},
@@ -178,36 +240,94 @@ func (d *KubeDiscoverer) setupPodDiscoverer(ctx context.Context, conf *PodConfig
 		},
 	}
 
-	secret := d.client.CoreV1().Secrets(namespace)
-	secretLW := &cache.ListWatch{
+	var secretInf cache.SharedInformer
+	if conf.ResolveSecretEnv == nil || *conf.ResolveSecretEnv {
+		secret := d.client.CoreV1().Secrets(namespace)
+		if _, err := secret.List(ctx, metav1.ListOptions{Limit: 1}); apierrors.IsForbidden(err) {
+			d.Warningf("namespace '%s': no permission to list secrets, env vars from secrets will not be resolved: %v",
+				namespace, err)
+		} else {
+			secretLW := &cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return secret.List(ctx, options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return secret.Watch(ctx, options)
+				},
+			}
+			secretInf = cache.NewSharedInformer(secretLW, &corev1.Secret{}, d.resyncPeriod)
+		}
+	}
+
+	podInf := cache.NewSharedInformer(podLW, &corev1.Pod{}, d.resyncPeriod)
+	cmapInf := cache.NewSharedInformer(cmapLW, &corev1.ConfigMap{}, d.resyncPeriod)
+
+	tr := objectTransformer{
+		stripAnnotations:  defaultStripAnnotations,
+		maxAnnotationSize: conf.MaxAnnotationSize,
+		keepPrefix:        conf.AnnotationPrefix,
+	}
+	if conf.StripAnnotations != nil {
+		tr.stripAnnotations = conf.StripAnnotations
+	}
+	if tr.keepPrefix == "" {
+		tr.keepPrefix = defaultAnnotationPrefix
+	}
+	for _, inf := range []cache.SharedInformer{podInf, cmapInf, secretInf} {
+		if inf == nil {
+			continue
+		}
+		if err := inf.SetTransform(tr.transform); err != nil {
+			return nil, fmt.Errorf("set informer transform: %v", err)
+		}
+	}
+
+	rs := d.client.AppsV1().ReplicaSets(namespace)
+	rsLW := &cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-			return secret.List(ctx, options)
+			return rs.List(ctx, options)
 		},
 		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-			return secret.Watch(ctx, options)
+			return rs.Watch(ctx, options)
+		},
+	}
+
+	job := d.client.BatchV1().Jobs(namespace)
+	jobLW := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return job.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return job.Watch(ctx, options)
 		},
 	}
 
 	td := newPodDiscoverer(
-		cache.NewSharedInformer(podLW, &corev1.Pod{}, resyncPeriod),
-		cache.NewSharedInformer(cmapLW, &corev1.ConfigMap{}, resyncPeriod),
-		cache.NewSharedInformer(secretLW, &corev1.Secret{}, resyncPeriod),
+		podInf,
+		cmapInf,
+		secretInf,
+		cache.NewSharedInformer(rsLW, &appsv1.ReplicaSet{}, d.resyncPeriod),
+		cache.NewSharedInformer(jobLW, &batchv1.Job{}, d.resyncPeriod),
 	)
+	if conf.AddressMode != "" {
+		td.addressMode = conf.AddressMode
+	}
+	if conf.AnnotationPrefix != "" {
+		td.annotationPrefix = conf.AnnotationPrefix
+	}
+	td.ipFamily = conf.IPFamily
+	td.includeInitContainers = conf.IncludeInitContainers
+	td.onlyRunning = conf.OnlyRunning
+	td.onlyReady = conf.OnlyReady
 	td.Tags().Merge(tags)
 
-	d.discoverers = append(d.discoverers, td)
-
-	return nil
+	return td, nil
 }
 
-func (d *KubeDiscoverer) setupServiceDiscoverer(ctx context.Context, conf *ServiceConfig, namespace string) error {
-	if conf == nil {
-		return nil
-	}
-
+func (d *KubeDiscoverer) createServiceDiscoverer(ctx context.Context, conf *ServiceConfig, namespace string) (model.Discoverer, error) {
 	tags, err := model.ParseTags(conf.Tags)
 	if err != nil {
-		return fmt.Errorf("parse tags: %v", err)
+		return nil, fmt.Errorf("parse tags: %v", err)
 	}
 
 	svc := d.client.CoreV1().Services(namespace)
@@ -225,14 +345,42 @@ func (d *KubeDiscoverer) setupServiceDiscoverer(ctx context.Context, conf *Servi
 		},
 	}
 
-	inf := cache.NewSharedInformer(svcLW, &corev1.Service{}, resyncPeriod)
+	inf := cache.NewSharedInformer(svcLW, &corev1.Service{}, d.resyncPeriod)
 
 	td := newServiceDiscoverer(inf)
 	td.Tags().Merge(tags)
 
-	d.discoverers = append(d.discoverers, td)
+	return td, nil
+}
+
+func (d *KubeDiscoverer) createEndpointSliceDiscoverer(ctx context.Context, conf *EndpointSliceConfig, namespace string) (model.Discoverer, error) {
+	tags, err := model.ParseTags(conf.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("parse tags: %v", err)
+	}
+
+	eps := d.client.DiscoveryV1().EndpointSlices(namespace)
 
-	return nil
+	epsLW := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = conf.Selector.Field
+			options.LabelSelector = conf.Selector.Label
+			return eps.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = conf.Selector.Field
+			options.LabelSelector = conf.Selector.Label
+			return eps.Watch(ctx, options)
+		},
+	}
+
+	inf := cache.NewSharedInformer(epsLW, &discoveryv1.EndpointSlice{}, d.resyncPeriod)
+
+	td := newEndpointSliceDiscoverer(inf)
+	td.includeTerminating = conf.IncludeTerminating
+	td.Tags().Merge(tags)
+
+	return td, nil
 }
 
 func enqueue(queue *workqueue.Type, obj any) {
@@ -257,6 +405,15 @@ func calcHash(obj any) (uint64, error) {
 	return hashstructure.Hash(obj, nil)
 }
 
+func joinHostPort(host, port string) string {
+	// net.JoinHostPort adds brackets to IPv6 literals itself
+	return net.JoinHostPort(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"), port)
+}
+
+func isIPv6(ip string) bool {
+	return strings.IndexByte(ip, ':') != -1
+}
+
 func joinSelectors(srs ...string) string {
 	var i int
 	for _, v := range srs {
@@ -3,19 +3,28 @@
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/sdtest"
 	"github.com/netdata/go.d.plugin/pkg/k8sclient"
+	"github.com/netdata/go.d.plugin/pkg/web"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var discoveryTags model.Tags = map[string]struct{}{"k8s": {}}
@@ -46,10 +55,46 @@ func TestNewKubeDiscoverer(t *testing.T) {
 			wantErr: false,
 			cfg:     Config{Service: &ServiceConfig{}},
 		},
+		"endpointslice config": {
+			wantErr: false,
+			cfg:     Config{EndpointSlice: &EndpointSliceConfig{}},
+		},
+		"pod config with namespace selector": {
+			wantErr: false,
+			cfg:     Config{NamespaceSelector: "netdata.io/monitor=true", Pod: &PodConfig{}},
+		},
 		"empty config": {
 			wantErr: true,
 			cfg:     Config{},
 		},
+		"namespaces and namespace selector": {
+			wantErr: true,
+			cfg:     Config{Namespaces: []string{"default"}, NamespaceSelector: "netdata.io/monitor=true", Pod: &PodConfig{}},
+		},
+		"pod config with unknown address mode": {
+			wantErr: true,
+			cfg:     Config{Pod: &PodConfig{AddressMode: "node"}},
+		},
+		"pod config with unknown ip family": {
+			wantErr: true,
+			cfg:     Config{Pod: &PodConfig{IPFamily: "ipv5"}},
+		},
+		"invalid namespace selector": {
+			wantErr: true,
+			cfg:     Config{NamespaceSelector: "netdata.io/monitor in (", Pod: &PodConfig{}},
+		},
+		"resync period less than 1m": {
+			wantErr: true,
+			cfg:     Config{ResyncPeriod: &web.Duration{Duration: time.Second * 30}, Pod: &PodConfig{}},
+		},
+		"negative client qps": {
+			wantErr: true,
+			cfg:     Config{ClientQPS: -1, Pod: &PodConfig{}},
+		},
+		"negative client burst": {
+			wantErr: true,
+			cfg:     Config{ClientBurst: -1, Pod: &PodConfig{}},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -66,14 +111,43 @@ func TestNewKubeDiscoverer(t *testing.T) {
 	}
 }
 
+func TestNewKubeDiscoverer_ResyncPeriod(t *testing.T) {
+	tests := map[string]struct {
+		resync     *web.Duration
+		wantResync time.Duration
+	}{
+		"not set": {
+			resync:     nil,
+			wantResync: defaultResyncPeriod,
+		},
+		"disabled": {
+			resync:     &web.Duration{},
+			wantResync: 0,
+		},
+		"configured": {
+			resync:     &web.Duration{Duration: time.Minute * 30},
+			wantResync: time.Minute * 30,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			disc, err := NewKubeDiscoverer(Config{ResyncPeriod: test.resync, ClientQPS: 50, ClientBurst: 100, Pod: &PodConfig{}})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.wantResync, disc.resyncPeriod)
+		})
+	}
+}
+
 func TestKubeDiscoverer_Discover(t *testing.T) {
 	const prod = "prod"
 	const dev = "dev"
 	prodNamespace := newNamespace(prod)
 	devNamespace := newNamespace(dev)
 
-	tests := map[string]func() discoverySim{
-		"multiple namespaces pod td": func() discoverySim {
+	tests := map[string]func() sdtest.Sim{
+		"multiple namespaces pod td": func() sdtest.Sim {
 			httpdProd, nginxProd := newHTTPDPod(), newNGINXPod()
 			httpdProd.Namespace = prod
 			nginxProd.Namespace = prod
@@ -86,10 +160,11 @@ func TestKubeDiscoverer_Discover(t *testing.T) {
 				[]string{prod, dev},
 				prodNamespace, devNamespace, httpdProd, nginxProd, httpdDev, nginxDev)
 
-			return discoverySim{
-				td:               disc,
-				sortBeforeVerify: true,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer:  disc,
+				Synced:      disc.synced,
+				IgnoreOrder: true,
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroup(httpdDev),
 					preparePodTargetGroup(nginxDev),
 					preparePodTargetGroup(httpdProd),
@@ -97,7 +172,7 @@ func TestKubeDiscoverer_Discover(t *testing.T) {
 				},
 			}
 		},
-		"multiple namespaces ClusterIP service td": func() discoverySim {
+		"multiple namespaces ClusterIP service td": func() sdtest.Sim {
 			httpdProd, nginxProd := newHTTPDClusterIPService(), newNGINXClusterIPService()
 			httpdProd.Namespace = prod
 			nginxProd.Namespace = prod
@@ -110,10 +185,11 @@ func TestKubeDiscoverer_Discover(t *testing.T) {
 				[]string{prod, dev},
 				prodNamespace, devNamespace, httpdProd, nginxProd, httpdDev, nginxDev)
 
-			return discoverySim{
-				td:               disc,
-				sortBeforeVerify: true,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer:  disc,
+				Synced:      disc.synced,
+				IgnoreOrder: true,
+				WantTargetGroups: []model.TargetGroup{
 					prepareSvcTargetGroup(httpdDev),
 					prepareSvcTargetGroup(nginxDev),
 					prepareSvcTargetGroup(httpdProd),
@@ -121,29 +197,197 @@ func TestKubeDiscoverer_Discover(t *testing.T) {
 				},
 			}
 		},
+		"namespace selector pod td": func() sdtest.Sim {
+			prodNs, devNs := newNamespace(prod), newNamespace(dev)
+			prodNs.Labels = map[string]string{"netdata.io/monitor": "true"}
+
+			httpdProd, httpdDev := newHTTPDPod(), newHTTPDPod()
+			httpdProd.Namespace = prod
+			httpdDev.Namespace = dev
+
+			disc, _ := prepareNsSelectorPodDiscoverer("netdata.io/monitor=true", prodNs, devNs, httpdProd, httpdDev)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpdProd),
+				},
+			}
+		},
+		"namespace selector pod td with labels changed after sync": func() sdtest.Sim {
+			prodNs, devNs := newNamespace(prod), newNamespace(dev)
+			prodNs.Labels = map[string]string{"netdata.io/monitor": "true"}
+
+			httpdProd, httpdDev := newHTTPDPod(), newHTTPDPod()
+			httpdProd.Namespace = prod
+			httpdDev.Namespace = dev
+
+			disc, client := prepareNsSelectorPodDiscoverer("netdata.io/monitor=true", prodNs, devNs, httpdProd, httpdDev)
+			nsClient := client.CoreV1().Namespaces()
+
+			prodNsUpd, devNsUpd := newNamespace(prod), newNamespace(dev)
+			devNsUpd.Labels = map[string]string{"netdata.io/monitor": "true"}
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					time.Sleep(time.Millisecond * 50)
+					_, _ = nsClient.Update(ctx, prodNsUpd, metav1.UpdateOptions{})
+					time.Sleep(time.Millisecond * 50)
+					_, _ = nsClient.Update(ctx, devNsUpd, metav1.UpdateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpdProd),
+					prepareEmptyPodTargetGroup(httpdProd),
+					preparePodTargetGroup(httpdDev),
+				},
+			}
+		},
+		"local mode pod td": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			nginx.Spec.NodeName = "m02"
+
+			disc, client := prepareLocalModePodDiscoverer("", httpd, nginx)
+			podClient := client.CoreV1().Pods("default")
+
+			nginxLocal := newNGINXPod()
+			nginxLocal.Name = "nginx-7cfd77469b-xk7lr"
+			nginxRemote := newNGINXPod()
+			nginxRemote.Name = "nginx-7cfd77469b-r5zgf"
+			nginxRemote.Spec.NodeName = "m02"
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					_, _ = podClient.Create(ctx, nginxRemote, metav1.CreateOptions{})
+					_, _ = podClient.Create(ctx, nginxLocal, metav1.CreateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					preparePodTargetGroup(nginxLocal),
+				},
+			}
+		},
+		"local mode pod td with node name env not set": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			nginx.Spec.NodeName = "m02"
+
+			disc, _ := prepareLocalModePodDiscoverer("NOT_SET_NODE_NAME_ENV", httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer:  disc,
+				Synced:      disc.synced,
+				IgnoreOrder: true,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					preparePodTargetGroup(nginx),
+				},
+			}
+		},
 	}
 
 	for name, createSim := range tests {
 		t.Run(name, func(t *testing.T) {
 			sim := createSim()
-			sim.run(t)
+			sim.Run(t)
+		})
+	}
+}
+
+func TestJoinHostPort(t *testing.T) {
+	tests := map[string]struct {
+		host string
+		want string
+	}{
+		"ipv4":                   {host: "172.17.0.1", want: "172.17.0.1:80"},
+		"ipv6":                   {host: "fd00:10:244::1", want: "[fd00:10:244::1]:80"},
+		"bracketed ipv6 literal": {host: "[fd00:10:244::1]", want: "[fd00:10:244::1]:80"},
+		"hostname":               {host: "httpd.example.com", want: "httpd.example.com:80"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, joinHostPort(test.host, "80"))
 		})
 	}
 }
 
+func prepareNsSelectorPodDiscoverer(selector string, objects ...runtime.Object) (*KubeDiscoverer, kubernetes.Interface) {
+	disc, client := prepareDiscoverer("pod", nil, objects...)
+	sr, err := labels.Parse(selector)
+	if err != nil {
+		panic(fmt.Sprintf("parse namespace selector: %v", err))
+	}
+	disc.nsSelector = sr
+	return disc, client
+}
+
+func prepareLocalModePodDiscoverer(nodeNameEnv string, objects ...runtime.Object) (*KubeDiscoverer, kubernetes.Interface) {
+	disc, client := prepareAllNsPodDiscoverer(objects...)
+	disc.podConf.LocalMode = true
+	disc.podConf.NodeNameEnv = nodeNameEnv
+	addPodNodeNameFieldSelectorReactors(client.(*fake.Clientset))
+	return disc, client
+}
+
+// addPodNodeNameFieldSelectorReactors makes the fake client respect the 'spec.nodeName' field selector,
+// the object tracker only supports label selectors.
+func addPodNodeNameFieldSelectorReactors(client *fake.Clientset) {
+	gvr := corev1.SchemeGroupVersion.WithResource("pods")
+	gvk := corev1.SchemeGroupVersion.WithKind("Pod")
+	match := func(sel fields.Selector, obj runtime.Object) bool {
+		pod, ok := obj.(*corev1.Pod)
+		return ok && sel.Matches(fields.Set{"spec.nodeName": pod.Spec.NodeName})
+	}
+
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		act := action.(k8stesting.ListAction)
+		obj, err := client.Tracker().List(gvr, gvk, act.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		list := obj.(*corev1.PodList)
+		var pods []corev1.Pod
+		for _, pod := range list.Items {
+			if match(act.GetListRestrictions().Fields, &pod) {
+				pods = append(pods, pod)
+			}
+		}
+		list.Items = pods
+		return true, list, nil
+	})
+	client.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		act := action.(k8stesting.WatchAction)
+		w, err := client.Tracker().Watch(gvr, act.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		return true, watch.Filter(w, func(e watch.Event) (watch.Event, bool) {
+			return e, match(act.GetWatchRestrictions().Fields, e.Object)
+		}), nil
+	})
+}
+
 func prepareDiscoverer(role string, namespaces []string, objects ...runtime.Object) (*KubeDiscoverer, kubernetes.Interface) {
 	client := fake.NewSimpleClientset(objects...)
 	disc := &KubeDiscoverer{
-		namespaces:  namespaces,
-		client:      client,
-		discoverers: nil,
-		started:     make(chan struct{}),
+		resyncPeriod:  defaultResyncPeriod,
+		namespaces:    namespaces,
+		client:        client,
+		discoverers:   nil,
+		nsDiscoverers: make(map[string]*namespaceDiscoverer),
+		started:       make(chan struct{}),
 	}
 	switch role {
 	case "pod":
 		disc.podConf = &PodConfig{Tags: "k8s"}
 	case "svc":
 		disc.svcConf = &ServiceConfig{Tags: "k8s"}
+	case "endpointslice":
+		disc.epsConf = &EndpointSliceConfig{Tags: "k8s"}
 	}
 	return disc, client
 }
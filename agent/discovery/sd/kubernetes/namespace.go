@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package kubernetes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// namespaceDiscoverer runs the role discoverers of a single namespace selected by the namespace selector.
+type namespaceDiscoverer struct {
+	discoverers []model.Discoverer
+	cancel      context.CancelFunc
+	done        chan struct{}
+
+	// last non-empty target group per source, used to clean up when the namespace is deselected
+	sources map[string]model.TargetGroup
+}
+
+func (d *KubeDiscoverer) discoverSelectedNamespaces(ctx context.Context, in chan<- []model.TargetGroup) {
+	ns := d.client.CoreV1().Namespaces()
+	// Not filtered by the selector on the server side: we need to see namespaces that lose the labels.
+	nsLW := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return ns.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return ns.Watch(ctx, options)
+		},
+	}
+
+	inf := cache.NewSharedInformer(nsLW, &corev1.Namespace{}, d.resyncPeriod)
+	queue := workqueue.NewWithConfig(workqueue.QueueConfig{Name: "namespace"})
+	defer queue.ShutDown()
+
+	_, _ = inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj any) { enqueue(queue, obj) },
+		UpdateFunc: func(_, obj any) { enqueue(queue, obj) },
+		DeleteFunc: func(obj any) { enqueue(queue, obj) },
+	})
+
+	go inf.Run(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), inf.HasSynced) {
+		d.Error("failed to sync namespace cache")
+		return
+	}
+
+	updates := make(chan []model.TargetGroup)
+
+	for _, key := range inf.GetStore().ListKeys() {
+		d.handleNamespace(ctx, inf, key, updates)
+	}
+
+	d.Infof("namespace selector '%s': watching %d namespace(s)", d.nsSelector, d.namespacesNum())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			item, shutdown := queue.Get()
+			if shutdown {
+				return
+			}
+			d.handleNamespace(ctx, inf, item.(string), updates)
+			queue.Done(item)
+		}
+	}()
+
+	close(d.started)
+
+	for {
+		select {
+		case <-ctx.Done():
+			queue.ShutDown()
+			<-done
+			d.stopAllNamespaces()
+			return
+		case tggs := <-updates:
+			select {
+			case <-ctx.Done():
+			case in <- tggs:
+			}
+		}
+	}
+}
+
+func (d *KubeDiscoverer) handleNamespace(ctx context.Context, inf cache.SharedInformer, name string, updates chan []model.TargetGroup) {
+	obj, exists, err := inf.GetStore().GetByKey(name)
+	if err != nil {
+		return
+	}
+
+	if exists {
+		ns, ok := obj.(*corev1.Namespace)
+		if ok && d.nsSelector.Matches(labels.Set(ns.Labels)) {
+			if err := d.startNamespace(ctx, name, updates); err != nil {
+				d.Errorf("namespace '%s': %v", name, err)
+			}
+			return
+		}
+	}
+
+	d.stopNamespace(ctx, name, updates)
+}
+
+func (d *KubeDiscoverer) startNamespace(ctx context.Context, name string, updates chan []model.TargetGroup) error {
+	d.nsMux.Lock()
+	_, ok := d.nsDiscoverers[name]
+	d.nsMux.Unlock()
+	if ok {
+		return nil
+	}
+
+	nsCtx, cancel := context.WithCancel(ctx)
+
+	discs, err := d.createDiscoverers(nsCtx, name)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	nd := &namespaceDiscoverer{
+		discoverers: discs,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+		sources:     make(map[string]model.TargetGroup),
+	}
+
+	var wg sync.WaitGroup
+	nsUpdates := make(chan []model.TargetGroup)
+	for _, disc := range discs {
+		wg.Add(1)
+		go func(disc model.Discoverer) { defer wg.Done(); disc.Discover(nsCtx, nsUpdates) }(disc)
+	}
+
+	stopped := make(chan struct{})
+	go func() { defer close(stopped); wg.Wait() }()
+
+	go func() {
+		defer close(nd.done)
+		for {
+			select {
+			case <-stopped:
+				return
+			case tggs := <-nsUpdates:
+				for _, tgg := range tggs {
+					if len(tgg.Targets()) == 0 {
+						delete(nd.sources, tgg.Source())
+					} else {
+						nd.sources[tgg.Source()] = tgg
+					}
+				}
+				select {
+				case <-nsCtx.Done():
+				case updates <- tggs:
+				}
+			}
+		}
+	}()
+
+	d.Infof("namespace '%s' matches the selector, started discoverers: %v", name, discs)
+
+	d.nsMux.Lock()
+	d.nsDiscoverers[name] = nd
+	d.nsMux.Unlock()
+
+	return nil
+}
+
+func (d *KubeDiscoverer) stopNamespace(ctx context.Context, name string, updates chan []model.TargetGroup) {
+	d.nsMux.Lock()
+	nd, ok := d.nsDiscoverers[name]
+	delete(d.nsDiscoverers, name)
+	d.nsMux.Unlock()
+	if !ok {
+		return
+	}
+
+	nd.cancel()
+	<-nd.done
+
+	d.Infof("namespace '%s' no longer matches the selector, stopped discoverers", name)
+
+	if len(nd.sources) == 0 {
+		return
+	}
+
+	tggs := make([]model.TargetGroup, 0, len(nd.sources))
+	for _, tgg := range nd.sources {
+		if v := emptyTargetGroup(tgg); v != nil {
+			tggs = append(tggs, v)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+	case updates <- tggs:
+	}
+}
+
+func (d *KubeDiscoverer) stopAllNamespaces() {
+	d.nsMux.Lock()
+	defer d.nsMux.Unlock()
+
+	for name, nd := range d.nsDiscoverers {
+		nd.cancel()
+		<-nd.done
+		delete(d.nsDiscoverers, name)
+	}
+}
+
+func (d *KubeDiscoverer) namespacesNum() int {
+	d.nsMux.Lock()
+	defer d.nsMux.Unlock()
+	return len(d.nsDiscoverers)
+}
+
+func emptyTargetGroup(tgg model.TargetGroup) model.TargetGroup {
+	switch v := tgg.(type) {
+	case *podTargetGroup:
+		return &podTargetGroup{source: v.source}
+	case *serviceTargetGroup:
+		return &serviceTargetGroup{source: v.source}
+	case *endpointSliceTargetGroup:
+		return &endpointSliceTargetGroup{source: v.source}
+	default:
+		return nil
+	}
+}
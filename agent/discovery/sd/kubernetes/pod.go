@@ -5,7 +5,6 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"net"
 	"strconv"
 	"strings"
 
@@ -13,10 +12,32 @@ import (
 	"github.com/netdata/go.d.plugin/logger"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
+const (
+	addressModePodIP = "pod_ip"
+	addressModeHost  = "host"
+)
+
+const (
+	defaultAnnotationPrefix = "netdata.io/"
+
+	annotationScrape = "scrape"
+	annotationPort   = "port"
+	annotationPorts  = "ports"
+	annotationPath   = "path"
+	annotationScheme = "scheme"
+)
+
+const (
+	ipFamilyIPv4 = "ipv4"
+	ipFamilyIPv6 = "ipv6"
+	ipFamilyAll  = "all"
+)
+
 type podTargetGroup struct {
 	targets []model.Target
 	source  string
@@ -26,6 +47,7 @@ func (p podTargetGroup) Provider() string        { return "sd:k8s:pod" }
 func (p podTargetGroup) Source() string          { return fmt.Sprintf("%s(%s)", p.Provider(), p.source) }
 func (p podTargetGroup) Targets() []model.Target { return p.targets }
 
+// PodTarget fields are available in the classify and compose rules templates, see README.md.
 type PodTarget struct {
 	model.Base `hash:"ignore"`
 
@@ -47,15 +69,26 @@ type PodTarget struct {
 	Port           string
 	PortName       string
 	PortProtocol   string
+	AddressMode    string
+	Path           string
+	Scheme         string
+	CPURequest     string
+	CPULimit       string
+	MemoryRequest  string
+	MemoryLimit    string
+	Ready          bool
+	// RestartCount is not part of the hash: a container restart should not trigger re-discovery.
+	RestartCount int `hash:"ignore"`
 }
 
 func (p PodTarget) Hash() uint64 { return p.hash }
 func (p PodTarget) TUID() string { return p.tuid }
 
-func newPodDiscoverer(pod, cmap, secret cache.SharedInformer) *podDiscoverer {
-
-	if pod == nil || cmap == nil || secret == nil {
-		panic("nil pod or cmap or secret informer")
+// newPodDiscoverer creates a pod discoverer. The secret informer is optional,
+// env vars sourced from Secrets are not resolved if it is nil.
+func newPodDiscoverer(pod, cmap, secret, rs, job cache.SharedInformer) *podDiscoverer {
+	if pod == nil || cmap == nil || rs == nil || job == nil {
+		panic("nil pod or cmap or replicaset or job informer")
 	}
 
 	queue := workqueue.NewWithConfig(workqueue.QueueConfig{Name: "pod"})
@@ -67,11 +100,15 @@ func newPodDiscoverer(pod, cmap, secret cache.SharedInformer) *podDiscoverer {
 	})
 
 	return &podDiscoverer{
-		Logger:         log,
-		podInformer:    pod,
-		cmapInformer:   cmap,
-		secretInformer: secret,
-		queue:          queue,
+		Logger:           log,
+		addressMode:      addressModePodIP,
+		annotationPrefix: defaultAnnotationPrefix,
+		podInformer:      pod,
+		cmapInformer:     cmap,
+		secretInformer:   secret,
+		rsInformer:       rs,
+		jobInformer:      job,
+		queue:            queue,
 	}
 }
 
@@ -82,7 +119,18 @@ type podDiscoverer struct {
 	podInformer    cache.SharedInformer
 	cmapInformer   cache.SharedInformer
 	secretInformer cache.SharedInformer
+	rsInformer     cache.SharedInformer
+	jobInformer    cache.SharedInformer
 	queue          *workqueue.Type
+
+	annotationPrefix      string
+	addressMode           string
+	ipFamily              string
+	includeInitContainers bool
+	onlyRunning           bool
+	onlyReady             bool
+
+	secretEnvWarned bool
 }
 
 func (p *podDiscoverer) String() string {
@@ -94,12 +142,20 @@ func (p *podDiscoverer) Discover(ctx context.Context, in chan<- []model.TargetGr
 	defer p.Info("instance is stopped")
 	defer p.queue.ShutDown()
 
+	synced := []cache.InformerSynced{
+		p.podInformer.HasSynced, p.cmapInformer.HasSynced, p.rsInformer.HasSynced, p.jobInformer.HasSynced,
+	}
+
 	go p.podInformer.Run(ctx.Done())
 	go p.cmapInformer.Run(ctx.Done())
-	go p.secretInformer.Run(ctx.Done())
+	go p.rsInformer.Run(ctx.Done())
+	go p.jobInformer.Run(ctx.Done())
+	if p.secretInformer != nil {
+		go p.secretInformer.Run(ctx.Done())
+		synced = append(synced, p.secretInformer.HasSynced)
+	}
 
-	if !cache.WaitForCacheSync(ctx.Done(),
-		p.podInformer.HasSynced, p.cmapInformer.HasSynced, p.secretInformer.HasSynced) {
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
 		p.Error("failed to sync caches")
 		return
 	}
@@ -155,7 +211,9 @@ func (p *podDiscoverer) handleQueueItem(ctx context.Context, in chan<- []model.T
 }
 
 func (p *podDiscoverer) buildTargetGroup(pod *corev1.Pod) model.TargetGroup {
-	if pod.Status.PodIP == "" || len(pod.Spec.Containers) == 0 {
+	if len(p.podHosts(pod)) == 0 || len(pod.Spec.Containers) == 0 || p.isScrapeDisabled(pod) ||
+		(p.onlyRunning && !isPodRunning(pod)) ||
+		(p.onlyReady && !isPodReady(pod)) {
 		return &podTargetGroup{
 			source: podSource(pod),
 		}
@@ -167,61 +225,40 @@ func (p *podDiscoverer) buildTargetGroup(pod *corev1.Pod) model.TargetGroup {
 }
 
 func (p *podDiscoverer) buildTargets(pod *corev1.Pod) (targets []model.Target) {
-	var name, kind string
-	for _, ref := range pod.OwnerReferences {
-		if ref.Controller != nil && *ref.Controller {
-			name = ref.Name
-			kind = ref.Kind
-			break
-		}
-	}
+	name, kind := p.resolveController(pod)
+	hosts := p.podHosts(pod)
+	path, scheme := p.annotation(pod, annotationPath), p.annotation(pod, annotationScheme)
 
-	for _, container := range pod.Spec.Containers {
-		env := p.collectEnv(pod.Namespace, container)
+	for _, container := range p.restrictToAnnotatedPort(pod, p.withAnnotatedPorts(pod, p.podContainers(pod))) {
+		env := p.collectEnv(pod.Namespace, container.Container)
+		res := container.Resources
+		ready, restarts := containerState(pod, container)
 
 		if len(container.Ports) == 0 {
-			tgt := &PodTarget{
-				tuid:           podTUID(pod, container),
-				Address:        pod.Status.PodIP,
-				Namespace:      pod.Namespace,
-				Name:           pod.Name,
-				Annotations:    mapAny(pod.Annotations),
-				Labels:         mapAny(pod.Labels),
-				NodeName:       pod.Spec.NodeName,
-				PodIP:          pod.Status.PodIP,
-				ControllerName: name,
-				ControllerKind: kind,
-				ContName:       container.Name,
-				Image:          container.Image,
-				Env:            mapAny(env),
-			}
-			hash, err := calcHash(tgt)
-			if err != nil {
-				continue
-			}
-			tgt.hash = hash
-
-			targets = append(targets, tgt)
-		} else {
-			for _, port := range container.Ports {
-				portNum := strconv.FormatUint(uint64(port.ContainerPort), 10)
+			for _, host := range hosts {
 				tgt := &PodTarget{
-					tuid:           podTUIDWithPort(pod, container, port),
-					Address:        net.JoinHostPort(pod.Status.PodIP, portNum),
+					tuid:           p.tuid(podTUID(pod, container), host),
+					Address:        host,
 					Namespace:      pod.Namespace,
 					Name:           pod.Name,
 					Annotations:    mapAny(pod.Annotations),
 					Labels:         mapAny(pod.Labels),
 					NodeName:       pod.Spec.NodeName,
-					PodIP:          pod.Status.PodIP,
+					PodIP:          p.podIP(pod, host),
 					ControllerName: name,
 					ControllerKind: kind,
 					ContName:       container.Name,
 					Image:          container.Image,
 					Env:            mapAny(env),
-					Port:           portNum,
-					PortName:       port.Name,
-					PortProtocol:   string(port.Protocol),
+					AddressMode:    p.addressMode,
+					Path:           path,
+					Scheme:         scheme,
+					CPURequest:     quantityString(res.Requests, corev1.ResourceCPU),
+					CPULimit:       quantityString(res.Limits, corev1.ResourceCPU),
+					MemoryRequest:  quantityString(res.Requests, corev1.ResourceMemory),
+					MemoryLimit:    quantityString(res.Limits, corev1.ResourceMemory),
+					Ready:          ready,
+					RestartCount:   restarts,
 				}
 				hash, err := calcHash(tgt)
 				if err != nil {
@@ -231,12 +268,304 @@ func (p *podDiscoverer) buildTargets(pod *corev1.Pod) (targets []model.Target) {
 
 				targets = append(targets, tgt)
 			}
+		} else {
+			for _, port := range container.Ports {
+				portNum := p.podPort(pod, port)
+				if portNum == "" {
+					continue
+				}
+				for _, host := range hosts {
+					tgt := &PodTarget{
+						tuid:           p.tuid(podTUIDWithPort(pod, container, port), host),
+						Address:        joinHostPort(host, portNum),
+						Namespace:      pod.Namespace,
+						Name:           pod.Name,
+						Annotations:    mapAny(pod.Annotations),
+						Labels:         mapAny(pod.Labels),
+						NodeName:       pod.Spec.NodeName,
+						PodIP:          p.podIP(pod, host),
+						ControllerName: name,
+						ControllerKind: kind,
+						ContName:       container.Name,
+						Image:          container.Image,
+						Env:            mapAny(env),
+						Port:           portNum,
+						PortName:       port.Name,
+						PortProtocol:   string(port.Protocol),
+						AddressMode:    p.addressMode,
+						Path:           path,
+						Scheme:         scheme,
+						CPURequest:     quantityString(res.Requests, corev1.ResourceCPU),
+						CPULimit:       quantityString(res.Limits, corev1.ResourceCPU),
+						MemoryRequest:  quantityString(res.Requests, corev1.ResourceMemory),
+						MemoryLimit:    quantityString(res.Limits, corev1.ResourceMemory),
+						Ready:          ready,
+						RestartCount:   restarts,
+					}
+					hash, err := calcHash(tgt)
+					if err != nil {
+						continue
+					}
+					tgt.hash = hash
+
+					targets = append(targets, tgt)
+				}
+			}
 		}
 	}
 
 	return targets
 }
 
+// podHosts returns the pod (or node in host address mode) IPs of the configured IP family.
+func (p *podDiscoverer) podHosts(pod *corev1.Pod) []string {
+	primary, ips := pod.Status.PodIP, make([]string, 0, len(pod.Status.PodIPs))
+	for _, v := range pod.Status.PodIPs {
+		ips = append(ips, v.IP)
+	}
+	if p.addressMode == addressModeHost {
+		primary, ips = pod.Status.HostIP, ips[:0]
+		for _, v := range pod.Status.HostIPs {
+			ips = append(ips, v.IP)
+		}
+	}
+
+	if primary == "" {
+		return nil
+	}
+	if p.ipFamily == "" {
+		return []string{primary}
+	}
+	if len(ips) == 0 {
+		ips = append(ips, primary)
+	}
+
+	var hosts []string
+	for _, ip := range ips {
+		switch p.ipFamily {
+		case ipFamilyAll:
+			hosts = append(hosts, ip)
+		case ipFamilyIPv4:
+			if !isIPv6(ip) {
+				hosts = append(hosts, ip)
+			}
+		case ipFamilyIPv6:
+			if isIPv6(ip) {
+				hosts = append(hosts, ip)
+			}
+		}
+	}
+	return hosts
+}
+
+func (p *podDiscoverer) podIP(pod *corev1.Pod, host string) string {
+	if p.addressMode == addressModeHost {
+		return pod.Status.PodIP
+	}
+	return host
+}
+
+func (p *podDiscoverer) podPort(pod *corev1.Pod, port corev1.ContainerPort) string {
+	switch {
+	case p.addressMode != addressModeHost, pod.Spec.HostNetwork:
+		return strconv.FormatUint(uint64(port.ContainerPort), 10)
+	case port.HostPort != 0:
+		return strconv.FormatUint(uint64(port.HostPort), 10)
+	default:
+		// the port is not exposed on the node
+		return ""
+	}
+}
+
+func (p *podDiscoverer) tuid(tuid, host string) string {
+	if p.ipFamily == ipFamilyAll && isIPv6(host) {
+		tuid += "_v6"
+	}
+	if p.addressMode == addressModeHost {
+		tuid += "_host"
+	}
+	return tuid
+}
+
+type podContainer struct {
+	corev1.Container
+	kind string // empty for app containers
+}
+
+const (
+	containerKindInit      = "init"
+	containerKindEphemeral = "ephemeral"
+)
+
+func (p *podDiscoverer) podContainers(pod *corev1.Pod) []podContainer {
+	containers := make([]podContainer, 0, len(pod.Spec.Containers))
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, podContainer{Container: c})
+	}
+
+	if !p.includeInitContainers {
+		return containers
+	}
+
+	for _, c := range pod.Spec.InitContainers {
+		// only sidecars (restartable init containers) keep running after the pod is initialized
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			containers = append(containers, podContainer{Container: c, kind: containerKindInit})
+		}
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		containers = append(containers, podContainer{
+			Container: corev1.Container(c.EphemeralContainerCommon),
+			kind:      containerKindEphemeral,
+		})
+	}
+
+	return containers
+}
+
+// containerState returns the readiness and restart count of the container from the pod status.
+func containerState(pod *corev1.Pod, container podContainer) (ready bool, restarts int) {
+	statuses := pod.Status.ContainerStatuses
+	switch container.kind {
+	case containerKindInit:
+		statuses = pod.Status.InitContainerStatuses
+	case containerKindEphemeral:
+		statuses = pod.Status.EphemeralContainerStatuses
+	}
+
+	for _, st := range statuses {
+		if st.Name == container.Name {
+			return st.Ready, int(st.RestartCount)
+		}
+	}
+	return false, 0
+}
+
+func quantityString(list corev1.ResourceList, name corev1.ResourceName) string {
+	if q, ok := list[name]; ok {
+		return q.String()
+	}
+	return ""
+}
+
+func (p *podDiscoverer) annotation(pod *corev1.Pod, name string) string {
+	return pod.Annotations[p.annotationPrefix+name]
+}
+
+func (p *podDiscoverer) isScrapeDisabled(pod *corev1.Pod) bool {
+	v, err := strconv.ParseBool(p.annotation(pod, annotationScrape))
+	return err == nil && !v
+}
+
+// withAnnotatedPorts assigns the ports listed in the ports annotation to the containers that declare no ports.
+func (p *podDiscoverer) withAnnotatedPorts(pod *corev1.Pod, containers []podContainer) []podContainer {
+	v := p.annotation(pod, annotationPorts)
+	if v == "" {
+		return containers
+	}
+
+	var ports []corev1.ContainerPort
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		num, err := strconv.ParseUint(s, 10, 16)
+		if err != nil || num == 0 {
+			p.Warningf("pod '%s/%s': invalid '%s' annotation port '%s'",
+				pod.Namespace, pod.Name, p.annotationPrefix+annotationPorts, s)
+			continue
+		}
+		ports = append(ports, corev1.ContainerPort{ContainerPort: int32(num), Protocol: corev1.ProtocolTCP})
+	}
+	if len(ports) == 0 {
+		return containers
+	}
+
+	res := make([]podContainer, 0, len(containers))
+	for _, c := range containers {
+		if len(c.Ports) == 0 {
+			c.Ports = ports
+		}
+		res = append(res, c)
+	}
+
+	return res
+}
+
+// restrictToAnnotatedPort keeps only the port set by the port annotation.
+// If no container declares that port, it is assigned to the first container.
+func (p *podDiscoverer) restrictToAnnotatedPort(pod *corev1.Pod, containers []podContainer) []podContainer {
+	v := p.annotation(pod, annotationPort)
+	if v == "" || len(containers) == 0 {
+		return containers
+	}
+
+	num, err := strconv.ParseUint(v, 10, 16)
+	if err != nil || num == 0 {
+		p.Warningf("pod '%s/%s': invalid '%s' annotation value '%s'",
+			pod.Namespace, pod.Name, p.annotationPrefix+annotationPort, v)
+		return containers
+	}
+	port := int32(num)
+
+	var restricted []podContainer
+	for _, c := range containers {
+		var ports []corev1.ContainerPort
+		for _, cp := range c.Ports {
+			if cp.ContainerPort == port {
+				ports = append(ports, cp)
+			}
+		}
+		if len(ports) > 0 {
+			c.Ports = ports
+			restricted = append(restricted, c)
+		}
+	}
+	if len(restricted) > 0 {
+		return restricted
+	}
+
+	c := containers[0]
+	c.Ports = []corev1.ContainerPort{{ContainerPort: port, Protocol: corev1.ProtocolTCP}}
+
+	return []podContainer{c}
+}
+
+func (p *podDiscoverer) resolveController(pod *corev1.Pod) (name, kind string) {
+	ref := controllerRef(pod.OwnerReferences)
+	if ref == nil {
+		return "", ""
+	}
+
+	// ReplicaSet and Job names are generated (contain a hash/timestamp suffix) and change on every
+	// rollout/schedule, so the top-level Deployment/CronJob is reported when there is one.
+	var informer cache.SharedInformer
+	switch ref.Kind {
+	case "ReplicaSet":
+		informer = p.rsInformer
+	case "Job":
+		informer = p.jobInformer
+	default:
+		return ref.Name, ref.Kind
+	}
+
+	item, exist, err := informer.GetStore().GetByKey(pod.Namespace + "/" + ref.Name)
+	if err != nil || !exist {
+		return ref.Name, ref.Kind
+	}
+
+	obj, ok := item.(metav1.Object)
+	if !ok {
+		return ref.Name, ref.Kind
+	}
+
+	if owner := controllerRef(obj.GetOwnerReferences()); owner != nil {
+		return owner.Name, owner.Kind
+	}
+	return ref.Name, ref.Kind
+}
+
 func (p *podDiscoverer) collectEnv(ns string, container corev1.Container) map[string]string {
 	vars := make(map[string]string)
 
@@ -301,7 +630,7 @@ func (p *podDiscoverer) valueFromConfigMap(vars map[string]string, ns string, en
 }
 
 func (p *podDiscoverer) valueFromSecret(vars map[string]string, ns string, env corev1.EnvVar) {
-	if env.ValueFrom.SecretKeyRef.Name == "" || env.ValueFrom.SecretKeyRef.Key == "" {
+	if env.ValueFrom.SecretKeyRef.Name == "" || env.ValueFrom.SecretKeyRef.Key == "" || !p.canResolveSecrets() {
 		return
 	}
 
@@ -345,7 +674,7 @@ func (p *podDiscoverer) envFromConfigMap(vars map[string]string, ns string, src
 }
 
 func (p *podDiscoverer) envFromSecret(vars map[string]string, ns string, src corev1.EnvFromSource) {
-	if src.SecretRef.Name == "" {
+	if src.SecretRef.Name == "" || !p.canResolveSecrets() {
 		return
 	}
 
@@ -365,24 +694,42 @@ func (p *podDiscoverer) envFromSecret(vars map[string]string, ns string, src cor
 	}
 }
 
-func podTUID(pod *corev1.Pod, container corev1.Container) string {
+func (p *podDiscoverer) canResolveSecrets() bool {
+	if p.secretInformer != nil {
+		return true
+	}
+	if !p.secretEnvWarned {
+		p.secretEnvWarned = true
+		p.Warning("secrets access is disabled, skipping env vars sourced from secrets")
+	}
+	return false
+}
+
+func podTUID(pod *corev1.Pod, container podContainer) string {
 	return fmt.Sprintf("%s_%s_%s",
 		pod.Namespace,
 		pod.Name,
-		container.Name,
+		containerTUID(container),
 	)
 }
 
-func podTUIDWithPort(pod *corev1.Pod, container corev1.Container, port corev1.ContainerPort) string {
+func podTUIDWithPort(pod *corev1.Pod, container podContainer, port corev1.ContainerPort) string {
 	return fmt.Sprintf("%s_%s_%s_%s_%s",
 		pod.Namespace,
 		pod.Name,
-		container.Name,
+		containerTUID(container),
 		strings.ToLower(string(port.Protocol)),
 		strconv.FormatUint(uint64(port.ContainerPort), 10),
 	)
 }
 
+func containerTUID(container podContainer) string {
+	if container.kind == "" {
+		return container.Name
+	}
+	return container.kind + "_" + container.Name
+}
+
 func podSourceFromNsName(namespace, name string) string {
 	return namespace + "/" + name
 }
@@ -399,6 +746,15 @@ func toPod(obj any) (*corev1.Pod, error) {
 	return pod, nil
 }
 
+func controllerRef(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i, ref := range refs {
+		if ref.Controller != nil && *ref.Controller {
+			return &refs[i]
+		}
+	}
+	return nil
+}
+
 func toConfigMap(obj any) (*corev1.ConfigMap, error) {
 	cmap, ok := obj.(*corev1.ConfigMap)
 	if !ok {
@@ -415,6 +771,22 @@ func toSecret(obj any) (*corev1.Secret, error) {
 	return secret, nil
 }
 
+func isPodRunning(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodRunning
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func isVar(name string) bool {
 	// Variable references $(VAR_NAME) are expanded using the previous defined
 	// environment variables in the container and any service environment
@@ -4,19 +4,29 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
 	"net"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/sdtest"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -27,17 +37,18 @@ func TestPodTargetGroup_Provider(t *testing.T) {
 
 func TestPodTargetGroup_Source(t *testing.T) {
 	tests := map[string]struct {
-		createSim   func() discoverySim
+		createSim   func() sdtest.Sim
 		wantSources []string
 	}{
 		"pods with multiple ports": {
-			createSim: func() discoverySim {
+			createSim: func() sdtest.Sim {
 				httpd, nginx := newHTTPDPod(), newNGINXPod()
 				disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
 
-				return discoverySim{
-					td: disc,
-					wantTargetGroups: []model.TargetGroup{
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
 						preparePodTargetGroup(httpd),
 						preparePodTargetGroup(nginx),
 					},
@@ -55,7 +66,7 @@ func TestPodTargetGroup_Source(t *testing.T) {
 			sim := test.createSim()
 
 			var sources []string
-			for _, tgg := range sim.run(t) {
+			for _, tgg := range sim.Run(t) {
 				sources = append(sources, tgg.Source())
 			}
 
@@ -66,17 +77,18 @@ func TestPodTargetGroup_Source(t *testing.T) {
 
 func TestPodTargetGroup_Targets(t *testing.T) {
 	tests := map[string]struct {
-		createSim   func() discoverySim
+		createSim   func() sdtest.Sim
 		wantTargets int
 	}{
 		"pods with multiple ports": {
-			createSim: func() discoverySim {
+			createSim: func() sdtest.Sim {
 				httpd, nginx := newHTTPDPod(), newNGINXPod()
 				discovery, _ := prepareAllNsPodDiscoverer(httpd, nginx)
 
-				return discoverySim{
-					td: discovery,
-					wantTargetGroups: []model.TargetGroup{
+				return sdtest.Sim{
+					Discoverer: discovery,
+					Synced:     discovery.synced,
+					WantTargetGroups: []model.TargetGroup{
 						preparePodTargetGroup(httpd),
 						preparePodTargetGroup(nginx),
 					},
@@ -91,7 +103,7 @@ func TestPodTargetGroup_Targets(t *testing.T) {
 			sim := test.createSim()
 
 			var targets int
-			for _, tgg := range sim.run(t) {
+			for _, tgg := range sim.Run(t) {
 				targets += len(tgg.Targets())
 			}
 
@@ -102,27 +114,28 @@ func TestPodTargetGroup_Targets(t *testing.T) {
 
 func TestPodTarget_Hash(t *testing.T) {
 	tests := map[string]struct {
-		createSim  func() discoverySim
+		createSim  func() sdtest.Sim
 		wantHashes []uint64
 	}{
 		"pods with multiple ports": {
-			createSim: func() discoverySim {
+			createSim: func() sdtest.Sim {
 				httpd, nginx := newHTTPDPod(), newNGINXPod()
 				discovery, _ := prepareAllNsPodDiscoverer(httpd, nginx)
 
-				return discoverySim{
-					td: discovery,
-					wantTargetGroups: []model.TargetGroup{
+				return sdtest.Sim{
+					Discoverer: discovery,
+					Synced:     discovery.synced,
+					WantTargetGroups: []model.TargetGroup{
 						preparePodTargetGroup(httpd),
 						preparePodTargetGroup(nginx),
 					},
 				}
 			},
 			wantHashes: []uint64{
-				12703169414253998055,
-				13351713096133918928,
-				8241692333761256175,
-				11562466355572729519,
+				3248987133860684358,
+				2600496185732861297,
+				17238757173207865166,
+				4407370582190107406,
 			},
 		},
 	}
@@ -132,7 +145,7 @@ func TestPodTarget_Hash(t *testing.T) {
 			sim := test.createSim()
 
 			var hashes []uint64
-			for _, tgg := range sim.run(t) {
+			for _, tgg := range sim.Run(t) {
 				for _, tg := range tgg.Targets() {
 					hashes = append(hashes, tg.Hash())
 				}
@@ -143,19 +156,72 @@ func TestPodTarget_Hash(t *testing.T) {
 	}
 }
 
+func TestPodTarget_ContainerState(t *testing.T) {
+	tests := map[string]struct {
+		mangle       func(pod *corev1.Pod)
+		wantSameHash bool
+	}{
+		"restart count changed": {
+			mangle:       func(pod *corev1.Pod) { pod.Status.ContainerStatuses[0].RestartCount = 5 },
+			wantSameHash: true,
+		},
+		"container became not ready": {
+			mangle:       func(pod *corev1.Pod) { pod.Status.ContainerStatuses[0].Ready = false },
+			wantSameHash: false,
+		},
+		"memory limit changed": {
+			mangle: func(pod *corev1.Pod) {
+				pod.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1Gi")
+			},
+			wantSameHash: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &podDiscoverer{addressMode: addressModePodIP, annotationPrefix: defaultAnnotationPrefix}
+
+			pod := newHTTPDPod()
+			setHTTPDPodContainerState(pod)
+
+			targets := p.buildTargets(pod)
+			require.NotEmpty(t, targets)
+
+			tgt := targets[0].(*PodTarget)
+			assert.Equal(t, "250m", tgt.CPURequest)
+			assert.Equal(t, "500m", tgt.CPULimit)
+			assert.Equal(t, "128Mi", tgt.MemoryRequest)
+			assert.Equal(t, "256Mi", tgt.MemoryLimit)
+			assert.True(t, tgt.Ready)
+			assert.Equal(t, 1, tgt.RestartCount)
+
+			test.mangle(pod)
+			mangled := p.buildTargets(pod)
+			require.Len(t, mangled, len(targets))
+
+			if test.wantSameHash {
+				assert.Equal(t, targets[0].Hash(), mangled[0].Hash())
+			} else {
+				assert.NotEqual(t, targets[0].Hash(), mangled[0].Hash())
+			}
+		})
+	}
+}
+
 func TestPodTarget_TUID(t *testing.T) {
 	tests := map[string]struct {
-		createSim func() discoverySim
+		createSim func() sdtest.Sim
 		wantTUID  []string
 	}{
 		"pods with multiple ports": {
-			createSim: func() discoverySim {
+			createSim: func() sdtest.Sim {
 				httpd, nginx := newHTTPDPod(), newNGINXPod()
 				discovery, _ := prepareAllNsPodDiscoverer(httpd, nginx)
 
-				return discoverySim{
-					td: discovery,
-					wantTargetGroups: []model.TargetGroup{
+				return sdtest.Sim{
+					Discoverer: discovery,
+					Synced:     discovery.synced,
+					WantTargetGroups: []model.TargetGroup{
 						preparePodTargetGroup(httpd),
 						preparePodTargetGroup(nginx),
 					},
@@ -168,6 +234,100 @@ func TestPodTarget_TUID(t *testing.T) {
 				"default_nginx-7cfd77469b-q6kxj_nginx_tcp_443",
 			},
 		},
+		"pod with sidecar and ephemeral containers": {
+			createSim: func() sdtest.Sim {
+				httpd := newHTTPDPod()
+				addHTTPDPodInitContainers(httpd)
+				discovery, _ := prepareAllNsPodDiscoverer(httpd)
+				discovery.podConf.IncludeInitContainers = true
+
+				return sdtest.Sim{
+					Discoverer: discovery,
+					Synced:     discovery.synced,
+					WantTargetGroups: []model.TargetGroup{
+						preparePodTargetGroupWithInitContainers(httpd),
+					},
+				}
+			},
+			wantTUID: []string{
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_80",
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_443",
+				"default_httpd-dd95c4d68-5bkwl_init_exporter_tcp_9117",
+				"default_httpd-dd95c4d68-5bkwl_ephemeral_debugger",
+			},
+		},
+		"dual-stack pods with all IP families": {
+			createSim: func() sdtest.Sim {
+				httpd := newHTTPDPod()
+				setDualStackPodIPs(httpd, "fd00:10:244::1")
+				discovery, _ := prepareAllNsPodDiscoverer(httpd)
+				discovery.podConf.IPFamily = ipFamilyAll
+
+				return sdtest.Sim{
+					Discoverer: discovery,
+					Synced:     discovery.synced,
+					WantTargetGroups: []model.TargetGroup{
+						preparePodTargetGroupWithIPFamily(httpd, ipFamilyAll),
+					},
+				}
+			},
+			wantTUID: []string{
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_80",
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_80_v6",
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_443",
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_443_v6",
+			},
+		},
+		"pod without container ports with ports annotation": {
+			createSim: func() sdtest.Sim {
+				httpd := newHTTPDPod()
+				httpd.Spec.Containers[0].Ports = nil
+				httpd.Annotations["netdata.io/ports"] = "8080,9090"
+				discovery, _ := prepareAllNsPodDiscoverer(httpd)
+
+				want := httpd.DeepCopy()
+				want.Spec.Containers[0].Ports = []corev1.ContainerPort{
+					{Protocol: corev1.ProtocolTCP, ContainerPort: 8080},
+					{Protocol: corev1.ProtocolTCP, ContainerPort: 9090},
+				}
+
+				return sdtest.Sim{
+					Discoverer: discovery,
+					Synced:     discovery.synced,
+					WantTargetGroups: []model.TargetGroup{
+						preparePodTargetGroup(want),
+					},
+				}
+			},
+			wantTUID: []string{
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_8080",
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_9090",
+			},
+		},
+		"pods with host address mode": {
+			createSim: func() sdtest.Sim {
+				httpd, nginx := newHTTPDPod(), newNGINXPod()
+				httpd.Spec.HostNetwork = true
+				nginx.Spec.HostNetwork = true
+				discovery, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+				discovery.podConf.AddressMode = addressModeHost
+
+				return sdtest.Sim{
+					Discoverer: discovery,
+					Synced:     discovery.synced,
+					WantTargetGroups: []model.TargetGroup{
+						preparePodTargetGroupWithAddressMode(httpd, addressModeHost),
+						preparePodTargetGroupWithAddressMode(nginx, addressModeHost),
+					},
+				}
+			},
+			wantTUID: []string{
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_80_host",
+				"default_httpd-dd95c4d68-5bkwl_httpd_tcp_443_host",
+				"default_nginx-7cfd77469b-q6kxj_nginx_tcp_80_host",
+				"default_nginx-7cfd77469b-q6kxj_nginx_tcp_443_host",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -175,7 +335,7 @@ func TestPodTarget_TUID(t *testing.T) {
 			sim := test.createSim()
 
 			var tuid []string
-			for _, tgg := range sim.run(t) {
+			for _, tgg := range sim.Run(t) {
 				for _, tg := range tgg.Targets() {
 					tuid = append(tuid, tg.TUID())
 				}
@@ -191,13 +351,31 @@ func TestNewPodDiscoverer(t *testing.T) {
 		podInf    cache.SharedInformer
 		cmapInf   cache.SharedInformer
 		secretInf cache.SharedInformer
+		rsInf     cache.SharedInformer
+		jobInf    cache.SharedInformer
 		wantPanic bool
 	}{
 		"valid informers": {
 			wantPanic: false,
-			podInf:    cache.NewSharedInformer(nil, &corev1.Pod{}, resyncPeriod),
-			cmapInf:   cache.NewSharedInformer(nil, &corev1.ConfigMap{}, resyncPeriod),
-			secretInf: cache.NewSharedInformer(nil, &corev1.Secret{}, resyncPeriod),
+			podInf:    cache.NewSharedInformer(nil, &corev1.Pod{}, defaultResyncPeriod),
+			cmapInf:   cache.NewSharedInformer(nil, &corev1.ConfigMap{}, defaultResyncPeriod),
+			secretInf: cache.NewSharedInformer(nil, &corev1.Secret{}, defaultResyncPeriod),
+			rsInf:     cache.NewSharedInformer(nil, &appsv1.ReplicaSet{}, defaultResyncPeriod),
+			jobInf:    cache.NewSharedInformer(nil, &batchv1.Job{}, defaultResyncPeriod),
+		},
+		"nil secret informer": {
+			wantPanic: false,
+			podInf:    cache.NewSharedInformer(nil, &corev1.Pod{}, defaultResyncPeriod),
+			cmapInf:   cache.NewSharedInformer(nil, &corev1.ConfigMap{}, defaultResyncPeriod),
+			rsInf:     cache.NewSharedInformer(nil, &appsv1.ReplicaSet{}, defaultResyncPeriod),
+			jobInf:    cache.NewSharedInformer(nil, &batchv1.Job{}, defaultResyncPeriod),
+		},
+		"nil replicaset informer": {
+			wantPanic: true,
+			podInf:    cache.NewSharedInformer(nil, &corev1.Pod{}, defaultResyncPeriod),
+			cmapInf:   cache.NewSharedInformer(nil, &corev1.ConfigMap{}, defaultResyncPeriod),
+			secretInf: cache.NewSharedInformer(nil, &corev1.Secret{}, defaultResyncPeriod),
+			jobInf:    cache.NewSharedInformer(nil, &batchv1.Job{}, defaultResyncPeriod),
 		},
 		"nil informers": {
 			wantPanic: true,
@@ -206,7 +384,7 @@ func TestNewPodDiscoverer(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			f := func() { newPodDiscoverer(test.podInf, test.cmapInf, test.secretInf) }
+			f := func() { newPodDiscoverer(test.podInf, test.cmapInf, test.secretInf, test.rsInf, test.jobInf) }
 
 			if test.wantPanic {
 				assert.Panics(t, f)
@@ -223,48 +401,51 @@ func TestPodDiscoverer_String(t *testing.T) {
 }
 
 func TestPodDiscoverer_Discover(t *testing.T) {
-	tests := map[string]func() discoverySim{
-		"ADD: pods exist before run": func() discoverySim {
+	tests := map[string]func() sdtest.Sim{
+		"ADD: pods exist before run": func() sdtest.Sim {
 			httpd, nginx := newHTTPDPod(), newNGINXPod()
 			td, _ := prepareAllNsPodDiscoverer(httpd, nginx)
 
-			return discoverySim{
-				td: td,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: td,
+				Synced:     td.synced,
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroup(httpd),
 					preparePodTargetGroup(nginx),
 				},
 			}
 		},
-		"ADD: pods exist before run and add after sync": func() discoverySim {
+		"ADD: pods exist before run and add after sync": func() sdtest.Sim {
 			httpd, nginx := newHTTPDPod(), newNGINXPod()
 			disc, client := prepareAllNsPodDiscoverer(httpd)
 			podClient := client.CoreV1().Pods("default")
 
-			return discoverySim{
-				td: disc,
-				runAfterSync: func(ctx context.Context) {
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
 					_, _ = podClient.Create(ctx, nginx, metav1.CreateOptions{})
 				},
-				wantTargetGroups: []model.TargetGroup{
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroup(httpd),
 					preparePodTargetGroup(nginx),
 				},
 			}
 		},
-		"DELETE: remove pods after sync": func() discoverySim {
+		"DELETE: remove pods after sync": func() sdtest.Sim {
 			httpd, nginx := newHTTPDPod(), newNGINXPod()
 			disc, client := prepareAllNsPodDiscoverer(httpd, nginx)
 			podClient := client.CoreV1().Pods("default")
 
-			return discoverySim{
-				td: disc,
-				runAfterSync: func(ctx context.Context) {
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
 					time.Sleep(time.Millisecond * 50)
 					_ = podClient.Delete(ctx, httpd.Name, metav1.DeleteOptions{})
 					_ = podClient.Delete(ctx, nginx.Name, metav1.DeleteOptions{})
 				},
-				wantTargetGroups: []model.TargetGroup{
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroup(httpd),
 					preparePodTargetGroup(nginx),
 					prepareEmptyPodTargetGroup(httpd),
@@ -272,54 +453,57 @@ func TestPodDiscoverer_Discover(t *testing.T) {
 				},
 			}
 		},
-		"DELETE,ADD: remove and add pods after sync": func() discoverySim {
+		"DELETE,ADD: remove and add pods after sync": func() sdtest.Sim {
 			httpd, nginx := newHTTPDPod(), newNGINXPod()
 			disc, client := prepareAllNsPodDiscoverer(httpd)
 			podClient := client.CoreV1().Pods("default")
 
-			return discoverySim{
-				td: disc,
-				runAfterSync: func(ctx context.Context) {
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
 					time.Sleep(time.Millisecond * 50)
 					_ = podClient.Delete(ctx, httpd.Name, metav1.DeleteOptions{})
 					_, _ = podClient.Create(ctx, nginx, metav1.CreateOptions{})
 				},
-				wantTargetGroups: []model.TargetGroup{
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroup(httpd),
 					prepareEmptyPodTargetGroup(httpd),
 					preparePodTargetGroup(nginx),
 				},
 			}
 		},
-		"ADD: pods with empty PodIP": func() discoverySim {
+		"ADD: pods with empty PodIP": func() sdtest.Sim {
 			httpd, nginx := newHTTPDPod(), newNGINXPod()
 			httpd.Status.PodIP = ""
 			nginx.Status.PodIP = ""
 			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					prepareEmptyPodTargetGroup(httpd),
 					prepareEmptyPodTargetGroup(nginx),
 				},
 			}
 		},
-		"UPDATE: set pods PodIP after sync": func() discoverySim {
+		"UPDATE: set pods PodIP after sync": func() sdtest.Sim {
 			httpd, nginx := newHTTPDPod(), newNGINXPod()
 			httpd.Status.PodIP = ""
 			nginx.Status.PodIP = ""
 			disc, client := prepareAllNsPodDiscoverer(httpd, nginx)
 			podClient := client.CoreV1().Pods("default")
 
-			return discoverySim{
-				td: disc,
-				runAfterSync: func(ctx context.Context) {
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
 					time.Sleep(time.Millisecond * 50)
 					_, _ = podClient.Update(ctx, newHTTPDPod(), metav1.UpdateOptions{})
 					_, _ = podClient.Update(ctx, newNGINXPod(), metav1.UpdateOptions{})
 				},
-				wantTargetGroups: []model.TargetGroup{
+				WantTargetGroups: []model.TargetGroup{
 					prepareEmptyPodTargetGroup(httpd),
 					prepareEmptyPodTargetGroup(nginx),
 					preparePodTargetGroup(newHTTPDPod()),
@@ -327,21 +511,515 @@ func TestPodDiscoverer_Discover(t *testing.T) {
 				},
 			}
 		},
-		"ADD: pods without containers": func() discoverySim {
+		"ADD: pods with last-applied-configuration annotation": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Annotations[annotationLastAppliedConfig] = `{"kind":"Pod"}`
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(newHTTPDPod()),
+					preparePodTargetGroup(nginx),
+				},
+			}
+		},
+		"ADD: pods with scrape annotation disabled": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Annotations["netdata.io/scrape"] = "false"
+			nginx.Annotations["netdata.io/scrape"] = "true"
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					prepareEmptyPodTargetGroup(httpd),
+					preparePodTargetGroup(nginx),
+				},
+			}
+		},
+		"ADD: pods with port, path and scheme annotations": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Annotations["netdata.io/port"] = "443"
+			httpd.Annotations["netdata.io/path"] = "/metrics"
+			httpd.Annotations["netdata.io/scheme"] = "https"
+			nginx.Annotations["netdata.io/port"] = "9113"
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+
+			wantHTTPD, wantNGINX := httpd.DeepCopy(), nginx.DeepCopy()
+			wantHTTPD.Spec.Containers[0].Ports = wantHTTPD.Spec.Containers[0].Ports[1:]
+			wantNGINX.Spec.Containers[0].Ports = []corev1.ContainerPort{{Protocol: corev1.ProtocolTCP, ContainerPort: 9113}}
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(wantHTTPD),
+					preparePodTargetGroup(wantNGINX),
+				},
+			}
+		},
+		"ADD: pods with invalid port annotation": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			httpd.Annotations["netdata.io/port"] = "http"
+			disc, _ := prepareAllNsPodDiscoverer(httpd)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+				},
+			}
+		},
+		"ADD: pods with custom annotation prefix": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Annotations["example.com/scrape"] = "false"
+			nginx.Annotations["netdata.io/scrape"] = "false"
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.AnnotationPrefix = "example.com/"
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					prepareEmptyPodTargetGroup(httpd),
+					preparePodTargetGroup(nginx),
+				},
+			}
+		},
+		"UPDATE: pods scrape annotation changes after sync": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			disc, client := prepareAllNsPodDiscoverer(httpd, nginx)
+			podClient := client.CoreV1().Pods("default")
+
+			updatedHTTPD, updatedNGINX := httpd.DeepCopy(), nginx.DeepCopy()
+			updatedHTTPD.Annotations["netdata.io/scrape"] = "false"
+			updatedNGINX.Annotations["netdata.io/port"] = "80"
+			wantNGINX := updatedNGINX.DeepCopy()
+			wantNGINX.Spec.Containers[0].Ports = wantNGINX.Spec.Containers[0].Ports[:1]
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					time.Sleep(time.Millisecond * 50)
+					_, _ = podClient.Update(ctx, updatedHTTPD, metav1.UpdateOptions{})
+					_, _ = podClient.Update(ctx, updatedNGINX, metav1.UpdateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					preparePodTargetGroup(nginx),
+					prepareEmptyPodTargetGroup(httpd),
+					preparePodTargetGroup(wantNGINX),
+				},
+			}
+		},
+		"ADD: only running pods": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			nginx.Status.Phase = corev1.PodFailed
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.OnlyRunning = true
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					prepareEmptyPodTargetGroup(nginx),
+				},
+			}
+		},
+		"ADD: only ready pods": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			nginx.Status.Conditions[0].Status = corev1.ConditionFalse
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.OnlyReady = true
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					prepareEmptyPodTargetGroup(nginx),
+				},
+			}
+		},
+		"ADD: only ready pods, terminating pod": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			now := metav1.Now()
+			nginx.DeletionTimestamp = &now
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.OnlyReady = true
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					prepareEmptyPodTargetGroup(nginx),
+				},
+			}
+		},
+		"ADD: not running and not ready pods without the options": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Status.Phase = corev1.PodPending
+			nginx.Status.Conditions[0].Status = corev1.ConditionFalse
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					preparePodTargetGroup(nginx),
+				},
+			}
+		},
+		"UPDATE: pods become ready after sync": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Status.Conditions[0].Status = corev1.ConditionFalse
+			nginx.Status.Phase = corev1.PodPending
+			nginx.Status.Conditions[0].Status = corev1.ConditionFalse
+			disc, client := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.OnlyRunning = true
+			disc.podConf.OnlyReady = true
+			podClient := client.CoreV1().Pods("default")
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					time.Sleep(time.Millisecond * 50)
+					_, _ = podClient.Update(ctx, newHTTPDPod(), metav1.UpdateOptions{})
+					_, _ = podClient.Update(ctx, newNGINXPod(), metav1.UpdateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					prepareEmptyPodTargetGroup(httpd),
+					prepareEmptyPodTargetGroup(nginx),
+					preparePodTargetGroup(newHTTPDPod()),
+					preparePodTargetGroup(newNGINXPod()),
+				},
+			}
+		},
+		"AddressMode: host": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Spec.Containers[0].Ports[0].HostPort = 8080
+			nginx.Spec.HostNetwork = true
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.AddressMode = addressModeHost
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithAddressMode(httpd, addressModeHost),
+					preparePodTargetGroupWithAddressMode(nginx, addressModeHost),
+				},
+			}
+		},
+		"AddressMode: host, set pods HostIP after sync": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Spec.HostNetwork = true
+			nginx.Spec.HostNetwork = true
+			httpdUpd, nginxUpd := *httpd, *nginx
+			httpd.Status.HostIP = ""
+			nginx.Status.HostIP = ""
+			disc, client := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.AddressMode = addressModeHost
+			podClient := client.CoreV1().Pods("default")
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					time.Sleep(time.Millisecond * 50)
+					_, _ = podClient.Update(ctx, &httpdUpd, metav1.UpdateOptions{})
+					_, _ = podClient.Update(ctx, &nginxUpd, metav1.UpdateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					prepareEmptyPodTargetGroup(httpd),
+					prepareEmptyPodTargetGroup(nginx),
+					preparePodTargetGroupWithAddressMode(&httpdUpd, addressModeHost),
+					preparePodTargetGroupWithAddressMode(&nginxUpd, addressModeHost),
+				},
+			}
+		},
+		"IPFamily: all": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			setDualStackPodIPs(httpd, "fd00:10:244::1")
+			setDualStackPodIPs(nginx, "fd00:10:244::2")
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.IPFamily = ipFamilyAll
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithIPFamily(httpd, ipFamilyAll),
+					preparePodTargetGroupWithIPFamily(nginx, ipFamilyAll),
+				},
+			}
+		},
+		"IPFamily: ipv6": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			setDualStackPodIPs(httpd, "fd00:10:244::1")
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.IPFamily = ipFamilyIPv6
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithIPFamily(httpd, ipFamilyIPv6),
+					prepareEmptyPodTargetGroup(nginx),
+				},
+			}
+		},
+		"IPFamily: ipv4": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			setDualStackPodIPs(httpd, "fd00:10:244::1")
+			setDualStackPodIPs(nginx, "fd00:10:244::2")
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+			disc.podConf.IPFamily = ipFamilyIPv4
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithIPFamily(httpd, ipFamilyIPv4),
+					preparePodTargetGroupWithIPFamily(nginx, ipFamilyIPv4),
+				},
+			}
+		},
+		"ADD: pods without containers": func() sdtest.Sim {
 			httpd, nginx := newHTTPDPod(), newNGINXPod()
 			httpd.Spec.Containers = httpd.Spec.Containers[:0]
 			nginx.Spec.Containers = httpd.Spec.Containers[:0]
 			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					prepareEmptyPodTargetGroup(httpd),
 					prepareEmptyPodTargetGroup(nginx),
 				},
 			}
 		},
-		"Env: from value": func() discoverySim {
+		"ADD: pods without container ports": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Spec.Containers[0].Ports = nil
+			nginx.Spec.Containers[0].Ports = nil
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					preparePodTargetGroup(nginx),
+				},
+			}
+		},
+		"ADD: pods without container ports with ports annotation": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Spec.Containers[0].Ports = nil
+			httpd.Annotations["netdata.io/ports"] = "8080, 9090"
+			nginx.Annotations["netdata.io/ports"] = "8080"
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+
+			wantHTTPD := httpd.DeepCopy()
+			wantHTTPD.Spec.Containers[0].Ports = []corev1.ContainerPort{
+				{Protocol: corev1.ProtocolTCP, ContainerPort: 8080},
+				{Protocol: corev1.ProtocolTCP, ContainerPort: 9090},
+			}
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(wantHTTPD),
+					preparePodTargetGroup(nginx),
+				},
+			}
+		},
+		"ADD: pods without container ports with invalid ports annotation": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			httpd.Spec.Containers[0].Ports = nil
+			httpd.Annotations["netdata.io/ports"] = "http,0"
+			nginx.Spec.Containers[0].Ports = nil
+			nginx.Annotations["netdata.io/ports"] = "http,9113"
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+
+			wantNGINX := nginx.DeepCopy()
+			wantNGINX.Spec.Containers[0].Ports = []corev1.ContainerPort{{Protocol: corev1.ProtocolTCP, ContainerPort: 9113}}
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					preparePodTargetGroup(wantNGINX),
+				},
+			}
+		},
+		"ADD: pods without containers with ports annotation": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			httpd.Spec.Containers = httpd.Spec.Containers[:0]
+			httpd.Annotations["netdata.io/ports"] = "8080"
+			disc, _ := prepareAllNsPodDiscoverer(httpd)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					prepareEmptyPodTargetGroup(httpd),
+				},
+			}
+		},
+		"ADD: pods with container resources and statuses": func() sdtest.Sim {
+			httpd, nginx := newHTTPDPod(), newNGINXPod()
+			setHTTPDPodContainerState(httpd)
+			disc, _ := prepareAllNsPodDiscoverer(httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					preparePodTargetGroup(nginx),
+				},
+			}
+		},
+		"UPDATE: pods container restarts after sync": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			setHTTPDPodContainerState(httpd)
+			disc, client := prepareAllNsPodDiscoverer(httpd)
+			podClient := client.CoreV1().Pods("default")
+
+			restarted := httpd.DeepCopy()
+			restarted.Status.ContainerStatuses[0].RestartCount++
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					time.Sleep(time.Millisecond * 50)
+					_, _ = podClient.Update(ctx, restarted, metav1.UpdateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+					preparePodTargetGroup(restarted),
+				},
+			}
+		},
+		"InitContainers: include sidecar and ephemeral containers": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			addHTTPDPodInitContainers(httpd)
+
+			disc, _ := prepareAllNsPodDiscoverer(httpd)
+			disc.podConf.IncludeInitContainers = true
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithInitContainers(httpd),
+				},
+			}
+		},
+		"InitContainers: not included by default": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			addHTTPDPodInitContainers(httpd)
+
+			disc, _ := prepareAllNsPodDiscoverer(httpd)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroup(httpd),
+				},
+			}
+		},
+		"Controller: ReplicaSet owned by Deployment": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			rs := prepareReplicaSet("httpd-dd95c4d68", &metav1.OwnerReference{Name: "httpd", Kind: "Deployment", Controller: &controllerTrue})
+			setPodController(httpd, rs.Name, "ReplicaSet")
+
+			disc, _ := prepareAllNsPodDiscoverer(httpd, rs)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithController(httpd, "httpd", "Deployment"),
+				},
+			}
+		},
+		"Controller: ReplicaSet without owner": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			rs := prepareReplicaSet("httpd-dd95c4d68", nil)
+			setPodController(httpd, rs.Name, "ReplicaSet")
+
+			disc, _ := prepareAllNsPodDiscoverer(httpd, rs)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithController(httpd, "httpd-dd95c4d68", "ReplicaSet"),
+				},
+			}
+		},
+		"Controller: ReplicaSet not found": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			setPodController(httpd, "httpd-dd95c4d68", "ReplicaSet")
+
+			disc, _ := prepareAllNsPodDiscoverer(httpd)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithController(httpd, "httpd-dd95c4d68", "ReplicaSet"),
+				},
+			}
+		},
+		"Controller: Job owned by CronJob": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			job := prepareJob("httpd-28342080", &metav1.OwnerReference{Name: "httpd", Kind: "CronJob", Controller: &controllerTrue})
+			setPodController(httpd, job.Name, "Job")
+
+			disc, _ := prepareAllNsPodDiscoverer(httpd, job)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithController(httpd, "httpd", "CronJob"),
+				},
+			}
+		},
+		"Controller: Job without owner": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			job := prepareJob("httpd-28342080", nil)
+			setPodController(httpd, job.Name, "Job")
+
+			disc, _ := prepareAllNsPodDiscoverer(httpd, job)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithController(httpd, "httpd-28342080", "Job"),
+				},
+			}
+		},
+		"Env: from value": func() sdtest.Sim {
 			httpd := newHTTPDPod()
 			mangle := func(c *corev1.Container) {
 				c.Env = []corev1.EnvVar{
@@ -353,14 +1031,15 @@ func TestPodDiscoverer_Discover(t *testing.T) {
 
 			disc, _ := prepareAllNsPodDiscoverer(httpd)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroupWithEnv(httpd, data),
 				},
 			}
 		},
-		"Env: from Secret": func() discoverySim {
+		"Env: from Secret": func() sdtest.Sim {
 			httpd := newHTTPDPod()
 			mangle := func(c *corev1.Container) {
 				c.Env = []corev1.EnvVar{
@@ -379,14 +1058,80 @@ func TestPodDiscoverer_Discover(t *testing.T) {
 
 			disc, _ := prepareAllNsPodDiscoverer(httpd, secret)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroupWithEnv(httpd, data),
 				},
 			}
 		},
-		"Env: from ConfigMap": func() discoverySim {
+		"Env: from Secret, secrets access disabled": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			mangle := func(c *corev1.Container) {
+				c.Env = []corev1.EnvVar{
+					{Name: "key1", Value: "value1"},
+					{
+						Name: "key2",
+						ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"},
+							Key:                  "key2",
+						}},
+					},
+				}
+				c.EnvFrom = []corev1.EnvFromSource{
+					{
+						SecretRef: &corev1.SecretEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}},
+					},
+				}
+			}
+			mangleContainers(httpd.Spec.Containers, mangle)
+			secret := prepareSecret("my-secret", map[string]string{"key2": "value2", "key3": "value3"})
+
+			disc, _ := prepareAllNsPodDiscoverer(httpd, secret)
+			resolve := false
+			disc.podConf.ResolveSecretEnv = &resolve
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithEnv(httpd, map[string]string{"key1": "value1"}),
+				},
+			}
+		},
+		"Env: from Secret, secrets access forbidden": func() sdtest.Sim {
+			httpd := newHTTPDPod()
+			mangle := func(c *corev1.Container) {
+				c.Env = []corev1.EnvVar{
+					{Name: "key1", Value: "value1"},
+					{
+						Name: "key2",
+						ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"},
+							Key:                  "key2",
+						}},
+					},
+				}
+			}
+			mangleContainers(httpd.Spec.Containers, mangle)
+			secret := prepareSecret("my-secret", map[string]string{"key2": "value2"})
+
+			disc, client := prepareAllNsPodDiscoverer(httpd, secret)
+			client.(*fake.Clientset).PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewForbidden(corev1.Resource("secrets"), "", errors.New("RBAC denied"))
+			})
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					preparePodTargetGroupWithEnv(httpd, map[string]string{"key1": "value1"}),
+				},
+			}
+		},
+		"Env: from ConfigMap": func() sdtest.Sim {
 			httpd := newHTTPDPod()
 			mangle := func(c *corev1.Container) {
 				c.Env = []corev1.EnvVar{
@@ -405,14 +1150,15 @@ func TestPodDiscoverer_Discover(t *testing.T) {
 
 			disc, _ := prepareAllNsPodDiscoverer(httpd, cmap)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroupWithEnv(httpd, data),
 				},
 			}
 		},
-		"EnvFrom: from ConfigMap": func() discoverySim {
+		"EnvFrom: from ConfigMap": func() sdtest.Sim {
 			httpd := newHTTPDPod()
 			mangle := func(c *corev1.Container) {
 				c.EnvFrom = []corev1.EnvFromSource{
@@ -428,14 +1174,15 @@ func TestPodDiscoverer_Discover(t *testing.T) {
 
 			disc, _ := prepareAllNsPodDiscoverer(httpd, cmap)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroupWithEnv(httpd, data),
 				},
 			}
 		},
-		"EnvFrom: from Secret": func() discoverySim {
+		"EnvFrom: from Secret": func() sdtest.Sim {
 			httpd := newHTTPDPod()
 			mangle := func(c *corev1.Container) {
 				c.EnvFrom = []corev1.EnvFromSource{
@@ -451,9 +1198,10 @@ func TestPodDiscoverer_Discover(t *testing.T) {
 
 			disc, _ := prepareAllNsPodDiscoverer(httpd, secret)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					preparePodTargetGroupWithEnv(httpd, data),
 				},
 			}
@@ -463,7 +1211,7 @@ func TestPodDiscoverer_Discover(t *testing.T) {
 	for name, createSim := range tests {
 		t.Run(name, func(t *testing.T) {
 			sim := createSim()
-			sim.run(t)
+			sim.Run(t)
 		})
 	}
 }
@@ -510,7 +1258,12 @@ func newHTTPDPod() *corev1.Pod {
 			},
 		},
 		Status: corev1.PodStatus{
-			PodIP: "172.17.0.1",
+			Phase:  corev1.PodRunning,
+			HostIP: "192.168.0.1",
+			PodIP:  "172.17.0.1",
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
 		},
 	}
 }
@@ -541,7 +1294,12 @@ func newNGINXPod() *corev1.Pod {
 			},
 		},
 		Status: corev1.PodStatus{
-			PodIP: "172.17.0.2",
+			Phase:  corev1.PodRunning,
+			HostIP: "192.168.0.1",
+			PodIP:  "172.17.0.2",
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
 		},
 	}
 }
@@ -572,25 +1330,211 @@ func prepareSecret(name string, data map[string]string) *corev1.Secret {
 	}
 }
 
+func setHTTPDPodContainerState(pod *corev1.Pod) {
+	pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("250m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("0.5"),
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "httpd", Ready: true, RestartCount: 1},
+	}
+}
+
+func setDualStackPodIPs(pod *corev1.Pod, ipv6 string) {
+	pod.Status.PodIPs = []corev1.PodIP{{IP: pod.Status.PodIP}, {IP: ipv6}}
+}
+
+func addHTTPDPodInitContainers(pod *corev1.Pod) {
+	restartAlways := corev1.ContainerRestartPolicyAlways
+	pod.Spec.InitContainers = []corev1.Container{
+		{
+			Name:  "init-config",
+			Image: "busybox",
+			Ports: []corev1.ContainerPort{
+				{Name: "http", Protocol: corev1.ProtocolTCP, ContainerPort: 8080},
+			},
+		},
+		{
+			Name:          "exporter",
+			Image:         "httpd-exporter",
+			RestartPolicy: &restartAlways,
+			Ports: []corev1.ContainerPort{
+				{Name: "metrics", Protocol: corev1.ProtocolTCP, ContainerPort: 9117},
+			},
+		},
+	}
+	pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{
+		{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}},
+	}
+}
+
+func prepareReplicaSet(name string, owner *metav1.OwnerReference) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID("a03b8dc6-dc40-46dc-b571-5030e69d8162" + name),
+		},
+	}
+	if owner != nil {
+		rs.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return rs
+}
+
+func prepareJob(name string, owner *metav1.OwnerReference) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID("a03b8dc6-dc40-46dc-b571-5030e69d8163" + name),
+		},
+	}
+	if owner != nil {
+		job.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return job
+}
+
+func setPodController(pod *corev1.Pod, name, kind string) {
+	pod.OwnerReferences = []metav1.OwnerReference{
+		{Name: name, Kind: kind, Controller: &controllerTrue},
+	}
+}
+
 func prepareEmptyPodTargetGroup(pod *corev1.Pod) *podTargetGroup {
 	return &podTargetGroup{source: podSource(pod)}
 }
 
 func preparePodTargetGroup(pod *corev1.Pod) *podTargetGroup {
+	return preparePodTargetGroupWithAddressMode(pod, addressModePodIP)
+}
+
+func preparePodTargetGroupWithAddressMode(pod *corev1.Pod, mode string) *podTargetGroup {
 	tgg := prepareEmptyPodTargetGroup(pod)
 
 	for _, container := range pod.Spec.Containers {
-		for _, port := range container.Ports {
-			portNum := strconv.FormatUint(uint64(port.ContainerPort), 10)
+		tgg.targets = append(tgg.targets, preparePodContainerTargets(pod, podContainer{Container: container}, mode, "")...)
+	}
+
+	return tgg
+}
+
+func preparePodTargetGroupWithIPFamily(pod *corev1.Pod, family string) *podTargetGroup {
+	tgg := prepareEmptyPodTargetGroup(pod)
+
+	for _, container := range pod.Spec.Containers {
+		tgg.targets = append(tgg.targets, preparePodContainerTargets(pod, podContainer{Container: container}, addressModePodIP, family)...)
+	}
+
+	return tgg
+}
+
+func preparePodTargetGroupWithInitContainers(pod *corev1.Pod) *podTargetGroup {
+	tgg := preparePodTargetGroup(pod)
+
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy == nil || *container.RestartPolicy != corev1.ContainerRestartPolicyAlways {
+			continue
+		}
+		cntr := podContainer{Container: container, kind: containerKindInit}
+		tgg.targets = append(tgg.targets, preparePodContainerTargets(pod, cntr, addressModePodIP, "")...)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		cntr := podContainer{Container: corev1.Container(container.EphemeralContainerCommon), kind: containerKindEphemeral}
+		tgg.targets = append(tgg.targets, preparePodContainerTargets(pod, cntr, addressModePodIP, "")...)
+	}
+
+	return tgg
+}
+
+func preparePodContainerTargets(pod *corev1.Pod, container podContainer, mode, family string) []model.Target {
+	hosts, suffix := []string{pod.Status.PodIP}, ""
+	if mode == addressModeHost {
+		hosts, suffix = []string{pod.Status.HostIP}, "_host"
+	}
+	if family != "" {
+		hosts = hosts[:0]
+		for _, ip := range pod.Status.PodIPs {
+			if v6 := strings.Contains(ip.IP, ":"); family == ipFamilyAll || (family == ipFamilyIPv6) == v6 {
+				hosts = append(hosts, ip.IP)
+			}
+		}
+	}
+	tuid := func(tuid, host string) string {
+		if family == ipFamilyAll && strings.Contains(host, ":") {
+			tuid += "_v6"
+		}
+		return tuid + suffix
+	}
+	podIP := func(host string) string {
+		if mode == addressModeHost {
+			return pod.Status.PodIP
+		}
+		return host
+	}
+
+	ready, restarts := containerState(pod, container)
+
+	var targets []model.Target
+
+	if len(container.Ports) == 0 {
+		for _, host := range hosts {
 			tgt := &PodTarget{
-				tuid:           podTUIDWithPort(pod, container, port),
-				Address:        net.JoinHostPort(pod.Status.PodIP, portNum),
+				tuid:           tuid(podTUID(pod, container), host),
+				Address:        host,
 				Namespace:      pod.Namespace,
 				Name:           pod.Name,
 				Annotations:    mapAny(pod.Annotations),
 				Labels:         mapAny(pod.Labels),
 				NodeName:       pod.Spec.NodeName,
-				PodIP:          pod.Status.PodIP,
+				PodIP:          podIP(host),
+				ControllerName: "netdata-test",
+				ControllerKind: "DaemonSet",
+				ContName:       container.Name,
+				Image:          container.Image,
+				AddressMode:    mode,
+				Path:           pod.Annotations["netdata.io/path"],
+				Scheme:         pod.Annotations["netdata.io/scheme"],
+				CPURequest:     quantityString(container.Resources.Requests, corev1.ResourceCPU),
+				CPULimit:       quantityString(container.Resources.Limits, corev1.ResourceCPU),
+				MemoryRequest:  quantityString(container.Resources.Requests, corev1.ResourceMemory),
+				MemoryLimit:    quantityString(container.Resources.Limits, corev1.ResourceMemory),
+				Ready:          ready,
+				RestartCount:   restarts,
+			}
+			tgt.hash = mustCalcHash(tgt)
+			tgt.Tags().Merge(discoveryTags)
+
+			targets = append(targets, tgt)
+		}
+		return targets
+	}
+
+	for _, port := range container.Ports {
+		portNum := strconv.FormatUint(uint64(port.ContainerPort), 10)
+		if mode == addressModeHost && !pod.Spec.HostNetwork {
+			if port.HostPort == 0 {
+				continue
+			}
+			portNum = strconv.FormatUint(uint64(port.HostPort), 10)
+		}
+		for _, host := range hosts {
+			tgt := &PodTarget{
+				tuid:           tuid(podTUIDWithPort(pod, container, port), host),
+				Address:        net.JoinHostPort(host, portNum),
+				Namespace:      pod.Namespace,
+				Name:           pod.Name,
+				Annotations:    mapAny(pod.Annotations),
+				Labels:         mapAny(pod.Labels),
+				NodeName:       pod.Spec.NodeName,
+				PodIP:          podIP(host),
 				ControllerName: "netdata-test",
 				ControllerKind: "DaemonSet",
 				ContName:       container.Name,
@@ -599,15 +1543,24 @@ func preparePodTargetGroup(pod *corev1.Pod) *podTargetGroup {
 				Port:           portNum,
 				PortName:       port.Name,
 				PortProtocol:   string(port.Protocol),
+				AddressMode:    mode,
+				Path:           pod.Annotations["netdata.io/path"],
+				Scheme:         pod.Annotations["netdata.io/scheme"],
+				CPURequest:     quantityString(container.Resources.Requests, corev1.ResourceCPU),
+				CPULimit:       quantityString(container.Resources.Limits, corev1.ResourceCPU),
+				MemoryRequest:  quantityString(container.Resources.Requests, corev1.ResourceMemory),
+				MemoryLimit:    quantityString(container.Resources.Limits, corev1.ResourceMemory),
+				Ready:          ready,
+				RestartCount:   restarts,
 			}
 			tgt.hash = mustCalcHash(tgt)
 			tgt.Tags().Merge(discoveryTags)
 
-			tgg.targets = append(tgg.targets, tgt)
+			targets = append(targets, tgt)
 		}
 	}
 
-	return tgg
+	return targets
 }
 
 func preparePodTargetGroupWithEnv(pod *corev1.Pod, env map[string]string) *podTargetGroup {
@@ -620,3 +1573,15 @@ func preparePodTargetGroupWithEnv(pod *corev1.Pod, env map[string]string) *podTa
 
 	return tgg
 }
+
+func preparePodTargetGroupWithController(pod *corev1.Pod, name, kind string) *podTargetGroup {
+	tgg := preparePodTargetGroup(pod)
+
+	for _, tgt := range tgg.Targets() {
+		tgt.(*PodTarget).ControllerName = name
+		tgt.(*PodTarget).ControllerKind = kind
+		tgt.(*PodTarget).hash = mustCalcHash(tgt)
+	}
+
+	return tgg
+}
@@ -5,7 +5,6 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"net"
 	"strconv"
 	"strings"
 
@@ -42,7 +41,7 @@ type ServiceTarget struct {
 	PortProtocol string
 	ClusterIP    string
 	ExternalName string
-	Type         string
+	ServiceType  string
 }
 
 func (s ServiceTarget) Hash() uint64 { return s.hash }
@@ -142,24 +141,24 @@ func (s *serviceDiscoverer) handleQueueItem(ctx context.Context, in chan<- []mod
 }
 
 func (s *serviceDiscoverer) buildTargetGroup(svc *corev1.Service) model.TargetGroup {
-	// TODO: headless service?
-	if svc.Spec.ClusterIP == "" || len(svc.Spec.Ports) == 0 {
+	host := serviceHost(svc)
+	if host == "" || len(svc.Spec.Ports) == 0 {
 		return &serviceTargetGroup{
 			source: serviceSource(svc),
 		}
 	}
 	return &serviceTargetGroup{
 		source:  serviceSource(svc),
-		targets: s.buildTargets(svc),
+		targets: s.buildTargets(svc, host),
 	}
 }
 
-func (s *serviceDiscoverer) buildTargets(svc *corev1.Service) (targets []model.Target) {
+func (s *serviceDiscoverer) buildTargets(svc *corev1.Service, host string) (targets []model.Target) {
 	for _, port := range svc.Spec.Ports {
 		portNum := strconv.FormatInt(int64(port.Port), 10)
 		tgt := &ServiceTarget{
 			tuid:         serviceTUID(svc, port),
-			Address:      net.JoinHostPort(svc.Name+"."+svc.Namespace+".svc", portNum),
+			Address:      joinHostPort(host, portNum),
 			Namespace:    svc.Namespace,
 			Name:         svc.Name,
 			Annotations:  mapAny(svc.Annotations),
@@ -169,7 +168,7 @@ func (s *serviceDiscoverer) buildTargets(svc *corev1.Service) (targets []model.T
 			PortProtocol: string(port.Protocol),
 			ClusterIP:    svc.Spec.ClusterIP,
 			ExternalName: svc.Spec.ExternalName,
-			Type:         string(svc.Spec.Type),
+			ServiceType:  string(svc.Spec.Type),
 		}
 		hash, err := calcHash(tgt)
 		if err != nil {
@@ -183,6 +182,18 @@ func (s *serviceDiscoverer) buildTargets(svc *corev1.Service) (targets []model.T
 	return targets
 }
 
+func serviceHost(svc *corev1.Service) string {
+	// ExternalName services have no ClusterIP, they are DNS aliases for the external name.
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return svc.Spec.ExternalName
+	}
+	// Headless services (ClusterIP: None) have no virtual IP to address.
+	if svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		return ""
+	}
+	return svc.Spec.ClusterIP
+}
+
 func serviceTUID(svc *corev1.Service, port corev1.ServicePort) string {
 	return fmt.Sprintf("%s_%s_%s_%s",
 		svc.Namespace,
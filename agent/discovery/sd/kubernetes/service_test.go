@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/sdtest"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -26,17 +27,18 @@ func TestServiceTargetGroup_Provider(t *testing.T) {
 
 func TestServiceTargetGroup_Source(t *testing.T) {
 	tests := map[string]struct {
-		createSim   func() discoverySim
+		createSim   func() sdtest.Sim
 		wantSources []string
 	}{
 		"ClusterIP svc with multiple ports": {
-			createSim: func() discoverySim {
+			createSim: func() sdtest.Sim {
 				httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 				disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
 
-				return discoverySim{
-					td: disc,
-					wantTargetGroups: []model.TargetGroup{
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
 						prepareSvcTargetGroup(httpd),
 						prepareSvcTargetGroup(nginx),
 					},
@@ -54,7 +56,7 @@ func TestServiceTargetGroup_Source(t *testing.T) {
 			sim := test.createSim()
 
 			var sources []string
-			for _, tgg := range sim.run(t) {
+			for _, tgg := range sim.Run(t) {
 				sources = append(sources, tgg.Source())
 			}
 
@@ -65,17 +67,18 @@ func TestServiceTargetGroup_Source(t *testing.T) {
 
 func TestServiceTargetGroup_Targets(t *testing.T) {
 	tests := map[string]struct {
-		createSim   func() discoverySim
+		createSim   func() sdtest.Sim
 		wantTargets int
 	}{
 		"ClusterIP svc with multiple ports": {
-			createSim: func() discoverySim {
+			createSim: func() sdtest.Sim {
 				httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 				disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
 
-				return discoverySim{
-					td: disc,
-					wantTargetGroups: []model.TargetGroup{
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
 						prepareSvcTargetGroup(httpd),
 						prepareSvcTargetGroup(nginx),
 					},
@@ -90,7 +93,7 @@ func TestServiceTargetGroup_Targets(t *testing.T) {
 			sim := test.createSim()
 
 			var targets int
-			for _, tgg := range sim.run(t) {
+			for _, tgg := range sim.Run(t) {
 				targets += len(tgg.Targets())
 			}
 
@@ -101,27 +104,28 @@ func TestServiceTargetGroup_Targets(t *testing.T) {
 
 func TestServiceTarget_Hash(t *testing.T) {
 	tests := map[string]struct {
-		createSim  func() discoverySim
+		createSim  func() sdtest.Sim
 		wantHashes []uint64
 	}{
 		"ClusterIP svc with multiple ports": {
-			createSim: func() discoverySim {
+			createSim: func() sdtest.Sim {
 				httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 				disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
 
-				return discoverySim{
-					td: disc,
-					wantTargetGroups: []model.TargetGroup{
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
 						prepareSvcTargetGroup(httpd),
 						prepareSvcTargetGroup(nginx),
 					},
 				}
 			},
 			wantHashes: []uint64{
-				17611803477081780974,
-				6019985892433421258,
-				4151907287549842238,
-				5757608926096186119,
+				8855334700550712208,
+				8391091686620742700,
+				16925392979199533758,
+				7176749876951867482,
 			},
 		},
 	}
@@ -131,7 +135,7 @@ func TestServiceTarget_Hash(t *testing.T) {
 			sim := test.createSim()
 
 			var hashes []uint64
-			for _, tgg := range sim.run(t) {
+			for _, tgg := range sim.Run(t) {
 				for _, tgt := range tgg.Targets() {
 					hashes = append(hashes, tgt.Hash())
 				}
@@ -144,17 +148,18 @@ func TestServiceTarget_Hash(t *testing.T) {
 
 func TestServiceTarget_TUID(t *testing.T) {
 	tests := map[string]struct {
-		createSim func() discoverySim
+		createSim func() sdtest.Sim
 		wantTUID  []string
 	}{
 		"ClusterIP svc with multiple ports": {
-			createSim: func() discoverySim {
+			createSim: func() sdtest.Sim {
 				httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 				disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
 
-				return discoverySim{
-					td: disc,
-					wantTargetGroups: []model.TargetGroup{
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
 						prepareSvcTargetGroup(httpd),
 						prepareSvcTargetGroup(nginx),
 					},
@@ -167,6 +172,27 @@ func TestServiceTarget_TUID(t *testing.T) {
 				"default_nginx-cluster-ip-service_tcp_443",
 			},
 		},
+		"ExternalName svc with multiple ports": {
+			createSim: func() sdtest.Sim {
+				httpd, nginx := newHTTPDExternalNameService(), newNGINXExternalNameService()
+				disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
+
+				return sdtest.Sim{
+					Discoverer: disc,
+					Synced:     disc.synced,
+					WantTargetGroups: []model.TargetGroup{
+						prepareSvcTargetGroup(httpd),
+						prepareSvcTargetGroup(nginx),
+					},
+				}
+			},
+			wantTUID: []string{
+				"default_httpd-external-name-service_tcp_80",
+				"default_httpd-external-name-service_tcp_443",
+				"default_nginx-external-name-service_tcp_80",
+				"default_nginx-external-name-service_tcp_443",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -174,7 +200,7 @@ func TestServiceTarget_TUID(t *testing.T) {
 			sim := test.createSim()
 
 			var tuid []string
-			for _, tgg := range sim.run(t) {
+			for _, tgg := range sim.Run(t) {
 				for _, tgt := range tgg.Targets() {
 					tuid = append(tuid, tgt.TUID())
 				}
@@ -192,7 +218,7 @@ func TestNewServiceDiscoverer(t *testing.T) {
 	}{
 		"valid informer": {
 			wantPanic: false,
-			informer:  cache.NewSharedInformer(nil, &corev1.Service{}, resyncPeriod),
+			informer:  cache.NewSharedInformer(nil, &corev1.Service{}, defaultResyncPeriod),
 		},
 		"nil informer": {
 			wantPanic: true,
@@ -219,48 +245,51 @@ func TestServiceDiscoverer_String(t *testing.T) {
 }
 
 func TestServiceDiscoverer_Discover(t *testing.T) {
-	tests := map[string]func() discoverySim{
-		"ADD: ClusterIP svc exist before run": func() discoverySim {
+	tests := map[string]func() sdtest.Sim{
+		"ADD: ClusterIP svc exist before run": func() sdtest.Sim {
 			httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 			disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					prepareSvcTargetGroup(httpd),
 					prepareSvcTargetGroup(nginx),
 				},
 			}
 		},
-		"ADD: ClusterIP svc exist before run and add after sync": func() discoverySim {
+		"ADD: ClusterIP svc exist before run and add after sync": func() sdtest.Sim {
 			httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 			disc, client := prepareAllNsSvcDiscoverer(httpd)
 			svcClient := client.CoreV1().Services("default")
 
-			return discoverySim{
-				td: disc,
-				runAfterSync: func(ctx context.Context) {
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
 					_, _ = svcClient.Create(ctx, nginx, metav1.CreateOptions{})
 				},
-				wantTargetGroups: []model.TargetGroup{
+				WantTargetGroups: []model.TargetGroup{
 					prepareSvcTargetGroup(httpd),
 					prepareSvcTargetGroup(nginx),
 				},
 			}
 		},
-		"DELETE: ClusterIP svc remove after sync": func() discoverySim {
+		"DELETE: ClusterIP svc remove after sync": func() sdtest.Sim {
 			httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 			disc, client := prepareAllNsSvcDiscoverer(httpd, nginx)
 			svcClient := client.CoreV1().Services("default")
 
-			return discoverySim{
-				td: disc,
-				runAfterSync: func(ctx context.Context) {
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
 					time.Sleep(time.Millisecond * 50)
 					_ = svcClient.Delete(ctx, httpd.Name, metav1.DeleteOptions{})
 					_ = svcClient.Delete(ctx, nginx.Name, metav1.DeleteOptions{})
 				},
-				wantTargetGroups: []model.TargetGroup{
+				WantTargetGroups: []model.TargetGroup{
 					prepareSvcTargetGroup(httpd),
 					prepareSvcTargetGroup(nginx),
 					prepareEmptySvcTargetGroup(httpd),
@@ -268,38 +297,40 @@ func TestServiceDiscoverer_Discover(t *testing.T) {
 				},
 			}
 		},
-		"ADD,DELETE: ClusterIP svc remove and add after sync": func() discoverySim {
+		"ADD,DELETE: ClusterIP svc remove and add after sync": func() sdtest.Sim {
 			httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 			disc, client := prepareAllNsSvcDiscoverer(httpd)
 			svcClient := client.CoreV1().Services("default")
 
-			return discoverySim{
-				td: disc,
-				runAfterSync: func(ctx context.Context) {
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
 					time.Sleep(time.Millisecond * 50)
 					_ = svcClient.Delete(ctx, httpd.Name, metav1.DeleteOptions{})
 					_, _ = svcClient.Create(ctx, nginx, metav1.CreateOptions{})
 				},
-				wantTargetGroups: []model.TargetGroup{
+				WantTargetGroups: []model.TargetGroup{
 					prepareSvcTargetGroup(httpd),
 					prepareEmptySvcTargetGroup(httpd),
 					prepareSvcTargetGroup(nginx),
 				},
 			}
 		},
-		"ADD: Headless svc exist before run": func() discoverySim {
+		"ADD: Headless svc exist before run": func() sdtest.Sim {
 			httpd, nginx := newHTTPDHeadlessService(), newNGINXHeadlessService()
 			disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					prepareEmptySvcTargetGroup(httpd),
 					prepareEmptySvcTargetGroup(nginx),
 				},
 			}
 		},
-		"UPDATE: Headless => ClusterIP svc after sync": func() discoverySim {
+		"UPDATE: Headless => ClusterIP svc after sync": func() sdtest.Sim {
 			httpd, nginx := newHTTPDHeadlessService(), newNGINXHeadlessService()
 			httpdUpd, nginxUpd := *httpd, *nginx
 			httpdUpd.Spec.ClusterIP = "10.100.0.1"
@@ -307,14 +338,15 @@ func TestServiceDiscoverer_Discover(t *testing.T) {
 			disc, client := prepareAllNsSvcDiscoverer(httpd, nginx)
 			svcClient := client.CoreV1().Services("default")
 
-			return discoverySim{
-				td: disc,
-				runAfterSync: func(ctx context.Context) {
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
 					time.Sleep(time.Millisecond * 50)
 					_, _ = svcClient.Update(ctx, &httpdUpd, metav1.UpdateOptions{})
 					_, _ = svcClient.Update(ctx, &nginxUpd, metav1.UpdateOptions{})
 				},
-				wantTargetGroups: []model.TargetGroup{
+				WantTargetGroups: []model.TargetGroup{
 					prepareEmptySvcTargetGroup(httpd),
 					prepareEmptySvcTargetGroup(nginx),
 					prepareSvcTargetGroup(&httpdUpd),
@@ -322,15 +354,53 @@ func TestServiceDiscoverer_Discover(t *testing.T) {
 				},
 			}
 		},
-		"ADD: ClusterIP svc with zero exposed ports": func() discoverySim {
+		"ADD: ExternalName svc exist before run": func() sdtest.Sim {
+			httpd, nginx := newHTTPDExternalNameService(), newNGINXExternalNameService()
+			disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
+					prepareSvcTargetGroup(httpd),
+					prepareSvcTargetGroup(nginx),
+				},
+			}
+		},
+		"UPDATE: ClusterIP svc ports after sync": func() sdtest.Sim {
+			httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
+			httpdUpd, nginxUpd := *httpd, *nginx
+			httpdUpd.Spec.Ports = []corev1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 8080}}
+			nginxUpd.Spec.Ports = []corev1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 8080}}
+			disc, client := prepareAllNsSvcDiscoverer(httpd, nginx)
+			svcClient := client.CoreV1().Services("default")
+
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				AfterSync: func(ctx context.Context) {
+					time.Sleep(time.Millisecond * 50)
+					_, _ = svcClient.Update(ctx, &httpdUpd, metav1.UpdateOptions{})
+					_, _ = svcClient.Update(ctx, &nginxUpd, metav1.UpdateOptions{})
+				},
+				WantTargetGroups: []model.TargetGroup{
+					prepareSvcTargetGroup(httpd),
+					prepareSvcTargetGroup(nginx),
+					prepareSvcTargetGroup(&httpdUpd),
+					prepareSvcTargetGroup(&nginxUpd),
+				},
+			}
+		},
+		"ADD: ClusterIP svc with zero exposed ports": func() sdtest.Sim {
 			httpd, nginx := newHTTPDClusterIPService(), newNGINXClusterIPService()
 			httpd.Spec.Ports = httpd.Spec.Ports[:0]
 			nginx.Spec.Ports = httpd.Spec.Ports[:0]
 			disc, _ := prepareAllNsSvcDiscoverer(httpd, nginx)
 
-			return discoverySim{
-				td: disc,
-				wantTargetGroups: []model.TargetGroup{
+			return sdtest.Sim{
+				Discoverer: disc,
+				Synced:     disc.synced,
+				WantTargetGroups: []model.TargetGroup{
 					prepareEmptySvcTargetGroup(httpd),
 					prepareEmptySvcTargetGroup(nginx),
 				},
@@ -341,7 +411,7 @@ func TestServiceDiscoverer_Discover(t *testing.T) {
 	for name, createSim := range tests {
 		t.Run(name, func(t *testing.T) {
 			sim := createSim()
-			sim.run(t)
+			sim.Run(t)
 		})
 	}
 }
@@ -397,14 +467,34 @@ func newNGINXClusterIPService() *corev1.Service {
 func newHTTPDHeadlessService() *corev1.Service {
 	svc := newHTTPDClusterIPService()
 	svc.Name = "httpd-headless-service"
-	svc.Spec.ClusterIP = ""
+	svc.Spec.ClusterIP = corev1.ClusterIPNone
 	return svc
 }
 
 func newNGINXHeadlessService() *corev1.Service {
 	svc := newNGINXClusterIPService()
 	svc.Name = "nginx-headless-service"
+	svc.Spec.ClusterIP = corev1.ClusterIPNone
+	return svc
+}
+
+func newHTTPDExternalNameService() *corev1.Service {
+	svc := newHTTPDClusterIPService()
+	svc.Name = "httpd-external-name-service"
+	svc.Spec.Type = corev1.ServiceTypeExternalName
 	svc.Spec.ClusterIP = ""
+	svc.Spec.ExternalName = "httpd.example.com"
+	svc.Spec.Selector = nil
+	return svc
+}
+
+func newNGINXExternalNameService() *corev1.Service {
+	svc := newNGINXClusterIPService()
+	svc.Name = "nginx-external-name-service"
+	svc.Spec.Type = corev1.ServiceTypeExternalName
+	svc.Spec.ClusterIP = ""
+	svc.Spec.ExternalName = "nginx.example.com"
+	svc.Spec.Selector = nil
 	return svc
 }
 
@@ -415,11 +505,16 @@ func prepareEmptySvcTargetGroup(svc *corev1.Service) *serviceTargetGroup {
 func prepareSvcTargetGroup(svc *corev1.Service) *serviceTargetGroup {
 	tgg := prepareEmptySvcTargetGroup(svc)
 
+	host := svc.Spec.ClusterIP
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		host = svc.Spec.ExternalName
+	}
+
 	for _, port := range svc.Spec.Ports {
 		portNum := strconv.FormatInt(int64(port.Port), 10)
 		tgt := &ServiceTarget{
 			tuid:         serviceTUID(svc, port),
-			Address:      net.JoinHostPort(svc.Name+"."+svc.Namespace+".svc", portNum),
+			Address:      net.JoinHostPort(host, portNum),
 			Namespace:    svc.Namespace,
 			Name:         svc.Name,
 			Annotations:  mapAny(svc.Annotations),
@@ -429,7 +524,7 @@ func prepareSvcTargetGroup(svc *corev1.Service) *serviceTargetGroup {
 			PortProtocol: string(port.Protocol),
 			ClusterIP:    svc.Spec.ClusterIP,
 			ExternalName: svc.Spec.ExternalName,
-			Type:         string(svc.Spec.Type),
+			ServiceType:  string(svc.Spec.Type),
 		}
 		tgt.hash = mustCalcHash(tgt)
 		tgt.Tags().Merge(discoveryTags)
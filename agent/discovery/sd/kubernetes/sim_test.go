@@ -2,103 +2,14 @@
 
 package kubernetes
 
-import (
-	"context"
-	"sort"
-	"testing"
-	"time"
-
-	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
-
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
-	"k8s.io/client-go/tools/cache"
-)
-
-const (
-	startWaitTimeout  = time.Second * 3
-	finishWaitTimeout = time.Second * 5
-)
-
-type discoverySim struct {
-	td               *KubeDiscoverer
-	runAfterSync     func(ctx context.Context)
-	sortBeforeVerify bool
-	wantTargetGroups []model.TargetGroup
-}
-
-func (sim discoverySim) run(t *testing.T) []model.TargetGroup {
-	t.Helper()
-	require.NotNil(t, sim.td)
-	require.NotEmpty(t, sim.wantTargetGroups)
-
-	in, out := make(chan []model.TargetGroup), make(chan []model.TargetGroup)
-	go sim.collectTargetGroups(t, in, out)
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
-	go sim.td.Discover(ctx, in)
-
+// synced reports whether the discoverer has started and all its informers are synced.
+func (d *KubeDiscoverer) synced() bool {
 	select {
-	case <-sim.td.started:
-	case <-time.After(startWaitTimeout):
-		t.Fatalf("td %s filed to start in %s", sim.td.discoverers, startWaitTimeout)
-	}
-
-	synced := cache.WaitForCacheSync(ctx.Done(), sim.td.hasSynced)
-	require.Truef(t, synced, "td %s failed to sync", sim.td.discoverers)
-
-	if sim.runAfterSync != nil {
-		sim.runAfterSync(ctx)
-	}
-
-	groups := <-out
-
-	if sim.sortBeforeVerify {
-		sortTargetGroups(groups)
-	}
-
-	sim.verifyResult(t, groups)
-	return groups
-}
-
-func (sim discoverySim) collectTargetGroups(t *testing.T, in, out chan []model.TargetGroup) {
-	var tggs []model.TargetGroup
-loop:
-	for {
-		select {
-		case inGroups := <-in:
-			if tggs = append(tggs, inGroups...); len(tggs) >= len(sim.wantTargetGroups) {
-				break loop
-			}
-		case <-time.After(finishWaitTimeout):
-			t.Logf("td %s timed out after %s, got %d groups, expected %d, some events are skipped",
-				sim.td.discoverers, finishWaitTimeout, len(tggs), len(sim.wantTargetGroups))
-			break loop
-		}
-	}
-	out <- tggs
-}
-
-func (sim discoverySim) verifyResult(t *testing.T, result []model.TargetGroup) {
-	var expected, actual any
-
-	if len(sim.wantTargetGroups) == len(result) {
-		expected = sim.wantTargetGroups
-		actual = result
-	} else {
-		want := make(map[string]model.TargetGroup)
-		for _, group := range sim.wantTargetGroups {
-			want[group.Source()] = group
-		}
-		got := make(map[string]model.TargetGroup)
-		for _, group := range result {
-			got[group.Source()] = group
-		}
-		expected, actual = want, got
+	case <-d.started:
+		return d.hasSynced()
+	default:
+		return false
 	}
-
-	assert.Equal(t, expected, actual)
 }
 
 type hasSynced interface {
@@ -109,6 +20,7 @@ var (
 	_ hasSynced = &KubeDiscoverer{}
 	_ hasSynced = &podDiscoverer{}
 	_ hasSynced = &serviceDiscoverer{}
+	_ hasSynced = &endpointSliceDiscoverer{}
 )
 
 func (d *KubeDiscoverer) hasSynced() bool {
@@ -118,20 +30,33 @@ func (d *KubeDiscoverer) hasSynced() bool {
 			return false
 		}
 	}
+
+	d.nsMux.Lock()
+	defer d.nsMux.Unlock()
+
+	for _, nd := range d.nsDiscoverers {
+		for _, disc := range nd.discoverers {
+			v, ok := disc.(hasSynced)
+			if !ok || !v.hasSynced() {
+				return false
+			}
+		}
+	}
 	return true
 }
 
 func (p *podDiscoverer) hasSynced() bool {
-	return p.podInformer.HasSynced() && p.cmapInformer.HasSynced() && p.secretInformer.HasSynced()
+	return p.podInformer.HasSynced() &&
+		p.cmapInformer.HasSynced() &&
+		(p.secretInformer == nil || p.secretInformer.HasSynced()) &&
+		p.rsInformer.HasSynced() &&
+		p.jobInformer.HasSynced()
 }
 
 func (s *serviceDiscoverer) hasSynced() bool {
 	return s.informer.HasSynced()
 }
 
-func sortTargetGroups(tggs []model.TargetGroup) {
-	if len(tggs) == 0 {
-		return
-	}
-	sort.Slice(tggs, func(i, j int) bool { return tggs[i].Source() < tggs[j].Source() })
+func (e *endpointSliceDiscoverer) hasSynced() bool {
+	return e.informer.HasSynced()
 }
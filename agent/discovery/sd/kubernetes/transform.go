@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package kubernetes
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const annotationLastAppliedConfig = "kubectl.kubernetes.io/last-applied-configuration"
+
+var defaultStripAnnotations = []string{annotationLastAppliedConfig}
+
+// objectTransformer drops the parts of the cached objects the discoverers never read.
+// It is used as the informers cache.TransformFunc, so it runs before objects are stored.
+type objectTransformer struct {
+	stripAnnotations  []string
+	maxAnnotationSize int
+	// annotations with this prefix are used to build targets and are never stripped by size
+	keepPrefix string
+}
+
+func (t objectTransformer) transform(obj any) (any, error) {
+	switch v := obj.(type) {
+	case *corev1.Pod:
+		t.stripMeta(&v.ObjectMeta)
+		stripPod(v)
+	case *corev1.ConfigMap:
+		t.stripMeta(&v.ObjectMeta)
+	case *corev1.Secret:
+		t.stripMeta(&v.ObjectMeta)
+	}
+	return obj, nil
+}
+
+func (t objectTransformer) stripMeta(meta *metav1.ObjectMeta) {
+	meta.ManagedFields = nil
+
+	for _, name := range t.stripAnnotations {
+		delete(meta.Annotations, name)
+	}
+
+	if t.maxAnnotationSize <= 0 {
+		return
+	}
+	for k, v := range meta.Annotations {
+		if len(v) > t.maxAnnotationSize && (t.keepPrefix == "" || !strings.HasPrefix(k, t.keepPrefix)) {
+			delete(meta.Annotations, k)
+		}
+	}
+}
+
+func stripPod(pod *corev1.Pod) {
+	pod.Spec.Volumes = nil
+	pod.Spec.Affinity = nil
+	pod.Spec.Tolerations = nil
+
+	stripContainers(pod.Spec.Containers)
+	stripContainers(pod.Spec.InitContainers)
+	for i := range pod.Spec.EphemeralContainers {
+		c := &pod.Spec.EphemeralContainers[i].EphemeralContainerCommon
+		c.Command, c.Args, c.VolumeMounts = nil, nil, nil
+		c.LivenessProbe, c.ReadinessProbe, c.StartupProbe = nil, nil, nil
+	}
+
+	pod.Status.Message = ""
+	pod.Status.Reason = ""
+
+	var conds []corev1.PodCondition
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			conds = append(conds, corev1.PodCondition{Type: cond.Type, Status: cond.Status})
+		}
+	}
+	pod.Status.Conditions = conds
+
+	stripContainerStatuses(pod.Status.ContainerStatuses)
+	stripContainerStatuses(pod.Status.InitContainerStatuses)
+	stripContainerStatuses(pod.Status.EphemeralContainerStatuses)
+}
+
+func stripContainers(containers []corev1.Container) {
+	for i := range containers {
+		c := &containers[i]
+		c.Command, c.Args, c.VolumeMounts = nil, nil, nil
+		c.LivenessProbe, c.ReadinessProbe, c.StartupProbe = nil, nil, nil
+	}
+}
+
+func stripContainerStatuses(statuses []corev1.ContainerStatus) {
+	for i, st := range statuses {
+		statuses[i] = corev1.ContainerStatus{Name: st.Name, Ready: st.Ready, RestartCount: st.RestartCount}
+	}
+}
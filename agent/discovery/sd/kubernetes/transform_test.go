@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObjectTransformer_transform(t *testing.T) {
+	tests := map[string]struct {
+		tr              objectTransformer
+		wantAnnotations map[string]string
+	}{
+		"default strip list": {
+			tr: objectTransformer{stripAnnotations: defaultStripAnnotations},
+			wantAnnotations: map[string]string{
+				"phase":             "prod",
+				"netdata.io/port":   "80",
+				"example.com/large": strings.Repeat("x", 2048),
+			},
+		},
+		"max annotation size": {
+			tr: objectTransformer{stripAnnotations: defaultStripAnnotations, maxAnnotationSize: 1024, keepPrefix: "netdata.io/"},
+			wantAnnotations: map[string]string{
+				"phase":           "prod",
+				"netdata.io/port": "80",
+			},
+		},
+		"max annotation size keeps prefixed annotations": {
+			tr: objectTransformer{stripAnnotations: defaultStripAnnotations, maxAnnotationSize: 1, keepPrefix: "netdata.io/"},
+			wantAnnotations: map[string]string{
+				"netdata.io/port": "80",
+			},
+		},
+		"empty strip list": {
+			tr: objectTransformer{},
+			wantAnnotations: map[string]string{
+				"phase":                     "prod",
+				"netdata.io/port":           "80",
+				"example.com/large":         strings.Repeat("x", 2048),
+				annotationLastAppliedConfig: strings.Repeat("y", 4096),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := newSyntheticBloatedPod()
+
+			obj, err := test.tr.transform(pod)
+			require.NoError(t, err)
+
+			got := obj.(*corev1.Pod)
+			assert.Empty(t, got.ManagedFields)
+			assert.Equal(t, test.wantAnnotations, got.Annotations)
+			assert.Nil(t, got.Spec.Volumes)
+			assert.Nil(t, got.Spec.Containers[0].LivenessProbe)
+			assert.Equal(t, newHTTPDPod().Spec.Containers[0].Ports, got.Spec.Containers[0].Ports)
+			assert.Equal(t, []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}, got.Status.Conditions)
+			assert.Equal(t, []corev1.ContainerStatus{{Name: "httpd", Ready: true, RestartCount: 3}}, got.Status.ContainerStatuses)
+		})
+	}
+}
+
+func TestObjectTransformer_transform_ReducesRetainedSize(t *testing.T) {
+	tr := objectTransformer{stripAnnotations: defaultStripAnnotations, maxAnnotationSize: 1024, keepPrefix: "netdata.io/"}
+
+	var before, after int
+	for i := 0; i < 100; i++ {
+		pod := newSyntheticBloatedPod()
+		before += pod.Size()
+
+		obj, err := tr.transform(pod)
+		require.NoError(t, err)
+		after += obj.(*corev1.Pod).Size()
+	}
+
+	assert.Less(t, after, before/10, "retained size before: %d, after: %d", before, after)
+}
+
+func BenchmarkObjectTransformer_transform(b *testing.B) {
+	tr := objectTransformer{stripAnnotations: defaultStripAnnotations, maxAnnotationSize: 1024, keepPrefix: "netdata.io/"}
+	b.ReportAllocs()
+
+	var size int
+	for i := 0; i < b.N; i++ {
+		obj, _ := tr.transform(newSyntheticBloatedPod())
+		size = obj.(*corev1.Pod).Size()
+	}
+	b.ReportMetric(float64(size), "retained-bytes/pod")
+}
+
+func newSyntheticBloatedPod() *corev1.Pod {
+	pod := newHTTPDPod()
+	pod.Annotations["netdata.io/port"] = "80"
+	pod.Annotations["example.com/large"] = strings.Repeat("x", 2048)
+	pod.Annotations[annotationLastAppliedConfig] = strings.Repeat("y", 4096)
+
+	for i := 0; i < 10; i++ {
+		pod.ManagedFields = append(pod.ManagedFields, metav1.ManagedFieldsEntry{
+			Manager:    "kube-controller-manager",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{` + strings.Repeat(`"f:x":{},`, 50) + `}}}`)},
+		})
+	}
+
+	pod.Spec.Volumes = []corev1.Volume{
+		{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "httpd-config"}}}},
+	}
+	pod.Spec.Containers[0].LivenessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", "true"}}},
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions,
+		corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, Message: "scheduled"},
+	)
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:         "httpd",
+			Ready:        true,
+			RestartCount: 3,
+			Image:        "httpd:2.4",
+			ImageID:      "docker-pullable://httpd@sha256:" + strings.Repeat("a", 64),
+			ContainerID:  "containerd://" + strings.Repeat("b", 64),
+			State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		},
+	}
+
+	return pod
+}
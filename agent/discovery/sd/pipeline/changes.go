@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pipeline
+
+import (
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+)
+
+func newGroupChangeFilter() *groupChangeFilter {
+	return &groupChangeFilter{
+		sources: make(map[string]map[uint64]bool),
+	}
+}
+
+// groupChangeFilter drops target groups that have the same set of targets as the previously forwarded group
+// with the same source. Discoverers re-emit all their groups periodically (e.g. k8s informers resync),
+// most of them are unchanged and there is no need to run them through the classify and compose stages.
+type groupChangeFilter struct {
+	sources map[string]map[uint64]bool // [source][targetHash], nil hashes force forwarding of the next group
+}
+
+// changed returns the groups that differ from the previously forwarded ones and remembers them.
+// An empty group is forwarded only if the previous group with the same source had targets.
+func (f *groupChangeFilter) changed(tggs []model.TargetGroup) []model.TargetGroup {
+	var changed []model.TargetGroup
+
+	for _, tgg := range tggs {
+		hashes := targetHashes(tgg)
+		prev, ok := f.sources[tgg.Source()]
+
+		switch {
+		case !ok && len(hashes) == 0:
+			// nothing has been forwarded for the source, there is nothing to remove
+			continue
+		case ok && prev != nil && equalHashes(prev, hashes):
+			continue
+		}
+
+		if len(hashes) == 0 {
+			delete(f.sources, tgg.Source())
+		} else {
+			f.sources[tgg.Source()] = hashes
+		}
+		changed = append(changed, tgg)
+	}
+
+	return changed
+}
+
+// reset forces forwarding of the next emission of every known group, including the empty ones.
+func (f *groupChangeFilter) reset() {
+	for source := range f.sources {
+		f.sources[source] = nil
+	}
+}
+
+func targetHashes(tgg model.TargetGroup) map[uint64]bool {
+	hashes := make(map[uint64]bool, len(tgg.Targets()))
+	for _, tgt := range tgg.Targets() {
+		if tgt != nil {
+			hashes[tgt.Hash()] = true
+		}
+	}
+	return hashes
+}
+
+func equalHashes(a, b map[uint64]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for hash := range a {
+		if !b[hash] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pipeline
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestGroupChangeFilter_changed(t *testing.T) {
+	type step struct {
+		reset      bool
+		groups     []model.TargetGroup
+		wantGroups []model.TargetGroup
+	}
+	tests := map[string][]step{
+		"new groups": {
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2", "mock2")},
+				wantGroups: []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2", "mock2")},
+			},
+		},
+		"unchanged groups are dropped": {
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock1", "mock2"), newMockTargetGroup("pod2", "mock3")},
+				wantGroups: []model.TargetGroup{newMockTargetGroup("pod1", "mock1", "mock2"), newMockTargetGroup("pod2", "mock3")},
+			},
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock2", "mock1"), newMockTargetGroup("pod2", "mock3")},
+				wantGroups: nil,
+			},
+		},
+		"changed group is forwarded": {
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2", "mock2")},
+				wantGroups: []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2", "mock2")},
+			},
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock1", "mock3"), newMockTargetGroup("pod2", "mock2")},
+				wantGroups: []model.TargetGroup{newMockTargetGroup("pod1", "mock1", "mock3")},
+			},
+		},
+		"empty group after non-empty is forwarded once": {
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock1")},
+				wantGroups: []model.TargetGroup{newMockTargetGroup("pod1", "mock1")},
+			},
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1")},
+				wantGroups: []model.TargetGroup{newMockTargetGroup("pod1")},
+			},
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1")},
+				wantGroups: nil,
+			},
+		},
+		"empty new group is dropped": {
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1")},
+				wantGroups: nil,
+			},
+		},
+		"reset forces forwarding": {
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2", "mock2")},
+				wantGroups: []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2", "mock2")},
+			},
+			{
+				reset:      true,
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2")},
+				wantGroups: []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2")},
+			},
+			{
+				groups:     []model.TargetGroup{newMockTargetGroup("pod1", "mock1"), newMockTargetGroup("pod2")},
+				wantGroups: nil,
+			},
+		},
+	}
+
+	for name, steps := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newGroupChangeFilter()
+
+			for i, step := range steps {
+				if step.reset {
+					f.reset()
+				}
+				assert.Equalf(t, step.wantGroups, f.changed(step.groups), "step %d", i+1)
+			}
+		})
+	}
+}
+
+// BenchmarkPipeline_processGroups_Resync measures a steady-state resync of 1000 unchanged pods.
+func BenchmarkPipeline_processGroups_Resync(b *testing.B) {
+	const config = `
+classify:
+  - selector: "!nothing"
+    tags: "foo"
+    match:
+      - tags: "bar"
+        expr: '{{ glob .Name "mock*" }}'
+compose:
+  - selector: "foo"
+    config:
+      - selector: "bar"
+        template: |
+          name: {{ .Name }}
+`
+	var cfg Config
+	require.NoError(b, yaml.Unmarshal([]byte(config), &cfg))
+
+	tggs := make([]model.TargetGroup, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		tggs = append(tggs, newMockTargetGroup(fmt.Sprintf("pod%d", i), fmt.Sprintf("mock%d", i)))
+	}
+
+	for _, forceAll := range []bool{false, true} {
+		name := "changed groups"
+		if forceAll {
+			name = "all groups"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			p := newPipeline()
+			p.Mute()
+
+			st, err := p.newRuleStages(cfg)
+			require.NoError(b, err)
+			p.clr, p.cmr = st.clr, st.cmr
+
+			require.Len(b, p.processGroups(tggs), len(tggs))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if forceAll {
+					p.changes.reset()
+				}
+				p.processGroups(tggs)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ package pipeline
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"text/template"
 
@@ -30,6 +31,7 @@ type (
 		*logger.Logger
 		rules []*classifyRule
 		buf   bytes.Buffer
+		stats *pipelineStats
 	}
 
 	classifyRule struct {
@@ -48,10 +50,14 @@ func (c *targetClassificator) classify(tgt model.Target) model.Tags {
 	var tags model.Tags
 
 	for i, rule := range c.rules {
-		if !rule.sr.matches(tgt.Tags()) {
+		if !rule.sr.matchesTarget(tgt) {
 			continue
 		}
 
+		name := ruleStatsName(rule.name, i)
+		c.stats.classifyRule(name, func(s *RuleStats) { s.TargetsSeen++ })
+
+		var matched bool
 		for j, match := range rule.match {
 			c.buf.Reset()
 
@@ -69,6 +75,11 @@ func (c *targetClassificator) classify(tgt model.Target) model.Tags {
 
 			tags.Merge(rule.tags)
 			tags.Merge(match.tags)
+			matched = true
+		}
+
+		if matched {
+			c.stats.classifyRule(name, func(s *RuleStats) { s.TargetsMatched++ })
 		}
 	}
 
@@ -80,33 +91,33 @@ func newClassifyRules(cfg []ClassifyRuleConfig) ([]*classifyRule, error) {
 
 	fmap := newFuncMap()
 
-	for _, ruleCfg := range cfg {
+	for i, ruleCfg := range cfg {
 		rule := classifyRule{name: ruleCfg.Name}
 
 		sr, err := parseSelector(ruleCfg.Selector)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("'rule[%s][%d]->selector': %v", ruleCfg.Name, i+1, err)
 		}
 		rule.sr = sr
 
 		tags, err := model.ParseTags(ruleCfg.Tags)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("'rule[%s][%d]->tags': %v", ruleCfg.Name, i+1, err)
 		}
 		rule.tags = tags
 
-		for _, matchCfg := range ruleCfg.Match {
+		for j, matchCfg := range ruleCfg.Match {
 			var match classifyRuleMatch
 
 			tags, err := model.ParseTags(matchCfg.Tags)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("'rule[%s][%d]->match[%d]->tags': %v", ruleCfg.Name, i+1, j+1, err)
 			}
 			match.tags = tags
 
 			tmpl, err := parseTemplate(matchCfg.Expr, fmap)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("'rule[%s][%d]->match[%d]->expr': %v", ruleCfg.Name, i+1, j+1, err)
 			}
 			match.expr = tmpl
 
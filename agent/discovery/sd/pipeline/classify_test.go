@@ -72,3 +72,16 @@ func TestTargetClassificator_classify(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTargetClassificator_InvalidFieldMatch(t *testing.T) {
+	cfg := []ClassifyRuleConfig{
+		{Name: "valid", Selector: "match(re,Labels.app,^payments-)", Tags: "foo"},
+		{Name: "payments", Selector: "k8s !match(re,Labels.app,^(payments)", Tags: "foo"},
+	}
+
+	_, err := newTargetClassificator(cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'rule[payments][2]->selector'")
+	assert.Contains(t, err.Error(), "k8s !match(re,Labels.app,^(payments)")
+}
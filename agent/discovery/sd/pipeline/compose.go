@@ -4,6 +4,7 @@ package pipeline
 
 import (
 	"bytes"
+	"fmt"
 	"text/template"
 
 	"github.com/netdata/go.d.plugin/agent/confgroup"
@@ -32,6 +33,7 @@ type (
 		*logger.Logger
 		rules []*composeRule
 		buf   bytes.Buffer
+		stats *pipelineStats
 	}
 
 	composeRule struct {
@@ -49,12 +51,16 @@ func (c *configComposer) compose(tgt model.Target) []confgroup.Config {
 	var configs []confgroup.Config
 
 	for i, rule := range c.rules {
-		if !rule.sr.matches(tgt.Tags()) {
+		if !rule.sr.matchesTarget(tgt) {
 			continue
 		}
 
+		name := ruleStatsName(rule.name, i)
+		c.stats.composeRule(name, func(s *RuleStats) { s.TargetsSeen++ })
+
+		var composed int64
 		for j, conf := range rule.conf {
-			if !conf.sr.matches(tgt.Tags()) {
+			if !conf.sr.matchesTarget(tgt) {
 				continue
 			}
 
@@ -68,14 +74,21 @@ func (c *configComposer) compose(tgt model.Target) []confgroup.Config {
 				continue
 			}
 
-			var cfg confgroup.Config
+			for k, doc := range splitYAMLDocuments(c.buf.Bytes()) {
+				cfgs, err := unmarshalConfigs(doc)
+				if err != nil {
+					c.Warningf("rule '%s'->config[%d]: failed on yaml unmarshalling of document %d on target '%s': %v",
+						name, j+1, k+1, tgt.TUID(), err)
+					continue
+				}
 
-			if err := yaml.Unmarshal(c.buf.Bytes(), &cfg); err != nil {
-				c.Warningf("failed on yaml unmarshalling: %v", err)
-				continue
+				configs = append(configs, cfgs...)
+				composed += int64(len(cfgs))
 			}
+		}
 
-			configs = append(configs, cfg)
+		if composed > 0 {
+			c.stats.composeRule(name, func(s *RuleStats) { s.TargetsMatched++; s.ConfigsComposed += composed })
 		}
 	}
 
@@ -85,32 +98,92 @@ func (c *configComposer) compose(tgt model.Target) []confgroup.Config {
 	return configs
 }
 
+// splitYAMLDocuments splits the template output into YAML documents, empty documents are skipped.
+func splitYAMLDocuments(data []byte) [][]byte {
+	var docs [][]byte
+	var doc []byte
+
+	add := func() {
+		if len(bytes.TrimSpace(doc)) > 0 {
+			docs = append(docs, doc)
+		}
+		doc = nil
+	}
+
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		switch {
+		case bytes.Equal(bytes.TrimRight(line, " \t\r\n"), []byte("---")):
+			add()
+		case bytes.HasPrefix(line, []byte("--- ")):
+			// the document start marker may be followed by the document content
+			add()
+			doc = append(doc, line[len("--- "):]...)
+		default:
+			doc = append(doc, line...)
+		}
+	}
+	add()
+
+	return docs
+}
+
+// unmarshalConfigs unmarshals a YAML document that is either a single config or a list of configs.
+func unmarshalConfigs(doc []byte) ([]confgroup.Config, error) {
+	var v any
+	if err := yaml.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+
+	switch v.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		var cfgs []confgroup.Config
+		if err := yaml.Unmarshal(doc, &cfgs); err != nil {
+			return nil, err
+		}
+		var res []confgroup.Config
+		for _, cfg := range cfgs {
+			if len(cfg) > 0 {
+				res = append(res, cfg)
+			}
+		}
+		return res, nil
+	default:
+		var cfg confgroup.Config
+		if err := yaml.Unmarshal(doc, &cfg); err != nil {
+			return nil, err
+		}
+		return []confgroup.Config{cfg}, nil
+	}
+}
+
 func newComposeRules(cfg []ComposeRuleConfig) ([]*composeRule, error) {
 	var rules []*composeRule
 
 	fmap := newFuncMap()
 
-	for _, ruleCfg := range cfg {
+	for i, ruleCfg := range cfg {
 		rule := composeRule{name: ruleCfg.Name}
 
 		sr, err := parseSelector(ruleCfg.Selector)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("'rule[%s][%d]->selector': %v", ruleCfg.Name, i+1, err)
 		}
 		rule.sr = sr
 
-		for _, confCfg := range ruleCfg.Config {
+		for j, confCfg := range ruleCfg.Config {
 			var conf composeRuleConf
 
 			sr, err := parseSelector(confCfg.Selector)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("'rule[%s][%d]->config[%d]->selector': %v", ruleCfg.Name, i+1, j+1, err)
 			}
 			conf.sr = sr
 
 			tmpl, err := parseTemplate(confCfg.Template, fmap)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("'rule[%s][%d]->config[%d]->template': %v", ruleCfg.Name, i+1, j+1, err)
 			}
 			conf.tmpl = tmpl
 
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/hostsocket"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/kubernetes"
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
 
 	"github.com/stretchr/testify/assert"
@@ -88,3 +90,215 @@ func TestConfigComposer_compose(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigComposer_compose_MultipleConfigs(t *testing.T) {
+	config := `
+- selector: "rule1"
+  config:
+    - selector: "*"
+      template: |
+        {{- if eq .Name "none" -}}
+        {{- else if eq .Name "one" -}}
+        name: {{ .Name }}-nginx
+        {{- else -}}
+        ---
+        name: {{ .Name }}-nginx
+        ---
+        ---
+        name: {{ .Name }}-weblog
+        --- name: {{ .Name }}-phpfpm
+        {{- end -}}
+- selector: "rule2"
+  config:
+    - selector: "*"
+      template: |
+        - name: {{ .Name }}-1
+        - name: {{ .Name }}-2
+        ---
+        name: {{ .Name }}-3
+- selector: "rule3"
+  config:
+    - selector: "*"
+      template: |
+        name: {{ .Name }}-1
+        ---
+        name: [{{ .Name }}-2
+        ---
+        name: {{ .Name }}-3
+`
+	tests := map[string]struct {
+		target      model.Target
+		wantConfigs []confgroup.Config
+	}{
+		"zero configs": {
+			target:      newMockTarget("none", "rule1"),
+			wantConfigs: nil,
+		},
+		"one config": {
+			target: newMockTarget("one", "rule1"),
+			wantConfigs: []confgroup.Config{
+				{"name": "one-nginx"},
+			},
+		},
+		"three configs": {
+			target: newMockTarget("three", "rule1"),
+			wantConfigs: []confgroup.Config{
+				{"name": "three-nginx"},
+				{"name": "three-weblog"},
+				{"name": "three-phpfpm"},
+			},
+		},
+		"list and document": {
+			target: newMockTarget("mock", "rule2"),
+			wantConfigs: []confgroup.Config{
+				{"name": "mock-1"},
+				{"name": "mock-2"},
+				{"name": "mock-3"},
+			},
+		},
+		"malformed document fails only itself": {
+			target: newMockTarget("mock", "rule3"),
+			wantConfigs: []confgroup.Config{
+				{"name": "mock-1"},
+				{"name": "mock-3"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg []ComposeRuleConfig
+
+			err := yaml.Unmarshal([]byte(config), &cfg)
+			require.NoErrorf(t, err, "yaml unmarshalling of config")
+
+			cmr, err := newConfigComposer(cfg)
+			require.NoErrorf(t, err, "configComposer creation")
+
+			assert.Equal(t, test.wantConfigs, cmr.compose(test.target))
+		})
+	}
+}
+
+func TestConfigComposer_compose_NetSocketTarget(t *testing.T) {
+	config := `
+- selector: "netsocket"
+  config:
+    - selector: "*"
+      template: |
+        {{- if and (eq .Protocol "udp") (glob .Cmdline "**/unbound *") -}}
+        module: unbound
+        name: local{{ if .ContainerID }}_{{ .ContainerID | trunc 12 }}{{ end }}
+        address: {{ .Address }}:{{ .Port }}
+        {{- end -}}
+`
+	newTarget := func(protocol, cmdline, containerID string) model.Target {
+		tgt := &hostsocket.NetSocketTarget{
+			Protocol:    protocol,
+			Address:     "127.0.0.1",
+			Port:        "53",
+			Comm:        "unbound",
+			Cmdline:     cmdline,
+			ContainerID: containerID,
+		}
+		tgt.Tags().Merge(mustParseTags("netsocket"))
+		return tgt
+	}
+
+	tests := map[string]struct {
+		target      model.Target
+		wantConfigs []confgroup.Config
+	}{
+		"udp, cmdline matches": {
+			target: newTarget("udp", "/usr/sbin/unbound -d -p", ""),
+			wantConfigs: []confgroup.Config{
+				{"module": "unbound", "name": "local", "address": "127.0.0.1:53"},
+			},
+		},
+		"udp, cmdline matches, in container": {
+			target: newTarget("udp", "/usr/sbin/unbound -d -p", "3f4e8a1b2c3d4e5f60718293a4b5c6d7e8f90123456789abcdef0123456789ab"),
+			wantConfigs: []confgroup.Config{
+				{"module": "unbound", "name": "local_3f4e8a1b2c3d", "address": "127.0.0.1:53"},
+			},
+		},
+		"udp, cmdline not matches": {
+			target: newTarget("udp", "/usr/sbin/dnsmasq -k", ""),
+		},
+		"tcp, cmdline matches": {
+			target: newTarget("tcp", "/usr/sbin/unbound -d -p", ""),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg []ComposeRuleConfig
+
+			err := yaml.Unmarshal([]byte(config), &cfg)
+			require.NoErrorf(t, err, "yaml unmarshalling of config")
+
+			cmr, err := newConfigComposer(cfg)
+			require.NoErrorf(t, err, "configComposer creation")
+
+			assert.Equal(t, test.wantConfigs, cmr.compose(test.target))
+		})
+	}
+}
+
+func TestConfigComposer_compose_PodAnnotationSchedulingOptions(t *testing.T) {
+	config := `
+- selector: "k8s"
+  config:
+    - selector: "*"
+      template: |
+        module: httpd
+        name: {{ .Name }}
+        url: http://{{ .Address }}/server-status?auto
+        {{- with index .Annotations "netdata.io/update_every" }}
+        update_every: {{ . }}
+        {{- end }}
+        {{- with index .Annotations "netdata.io/priority" }}
+        priority: {{ . }}
+        {{- end }}
+`
+	newTarget := func(annotations map[string]any) model.Target {
+		tgt := &kubernetes.PodTarget{
+			Name:        "httpd",
+			Address:     "10.0.0.5:80",
+			Annotations: annotations,
+		}
+		tgt.Tags().Merge(mustParseTags("k8s"))
+		return tgt
+	}
+
+	tests := map[string]struct {
+		target      model.Target
+		wantConfigs []confgroup.Config
+	}{
+		"no annotations": {
+			target: newTarget(nil),
+			wantConfigs: []confgroup.Config{
+				{"module": "httpd", "name": "httpd", "url": "http://10.0.0.5:80/server-status?auto"},
+			},
+		},
+		"update_every and priority annotations": {
+			target: newTarget(map[string]any{"netdata.io/update_every": "5", "netdata.io/priority": "70100"}),
+			wantConfigs: []confgroup.Config{
+				{"module": "httpd", "name": "httpd", "url": "http://10.0.0.5:80/server-status?auto", "update_every": 5, "priority": 70100},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg []ComposeRuleConfig
+
+			err := yaml.Unmarshal([]byte(config), &cfg)
+			require.NoErrorf(t, err, "yaml unmarshalling of config")
+
+			cmr, err := newConfigComposer(cfg)
+			require.NoErrorf(t, err, "configComposer creation")
+
+			assert.Equal(t, test.wantConfigs, cmr.compose(test.target))
+		})
+	}
+}
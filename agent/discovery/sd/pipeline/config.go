@@ -5,9 +5,13 @@ package pipeline
 import (
 	"errors"
 	"fmt"
-	"github.com/netdata/go.d.plugin/agent/discovery/sd/hostsocket"
 
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/dnssrv"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/docker"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/hostsocket"
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/kubernetes"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/snmp"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/systemd"
 )
 
 type Config struct {
@@ -20,7 +24,11 @@ type Config struct {
 type (
 	DiscoveryConfig struct {
 		K8s        []kubernetes.Config `yaml:"k8s"`
+		Docker     []docker.Config     `yaml:"docker"`
 		HostSocket HostSocketConfig    `yaml:"hostsocket"`
+		SNMP       []snmp.Config       `yaml:"snmp"`
+		DNSSRV     []dnssrv.Config     `yaml:"dns_srv"`
+		Systemd    []systemd.Config    `yaml:"systemd"`
 	}
 	HostSocketConfig struct {
 		Net *hostsocket.NetworkSocketConfig `yaml:"net"`
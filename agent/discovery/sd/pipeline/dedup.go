@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+)
+
+func newConfigDeduplicator() *configDeduplicator {
+	return &configDeduplicator{
+		configs: make(map[uint64]*dedupEntry),
+		sources: make(map[string][]uint64),
+	}
+}
+
+type (
+	// configDeduplicator forwards only one instance of identical configs composed from different targets
+	// (e.g. all replicas of a Deployment when the template uses the Service address).
+	// Configs are identical if their hashes are equal, the hash doesn't include the source and the provider.
+	// Every contributing target holds a reference, the config is retracted when the last one is gone.
+	configDeduplicator struct {
+		configs map[uint64]*dedupEntry // [cfgHash]
+		sources map[string][]uint64    // [source]cfgHashes, a hash per contributing target
+	}
+	dedupEntry struct {
+		owner string                      // the source the config is sent with
+		refs  map[string]int              // [source]targets
+		cfgs  map[string]confgroup.Config // [source]config
+	}
+)
+
+// update applies the changed groups and returns the groups to send: the changed ones
+// and the ones that took over (or changed the number of targets of) a shared config.
+func (d *configDeduplicator) update(groups []*confgroup.Group) []*confgroup.Group {
+	var order []string
+	dirty := make(map[string]bool)
+	touched := make(map[uint64]bool)
+
+	markDirty := func(source string) {
+		if !dirty[source] {
+			dirty[source] = true
+			order = append(order, source)
+		}
+	}
+
+	for _, group := range groups {
+		markDirty(group.Source)
+
+		for _, hash := range d.sources[group.Source] {
+			touched[hash] = true
+			d.unref(hash, group.Source)
+		}
+		delete(d.sources, group.Source)
+
+		for _, cfg := range group.Configs {
+			hash := cfg.Hash()
+			touched[hash] = true
+			d.ref(hash, group.Source, cfg)
+		}
+	}
+
+	for _, hash := range sortedHashes(touched) {
+		entry, ok := d.configs[hash]
+		if !ok {
+			continue
+		}
+		if _, ok := entry.refs[entry.owner]; !ok {
+			entry.owner = entry.nextOwner()
+		}
+		// the owner group is resent because the config source lists the number of targets
+		markDirty(entry.owner)
+	}
+
+	var res []*confgroup.Group
+	for _, source := range order {
+		res = append(res, d.group(source))
+	}
+	return res
+}
+
+func (d *configDeduplicator) ref(hash uint64, source string, cfg confgroup.Config) {
+	entry, ok := d.configs[hash]
+	if !ok {
+		entry = &dedupEntry{
+			owner: source,
+			refs:  make(map[string]int),
+			cfgs:  make(map[string]confgroup.Config),
+		}
+		d.configs[hash] = entry
+	}
+	entry.refs[source]++
+	entry.cfgs[source] = cfg
+	d.sources[source] = append(d.sources[source], hash)
+}
+
+func (d *configDeduplicator) unref(hash uint64, source string) {
+	entry, ok := d.configs[hash]
+	if !ok {
+		return
+	}
+	if entry.refs[source]--; entry.refs[source] > 0 {
+		return
+	}
+	delete(entry.refs, source)
+	delete(entry.cfgs, source)
+	if len(entry.refs) == 0 {
+		delete(d.configs, hash)
+	}
+}
+
+func (d *configDeduplicator) group(source string) *confgroup.Group {
+	group := &confgroup.Group{Source: source}
+	seen := make(map[uint64]bool)
+
+	for _, hash := range d.sources[source] {
+		entry := d.configs[hash]
+		if seen[hash] || entry.owner != source {
+			continue
+		}
+		seen[hash] = true
+
+		cfg := entry.cfgs[source]
+		if n := entry.targets(); n > 1 {
+			cfg = copyConfig(cfg)
+			cfg.SetSource(fmt.Sprintf("%s, backed by %d targets", source, n))
+		}
+		group.Configs = append(group.Configs, cfg)
+	}
+
+	return group
+}
+
+func (e *dedupEntry) targets() (n int) {
+	for _, v := range e.refs {
+		n += v
+	}
+	return n
+}
+
+// nextOwner is the lowest source name to make the choice stable.
+func (e *dedupEntry) nextOwner() string {
+	var owner string
+	for source := range e.refs {
+		if owner == "" || source < owner {
+			owner = source
+		}
+	}
+	return owner
+}
+
+func copyConfig(cfg confgroup.Config) confgroup.Config {
+	c := make(confgroup.Config, len(cfg))
+	for k, v := range cfg {
+		c[k] = v
+	}
+	return c
+}
+
+func sortedHashes(hashes map[uint64]bool) []uint64 {
+	res := make([]uint64, 0, len(hashes))
+	for hash := range hashes {
+		res = append(res, hash)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
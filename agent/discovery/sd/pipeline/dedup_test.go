@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pipeline
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDeduplicator_update(t *testing.T) {
+	type step struct {
+		groups     []*confgroup.Group
+		wantGroups []*confgroup.Group
+	}
+	tests := map[string][]step{
+		"scale up": {
+			{
+				groups: []*confgroup.Group{newDedupGroup("pod1", "svc")},
+				wantGroups: []*confgroup.Group{
+					newDedupGroup("pod1", "svc"),
+				},
+			},
+			{
+				groups: []*confgroup.Group{newDedupGroup("pod2", "svc"), newDedupGroup("pod3", "svc")},
+				wantGroups: []*confgroup.Group{
+					newDedupGroup("pod2"),
+					newDedupGroup("pod3"),
+					newDedupGroupBackedBy("pod1", 3, "svc"),
+				},
+			},
+		},
+		"scale down to zero": {
+			{
+				groups: []*confgroup.Group{newDedupGroup("pod1", "svc"), newDedupGroup("pod2", "svc")},
+				wantGroups: []*confgroup.Group{
+					newDedupGroupBackedBy("pod1", 2, "svc"),
+					newDedupGroup("pod2"),
+				},
+			},
+			{
+				groups: []*confgroup.Group{newDedupGroup("pod1")},
+				wantGroups: []*confgroup.Group{
+					newDedupGroup("pod1"),
+					newDedupGroup("pod2", "svc"),
+				},
+			},
+			{
+				groups: []*confgroup.Group{newDedupGroup("pod2")},
+				wantGroups: []*confgroup.Group{
+					newDedupGroup("pod2"),
+				},
+			},
+		},
+		"same target group sends a config twice": {
+			{
+				groups: []*confgroup.Group{newDedupGroup("pod1", "svc", "svc")},
+				wantGroups: []*confgroup.Group{
+					newDedupGroupBackedBy("pod1", 2, "svc"),
+				},
+			},
+			{
+				groups: []*confgroup.Group{newDedupGroup("pod1", "svc")},
+				wantGroups: []*confgroup.Group{
+					newDedupGroup("pod1", "svc"),
+				},
+			},
+		},
+		"distinct configs that share a name": {
+			{
+				groups: []*confgroup.Group{
+					newDedupGroup("pod1", "svc"),
+					{Source: "pod2", Configs: []confgroup.Config{newDedupConfig("pod2", "svc", "url", "http://127.0.0.2")}},
+				},
+				wantGroups: []*confgroup.Group{
+					newDedupGroup("pod1", "svc"),
+					{Source: "pod2", Configs: []confgroup.Config{newDedupConfig("pod2", "svc", "url", "http://127.0.0.2")}},
+				},
+			},
+			{
+				groups: []*confgroup.Group{newDedupGroup("pod2")},
+				wantGroups: []*confgroup.Group{
+					newDedupGroup("pod2"),
+				},
+			},
+		},
+	}
+
+	for name, steps := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := newConfigDeduplicator()
+
+			for i, step := range steps {
+				groups := d.update(step.groups)
+
+				sortConfigGroups(groups)
+				sortConfigGroups(step.wantGroups)
+
+				assert.Equalf(t, step.wantGroups, groups, "step %d", i+1)
+			}
+		})
+	}
+}
+
+func TestConfigDeduplicator_update_KeepsRefsUntilLastTarget(t *testing.T) {
+	d := newConfigDeduplicator()
+
+	d.update([]*confgroup.Group{newDedupGroup("pod1", "svc"), newDedupGroup("pod2", "svc")})
+	d.update([]*confgroup.Group{newDedupGroup("pod1")})
+	assert.Len(t, d.configs, 1)
+
+	d.update([]*confgroup.Group{newDedupGroup("pod2")})
+	assert.Empty(t, d.configs)
+	assert.Empty(t, d.sources)
+}
+
+func newDedupGroup(source string, names ...string) *confgroup.Group {
+	group := &confgroup.Group{Source: source}
+	for _, name := range names {
+		group.Configs = append(group.Configs, newDedupConfig(source, name))
+	}
+	return group
+}
+
+func newDedupGroupBackedBy(source string, targets int, names ...string) *confgroup.Group {
+	group := newDedupGroup(source, names...)
+	for _, cfg := range group.Configs {
+		cfg.SetSource(fmt.Sprintf("%s, backed by %d targets", source, targets))
+	}
+	return group
+}
+
+func newDedupConfig(source, name string, kvs ...string) confgroup.Config {
+	cfg := confgroup.Config{"name": name, "module": "mock"}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		cfg[kvs[i]] = kvs[i+1]
+	}
+	cfg.SetSource(source)
+	cfg.SetProvider("mock")
+	return cfg
+}
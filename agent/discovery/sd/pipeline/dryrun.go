@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/ilyam8/hashstructure"
+	"gopkg.in/yaml.v2"
+)
+
+type DryRunConfig struct {
+	// TargetsFile is a targets fixture file, used instead of (or in addition to) the configured discoverers.
+	TargetsFile string
+	// Timeout limits the time to wait for the discoverers to sync.
+	Timeout time.Duration
+}
+
+// DryRun runs the configured discoverers for one sync cycle and writes a report to w: every target
+// with its tags after classification, the matched classify and compose rules, and the composed configs.
+// Nothing is sent to the job manager.
+func DryRun(ctx context.Context, cfg Config, conf DryRunConfig, w io.Writer) error {
+	p := newPipeline()
+
+	st, err := p.newRuleStages(cfg)
+	if err != nil {
+		return err
+	}
+	if err := p.registerDiscoverers(cfg); err != nil {
+		return err
+	}
+	if conf.TargetsFile != "" {
+		d, err := newFixtureDiscoverer(conf.TargetsFile)
+		if err != nil {
+			return err
+		}
+		p.discoverers = append(p.discoverers, d)
+	}
+	if len(p.discoverers) == 0 {
+		return errors.New("no discoverers configured and no targets file set")
+	}
+
+	timeout := conf.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	return p.dryRun(ctx, st, w, timeout, time.Second*3)
+}
+
+func (p *Pipeline) dryRun(ctx context.Context, st *ruleStages, w io.Writer, timeout, idle time.Duration) error {
+	tggs := collectTargetGroups(ctx, p.discoverers, timeout, idle)
+
+	var buf bytes.Buffer
+	writeDryRunReport(&buf, st, tggs)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// collectTargetGroups returns the latest target groups, it stops when all discoverers exited,
+// no updates came during the idle period or on timeout.
+func collectTargetGroups(ctx context.Context, discoverers []model.Discoverer, timeout, idle time.Duration) []model.TargetGroup {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	updates := make(chan []model.TargetGroup)
+	var wg sync.WaitGroup
+	for _, d := range discoverers {
+		wg.Add(1)
+		d := d
+		go func() { defer wg.Done(); d.Discover(ctx, updates) }()
+	}
+
+	done := make(chan struct{})
+	go func() { defer close(done); wg.Wait() }()
+
+	groups := make(map[string]model.TargetGroup)
+	var idleC <-chan time.Time
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-done:
+			break loop
+		case <-idleC:
+			break loop
+		case tggs := <-updates:
+			for _, tgg := range tggs {
+				groups[tgg.Source()] = tgg
+			}
+			idleC = time.After(idle)
+		}
+	}
+
+	cancel()
+	for {
+		select {
+		case <-updates:
+		case <-done:
+			tggs := make([]model.TargetGroup, 0, len(groups))
+			for _, tgg := range groups {
+				tggs = append(tggs, tgg)
+			}
+			sort.Slice(tggs, func(i, j int) bool { return tggs[i].Source() < tggs[j].Source() })
+			return tggs
+		}
+	}
+}
+
+func writeDryRunReport(w io.Writer, st *ruleStages, tggs []model.TargetGroup) {
+	var targets int
+	for _, tgg := range tggs {
+		targets += len(tgg.Targets())
+	}
+	_, _ = fmt.Fprintf(w, "discovered %d target group(s), %d target(s)\n", len(tggs), targets)
+
+	for _, tgg := range tggs {
+		_, _ = fmt.Fprintf(w, "\ngroup '%s' (%s)\n", tgg.Source(), tgg.Provider())
+		if len(tgg.Targets()) == 0 {
+			_, _ = fmt.Fprintln(w, "  no targets")
+			continue
+		}
+
+		tgts := append([]model.Target(nil), tgg.Targets()...)
+		sort.Slice(tgts, func(i, j int) bool { return tgts[i].TUID() < tgts[j].TUID() })
+
+		for _, tgt := range tgts {
+			writeDryRunTarget(w, st, tgt)
+		}
+	}
+}
+
+func writeDryRunTarget(w io.Writer, st *ruleStages, tgt model.Target) {
+	_, _ = fmt.Fprintf(w, "  target '%s'\n", tgt.TUID())
+
+	// fresh stats per target tell which rules matched it
+	stats := newPipelineStats()
+	st.clr.stats, st.cmr.stats = stats, stats
+	defer func() { st.clr.stats, st.cmr.stats = nil, nil }()
+
+	tags := st.clr.classify(tgt)
+	tgt.Tags().Merge(tags)
+
+	_, _ = fmt.Fprintf(w, "    tags: %s\n", tgt.Tags())
+
+	snapshot := stats.snapshot()
+	var classified []string
+	for i, rule := range st.clr.rules {
+		if name := ruleStatsName(rule.name, i); snapshot.ClassifyRules[name].TargetsMatched > 0 {
+			classified = append(classified, name)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "    classify rules: %s\n", joinOrNone(classified))
+
+	if len(tags) == 0 {
+		_, _ = fmt.Fprintln(w, "    compose rules: none")
+		_, _ = fmt.Fprintln(w, "    no config")
+		return
+	}
+
+	configs := st.cmr.compose(tgt)
+
+	snapshot = stats.snapshot()
+	var composed []string
+	for i, rule := range st.cmr.rules {
+		if name := ruleStatsName(rule.name, i); snapshot.ComposeRules[name].ConfigsComposed > 0 {
+			composed = append(composed, name)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "    compose rules: %s\n", joinOrNone(composed))
+
+	if len(configs) == 0 {
+		_, _ = fmt.Fprintln(w, "    no config")
+		return
+	}
+	for i, cfg := range configs {
+		_, _ = fmt.Fprintf(w, "    config[%d]:\n", i+1)
+		bs, err := yaml.Marshal(cfg)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "      failed to marshal: %v\n", err)
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(string(bs), "\n"), "\n") {
+			_, _ = fmt.Fprintf(w, "      %s\n", line)
+		}
+	}
+}
+
+func joinOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+type (
+	fixtureDiscoverer struct {
+		tggs []model.TargetGroup
+	}
+	fixtureTargetGroup struct {
+		source  string
+		targets []model.Target
+	}
+	// fixtureTarget fields are available in templates and selectors as they are in the fixture: {{ .Name }}.
+	fixtureTarget map[string]any
+)
+
+const (
+	fixtureTUIDKey = "__tuid__"
+	fixtureTagsKey = "__tags__"
+)
+
+func (d *fixtureDiscoverer) Discover(ctx context.Context, in chan<- []model.TargetGroup) {
+	select {
+	case <-ctx.Done():
+	case in <- d.tggs:
+	}
+}
+
+func (g *fixtureTargetGroup) Provider() string        { return "sd:fixture" }
+func (g *fixtureTargetGroup) Source() string          { return g.source }
+func (g *fixtureTargetGroup) Targets() []model.Target { return g.targets }
+
+func (t fixtureTarget) TUID() string     { v, _ := t[fixtureTUIDKey].(string); return v }
+func (t fixtureTarget) Tags() model.Tags { v, _ := t[fixtureTagsKey].(model.Tags); return v }
+func (t fixtureTarget) Hash() uint64 {
+	h, _ := hashstructure.Hash(t.TUID(), nil)
+	return h
+}
+
+// newFixtureDiscoverer reads the targets fixture file, a YAML list of target groups:
+//
+//	# the fields are available in selectors and templates as is: {{ .Name }}
+//	- source: fixture/nginx
+//	  targets:
+//	    - tuid: nginx_127.0.0.1_80
+//	      tags: "unknown"
+//	      fields:
+//	        Name: nginx
+//	        Address: 127.0.0.1:80
+func newFixtureDiscoverer(path string) (*fixtureDiscoverer, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		Source  string `yaml:"source"`
+		Targets []struct {
+			TUID   string         `yaml:"tuid"`
+			Tags   string         `yaml:"tags"`
+			Fields map[string]any `yaml:"fields"`
+		} `yaml:"targets"`
+	}
+	if err := yaml.Unmarshal(bs, &groups); err != nil {
+		return nil, fmt.Errorf("targets file '%s': %v", path, err)
+	}
+
+	d := &fixtureDiscoverer{}
+	for i, group := range groups {
+		if group.Source == "" {
+			return nil, fmt.Errorf("targets file '%s': group[%d]->source not set", path, i+1)
+		}
+		tgg := &fixtureTargetGroup{source: group.Source}
+
+		for j, tgtCfg := range group.Targets {
+			if tgtCfg.TUID == "" {
+				return nil, fmt.Errorf("targets file '%s': group[%d]->target[%d]->tuid not set", path, i+1, j+1)
+			}
+			tags, err := model.ParseTags(tgtCfg.Tags)
+			if err != nil {
+				return nil, fmt.Errorf("targets file '%s': group[%d]->target[%d]->tags: %v", path, i+1, j+1, err)
+			}
+
+			tgt := fixtureTarget{fixtureTUIDKey: tgtCfg.TUID, fixtureTagsKey: tags}
+			for k, v := range tgtCfg.Fields {
+				tgt[k] = v
+			}
+			tgg.targets = append(tgg.targets, tgt)
+		}
+
+		d.tggs = append(d.tggs, tgg)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPipeline_dryRun(t *testing.T) {
+	const config = `
+classify:
+  - name: "mocks"
+    selector: "rule1"
+    tags: "foo1"
+    match:
+      - tags: "bar1"
+        expr: '{{ glob .Name "mock*1*" }}'
+      - tags: "bar2"
+        expr: '{{ glob .Name "mock*2*" }}'
+compose:
+  - name: "foobar"
+    selector: "foo1"
+    config:
+      - selector: "bar1"
+        template: |
+          name: {{ .Name }}-foobar1
+  - selector: "bar2"
+    config:
+      - selector: "bar2"
+        template: |
+          name: {{ .Name }}-foobar2
+          ---
+          name: {{ .Name }}-foobar2-extra
+`
+	tests := map[string]struct {
+		discoverers []model.Discoverer
+		wantReport  string
+	}{
+		"no targets": {
+			discoverers: []model.Discoverer{
+				newMockDiscoverer("rule1", newMockTargetGroup("test")),
+			},
+			wantReport: `discovered 1 target group(s), 0 target(s)
+
+group 'test' (mock)
+  no targets
+`,
+		},
+		"targets from several discoverers": {
+			discoverers: []model.Discoverer{
+				newMockDiscoverer("rule1", newMockTargetGroup("test1", "mock2", "mock1", "mock3")),
+				newMockDiscoverer("", newMockTargetGroup("test2", "mock1")),
+			},
+			wantReport: `discovered 2 target group(s), 4 target(s)
+
+group 'test1' (mock)
+  target 'mock1'
+    tags: {bar1, foo1, rule1}
+    classify rules: mocks
+    compose rules: foobar
+    config[1]:
+      name: mock1-foobar1
+  target 'mock2'
+    tags: {bar2, foo1, rule1}
+    classify rules: mocks
+    compose rules: rule[2]
+    config[1]:
+      name: mock2-foobar2
+    config[2]:
+      name: mock2-foobar2-extra
+  target 'mock3'
+    tags: {rule1}
+    classify rules: none
+    compose rules: none
+    no config
+
+group 'test2' (mock)
+  target 'mock1'
+    tags: {}
+    classify rules: none
+    compose rules: none
+    no config
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg Config
+			require.NoError(t, yaml.Unmarshal([]byte(config), &cfg))
+
+			p := newPipeline()
+			p.discoverers = test.discoverers
+			st, err := p.newRuleStages(cfg)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, p.dryRun(context.Background(), st, &buf, time.Second*5, time.Millisecond*500))
+
+			assert.Equal(t, test.wantReport, buf.String())
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	const config = `
+classify:
+  - selector: "nginx"
+    tags: "web"
+    match:
+      - tags: "stub_status"
+        expr: '{{ eq .Port "80" }}'
+compose:
+  - selector: "web"
+    config:
+      - selector: "stub_status"
+        template: |
+          module: nginx
+          name: {{ .Name }}
+          url: http://{{ .Address }}/stub_status
+`
+	const targets = `
+- source: fixture/nginx
+  targets:
+    - tuid: nginx_127.0.0.1_80
+      tags: "nginx"
+      fields:
+        Name: nginx
+        Address: 127.0.0.1:80
+        Port: "80"
+`
+	tests := map[string]struct {
+		config     string
+		targets    string
+		wantErr    bool
+		wantReport string
+	}{
+		"targets file": {
+			config:  config,
+			targets: targets,
+			wantReport: `discovered 1 target group(s), 1 target(s)
+
+group 'fixture/nginx' (sd:fixture)
+  target 'nginx_127.0.0.1_80'
+    tags: {nginx, stub_status, web}
+    classify rules: rule[1]
+    compose rules: rule[1]
+    config[1]:
+      module: nginx
+      name: nginx
+      url: http://127.0.0.1:80/stub_status
+`,
+		},
+		"no discoverers": {
+			config:  config,
+			wantErr: true,
+		},
+		"invalid rules": {
+			config:  "classify:\n  - selector: nginx\n",
+			targets: targets,
+			wantErr: true,
+		},
+		"invalid targets file": {
+			config:  config,
+			targets: "- targets: []",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg Config
+			require.NoError(t, yaml.Unmarshal([]byte(test.config), &cfg))
+
+			var conf DryRunConfig
+			if test.targets != "" {
+				conf.TargetsFile = filepath.Join(t.TempDir(), "targets.yaml")
+				require.NoError(t, os.WriteFile(conf.TargetsFile, []byte(test.targets), 0644))
+			}
+
+			var buf bytes.Buffer
+			err := DryRun(context.Background(), cfg, conf, &buf)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.wantReport, buf.String())
+			}
+		})
+	}
+}
@@ -4,16 +4,40 @@ package pipeline
 
 import (
 	"regexp"
+	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v2"
 )
 
+// newFuncMap returns the functions available in classify expressions and compose templates.
+//
+// It is the sprig hermetic function map (no environment, file, time or random functions), so all functions are pure.
+// The commonly used ones (sprig argument order, the value is the last argument so they can be piped):
+//
+//	lower STR, upper STR                   - change the case: {{ .Name | lower }}
+//	trimPrefix PREFIX STR                  - remove the prefix: {{ .Image | trimPrefix "docker.io/" }}
+//	trimSuffix SUFFIX STR                  - remove the suffix: {{ .Image | trimSuffix ":latest" }}
+//	replace OLD NEW STR                    - replace all occurrences: {{ .Name | replace "-" "_" }}
+//	default DEFAULT VALUE                  - the default if the value is empty: {{ .Annotations.path | default "/metrics" }}
+//	contains SUBSTR STR                    - reports whether the string contains the substring
+//	hasPrefix PREFIX STR                   - reports whether the string has the prefix
+//	split SEP STR                          - split into a map with _0, _1, ... keys: {{ (split ":" .Image)._0 }}
+//	join SEP LIST                          - join a list: {{ list .Name .Port | join ":" }}
+//	quote VALUE                            - wrap in double quotes: {{ .Name | quote }}
+//
+// In addition:
+//
+//	glob VALUE PATTERN...  - reports whether the value matches any of the glob patterns
+//	re VALUE PATTERN...    - reports whether the value matches any of the regexp patterns
+//	toYaml VALUE           - the value as YAML, without the trailing newline: {{ .Labels | toYaml | nindent 2 }}
 func newFuncMap() template.FuncMap {
 	custom := map[string]interface{}{
-		"glob": globAny,
-		"re":   regexpAny,
+		"glob":   globAny,
+		"re":     regexpAny,
+		"toYaml": toYAML,
 	}
 
 	fm := sprig.HermeticTxtFuncMap()
@@ -51,3 +75,12 @@ func regexpOnce(value, pattern string) bool {
 	ok, err := regexp.MatchString(pattern, value)
 	return err == nil && ok
 }
+
+// toYAML returns an empty string if the value can't be marshalled, the templates have no way to handle an error.
+func toYAML(v any) string {
+	bs, err := yaml.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(bs), "\n")
+}
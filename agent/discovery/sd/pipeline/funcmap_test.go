@@ -3,10 +3,14 @@
 package pipeline
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/kubernetes"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_globAny(t *testing.T) {
@@ -93,3 +97,55 @@ func Test_regexpAny(t *testing.T) {
 		}
 	}
 }
+
+func Test_newFuncMap_Templates(t *testing.T) {
+	tgt := &kubernetes.PodTarget{
+		Name:        "Payments-API-0",
+		Namespace:   "default",
+		Image:       "docker.io/payments/api:latest",
+		Port:        "8080",
+		Annotations: map[string]any{"netdata.io/path": "/stats"},
+		Labels:      map[string]any{"app": "payments", "tier": "backend"},
+	}
+
+	tests := map[string]struct {
+		tmpl    string
+		wantOut string
+	}{
+		"lower":              {tmpl: `{{ .Name | lower }}`, wantOut: "payments-api-0"},
+		"upper":              {tmpl: `{{ .Namespace | upper }}`, wantOut: "DEFAULT"},
+		"trimPrefix":         {tmpl: `{{ .Image | trimPrefix "docker.io/" }}`, wantOut: "payments/api:latest"},
+		"trimSuffix":         {tmpl: `{{ .Image | trimSuffix ":latest" }}`, wantOut: "docker.io/payments/api"},
+		"replace":            {tmpl: `{{ .Name | lower | replace "-" "_" }}`, wantOut: "payments_api_0"},
+		"default missing":    {tmpl: `{{ index .Annotations "netdata.io/scheme" | default "http" }}`, wantOut: "http"},
+		"default present":    {tmpl: `{{ index .Annotations "netdata.io/path" | default "/metrics" }}`, wantOut: "/stats"},
+		"contains":           {tmpl: `{{ contains "payments" .Image }}`, wantOut: "true"},
+		"hasPrefix":          {tmpl: `{{ hasPrefix "quay.io/" .Image }}`, wantOut: "false"},
+		"split":              {tmpl: `{{ (split ":" .Image)._1 }}`, wantOut: "latest"},
+		"join":               {tmpl: `{{ list .Namespace .Name .Port | join "/" }}`, wantOut: "default/Payments-API-0/8080"},
+		"quote":              {tmpl: `{{ .Labels.app | quote }}`, wantOut: `"payments"`},
+		"toYaml":             {tmpl: `{{ .Labels | toYaml }}`, wantOut: "app: payments\ntier: backend"},
+		"toYaml nil":         {tmpl: `{{ .Env | toYaml }}`, wantOut: "{}"},
+		"toYaml with indent": {tmpl: "labels:{{ .Labels | toYaml | nindent 2 }}", wantOut: "labels:\n  app: payments\n  tier: backend"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := parseTemplate(test.tmpl, newFuncMap())
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, tmpl.Execute(&buf, tgt))
+
+			assert.Equal(t, test.wantOut, buf.String())
+		})
+	}
+}
+
+func Test_newFuncMap_Hermetic(t *testing.T) {
+	fm := newFuncMap()
+
+	for _, name := range []string{"env", "expandenv", "now", "randAlpha", "uuidv4"} {
+		assert.NotContainsf(t, fm, name, "function '%s'", name)
+	}
+}
@@ -4,13 +4,20 @@ package pipeline
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/dnssrv"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/docker"
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/hostsocket"
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/kubernetes"
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/snmp"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/systemd"
 	"github.com/netdata/go.d.plugin/logger"
 )
 
@@ -19,20 +26,36 @@ func New(cfg Config) (*Pipeline, error) {
 		return nil, err
 	}
 
-	p := &Pipeline{
+	p := newPipeline()
+
+	if err := p.registerDiscoverers(cfg); err != nil {
+		return nil, err
+	}
+
+	st, err := p.newRuleStages(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.clr, p.cmr = st.clr, st.cmr
+
+	return p, nil
+}
+
+func newPipeline() *Pipeline {
+	return &Pipeline{
 		Logger: logger.New().With(
 			slog.String("component", "discovery sd pipeline"),
 		),
 		accum:       newAccumulator(),
 		discoverers: make([]model.Discoverer, 0),
+		changes:     newGroupChangeFilter(),
 		items:       make(map[string]map[uint64][]confgroup.Config),
+		groups:      make(map[string]model.TargetGroup),
+		baseTags:    make(map[string]map[uint64]model.Tags),
+		dedup:       newConfigDeduplicator(),
+		stats:       newPipelineStats(),
+		reloads:     make(chan *ruleStages, 1),
 	}
-
-	if err := p.registerDiscoverers(cfg); err != nil {
-		return nil, err
-	}
-
-	return p, nil
 }
 
 type (
@@ -41,11 +64,26 @@ type (
 
 		discoverers []model.Discoverer
 		accum       *accumulator
+		changes     *groupChangeFilter
 
 		clr classificator
 		cmr composer
 
 		items map[string]map[uint64][]confgroup.Config // [source][targetHash]
+		dedup *configDeduplicator
+
+		// the last target groups and the targets tags before classification, used to re-run the rules on reload
+		groups   map[string]model.TargetGroup     // [source]
+		baseTags map[string]map[uint64]model.Tags // [source][targetHash]
+
+		reloadMux sync.Mutex
+		reloads   chan *ruleStages
+
+		stats *pipelineStats
+	}
+	ruleStages struct {
+		clr *targetClassificator
+		cmr *configComposer
 	}
 	classificator interface {
 		classify(model.Target) model.Tags
@@ -55,6 +93,57 @@ type (
 	}
 )
 
+// Stats returns a snapshot of the pipeline self-metrics. It is safe to call while the pipeline is running.
+func (p *Pipeline) Stats() Stats {
+	return p.stats.snapshot()
+}
+
+// Reload replaces the classify and compose rules of the running pipeline.
+// The cached target groups are re-run through the new rules: configs of the added rules are sent
+// and configs of the removed rules are retracted. An invalid config is rejected and the current rules are kept.
+// The discovery config is not reloaded.
+func (p *Pipeline) Reload(cfg Config) error {
+	st, err := p.newRuleStages(cfg)
+	if err != nil {
+		return err
+	}
+
+	p.reloadMux.Lock()
+	defer p.reloadMux.Unlock()
+
+	// only the latest rules matter if the previous reload has not been applied yet
+	select {
+	case <-p.reloads:
+	default:
+	}
+	p.reloads <- st
+
+	return nil
+}
+
+func (p *Pipeline) newRuleStages(cfg Config) (*ruleStages, error) {
+	if err := validateClassifyConfig(cfg.Classify); err != nil {
+		return nil, fmt.Errorf("tag rules: %v", err)
+	}
+	if err := validateComposeConfig(cfg.Compose); err != nil {
+		return nil, fmt.Errorf("config rules: %v", err)
+	}
+
+	clr, err := newTargetClassificator(cfg.Classify)
+	if err != nil {
+		return nil, err
+	}
+	clr.Logger, clr.stats = p.Logger, p.stats
+
+	cmr, err := newConfigComposer(cfg.Compose)
+	if err != nil {
+		return nil, err
+	}
+	cmr.Logger, cmr.stats = p.Logger, p.stats
+
+	return &ruleStages{clr: clr, cmr: cmr}, nil
+}
+
 func (p *Pipeline) registerDiscoverers(conf Config) error {
 	for _, cfg := range conf.Discovery.K8s {
 		td, err := kubernetes.NewKubeDiscoverer(cfg)
@@ -63,6 +152,13 @@ func (p *Pipeline) registerDiscoverers(conf Config) error {
 		}
 		p.discoverers = append(p.discoverers, td)
 	}
+	for _, cfg := range conf.Discovery.Docker {
+		td, err := docker.NewDiscoverer(cfg)
+		if err != nil {
+			return err
+		}
+		p.discoverers = append(p.discoverers, td)
+	}
 	if conf.Discovery.HostSocket.Net != nil {
 		td, err := hostsocket.NewNetSocketDiscoverer(*conf.Discovery.HostSocket.Net)
 		if err != nil {
@@ -70,6 +166,27 @@ func (p *Pipeline) registerDiscoverers(conf Config) error {
 		}
 		p.discoverers = append(p.discoverers, td)
 	}
+	for _, cfg := range conf.Discovery.SNMP {
+		td, err := snmp.NewDiscoverer(cfg)
+		if err != nil {
+			return err
+		}
+		p.discoverers = append(p.discoverers, td)
+	}
+	for _, cfg := range conf.Discovery.DNSSRV {
+		td, err := dnssrv.NewDiscoverer(cfg)
+		if err != nil {
+			return err
+		}
+		p.discoverers = append(p.discoverers, td)
+	}
+	for _, cfg := range conf.Discovery.Systemd {
+		td, err := systemd.NewDiscoverer(cfg)
+		if err != nil {
+			return err
+		}
+		p.discoverers = append(p.discoverers, td)
+	}
 
 	return nil
 }
@@ -98,6 +215,9 @@ func (p *Pipeline) Run(ctx context.Context, in chan<- []*confgroup.Group) {
 		case tggs := <-updates:
 			p.Infof("received %d target groups", len(tggs))
 			send(ctx, in, p.processGroups(tggs))
+		case st := <-p.reloads:
+			p.Infof("reloading rules, re-running %d target groups", len(p.groups))
+			send(ctx, in, p.reload(st))
 		}
 	}
 }
@@ -105,17 +225,74 @@ func (p *Pipeline) Run(ctx context.Context, in chan<- []*confgroup.Group) {
 func (p *Pipeline) processGroups(tggs []model.TargetGroup) []*confgroup.Group {
 	var confGroups []*confgroup.Group
 	// updates come from the accumulator, this ensures that all groups have different sources
-	for _, tgg := range tggs {
+	for _, tgg := range p.changes.changed(tggs) {
 		p.Infof("processing group '%s' with %d target(s)", tgg.Source(), len(tgg.Targets()))
 		if v := p.processGroup(tgg); v != nil {
 			confGroups = append(confGroups, v)
 		}
 	}
+	return p.emitGroups(confGroups)
+}
+
+func (p *Pipeline) reload(st *ruleStages) []*confgroup.Group {
+	p.clr, p.cmr = st.clr, st.cmr
+	p.stats.resetRules()
+	// the groups are re-run below, but the next emission of the discoverers goes through the new rules in full
+	p.changes.reset()
+
+	sources := make([]string, 0, len(p.groups))
+	for source := range p.groups {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var confGroups []*confgroup.Group
+	for _, source := range sources {
+		tgg := p.groups[source]
+
+		// the targets are classified from scratch, the tags added by the previous rules have to go
+		for _, tgt := range tgg.Targets() {
+			if tags, ok := p.baseTags[source][tgt.Hash()]; ok {
+				clear(tgt.Tags())
+				tgt.Tags().Merge(tags)
+			}
+		}
+
+		prev := p.items[source]
+		delete(p.items, source)
+
+		if v := p.processGroup(tgg); v != nil {
+			confGroups = append(confGroups, v)
+		} else if hasConfigs(prev) {
+			confGroups = append(confGroups, &confgroup.Group{Source: source})
+		}
+	}
+
+	return p.emitGroups(confGroups)
+}
+
+func (p *Pipeline) emitGroups(confGroups []*confgroup.Group) []*confgroup.Group {
+	if len(confGroups) == 0 {
+		return nil
+	}
+
+	confGroups = p.dedup.update(confGroups)
+
+	now := time.Now()
+	for _, group := range confGroups {
+		for _, cfg := range group.Configs {
+			p.stats.discoverer(cfg.Provider(), func(s *DiscovererStats) { s.ConfigsSent++; s.LastEmission = now })
+		}
+	}
+
 	return confGroups
 }
 
 func (p *Pipeline) processGroup(tgg model.TargetGroup) *confgroup.Group {
 	if len(tgg.Targets()) == 0 {
+		delete(p.groups, tgg.Source())
+		delete(p.baseTags, tgg.Source())
+
 		if _, ok := p.items[tgg.Source()]; !ok {
 			return nil
 		}
@@ -129,6 +306,11 @@ func (p *Pipeline) processGroup(tgg model.TargetGroup) *confgroup.Group {
 		p.items[tgg.Source()] = targetsCache
 	}
 
+	p.groups[tgg.Source()] = tgg
+	prevTags := p.baseTags[tgg.Source()]
+	baseTags := make(map[uint64]model.Tags)
+	p.baseTags[tgg.Source()] = baseTags
+
 	var changed bool
 	seen := make(map[uint64]bool)
 
@@ -140,14 +322,25 @@ func (p *Pipeline) processGroup(tgg model.TargetGroup) *confgroup.Group {
 		hash := tgt.Hash()
 		seen[hash] = true
 
+		if tags, ok := prevTags[hash]; ok {
+			baseTags[hash] = tags
+		} else {
+			baseTags[hash] = model.NewTags()
+			baseTags[hash].Merge(tgt.Tags())
+		}
+
 		if _, ok := targetsCache[hash]; ok {
 			continue
 		}
 
+		p.stats.discoverer(tgg.Provider(), func(s *DiscovererStats) { s.TargetsSeen++ })
+
 		if tags := p.clr.classify(tgt); len(tags) > 0 {
 			tgt.Tags().Merge(tags)
+			p.stats.discoverer(tgg.Provider(), func(s *DiscovererStats) { s.TargetsMatched++ })
 
 			if configs := p.cmr.compose(tgt); len(configs) > 0 {
+				p.stats.discoverer(tgg.Provider(), func(s *DiscovererStats) { s.ConfigsComposed += int64(len(configs)) })
 				for _, cfg := range configs {
 					cfg.SetProvider(tgg.Provider())
 					cfg.SetSource(tgg.Source())
@@ -183,6 +376,15 @@ func (p *Pipeline) processGroup(tgg model.TargetGroup) *confgroup.Group {
 	return cfgGroup
 }
 
+func hasConfigs(targetsCache map[uint64][]confgroup.Config) bool {
+	for _, cfgs := range targetsCache {
+		if len(cfgs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func send(ctx context.Context, in chan<- []*confgroup.Group, configs []*confgroup.Group) {
 	if len(configs) == 0 {
 		return
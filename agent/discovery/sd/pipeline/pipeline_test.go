@@ -78,6 +78,11 @@ compose:
 			wantClassifyCalls: 0,
 			wantComposeCalls:  0,
 			wantConfGroups:    nil,
+			wantStats: &Stats{
+				Discoverers:   map[string]DiscovererStats{},
+				ClassifyRules: map[string]RuleStats{},
+				ComposeRules:  map[string]RuleStats{},
+			},
 		},
 		"new group with targets": {
 			config: config,
@@ -102,6 +107,17 @@ compose:
 					},
 				}},
 			},
+			wantStats: &Stats{
+				Discoverers: map[string]DiscovererStats{
+					"mock": {TargetsSeen: 2, TargetsMatched: 2, ConfigsComposed: 2, ConfigsSent: 2},
+				},
+				ClassifyRules: map[string]RuleStats{
+					"rule[1]": {TargetsSeen: 2, TargetsMatched: 2},
+				},
+				ComposeRules: map[string]RuleStats{
+					"rule[1]": {TargetsSeen: 2, TargetsMatched: 2, ConfigsComposed: 2},
+				},
+			},
 		},
 		"existing group with same targets": {
 			config: config,
@@ -206,6 +222,17 @@ compose:
 					},
 				}},
 			},
+			wantStats: &Stats{
+				Discoverers: map[string]DiscovererStats{
+					"mock": {TargetsSeen: 4, TargetsMatched: 4, ConfigsComposed: 4, ConfigsSent: 6},
+				},
+				ClassifyRules: map[string]RuleStats{
+					"rule[1]": {TargetsSeen: 4, TargetsMatched: 4},
+				},
+				ComposeRules: map[string]RuleStats{
+					"rule[1]": {TargetsSeen: 4, TargetsMatched: 4, ConfigsComposed: 4},
+				},
+			},
 		},
 		"existing group with new targets only": {
 			config: config,
@@ -255,6 +282,113 @@ compose:
 	}
 }
 
+func TestPipeline_Reload(t *testing.T) {
+	const classify = `
+classify:
+  - selector: "rule1"
+    tags: "foo1"
+    match:
+%s
+`
+	const compose = `
+compose:
+  - selector: "foo1"
+    config:
+      - selector: "bar1"
+        template: |
+          name: {{ .Name }}-foobar1
+      - selector: "bar2"
+        template: |
+          name: {{ .Name }}-foobar2
+`
+	const matchBar1 = `
+      - tags: "bar1"
+        expr: '{{ glob .Name "mock*1*" }}'`
+	const matchBar2 = `
+      - tags: "bar2"
+        expr: '{{ glob .Name "mock*2*" }}'`
+	const matchNothing = `
+      - tags: "bar3"
+        expr: '{{ glob .Name "nothing" }}'`
+
+	newConfig := func(match string) Config {
+		var cfg Config
+		require.NoError(t, yaml.Unmarshal([]byte(fmt.Sprintf(classify, match)+compose), &cfg))
+		return cfg
+	}
+	newGroup := func(names ...string) *confgroup.Group {
+		group := &confgroup.Group{Source: "test"}
+		for _, name := range names {
+			group.Configs = append(group.Configs, confgroup.Config{
+				"__provider__": "mock",
+				"__source__":   "test",
+				"name":         name,
+			})
+		}
+		return group
+	}
+
+	type step struct {
+		match          string
+		wantErr        bool
+		wantConfGroups []*confgroup.Group
+	}
+	tests := map[string][]step{
+		"rule addition": {
+			{match: matchBar1 + matchBar2, wantConfGroups: []*confgroup.Group{newGroup("mock1-foobar1", "mock2-foobar2")}},
+		},
+		"rule removal": {
+			{match: matchBar2, wantConfGroups: []*confgroup.Group{newGroup("mock2-foobar2")}},
+		},
+		"rule removal retracts all configs": {
+			{match: matchNothing, wantConfGroups: []*confgroup.Group{newGroup()}},
+		},
+		"rule addition after removal": {
+			{match: matchNothing, wantConfGroups: []*confgroup.Group{newGroup()}},
+			{match: matchBar2, wantConfGroups: []*confgroup.Group{newGroup("mock2-foobar2")}},
+			{match: matchBar1 + matchBar2, wantConfGroups: []*confgroup.Group{newGroup("mock1-foobar1", "mock2-foobar2")}},
+		},
+		"invalid config keeps the current rules": {
+			{match: `
+      - tags: "bar2"
+        expr: '{{ glob .Name "mock*2*" '`, wantErr: true},
+			{match: matchBar1, wantConfGroups: []*confgroup.Group{newGroup("mock1-foobar1")}},
+		},
+	}
+
+	for name, steps := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := newPipeline()
+			st, err := p.newRuleStages(newConfig(matchBar1))
+			require.NoError(t, err)
+			p.clr, p.cmr = st.clr, st.cmr
+
+			tgg := newMockTargetGroup("test", "mock1", "mock2")
+			for _, tgt := range tgg.Targets() {
+				tgt.Tags().Merge(mustParseTags("rule1"))
+			}
+
+			groups := p.processGroups([]model.TargetGroup{tgg})
+			require.Equal(t, []*confgroup.Group{newGroup("mock1-foobar1")}, groups)
+
+			for i, step := range steps {
+				err := p.Reload(newConfig(step.match))
+				if step.wantErr {
+					assert.Errorf(t, err, "step %d", i+1)
+					assert.Lenf(t, p.reloads, 0, "step %d: pending reload", i+1)
+					continue
+				}
+				require.NoErrorf(t, err, "step %d", i+1)
+
+				groups := p.reload(<-p.reloads)
+
+				sortConfigGroups(groups)
+				assert.Equalf(t, step.wantConfGroups, groups, "step %d", i+1)
+			}
+		})
+	}
+}
+
 func newMockDiscoverer(tags string, tggs ...model.TargetGroup) *mockDiscoverer {
 	return &mockDiscoverer{
 		tags: mustParseTags(tags),
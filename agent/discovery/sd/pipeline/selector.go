@@ -5,13 +5,20 @@ package pipeline
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/bmatcuk/doublestar/v4"
 )
 
+// selector matches tags (matches) or the whole target (matchesTarget).
+// Field terms need the target, they never match tags alone.
 type selector interface {
 	matches(model.Tags) bool
+	matchesTarget(model.Target) bool
 }
 
 type (
@@ -20,6 +27,13 @@ type (
 	negSelector   struct{ selector }
 	orSelector    struct{ lhs, rhs selector }
 	andSelector   struct{ lhs, rhs selector }
+	// fieldSelector matches an exported target field value against a glob or regexp pattern.
+	fieldSelector struct {
+		kind    string
+		path    []string
+		pattern string
+		match   func(string) bool
+	}
 )
 
 func (s exactSelector) matches(tags model.Tags) bool { _, ok := tags[string(s)]; return ok }
@@ -27,29 +41,47 @@ func (s trueSelector) matches(model.Tags) bool       { return true }
 func (s negSelector) matches(tags model.Tags) bool   { return !s.selector.matches(tags) }
 func (s orSelector) matches(tags model.Tags) bool    { return s.lhs.matches(tags) || s.rhs.matches(tags) }
 func (s andSelector) matches(tags model.Tags) bool   { return s.lhs.matches(tags) && s.rhs.matches(tags) }
+func (s fieldSelector) matches(model.Tags) bool      { return false }
+
+func (s exactSelector) matchesTarget(tgt model.Target) bool { return s.matches(tgt.Tags()) }
+func (s trueSelector) matchesTarget(model.Target) bool      { return true }
+func (s negSelector) matchesTarget(tgt model.Target) bool   { return !s.selector.matchesTarget(tgt) }
+func (s orSelector) matchesTarget(tgt model.Target) bool {
+	return s.lhs.matchesTarget(tgt) || s.rhs.matchesTarget(tgt)
+}
+func (s andSelector) matchesTarget(tgt model.Target) bool {
+	return s.lhs.matchesTarget(tgt) && s.rhs.matchesTarget(tgt)
+}
+func (s fieldSelector) matchesTarget(tgt model.Target) bool {
+	v, ok := lookupTargetField(tgt, s.path)
+	return ok && s.match(v)
+}
 
 func (s exactSelector) String() string { return "{" + string(s) + "}" }
 func (s negSelector) String() string   { return "{!" + stringify(s.selector) + "}" }
 func (s trueSelector) String() string  { return "{*}" }
 func (s orSelector) String() string    { return "{" + stringify(s.lhs) + "|" + stringify(s.rhs) + "}" }
 func (s andSelector) String() string   { return "{" + stringify(s.lhs) + ", " + stringify(s.rhs) + "}" }
-func stringify(sr selector) string     { return strings.Trim(fmt.Sprintf("%s", sr), "{}") }
+func (s fieldSelector) String() string {
+	return fmt.Sprintf("{match(%s,%s,%s)}", s.kind, strings.Join(s.path, "."), s.pattern)
+}
+func stringify(sr selector) string { return strings.Trim(fmt.Sprintf("%s", sr), "{}") }
 
 func parseSelector(line string) (sr selector, err error) {
-	words := strings.Fields(line)
+	words := splitSelectorWords(line)
 	if len(words) == 0 {
 		return trueSelector{}, nil
 	}
 
 	var srs []selector
 	for _, word := range words {
-		if idx := strings.IndexByte(word, '|'); idx > 0 {
+		if len(splitOrWord(word)) > 1 {
 			sr, err = parseOrSelectorWord(word)
 		} else {
 			sr, err = parseSingleSelectorWord(word)
 		}
 		if err != nil {
-			return nil, fmt.Errorf("selector '%s' contains selector '%s' with forbidden symbol", line, word)
+			return nil, fmt.Errorf("selector '%s' contains invalid selector '%s': %v", line, word, err)
 		}
 		srs = append(srs, sr)
 	}
@@ -66,7 +98,7 @@ func parseSelector(line string) (sr selector, err error) {
 
 func parseOrSelectorWord(orWord string) (sr selector, err error) {
 	var srs []selector
-	for _, word := range strings.Split(orWord, "|") {
+	for _, word := range splitOrWord(orWord) {
 		if sr, err = parseSingleSelectorWord(word); err != nil {
 			return nil, err
 		}
@@ -93,6 +125,16 @@ func parseSingleSelectorWord(word string) (selector, error) {
 	if len(word) == 0 {
 		return nil, errors.New("empty word")
 	}
+	if strings.HasPrefix(word, "match(") {
+		sr, err := parseFieldSelectorWord(word)
+		if err != nil {
+			return nil, err
+		}
+		if neg {
+			return negSelector{sr}, nil
+		}
+		return sr, nil
+	}
 	if word != "*" && !isSelectorWordValid(word) {
 		return nil, errors.New("forbidden symbol")
 	}
@@ -110,6 +152,128 @@ func parseSingleSelectorWord(word string) (selector, error) {
 	return sr, nil
 }
 
+// parseFieldSelectorWord parses 'match(glob|re,Field.Path,pattern)'.
+func parseFieldSelectorWord(word string) (selector, error) {
+	if !strings.HasSuffix(word, ")") {
+		return nil, errors.New("unclosed 'match('")
+	}
+
+	args := strings.SplitN(word[len("match("):len(word)-1], ",", 3)
+	if len(args) == 3 {
+		// the pattern is used as is, it may contain spaces
+		args[0], args[1] = strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+	}
+	if len(args) != 3 || args[1] == "" || args[2] == "" {
+		return nil, errors.New("'match' expects 3 arguments: kind, field and pattern")
+	}
+
+	sr := fieldSelector{kind: args[0], path: strings.Split(args[1], "."), pattern: args[2]}
+
+	switch sr.kind {
+	case "glob":
+		if !doublestar.ValidatePattern(sr.pattern) {
+			return nil, fmt.Errorf("invalid glob pattern '%s'", sr.pattern)
+		}
+		sr.match = func(v string) bool { return globOnce(v, sr.pattern) }
+	case "re":
+		re, err := regexp.Compile(sr.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp pattern '%s': %v", sr.pattern, err)
+		}
+		sr.match = re.MatchString
+	default:
+		return nil, fmt.Errorf("unknown 'match' kind '%s', expected 'glob' or 're'", sr.kind)
+	}
+
+	return sr, nil
+}
+
+// splitSelectorWords splits the selector line by whitespace, ignoring the ones inside 'match(...)' so the patterns
+// may contain spaces.
+func splitSelectorWords(line string) []string {
+	var words []string
+	isSpace := func(b byte) bool { return b == ' ' || b == '\t' || b == '\n' || b == '\r' }
+
+	for _, word := range splitOutsideParens(line, isSpace) {
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// splitOrWord splits the word by '|', ignoring the ones inside parentheses ('match' regexp patterns).
+func splitOrWord(word string) []string {
+	return splitOutsideParens(word, func(b byte) bool { return b == '|' })
+}
+
+// splitOutsideParens splits s by the separators that are not inside parentheses.
+// A backslash escapes the next byte, so '\(' and '\)' in the patterns don't change the nesting.
+func splitOutsideParens(s string, isSep func(byte) bool) []string {
+	var parts []string
+	var depth, start int
+	for i := 0; i < len(s); i++ {
+		switch b := s[i]; {
+		case b == '\\':
+			i++
+		case b == '(':
+			depth++
+		case b == ')':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && isSep(b):
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// lookupTargetField returns the string value of the exported target field.
+// A map field consumes the rest of the path as the key, so label names with dots work: 'Labels.app.kubernetes.io/name'.
+func lookupTargetField(tgt model.Target, path []string) (string, bool) {
+	v := reflect.ValueOf(tgt)
+
+	for i := 0; i < len(path); i++ {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return "", false
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := v.Type().FieldByName(path[i])
+			if !ok || !f.IsExported() {
+				return "", false
+			}
+			v = v.FieldByIndex(f.Index)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return "", false
+			}
+			key := strings.Join(path[i:], ".")
+			if v = v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())); !v.IsValid() {
+				return "", false
+			}
+			i = len(path)
+		default:
+			return "", false
+		}
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+
+	return fmt.Sprint(v.Interface()), true
+}
+
 func newAndSelector(lhs, rhs selector, others ...selector) selector {
 	m := andSelector{lhs: lhs, rhs: rhs}
 	switch len(others) {
@@ -3,12 +3,15 @@
 package pipeline
 
 import (
+	"fmt"
 	"regexp"
 	"testing"
 
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/kubernetes"
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var reSrString = regexp.MustCompile(`^{[^{}]+}$`)
@@ -246,3 +249,113 @@ func TestParseSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSelector_FieldMatch(t *testing.T) {
+	tests := map[string]struct {
+		wantSelector string
+		wantErr      bool
+	}{
+		"match(re,Labels.app,^payments-.*)": {
+			wantSelector: "{match(re,Labels.app,^payments-.*)}",
+		},
+		"!match(glob,Labels.app,payments-*)": {
+			wantSelector: "{!match(glob,Labels.app,payments-*)}",
+		},
+		"match(re,Labels.app,^(a|b),c$)": {
+			wantSelector: "{match(re,Labels.app,^(a|b),c$)}",
+		},
+		"a|match(re,Labels.app,^(a|b)$)": {
+			wantSelector: "{a|match(re,Labels.app,^(a|b)$)}",
+		},
+		"k8s match(re,Labels.desc,^(payments api)$) pod": {
+			wantSelector: "{k8s, match(re,Labels.desc,^(payments api)$), pod}",
+		},
+		"match(glob, Labels.desc,payments api*)": {
+			wantSelector: "{match(glob,Labels.desc,payments api*)}",
+		},
+		"match(re,Labels.app,\\(a b)": {
+			wantSelector: "{match(re,Labels.app,\\(a b)}",
+		},
+		"match(re,Labels.app,^(a)":         {wantErr: true},
+		"match(glob,Labels.app,[a)":        {wantErr: true},
+		"match(regex,Labels.app,a)":        {wantErr: true},
+		"match(re,Labels.app)":             {wantErr: true},
+		"match(re,,a)":                     {wantErr: true},
+		"match(re,Labels.app,a":            {wantErr: true},
+		"a !match(glob,Labels.app,[a)|b c": {wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sr, err := parseSelector(name)
+
+			if test.wantErr {
+				assert.Nil(t, sr)
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.wantSelector, fmt.Sprintf("%s", sr))
+			}
+		})
+	}
+}
+
+func TestSelector_MatchesTarget(t *testing.T) {
+	newTarget := func(tags string, labels map[string]any) model.Target {
+		tgt := &kubernetes.PodTarget{Name: "payments-api-0", Labels: labels}
+		tgt.Tags().Merge(mustParseTags(tags))
+		return tgt
+	}
+	tgt := newTarget("k8s pod", map[string]any{
+		"app":                    "payments-api",
+		"app.kubernetes.io/name": "payments",
+		"tier":                   "backend",
+		"desc":                   "payments api server",
+	})
+
+	tests := map[string]struct {
+		target    model.Target
+		selector  string
+		wantMatch bool
+	}{
+		"glob label":                {target: tgt, selector: "match(glob,Labels.app,payments-*)", wantMatch: true},
+		"pattern with spaces":       {target: tgt, selector: "k8s match(glob,Labels.desc,payments api *)", wantMatch: true},
+		"glob label not match":      {target: tgt, selector: "match(glob,Labels.app,payments)", wantMatch: false},
+		"glob label whole value":    {target: tgt, selector: "match(glob,Labels.app,*-api)", wantMatch: true},
+		"re label":                  {target: tgt, selector: "match(re,Labels.app,^payments-)", wantMatch: true},
+		"re label partial value":    {target: tgt, selector: "match(re,Labels.app,api)", wantMatch: true},
+		"re label not match":        {target: tgt, selector: "match(re,Labels.app,^api)", wantMatch: false},
+		"re label with alternation": {target: tgt, selector: "match(re,Labels.tier,^(frontend|backend)$)", wantMatch: true},
+		"label key with dots":       {target: tgt, selector: "match(glob,Labels.app.kubernetes.io/name,payments)", wantMatch: true},
+		"missing label":             {target: tgt, selector: "match(glob,Labels.version,*)", wantMatch: false},
+		"negated missing label":     {target: tgt, selector: "!match(glob,Labels.version,*)", wantMatch: true},
+		"struct field":              {target: tgt, selector: "match(re,Name,-0$)", wantMatch: true},
+		"unknown field":             {target: tgt, selector: "match(glob,Unknown,*)", wantMatch: false},
+		"unexported field":          {target: tgt, selector: "match(glob,hash,*)", wantMatch: false},
+		"negated":                   {target: tgt, selector: "!match(re,Labels.app,^payments-)", wantMatch: false},
+		"and with tag":              {target: tgt, selector: "k8s match(re,Labels.app,^payments-)", wantMatch: true},
+		"and with negated tag":      {target: tgt, selector: "!k8s match(re,Labels.app,^payments-)", wantMatch: false},
+		"or binds tighter than and": {target: tgt, selector: "unknown|!match(re,Labels.app,^api) pod", wantMatch: true},
+		"or with negated terms":     {target: tgt, selector: "!k8s|!match(re,Labels.app,^payments-)", wantMatch: false},
+		"and of or groups":          {target: tgt, selector: "k8s|unknown !pod|match(glob,Labels.tier,back*)", wantMatch: true},
+		"and of or groups not match": {
+			target:    tgt,
+			selector:  "k8s|unknown !pod|match(glob,Labels.tier,front*)",
+			wantMatch: false,
+		},
+		"field term never matches tags only": {
+			target:    newTarget("k8s", nil),
+			selector:  "match(glob,Labels.app,*)",
+			wantMatch: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sr, err := parseSelector(test.selector)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.wantMatch, sr.matchesTarget(test.target))
+		})
+	}
+}
@@ -23,6 +23,7 @@ type discoverySim struct {
 	wantClassifyCalls int
 	wantComposeCalls  int
 	wantConfGroups    []*confgroup.Group
+	wantStats         *Stats
 }
 
 func (sim discoverySim) run(t *testing.T) {
@@ -48,14 +49,19 @@ func (sim discoverySim) run(t *testing.T) {
 		Logger:      logger.New(),
 		accum:       accum,
 		discoverers: sim.discoverers,
+		changes:     newGroupChangeFilter(),
 		clr:         mockClr,
 		cmr:         mockCmr,
 		items:       make(map[string]map[uint64][]confgroup.Config),
+		groups:      make(map[string]model.TargetGroup),
+		baseTags:    make(map[string]map[uint64]model.Tags),
+		dedup:       newConfigDeduplicator(),
+		stats:       newPipelineStats(),
 	}
 
 	pl.accum.Logger = pl.Logger
-	clr.Logger = pl.Logger
-	cmr.Logger = pl.Logger
+	clr.Logger, clr.stats = pl.Logger, pl.stats
+	cmr.Logger, cmr.stats = pl.Logger, pl.stats
 
 	groups := sim.collectGroups(t, pl)
 
@@ -65,6 +71,18 @@ func (sim discoverySim) run(t *testing.T) {
 	assert.Equal(t, sim.wantConfGroups, groups)
 	assert.Equalf(t, sim.wantClassifyCalls, mockClr.calls, "classify calls")
 	assert.Equalf(t, sim.wantComposeCalls, mockCmr.calls, "compose calls")
+
+	if sim.wantStats != nil {
+		stats := pl.Stats()
+		for k, v := range stats.Discoverers {
+			if v.ConfigsSent > 0 {
+				assert.Falsef(t, v.LastEmission.IsZero(), "discoverer '%s' last emission", k)
+			}
+			v.LastEmission = time.Time{}
+			stats.Discoverers[k] = v
+		}
+		assert.Equal(t, *sim.wantStats, stats)
+	}
 }
 
 func (sim discoverySim) collectGroups(t *testing.T, pl *Pipeline) []*confgroup.Group {
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pipeline
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Stats is a snapshot of the pipeline self-metrics.
+type Stats struct {
+	Discoverers   map[string]DiscovererStats `json:"discoverers"`
+	ClassifyRules map[string]RuleStats       `json:"classify_rules"`
+	ComposeRules  map[string]RuleStats       `json:"compose_rules"`
+}
+
+type DiscovererStats struct {
+	TargetsSeen     int64     `json:"targets_seen"`
+	TargetsMatched  int64     `json:"targets_matched"`
+	ConfigsComposed int64     `json:"configs_composed"`
+	ConfigsSent     int64     `json:"configs_sent"`
+	LastEmission    time.Time `json:"last_emission"`
+}
+
+type RuleStats struct {
+	TargetsSeen     int64 `json:"targets_seen"`
+	TargetsMatched  int64 `json:"targets_matched"`
+	ConfigsComposed int64 `json:"configs_composed"`
+}
+
+// pipelineStats is safe for concurrent use, all methods are no-op on a nil receiver.
+type pipelineStats struct {
+	mux           sync.Mutex
+	discoverers   map[string]*DiscovererStats
+	classifyRules map[string]*RuleStats
+	composeRules  map[string]*RuleStats
+}
+
+func newPipelineStats() *pipelineStats {
+	return &pipelineStats{
+		discoverers:   make(map[string]*DiscovererStats),
+		classifyRules: make(map[string]*RuleStats),
+		composeRules:  make(map[string]*RuleStats),
+	}
+}
+
+func (s *pipelineStats) discoverer(provider string, fn func(*DiscovererStats)) {
+	if s == nil {
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	v, ok := s.discoverers[provider]
+	if !ok {
+		v = &DiscovererStats{}
+		s.discoverers[provider] = v
+	}
+	fn(v)
+}
+
+func (s *pipelineStats) classifyRule(name string, fn func(*RuleStats)) {
+	if s == nil {
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	fn(ruleStats(s.classifyRules, name))
+}
+
+func (s *pipelineStats) composeRule(name string, fn func(*RuleStats)) {
+	if s == nil {
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	fn(ruleStats(s.composeRules, name))
+}
+
+// resetRules drops the rules stats, the rules are replaced on reload.
+func (s *pipelineStats) resetRules() {
+	if s == nil {
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	clear(s.classifyRules)
+	clear(s.composeRules)
+}
+
+func (s *pipelineStats) snapshot() Stats {
+	stats := Stats{
+		Discoverers:   make(map[string]DiscovererStats),
+		ClassifyRules: make(map[string]RuleStats),
+		ComposeRules:  make(map[string]RuleStats),
+	}
+	if s == nil {
+		return stats
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for k, v := range s.discoverers {
+		stats.Discoverers[k] = *v
+	}
+	for k, v := range s.classifyRules {
+		stats.ClassifyRules[k] = *v
+	}
+	for k, v := range s.composeRules {
+		stats.ComposeRules[k] = *v
+	}
+	return stats
+}
+
+func ruleStats(rules map[string]*RuleStats, name string) *RuleStats {
+	v, ok := rules[name]
+	if !ok {
+		v = &RuleStats{}
+		rules[name] = v
+	}
+	return v
+}
+
+func ruleStatsName(name string, idx int) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("rule[%d]", idx+1)
+}
@@ -4,37 +4,96 @@ package sd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"sync"
 
 	"github.com/netdata/go.d.plugin/agent/confgroup"
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/pipeline"
+	"github.com/netdata/go.d.plugin/agent/functions"
 	"github.com/netdata/go.d.plugin/logger"
 
+	"github.com/ilyam8/hashstructure"
 	"gopkg.in/yaml.v2"
 )
 
-func NewServiceDiscovery() (*ServiceDiscovery, error) {
-	return nil, nil
+type Config struct {
+	// ConfDir is the directory with the pipeline config files, the changes are applied without restart.
+	ConfDir string
+}
+
+func NewServiceDiscovery(cfg Config) (*ServiceDiscovery, error) {
+	if cfg.ConfDir == "" {
+		return nil, errors.New("service discovery: config dir not set")
+	}
+
+	d := &ServiceDiscovery{
+		Logger: logger.New().With(
+			slog.String("component", "service discovery"),
+		),
+		confProv:        NewDirConfigFileProvider(cfg.ConfDir),
+		sdFactory:       pipelineFactory{},
+		confCache:       make(map[string]uint64),
+		pipelines:       make(map[string]func()),
+		discoveryHashes: make(map[string]uint64),
+		running:         make(map[string]sdPipeline),
+	}
+
+	return d, nil
 }
 
 type (
 	ServiceDiscovery struct {
 		*logger.Logger
 
+		API NetdataAPI
+
 		confProv  ConfigFileProvider
 		sdFactory sdPipelineFactory
 
 		confCache map[string]uint64
 		pipelines map[string]func()
+		// [source]hash of the pipeline name and discovery config, only the rules can be reloaded in place
+		discoveryHashes map[string]uint64
+
+		mux     sync.Mutex
+		running map[string]sdPipeline
 	}
 	sdPipeline interface {
 		Run(ctx context.Context, in chan<- []*confgroup.Group)
+		Reload(cfg pipeline.Config) error
+		Stats() pipeline.Stats
 	}
 	sdPipelineFactory interface {
 		create(config pipeline.Config) (sdPipeline, error)
 	}
+
+	NetdataAPI interface {
+		FunctionResultSuccess(uid, contentType, payload string) error
+		FunctionResultReject(uid, contentType, payload string) error
+	}
+	FunctionRegistry interface {
+		Register(name string, reg func(functions.Function))
+	}
 )
 
+type pipelineFactory struct{}
+
+func (pipelineFactory) create(cfg pipeline.Config) (sdPipeline, error) {
+	pl, err := pipeline.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return pl, nil
+}
+
+// RegisterFunctions registers the 'sd-status' function that reports the self-metrics of the running pipelines.
+func (d *ServiceDiscovery) RegisterFunctions(r FunctionRegistry) {
+	r.Register("sd-status", d.sdStatus)
+}
+
 func (d *ServiceDiscovery) Run(ctx context.Context, in chan<- []*confgroup.Group) {
 	d.Info("instance is started")
 	defer d.Info("instance is stopped")
@@ -72,6 +131,18 @@ func (d *ServiceDiscovery) addPipeline(ctx context.Context, cf ConfigFile, in ch
 		return
 	}
 
+	discoveryHash := calcDiscoveryHash(cfg)
+
+	if pl, ok := d.running[cf.Source]; ok && d.discoveryHashes[cf.Source] == discoveryHash {
+		// the running pipeline keeps its discoverers and re-runs the cached targets through the new rules
+		if err := pl.Reload(cfg); err != nil {
+			d.Errorf("reload pipeline '%s', keeping the current rules: %v", cf.Source, err)
+			return
+		}
+		d.Infof("reloaded pipeline '%s' rules", cf.Source)
+		return
+	}
+
 	pl, err := d.sdFactory.create(cfg)
 	if err != nil {
 		d.Error(err)
@@ -90,6 +161,11 @@ func (d *ServiceDiscovery) addPipeline(ctx context.Context, cf ConfigFile, in ch
 	stop := func() { cancel(); wg.Wait() }
 
 	d.pipelines[cf.Source] = stop
+	d.discoveryHashes[cf.Source] = discoveryHash
+
+	d.mux.Lock()
+	d.running[cf.Source] = pl
+	d.mux.Unlock()
 }
 
 func (d *ServiceDiscovery) removePipeline(cf ConfigFile) {
@@ -97,10 +173,49 @@ func (d *ServiceDiscovery) removePipeline(cf ConfigFile) {
 		delete(d.pipelines, cf.Source)
 		stop()
 	}
+	delete(d.discoveryHashes, cf.Source)
+
+	d.mux.Lock()
+	delete(d.running, cf.Source)
+	d.mux.Unlock()
 }
 
 func (d *ServiceDiscovery) cleanup() {
 	for _, stop := range d.pipelines {
 		stop()
 	}
+
+	d.mux.Lock()
+	clear(d.running)
+	d.mux.Unlock()
+}
+
+func calcDiscoveryHash(cfg pipeline.Config) uint64 {
+	h, _ := hashstructure.Hash(struct {
+		Name      string
+		Discovery pipeline.DiscoveryConfig
+	}{cfg.Name, cfg.Discovery}, nil)
+	return h
+}
+
+func (d *ServiceDiscovery) sdStatus(fn functions.Function) {
+	if d.API == nil {
+		return
+	}
+
+	d.mux.Lock()
+	stats := make(map[string]pipeline.Stats, len(d.running))
+	for source, pl := range d.running {
+		stats[source] = pl.Stats()
+	}
+	d.mux.Unlock()
+
+	bs, err := json.Marshal(map[string]any{"pipelines": stats})
+	if err != nil {
+		d.Warningf("sd-status: %v", err)
+		_ = d.API.FunctionResultReject(fn.UID, "application/json", fmt.Sprintf(`{ "error": "%v" }`, err))
+		return
+	}
+
+	_ = d.API.FunctionResultSuccess(fn.UID, "application/json", string(bs))
 }
@@ -6,10 +6,37 @@ import (
 	"testing"
 
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/pipeline"
+	"github.com/netdata/go.d.plugin/agent/functions"
+	"github.com/netdata/go.d.plugin/logger"
 
+	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 )
 
+func TestNewServiceDiscovery(t *testing.T) {
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"valid config":       {cfg: Config{ConfDir: "/etc/netdata/go.d/sd"}},
+		"config dir not set": {cfg: Config{}, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewServiceDiscovery(test.cfg)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, d)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, d)
+			}
+		})
+	}
+}
+
 func TestServiceDiscovery_Run(t *testing.T) {
 	tests := map[string]discoverySim{
 		"add pipeline": {
@@ -55,6 +82,25 @@ func TestServiceDiscovery_Run(t *testing.T) {
 			},
 			wantPipelines: nil,
 		},
+		"reload pipeline rules": {
+			configs: []ConfigFile{
+				prepareConfigFile("source", "name"),
+				prepareConfigFileWithRule("source", "name", "rule1"),
+				prepareConfigFileWithRule("source", "name", "rule2"),
+			},
+			wantPipelines: []*mockPipeline{
+				{name: "name", started: true, stopped: false, reloaded: 2},
+			},
+		},
+		"invalid rules for running pipeline": {
+			configs: []ConfigFile{
+				prepareConfigFile("source", "name"),
+				prepareConfigFileWithRule("source", "name", "invalid"),
+			},
+			wantPipelines: []*mockPipeline{
+				{name: "name", started: true, stopped: false},
+			},
+		},
 		"invalid config for running pipeline": {
 			configs: []ConfigFile{
 				prepareConfigFile("source", "name"),
@@ -73,6 +119,67 @@ func TestServiceDiscovery_Run(t *testing.T) {
 	}
 }
 
+func TestServiceDiscovery_sdStatus(t *testing.T) {
+	tests := map[string]struct {
+		running     map[string]sdPipeline
+		wantPayload string
+	}{
+		"no pipelines": {
+			running:     map[string]sdPipeline{},
+			wantPayload: `{"pipelines":{}}`,
+		},
+		"running pipeline": {
+			running: map[string]sdPipeline{"source": &mockPipeline{name: "name"}},
+			wantPayload: `{"pipelines":{"source":{"discoverers":{"mock":{"targets_seen":1,"targets_matched":1,` +
+				`"configs_composed":1,"configs_sent":1,"last_emission":"0001-01-01T00:00:00Z"}},` +
+				`"classify_rules":null,"compose_rules":null}}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &mockAPI{}
+			reg := &mockFunctionRegistry{}
+			d := &ServiceDiscovery{Logger: logger.New(), API: api, running: test.running}
+
+			d.RegisterFunctions(reg)
+			fn, ok := reg.fns["sd-status"]
+			assert.True(t, ok)
+
+			fn(functions.Function{UID: "uid"})
+
+			assert.Equal(t, "uid", api.uid)
+			assert.Equal(t, "application/json", api.contentType)
+			assert.JSONEq(t, test.wantPayload, api.payload)
+		})
+	}
+}
+
+type mockAPI struct {
+	uid, contentType, payload string
+}
+
+func (m *mockAPI) FunctionResultSuccess(uid, contentType, payload string) error {
+	m.uid, m.contentType, m.payload = uid, contentType, payload
+	return nil
+}
+
+func (m *mockAPI) FunctionResultReject(uid, contentType, payload string) error {
+	m.uid, m.contentType, m.payload = uid, contentType, payload
+	return nil
+}
+
+type mockFunctionRegistry struct {
+	fns map[string]func(functions.Function)
+}
+
+func (m *mockFunctionRegistry) Register(name string, fn func(functions.Function)) {
+	if m.fns == nil {
+		m.fns = make(map[string]func(functions.Function))
+	}
+	m.fns[name] = fn
+}
+
 func prepareConfigFile(source, name string) ConfigFile {
 	bs, _ := yaml.Marshal(pipeline.Config{Name: name})
 
@@ -82,6 +189,18 @@ func prepareConfigFile(source, name string) ConfigFile {
 	}
 }
 
+func prepareConfigFileWithRule(source, name, selector string) ConfigFile {
+	bs, _ := yaml.Marshal(pipeline.Config{
+		Name:     name,
+		Classify: []pipeline.ClassifyRuleConfig{{Selector: selector}},
+	})
+
+	return ConfigFile{
+		Source: source,
+		Data:   bs,
+	}
+}
+
 func prepareEmptyConfigFile(source string) ConfigFile {
 	return ConfigFile{
 		Source: source,
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package sdtest provides a harness for testing service discovery discoverers.
+package sdtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	defaultSyncTimeout    = time.Second * 30
+	defaultCollectTimeout = time.Second * 5
+)
+
+// Sim runs a discoverer and verifies the target groups it sends.
+type Sim struct {
+	// Discoverer is the discoverer under test, it is run until the expected number of groups is received.
+	Discoverer model.Discoverer
+	// Synced reports whether the discoverer has got the initial state (e.g. informer caches are synced).
+	// AfterSync is not called until it returns true. Optional.
+	Synced func() bool
+	// AfterSync runs once the discoverer is synced, it is used to change the state the discoverer watches. Optional.
+	AfterSync func(ctx context.Context)
+	// WantTargetGroups are the expected groups in the order they are sent.
+	WantTargetGroups []model.TargetGroup
+	// IgnoreOrder compares the groups sorted by source, the order they are sent in doesn't matter.
+	IgnoreOrder bool
+	// SyncTimeout is how long to wait for Synced, 30 seconds if not set.
+	SyncTimeout time.Duration
+	// CollectTimeout is how long to wait for the next groups, 5 seconds if not set.
+	CollectTimeout time.Duration
+}
+
+// Run runs the simulation and returns the received groups.
+// If the discoverer sends more groups than expected, the last group of every source is compared.
+func (sim Sim) Run(t *testing.T) []model.TargetGroup {
+	t.Helper()
+	require.NotNil(t, sim.Discoverer, "discoverer not set")
+	require.NotEmpty(t, sim.WantTargetGroups, "expected target groups not set")
+
+	syncTimeout, collectTimeout := sim.SyncTimeout, sim.CollectTimeout
+	if syncTimeout == 0 {
+		syncTimeout = defaultSyncTimeout
+	}
+	if collectTimeout == 0 {
+		collectTimeout = defaultCollectTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in, out := make(chan []model.TargetGroup), make(chan []model.TargetGroup, 1)
+	go sim.collect(t, in, out, collectTimeout)
+
+	done := make(chan struct{})
+	go func() { defer close(done); sim.Discoverer.Discover(ctx, in) }()
+
+	if sim.Synced != nil {
+		require.Eventuallyf(t, sim.Synced, syncTimeout, time.Millisecond*100,
+			"discoverer %v failed to sync in %s", sim.Discoverer, syncTimeout)
+	}
+
+	if sim.AfterSync != nil {
+		sim.AfterSync(ctx)
+	}
+
+	groups := <-out
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(collectTimeout):
+		t.Errorf("discoverer %v hasn't finished in %s after cancel", sim.Discoverer, collectTimeout)
+	}
+
+	sim.verify(t, groups)
+
+	return groups
+}
+
+func (sim Sim) collect(t *testing.T, in <-chan []model.TargetGroup, out chan<- []model.TargetGroup, timeout time.Duration) {
+	var tggs []model.TargetGroup
+	defer func() { out <- tggs }()
+
+	for {
+		select {
+		case groups := <-in:
+			if tggs = append(tggs, groups...); len(tggs) >= len(sim.WantTargetGroups) {
+				return
+			}
+		case <-time.After(timeout):
+			t.Logf("discoverer %v timed out after %s, got %d groups, expected %d, some events are skipped",
+				sim.Discoverer, timeout, len(tggs), len(sim.WantTargetGroups))
+			return
+		}
+	}
+}
+
+func (sim Sim) verify(t *testing.T, got []model.TargetGroup) {
+	t.Helper()
+
+	want, ignoreOrder := sim.WantTargetGroups, sim.IgnoreOrder
+
+	if len(want) != len(got) {
+		// the discoverer re-sent some groups, only the last state of every source matters
+		want, got = lastBySource(want), lastBySource(got)
+		ignoreOrder = true
+	}
+	if ignoreOrder {
+		want, got = sortedBySource(want), sortedBySource(got)
+	}
+
+	if diff := diffSources(want, got); diff != "" {
+		t.Errorf("target groups sources mismatch:\n%s", diff)
+	}
+	assert.Equal(t, want, got)
+}
+
+func lastBySource(tggs []model.TargetGroup) []model.TargetGroup {
+	seen := make(map[string]int)
+	var res []model.TargetGroup
+
+	for _, tgg := range tggs {
+		if i, ok := seen[tgg.Source()]; ok {
+			res[i] = tgg
+			continue
+		}
+		seen[tgg.Source()] = len(res)
+		res = append(res, tgg)
+	}
+	return res
+}
+
+func sortedBySource(tggs []model.TargetGroup) []model.TargetGroup {
+	res := append([]model.TargetGroup(nil), tggs...)
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Source() < res[j].Source() })
+	return res
+}
+
+// diffSources returns the sources that are expected but not received and vice versa.
+func diffSources(want, got []model.TargetGroup) string {
+	count := make(map[string]int)
+	for _, tgg := range want {
+		count[tgg.Source()]++
+	}
+	for _, tgg := range got {
+		count[tgg.Source()]--
+	}
+
+	var sources []string
+	for source := range count {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var sb strings.Builder
+	for _, source := range sources {
+		switch n := count[source]; {
+		case n > 0:
+			fmt.Fprintf(&sb, "- %s (missing)\n", source)
+		case n < 0:
+			fmt.Fprintf(&sb, "+ %s (unexpected)\n", source)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package sdtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSim_Run(t *testing.T) {
+	tests := map[string]func() Sim{
+		"groups in order": func() Sim {
+			return Sim{
+				Discoverer:       newMockDiscoverer(newMockGroup("a"), newMockGroup("b")),
+				WantTargetGroups: []model.TargetGroup{newMockGroup("a"), newMockGroup("b")},
+			}
+		},
+		"groups in any order": func() Sim {
+			return Sim{
+				Discoverer:       newMockDiscoverer(newMockGroup("b"), newMockGroup("a")),
+				IgnoreOrder:      true,
+				WantTargetGroups: []model.TargetGroup{newMockGroup("a"), newMockGroup("b")},
+			}
+		},
+		"re-sent group": func() Sim {
+			return Sim{
+				Discoverer:       newMockDiscoverer(newMockGroup("a"), newMockGroup("b"), newMockGroup("a", "x")),
+				WantTargetGroups: []model.TargetGroup{newMockGroup("b"), newMockGroup("a", "x")},
+			}
+		},
+		"groups sent after sync": func() Sim {
+			d := newMockDiscoverer(newMockGroup("a"))
+			return Sim{
+				Discoverer:       d,
+				Synced:           d.synced,
+				AfterSync:        func(context.Context) { d.updates <- []model.TargetGroup{newMockGroup("a")} },
+				WantTargetGroups: []model.TargetGroup{newMockGroup("a"), newMockGroup("a")},
+			}
+		},
+	}
+
+	for name, createSim := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim := createSim()
+			assert.NotEmpty(t, sim.Run(t))
+		})
+	}
+}
+
+func newMockDiscoverer(tggs ...model.TargetGroup) *mockDiscoverer {
+	return &mockDiscoverer{
+		initial: tggs,
+		started: make(chan struct{}),
+		updates: make(chan []model.TargetGroup),
+	}
+}
+
+type mockDiscoverer struct {
+	initial []model.TargetGroup
+	started chan struct{}
+	updates chan []model.TargetGroup
+}
+
+func (d *mockDiscoverer) synced() bool {
+	select {
+	case <-d.started:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *mockDiscoverer) Discover(ctx context.Context, in chan<- []model.TargetGroup) {
+	select {
+	case <-ctx.Done():
+		return
+	case in <- d.initial:
+	}
+	close(d.started)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tggs := <-d.updates:
+			select {
+			case <-ctx.Done():
+				return
+			case in <- tggs:
+			}
+		}
+	}
+}
+
+func newMockGroup(source string, targets ...string) *mockGroup {
+	g := &mockGroup{source: source}
+	for _, name := range targets {
+		g.targets = append(g.targets, &mockTarget{name: name})
+	}
+	return g
+}
+
+type mockGroup struct {
+	source  string
+	targets []model.Target
+}
+
+func (g *mockGroup) Provider() string        { return "mock" }
+func (g *mockGroup) Source() string          { return g.source }
+func (g *mockGroup) Targets() []model.Target { return g.targets }
+
+type mockTarget struct {
+	model.Base
+	name string
+}
+
+func (t *mockTarget) TUID() string { return t.name }
+func (t *mockTarget) Hash() uint64 { return uint64(len(t.name)) }
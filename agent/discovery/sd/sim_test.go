@@ -11,9 +11,9 @@ import (
 
 	"github.com/netdata/go.d.plugin/agent/confgroup"
 	"github.com/netdata/go.d.plugin/agent/discovery/sd/pipeline"
-	"github.com/netdata/go.d.plugin/logger"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var lock = &sync.Mutex{}
@@ -25,15 +25,12 @@ type discoverySim struct {
 
 func (sim *discoverySim) run(t *testing.T) {
 	fact := &mockFactory{}
-	mgr := &ServiceDiscovery{
-		Logger:    logger.New(),
-		sdFactory: fact,
-		confProv: &mockConfigProvider{
-			configs: sim.configs,
-			ch:      make(chan ConfigFile),
-		},
-		confCache: make(map[string]uint64),
-		pipelines: make(map[string]func()),
+	mgr, err := NewServiceDiscovery(Config{ConfDir: t.TempDir()})
+	require.NoError(t, err)
+	mgr.sdFactory = fact
+	mgr.confProv = &mockConfigProvider{
+		configs: sim.configs,
+		ch:      make(chan ConfigFile),
 	}
 
 	in := make(chan<- []*confgroup.Group)
@@ -103,9 +100,10 @@ func (m *mockFactory) create(cfg pipeline.Config) (sdPipeline, error) {
 }
 
 type mockPipeline struct {
-	name    string
-	started bool
-	stopped bool
+	name     string
+	started  bool
+	stopped  bool
+	reloaded int
 }
 
 func (m *mockPipeline) Run(ctx context.Context, _ chan<- []*confgroup.Group) {
@@ -115,3 +113,23 @@ func (m *mockPipeline) Run(ctx context.Context, _ chan<- []*confgroup.Group) {
 	defer func() { lock.Lock(); m.stopped = true; lock.Unlock() }()
 	<-ctx.Done()
 }
+
+func (m *mockPipeline) Reload(cfg pipeline.Config) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if len(cfg.Classify) > 0 && cfg.Classify[0].Selector == "invalid" {
+		return errors.New("mock sdPipeline.Reload() error")
+	}
+	m.reloaded++
+
+	return nil
+}
+
+func (m *mockPipeline) Stats() pipeline.Stats {
+	return pipeline.Stats{
+		Discoverers: map[string]pipeline.DiscovererStats{
+			"mock": {TargetsSeen: 1, TargetsMatched: 1, ConfigsComposed: 1, ConfigsSent: 1},
+		},
+	}
+}
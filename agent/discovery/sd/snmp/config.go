@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package snmp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+type Config struct {
+	Tags string `yaml:"tags"`
+	// Networks are IP ranges in any pkg/iprange form: '192.0.2.0/24', '192.0.2.1-192.0.2.10'.
+	Networks []string `yaml:"networks"`
+	// Credentials are tried in order, the first one a device responds to is used.
+	Credentials []Credential `yaml:"credentials"`
+	Port        int          `yaml:"port"`
+	// Timeout is the per host, per credential probe timeout.
+	Timeout        web.Duration `yaml:"timeout"`
+	Concurrency    int          `yaml:"concurrency"`
+	RescanInterval web.Duration `yaml:"rescan_interval"`
+	// MaxFailedScans is the number of consecutive scans a device can miss before its target is removed.
+	MaxFailedScans int `yaml:"max_failed_scans"`
+}
+
+type Credential struct {
+	Name      string `yaml:"name"`
+	Version   string `yaml:"version"`
+	Community string `yaml:"community"`
+	User      User   `yaml:"user"`
+}
+
+type User struct {
+	Name          string `yaml:"name"`
+	SecurityLevel string `yaml:"level"`
+	AuthProto     string `yaml:"auth_proto"`
+	AuthKey       string `yaml:"auth_key"`
+	PrivProto     string `yaml:"priv_proto"`
+	PrivKey       string `yaml:"priv_key"`
+}
+
+func validateConfig(cfg Config) error {
+	if cfg.Tags == "" {
+		return errors.New("'tags' not set")
+	}
+	if len(cfg.Networks) == 0 {
+		return errors.New("'networks' not set")
+	}
+	if len(cfg.Credentials) == 0 {
+		return errors.New("'credentials' not set")
+	}
+	for i, cred := range cfg.Credentials {
+		if err := validateCredential(cred); err != nil {
+			return fmt.Errorf("credentials[%d]: %v", i+1, err)
+		}
+	}
+	if cfg.Port < 0 || cfg.Port > 65535 {
+		return fmt.Errorf("invalid 'port' (%d)", cfg.Port)
+	}
+	if cfg.Concurrency < 0 {
+		return errors.New("'concurrency' can not be negative")
+	}
+	if cfg.MaxFailedScans < 0 {
+		return errors.New("'max_failed_scans' can not be negative")
+	}
+	return nil
+}
+
+func validateCredential(cred Credential) error {
+	ver, err := parseSNMPVersion(cred.Version)
+	if err != nil {
+		return err
+	}
+	if ver != gosnmp.Version3 {
+		if cred.Community == "" {
+			return errors.New("'community' not set")
+		}
+		return nil
+	}
+	if cred.User.Name == "" {
+		return errors.New("'user.name' is required when using SNMPv3 but not set")
+	}
+	if _, err := parseSNMPv3SecurityLevel(cred.User.SecurityLevel); err != nil {
+		return err
+	}
+	if _, err := parseSNMPv3AuthProtocol(cred.User.AuthProto); err != nil {
+		return err
+	}
+	if _, err := parseSNMPv3PrivProtocol(cred.User.PrivProto); err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseSNMPVersion(version string) (gosnmp.SnmpVersion, error) {
+	switch version {
+	case "0", "1":
+		return gosnmp.Version1, nil
+	case "2", "2c", "":
+		return gosnmp.Version2c, nil
+	case "3":
+		return gosnmp.Version3, nil
+	default:
+		return gosnmp.Version2c, fmt.Errorf("invalid snmp version value (%s)", version)
+	}
+}
+
+func parseSNMPv3SecurityLevel(level string) (gosnmp.SnmpV3MsgFlags, error) {
+	switch level {
+	case "1", "none", "noAuthNoPriv", "":
+		return gosnmp.NoAuthNoPriv, nil
+	case "2", "authNoPriv":
+		return gosnmp.AuthNoPriv, nil
+	case "3", "authPriv":
+		return gosnmp.AuthPriv, nil
+	default:
+		return gosnmp.NoAuthNoPriv, fmt.Errorf("invalid snmpv3 user security level value (%s)", level)
+	}
+}
+
+func parseSNMPv3AuthProtocol(protocol string) (gosnmp.SnmpV3AuthProtocol, error) {
+	switch protocol {
+	case "1", "none", "noAuth", "":
+		return gosnmp.NoAuth, nil
+	case "2", "md5":
+		return gosnmp.MD5, nil
+	case "3", "sha":
+		return gosnmp.SHA, nil
+	case "4", "sha224":
+		return gosnmp.SHA224, nil
+	case "5", "sha256":
+		return gosnmp.SHA256, nil
+	case "6", "sha384":
+		return gosnmp.SHA384, nil
+	case "7", "sha512":
+		return gosnmp.SHA512, nil
+	default:
+		return gosnmp.NoAuth, fmt.Errorf("invalid snmpv3 user auth protocol value (%s)", protocol)
+	}
+}
+
+func parseSNMPv3PrivProtocol(protocol string) (gosnmp.SnmpV3PrivProtocol, error) {
+	switch protocol {
+	case "1", "none", "noPriv", "":
+		return gosnmp.NoPriv, nil
+	case "2", "des":
+		return gosnmp.DES, nil
+	case "3", "aes":
+		return gosnmp.AES, nil
+	case "4", "aes192":
+		return gosnmp.AES192, nil
+	case "5", "aes256":
+		return gosnmp.AES256, nil
+	case "6", "aes192c":
+		return gosnmp.AES192C, nil
+	case "7", "aes256c":
+		return gosnmp.AES256C, nil
+	default:
+		return gosnmp.NoPriv, fmt.Errorf("invalid snmpv3 user priv protocol value (%s)", protocol)
+	}
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package snmp
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type discoverySim struct {
+	cfg              Config
+	updates          []func()
+	wantTargetGroups [][]model.TargetGroup
+}
+
+func (sim *discoverySim) run(t *testing.T) {
+	d, err := NewDiscoverer(sim.cfg)
+	require.NoError(t, err)
+
+	d.rescanInterval = time.Millisecond * 200
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan []model.TargetGroup)
+	done := make(chan struct{})
+	go func() { defer close(done); d.Discover(ctx, in) }()
+
+	for i, want := range sim.wantTargetGroups {
+		if i > 0 {
+			sim.updates[i-1]()
+		}
+
+		select {
+		case tggs := <-in:
+			assert.Equalf(t, want, tggs, "update %d", i+1)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("update %d: timed out waiting for target groups", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 3):
+		t.Error("discovery hasn't finished after cancel")
+	}
+}
+
+// stubAgent is an SNMP v1/v2c agent that answers GET requests for the configured OIDs.
+type stubAgent struct {
+	conn      net.PacketConn
+	community string
+	values    map[string]gosnmp.SnmpPDU
+
+	mux     sync.Mutex
+	stopped bool
+}
+
+func newStubAgent(t *testing.T, addr, community, sysName string) *stubAgent {
+	conn, err := net.ListenPacket("udp", addr)
+	require.NoError(t, err)
+
+	a := &stubAgent{
+		conn:      conn,
+		community: community,
+		values: map[string]gosnmp.SnmpPDU{
+			oidSysDescr:    {Type: gosnmp.OctetString, Value: []byte("Linux " + sysName + " 6.1.0-amd64")},
+			oidSysObjectID: {Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.8072.3.2.10"},
+			oidSysName:     {Type: gosnmp.OctetString, Value: []byte(sysName)},
+		},
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go a.serve()
+
+	return a
+}
+
+func (a *stubAgent) port() int {
+	return a.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// stop makes the agent drop requests, the socket stays bound.
+func (a *stubAgent) stop() {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.stopped = true
+}
+
+func (a *stubAgent) setSysName(name string) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.values[oidSysName] = gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte(name)}
+}
+
+func (a *stubAgent) isStopped() bool {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	return a.stopped
+}
+
+func (a *stubAgent) lookup(oid string) (gosnmp.SnmpPDU, bool) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	pdu, ok := a.values[oid]
+	return pdu, ok
+}
+
+func (a *stubAgent) serve() {
+	buf := make([]byte, 65535)
+	dec := &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: a.community, Logger: gosnmp.NewLogger(nil)}
+
+	for {
+		n, from, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if a.isStopped() {
+			continue
+		}
+
+		req, err := dec.SnmpDecodePacket(buf[:n])
+		if err != nil || req.PDUType != gosnmp.GetRequest || req.Community != a.community {
+			// real agents silently drop requests with a wrong community
+			continue
+		}
+
+		resp := &gosnmp.SnmpPacket{
+			Version:   req.Version,
+			Community: req.Community,
+			PDUType:   gosnmp.GetResponse,
+			RequestID: req.RequestID,
+			Logger:    req.Logger,
+		}
+		for _, v := range req.Variables {
+			pdu, ok := a.lookup(strings.TrimPrefix(v.Name, "."))
+			if !ok {
+				pdu = gosnmp.SnmpPDU{Type: gosnmp.NoSuchObject}
+			}
+			pdu.Name = v.Name
+			resp.Variables = append(resp.Variables, pdu)
+		}
+
+		bs, err := resp.MarshalMsg()
+		if err != nil {
+			continue
+		}
+		_, _ = a.conn.WriteTo(bs, from)
+	}
+}
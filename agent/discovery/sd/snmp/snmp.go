@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package snmp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/logger"
+	"github.com/netdata/go.d.plugin/pkg/iprange"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/ilyam8/hashstructure"
+)
+
+const (
+	oidSysDescr    = "1.3.6.1.2.1.1.1.0"
+	oidSysObjectID = "1.3.6.1.2.1.1.2.0"
+	oidSysName     = "1.3.6.1.2.1.1.5.0"
+)
+
+// maxNetworkSize limits a single network to a /16, a bigger one is likely a typo.
+const maxNetworkSize = 1 << 16
+
+type targetGroup struct {
+	source  string
+	targets []model.Target
+}
+
+func (g *targetGroup) Provider() string        { return "sd:snmp" }
+func (g *targetGroup) Source() string          { return g.source }
+func (g *targetGroup) Targets() []model.Target { return g.targets }
+
+type Target struct {
+	model.Base `hash:"ignore"`
+
+	hash uint64
+	tuid string
+
+	Address     string
+	Port        int
+	SysObjectID string
+	SysDescr    string
+	SysName     string
+	Credential  Credential
+}
+
+func (t *Target) Hash() uint64 { return t.hash }
+func (t *Target) TUID() string { return t.tuid }
+
+func NewDiscoverer(cfg Config) (*Discoverer, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("config validation: %v", err)
+	}
+
+	tags, err := model.ParseTags(cfg.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("parse tags: %v", err)
+	}
+
+	var networks []iprange.Range
+	for _, v := range cfg.Networks {
+		r, err := iprange.ParseRange(v)
+		if err != nil {
+			return nil, fmt.Errorf("parse network '%s': %v", v, err)
+		}
+		if r.Size().Int64() > maxNetworkSize {
+			return nil, fmt.Errorf("network '%s' is too big (%s addresses, max %d)", v, r.Size(), maxNetworkSize)
+		}
+		networks = append(networks, r)
+	}
+
+	d := &Discoverer{
+		Logger: logger.New().With(
+			slog.String("component", "discovery sd snmp"),
+		),
+		networks:       networks,
+		credentials:    cfg.Credentials,
+		port:           cfg.Port,
+		timeout:        cfg.Timeout.Duration,
+		concurrency:    cfg.Concurrency,
+		rescanInterval: cfg.RescanInterval.Duration,
+		maxFailedScans: cfg.MaxFailedScans,
+		devices:        make(map[string]*Target),
+		failures:       make(map[string]int),
+	}
+	if d.port == 0 {
+		d.port = 161
+	}
+	if d.timeout == 0 {
+		d.timeout = time.Second
+	}
+	if d.concurrency == 0 {
+		d.concurrency = 32
+	}
+	if d.rescanInterval == 0 {
+		d.rescanInterval = time.Minute * 30
+	}
+	if d.maxFailedScans == 0 {
+		d.maxFailedScans = 3
+	}
+	d.Tags().Merge(tags)
+
+	return d, nil
+}
+
+type Discoverer struct {
+	*logger.Logger
+	model.Base
+
+	networks       []iprange.Range
+	credentials    []Credential
+	port           int
+	timeout        time.Duration
+	concurrency    int
+	rescanInterval time.Duration
+	maxFailedScans int
+
+	devices  map[string]*Target // responding devices by address
+	failures map[string]int     // consecutive failed scans by address
+}
+
+func (d *Discoverer) String() string {
+	return "sd:snmp"
+}
+
+func (d *Discoverer) Discover(ctx context.Context, in chan<- []model.TargetGroup) {
+	d.Info("instance is started")
+	defer d.Info("instance is stopped")
+
+	d.scan(ctx, in)
+
+	tk := time.NewTicker(d.rescanInterval)
+	defer tk.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			d.scan(ctx, in)
+		}
+	}
+}
+
+func (d *Discoverer) scan(ctx context.Context, in chan<- []model.TargetGroup) {
+	now := time.Now()
+	found := d.probeNetworks(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	d.Debugf("scan finished in %s, found %d devices", time.Since(now), len(found))
+
+	var tggs []model.TargetGroup
+
+	for addr, tgt := range found {
+		delete(d.failures, addr)
+		if prev, ok := d.devices[addr]; ok && prev.Hash() == tgt.Hash() {
+			continue
+		}
+		d.devices[addr] = tgt
+		tggs = append(tggs, &targetGroup{source: deviceSource(addr), targets: []model.Target{tgt}})
+	}
+
+	for addr := range d.devices {
+		if _, ok := found[addr]; ok {
+			continue
+		}
+		if d.failures[addr]++; d.failures[addr] < d.maxFailedScans {
+			continue
+		}
+		d.Infof("device '%s' hasn't responded for %d scans, removing it", addr, d.failures[addr])
+		delete(d.devices, addr)
+		delete(d.failures, addr)
+		tggs = append(tggs, &targetGroup{source: deviceSource(addr)})
+	}
+
+	if len(tggs) == 0 {
+		return
+	}
+
+	sort.Slice(tggs, func(i, j int) bool { return tggs[i].Source() < tggs[j].Source() })
+
+	select {
+	case <-ctx.Done():
+	case in <- tggs:
+	}
+}
+
+func (d *Discoverer) probeNetworks(ctx context.Context) map[string]*Target {
+	addrs := make(chan string)
+
+	go func() {
+		defer close(addrs)
+		for _, r := range d.networks {
+			ok := forEachIP(r, func(ip net.IP) bool {
+				select {
+				case <-ctx.Done():
+					return false
+				case addrs <- ip.String():
+					return true
+				}
+			})
+			if !ok {
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+	found := make(map[string]*Target)
+
+	for i := 0; i < d.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for addr := range addrs {
+				if tgt := d.probeDevice(ctx, addr); tgt != nil {
+					mux.Lock()
+					found[addr] = tgt
+					mux.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return found
+}
+
+func (d *Discoverer) probeDevice(ctx context.Context, addr string) *Target {
+	for _, cred := range d.credentials {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		pdus, err := d.get(ctx, addr, cred)
+		if err != nil {
+			continue
+		}
+
+		tgt := &Target{
+			tuid:       "snmp_" + strings.NewReplacer(".", "_", ":", "_").Replace(addr),
+			Address:    addr,
+			Port:       d.port,
+			Credential: cred,
+		}
+		for _, pdu := range pdus {
+			switch strings.TrimPrefix(pdu.Name, ".") {
+			case oidSysDescr:
+				tgt.SysDescr = pduString(pdu)
+			case oidSysObjectID:
+				tgt.SysObjectID = strings.TrimPrefix(pduString(pdu), ".")
+			case oidSysName:
+				tgt.SysName = pduString(pdu)
+			}
+		}
+
+		hash, err := calcHash(tgt)
+		if err != nil {
+			return nil
+		}
+		tgt.hash = hash
+		tgt.Tags().Merge(d.Tags())
+
+		return tgt
+	}
+
+	return nil
+}
+
+func (d *Discoverer) get(ctx context.Context, addr string, cred Credential) ([]gosnmp.SnmpPDU, error) {
+	client := newClient(cred)
+	client.Context = ctx
+	client.Target = addr
+	client.Port = uint16(d.port)
+	client.Timeout = d.timeout
+	client.Retries = 0
+
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Conn.Close() }()
+
+	resp, err := client.Get([]string{oidSysDescr, oidSysObjectID, oidSysName})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != gosnmp.NoError {
+		return nil, fmt.Errorf("response error: %s", resp.Error)
+	}
+	for _, pdu := range resp.Variables {
+		if pdu.Type == gosnmp.NoSuchObject || pdu.Type == gosnmp.NoSuchInstance {
+			return nil, fmt.Errorf("no such object '%s'", pdu.Name)
+		}
+	}
+
+	return resp.Variables, nil
+}
+
+func newClient(cred Credential) *gosnmp.GoSNMP {
+	client := &gosnmp.GoSNMP{
+		Transport: "udp",
+		MaxOids:   gosnmp.MaxOids,
+	}
+
+	ver, _ := parseSNMPVersion(cred.Version)
+	client.Version = ver
+
+	if ver != gosnmp.Version3 {
+		client.Community = cred.Community
+		return client
+	}
+
+	level, _ := parseSNMPv3SecurityLevel(cred.User.SecurityLevel)
+	authProto, _ := parseSNMPv3AuthProtocol(cred.User.AuthProto)
+	privProto, _ := parseSNMPv3PrivProtocol(cred.User.PrivProto)
+
+	client.SecurityModel = gosnmp.UserSecurityModel
+	client.MsgFlags = level
+	client.SecurityParameters = &gosnmp.UsmSecurityParameters{
+		UserName:                 cred.User.Name,
+		AuthenticationProtocol:   authProto,
+		AuthenticationPassphrase: cred.User.AuthKey,
+		PrivacyProtocol:          privProto,
+		PrivacyPassphrase:        cred.User.PrivKey,
+	}
+
+	return client
+}
+
+func pduString(pdu gosnmp.SnmpPDU) string {
+	switch v := pdu.Value.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// forEachIP calls fn for every address in the range until it returns false.
+func forEachIP(r iprange.Range, fn func(net.IP) bool) bool {
+	// the range string form is 'start-end'
+	first, last, _ := strings.Cut(r.String(), "-")
+	start, end := net.ParseIP(first), net.ParseIP(last)
+	if r.Family() == iprange.V4Family {
+		start, end = start.To4(), end.To4()
+	}
+	if start == nil || end == nil {
+		return true
+	}
+
+	for ip := start; bytes.Compare(ip, end) <= 0; ip = nextIP(ip) {
+		if !fn(ip) {
+			return false
+		}
+		if ip.Equal(end) {
+			break
+		}
+	}
+	return true
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		if next[i]++; next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func deviceSource(addr string) string {
+	return fmt.Sprintf("sd:snmp(%s)", addr)
+}
+
+func calcHash(obj any) (uint64, error) {
+	return hashstructure.Hash(obj, nil)
+}
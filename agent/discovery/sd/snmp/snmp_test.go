@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package snmp
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/pkg/iprange"
+	"github.com/netdata/go.d.plugin/pkg/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	credPublic  = Credential{Name: "public", Version: "2c", Community: "public"}
+	credPrivate = Credential{Name: "private", Version: "2c", Community: "private"}
+)
+
+func TestNewDiscoverer(t *testing.T) {
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"valid config": {
+			cfg: Config{Tags: "snmp", Networks: []string{"192.0.2.0/24"}, Credentials: []Credential{credPublic}},
+		},
+		"valid v3 credential": {
+			cfg: Config{Tags: "snmp", Networks: []string{"192.0.2.0/24"}, Credentials: []Credential{
+				{Version: "3", User: User{Name: "netdata", SecurityLevel: "authPriv", AuthProto: "sha", PrivProto: "aes"}},
+			}},
+		},
+		"tags not set": {
+			cfg:     Config{Networks: []string{"192.0.2.0/24"}, Credentials: []Credential{credPublic}},
+			wantErr: true,
+		},
+		"networks not set": {
+			cfg:     Config{Tags: "snmp", Credentials: []Credential{credPublic}},
+			wantErr: true,
+		},
+		"invalid network": {
+			cfg:     Config{Tags: "snmp", Networks: []string{"192.0.2.0/33"}, Credentials: []Credential{credPublic}},
+			wantErr: true,
+		},
+		"network is too big": {
+			cfg:     Config{Tags: "snmp", Networks: []string{"10.0.0.0/8"}, Credentials: []Credential{credPublic}},
+			wantErr: true,
+		},
+		"credentials not set": {
+			cfg:     Config{Tags: "snmp", Networks: []string{"192.0.2.0/24"}},
+			wantErr: true,
+		},
+		"community not set": {
+			cfg:     Config{Tags: "snmp", Networks: []string{"192.0.2.0/24"}, Credentials: []Credential{{Version: "2c"}}},
+			wantErr: true,
+		},
+		"invalid version": {
+			cfg:     Config{Tags: "snmp", Networks: []string{"192.0.2.0/24"}, Credentials: []Credential{{Version: "4", Community: "public"}}},
+			wantErr: true,
+		},
+		"v3 user not set": {
+			cfg:     Config{Tags: "snmp", Networks: []string{"192.0.2.0/24"}, Credentials: []Credential{{Version: "3"}}},
+			wantErr: true,
+		},
+		"v3 invalid auth protocol": {
+			cfg: Config{Tags: "snmp", Networks: []string{"192.0.2.0/24"}, Credentials: []Credential{
+				{Version: "3", User: User{Name: "netdata", AuthProto: "sha1024"}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewDiscoverer(test.cfg)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, d)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, d)
+			}
+		})
+	}
+}
+
+func TestDiscoverer_Discover(t *testing.T) {
+	tests := map[string]func(t *testing.T) discoverySim{
+		"devices respond to different credentials": func(t *testing.T) discoverySim {
+			router := newStubAgent(t, "127.0.0.1:0", "private", "router")
+			_ = newStubAgent(t, fmt.Sprintf("127.0.0.2:%d", router.port()), "public", "switch")
+
+			return discoverySim{
+				cfg: prepareConfig(router.port(), "127.0.0.1-127.0.0.3"),
+				wantTargetGroups: [][]model.TargetGroup{{
+					prepareTargetGroup("127.0.0.1", router.port(), "router", "Linux router 6.1.0-amd64", credPrivate),
+					prepareTargetGroup("127.0.0.2", router.port(), "switch", "Linux switch 6.1.0-amd64", credPublic),
+				}},
+			}
+		},
+		"changed device is sent again": func(t *testing.T) discoverySim {
+			router := newStubAgent(t, "127.0.0.1:0", "private", "router")
+
+			return discoverySim{
+				cfg:     prepareConfig(router.port(), "127.0.0.1"),
+				updates: []func(){func() { router.setSysName("core-router") }},
+				wantTargetGroups: [][]model.TargetGroup{
+					{prepareTargetGroup("127.0.0.1", router.port(), "router", "Linux router 6.1.0-amd64", credPrivate)},
+					{prepareTargetGroup("127.0.0.1", router.port(), "core-router", "Linux router 6.1.0-amd64", credPrivate)},
+				},
+			}
+		},
+		"device removed after max failed scans": func(t *testing.T) discoverySim {
+			router := newStubAgent(t, "127.0.0.1:0", "private", "router")
+			sw := newStubAgent(t, fmt.Sprintf("127.0.0.2:%d", router.port()), "public", "switch")
+
+			return discoverySim{
+				cfg:     prepareConfig(router.port(), "127.0.0.1-127.0.0.2"),
+				updates: []func(){sw.stop},
+				wantTargetGroups: [][]model.TargetGroup{
+					{
+						prepareTargetGroup("127.0.0.1", router.port(), "router", "Linux router 6.1.0-amd64", credPrivate),
+						prepareTargetGroup("127.0.0.2", router.port(), "switch", "Linux switch 6.1.0-amd64", credPublic),
+					},
+					{
+						&targetGroup{source: deviceSource("127.0.0.2")},
+					},
+				},
+			}
+		},
+	}
+
+	for name, prepareSim := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim := prepareSim(t)
+			sim.run(t)
+		})
+	}
+}
+
+func Test_forEachIP(t *testing.T) {
+	tests := map[string]struct {
+		rng     string
+		limit   int
+		wantIPs []string
+	}{
+		"v4 range": {
+			rng:     "192.0.2.254-192.0.3.1",
+			wantIPs: []string{"192.0.2.254", "192.0.2.255", "192.0.3.0", "192.0.3.1"},
+		},
+		"v4 cidr": {
+			rng:     "192.0.2.0/30",
+			wantIPs: []string{"192.0.2.1", "192.0.2.2"},
+		},
+		"v4 single address": {
+			rng:     "192.0.2.1",
+			wantIPs: []string{"192.0.2.1"},
+		},
+		"v4 last address": {
+			rng:     "255.255.255.254-255.255.255.255",
+			wantIPs: []string{"255.255.255.254", "255.255.255.255"},
+		},
+		"v6 range": {
+			rng:     "2001:db8::fe-2001:db8::101",
+			wantIPs: []string{"2001:db8::fe", "2001:db8::ff", "2001:db8::100", "2001:db8::101"},
+		},
+		"stop early": {
+			rng:     "192.0.2.0/24",
+			limit:   2,
+			wantIPs: []string{"192.0.2.1", "192.0.2.2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := iprange.ParseRange(test.rng)
+			require.NoError(t, err)
+
+			var ips []string
+			forEachIP(r, func(ip net.IP) bool {
+				ips = append(ips, ip.String())
+				return test.limit == 0 || len(ips) < test.limit
+			})
+
+			assert.Equal(t, test.wantIPs, ips)
+		})
+	}
+}
+
+func prepareConfig(port int, networks ...string) Config {
+	return Config{
+		Tags:           "snmp",
+		Networks:       networks,
+		Credentials:    []Credential{credPublic, credPrivate},
+		Port:           port,
+		Timeout:        web.Duration{Duration: time.Millisecond * 200},
+		MaxFailedScans: 2,
+	}
+}
+
+func prepareTargetGroup(addr string, port int, sysName, sysDescr string, cred Credential) *targetGroup {
+	tgt := &Target{
+		tuid:        "snmp_" + strings.ReplaceAll(addr, ".", "_"),
+		Address:     addr,
+		Port:        port,
+		SysObjectID: "1.3.6.1.4.1.8072.3.2.10",
+		SysDescr:    sysDescr,
+		SysName:     sysName,
+		Credential:  cred,
+	}
+	tgt.hash, _ = calcHash(tgt)
+	tgt.Tags().Merge(model.Tags{"snmp": {}})
+
+	return &targetGroup{source: deviceSource(addr), targets: []model.Target{tgt}}
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package systemd
+
+import (
+	"errors"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+)
+
+type Config struct {
+	Tags string `yaml:"tags"`
+	// Include are unit name glob patterns: 'postgresql*.service'.
+	Include      []string     `yaml:"include"`
+	Timeout      web.Duration `yaml:"timeout"`
+	RefreshEvery web.Duration `yaml:"refresh_every"`
+}
+
+func validateConfig(cfg Config) error {
+	if cfg.Tags == "" {
+		return errors.New("'tags' not set")
+	}
+	if len(cfg.Include) == 0 {
+		return errors.New("'include' not set")
+	}
+	if cfg.Timeout.Duration < 0 {
+		return errors.New("'timeout' can not be negative")
+	}
+	if cfg.RefreshEvery.Duration < 0 {
+		return errors.New("'refresh_every' can not be negative")
+	}
+	return nil
+}
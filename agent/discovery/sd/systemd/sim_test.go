@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build linux
+// +build linux
+
+package systemd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type discoverySim struct {
+	cfg              Config
+	conn             *mockConn
+	updates          []func(c *mockConn)
+	wantTargetGroups [][]model.TargetGroup
+	wantReconnect    bool
+}
+
+func (sim *discoverySim) run(t *testing.T) {
+	d, err := NewDiscoverer(sim.cfg)
+	require.NoError(t, err)
+
+	client := &mockClient{conn: sim.conn}
+	d.client = client
+	d.interval = time.Millisecond * 100
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan []model.TargetGroup)
+	done := make(chan struct{})
+	go func() { defer close(done); d.Discover(ctx, in) }()
+
+	for i, want := range sim.wantTargetGroups {
+		if i > 0 {
+			sim.updates[i-1](sim.conn)
+		}
+
+		select {
+		case tggs := <-in:
+			assert.Equalf(t, want, tggs, "update %d", i+1)
+		case <-time.After(time.Second * 3):
+			t.Fatalf("update %d: timed out waiting for target groups", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+		assert.True(t, sim.conn.closed, "connection is not closed")
+		if sim.wantReconnect {
+			assert.Greater(t, client.connects, 1, "connection is not re-established")
+		}
+	case <-time.After(time.Second * 3):
+		t.Error("discovery hasn't finished after cancel")
+	}
+}
+
+type mockClient struct {
+	conn     *mockConn
+	connects int
+}
+
+func (m *mockClient) connect() (systemdConnection, error) {
+	m.connects++
+	m.conn.setClosed(false)
+	return m.conn, nil
+}
+
+type mockConn struct {
+	mux         sync.Mutex
+	units       []dbus.UnitStatus
+	mainPID     map[string]uint32
+	triggeredBy map[string][]string
+	listen      map[string][][]any
+	errOnList   bool
+	closed      bool
+}
+
+func (m *mockConn) update(fn func()) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	fn()
+}
+
+func (m *mockConn) setClosed(v bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.closed = v
+}
+
+func (m *mockConn) setUnitState(name, active, sub string) {
+	m.update(func() {
+		for i, u := range m.units {
+			if u.Name == name {
+				m.units[i].ActiveState, m.units[i].SubState = active, sub
+			}
+		}
+	})
+}
+
+func (m *mockConn) Close() {
+	m.setClosed(true)
+}
+
+func (m *mockConn) ListUnitsByPatternsContext(_ context.Context, _ []string, patterns []string) ([]dbus.UnitStatus, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.errOnList {
+		return nil, errors.New("mock ListUnitsByPatternsContext() error")
+	}
+
+	var units []dbus.UnitStatus
+	for _, u := range m.units {
+		for _, p := range patterns {
+			if ok, _ := filepath.Match(p, u.Name); ok {
+				units = append(units, u)
+				break
+			}
+		}
+	}
+	return units, nil
+}
+
+func (m *mockConn) GetUnitPropertyContext(_ context.Context, unit string, name string) (*dbus.Property, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if name != "TriggeredBy" {
+		return nil, errors.New("mock GetUnitPropertyContext() unexpected property " + name)
+	}
+	return &dbus.Property{Name: name, Value: godbus.MakeVariant(append([]string{}, m.triggeredBy[unit]...))}, nil
+}
+
+func (m *mockConn) GetUnitTypePropertyContext(_ context.Context, unit string, unitType string, name string) (*dbus.Property, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	switch {
+	case unitType == "Service" && name == "MainPID":
+		return &dbus.Property{Name: name, Value: godbus.MakeVariant(m.mainPID[unit])}, nil
+	case unitType == "Socket" && name == "Listen":
+		return &dbus.Property{Name: name, Value: godbus.MakeVariant(m.listen[unit])}, nil
+	default:
+		return nil, errors.New("mock GetUnitTypePropertyContext() unexpected property " + unitType + "." + name)
+	}
+}
@@ -0,0 +1,298 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build linux
+// +build linux
+
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+	"github.com/netdata/go.d.plugin/logger"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	"github.com/ilyam8/hashstructure"
+)
+
+type targetGroup struct {
+	source  string
+	targets []model.Target
+}
+
+func (g *targetGroup) Provider() string        { return "sd:systemd" }
+func (g *targetGroup) Source() string          { return g.source }
+func (g *targetGroup) Targets() []model.Target { return g.targets }
+
+type Target struct {
+	model.Base `hash:"ignore"`
+
+	hash uint64
+	tuid string
+
+	UnitName string
+	// states change on reload (active/reloading), they are not a part of the hash to not restart jobs
+	ActiveState string `hash:"ignore"`
+	SubState    string `hash:"ignore"`
+	MainPID     int
+	Listen      []Listen
+}
+
+// Listen is a listening address of a socket unit that activates the service: {Type: Stream, Address: 127.0.0.1:5432}.
+type Listen struct {
+	Type    string
+	Address string
+}
+
+func (t *Target) Hash() uint64 { return t.hash }
+func (t *Target) TUID() string { return t.tuid }
+
+type (
+	systemdClient interface {
+		connect() (systemdConnection, error)
+	}
+	systemdConnection interface {
+		Close()
+		ListUnitsByPatternsContext(ctx context.Context, states []string, patterns []string) ([]dbus.UnitStatus, error)
+		GetUnitPropertyContext(ctx context.Context, unit string, propertyName string) (*dbus.Property, error)
+		GetUnitTypePropertyContext(ctx context.Context, unit string, unitType string, propertyName string) (*dbus.Property, error)
+	}
+)
+
+type systemdDBusClient struct{}
+
+func (systemdDBusClient) connect() (systemdConnection, error) {
+	return dbus.NewWithContext(context.Background())
+}
+
+func NewDiscoverer(cfg Config) (*Discoverer, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("config validation: %v", err)
+	}
+
+	tags, err := model.ParseTags(cfg.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("parse tags: %v", err)
+	}
+
+	d := &Discoverer{
+		Logger: logger.New().With(
+			slog.String("component", "discovery sd systemd"),
+		),
+		client:   systemdDBusClient{},
+		include:  cfg.Include,
+		timeout:  cfg.Timeout.Duration,
+		interval: cfg.RefreshEvery.Duration,
+		sent:     make(map[string]uint64),
+	}
+	if d.timeout == 0 {
+		d.timeout = time.Second * 2
+	}
+	if d.interval == 0 {
+		d.interval = time.Second * 30
+	}
+	d.Tags().Merge(tags)
+
+	return d, nil
+}
+
+type Discoverer struct {
+	*logger.Logger
+	model.Base
+
+	client systemdClient
+	conn   systemdConnection
+
+	include  []string
+	timeout  time.Duration
+	interval time.Duration
+
+	sent map[string]uint64 // unit name => target hash
+}
+
+func (d *Discoverer) String() string {
+	return "sd:systemd"
+}
+
+func (d *Discoverer) Discover(ctx context.Context, in chan<- []model.TargetGroup) {
+	d.Info("instance is started")
+	defer d.Info("instance is stopped")
+	defer d.closeConnection()
+
+	d.refresh(ctx, in)
+
+	tk := time.NewTicker(d.interval)
+	defer tk.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			d.refresh(ctx, in)
+		}
+	}
+}
+
+func (d *Discoverer) refresh(ctx context.Context, in chan<- []model.TargetGroup) {
+	targets, err := d.discoverUnits(ctx)
+	if err != nil {
+		// systemd may be restarting (daemon-reexec), keep the current targets and reconnect on the next refresh
+		d.Warning(err)
+		d.closeConnection()
+		return
+	}
+
+	var tggs []model.TargetGroup
+
+	for name, tgt := range targets {
+		if hash, ok := d.sent[name]; ok && hash == tgt.Hash() {
+			continue
+		}
+		d.sent[name] = tgt.Hash()
+		tggs = append(tggs, &targetGroup{source: unitSource(name), targets: []model.Target{tgt}})
+	}
+	for name := range d.sent {
+		if _, ok := targets[name]; !ok {
+			delete(d.sent, name)
+			tggs = append(tggs, &targetGroup{source: unitSource(name)})
+		}
+	}
+
+	if len(tggs) == 0 {
+		return
+	}
+
+	sort.Slice(tggs, func(i, j int) bool { return tggs[i].Source() < tggs[j].Source() })
+
+	select {
+	case <-ctx.Done():
+	case in <- tggs:
+	}
+}
+
+// discoverUnits returns running units (active or reloading) matching the include patterns.
+func (d *Discoverer) discoverUnits(ctx context.Context) (map[string]*Target, error) {
+	if d.conn == nil {
+		conn, err := d.client.connect()
+		if err != nil {
+			return nil, fmt.Errorf("connect to systemd: %v", err)
+		}
+		d.conn = conn
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	units, err := d.conn.ListUnitsByPatternsContext(listCtx, nil, d.include)
+	if err != nil {
+		return nil, fmt.Errorf("list units: %v", err)
+	}
+
+	targets := make(map[string]*Target)
+
+	for _, unit := range units {
+		if unit.ActiveState != "active" && unit.ActiveState != "reloading" {
+			continue
+		}
+
+		tgt := &Target{
+			tuid:        strings.ReplaceAll(unit.Name, "@", "_"),
+			UnitName:    unit.Name,
+			ActiveState: unit.ActiveState,
+			SubState:    unit.SubState,
+		}
+
+		if strings.HasSuffix(unit.Name, ".service") {
+			if tgt.MainPID, err = d.mainPID(ctx, unit.Name); err != nil {
+				return nil, err
+			}
+			if tgt.Listen, err = d.listen(ctx, unit.Name); err != nil {
+				return nil, err
+			}
+		}
+
+		hash, err := calcHash(tgt)
+		if err != nil {
+			continue
+		}
+		tgt.hash = hash
+		tgt.Tags().Merge(d.Tags())
+
+		targets[unit.Name] = tgt
+	}
+
+	return targets, nil
+}
+
+func (d *Discoverer) mainPID(ctx context.Context, unit string) (int, error) {
+	propCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	prop, err := d.conn.GetUnitTypePropertyContext(propCtx, unit, "Service", "MainPID")
+	if err != nil {
+		return 0, fmt.Errorf("get '%s' MainPID: %v", unit, err)
+	}
+
+	pid, _ := prop.Value.Value().(uint32)
+	return int(pid), nil
+}
+
+// listen returns listening addresses of the socket units that activate the service.
+func (d *Discoverer) listen(ctx context.Context, unit string) ([]Listen, error) {
+	propCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	prop, err := d.conn.GetUnitPropertyContext(propCtx, unit, "TriggeredBy")
+	if err != nil {
+		return nil, fmt.Errorf("get '%s' TriggeredBy: %v", unit, err)
+	}
+
+	triggers, _ := prop.Value.Value().([]string)
+	sort.Strings(triggers)
+
+	var listen []Listen
+	for _, name := range triggers {
+		if !strings.HasSuffix(name, ".socket") {
+			continue
+		}
+
+		prop, err := d.conn.GetUnitTypePropertyContext(propCtx, name, "Socket", "Listen")
+		if err != nil {
+			return nil, fmt.Errorf("get '%s' Listen: %v", name, err)
+		}
+
+		// a(ss): [[type, address], ...]
+		pairs, _ := prop.Value.Value().([][]any)
+		for _, pair := range pairs {
+			if len(pair) != 2 {
+				continue
+			}
+			typ, _ := pair[0].(string)
+			addr, _ := pair[1].(string)
+			listen = append(listen, Listen{Type: typ, Address: addr})
+		}
+	}
+
+	return listen, nil
+}
+
+func (d *Discoverer) closeConnection() {
+	if d.conn != nil {
+		d.conn.Close()
+		d.conn = nil
+	}
+}
+
+func unitSource(name string) string {
+	return fmt.Sprintf("sd:systemd(%s)", name)
+}
+
+func calcHash(obj any) (uint64, error) {
+	return hashstructure.Hash(obj, nil)
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build !linux
+// +build !linux
+
+package systemd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+)
+
+type Discoverer struct {
+	model.Base
+}
+
+func NewDiscoverer(Config) (*Discoverer, error) {
+	return nil, errors.New("systemd units discovery is supported only on Linux")
+}
+
+func (d *Discoverer) String() string                                       { return "sd:systemd" }
+func (d *Discoverer) Discover(context.Context, chan<- []model.TargetGroup) {}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build linux
+// +build linux
+
+package systemd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/model"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDiscoverer(t *testing.T) {
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"valid config": {
+			cfg: Config{Tags: "systemd", Include: []string{"postgresql*.service"}},
+		},
+		"tags not set": {
+			cfg:     Config{Include: []string{"postgresql*.service"}},
+			wantErr: true,
+		},
+		"include not set": {
+			cfg:     Config{Tags: "systemd"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewDiscoverer(test.cfg)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, d)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, d)
+			}
+		})
+	}
+}
+
+func TestDiscoverer_Discover(t *testing.T) {
+	cfg := Config{Tags: "systemd", Include: []string{"postgresql*.service", "redis*.service", "cups.*"}}
+
+	tests := map[string]discoverySim{
+		"running units matching patterns": {
+			cfg:  cfg,
+			conn: prepareMockConn(),
+			wantTargetGroups: [][]model.TargetGroup{{
+				prepareTargetGroup("cups.service", "active", "running", 812,
+					Listen{Type: "Stream", Address: "/run/cups/cups.sock"},
+					Listen{Type: "Stream", Address: "0.0.0.0:631"},
+				),
+				prepareTargetGroup("cups.socket", "active", "running", 0),
+				prepareTargetGroup("postgresql.service", "active", "exited", 0),
+				prepareTargetGroup("postgresql@14-main.service", "active", "running", 1024),
+			}},
+		},
+		"stopped unit is removed": {
+			cfg:  cfg,
+			conn: prepareMockConn(),
+			updates: []func(c *mockConn){
+				func(c *mockConn) { c.setUnitState("postgresql@14-main.service", "deactivating", "stop-sigterm") },
+				func(c *mockConn) { c.setUnitState("postgresql@14-main.service", "active", "running") },
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{
+					prepareTargetGroup("cups.service", "active", "running", 812,
+						Listen{Type: "Stream", Address: "/run/cups/cups.sock"},
+						Listen{Type: "Stream", Address: "0.0.0.0:631"},
+					),
+					prepareTargetGroup("cups.socket", "active", "running", 0),
+					prepareTargetGroup("postgresql.service", "active", "exited", 0),
+					prepareTargetGroup("postgresql@14-main.service", "active", "running", 1024),
+				},
+				{
+					&targetGroup{source: unitSource("postgresql@14-main.service")},
+				},
+				{
+					prepareTargetGroup("postgresql@14-main.service", "active", "running", 1024),
+				},
+			},
+		},
+		"reload does not change targets": {
+			cfg:  Config{Tags: "systemd", Include: []string{"postgresql@*.service"}},
+			conn: prepareMockConn(),
+			updates: []func(c *mockConn){
+				func(c *mockConn) {
+					c.setUnitState("postgresql@14-main.service", "reloading", "reload")
+					time.Sleep(time.Millisecond * 300)
+					c.setUnitState("postgresql@14-main.service", "active", "running")
+					time.Sleep(time.Millisecond * 300)
+					// restart
+					c.update(func() { c.mainPID["postgresql@14-main.service"] = 2048 })
+				},
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{prepareTargetGroup("postgresql@14-main.service", "active", "running", 1024)},
+				{prepareTargetGroup("postgresql@14-main.service", "active", "running", 2048)},
+			},
+		},
+		"list error keeps targets and reconnects": {
+			cfg:  Config{Tags: "systemd", Include: []string{"postgresql@*.service"}},
+			conn: prepareMockConn(),
+			updates: []func(c *mockConn){
+				func(c *mockConn) {
+					c.update(func() { c.errOnList = true })
+					time.Sleep(time.Millisecond * 250)
+					c.update(func() { c.errOnList = false; c.mainPID["postgresql@14-main.service"] = 2048 })
+				},
+			},
+			wantTargetGroups: [][]model.TargetGroup{
+				{prepareTargetGroup("postgresql@14-main.service", "active", "running", 1024)},
+				{prepareTargetGroup("postgresql@14-main.service", "active", "running", 2048)},
+			},
+			wantReconnect: true,
+		},
+	}
+
+	for name, sim := range tests {
+		t.Run(name, func(t *testing.T) {
+			sim.run(t)
+		})
+	}
+}
+
+func prepareMockConn() *mockConn {
+	return &mockConn{
+		units: []dbus.UnitStatus{
+			{Name: "cups.path", LoadState: "loaded", ActiveState: "inactive", SubState: "dead"},
+			{Name: "cups.service", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+			{Name: "cups.socket", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+			{Name: "nginx.service", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+			{Name: "postgresql.service", LoadState: "loaded", ActiveState: "active", SubState: "exited"},
+			{Name: "postgresql@14-main.service", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+			{Name: "redis-server.service", LoadState: "loaded", ActiveState: "failed", SubState: "failed"},
+		},
+		mainPID: map[string]uint32{
+			"cups.service":               812,
+			"nginx.service":              900,
+			"postgresql@14-main.service": 1024,
+		},
+		triggeredBy: map[string][]string{
+			"cups.service": {"cups.socket", "cups.path"},
+		},
+		listen: map[string][][]any{
+			"cups.socket": {{"Stream", "/run/cups/cups.sock"}, {"Stream", "0.0.0.0:631"}},
+		},
+	}
+}
+
+func prepareTargetGroup(unit, active, sub string, pid int, listen ...Listen) *targetGroup {
+	tgt := &Target{
+		tuid:        unit,
+		UnitName:    unit,
+		ActiveState: active,
+		SubState:    sub,
+		MainPID:     pid,
+		Listen:      listen,
+	}
+	if tgt.tuid == "postgresql@14-main.service" {
+		tgt.tuid = "postgresql_14-main.service"
+	}
+	tgt.hash, _ = calcHash(tgt)
+	tgt.Tags().Merge(model.Tags{"systemd": {}})
+
+	return &targetGroup{source: unitSource(unit), targets: []model.Target{tgt}}
+}
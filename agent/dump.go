@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/discovery"
+	"github.com/netdata/go.d.plugin/agent/jobmgr"
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/logger"
+)
+
+// dumpConfigTimeout is the time to wait for the job config when the module configs are also watched.
+var dumpConfigTimeout = time.Second * 10
+
+type (
+	dumpResult struct {
+		Module  string           `json:"module"`
+		Job     string           `json:"job"`
+		Timings dumpTimings      `json:"timings"`
+		Metrics map[string]int64 `json:"metrics"`
+		Charts  []dumpChart      `json:"charts,omitempty"`
+	}
+	dumpTimings struct {
+		InitMs    float64 `json:"init_ms"`
+		CheckMs   float64 `json:"check_ms"`
+		CollectMs float64 `json:"collect_ms"`
+	}
+	dumpChart struct {
+		ID       string    `json:"id"`
+		Title    string    `json:"title"`
+		Units    string    `json:"units"`
+		Family   string    `json:"family"`
+		Context  string    `json:"context"`
+		Type     string    `json:"type"`
+		Priority int       `json:"priority"`
+		Dims     []dumpDim `json:"dimensions"`
+	}
+	dumpDim struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		Algorithm string `json:"algorithm"`
+		Mul       int    `json:"multiplier"`
+		Div       int    `json:"divisor"`
+		Hidden    bool   `json:"hidden,omitempty"`
+	}
+)
+
+// Dump runs the RunJob job of the RunModule module once and writes the collected metrics as JSON to Out.
+// The chart protocol is not used. It returns the process exit code.
+func (a *Agent) Dump() int {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := a.dump(ctx); err != nil {
+		a.Errorf("dump: %v", err)
+		return 1
+	}
+	return 0
+}
+
+func (a *Agent) dump(ctx context.Context) error {
+	if a.RunModule == "" || a.RunModule == "all" {
+		return errors.New("the module to run is not set")
+	}
+
+	jobName := a.RunJob
+	if jobName == "" {
+		jobName = a.RunModule
+	}
+
+	pluginCfg := a.loadPluginConfig()
+	enabled := a.loadEnabledModules(pluginCfg)
+	creator, ok := enabled[a.RunModule]
+	if !ok {
+		return fmt.Errorf("can not find %s module", a.RunModule)
+	}
+
+	cfg, err := a.findJobConfig(ctx, a.buildDiscoveryConf(enabled), jobName)
+	if err != nil {
+		return err
+	}
+
+	mod, err := jobmgr.NewModule(creator, cfg, pluginCfg.StrictConfig)
+	if err != nil {
+		return err
+	}
+	mod.GetBase().Logger = logger.New().With(
+		slog.String("collector", a.RunModule),
+		slog.String("job", jobName),
+	)
+	defer mod.Cleanup()
+
+	res := dumpResult{Module: a.RunModule, Job: jobName}
+
+	now := time.Now()
+	ok = mod.Init()
+	res.Timings.InitMs = sinceMs(now)
+	if !ok {
+		return errors.New("init failed")
+	}
+
+	now = time.Now()
+	ok = mod.Check()
+	res.Timings.CheckMs = sinceMs(now)
+	if !ok {
+		return errors.New("check failed")
+	}
+
+	charts := mod.Charts()
+	if charts == nil {
+		return errors.New("nil charts")
+	}
+
+	now = time.Now()
+	res.Metrics = mod.Collect()
+	res.Timings.CollectMs = sinceMs(now)
+	if len(res.Metrics) == 0 {
+		return errors.New("collect failed: no metrics collected")
+	}
+
+	if a.DumpCharts {
+		res.Charts = newDumpCharts(*charts)
+	}
+
+	enc := json.NewEncoder(a.Out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(res)
+}
+
+// findJobConfig runs the discovery until the job config is found.
+// Static configs are sent at once, the discovery is waited for only if the module configs are watched.
+func (a *Agent) findJobConfig(ctx context.Context, discCfg discovery.Config, jobName string) (confgroup.Config, error) {
+	discoveryManager, err := discovery.NewManager(discCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dumpConfigTimeout)
+	defer cancel()
+
+	in := make(chan []*confgroup.Group)
+	done := make(chan struct{})
+	go func() { defer close(done); discoveryManager.Run(ctx, in) }()
+	defer func() { cancel(); <-done }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("can not find %s[%s] job config", a.RunModule, jobName)
+		case groups := <-in:
+			for _, group := range groups {
+				for _, cfg := range group.Configs {
+					if cfg.Module() == a.RunModule && cfg.Name() == jobName {
+						return cfg, nil
+					}
+				}
+			}
+			if len(a.ModulesSDConfPath) == 0 {
+				return nil, fmt.Errorf("can not find %s[%s] job config", a.RunModule, jobName)
+			}
+		}
+	}
+}
+
+func newDumpCharts(charts module.Charts) []dumpChart {
+	var res []dumpChart
+	for _, chart := range charts {
+		if chart.Obsolete {
+			continue
+		}
+		c := dumpChart{
+			ID:       chart.ID,
+			Title:    chart.Title,
+			Units:    chart.Units,
+			Family:   chart.Fam,
+			Context:  chart.Ctx,
+			Type:     chart.Type.String(),
+			Priority: chart.Priority,
+		}
+		for _, dim := range chart.Dims {
+			c.Dims = append(c.Dims, dumpDim{
+				ID:        dim.ID,
+				Name:      dim.Name,
+				Algorithm: dim.Algo.String(),
+				Mul:       dim.Mul,
+				Div:       dim.Div,
+				Hidden:    dim.Hidden,
+			})
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+func sinceMs(t time.Time) float64 {
+	return float64(time.Since(t).Microseconds()) / 1000
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/modules/example"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgent_dump(t *testing.T) {
+	exampleCreator := module.Creator{Create: func() module.Module { return example.New() }}
+
+	tests := map[string]struct {
+		runModule   string
+		runJob      string
+		dumpCharts  bool
+		moduleConf  string
+		creator     module.Creator
+		wantFail    bool
+		wantMetrics int
+		wantCharts  int
+	}{
+		"default config": {
+			runModule:   "example",
+			creator:     exampleCreator,
+			wantMetrics: 4,
+		},
+		"default config with charts": {
+			runModule:   "example",
+			dumpCharts:  true,
+			creator:     exampleCreator,
+			wantMetrics: 4,
+			wantCharts:  1,
+		},
+		"job from the module config": {
+			runModule:   "example",
+			runJob:      "myjob",
+			dumpCharts:  true,
+			moduleConf:  "jobs:\n  - name: myjob\n    charts:\n      num: 2\n",
+			creator:     exampleCreator,
+			wantMetrics: 8,
+			wantCharts:  2,
+		},
+		"job not found": {
+			runModule: "example",
+			runJob:    "myjob",
+			creator:   exampleCreator,
+			wantFail:  true,
+		},
+		"module not set": {
+			runModule: "all",
+			creator:   exampleCreator,
+			wantFail:  true,
+		},
+		"check fails": {
+			runModule: "example",
+			creator: module.Creator{Create: func() module.Module {
+				return &module.MockModule{CheckFunc: func() bool { return false }}
+			}},
+			wantFail: true,
+		},
+		"collect fails": {
+			runModule: "example",
+			creator: module.Creator{Create: func() module.Module {
+				return &module.MockModule{
+					ChartsFunc:  func() *module.Charts { return &module.Charts{} },
+					CollectFunc: func() map[string]int64 { return nil },
+				}
+			}},
+			wantFail: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			a := New(Config{
+				RunModule:  test.runModule,
+				RunJob:     test.runJob,
+				DumpCharts: test.dumpCharts,
+			})
+			a.Out = &buf
+			a.ModuleRegistry = module.Registry{"example": test.creator}
+
+			if test.moduleConf != "" {
+				dir := t.TempDir()
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "example.conf"), []byte(test.moduleConf), 0644))
+				a.ModulesConfDir = []string{dir}
+			}
+
+			err := a.dump(context.Background())
+
+			if test.wantFail {
+				assert.Error(t, err)
+				assert.Zero(t, buf.Len())
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res dumpResult
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &res))
+
+			wantJob := test.runJob
+			if wantJob == "" {
+				wantJob = test.runModule
+			}
+			assert.Equal(t, test.runModule, res.Module)
+			assert.Equal(t, wantJob, res.Job)
+			assert.Len(t, res.Metrics, test.wantMetrics)
+			assert.Len(t, res.Charts, test.wantCharts)
+			for _, chart := range res.Charts {
+				assert.NotEmpty(t, chart.Dims)
+			}
+		})
+	}
+}
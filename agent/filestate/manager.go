@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package filestate
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/logger"
+)
+
+func NewManager(path string) *Manager {
+	return &Manager{
+		Logger: logger.New().With(
+			slog.String("component", "filestate manager"),
+		),
+		path:       path,
+		store:      &Store{},
+		flushEvery: time.Second * 5,
+		flushCh:    make(chan struct{}, 1),
+	}
+}
+
+type Manager struct {
+	*logger.Logger
+
+	path string
+
+	store *Store
+
+	flushEvery time.Duration
+	flushCh    chan struct{}
+}
+
+func (m *Manager) Run(ctx context.Context) {
+	m.Info("instance is started")
+	defer func() { m.Info("instance is stopped") }()
+
+	tk := time.NewTicker(m.flushEvery)
+	defer tk.Stop()
+	defer m.flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			m.tryFlush()
+		}
+	}
+}
+
+func (m *Manager) Save(cfg confgroup.Config, state module.JobState) {
+	m.store.add(cfg, state, time.Now())
+	m.triggerFlush()
+}
+
+func (m *Manager) Remove(cfg confgroup.Config) {
+	if m.store.remove(cfg) {
+		m.triggerFlush()
+	}
+}
+
+func (m *Manager) triggerFlush() {
+	select {
+	case m.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) tryFlush() {
+	select {
+	case <-m.flushCh:
+		m.flush()
+	default:
+	}
+}
+
+func (m *Manager) flush() {
+	bs, err := m.store.bytes()
+	if err != nil {
+		return
+	}
+
+	// the file is replaced atomically, the plugin may be killed in the middle of writing
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, bs, 0644); err != nil {
+		m.Warningf("couldn't write state file: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		m.Warningf("couldn't write state file: %v", err)
+		_ = os.Remove(tmp)
+	}
+}
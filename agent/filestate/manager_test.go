@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package filestate
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManager(t *testing.T) {
+	mgr := NewManager("")
+	assert.NotNil(t, mgr.store)
+}
+
+func TestManager_Run(t *testing.T) {
+	cfg1 := prepareConfig("module", "module1", "name", "name1")
+	cfg2 := prepareConfig("module", "module2", "name", "name2")
+	lastSuccess := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	filename := filepath.Join(t.TempDir(), "state.json")
+	mgr := NewManager(filename)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { defer close(done); mgr.Run(ctx) }()
+
+	mgr.Save(cfg1, module.JobState{LastSuccess: lastSuccess, ModuleState: []byte("state")})
+	mgr.Save(cfg2, module.JobState{Failures: 3})
+	mgr.Remove(cfg2)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out after 5s")
+	}
+
+	s, err := LoadStore(filename, time.Minute)
+	require.NoError(t, err)
+
+	state, ok := s.Lookup(cfg1)
+	require.True(t, ok)
+	assert.Equal(t, module.JobState{LastSuccess: lastSuccess, ModuleState: []byte("state")}, state)
+
+	_, ok = s.Lookup(cfg2)
+	assert.False(t, ok)
+
+	assert.NoFileExists(t, filename+".tmp")
+}
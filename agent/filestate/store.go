@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package filestate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+)
+
+// LoadStore reads the job states from the file. The states that were saved more than maxAge ago are dropped.
+// Zero maxAge means no limit.
+func LoadStore(path string, maxAge time.Duration) (*Store, error) {
+	var s Store
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := json.NewDecoder(f).Decode(&s.items); err != nil {
+		return nil, err
+	}
+
+	if maxAge > 0 {
+		now := time.Now()
+		for mod, jobs := range s.items {
+			for key, item := range jobs {
+				if item == nil || now.Sub(item.Updated) > maxAge {
+					delete(jobs, key)
+				}
+			}
+			if len(jobs) == 0 {
+				delete(s.items, mod)
+			}
+		}
+	}
+
+	return &s, nil
+}
+
+type (
+	Store struct {
+		mux   sync.Mutex
+		items map[string]map[string]*storeItem // [module][name:hash]state
+	}
+	storeItem struct {
+		Updated time.Time `json:"updated"`
+		module.JobState
+	}
+)
+
+func (s *Store) Lookup(cfg confgroup.Config) (module.JobState, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	item, ok := s.items[cfg.Module()][storeJobKey(cfg)]
+	if !ok || item == nil {
+		return module.JobState{}, false
+	}
+
+	return item.JobState, true
+}
+
+func (s *Store) add(cfg confgroup.Config, state module.JobState, now time.Time) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.items == nil {
+		s.items = make(map[string]map[string]*storeItem)
+	}
+
+	if s.items[cfg.Module()] == nil {
+		s.items[cfg.Module()] = make(map[string]*storeItem)
+	}
+
+	s.items[cfg.Module()][storeJobKey(cfg)] = &storeItem{Updated: now, JobState: state}
+}
+
+func (s *Store) remove(cfg confgroup.Config) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, ok := s.items[cfg.Module()][storeJobKey(cfg)]; !ok {
+		return false
+	}
+
+	delete(s.items[cfg.Module()], storeJobKey(cfg))
+
+	if len(s.items[cfg.Module()]) == 0 {
+		delete(s.items, cfg.Module())
+	}
+
+	return true
+}
+
+func (s *Store) bytes() ([]byte, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return json.MarshalIndent(s.items, "", " ")
+}
+
+func storeJobKey(cfg confgroup.Config) string {
+	return fmt.Sprintf("%s:%d", cfg.Name(), cfg.Hash())
+}
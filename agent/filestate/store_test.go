@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package filestate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStore(t *testing.T) {
+	cfg := prepareConfig("module", "modName", "name", "jobName")
+	now := time.Now()
+
+	tests := map[string]struct {
+		content  func() string
+		maxAge   time.Duration
+		wantErr  bool
+		wantLoad bool
+	}{
+		"valid state": {
+			content:  func() string { return prepareStateFile(t, cfg, now.Add(-time.Hour)) },
+			maxAge:   time.Hour * 2,
+			wantLoad: true,
+		},
+		"stale state": {
+			content: func() string { return prepareStateFile(t, cfg, now.Add(-time.Hour*3)) },
+			maxAge:  time.Hour * 2,
+		},
+		"stale state, no max age": {
+			content:  func() string { return prepareStateFile(t, cfg, now.Add(-time.Hour*3)) },
+			wantLoad: true,
+		},
+		"corrupt file": {
+			content: func() string { return `{"modName": {"jobName:1": ` },
+			wantErr: true,
+		},
+		"unexpected format": {
+			content: func() string { return `{"modName": ["jobName"]}` },
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			require.NoError(t, os.WriteFile(path, []byte(test.content()), 0644))
+
+			s, err := LoadStore(path, test.maxAge)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			state, ok := s.Lookup(cfg)
+			assert.Equal(t, test.wantLoad, ok)
+			if test.wantLoad {
+				assert.Equal(t, 2, state.Failures)
+				assert.Equal(t, []byte("state"), state.ModuleState)
+			}
+		})
+	}
+}
+
+func TestLoadStore_NotExist(t *testing.T) {
+	_, err := LoadStore(filepath.Join(t.TempDir(), "state.json"), 0)
+	assert.Error(t, err)
+}
+
+func TestStore_add_remove(t *testing.T) {
+	cfg1 := prepareConfig("module", "modName", "name", "jobName")
+	cfg2 := prepareConfig("module", "modName", "name", "jobName", "opt", "val")
+
+	s := &Store{}
+
+	s.add(cfg1, module.JobState{Failures: 1}, time.Now())
+	s.add(cfg2, module.JobState{Failures: 2}, time.Now())
+	assert.Equal(t, 2, calcStoreItems(s))
+
+	state, ok := s.Lookup(cfg2)
+	require.True(t, ok)
+	assert.Equal(t, 2, state.Failures)
+
+	assert.True(t, s.remove(cfg1))
+	assert.False(t, s.remove(cfg1))
+	assert.Equal(t, 1, calcStoreItems(s))
+
+	assert.True(t, s.remove(cfg2))
+	assert.Empty(t, s.items)
+}
+
+func prepareStateFile(t *testing.T, cfg confgroup.Config, updated time.Time) string {
+	s := &Store{}
+	s.add(cfg, module.JobState{Failures: 2, ModuleState: []byte("state")}, updated)
+	bs, err := s.bytes()
+	require.NoError(t, err)
+	return string(bs)
+}
+
+func calcStoreItems(s *Store) (num int) {
+	for _, v := range s.items {
+		num += len(v)
+	}
+	return num
+}
+
+func prepareConfig(values ...string) confgroup.Config {
+	cfg := confgroup.Config{}
+	for i := 1; i < len(values); i += 2 {
+		cfg[values[i-1]] = values[i]
+	}
+	return cfg
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+)
+
+const defaultRetryBackoffMax = 600 // seconds
+
+// retryDelay returns the delay before the next autodetection attempt of a job that failed 'attempt' + 1 times.
+// The delay starts at 'autodetection_retry' and doubles on every failed attempt up to 'autodetection_retry_backoff_max'.
+// A random jitter (up to 10%) spreads the attempts of the jobs that failed at the same time (e.g. an endpoint is down).
+// The delay is fixed if 'autodetection_retry_backoff' is false.
+func retryDelay(cfg confgroup.Config, base, attempt int) time.Duration {
+	if v, ok := cfg["autodetection_retry_backoff"].(bool); ok && !v {
+		return time.Duration(base) * time.Second
+	}
+
+	limit := defaultRetryBackoffMax
+	if v, ok := cfg["autodetection_retry_backoff_max"].(int); ok && v > 0 {
+		limit = v
+	}
+	limit = max(limit, base)
+
+	delay := base
+	for i := 0; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	delay = min(delay, limit)
+
+	d := time.Duration(delay) * time.Second
+
+	return d + time.Duration(rand.Int63n(int64(d)/10+1))
+}
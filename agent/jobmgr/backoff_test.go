@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_retryDelay(t *testing.T) {
+	tests := map[string]struct {
+		cfg       confgroup.Config
+		base      int
+		attempt   int
+		wantDelay time.Duration
+	}{
+		"first attempt": {
+			cfg:       confgroup.Config{},
+			base:      10,
+			attempt:   0,
+			wantDelay: time.Second * 10,
+		},
+		"doubles on every attempt": {
+			cfg:       confgroup.Config{},
+			base:      10,
+			attempt:   3,
+			wantDelay: time.Second * 80,
+		},
+		"limited by the default max": {
+			cfg:       confgroup.Config{},
+			base:      10,
+			attempt:   100,
+			wantDelay: time.Minute * 10,
+		},
+		"limited by the configured max": {
+			cfg:       confgroup.Config{"autodetection_retry_backoff_max": 60},
+			base:      10,
+			attempt:   5,
+			wantDelay: time.Minute,
+		},
+		"max less than base": {
+			cfg:       confgroup.Config{"autodetection_retry_backoff_max": 5},
+			base:      10,
+			attempt:   5,
+			wantDelay: time.Second * 10,
+		},
+		"backoff disabled": {
+			cfg:       confgroup.Config{"autodetection_retry_backoff": false},
+			base:      10,
+			attempt:   5,
+			wantDelay: time.Second * 10,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				delay := retryDelay(test.cfg, test.base, test.attempt)
+
+				assert.GreaterOrEqual(t, delay, test.wantDelay)
+				assert.LessOrEqual(t, delay, test.wantDelay+test.wantDelay/10)
+			}
+		})
+	}
+}
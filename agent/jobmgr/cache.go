@@ -17,24 +17,30 @@ func newRetryingJobsCache() *retryingJobsCache {
 }
 
 type (
-	runningJobsCache  map[string]bool
+	runningJobsCache  map[string]uint64 // [fullName]cfgHash
 	retryingJobsCache map[uint64]retryTask
 
 	retryTask struct {
 		cancel  context.CancelFunc
 		timeout int
 		retries int
+		attempt int // failed attempts since the first one, used to calculate the backoff
 	}
 )
 
 func (c runningJobsCache) put(cfg confgroup.Config) {
-	c[cfg.FullName()] = true
+	c[cfg.FullName()] = cfg.Hash()
 }
 func (c runningJobsCache) remove(cfg confgroup.Config) {
 	delete(c, cfg.FullName())
 }
 func (c runningJobsCache) has(cfg confgroup.Config) bool {
-	return c[cfg.FullName()]
+	_, ok := c[cfg.FullName()]
+	return ok
+}
+func (c runningJobsCache) hasConfig(cfg confgroup.Config) bool {
+	hash, ok := c[cfg.FullName()]
+	return ok && hash == cfg.Hash()
 }
 
 func (c retryingJobsCache) put(cfg confgroup.Config, retry retryTask) {
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+)
+
+const (
+	maxCheckWorkers = 32
+	// defaultDetectionTimeout is used for the jobs that have no 'timeout' option.
+	defaultDetectionTimeout = time.Second * 30
+	// Check() usually makes a few requests, each of them limited by the job 'timeout' option.
+	detectionTimeoutFactor = 5
+)
+
+type detectionResult struct {
+	cfg     confgroup.Config
+	job     *module.Job
+	attempt int
+
+	status          jobStatus
+	timedOut        bool
+	timeout         time.Duration
+	autoDetectEvery int
+	autoDetectTries int
+}
+
+// runDetection runs the job autodetection in the background, the result is handled by the configs handling goroutine.
+// The number of concurrent autodetections is limited by the check workers, an autodetection that doesn't complete
+// within the timeout frees the worker and is considered failed.
+func (m *Manager) runDetection(ctx context.Context, cfg confgroup.Config, job *module.Job, attempt int) {
+	if m.checkWorkers == nil {
+		m.checkWorkers = make(chan struct{}, checkWorkers(m.CheckWorkers))
+	}
+
+	m.detecting[cfg.Hash()] = job
+	m.jobs[cfg.Hash()] = &jobInfo{cfg: cfg, status: jobStatusDetecting}
+
+	res := detectionResult{
+		cfg:     cfg,
+		job:     job,
+		attempt: attempt,
+		timeout: detectionTimeout(cfg),
+		// a timed out job is still being detected, its fields can't be read
+		autoDetectEvery: job.AutoDetectEvery,
+		autoDetectTries: job.AutoDetectTries,
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case m.checkWorkers <- struct{}{}:
+		}
+
+		res = m.detect(ctx, res)
+		<-m.checkWorkers
+
+		select {
+		case <-ctx.Done():
+		case m.detectedCh <- res:
+		}
+	}()
+}
+
+func (m *Manager) detect(ctx context.Context, res detectionResult) detectionResult {
+	done := make(chan jobStatus, 1)
+	go func() { done <- detection(res.job) }()
+
+	tk := time.NewTimer(res.timeout)
+	defer tk.Stop()
+
+	select {
+	case status := <-done:
+		res.status = status
+		res.autoDetectEvery = res.job.AutoDetectionEvery()
+		res.autoDetectTries = res.job.AutoDetectTries
+	case <-tk.C:
+		// the detection can't be cancelled, the job is released when it completes, the result is discarded
+		go func() {
+			if status := <-done; status == jobStatusRunning {
+				res.job.CleanupModule()
+			}
+			res.job.Cleanup()
+		}()
+
+		// the same as the failed Check()
+		res.timedOut = true
+		if res.autoDetectTries > 0 {
+			res.autoDetectTries--
+		}
+		if res.autoDetectEvery > 0 && res.autoDetectTries != 0 {
+			res.status = jobStatusRetrying
+		} else {
+			res.status = jobStatusStoppedFailed
+		}
+	case <-ctx.Done():
+	}
+
+	return res
+}
+
+func checkWorkers(n int) int {
+	if n <= 0 {
+		n = runtime.NumCPU() * 2
+	}
+	return min(n, maxCheckWorkers)
+}
+
+// detectionTimeout returns the time the job autodetection is allowed to take.
+func detectionTimeout(cfg confgroup.Config) time.Duration {
+	var timeout time.Duration
+
+	switch v := cfg["timeout"].(type) {
+	case int:
+		timeout = time.Duration(v) * time.Second
+	case float64:
+		timeout = time.Duration(v * float64(time.Second))
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			timeout = d
+		} else if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			timeout = time.Duration(f * float64(time.Second))
+		}
+	}
+
+	if timeout <= 0 {
+		return defaultDetectionTimeout
+	}
+	return timeout * detectionTimeoutFactor
+}
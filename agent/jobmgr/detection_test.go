@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_runDetection_Concurrency(t *testing.T) {
+	const (
+		jobs      = 40
+		workers   = 10
+		checkTime = time.Millisecond * 200
+	)
+
+	var mux sync.Mutex
+	var checking, maxChecking int
+	reg := module.Registry{}
+	reg.Register("slow", module.Creator{
+		Create: func() module.Module {
+			return &module.MockModule{
+				CheckFunc: func() bool {
+					mux.Lock()
+					checking++
+					maxChecking = max(maxChecking, checking)
+					mux.Unlock()
+
+					time.Sleep(checkTime)
+
+					mux.Lock()
+					checking--
+					mux.Unlock()
+					return true
+				},
+				ChartsFunc: func() *module.Charts {
+					return &module.Charts{
+						&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}},
+					}
+				},
+			}
+		},
+	})
+
+	mgr := NewManager()
+	mgr.Modules = reg
+	mgr.CheckWorkers = workers
+	defer mgr.stopRunningJobs()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.runConfigsHandling(ctx)
+
+	start := time.Now()
+	for i := 0; i < jobs; i++ {
+		sendConfig(ctx, mgr.addCh, confgroup.Config{"name": fmt.Sprintf("job%d", i), "module": "slow", "update_every": 1})
+	}
+
+	require.Eventually(t, func() bool {
+		mgr.queueMux.Lock()
+		defer mgr.queueMux.Unlock()
+		return len(mgr.queue) == jobs
+	}, time.Second*5, time.Millisecond*10, "not all the jobs are started")
+
+	// serial checks take jobs*checkTime (8s)
+	elapsed := time.Since(start)
+	assert.Less(t, elapsed, checkTime*jobs/workers*2, "startup time")
+	assert.Equal(t, workers, maxChecking, "concurrent checks")
+}
+
+func TestManager_runDetection_Timeout(t *testing.T) {
+	hang := make(chan struct{})
+	var checkReturned atomic.Bool
+	cleanup := make(chan bool, 1)
+
+	reg := prepareMockRegistry()
+	reg.Register("hung", module.Creator{
+		Create: func() module.Module {
+			return &module.MockModule{
+				CheckFunc: func() bool { <-hang; checkReturned.Store(true); return true },
+				// the late detection succeeds, the module is not cleaned up by the failed AutoDetection
+				ChartsFunc: func() *module.Charts {
+					return &module.Charts{
+						&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}},
+					}
+				},
+				CleanupFunc: func() { cleanup <- checkReturned.Load() },
+			}
+		},
+	})
+
+	mgr := NewManager()
+	mgr.Modules = reg
+	mgr.CheckWorkers = 1
+	defer mgr.stopRunningJobs()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.runConfigsHandling(ctx)
+
+	// detection timeout is 5 times the job timeout
+	hung := confgroup.Config{"name": "hung", "module": "hung", "update_every": 1, "timeout": 0.05, "__source__": "test"}
+	fast := confgroup.Config{"name": "fast", "module": "success", "update_every": 1, "__source__": "test"}
+
+	start := time.Now()
+	sendConfig(ctx, mgr.addCh, hung)
+	sendConfig(ctx, mgr.addCh, fast)
+
+	assertJobs(t, mgr,
+		JobState{Module: "hung", Name: "hung", State: jobStatusStoppedFailed, Source: "test"},
+		JobState{Module: "success", Name: "fast", State: jobStatusRunning, Source: "test"},
+	)
+	assert.Less(t, time.Since(start), time.Second, "the hung check occupies the worker")
+
+	select {
+	case <-cleanup:
+		t.Fatal("the timed out job is cleaned up while its check is running")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	close(hang)
+
+	select {
+	case afterCheck := <-cleanup:
+		assert.True(t, afterCheck, "the timed out job is cleaned up after its check returns")
+	case <-time.After(time.Second):
+		t.Error("the timed out job is not cleaned up after its check returns")
+	}
+}
+
+func TestManager_runDetection_RemovedWhileDetecting(t *testing.T) {
+	check := make(chan struct{})
+
+	reg := module.Registry{}
+	reg.Register("slow", module.Creator{
+		Create: func() module.Module {
+			return &module.MockModule{
+				CheckFunc: func() bool { <-check; return true },
+				ChartsFunc: func() *module.Charts {
+					return &module.Charts{
+						&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}},
+					}
+				},
+			}
+		},
+	})
+
+	mgr := NewManager()
+	mgr.Modules = reg
+	defer mgr.stopRunningJobs()
+
+	ctx := context.Background()
+	cfg := confgroup.Config{"name": "slow", "module": "slow", "update_every": 1}
+
+	mgr.addConfig(ctx, cfg)
+	require.Contains(t, mgr.detecting, cfg.Hash())
+
+	mgr.removeConfig(cfg)
+	close(check)
+	mgr.handleDetection(ctx, <-mgr.detectedCh)
+
+	assert.Empty(t, mgr.queue, "the job is started")
+	assert.Empty(t, mgr.detecting)
+	assert.Empty(t, mgr.jobs)
+	assert.False(t, mgr.runningJobs.has(cfg))
+}
+
+func Test_checkWorkers(t *testing.T) {
+	assert.Equal(t, min(runtime.NumCPU()*2, maxCheckWorkers), checkWorkers(0))
+	assert.Equal(t, 4, checkWorkers(4))
+	assert.Equal(t, maxCheckWorkers, checkWorkers(100))
+}
+
+func Test_detectionTimeout(t *testing.T) {
+	tests := map[string]struct {
+		timeout any
+		want    time.Duration
+	}{
+		"not set":        {want: defaultDetectionTimeout},
+		"int":            {timeout: 2, want: time.Second * 10},
+		"float":          {timeout: 0.5, want: time.Millisecond * 2500},
+		"duration":       {timeout: "3s", want: time.Second * 15},
+		"number string":  {timeout: "1", want: time.Second * 5},
+		"invalid string": {timeout: "1z", want: defaultDetectionTimeout},
+		"zero":           {timeout: 0, want: defaultDetectionTimeout},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := confgroup.Config{"name": "name", "module": "module"}
+			if test.timeout != nil {
+				cfg["timeout"] = test.timeout
+			}
+			assert.Equal(t, test.want, detectionTimeout(cfg))
+		})
+	}
+}
@@ -4,6 +4,8 @@ package jobmgr
 
 import (
 	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/functions"
+	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/netdata/go.d.plugin/agent/vnodes"
 )
 
@@ -25,8 +27,31 @@ type StatusStore interface {
 	Contains(cfg confgroup.Config, states ...string) bool
 }
 
+type JobStateSaver interface {
+	Save(cfg confgroup.Config, state module.JobState)
+	Remove(cfg confgroup.Config)
+}
+
+type JobStateStore interface {
+	Lookup(cfg confgroup.Config) (module.JobState, bool)
+}
+
 type Dyncfg interface {
 	Register(cfg confgroup.Config)
 	Unregister(cfg confgroup.Config)
 	UpdateStatus(cfg confgroup.Config, status, payload string)
 }
+
+type FunctionAPI interface {
+	FunctionResultSuccess(uid, contentType, payload string) error
+	FunctionResultReject(uid, contentType, payload string) error
+}
+
+type FunctionRegistry interface {
+	Register(name string, reg func(functions.Function))
+}
+
+type PluginStats interface {
+	module.Module
+	module.RunObserver
+}
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/functions"
+)
+
+const commandTimeout = time.Second * 5
+
+type (
+	jobInfo struct {
+		cfg    confgroup.Config
+		status jobStatus
+	}
+	// JobState is a job in the 'jobs' function output.
+	JobState struct {
+		Module string `json:"module"`
+		Name   string `json:"name"`
+		State  string `json:"state"`
+		Source string `json:"source"`
+	}
+	jobController interface {
+		listJobs() ([]JobState, error)
+		disableJob(module, name string, persist bool) error
+		enableJob(module, name string) error
+	}
+	jobsFunction struct {
+		api FunctionAPI
+		ctl jobController
+	}
+)
+
+// RegisterFunctions registers the 'jobs' function:
+//
+//   - 'jobs' lists all the jobs with their module, name, state and source.
+//   - 'jobs disable <module:name> [persist]' stops the job and marks its charts obsolete.
+//     The job stays disabled when its config is updated, 'persist' keeps it disabled after the plugin restart (requires the state file).
+//   - 'jobs enable <module:name>' re-creates the job (Init and Check are run again).
+func (m *Manager) RegisterFunctions(r FunctionRegistry) {
+	f := &jobsFunction{api: m.API, ctl: m}
+	r.Register("jobs", f.handle)
+}
+
+func (f *jobsFunction) handle(fn functions.Function) {
+	if f.api == nil {
+		return
+	}
+
+	if len(fn.Args) == 0 {
+		jobs, err := f.ctl.listJobs()
+		if err != nil {
+			f.reject(fn, err)
+			return
+		}
+		bs, err := json.Marshal(map[string]any{"jobs": jobs})
+		if err != nil {
+			f.reject(fn, err)
+			return
+		}
+		_ = f.api.FunctionResultSuccess(fn.UID, "application/json", string(bs))
+		return
+	}
+
+	cmd, args := fn.Args[0], fn.Args[1:]
+
+	var err error
+	switch {
+	case cmd == "disable" && (len(args) == 1 || len(args) == 2 && args[1] == "persist"):
+		var mod, name string
+		if mod, name, err = parseJobKey(args[0]); err == nil {
+			err = f.ctl.disableJob(mod, name, len(args) == 2)
+		}
+	case cmd == "enable" && len(args) == 1:
+		var mod, name string
+		if mod, name, err = parseJobKey(args[0]); err == nil {
+			err = f.ctl.enableJob(mod, name)
+		}
+	default:
+		err = fmt.Errorf("unknown command '%s', expected 'disable <module:name> [persist]' or 'enable <module:name>'",
+			strings.Join(fn.Args, " "))
+	}
+	if err != nil {
+		f.reject(fn, err)
+		return
+	}
+
+	_ = f.api.FunctionResultSuccess(fn.UID, "application/json", `{ "status": "ok" }`)
+}
+
+func (f *jobsFunction) reject(fn functions.Function, err error) {
+	bs, _ := json.Marshal(map[string]string{"error": err.Error()})
+	_ = f.api.FunctionResultReject(fn.UID, "application/json", string(bs))
+}
+
+func parseJobKey(key string) (mod, name string, err error) {
+	mod, name, ok := strings.Cut(key, ":")
+	if !ok || mod == "" || name == "" {
+		return "", "", fmt.Errorf("invalid job '%s', expected 'module:name'", key)
+	}
+	return mod, name, nil
+}
+
+func (m *Manager) listJobs() (jobs []JobState, err error) {
+	err = m.execCommand(func(context.Context) {
+		for _, info := range m.jobs {
+			jobs = append(jobs, JobState{
+				Module: info.cfg.Module(),
+				Name:   info.cfg.Name(),
+				State:  info.status,
+				Source: info.cfg.Source(),
+			})
+		}
+	})
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Module != jobs[j].Module {
+			return jobs[i].Module < jobs[j].Module
+		}
+		if jobs[i].Name != jobs[j].Name {
+			return jobs[i].Name < jobs[j].Name
+		}
+		return jobs[i].Source < jobs[j].Source
+	})
+	return jobs, err
+}
+
+func (m *Manager) disableJob(mod, name string, persist bool) error {
+	var err error
+	execErr := m.execCommand(func(context.Context) {
+		cfgs := m.lookupConfigs(mod, name)
+		if len(cfgs) == 0 {
+			err = fmt.Errorf("job '%s:%s' not found", mod, name)
+			return
+		}
+
+		m.Infof("%s[%s] disabling the job", mod, name)
+		m.disabled[jobKey(mod, name)] = persist
+
+		for _, cfg := range cfgs {
+			if m.runningJobs.hasConfig(cfg) {
+				m.stopJob(cfg.FullName())
+				_ = m.FileLock.Unlock(cfg.FullName())
+				m.runningJobs.remove(cfg)
+			}
+			if task, ok := m.retryingJobs.lookup(cfg); ok {
+				task.cancel()
+				m.retryingJobs.remove(cfg)
+			}
+			delete(m.detecting, cfg.Hash())
+			m.setDisabledStatus(cfg, persist)
+		}
+	})
+	return errors.Join(execErr, err)
+}
+
+func (m *Manager) enableJob(mod, name string) error {
+	var err error
+	execErr := m.execCommand(func(ctx context.Context) {
+		if _, ok := m.disabled[jobKey(mod, name)]; !ok {
+			err = fmt.Errorf("job '%s:%s' is not disabled", mod, name)
+			return
+		}
+
+		m.Infof("%s[%s] enabling the job", mod, name)
+		delete(m.disabled, jobKey(mod, name))
+
+		for _, cfg := range m.lookupConfigs(mod, name) {
+			m.addConfig(ctx, cfg)
+		}
+	})
+	return errors.Join(execErr, err)
+}
+
+// execCommand runs the command in the configs handling goroutine and waits for it to complete.
+func (m *Manager) execCommand(cmd func(ctx context.Context)) error {
+	done := make(chan struct{})
+
+	select {
+	case m.commandCh <- func(ctx context.Context) { defer close(done); cmd(ctx) }:
+	case <-time.After(commandTimeout):
+		return errors.New("job manager is not responding")
+	}
+
+	<-done
+	return nil
+}
+
+func (m *Manager) lookupConfigs(mod, name string) []confgroup.Config {
+	var cfgs []confgroup.Config
+	for _, info := range m.jobs {
+		if info.cfg.Module() == mod && info.cfg.Name() == name {
+			cfgs = append(cfgs, info.cfg)
+		}
+	}
+	sort.Slice(cfgs, func(i, j int) bool { return cfgs[i].Source() < cfgs[j].Source() })
+	return cfgs
+}
+
+// isDisabled returns whether the job is disabled, and if it is, whether it is disabled persistently.
+func (m *Manager) isDisabled(cfg confgroup.Config) (persist bool, ok bool) {
+	key := jobKey(cfg.Module(), cfg.Name())
+	if persist, ok = m.disabled[key]; ok {
+		return persist, true
+	}
+	// the state file of the previous run is checked only for the new configs, the job could be enabled since then
+	if _, known := m.jobs[cfg.Hash()]; !known && m.StatusStore.Contains(cfg, jobStatusDisabled) {
+		m.disabled[key] = true
+		return true, true
+	}
+	return false, false
+}
+
+// setDisabledStatus saves the status to the state file only if the job is disabled persistently.
+func (m *Manager) setDisabledStatus(cfg confgroup.Config, persist bool) {
+	if persist {
+		m.saveStatus(cfg, jobStatusDisabled)
+	} else {
+		m.jobs[cfg.Hash()] = &jobInfo{cfg: cfg, status: jobStatusDisabled}
+		m.StatusSaver.Remove(cfg)
+	}
+	m.Dyncfg.UpdateStatus(cfg, "disabled", "disabled via the 'jobs' function")
+}
+
+func jobKey(mod, name string) string {
+	return mod + ":" + name
+}
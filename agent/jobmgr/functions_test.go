@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/functions"
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobsFunction_handle(t *testing.T) {
+	tests := map[string]struct {
+		args        []string
+		ctl         *mockJobController
+		wantSuccess string
+		wantReject  string
+		wantCalls   []string
+	}{
+		"list": {
+			ctl: &mockJobController{jobs: []JobState{
+				{Module: "nginx", Name: "local", State: "running", Source: "/etc/netdata/go.d/nginx.conf"},
+				{Module: "redis", Name: "redis-0", State: "disabled", Source: "sd:k8s:pod(default/redis-0)"},
+			}},
+			wantSuccess: `{"jobs":[` +
+				`{"module":"nginx","name":"local","state":"running","source":"/etc/netdata/go.d/nginx.conf"},` +
+				`{"module":"redis","name":"redis-0","state":"disabled","source":"sd:k8s:pod(default/redis-0)"}]}`,
+		},
+		"list error": {
+			ctl:        &mockJobController{err: errors.New("job manager is not responding")},
+			wantReject: `{"error":"job manager is not responding"}`,
+		},
+		"disable": {
+			args:        []string{"disable", "nginx:local"},
+			ctl:         &mockJobController{},
+			wantSuccess: `{ "status": "ok" }`,
+			wantCalls:   []string{"disable nginx local false"},
+		},
+		"disable persist": {
+			args:        []string{"disable", "nginx:local", "persist"},
+			ctl:         &mockJobController{},
+			wantSuccess: `{ "status": "ok" }`,
+			wantCalls:   []string{"disable nginx local true"},
+		},
+		"disable unknown job": {
+			args:       []string{"disable", "nginx:remote"},
+			ctl:        &mockJobController{err: errors.New("job 'nginx:remote' not found")},
+			wantReject: `{"error":"job 'nginx:remote' not found"}`,
+			wantCalls:  []string{"disable nginx remote false"},
+		},
+		"enable": {
+			args:        []string{"enable", "nginx:local"},
+			ctl:         &mockJobController{},
+			wantSuccess: `{ "status": "ok" }`,
+			wantCalls:   []string{"enable nginx local"},
+		},
+		"invalid job key": {
+			args:       []string{"enable", "nginx"},
+			ctl:        &mockJobController{},
+			wantReject: `{"error":"invalid job 'nginx', expected 'module:name'"}`,
+		},
+		"unknown command": {
+			args:       []string{"restart", "nginx:local"},
+			ctl:        &mockJobController{},
+			wantReject: `{"error":"unknown command 'restart nginx:local', expected 'disable \u003cmodule:name\u003e [persist]' or 'enable \u003cmodule:name\u003e'"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &mockFunctionAPI{}
+			f := &jobsFunction{api: api, ctl: test.ctl}
+
+			f.handle(functions.Function{UID: "uid", Name: "jobs", Args: test.args})
+
+			assert.Equal(t, test.wantSuccess, api.success, "success payload")
+			assert.Equal(t, test.wantReject, api.reject, "reject payload")
+			assert.Equal(t, test.wantCalls, test.ctl.calls, "controller calls")
+		})
+	}
+}
+
+func TestManager_disableJob_enableJob(t *testing.T) {
+	var inits int
+	reg := module.Registry{}
+	reg.Register("success", module.Creator{
+		Create: func() module.Module {
+			return &module.MockModule{
+				InitFunc:  func() bool { inits++; return true },
+				CheckFunc: func() bool { return true },
+				ChartsFunc: func() *module.Charts {
+					return &module.Charts{
+						&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}},
+					}
+				},
+				CollectFunc: func() map[string]int64 { return map[string]int64{"id1": 1} },
+			}
+		},
+	})
+
+	cfg := confgroup.Config{"name": "httpd", "module": "success", "update_every": 1, "__source__": "sd:k8s:pod(default/httpd)"}
+	cfgRev := confgroup.Config{"name": "httpd", "module": "success", "update_every": 1, "url": "http://10.0.0.6", "__source__": "sd:k8s:pod(default/httpd)"}
+
+	var buf lockedBuffer
+	mgr := NewManager()
+	mgr.Modules = reg
+	mgr.Out = &buf
+	mgr.PluginName = "test.plugin"
+	defer mgr.stopRunningJobs()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.runConfigsHandling(ctx)
+
+	sendConfig(ctx, mgr.addCh, cfg)
+	assertJobs(t, mgr, JobState{Module: "success", Name: "httpd", State: jobStatusRunning, Source: cfg.Source()})
+
+	var clock int
+	for clock < 5 && !strings.Contains(buf.String(), "CHART 'success_httpd.id'") {
+		clock++
+		mgr.notifyRunningJobs(clock)
+	}
+
+	require.NoError(t, mgr.disableJob("success", "httpd", false))
+	assertJobs(t, mgr, JobState{Module: "success", Name: "httpd", State: jobStatusDisabled, Source: cfg.Source()})
+	assert.Empty(t, mgr.queue, "the job is stopped")
+
+	// the config is updated by sd
+	sendConfig(ctx, mgr.removeCh, cfg)
+	sendConfig(ctx, mgr.addCh, cfgRev)
+	assertJobs(t, mgr, JobState{Module: "success", Name: "httpd", State: jobStatusDisabled, Source: cfg.Source()})
+	assert.Equal(t, 1, inits)
+
+	require.NoError(t, mgr.enableJob("success", "httpd"))
+	assertJobs(t, mgr, JobState{Module: "success", Name: "httpd", State: jobStatusRunning, Source: cfg.Source()})
+	assert.Equal(t, 2, inits, "Init is called again")
+	assert.Len(t, mgr.queue, 1)
+
+	assert.Error(t, mgr.enableJob("success", "httpd"), "the job is not disabled")
+	assert.Error(t, mgr.disableJob("success", "nginx", false), "the job doesn't exist")
+}
+
+func assertJobs(t *testing.T, mgr *Manager, want ...JobState) {
+	t.Helper()
+	var jobs []JobState
+	// the autodetection runs in the background
+	ok := assert.Eventually(t, func() bool {
+		v, err := mgr.listJobs()
+		jobs = v
+		return err == nil && reflect.DeepEqual(want, v)
+	}, time.Second*5, time.Millisecond*10)
+	if !ok {
+		assert.Equal(t, want, jobs)
+	}
+}
+
+type mockJobController struct {
+	jobs  []JobState
+	err   error
+	calls []string
+}
+
+func (m *mockJobController) listJobs() ([]JobState, error) { return m.jobs, m.err }
+
+func (m *mockJobController) disableJob(mod, name string, persist bool) error {
+	m.calls = append(m.calls, strings.Join([]string{"disable", mod, name, map[bool]string{true: "true", false: "false"}[persist]}, " "))
+	return m.err
+}
+
+func (m *mockJobController) enableJob(mod, name string) error {
+	m.calls = append(m.calls, strings.Join([]string{"enable", mod, name}, " "))
+	return m.err
+}
+
+type mockFunctionAPI struct {
+	success string
+	reject  string
+}
+
+func (m *mockFunctionAPI) FunctionResultSuccess(_, _, payload string) error {
+	m.success = payload
+	return nil
+}
+
+func (m *mockFunctionAPI) FunctionResultReject(_, _, payload string) error {
+	m.reject = payload
+	return nil
+}
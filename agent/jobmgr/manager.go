@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +29,7 @@ type Job interface {
 	AutoDetectionEvery() int
 	RetryAutoDetection() bool
 	Tick(clock int)
+	SetUpdateEvery(updateEvery int)
 	Start()
 	Stop()
 	Cleanup()
@@ -42,8 +45,15 @@ const (
 	jobStatusStoppedDupGlobal jobStatus = "stopped_duplicate_global"   // a job with the same FullName is registered by another plugin
 	jobStatusStoppedRegErr    jobStatus = "stopped_registration_error" // an error during registration (only 'too many open files')
 	jobStatusStoppedCreateErr jobStatus = "stopped_creation_error"     // an error during creation (yaml unmarshal)
+	jobStatusDisabled         jobStatus = "disabled"                   // disabled at runtime via the 'jobs' function
+	jobStatusDetecting        jobStatus = "detecting"                  // Init() and Check() are in progress, not saved
 )
 
+const statsJobName = "plugin_stats"
+
+// jobStatesSaveEvery is the interval of saving the running jobs states.
+const jobStatesSaveEvery = time.Second * 10
+
 func NewManager() *Manager {
 	np := noop{}
 	mgr := &Manager{
@@ -62,32 +72,70 @@ func NewManager() *Manager {
 		runningJobs:  newRunningJobsCache(),
 		retryingJobs: newRetryingJobsCache(),
 
-		addCh:    make(chan confgroup.Config),
-		removeCh: make(chan confgroup.Config),
+		jobs:            make(map[uint64]*jobInfo),
+		disabled:        make(map[string]bool),
+		ephemeralVnodes: make(map[string]*ephemeralVnode),
+		detecting:       make(map[uint64]*module.Job),
+
+		addCh:        make(chan confgroup.Config),
+		removeCh:     make(chan confgroup.Config),
+		rescheduleCh: make(chan configRevision),
+		commandCh:    make(chan func(ctx context.Context)),
+		detectedCh:   make(chan detectionResult),
 	}
 
 	return mgr
 }
 
+// configRevision is a new revision of a job config that differs from the previous one only in 'update_every'.
+type configRevision struct {
+	prev confgroup.Config
+	cfg  confgroup.Config
+}
+
 type Manager struct {
 	*logger.Logger
 
 	PluginName string
 	Out        io.Writer
 	Modules    module.Registry
+	API        FunctionAPI
 
 	FileLock    FileLocker
 	StatusSaver StatusSaver
 	StatusStore StatusStore
 	Vnodes      Vnodes
 	Dyncfg      Dyncfg
+	// Stats is the plugin self-monitoring module, it runs as the internal 'plugin_stats' job
+	// and is notified about the data collection runs of all the other jobs.
+	Stats PluginStats
+	// JobStateSaver and JobStateStore persist the running jobs states across plugin restarts, nil disables it.
+	JobStateSaver JobStateSaver
+	JobStateStore JobStateStore
+	// StrictConfig makes the options the module doesn't have an error, a job config can opt out with 'strict_config: false'.
+	StrictConfig bool
+	// CheckWorkers is the number of jobs autodetections (Init() and Check()) that run concurrently.
+	// Zero means twice the number of CPUs, it is capped at 32.
+	CheckWorkers int
+	// MinUpdateEvery is the plugin-wide minimum data collection interval, the file configs have it applied
+	// by the discovery, the composed ones are clamped by the manager.
+	MinUpdateEvery int
 
 	confGroupCache *confgroup.Cache
 	runningJobs    *runningJobsCache
 	retryingJobs   *retryingJobsCache
 
-	addCh    chan confgroup.Config
-	removeCh chan confgroup.Config
+	jobs            map[uint64]*jobInfo        // [cfgHash], all the known configs and their status
+	disabled        map[string]bool            // [module:name]persist, jobs disabled via the 'jobs' function
+	ephemeralVnodes map[string]*ephemeralVnode // [hostname], vnodes defined by service discovery configs
+	detecting       map[uint64]*module.Job     // [cfgHash], jobs with the autodetection in progress
+	checkWorkers    chan struct{}              // limits the number of concurrent autodetections
+
+	addCh        chan confgroup.Config
+	removeCh     chan confgroup.Config
+	rescheduleCh chan configRevision
+	commandCh    chan func(ctx context.Context) // the 'jobs' function commands, executed by the configs handling
+	detectedCh   chan detectionResult
 
 	queueMux sync.Mutex
 	queue    []Job
@@ -97,6 +145,10 @@ func (m *Manager) Run(ctx context.Context, in chan []*confgroup.Group) {
 	m.Info("instance is started")
 	defer func() { m.cleanup(); m.Info("instance is stopped") }()
 
+	if m.Stats != nil {
+		m.startStatsJob()
+	}
+
 	var wg sync.WaitGroup
 
 	wg.Add(1)
@@ -125,7 +177,9 @@ func (m *Manager) runConfigGroupsHandling(ctx context.Context, in chan []*confgr
 				default:
 					a, r := m.confGroupCache.Add(gr)
 					m.Debugf("received config group ('%s'): %d jobs (added: %d, removed: %d)", gr.Source, len(gr.Configs), len(a), len(r))
+					revs, a, r := splitRevisions(a, r)
 					sendConfigs(ctx, m.removeCh, r)
+					sendRevisions(ctx, m.rescheduleCh, revs)
 					sendConfigs(ctx, m.addCh, a)
 				}
 			}
@@ -134,14 +188,30 @@ func (m *Manager) runConfigGroupsHandling(ctx context.Context, in chan []*confgr
 }
 
 func (m *Manager) runConfigsHandling(ctx context.Context) {
+	var saveStates <-chan time.Time
+	if m.JobStateSaver != nil {
+		tk := time.NewTicker(jobStatesSaveEvery)
+		defer tk.Stop()
+		saveStates = tk.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
+			m.saveJobStates()
 			return
+		case <-saveStates:
+			m.saveJobStates()
 		case cfg := <-m.addCh:
 			m.addConfig(ctx, cfg)
 		case cfg := <-m.removeCh:
 			m.removeConfig(cfg)
+		case rev := <-m.rescheduleCh:
+			m.rescheduleConfig(ctx, rev)
+		case res := <-m.detectedCh:
+			m.handleDetection(ctx, res)
+		case cmd := <-m.commandCh:
+			cmd(ctx)
 		}
 	}
 }
@@ -166,9 +236,15 @@ func (m *Manager) addConfig(ctx context.Context, cfg confgroup.Config) {
 		m.Dyncfg.Register(cfg)
 	}
 
+	if persist, ok := m.isDisabled(cfg); ok {
+		m.Infof("%s[%s] job is disabled, skipping it", cfg.Module(), cfg.Name())
+		m.setDisabledStatus(cfg, persist)
+		return
+	}
+
 	if m.runningJobs.has(cfg) {
 		m.Infof("%s[%s] job is being served by another job, skipping it", cfg.Module(), cfg.Name())
-		m.StatusSaver.Save(cfg, jobStatusStoppedDupLocal)
+		m.saveStatus(cfg, jobStatusStoppedDupLocal)
 		m.Dyncfg.UpdateStatus(cfg, "error", "duplicate, served by another job")
 		return
 	}
@@ -176,18 +252,11 @@ func (m *Manager) addConfig(ctx context.Context, cfg confgroup.Config) {
 	job, err := m.createJob(cfg)
 	if err != nil {
 		m.Warningf("couldn't create %s[%s]: %v", cfg.Module(), cfg.Name(), err)
-		m.StatusSaver.Save(cfg, jobStatusStoppedCreateErr)
+		m.saveStatus(cfg, jobStatusStoppedCreateErr)
 		m.Dyncfg.UpdateStatus(cfg, "error", fmt.Sprintf("build error: %s", err))
 		return
 	}
 
-	cleanupJob := true
-	defer func() {
-		if cleanupJob {
-			job.Cleanup()
-		}
-	}()
-
 	if isRetry {
 		job.AutoDetectEvery = task.timeout
 		job.AutoDetectTries = task.retries
@@ -204,36 +273,76 @@ func (m *Manager) addConfig(ctx context.Context, cfg confgroup.Config) {
 		}
 	}
 
-	switch detection(job) {
+	var attempt int
+	if isRetry {
+		attempt = task.attempt + 1
+	}
+
+	m.runDetection(ctx, cfg, job, attempt)
+}
+
+// handleDetection starts the job or schedules the next autodetection attempt depending on the detection result.
+func (m *Manager) handleDetection(ctx context.Context, res detectionResult) {
+	cfg, job := res.cfg, res.job
+
+	if m.detecting[cfg.Hash()] != job {
+		// the config was removed or the job was disabled while the detection was in progress
+		if !res.timedOut {
+			job.Cleanup()
+		}
+		return
+	}
+	delete(m.detecting, cfg.Hash())
+
+	// a timed out job is cleaned up when its detection completes, see detect
+	cleanupJob := !res.timedOut
+	defer func() {
+		if cleanupJob {
+			job.Cleanup()
+		}
+	}()
+
+	if res.timedOut {
+		m.Warningf("%s[%s] job detection timed out after %s", cfg.Module(), cfg.Name(), res.timeout)
+	}
+
+	switch res.status {
 	case jobStatusRunning:
-		if ok, err := m.FileLock.Lock(cfg.FullName()); ok || err != nil && !isTooManyOpenFiles(err) {
+		if m.runningJobs.has(cfg) {
+			m.Infof("%s[%s] job is being served by another job, skipping it", cfg.Module(), cfg.Name())
+			m.saveStatus(cfg, jobStatusStoppedDupLocal)
+			m.Dyncfg.UpdateStatus(cfg, "error", "duplicate, served by another job")
+		} else if ok, err := m.FileLock.Lock(cfg.FullName()); ok || err != nil && !isTooManyOpenFiles(err) {
 			cleanupJob = false
 			m.runningJobs.put(cfg)
-			m.StatusSaver.Save(cfg, jobStatusRunning)
+			m.saveStatus(cfg, jobStatusRunning)
 			m.Dyncfg.UpdateStatus(cfg, "running", "")
 			m.startJob(job)
 		} else if isTooManyOpenFiles(err) {
 			m.Error(err)
-			m.StatusSaver.Save(cfg, jobStatusStoppedRegErr)
+			m.saveStatus(cfg, jobStatusStoppedRegErr)
 			m.Dyncfg.UpdateStatus(cfg, "error", "too many open files")
 		} else {
 			m.Infof("%s[%s] job is being served by another plugin, skipping it", cfg.Module(), cfg.Name())
-			m.StatusSaver.Save(cfg, jobStatusStoppedDupGlobal)
+			m.saveStatus(cfg, jobStatusStoppedDupGlobal)
 			m.Dyncfg.UpdateStatus(cfg, "error", "duplicate, served by another plugin")
 		}
 	case jobStatusRetrying:
-		m.Infof("%s[%s] job detection failed, will retry in %d seconds", cfg.Module(), cfg.Name(), job.AutoDetectionEvery())
+		delay := retryDelay(cfg, res.autoDetectEvery, res.attempt)
+		m.Infof("%s[%s] job detection failed, will retry in %s", cfg.Module(), cfg.Name(), delay.Round(time.Second))
 		ctx, cancel := context.WithCancel(ctx)
 		m.retryingJobs.put(cfg, retryTask{
 			cancel:  cancel,
-			timeout: job.AutoDetectionEvery(),
-			retries: job.AutoDetectTries,
+			timeout: res.autoDetectEvery,
+			retries: res.autoDetectTries,
+			attempt: res.attempt,
 		})
-		go runRetryTask(ctx, m.addCh, cfg, time.Second*time.Duration(job.AutoDetectionEvery()))
-		m.StatusSaver.Save(cfg, jobStatusRetrying)
-		m.Dyncfg.UpdateStatus(cfg, "error", "job detection failed, will retry later")
+		go runRetryTask(ctx, m.addCh, cfg, delay)
+		m.saveStatus(cfg, jobStatusRetrying)
+		m.Dyncfg.UpdateStatus(cfg, "error", fmt.Sprintf("job detection failed, will retry in %s (next attempt at %s)",
+			delay.Round(time.Second), time.Now().Add(delay).Format(time.RFC3339)))
 	case jobStatusStoppedFailed:
-		m.StatusSaver.Save(cfg, jobStatusStoppedFailed)
+		m.saveStatus(cfg, jobStatusStoppedFailed)
 		m.Dyncfg.UpdateStatus(cfg, "error", "job detection failed, stopping it")
 	default:
 		m.Warningf("%s[%s] job detection: unknown state", cfg.Module(), cfg.Name())
@@ -252,25 +361,70 @@ func (m *Manager) removeConfig(cfg confgroup.Config) {
 		m.retryingJobs.remove(cfg)
 	}
 
-	m.StatusSaver.Remove(cfg)
+	delete(m.detecting, cfg.Hash())
+	m.releaseVnode(cfg)
+	m.removeStatus(cfg)
+	m.removeJobState(cfg)
 	m.Dyncfg.Unregister(cfg)
 }
 
+// rescheduleConfig changes the data collection interval of the running job instead of restarting it.
+// The previous revision is removed and the new one is added if the job is not running.
+func (m *Manager) rescheduleConfig(ctx context.Context, rev configRevision) {
+	creator, ok := m.Modules[rev.cfg.Module()]
+	if !ok || !m.runningJobs.hasConfig(rev.prev) {
+		m.removeConfig(rev.prev)
+		m.addConfig(ctx, rev.cfg)
+		return
+	}
+
+	updateEvery, _, _ := m.schedulingOptions(rev.cfg, creator.Defaults)
+	if !m.setJobUpdateEvery(rev.cfg.FullName(), updateEvery) {
+		m.removeConfig(rev.prev)
+		m.addConfig(ctx, rev.cfg)
+		return
+	}
+
+	m.Infof("%s[%s] job data collection interval changed to %ds, rescheduling it", rev.cfg.Module(), rev.cfg.Name(), updateEvery)
+
+	if _, err := m.resolveVnode(rev.cfg); err != nil {
+		m.Warning(err)
+	}
+	m.releaseVnode(rev.prev)
+	m.runningJobs.put(rev.cfg)
+	m.removeStatus(rev.prev)
+	m.removeJobState(rev.prev)
+	m.saveStatus(rev.cfg, jobStatusRunning)
+	m.Dyncfg.Unregister(rev.prev)
+	m.Dyncfg.Register(rev.cfg)
+	m.Dyncfg.UpdateStatus(rev.cfg, "running", "")
+}
+
+func (m *Manager) saveStatus(cfg confgroup.Config, status jobStatus) {
+	m.jobs[cfg.Hash()] = &jobInfo{cfg: cfg, status: status}
+	m.StatusSaver.Save(cfg, status)
+}
+
+func (m *Manager) removeStatus(cfg confgroup.Config) {
+	delete(m.jobs, cfg.Hash())
+	m.StatusSaver.Remove(cfg)
+}
+
 func (m *Manager) createJob(cfg confgroup.Config) (*module.Job, error) {
 	creator, ok := m.Modules[cfg.Module()]
 	if !ok {
 		return nil, fmt.Errorf("can not find %s module", cfg.Module())
 	}
 
-	m.Debugf("creating %s[%s] job, config: %v", cfg.Module(), cfg.Name(), cfg)
+	m.Debugf("creating %s[%s] job, config: %v", cfg.Module(), cfg.Name(), redactSecrets(cfg))
 
-	mod := creator.Create()
-	if err := unmarshal(cfg, mod); err != nil {
+	mod, expanded, err := newModule(creator, cfg, m.StrictConfig)
+	if err != nil {
 		return nil, err
 	}
 
 	labels := make(map[string]string)
-	for name, value := range cfg.Labels() {
+	for name, value := range expanded.Labels() {
 		n, ok1 := name.(string)
 		v, ok2 := value.(string)
 		if ok1 && ok2 {
@@ -278,26 +432,38 @@ func (m *Manager) createJob(cfg confgroup.Config) (*module.Job, error) {
 		}
 	}
 
+	updateEvery, autoDetectEvery, priority := m.schedulingOptions(cfg, creator.Defaults)
+
 	jobCfg := module.JobConfig{
 		PluginName:      m.PluginName,
 		Name:            cfg.Name(),
 		ModuleName:      cfg.Module(),
 		FullName:        cfg.FullName(),
-		UpdateEvery:     cfg.UpdateEvery(),
-		AutoDetectEvery: cfg.AutoDetectionRetry(),
-		Priority:        cfg.Priority(),
+		UpdateEvery:     updateEvery,
+		AutoDetectEvery: autoDetectEvery,
+		Priority:        priority,
 		Labels:          labels,
 		IsStock:         isStockConfig(cfg),
 		Module:          mod,
 		Out:             m.Out,
+		ChartOverrides:  m.chartOverrides(expanded),
 	}
 
-	if cfg.Vnode() != "" {
-		n, ok := m.Vnodes.Lookup(cfg.Vnode())
-		if !ok {
-			return nil, fmt.Errorf("vnode '%s' is not found", cfg.Vnode())
+	if m.Stats != nil {
+		jobCfg.Observer = m.Stats
+	}
+	if m.JobStateStore != nil {
+		if state, ok := m.JobStateStore.Lookup(cfg); ok {
+			m.Debugf("%s[%s] job state found, restoring it", cfg.Module(), cfg.Name())
+			jobCfg.State = &state
 		}
+	}
 
+	n, err := m.resolveVnode(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if n != nil {
 		jobCfg.VnodeGUID = n.GUID
 		jobCfg.VnodeHostname = n.Hostname
 		jobCfg.VnodeLabels = n.Labels
@@ -308,6 +474,107 @@ func (m *Manager) createJob(cfg confgroup.Config) (*module.Job, error) {
 	return job, nil
 }
 
+// NewModule creates a module instance and applies the config to it.
+// Environment variables and '*_file' secrets in the config are expanded.
+// If strict is set, unknown options are an error unless the config sets 'strict_config: false'.
+func NewModule(creator module.Creator, cfg confgroup.Config, strict bool) (module.Module, error) {
+	mod, _, err := newModule(creator, cfg, strict)
+	return mod, err
+}
+
+func newModule(creator module.Creator, cfg confgroup.Config, strict bool) (module.Module, confgroup.Config, error) {
+	mod := creator.Create()
+
+	expanded, err := expandSecrets(cfg, moduleOptions(mod))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if isStrictConfig(expanded, strict) {
+		err = unmarshalStrict(expanded, mod)
+	} else {
+		err = unmarshal(expanded, mod)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid config (source '%s'): %v", cfg.Source(), joinedErrorString(err))
+	}
+
+	if v, ok := mod.(module.ConfigValidator); ok {
+		if err := v.ValidateConfig(); err != nil {
+			return nil, nil, fmt.Errorf("invalid config (source '%s'): %v", cfg.Source(), err)
+		}
+	}
+
+	return mod, expanded, nil
+}
+
+func (m *Manager) startStatsJob() {
+	job := module.NewJob(module.JobConfig{
+		PluginName:  m.PluginName,
+		Name:        statsJobName,
+		ModuleName:  statsJobName,
+		FullName:    statsJobName,
+		Module:      m.Stats,
+		UpdateEvery: module.UpdateEvery,
+		Priority:    module.Priority,
+		Out:         m.Out,
+	})
+
+	if !job.AutoDetection() {
+		m.Warning("plugin stats job detection failed, plugin statistics are disabled")
+		return
+	}
+
+	m.startJob(job)
+}
+
+// schedulingOptions returns 'update_every', 'autodetection_retry' and 'priority' of the job.
+// Configs from the file providers have the defaults applied, the dynamically composed (service discovery) ones
+// may not have the options set or have invalid values, the module defaults are used in that case.
+// They don't go through confgroup.Config.Apply, 'update_every' is clamped to the plugin minimum here.
+func (m *Manager) schedulingOptions(cfg confgroup.Config, def module.Defaults) (updateEvery, autoDetectEvery, priority int) {
+	updateEvery = m.schedulingOption(cfg, "update_every", 1, firstPositive(def.UpdateEvery, module.UpdateEvery))
+	if m.MinUpdateEvery > 0 && updateEvery < m.MinUpdateEvery {
+		updateEvery = m.MinUpdateEvery
+	}
+	autoDetectEvery = m.schedulingOption(cfg, "autodetection_retry", 0, firstPositive(def.AutoDetectionRetry, module.AutoDetectionRetry))
+	priority = m.schedulingOption(cfg, "priority", 1, firstPositive(def.Priority, module.Priority))
+	return updateEvery, autoDetectEvery, priority
+}
+
+func (m *Manager) schedulingOption(cfg confgroup.Config, key string, min, def int) int {
+	v, ok := cfg[key]
+	if !ok {
+		return def
+	}
+	if n, ok := parseSchedulingValue(v); ok && n >= min {
+		return n
+	}
+	m.Warningf("%s[%s] invalid '%s' value '%v' (source '%s'), using the module default (%d)",
+		cfg.Module(), cfg.Name(), key, v, cfg.Source(), def)
+	return def
+}
+
+// parseSchedulingValue converts the option value to int. The values taken from annotations and labels
+// are often numeric strings, YAML/JSON decoders may produce floats.
+func parseSchedulingValue(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case uint64:
+		return int(v), v <= math.MaxInt32
+	case float64:
+		return int(v), v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
 func detection(job Job) jobStatus {
 	if !job.AutoDetection() {
 		if job.RetryAutoDetection() {
@@ -336,6 +603,55 @@ func sendConfigs(ctx context.Context, out chan<- confgroup.Config, cfgs []confgr
 	}
 }
 
+// splitRevisions finds the added configs that replace the removed ones and differ from them only in 'update_every'.
+func splitRevisions(added, removed []confgroup.Config) (revs []configRevision, a, r []confgroup.Config) {
+	if len(added) == 0 || len(removed) == 0 {
+		return nil, added, removed
+	}
+
+	prev := make(map[string]confgroup.Config, len(removed))
+	for _, cfg := range removed {
+		prev[cfg.FullName()] = cfg
+	}
+
+	for _, cfg := range added {
+		p, ok := prev[cfg.FullName()]
+		if ok && withoutUpdateEvery(p).Hash() == withoutUpdateEvery(cfg).Hash() {
+			delete(prev, cfg.FullName())
+			revs = append(revs, configRevision{prev: p, cfg: cfg})
+			continue
+		}
+		a = append(a, cfg)
+	}
+	for _, cfg := range removed {
+		if _, ok := prev[cfg.FullName()]; ok {
+			r = append(r, cfg)
+		}
+	}
+
+	return revs, a, r
+}
+
+func withoutUpdateEvery(cfg confgroup.Config) confgroup.Config {
+	c := make(confgroup.Config, len(cfg))
+	for k, v := range cfg {
+		if k != "update_every" {
+			c[k] = v
+		}
+	}
+	return c
+}
+
+func sendRevisions(ctx context.Context, out chan<- configRevision, revs []configRevision) {
+	for _, rev := range revs {
+		select {
+		case <-ctx.Done():
+			return
+		case out <- rev:
+		}
+	}
+}
+
 func sendConfig(ctx context.Context, out chan<- confgroup.Config, cfg confgroup.Config) {
 	select {
 	case <-ctx.Done():
@@ -352,6 +668,13 @@ func unmarshal(conf interface{}, module interface{}) error {
 	return yaml.Unmarshal(bs, module)
 }
 
+func firstPositive(value int, others ...int) int {
+	if value > 0 || len(others) == 0 {
+		return value
+	}
+	return firstPositive(others[0], others[1:]...)
+}
+
 func isInsideK8sCluster() bool {
 	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
 	return host != "" && port != ""
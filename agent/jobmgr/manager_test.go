@@ -5,14 +5,19 @@ package jobmgr
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/confgroup"
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/agent/pluginstats"
 	"github.com/netdata/go.d.plugin/agent/safewriter"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TODO: tech dept
@@ -102,3 +107,279 @@ func prepareMockRegistry() module.Registry {
 	})
 	return reg
 }
+
+func TestManager_schedulingOptions(t *testing.T) {
+	def := module.Defaults{UpdateEvery: 10}
+
+	tests := map[string]struct {
+		cfg             confgroup.Config
+		minUpdateEvery  int
+		wantUpdateEvery int
+		wantAutoDetect  int
+		wantPriority    int
+	}{
+		"options not set": {
+			cfg:             confgroup.Config{"name": "name", "module": "success"},
+			wantUpdateEvery: 10,
+			wantAutoDetect:  module.AutoDetectionRetry,
+			wantPriority:    module.Priority,
+		},
+		"valid options": {
+			cfg:             confgroup.Config{"update_every": 5, "autodetection_retry": 0, "priority": 1000},
+			wantUpdateEvery: 5,
+			wantAutoDetect:  0,
+			wantPriority:    1000,
+		},
+		"invalid options": {
+			cfg:             confgroup.Config{"update_every": 0, "autodetection_retry": -1, "priority": -5},
+			wantUpdateEvery: 10,
+			wantAutoDetect:  module.AutoDetectionRetry,
+			wantPriority:    module.Priority,
+		},
+		"numeric strings and floats": {
+			cfg:             confgroup.Config{"update_every": "5", "autodetection_retry": " 30 ", "priority": 1000.0},
+			wantUpdateEvery: 5,
+			wantAutoDetect:  30,
+			wantPriority:    1000,
+		},
+		"options of wrong type": {
+			cfg:             confgroup.Config{"update_every": "5s", "autodetection_retry": "no", "priority": 1.5},
+			wantUpdateEvery: 10,
+			wantAutoDetect:  module.AutoDetectionRetry,
+			wantPriority:    module.Priority,
+		},
+		"update_every below the plugin minimum": {
+			cfg:             confgroup.Config{"update_every": 1},
+			minUpdateEvery:  3,
+			wantUpdateEvery: 3,
+			wantAutoDetect:  module.AutoDetectionRetry,
+			wantPriority:    module.Priority,
+		},
+		"default update_every below the plugin minimum": {
+			cfg:             confgroup.Config{},
+			minUpdateEvery:  15,
+			wantUpdateEvery: 15,
+			wantAutoDetect:  module.AutoDetectionRetry,
+			wantPriority:    module.Priority,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mgr := NewManager()
+			mgr.MinUpdateEvery = test.minUpdateEvery
+
+			updateEvery, autoDetect, priority := mgr.schedulingOptions(test.cfg, def)
+
+			assert.Equal(t, test.wantUpdateEvery, updateEvery, "update_every")
+			assert.Equal(t, test.wantAutoDetect, autoDetect, "autodetection_retry")
+			assert.Equal(t, test.wantPriority, priority, "priority")
+		})
+	}
+}
+
+func Test_splitRevisions(t *testing.T) {
+	cfg := func(name string, updateEvery int, kvs ...any) confgroup.Config {
+		c := confgroup.Config{"name": name, "module": "success", "update_every": updateEvery, "__source__": "sd:k8s:pod(default/httpd)"}
+		for i := 0; i+1 < len(kvs); i += 2 {
+			c[kvs[i].(string)] = kvs[i+1]
+		}
+		return c
+	}
+
+	tests := map[string]struct {
+		added, removed []confgroup.Config
+		wantRevs       []configRevision
+		wantAdded      []confgroup.Config
+		wantRemoved    []confgroup.Config
+	}{
+		"only added": {
+			added:     []confgroup.Config{cfg("job1", 1)},
+			wantAdded: []confgroup.Config{cfg("job1", 1)},
+		},
+		"only update_every changed": {
+			added:    []confgroup.Config{cfg("job1", 5), cfg("job2", 1)},
+			removed:  []confgroup.Config{cfg("job1", 1)},
+			wantRevs: []configRevision{{prev: cfg("job1", 1), cfg: cfg("job1", 5)}},
+			wantAdded: []confgroup.Config{
+				cfg("job2", 1),
+			},
+		},
+		"other options changed": {
+			added:       []confgroup.Config{cfg("job1", 5, "url", "http://127.0.0.1:8080")},
+			removed:     []confgroup.Config{cfg("job1", 1, "url", "http://127.0.0.1")},
+			wantAdded:   []confgroup.Config{cfg("job1", 5, "url", "http://127.0.0.1:8080")},
+			wantRemoved: []confgroup.Config{cfg("job1", 1, "url", "http://127.0.0.1")},
+		},
+		"priority changed": {
+			added:       []confgroup.Config{cfg("job1", 5, "priority", 1000)},
+			removed:     []confgroup.Config{cfg("job1", 1, "priority", 2000)},
+			wantAdded:   []confgroup.Config{cfg("job1", 5, "priority", 1000)},
+			wantRemoved: []confgroup.Config{cfg("job1", 1, "priority", 2000)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			revs, added, removed := splitRevisions(test.added, test.removed)
+
+			assert.Equal(t, test.wantRevs, revs, "revisions")
+			assert.Equal(t, test.wantAdded, added, "added")
+			assert.Equal(t, test.wantRemoved, removed, "removed")
+		})
+	}
+}
+
+func TestManager_rescheduleConfig(t *testing.T) {
+	var inits int
+	reg := module.Registry{}
+	reg.Register("success", module.Creator{
+		Create: func() module.Module {
+			return &module.MockModule{
+				InitFunc:  func() bool { inits++; return true },
+				CheckFunc: func() bool { return true },
+				ChartsFunc: func() *module.Charts {
+					return &module.Charts{
+						&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}},
+					}
+				},
+				CollectFunc: func() map[string]int64 { return map[string]int64{"id1": 1} },
+			}
+		},
+	})
+
+	// 'update_every' comes from the 'netdata.io/update_every' pod annotation via a compose template
+	prev := confgroup.Config{"name": "httpd", "module": "success", "update_every": 1, "__source__": "sd:k8s:pod(default/httpd)"}
+	cfg := confgroup.Config{"name": "httpd", "module": "success", "update_every": 3, "__source__": "sd:k8s:pod(default/httpd)"}
+
+	var buf lockedBuffer
+	mgr := NewManager()
+	mgr.Modules = reg
+	mgr.Out = &buf
+	mgr.PluginName = "test.plugin"
+	defer mgr.stopRunningJobs()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var clock int
+	waitChart := func(updateEvery int) bool {
+		line := fmt.Sprintf("CHART 'success_httpd.id' '' 'title' 'units' '' '' 'line' '70000' '%d'", updateEvery)
+		return assert.Eventuallyf(t, func() bool {
+			clock++
+			mgr.notifyRunningJobs(clock)
+			return strings.Contains(buf.String(), line)
+		}, time.Second*5, time.Millisecond*10, "the chart is not created with data collection interval %ds", updateEvery)
+	}
+
+	addConfigAndWait(ctx, mgr, prev)
+	require.Len(t, mgr.queue, 1)
+	require.True(t, waitChart(1))
+
+	mgr.rescheduleConfig(ctx, configRevision{prev: prev, cfg: cfg})
+
+	assert.Equal(t, 1, inits, "the job is not re-created")
+	assert.Len(t, mgr.queue, 1)
+	assert.True(t, mgr.runningJobs.hasConfig(cfg))
+	assert.True(t, waitChart(3))
+}
+
+// addConfigAndWait adds the config and handles its detection result the way the configs handling goroutine does.
+func addConfigAndWait(ctx context.Context, mgr *Manager, cfg confgroup.Config) {
+	mgr.addConfig(ctx, cfg)
+	if _, ok := mgr.detecting[cfg.Hash()]; ok {
+		mgr.handleDetection(ctx, <-mgr.detectedCh)
+	}
+}
+
+type lockedBuffer struct {
+	mux sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.buf.String()
+}
+
+func TestManager_addConfig_RetryBackoff(t *testing.T) {
+	cfg := confgroup.Config{"name": "name", "module": "unavailable", "autodetection_retry": 10}
+
+	dyncfg := &mockDyncfg{}
+	mgr := NewManager()
+	mgr.Modules = module.Registry{}
+	mgr.Modules.Register("unavailable", module.Creator{
+		Create: func() module.Module {
+			return &module.MockModule{
+				InitFunc:  func() bool { return true },
+				CheckFunc: func() bool { return false },
+			}
+		},
+	})
+	mgr.Dyncfg = dyncfg
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for attempt := 0; attempt < 3; attempt++ {
+		addConfigAndWait(ctx, mgr, cfg)
+
+		task, ok := mgr.retryingJobs.lookup(cfg)
+		require.Truef(t, ok, "attempt %d: job is not retrying", attempt)
+		assert.Equalf(t, attempt, task.attempt, "attempt %d", attempt)
+		assert.Equalf(t, 10, task.timeout, "attempt %d: base interval", attempt)
+	}
+
+	// 40s (10s doubled twice) plus up to 10% jitter
+	assert.Regexp(t, `will retry in 4[0-4]s \(next attempt at \S+\)$`, dyncfg.payload)
+}
+
+type mockDyncfg struct {
+	noop
+	payload string
+}
+
+func (m *mockDyncfg) UpdateStatus(_ confgroup.Config, _, payload string) { m.payload = payload }
+
+func TestManager_Stats(t *testing.T) {
+	reg := module.Registry{}
+	reg.Register("success", module.Creator{
+		Create: func() module.Module {
+			return &module.MockModule{
+				ChartsFunc: func() *module.Charts {
+					return &module.Charts{
+						&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}},
+					}
+				},
+				CollectFunc: func() map[string]int64 { return map[string]int64{"id1": 1} },
+			}
+		},
+	})
+
+	var buf lockedBuffer
+	mgr := NewManager()
+	mgr.Modules = reg
+	mgr.Out = &buf
+	mgr.PluginName = "test.plugin"
+	mgr.Stats = pluginstats.New(pluginstats.Config{})
+	defer mgr.stopRunningJobs()
+
+	mgr.startStatsJob()
+	addConfigAndWait(context.Background(), mgr, confgroup.Config{"name": "httpd", "module": "success", "update_every": 1})
+	require.Len(t, mgr.queue, 2)
+
+	var clock int
+	assert.Eventually(t, func() bool {
+		clock++
+		mgr.notifyRunningJobs(clock)
+		return strings.Contains(buf.String(), "CHART 'plugin_stats.module_success_successful_collections'")
+	}, time.Second*5, time.Millisecond*10, "the job runs are not reported by the stats job")
+	assert.Contains(t, buf.String(), "DIMENSION 'httpd' 'httpd' 'incremental'")
+}
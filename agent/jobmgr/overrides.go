@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"gopkg.in/yaml.v2"
+)
+
+// chartOverrides returns the valid 'chart_overrides' of the job config, the invalid ones are logged and ignored.
+func (m *Manager) chartOverrides(cfg confgroup.Config) []module.ChartOverride {
+	v, ok := cfg["chart_overrides"]
+	if !ok || v == nil {
+		return nil
+	}
+
+	var overrides []module.ChartOverride
+	bs, err := yaml.Marshal(v)
+	if err == nil {
+		err = yaml.Unmarshal(bs, &overrides)
+	}
+	if err != nil {
+		m.Warningf("%s[%s] job: 'chart_overrides': %v, ignoring them", cfg.Module(), cfg.Name(), err)
+		return nil
+	}
+
+	valid := overrides[:0]
+	for i, o := range overrides {
+		if err := o.Validate(); err != nil {
+			m.Warningf("%s[%s] job: 'chart_overrides[%d]': %v, ignoring it", cfg.Module(), cfg.Name(), i, err)
+			continue
+		}
+		valid = append(valid, o)
+	}
+	return valid
+}
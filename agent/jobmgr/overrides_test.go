@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"testing"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_chartOverrides(t *testing.T) {
+	hidden := true
+
+	tests := map[string]struct {
+		cfg  confgroup.Config
+		want []module.ChartOverride
+	}{
+		"not set": {
+			cfg: confgroup.Config{"module": "module", "name": "job"},
+		},
+		"valid overrides": {
+			cfg: confgroup.Config{"module": "module", "name": "job", "chart_overrides": []any{
+				map[any]any{"chart": "requests", "algorithm": "incremental", "multiplier": 8},
+				map[any]any{"chart": "connections*", "dimension": "waiting", "hidden": true},
+			}},
+			want: []module.ChartOverride{
+				{Chart: "requests", Algorithm: "incremental", Multiplier: 8},
+				{Chart: "connections*", Dimension: "waiting", Hidden: &hidden},
+			},
+		},
+		"invalid overrides are ignored": {
+			cfg: confgroup.Config{"module": "module", "name": "job", "chart_overrides": []any{
+				map[any]any{"chart": "requests", "algorithm": "derivative"},
+				map[any]any{"dimension": "waiting", "hidden": true},
+				map[any]any{"chart": "requests", "divisor": 1000},
+			}},
+			want: []module.ChartOverride{
+				{Chart: "requests", Divisor: 1000},
+			},
+		},
+		"not a list": {
+			cfg: confgroup.Config{"module": "module", "name": "job", "chart_overrides": "requests"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mgr := NewManager()
+
+			assert.Equal(t, test.want, mgr.chartOverrides(test.cfg))
+		})
+	}
+}
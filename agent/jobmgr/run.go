@@ -61,6 +61,21 @@ func (m *Manager) stopJob(name string) {
 	}
 }
 
+func (m *Manager) setJobUpdateEvery(name string, updateEvery int) bool {
+	m.queueMux.Lock()
+	defer m.queueMux.Unlock()
+
+	idx := slices.IndexFunc(m.queue, func(job Job) bool {
+		return job.FullName() == name
+	})
+	if idx == -1 {
+		return false
+	}
+
+	m.queue[idx].SetUpdateEvery(updateEvery)
+	return true
+}
+
 func (m *Manager) stopRunningJobs() {
 	m.queueMux.Lock()
 	defer m.queueMux.Unlock()
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+)
+
+const fileKeySuffix = "_file"
+
+var reEnvVar = regexp.MustCompile(`\$\$\{|\$\{([a-zA-Z_][a-zA-Z0-9_]*)}`)
+
+// expandSecrets returns a copy of the config with the secrets resolved:
+//   - '${NAME}' in string values is replaced with the value of the NAME environment variable ('$${' is a literal '${').
+//   - 'KEY_file: <path>' is read and set as 'KEY: <file content>', except for the keys that are the module options
+//     (e.g. 'cookie_file'), the modules read these files themselves.
+//
+// A missing environment variable or an unreadable file is an error, the job is not created.
+func expandSecrets(cfg confgroup.Config, moduleOptions map[string]bool) (confgroup.Config, error) {
+	expanded := make(confgroup.Config, len(cfg))
+
+	for key, value := range cfg {
+		v, err := expandEnv(value)
+		if err != nil {
+			return nil, fmt.Errorf("option '%s': %v", key, err)
+		}
+		expanded[key] = v
+	}
+
+	var fileKeys []string
+	for key := range expanded {
+		if strings.HasSuffix(key, fileKeySuffix) && key != fileKeySuffix && !moduleOptions[key] {
+			fileKeys = append(fileKeys, key)
+		}
+	}
+	sort.Strings(fileKeys)
+
+	for _, fileKey := range fileKeys {
+		key := strings.TrimSuffix(fileKey, fileKeySuffix)
+		path, ok := expanded[fileKey].(string)
+		if !ok || path == "" {
+			return nil, fmt.Errorf("option '%s': expected a file path, got '%v'", fileKey, expanded[fileKey])
+		}
+		if _, ok := expanded[key]; ok {
+			return nil, fmt.Errorf("both '%s' and '%s' options are set", key, fileKey)
+		}
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("option '%s': %v", fileKey, err)
+		}
+		expanded[key] = strings.TrimRight(string(bs), "\r\n")
+		delete(expanded, fileKey)
+	}
+
+	return expanded, nil
+}
+
+func expandEnv(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return expandEnvString(v)
+	case map[any]any:
+		m := make(map[any]any, len(v))
+		for key, value := range v {
+			ev, err := expandEnv(value)
+			if err != nil {
+				return nil, fmt.Errorf("'%v': %v", key, err)
+			}
+			m[key] = ev
+		}
+		return m, nil
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, value := range v {
+			ev, err := expandEnv(value)
+			if err != nil {
+				return nil, fmt.Errorf("'%s': %v", key, err)
+			}
+			m[key] = ev
+		}
+		return m, nil
+	case []any:
+		s := make([]any, len(v))
+		for i, value := range v {
+			ev, err := expandEnv(value)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %v", i, err)
+			}
+			s[i] = ev
+		}
+		return s, nil
+	default:
+		return value, nil
+	}
+}
+
+func expandEnvString(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var errs []error
+	s = reEnvVar.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$${" {
+			return "${"
+		}
+		name := ref[2 : len(ref)-1]
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("environment variable '%s' is not set", name))
+		}
+		return v
+	})
+
+	return s, errors.Join(errs...)
+}
+
+// redactSecrets returns a copy of the config that is safe to log: the values of the options
+// which names contain 'password', 'token' or 'secret' are replaced.
+func redactSecrets(cfg confgroup.Config) confgroup.Config {
+	redacted := make(confgroup.Config, len(cfg))
+	for key, value := range cfg {
+		redacted[key] = redactValue(key, value)
+	}
+	return redacted
+}
+
+func redactValue(key string, value any) any {
+	if isSecretKey(key) {
+		return "<redacted>"
+	}
+
+	switch v := value.(type) {
+	case map[any]any:
+		m := make(map[any]any, len(v))
+		for k, value := range v {
+			m[k] = redactValue(fmt.Sprint(k), value)
+		}
+		return m
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, value := range v {
+			m[k] = redactValue(k, value)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, value := range v {
+			s[i] = redactValue("", value)
+		}
+		return s
+	default:
+		return value
+	}
+}
+
+func isSecretKey(key string) bool {
+	// the file paths are not secrets
+	if strings.HasSuffix(key, fileKeySuffix) {
+		return false
+	}
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "token") || strings.Contains(key, "secret")
+}
+
+// moduleOptions returns the top level config options of the module (the yaml tags of its fields).
+func moduleOptions(mod any) map[string]bool {
+	opts := make(map[string]bool)
+	collectYAMLKeys(reflect.TypeOf(mod), opts)
+	return opts
+}
+
+func collectYAMLKeys(typ reflect.Type, keys map[string]bool) {
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup("yaml")
+		if !ok {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(opts, "inline") {
+			collectYAMLKeys(field.Type, keys)
+			continue
+		}
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+}
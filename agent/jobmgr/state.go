@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+)
+
+type statefulJob interface {
+	State() module.JobState
+}
+
+// saveJobStates saves the states of the running jobs.
+func (m *Manager) saveJobStates() {
+	if m.JobStateSaver == nil {
+		return
+	}
+
+	m.queueMux.Lock()
+	jobs := make(map[string]statefulJob, len(m.queue))
+	for _, job := range m.queue {
+		if v, ok := job.(statefulJob); ok {
+			jobs[job.FullName()] = v
+		}
+	}
+	m.queueMux.Unlock()
+
+	for _, info := range m.jobs {
+		if info.status != jobStatusRunning || !m.runningJobs.hasConfig(info.cfg) {
+			continue
+		}
+		if job, ok := jobs[info.cfg.FullName()]; ok {
+			m.JobStateSaver.Save(info.cfg, job.State())
+		}
+	}
+}
+
+func (m *Manager) removeJobState(cfg confgroup.Config) {
+	if m.JobStateSaver != nil {
+		m.JobStateSaver.Remove(cfg)
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_JobStates(t *testing.T) {
+	cfg := confgroup.Config{"name": "name", "module": "success", "update_every": 1}
+	states := &mockJobStates{
+		states: map[uint64]module.JobState{cfg.Hash(): {Failures: 2}},
+	}
+
+	mgr := NewManager()
+	mgr.Modules = prepareMockRegistry()
+	mgr.JobStateSaver = states
+	mgr.JobStateStore = states
+	defer mgr.stopRunningJobs()
+
+	addConfigAndWait(context.Background(), mgr, cfg)
+	require.Len(t, mgr.queue, 1)
+
+	mgr.saveJobStates()
+	assert.Equal(t, 2, states.states[cfg.Hash()].Failures, "the saved state is not restored")
+
+	var clock int
+	assert.Eventually(t, func() bool {
+		clock++
+		mgr.notifyRunningJobs(clock)
+		mgr.saveJobStates()
+		return !states.states[cfg.Hash()].LastSuccess.IsZero()
+	}, time.Second*5, time.Millisecond*10, "the job state is not saved")
+	assert.Zero(t, states.states[cfg.Hash()].Failures)
+
+	mgr.removeConfig(cfg)
+	assert.NotContains(t, states.states, cfg.Hash())
+}
+
+type mockJobStates struct {
+	states map[uint64]module.JobState
+}
+
+func (m *mockJobStates) Save(cfg confgroup.Config, state module.JobState) {
+	m.states[cfg.Hash()] = state
+}
+
+func (m *mockJobStates) Remove(cfg confgroup.Config) { delete(m.states, cfg.Hash()) }
+
+func (m *mockJobStates) Lookup(cfg confgroup.Config) (module.JobState, bool) {
+	v, ok := m.states[cfg.Hash()]
+	return v, ok
+}
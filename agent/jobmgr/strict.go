@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+
+	"gopkg.in/yaml.v2"
+)
+
+// frameworkOptions are the job config options handled by the plugin, they are not module options.
+var frameworkOptions = map[string]bool{
+	"name":                            true,
+	"module":                          true,
+	"update_every":                    true,
+	"autodetection_retry":             true,
+	"autodetection_retry_backoff":     true,
+	"autodetection_retry_backoff_max": true,
+	"priority":                        true,
+	"labels":                          true,
+	"vnode":                           true,
+	"vnode_guid":                      true,
+	"vnode_labels":                    true,
+	"strict_config":                   true,
+	"chart_overrides":                 true,
+}
+
+var (
+	reFieldNotFound = regexp.MustCompile(`^line \d+: field (.+) not found in type .+$`)
+	reTypeMismatch  = regexp.MustCompile("^line \\d+: cannot unmarshal !!(\\w+) `(.*)` into (.+)$")
+	reLinePrefix    = regexp.MustCompile(`^line \d+: `)
+)
+
+// isStrictConfig reports whether the config is decoded strictly, the 'strict_config' job option overrides the default.
+func isStrictConfig(cfg confgroup.Config, def bool) bool {
+	if v, ok := cfg["strict_config"].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// unmarshalStrict decodes the config into the module, unlike unmarshal it fails on the options
+// the module doesn't have and reports every invalid option.
+func unmarshalStrict(cfg confgroup.Config, mod any) error {
+	opts := moduleOptions(mod)
+	framework := make(map[string]any)
+	var keys []string
+
+	for key, value := range cfg {
+		switch {
+		case strings.HasPrefix(key, "__"):
+		case frameworkOptions[key] && !opts[key]:
+			framework[key] = value
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	if err := unmarshal(framework, mod); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, key := range keys {
+		bs, err := yaml.Marshal(map[string]any{key: cfg[key]})
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(bs, mod)
+
+		var typeErr *yaml.TypeError
+		switch {
+		case err == nil:
+		case errors.As(err, &typeErr):
+			for _, msg := range typeErr.Errors {
+				errs = append(errs, optionError(key, msg, opts))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("option '%s': %v", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func optionError(key, msg string, opts map[string]bool) error {
+	if m := reFieldNotFound.FindStringSubmatch(msg); m != nil {
+		if m[1] != key {
+			return fmt.Errorf("option '%s': unknown field '%s'", key, m[1])
+		}
+		if s := closestOption(key, opts); s != "" {
+			return fmt.Errorf("unknown option '%s' (did you mean '%s'?)", key, s)
+		}
+		return fmt.Errorf("unknown option '%s'", key)
+	}
+	if m := reTypeMismatch.FindStringSubmatch(msg); m != nil {
+		return fmt.Errorf("option '%s': expected %s, got %s '%s'", key, m[3], yamlTagName(m[1]), m[2])
+	}
+	return fmt.Errorf("option '%s': %s", key, reLinePrefix.ReplaceAllString(msg, ""))
+}
+
+func yamlTagName(tag string) string {
+	switch tag {
+	case "str":
+		return "string"
+	case "int":
+		return "integer"
+	case "bool":
+		return "boolean"
+	case "seq":
+		return "list"
+	default:
+		return tag
+	}
+}
+
+// closestOption returns the module option that is the most similar to the unknown one, if any.
+func closestOption(key string, opts map[string]bool) string {
+	var closest string
+	best := 3 // a suggestion is useful only for typos
+
+	names := make([]string, 0, len(opts))
+	for name := range opts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if d := levenshtein(key, name); d < best {
+			closest, best = name, d
+		}
+	}
+	return closest
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// joinedErrorString formats the errors joined by errors.Join in a single line.
+func joinedErrorString(err error) string {
+	v, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return err.Error()
+	}
+
+	var msgs []string
+	for _, e := range v.Unwrap() {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/modules/example"
+	"github.com/netdata/go.d.plugin/modules/nginx"
+	"github.com/netdata/go.d.plugin/modules/portcheck"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewModule_StrictConfig(t *testing.T) {
+	creators := map[string]module.Creator{
+		"example":   {Create: func() module.Module { return example.New() }},
+		"nginx":     {Create: func() module.Module { return nginx.New() }},
+		"portcheck": {Create: func() module.Module { return portcheck.New() }},
+	}
+
+	tests := map[string]struct {
+		cfg     confgroup.Config
+		strict  bool
+		wantErr string
+		check   func(t *testing.T, mod module.Module)
+	}{
+		"example: valid config": {
+			strict: true,
+			cfg:    confgroup.Config{"module": "example", "name": "job", "update_every": 1, "charts": map[any]any{"num": 2}},
+			check: func(t *testing.T, mod module.Module) {
+				assert.Equal(t, 2, mod.(*example.Example).Config.Charts.Num)
+			},
+		},
+		"example: unknown nested field": {
+			strict:  true,
+			cfg:     confgroup.Config{"module": "example", "name": "job", "charts": map[any]any{"numm": 2}},
+			wantErr: "option 'charts': unknown field 'numm'",
+		},
+		"nginx: valid config": {
+			strict: true,
+			cfg:    confgroup.Config{"module": "nginx", "name": "job", "url": "http://127.0.0.1/status", "timeout": 2},
+			check: func(t *testing.T, mod module.Module) {
+				assert.Equal(t, "http://127.0.0.1/status", mod.(*nginx.Nginx).URL)
+			},
+		},
+		"nginx: unknown option with a suggestion": {
+			strict:  true,
+			cfg:     confgroup.Config{"module": "nginx", "name": "job", "urll": "http://127.0.0.1/status"},
+			wantErr: "unknown option 'urll' (did you mean 'url'?)",
+		},
+		"nginx: unknown option without a suggestion": {
+			strict:  true,
+			cfg:     confgroup.Config{"module": "nginx", "name": "job", "collect_everything": true},
+			wantErr: "unknown option 'collect_everything'",
+		},
+		"nginx: unknown option, strict mode is disabled": {
+			cfg: confgroup.Config{"module": "nginx", "name": "job", "urll": "http://127.0.0.1/status"},
+		},
+		"nginx: unknown option, strict mode is disabled in the job config": {
+			strict: true,
+			cfg:    confgroup.Config{"module": "nginx", "name": "job", "urll": "http://127.0.0.1/status", "strict_config": false},
+		},
+		"nginx: invalid duration": {
+			strict:  true,
+			cfg:     confgroup.Config{"module": "nginx", "name": "job", "timeout": "2 seconds"},
+			wantErr: "option 'timeout': unparsable duration format '2 seconds'",
+		},
+		"portcheck: valid config": {
+			strict: true,
+			cfg:    confgroup.Config{"module": "portcheck", "name": "job", "update_every": 5, "host": "127.0.0.1", "ports": []any{22, 80}},
+			check: func(t *testing.T, mod module.Module) {
+				pc := mod.(*portcheck.PortCheck)
+				assert.Equal(t, []portcheck.PortConfig{{Port: 22}, {Port: 80}}, pc.Ports)
+				assert.Equal(t, 5, pc.UpdateEvery)
+			},
+		},
+		"portcheck: type mismatch": {
+			strict:  true,
+			cfg:     confgroup.Config{"module": "portcheck", "name": "job", "host": "127.0.0.1", "ports": "22"},
+			wantErr: "option 'ports': expected []portcheck.PortConfig, got string '22'",
+		},
+		"portcheck: several invalid options": {
+			strict:  true,
+			cfg:     confgroup.Config{"module": "portcheck", "name": "job", "hots": "127.0.0.1", "ports": []any{"ssh"}},
+			wantErr: "unknown option 'hots' (did you mean 'host'?); option 'ports': port must be a number or an object with 'port'",
+		},
+		"portcheck: invalid port object": {
+			strict:  true,
+			cfg:     confgroup.Config{"module": "portcheck", "name": "job", "host": "127.0.0.1", "ports": []any{map[any]any{"port": 22, "sned": "PING"}}},
+			wantErr: "option 'ports': unknown field 'sned'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.cfg.SetSource("/etc/netdata/go.d/" + test.cfg.Module() + ".conf")
+
+			mod, err := NewModule(creators[test.cfg.Module()], test.cfg, test.strict)
+
+			if test.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+				assert.Contains(t, err.Error(), test.cfg.Source())
+				return
+			}
+
+			require.NoError(t, err)
+			if test.check != nil {
+				test.check(t, mod)
+			}
+		})
+	}
+}
+
+func TestNewModule_ValidateConfig(t *testing.T) {
+	creator := module.Creator{Create: func() module.Module {
+		return &mockValidatedModule{}
+	}}
+
+	_, err := NewModule(creator, confgroup.Config{"module": "mock", "name": "job"}, true)
+	assert.ErrorContains(t, err, "'url' is required")
+
+	_, err = NewModule(creator, confgroup.Config{"module": "mock", "name": "job", "url": "http://127.0.0.1"}, true)
+	assert.NoError(t, err)
+}
+
+type mockValidatedModule struct {
+	module.MockModule `yaml:"-"`
+	URL               string `yaml:"url"`
+}
+
+func (m *mockValidatedModule) ValidateConfig() error {
+	if m.URL == "" {
+		return errors.New("'url' is required")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/vnodes"
+
+	"github.com/google/uuid"
+)
+
+// ephemeralVnode is a virtual node defined by service discovery configs, it lives while there are jobs using it.
+type ephemeralVnode struct {
+	node *vnodes.VirtualNode
+	jobs map[uint64]bool // [cfgHash]
+}
+
+// resolveVnode returns the virtual node the job reports to, or nil if the job reports to the host.
+// A vnode from the 'vnodes/' configuration directory is used if it exists. Service discovery configs
+// may reference a vnode that is not there, it is registered on first use with the 'vnode_guid' and 'vnode_labels'
+// from the config (the GUID is derived from the hostname if not set) and shared by all the jobs that reference it.
+func (m *Manager) resolveVnode(cfg confgroup.Config) (*vnodes.VirtualNode, error) {
+	hostname := cfg.Vnode()
+	if hostname == "" {
+		return nil, nil
+	}
+
+	if n, ok := m.Vnodes.Lookup(hostname); ok {
+		return n, nil
+	}
+
+	if !isDiscoveredConfig(cfg) {
+		return nil, fmt.Errorf("vnode '%s' is not found", hostname)
+	}
+
+	guid, err := vnodeGUID(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := m.ephemeralVnodes[hostname]
+	if !ok {
+		v = &ephemeralVnode{
+			node: &vnodes.VirtualNode{GUID: guid, Hostname: hostname, Labels: vnodeLabels(cfg)},
+			jobs: make(map[uint64]bool),
+		}
+		m.Infof("registering vnode '%s' (guid '%s', source '%s')", hostname, guid, cfg.Source())
+		m.ephemeralVnodes[hostname] = v
+	} else if v.node.GUID != guid {
+		return nil, fmt.Errorf("vnode '%s' is already registered with guid '%s'", hostname, v.node.GUID)
+	}
+
+	v.jobs[cfg.Hash()] = true
+
+	return v.node, nil
+}
+
+// releaseVnode detaches the config from its ephemeral vnode and retires the vnode if it was the last job using it.
+func (m *Manager) releaseVnode(cfg confgroup.Config) {
+	v, ok := m.ephemeralVnodes[cfg.Vnode()]
+	if !ok {
+		return
+	}
+
+	delete(v.jobs, cfg.Hash())
+
+	if len(v.jobs) == 0 {
+		m.Infof("retiring vnode '%s' (guid '%s'): no jobs left", v.node.Hostname, v.node.GUID)
+		delete(m.ephemeralVnodes, v.node.Hostname)
+	}
+}
+
+func vnodeGUID(cfg confgroup.Config) (string, error) {
+	if guid := cfg.VnodeGUID(); guid != "" {
+		v, err := uuid.Parse(guid)
+		if err != nil {
+			return "", fmt.Errorf("invalid vnode guid '%s': %v", guid, err)
+		}
+		return v.String(), nil
+	}
+	// the same hostname results in the same node after the target is rediscovered or the plugin is restarted
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("go.d.plugin/vnode/"+cfg.Vnode())).String(), nil
+}
+
+func vnodeLabels(cfg confgroup.Config) map[string]string {
+	labels := make(map[string]string)
+	for name, value := range cfg.VnodeLabels() {
+		n, ok1 := name.(string)
+		v, ok2 := value.(string)
+		if ok1 && ok2 {
+			labels[n] = v
+		}
+	}
+	return labels
+}
+
+func isDiscoveredConfig(cfg confgroup.Config) bool {
+	return strings.HasPrefix(cfg.Provider(), "sd:")
+}
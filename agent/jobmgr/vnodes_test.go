@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package jobmgr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/confgroup"
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/agent/vnodes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_resolveVnode(t *testing.T) {
+	const guid = "a8e3d9b0-2e6f-4a6e-9c1f-0d8f6b2c7e11"
+
+	tests := map[string]struct {
+		registered map[string]*ephemeralVnode
+		cfg        confgroup.Config
+		wantNode   *vnodes.VirtualNode
+		wantErr    bool
+	}{
+		"no vnode": {
+			cfg: confgroup.Config{"name": "local", "module": "snmp"},
+		},
+		"static vnode": {
+			cfg:      confgroup.Config{"name": "local", "module": "snmp", "vnode": "static", "__provider__": "sd:snmp"},
+			wantNode: &vnodes.VirtualNode{GUID: "static-guid", Hostname: "static"},
+		},
+		"unknown vnode from a file config": {
+			cfg:     confgroup.Config{"name": "local", "module": "snmp", "vnode": "switch", "__provider__": "file reader"},
+			wantErr: true,
+		},
+		"ephemeral vnode with guid and labels": {
+			cfg: confgroup.Config{"name": "local", "module": "snmp", "vnode": "switch", "vnode_guid": guid,
+				"vnode_labels": map[any]any{"type": "switch"}, "__provider__": "sd:snmp"},
+			wantNode: &vnodes.VirtualNode{GUID: guid, Hostname: "switch", Labels: map[string]string{"type": "switch"}},
+		},
+		"ephemeral vnode without guid": {
+			cfg: confgroup.Config{"name": "local", "module": "snmp", "vnode": "switch", "__provider__": "sd:snmp"},
+			wantNode: &vnodes.VirtualNode{GUID: "d7a3780a-7dfc-585b-9f48-2f97581b91c8", Hostname: "switch",
+				Labels: map[string]string{}},
+		},
+		"ephemeral vnode invalid guid": {
+			cfg:     confgroup.Config{"name": "local", "module": "snmp", "vnode": "switch", "vnode_guid": "123", "__provider__": "sd:snmp"},
+			wantErr: true,
+		},
+		"ephemeral vnode registered with another guid": {
+			registered: map[string]*ephemeralVnode{
+				"switch": {node: &vnodes.VirtualNode{GUID: guid, Hostname: "switch"}, jobs: map[uint64]bool{1: true}},
+			},
+			cfg:     confgroup.Config{"name": "local", "module": "snmp", "vnode": "switch", "__provider__": "sd:snmp"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mgr := NewManager()
+			mgr.Vnodes = mockVnodes{"static": {GUID: "static-guid", Hostname: "static"}}
+			if test.registered != nil {
+				mgr.ephemeralVnodes = test.registered
+			}
+
+			node, err := mgr.resolveVnode(test.cfg)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantNode, node)
+		})
+	}
+}
+
+func TestManager_ephemeralVnodes(t *testing.T) {
+	reg := module.Registry{}
+	reg.Register("snmp", module.Creator{
+		Create: func() module.Module {
+			return &module.MockModule{
+				ChartsFunc: func() *module.Charts {
+					return &module.Charts{
+						&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}},
+					}
+				},
+				CollectFunc: func() map[string]int64 { return map[string]int64{"id1": 1} },
+			}
+		},
+	})
+
+	var buf lockedBuffer
+	mgr := NewManager()
+	mgr.Modules = reg
+	mgr.Out = &buf
+	mgr.PluginName = "test.plugin"
+	defer mgr.stopRunningJobs()
+
+	// two jobs of the same device (e.g. a device and its interfaces)
+	device := confgroup.Config{"name": "switch", "module": "snmp", "update_every": 1, "vnode": "switch",
+		"__provider__": "sd:snmp", "__source__": "sd:snmp(10.0.0.1)"}
+	ifaces := confgroup.Config{"name": "switch_ifaces", "module": "snmp", "update_every": 1, "vnode": "switch",
+		"__provider__": "sd:snmp", "__source__": "sd:snmp(10.0.0.1)"}
+
+	ctx := context.Background()
+	addConfigAndWait(ctx, mgr, device)
+	addConfigAndWait(ctx, mgr, ifaces)
+
+	require.Len(t, mgr.queue, 2)
+	require.Contains(t, mgr.ephemeralVnodes, "switch")
+	node := mgr.ephemeralVnodes["switch"].node
+	assert.Len(t, mgr.ephemeralVnodes["switch"].jobs, 2, "the vnode is reused")
+
+	var clock int
+	assert.Eventually(t, func() bool {
+		clock++
+		mgr.notifyRunningJobs(clock)
+		return strings.Count(buf.String(), "HOST_DEFINE '"+node.GUID+"' 'switch'") == 2
+	}, time.Second*5, time.Millisecond*10, "both jobs report to the vnode")
+
+	mgr.removeConfig(device)
+	assert.Contains(t, mgr.ephemeralVnodes, "switch", "the vnode is used by the ifaces job")
+
+	mgr.removeConfig(ifaces)
+	assert.NotContains(t, mgr.ephemeralVnodes, "switch", "the vnode is retired")
+	assert.Empty(t, mgr.queue)
+
+	// the device is rediscovered
+	addConfigAndWait(ctx, mgr, device)
+	require.Contains(t, mgr.ephemeralVnodes, "switch")
+	assert.Equal(t, node.GUID, mgr.ephemeralVnodes["switch"].node.GUID, "the vnode gets the same guid")
+}
+
+type mockVnodes map[string]*vnodes.VirtualNode
+
+func (m mockVnodes) Lookup(key string) (*vnodes.VirtualNode, bool) { v, ok := m[key]; return v, ok }
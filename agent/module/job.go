@@ -70,6 +70,11 @@ type JobConfig struct {
 	AutoDetectEvery int
 	Priority        int
 	IsStock         bool
+	Observer        RunObserver
+	// State is the job state saved before the plugin restart, it is restored before Init.
+	State *JobState
+	// ChartOverrides are applied to the module charts dimensions.
+	ChartOverrides []ChartOverride
 
 	VnodeGUID     string
 	VnodeHostname string
@@ -89,21 +94,25 @@ func NewJob(cfg JobConfig) *Job {
 		AutoDetectEvery: cfg.AutoDetectEvery,
 		AutoDetectTries: infTries,
 
-		pluginName:  cfg.PluginName,
-		name:        cfg.Name,
-		moduleName:  cfg.ModuleName,
-		fullName:    cfg.FullName,
-		updateEvery: cfg.UpdateEvery,
-		priority:    cfg.Priority,
-		isStock:     cfg.IsStock,
-		module:      cfg.Module,
-		labels:      cfg.Labels,
-		out:         cfg.Out,
-		runChart:    newRuntimeChart(cfg.PluginName),
-		stop:        make(chan struct{}),
-		tick:        make(chan int),
-		buf:         &buf,
-		api:         netdataapi.New(&buf),
+		pluginName:    cfg.PluginName,
+		name:          cfg.Name,
+		moduleName:    cfg.ModuleName,
+		fullName:      cfg.FullName,
+		updateEvery:   cfg.UpdateEvery,
+		priority:      cfg.Priority,
+		isStock:       cfg.IsStock,
+		module:        cfg.Module,
+		labels:        cfg.Labels,
+		out:           cfg.Out,
+		observer:      cfg.Observer,
+		savedState:    cfg.State,
+		overrides:     cfg.ChartOverrides,
+		runChart:      newRuntimeChart(cfg.PluginName),
+		stop:          make(chan struct{}),
+		tick:          make(chan int),
+		updateEveryCh: make(chan int, 1),
+		buf:           &buf,
+		api:           netdataapi.New(&buf),
 
 		vnodeGUID:     cfg.VnodeGUID,
 		vnodeHostname: cfg.VnodeHostname,
@@ -148,13 +157,23 @@ type Job struct {
 	runChart *Chart
 	charts   *Charts
 	tick     chan int
-	out      io.Writer
-	buf      *bytes.Buffer
-	api      *netdataapi.API
+	// the new data collection interval of the running job, applied by the main loop
+	updateEveryCh chan int
+	out           io.Writer
+	buf           *bytes.Buffer
+	api           *netdataapi.API
+	observer      RunObserver
 
 	retries int
 	prevRun time.Time
 
+	savedState *JobState
+	stateMux   sync.Mutex
+	state      JobState
+
+	overrides        []ChartOverride
+	overridesChecked bool
+
 	stop chan struct{}
 
 	vnodeCreated  bool
@@ -167,32 +186,32 @@ type Job struct {
 const NetdataChartIDMaxLength = 1200
 
 // FullName returns job full name.
-func (j Job) FullName() string {
+func (j *Job) FullName() string {
 	return j.fullName
 }
 
 // ModuleName returns job module name.
-func (j Job) ModuleName() string {
+func (j *Job) ModuleName() string {
 	return j.moduleName
 }
 
 // Name returns job name.
-func (j Job) Name() string {
+func (j *Job) Name() string {
 	return j.name
 }
 
 // Panicked returns 'panicked' flag value.
-func (j Job) Panicked() bool {
+func (j *Job) Panicked() bool {
 	return j.panicked
 }
 
 // AutoDetectionEvery returns value of AutoDetectEvery.
-func (j Job) AutoDetectionEvery() int {
+func (j *Job) AutoDetectionEvery() int {
 	return j.AutoDetectEvery
 }
 
 // RetryAutoDetection returns whether it is needed to retry autodetection.
-func (j Job) RetryAutoDetection() bool {
+func (j *Job) RetryAutoDetection() bool {
 	return j.AutoDetectEvery > 0 && (j.AutoDetectTries == infTries || j.AutoDetectTries > 0)
 }
 
@@ -263,6 +282,7 @@ LOOP:
 		case <-j.stop:
 			break LOOP
 		case t := <-j.tick:
+			j.applyUpdateEvery()
 			if t%(j.updateEvery+j.penalty()) == 0 {
 				j.runOnce()
 			}
@@ -270,6 +290,9 @@ LOOP:
 	}
 	j.module.Cleanup()
 	j.Cleanup()
+	if j.observer != nil {
+		j.observer.ObserveStop(j.ModuleName(), j.Name())
+	}
 	j.stop <- struct{}{}
 }
 
@@ -280,23 +303,54 @@ func (j *Job) Stop() {
 	<-j.stop
 }
 
+// SetUpdateEvery changes the data collection interval of the running job without restarting it.
+// It is applied on the next tick, the charts are re-created with the new interval.
+func (j *Job) SetUpdateEvery(updateEvery int) {
+	if updateEvery <= 0 {
+		return
+	}
+	select {
+	case <-j.updateEveryCh:
+	default:
+	}
+	j.updateEveryCh <- updateEvery
+}
+
+func (j *Job) applyUpdateEvery() {
+	select {
+	case v := <-j.updateEveryCh:
+		if v == j.updateEvery {
+			return
+		}
+		j.Infof("data collection interval changed from %ds to %ds", j.updateEvery, v)
+		j.updateEvery = v
+		j.runChart.MarkNotCreated()
+		if j.charts != nil {
+			for _, chart := range *j.charts {
+				chart.MarkNotCreated()
+			}
+		}
+	default:
+	}
+}
+
 func (j *Job) disableAutoDetection() {
 	j.AutoDetectEvery = 0
 }
 
+// CleanupModule releases the module resources of the detected job that is not going to be started.
+// A failed AutoDetection releases them itself.
+func (j *Job) CleanupModule() {
+	j.module.Cleanup()
+}
+
 func (j *Job) Cleanup() {
 	j.buf.Reset()
 	if !shouldObsoleteCharts() {
 		return
 	}
 
-	if !vnodes.Disabled {
-		if !j.vnodeCreated && j.vnodeGUID != "" {
-			_ = j.api.HOSTINFO(j.vnodeGUID, j.vnodeHostname, j.vnodeLabels)
-			j.vnodeCreated = true
-		}
-		_ = j.api.HOST(j.vnodeGUID)
-	}
+	j.switchHost()
 
 	if j.runChart.created {
 		j.runChart.MarkRemove()
@@ -311,16 +365,39 @@ func (j *Job) Cleanup() {
 		}
 	}
 
+	j.resetHost()
+
 	if j.buf.Len() > 0 {
 		_, _ = io.Copy(j.out, j.buf)
 	}
 }
 
+// switchHost directs the following output to the job vnode (the host if the job has no vnode).
+// If vnodes are disabled, only the jobs with a vnode switch the host and switch it back after (resetHost).
+// This is the case for the vnodes registered by service discovery when there is no 'vnodes/' configuration.
+func (j *Job) switchHost() {
+	if vnodes.Disabled && j.vnodeGUID == "" {
+		return
+	}
+	if !j.vnodeCreated && j.vnodeGUID != "" {
+		_ = j.api.HOSTINFO(j.vnodeGUID, j.vnodeHostname, j.vnodeLabels)
+		j.vnodeCreated = true
+	}
+	_ = j.api.HOST(j.vnodeGUID)
+}
+
+func (j *Job) resetHost() {
+	if vnodes.Disabled && j.vnodeGUID != "" {
+		_ = j.api.HOST("")
+	}
+}
+
 func (j *Job) init() bool {
 	if j.initialized {
 		return true
 	}
 
+	j.restoreState()
 	j.initialized = j.module.Init()
 
 	return j.initialized
@@ -352,21 +429,90 @@ func (j *Job) runOnce() {
 	j.prevRun = curTime
 
 	metrics := j.collect()
+	elapsed := time.Since(curTime)
 
 	if j.panicked {
+		j.observeRun(elapsed, false, 0)
 		return
 	}
 
-	if j.processMetrics(metrics, curTime, sinceLastRun) {
+	ok := j.processMetrics(metrics, curTime, sinceLastRun)
+	if ok {
 		j.retries = 0
 	} else {
 		j.retries++
 	}
+	j.observeRun(elapsed, ok, len(metrics))
+	j.updateState(curTime, ok)
+
+	j.resetHost()
 
 	_, _ = io.Copy(j.out, j.buf)
 	j.buf.Reset()
 }
 
+// State returns the current job state. It is safe to call while the job is running.
+func (j *Job) State() JobState {
+	j.stateMux.Lock()
+	defer j.stateMux.Unlock()
+
+	return j.state
+}
+
+func (j *Job) restoreState() {
+	if j.savedState == nil {
+		return
+	}
+
+	state := *j.savedState
+	j.savedState = nil
+
+	j.retries = state.Failures
+	j.stateMux.Lock()
+	j.state = state
+	j.stateMux.Unlock()
+
+	if m, ok := j.module.(StatefulModule); ok && len(state.ModuleState) > 0 {
+		if err := m.Load(state.ModuleState); err != nil {
+			j.Warningf("couldn't restore the module state, ignoring it: %v", err)
+		}
+	}
+}
+
+func (j *Job) updateState(now time.Time, ok bool) {
+	state := j.State()
+
+	state.Failures = j.retries
+	if ok {
+		state.LastSuccess = now
+	}
+
+	if m, ok := j.module.(StatefulModule); ok {
+		if bs, err := m.Save(); err != nil {
+			j.Warningf("couldn't save the module state: %v", err)
+		} else {
+			state.ModuleState = bs
+		}
+	}
+
+	j.stateMux.Lock()
+	j.state = state
+	j.stateMux.Unlock()
+}
+
+func (j *Job) observeRun(elapsed time.Duration, ok bool, metrics int) {
+	if j.observer == nil {
+		return
+	}
+	j.observer.ObserveRun(RunStats{
+		ModuleName: j.ModuleName(),
+		JobName:    j.Name(),
+		Duration:   elapsed,
+		Failed:     !ok,
+		Metrics:    metrics,
+	})
+}
+
 func (j *Job) collect() (result map[string]int64) {
 	j.panicked = false
 	defer func() {
@@ -382,14 +528,7 @@ func (j *Job) collect() (result map[string]int64) {
 }
 
 func (j *Job) processMetrics(metrics map[string]int64, startTime time.Time, sinceLastRun int) bool {
-	if !vnodes.Disabled {
-		if !j.vnodeCreated && j.vnodeGUID != "" {
-			_ = j.api.HOSTINFO(j.vnodeGUID, j.vnodeHostname, j.vnodeLabels)
-			j.vnodeCreated = true
-		}
-
-		_ = j.api.HOST(j.vnodeGUID)
-	}
+	j.switchHost()
 
 	if !ndInternalMonitoringDisabled && !j.runChart.created {
 		j.runChart.ID = fmt.Sprintf("execution_time_of_%s", j.FullName())
@@ -426,6 +565,10 @@ func (j *Job) processMetrics(metrics map[string]int64, startTime time.Time, sinc
 	if updated == 0 {
 		return false
 	}
+	if !j.overridesChecked {
+		j.overridesChecked = true
+		j.checkChartOverrides()
+	}
 	if !ndInternalMonitoringDisabled {
 		j.updateChart(j.runChart, map[string]int64{"time": elapsed}, sinceLastRun)
 	}
@@ -486,6 +629,10 @@ func (j *Job) createChart(chart *Chart) {
 	_ = j.api.CLABELCOMMIT()
 
 	for _, dim := range chart.Dims {
+		if len(j.overrides) > 0 && chart != j.runChart {
+			d := applyChartOverrides(j.overrides, chart, dim)
+			dim = &d
+		}
 		_ = j.api.DIMENSION(
 			firstNotEmpty(dim.Name, dim.ID),
 			dim.Name,
@@ -505,6 +652,22 @@ func (j *Job) createChart(chart *Chart) {
 	_ = j.api.EMPTYLINE()
 }
 
+// checkChartOverrides warns about the chart overrides that match none of the charts, it is done once
+// after the first successful data collection.
+func (j *Job) checkChartOverrides() {
+	for _, o := range j.overrides {
+		var found bool
+		for _, chart := range *j.charts {
+			if found = o.matchChart(chart); found {
+				break
+			}
+		}
+		if !found {
+			j.Warningf("chart override: pattern '%s' matches none of the charts", o.Chart)
+		}
+	}
+}
+
 func (j *Job) updateChart(chart *Chart, collected map[string]int64, sinceLastRun int) bool {
 	if chart.ignore {
 		dims := chart.Dims[:0]
@@ -562,7 +725,7 @@ func (j *Job) updateChart(chart *Chart, collected map[string]int64, sinceLastRun
 	return chart.updated
 }
 
-func (j Job) penalty() int {
+func (j *Job) penalty() int {
 	v := j.retries / penaltyStep * penaltyStep * j.updateEvery / 2
 	if v > maxPenalty {
 		return maxPenalty
@@ -3,12 +3,17 @@
 package module
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/netdata/go.d.plugin/agent/vnodes"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -258,6 +263,237 @@ func TestJob_Start(t *testing.T) {
 	assert.True(t, m.CleanupDone)
 }
 
+func TestJob_SetUpdateEvery(t *testing.T) {
+	var collects int
+	m := &MockModule{
+		ChartsFunc: func() *Charts {
+			return &Charts{
+				&Chart{ID: "id", Title: "title", Units: "units", Dims: Dims{{ID: "id1"}}},
+			}
+		},
+		CollectFunc: func() map[string]int64 {
+			collects++
+			return map[string]int64{"id1": 1}
+		},
+	}
+	var buf bytes.Buffer
+	job := newTestJob()
+	job.out = &buf
+	job.module = m
+	job.charts = job.module.Charts()
+	job.updateEvery = 1
+
+	go func() {
+		for clock := 1; clock <= 4; clock++ {
+			job.tick <- clock
+		}
+		job.SetUpdateEvery(3)
+		for clock := 5; clock <= 10; clock++ {
+			job.tick <- clock
+		}
+		job.Stop()
+	}()
+
+	job.Start()
+
+	assert.Equal(t, 6, collects, "collects: 4 every second, 2 every 3 seconds")
+	assert.Equal(t, 3, job.updateEvery)
+
+	var created []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "CHART 'module_job.id'") && !strings.Contains(line, "obsolete") {
+			created = append(created, line)
+		}
+	}
+	if assert.Len(t, created, 2, "the chart is re-created with the new interval") {
+		assert.Contains(t, created[1], "'3' '' 'plugin' 'module'")
+	}
+}
+
+func TestJob_runOnce_Vnode(t *testing.T) {
+	tests := map[string]struct {
+		vnodesDisabled bool
+		vnodeGUID      string
+		wantHosts      []string
+	}{
+		"vnodes enabled, host job": {
+			wantHosts: []string{"HOST ''"},
+		},
+		"vnodes enabled, vnode job": {
+			vnodeGUID: "guid",
+			wantHosts: []string{"HOST 'guid'"},
+		},
+		"vnodes disabled, host job": {
+			vnodesDisabled: true,
+		},
+		"vnodes disabled, vnode job switches back to the host": {
+			vnodesDisabled: true,
+			vnodeGUID:      "guid",
+			wantHosts:      []string{"HOST 'guid'", "HOST ''"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func(v bool) { vnodes.Disabled = v }(vnodes.Disabled)
+			vnodes.Disabled = test.vnodesDisabled
+
+			var buf bytes.Buffer
+			job := newTestJob()
+			job.out = &buf
+			job.vnodeGUID = test.vnodeGUID
+			job.vnodeHostname = "hostname"
+			job.module = &MockModule{
+				ChartsFunc: func() *Charts {
+					return &Charts{
+						&Chart{ID: "id", Title: "title", Units: "units", Dims: Dims{{ID: "id1"}}},
+					}
+				},
+				CollectFunc: func() map[string]int64 { return map[string]int64{"id1": 1} },
+			}
+			job.charts = job.module.Charts()
+
+			job.runOnce()
+
+			var hosts []string
+			for _, line := range strings.Split(buf.String(), "\n") {
+				if strings.HasPrefix(line, "HOST ") {
+					hosts = append(hosts, line)
+				}
+			}
+			assert.Equal(t, test.wantHosts, hosts)
+			if len(test.wantHosts) > 0 {
+				assert.True(t, strings.HasPrefix(buf.String(), "HOST") || strings.HasPrefix(buf.String(), "HOST_DEFINE"))
+			}
+		})
+	}
+}
+
+func TestJob_Start_RunObserver(t *testing.T) {
+	var calls int
+	job := newTestJob()
+	job.module = &MockModule{
+		ChartsFunc: func() *Charts {
+			return &Charts{
+				&Chart{ID: "id", Title: "title", Units: "units", Dims: Dims{{ID: "id1"}}},
+			}
+		},
+		CollectFunc: func() map[string]int64 {
+			calls++
+			if calls == 2 {
+				return nil
+			}
+			return map[string]int64{"id1": 1, "id2": 2}
+		},
+	}
+	job.charts = job.module.Charts()
+	job.updateEvery = 1
+	obs := &mockRunObserver{}
+	job.observer = obs
+
+	go func() {
+		for clock := 1; clock <= 3; clock++ {
+			job.tick <- clock
+		}
+		job.Stop()
+	}()
+
+	job.Start()
+
+	require.Len(t, obs.runs, 3)
+	for i, run := range obs.runs {
+		assert.Equal(t, modName, run.ModuleName)
+		assert.Equal(t, jobName, run.JobName)
+		assert.Equal(t, i == 1, run.Failed, "run %d", i+1)
+	}
+	assert.Equal(t, 2, obs.runs[0].Metrics)
+	assert.Equal(t, 0, obs.runs[1].Metrics)
+	assert.Equal(t, []string{modName + "/" + jobName}, obs.stops)
+}
+
+type mockRunObserver struct {
+	runs  []RunStats
+	stops []string
+}
+
+func (m *mockRunObserver) ObserveRun(stats RunStats) { m.runs = append(m.runs, stats) }
+
+func (m *mockRunObserver) ObserveStop(moduleName, jobName string) {
+	m.stops = append(m.stops, moduleName+"/"+jobName)
+}
+
+func TestJob_State(t *testing.T) {
+	var calls int
+	mod := &mockStatefulModule{}
+	mod.InitFunc = func() bool { return mod.loaded != nil }
+	mod.ChartsFunc = func() *Charts {
+		return &Charts{
+			&Chart{ID: "id", Title: "title", Units: "units", Dims: Dims{{ID: "id1"}}},
+		}
+	}
+	mod.CollectFunc = func() map[string]int64 {
+		calls++
+		if calls > 1 {
+			return nil
+		}
+		return map[string]int64{"id1": 1}
+	}
+
+	job := newTestJob()
+	job.module = mod
+	job.updateEvery = 1
+	job.savedState = &JobState{Failures: 3, ModuleState: []byte("saved")}
+
+	require.True(t, job.AutoDetection())
+	assert.Equal(t, []byte("saved"), mod.loaded)
+	assert.Equal(t, 3, job.State().Failures)
+
+	job.runOnce()
+	state := job.State()
+	assert.Zero(t, state.Failures)
+	assert.False(t, state.LastSuccess.IsZero())
+	assert.Equal(t, []byte("state1"), state.ModuleState)
+
+	job.runOnce()
+	job.runOnce()
+	state = job.State()
+	assert.Equal(t, 2, state.Failures)
+	assert.Equal(t, []byte("state3"), state.ModuleState)
+}
+
+func TestJob_State_CorruptModuleState(t *testing.T) {
+	mod := &mockStatefulModule{loadErr: true}
+	mod.ChartsFunc = func() *Charts { return &Charts{} }
+
+	job := newTestJob()
+	job.module = mod
+	job.savedState = &JobState{Failures: 1, ModuleState: []byte("corrupt")}
+
+	assert.True(t, job.AutoDetection())
+	assert.Nil(t, mod.loaded)
+	assert.Equal(t, 1, job.State().Failures)
+}
+
+type mockStatefulModule struct {
+	MockModule
+	loaded  []byte
+	loadErr bool
+	saves   int
+}
+
+func (m *mockStatefulModule) Save() ([]byte, error) {
+	m.saves++
+	return []byte(fmt.Sprintf("state%d", m.saves)), nil
+}
+
+func (m *mockStatefulModule) Load(state []byte) error {
+	if m.loadErr {
+		return fmt.Errorf("corrupt state")
+	}
+	m.loaded = state
+	return nil
+}
+
 func TestJob_MainLoop_Panic(t *testing.T) {
 	m := &MockModule{
 		CollectFunc: func() map[string]int64 {
@@ -29,6 +29,13 @@ type Module interface {
 	GetBase() *Base
 }
 
+// ConfigValidator is an optional interface of a module that validates its config.
+// ValidateConfig is called after the job config is applied to the module, before Init.
+// If it returns an error, the job is not created.
+type ConfigValidator interface {
+	ValidateConfig() error
+}
+
 // Base is a helper struct. All modules should embed this struct.
 type Base struct {
 	*logger.Logger
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package module
+
+import "time"
+
+// RunObserver is notified about the data collection runs of the jobs, it is used for the plugin self-monitoring.
+// The methods are called from the job goroutines.
+type RunObserver interface {
+	// ObserveRun is called after every data collection run.
+	ObserveRun(stats RunStats)
+	// ObserveStop is called when the job is stopped.
+	ObserveStop(moduleName, jobName string)
+}
+
+// RunStats describes a data collection run of a job.
+type RunStats struct {
+	ModuleName string
+	JobName    string
+	Duration   time.Duration // time spent in Collect()
+	Failed     bool          // Collect() panicked or returned no metrics for the charts
+	Metrics    int           // the number of collected metrics
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package module
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+// ChartOverride changes the dimensions of the charts defined by the module.
+// It is set in the job config ('chart_overrides') and applied by the job when the chart is created,
+// the dimensions the module adds at runtime get the override as well.
+type ChartOverride struct {
+	Chart      string `yaml:"chart"`     // chart ID glob pattern, mandatory
+	Dimension  string `yaml:"dimension"` // dimension ID glob pattern, all the dimensions if not set
+	Algorithm  string `yaml:"algorithm"`
+	Multiplier int    `yaml:"multiplier"`
+	Divisor    int    `yaml:"divisor"`
+	Hidden     *bool  `yaml:"hidden"`
+}
+
+// Validate checks the patterns and the algorithm of the override.
+func (o ChartOverride) Validate() error {
+	if o.Chart == "" {
+		return errors.New("'chart' not set")
+	}
+	if _, err := path.Match(o.Chart, ""); err != nil {
+		return fmt.Errorf("'chart' pattern '%s': %v", o.Chart, err)
+	}
+	if _, err := path.Match(o.Dimension, ""); err != nil {
+		return fmt.Errorf("'dimension' pattern '%s': %v", o.Dimension, err)
+	}
+	switch DimAlgo(o.Algorithm) {
+	case "", Absolute, Incremental, PercentOfAbsolute, PercentOfIncremental:
+	default:
+		return fmt.Errorf("unknown algorithm '%s'", o.Algorithm)
+	}
+	if o.Algorithm == "" && o.Multiplier == 0 && o.Divisor == 0 && o.Hidden == nil {
+		return errors.New("nothing to override")
+	}
+	return nil
+}
+
+func (o ChartOverride) matchChart(chart *Chart) bool {
+	ok, _ := path.Match(o.Chart, chart.ID)
+	return ok
+}
+
+func (o ChartOverride) matchDim(dim *Dim) bool {
+	if o.Dimension == "" {
+		return true
+	}
+	ok, _ := path.Match(o.Dimension, dim.ID)
+	return ok
+}
+
+// applyChartOverrides returns the dimension with the overrides applied, the module dimension is not modified.
+func applyChartOverrides(overrides []ChartOverride, chart *Chart, dim *Dim) Dim {
+	d := *dim
+	for _, o := range overrides {
+		if !o.matchChart(chart) || !o.matchDim(dim) {
+			continue
+		}
+		if o.Algorithm != "" {
+			d.Algo = DimAlgo(o.Algorithm)
+		}
+		if o.Multiplier != 0 {
+			d.Mul = o.Multiplier
+		}
+		if o.Divisor != 0 {
+			d.Div = o.Divisor
+		}
+		if o.Hidden != nil {
+			d.Hidden = *o.Hidden
+		}
+	}
+	return d
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package module
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartOverride_Validate(t *testing.T) {
+	hidden := true
+
+	tests := map[string]struct {
+		override ChartOverride
+		wantErr  bool
+	}{
+		"valid":                  {override: ChartOverride{Chart: "requests*", Dimension: "2??", Algorithm: "incremental", Multiplier: 8}},
+		"only hidden":            {override: ChartOverride{Chart: "requests", Hidden: &hidden}},
+		"chart not set":          {override: ChartOverride{Algorithm: "incremental"}, wantErr: true},
+		"bad chart pattern":      {override: ChartOverride{Chart: "[requests", Divisor: 8}, wantErr: true},
+		"bad dimension pattern":  {override: ChartOverride{Chart: "requests", Dimension: "[2", Divisor: 8}, wantErr: true},
+		"unknown algorithm":      {override: ChartOverride{Chart: "requests", Algorithm: "derivative"}, wantErr: true},
+		"nothing to override":    {override: ChartOverride{Chart: "requests", Dimension: "2xx"}, wantErr: true},
+		"percentage algorithm":   {override: ChartOverride{Chart: "requests", Algorithm: "percentage-of-incremental-row"}},
+		"multiplier and divisor": {override: ChartOverride{Chart: "*", Multiplier: 8, Divisor: 1000}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.wantErr {
+				assert.Error(t, test.override.Validate())
+			} else {
+				assert.NoError(t, test.override.Validate())
+			}
+		})
+	}
+}
+
+func TestJob_ChartOverrides(t *testing.T) {
+	hidden := true
+	charts := &Charts{
+		&Chart{ID: "requests", Title: "title", Units: "units", Dims: Dims{{ID: "req_2xx"}, {ID: "total"}}},
+		&Chart{ID: "bandwidth", Title: "title", Units: "units", Dims: Dims{{ID: "bytes", Algo: Incremental}}},
+	}
+	var collects int
+
+	var buf bytes.Buffer
+	job := newTestJob()
+	job.out = &buf
+	job.overrides = []ChartOverride{
+		{Chart: "req*", Dimension: "req_*", Algorithm: "incremental", Multiplier: 8, Hidden: &hidden},
+		{Chart: "bandwidth", Multiplier: 8, Divisor: 1000},
+		{Chart: "unknown", Divisor: 1000},
+	}
+	job.module = &MockModule{
+		ChartsFunc: func() *Charts { return charts },
+		CollectFunc: func() map[string]int64 {
+			collects++
+			if collects == 2 {
+				chart := charts.Get("requests")
+				_ = chart.AddDim(&Dim{ID: "req_5xx"})
+				chart.MarkNotCreated()
+			}
+			return map[string]int64{"req_2xx": 1, "req_5xx": 1, "total": 1, "bytes": 1}
+		},
+	}
+	job.charts = job.module.Charts()
+
+	job.runOnce()
+
+	dims := dimensionLines(buf.String())
+	assert.Equal(t, []string{
+		"DIMENSION 'req_2xx' '' 'incremental' '8' '1' 'hidden'",
+		"DIMENSION 'total' '' 'absolute' '1' '1' ''",
+		"DIMENSION 'bytes' '' 'incremental' '8' '1000' ''",
+	}, dims)
+	assert.True(t, job.overridesChecked)
+
+	buf.Reset()
+	job.runOnce()
+
+	dims = dimensionLines(buf.String())
+	assert.Equal(t, []string{
+		"DIMENSION 'req_2xx' '' 'incremental' '8' '1' 'hidden'",
+		"DIMENSION 'total' '' 'absolute' '1' '1' ''",
+		"DIMENSION 'req_5xx' '' 'incremental' '8' '1' 'hidden'",
+	}, dims, "the dimension added at runtime gets the override")
+
+	dim := charts.Get("requests").Dims[0]
+	require.Equal(t, "req_2xx", dim.ID)
+	assert.Equal(t, Dim{ID: "req_2xx"}, *dim, "the module dimension is not modified")
+}
+
+func dimensionLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(line, "DIMENSION ") && line != "DIMENSION 'time' '' 'absolute' '1' '1' ''" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package module
+
+import (
+	"time"
+)
+
+// StatefulModule is an optional interface of a module that keeps its state across plugin restarts.
+type StatefulModule interface {
+	Module
+
+	// Save returns the module state. It is called by the job after every data collection.
+	Save() ([]byte, error)
+
+	// Load restores the module state saved before the restart. It is called before Init.
+	Load(state []byte) error
+}
+
+// JobState is the state of a running job that is persisted across plugin restarts.
+type JobState struct {
+	LastSuccess time.Time `json:"last_success"`
+	Failures    int       `json:"consecutive_failures"`
+	ModuleState []byte    `json:"module_state,omitempty"`
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pluginstats
+
+import (
+	"fmt"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
+
+const (
+	prioRunningJobs = 146000 + iota
+	prioJobsCollectionDuration
+	prioJobsSuccessfulCollections
+	prioJobsFailedCollections
+	prioJobsCollectedMetrics
+)
+
+var runningJobsChart = module.Chart{
+	ID:       "running_jobs",
+	Title:    "Running Jobs",
+	Units:    "jobs",
+	Fam:      "jobs",
+	Ctx:      "plugin_stats.running_jobs",
+	Priority: prioRunningJobs,
+	Dims: module.Dims{
+		{ID: "running_jobs", Name: "running"},
+	},
+}
+
+var moduleChartsTmpl = module.Charts{
+	moduleCollectionDurationChartTmpl.Copy(),
+	moduleSuccessfulCollectionsChartTmpl.Copy(),
+	moduleFailedCollectionsChartTmpl.Copy(),
+	moduleCollectedMetricsChartTmpl.Copy(),
+}
+
+var (
+	moduleCollectionDurationChartTmpl = module.Chart{
+		ID:       "module_%s_collection_duration",
+		Title:    "Jobs Data Collection Duration",
+		Units:    "ms",
+		Fam:      "%s",
+		Ctx:      "plugin_stats.jobs_collection_duration",
+		Priority: prioJobsCollectionDuration,
+	}
+	moduleSuccessfulCollectionsChartTmpl = module.Chart{
+		ID:       "module_%s_successful_collections",
+		Title:    "Jobs Successful Data Collections",
+		Units:    "collections/s",
+		Fam:      "%s",
+		Ctx:      "plugin_stats.jobs_successful_collections",
+		Priority: prioJobsSuccessfulCollections,
+	}
+	moduleFailedCollectionsChartTmpl = module.Chart{
+		ID:       "module_%s_failed_collections",
+		Title:    "Jobs Failed Data Collections",
+		Units:    "collections/s",
+		Fam:      "%s",
+		Ctx:      "plugin_stats.jobs_failed_collections",
+		Priority: prioJobsFailedCollections,
+	}
+	moduleCollectedMetricsChartTmpl = module.Chart{
+		ID:       "module_%s_collected_metrics",
+		Title:    "Jobs Collected Metrics",
+		Units:    "metrics",
+		Fam:      "%s",
+		Ctx:      "plugin_stats.jobs_collected_metrics",
+		Priority: prioJobsCollectedMetrics,
+	}
+)
+
+func newModuleCharts(moduleName string) *module.Charts {
+	charts := moduleChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, moduleName)
+		chart.Fam = fmt.Sprintf(chart.Fam, moduleName)
+		chart.Labels = []module.Label{
+			{Key: "module", Value: moduleName},
+		}
+	}
+
+	return charts
+}
+
+func (p *PluginStats) addModuleCharts(moduleName string) {
+	if err := p.Charts().Add(*newModuleCharts(moduleName)...); err != nil {
+		p.Warning(err)
+	}
+}
+
+func (p *PluginStats) removeModuleCharts(moduleName string) {
+	px := fmt.Sprintf("module_%s_", moduleName)
+
+	for _, chart := range *p.Charts() {
+		if chart.ID == px+"collection_duration" || chart.ID == px+"successful_collections" ||
+			chart.ID == px+"failed_collections" || chart.ID == px+"collected_metrics" {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+func (p *PluginStats) addJobDimensions(js *jobStats) {
+	for _, chart := range *p.Charts() {
+		id, algo, ok := jobDimID(chart, js)
+		if !ok {
+			continue
+		}
+		if chart.HasDim(id) {
+			// not yet removed from the chart after the job was stopped
+			_ = chart.RemoveDim(id)
+		}
+		if err := chart.AddDim(&module.Dim{ID: id, Name: js.jobName, Algo: algo}); err != nil {
+			p.Warning(err)
+			continue
+		}
+		chart.MarkNotCreated()
+	}
+}
+
+func (p *PluginStats) removeJobDimensions(js *jobStats) {
+	for _, chart := range *p.Charts() {
+		id, _, ok := jobDimID(chart, js)
+		if !ok || !chart.HasDim(id) {
+			continue
+		}
+		if err := chart.MarkDimRemove(id, true); err != nil {
+			p.Warning(err)
+			continue
+		}
+		chart.MarkNotCreated()
+	}
+}
+
+func jobDimID(chart *module.Chart, js *jobStats) (id string, algo module.DimAlgo, ok bool) {
+	px := fmt.Sprintf("module_%s_", js.moduleName)
+
+	switch chart.ID {
+	case px + "collection_duration":
+		return js.dimPrefix + "collection_duration", module.Absolute, true
+	case px + "successful_collections":
+		return js.dimPrefix + "successful_collections", module.Incremental, true
+	case px + "failed_collections":
+		return js.dimPrefix + "failed_collections", module.Incremental, true
+	case px + "collected_metrics":
+		return js.dimPrefix + "collected_metrics", module.Absolute, true
+	}
+	return "", "", false
+}
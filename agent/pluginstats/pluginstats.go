@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pluginstats
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
+
+// Config is the plugin statistics configuration.
+type Config struct {
+	// DimensionIdleTimeout is the time after which the dimensions of a job that has not reported
+	// a data collection run are removed. It limits the number of dimensions when service discovery churns jobs.
+	DimensionIdleTimeout time.Duration
+}
+
+// New creates the internal 'plugin_stats' module. It is not registered in the modules registry,
+// the job manager runs it and reports the data collection runs of all the other jobs to it.
+func New(cfg Config) *PluginStats {
+	p := &PluginStats{
+		idleTimeout: cfg.DimensionIdleTimeout,
+		now:         time.Now,
+		charts:      &module.Charts{runningJobsChart.Copy()},
+		jobs:        make(map[string]*jobStats),
+		modules:     make(map[string]int),
+	}
+	if p.idleTimeout <= 0 {
+		p.idleTimeout = time.Minute * 10
+	}
+	return p
+}
+
+type (
+	PluginStats struct {
+		module.Base
+
+		idleTimeout time.Duration
+		now         func() time.Time
+
+		charts *module.Charts
+
+		mux  sync.Mutex
+		jobs map[string]*jobStats // [module_job]
+		// the number of jobs that have dimensions on the module charts, charts are removed when it drops to 0
+		modules map[string]int // [module]
+	}
+	jobStats struct {
+		moduleName string
+		jobName    string
+		dimPrefix  string
+
+		charted bool
+		stopped bool
+		lastRun time.Time
+
+		duration  int64
+		successes int64
+		failures  int64
+		metrics   int64
+	}
+)
+
+// ObserveRun implements module.RunObserver.
+func (p *PluginStats) ObserveRun(stats module.RunStats) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	key := jobKey(stats.ModuleName, stats.JobName)
+	js, ok := p.jobs[key]
+	if !ok {
+		js = &jobStats{
+			moduleName: stats.ModuleName,
+			jobName:    stats.JobName,
+			dimPrefix:  "job_" + key + "_",
+		}
+		p.jobs[key] = js
+	}
+
+	// the job might be restarted (e.g. the config changed) before the stop was processed
+	js.stopped = false
+	js.lastRun = p.now()
+	js.duration = stats.Duration.Milliseconds()
+	js.metrics = int64(stats.Metrics)
+	if stats.Failed {
+		js.failures++
+	} else {
+		js.successes++
+	}
+}
+
+// ObserveStop implements module.RunObserver.
+func (p *PluginStats) ObserveStop(moduleName, jobName string) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if js, ok := p.jobs[jobKey(moduleName, jobName)]; ok {
+		js.stopped = true
+	}
+}
+
+func (p *PluginStats) Init() bool { return true }
+
+func (p *PluginStats) Check() bool { return true }
+
+func (p *PluginStats) Charts() *module.Charts { return p.charts }
+
+func (p *PluginStats) Collect() map[string]int64 {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	now := p.now()
+	mx := make(map[string]int64)
+
+	for key, js := range p.jobs {
+		if js.stopped || now.Sub(js.lastRun) > p.idleTimeout {
+			delete(p.jobs, key)
+			if js.charted {
+				p.removeJob(js)
+			}
+			continue
+		}
+
+		if !js.charted {
+			js.charted = true
+			p.addJob(js)
+		}
+
+		mx[js.dimPrefix+"collection_duration"] = js.duration
+		mx[js.dimPrefix+"successful_collections"] = js.successes
+		mx[js.dimPrefix+"failed_collections"] = js.failures
+		mx[js.dimPrefix+"collected_metrics"] = js.metrics
+	}
+
+	mx["running_jobs"] = int64(len(p.jobs))
+
+	for name, n := range p.modules {
+		if n == 0 {
+			delete(p.modules, name)
+			p.removeModuleCharts(name)
+		}
+	}
+
+	return mx
+}
+
+func (p *PluginStats) Cleanup() {}
+
+func (p *PluginStats) addJob(js *jobStats) {
+	if _, ok := p.modules[js.moduleName]; !ok {
+		p.addModuleCharts(js.moduleName)
+	}
+	p.modules[js.moduleName]++
+	p.addJobDimensions(js)
+}
+
+func (p *PluginStats) removeJob(js *jobStats) {
+	p.modules[js.moduleName]--
+	p.removeJobDimensions(js)
+}
+
+func jobKey(moduleName, jobName string) string {
+	return fmt.Sprintf("%s_%s", moduleName, jobName)
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package pluginstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	assert.IsType(t, (*PluginStats)(nil), New(Config{}))
+	assert.Equal(t, time.Minute*10, New(Config{}).idleTimeout)
+	assert.Equal(t, time.Minute, New(Config{DimensionIdleTimeout: time.Minute}).idleTimeout)
+}
+
+func TestPluginStats_Init(t *testing.T) {
+	assert.True(t, New(Config{}).Init())
+}
+
+func TestPluginStats_Check(t *testing.T) {
+	assert.True(t, New(Config{}).Check())
+}
+
+func TestPluginStats_Charts(t *testing.T) {
+	charts := New(Config{}).Charts()
+
+	require.NotNil(t, charts)
+	assert.True(t, charts.Has(runningJobsChart.ID))
+}
+
+func TestPluginStats_Collect(t *testing.T) {
+	p := New(Config{DimensionIdleTimeout: time.Minute})
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	p.ObserveRun(module.RunStats{ModuleName: "nginx", JobName: "local", Duration: time.Millisecond * 15, Metrics: 10})
+	p.ObserveRun(module.RunStats{ModuleName: "nginx", JobName: "local", Duration: time.Millisecond * 12, Metrics: 10})
+	p.ObserveRun(module.RunStats{ModuleName: "nginx", JobName: "remote", Duration: time.Second, Failed: true})
+	p.ObserveRun(module.RunStats{ModuleName: "redis", JobName: "local", Duration: time.Millisecond * 3, Metrics: 50})
+
+	expected := map[string]int64{
+		"running_jobs": 3,
+
+		"job_nginx_local_collection_duration":    12,
+		"job_nginx_local_successful_collections": 2,
+		"job_nginx_local_failed_collections":     0,
+		"job_nginx_local_collected_metrics":      10,
+
+		"job_nginx_remote_collection_duration":    1000,
+		"job_nginx_remote_successful_collections": 0,
+		"job_nginx_remote_failed_collections":     1,
+		"job_nginx_remote_collected_metrics":      0,
+
+		"job_redis_local_collection_duration":    3,
+		"job_redis_local_successful_collections": 1,
+		"job_redis_local_failed_collections":     0,
+		"job_redis_local_collected_metrics":      50,
+	}
+	assert.Equal(t, expected, p.Collect())
+
+	require.True(t, p.Charts().Has("module_nginx_collection_duration"))
+	require.True(t, p.Charts().Has("module_redis_collection_duration"))
+	chart := p.Charts().Get("module_nginx_failed_collections")
+	assert.Equal(t, []module.Label{{Key: "module", Value: "nginx"}}, chart.Labels)
+	assert.True(t, chart.HasDim("job_nginx_local_failed_collections"))
+	assert.True(t, chart.HasDim("job_nginx_remote_failed_collections"))
+	assert.Equal(t, module.Incremental, chart.GetDim("job_nginx_remote_failed_collections").Algo)
+
+	// the redis job is removed, the nginx 'remote' job stops reporting (e.g. its target is gone)
+	p.ObserveStop("redis", "local")
+	now = now.Add(time.Second * 61)
+	p.ObserveRun(module.RunStats{ModuleName: "nginx", JobName: "local", Duration: time.Millisecond * 10, Metrics: 10})
+
+	expected = map[string]int64{
+		"running_jobs": 1,
+
+		"job_nginx_local_collection_duration":    10,
+		"job_nginx_local_successful_collections": 3,
+		"job_nginx_local_failed_collections":     0,
+		"job_nginx_local_collected_metrics":      10,
+	}
+	assert.Equal(t, expected, p.Collect())
+
+	for _, chart := range *p.Charts() {
+		switch chart.ID {
+		case runningJobsChart.ID:
+			assert.False(t, chart.Obsolete)
+		case "module_redis_collection_duration", "module_redis_successful_collections",
+			"module_redis_failed_collections", "module_redis_collected_metrics":
+			assert.Truef(t, chart.Obsolete, "chart '%s' is not obsolete", chart.ID)
+		default:
+			require.Truef(t, len(chart.Dims) == 2, "chart '%s' dims", chart.ID)
+			assert.False(t, chart.Obsolete)
+			assert.False(t, chart.Dims[0].Obsolete, "nginx 'local' dim is obsolete")
+			assert.True(t, chart.Dims[1].Obsolete, "nginx 'remote' dim is not obsolete")
+		}
+	}
+
+	// the redis job is back
+	p.ObserveRun(module.RunStats{ModuleName: "redis", JobName: "local", Duration: time.Millisecond * 3, Metrics: 50})
+	mx := p.Collect()
+	assert.Equal(t, int64(2), mx["running_jobs"])
+	assert.Equal(t, int64(1), mx["job_redis_local_successful_collections"], "counters start over")
+}
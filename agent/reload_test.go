@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package agent
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgent_reload(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleConf := func(job2URL string) {
+		conf := "jobs:\n  - name: job1\n    url: http://127.0.0.1:80\n  - name: job2\n    url: " + job2URL + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "module1.conf"), []byte(conf), 0644))
+	}
+	writeModuleConf("http://127.0.0.1:81")
+
+	var mux sync.Mutex
+	inits := make(map[string]int)
+	cleanups := make(map[string]int)
+	stats := func(m map[string]int, url string) int {
+		mux.Lock()
+		defer mux.Unlock()
+		return m[url]
+	}
+
+	a := New(Config{RunModule: "module1"})
+	a.Out = io.Discard
+	a.ModulesConfDir = []string{dir}
+	a.ModuleRegistry = module.Registry{"module1": module.Creator{
+		Create: func() module.Module {
+			m := &reloadTestModule{}
+			m.InitFunc = func() bool { mux.Lock(); defer mux.Unlock(); inits[m.URL]++; return true }
+			m.CleanupFunc = func() { mux.Lock(); defer mux.Unlock(); cleanups[m.URL]++ }
+			m.ChartsFunc = func() *module.Charts {
+				return &module.Charts{&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}}}
+			}
+			m.CollectFunc = func() map[string]int64 { return map[string]int64{"id1": 1} }
+			return m
+		},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() { defer wg.Done(); a.run(ctx) }()
+	defer func() { cancel(); wg.Wait() }()
+
+	require.Eventually(t, func() bool {
+		return stats(inits, "http://127.0.0.1:80") == 1 && stats(inits, "http://127.0.0.1:81") == 1
+	}, time.Second*10, time.Millisecond*50, "jobs are not started")
+
+	writeModuleConf("http://127.0.0.1:8081")
+	a.triggerReload()
+
+	require.Eventually(t, func() bool {
+		return stats(inits, "http://127.0.0.1:8081") == 1
+	}, time.Second*10, time.Millisecond*50, "the changed job is not started")
+
+	assert.Equal(t, 1, stats(inits, "http://127.0.0.1:80"), "the unchanged job is restarted")
+	assert.Zero(t, stats(cleanups, "http://127.0.0.1:80"), "the unchanged job is stopped")
+	assert.Equal(t, 1, stats(cleanups, "http://127.0.0.1:81"), "the changed job is not stopped")
+}
+
+func TestAgent_reloadEnabledModules(t *testing.T) {
+	confDir, modulesConfDir := t.TempDir(), t.TempDir()
+	writePluginConf := func(module2 string) {
+		conf := "modules:\n  module2: " + module2 + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(confDir, "go.d.conf"), []byte(conf), 0644))
+	}
+	writePluginConf("no")
+	for _, name := range []string{"module1", "module2"} {
+		conf := "jobs:\n  - name: job\n    url: http://127.0.0.1/" + name + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(modulesConfDir, name+".conf"), []byte(conf), 0644))
+	}
+
+	var mux sync.Mutex
+	running := make(map[string]bool)
+	isRunning := func(url string) bool { mux.Lock(); defer mux.Unlock(); return running[url] }
+
+	creator := module.Creator{
+		Create: func() module.Module {
+			m := &reloadTestModule{}
+			m.InitFunc = func() bool { mux.Lock(); defer mux.Unlock(); running[m.URL] = true; return true }
+			m.CleanupFunc = func() { mux.Lock(); defer mux.Unlock(); running[m.URL] = false }
+			m.ChartsFunc = func() *module.Charts {
+				return &module.Charts{&module.Chart{ID: "id", Title: "title", Units: "units", Dims: module.Dims{{ID: "id1"}}}}
+			}
+			m.CollectFunc = func() map[string]int64 { return map[string]int64{"id1": 1} }
+			return m
+		},
+	}
+
+	a := New(Config{Name: "go.d"})
+	a.Out = io.Discard
+	a.ConfDir = []string{confDir}
+	a.ModulesConfDir = []string{modulesConfDir}
+	a.ModuleRegistry = module.Registry{"module1": creator, "module2": creator}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() { defer wg.Done(); a.run(ctx) }()
+	defer func() { cancel(); wg.Wait() }()
+
+	require.Eventually(t, func() bool { return isRunning("http://127.0.0.1/module1") },
+		time.Second*10, time.Millisecond*50, "the enabled module job is not started")
+	assert.False(t, isRunning("http://127.0.0.1/module2"), "the disabled module job is started")
+
+	writePluginConf("yes")
+	a.triggerReload()
+
+	require.Eventually(t, func() bool { return isRunning("http://127.0.0.1/module2") },
+		time.Second*10, time.Millisecond*50, "the module enabled on reload is not started")
+
+	writePluginConf("no")
+	a.triggerReload()
+
+	require.Eventually(t, func() bool { return !isRunning("http://127.0.0.1/module2") },
+		time.Second*10, time.Millisecond*50, "the module disabled on reload is not stopped")
+	assert.True(t, isRunning("http://127.0.0.1/module1"))
+}
+
+func Test_restartRequiredOptions(t *testing.T) {
+	prev := defaultConfig()
+	cur := defaultConfig()
+	cur.Modules = map[string]bool{"nginx": false}
+	cur.DefaultRun = false
+	assert.Empty(t, restartRequiredOptions(prev, cur))
+
+	cur.CheckWorkers = 4
+	cur.JobState.Enabled = true
+	assert.Equal(t, []string{"check_workers", "job_state"}, restartRequiredOptions(prev, cur))
+}
+
+type reloadTestModule struct {
+	module.MockModule `yaml:",inline"`
+	URL               string `yaml:"url"`
+}
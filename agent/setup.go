@@ -11,6 +11,7 @@ import (
 	"github.com/netdata/go.d.plugin/agent/discovery"
 	"github.com/netdata/go.d.plugin/agent/discovery/dummy"
 	"github.com/netdata/go.d.plugin/agent/discovery/file"
+	"github.com/netdata/go.d.plugin/agent/discovery/sd"
 	"github.com/netdata/go.d.plugin/agent/hostinfo"
 	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/netdata/go.d.plugin/agent/vnodes"
@@ -74,6 +75,18 @@ func (a *Agent) loadEnabledModules(cfg config) module.Registry {
 	return enabled
 }
 
+// loadRunnableModules returns the registered modules allowed by the '-m' command line option.
+func (a *Agent) loadRunnableModules() module.Registry {
+	if a.RunModule == "all" || a.RunModule == "" {
+		return a.ModuleRegistry
+	}
+	runnable := module.Registry{}
+	if creator, ok := a.ModuleRegistry[a.RunModule]; ok {
+		runnable[a.RunModule] = creator
+	}
+	return runnable
+}
+
 func (a *Agent) buildDiscoveryConf(enabled module.Registry) discovery.Config {
 	a.Info("building discovery config")
 
@@ -174,6 +187,29 @@ func (a *Agent) setupVnodeRegistry() *vnodes.Vnodes {
 	return reg
 }
 
+// setupServiceDiscovery returns the service discovery if the 'sd/' pipeline configs directory exists.
+func (a *Agent) setupServiceDiscovery() *sd.ServiceDiscovery {
+	a.Debugf("looking for 'sd/' in %v", a.ModulesConfDir)
+
+	if len(a.ModulesConfDir) == 0 {
+		return nil
+	}
+
+	dirPath, err := a.ModulesConfDir.Find("sd/")
+	if err != nil || dirPath == "" {
+		return nil
+	}
+
+	d, err := sd.NewServiceDiscovery(sd.Config{ConfDir: dirPath})
+	if err != nil {
+		a.Warningf("couldn't create service discovery: %v", err)
+		return nil
+	}
+	a.Infof("found '%s', service discovery is enabled", dirPath)
+
+	return d
+}
+
 func loadYAML(conf interface{}, path string) error {
 	f, err := os.Open(path)
 	if err != nil {
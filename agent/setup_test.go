@@ -39,6 +39,32 @@ func TestConfig_UnmarshalYAML(t *testing.T) {
 				},
 			},
 		},
+		"valid configuration with plugin stats": {
+			input: "enabled: yes\nplugin_stats:\n  enabled: no\n  dimension_idle_timeout: 60\nmodules:\n  module1: yes",
+			wantCfg: config{
+				Enabled: true,
+				Modules: map[string]bool{
+					"module1": true,
+				},
+				PluginStats: pluginStatsConfig{
+					Enabled:              false,
+					DimensionIdleTimeout: 60,
+				},
+			},
+		},
+		"valid configuration with job state": {
+			input: "enabled: yes\njob_state:\n  enabled: yes\n  max_age: 12\nmodules:\n  module1: yes",
+			wantCfg: config{
+				Enabled: true,
+				Modules: map[string]bool{
+					"module1": true,
+				},
+				JobState: jobStateConfig{
+					Enabled: true,
+					MaxAge:  12,
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -62,13 +88,22 @@ func TestAgent_loadConfig(t *testing.T) {
 				ConfDir: []string{"testdata"},
 			},
 			wantCfg: config{
-				Enabled:    true,
-				DefaultRun: true,
-				MaxProcs:   1,
+				Enabled:      true,
+				DefaultRun:   true,
+				MaxProcs:     1,
+				StrictConfig: true,
 				Modules: map[string]bool{
 					"module1": true,
 					"module2": true,
 				},
+				PluginStats: pluginStatsConfig{
+					Enabled:              true,
+					DimensionIdleTimeout: 600,
+				},
+				JobState: jobStateConfig{
+					Enabled: false,
+					MaxAge:  24,
+				},
 			},
 		},
 		"no config path provided": {
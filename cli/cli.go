@@ -12,8 +12,12 @@ import (
 type Option struct {
 	UpdateEvery int
 	Module      string   `short:"m" long:"modules" description:"module name to run" default:"all"`
+	Job         string   `short:"j" long:"job" description:"job name to run in the 'dump' mode (default: module name)"`
+	Dump        bool     `long:"dump" description:"run the job once and print collected metrics as JSON"`
+	DumpCharts  bool     `long:"dump-charts" description:"run the job once and print collected metrics and chart definitions as JSON"`
 	ConfDir     []string `short:"c" long:"config-dir" description:"config dir to read"`
 	WatchPath   []string `short:"w" long:"watch-path" description:"config path to watch"`
+	SDTargets   string   `long:"sd-targets" description:"targets fixture file for the 'sd-dryrun' mode"`
 	Debug       bool     `short:"d" long:"debug" description:"debug mode"`
 	Version     bool     `short:"v" long:"version" description:"display the version and exit"`
 }
@@ -97,6 +97,13 @@ func stateFile() string {
 	return filepath.Join(varLibDir, "god-jobs-statuses.json")
 }
 
+func jobStateFile() string {
+	if varLibDir == "" {
+		return ""
+	}
+	return filepath.Join(varLibDir, "god-jobs-state.json")
+}
+
 func init() {
 	// https://github.com/netdata/netdata/issues/8949#issuecomment-638294959
 	if v := os.Getenv("TZ"); strings.HasPrefix(v, ":") {
@@ -120,6 +127,10 @@ func main() {
 		logger.Level.Set(slog.LevelDebug)
 	}
 
+	if opts.Module == sdDryRunModule {
+		os.Exit(sdDryRun(opts))
+	}
+
 	a := agent.New(agent.Config{
 		Name:              name,
 		ConfDir:           confDir(opts),
@@ -127,8 +138,11 @@ func main() {
 		ModulesSDConfPath: watchPaths(opts),
 		VnodesConfDir:     confDir(opts),
 		StateFile:         stateFile(),
+		JobStateFile:      jobStateFile(),
 		LockDir:           lockDir,
 		RunModule:         opts.Module,
+		RunJob:            opts.Job,
+		DumpCharts:        opts.DumpCharts,
 		MinUpdateEvery:    opts.UpdateEvery,
 	})
 
@@ -137,6 +151,10 @@ func main() {
 		a.Debugf("current user: name=%s, uid=%s", u.Username, u.Uid)
 	}
 
+	if opts.Dump || opts.DumpCharts {
+		os.Exit(a.Dump())
+	}
+
 	cfg := httpproxy.FromEnvironment()
 	a.Infof("env HTTP_PROXY '%s', HTTPS_PROXY '%s'", cfg.HTTPProxy, cfg.HTTPSProxy)
 
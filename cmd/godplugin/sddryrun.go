@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/netdata/go.d.plugin/agent/discovery/sd/pipeline"
+	"github.com/netdata/go.d.plugin/cli"
+
+	"gopkg.in/yaml.v2"
+)
+
+// sdDryRunModule runs the sd pipeline config once and prints what would be discovered and composed:
+//
+//	go.d.plugin -m sd-dryrun -c <pipeline.conf> [--sd-targets <targets.yaml>]
+const sdDryRunModule = "sd-dryrun"
+
+func sdDryRun(opts *cli.Option) int {
+	if len(opts.ConfDir) != 1 {
+		fmt.Fprintln(os.Stderr, "sd-dryrun: expects exactly one pipeline config file ('-c <pipeline.conf>')")
+		return 1
+	}
+
+	bs, err := os.ReadFile(opts.ConfDir[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sd-dryrun: %v\n", err)
+		return 1
+	}
+
+	var cfg pipeline.Config
+	if err := yaml.Unmarshal(bs, &cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "sd-dryrun: parse '%s': %v\n", opts.ConfDir[0], err)
+		return 1
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	conf := pipeline.DryRunConfig{TargetsFile: opts.SDTargets}
+	if err := pipeline.DryRun(ctx, cfg, conf, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "sd-dryrun: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -34,13 +35,17 @@ func New() *Apache {
 				},
 			},
 		},
-		charts: &module.Charts{},
-		once:   &sync.Once{},
+		charts:      &module.Charts{},
+		once:        &sync.Once{},
+		workerSlots: make(map[string]workerSlot),
+		vhosts:      make(map[string]*vhostStats),
 	}
 }
 
 type Config struct {
-	web.HTTP `yaml:",inline"`
+	web.HTTP      `yaml:",inline"`
+	CollectVhosts bool   `yaml:"collect_vhosts"`
+	VhostSelector string `yaml:"vhost_selector"`
 }
 
 type Apache struct {
@@ -52,6 +57,11 @@ type Apache struct {
 
 	httpClient *http.Client
 	once       *sync.Once
+
+	doVhosts      bool
+	vhostSelector matcher.Matcher
+	workerSlots   map[string]workerSlot
+	vhosts        map[string]*vhostStats
 }
 
 func (a *Apache) Init() bool {
@@ -67,6 +77,14 @@ func (a *Apache) Init() bool {
 	}
 	a.httpClient = httpClient
 
+	sr, err := a.initVhostSelector()
+	if err != nil {
+		a.Errorf("init vhost selector: %v", err)
+		return false
+	}
+	a.vhostSelector = sr
+	a.doVhosts = a.CollectVhosts
+
 	a.Debugf("using URL %s", a.URL)
 	a.Debugf("using timeout: %s", a.Timeout.Duration)
 	return true
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/netdata/go.d.plugin/pkg/web"
@@ -19,6 +20,8 @@ var (
 	dataExtendedStatusMPMEvent, _   = os.ReadFile("testdata/extended-status-mpm-event.txt")
 	dataExtendedStatusMPMPrefork, _ = os.ReadFile("testdata/extended-status-mpm-prefork.txt")
 	dataLighttpdStatus, _           = os.ReadFile("testdata/lighttpd-status.txt")
+	dataExtendedStatusHTML, _       = os.ReadFile("testdata/extended-status-mpm-event.html")
+	dataExtendedStatusHTML2, _      = os.ReadFile("testdata/extended-status-mpm-event-2.html")
 )
 
 func Test_testDataIsValid(t *testing.T) {
@@ -27,6 +30,8 @@ func Test_testDataIsValid(t *testing.T) {
 		"dataExtendedStatusMPMEvent":   dataExtendedStatusMPMEvent,
 		"dataExtendedStatusMPMPrefork": dataExtendedStatusMPMPrefork,
 		"dataLighttpdStatus":           dataLighttpdStatus,
+		"dataExtendedStatusHTML":       dataExtendedStatusHTML,
+		"dataExtendedStatusHTML2":      dataExtendedStatusHTML2,
 	} {
 		require.NotNilf(t, data, name)
 
@@ -50,6 +55,14 @@ func TestApache_Init(t *testing.T) {
 				},
 			},
 		},
+		"fail on invalid vhost selector": {
+			wantFail: true,
+			config: Config{
+				HTTP:          New().HTTP,
+				CollectVhosts: true,
+				VhostSelector: "[",
+			},
+		},
 		"fail when URL has no wantMetrics suffix": {
 			wantFail: true,
 			config: Config{
@@ -247,6 +260,109 @@ func TestApache_Collect(t *testing.T) {
 	}
 }
 
+func TestApache_CollectVhosts(t *testing.T) {
+	tests := map[string]struct {
+		selector    string
+		wantMetrics map[string]int64
+	}{
+		"all virtual hosts": {
+			wantMetrics: map[string]int64{
+				"vhost_api.example.com:443_kbytes":   256,
+				"vhost_api.example.com:443_requests": 2,
+				"vhost_localhost:80_kbytes":          0,
+				"vhost_localhost:80_requests":        1,
+				"vhost_www.example.com:80_kbytes":    256,
+				"vhost_www.example.com:80_requests":  5,
+			},
+		},
+		"with selector": {
+			selector: "!localhost:* *",
+			wantMetrics: map[string]int64{
+				"vhost_api.example.com:443_kbytes":   256,
+				"vhost_api.example.com:443_requests": 2,
+				"vhost_www.example.com:80_kbytes":    256,
+				"vhost_www.example.com:80_requests":  5,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			page := dataExtendedStatusHTML
+			srv := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.RawQuery == "auto" {
+						_, _ = w.Write(dataExtendedStatusMPMEvent)
+						return
+					}
+					_, _ = w.Write(page)
+				}))
+			defer srv.Close()
+
+			apache := New()
+			apache.URL = srv.URL + "/server-status?auto"
+			apache.CollectVhosts = true
+			apache.VhostSelector = test.selector
+			require.True(t, apache.Init())
+			require.True(t, apache.Check())
+
+			page = dataExtendedStatusHTML2
+			mx := apache.Collect()
+			require.NotNil(t, mx)
+
+			vhosts := make(map[string]int64)
+			for k, v := range mx {
+				if strings.HasPrefix(k, "vhost_") {
+					vhosts[k] = v
+				}
+			}
+			assert.Equal(t, test.wantMetrics, vhosts)
+
+			for _, chart := range *apache.Charts() {
+				for _, dim := range chart.Dims {
+					_, ok := mx[dim.ID]
+					assert.Truef(t, ok, "chart '%s' dim '%s': no dim in collected", chart.ID, dim.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestApache_CollectVhosts_ExtendedStatusOff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RawQuery == "auto" {
+				_, _ = w.Write(dataSimpleStatusMPMEvent)
+				return
+			}
+			_, _ = w.Write([]byte("<html><body><h1>Apache Server Status</h1></body></html>"))
+		}))
+	defer srv.Close()
+
+	apache := New()
+	apache.URL = srv.URL + "/server-status?auto"
+	apache.CollectVhosts = true
+	require.True(t, apache.Init())
+
+	assert.True(t, apache.Check())
+	assert.False(t, apache.doVhosts)
+}
+
+func Test_parseWorkersTable_UnknownHeader(t *testing.T) {
+	// the header names are changed, the columns are found by the table structure
+	page := strings.NewReplacer("<th>Acc</th>", "<th>Zugriffe</th>", "<th>VHost</th>", "<th>Host</th>").
+		Replace(string(dataExtendedStatusHTML))
+
+	tables, err := parseHTMLTables(strings.NewReader(page))
+	require.NoError(t, err)
+
+	assert.Equal(t, []workerRow{
+		{slot: "0-0", vhost: "www.example.com:80", accesses: 10, kbytes: 512},
+		{slot: "0-1", vhost: "api.example.com:443", accesses: 4, kbytes: 256},
+		{slot: "1-0", vhost: "localhost:80", accesses: 20, kbytes: 1024},
+	}, parseWorkersTable(tables))
+}
+
 func caseMPMEventSimpleStatus(t *testing.T) (*Apache, func()) {
 	t.Helper()
 	srv := httptest.NewServer(http.HandlerFunc(
@@ -2,7 +2,12 @@
 
 package apache
 
-import "github.com/netdata/go.d.plugin/agent/module"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
 
 const (
 	prioRequests = module.Priority + iota
@@ -15,6 +20,8 @@ const (
 	prioBytesPerSec
 	prioBytesPerReq
 	prioUptime
+	prioVhostRequests
+	prioVhostNet
 )
 
 var baseCharts = module.Charts{
@@ -187,3 +194,64 @@ var (
 		},
 	}
 )
+
+// extended status page (HTML)
+var (
+	vhostChartsTmpl = module.Charts{
+		vhostRequestsChartTmpl.Copy(),
+		vhostBandwidthChartTmpl.Copy(),
+	}
+	vhostRequestsChartTmpl = module.Chart{
+		ID:       "vhost_%s_requests",
+		Title:    "Virtual Host Requests",
+		Units:    "requests/s",
+		Fam:      "vhosts",
+		Ctx:      "apache.vhost_requests",
+		Priority: prioVhostRequests,
+		Dims: module.Dims{
+			{ID: "vhost_%s_requests", Name: "requests", Algo: module.Incremental},
+		},
+	}
+	vhostBandwidthChartTmpl = module.Chart{
+		ID:       "vhost_%s_net",
+		Title:    "Virtual Host Bandwidth",
+		Units:    "kilobits/s",
+		Fam:      "vhosts",
+		Ctx:      "apache.vhost_net",
+		Type:     module.Area,
+		Priority: prioVhostNet,
+		Dims: module.Dims{
+			{ID: "vhost_%s_kbytes", Name: "sent", Algo: module.Incremental, Mul: 8},
+		},
+	}
+)
+
+var vhostIDReplacer = strings.NewReplacer(".", "_", ":", "_", " ", "_")
+
+func (a *Apache) addVhostCharts(vhost string) {
+	charts := vhostChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, vhostIDReplacer.Replace(vhost))
+		chart.Labels = []module.Label{
+			{Key: "vhost", Value: vhost},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, vhost)
+		}
+	}
+
+	if err := a.Charts().Add(*charts...); err != nil {
+		a.Warning(err)
+	}
+}
+
+func (a *Apache) removeVhostCharts(vhost string) {
+	px := fmt.Sprintf("vhost_%s_", vhostIDReplacer.Replace(vhost))
+	for _, chart := range *a.Charts() {
+		if strings.HasPrefix(chart.ID, px) {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
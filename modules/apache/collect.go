@@ -27,6 +27,10 @@ func (a *Apache) collect() (map[string]int64, error) {
 
 	a.once.Do(func() { a.charts = newCharts(status) })
 
+	if a.doVhosts {
+		a.collectVhosts(mx)
+	}
+
 	return mx, nil
 }
 
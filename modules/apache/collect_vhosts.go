@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package apache
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+
+	"golang.org/x/net/html"
+)
+
+// reWorkerAccesses matches the 'Acc' column: accesses this connection / this child / this slot.
+var reWorkerAccesses = regexp.MustCompile(`^(\d+)/(\d+)/(\d+)$`)
+
+type (
+	// workerRow is a row of the extended server-status workers table.
+	workerRow struct {
+		slot     string
+		vhost    string
+		accesses int64 // this slot
+		kbytes   int64 // this slot
+	}
+	workerSlot struct {
+		accesses int64
+		kbytes   int64
+	}
+	vhostStats struct {
+		requests int64
+		kbytes   int64
+	}
+)
+
+// collectVhosts attributes the worker slots accesses and traffic since the previous collection
+// to the virtual host the slot serves now. It is an approximation: a slot that served several
+// virtual hosts between the collections accounts everything to the last one.
+func (a *Apache) collectVhosts(mx map[string]int64) {
+	rows, err := a.scrapeExtendedStatusHTML()
+	if err != nil {
+		a.Warning(err)
+		return
+	}
+	if len(rows) == 0 {
+		a.Warning("no workers table in the server-status page (ExtendedStatus is off?), virtual hosts are not collected")
+		a.doVhosts = false
+		return
+	}
+
+	seen := make(map[string]bool)
+	slots := make(map[string]workerSlot, len(rows))
+
+	for _, w := range rows {
+		cur := workerSlot{accesses: w.accesses, kbytes: w.kbytes}
+		prev, hasPrev := a.workerSlots[w.slot]
+		slots[w.slot] = cur
+
+		if w.vhost == "" || (a.vhostSelector != nil && !a.vhostSelector.MatchString(w.vhost)) {
+			continue
+		}
+		seen[w.vhost] = true
+
+		v, ok := a.vhosts[w.vhost]
+		if !ok {
+			v = &vhostStats{}
+			a.vhosts[w.vhost] = v
+			a.Debugf("new virtual host '%s': adding charts", w.vhost)
+			a.addVhostCharts(w.vhost)
+		}
+		if hasPrev {
+			v.requests += counterDelta(cur.accesses, prev.accesses)
+			v.kbytes += counterDelta(cur.kbytes, prev.kbytes)
+		}
+	}
+	a.workerSlots = slots
+
+	for vhost, v := range a.vhosts {
+		if !seen[vhost] {
+			delete(a.vhosts, vhost)
+			a.Debugf("virtual host '%s' is gone: removing charts", vhost)
+			a.removeVhostCharts(vhost)
+			continue
+		}
+		px := "vhost_" + vhost + "_"
+		mx[px+"requests"] = v.requests
+		mx[px+"kbytes"] = v.kbytes
+	}
+}
+
+// counterDelta returns the slot counter increase, the counters are reset when the worker is restarted.
+func counterDelta(cur, prev int64) int64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+func (a *Apache) scrapeExtendedStatusHTML() ([]workerRow, error) {
+	req, err := web.NewHTTPRequest(a.Request)
+	if err != nil {
+		return nil, err
+	}
+	// the HTML page is the same URL without the 'auto' query parameter
+	q := req.URL.Query()
+	q.Del("auto")
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error on HTTP request '%s': %v", req.URL, err)
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("'%s' returned HTTP status code: %d", req.URL, resp.StatusCode)
+	}
+
+	tables, err := parseHTMLTables(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error on parsing '%s': %v", req.URL, err)
+	}
+
+	return parseWorkersTable(tables), nil
+}
+
+// parseHTMLTables returns the text of the table cells: tables, rows, cells.
+func parseHTMLTables(r io.Reader) ([][][]string, error) {
+	var tables [][][]string
+	var table [][]string
+	var row []string
+	var cell strings.Builder
+	var inTable, inCell bool
+
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return tables, nil
+			}
+			return nil, z.Err()
+		case html.TextToken:
+			if inCell {
+				cell.Write(z.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "table":
+				inTable, table = true, nil
+			case "tr":
+				row = nil
+			case "td", "th":
+				inCell = inTable
+				cell.Reset()
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "table":
+				if inTable && len(table) > 0 {
+					tables = append(tables, table)
+				}
+				inTable = false
+			case "tr":
+				if inTable && len(row) > 0 {
+					table = append(table, row)
+				}
+				row = nil
+			case "td", "th":
+				if inCell {
+					row = append(row, strings.Join(strings.Fields(cell.String()), " "))
+				}
+				inCell = false
+			}
+		}
+	}
+}
+
+// parseWorkersTable finds the workers table by its structure: a row with the 'Acc' (n/n/n) column.
+// The column positions are taken from the header if it has the known names, the httpd 2.4 layout is assumed otherwise.
+func parseWorkersTable(tables [][][]string) []workerRow {
+	for _, table := range tables {
+		cols, ok := findWorkersColumns(table)
+		if !ok {
+			continue
+		}
+
+		var rows []workerRow
+		for i, row := range table {
+			if cols.max() >= len(row) {
+				continue
+			}
+			m := reWorkerAccesses.FindStringSubmatch(row[cols.acc])
+			if m == nil {
+				continue
+			}
+			slot := strconv.Itoa(i)
+			if cols.srv >= 0 {
+				slot = row[cols.srv]
+			}
+			accesses, _ := strconv.ParseInt(m[3], 10, 64)
+			mbytes, _ := strconv.ParseFloat(row[cols.slot], 64)
+
+			rows = append(rows, workerRow{
+				slot:     slot,
+				vhost:    row[cols.vhost],
+				accesses: accesses,
+				kbytes:   int64(mbytes * 1024),
+			})
+		}
+		if len(rows) > 0 {
+			return rows
+		}
+	}
+	return nil
+}
+
+type workersColumns struct {
+	srv, acc, slot, vhost int
+}
+
+func (c workersColumns) max() int {
+	v := c.acc
+	for _, i := range []int{c.srv, c.slot, c.vhost} {
+		if i > v {
+			v = i
+		}
+	}
+	return v
+}
+
+func findWorkersColumns(table [][]string) (workersColumns, bool) {
+	cols := workersColumns{srv: -1, acc: -1, slot: -1, vhost: -1}
+
+	if len(table) > 0 {
+		for i, name := range table[0] {
+			switch name {
+			case "Srv":
+				cols.srv = i
+			case "Acc":
+				cols.acc = i
+			case "Slot":
+				cols.slot = i
+			case "VHost":
+				cols.vhost = i
+			}
+		}
+	}
+	if cols.acc >= 0 && cols.slot >= 0 && cols.vhost >= 0 {
+		return cols, true
+	}
+
+	// Srv PID Acc M CPU SS Req Dur Conn Child Slot Client Protocol VHost Request
+	for _, row := range table {
+		for i, v := range row {
+			if !reWorkerAccesses.MatchString(v) {
+				continue
+			}
+			cols = workersColumns{srv: 0, acc: i, slot: i + 8, vhost: len(row) - 2}
+			if cols.slot >= cols.vhost {
+				return cols, false
+			}
+			return cols, true
+		}
+	}
+	return cols, false
+}
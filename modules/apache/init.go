@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -23,3 +24,10 @@ func (a Apache) verifyConfig() error {
 func (a Apache) initHTTPClient() (*http.Client, error) {
 	return web.NewHTTPClient(a.Client)
 }
+
+func (a Apache) initVhostSelector() (matcher.Matcher, error) {
+	if a.VhostSelector == "" {
+		return nil, nil
+	}
+	return matcher.NewSimplePatternsMatcher(a.VhostSelector)
+}
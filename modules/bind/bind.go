@@ -43,19 +43,23 @@ func New() *Bind {
 	}
 
 	return &Bind{
-		Config: config,
-		charts: &Charts{},
+		Config:         config,
+		charts:         &Charts{},
+		collectedViews: make(map[string]bool),
+		collectedZones: make(map[zoneKey]bool),
 	}
 }
 
 type bindAPIClient interface {
 	serverStats() (*serverStats, error)
+	zonesStats() (*zonesStats, error)
 }
 
 // Config is the Bind module configuration.
 type Config struct {
 	web.HTTP   `yaml:",inline"`
 	PermitView string `yaml:"permit_view"`
+	PermitZone string `yaml:"permit_zone"`
 }
 
 // Bind Bind module.
@@ -65,7 +69,17 @@ type Bind struct {
 
 	bindAPIClient
 	permitView matcher.Matcher
+	permitZone matcher.Matcher
 	charts     *Charts
+
+	// views and zones come and go with 'rndc reconfig', their charts are removed when they disappear
+	collectedViews map[string]bool
+	collectedZones map[zoneKey]bool
+}
+
+type zoneKey struct {
+	view string
+	zone string
 }
 
 // Cleanup makes cleanup.
@@ -103,6 +117,15 @@ func (b *Bind) Init() bool {
 		b.permitView = matcher.WithCache(m)
 	}
 
+	if b.PermitZone != "" {
+		m, err := matcher.NewSimplePatternsMatcher(b.PermitZone)
+		if err != nil {
+			b.Errorf("error on creating permitZone matcher : %v", err)
+			return false
+		}
+		b.permitZone = matcher.WithCache(m)
+	}
+
 	return true
 }
 
@@ -127,6 +150,19 @@ func (b *Bind) Collect() map[string]int64 {
 	}
 	b.collectServerStats(metrics, s)
 
+	if b.permitView != nil {
+		b.collectViewsStats(metrics, s)
+	}
+
+	if b.permitZone != nil {
+		z, err := b.zonesStats()
+		if err != nil {
+			b.Warning(err)
+		} else {
+			b.collectZonesStats(metrics, z)
+		}
+	}
+
 	return metrics
 }
 
@@ -216,14 +252,18 @@ func (b *Bind) collectServerStats(metrics map[string]int64, stats *serverStats)
 		}
 	}
 
-	if !(b.permitView != nil && len(stats.Views) > 0) {
-		return
-	}
+}
+
+func (b *Bind) collectViewsStats(metrics map[string]int64, stats *serverStats) {
+	var chart *Chart
+	seen := make(map[string]bool)
 
 	for name, view := range stats.Views {
 		if !b.permitView.MatchString(name) {
 			continue
 		}
+		seen[name] = true
+		b.collectedViews[name] = true
 		r := view.Resolver
 
 		delete(r.Stats, "BucketSize")
@@ -249,14 +289,10 @@ func (b *Bind) collectServerStats(metrics map[string]int64, stats *serverStats)
 
 			chartID := fmt.Sprintf(chartKey, name)
 
-			if !b.charts.Has(chartID) {
-				chart = charts[chartKey].Copy()
-				chart.ID = chartID
-				chart.Fam = fmt.Sprintf(chart.Fam, name)
-				_ = b.charts.Add(chart)
+			if chart = b.charts.Get(chartID); chart == nil {
+				chart = b.addViewChart(chartKey, name)
 			}
 
-			chart = b.charts.Get(chartID)
 			dimID := fmt.Sprintf("%s_%s", name, key)
 
 			if !chart.HasDim(dimID) {
@@ -267,18 +303,23 @@ func (b *Bind) collectServerStats(metrics map[string]int64, stats *serverStats)
 			metrics[dimID] = val
 		}
 
+		if len(r.Stats) > 0 {
+			if !b.charts.Has(fmt.Sprintf(keyResolverFailures, name)) {
+				b.addViewChart(keyResolverFailures, name)
+			}
+
+			// the zero counters are omitted in the JSON output
+			metrics[name+"_SERVFAIL"] = r.Stats["SERVFAIL"]
+			metrics[name+"_QueryTimeout"] = r.Stats["QueryTimeout"]
+		}
+
 		if len(r.QTypes) > 0 {
 			chartID := fmt.Sprintf(keyResolverInQTypes, name)
 
-			if !b.charts.Has(chartID) {
-				chart = charts[keyResolverInQTypes].Copy()
-				chart.ID = chartID
-				chart.Fam = fmt.Sprintf(chart.Fam, name)
-				_ = b.charts.Add(chart)
+			if chart = b.charts.Get(chartID); chart == nil {
+				chart = b.addViewChart(keyResolverInQTypes, name)
 			}
 
-			chart = b.charts.Get(chartID)
-
 			for key, val := range r.QTypes {
 				dimID := fmt.Sprintf("%s_%s", name, key)
 				if !chart.HasDim(dimID) {
@@ -290,20 +331,60 @@ func (b *Bind) collectServerStats(metrics map[string]int64, stats *serverStats)
 		}
 
 		if len(r.CacheStats) > 0 {
-			chartID := fmt.Sprintf(keyResolverCacheHits, name)
-
-			if !b.charts.Has(chartID) {
-				chart = charts[keyResolverCacheHits].Copy()
-				chart.ID = chartID
-				chart.Fam = fmt.Sprintf(chart.Fam, name)
-				_ = b.charts.Add(chart)
-				for _, dim := range chart.Dims {
-					dim.ID = fmt.Sprintf(dim.ID, name)
-				}
+			if !b.charts.Has(fmt.Sprintf(keyResolverCacheHits, name)) {
+				b.addViewChart(keyResolverCacheHits, name)
 			}
 
 			metrics[name+"_CacheHits"] = r.CacheStats["CacheHits"]
 			metrics[name+"_CacheMisses"] = r.CacheStats["CacheMisses"]
 		}
 	}
+
+	for name := range b.collectedViews {
+		if !seen[name] {
+			delete(b.collectedViews, name)
+			b.removeViewCharts(name)
+		}
+	}
+}
+
+func (b *Bind) collectZonesStats(metrics map[string]int64, stats *zonesStats) {
+	seen := make(map[zoneKey]bool)
+
+	for viewName, view := range stats.Views {
+		for _, zone := range view.Zones {
+			if !b.permitZone.MatchString(zone.Name) {
+				continue
+			}
+
+			key := zoneKey{view: viewName, zone: zone.Name}
+			seen[key] = true
+			if !b.collectedZones[key] {
+				b.collectedZones[key] = true
+				b.addZoneCharts(viewName, zone.Name)
+			}
+
+			px := fmt.Sprintf("zone_%s_%s_", viewName, zone.Name)
+
+			if zone.Serial >= 0 {
+				metrics[px+"serial"] = int64(zone.Serial)
+			}
+
+			c := zone.RCodes
+			metrics[px+"notify_out"] = c["NotifyOutv4"] + c["NotifyOutv6"]
+			metrics[px+"notify_in"] = c["NotifyInv4"] + c["NotifyInv6"]
+			metrics[px+"notify_rej"] = c["NotifyRej"]
+			metrics[px+"axfr_req"] = c["AXFRReqv4"] + c["AXFRReqv6"]
+			metrics[px+"ixfr_req"] = c["IXFRReqv4"] + c["IXFRReqv6"]
+			metrics[px+"xfr_success"] = c["XfrSuccess"]
+			metrics[px+"xfr_fail"] = c["XfrFail"]
+		}
+	}
+
+	for key := range b.collectedZones {
+		if !seen[key] {
+			delete(b.collectedZones, key)
+			b.removeZoneCharts(key.view, key.zone)
+		}
+	}
 }
@@ -6,8 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,6 +19,8 @@ import (
 var (
 	jsonServerData, _ = os.ReadFile("testdata/query-server.json")
 	xmlServerData, _  = os.ReadFile("testdata/query-server.xml")
+	jsonZonesData, _  = os.ReadFile("testdata/query-zones.json")
+	xmlZonesData, _   = os.ReadFile("testdata/query-zones.xml")
 )
 
 func TestNew(t *testing.T) {
@@ -242,7 +248,7 @@ func TestBind_CollectJSON(t *testing.T) {
 	}
 
 	assert.Equal(t, expected, job.Collect())
-	assert.Len(t, *job.charts, 17)
+	assert.Len(t, *job.charts, 18)
 }
 
 func TestBind_CollectXML3(t *testing.T) {
@@ -495,7 +501,135 @@ func TestBind_CollectXML3(t *testing.T) {
 	}
 
 	assert.Equal(t, expected, job.Collect())
-	assert.Len(t, *job.charts, 20)
+	assert.Len(t, *job.charts, 22)
+}
+
+func TestBind_CollectZones(t *testing.T) {
+	tests := map[string]struct {
+		path       string
+		serverData []byte
+		zonesData  []byte
+	}{
+		"JSON": {path: "/json/v1", serverData: jsonServerData, zonesData: jsonZonesData},
+		"XML3": {path: "/xml/v3", serverData: xmlServerData, zonesData: xmlZonesData},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						switch r.URL.Path {
+						case test.path + "/server":
+							_, _ = w.Write(test.serverData)
+						case test.path + "/zones":
+							_, _ = w.Write(test.zonesData)
+						}
+					}))
+			defer ts.Close()
+
+			job := New()
+			job.URL = ts.URL + test.path
+			job.PermitZone = "example.*"
+
+			require.True(t, job.Init())
+			mx := job.Collect()
+			require.NotNil(t, mx)
+
+			expected := map[string]int64{
+				"zone__default_example.com_serial":      2019020601,
+				"zone__default_example.com_notify_out":  1218,
+				"zone__default_example.com_notify_in":   0,
+				"zone__default_example.com_notify_rej":  0,
+				"zone__default_example.com_axfr_req":    0,
+				"zone__default_example.com_ixfr_req":    0,
+				"zone__default_example.com_xfr_success": 0,
+				"zone__default_example.com_xfr_fail":    0,
+				"zone__default_example.org_serial":      2019013102,
+				"zone__default_example.org_notify_out":  0,
+				"zone__default_example.org_notify_in":   39,
+				"zone__default_example.org_notify_rej":  3,
+				"zone__default_example.org_axfr_req":    4,
+				"zone__default_example.org_ixfr_req":    36,
+				"zone__default_example.org_xfr_success": 31,
+				"zone__default_example.org_xfr_fail":    9,
+				"zone__default_example.net_notify_out":  0,
+				"zone__default_example.net_notify_in":   0,
+				"zone__default_example.net_notify_rej":  0,
+				"zone__default_example.net_axfr_req":    0,
+				"zone__default_example.net_ixfr_req":    0,
+				"zone__default_example.net_xfr_success": 0,
+				"zone__default_example.net_xfr_fail":    0,
+			}
+			for k, v := range expected {
+				assert.Equalf(t, v, mx[k], "metric '%s'", k)
+			}
+			assert.NotContains(t, mx, "zone__default_example.net_serial")
+			assert.NotContains(t, mx, "zone__bind_version.bind_serial")
+
+			chart := job.Charts().Get("zone_transfers__default_example_org")
+			require.NotNil(t, chart)
+			assert.Equal(t, []module.Label{
+				{Key: "view", Value: "_default"},
+				{Key: "zone", Value: "example.org"},
+			}, chart.Labels)
+
+			var zoneCharts int
+			for _, chart := range *job.Charts() {
+				if strings.HasPrefix(chart.ID, "zone_") {
+					zoneCharts++
+				}
+			}
+			assert.Equal(t, len(zoneChartKeys)*3, zoneCharts)
+		})
+	}
+}
+
+func TestBind_ViewsAndZonesLifecycle(t *testing.T) {
+	zonesData := jsonZonesData
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/json/v1/server":
+					_, _ = w.Write(jsonServerData)
+				case "/json/v1/zones":
+					_, _ = w.Write(zonesData)
+				}
+			}))
+	defer ts.Close()
+
+	job := New()
+	job.URL = ts.URL + "/json/v1"
+	job.PermitView = "*"
+	job.PermitZone = "*"
+
+	require.True(t, job.Init())
+	require.NotNil(t, job.Collect())
+
+	chart := job.Charts().Get("view_resolver_failures__default")
+	require.NotNil(t, chart)
+	assert.Equal(t, []module.Label{{Key: "view", Value: "_default"}}, chart.Labels)
+	require.NotNil(t, job.Charts().Get("zone_serial__bind_version_bind"))
+
+	// a reconfig removed the zone
+	zonesData = []byte(`{"views":{"_default":{"zones":[{"name":"example.com","serial":2019020602}]}}}`)
+	job.permitView, _ = matcher.NewSimplePatternsMatcher("!_default *")
+	mx := job.Collect()
+	require.NotNil(t, mx)
+
+	assert.Equal(t, int64(2019020602), mx["zone__default_example.com_serial"])
+	for _, chart := range *job.Charts() {
+		removed := chart.Obsolete
+		switch {
+		case strings.HasPrefix(chart.ID, "view_") && strings.HasSuffix(chart.ID, "__default"):
+			assert.Truef(t, removed, "chart '%s' is not removed", chart.ID)
+		case strings.HasPrefix(chart.ID, "zone_") && !strings.HasSuffix(chart.ID, "__default_example_com"):
+			assert.Truef(t, removed, "chart '%s' is not removed", chart.ID)
+		default:
+			assert.Falsef(t, removed, "chart '%s' is removed", chart.ID)
+		}
+	}
 }
 
 func TestBind_InvalidData(t *testing.T) {
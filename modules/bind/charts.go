@@ -3,6 +3,9 @@
 package bind
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/netdata/go.d.plugin/agent/module"
 )
 
@@ -39,6 +42,29 @@ const (
 	keyResolverInQTypes  = "view_resolver_qtypes_%s"
 	keyResolverCacheHits = "view_resolver_cachehits_%s"
 	keyResolverNumFetch  = "view_resolver_numfetch_%s"
+	keyResolverFailures  = "view_resolver_failures_%s"
+
+	keyZoneSerial           = "zone_serial_%s_%s"
+	keyZoneNotifies         = "zone_notifies_%s_%s"
+	keyZoneTransferRequests = "zone_transfer_requests_%s_%s"
+	keyZoneTransfers        = "zone_transfers_%s_%s"
+)
+
+var (
+	viewChartKeys = []string{
+		keyResolverStats,
+		keyResolverRTT,
+		keyResolverInQTypes,
+		keyResolverCacheHits,
+		keyResolverNumFetch,
+		keyResolverFailures,
+	}
+	zoneChartKeys = []string{
+		keyZoneSerial,
+		keyZoneNotifies,
+		keyZoneTransferRequests,
+		keyZoneTransfers,
+	}
 )
 
 var charts = map[string]Chart{
@@ -193,4 +219,133 @@ var charts = map[string]Chart{
 			{ID: "%s_CacheMisses", Name: "misses", Algo: module.Incremental, Mul: -1},
 		},
 	},
+	keyResolverFailures: {
+		ID:       keyResolverFailures,
+		Title:    "Resolver Failures",
+		Units:    "failures/s",
+		Fam:      "view %s",
+		Ctx:      "bind.resolver_failures",
+		Priority: basePriority + 27,
+		Dims: Dims{
+			{ID: "%s_SERVFAIL", Name: "servfail", Algo: module.Incremental},
+			{ID: "%s_QueryTimeout", Name: "timeouts", Algo: module.Incremental},
+		},
+	},
+
+	keyZoneSerial: {
+		ID:       keyZoneSerial,
+		Title:    "Zone Serial Number",
+		Units:    "serial",
+		Fam:      "zones",
+		Ctx:      "bind.zone_serial",
+		Priority: basePriority + 40,
+		Dims: Dims{
+			{ID: "zone_%s_%s_serial", Name: "serial"},
+		},
+	},
+	keyZoneNotifies: {
+		ID:       keyZoneNotifies,
+		Title:    "Zone Notifies",
+		Units:    "notifies/s",
+		Fam:      "zones",
+		Ctx:      "bind.zone_notifies",
+		Priority: basePriority + 41,
+		Dims: Dims{
+			{ID: "zone_%s_%s_notify_out", Name: "sent", Algo: module.Incremental},
+			{ID: "zone_%s_%s_notify_in", Name: "received", Algo: module.Incremental},
+			{ID: "zone_%s_%s_notify_rej", Name: "rejected", Algo: module.Incremental},
+		},
+	},
+	keyZoneTransferRequests: {
+		ID:       keyZoneTransferRequests,
+		Title:    "Zone Transfer Requests",
+		Units:    "requests/s",
+		Fam:      "zones",
+		Ctx:      "bind.zone_transfer_requests",
+		Type:     module.Stacked,
+		Priority: basePriority + 42,
+		Dims: Dims{
+			{ID: "zone_%s_%s_axfr_req", Name: "axfr", Algo: module.Incremental},
+			{ID: "zone_%s_%s_ixfr_req", Name: "ixfr", Algo: module.Incremental},
+		},
+	},
+	keyZoneTransfers: {
+		ID:       keyZoneTransfers,
+		Title:    "Zone Transfers",
+		Units:    "transfers/s",
+		Fam:      "zones",
+		Ctx:      "bind.zone_transfers",
+		Priority: basePriority + 43,
+		Dims: Dims{
+			{ID: "zone_%s_%s_xfr_success", Name: "success", Algo: module.Incremental},
+			{ID: "zone_%s_%s_xfr_fail", Name: "failed", Algo: module.Incremental},
+		},
+	},
+}
+
+func (b *Bind) addViewChart(chartKey, view string) *Chart {
+	chart := charts[chartKey].Copy()
+	chart.ID = fmt.Sprintf(chartKey, view)
+	chart.Fam = fmt.Sprintf(chart.Fam, view)
+	chart.Labels = []module.Label{
+		{Key: "view", Value: view},
+	}
+	for _, dim := range chart.Dims {
+		dim.ID = fmt.Sprintf(dim.ID, view)
+	}
+
+	if err := b.charts.Add(chart); err != nil {
+		b.Warning(err)
+	}
+	return chart
+}
+
+func (b *Bind) removeViewCharts(view string) {
+	ids := make(map[string]bool)
+	for _, key := range viewChartKeys {
+		ids[fmt.Sprintf(key, view)] = true
+	}
+
+	for _, chart := range *b.charts {
+		if ids[chart.ID] {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+func (b *Bind) addZoneCharts(view, zone string) {
+	for _, key := range zoneChartKeys {
+		chart := charts[key].Copy()
+		chart.ID = zoneChartID(key, view, zone)
+		chart.Labels = []module.Label{
+			{Key: "view", Value: view},
+			{Key: "zone", Value: zone},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, view, zone)
+		}
+
+		if err := b.charts.Add(chart); err != nil {
+			b.Warning(err)
+		}
+	}
+}
+
+func (b *Bind) removeZoneCharts(view, zone string) {
+	ids := make(map[string]bool)
+	for _, key := range zoneChartKeys {
+		ids[zoneChartID(key, view, zone)] = true
+	}
+
+	for _, chart := range *b.charts {
+		if ids[chart.ID] {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+func zoneChartID(chartKey, view, zone string) string {
+	return fmt.Sprintf(chartKey, view, strings.ReplaceAll(zone, ".", "_"))
 }
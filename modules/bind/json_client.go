@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
@@ -33,6 +34,40 @@ type jsonViewResolver struct {
 	CacheStats map[string]int64
 }
 
+type zonesStats = jsonZonesStats
+
+type jsonZonesStats struct {
+	Views map[string]jsonZonesView
+}
+
+type jsonZonesView struct {
+	Zones []jsonZone
+}
+
+type jsonZone struct {
+	Name   string
+	Class  string
+	Serial zoneSerial
+	// the zone maintenance counters, they are reported if 'zone-statistics' is enabled
+	RCodes map[string]int64
+}
+
+// zoneSerial is the zone SOA serial number, -1 if the zone is not loaded (BIND reports '-').
+type zoneSerial int64
+
+func (s *zoneSerial) UnmarshalJSON(data []byte) error {
+	*s = parseZoneSerial(string(data))
+	return nil
+}
+
+func parseZoneSerial(s string) zoneSerial {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v < 0 {
+		return -1
+	}
+	return zoneSerial(v)
+}
+
 func newJSONClient(client *http.Client, request web.Request) *jsonClient {
 	return &jsonClient{httpClient: client, request: request}
 }
@@ -43,35 +78,50 @@ type jsonClient struct {
 }
 
 func (c jsonClient) serverStats() (*serverStats, error) {
+	stats := &jsonServerStats{}
+	if err := c.query("/server", stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (c jsonClient) zonesStats() (*zonesStats, error) {
+	stats := &jsonZonesStats{}
+	if err := c.query("/zones", stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (c jsonClient) query(urlPath string, stats interface{}) error {
 	req := c.request.Copy()
 	u, err := url.Parse(req.URL)
 	if err != nil {
-		return nil, fmt.Errorf("error on parsing URL: %v", err)
+		return fmt.Errorf("error on parsing URL: %v", err)
 	}
 
-	u.Path = path.Join(u.Path, "/server")
+	u.Path = path.Join(u.Path, urlPath)
 	req.URL = u.String()
 
 	httpReq, err := web.NewHTTPRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("error on creating HTTP request: %v", err)
+		return fmt.Errorf("error on creating HTTP request: %v", err)
 	}
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("error on request : %v", err)
+		return fmt.Errorf("error on request : %v", err)
 	}
 	defer closeBody(resp)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s returned HTTP status %d", httpReq.URL, resp.StatusCode)
+		return fmt.Errorf("%s returned HTTP status %d", httpReq.URL, resp.StatusCode)
 	}
 
-	stats := &jsonServerStats{}
 	if err = json.NewDecoder(resp.Body).Decode(stats); err != nil {
-		return nil, fmt.Errorf("error on decoding response from %s : %v", httpReq.URL, err)
+		return fmt.Errorf("error on decoding response from %s : %v", httpReq.URL, err)
 	}
-	return stats, nil
+	return nil
 }
 
 func closeBody(resp *http.Response) {
@@ -34,6 +34,22 @@ type xml3View struct {
 	CounterGroups []xml3CounterGroup `xml:"counters"`
 }
 
+type xml3ZonesStats struct {
+	Views []xml3ZonesView `xml:"views>view"`
+}
+
+type xml3ZonesView struct {
+	Name  string     `xml:"name,attr"`
+	Zones []xml3Zone `xml:"zones>zone"`
+}
+
+type xml3Zone struct {
+	Name          string             `xml:"name,attr"`
+	Class         string             `xml:"rdataclass,attr"`
+	Serial        string             `xml:"serial"`
+	CounterGroups []xml3CounterGroup `xml:"counters"`
+}
+
 func newXML3Client(client *http.Client, request web.Request) *xml3Client {
 	return &xml3Client{httpClient: client, request: request}
 }
@@ -44,35 +60,50 @@ type xml3Client struct {
 }
 
 func (c xml3Client) serverStats() (*serverStats, error) {
+	stats := xml3Stats{}
+	if err := c.query("/server", &stats); err != nil {
+		return nil, err
+	}
+	return convertXML(stats), nil
+}
+
+func (c xml3Client) zonesStats() (*zonesStats, error) {
+	stats := xml3ZonesStats{}
+	if err := c.query("/zones", &stats); err != nil {
+		return nil, err
+	}
+	return convertXMLZones(stats), nil
+}
+
+func (c xml3Client) query(urlPath string, stats interface{}) error {
 	req := c.request.Copy()
 	u, err := url.Parse(req.URL)
 	if err != nil {
-		return nil, fmt.Errorf("error on parsing URL: %v", err)
+		return fmt.Errorf("error on parsing URL: %v", err)
 	}
 
-	u.Path = path.Join(u.Path, "/server")
+	u.Path = path.Join(u.Path, urlPath)
 	req.URL = u.String()
 
 	httpReq, err := web.NewHTTPRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("error on creating HTTP request: %v", err)
+		return fmt.Errorf("error on creating HTTP request: %v", err)
 	}
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("error on request : %v", err)
+		return fmt.Errorf("error on request : %v", err)
 	}
 	defer closeBody(resp)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s returned HTTP status %d", httpReq.URL, resp.StatusCode)
+		return fmt.Errorf("%s returned HTTP status %d", httpReq.URL, resp.StatusCode)
 	}
 
-	stats := xml3Stats{}
-	if err = xml.NewDecoder(resp.Body).Decode(&stats); err != nil {
-		return nil, fmt.Errorf("error on decoding response from %s : %v", httpReq.URL, err)
+	if err = xml.NewDecoder(resp.Body).Decode(stats); err != nil {
+		return fmt.Errorf("error on decoding response from %s : %v", httpReq.URL, err)
 	}
-	return convertXML(stats), nil
+	return nil
 }
 
 func convertXML(xmlStats xml3Stats) *serverStats {
@@ -131,3 +162,32 @@ func convertXML(xmlStats xml3Stats) *serverStats {
 	}
 	return &stats
 }
+
+func convertXMLZones(xmlStats xml3ZonesStats) *zonesStats {
+	stats := zonesStats{
+		Views: make(map[string]jsonZonesView),
+	}
+
+	for _, view := range xmlStats.Views {
+		var zones []jsonZone
+		for _, xmlZone := range view.Zones {
+			zone := jsonZone{
+				Name:   xmlZone.Name,
+				Class:  xmlZone.Class,
+				Serial: parseZoneSerial(xmlZone.Serial),
+				RCodes: make(map[string]int64),
+			}
+			for _, group := range xmlZone.CounterGroups {
+				if group.Type != "rcode" {
+					continue
+				}
+				for _, v := range group.Counters {
+					zone.RCodes[v.Name] = v.Value
+				}
+			}
+			zones = append(zones, zone)
+		}
+		stats.Views[view.Name] = jsonZonesView{Zones: zones}
+	}
+	return &stats
+}
@@ -324,3 +324,69 @@ var zoneCharts = func() Charts {
 	_ = c.Remove("per_%s_%s_dns_request_count_total_per_status")
 	return *c
 }()
+
+var upstreamCharts = Charts{
+	{
+		ID:    "forward_%s_dns_request_count_total",
+		Title: "Number Of DNS Requests Forwarded To The Upstream",
+		Units: "requests/s",
+		Fam:   "forward",
+		Ctx:   "coredns.forward_upstream_dns_request_count_total",
+		Dims: Dims{
+			{ID: "forward_%s_request_total", Name: "requests", Algo: module.Incremental},
+		},
+	},
+	{
+		ID:    "forward_%s_dns_responses_count_total_per_rcode",
+		Title: "Number Of Upstream Responses Per Rcode",
+		Units: "responses/s",
+		Fam:   "forward",
+		Ctx:   "coredns.forward_upstream_dns_responses_count_total_per_rcode",
+		Type:  module.Stacked,
+		Dims: Dims{
+			{ID: "forward_%s_response_per_rcode_NOERROR", Name: "NOERROR", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_FORMERR", Name: "FORMERR", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_SERVFAIL", Name: "SERVFAIL", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_NXDOMAIN", Name: "NXDOMAIN", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_NOTIMP", Name: "NOTIMP", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_REFUSED", Name: "REFUSED", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_YXDOMAIN", Name: "YXDOMAIN", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_YXRRSET", Name: "YXRRSET", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_NXRRSET", Name: "NXRRSET", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_NOTAUTH", Name: "NOTAUTH", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_NOTZONE", Name: "NOTZONE", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_BADSIG", Name: "BADSIG", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_BADKEY", Name: "BADKEY", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_BADTIME", Name: "BADTIME", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_BADMODE", Name: "BADMODE", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_BADNAME", Name: "BADNAME", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_BADALG", Name: "BADALG", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_BADTRUNC", Name: "BADTRUNC", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_BADCOOKIE", Name: "BADCOOKIE", Algo: module.Incremental},
+			{ID: "forward_%s_response_per_rcode_other", Name: "other", Algo: module.Incremental},
+		},
+	},
+	{
+		ID:    "forward_%s_healthcheck_failures_total",
+		Title: "Number Of Upstream Health Check Failures",
+		Units: "failures/s",
+		Fam:   "forward",
+		Ctx:   "coredns.forward_upstream_healthcheck_failures_total",
+		Dims: Dims{
+			{ID: "forward_%s_healthcheck_failures_total", Name: "failures", Algo: module.Incremental},
+		},
+	},
+}
+
+var k8sCharts = Charts{
+	{
+		ID:    "kubernetes_dns_programming_duration",
+		Title: "Average Kubernetes DNS Programming Duration",
+		Units: "seconds",
+		Fam:   "kubernetes",
+		Ctx:   "coredns.kubernetes_dns_programming_duration",
+		Dims: Dims{
+			{ID: "kubernetes_dns_programming_duration_avg", Name: "duration", Div: 1000},
+		},
+	},
+}
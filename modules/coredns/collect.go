@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/blang/semver/v4"
+	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
 	"github.com/netdata/go.d.plugin/pkg/stm"
 )
@@ -18,10 +19,21 @@ const (
 	metricRequestTypeCountTotal169orOlder   = "coredns_dns_request_type_count_total"
 	metricResponseRcodeCountTotal169orOlder = "coredns_dns_response_rcode_count_total"
 
+	metricForwardRequestCountTotal169orOlder       = "coredns_forward_request_count_total"
+	metricForwardResponseRcodeCountTotal169orOlder = "coredns_forward_response_rcode_count_total"
+	metricForwardHealthcheckFailureCount169orOlder = "coredns_forward_healthcheck_failure_count_total"
+
 	metricPanicCountTotal170orNewer         = "coredns_panics_total"
 	metricRequestCountTotal170orNewer       = "coredns_dns_requests_total"
 	metricRequestTypeCountTotal170orNewer   = "coredns_dns_requests_total"
 	metricResponseRcodeCountTotal170orNewer = "coredns_dns_responses_total"
+
+	metricForwardRequestCountTotal170orNewer       = "coredns_forward_requests_total"
+	metricForwardResponseRcodeCountTotal170orNewer = "coredns_forward_responses_total"
+	metricForwardHealthcheckFailureCount170orNewer = "coredns_forward_healthcheck_failures_total"
+
+	metricK8sDNSProgrammingDurationSum   = "coredns_kubernetes_dns_programming_duration_seconds_sum"
+	metricK8sDNSProgrammingDurationCount = "coredns_kubernetes_dns_programming_duration_seconds_count"
 )
 
 var (
@@ -45,6 +57,18 @@ type requestMetricsNames struct {
 	requestCountTotal       string
 	requestTypeCountTotal   string
 	responseRcodeCountTotal string
+
+	forwardRequestCountTotal       string
+	forwardResponseRcodeCountTotal string
+	forwardHealthcheckFailureCount string
+}
+
+// k8sDurationState keeps the previous values of the DNS programming duration histogram, the average is
+// calculated for the interval between the collections (0 if there are no new samples).
+type k8sDurationState struct {
+	hasPrev   bool
+	prevSum   float64
+	prevCount float64
 }
 
 func (cd *CoreDNS) collect() (map[string]int64, error) {
@@ -87,6 +111,14 @@ func (cd *CoreDNS) collect() (map[string]int64, error) {
 		cd.collectPerZoneResponsesPerRcode(mx, raw)
 	}
 
+	if cd.perUpstreamMatcher != nil {
+		cd.collectPerUpstreamRequests(mx, raw)
+		cd.collectPerUpstreamResponsesPerRcode(mx, raw)
+		cd.collectPerUpstreamHealthcheckFailures(mx, raw)
+	}
+
+	cd.collectK8sDNSProgrammingDuration(mx, raw)
+
 	return stm.ToMap(mx), nil
 }
 
@@ -101,11 +133,17 @@ func (cd *CoreDNS) updateVersionDependentMetrics(raw prometheus.Series) {
 		cd.metricNames.requestCountTotal = metricRequestCountTotal169orOlder
 		cd.metricNames.requestTypeCountTotal = metricRequestTypeCountTotal169orOlder
 		cd.metricNames.responseRcodeCountTotal = metricResponseRcodeCountTotal169orOlder
+		cd.metricNames.forwardRequestCountTotal = metricForwardRequestCountTotal169orOlder
+		cd.metricNames.forwardResponseRcodeCountTotal = metricForwardResponseRcodeCountTotal169orOlder
+		cd.metricNames.forwardHealthcheckFailureCount = metricForwardHealthcheckFailureCount169orOlder
 	} else {
 		cd.metricNames.panicCountTotal = metricPanicCountTotal170orNewer
 		cd.metricNames.requestCountTotal = metricRequestCountTotal170orNewer
 		cd.metricNames.requestTypeCountTotal = metricRequestTypeCountTotal170orNewer
 		cd.metricNames.responseRcodeCountTotal = metricResponseRcodeCountTotal170orNewer
+		cd.metricNames.forwardRequestCountTotal = metricForwardRequestCountTotal170orNewer
+		cd.metricNames.forwardResponseRcodeCountTotal = metricForwardResponseRcodeCountTotal170orNewer
+		cd.metricNames.forwardHealthcheckFailureCount = metricForwardHealthcheckFailureCount170orNewer
 	}
 }
 
@@ -506,6 +544,102 @@ func (cd *CoreDNS) collectPerZoneResponsesPerRcode(mx *metrics, raw prometheus.S
 	}
 }
 
+// Per Upstream (forward plugin)
+
+func (cd *CoreDNS) collectPerUpstreamRequests(mx *metrics, raw prometheus.Series) {
+	for _, metric := range raw.FindByName(cd.metricNames.forwardRequestCountTotal) {
+		to := metric.Labels.Get("to")
+
+		if to == empty || !cd.perUpstreamMatcher.MatchString(to) {
+			continue
+		}
+
+		// 1.6.9 and older have 'proto' and 'family' labels
+		cd.upstreamMetrics(mx, to).Requests.Add(metric.Value)
+	}
+}
+
+func (cd *CoreDNS) collectPerUpstreamResponsesPerRcode(mx *metrics, raw prometheus.Series) {
+	for _, metric := range raw.FindByName(cd.metricNames.forwardResponseRcodeCountTotal) {
+		var (
+			rcode = metric.Labels.Get("rcode")
+			to    = metric.Labels.Get("to")
+		)
+
+		if rcode == empty || to == empty || !cd.perUpstreamMatcher.MatchString(to) {
+			continue
+		}
+
+		setResponsePerRcode(&cd.upstreamMetrics(mx, to).Response, metric.Value, rcode)
+	}
+}
+
+func (cd *CoreDNS) collectPerUpstreamHealthcheckFailures(mx *metrics, raw prometheus.Series) {
+	for _, metric := range raw.FindByName(cd.metricNames.forwardHealthcheckFailureCount) {
+		to := metric.Labels.Get("to")
+
+		if to == empty || !cd.perUpstreamMatcher.MatchString(to) {
+			continue
+		}
+
+		cd.upstreamMetrics(mx, to).HealthcheckFailures.Add(metric.Value)
+	}
+}
+
+func (cd *CoreDNS) upstreamMetrics(mx *metrics, to string) *upstream {
+	if !cd.collectedUpstreams[to] {
+		cd.addNewUpstreamCharts(to)
+		cd.collectedUpstreams[to] = true
+	}
+
+	if _, ok := mx.PerUpstream[to]; !ok {
+		mx.PerUpstream[to] = &upstream{}
+	}
+
+	return mx.PerUpstream[to]
+}
+
+// Kubernetes
+
+func (cd *CoreDNS) collectK8sDNSProgrammingDuration(mx *metrics, raw prometheus.Series) {
+	sums := raw.FindByName(metricK8sDNSProgrammingDurationSum)
+	counts := raw.FindByName(metricK8sDNSProgrammingDurationCount)
+
+	// the kubernetes plugin is not enabled
+	if len(sums) == 0 || len(counts) == 0 {
+		return
+	}
+
+	// the histogram is per 'service_kind'
+	var sum, count float64
+	for _, metric := range sums {
+		sum += metric.Value
+	}
+	for _, metric := range counts {
+		count += metric.Value
+	}
+
+	if !cd.hasK8sCharts {
+		cd.hasK8sCharts = true
+		_ = cd.charts.Add(*k8sCharts.Copy()...)
+	}
+
+	st := &cd.k8sDurationState
+	prevSum, prevCount, hasPrev := st.prevSum, st.prevCount, st.hasPrev
+	st.hasPrev, st.prevSum, st.prevCount = true, sum, count
+
+	// the first collection or CoreDNS restart: the lifetime average is not the interval value
+	if !hasPrev || count < prevCount {
+		return
+	}
+
+	var avg float64
+	if count > prevCount {
+		avg = (sum - prevSum) / (count - prevCount)
+	}
+	mx.K8sDNSProgrammingDuration = &avg
+}
+
 // ---
 
 func setRequestPerIPFamily(mx *request, value float64, family string) {
@@ -711,3 +845,18 @@ func (cd *CoreDNS) addNewZoneCharts(name string) {
 	}
 	_ = cd.charts.Add(*charts...)
 }
+
+func (cd *CoreDNS) addNewUpstreamCharts(name string) {
+	charts := upstreamCharts.Copy()
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, name)
+		chart.Labels = []module.Label{
+			{Key: "to", Value: name},
+		}
+
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, name)
+		}
+	}
+	_ = cd.charts.Add(*charts...)
+}
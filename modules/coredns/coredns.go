@@ -42,10 +42,11 @@ func New() *CoreDNS {
 		},
 	}
 	return &CoreDNS{
-		Config:           config,
-		charts:           summaryCharts.Copy(),
-		collectedServers: make(map[string]bool),
-		collectedZones:   make(map[string]bool),
+		Config:             config,
+		charts:             summaryCharts.Copy(),
+		collectedServers:   make(map[string]bool),
+		collectedZones:     make(map[string]bool),
+		collectedUpstreams: make(map[string]bool),
 	}
 }
 
@@ -54,21 +55,27 @@ type Config struct {
 	web.HTTP       `yaml:",inline"`
 	PerServerStats matcher.SimpleExpr `yaml:"per_server_stats"`
 	PerZoneStats   matcher.SimpleExpr `yaml:"per_zone_stats"`
+	// the forward plugin metrics have no server and zone labels, the upstreams are selected by the address
+	PerUpstreamStats matcher.SimpleExpr `yaml:"per_upstream_stats"`
 }
 
 // CoreDNS CoreDNS module.
 type CoreDNS struct {
 	module.Base
-	Config           `yaml:",inline"`
-	charts           *Charts
-	prom             prometheus.Prometheus
-	perServerMatcher matcher.Matcher
-	perZoneMatcher   matcher.Matcher
-	collectedServers map[string]bool
-	collectedZones   map[string]bool
-	skipVersionCheck bool
-	version          *semver.Version
-	metricNames      requestMetricsNames
+	Config             `yaml:",inline"`
+	charts             *Charts
+	prom               prometheus.Prometheus
+	perServerMatcher   matcher.Matcher
+	perZoneMatcher     matcher.Matcher
+	perUpstreamMatcher matcher.Matcher
+	collectedServers   map[string]bool
+	collectedZones     map[string]bool
+	collectedUpstreams map[string]bool
+	k8sDurationState   k8sDurationState
+	hasK8sCharts       bool
+	skipVersionCheck   bool
+	version            *semver.Version
+	metricNames        requestMetricsNames
 }
 
 // Cleanup makes cleanup.
@@ -99,6 +106,15 @@ func (cd *CoreDNS) Init() bool {
 		cd.perZoneMatcher = matcher.WithCache(m)
 	}
 
+	if !cd.PerUpstreamStats.Empty() {
+		m, err := cd.PerUpstreamStats.Parse()
+		if err != nil {
+			cd.Errorf("error on creating 'per_upstream_stats' matcher : %v", err)
+			return false
+		}
+		cd.perUpstreamMatcher = matcher.WithCache(m)
+	}
+
 	client, err := web.NewHTTPClient(cd.Client)
 	if err != nil {
 		cd.Errorf("error on creating http client : %v", err)
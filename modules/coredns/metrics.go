@@ -10,6 +10,7 @@ func newMetrics() *metrics {
 	mx := &metrics{}
 	mx.PerServer = make(map[string]*requestResponse)
 	mx.PerZone = make(map[string]*requestResponse)
+	mx.PerUpstream = make(map[string]*upstream)
 
 	return mx
 }
@@ -20,6 +21,16 @@ type metrics struct {
 	Summary       requestResponse             `stm:""`
 	PerServer     map[string]*requestResponse `stm:""`
 	PerZone       map[string]*requestResponse `stm:""`
+	PerUpstream   map[string]*upstream        `stm:"forward"`
+	// seconds, nil if the kubernetes plugin is not enabled
+	K8sDNSProgrammingDuration *float64 `stm:"kubernetes_dns_programming_duration_avg,1000,1"`
+}
+
+// https://github.com/coredns/coredns/blob/master/plugin/forward/README.md#metrics
+type upstream struct {
+	Requests            mtx.Gauge `stm:"request_total"`
+	Response            response  `stm:"response"`
+	HealthcheckFailures mtx.Gauge `stm:"healthcheck_failures_total"`
 }
 
 type requestResponse struct {
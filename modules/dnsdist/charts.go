@@ -2,7 +2,12 @@
 
 package dnsdist
 
-import "github.com/netdata/go.d.plugin/agent/module"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
 
 var charts = module.Charts{
 	{
@@ -53,6 +58,20 @@ var charts = module.Charts{
 			{ID: "trunc-failures", Name: "trunc failures", Algo: module.Incremental, Mul: -1},
 		},
 	},
+	{
+		ID:    "rule_actions",
+		Title: "Rule actions",
+		Units: "actions/s",
+		Fam:   "answers",
+		Ctx:   "dnsdist.rule_actions",
+		Dims: module.Dims{
+			{ID: "rule-drop", Name: "drop", Algo: module.Incremental},
+			{ID: "rule-nxdomain", Name: "nxdomain", Algo: module.Incremental},
+			{ID: "rule-refused", Name: "refused", Algo: module.Incremental},
+			{ID: "rule-servfail", Name: "servfail", Algo: module.Incremental},
+			{ID: "rule-truncated", Name: "truncated", Algo: module.Incremental},
+		},
+	},
 	{
 		ID:    "backend_responses",
 		Title: "Backend responses",
@@ -149,3 +168,102 @@ var charts = module.Charts{
 		},
 	},
 }
+
+const chartPxBackend = "backend_server_"
+
+var chartsTmplBackend = module.Charts{
+	chartTmplBackendQueries.Copy(),
+	chartTmplBackendErrors.Copy(),
+	chartTmplBackendOutstanding.Copy(),
+	chartTmplBackendLatency.Copy(),
+	chartTmplBackendState.Copy(),
+}
+
+var (
+	chartTmplBackendQueries = module.Chart{
+		ID:    chartPxBackend + "%s_queries",
+		Title: "Backend server queries",
+		Units: "queries/s",
+		Fam:   "backend servers",
+		Ctx:   "dnsdist.backend_server_queries",
+		Dims: module.Dims{
+			{ID: "backend_%s_queries", Name: "queries", Algo: module.Incremental},
+			{ID: "backend_%s_responses", Name: "responses", Algo: module.Incremental},
+		},
+	}
+	chartTmplBackendErrors = module.Chart{
+		ID:    chartPxBackend + "%s_errors",
+		Title: "Backend server errors",
+		Units: "errors/s",
+		Fam:   "backend servers",
+		Ctx:   "dnsdist.backend_server_errors",
+		Dims: module.Dims{
+			{ID: "backend_%s_drops", Name: "drops", Algo: module.Incremental},
+			{ID: "backend_%s_send_errors", Name: "send_errors", Algo: module.Incremental},
+		},
+	}
+	chartTmplBackendOutstanding = module.Chart{
+		ID:    chartPxBackend + "%s_outstanding",
+		Title: "Backend server outstanding queries",
+		Units: "queries",
+		Fam:   "backend servers",
+		Ctx:   "dnsdist.backend_server_outstanding_queries",
+		Dims: module.Dims{
+			{ID: "backend_%s_outstanding", Name: "outstanding"},
+		},
+	}
+	chartTmplBackendLatency = module.Chart{
+		ID:    chartPxBackend + "%s_latency",
+		Title: "Backend server average latency",
+		Units: "milliseconds",
+		Fam:   "backend servers",
+		Ctx:   "dnsdist.backend_server_latency",
+		Dims: module.Dims{
+			{ID: "backend_%s_latency", Name: "latency", Div: 1000},
+		},
+	}
+	chartTmplBackendState = module.Chart{
+		ID:    chartPxBackend + "%s_state",
+		Title: "Backend server state",
+		Units: "state",
+		Fam:   "backend servers",
+		Ctx:   "dnsdist.backend_server_state",
+		Dims: module.Dims{
+			{ID: "backend_%s_state_up", Name: "up"},
+			{ID: "backend_%s_state_down", Name: "down"},
+		},
+	}
+)
+
+func (d *DNSdist) addBackendCharts(srv backendServer) {
+	charts := chartsTmplBackend.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, cleanChartID(srv.Name))
+		chart.Labels = []module.Label{
+			{Key: "backend_name", Value: srv.Name},
+			{Key: "backend_address", Value: srv.Address},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, srv.Name)
+		}
+	}
+
+	if err := d.Charts().Add(*charts...); err != nil {
+		d.Warning(err)
+	}
+}
+
+func (d *DNSdist) removeBackendCharts(name string) {
+	for _, tmpl := range chartsTmplBackend {
+		if chart := d.Charts().Get(fmt.Sprintf(tmpl.ID, cleanChartID(name))); chart != nil {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+func cleanChartID(id string) string {
+	r := strings.NewReplacer(".", "_", ":", "_", " ", "_", "[", "", "]", "")
+	return strings.ToLower(r.Replace(id))
+}
@@ -4,6 +4,7 @@ package dnsdist
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,7 +15,13 @@ import (
 )
 
 const (
-	urlPathJSONStat = "/jsonstat"
+	urlPathJSONStat        = "/jsonstat"
+	urlPathAPIStatistics   = "/api/v1/servers/localhost/statistics"
+	urlPathAPIServerInfo   = "/api/v1/servers/localhost"
+	backendStateUp         = "up"
+	backendStateDown       = "down"
+	backendStateForcedUp   = "UP"
+	backendStateForcedDown = "DOWN"
 )
 
 func (d *DNSdist) collect() (map[string]int64, error) {
@@ -26,6 +33,11 @@ func (d *DNSdist) collect() (map[string]int64, error) {
 	collected := make(map[string]int64)
 	d.collectStatistic(collected, statistics)
 
+	// the statistics are collected even if the backends can't be
+	if err := d.collectBackends(collected); err != nil {
+		return collected, err
+	}
+
 	return collected, nil
 }
 
@@ -35,19 +47,108 @@ func (d *DNSdist) collectStatistic(collected map[string]int64, statistics *stati
 	}
 }
 
+func (d *DNSdist) collectBackends(collected map[string]int64) error {
+	info, err := d.scrapeServerInfo()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+
+	for _, srv := range info.Servers {
+		if srv.Name == "" || seen[srv.Name] {
+			continue
+		}
+		seen[srv.Name] = true
+
+		if !d.backends[srv.Name] {
+			d.backends[srv.Name] = true
+			d.Debugf("new backend server '%s' (%s): adding charts", srv.Name, srv.Address)
+			d.addBackendCharts(srv)
+		}
+
+		px := backendMetricPrefix(srv.Name)
+		collected[px+"queries"] = srv.Queries
+		collected[px+"responses"] = srv.Responses
+		collected[px+"drops"] = srv.Drops
+		collected[px+"send_errors"] = srv.SendErrors
+		collected[px+"outstanding"] = srv.Outstanding
+		collected[px+"latency"] = int64(srv.Latency * 1000)
+		collected[px+"state_up"] = boolToInt(srv.State == backendStateUp || srv.State == backendStateForcedUp)
+		collected[px+"state_down"] = boolToInt(srv.State == backendStateDown || srv.State == backendStateForcedDown)
+	}
+
+	for name := range d.backends {
+		if !seen[name] {
+			delete(d.backends, name)
+			d.Debugf("stale backend server '%s': removing charts", name)
+			d.removeBackendCharts(name)
+		}
+	}
+
+	return nil
+}
+
 func (d *DNSdist) scrapeStatistics() (*statisticMetrics, error) {
 	req, _ := web.NewHTTPRequest(d.Request)
 	req.URL.Path = urlPathJSONStat
 	req.URL.RawQuery = url.Values{"command": []string{"stats"}}.Encode()
 
 	var statistics statisticMetrics
-	if err := d.doOKDecode(req, &statistics); err != nil {
+	err := d.doOKDecode(req, &statistics)
+	if err == nil {
+		return &statistics, nil
+	}
+	if !errors.Is(err, errNotFound) {
+		return nil, err
+	}
+
+	d.Debugf("'%s' not found, falling back to the REST API statistics endpoint", req.URL)
+
+	return d.scrapeAPIStatistics()
+}
+
+func (d *DNSdist) scrapeAPIStatistics() (*statisticMetrics, error) {
+	req, _ := web.NewHTTPRequest(d.Request)
+	req.URL.Path = urlPathAPIStatistics
+
+	var items []statisticItem
+	if err := d.doOKDecode(req, &items); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64, len(items))
+	for _, item := range items {
+		values[item.Name] = item.Value
+	}
+
+	// the items have the same names as the jsonstat statistics
+	bs, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	var statistics statisticMetrics
+	if err := json.Unmarshal(bs, &statistics); err != nil {
 		return nil, err
 	}
 
 	return &statistics, nil
 }
 
+func (d *DNSdist) scrapeServerInfo() (*serverInfo, error) {
+	req, _ := web.NewHTTPRequest(d.Request)
+	req.URL.Path = urlPathAPIServerInfo
+
+	var info serverInfo
+	if err := d.doOKDecode(req, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+var errNotFound = errors.New("not found")
+
 func (d *DNSdist) doOKDecode(req *http.Request, in interface{}) error {
 	resp, err := d.httpClient.Do(req)
 	if err != nil {
@@ -55,6 +156,9 @@ func (d *DNSdist) doOKDecode(req *http.Request, in interface{}) error {
 	}
 	defer closeBody(resp)
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("'%s' returned HTTP status code: %d (%w)", req.URL, resp.StatusCode, errNotFound)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("'%s' returned HTTP status code: %d", req.URL, resp.StatusCode)
 	}
@@ -72,3 +176,14 @@ func closeBody(resp *http.Response) {
 		_ = resp.Body.Close()
 	}
 }
+
+func backendMetricPrefix(name string) string {
+	return "backend_" + name + "_"
+}
+
+func boolToInt(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -34,6 +34,8 @@ type DNSdist struct {
 
 	httpClient *http.Client
 	charts     *module.Charts
+
+	backends map[string]bool
 }
 
 func New() *DNSdist {
@@ -48,6 +50,7 @@ func New() *DNSdist {
 				},
 			},
 		},
+		backends: make(map[string]bool),
 	}
 }
 
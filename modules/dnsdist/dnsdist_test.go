@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
@@ -16,12 +17,16 @@ import (
 )
 
 var (
-	v151JSONStat, _ = os.ReadFile("testdata/v1.5.1/jsonstat.json")
+	v151JSONStat, _         = os.ReadFile("testdata/v1.5.1/jsonstat.json")
+	v151Statistics, _       = os.ReadFile("testdata/v1.5.1/statistics.json")
+	v151ServersLocalhost, _ = os.ReadFile("testdata/v1.5.1/servers_localhost.json")
 )
 
 func Test_testDataIsCorrectlyReadAndValid(t *testing.T) {
 	for name, data := range map[string][]byte{
-		"v151JSONStat": v151JSONStat,
+		"v151JSONStat":         v151JSONStat,
+		"v151Statistics":       v151Statistics,
+		"v151ServersLocalhost": v151ServersLocalhost,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -125,46 +130,70 @@ func Test_Check(t *testing.T) {
 }
 
 func Test_Collect(t *testing.T) {
+	wantV151 := map[string]int64{
+		"acl-drops":                        1,
+		"backend_10.0.0.11:53_drops":       0,
+		"backend_10.0.0.11:53_latency":     0,
+		"backend_10.0.0.11:53_outstanding": 0,
+		"backend_10.0.0.11:53_queries":     403,
+		"backend_10.0.0.11:53_responses":   0,
+		"backend_10.0.0.11:53_send_errors": 5,
+		"backend_10.0.0.11:53_state_down":  1,
+		"backend_10.0.0.11:53_state_up":    0,
+		"backend_ns1_drops":                2,
+		"backend_ns1_latency":              12500,
+		"backend_ns1_outstanding":          3,
+		"backend_ns1_queries":              600,
+		"backend_ns1_responses":            598,
+		"backend_ns1_send_errors":          1,
+		"backend_ns1_state_down":           0,
+		"backend_ns1_state_up":             1,
+		"cache-hits":                       1,
+		"cache-misses":                     1,
+		"cpu-sys-msec":                     411,
+		"cpu-user-msec":                    939,
+		"downstream-send-errors":           1,
+		"downstream-timeouts":              1,
+		"dyn-blocked":                      1,
+		"empty-queries":                    1,
+		"latency-avg100":                   14237,
+		"latency-avg1000":                  9728,
+		"latency-avg10000":                 1514,
+		"latency-avg1000000":               15,
+		"latency-slow":                     1,
+		"latency0-1":                       1,
+		"latency1-10":                      3,
+		"latency10-50":                     996,
+		"latency100-1000":                  4,
+		"latency50-100":                    1,
+		"no-policy":                        1,
+		"noncompliant-queries":             1,
+		"noncompliant-responses":           1,
+		"queries":                          1003,
+		"rdqueries":                        1003,
+		"real-memory-usage":                202125312,
+		"responses":                        1003,
+		"rule-drop":                        1,
+		"rule-nxdomain":                    1,
+		"rule-refused":                     1,
+		"rule-servfail":                    1,
+		"rule-truncated":                   0,
+		"self-answered":                    1,
+		"servfail-responses":               1,
+		"trunc-failures":                   1,
+	}
+
 	tests := map[string]struct {
 		prepare       func() (dist *DNSdist, cleanup func())
 		wantCollected map[string]int64
 	}{
 		"success on valid response v1.5.1": {
-			prepare: preparePowerDNSdistV151,
-			wantCollected: map[string]int64{
-				"acl-drops":              1,
-				"cache-hits":             1,
-				"cache-misses":           1,
-				"cpu-sys-msec":           411,
-				"cpu-user-msec":          939,
-				"downstream-send-errors": 1,
-				"downstream-timeouts":    1,
-				"dyn-blocked":            1,
-				"empty-queries":          1,
-				"latency-avg100":         14237,
-				"latency-avg1000":        9728,
-				"latency-avg10000":       1514,
-				"latency-avg1000000":     15,
-				"latency-slow":           1,
-				"latency0-1":             1,
-				"latency1-10":            3,
-				"latency10-50":           996,
-				"latency100-1000":        4,
-				"latency50-100":          1,
-				"no-policy":              1,
-				"noncompliant-queries":   1,
-				"noncompliant-responses": 1,
-				"queries":                1003,
-				"rdqueries":              1003,
-				"real-memory-usage":      202125312,
-				"responses":              1003,
-				"rule-drop":              1,
-				"rule-nxdomain":          1,
-				"rule-refused":           1,
-				"self-answered":          1,
-				"servfail-responses":     1,
-				"trunc-failures":         1,
-			},
+			prepare:       preparePowerDNSdistV151,
+			wantCollected: wantV151,
+		},
+		"success on valid response v1.5.1 (REST API statistics)": {
+			prepare:       preparePowerDNSdistV151APIOnly,
+			wantCollected: wantV151,
 		},
 		"fails on 404 response": {
 			prepare: preparePowerDNSdist404,
@@ -193,6 +222,64 @@ func Test_Collect(t *testing.T) {
 	}
 }
 
+func Test_Collect_BackendServersChanges(t *testing.T) {
+	servers := v151ServersLocalhost
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.String() {
+			case "/jsonstat?command=stats":
+				_, _ = w.Write(v151JSONStat)
+			case "/api/v1/servers/localhost":
+				_, _ = w.Write(servers)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer srv.Close()
+
+	dist := New()
+	dist.URL = srv.URL
+	require.True(t, dist.Init())
+
+	require.NotNil(t, dist.Collect())
+	assert.True(t, dist.Charts().Has("backend_server_ns1_queries"))
+	assert.True(t, dist.Charts().Has("backend_server_10_0_0_11_53_queries"))
+
+	servers = []byte(`{"servers":[{"name":"ns1","address":"10.0.0.10:53","state":"up"}]}`)
+	require.NotNil(t, dist.Collect())
+
+	for _, chart := range *dist.Charts() {
+		if strings.HasPrefix(chart.ID, "backend_server_10_0_0_11_53_") {
+			assert.Truef(t, chart.Obsolete, "chart '%s' is not obsolete", chart.ID)
+		}
+		if strings.HasPrefix(chart.ID, "backend_server_ns1_") {
+			assert.Falsef(t, chart.Obsolete, "chart '%s' is obsolete", chart.ID)
+		}
+	}
+}
+
+func Test_Collect_BackendServersFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.String() {
+			case "/jsonstat?command=stats":
+				_, _ = w.Write(v151JSONStat)
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+	defer srv.Close()
+
+	dist := New()
+	dist.URL = srv.URL
+	require.True(t, dist.Init())
+
+	// the statistics are collected even if the backend servers endpoint fails
+	mx := dist.Collect()
+	require.NotNil(t, mx)
+	assert.Equal(t, int64(1003), mx["queries"])
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, dist *DNSdist, collected map[string]int64) {
 	for _, chart := range *dist.Charts() {
 		if chart.Obsolete {
@@ -217,6 +304,24 @@ func preparePowerDNSdistV151() (*DNSdist, func()) {
 	return ns, srv.Close
 }
 
+func preparePowerDNSdistV151APIOnly() (*DNSdist, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.String() {
+			case "/api/v1/servers/localhost/statistics":
+				_, _ = w.Write(v151Statistics)
+			case "/api/v1/servers/localhost":
+				_, _ = w.Write(v151ServersLocalhost)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	ns := New()
+	ns.URL = srv.URL
+
+	return ns, srv.Close
+}
+
 func preparePowerDNSdist404() (*DNSdist, func()) {
 	srv := httptest.NewServer(http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
@@ -252,6 +357,8 @@ func preparePowerDNSDistEndpoint() *httptest.Server {
 			switch r.URL.String() {
 			case "/jsonstat?command=stats":
 				_, _ = w.Write(v151JSONStat)
+			case "/api/v1/servers/localhost":
+				_, _ = w.Write(v151ServersLocalhost)
 			default:
 				w.WriteHeader(http.StatusNotFound)
 			}
@@ -35,7 +35,33 @@ type statisticMetrics struct {
 	RuleDrop              float64 `stm:"rule-drop" json:"rule-drop"`
 	RuleNxDomain          float64 `stm:"rule-nxdomain" json:"rule-nxdomain"`
 	RuleRefused           float64 `stm:"rule-refused" json:"rule-refused"`
+	RuleServFail          float64 `stm:"rule-servfail" json:"rule-servfail"`
+	RuleTruncated         float64 `stm:"rule-truncated" json:"rule-truncated"`
 	SelfAnswered          float64 `stm:"self-answered" json:"self-answered"`
 	ServFailResponses     float64 `stm:"servfail-responses" json:"servfail-responses"`
 	TruncFailures         float64 `stm:"trunc-failures" json:"trunc-failures"`
 }
+
+// https://dnsdist.org/guides/webserver.html#get--api-v1-servers-localhost-statistics
+type statisticItem struct {
+	Name  string  `json:"name"`
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+}
+
+// https://dnsdist.org/guides/webserver.html#get--api-v1-servers-localhost
+type serverInfo struct {
+	Servers []backendServer `json:"servers"`
+}
+
+type backendServer struct {
+	Name        string  `json:"name"`
+	Address     string  `json:"address"`
+	State       string  `json:"state"`
+	Queries     int64   `json:"queries"`
+	Responses   int64   `json:"responses"`
+	Drops       int64   `json:"drops"`
+	SendErrors  int64   `json:"sendErrors"`
+	Outstanding int64   `json:"outstanding"`
+	Latency     float64 `json:"latency"` // milliseconds
+}
@@ -2,7 +2,14 @@
 
 package dnsmasq
 
-import "github.com/netdata/go.d.plugin/agent/module"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
+
+const precision = 1000
 
 var cacheCharts = module.Charts{
 	{
@@ -27,6 +34,16 @@ var cacheCharts = module.Charts{
 			{ID: "misses", Algo: module.Incremental},
 		},
 	},
+	{
+		ID:    "cache_hit_ratio",
+		Title: "Cache hit ratio",
+		Units: "percentage",
+		Fam:   "cache",
+		Ctx:   "dnsmasq.cache_hit_ratio",
+		Dims: module.Dims{
+			{ID: "cache_hit_ratio", Name: "hit_ratio", Div: precision},
+		},
+	},
 	{
 		ID:    "cache_operations",
 		Title: "Cache operations",
@@ -49,3 +66,61 @@ var cacheCharts = module.Charts{
 		},
 	},
 }
+
+var authQueriesChart = module.Chart{
+	ID:    "auth_queries",
+	Title: "Queries for the authoritative zones",
+	Units: "queries/s",
+	Fam:   "auth",
+	Ctx:   "dnsmasq.auth_queries",
+	Dims: module.Dims{
+		{ID: "auth", Name: "queries", Algo: module.Incremental},
+	},
+}
+
+var serverQueriesChartTmpl = module.Chart{
+	ID:    "server_%s_queries",
+	Title: "Queries forwarded to the upstream server",
+	Units: "queries/s",
+	Fam:   "servers",
+	Ctx:   "dnsmasq.server_queries",
+	Dims: module.Dims{
+		{ID: "server_%s_queries", Name: "success", Algo: module.Incremental},
+		{ID: "server_%s_failed_queries", Name: "failed", Algo: module.Incremental},
+	},
+}
+
+func (d *Dnsmasq) addAuthCharts() {
+	if err := d.charts.Add(authQueriesChart.Copy()); err != nil {
+		d.Warning(err)
+	}
+}
+
+func (d *Dnsmasq) addServerCharts(server string) {
+	chart := serverQueriesChartTmpl.Copy()
+
+	chart.ID = fmt.Sprintf(chart.ID, serverChartID(server))
+	chart.Labels = []module.Label{
+		{Key: "server", Value: server},
+	}
+	for _, dim := range chart.Dims {
+		dim.ID = fmt.Sprintf(dim.ID, server)
+	}
+
+	if err := d.charts.Add(chart); err != nil {
+		d.Warning(err)
+	}
+}
+
+func (d *Dnsmasq) removeServerCharts(server string) {
+	id := fmt.Sprintf(serverQueriesChartTmpl.ID, serverChartID(server))
+	if chart := d.charts.Get(id); chart != nil {
+		chart.MarkRemove()
+		chart.MarkNotCreated()
+	}
+}
+
+// serverChartID replaces the dots and the port separator ("10.0.0.1#53") in the server address.
+func serverChartID(server string) string {
+	return strings.NewReplacer(".", "_", ":", "_", "#", "_").Replace(server)
+}
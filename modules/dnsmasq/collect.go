@@ -10,7 +10,14 @@ import (
 	"github.com/miekg/dns"
 )
 
+// maxTXTStringLen is the max length of a TXT record character-string, longer values are split into several strings.
+const maxTXTStringLen = 255
+
 func (d *Dnsmasq) collect() (map[string]int64, error) {
+	if !d.authChecked {
+		d.checkAuthSupport()
+	}
+
 	r, err := d.queryCacheStatistics()
 	if err != nil {
 		return nil, err
@@ -21,6 +28,8 @@ func (d *Dnsmasq) collect() (map[string]int64, error) {
 		return nil, err
 	}
 
+	d.collectCacheHitRatio(ms)
+
 	return ms, nil
 }
 
@@ -46,22 +55,26 @@ func (d *Dnsmasq) collectResponse(ms map[string]int64, resp *dns.Msg) error {
 		auth.bind.			0	CH	TXT	"0"
 		servers.bind.		0	CH	TXT	"10.0.0.1#53 0 0" "1.1.1.1#53 4 3" "1.0.0.1#53 3 0"
 	*/
+	var servers []string
+
 	for _, a := range resp.Answer {
 		txt, ok := a.(*dns.TXT)
 		if !ok {
 			continue
 		}
 
-		idx := strings.IndexByte(txt.Hdr.Name, '.')
+		// the name case can be randomized (DNS 0x20 encoding)
+		qname := strings.ToLower(txt.Hdr.Name)
+		idx := strings.IndexByte(qname, '.')
 		if idx == -1 {
 			continue
 		}
 
-		switch name := txt.Hdr.Name[:idx]; name {
+		switch name := qname[:idx]; name {
 		case "servers":
-			for _, entry := range txt.Txt {
+			for _, entry := range joinSplitTXTStrings(txt.Txt) {
 				parts := strings.Fields(entry)
-				if len(parts) != 3 {
+				if len(parts) < 3 {
 					return fmt.Errorf("parse %s (%s): unexpected format", txt.Hdr.Name, entry)
 				}
 				queries, err := strconv.ParseFloat(parts[1], 64)
@@ -73,45 +86,109 @@ func (d *Dnsmasq) collectResponse(ms map[string]int64, resp *dns.Msg) error {
 					return fmt.Errorf("parse '%s' (%s): %v", txt.Hdr.Name, entry, err)
 				}
 
+				server := parts[0]
+				if _, ok := ms["server_"+server+"_queries"]; !ok {
+					servers = append(servers, server)
+				}
+				// the same server can be listed several times (different source addresses or interfaces)
+				ms["server_"+server+"_queries"] += int64(queries)
+				ms["server_"+server+"_failed_queries"] += int64(failedQueries)
+
 				ms["queries"] += int64(queries)
 				ms["failed_queries"] += int64(failedQueries)
 			}
 		case "cachesize", "insertions", "evictions", "hits", "misses", "auth":
-			if len(txt.Txt) != 1 {
+			if len(txt.Txt) == 0 {
 				return fmt.Errorf("parse '%s' (%v): unexpected format", txt.Hdr.Name, txt.Txt)
 			}
-			v, err := strconv.ParseFloat(txt.Txt[0], 64)
+			s := strings.Join(txt.Txt, "")
+			v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
 			if err != nil {
-				return fmt.Errorf("parse '%s' (%s): %v", txt.Hdr.Name, txt.Txt[0], err)
+				return fmt.Errorf("parse '%s' (%s): %v", txt.Hdr.Name, s, err)
 			}
 
 			ms[name] = int64(v)
 		}
 	}
+
+	d.updateServersCharts(servers)
+
 	return nil
 }
 
-func (d *Dnsmasq) queryCacheStatistics() (*dns.Msg, error) {
-	msg := &dns.Msg{
-		MsgHdr: dns.MsgHdr{
-			Id:               dns.Id(),
-			RecursionDesired: true,
-		},
-		Question: []dns.Question{
-			{Name: "cachesize.bind.", Qtype: dns.TypeTXT, Qclass: dns.ClassCHAOS},
-			{Name: "insertions.bind.", Qtype: dns.TypeTXT, Qclass: dns.ClassCHAOS},
-			{Name: "evictions.bind.", Qtype: dns.TypeTXT, Qclass: dns.ClassCHAOS},
-			{Name: "hits.bind.", Qtype: dns.TypeTXT, Qclass: dns.ClassCHAOS},
-			{Name: "misses.bind.", Qtype: dns.TypeTXT, Qclass: dns.ClassCHAOS},
-			// TODO: collect auth.bind if available
-			// auth.bind query is only supported if dnsmasq has been built
-			// to support running as an authoritative name server. See https://github.com/netdata/netdata/issues/13766
-			//{Name: "auth.bind.", Qtype: dns.TypeTXT, Qclass: dns.ClassCHAOS},
-			{Name: "servers.bind.", Qtype: dns.TypeTXT, Qclass: dns.ClassCHAOS},
-		},
+func (d *Dnsmasq) collectCacheHitRatio(ms map[string]int64) {
+	hits, ok1 := ms["hits"]
+	misses, ok2 := ms["misses"]
+	if !ok1 || !ok2 {
+		return
 	}
 
+	// the ratio of the last interval, the counters are reset on restart
+	if d.hasPrevCacheStats && hits >= d.prevHits && misses >= d.prevMisses {
+		if total := (hits - d.prevHits) + (misses - d.prevMisses); total > 0 {
+			d.cacheHitRatio = (hits - d.prevHits) * 100 * precision / total
+		}
+	}
+	d.hasPrevCacheStats = true
+	d.prevHits, d.prevMisses = hits, misses
+
+	ms["cache_hit_ratio"] = d.cacheHitRatio
+}
+
+func (d *Dnsmasq) updateServersCharts(servers []string) {
+	seen := make(map[string]bool)
+	for _, server := range servers {
+		seen[server] = true
+		if !d.servers[server] {
+			d.servers[server] = true
+			d.addServerCharts(server)
+		}
+	}
+	// the upstream servers list changes on SIGHUP (the resolv file is re-read)
+	for server := range d.servers {
+		if !seen[server] {
+			delete(d.servers, server)
+			d.removeServerCharts(server)
+		}
+	}
+}
+
+// checkAuthSupport checks whether the 'auth.bind' query is supported. It is only supported if dnsmasq has been built
+// to support running as an authoritative name server, otherwise the whole query fails.
+// See https://github.com/netdata/netdata/issues/13766
+func (d *Dnsmasq) checkAuthSupport() {
+	d.authChecked = true
+
+	msg := newChaosTXTQuery("auth.bind.")
 	r, _, err := d.dnsClient.Exchange(msg, d.Address)
+	if err != nil {
+		d.authChecked = false
+		d.Debugf("'auth.bind' query: %v", err)
+		return
+	}
+	if r == nil || r.Rcode != dns.RcodeSuccess || len(r.Answer) == 0 {
+		d.Debug("'auth.bind' query is not supported, authoritative zones queries are not collected")
+		return
+	}
+
+	d.hasAuth = true
+	d.addAuthCharts()
+}
+
+func (d *Dnsmasq) queryCacheStatistics() (*dns.Msg, error) {
+	names := []string{
+		"cachesize.bind.",
+		"insertions.bind.",
+		"evictions.bind.",
+		"hits.bind.",
+		"misses.bind.",
+		"servers.bind.",
+	}
+	if d.hasAuth {
+		names = append(names, "auth.bind.")
+	}
+
+	r, _, err := d.dnsClient.Exchange(newChaosTXTQuery(names...), d.Address)
 	if err != nil {
 		return nil, err
 	}
@@ -124,3 +201,34 @@ func (d *Dnsmasq) queryCacheStatistics() (*dns.Msg, error) {
 	}
 	return r, nil
 }
+
+func newChaosTXTQuery(names ...string) *dns.Msg {
+	msg := &dns.Msg{
+		MsgHdr: dns.MsgHdr{
+			Id:               dns.Id(),
+			RecursionDesired: true,
+		},
+	}
+	for _, name := range names {
+		msg.Question = append(msg.Question, dns.Question{Name: name, Qtype: dns.TypeTXT, Qclass: dns.ClassCHAOS})
+	}
+	return msg
+}
+
+// joinSplitTXTStrings joins the values split at 255 bytes into several character-strings.
+func joinSplitTXTStrings(txt []string) []string {
+	var res []string
+	var sb strings.Builder
+	for _, s := range txt {
+		sb.WriteString(s)
+		if len(s) == maxTXTStringLen {
+			continue
+		}
+		res = append(res, sb.String())
+		sb.Reset()
+	}
+	if sb.Len() > 0 {
+		res = append(res, sb.String())
+	}
+	return res
+}
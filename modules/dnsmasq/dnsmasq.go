@@ -36,6 +36,7 @@ func New() *Dnsmasq {
 				Timeout: timeout,
 			}
 		},
+		servers: make(map[string]bool),
 	}
 }
 
@@ -54,6 +55,15 @@ type (
 		dnsClient    dnsClient
 
 		charts *module.Charts
+
+		servers     map[string]bool
+		authChecked bool
+		hasAuth     bool
+
+		hasPrevCacheStats bool
+		prevHits          int64
+		prevMisses        int64
+		cacheHitRatio     int64
 	}
 
 	dnsClient interface {
@@ -5,6 +5,8 @@ package dnsmasq
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -112,14 +114,40 @@ func TestDnsmasq_Collect(t *testing.T) {
 		"success on valid response": {
 			prepare: prepareOKDnsmasq,
 			wantCollected: map[string]int64{
-				//"auth":           5,
-				"cachesize":      999,
-				"evictions":      5,
-				"failed_queries": 9,
-				"hits":           100,
-				"insertions":     10,
-				"misses":         50,
-				"queries":        17,
+				"auth":                              5,
+				"cache_hit_ratio":                   0,
+				"cachesize":                         999,
+				"evictions":                         5,
+				"failed_queries":                    9,
+				"hits":                              100,
+				"insertions":                        10,
+				"misses":                            50,
+				"queries":                           17,
+				"server_1.0.0.1#53_failed_queries":  1,
+				"server_1.0.0.1#53_queries":         3,
+				"server_1.1.1.1#53_failed_queries":  3,
+				"server_1.1.1.1#53_queries":         4,
+				"server_10.0.0.1#53_failed_queries": 5,
+				"server_10.0.0.1#53_queries":        10,
+			},
+		},
+		"success on valid response (auth is not supported)": {
+			prepare: prepareAuthNotSupportedDnsmasq,
+			wantCollected: map[string]int64{
+				"cache_hit_ratio":                   0,
+				"cachesize":                         999,
+				"evictions":                         5,
+				"failed_queries":                    9,
+				"hits":                              100,
+				"insertions":                        10,
+				"misses":                            50,
+				"queries":                           17,
+				"server_1.0.0.1#53_failed_queries":  1,
+				"server_1.0.0.1#53_queries":         3,
+				"server_1.1.1.1#53_failed_queries":  3,
+				"server_1.1.1.1#53_queries":         4,
+				"server_10.0.0.1#53_failed_queries": 5,
+				"server_10.0.0.1#53_queries":        10,
 			},
 		},
 		"fails on error on cache stats query": {
@@ -145,6 +173,68 @@ func TestDnsmasq_Collect(t *testing.T) {
 	}
 }
 
+func TestDnsmasq_Collect_CacheHitRatio(t *testing.T) {
+	dnsmasq := prepareOKDnsmasq()
+	require.True(t, dnsmasq.Init())
+	mock := &mockDNSClient{}
+	dnsmasq.dnsClient = mock
+
+	_ = dnsmasq.Collect()
+	mock.hits, mock.misses = 130, 60
+	collected := dnsmasq.Collect()
+
+	assert.Equal(t, int64(75000), collected["cache_hit_ratio"])
+
+	// no lookups in the interval, the last ratio is reported
+	collected = dnsmasq.Collect()
+
+	assert.Equal(t, int64(75000), collected["cache_hit_ratio"])
+}
+
+func TestDnsmasq_Collect_ServersChanged(t *testing.T) {
+	dnsmasq := prepareOKDnsmasq()
+	require.True(t, dnsmasq.Init())
+	mock := &mockDNSClient{}
+	dnsmasq.dnsClient = mock
+
+	_ = dnsmasq.Collect()
+	require.NotNil(t, dnsmasq.Charts().Get("server_1_1_1_1_53_queries"))
+
+	// SIGHUP, the resolv file is re-read
+	mock.servers = []string{"10.0.0.1#53 10 5", "8.8.8.8#53 1 0"}
+	collected := dnsmasq.Collect()
+
+	assert.Equal(t, int64(1), collected["server_8.8.8.8#53_queries"])
+	assert.NotContains(t, collected, "server_1.1.1.1#53_queries")
+	for id, wantRemoved := range map[string]bool{
+		"server_10_0_0_1_53_queries": false,
+		"server_8_8_8_8_53_queries":  false,
+		"server_1_1_1_1_53_queries":  true,
+		"server_1_0_0_1_53_queries":  true,
+	} {
+		chart := dnsmasq.Charts().Get(id)
+		require.NotNilf(t, chart, "chart '%s'", id)
+		assert.Equalf(t, wantRemoved, chart.Obsolete, "chart '%s'", id)
+	}
+	assert.Equal(t, "8.8.8.8#53", dnsmasq.Charts().Get("server_8_8_8_8_53_queries").Labels[0].Value)
+}
+
+func TestDnsmasq_Collect_SplitTXTStrings(t *testing.T) {
+	long := strings.Repeat("a", 300) + "#53 7 2"
+	dnsmasq := prepareOKDnsmasq()
+	require.True(t, dnsmasq.Init())
+	dnsmasq.dnsClient = &mockDNSClient{
+		servers: []string{long[:maxTXTStringLen], long[maxTXTStringLen:], "1.1.1.1#53 4 3"},
+	}
+
+	collected := dnsmasq.Collect()
+
+	require.NotNil(t, collected)
+	assert.Equal(t, int64(7), collected["server_"+strings.Repeat("a", 300)+"#53_queries"])
+	assert.Equal(t, int64(2), collected["server_"+strings.Repeat("a", 300)+"#53_failed_queries"])
+	assert.Equal(t, int64(11), collected["queries"])
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, dnsmasq *Dnsmasq, collected map[string]int64) {
 	for _, chart := range *dnsmasq.Charts() {
 		if chart.Obsolete {
@@ -169,6 +259,16 @@ func prepareOKDnsmasq() *Dnsmasq {
 	return dnsmasq
 }
 
+func prepareAuthNotSupportedDnsmasq() *Dnsmasq {
+	dnsmasq := New()
+	dnsmasq.newDNSClient = func(network string, timeout time.Duration) dnsClient {
+		return &mockDNSClient{
+			authNotSupported: true,
+		}
+	}
+	return dnsmasq
+}
+
 func prepareErrorOnExchangeDnsmasq() *Dnsmasq {
 	dnsmasq := New()
 	dnsmasq.newDNSClient = func(network string, timeout time.Duration) dnsClient {
@@ -192,6 +292,10 @@ func prepareRcodeServerFailureOnExchangeDnsmasq() *Dnsmasq {
 type mockDNSClient struct {
 	errOnExchange                bool
 	rcodeServerFailureOnExchange bool
+	authNotSupported             bool
+	hits                         int
+	misses                       int
+	servers                      []string
 }
 
 func (m mockDNSClient) Exchange(msg *dns.Msg, _ string) (*dns.Msg, time.Duration, error) {
@@ -205,7 +309,11 @@ func (m mockDNSClient) Exchange(msg *dns.Msg, _ string) (*dns.Msg, time.Duration
 
 	var answers []dns.RR
 	for _, q := range msg.Question {
-		a, err := prepareDNSAnswer(q)
+		if m.authNotSupported && q.Name == "auth.bind." {
+			resp := &dns.Msg{MsgHdr: dns.MsgHdr{Rcode: dns.RcodeRefused}}
+			return resp, 0, nil
+		}
+		a, err := m.prepareDNSAnswer(q)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -221,7 +329,7 @@ func (m mockDNSClient) Exchange(msg *dns.Msg, _ string) (*dns.Msg, time.Duration
 	return resp, 0, nil
 }
 
-func prepareDNSAnswer(q dns.Question) (dns.RR, error) {
+func (m mockDNSClient) prepareDNSAnswer(q dns.Question) (dns.RR, error) {
 	if want, got := dns.TypeToString[dns.TypeTXT], dns.TypeToString[q.Qtype]; want != got {
 		return nil, fmt.Errorf("unexpected Qtype, want=%s, got=%s", want, got)
 	}
@@ -239,12 +347,21 @@ func prepareDNSAnswer(q dns.Question) (dns.RR, error) {
 		txt = []string{"5"}
 	case "hits.bind.":
 		txt = []string{"100"}
+		if m.hits > 0 {
+			txt = []string{strconv.Itoa(m.hits)}
+		}
 	case "misses.bind.":
 		txt = []string{"50"}
+		if m.misses > 0 {
+			txt = []string{strconv.Itoa(m.misses)}
+		}
 	case "auth.bind.":
 		txt = []string{"5"}
 	case "servers.bind.":
 		txt = []string{"10.0.0.1#53 10 5", "1.1.1.1#53 4 3", "1.0.0.1#53 3 1"}
+		if m.servers != nil {
+			txt = m.servers
+		}
 	default:
 		return nil, fmt.Errorf("unexpected question Name: %s", q.Name)
 	}
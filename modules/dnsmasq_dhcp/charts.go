@@ -12,6 +12,8 @@ import (
 const (
 	prioDHCPRangeUtilization = module.Priority + iota
 	prioDHCPRangeAllocatesLeases
+	prioDHCPRangeLeaseEvents
+	prioDHCPRangeLeaseTimeRemaining
 	prioDHCPRanges
 	prioDHCPHosts
 )
@@ -49,6 +51,8 @@ var (
 	chartsTmpl = module.Charts{
 		chartTmplDHCPRangeUtilization.Copy(),
 		chartTmplDHCPRangeAllocatedLeases.Copy(),
+		chartTmplDHCPRangeLeaseEvents.Copy(),
+		chartTmplDHCPRangeLeaseTimeRemaining.Copy(),
 	}
 )
 
@@ -76,6 +80,30 @@ var (
 			{ID: "dhcp_range_%s_allocated_leases", Name: "leases"},
 		},
 	}
+	chartTmplDHCPRangeLeaseEvents = module.Chart{
+		ID:       "dhcp_range_%s_lease_events",
+		Title:    "DHCP Range Lease Events",
+		Units:    "leases/s",
+		Fam:      "dhcp range leases",
+		Ctx:      "dnsmasq_dhcp.dhcp_range_lease_events",
+		Priority: prioDHCPRangeLeaseEvents,
+		Dims: module.Dims{
+			{ID: "dhcp_range_%s_new_leases", Name: "new", Algo: module.Incremental},
+			{ID: "dhcp_range_%s_released_leases", Name: "released", Algo: module.Incremental},
+			{ID: "dhcp_range_%s_expired_leases", Name: "expired", Algo: module.Incremental},
+		},
+	}
+	chartTmplDHCPRangeLeaseTimeRemaining = module.Chart{
+		ID:       "dhcp_range_%s_avg_lease_time_remaining",
+		Title:    "DHCP Range Average Lease Time Remaining",
+		Units:    "seconds",
+		Fam:      "dhcp range leases",
+		Ctx:      "dnsmasq_dhcp.dhcp_range_avg_lease_time_remaining",
+		Priority: prioDHCPRangeLeaseTimeRemaining,
+		Dims: module.Dims{
+			{ID: "dhcp_range_%s_avg_lease_time_remaining", Name: "avg"},
+		},
+	}
 )
 
 func newDHCPRangeCharts(dhcpRange string) *module.Charts {
@@ -101,9 +129,9 @@ func (d *DnsmasqDHCP) addDHCPRangeCharts(dhcpRange string) {
 }
 
 func (d *DnsmasqDHCP) removeDHCPRangeCharts(dhcpRange string) {
-	p := "dhcp_range_" + dhcpRange
+	p := "dhcp_range_" + dhcpRange + "_"
 	for _, c := range *d.Charts() {
-		if strings.HasSuffix(c.ID, p) {
+		if strings.HasPrefix(c.ID, p) {
 			c.MarkRemove()
 			c.MarkNotCreated()
 		}
@@ -4,19 +4,30 @@ package dnsmasq_dhcp
 
 import (
 	"bufio"
+	"errors"
 	"io"
 	"math"
 	"math/big"
 	"net"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/iprange"
 )
 
+type lease struct {
+	ip      net.IP
+	expires int64 // unix time, 0 means infinite
+	// the client identity: the MAC address (IPv4) or the DUID and the IAID (IPv6)
+	client string
+}
+
+func (l lease) key() string { return l.ip.String() + " " + l.client }
+
 func (d *DnsmasqDHCP) collect() (map[string]int64, error) {
-	now := time.Now()
+	now := d.now()
 	var updated bool
 
 	if now.Sub(d.parseConfigTime) > d.parseConfigEvery {
@@ -29,29 +40,26 @@ func (d *DnsmasqDHCP) collect() (map[string]int64, error) {
 		d.collectV4V6Stats()
 	}
 
-	f, err := os.Open(d.LeasesPath)
+	changed, err := d.leasesChanged()
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = f.Close() }()
 
-	if !updated {
-		fi, err := f.Stat()
+	if updated || changed {
+		d.Debug("leases db files modification time has changed, reading them")
+		leases, err := d.readLeases()
 		if err != nil {
 			return nil, err
 		}
-
-		if d.leasesModTime.Equal(fi.ModTime()) {
-			d.Debug("lease database file modification time has not changed, old data is returned")
-			return d.mx, nil
-		}
-
-		d.Debug("leases db file modification time has changed, reading it")
-		d.leasesModTime = fi.ModTime()
+		d.collectRangesStats(leases)
+		d.collectLeasesChurn(leases, now)
+		d.leases = leases
+	} else {
+		d.Debug("lease database files modification time has not changed, old data is returned")
 	}
 
-	leases := findLeases(f)
-	d.collectRangesStats(leases)
+	// the remaining time changes even if the leases don't
+	d.collectLeaseTimeRemaining(now)
 
 	return d.mx, nil
 }
@@ -76,27 +84,21 @@ func (d *DnsmasqDHCP) collectV4V6Stats() {
 	}
 }
 
-func (d *DnsmasqDHCP) collectRangesStats(leases []net.IP) {
+func (d *DnsmasqDHCP) collectRangesStats(leases []lease) {
 	for _, r := range d.dhcpRanges {
 		d.mx["dhcp_range_"+r.String()+"_allocated_leases"] = 0
 		d.mx["dhcp_range_"+r.String()+"_utilization"] = 0
 	}
 
-	for _, ip := range leases {
-		for _, r := range d.dhcpRanges {
-			if r.Contains(ip) {
-				d.mx["dhcp_range_"+r.String()+"_allocated_leases"]++
-				break
-			}
+	for _, l := range leases {
+		if r := d.findDHCPRange(l.ip); r != nil {
+			d.mx["dhcp_range_"+r.String()+"_allocated_leases"]++
 		}
 	}
 
 	for _, ip := range d.dhcpHosts {
-		for _, r := range d.dhcpRanges {
-			if r.Contains(ip) {
-				d.mx["dhcp_range_"+r.String()+"_allocated_leases"]++
-				break
-			}
+		if r := d.findDHCPRange(ip); r != nil {
+			d.mx["dhcp_range_"+r.String()+"_allocated_leases"]++
 		}
 	}
 
@@ -110,6 +112,89 @@ func (d *DnsmasqDHCP) collectRangesStats(leases []net.IP) {
 	}
 }
 
+// collectLeasesChurn compares the leases with the previous read. The lease that is gone before the expiration time
+// is released, after - expired.
+func (d *DnsmasqDHCP) collectLeasesChurn(leases []lease, now time.Time) {
+	for _, r := range d.dhcpRanges {
+		for _, v := range []string{"new", "released", "expired"} {
+			if _, ok := d.mx["dhcp_range_"+r.String()+"_"+v+"_leases"]; !ok {
+				d.mx["dhcp_range_"+r.String()+"_"+v+"_leases"] = 0
+			}
+		}
+	}
+
+	// the first read, all the leases are not new
+	if d.leases == nil {
+		return
+	}
+
+	prev := make(map[string]lease, len(d.leases))
+	for _, l := range d.leases {
+		prev[l.key()] = l
+	}
+	curr := make(map[string]bool, len(leases))
+	for _, l := range leases {
+		curr[l.key()] = true
+	}
+
+	for _, l := range leases {
+		if _, ok := prev[l.key()]; ok {
+			continue
+		}
+		if r := d.findDHCPRange(l.ip); r != nil {
+			d.mx["dhcp_range_"+r.String()+"_new_leases"]++
+		}
+	}
+
+	for key, l := range prev {
+		if curr[key] {
+			continue
+		}
+		r := d.findDHCPRange(l.ip)
+		if r == nil {
+			continue
+		}
+		if l.expires != 0 && l.expires <= now.Unix() {
+			d.mx["dhcp_range_"+r.String()+"_expired_leases"]++
+		} else {
+			d.mx["dhcp_range_"+r.String()+"_released_leases"]++
+		}
+	}
+}
+
+func (d *DnsmasqDHCP) collectLeaseTimeRemaining(now time.Time) {
+	sum := make(map[string]int64)
+	num := make(map[string]int64)
+
+	for _, l := range d.leases {
+		// the infinite and the expired (not yet removed) leases
+		if l.expires == 0 || l.expires <= now.Unix() {
+			continue
+		}
+		if r := d.findDHCPRange(l.ip); r != nil {
+			sum[r.String()] += l.expires - now.Unix()
+			num[r.String()]++
+		}
+	}
+
+	for _, r := range d.dhcpRanges {
+		var v int64
+		if n := num[r.String()]; n > 0 {
+			v = sum[r.String()] / n
+		}
+		d.mx["dhcp_range_"+r.String()+"_avg_lease_time_remaining"] = v
+	}
+}
+
+func (d *DnsmasqDHCP) findDHCPRange(ip net.IP) iprange.Range {
+	for _, r := range d.dhcpRanges {
+		if r.Contains(ip) {
+			return r
+		}
+	}
+	return nil
+}
+
 func (d *DnsmasqDHCP) updateCharts() bool {
 	var updated bool
 	seen := make(map[string]bool)
@@ -126,19 +211,89 @@ func (d *DnsmasqDHCP) updateCharts() bool {
 		if !seen[v] {
 			delete(d.cacheDHCPRanges, v)
 			d.removeDHCPRangeCharts(v)
+			for k := range d.mx {
+				if strings.HasPrefix(k, "dhcp_range_"+v+"_") {
+					delete(d.mx, k)
+				}
+			}
 			updated = true
 		}
 	}
 	return updated
 }
 
-func findLeases(r io.Reader) []net.IP {
+// leasesChanged reports whether the modification time of any leases file has changed. It fails only if none
+// of the files can be checked (e.g. the IPv6 leases file may not exist yet).
+func (d *DnsmasqDHCP) leasesChanged() (bool, error) {
+	var changed bool
+	var errs []error
+
+	paths := d.leasesFiles()
+	if len(paths) == 0 {
+		return false, errors.New("no leases files")
+	}
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, err)
+			if _, ok := d.leasesModTime[path]; ok {
+				delete(d.leasesModTime, path)
+				changed = true
+			}
+			continue
+		}
+		if !d.leasesModTime[path].Equal(fi.ModTime()) {
+			d.leasesModTime[path] = fi.ModTime()
+			changed = true
+		}
+	}
+
+	if len(errs) == len(paths) {
+		return false, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		d.Debug(err)
+	}
+	return changed, nil
+}
+
+// readLeases reads all the leases files, the leases are merged.
+func (d *DnsmasqDHCP) readLeases() ([]lease, error) {
+	var leases []lease
+	var errs []error
+
+	paths := d.leasesFiles()
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		leases = append(leases, parseLeases(f)...)
+		_ = f.Close()
+	}
+
+	if len(errs) == len(paths) {
+		return nil, errors.Join(errs...)
+	}
+	if leases == nil {
+		leases = []lease{}
+	}
+	return leases, nil
+}
+
+func parseLeases(r io.Reader) []lease {
 	/*
 		1560300536 08:00:27:61:3c:ee 2.2.2.3 debian8 *
 		duid 00:01:00:01:24:90:cf:5b:08:00:27:61:2e:2c
 		1560300414 660684014 1234::20b * 00:01:00:01:24:90:cf:a3:08:00:27:61:3c:ee
+		1560300414 T660684015 1234::20c * 00:01:00:01:24:90:cf:a3:08:00:27:61:3c:ee
+
+		IPv4: <expires> <MAC> <IP> <hostname> <client ID>
+		IPv6: <expires> <IAID ('T' prefix for a temporary address)> <IP> <hostname> <client DUID>
+		The server DUID line precedes the IPv6 leases.
 	*/
-	var ips []net.IP
+	var leases []lease
 	s := bufio.NewScanner(r)
 
 	for s.Scan() {
@@ -151,10 +306,19 @@ func findLeases(r io.Reader) []net.IP {
 		if ip == nil {
 			continue
 		}
-		ips = append(ips, ip)
+		expires, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		l := lease{ip: ip, expires: expires, client: parts[1]}
+		if ip.To4() == nil {
+			l.client = parts[4] + "/" + parts[1]
+		}
+		leases = append(leases, l)
 	}
 
-	return ips
+	return leases
 }
 
 func calcPercent(ips int64, hosts *big.Int) float64 {
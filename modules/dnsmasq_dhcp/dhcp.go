@@ -34,14 +34,18 @@ func New() *DnsmasqDHCP {
 		charts:           charts.Copy(),
 		parseConfigEvery: time.Minute,
 		cacheDHCPRanges:  make(map[string]bool),
+		leasesModTime:    make(map[string]time.Time),
 		mx:               make(map[string]int64),
+		now:              time.Now,
+		interfaceAddrs:   interfaceAddrs,
 	}
 }
 
 type Config struct {
-	LeasesPath string `yaml:"leases_path"`
-	ConfPath   string `yaml:"conf_path"`
-	ConfDir    string `yaml:"conf_dir"`
+	LeasesPath  string   `yaml:"leases_path"`
+	LeasesPaths []string `yaml:"leases_paths"`
+	ConfPath    string   `yaml:"conf_path"`
+	ConfDir     string   `yaml:"conf_dir"`
 }
 
 type DnsmasqDHCP struct {
@@ -50,7 +54,8 @@ type DnsmasqDHCP struct {
 
 	charts *module.Charts
 
-	leasesModTime time.Time
+	leasesModTime map[string]time.Time
+	leases        []lease
 
 	parseConfigTime  time.Time
 	parseConfigEvery time.Duration
@@ -61,6 +66,9 @@ type DnsmasqDHCP struct {
 	cacheDHCPRanges map[string]bool
 
 	mx map[string]int64
+
+	now            func() time.Time
+	interfaceAddrs func(pattern string) ([]*net.IPNet, error)
 }
 
 func (d *DnsmasqDHCP) Init() bool {
@@ -3,7 +3,11 @@
 package dnsmasq_dhcp
 
 import (
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -89,28 +93,72 @@ func TestDnsmasqDHCP_Collect(t *testing.T) {
 	require.True(t, job.Check())
 
 	expected := map[string]int64{
-		"dhcp_range_1230::1-1230::64_allocated_leases":              7,
-		"dhcp_range_1230::1-1230::64_utilization":                   7,
-		"dhcp_range_1231::1-1231::64_allocated_leases":              1,
-		"dhcp_range_1231::1-1231::64_utilization":                   1,
-		"dhcp_range_1232::1-1232::64_allocated_leases":              1,
-		"dhcp_range_1232::1-1232::64_utilization":                   1,
-		"dhcp_range_1233::1-1233::64_allocated_leases":              1,
-		"dhcp_range_1233::1-1233::64_utilization":                   1,
-		"dhcp_range_1234::1-1234::64_allocated_leases":              1,
-		"dhcp_range_1234::1-1234::64_utilization":                   1,
-		"dhcp_range_192.168.0.1-192.168.0.100_allocated_leases":     6,
-		"dhcp_range_192.168.0.1-192.168.0.100_utilization":          6,
-		"dhcp_range_192.168.1.1-192.168.1.100_allocated_leases":     5,
-		"dhcp_range_192.168.1.1-192.168.1.100_utilization":          5,
-		"dhcp_range_192.168.2.1-192.168.2.100_allocated_leases":     4,
-		"dhcp_range_192.168.2.1-192.168.2.100_utilization":          4,
-		"dhcp_range_192.168.200.1-192.168.200.100_allocated_leases": 1,
-		"dhcp_range_192.168.200.1-192.168.200.100_utilization":      1,
-		"dhcp_range_192.168.3.1-192.168.3.100_allocated_leases":     1,
-		"dhcp_range_192.168.3.1-192.168.3.100_utilization":          1,
-		"dhcp_range_192.168.4.1-192.168.4.100_allocated_leases":     1,
-		"dhcp_range_192.168.4.1-192.168.4.100_utilization":          1,
+		"dhcp_range_1230::1-1230::64_allocated_leases":                      7,
+		"dhcp_range_1230::1-1230::64_utilization":                           7,
+		"dhcp_range_1230::1-1230::64_avg_lease_time_remaining":              0,
+		"dhcp_range_1230::1-1230::64_expired_leases":                        0,
+		"dhcp_range_1230::1-1230::64_new_leases":                            0,
+		"dhcp_range_1230::1-1230::64_released_leases":                       0,
+		"dhcp_range_1231::1-1231::64_allocated_leases":                      1,
+		"dhcp_range_1231::1-1231::64_utilization":                           1,
+		"dhcp_range_1231::1-1231::64_avg_lease_time_remaining":              0,
+		"dhcp_range_1231::1-1231::64_expired_leases":                        0,
+		"dhcp_range_1231::1-1231::64_new_leases":                            0,
+		"dhcp_range_1231::1-1231::64_released_leases":                       0,
+		"dhcp_range_1232::1-1232::64_allocated_leases":                      1,
+		"dhcp_range_1232::1-1232::64_utilization":                           1,
+		"dhcp_range_1232::1-1232::64_avg_lease_time_remaining":              0,
+		"dhcp_range_1232::1-1232::64_expired_leases":                        0,
+		"dhcp_range_1232::1-1232::64_new_leases":                            0,
+		"dhcp_range_1232::1-1232::64_released_leases":                       0,
+		"dhcp_range_1233::1-1233::64_allocated_leases":                      1,
+		"dhcp_range_1233::1-1233::64_utilization":                           1,
+		"dhcp_range_1233::1-1233::64_avg_lease_time_remaining":              0,
+		"dhcp_range_1233::1-1233::64_expired_leases":                        0,
+		"dhcp_range_1233::1-1233::64_new_leases":                            0,
+		"dhcp_range_1233::1-1233::64_released_leases":                       0,
+		"dhcp_range_1234::1-1234::64_allocated_leases":                      1,
+		"dhcp_range_1234::1-1234::64_utilization":                           1,
+		"dhcp_range_1234::1-1234::64_avg_lease_time_remaining":              0,
+		"dhcp_range_1234::1-1234::64_expired_leases":                        0,
+		"dhcp_range_1234::1-1234::64_new_leases":                            0,
+		"dhcp_range_1234::1-1234::64_released_leases":                       0,
+		"dhcp_range_192.168.0.1-192.168.0.100_allocated_leases":             6,
+		"dhcp_range_192.168.0.1-192.168.0.100_utilization":                  6,
+		"dhcp_range_192.168.0.1-192.168.0.100_avg_lease_time_remaining":     0,
+		"dhcp_range_192.168.0.1-192.168.0.100_expired_leases":               0,
+		"dhcp_range_192.168.0.1-192.168.0.100_new_leases":                   0,
+		"dhcp_range_192.168.0.1-192.168.0.100_released_leases":              0,
+		"dhcp_range_192.168.1.1-192.168.1.100_allocated_leases":             5,
+		"dhcp_range_192.168.1.1-192.168.1.100_utilization":                  5,
+		"dhcp_range_192.168.1.1-192.168.1.100_avg_lease_time_remaining":     0,
+		"dhcp_range_192.168.1.1-192.168.1.100_expired_leases":               0,
+		"dhcp_range_192.168.1.1-192.168.1.100_new_leases":                   0,
+		"dhcp_range_192.168.1.1-192.168.1.100_released_leases":              0,
+		"dhcp_range_192.168.2.1-192.168.2.100_allocated_leases":             4,
+		"dhcp_range_192.168.2.1-192.168.2.100_utilization":                  4,
+		"dhcp_range_192.168.2.1-192.168.2.100_avg_lease_time_remaining":     0,
+		"dhcp_range_192.168.2.1-192.168.2.100_expired_leases":               0,
+		"dhcp_range_192.168.2.1-192.168.2.100_new_leases":                   0,
+		"dhcp_range_192.168.2.1-192.168.2.100_released_leases":              0,
+		"dhcp_range_192.168.200.1-192.168.200.100_allocated_leases":         1,
+		"dhcp_range_192.168.200.1-192.168.200.100_utilization":              1,
+		"dhcp_range_192.168.200.1-192.168.200.100_avg_lease_time_remaining": 0,
+		"dhcp_range_192.168.200.1-192.168.200.100_expired_leases":           0,
+		"dhcp_range_192.168.200.1-192.168.200.100_new_leases":               0,
+		"dhcp_range_192.168.200.1-192.168.200.100_released_leases":          0,
+		"dhcp_range_192.168.3.1-192.168.3.100_allocated_leases":             1,
+		"dhcp_range_192.168.3.1-192.168.3.100_utilization":                  1,
+		"dhcp_range_192.168.3.1-192.168.3.100_avg_lease_time_remaining":     0,
+		"dhcp_range_192.168.3.1-192.168.3.100_expired_leases":               0,
+		"dhcp_range_192.168.3.1-192.168.3.100_new_leases":                   0,
+		"dhcp_range_192.168.3.1-192.168.3.100_released_leases":              0,
+		"dhcp_range_192.168.4.1-192.168.4.100_allocated_leases":             1,
+		"dhcp_range_192.168.4.1-192.168.4.100_utilization":                  1,
+		"dhcp_range_192.168.4.1-192.168.4.100_avg_lease_time_remaining":     0,
+		"dhcp_range_192.168.4.1-192.168.4.100_expired_leases":               0,
+		"dhcp_range_192.168.4.1-192.168.4.100_new_leases":                   0,
+		"dhcp_range_192.168.4.1-192.168.4.100_released_leases":              0,
 		"ipv4_dhcp_hosts":  6,
 		"ipv4_dhcp_ranges": 6,
 		"ipv6_dhcp_hosts":  5,
@@ -132,3 +180,158 @@ func TestDnsmasqDHCP_CollectFailedToOpenLeasesPath(t *testing.T) {
 	job.LeasesPath = ""
 	assert.Nil(t, job.Collect())
 }
+
+func TestDnsmasqDHCP_Collect_LeasesChurn(t *testing.T) {
+	dir := t.TempDir()
+	leasesV4, leasesV6 := filepath.Join(dir, "dnsmasq.leases"), filepath.Join(dir, "dnsmasq6.leases")
+	copyFile(t, "testdata/churn/dnsmasq.leases", leasesV4)
+	copyFile(t, "testdata/churn/dnsmasq6.leases", leasesV6)
+
+	job := New()
+	job.LeasesPaths = []string{leasesV4, leasesV6, filepath.Join(dir, "not_exists.leases")}
+	job.ConfPath = "testdata/churn/dnsmasq.conf"
+	job.ConfDir = ""
+	job.interfaceAddrs = prepareInterfaceAddrs
+	now := time.Unix(1700000000, 0)
+	job.now = func() time.Time { return now }
+
+	require.True(t, job.Init())
+
+	expected := map[string]int64{
+		"dhcp_range_1240::1-1240::100_allocated_leases":                   2,
+		"dhcp_range_1240::1-1240::100_avg_lease_time_remaining":           5400,
+		"dhcp_range_1240::1-1240::100_expired_leases":                     0,
+		"dhcp_range_1240::1-1240::100_new_leases":                         0,
+		"dhcp_range_1240::1-1240::100_released_leases":                    0,
+		"dhcp_range_1240::1-1240::100_utilization":                        1,
+		"dhcp_range_1241::1-1241::ff_allocated_leases":                    1,
+		"dhcp_range_1241::1-1241::ff_avg_lease_time_remaining":            600,
+		"dhcp_range_1241::1-1241::ff_expired_leases":                      0,
+		"dhcp_range_1241::1-1241::ff_new_leases":                          0,
+		"dhcp_range_1241::1-1241::ff_released_leases":                     0,
+		"dhcp_range_1241::1-1241::ff_utilization":                         0,
+		"dhcp_range_192.168.10.1-192.168.10.100_allocated_leases":         4,
+		"dhcp_range_192.168.10.1-192.168.10.100_avg_lease_time_remaining": 1500,
+		"dhcp_range_192.168.10.1-192.168.10.100_expired_leases":           0,
+		"dhcp_range_192.168.10.1-192.168.10.100_new_leases":               0,
+		"dhcp_range_192.168.10.1-192.168.10.100_released_leases":          0,
+		"dhcp_range_192.168.10.1-192.168.10.100_utilization":              4,
+		"ipv4_dhcp_hosts":  0,
+		"ipv4_dhcp_ranges": 1,
+		"ipv6_dhcp_hosts":  0,
+		"ipv6_dhcp_ranges": 2,
+	}
+	assert.Equal(t, expected, job.Collect())
+	ensureCollectedHasAllChartsDimsVarsIDs(t, job, expected)
+
+	now = now.Add(time.Second * 100)
+	copyFile(t, "testdata/churn/dnsmasq.leases.after", leasesV4)
+	copyFile(t, "testdata/churn/dnsmasq6.leases.after", leasesV6)
+	for _, path := range []string{leasesV4, leasesV6} {
+		require.NoError(t, os.Chtimes(path, now, now.Add(time.Hour)))
+	}
+
+	expected = map[string]int64{
+		"dhcp_range_1240::1-1240::100_allocated_leases":                   3,
+		"dhcp_range_1240::1-1240::100_avg_lease_time_remaining":           17900,
+		"dhcp_range_1240::1-1240::100_expired_leases":                     0,
+		"dhcp_range_1240::1-1240::100_new_leases":                         1,
+		"dhcp_range_1240::1-1240::100_released_leases":                    0,
+		"dhcp_range_1240::1-1240::100_utilization":                        1,
+		"dhcp_range_1241::1-1241::ff_allocated_leases":                    1,
+		"dhcp_range_1241::1-1241::ff_avg_lease_time_remaining":            43100,
+		"dhcp_range_1241::1-1241::ff_expired_leases":                      0,
+		"dhcp_range_1241::1-1241::ff_new_leases":                          0,
+		"dhcp_range_1241::1-1241::ff_released_leases":                     0,
+		"dhcp_range_1241::1-1241::ff_utilization":                         0,
+		"dhcp_range_192.168.10.1-192.168.10.100_allocated_leases":         3,
+		"dhcp_range_192.168.10.1-192.168.10.100_avg_lease_time_remaining": 22000,
+		"dhcp_range_192.168.10.1-192.168.10.100_expired_leases":           1,
+		"dhcp_range_192.168.10.1-192.168.10.100_new_leases":               1,
+		"dhcp_range_192.168.10.1-192.168.10.100_released_leases":          1,
+		"dhcp_range_192.168.10.1-192.168.10.100_utilization":              3,
+		"ipv4_dhcp_hosts":  0,
+		"ipv4_dhcp_ranges": 1,
+		"ipv6_dhcp_hosts":  0,
+		"ipv6_dhcp_ranges": 2,
+	}
+	assert.Equal(t, expected, job.Collect())
+}
+
+func TestDnsmasqDHCP_InitNoLeasesPathsExist(t *testing.T) {
+	job := New()
+	job.LeasesPaths = []string{testLeasesPath + "!", testLeasesPath + "!!"}
+
+	assert.False(t, job.Init())
+}
+
+func TestDnsmasqDHCP_Collect_ConstructorRangePrefixChanged(t *testing.T) {
+	job := New()
+	job.LeasesPath = "testdata/churn/dnsmasq6.leases"
+	job.ConfPath = "testdata/churn/dnsmasq.conf"
+	job.ConfDir = ""
+	job.interfaceAddrs = prepareInterfaceAddrs
+
+	require.True(t, job.Init())
+	require.NotNil(t, job.Collect())
+	require.NotNil(t, job.Charts().Get("dhcp_range_1241::1-1241::ff_utilization"))
+
+	job.parseConfigTime = time.Time{}
+	job.interfaceAddrs = func(pattern string) ([]*net.IPNet, error) {
+		_, n, _ := net.ParseCIDR("1242::abcd/64")
+		n.IP = net.ParseIP("1242::abcd")
+		return []*net.IPNet{n}, nil
+	}
+	mx := job.Collect()
+
+	assert.Contains(t, mx, "dhcp_range_1242::1-1242::ff_utilization")
+	assert.NotContains(t, mx, "dhcp_range_1241::1-1241::ff_utilization")
+	assert.True(t, job.Charts().Get("dhcp_range_1241::1-1241::ff_utilization").Obsolete)
+	assert.NotNil(t, job.Charts().Get("dhcp_range_1242::1-1242::ff_utilization"))
+}
+
+func Test_parseLeases(t *testing.T) {
+	f, err := os.Open("testdata/churn/dnsmasq6.leases")
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	expected := []lease{
+		{ip: net.ParseIP("1240::10"), expires: 1700003600, client: "00:01:00:01:24:90:cf:a3:08:00:27:61:3c:ee/660684014"},
+		{ip: net.ParseIP("1240::11"), expires: 1700007200, client: "00:01:00:01:24:90:cf:a3:08:00:27:61:3c:ee/T660684015"},
+		{ip: net.ParseIP("1241::20"), expires: 1700000600, client: "00:04:ab:cd:ef:01:23:45:67:89:ab:cd:ef:01:23:45:67:89/12345"},
+	}
+
+	assert.Equal(t, expected, parseLeases(f))
+}
+
+func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, job *DnsmasqDHCP, collected map[string]int64) {
+	for _, chart := range *job.Charts() {
+		if chart.Obsolete {
+			continue
+		}
+		for _, dim := range chart.Dims {
+			_, ok := collected[dim.ID]
+			assert.Truef(t, ok, "collected metrics has no data for dim '%s' chart '%s'", dim.ID, chart.ID)
+		}
+	}
+}
+
+func prepareInterfaceAddrs(pattern string) ([]*net.IPNet, error) {
+	if pattern != "eth0" {
+		return nil, nil
+	}
+	var nets []*net.IPNet
+	for _, v := range []string{"10.0.0.1/24", "fe80::1/64", "1241::abcd/64"} {
+		ip, n, _ := net.ParseCIDR(v)
+		n.IP = ip
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	bs, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, bs, 0644))
+}
@@ -2,20 +2,53 @@
 
 package dnsmasq_dhcp
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 func (d *DnsmasqDHCP) validateConfig() error {
-	if d.LeasesPath == "" {
-		return errors.New("empty 'leases_path'")
+	if len(d.leasesFiles()) == 0 {
+		return errors.New("empty 'leases_path' and 'leases_paths'")
 	}
 	return nil
 }
 
+// leasesFiles returns 'leases_paths' if set ('leases_path' is ignored), some setups keep IPv4 and IPv6 leases
+// in separate files.
+func (d *DnsmasqDHCP) leasesFiles() []string {
+	if len(d.LeasesPaths) == 0 {
+		if d.LeasesPath == "" {
+			return nil
+		}
+		return []string{d.LeasesPath}
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range d.LeasesPaths {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// checkLeasesPath checks that at least one leases file can be opened.
 func (d *DnsmasqDHCP) checkLeasesPath() error {
-	f, err := openFile(d.LeasesPath)
-	if err != nil {
-		return err
+	var errs []error
+	paths := d.leasesFiles()
+	for _, path := range paths {
+		f, err := openFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_ = f.Close()
+	}
+	if len(errs) == len(paths) {
+		return fmt.Errorf("no leases file can be opened: %v", errors.Join(errs...))
 	}
-	_ = f.Close()
 	return nil
 }
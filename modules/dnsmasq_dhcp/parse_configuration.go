@@ -34,19 +34,30 @@ func (d *DnsmasqDHCP) getDHCPRanges(configs []*configFile) []iprange.Range {
 
 		for _, value := range conf.get("dhcp-range") {
 			d.Debugf("found dhcp-range '%s'", value)
-			if parsed = parseDHCPRangeValue(value); parsed == "" || seen[parsed] {
+			if parsed = parseDHCPRangeValue(value); parsed == "" {
 				continue
 			}
-			seen[parsed] = true
 
-			r, err := iprange.ParseRange(parsed)
-			if r == nil || err != nil {
-				d.Warningf("error on parsing dhcp-range '%s', skipping it", parsed)
-				continue
+			ranges := []string{parsed}
+			if iface := parseDHCPRangeConstructor(value); iface != "" {
+				ranges = d.constructDHCPRanges(parsed, iface)
 			}
 
-			d.Debugf("adding dhcp-range '%s'", parsed)
-			dhcpRanges = append(dhcpRanges, r)
+			for _, v := range ranges {
+				if seen[v] {
+					continue
+				}
+				seen[v] = true
+
+				r, err := iprange.ParseRange(v)
+				if r == nil || err != nil {
+					d.Warningf("error on parsing dhcp-range '%s', skipping it", v)
+					continue
+				}
+
+				d.Debugf("adding dhcp-range '%s'", v)
+				dhcpRanges = append(dhcpRanges, r)
+			}
 		}
 	}
 
@@ -117,6 +128,84 @@ func parseDHCPRangeValue(s string) (r string) {
 	return fmt.Sprintf("%s-%s", start, end)
 }
 
+/*
+Examples:
+  - ::1,::400,constructor:eth0
+  - ::,constructor:eth*,ra-only
+*/
+var reDHCPRangeConstructor = regexp.MustCompile(`constructor:([^,\s]+)`)
+
+func parseDHCPRangeConstructor(s string) string {
+	match := reDHCPRangeConstructor.FindStringSubmatch(s)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// constructDHCPRanges returns the ranges the constructor range ('::1-::400') has on the matching interfaces. The start
+// and the end addresses are the suffixes, the prefix is taken from the current IPv6 addresses of the interface.
+func (d *DnsmasqDHCP) constructDHCPRanges(suffixes, ifacePattern string) []string {
+	start, end, _ := strings.Cut(suffixes, "-")
+	startIP, endIP := net.ParseIP(start), net.ParseIP(end)
+	if startIP == nil || endIP == nil || startIP.To4() != nil {
+		return nil
+	}
+
+	nets, err := d.interfaceAddrs(ifacePattern)
+	if err != nil {
+		d.Warningf("error on getting '%s' interface addresses: %v", ifacePattern, err)
+		return nil
+	}
+
+	var ranges []string
+	for _, n := range nets {
+		if n.IP.To4() != nil || n.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		ranges = append(ranges, fmt.Sprintf("%s-%s", constructIP(n, startIP), constructIP(n, endIP)))
+	}
+	if len(ranges) == 0 {
+		d.Debugf("no IPv6 prefix on the '%s' interface, skipping the constructor dhcp-range '%s'", ifacePattern, suffixes)
+	}
+	return ranges
+}
+
+func constructIP(prefix *net.IPNet, suffix net.IP) net.IP {
+	ip, sfx, mask := prefix.IP.To16(), suffix.To16(), prefix.Mask
+
+	v := make(net.IP, net.IPv6len)
+	for i := range v {
+		v[i] = ip[i]&mask[i] | sfx[i]&^mask[i]
+	}
+	return v
+}
+
+// interfaceAddrs returns the addresses of the interfaces with the name matching the pattern ('eth0', 'eth*').
+func interfaceAddrs(pattern string) ([]*net.IPNet, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var nets []*net.IPNet
+	for _, iface := range ifaces {
+		if ok, _ := filepath.Match(pattern, iface.Name); !ok {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if n, ok := addr.(*net.IPNet); ok {
+				nets = append(nets, n)
+			}
+		}
+	}
+	return nets, nil
+}
+
 /*
 Examples:
   - 11:22:33:44:55:66,192.168.0.60
@@ -30,6 +30,10 @@ var (
 			{ID: "server_%s_record_%s_query_status_success", Name: "success"},
 			{ID: "server_%s_record_%s_query_status_network_error", Name: "network_error"},
 			{ID: "server_%s_record_%s_query_status_dns_error", Name: "dns_error"},
+			{ID: "server_%s_record_%s_query_status_timeout", Name: "timeout"},
+			{ID: "server_%s_record_%s_query_status_servfail", Name: "servfail"},
+			{ID: "server_%s_record_%s_query_status_nxdomain", Name: "nxdomain"},
+			{ID: "server_%s_record_%s_query_status_unexpected_answer", Name: "unexpected_answer"},
 		},
 	}
 	dnsQueryTimeChartTmpl = module.Chart{
@@ -45,18 +49,18 @@ var (
 	}
 )
 
-func newDNSServerCharts(server, network, rtype string) *module.Charts {
+func newDNSServerCharts(srv *dnsServer, rtype string) *module.Charts {
 	charts := dnsChartsTmpl.Copy()
 
 	for _, chart := range *charts {
-		chart.ID = fmt.Sprintf(chart.ID, strings.ReplaceAll(server, ".", "_"), rtype)
+		chart.ID = fmt.Sprintf(chart.ID, strings.ReplaceAll(srv.id, ".", "_"), rtype)
 		chart.Labels = []module.Label{
-			{Key: "server", Value: server},
-			{Key: "network", Value: network},
+			{Key: "server", Value: srv.name},
+			{Key: "network", Value: srv.network},
 			{Key: "record_type", Value: rtype},
 		}
 		for _, d := range chart.Dims {
-			d.ID = fmt.Sprintf(d.ID, server, rtype)
+			d.ID = fmt.Sprintf(d.ID, srv.id, rtype)
 		}
 	}
 
@@ -3,60 +3,99 @@
 package dnsquery
 
 import (
+	"context"
+	"errors"
 	"math/rand"
 	"net"
-	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/netdata/go.d.plugin/pkg/matcher"
+
 	"github.com/miekg/dns"
 )
 
+var queryStatuses = []string{
+	"success",
+	"network_error",
+	"dns_error",
+	"timeout",
+	"servfail",
+	"nxdomain",
+	"unexpected_answer",
+}
+
 func (d *DNSQuery) collect() (map[string]int64, error) {
-	if d.dnsClient == nil {
-		d.dnsClient = d.newDNSClient(d.Network, d.Timeout.Duration)
+	if d.dnsClients == nil {
+		d.dnsClients = make(map[string]dnsClient)
+	}
+	for _, srv := range d.servers {
+		if _, ok := d.dnsClients[srv.network]; !ok {
+			d.dnsClients[srv.network] = d.newDNSClient(srv.network, d.Timeout.Duration, d.tlsConfig)
+		}
 	}
 
 	mx := make(map[string]int64)
-	domain := randomDomain(d.Domains)
-	d.Debugf("current domain : %s", domain)
+	domain := randomDomain(d.domains)
+	d.Debugf("current domain : %s", domain.name)
 
 	var wg sync.WaitGroup
 	var mux sync.RWMutex
-	for _, srv := range d.Servers {
+	for _, srv := range d.servers {
 		for rtypeName, rtype := range d.recordTypes {
 			wg.Add(1)
-			go func(srv, rtypeName string, rtype uint16, wg *sync.WaitGroup) {
+			go func(srv *dnsServer, rtypeName string, rtype uint16, wg *sync.WaitGroup) {
 				defer wg.Done()
 
 				msg := new(dns.Msg)
-				msg.SetQuestion(dns.Fqdn(domain), rtype)
-				address := net.JoinHostPort(srv, strconv.Itoa(d.Port))
+				msg.SetQuestion(dns.Fqdn(domain.name), rtype)
 
-				resp, rtt, err := d.dnsClient.Exchange(msg, address)
+				resp, rtt, err := d.dnsClients[srv.network].Exchange(msg, srv.address)
 
 				mux.Lock()
 				defer mux.Unlock()
 
-				px := "server_" + srv + "_record_" + rtypeName + "_"
+				px := "server_" + srv.id + "_record_" + rtypeName + "_"
 
-				mx[px+"query_status_success"] = 0
-				mx[px+"query_status_network_error"] = 0
-				mx[px+"query_status_dns_error"] = 0
+				for _, v := range queryStatuses {
+					mx[px+"query_status_"+v] = 0
+				}
 
 				if err != nil {
-					d.Debugf("error on querying %s after %s query for %s : %s", srv, rtypeName, domain, err)
-					mx[px+"query_status_network_error"] = 1
+					d.Debugf("error on querying %s after %s query for %s : %s", srv.name, rtypeName, domain.name, err)
+					if isTimeout(err) {
+						mx[px+"query_status_timeout"] = 1
+					} else {
+						mx[px+"query_status_network_error"] = 1
+					}
 					return
 				}
 
-				if resp != nil && resp.Rcode != dns.RcodeSuccess {
-					d.Debugf("invalid answer from %s after %s query for %s (rcode %d)", srv, rtypeName, domain, resp.Rcode)
+				rcode := dns.RcodeSuccess
+				if resp != nil {
+					rcode = resp.Rcode
+				}
+
+				switch rcode {
+				case dns.RcodeSuccess:
+					if m, ok := domain.expected[rtypeName]; ok && !matchAnswer(m, resp, rtype) {
+						d.Debugf("unexpected answer from %s after %s query for %s", srv.name, rtypeName, domain.name)
+						mx[px+"query_status_unexpected_answer"] = 1
+					} else {
+						mx[px+"query_status_success"] = 1
+					}
+				case dns.RcodeServerFailure:
+					d.Debugf("SERVFAIL answer from %s after %s query for %s", srv.name, rtypeName, domain.name)
+					mx[px+"query_status_servfail"] = 1
+				case dns.RcodeNameError:
+					d.Debugf("NXDOMAIN answer from %s after %s query for %s", srv.name, rtypeName, domain.name)
+					mx[px+"query_status_nxdomain"] = 1
+				default:
+					d.Debugf("invalid answer from %s after %s query for %s (rcode %d)", srv.name, rtypeName, domain.name, rcode)
 					mx[px+"query_status_dns_error"] = 1
-				} else {
-					mx[px+"query_status_success"] = 1
 				}
-				mx["server_"+srv+"_record_"+rtypeName+"_query_time"] = rtt.Nanoseconds()
+				mx[px+"query_time"] = rtt.Nanoseconds()
 
 			}(srv, rtypeName, rtype, &wg)
 		}
@@ -66,7 +105,58 @@ func (d *DNSQuery) collect() (map[string]int64, error) {
 	return mx, nil
 }
 
-func randomDomain(domains []string) string {
+// matchAnswer reports whether any answer record of the queried type matches.
+func matchAnswer(m matcher.Matcher, resp *dns.Msg, rtype uint16) bool {
+	if resp == nil {
+		return false
+	}
+	for _, rr := range resp.Answer {
+		if rtype != dns.TypeANY && rr.Header().Rrtype != rtype {
+			continue
+		}
+		if m.MatchString(answerValue(rr)) {
+			return true
+		}
+	}
+	return false
+}
+
+// answerValue returns the record data, the domain names are without the trailing dot.
+func answerValue(rr dns.RR) string {
+	var v string
+	switch rr := rr.(type) {
+	case *dns.A:
+		v = rr.A.String()
+	case *dns.AAAA:
+		v = rr.AAAA.String()
+	case *dns.CNAME:
+		v = rr.Target
+	case *dns.NS:
+		v = rr.Ns
+	case *dns.PTR:
+		v = rr.Ptr
+	case *dns.MX:
+		v = rr.Mx
+	case *dns.SRV:
+		v = rr.Target
+	case *dns.SOA:
+		v = rr.Ns
+	case *dns.TXT:
+		v = strings.Join(rr.Txt, "")
+	case *dns.SPF:
+		v = strings.Join(rr.Txt, "")
+	default:
+		v = strings.TrimPrefix(rr.String(), rr.Header().String())
+	}
+	return strings.TrimSuffix(v, ".")
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &ne) && ne.Timeout())
+}
+
+func randomDomain(domains []*domain) *domain {
 	src := rand.NewSource(time.Now().UnixNano())
 	r := rand.New(src)
 	return domains[r.Intn(len(domains))]
@@ -3,10 +3,13 @@
 package dnsquery
 
 import (
+	"crypto/tls"
 	_ "embed"
+	"net/http"
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
 	"github.com/miekg/dns"
@@ -33,23 +36,50 @@ func New() *DNSQuery {
 			RecordTypes: []string{"A"},
 			Port:        53,
 		},
-		newDNSClient: func(network string, timeout time.Duration) dnsClient {
+		newDNSClient: func(network string, timeout time.Duration, tlsConfig *tls.Config) dnsClient {
+			if network == networkHTTPS {
+				return &dohClient{httpClient: &http.Client{
+					Timeout:   timeout,
+					Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+				}}
+			}
 			return &dns.Client{
 				Net:         network,
 				ReadTimeout: timeout,
+				TLSConfig:   tlsConfig,
 			}
 		},
 	}
 }
 
-type Config struct {
-	Domains     []string     `yaml:"domains"`
-	Servers     []string     `yaml:"servers"`
-	Network     string       `yaml:"network"`
-	RecordType  string       `yaml:"record_type"`
-	RecordTypes []string     `yaml:"record_types"`
-	Port        int          `yaml:"port"`
-	Timeout     web.Duration `yaml:"timeout"`
+type (
+	Config struct {
+		Domains          []DomainConfig `yaml:"domains"`
+		Servers          []string       `yaml:"servers"`
+		Network          string         `yaml:"network"`
+		RecordType       string         `yaml:"record_type"`
+		RecordTypes      []string       `yaml:"record_types"`
+		Port             int            `yaml:"port"`
+		Timeout          web.Duration   `yaml:"timeout"`
+		tlscfg.TLSConfig `yaml:",inline"`
+	}
+	// DomainConfig is a 'domains' entry: either a domain name or an object with the domain name and the expected
+	// answer per record type.
+	DomainConfig struct {
+		Name     string            `yaml:"name"`
+		Expected map[string]string `yaml:"expected"`
+	}
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *DomainConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		*d = DomainConfig{Name: name}
+		return nil
+	}
+	type plain DomainConfig
+	return unmarshal((*plain)(d))
 }
 
 type (
@@ -60,10 +90,13 @@ type (
 
 		charts *module.Charts
 
-		newDNSClient func(network string, duration time.Duration) dnsClient
+		newDNSClient func(network string, duration time.Duration, tlsConfig *tls.Config) dnsClient
 		recordTypes  map[string]uint16
+		servers      []*dnsServer
+		domains      []*domain
+		tlsConfig    *tls.Config
 
-		dnsClient dnsClient
+		dnsClients map[string]dnsClient
 	}
 
 	dnsClient interface {
@@ -84,6 +117,27 @@ func (d *DNSQuery) Init() bool {
 	}
 	d.recordTypes = rt
 
+	servers, err := d.initServers()
+	if err != nil {
+		d.Errorf("init servers: %v", err)
+		return false
+	}
+	d.servers = servers
+
+	domains, err := d.initDomains()
+	if err != nil {
+		d.Errorf("init domains: %v", err)
+		return false
+	}
+	d.domains = domains
+
+	tlsConfig, err := tlscfg.NewTLSConfig(d.TLSConfig)
+	if err != nil {
+		d.Errorf("init TLS config: %v", err)
+		return false
+	}
+	d.tlsConfig = tlsConfig
+
 	charts, err := d.initCharts()
 	if err != nil {
 		d.Errorf("init charts: %v", err)
@@ -3,7 +3,14 @@
 package dnsquery
 
 import (
+	"crypto/tls"
 	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +20,7 @@ import (
 	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestNew(t *testing.T) {
@@ -27,7 +35,7 @@ func TestDNSQuery_Init(t *testing.T) {
 		"success when all set": {
 			wantFail: false,
 			config: Config{
-				Domains:     []string{"example.com"},
+				Domains:     []DomainConfig{{Name: "example.com"}},
 				Servers:     []string{"192.0.2.0"},
 				Network:     "udp",
 				RecordTypes: []string{"A"},
@@ -38,7 +46,7 @@ func TestDNSQuery_Init(t *testing.T) {
 		"success when using deprecated record_type": {
 			wantFail: false,
 			config: Config{
-				Domains:    []string{"example.com"},
+				Domains:    []DomainConfig{{Name: "example.com"}},
 				Servers:    []string{"192.0.2.0"},
 				Network:    "udp",
 				RecordType: "A",
@@ -64,7 +72,7 @@ func TestDNSQuery_Init(t *testing.T) {
 		"fail when servers not set": {
 			wantFail: true,
 			config: Config{
-				Domains:     []string{"example.com"},
+				Domains:     []DomainConfig{{Name: "example.com"}},
 				Servers:     nil,
 				Network:     "udp",
 				RecordTypes: []string{"A"},
@@ -75,7 +83,7 @@ func TestDNSQuery_Init(t *testing.T) {
 		"fail when network is invalid": {
 			wantFail: true,
 			config: Config{
-				Domains:     []string{"example.com"},
+				Domains:     []DomainConfig{{Name: "example.com"}},
 				Servers:     []string{"192.0.2.0"},
 				Network:     "gcp",
 				RecordTypes: []string{"A"},
@@ -86,7 +94,7 @@ func TestDNSQuery_Init(t *testing.T) {
 		"fail when record_type is invalid": {
 			wantFail: true,
 			config: Config{
-				Domains:     []string{"example.com"},
+				Domains:     []DomainConfig{{Name: "example.com"}},
 				Servers:     []string{"192.0.2.0"},
 				Network:     "udp",
 				RecordTypes: []string{"B"},
@@ -143,7 +151,7 @@ func TestDNSQuery_Check(t *testing.T) {
 func TestDNSQuery_Charts(t *testing.T) {
 	dq := New()
 
-	dq.Domains = []string{"google.com"}
+	dq.Domains = []DomainConfig{{Name: "google.com"}}
 	dq.Servers = []string{"192.0.2.0", "192.0.2.1"}
 	require.True(t, dq.Init())
 
@@ -159,25 +167,41 @@ func TestDNSQuery_Collect(t *testing.T) {
 		"success when DNS query successful": {
 			prepare: caseDNSClientOK,
 			wantMetrics: map[string]int64{
-				"server_192.0.2.0_record_A_query_status_dns_error":     0,
-				"server_192.0.2.0_record_A_query_status_network_error": 0,
-				"server_192.0.2.0_record_A_query_status_success":       1,
-				"server_192.0.2.0_record_A_query_time":                 1000000000,
-				"server_192.0.2.1_record_A_query_status_dns_error":     0,
-				"server_192.0.2.1_record_A_query_status_network_error": 0,
-				"server_192.0.2.1_record_A_query_status_success":       1,
-				"server_192.0.2.1_record_A_query_time":                 1000000000,
+				"server_192.0.2.0_record_A_query_status_dns_error":         0,
+				"server_192.0.2.0_record_A_query_status_network_error":     0,
+				"server_192.0.2.0_record_A_query_status_nxdomain":          0,
+				"server_192.0.2.0_record_A_query_status_servfail":          0,
+				"server_192.0.2.0_record_A_query_status_success":           1,
+				"server_192.0.2.0_record_A_query_status_timeout":           0,
+				"server_192.0.2.0_record_A_query_status_unexpected_answer": 0,
+				"server_192.0.2.0_record_A_query_time":                     1000000000,
+				"server_192.0.2.1_record_A_query_status_dns_error":         0,
+				"server_192.0.2.1_record_A_query_status_network_error":     0,
+				"server_192.0.2.1_record_A_query_status_nxdomain":          0,
+				"server_192.0.2.1_record_A_query_status_servfail":          0,
+				"server_192.0.2.1_record_A_query_status_success":           1,
+				"server_192.0.2.1_record_A_query_status_timeout":           0,
+				"server_192.0.2.1_record_A_query_status_unexpected_answer": 0,
+				"server_192.0.2.1_record_A_query_time":                     1000000000,
 			},
 		},
 		"fail when DNS query returns an error": {
 			prepare: caseDNSClientErr,
 			wantMetrics: map[string]int64{
-				"server_192.0.2.0_record_A_query_status_dns_error":     0,
-				"server_192.0.2.0_record_A_query_status_network_error": 1,
-				"server_192.0.2.0_record_A_query_status_success":       0,
-				"server_192.0.2.1_record_A_query_status_dns_error":     0,
-				"server_192.0.2.1_record_A_query_status_network_error": 1,
-				"server_192.0.2.1_record_A_query_status_success":       0,
+				"server_192.0.2.0_record_A_query_status_dns_error":         0,
+				"server_192.0.2.0_record_A_query_status_network_error":     1,
+				"server_192.0.2.0_record_A_query_status_nxdomain":          0,
+				"server_192.0.2.0_record_A_query_status_servfail":          0,
+				"server_192.0.2.0_record_A_query_status_success":           0,
+				"server_192.0.2.0_record_A_query_status_timeout":           0,
+				"server_192.0.2.0_record_A_query_status_unexpected_answer": 0,
+				"server_192.0.2.1_record_A_query_status_dns_error":         0,
+				"server_192.0.2.1_record_A_query_status_network_error":     1,
+				"server_192.0.2.1_record_A_query_status_nxdomain":          0,
+				"server_192.0.2.1_record_A_query_status_servfail":          0,
+				"server_192.0.2.1_record_A_query_status_success":           0,
+				"server_192.0.2.1_record_A_query_status_timeout":           0,
+				"server_192.0.2.1_record_A_query_status_unexpected_answer": 0,
 			},
 		},
 	}
@@ -195,11 +219,214 @@ func TestDNSQuery_Collect(t *testing.T) {
 	}
 }
 
+func TestDNSQuery_Collect_DoH(t *testing.T) {
+	tests := map[string]struct {
+		domain     DomainConfig
+		wantStatus string
+	}{
+		"success": {
+			domain:     DomainConfig{Name: "example.com"},
+			wantStatus: "success",
+		},
+		"success on expected answer": {
+			domain:     DomainConfig{Name: "example.com", Expected: map[string]string{"A": "192.0.2.10"}},
+			wantStatus: "success",
+		},
+		"success on expected answer regexp": {
+			domain:     DomainConfig{Name: "example.com", Expected: map[string]string{"A": `~ ^192\.0\.2\.`}},
+			wantStatus: "success",
+		},
+		"unexpected answer": {
+			domain:     DomainConfig{Name: "example.com", Expected: map[string]string{"A": "192.0.2.11"}},
+			wantStatus: "unexpected_answer",
+		},
+		"unexpected answer regexp": {
+			domain:     DomainConfig{Name: "example.com", Expected: map[string]string{"A": `~ ^10\.`}},
+			wantStatus: "unexpected_answer",
+		},
+		"nxdomain": {
+			domain:     DomainConfig{Name: "nxdomain.example.com"},
+			wantStatus: "nxdomain",
+		},
+		"servfail": {
+			domain:     DomainConfig{Name: "servfail.example.com"},
+			wantStatus: "servfail",
+		},
+		"dns error": {
+			domain:     DomainConfig{Name: "refused.example.com"},
+			wantStatus: "dns_error",
+		},
+		"network error on bad response": {
+			domain:     DomainConfig{Name: "garbage.example.com"},
+			wantStatus: "network_error",
+		},
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(dohHandler))
+	defer srv.Close()
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dq := New()
+			dq.Domains = []DomainConfig{test.domain}
+			dq.Servers = []string{srv.URL + "/dns-query"}
+			dq.InsecureSkipVerify = true
+			require.True(t, dq.Init())
+
+			mx := dq.Collect()
+			require.NotNil(t, mx)
+
+			srvID := strings.NewReplacer(":", "_", "/", "_").Replace(strings.TrimPrefix(srv.URL, "https://"))
+			px := "server_https_" + srvID + "_dns-query_record_A_query_status_"
+			for _, status := range queryStatuses {
+				want := int64(0)
+				if status == test.wantStatus {
+					want = 1
+				}
+				assert.Equalf(t, want, mx[px+status], "status '%s'", status)
+			}
+
+			chart := (*dq.Charts())[0]
+			assert.Equal(t, []module.Label{
+				{Key: "server", Value: srv.URL + "/dns-query"},
+				{Key: "network", Value: "https"},
+				{Key: "record_type", Value: "A"},
+			}, chart.Labels)
+			for _, dim := range chart.Dims {
+				assert.Contains(t, mx, dim.ID)
+			}
+		})
+	}
+}
+
+func TestDNSQuery_Collect_Timeout(t *testing.T) {
+	dq := caseDNSClientOK()
+	dq.newDNSClient = func(_ string, _ time.Duration, _ *tls.Config) dnsClient {
+		return mockDNSClient{errOnExchange: true, err: os.ErrDeadlineExceeded}
+	}
+	require.True(t, dq.Init())
+
+	mx := dq.Collect()
+
+	assert.Equal(t, int64(1), mx["server_192.0.2.0_record_A_query_status_timeout"])
+	assert.Equal(t, int64(0), mx["server_192.0.2.0_record_A_query_status_network_error"])
+}
+
+func Test_parseServer(t *testing.T) {
+	tests := map[string]struct {
+		server  string
+		want    *dnsServer
+		wantErr bool
+	}{
+		"plain": {
+			server: "192.0.2.0",
+			want:   &dnsServer{name: "192.0.2.0", id: "192.0.2.0", network: "udp", address: "192.0.2.0:53"},
+		},
+		"plain IPv6": {
+			server: "2001:db8::1",
+			want:   &dnsServer{name: "2001:db8::1", id: "2001:db8::1", network: "udp", address: "[2001:db8::1]:53"},
+		},
+		"tcp": {
+			server: "tcp://192.0.2.0",
+			want:   &dnsServer{name: "tcp://192.0.2.0", id: "tcp_192.0.2.0_53", network: "tcp", address: "192.0.2.0:53"},
+		},
+		"DoT": {
+			server: "tls://192.0.2.0",
+			want:   &dnsServer{name: "tls://192.0.2.0", id: "tls_192.0.2.0_853", network: "tcp-tls", address: "192.0.2.0:853"},
+		},
+		"DoT with port": {
+			server: "tls://[2001:db8::1]:8853",
+			want:   &dnsServer{name: "tls://[2001:db8::1]:8853", id: "tls_2001_db8__1_8853", network: "tcp-tls", address: "[2001:db8::1]:8853"},
+		},
+		"DoH": {
+			server: "https://dns.example.com/dns-query",
+			want:   &dnsServer{name: "https://dns.example.com/dns-query", id: "https_dns.example.com_dns-query", network: "https", address: "https://dns.example.com/dns-query"},
+		},
+		"DoH without path": {
+			server: "https://dns.example.com",
+			want:   &dnsServer{name: "https://dns.example.com", id: "https_dns.example.com_dns-query", network: "https", address: "https://dns.example.com/dns-query"},
+		},
+		"unknown scheme": {
+			server:  "quic://192.0.2.0",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv, err := parseServer(test.server, "udp", 53)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.want, srv)
+			}
+		})
+	}
+}
+
+func TestDomainConfig_UnmarshalYAML(t *testing.T) {
+	data := `
+domains:
+  - example.com
+  - name: example.org
+    expected:
+      A: 192.0.2.10
+      TXT: "~ ^v=spf1 "
+`
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal([]byte(data), &cfg))
+
+	assert.Equal(t, []DomainConfig{
+		{Name: "example.com"},
+		{Name: "example.org", Expected: map[string]string{"A": "192.0.2.10", "TXT": "~ ^v=spf1 "}},
+	}, cfg.Domains)
+}
+
+func dohHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/dns-message" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	var req dns.Msg
+	if err := req.Unpack(body); err != nil || len(req.Question) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp := new(dns.Msg)
+	resp.SetReply(&req)
+
+	switch req.Question[0].Name {
+	case "example.com.":
+		resp.Answer = append(resp.Answer, &dns.A{
+			Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+			A:   net.ParseIP("192.0.2.10"),
+		})
+	case "nxdomain.example.com.":
+		resp.Rcode = dns.RcodeNameError
+	case "servfail.example.com.":
+		resp.Rcode = dns.RcodeServerFailure
+	case "refused.example.com.":
+		resp.Rcode = dns.RcodeRefused
+	case "garbage.example.com.":
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write([]byte("garbage"))
+		return
+	}
+
+	bs, _ := resp.Pack()
+	w.Header().Set("Content-Type", "application/dns-message")
+	_, _ = w.Write(bs)
+}
+
 func caseDNSClientOK() *DNSQuery {
 	dq := New()
-	dq.Domains = []string{"example.com"}
+	dq.Domains = []DomainConfig{{Name: "example.com"}}
 	dq.Servers = []string{"192.0.2.0", "192.0.2.1"}
-	dq.newDNSClient = func(_ string, _ time.Duration) dnsClient {
+	dq.newDNSClient = func(_ string, _ time.Duration, _ *tls.Config) dnsClient {
 		return mockDNSClient{errOnExchange: false}
 	}
 	return dq
@@ -207,9 +434,9 @@ func caseDNSClientOK() *DNSQuery {
 
 func caseDNSClientErr() *DNSQuery {
 	dq := New()
-	dq.Domains = []string{"example.com"}
+	dq.Domains = []DomainConfig{{Name: "example.com"}}
 	dq.Servers = []string{"192.0.2.0", "192.0.2.1"}
-	dq.newDNSClient = func(_ string, _ time.Duration) dnsClient {
+	dq.newDNSClient = func(_ string, _ time.Duration, _ *tls.Config) dnsClient {
 		return mockDNSClient{errOnExchange: true}
 	}
 	return dq
@@ -217,10 +444,14 @@ func caseDNSClientErr() *DNSQuery {
 
 type mockDNSClient struct {
 	errOnExchange bool
+	err           error
 }
 
 func (m mockDNSClient) Exchange(_ *dns.Msg, _ string) (response *dns.Msg, rtt time.Duration, err error) {
 	if m.errOnExchange {
+		if m.err != nil {
+			return nil, time.Second, m.err
+		}
 		return nil, time.Second, errors.New("mock.Exchange() error")
 	}
 	return nil, time.Second, nil
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package dnsquery
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const dohMediaType = "application/dns-message"
+
+// dohClient sends the DNS-over-HTTPS queries (RFC 8484) using the POST method.
+type dohClient struct {
+	httpClient *http.Client
+}
+
+func (c *dohClient) Exchange(msg *dns.Msg, url string) (*dns.Msg, time.Duration, error) {
+	// the ID is 0 to make the requests cache friendly (RFC 8484 4.1)
+	m := msg.Copy()
+	m.Id = 0
+
+	bs, err := m.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error on packing the query: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(bs))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", dohMediaType)
+	req.Header.Set("Accept", dohMediaType)
+
+	start := time.Now()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("'%s' returned HTTP status code: %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	rtt := time.Since(start)
+
+	var r dns.Msg
+	if err := r.Unpack(body); err != nil {
+		return nil, 0, fmt.Errorf("error on unpacking the response: %v", err)
+	}
+	r.Id = msg.Id
+
+	return &r, rtt, nil
+}
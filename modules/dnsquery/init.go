@@ -5,11 +5,37 @@ package dnsquery
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 
 	"github.com/miekg/dns"
 )
 
+const (
+	networkUDP    = "udp"
+	networkTCP    = "tcp"
+	networkTCPTLS = "tcp-tls"
+	networkHTTPS  = "https"
+)
+
+type (
+	dnsServer struct {
+		name    string // as it is set in the configuration, the 'server' label
+		id      string // the charts and the dimensions ID part
+		network string
+		address string // 'host:port' or the DNS-over-HTTPS URL
+	}
+	domain struct {
+		name     string
+		expected map[string]matcher.Matcher // per record type
+	}
+)
+
 func (d *DNSQuery) verifyConfig() error {
 	if len(d.Domains) == 0 {
 		return errors.New("no domains specified")
@@ -35,6 +61,53 @@ func (d *DNSQuery) verifyConfig() error {
 	return nil
 }
 
+// initServers parses the servers. The transport is set per server using the scheme: 'udp://', 'tcp://',
+// 'tls://' (DNS-over-TLS, the default port is 853) or 'https://' (DNS-over-HTTPS). The servers without the scheme
+// use 'network' and 'port'.
+func (d *DNSQuery) initServers() ([]*dnsServer, error) {
+	var servers []*dnsServer
+	seen := make(map[string]bool)
+
+	for _, v := range d.Servers {
+		srv, err := parseServer(v, d.Network, d.Port)
+		if err != nil {
+			return nil, fmt.Errorf("server '%s': %v", v, err)
+		}
+		if seen[srv.id] {
+			continue
+		}
+		seen[srv.id] = true
+		servers = append(servers, srv)
+	}
+
+	return servers, nil
+}
+
+func (d *DNSQuery) initDomains() ([]*domain, error) {
+	var domains []*domain
+
+	for _, v := range d.Domains {
+		if v.Name == "" {
+			return nil, errors.New("domain name is not set")
+		}
+
+		dom := &domain{name: v.Name, expected: make(map[string]matcher.Matcher)}
+		for rtype, expected := range v.Expected {
+			if _, err := parseRecordType(rtype); err != nil {
+				return nil, fmt.Errorf("domain '%s': %v", v.Name, err)
+			}
+			m, err := parseExpectedAnswer(expected)
+			if err != nil {
+				return nil, fmt.Errorf("domain '%s' record type '%s' expected answer: %v", v.Name, rtype, err)
+			}
+			dom.expected[rtype] = m
+		}
+		domains = append(domains, dom)
+	}
+
+	return domains, nil
+}
+
 func (d *DNSQuery) initRecordTypes() (map[string]uint16, error) {
 	types := make(map[string]uint16)
 	for _, v := range d.RecordTypes {
@@ -52,9 +125,9 @@ func (d *DNSQuery) initRecordTypes() (map[string]uint16, error) {
 func (d *DNSQuery) initCharts() (*module.Charts, error) {
 	var charts module.Charts
 
-	for _, srv := range d.Servers {
+	for _, srv := range d.servers {
 		for _, rtype := range d.RecordTypes {
-			cs := newDNSServerCharts(srv, d.Network, rtype)
+			cs := newDNSServerCharts(srv, rtype)
 			if err := charts.Add(*cs...); err != nil {
 				return nil, err
 			}
@@ -96,3 +169,70 @@ func parseRecordType(recordType string) (uint16, error) {
 
 	return rtype, nil
 }
+
+func parseServer(s, network string, port int) (*dnsServer, error) {
+	if !strings.Contains(s, "://") {
+		if network == "" {
+			network = networkUDP
+		}
+		return &dnsServer{
+			name:    s,
+			id:      s,
+			network: network,
+			address: net.JoinHostPort(s, strconv.Itoa(port)),
+		}, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("no host")
+	}
+
+	srv := &dnsServer{name: s}
+
+	switch u.Scheme {
+	case "udp", "tcp":
+		srv.network = u.Scheme
+		srv.address = hostPort(u, 53)
+	case "tls":
+		srv.network = networkTCPTLS
+		srv.address = hostPort(u, 853)
+	case "https":
+		srv.network = networkHTTPS
+		if u.Path == "" {
+			u.Path = "/dns-query"
+		}
+		srv.address = u.String()
+	default:
+		return nil, fmt.Errorf("unknown scheme '%s' (expected one of udp, tcp, tls, https)", u.Scheme)
+	}
+
+	// e.g. 'tls_1.1.1.1_853', 'https_dns.google_dns-query'
+	id := u.Scheme + "_" + u.Host + u.Path
+	if srv.network != networkHTTPS {
+		id = u.Scheme + "_" + srv.address
+	}
+	srv.id = strings.NewReplacer("[", "", "]", "", ":", "_", "/", "_").Replace(strings.TrimSuffix(id, "/"))
+
+	return srv, nil
+}
+
+func hostPort(u *url.URL, defaultPort int) string {
+	port := u.Port()
+	if port == "" {
+		port = strconv.Itoa(defaultPort)
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// parseExpectedAnswer returns the expected answer matcher. The value is either an exact answer
+// or a regular expression in the '~ <regexp>' form.
+func parseExpectedAnswer(s string) (matcher.Matcher, error) {
+	if strings.HasPrefix(s, "~ ") {
+		return matcher.Parse(s)
+	}
+	return matcher.NewStringMatcher(s, true, true)
+}
@@ -16,6 +16,10 @@ const (
 	prioContainerState
 	prioContainerHealthStatus
 	prioContainerWritableLayerSize
+	prioContainerCPUUsage
+	prioContainerMemoryUsage
+	prioContainerNetworkIO
+	prioContainerBlockIO
 
 	prioImagesCount
 	prioImagesSize
@@ -140,6 +144,65 @@ var (
 	}
 )
 
+var (
+	containerStatsChartsTmpl = module.Charts{
+		containerCPUUsageChartTmpl.Copy(),
+		containerMemoryUsageChartTmpl.Copy(),
+		containerNetworkIOChartTmpl.Copy(),
+		containerBlockIOChartTmpl.Copy(),
+	}
+
+	containerCPUUsageChartTmpl = module.Chart{
+		ID:       "container_%s_cpu_usage",
+		Title:    "Docker container CPU usage",
+		Units:    "percentage",
+		Fam:      "containers",
+		Ctx:      "docker.container_cpu_usage",
+		Priority: prioContainerCPUUsage,
+		Dims: module.Dims{
+			{ID: "container_%s_cpu_usage", Name: "cpu", Div: precision},
+		},
+	}
+	containerMemoryUsageChartTmpl = module.Chart{
+		ID:       "container_%s_mem_usage",
+		Title:    "Docker container memory usage",
+		Units:    "bytes",
+		Fam:      "containers",
+		Ctx:      "docker.container_mem_usage",
+		Priority: prioContainerMemoryUsage,
+		Dims: module.Dims{
+			{ID: "container_%s_mem_usage", Name: "usage"},
+			{ID: "container_%s_mem_limit", Name: "limit"},
+		},
+	}
+	containerNetworkIOChartTmpl = module.Chart{
+		ID:       "container_%s_net_io",
+		Title:    "Docker container network I/O",
+		Units:    "kilobits/s",
+		Fam:      "containers",
+		Ctx:      "docker.container_net_io",
+		Priority: prioContainerNetworkIO,
+		Type:     module.Area,
+		Dims: module.Dims{
+			{ID: "container_%s_net_rx_bytes", Name: "received", Algo: module.Incremental, Mul: 8, Div: 1000},
+			{ID: "container_%s_net_tx_bytes", Name: "sent", Algo: module.Incremental, Mul: -8, Div: 1000},
+		},
+	}
+	containerBlockIOChartTmpl = module.Chart{
+		ID:       "container_%s_blkio",
+		Title:    "Docker container block I/O",
+		Units:    "bytes/s",
+		Fam:      "containers",
+		Ctx:      "docker.container_blkio",
+		Priority: prioContainerBlockIO,
+		Type:     module.Area,
+		Dims: module.Dims{
+			{ID: "container_%s_blkio_read_bytes", Name: "read", Algo: module.Incremental},
+			{ID: "container_%s_blkio_write_bytes", Name: "write", Algo: module.Incremental, Mul: -1},
+		},
+	}
+)
+
 func (d *Docker) addContainerCharts(name, image string) {
 	charts := containerChartsTmpl.Copy()
 	if !d.CollectContainerSize {
@@ -162,6 +225,39 @@ func (d *Docker) addContainerCharts(name, image string) {
 	}
 }
 
+func (d *Docker) addContainerStatsCharts(name, image string) {
+	charts := containerStatsChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, name)
+		chart.Labels = []module.Label{
+			{Key: "container_name", Value: name},
+			{Key: "image", Value: image},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, name)
+		}
+	}
+
+	if err := d.Charts().Add(*charts...); err != nil {
+		d.Warning(err)
+	}
+}
+
+func (d *Docker) removeContainerStatsCharts(name string) {
+	ids := make(map[string]bool)
+	for _, tmpl := range containerStatsChartsTmpl {
+		ids[fmt.Sprintf(tmpl.ID, name)] = true
+	}
+
+	for _, chart := range *d.Charts() {
+		if ids[chart.ID] {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
 func (d *Docker) removeContainerCharts(name string) {
 	px := fmt.Sprintf("container_%s", name)
 
@@ -126,6 +126,7 @@ func (d *Docker) collectContainers(mx map[string]int64) error {
 	}
 
 	seen := make(map[string]bool)
+	var statsContainers []types.Container
 
 	for _, s := range containerHealthStatuses {
 		mx["containers_health_status_"+s] = 0
@@ -177,9 +178,17 @@ func (d *Docker) collectContainers(mx map[string]int64) error {
 			mx[px+"state_"+cntr.State] = 1
 			mx[px+"size_rw"] = cntr.SizeRw
 			mx[px+"size_root_fs"] = cntr.SizeRootFs
+
+			if d.CollectContainerStats && cntr.State == "running" && d.matchContainer(name, cntr.Labels) {
+				statsContainers = append(statsContainers, cntr)
+			}
 		}
 	}
 
+	if d.CollectContainerStats {
+		d.collectContainersStats(mx, statsContainers)
+	}
+
 	for name := range d.containers {
 		if !seen[name] {
 			delete(d.containers, name)
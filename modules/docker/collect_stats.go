@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+)
+
+const precision = 1000
+
+type containerStatsState struct {
+	hasPrev    bool
+	prevCPU    uint64
+	prevSystem uint64
+}
+
+// matchContainer reports whether the container name matches 'container_selector' and the container has all
+// the 'container_label_selector' labels ('key' or 'key=value').
+func (d *Docker) matchContainer(name string, labels map[string]string) bool {
+	if !d.containerSelector.MatchString(name) {
+		return false
+	}
+	for _, v := range d.ContainerLabelSelector {
+		key, value, withValue := strings.Cut(v, "=")
+		lv, ok := labels[key]
+		if !ok || (withValue && lv != value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *Docker) collectContainersStats(mx map[string]int64, containers []types.Container) {
+	if d.ContainerStatsMaxContainers > 0 && len(containers) > d.ContainerStatsMaxContainers {
+		if !d.statsCapExceeded {
+			d.statsCapExceeded = true
+			d.Warningf("the number of matched containers (%d) exceeds 'container_stats_max_containers' (%d), skipping stats collection",
+				len(containers), d.ContainerStatsMaxContainers)
+		}
+		containers = nil
+	} else {
+		d.statsCapExceeded = false
+	}
+
+	stats := d.queryContainersStats(containers)
+
+	seen := make(map[string]bool)
+	for _, cntr := range containers {
+		name := strings.TrimPrefix(cntr.Names[0], "/")
+		seen[name] = true
+
+		state, ok := d.containerStats[name]
+		if !ok {
+			state = &containerStatsState{}
+			d.containerStats[name] = state
+			d.addContainerStatsCharts(name, cntr.Image)
+		}
+
+		if st, ok := stats[cntr.ID]; ok {
+			writeContainerStats(mx, fmt.Sprintf("container_%s_", name), st, state)
+		}
+	}
+
+	// the stopped (and removed) containers
+	for name := range d.containerStats {
+		if !seen[name] {
+			delete(d.containerStats, name)
+			d.removeContainerStatsCharts(name)
+		}
+	}
+}
+
+// queryContainersStats requests the containers stats concurrently (at most 'container_stats_concurrency' requests).
+func (d *Docker) queryContainersStats(containers []types.Container) map[string]*types.StatsJSON {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, d.ContainerStatsConcurrency)
+	stats := make(map[string]*types.StatsJSON)
+
+	for _, cntr := range containers {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(id string) {
+			defer func() { <-sem; wg.Done() }()
+
+			st, err := d.queryContainerStats(id)
+			if err != nil {
+				d.Warningf("container '%s' stats: %v", id, err)
+				return
+			}
+
+			mu.Lock()
+			stats[id] = st
+			mu.Unlock()
+		}(cntr.ID)
+	}
+
+	wg.Wait()
+
+	return stats
+}
+
+func (d *Docker) queryContainerStats(id string) (*types.StatsJSON, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.ContainerStatsTimeout.Duration)
+	defer cancel()
+
+	// one-shot: the engine doesn't wait for the second sample to fill 'precpu_stats'
+	resp, err := d.client.ContainerStatsOneShot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var st types.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return nil, fmt.Errorf("error on decoding response: %v", err)
+	}
+	return &st, nil
+}
+
+func writeContainerStats(mx map[string]int64, px string, st *types.StatsJSON, state *containerStatsState) {
+	// the same calculation as 'docker stats' does, 100% is one CPU
+	cpu, system := st.CPUStats.CPUUsage.TotalUsage, st.CPUStats.SystemUsage
+	if state.hasPrev && cpu >= state.prevCPU && system > state.prevSystem {
+		cpus := float64(st.CPUStats.OnlineCPUs)
+		if cpus == 0 {
+			cpus = float64(len(st.CPUStats.CPUUsage.PercpuUsage))
+		}
+		v := float64(cpu-state.prevCPU) / float64(system-state.prevSystem) * cpus * 100
+		mx[px+"cpu_usage"] = int64(v * precision)
+	}
+	// the Windows engine doesn't report the system usage
+	state.hasPrev = system > 0
+	state.prevCPU, state.prevSystem = cpu, system
+
+	mx[px+"mem_usage"] = int64(memoryUsage(st.MemoryStats))
+	mx[px+"mem_limit"] = int64(st.MemoryStats.Limit)
+
+	var rx, tx uint64
+	for _, n := range st.Networks {
+		rx += n.RxBytes
+		tx += n.TxBytes
+	}
+	mx[px+"net_rx_bytes"] = int64(rx)
+	mx[px+"net_tx_bytes"] = int64(tx)
+
+	var read, write uint64
+	for _, e := range st.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			read += e.Value
+		case "write":
+			write += e.Value
+		}
+	}
+	mx[px+"blkio_read_bytes"] = int64(read)
+	mx[px+"blkio_write_bytes"] = int64(write)
+}
+
+// memoryUsage excludes the page cache the same way 'docker stats' does.
+func memoryUsage(mem types.MemoryStats) uint64 {
+	// cgroup v1
+	if v, ok := mem.Stats["total_inactive_file"]; ok && v < mem.Usage {
+		return mem.Usage - v
+	}
+	// cgroup v2
+	if v, ok := mem.Stats["inactive_file"]; ok && v < mem.Usage {
+		return mem.Usage - v
+	}
+	return mem.Usage
+}
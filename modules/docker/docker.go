@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
 	"github.com/docker/docker/api/types"
@@ -30,13 +31,18 @@ func New() *Docker {
 			Address:              docker.DefaultDockerHost,
 			Timeout:              web.Duration{Duration: time.Second * 5},
 			CollectContainerSize: false,
+
+			ContainerStatsTimeout:       web.Duration{Duration: time.Second * 2},
+			ContainerStatsConcurrency:   5,
+			ContainerStatsMaxContainers: 100,
 		},
 
 		charts: summaryCharts.Copy(),
 		newClient: func(cfg Config) (dockerClient, error) {
 			return docker.NewClientWithOpts(docker.WithHost(cfg.Address))
 		},
-		containers: make(map[string]bool),
+		containers:     make(map[string]bool),
+		containerStats: make(map[string]*containerStatsState),
 	}
 }
 
@@ -44,6 +50,13 @@ type Config struct {
 	Timeout              web.Duration `yaml:"timeout"`
 	Address              string       `yaml:"address"`
 	CollectContainerSize bool         `yaml:"collect_container_size"`
+
+	CollectContainerStats       bool         `yaml:"collect_container_stats"`
+	ContainerSelector           string       `yaml:"container_selector"`
+	ContainerLabelSelector      []string     `yaml:"container_label_selector"`
+	ContainerStatsTimeout       web.Duration `yaml:"container_stats_timeout"`
+	ContainerStatsConcurrency   int          `yaml:"container_stats_concurrency"`
+	ContainerStatsMaxContainers int          `yaml:"container_stats_max_containers"`
 }
 
 type (
@@ -58,17 +71,34 @@ type (
 		verNegotiated bool
 
 		containers map[string]bool
+
+		containerSelector matcher.Matcher
+		containerStats    map[string]*containerStatsState
+		statsCapExceeded  bool
 	}
 	dockerClient interface {
 		NegotiateAPIVersion(context.Context)
 		Info(context.Context) (types.Info, error)
 		ImageList(context.Context, types.ImageListOptions) ([]types.ImageSummary, error)
 		ContainerList(context.Context, types.ContainerListOptions) ([]types.Container, error)
+		ContainerStatsOneShot(context.Context, string) (types.ContainerStats, error)
 		Close() error
 	}
 )
 
 func (d *Docker) Init() bool {
+	if err := d.validateConfig(); err != nil {
+		d.Errorf("config validation: %v", err)
+		return false
+	}
+
+	sr, err := d.initContainerSelector()
+	if err != nil {
+		d.Errorf("init container selector: %v", err)
+		return false
+	}
+	d.containerSelector = sr
+
 	return true
 }
 
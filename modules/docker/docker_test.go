@@ -3,9 +3,14 @@
 package docker
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +32,28 @@ func TestDocker_Init(t *testing.T) {
 				Address: "",
 			},
 		},
+		"container stats with bad container selector": {
+			wantFail: true,
+			config: Config{
+				CollectContainerStats:     true,
+				ContainerStatsConcurrency: 1,
+				ContainerSelector:         "~ [",
+			},
+		},
+		"container stats with bad label selector": {
+			wantFail: true,
+			config: Config{
+				CollectContainerStats:     true,
+				ContainerStatsConcurrency: 1,
+				ContainerLabelSelector:    []string{"=value"},
+			},
+		},
+		"container stats with zero concurrency": {
+			wantFail: true,
+			config: Config{
+				CollectContainerStats: true,
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -682,6 +709,115 @@ func TestDocker_Collect(t *testing.T) {
 	}
 }
 
+func TestDocker_Collect_ContainerStats(t *testing.T) {
+	tests := map[string]struct {
+		prepare   func(d *Docker)
+		wantStats []string
+	}{
+		"name selector": {
+			prepare: func(d *Docker) {
+				d.ContainerSelector = "container2 container3"
+			},
+			wantStats: []string{"container2", "container3"},
+		},
+		"label selector": {
+			prepare: func(d *Docker) {
+				d.ContainerLabelSelector = []string{"monitor=yes", "tier"}
+			},
+			wantStats: []string{"container2"},
+		},
+		"all running containers": {
+			prepare:   func(d *Docker) {},
+			wantStats: []string{"container2", "container3", "container5"},
+		},
+		"max containers exceeded": {
+			prepare: func(d *Docker) {
+				d.ContainerStatsMaxContainers = 2
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := New()
+			d.CollectContainerStats = true
+			d.ContainerStatsConcurrency = 2
+			test.prepare(d)
+			m := &mockClient{statsDelay: time.Millisecond * 10}
+			d.newClient = prepareNewClientFunc(m)
+			require.True(t, d.Init())
+
+			mx1 := d.Collect()
+			mx2 := d.Collect()
+
+			assert.Equal(t, 2*len(test.wantStats), m.statsCalls)
+			assert.LessOrEqual(t, m.statsMaxInFlight, d.ContainerStatsConcurrency)
+
+			for _, name := range []string{"container2", "container3", "container5"} {
+				px := "container_" + name + "_"
+				if !contains(test.wantStats, name) {
+					assert.NotContains(t, mx2, px+"mem_usage")
+					assert.False(t, d.Charts().Has(px+"cpu_usage"))
+					continue
+				}
+
+				// the CPU usage is calculated against the previous sample
+				assert.NotContains(t, mx1, px+"cpu_usage")
+				// (1e9 / 1e10) * 2 CPUs * 100
+				assert.Equal(t, int64(20*precision), mx2[px+"cpu_usage"])
+				assert.Equal(t, int64(90<<20), mx2[px+"mem_usage"])
+				assert.Equal(t, int64(1<<30), mx2[px+"mem_limit"])
+				assert.Equal(t, int64(2010), mx2[px+"net_rx_bytes"])
+				assert.Equal(t, int64(1005), mx2[px+"net_tx_bytes"])
+				assert.Equal(t, int64(8192), mx2[px+"blkio_read_bytes"])
+				assert.Equal(t, int64(16384), mx2[px+"blkio_write_bytes"])
+
+				for _, id := range []string{"cpu_usage", "mem_usage", "net_io", "blkio"} {
+					chart := d.Charts().Get(px + id)
+					require.NotNilf(t, chart, "chart '%s'", px+id)
+					assert.False(t, chart.Obsolete)
+				}
+			}
+		})
+	}
+}
+
+func TestDocker_Collect_ContainerStatsStoppedContainer(t *testing.T) {
+	d := New()
+	d.CollectContainerStats = true
+	d.ContainerSelector = "container2 container3"
+	m := &mockClient{}
+	d.newClient = prepareNewClientFunc(m)
+	require.True(t, d.Init())
+
+	require.Contains(t, d.Collect(), "container_container3_mem_usage")
+
+	m.exited = map[string]bool{"container3": true}
+	mx := d.Collect()
+
+	assert.Contains(t, mx, "container_container2_mem_usage")
+	assert.NotContains(t, mx, "container_container3_mem_usage")
+	assert.Equal(t, int64(1), mx["container_container3_state_exited"])
+	for _, id := range []string{"cpu_usage", "mem_usage", "net_io", "blkio"} {
+		assert.True(t, d.Charts().Get("container_container3_"+id).Obsolete)
+		assert.False(t, d.Charts().Get("container_container2_"+id).Obsolete)
+	}
+	assert.False(t, d.Charts().Get("container_container3_state").Obsolete)
+
+	// started again
+	m.exited = nil
+	assert.Contains(t, d.Collect(), "container_container3_mem_usage")
+}
+
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
 func prepareCaseSuccess() *Docker {
 	d := New()
 	d.CollectContainerSize = true
@@ -733,6 +869,14 @@ type mockClient struct {
 	errOnContainerList        bool
 	negotiateAPIVersionCalled bool
 	closeCalled               bool
+
+	exited           map[string]bool
+	statsDelay       time.Duration
+	mu               sync.Mutex
+	statsCalls       int
+	statsInFlight    int
+	statsMaxInFlight int
+	statsSamples     map[string]uint64
 }
 
 func (m *mockClient) Info(_ context.Context) (types.Info, error) {
@@ -798,9 +942,63 @@ func (m *mockClient) ContainerList(_ context.Context, opts types.ContainerListOp
 		}
 	}
 
+	for i, c := range containers {
+		containers[i].ID = c.Names[0] + "-id"
+		if m.exited[c.Names[0]] {
+			containers[i].State = "exited"
+		}
+		if c.Names[0] == "container2" {
+			containers[i].Labels = map[string]string{"monitor": "yes", "tier": "web"}
+		}
+	}
+
 	return containers, nil
 }
 
+func (m *mockClient) ContainerStatsOneShot(_ context.Context, id string) (types.ContainerStats, error) {
+	m.mu.Lock()
+	m.statsCalls++
+	m.statsInFlight++
+	if m.statsInFlight > m.statsMaxInFlight {
+		m.statsMaxInFlight = m.statsInFlight
+	}
+	if m.statsSamples == nil {
+		m.statsSamples = make(map[string]uint64)
+	}
+	m.statsSamples[id]++
+	n := m.statsSamples[id]
+	m.mu.Unlock()
+
+	time.Sleep(m.statsDelay)
+
+	m.mu.Lock()
+	m.statsInFlight--
+	m.mu.Unlock()
+
+	var st types.StatsJSON
+	st.ID = id
+	st.CPUStats.CPUUsage.TotalUsage = 1e9 * n
+	st.CPUStats.SystemUsage = 1e10 * n
+	st.CPUStats.OnlineCPUs = 2
+	st.MemoryStats.Usage = 100 << 20
+	st.MemoryStats.Limit = 1 << 30
+	st.MemoryStats.Stats = map[string]uint64{"inactive_file": 10 << 20}
+	st.Networks = map[string]types.NetworkStats{
+		"eth0": {RxBytes: 1000 * n, TxBytes: 500 * n},
+		"eth1": {RxBytes: 10, TxBytes: 5},
+	}
+	st.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{
+		{Op: "read", Value: 4096 * n},
+		{Op: "write", Value: 8192 * n},
+	}
+
+	bs, err := json.Marshal(st)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
+	return types.ContainerStats{Body: io.NopCloser(bytes.NewReader(bs)), OSType: "linux"}, nil
+}
+
 func (m *mockClient) ImageList(_ context.Context, _ types.ImageListOptions) ([]types.ImageSummary, error) {
 	if m.errOnImageList {
 		return nil, errors.New("mockClient.ImageList() error")
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/pkg/matcher"
+)
+
+func (d *Docker) validateConfig() error {
+	if !d.CollectContainerStats {
+		return nil
+	}
+	if d.ContainerStatsConcurrency <= 0 {
+		return errors.New("'container_stats_concurrency' must be positive")
+	}
+	for _, v := range d.ContainerLabelSelector {
+		if strings.HasPrefix(v, "=") || v == "" {
+			return fmt.Errorf("bad 'container_label_selector' entry '%s' (expected 'key' or 'key=value')", v)
+		}
+	}
+	return nil
+}
+
+func (d *Docker) initContainerSelector() (matcher.Matcher, error) {
+	if d.ContainerSelector == "" {
+		return matcher.TRUE(), nil
+	}
+	return matcher.NewSimplePatternsMatcher(d.ContainerSelector)
+}
@@ -2,7 +2,11 @@
 
 package docker_engine
 
-import "github.com/netdata/go.d.plugin/agent/module"
+import (
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
 
 type (
 	Charts = module.Charts
@@ -134,3 +138,74 @@ var swarmManagerCharts = Charts{
 		},
 	},
 }
+
+var diskUsageCharts = Charts{
+	{
+		ID:    "disk_usage_size",
+		Title: "Disk Usage",
+		Units: "bytes",
+		Fam:   "disk usage",
+		Ctx:   "docker_engine.disk_usage_size",
+		Type:  module.Stacked,
+		Dims: Dims{
+			{ID: "disk_usage_images_size", Name: "images"},
+			{ID: "disk_usage_containers_size", Name: "containers"},
+			{ID: "disk_usage_volumes_size", Name: "volumes"},
+			{ID: "disk_usage_build_cache_size", Name: "build_cache"},
+		},
+	},
+	{
+		ID:    "disk_usage_reclaimable",
+		Title: "Reclaimable Disk Space",
+		Units: "bytes",
+		Fam:   "disk usage",
+		Ctx:   "docker_engine.disk_usage_reclaimable",
+		Type:  module.Stacked,
+		Dims: Dims{
+			{ID: "disk_usage_images_reclaimable", Name: "images"},
+			{ID: "disk_usage_containers_reclaimable", Name: "containers"},
+			{ID: "disk_usage_volumes_reclaimable", Name: "volumes"},
+			{ID: "disk_usage_build_cache_reclaimable", Name: "build_cache"},
+		},
+	},
+	{
+		ID:    "disk_usage_reclaimable_perc",
+		Title: "Reclaimable Disk Space Percentage",
+		Units: "percentage",
+		Fam:   "disk usage",
+		Ctx:   "docker_engine.disk_usage_reclaimable_perc",
+		Dims: Dims{
+			{ID: "disk_usage_images_reclaimable_perc", Name: "images", Div: 1000},
+			{ID: "disk_usage_containers_reclaimable_perc", Name: "containers", Div: 1000},
+			{ID: "disk_usage_volumes_reclaimable_perc", Name: "volumes", Div: 1000},
+			{ID: "disk_usage_build_cache_reclaimable_perc", Name: "build_cache", Div: 1000},
+		},
+	},
+	{
+		ID:    "disk_usage_unused_objects",
+		Title: "Dangling Images and Unused Volumes",
+		Units: "objects",
+		Fam:   "disk usage",
+		Ctx:   "docker_engine.disk_usage_unused_objects",
+		Dims: Dims{
+			{ID: "disk_usage_dangling_images", Name: "dangling_images"},
+			{ID: "disk_usage_unused_volumes", Name: "unused_volumes"},
+		},
+	},
+}
+
+func newDiskUsageCharts(hasBuildCache bool) *Charts {
+	cs := diskUsageCharts.Copy()
+	if hasBuildCache {
+		return cs
+	}
+	for _, chart := range *cs {
+		for _, dim := range chart.Dims {
+			if strings.HasPrefix(dim.ID, "disk_usage_build_cache_") {
+				_ = chart.RemoveDim(dim.ID)
+				break
+			}
+		}
+	}
+	return cs
+}
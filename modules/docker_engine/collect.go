@@ -24,6 +24,11 @@ func (de *DockerEngine) collect() (map[string]int64, error) {
 	}
 
 	mx := de.collectMetrics(pms)
+
+	if de.CollectDiskUsage {
+		mx.DiskUsage = de.collectDiskUsage()
+	}
+
 	return stm.ToMap(mx), nil
 }
 
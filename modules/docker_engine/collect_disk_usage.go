@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package docker_engine
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+)
+
+// collectDiskUsage requests '/system/df' every 'disk_usage_every' collections (it is expensive on the hosts with
+// many layers), the last result is reported in between.
+func (de *DockerEngine) collectDiskUsage() *diskUsage {
+	runs := de.diskUsageRuns
+	de.diskUsageRuns++
+	if de.DiskUsageEvery > 1 && runs%de.DiskUsageEvery != 0 {
+		return de.diskUsage
+	}
+
+	du, err := de.queryDiskUsage()
+	if err != nil {
+		de.Warningf("disk usage: %v", err)
+		de.diskUsage = nil
+		return nil
+	}
+
+	de.diskUsage = calcDiskUsage(du)
+	de.hasDiskUsage = true
+	// the build cache is not reported before API v1.39
+	de.hasBuildCache = de.hasBuildCache || du.BuildCache != nil
+
+	return de.diskUsage
+}
+
+func (de *DockerEngine) queryDiskUsage() (types.DiskUsage, error) {
+	if de.dockerClient == nil {
+		client, err := de.newDockerClient(de.DockerHost)
+		if err != nil {
+			return types.DiskUsage{}, err
+		}
+		de.dockerClient = client
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), de.DiskUsageTimeout.Duration)
+	defer cancel()
+
+	if !de.verNegotiated {
+		de.verNegotiated = true
+		de.dockerClient.NegotiateAPIVersion(ctx)
+	}
+
+	return de.dockerClient.DiskUsage(ctx, types.DiskUsageOptions{})
+}
+
+// calcDiskUsage calculates the sizes the same way 'docker system df' does.
+func calcDiskUsage(du types.DiskUsage) *diskUsage {
+	var mx diskUsage
+
+	// the images share layers, the total is the size of all layers
+	var imagesUsed int64
+	for _, img := range du.Images {
+		if img.Containers > 0 && img.Size != -1 && img.SharedSize != -1 {
+			imagesUsed += img.Size - img.SharedSize
+		}
+		if isDanglingImage(img) {
+			mx.DanglingImages++
+		}
+	}
+	setDiskUsageCategory(&mx.Images, du.LayersSize, du.LayersSize-imagesUsed)
+
+	var containersSize, containersReclaimable int64
+	for _, cntr := range du.Containers {
+		containersSize += cntr.SizeRw
+		if !isActiveContainer(cntr) {
+			containersReclaimable += cntr.SizeRw
+		}
+	}
+	setDiskUsageCategory(&mx.Containers, containersSize, containersReclaimable)
+
+	var volumesSize, volumesReclaimable int64
+	for _, vol := range du.Volumes {
+		if vol.UsageData == nil {
+			continue
+		}
+		if vol.UsageData.RefCount == 0 {
+			mx.UnusedVolumes++
+		}
+		// -1: the size is not available
+		if vol.UsageData.Size == -1 {
+			continue
+		}
+		volumesSize += vol.UsageData.Size
+		if vol.UsageData.RefCount == 0 {
+			volumesReclaimable += vol.UsageData.Size
+		}
+	}
+	setDiskUsageCategory(&mx.Volumes, volumesSize, volumesReclaimable)
+
+	if du.BuildCache != nil {
+		var cacheSize, cacheReclaimable int64
+		for _, bc := range du.BuildCache {
+			if bc.Shared {
+				continue
+			}
+			cacheSize += bc.Size
+			if !bc.InUse {
+				cacheReclaimable += bc.Size
+			}
+		}
+		mx.BuildCache = &diskUsageCategory{}
+		setDiskUsageCategory(mx.BuildCache, cacheSize, cacheReclaimable)
+	}
+
+	return &mx
+}
+
+func setDiskUsageCategory(c *diskUsageCategory, size, reclaimable int64) {
+	if reclaimable < 0 {
+		reclaimable = 0
+	}
+	c.Size = float64(size)
+	c.Reclaimable = float64(reclaimable)
+	if size > 0 {
+		c.ReclaimablePerc = float64(reclaimable) * 100 / float64(size)
+	}
+}
+
+func isDanglingImage(img *types.ImageSummary) bool {
+	return len(img.RepoTags) == 0 || (len(img.RepoTags) == 1 && img.RepoTags[0] == "<none>:<none>")
+}
+
+func isActiveContainer(cntr *types.Container) bool {
+	switch cntr.State {
+	case "running", "paused", "restarting":
+		return true
+	default:
+		return false
+	}
+}
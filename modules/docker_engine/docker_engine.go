@@ -3,6 +3,7 @@
 package docker_engine
 
 import (
+	"context"
 	_ "embed"
 	"errors"
 	"time"
@@ -10,6 +11,8 @@ import (
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
+	"github.com/docker/docker/api/types"
+	docker "github.com/docker/docker/client"
 	"github.com/netdata/go.d.plugin/agent/module"
 )
 
@@ -33,15 +36,26 @@ func New() *DockerEngine {
 				Timeout: web.Duration{Duration: time.Second},
 			},
 		},
+		DockerHost:       docker.DefaultDockerHost,
+		DiskUsageEvery:   10,
+		DiskUsageTimeout: web.Duration{Duration: time.Second * 10},
 	}
 	return &DockerEngine{
 		Config: config,
+		newDockerClient: func(host string) (dockerClient, error) {
+			return docker.NewClientWithOpts(docker.WithHost(host))
+		},
 	}
 }
 
 type (
 	Config struct {
 		web.HTTP `yaml:",inline"`
+
+		CollectDiskUsage bool         `yaml:"collect_disk_usage"`
+		DockerHost       string       `yaml:"docker_host"`
+		DiskUsageEvery   int          `yaml:"disk_usage_every"`
+		DiskUsageTimeout web.Duration `yaml:"disk_usage_timeout"`
 	}
 	DockerEngine struct {
 		module.Base
@@ -50,6 +64,19 @@ type (
 		prom               prometheus.Prometheus
 		isSwarmManager     bool
 		hasContainerStates bool
+
+		newDockerClient func(host string) (dockerClient, error)
+		dockerClient    dockerClient
+		verNegotiated   bool
+		diskUsageRuns   int
+		diskUsage       *diskUsage
+		hasDiskUsage    bool
+		hasBuildCache   bool
+	}
+	dockerClient interface {
+		NegotiateAPIVersion(context.Context)
+		DiskUsage(context.Context, types.DiskUsageOptions) (types.DiskUsage, error)
+		Close() error
 	}
 )
 
@@ -57,6 +84,9 @@ func (de DockerEngine) validateConfig() error {
 	if de.URL == "" {
 		return errors.New("URL is not set")
 	}
+	if de.CollectDiskUsage && de.DockerHost == "" {
+		return errors.New("'docker_host' is not set")
+	}
 	return nil
 }
 
@@ -94,6 +124,12 @@ func (de DockerEngine) Charts() *Charts {
 		}
 	}
 
+	if de.hasDiskUsage {
+		if err := cs.Add(*newDiskUsageCharts(de.hasBuildCache)...); err != nil {
+			de.Warning(err)
+		}
+	}
+
 	if !de.isSwarmManager {
 		return cs
 	}
@@ -117,4 +153,12 @@ func (de *DockerEngine) Collect() map[string]int64 {
 	return mx
 }
 
-func (DockerEngine) Cleanup() {}
+func (de *DockerEngine) Cleanup() {
+	if de.dockerClient == nil {
+		return
+	}
+	if err := de.dockerClient.Close(); err != nil {
+		de.Warningf("error on closing docker client: %v", err)
+	}
+	de.dockerClient = nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
@@ -21,6 +22,8 @@ var (
 	metricsV17050CE, _        = os.ReadFile("testdata/v17.05.0-ce.txt")
 	metricsV18093CE, _        = os.ReadFile("testdata/v18.09.3-ce.txt")
 	metricsV18093CESwarm, _   = os.ReadFile("testdata/v18.09.3-ce-swarm.txt")
+	systemDFV125, _           = os.ReadFile("testdata/system-df-v1.25.json")
+	systemDFV143, _           = os.ReadFile("testdata/system-df-v1.43.json")
 )
 
 func Test_readTestData(t *testing.T) {
@@ -28,6 +31,8 @@ func Test_readTestData(t *testing.T) {
 	assert.NotNil(t, metricsV17050CE)
 	assert.NotNil(t, metricsV18093CE)
 	assert.NotNil(t, metricsV18093CESwarm)
+	assert.NotNil(t, systemDFV125)
+	assert.NotNil(t, systemDFV143)
 }
 
 func TestNew(t *testing.T) {
@@ -253,6 +258,94 @@ func TestDockerEngine_Collect(t *testing.T) {
 	}
 }
 
+func TestDockerEngine_Collect_DiskUsage(t *testing.T) {
+	tests := map[string]struct {
+		apiVersion    string
+		systemDF      []byte
+		wantNumCharts int
+		wantDiskUsage map[string]int64
+	}{
+		"API v1.25 (no build cache)": {
+			apiVersion:    "1.25",
+			systemDF:      systemDFV125,
+			wantNumCharts: len(charts) + len(diskUsageCharts),
+			wantDiskUsage: map[string]int64{
+				"disk_usage_images_size":                 100000,
+				"disk_usage_images_reclaimable":          0,
+				"disk_usage_images_reclaimable_perc":     0,
+				"disk_usage_containers_size":             10,
+				"disk_usage_containers_reclaimable":      0,
+				"disk_usage_containers_reclaimable_perc": 0,
+				"disk_usage_volumes_size":                200,
+				"disk_usage_volumes_reclaimable":         200,
+				"disk_usage_volumes_reclaimable_perc":    100000,
+				"disk_usage_dangling_images":             0,
+				"disk_usage_unused_volumes":              1,
+			},
+		},
+		"API v1.43": {
+			apiVersion:    "1.43",
+			systemDF:      systemDFV143,
+			wantNumCharts: len(charts) + len(diskUsageCharts),
+			wantDiskUsage: map[string]int64{
+				"disk_usage_images_size":                  1000000,
+				"disk_usage_images_reclaimable":           500000,
+				"disk_usage_images_reclaimable_perc":      50000,
+				"disk_usage_containers_size":              6000,
+				"disk_usage_containers_reclaimable":       3000,
+				"disk_usage_containers_reclaimable_perc":  50000,
+				"disk_usage_volumes_size":                 15000,
+				"disk_usage_volumes_reclaimable":          5000,
+				"disk_usage_volumes_reclaimable_perc":     33333,
+				"disk_usage_build_cache_size":             5000,
+				"disk_usage_build_cache_reclaimable":      4000,
+				"disk_usage_build_cache_reclaimable_perc": 80000,
+				"disk_usage_dangling_images":              1,
+				"disk_usage_unused_volumes":               2,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dockerEngine, srv, dfCalls := prepareClientServerDiskUsage(t, test.apiVersion, test.systemDF)
+			defer srv.Close()
+			dockerEngine.DiskUsageEvery = 3
+
+			var collected map[string]int64
+			for i := 0; i < 7; i++ {
+				collected = dockerEngine.Collect()
+			}
+
+			require.NotNil(t, collected)
+			assert.Equal(t, 3, *dfCalls)
+
+			diskUsage := make(map[string]int64)
+			for k, v := range collected {
+				if strings.HasPrefix(k, "disk_usage_") {
+					diskUsage[k] = v
+				}
+			}
+			assert.Equal(t, test.wantDiskUsage, diskUsage)
+			assert.Equal(t, int64(12), collected["container_states_running"])
+
+			assert.Len(t, *dockerEngine.Charts(), test.wantNumCharts)
+			ensureCollectedHasAllChartsDimsVarsIDs(t, dockerEngine, collected)
+		})
+	}
+}
+
+func TestDockerEngine_Collect_DiskUsageError(t *testing.T) {
+	dockerEngine, srv, _ := prepareClientServerDiskUsage(t, "1.43", []byte("hello and\n goodbye"))
+	defer srv.Close()
+
+	collected := dockerEngine.Collect()
+
+	require.NotNil(t, collected)
+	assert.NotContains(t, collected, "disk_usage_images_size")
+	assert.Len(t, *dockerEngine.Charts(), len(charts))
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, dockerEngine *DockerEngine, collected map[string]int64) {
 	t.Helper()
 	for _, chart := range *dockerEngine.Charts() {
@@ -309,6 +402,35 @@ func prepareClientServerV18093CESwarm(t *testing.T) (*DockerEngine, *httptest.Se
 	return dockerEngine, srv
 }
 
+func prepareClientServerDiskUsage(t *testing.T, apiVersion string, systemDF []byte) (*DockerEngine, *httptest.Server, *int) {
+	t.Helper()
+	var dfCalls int
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/metrics":
+				_, _ = w.Write(metricsV18093CE)
+			case strings.HasSuffix(r.URL.Path, "/_ping"):
+				w.Header().Set("API-Version", apiVersion)
+				_, _ = w.Write([]byte("OK"))
+			case r.URL.Path == "/v"+apiVersion+"/system/df":
+				dfCalls++
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(systemDF)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+	dockerEngine := New()
+	dockerEngine.URL = srv.URL + "/metrics"
+	dockerEngine.CollectDiskUsage = true
+	dockerEngine.DockerHost = "tcp://" + srv.Listener.Addr().String()
+	require.True(t, dockerEngine.Init())
+
+	return dockerEngine, srv, &dfCalls
+}
+
 func prepareClientServerNonDockerEngine(t *testing.T) (*DockerEngine, *httptest.Server) {
 	t.Helper()
 	srv := httptest.NewServer(http.HandlerFunc(
@@ -29,6 +29,7 @@ type metrics struct {
 		Failed float64 `stm:"failed"`
 	} `stm:"health_checks"`
 	SwarmManager *swarmManager `stm:"swarm_manager"`
+	DiskUsage    *diskUsage    `stm:"disk_usage"`
 }
 
 type containerStates struct {
@@ -72,3 +73,18 @@ type swarmManager struct {
 		} `stm:"state"`
 	} `stm:"tasks"`
 }
+
+type diskUsage struct {
+	Images         diskUsageCategory  `stm:"images"`
+	Containers     diskUsageCategory  `stm:"containers"`
+	Volumes        diskUsageCategory  `stm:"volumes"`
+	BuildCache     *diskUsageCategory `stm:"build_cache"`
+	DanglingImages float64            `stm:"dangling_images"`
+	UnusedVolumes  float64            `stm:"unused_volumes"`
+}
+
+type diskUsageCategory struct {
+	Size            float64 `stm:"size"`
+	Reclaimable     float64 `stm:"reclaimable"`
+	ReclaimablePerc float64 `stm:"reclaimable_perc,1000,1"`
+}
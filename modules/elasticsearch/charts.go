@@ -59,6 +59,12 @@ const (
 	prioNodeIndexShardsCount
 	prioNodeIndexDocsCount
 	prioNodeIndexStoreSize
+
+	prioIndexDocsCount
+	prioIndexStoreSize
+	prioIndexOperations
+	prioIndexRefreshTime
+	prioIndexMergeTime
 )
 
 var nodeChartsTmpl = module.Charts{
@@ -756,6 +762,73 @@ var (
 	}
 )
 
+var indexChartsTmpl = module.Charts{
+	indexDocsCountChartTmpl.Copy(),
+	indexStoreSizeChartTmpl.Copy(),
+	indexOperationsChartTmpl.Copy(),
+	indexRefreshTimeChartTmpl.Copy(),
+	indexMergeTimeChartTmpl.Copy(),
+}
+
+var (
+	indexDocsCountChartTmpl = module.Chart{
+		ID:       "index_%s_cluster_%s_docs_count",
+		Title:    "Index Docs Count",
+		Units:    "docs",
+		Fam:      "indices",
+		Ctx:      "elasticsearch.index_docs_count",
+		Priority: prioIndexDocsCount,
+		Dims: module.Dims{
+			{ID: "index_%s_docs_count", Name: "docs"},
+		},
+	}
+	indexStoreSizeChartTmpl = module.Chart{
+		ID:       "index_%s_cluster_%s_store_size",
+		Title:    "Index Store Size",
+		Units:    "bytes",
+		Fam:      "indices",
+		Ctx:      "elasticsearch.index_store_size",
+		Priority: prioIndexStoreSize,
+		Dims: module.Dims{
+			{ID: "index_%s_store_size_in_bytes", Name: "store_size"},
+		},
+	}
+	indexOperationsChartTmpl = module.Chart{
+		ID:       "index_%s_cluster_%s_operations",
+		Title:    "Index Operations",
+		Units:    "operations/s",
+		Fam:      "indices",
+		Ctx:      "elasticsearch.index_operations",
+		Priority: prioIndexOperations,
+		Dims: module.Dims{
+			{ID: "index_%s_indexing_rate", Name: "indexing", Div: precision},
+			{ID: "index_%s_search_query_rate", Name: "search_query", Div: precision},
+		},
+	}
+	indexRefreshTimeChartTmpl = module.Chart{
+		ID:       "index_%s_cluster_%s_refresh_time",
+		Title:    "Index Refresh Time",
+		Units:    "milliseconds/s",
+		Fam:      "indices",
+		Ctx:      "elasticsearch.index_refresh_time",
+		Priority: prioIndexRefreshTime,
+		Dims: module.Dims{
+			{ID: "index_%s_refresh_time", Name: "refresh", Div: precision},
+		},
+	}
+	indexMergeTimeChartTmpl = module.Chart{
+		ID:       "index_%s_cluster_%s_merge_time",
+		Title:    "Index Merge Time",
+		Units:    "milliseconds/s",
+		Fam:      "indices",
+		Ctx:      "elasticsearch.index_merge_time",
+		Priority: prioIndexMergeTime,
+		Dims: module.Dims{
+			{ID: "index_%s_merge_time", Name: "merge", Div: precision},
+		},
+	}
+)
+
 func (es *Elasticsearch) addClusterStatsCharts() {
 	charts := clusterStatsChartsTmpl.Copy()
 
@@ -786,18 +859,18 @@ func (es *Elasticsearch) addClusterHealthCharts() {
 	}
 }
 
-func (es *Elasticsearch) addNodeCharts(nodeID string, node *esNodeStats) {
+func (es *Elasticsearch) addNodeCharts(name string, node *esNodeStats) {
 	charts := nodeChartsTmpl.Copy()
 
 	for _, chart := range *charts {
-		chart.ID = fmt.Sprintf(chart.ID, nodeID, es.clusterName)
+		chart.ID = fmt.Sprintf(chart.ID, name, es.clusterName)
 		chart.Labels = []module.Label{
 			{Key: "cluster_name", Value: es.clusterName},
 			{Key: "node_name", Value: node.Name},
 			{Key: "host", Value: node.Host},
 		}
 		for _, dim := range chart.Dims {
-			dim.ID = fmt.Sprintf(dim.ID, nodeID)
+			dim.ID = fmt.Sprintf(dim.ID, name)
 		}
 	}
 
@@ -806,8 +879,8 @@ func (es *Elasticsearch) addNodeCharts(nodeID string, node *esNodeStats) {
 	}
 }
 
-func (es *Elasticsearch) removeNodeCharts(nodeID string) {
-	px := fmt.Sprintf("node_%s_cluster_%s_", nodeID, es.clusterName)
+func (es *Elasticsearch) removeNodeCharts(name string) {
+	px := fmt.Sprintf("node_%s_cluster_%s_", name, es.clusterName)
 	es.removeCharts(px)
 }
 
@@ -835,6 +908,30 @@ func (es *Elasticsearch) removeIndexCharts(index string) {
 	es.removeCharts(px)
 }
 
+func (es *Elasticsearch) addIndexStatsCharts(index string) {
+	charts := indexChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, index, es.clusterName)
+		chart.Labels = []module.Label{
+			{Key: "cluster_name", Value: es.clusterName},
+			{Key: "index", Value: index},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, index)
+		}
+	}
+
+	if err := es.Charts().Add(*charts...); err != nil {
+		es.Warning(err)
+	}
+}
+
+func (es *Elasticsearch) removeIndexStatsCharts(index string) {
+	px := fmt.Sprintf("index_%s_cluster_%s_", index, es.clusterName)
+	es.removeCharts(px)
+}
+
 func (es *Elasticsearch) removeCharts(prefix string) {
 	for _, chart := range *es.Charts() {
 		if strings.HasPrefix(chart.ID, prefix) {
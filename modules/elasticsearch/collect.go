@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/stm"
 	"github.com/netdata/go.d.plugin/pkg/web"
@@ -34,8 +35,11 @@ func (es *Elasticsearch) collect() (map[string]int64, error) {
 		es.clusterName = name
 	}
 
-	ms := es.scrapeElasticsearch()
-	if ms.empty() {
+	now := time.Now()
+
+	ms := es.scrapeElasticsearch(now)
+	// the per index metrics of the last '_stats' pull are reported until the next one
+	if ms.empty() && len(es.indicesStats) == 0 {
 		return nil, nil
 	}
 
@@ -45,6 +49,7 @@ func (es *Elasticsearch) collect() (map[string]int64, error) {
 	es.collectClusterHealth(mx, ms)
 	es.collectClusterStats(mx, ms)
 	es.collectLocalIndicesStats(mx, ms)
+	es.collectIndicesStats(mx, ms, now)
 
 	return mx, nil
 }
@@ -57,20 +62,30 @@ func (es *Elasticsearch) collectNodesStats(mx map[string]int64, ms *esMetrics) {
 	seen := make(map[string]bool)
 
 	for nodeID, node := range ms.NodesStats.Nodes {
-		seen[nodeID] = true
+		// the node ID changes if the node data path is wiped (e.g. a container restart), the name is persistent
+		name := node.Name
+		if name == "" || seen[name] {
+			name = nodeID
+		}
+		seen[name] = true
 
-		if !es.nodes[nodeID] {
-			es.nodes[nodeID] = true
-			es.addNodeCharts(nodeID, node)
+		if _, ok := es.nodes[name]; !ok {
+			es.addNodeCharts(name, node)
 		}
+		es.nodes[name] = 0
 
-		merge(mx, stm.ToMap(node), "node_"+nodeID)
+		merge(mx, stm.ToMap(node), "node_"+name)
 	}
 
-	for nodeID := range es.nodes {
-		if !seen[nodeID] {
-			delete(es.nodes, nodeID)
-			es.removeNodeCharts(nodeID)
+	for name := range es.nodes {
+		if seen[name] {
+			continue
+		}
+		// a node that is restarting is absent from the response for some time
+		if es.nodes[name]++; es.nodes[name] > es.NodeAbsentCycles {
+			es.Debugf("node '%s' is absent for more than %d collections: removing charts", name, es.NodeAbsentCycles)
+			delete(es.nodes, name)
+			es.removeNodeCharts(name)
 		}
 	}
 }
@@ -132,7 +147,7 @@ func (es *Elasticsearch) collectLocalIndicesStats(mx map[string]int64, ms *esMet
 	}
 }
 
-func (es *Elasticsearch) scrapeElasticsearch() *esMetrics {
+func (es *Elasticsearch) scrapeElasticsearch(now time.Time) *esMetrics {
 	ms := &esMetrics{}
 	wg := &sync.WaitGroup{}
 
@@ -152,6 +167,10 @@ func (es *Elasticsearch) scrapeElasticsearch() *esMetrics {
 		wg.Add(1)
 		go func() { defer wg.Done(); es.scrapeLocalIndicesStats(ms) }()
 	}
+	if es.DoIndices && es.indicesStatsDue(now) {
+		wg.Add(1)
+		go func() { defer wg.Done(); es.scrapeIndicesStats(ms) }()
+	}
 	wg.Wait()
 
 	return ms
@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package elasticsearch
+
+import (
+	"sort"
+	"time"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+)
+
+const (
+	urlPathIndicesStatsAll = "/_stats/docs,store,indexing,search,refresh,merge"
+
+	precision = 1000 // rates multiplier and dimensions divisor
+
+	// otherIndex aggregates the indices that are over the 'max_indices' limit.
+	// Index names can not start with '_', so it doesn't clash with a real index.
+	otherIndex = "_other"
+)
+
+type (
+	// indexStats holds the values pulled from '_stats' and the per second rates calculated from the counters.
+	indexStats struct {
+		docsCount        int64
+		storeSizeInBytes int64
+		indexingRate     int64
+		searchQueryRate  int64
+		refreshTime      int64
+		mergeTime        int64
+	}
+	indexCounters struct {
+		indexTotal        int64
+		queryTotal        int64
+		refreshTimeMillis int64
+		mergeTimeMillis   int64
+	}
+)
+
+func (s *indexStats) add(v indexStats) {
+	s.docsCount += v.docsCount
+	s.storeSizeInBytes += v.storeSizeInBytes
+	s.indexingRate += v.indexingRate
+	s.searchQueryRate += v.searchQueryRate
+	s.refreshTime += v.refreshTime
+	s.mergeTime += v.mergeTime
+}
+
+// indicesStatsDue reports whether '_stats' should be pulled on this collection,
+// 'indices_update_every' allows to pull it less often than update_every on clusters with many indices.
+func (es *Elasticsearch) indicesStatsDue(now time.Time) bool {
+	return es.indicesLastPull.IsZero() || now.Sub(es.indicesLastPull) >= es.IndicesUpdateEvery.Duration
+}
+
+func (es *Elasticsearch) scrapeIndicesStats(ms *esMetrics) {
+	req, _ := web.NewHTTPRequest(es.Request)
+	req.URL.Path = urlPathIndicesStatsAll
+	req.URL.RawQuery = "level=indices"
+
+	var stats esIndicesStats
+	if err := es.doOKDecode(req, &stats); err != nil {
+		es.Warning(err)
+		return
+	}
+
+	ms.IndicesStats = &stats
+}
+
+// collectIndicesStats writes the per index metrics. Between the '_stats' pulls the last values are reused.
+func (es *Elasticsearch) collectIndicesStats(mx map[string]int64, ms *esMetrics, now time.Time) {
+	if !es.DoIndices {
+		return
+	}
+
+	if ms.hasIndicesStats() {
+		es.updateIndicesStats(ms.IndicesStats, now)
+	}
+
+	for index, v := range es.indicesStats {
+		px := "index_" + index + "_"
+		mx[px+"docs_count"] = v.docsCount
+		mx[px+"store_size_in_bytes"] = v.storeSizeInBytes
+		mx[px+"indexing_rate"] = v.indexingRate
+		mx[px+"search_query_rate"] = v.searchQueryRate
+		mx[px+"refresh_time"] = v.refreshTime
+		mx[px+"merge_time"] = v.mergeTime
+	}
+}
+
+func (es *Elasticsearch) updateIndicesStats(stats *esIndicesStats, now time.Time) {
+	var elapsed time.Duration
+	if !es.indicesLastPull.IsZero() {
+		elapsed = now.Sub(es.indicesLastPull)
+	}
+	es.indicesLastPull = now
+
+	all := make(map[string]indexStats)
+	counters := make(map[string]indexCounters)
+
+	for index, v := range stats.Indices {
+		if es.indicesSelector != nil && !es.indicesSelector.MatchString(index) {
+			continue
+		}
+
+		cur := indexCounters{
+			indexTotal:        v.Total.Indexing.IndexTotal,
+			queryTotal:        v.Total.Search.QueryTotal,
+			refreshTimeMillis: v.Total.Refresh.TotalTimeInMillis,
+			mergeTimeMillis:   v.Total.Merges.TotalTimeInMillis,
+		}
+		counters[index] = cur
+
+		s := indexStats{
+			docsCount:        v.Primaries.Docs.Count,
+			storeSizeInBytes: v.Total.Store.SizeInBytes,
+		}
+		if prev, ok := es.indicesCounters[index]; ok {
+			s.indexingRate = perSecond(cur.indexTotal-prev.indexTotal, elapsed)
+			s.searchQueryRate = perSecond(cur.queryTotal-prev.queryTotal, elapsed)
+			s.refreshTime = perSecond(cur.refreshTimeMillis-prev.refreshTimeMillis, elapsed)
+			s.mergeTime = perSecond(cur.mergeTimeMillis-prev.mergeTimeMillis, elapsed)
+		}
+		all[index] = s
+	}
+	es.indicesCounters = counters
+
+	charted := es.selectChartedIndices(all)
+
+	es.indicesStats = make(map[string]indexStats)
+	var other indexStats
+	var hasOther bool
+
+	for index, s := range all {
+		if charted[index] {
+			es.indicesStats[index] = s
+		} else {
+			other.add(s)
+			hasOther = true
+		}
+	}
+	if hasOther {
+		es.indicesStats[otherIndex] = other
+	}
+
+	for index := range es.indicesStats {
+		if !es.chartedIndices[index] {
+			es.chartedIndices[index] = true
+			es.addIndexStatsCharts(index)
+		}
+	}
+	for index := range es.chartedIndices {
+		if _, ok := es.indicesStats[index]; !ok {
+			delete(es.chartedIndices, index)
+			es.removeIndexStatsCharts(index)
+		}
+	}
+}
+
+// selectChartedIndices returns up to 'max_indices' indices to chart individually.
+// Already charted indices keep their slot, the free slots are taken by the largest indices.
+func (es *Elasticsearch) selectChartedIndices(all map[string]indexStats) map[string]bool {
+	charted := make(map[string]bool)
+
+	for index := range es.chartedIndices {
+		if _, ok := all[index]; ok && len(charted) < es.MaxIndices {
+			charted[index] = true
+		}
+	}
+
+	var candidates []string
+	for index := range all {
+		if !charted[index] {
+			candidates = append(candidates, index)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		si, sj := all[candidates[i]].storeSizeInBytes, all[candidates[j]].storeSizeInBytes
+		if si == sj {
+			return candidates[i] < candidates[j]
+		}
+		return si > sj
+	})
+
+	for _, index := range candidates {
+		if len(charted) >= es.MaxIndices {
+			break
+		}
+		charted[index] = true
+	}
+
+	return charted
+}
+
+// perSecond returns the counter delta per second multiplied by precision, a negative delta means the counter reset.
+func perSecond(delta int64, elapsed time.Duration) int64 {
+	if delta <= 0 || elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(delta) * precision / elapsed.Seconds())
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
 	"github.com/netdata/go.d.plugin/agent/module"
@@ -37,19 +38,25 @@ func New() *Elasticsearch {
 					Timeout: web.Duration{Duration: time.Second * 5},
 				},
 			},
-			ClusterMode: false,
+			ClusterMode:      false,
+			NodeAbsentCycles: 10,
 
 			DoNodeStats:     true,
 			DoClusterStats:  true,
 			DoClusterHealth: true,
 			DoIndicesStats:  false,
+			DoIndices:       false,
+			IndicesSelector: "!.* *",
+			MaxIndices:      50,
 		},
 
 		charts:                     &module.Charts{},
 		addClusterHealthChartsOnce: &sync.Once{},
 		addClusterStatsChartsOnce:  &sync.Once{},
-		nodes:                      make(map[string]bool),
+		nodes:                      make(map[string]int),
 		indices:                    make(map[string]bool),
+		chartedIndices:             make(map[string]bool),
+		indicesCounters:            make(map[string]indexCounters),
 	}
 }
 
@@ -60,6 +67,13 @@ type Config struct {
 	DoClusterHealth bool `yaml:"collect_cluster_health"`
 	DoClusterStats  bool `yaml:"collect_cluster_stats"`
 	DoIndicesStats  bool `yaml:"collect_indices_stats"`
+
+	NodeAbsentCycles int `yaml:"node_absent_cycles"`
+
+	DoIndices          bool         `yaml:"collect_indices"`
+	IndicesSelector    string       `yaml:"indices_selector"`
+	IndicesUpdateEvery web.Duration `yaml:"indices_update_every"`
+	MaxIndices         int          `yaml:"max_indices"`
 }
 
 type Elasticsearch struct {
@@ -74,8 +88,14 @@ type Elasticsearch struct {
 	addClusterHealthChartsOnce *sync.Once
 	addClusterStatsChartsOnce  *sync.Once
 
-	nodes   map[string]bool
+	nodes   map[string]int // node name -> consecutive collections the node is absent
 	indices map[string]bool
+
+	indicesSelector matcher.Matcher
+	indicesLastPull time.Time
+	indicesCounters map[string]indexCounters
+	indicesStats    map[string]indexStats
+	chartedIndices  map[string]bool
 }
 
 func (es *Elasticsearch) Init() bool {
@@ -92,6 +112,13 @@ func (es *Elasticsearch) Init() bool {
 	}
 	es.httpClient = httpClient
 
+	sr, err := es.initIndicesSelector()
+	if err != nil {
+		es.Errorf("init indices selector: %v", err)
+		return false
+	}
+	es.indicesSelector = sr
+
 	return true
 }
 
@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 	"github.com/netdata/go.d.plugin/pkg/web"
@@ -22,6 +24,12 @@ var (
 	v842ClusterStats, _    = os.ReadFile("testdata/v8.4.2/cluster_stats.json")
 	v842CatIndicesStats, _ = os.ReadFile("testdata/v8.4.2/cat_indices_stats.json")
 	v842Info, _            = os.ReadFile("testdata/v8.4.2/info.json")
+
+	v842NodesStatsRestart1, _ = os.ReadFile("testdata/v8.4.2/nodes_stats_restart_1.json")
+	v842NodesStatsRestart2, _ = os.ReadFile("testdata/v8.4.2/nodes_stats_restart_2.json")
+
+	v842IndicesStats, _        = os.ReadFile("testdata/v8.4.2/indices_stats.json")
+	v842IndicesStatsRotated, _ = os.ReadFile("testdata/v8.4.2/indices_stats_rotated.json")
 )
 
 func Test_testDataIsCorrectlyReadAndValid(t *testing.T) {
@@ -32,6 +40,12 @@ func Test_testDataIsCorrectlyReadAndValid(t *testing.T) {
 		"v842ClusterStats":    v842ClusterStats,
 		"v842CatIndicesStats": v842CatIndicesStats,
 		"v842Info":            v842Info,
+
+		"v842NodesStatsRestart1": v842NodesStatsRestart1,
+		"v842NodesStatsRestart2": v842NodesStatsRestart2,
+
+		"v842IndicesStats":        v842IndicesStats,
+		"v842IndicesStatsRotated": v842IndicesStatsRotated,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -67,6 +81,47 @@ func TestElasticsearch_Init(t *testing.T) {
 				DoIndicesStats:  false,
 			},
 		},
+		"only indices": {
+			config: Config{
+				HTTP: web.HTTP{
+					Request: web.Request{URL: "http://127.0.0.1:38001"},
+				},
+				DoIndices:       true,
+				IndicesSelector: "!.* *",
+				MaxIndices:      10,
+			},
+		},
+		"indices with non-positive max_indices": {
+			wantFail: true,
+			config: Config{
+				HTTP: web.HTTP{
+					Request: web.Request{URL: "http://127.0.0.1:38001"},
+				},
+				DoIndices:  true,
+				MaxIndices: 0,
+			},
+		},
+		"indices with bad selector": {
+			wantFail: true,
+			config: Config{
+				HTTP: web.HTTP{
+					Request: web.Request{URL: "http://127.0.0.1:38001"},
+				},
+				DoIndices:       true,
+				IndicesSelector: "[",
+				MaxIndices:      10,
+			},
+		},
+		"negative node_absent_cycles": {
+			wantFail: true,
+			config: Config{
+				HTTP: web.HTTP{
+					Request: web.Request{URL: "http://127.0.0.1:38001"},
+				},
+				DoNodeStats:      true,
+				NodeAbsentCycles: -1,
+			},
+		},
 		"URL not set": {
 			wantFail: true,
 			config: Config{
@@ -162,264 +217,264 @@ func TestElasticsearch_Collect(t *testing.T) {
 			},
 			wantCharts: len(nodeChartsTmpl) * 3,
 			wantCollected: map[string]int64{
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_accounting_tripped":                       0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_fielddata_tripped":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_in_flight_requests_tripped":               0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_model_inference_tripped":                  0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_parent_tripped":                           0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_request_tripped":                          0,
-				"node_Klg1CjgMTouentQcJlRGuA_http_current_open":                                 75,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_fielddata_evictions":                       0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_fielddata_memory_size_in_bytes":            600,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_flush_total":                               35130,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_flush_total_time_in_millis":                22204637,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_indexing_index_current":                    0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_indexing_index_time_in_millis":             1100012973,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_indexing_index_total":                      3667364815,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_refresh_total":                             7720800,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_refresh_total_time_in_millis":              94297737,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_fetch_current":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_fetch_time_in_millis":               21316723,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_fetch_total":                        42642621,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_query_current":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_query_time_in_millis":               51262303,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_query_total":                        166820275,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_count":                            320,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_doc_values_memory_in_bytes":       0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_fixed_bit_set_memory_in_bytes":    1904,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_index_writer_memory_in_bytes":     262022568,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_memory_in_bytes":                  0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_norms_memory_in_bytes":            0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_points_memory_in_bytes":           0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_stored_fields_memory_in_bytes":    0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_term_vectors_memory_in_bytes":     0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_terms_memory_in_bytes":            0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_version_map_memory_in_bytes":      49200018,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_translog_operations":                       352376,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_translog_size_in_bytes":                    447695989,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_translog_uncommitted_operations":           352376,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_translog_uncommitted_size_in_bytes":        447695989,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_direct_count":                     94,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_direct_total_capacity_in_bytes":   4654848,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_direct_used_in_bytes":             4654850,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_mapped_count":                     858,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_mapped_total_capacity_in_bytes":   103114998135,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_mapped_used_in_bytes":             103114998135,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_gc_collectors_old_collection_count":            0,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_gc_collectors_old_collection_time_in_millis":   0,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_gc_collectors_young_collection_count":          78652,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_gc_collectors_young_collection_time_in_millis": 6014274,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_mem_heap_committed_in_bytes":                   7864320000,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_mem_heap_used_in_bytes":                        5059735552,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_mem_heap_used_percent":                         64,
-				"node_Klg1CjgMTouentQcJlRGuA_process_max_file_descriptors":                      1048576,
-				"node_Klg1CjgMTouentQcJlRGuA_process_open_file_descriptors":                     1156,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_analyze_queue":                         0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_analyze_rejected":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_fetch_shard_started_queue":             0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_fetch_shard_started_rejected":          0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_fetch_shard_store_queue":               0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_fetch_shard_store_rejected":            0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_flush_queue":                           0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_flush_rejected":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_force_merge_queue":                     0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_force_merge_rejected":                  0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_generic_queue":                         0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_generic_rejected":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_get_queue":                             0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_get_rejected":                          0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_listener_queue":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_listener_rejected":                     0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_management_queue":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_management_rejected":                   0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_refresh_queue":                         0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_refresh_rejected":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_search_queue":                          0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_search_rejected":                       0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_search_throttled_queue":                0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_search_throttled_rejected":             0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_snapshot_queue":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_snapshot_rejected":                     0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_warmer_queue":                          0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_warmer_rejected":                       0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_write_queue":                           0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_write_rejected":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_transport_rx_count":                                1300324276,
-				"node_Klg1CjgMTouentQcJlRGuA_transport_rx_size_in_bytes":                        1789333458217,
-				"node_Klg1CjgMTouentQcJlRGuA_transport_tx_count":                                1300324275,
-				"node_Klg1CjgMTouentQcJlRGuA_transport_tx_size_in_bytes":                        2927487680282,
-				"node_k_AifYMWQTykjUq3pgE_-w_breakers_accounting_tripped":                       0,
-				"node_k_AifYMWQTykjUq3pgE_-w_breakers_fielddata_tripped":                        0,
-				"node_k_AifYMWQTykjUq3pgE_-w_breakers_in_flight_requests_tripped":               0,
-				"node_k_AifYMWQTykjUq3pgE_-w_breakers_model_inference_tripped":                  0,
-				"node_k_AifYMWQTykjUq3pgE_-w_breakers_parent_tripped":                           0,
-				"node_k_AifYMWQTykjUq3pgE_-w_breakers_request_tripped":                          0,
-				"node_k_AifYMWQTykjUq3pgE_-w_http_current_open":                                 14,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_fielddata_evictions":                       0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_fielddata_memory_size_in_bytes":            0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_flush_total":                               0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_flush_total_time_in_millis":                0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_indexing_index_current":                    0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_indexing_index_time_in_millis":             0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_indexing_index_total":                      0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_refresh_total":                             0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_refresh_total_time_in_millis":              0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_search_fetch_current":                      0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_search_fetch_time_in_millis":               0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_search_fetch_total":                        0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_search_query_current":                      0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_search_query_time_in_millis":               0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_search_query_total":                        0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_count":                            0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_doc_values_memory_in_bytes":       0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_fixed_bit_set_memory_in_bytes":    0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_index_writer_memory_in_bytes":     0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_memory_in_bytes":                  0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_norms_memory_in_bytes":            0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_points_memory_in_bytes":           0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_stored_fields_memory_in_bytes":    0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_term_vectors_memory_in_bytes":     0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_terms_memory_in_bytes":            0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_segments_version_map_memory_in_bytes":      0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_translog_operations":                       0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_translog_size_in_bytes":                    0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_translog_uncommitted_operations":           0,
-				"node_k_AifYMWQTykjUq3pgE_-w_indices_translog_uncommitted_size_in_bytes":        0,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_buffer_pools_direct_count":                     19,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_buffer_pools_direct_total_capacity_in_bytes":   2142214,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_buffer_pools_direct_used_in_bytes":             2142216,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_buffer_pools_mapped_count":                     0,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_buffer_pools_mapped_total_capacity_in_bytes":   0,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_buffer_pools_mapped_used_in_bytes":             0,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_gc_collectors_old_collection_count":            0,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_gc_collectors_old_collection_time_in_millis":   0,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_gc_collectors_young_collection_count":          342994,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_gc_collectors_young_collection_time_in_millis": 768917,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_mem_heap_committed_in_bytes":                   281018368,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_mem_heap_used_in_bytes":                        178362704,
-				"node_k_AifYMWQTykjUq3pgE_-w_jvm_mem_heap_used_percent":                         63,
-				"node_k_AifYMWQTykjUq3pgE_-w_process_max_file_descriptors":                      1048576,
-				"node_k_AifYMWQTykjUq3pgE_-w_process_open_file_descriptors":                     557,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_analyze_queue":                         0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_analyze_rejected":                      0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_fetch_shard_started_queue":             0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_fetch_shard_started_rejected":          0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_fetch_shard_store_queue":               0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_fetch_shard_store_rejected":            0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_flush_queue":                           0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_flush_rejected":                        0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_force_merge_queue":                     0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_force_merge_rejected":                  0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_generic_queue":                         0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_generic_rejected":                      0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_get_queue":                             0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_get_rejected":                          0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_listener_queue":                        0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_listener_rejected":                     0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_management_queue":                      0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_management_rejected":                   0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_refresh_queue":                         0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_refresh_rejected":                      0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_search_queue":                          0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_search_rejected":                       0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_search_throttled_queue":                0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_search_throttled_rejected":             0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_snapshot_queue":                        0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_snapshot_rejected":                     0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_warmer_queue":                          0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_warmer_rejected":                       0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_write_queue":                           0,
-				"node_k_AifYMWQTykjUq3pgE_-w_thread_pool_write_rejected":                        0,
-				"node_k_AifYMWQTykjUq3pgE_-w_transport_rx_count":                                107632996,
-				"node_k_AifYMWQTykjUq3pgE_-w_transport_rx_size_in_bytes":                        180620082152,
-				"node_k_AifYMWQTykjUq3pgE_-w_transport_tx_count":                                107633007,
-				"node_k_AifYMWQTykjUq3pgE_-w_transport_tx_size_in_bytes":                        420999501235,
-				"node_tk_U7GMCRkCG4FoOvusrng_breakers_accounting_tripped":                       0,
-				"node_tk_U7GMCRkCG4FoOvusrng_breakers_fielddata_tripped":                        0,
-				"node_tk_U7GMCRkCG4FoOvusrng_breakers_in_flight_requests_tripped":               0,
-				"node_tk_U7GMCRkCG4FoOvusrng_breakers_model_inference_tripped":                  0,
-				"node_tk_U7GMCRkCG4FoOvusrng_breakers_parent_tripped":                           93,
-				"node_tk_U7GMCRkCG4FoOvusrng_breakers_request_tripped":                          1,
-				"node_tk_U7GMCRkCG4FoOvusrng_http_current_open":                                 84,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_fielddata_evictions":                       0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_fielddata_memory_size_in_bytes":            0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_flush_total":                               67895,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_flush_total_time_in_millis":                81917283,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_indexing_index_current":                    0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_indexing_index_time_in_millis":             1244633519,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_indexing_index_total":                      6550378755,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_refresh_total":                             12359783,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_refresh_total_time_in_millis":              300152615,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_search_fetch_current":                      0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_search_fetch_time_in_millis":               24517851,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_search_fetch_total":                        25105951,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_search_query_current":                      0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_search_query_time_in_millis":               158980385,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_search_query_total":                        157912598,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_count":                            291,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_doc_values_memory_in_bytes":       0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_fixed_bit_set_memory_in_bytes":    55672,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_index_writer_memory_in_bytes":     57432664,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_memory_in_bytes":                  0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_norms_memory_in_bytes":            0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_points_memory_in_bytes":           0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_stored_fields_memory_in_bytes":    0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_term_vectors_memory_in_bytes":     0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_terms_memory_in_bytes":            0,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_segments_version_map_memory_in_bytes":      568,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_translog_operations":                       1449698,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_translog_size_in_bytes":                    1214204014,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_translog_uncommitted_operations":           1449698,
-				"node_tk_U7GMCRkCG4FoOvusrng_indices_translog_uncommitted_size_in_bytes":        1214204014,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_buffer_pools_direct_count":                     90,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_buffer_pools_direct_total_capacity_in_bytes":   4571711,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_buffer_pools_direct_used_in_bytes":             4571713,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_buffer_pools_mapped_count":                     831,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_buffer_pools_mapped_total_capacity_in_bytes":   99844219805,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_buffer_pools_mapped_used_in_bytes":             99844219805,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_gc_collectors_old_collection_count":            1,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_gc_collectors_old_collection_time_in_millis":   796,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_gc_collectors_young_collection_count":          139959,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_gc_collectors_young_collection_time_in_millis": 3581668,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_mem_heap_committed_in_bytes":                   7864320000,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_mem_heap_used_in_bytes":                        1884124192,
-				"node_tk_U7GMCRkCG4FoOvusrng_jvm_mem_heap_used_percent":                         23,
-				"node_tk_U7GMCRkCG4FoOvusrng_process_max_file_descriptors":                      1048576,
-				"node_tk_U7GMCRkCG4FoOvusrng_process_open_file_descriptors":                     1180,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_analyze_queue":                         0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_analyze_rejected":                      0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_fetch_shard_started_queue":             0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_fetch_shard_started_rejected":          0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_fetch_shard_store_queue":               0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_fetch_shard_store_rejected":            0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_flush_queue":                           0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_flush_rejected":                        0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_force_merge_queue":                     0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_force_merge_rejected":                  0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_generic_queue":                         0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_generic_rejected":                      0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_get_queue":                             0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_get_rejected":                          0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_listener_queue":                        0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_listener_rejected":                     0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_management_queue":                      0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_management_rejected":                   0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_refresh_queue":                         0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_refresh_rejected":                      0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_search_queue":                          0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_search_rejected":                       0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_search_throttled_queue":                0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_search_throttled_rejected":             0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_snapshot_queue":                        0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_snapshot_rejected":                     0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_warmer_queue":                          0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_warmer_rejected":                       0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_write_queue":                           0,
-				"node_tk_U7GMCRkCG4FoOvusrng_thread_pool_write_rejected":                        0,
-				"node_tk_U7GMCRkCG4FoOvusrng_transport_rx_count":                                2167879292,
-				"node_tk_U7GMCRkCG4FoOvusrng_transport_rx_size_in_bytes":                        4905919297323,
-				"node_tk_U7GMCRkCG4FoOvusrng_transport_tx_count":                                2167879293,
-				"node_tk_U7GMCRkCG4FoOvusrng_transport_tx_size_in_bytes":                        2964638852652,
+				"node_instance-0000000006_breakers_accounting_tripped":                         0,
+				"node_instance-0000000006_breakers_fielddata_tripped":                          0,
+				"node_instance-0000000006_breakers_in_flight_requests_tripped":                 0,
+				"node_instance-0000000006_breakers_model_inference_tripped":                    0,
+				"node_instance-0000000006_breakers_parent_tripped":                             0,
+				"node_instance-0000000006_breakers_request_tripped":                            0,
+				"node_instance-0000000006_http_current_open":                                   75,
+				"node_instance-0000000006_indices_fielddata_evictions":                         0,
+				"node_instance-0000000006_indices_fielddata_memory_size_in_bytes":              600,
+				"node_instance-0000000006_indices_flush_total":                                 35130,
+				"node_instance-0000000006_indices_flush_total_time_in_millis":                  22204637,
+				"node_instance-0000000006_indices_indexing_index_current":                      0,
+				"node_instance-0000000006_indices_indexing_index_time_in_millis":               1100012973,
+				"node_instance-0000000006_indices_indexing_index_total":                        3667364815,
+				"node_instance-0000000006_indices_refresh_total":                               7720800,
+				"node_instance-0000000006_indices_refresh_total_time_in_millis":                94297737,
+				"node_instance-0000000006_indices_search_fetch_current":                        0,
+				"node_instance-0000000006_indices_search_fetch_time_in_millis":                 21316723,
+				"node_instance-0000000006_indices_search_fetch_total":                          42642621,
+				"node_instance-0000000006_indices_search_query_current":                        0,
+				"node_instance-0000000006_indices_search_query_time_in_millis":                 51262303,
+				"node_instance-0000000006_indices_search_query_total":                          166820275,
+				"node_instance-0000000006_indices_segments_count":                              320,
+				"node_instance-0000000006_indices_segments_doc_values_memory_in_bytes":         0,
+				"node_instance-0000000006_indices_segments_fixed_bit_set_memory_in_bytes":      1904,
+				"node_instance-0000000006_indices_segments_index_writer_memory_in_bytes":       262022568,
+				"node_instance-0000000006_indices_segments_memory_in_bytes":                    0,
+				"node_instance-0000000006_indices_segments_norms_memory_in_bytes":              0,
+				"node_instance-0000000006_indices_segments_points_memory_in_bytes":             0,
+				"node_instance-0000000006_indices_segments_stored_fields_memory_in_bytes":      0,
+				"node_instance-0000000006_indices_segments_term_vectors_memory_in_bytes":       0,
+				"node_instance-0000000006_indices_segments_terms_memory_in_bytes":              0,
+				"node_instance-0000000006_indices_segments_version_map_memory_in_bytes":        49200018,
+				"node_instance-0000000006_indices_translog_operations":                         352376,
+				"node_instance-0000000006_indices_translog_size_in_bytes":                      447695989,
+				"node_instance-0000000006_indices_translog_uncommitted_operations":             352376,
+				"node_instance-0000000006_indices_translog_uncommitted_size_in_bytes":          447695989,
+				"node_instance-0000000006_jvm_buffer_pools_direct_count":                       94,
+				"node_instance-0000000006_jvm_buffer_pools_direct_total_capacity_in_bytes":     4654848,
+				"node_instance-0000000006_jvm_buffer_pools_direct_used_in_bytes":               4654850,
+				"node_instance-0000000006_jvm_buffer_pools_mapped_count":                       858,
+				"node_instance-0000000006_jvm_buffer_pools_mapped_total_capacity_in_bytes":     103114998135,
+				"node_instance-0000000006_jvm_buffer_pools_mapped_used_in_bytes":               103114998135,
+				"node_instance-0000000006_jvm_gc_collectors_old_collection_count":              0,
+				"node_instance-0000000006_jvm_gc_collectors_old_collection_time_in_millis":     0,
+				"node_instance-0000000006_jvm_gc_collectors_young_collection_count":            78652,
+				"node_instance-0000000006_jvm_gc_collectors_young_collection_time_in_millis":   6014274,
+				"node_instance-0000000006_jvm_mem_heap_committed_in_bytes":                     7864320000,
+				"node_instance-0000000006_jvm_mem_heap_used_in_bytes":                          5059735552,
+				"node_instance-0000000006_jvm_mem_heap_used_percent":                           64,
+				"node_instance-0000000006_process_max_file_descriptors":                        1048576,
+				"node_instance-0000000006_process_open_file_descriptors":                       1156,
+				"node_instance-0000000006_thread_pool_analyze_queue":                           0,
+				"node_instance-0000000006_thread_pool_analyze_rejected":                        0,
+				"node_instance-0000000006_thread_pool_fetch_shard_started_queue":               0,
+				"node_instance-0000000006_thread_pool_fetch_shard_started_rejected":            0,
+				"node_instance-0000000006_thread_pool_fetch_shard_store_queue":                 0,
+				"node_instance-0000000006_thread_pool_fetch_shard_store_rejected":              0,
+				"node_instance-0000000006_thread_pool_flush_queue":                             0,
+				"node_instance-0000000006_thread_pool_flush_rejected":                          0,
+				"node_instance-0000000006_thread_pool_force_merge_queue":                       0,
+				"node_instance-0000000006_thread_pool_force_merge_rejected":                    0,
+				"node_instance-0000000006_thread_pool_generic_queue":                           0,
+				"node_instance-0000000006_thread_pool_generic_rejected":                        0,
+				"node_instance-0000000006_thread_pool_get_queue":                               0,
+				"node_instance-0000000006_thread_pool_get_rejected":                            0,
+				"node_instance-0000000006_thread_pool_listener_queue":                          0,
+				"node_instance-0000000006_thread_pool_listener_rejected":                       0,
+				"node_instance-0000000006_thread_pool_management_queue":                        0,
+				"node_instance-0000000006_thread_pool_management_rejected":                     0,
+				"node_instance-0000000006_thread_pool_refresh_queue":                           0,
+				"node_instance-0000000006_thread_pool_refresh_rejected":                        0,
+				"node_instance-0000000006_thread_pool_search_queue":                            0,
+				"node_instance-0000000006_thread_pool_search_rejected":                         0,
+				"node_instance-0000000006_thread_pool_search_throttled_queue":                  0,
+				"node_instance-0000000006_thread_pool_search_throttled_rejected":               0,
+				"node_instance-0000000006_thread_pool_snapshot_queue":                          0,
+				"node_instance-0000000006_thread_pool_snapshot_rejected":                       0,
+				"node_instance-0000000006_thread_pool_warmer_queue":                            0,
+				"node_instance-0000000006_thread_pool_warmer_rejected":                         0,
+				"node_instance-0000000006_thread_pool_write_queue":                             0,
+				"node_instance-0000000006_thread_pool_write_rejected":                          0,
+				"node_instance-0000000006_transport_rx_count":                                  1300324276,
+				"node_instance-0000000006_transport_rx_size_in_bytes":                          1789333458217,
+				"node_instance-0000000006_transport_tx_count":                                  1300324275,
+				"node_instance-0000000006_transport_tx_size_in_bytes":                          2927487680282,
+				"node_tiebreaker-0000000002_breakers_accounting_tripped":                       0,
+				"node_tiebreaker-0000000002_breakers_fielddata_tripped":                        0,
+				"node_tiebreaker-0000000002_breakers_in_flight_requests_tripped":               0,
+				"node_tiebreaker-0000000002_breakers_model_inference_tripped":                  0,
+				"node_tiebreaker-0000000002_breakers_parent_tripped":                           0,
+				"node_tiebreaker-0000000002_breakers_request_tripped":                          0,
+				"node_tiebreaker-0000000002_http_current_open":                                 14,
+				"node_tiebreaker-0000000002_indices_fielddata_evictions":                       0,
+				"node_tiebreaker-0000000002_indices_fielddata_memory_size_in_bytes":            0,
+				"node_tiebreaker-0000000002_indices_flush_total":                               0,
+				"node_tiebreaker-0000000002_indices_flush_total_time_in_millis":                0,
+				"node_tiebreaker-0000000002_indices_indexing_index_current":                    0,
+				"node_tiebreaker-0000000002_indices_indexing_index_time_in_millis":             0,
+				"node_tiebreaker-0000000002_indices_indexing_index_total":                      0,
+				"node_tiebreaker-0000000002_indices_refresh_total":                             0,
+				"node_tiebreaker-0000000002_indices_refresh_total_time_in_millis":              0,
+				"node_tiebreaker-0000000002_indices_search_fetch_current":                      0,
+				"node_tiebreaker-0000000002_indices_search_fetch_time_in_millis":               0,
+				"node_tiebreaker-0000000002_indices_search_fetch_total":                        0,
+				"node_tiebreaker-0000000002_indices_search_query_current":                      0,
+				"node_tiebreaker-0000000002_indices_search_query_time_in_millis":               0,
+				"node_tiebreaker-0000000002_indices_search_query_total":                        0,
+				"node_tiebreaker-0000000002_indices_segments_count":                            0,
+				"node_tiebreaker-0000000002_indices_segments_doc_values_memory_in_bytes":       0,
+				"node_tiebreaker-0000000002_indices_segments_fixed_bit_set_memory_in_bytes":    0,
+				"node_tiebreaker-0000000002_indices_segments_index_writer_memory_in_bytes":     0,
+				"node_tiebreaker-0000000002_indices_segments_memory_in_bytes":                  0,
+				"node_tiebreaker-0000000002_indices_segments_norms_memory_in_bytes":            0,
+				"node_tiebreaker-0000000002_indices_segments_points_memory_in_bytes":           0,
+				"node_tiebreaker-0000000002_indices_segments_stored_fields_memory_in_bytes":    0,
+				"node_tiebreaker-0000000002_indices_segments_term_vectors_memory_in_bytes":     0,
+				"node_tiebreaker-0000000002_indices_segments_terms_memory_in_bytes":            0,
+				"node_tiebreaker-0000000002_indices_segments_version_map_memory_in_bytes":      0,
+				"node_tiebreaker-0000000002_indices_translog_operations":                       0,
+				"node_tiebreaker-0000000002_indices_translog_size_in_bytes":                    0,
+				"node_tiebreaker-0000000002_indices_translog_uncommitted_operations":           0,
+				"node_tiebreaker-0000000002_indices_translog_uncommitted_size_in_bytes":        0,
+				"node_tiebreaker-0000000002_jvm_buffer_pools_direct_count":                     19,
+				"node_tiebreaker-0000000002_jvm_buffer_pools_direct_total_capacity_in_bytes":   2142214,
+				"node_tiebreaker-0000000002_jvm_buffer_pools_direct_used_in_bytes":             2142216,
+				"node_tiebreaker-0000000002_jvm_buffer_pools_mapped_count":                     0,
+				"node_tiebreaker-0000000002_jvm_buffer_pools_mapped_total_capacity_in_bytes":   0,
+				"node_tiebreaker-0000000002_jvm_buffer_pools_mapped_used_in_bytes":             0,
+				"node_tiebreaker-0000000002_jvm_gc_collectors_old_collection_count":            0,
+				"node_tiebreaker-0000000002_jvm_gc_collectors_old_collection_time_in_millis":   0,
+				"node_tiebreaker-0000000002_jvm_gc_collectors_young_collection_count":          342994,
+				"node_tiebreaker-0000000002_jvm_gc_collectors_young_collection_time_in_millis": 768917,
+				"node_tiebreaker-0000000002_jvm_mem_heap_committed_in_bytes":                   281018368,
+				"node_tiebreaker-0000000002_jvm_mem_heap_used_in_bytes":                        178362704,
+				"node_tiebreaker-0000000002_jvm_mem_heap_used_percent":                         63,
+				"node_tiebreaker-0000000002_process_max_file_descriptors":                      1048576,
+				"node_tiebreaker-0000000002_process_open_file_descriptors":                     557,
+				"node_tiebreaker-0000000002_thread_pool_analyze_queue":                         0,
+				"node_tiebreaker-0000000002_thread_pool_analyze_rejected":                      0,
+				"node_tiebreaker-0000000002_thread_pool_fetch_shard_started_queue":             0,
+				"node_tiebreaker-0000000002_thread_pool_fetch_shard_started_rejected":          0,
+				"node_tiebreaker-0000000002_thread_pool_fetch_shard_store_queue":               0,
+				"node_tiebreaker-0000000002_thread_pool_fetch_shard_store_rejected":            0,
+				"node_tiebreaker-0000000002_thread_pool_flush_queue":                           0,
+				"node_tiebreaker-0000000002_thread_pool_flush_rejected":                        0,
+				"node_tiebreaker-0000000002_thread_pool_force_merge_queue":                     0,
+				"node_tiebreaker-0000000002_thread_pool_force_merge_rejected":                  0,
+				"node_tiebreaker-0000000002_thread_pool_generic_queue":                         0,
+				"node_tiebreaker-0000000002_thread_pool_generic_rejected":                      0,
+				"node_tiebreaker-0000000002_thread_pool_get_queue":                             0,
+				"node_tiebreaker-0000000002_thread_pool_get_rejected":                          0,
+				"node_tiebreaker-0000000002_thread_pool_listener_queue":                        0,
+				"node_tiebreaker-0000000002_thread_pool_listener_rejected":                     0,
+				"node_tiebreaker-0000000002_thread_pool_management_queue":                      0,
+				"node_tiebreaker-0000000002_thread_pool_management_rejected":                   0,
+				"node_tiebreaker-0000000002_thread_pool_refresh_queue":                         0,
+				"node_tiebreaker-0000000002_thread_pool_refresh_rejected":                      0,
+				"node_tiebreaker-0000000002_thread_pool_search_queue":                          0,
+				"node_tiebreaker-0000000002_thread_pool_search_rejected":                       0,
+				"node_tiebreaker-0000000002_thread_pool_search_throttled_queue":                0,
+				"node_tiebreaker-0000000002_thread_pool_search_throttled_rejected":             0,
+				"node_tiebreaker-0000000002_thread_pool_snapshot_queue":                        0,
+				"node_tiebreaker-0000000002_thread_pool_snapshot_rejected":                     0,
+				"node_tiebreaker-0000000002_thread_pool_warmer_queue":                          0,
+				"node_tiebreaker-0000000002_thread_pool_warmer_rejected":                       0,
+				"node_tiebreaker-0000000002_thread_pool_write_queue":                           0,
+				"node_tiebreaker-0000000002_thread_pool_write_rejected":                        0,
+				"node_tiebreaker-0000000002_transport_rx_count":                                107632996,
+				"node_tiebreaker-0000000002_transport_rx_size_in_bytes":                        180620082152,
+				"node_tiebreaker-0000000002_transport_tx_count":                                107633007,
+				"node_tiebreaker-0000000002_transport_tx_size_in_bytes":                        420999501235,
+				"node_instance-0000000005_breakers_accounting_tripped":                         0,
+				"node_instance-0000000005_breakers_fielddata_tripped":                          0,
+				"node_instance-0000000005_breakers_in_flight_requests_tripped":                 0,
+				"node_instance-0000000005_breakers_model_inference_tripped":                    0,
+				"node_instance-0000000005_breakers_parent_tripped":                             93,
+				"node_instance-0000000005_breakers_request_tripped":                            1,
+				"node_instance-0000000005_http_current_open":                                   84,
+				"node_instance-0000000005_indices_fielddata_evictions":                         0,
+				"node_instance-0000000005_indices_fielddata_memory_size_in_bytes":              0,
+				"node_instance-0000000005_indices_flush_total":                                 67895,
+				"node_instance-0000000005_indices_flush_total_time_in_millis":                  81917283,
+				"node_instance-0000000005_indices_indexing_index_current":                      0,
+				"node_instance-0000000005_indices_indexing_index_time_in_millis":               1244633519,
+				"node_instance-0000000005_indices_indexing_index_total":                        6550378755,
+				"node_instance-0000000005_indices_refresh_total":                               12359783,
+				"node_instance-0000000005_indices_refresh_total_time_in_millis":                300152615,
+				"node_instance-0000000005_indices_search_fetch_current":                        0,
+				"node_instance-0000000005_indices_search_fetch_time_in_millis":                 24517851,
+				"node_instance-0000000005_indices_search_fetch_total":                          25105951,
+				"node_instance-0000000005_indices_search_query_current":                        0,
+				"node_instance-0000000005_indices_search_query_time_in_millis":                 158980385,
+				"node_instance-0000000005_indices_search_query_total":                          157912598,
+				"node_instance-0000000005_indices_segments_count":                              291,
+				"node_instance-0000000005_indices_segments_doc_values_memory_in_bytes":         0,
+				"node_instance-0000000005_indices_segments_fixed_bit_set_memory_in_bytes":      55672,
+				"node_instance-0000000005_indices_segments_index_writer_memory_in_bytes":       57432664,
+				"node_instance-0000000005_indices_segments_memory_in_bytes":                    0,
+				"node_instance-0000000005_indices_segments_norms_memory_in_bytes":              0,
+				"node_instance-0000000005_indices_segments_points_memory_in_bytes":             0,
+				"node_instance-0000000005_indices_segments_stored_fields_memory_in_bytes":      0,
+				"node_instance-0000000005_indices_segments_term_vectors_memory_in_bytes":       0,
+				"node_instance-0000000005_indices_segments_terms_memory_in_bytes":              0,
+				"node_instance-0000000005_indices_segments_version_map_memory_in_bytes":        568,
+				"node_instance-0000000005_indices_translog_operations":                         1449698,
+				"node_instance-0000000005_indices_translog_size_in_bytes":                      1214204014,
+				"node_instance-0000000005_indices_translog_uncommitted_operations":             1449698,
+				"node_instance-0000000005_indices_translog_uncommitted_size_in_bytes":          1214204014,
+				"node_instance-0000000005_jvm_buffer_pools_direct_count":                       90,
+				"node_instance-0000000005_jvm_buffer_pools_direct_total_capacity_in_bytes":     4571711,
+				"node_instance-0000000005_jvm_buffer_pools_direct_used_in_bytes":               4571713,
+				"node_instance-0000000005_jvm_buffer_pools_mapped_count":                       831,
+				"node_instance-0000000005_jvm_buffer_pools_mapped_total_capacity_in_bytes":     99844219805,
+				"node_instance-0000000005_jvm_buffer_pools_mapped_used_in_bytes":               99844219805,
+				"node_instance-0000000005_jvm_gc_collectors_old_collection_count":              1,
+				"node_instance-0000000005_jvm_gc_collectors_old_collection_time_in_millis":     796,
+				"node_instance-0000000005_jvm_gc_collectors_young_collection_count":            139959,
+				"node_instance-0000000005_jvm_gc_collectors_young_collection_time_in_millis":   3581668,
+				"node_instance-0000000005_jvm_mem_heap_committed_in_bytes":                     7864320000,
+				"node_instance-0000000005_jvm_mem_heap_used_in_bytes":                          1884124192,
+				"node_instance-0000000005_jvm_mem_heap_used_percent":                           23,
+				"node_instance-0000000005_process_max_file_descriptors":                        1048576,
+				"node_instance-0000000005_process_open_file_descriptors":                       1180,
+				"node_instance-0000000005_thread_pool_analyze_queue":                           0,
+				"node_instance-0000000005_thread_pool_analyze_rejected":                        0,
+				"node_instance-0000000005_thread_pool_fetch_shard_started_queue":               0,
+				"node_instance-0000000005_thread_pool_fetch_shard_started_rejected":            0,
+				"node_instance-0000000005_thread_pool_fetch_shard_store_queue":                 0,
+				"node_instance-0000000005_thread_pool_fetch_shard_store_rejected":              0,
+				"node_instance-0000000005_thread_pool_flush_queue":                             0,
+				"node_instance-0000000005_thread_pool_flush_rejected":                          0,
+				"node_instance-0000000005_thread_pool_force_merge_queue":                       0,
+				"node_instance-0000000005_thread_pool_force_merge_rejected":                    0,
+				"node_instance-0000000005_thread_pool_generic_queue":                           0,
+				"node_instance-0000000005_thread_pool_generic_rejected":                        0,
+				"node_instance-0000000005_thread_pool_get_queue":                               0,
+				"node_instance-0000000005_thread_pool_get_rejected":                            0,
+				"node_instance-0000000005_thread_pool_listener_queue":                          0,
+				"node_instance-0000000005_thread_pool_listener_rejected":                       0,
+				"node_instance-0000000005_thread_pool_management_queue":                        0,
+				"node_instance-0000000005_thread_pool_management_rejected":                     0,
+				"node_instance-0000000005_thread_pool_refresh_queue":                           0,
+				"node_instance-0000000005_thread_pool_refresh_rejected":                        0,
+				"node_instance-0000000005_thread_pool_search_queue":                            0,
+				"node_instance-0000000005_thread_pool_search_rejected":                         0,
+				"node_instance-0000000005_thread_pool_search_throttled_queue":                  0,
+				"node_instance-0000000005_thread_pool_search_throttled_rejected":               0,
+				"node_instance-0000000005_thread_pool_snapshot_queue":                          0,
+				"node_instance-0000000005_thread_pool_snapshot_rejected":                       0,
+				"node_instance-0000000005_thread_pool_warmer_queue":                            0,
+				"node_instance-0000000005_thread_pool_warmer_rejected":                         0,
+				"node_instance-0000000005_thread_pool_write_queue":                             0,
+				"node_instance-0000000005_thread_pool_write_rejected":                          0,
+				"node_instance-0000000005_transport_rx_count":                                  2167879292,
+				"node_instance-0000000005_transport_rx_size_in_bytes":                          4905919297323,
+				"node_instance-0000000005_transport_tx_count":                                  2167879293,
+				"node_instance-0000000005_transport_tx_size_in_bytes":                          2964638852652,
 			},
 		},
 		"v842: local node stats": {
@@ -433,92 +488,92 @@ func TestElasticsearch_Collect(t *testing.T) {
 			},
 			wantCharts: len(nodeChartsTmpl),
 			wantCollected: map[string]int64{
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_accounting_tripped":                       0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_fielddata_tripped":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_in_flight_requests_tripped":               0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_model_inference_tripped":                  0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_parent_tripped":                           0,
-				"node_Klg1CjgMTouentQcJlRGuA_breakers_request_tripped":                          0,
-				"node_Klg1CjgMTouentQcJlRGuA_http_current_open":                                 73,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_fielddata_evictions":                       0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_fielddata_memory_size_in_bytes":            600,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_flush_total":                               35134,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_flush_total_time_in_millis":                22213090,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_indexing_index_current":                    1,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_indexing_index_time_in_millis":             1100149051,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_indexing_index_total":                      3667793202,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_refresh_total":                             7721472,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_refresh_total_time_in_millis":              94304142,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_fetch_current":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_fetch_time_in_millis":               21316820,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_fetch_total":                        42645288,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_query_current":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_query_time_in_millis":               51265805,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_search_query_total":                        166823028,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_count":                            307,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_doc_values_memory_in_bytes":       0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_fixed_bit_set_memory_in_bytes":    2008,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_index_writer_memory_in_bytes":     240481008,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_memory_in_bytes":                  0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_norms_memory_in_bytes":            0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_points_memory_in_bytes":           0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_stored_fields_memory_in_bytes":    0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_term_vectors_memory_in_bytes":     0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_terms_memory_in_bytes":            0,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_segments_version_map_memory_in_bytes":      44339216,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_translog_operations":                       362831,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_translog_size_in_bytes":                    453491882,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_translog_uncommitted_operations":           362831,
-				"node_Klg1CjgMTouentQcJlRGuA_indices_translog_uncommitted_size_in_bytes":        453491882,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_direct_count":                     94,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_direct_total_capacity_in_bytes":   4654848,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_direct_used_in_bytes":             4654850,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_mapped_count":                     844,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_mapped_total_capacity_in_bytes":   103411995802,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_buffer_pools_mapped_used_in_bytes":             103411995802,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_gc_collectors_old_collection_count":            0,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_gc_collectors_old_collection_time_in_millis":   0,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_gc_collectors_young_collection_count":          78661,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_gc_collectors_young_collection_time_in_millis": 6014901,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_mem_heap_committed_in_bytes":                   7864320000,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_mem_heap_used_in_bytes":                        4337402488,
-				"node_Klg1CjgMTouentQcJlRGuA_jvm_mem_heap_used_percent":                         55,
-				"node_Klg1CjgMTouentQcJlRGuA_process_max_file_descriptors":                      1048576,
-				"node_Klg1CjgMTouentQcJlRGuA_process_open_file_descriptors":                     1149,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_analyze_queue":                         0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_analyze_rejected":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_fetch_shard_started_queue":             0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_fetch_shard_started_rejected":          0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_fetch_shard_store_queue":               0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_fetch_shard_store_rejected":            0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_flush_queue":                           0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_flush_rejected":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_force_merge_queue":                     0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_force_merge_rejected":                  0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_generic_queue":                         0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_generic_rejected":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_get_queue":                             0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_get_rejected":                          0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_listener_queue":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_listener_rejected":                     0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_management_queue":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_management_rejected":                   0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_refresh_queue":                         0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_refresh_rejected":                      0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_search_queue":                          0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_search_rejected":                       0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_search_throttled_queue":                0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_search_throttled_rejected":             0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_snapshot_queue":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_snapshot_rejected":                     0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_warmer_queue":                          0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_warmer_rejected":                       0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_write_queue":                           0,
-				"node_Klg1CjgMTouentQcJlRGuA_thread_pool_write_rejected":                        0,
-				"node_Klg1CjgMTouentQcJlRGuA_transport_rx_count":                                1300468666,
-				"node_Klg1CjgMTouentQcJlRGuA_transport_rx_size_in_bytes":                        1789647854011,
-				"node_Klg1CjgMTouentQcJlRGuA_transport_tx_count":                                1300468665,
-				"node_Klg1CjgMTouentQcJlRGuA_transport_tx_size_in_bytes":                        2927853534431,
+				"node_instance-0000000006_breakers_accounting_tripped":                       0,
+				"node_instance-0000000006_breakers_fielddata_tripped":                        0,
+				"node_instance-0000000006_breakers_in_flight_requests_tripped":               0,
+				"node_instance-0000000006_breakers_model_inference_tripped":                  0,
+				"node_instance-0000000006_breakers_parent_tripped":                           0,
+				"node_instance-0000000006_breakers_request_tripped":                          0,
+				"node_instance-0000000006_http_current_open":                                 73,
+				"node_instance-0000000006_indices_fielddata_evictions":                       0,
+				"node_instance-0000000006_indices_fielddata_memory_size_in_bytes":            600,
+				"node_instance-0000000006_indices_flush_total":                               35134,
+				"node_instance-0000000006_indices_flush_total_time_in_millis":                22213090,
+				"node_instance-0000000006_indices_indexing_index_current":                    1,
+				"node_instance-0000000006_indices_indexing_index_time_in_millis":             1100149051,
+				"node_instance-0000000006_indices_indexing_index_total":                      3667793202,
+				"node_instance-0000000006_indices_refresh_total":                             7721472,
+				"node_instance-0000000006_indices_refresh_total_time_in_millis":              94304142,
+				"node_instance-0000000006_indices_search_fetch_current":                      0,
+				"node_instance-0000000006_indices_search_fetch_time_in_millis":               21316820,
+				"node_instance-0000000006_indices_search_fetch_total":                        42645288,
+				"node_instance-0000000006_indices_search_query_current":                      0,
+				"node_instance-0000000006_indices_search_query_time_in_millis":               51265805,
+				"node_instance-0000000006_indices_search_query_total":                        166823028,
+				"node_instance-0000000006_indices_segments_count":                            307,
+				"node_instance-0000000006_indices_segments_doc_values_memory_in_bytes":       0,
+				"node_instance-0000000006_indices_segments_fixed_bit_set_memory_in_bytes":    2008,
+				"node_instance-0000000006_indices_segments_index_writer_memory_in_bytes":     240481008,
+				"node_instance-0000000006_indices_segments_memory_in_bytes":                  0,
+				"node_instance-0000000006_indices_segments_norms_memory_in_bytes":            0,
+				"node_instance-0000000006_indices_segments_points_memory_in_bytes":           0,
+				"node_instance-0000000006_indices_segments_stored_fields_memory_in_bytes":    0,
+				"node_instance-0000000006_indices_segments_term_vectors_memory_in_bytes":     0,
+				"node_instance-0000000006_indices_segments_terms_memory_in_bytes":            0,
+				"node_instance-0000000006_indices_segments_version_map_memory_in_bytes":      44339216,
+				"node_instance-0000000006_indices_translog_operations":                       362831,
+				"node_instance-0000000006_indices_translog_size_in_bytes":                    453491882,
+				"node_instance-0000000006_indices_translog_uncommitted_operations":           362831,
+				"node_instance-0000000006_indices_translog_uncommitted_size_in_bytes":        453491882,
+				"node_instance-0000000006_jvm_buffer_pools_direct_count":                     94,
+				"node_instance-0000000006_jvm_buffer_pools_direct_total_capacity_in_bytes":   4654848,
+				"node_instance-0000000006_jvm_buffer_pools_direct_used_in_bytes":             4654850,
+				"node_instance-0000000006_jvm_buffer_pools_mapped_count":                     844,
+				"node_instance-0000000006_jvm_buffer_pools_mapped_total_capacity_in_bytes":   103411995802,
+				"node_instance-0000000006_jvm_buffer_pools_mapped_used_in_bytes":             103411995802,
+				"node_instance-0000000006_jvm_gc_collectors_old_collection_count":            0,
+				"node_instance-0000000006_jvm_gc_collectors_old_collection_time_in_millis":   0,
+				"node_instance-0000000006_jvm_gc_collectors_young_collection_count":          78661,
+				"node_instance-0000000006_jvm_gc_collectors_young_collection_time_in_millis": 6014901,
+				"node_instance-0000000006_jvm_mem_heap_committed_in_bytes":                   7864320000,
+				"node_instance-0000000006_jvm_mem_heap_used_in_bytes":                        4337402488,
+				"node_instance-0000000006_jvm_mem_heap_used_percent":                         55,
+				"node_instance-0000000006_process_max_file_descriptors":                      1048576,
+				"node_instance-0000000006_process_open_file_descriptors":                     1149,
+				"node_instance-0000000006_thread_pool_analyze_queue":                         0,
+				"node_instance-0000000006_thread_pool_analyze_rejected":                      0,
+				"node_instance-0000000006_thread_pool_fetch_shard_started_queue":             0,
+				"node_instance-0000000006_thread_pool_fetch_shard_started_rejected":          0,
+				"node_instance-0000000006_thread_pool_fetch_shard_store_queue":               0,
+				"node_instance-0000000006_thread_pool_fetch_shard_store_rejected":            0,
+				"node_instance-0000000006_thread_pool_flush_queue":                           0,
+				"node_instance-0000000006_thread_pool_flush_rejected":                        0,
+				"node_instance-0000000006_thread_pool_force_merge_queue":                     0,
+				"node_instance-0000000006_thread_pool_force_merge_rejected":                  0,
+				"node_instance-0000000006_thread_pool_generic_queue":                         0,
+				"node_instance-0000000006_thread_pool_generic_rejected":                      0,
+				"node_instance-0000000006_thread_pool_get_queue":                             0,
+				"node_instance-0000000006_thread_pool_get_rejected":                          0,
+				"node_instance-0000000006_thread_pool_listener_queue":                        0,
+				"node_instance-0000000006_thread_pool_listener_rejected":                     0,
+				"node_instance-0000000006_thread_pool_management_queue":                      0,
+				"node_instance-0000000006_thread_pool_management_rejected":                   0,
+				"node_instance-0000000006_thread_pool_refresh_queue":                         0,
+				"node_instance-0000000006_thread_pool_refresh_rejected":                      0,
+				"node_instance-0000000006_thread_pool_search_queue":                          0,
+				"node_instance-0000000006_thread_pool_search_rejected":                       0,
+				"node_instance-0000000006_thread_pool_search_throttled_queue":                0,
+				"node_instance-0000000006_thread_pool_search_throttled_rejected":             0,
+				"node_instance-0000000006_thread_pool_snapshot_queue":                        0,
+				"node_instance-0000000006_thread_pool_snapshot_rejected":                     0,
+				"node_instance-0000000006_thread_pool_warmer_queue":                          0,
+				"node_instance-0000000006_thread_pool_warmer_rejected":                       0,
+				"node_instance-0000000006_thread_pool_write_queue":                           0,
+				"node_instance-0000000006_thread_pool_write_rejected":                        0,
+				"node_instance-0000000006_transport_rx_count":                                1300468666,
+				"node_instance-0000000006_transport_rx_size_in_bytes":                        1789647854011,
+				"node_instance-0000000006_transport_tx_count":                                1300468665,
+				"node_instance-0000000006_transport_tx_size_in_bytes":                        2927853534431,
 			},
 		},
 		"v842: only cluster_health": {
@@ -644,6 +699,214 @@ func TestElasticsearch_Collect(t *testing.T) {
 	}
 }
 
+func TestElasticsearch_Collect_NodesRollingRestart(t *testing.T) {
+	nodesStats := v842NodesStats
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case urlPathNodesStats:
+				_, _ = w.Write(nodesStats)
+			case "/":
+				_, _ = w.Write(v842Info)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer srv.Close()
+
+	es := New()
+	es.URL = srv.URL
+	es.ClusterMode = true
+	es.DoClusterHealth = false
+	es.DoClusterStats = false
+	es.NodeAbsentCycles = 1
+	require.True(t, es.Init())
+
+	isNodeChartsObsolete := func(name string) (obsolete bool, found bool) {
+		for _, chart := range *es.Charts() {
+			if strings.HasPrefix(chart.ID, "node_"+name+"_cluster_") {
+				found = true
+				obsolete = chart.Obsolete
+			}
+		}
+		return obsolete, found
+	}
+
+	steps := []struct {
+		nodesStats   []byte
+		wantNodes    []string
+		wantObsolete []string
+		wantCharts   int
+	}{
+		{
+			nodesStats: v842NodesStats,
+			wantNodes:  []string{"instance-0000000005", "instance-0000000006", "tiebreaker-0000000002"},
+			wantCharts: len(nodeChartsTmpl) * 3,
+		},
+		{
+			// instance-0000000005 is stopped, its charts are kept for 'node_absent_cycles'
+			nodesStats: v842NodesStatsRestart1,
+			wantNodes:  []string{"instance-0000000006", "tiebreaker-0000000002"},
+			wantCharts: len(nodeChartsTmpl) * 3,
+		},
+		{
+			nodesStats:   v842NodesStatsRestart1,
+			wantNodes:    []string{"instance-0000000006", "tiebreaker-0000000002"},
+			wantObsolete: []string{"instance-0000000005"},
+			wantCharts:   len(nodeChartsTmpl) * 3,
+		},
+		{
+			// instance-0000000005 is back with a new node ID, instance-0000000006 is stopped
+			nodesStats: v842NodesStatsRestart2,
+			wantNodes:  []string{"instance-0000000005", "tiebreaker-0000000002"},
+			wantCharts: len(nodeChartsTmpl) * 4,
+		},
+	}
+
+	for i, step := range steps {
+		nodesStats = step.nodesStats
+
+		mx := es.Collect()
+
+		for _, name := range step.wantNodes {
+			assert.Containsf(t, mx, "node_"+name+"_jvm_mem_heap_used_percent", "step %d node '%s'", i, name)
+		}
+		for _, name := range step.wantObsolete {
+			obsolete, found := isNodeChartsObsolete(name)
+			assert.Truef(t, found && obsolete, "step %d node '%s' charts are not obsolete", i, name)
+		}
+		assert.NotContains(t, mx, "node_Qm3bT0wAQ9yKcLrXhPpN1g_jvm_mem_heap_used_percent")
+		assert.Lenf(t, *es.Charts(), step.wantCharts, "step %d", i)
+	}
+
+	// the restarted node charts are not obsolete
+	obsolete, _ := isNodeChartsObsolete("instance-0000000005")
+	assert.False(t, obsolete)
+}
+
+func TestElasticsearch_Collect_Indices(t *testing.T) {
+	indicesStats := v842IndicesStats
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case urlPathIndicesStatsAll:
+				_, _ = w.Write(indicesStats)
+			case "/":
+				_, _ = w.Write(v842Info)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer srv.Close()
+
+	es := New()
+	es.URL = srv.URL
+	es.DoNodeStats = false
+	es.DoClusterHealth = false
+	es.DoClusterStats = false
+	es.DoIndices = true
+	require.True(t, es.Init())
+
+	mx := es.Collect()
+
+	expected := map[string]int64{
+		"index_my-index-000001_docs_count":          10,
+		"index_my-index-000001_indexing_rate":       0,
+		"index_my-index-000001_merge_time":          0,
+		"index_my-index-000001_refresh_time":        0,
+		"index_my-index-000001_search_query_rate":   0,
+		"index_my-index-000001_store_size_in_bytes": 1000,
+		"index_my-index-000002_docs_count":          20,
+		"index_my-index-000002_indexing_rate":       0,
+		"index_my-index-000002_merge_time":          0,
+		"index_my-index-000002_refresh_time":        0,
+		"index_my-index-000002_search_query_rate":   0,
+		"index_my-index-000002_store_size_in_bytes": 2000,
+		"index_my-index-000003_docs_count":          30,
+		"index_my-index-000003_indexing_rate":       0,
+		"index_my-index-000003_merge_time":          0,
+		"index_my-index-000003_refresh_time":        0,
+		"index_my-index-000003_search_query_rate":   0,
+		"index_my-index-000003_store_size_in_bytes": 3000,
+	}
+	assert.Equal(t, expected, mx)
+	assert.Len(t, *es.Charts(), len(indexChartsTmpl)*3)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, es, mx)
+
+	// the index 'my-index-000001' is deleted
+	indicesStats = v842IndicesStatsRotated
+	es.indicesLastPull = es.indicesLastPull.Add(-time.Second * 10)
+
+	mx = es.Collect()
+
+	assert.Equal(t, int64(25), mx["index_my-index-000002_docs_count"])
+	assert.Equal(t, int64(2100), mx["index_my-index-000002_store_size_in_bytes"])
+	assert.InDelta(t, 5*precision, mx["index_my-index-000002_indexing_rate"], 10)
+	assert.InDelta(t, 10*precision, mx["index_my-index-000002_search_query_rate"], 10)
+	assert.InDelta(t, 2*precision, mx["index_my-index-000002_refresh_time"], 10)
+	assert.Equal(t, int64(0), mx["index_my-index-000002_merge_time"])
+	assert.InDelta(t, 10*precision, mx["index_my-index-000003_indexing_rate"], 10)
+	assert.InDelta(t, 3*precision, mx["index_my-index-000003_merge_time"], 10)
+	assert.NotContains(t, mx, "index_my-index-000001_docs_count")
+	assert.NotContains(t, mx, "index__kibana_1_docs_count")
+	assert.NotContains(t, mx, "index_.kibana_1_docs_count")
+
+	for _, chart := range *es.Charts() {
+		if strings.HasPrefix(chart.ID, "index_my-index-000001_") {
+			assert.Truef(t, chart.Obsolete, "chart '%s' is not obsolete", chart.ID)
+		} else {
+			assert.Falsef(t, chart.Obsolete, "chart '%s' is obsolete", chart.ID)
+		}
+	}
+}
+
+func TestElasticsearch_Collect_IndicesMaxIndices(t *testing.T) {
+	es, cleanup := prepareElasticsearch(t, func() *Elasticsearch {
+		es := New()
+		es.DoNodeStats = false
+		es.DoClusterHealth = false
+		es.DoClusterStats = false
+		es.DoIndices = true
+		es.IndicesSelector = "*"
+		es.MaxIndices = 2
+		return es
+	})
+	defer cleanup()
+
+	mx := es.Collect()
+
+	// the largest indices are charted, the rest are aggregated
+	assert.Equal(t, int64(30), mx["index_my-index-000003_docs_count"])
+	assert.Equal(t, int64(20), mx["index_my-index-000002_docs_count"])
+	assert.Equal(t, int64(10+5), mx["index__other_docs_count"])
+	assert.Equal(t, int64(1000+500), mx["index__other_store_size_in_bytes"])
+	assert.NotContains(t, mx, "index_my-index-000001_docs_count")
+	assert.NotContains(t, mx, "index_.kibana_1_docs_count")
+	assert.Len(t, *es.Charts(), len(indexChartsTmpl)*3)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, es, mx)
+}
+
+func TestElasticsearch_Collect_IndicesUpdateEvery(t *testing.T) {
+	es, cleanup := prepareElasticsearch(t, func() *Elasticsearch {
+		es := New()
+		es.DoNodeStats = false
+		es.DoClusterHealth = false
+		es.DoClusterStats = false
+		es.DoIndices = true
+		es.IndicesUpdateEvery = web.Duration{Duration: time.Minute}
+		return es
+	})
+	defer cleanup()
+
+	mx := es.Collect()
+	require.NotEmpty(t, mx)
+	lastPull := es.indicesLastPull
+
+	// '_stats' is not pulled again, the last values are reported
+	assert.Equal(t, mx, es.Collect())
+	assert.Equal(t, lastPull, es.indicesLastPull)
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, es *Elasticsearch, collected map[string]int64) {
 	for _, chart := range *es.Charts() {
 		if chart.Obsolete {
@@ -724,6 +987,8 @@ func prepareElasticsearchEndpoint() *httptest.Server {
 				_, _ = w.Write(v842ClusterStats)
 			case urlPathIndicesStats:
 				_, _ = w.Write(v842CatIndicesStats)
+			case urlPathIndicesStatsAll:
+				_, _ = w.Write(v842IndicesStats)
 			case "/":
 				_, _ = w.Write(v842Info)
 			default:
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -13,15 +14,29 @@ func (es *Elasticsearch) validateConfig() error {
 	if es.URL == "" {
 		return errors.New("URL not set")
 	}
-	if !(es.DoNodeStats || es.DoClusterHealth || es.DoClusterStats || es.DoIndicesStats) {
+	if !(es.DoNodeStats || es.DoClusterHealth || es.DoClusterStats || es.DoIndicesStats || es.DoIndices) {
 		return errors.New("all API calls are disabled")
 	}
+	if es.NodeAbsentCycles < 0 {
+		return errors.New("'node_absent_cycles' can not be negative")
+	}
+	if es.DoIndices && es.MaxIndices <= 0 {
+		return errors.New("'max_indices' must be positive when 'collect_indices' is enabled")
+	}
 	if _, err := web.NewHTTPRequest(es.Request); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (es *Elasticsearch) initIndicesSelector() (matcher.Matcher, error) {
+	if !es.DoIndices || es.IndicesSelector == "" {
+		return nil, nil
+	}
+
+	return matcher.NewSimplePatternsMatcher(es.IndicesSelector)
+}
+
 func (es *Elasticsearch) initHTTPClient() (*http.Client, error) {
 	return web.NewHTTPClient(es.Client)
 }
@@ -13,11 +13,13 @@ type esMetrics struct {
 	ClusterStats *esClusterStats
 	// https://www.elastic.co/guide/en/elasticsearch/reference/current/cat-indices.html
 	LocalIndicesStats []esIndexStats
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-stats.html
+	IndicesStats *esIndicesStats
 }
 
 func (m esMetrics) empty() bool {
 	switch {
-	case m.hasNodesStats(), m.hasClusterHealth(), m.hasClusterStats(), m.hasLocalIndicesStats():
+	case m.hasNodesStats(), m.hasClusterHealth(), m.hasClusterStats(), m.hasLocalIndicesStats(), m.hasIndicesStats():
 		return false
 	}
 	return true
@@ -27,6 +29,7 @@ func (m esMetrics) hasNodesStats() bool        { return m.NodesStats != nil && l
 func (m esMetrics) hasClusterHealth() bool     { return m.ClusterHealth != nil }
 func (m esMetrics) hasClusterStats() bool      { return m.ClusterStats != nil }
 func (m esMetrics) hasLocalIndicesStats() bool { return len(m.LocalIndicesStats) > 0 }
+func (m esMetrics) hasIndicesStats() bool      { return m.IndicesStats != nil }
 
 type (
 	esNodesStats struct {
@@ -275,3 +278,33 @@ type esIndexStats struct {
 	DocsCount string `json:"docs.count"`
 	StoreSize string `json:"store.size"`
 }
+
+type (
+	esIndicesStats struct {
+		Indices map[string]esIndicesStatsIndex `json:"indices"`
+	}
+	esIndicesStatsIndex struct {
+		Primaries struct {
+			Docs struct {
+				Count int64 `json:"count"`
+			} `json:"docs"`
+		} `json:"primaries"`
+		Total struct {
+			Store struct {
+				SizeInBytes int64 `json:"size_in_bytes"`
+			} `json:"store"`
+			Indexing struct {
+				IndexTotal int64 `json:"index_total"`
+			} `json:"indexing"`
+			Search struct {
+				QueryTotal int64 `json:"query_total"`
+			} `json:"search"`
+			Refresh struct {
+				TotalTimeInMillis int64 `json:"total_time_in_millis"`
+			} `json:"refresh"`
+			Merges struct {
+				TotalTimeInMillis int64 `json:"total_time_in_millis"`
+			} `json:"merges"`
+		} `json:"total"`
+	}
+)
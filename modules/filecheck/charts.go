@@ -35,6 +35,13 @@ var (
 		Fam:   "files",
 		Ctx:   "filecheck.file_size",
 	}
+	fileModifiedChart = module.Chart{
+		ID:    "file_modified",
+		Title: "File Modified Since the Last Check (0: not modified, 1: modified)",
+		Units: "boolean",
+		Fam:   "files",
+		Ctx:   "filecheck.file_modified",
+	}
 )
 
 var (
@@ -76,4 +83,11 @@ var (
 		Fam:   "dirs",
 		Ctx:   "filecheck.dir_size",
 	}
+	dirNumOfMatchedFilesChart = module.Chart{
+		ID:    "dir_num_of_matched_files",
+		Title: "Dir Number of Files Matching the Pattern",
+		Units: "files",
+		Fam:   "dirs",
+		Ctx:   "filecheck.dir_num_of_matched_files",
+	}
 )
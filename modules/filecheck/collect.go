@@ -37,4 +37,11 @@ func removeDuplicates(s []string) []string {
 	return uniq
 }
 
+func boolToInt(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
 var reSpace = regexp.MustCompile(`\s`)
@@ -4,6 +4,7 @@ package filecheck
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -47,6 +48,11 @@ func (fc *Filecheck) collectDir(ms map[string]int64, path string, curTime time.T
 	if num, err := calcDirNumOfFiles(path); err == nil {
 		ms[dirDimID(path, "num_of_files")] = int64(num)
 	}
+	if fc.Dirs.FilesPattern != "" {
+		if num, err := calcDirNumOfMatchedFiles(path, fc.Dirs.FilesPattern); err == nil {
+			ms[dirDimID(path, "num_of_matched_files")] = int64(num)
+		}
+	}
 	if fc.Dirs.CollectDirSize {
 		if size, err := calcDirSize(path); err == nil {
 			ms[dirDimID(path, "size_bytes")] = size
@@ -54,7 +60,7 @@ func (fc *Filecheck) collectDir(ms map[string]int64, path string, curTime time.T
 	}
 }
 
-func (fc Filecheck) discoveryDirs() (dirs []string) {
+func (fc *Filecheck) discoveryDirs() (dirs []string) {
 	for _, path := range fc.Dirs.Include {
 		if hasMeta(path) {
 			continue
@@ -66,13 +72,7 @@ func (fc Filecheck) discoveryDirs() (dirs []string) {
 		if !hasMeta(path) {
 			continue
 		}
-		matches, _ := filepath.Glob(path)
-		for _, v := range matches {
-			fi, err := os.Lstat(v)
-			if err == nil && fi.IsDir() {
-				dirs = append(dirs, v)
-			}
-		}
+		dirs = append(dirs, fc.glob(path, fs.FileMode.IsDir)...)
 	}
 	return removeDuplicates(dirs)
 }
@@ -108,6 +108,8 @@ func (fc *Filecheck) addDirToCharts(path string) {
 			id = dirDimID(path, "mtime_ago")
 		case dirNumOfFilesChart.ID:
 			id = dirDimID(path, "num_of_files")
+		case dirNumOfMatchedFilesChart.ID:
+			id = dirDimID(path, "num_of_matched_files")
 		case dirSizeChart.ID:
 			id = dirDimID(path, "size_bytes")
 		default:
@@ -139,6 +141,8 @@ func (fc *Filecheck) removeDirFromCharts(path string) {
 			id = dirDimID(path, "mtime_ago")
 		case dirNumOfFilesChart.ID:
 			id = dirDimID(path, "num_of_files")
+		case dirNumOfMatchedFilesChart.ID:
+			id = dirDimID(path, "num_of_matched_files")
 		case dirSizeChart.ID:
 			id = dirDimID(path, "size_bytes")
 		default:
@@ -169,6 +173,29 @@ func calcDirNumOfFiles(dirpath string) (int, error) {
 	return len(names), err
 }
 
+// calcDirNumOfMatchedFiles counts the directory entries (not recursively) that are not directories and
+// whose names match the pattern.
+func calcDirNumOfMatchedFiles(dirpath, pattern string) (int, error) {
+	f, err := os.Open(dirpath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	entries, err := f.ReadDir(-1)
+	if err != nil {
+		return 0, err
+	}
+
+	var num int
+	for _, e := range entries {
+		if ok, _ := filepath.Match(pattern, e.Name()); ok && !e.IsDir() {
+			num++
+		}
+	}
+	return num, nil
+}
+
 func calcDirSize(dirpath string) (int64, error) {
 	var size int64
 	err := filepath.Walk(dirpath, func(_ string, info os.FileInfo, err error) error {
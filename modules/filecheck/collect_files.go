@@ -3,9 +3,11 @@
 package filecheck
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -45,9 +47,44 @@ func (fc *Filecheck) collectFile(ms map[string]int64, path string, curTime time.
 	ms[fileDimID(path, "exists")] = 1
 	ms[fileDimID(path, "size_bytes")] = info.Size()
 	ms[fileDimID(path, "mtime_ago")] = int64(curTime.Sub(info.ModTime()).Seconds())
+	if fc.Files.CollectChecksum {
+		fc.collectFileChecksum(ms, path, info)
+	}
+}
+
+type fileSum struct {
+	size  int64
+	mtime time.Time
+	sum   [sha256.Size]byte
+}
+
+// collectFileChecksum reports whether the file content changed since the last check. The checksum is computed
+// only if the size or the modification time changed, the files bigger than 'checksum_max_size' are skipped.
+func (fc *Filecheck) collectFileChecksum(ms map[string]int64, path string, info os.FileInfo) {
+	prev, ok := fc.fileSums[path]
+	if ok && prev.size == info.Size() && prev.mtime.Equal(info.ModTime()) {
+		ms[fileDimID(path, "modified")] = 0
+		return
+	}
+
+	if fc.Files.ChecksumMaxSize > 0 && info.Size() > fc.Files.ChecksumMaxSize {
+		fc.Debugf("file '%s' size (%d) exceeds the checksum max size (%d), skipping the checksum",
+			path, info.Size(), fc.Files.ChecksumMaxSize)
+		delete(fc.fileSums, path)
+		return
+	}
+
+	sum, err := calcFileChecksum(path)
+	if err != nil {
+		fc.Debug(err)
+		return
+	}
+
+	fc.fileSums[path] = &fileSum{size: info.Size(), mtime: info.ModTime(), sum: sum}
+	ms[fileDimID(path, "modified")] = boolToInt(ok && prev.sum != sum)
 }
 
-func (fc Filecheck) discoveryFiles() (files []string) {
+func (fc *Filecheck) discoveryFiles() (files []string) {
 	for _, path := range fc.Files.Include {
 		if hasMeta(path) {
 			continue
@@ -59,13 +96,7 @@ func (fc Filecheck) discoveryFiles() (files []string) {
 		if !hasMeta(path) {
 			continue
 		}
-		matches, _ := filepath.Glob(path)
-		for _, v := range matches {
-			fi, err := os.Lstat(v)
-			if err == nil && fi.Mode().IsRegular() {
-				files = append(files, v)
-			}
-		}
+		files = append(files, fc.glob(path, fs.FileMode.IsRegular)...)
 	}
 	return removeDuplicates(files)
 }
@@ -82,6 +113,7 @@ func (fc *Filecheck) updateFilesCharts(files []string) {
 	for path := range fc.collectedFiles {
 		if !set[path] {
 			delete(fc.collectedFiles, path)
+			delete(fc.fileSums, path)
 			fc.removeFileFromCharts(path)
 		}
 	}
@@ -101,6 +133,8 @@ func (fc *Filecheck) addFileToCharts(path string) {
 			id = fileDimID(path, "mtime_ago")
 		case fileSizeChart.ID:
 			id = fileDimID(path, "size_bytes")
+		case fileModifiedChart.ID:
+			id = fileDimID(path, "modified")
 		default:
 			fc.Warningf("add dimension: couldn't dim id for '%s' chart (file '%s')", chart.ID, path)
 			continue
@@ -130,6 +164,8 @@ func (fc *Filecheck) removeFileFromCharts(path string) {
 			id = fileDimID(path, "mtime_ago")
 		case fileSizeChart.ID:
 			id = fileDimID(path, "size_bytes")
+		case fileModifiedChart.ID:
+			id = fileDimID(path, "modified")
 		default:
 			fc.Warningf("remove dimension: couldn't dim id for '%s' chart (file '%s')", chart.ID, path)
 			continue
@@ -143,6 +179,23 @@ func (fc *Filecheck) removeFileFromCharts(path string) {
 	}
 }
 
+func calcFileChecksum(path string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+
+	f, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
 func fileDimID(path, metric string) string {
 	return fmt.Sprintf("file_%s_%s", reSpace.ReplaceAllString(path, "_"), metric)
 }
@@ -27,30 +27,38 @@ func New() *Filecheck {
 	return &Filecheck{
 		Config: Config{
 			DiscoveryEvery: web.Duration{Duration: time.Second * 30},
-			Files:          filesConfig{},
+			MaxMatches:     1000,
+			Files: filesConfig{
+				ChecksumMaxSize: 100 * 1024 * 1024,
+			},
 			Dirs: dirsConfig{
 				CollectDirSize: true,
 			},
 		},
 		collectedFiles: make(map[string]bool),
 		collectedDirs:  make(map[string]bool),
+		fileSums:       make(map[string]*fileSum),
 	}
 }
 
 type (
 	Config struct {
 		DiscoveryEvery web.Duration `yaml:"discovery_every"`
+		MaxMatches     int          `yaml:"max_matches"`
 		Files          filesConfig  `yaml:"files"`
 		Dirs           dirsConfig   `yaml:"dirs"`
 	}
 	filesConfig struct {
-		Include []string `yaml:"include"`
-		Exclude []string `yaml:"exclude"`
+		Include         []string `yaml:"include"`
+		Exclude         []string `yaml:"exclude"`
+		CollectChecksum bool     `yaml:"collect_checksum"`
+		ChecksumMaxSize int64    `yaml:"checksum_max_size"`
 	}
 	dirsConfig struct {
 		Include        []string `yaml:"include"`
 		Exclude        []string `yaml:"exclude"`
 		CollectDirSize bool     `yaml:"collect_dir_size"`
+		FilesPattern   string   `yaml:"files_pattern"`
 	}
 )
 
@@ -61,6 +69,7 @@ type Filecheck struct {
 	lastDiscoveryFiles time.Time
 	curFiles           []string
 	collectedFiles     map[string]bool
+	fileSums           map[string]*fileSum
 
 	lastDiscoveryDirs time.Time
 	curDirs           []string
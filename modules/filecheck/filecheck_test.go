@@ -3,8 +3,11 @@
 package filecheck
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
 
@@ -237,6 +240,172 @@ func TestFilecheck_Collect(t *testing.T) {
 	}
 }
 
+func TestFilecheck_Collect_Checksum(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.conf")
+	require.NoError(t, os.WriteFile(path, []byte("key=value"), 0644))
+
+	fc := New()
+	fc.Files.Include = []string{path}
+	fc.Files.CollectChecksum = true
+	require.True(t, fc.Init())
+
+	modified := fileDimID(path, "modified")
+	mtime := time.Now().Add(-time.Hour)
+	setMtime := func() {
+		mtime = mtime.Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	setMtime()
+	assert.Equal(t, int64(0), fc.Collect()[modified], "first check")
+	assert.Equal(t, int64(0), fc.Collect()[modified], "not changed")
+
+	require.NoError(t, os.WriteFile(path, []byte("key=other"), 0644))
+	setMtime()
+	assert.Equal(t, int64(1), fc.Collect()[modified], "content changed")
+	assert.Equal(t, int64(0), fc.Collect()[modified], "not changed since the last check")
+
+	setMtime()
+	assert.Equal(t, int64(0), fc.Collect()[modified], "touched")
+
+	fc.Files.ChecksumMaxSize = 1
+	require.NoError(t, os.WriteFile(path, []byte("key=value"), 0644))
+	setMtime()
+	collected := fc.Collect()
+	assert.NotContains(t, collected, modified, "exceeds the checksum max size")
+	assert.Equal(t, int64(1), collected[fileDimID(path, "exists")])
+}
+
+func TestFilecheck_Collect_RecursivePattern(t *testing.T) {
+	root := prepareDirTree(t)
+
+	tests := map[string]struct {
+		prepare   func() *Filecheck
+		wantFiles []string
+		wantDirs  []string
+	}{
+		"files": {
+			prepare: func() *Filecheck {
+				fc := New()
+				fc.Files.Include = []string{filepath.Join(root, "**", "*.log")}
+				return fc
+			},
+			wantFiles: []string{"a/b/y.log", "a/x.log", "z.log"},
+		},
+		"files in subdirs": {
+			prepare: func() *Filecheck {
+				fc := New()
+				fc.Files.Include = []string{filepath.Join(root, "a", "**", "b", "*")}
+				return fc
+			},
+			wantFiles: []string{"a/b/c.txt", "a/b/y.log"},
+		},
+		"files limit": {
+			prepare: func() *Filecheck {
+				fc := New()
+				fc.MaxMatches = 2
+				fc.Files.Include = []string{filepath.Join(root, "**", "*.log")}
+				return fc
+			},
+			wantFiles: []string{"a/b/y.log", "a/x.log"},
+		},
+		"dirs": {
+			prepare: func() *Filecheck {
+				fc := New()
+				fc.Dirs.Include = []string{filepath.Join(root, "**")}
+				return fc
+			},
+			wantDirs: []string{"a", "a/b"},
+		},
+		"relative pattern": {
+			prepare: func() *Filecheck {
+				fc := New()
+				fc.Files.Include = []string{"testdata/**/*.log"}
+				return fc
+			},
+			wantFiles: []string{
+				"testdata/dir/empty_file.log",
+				"testdata/dir/file.log",
+				"testdata/dir/subdir/empty_file.log",
+				"testdata/empty_file.log",
+				"testdata/file.log",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fc := test.prepare()
+			require.True(t, fc.Init())
+
+			done := make(chan map[string]int64)
+			go func() { done <- fc.Collect() }()
+
+			var collected map[string]int64
+			select {
+			case collected = <-done:
+			case <-time.After(time.Second * 5):
+				t.Fatal("collection hangs")
+			}
+
+			abs := func(paths []string) []string {
+				var res []string
+				for _, v := range paths {
+					if !strings.HasPrefix(v, "testdata") {
+						v = filepath.Join(root, v)
+					}
+					res = append(res, v)
+				}
+				return res
+			}
+			assert.Equal(t, abs(test.wantFiles), fc.curFiles)
+			assert.Equal(t, abs(test.wantDirs), fc.curDirs)
+			assert.Equal(t, int64(len(test.wantFiles)), collected["num_of_files"])
+			assert.Equal(t, int64(len(test.wantDirs)), collected["num_of_dirs"])
+			ensureCollectedHasAllChartsDimsVarsIDs(t, fc, collected)
+		})
+	}
+}
+
+func TestFilecheck_Collect_DirFilesPattern(t *testing.T) {
+	root := prepareDirTree(t)
+
+	fc := New()
+	fc.Dirs.Include = []string{filepath.Join(root, "a"), filepath.Join(root, "a", "b")}
+	fc.Dirs.FilesPattern = "*.log"
+	require.True(t, fc.Init())
+
+	collected := fc.Collect()
+
+	assert.Equal(t, int64(1), collected[dirDimID(filepath.Join(root, "a"), "num_of_matched_files")])
+	assert.Equal(t, int64(2), collected[dirDimID(filepath.Join(root, "a"), "num_of_files")])
+	assert.Equal(t, int64(1), collected[dirDimID(filepath.Join(root, "a", "b"), "num_of_matched_files")])
+	assert.Equal(t, int64(3), collected[dirDimID(filepath.Join(root, "a", "b"), "num_of_files")])
+
+	fc = New()
+	fc.Dirs.Include = []string{root}
+	fc.Dirs.FilesPattern = "[.log"
+	assert.False(t, fc.Init())
+}
+
+// prepareDirTree creates:
+//
+//	a/x.log
+//	a/b/y.log
+//	a/b/c.txt
+//	a/b/loop -> .. (symbolic link loop)
+//	z.log
+func prepareDirTree(t *testing.T) string {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "a", "b"), 0755))
+	for _, path := range []string{"a/x.log", "a/b/y.log", "a/b/c.txt", "z.log"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte("data"), 0644))
+	}
+	require.NoError(t, os.Symlink("..", filepath.Join(root, "a", "b", "loop")))
+	return root
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, fc *Filecheck, collected map[string]int64) {
 	// TODO: check other charts
 	for _, chart := range *fc.Charts() {
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package filecheck
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const globStar = "**"
+
+// glob returns the paths matching the pattern that have the kept file mode type, at most 'max_matches'.
+func (fc *Filecheck) glob(pattern string, keep func(fs.FileMode) bool) []string {
+	var matches []string
+
+	if isRecursivePattern(pattern) {
+		matches = globRecursive(pattern, keep, fc.MaxMatches)
+	} else {
+		names, _ := filepath.Glob(pattern)
+		for _, v := range names {
+			fi, err := os.Lstat(v)
+			if err != nil || !keep(fi.Mode()) {
+				continue
+			}
+			if fc.MaxMatches > 0 && len(matches) == fc.MaxMatches {
+				break
+			}
+			matches = append(matches, v)
+		}
+	}
+
+	if fc.MaxMatches > 0 && len(matches) == fc.MaxMatches {
+		fc.Warningf("pattern '%s': the number of matches reached the limit (%d), the rest is not monitored", pattern, fc.MaxMatches)
+	}
+	return matches
+}
+
+func isRecursivePattern(pattern string) bool {
+	for _, v := range splitPath(pattern) {
+		if v == globStar {
+			return true
+		}
+	}
+	return false
+}
+
+// globRecursive is filepath.Glob with '**' support, it matches zero or more directories. The symbolic links are
+// not followed, a link loop can't hang the walk. It stops after 'limit' matches (no limit if zero).
+func globRecursive(pattern string, keep func(fs.FileMode) bool, limit int) []string {
+	root, rest := splitPatternRoot(pattern)
+
+	var matches []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			// unreadable entries are skipped
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		name := splitPath(rel)
+
+		if keep(d.Type()) && matchSegments(rest, name, false) {
+			matches = append(matches, path)
+			if limit > 0 && len(matches) == limit {
+				return filepath.SkipAll
+			}
+		}
+		if d.IsDir() && !matchSegments(rest, name, true) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	return matches
+}
+
+// splitPatternRoot splits the pattern into the directory the walk starts from (the leading segments without
+// the meta characters) and the rest of the segments.
+func splitPatternRoot(pattern string) (string, []string) {
+	segments := splitPath(pattern)
+
+	i := 0
+	for i < len(segments)-1 && !hasMeta(segments[i]) {
+		i++
+	}
+
+	root := filepath.Join(segments[:i]...)
+	switch {
+	case filepath.IsAbs(pattern):
+		root = string(filepath.Separator) + root
+	case root == "":
+		root = "."
+	}
+	return root, segments[i:]
+}
+
+// matchSegments reports whether the path segments match the pattern segments. If partial, it reports
+// whether the path can be a prefix of a matching path (the walk has to descend into the directory).
+func matchSegments(pattern, name []string, partial bool) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == globStar {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:], partial) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return partial
+		}
+		if ok, _ := filepath.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	for _, v := range strings.Split(path, string(filepath.Separator)) {
+		if v != "" {
+			segments = append(segments, v)
+		}
+	}
+	return segments
+}
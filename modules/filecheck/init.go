@@ -4,6 +4,8 @@ package filecheck
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
 
 	"github.com/netdata/go.d.plugin/agent/module"
 )
@@ -12,6 +14,9 @@ func (fc Filecheck) validateConfig() error {
 	if len(fc.Files.Include) == 0 && len(fc.Dirs.Include) == 0 {
 		return errors.New("both 'files->include' and 'dirs->include' are empty")
 	}
+	if _, err := filepath.Match(fc.Dirs.FilesPattern, ""); err != nil {
+		return fmt.Errorf("bad 'dirs->files_pattern' '%s': %v", fc.Dirs.FilesPattern, err)
+	}
 	return nil
 }
 
@@ -22,6 +27,11 @@ func (fc Filecheck) initCharts() (*module.Charts, error) {
 		if err := charts.Add(*fileCharts.Copy()...); err != nil {
 			return nil, err
 		}
+		if fc.Files.CollectChecksum {
+			if err := charts.Add(fileModifiedChart.Copy()); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if len(fc.Dirs.Include) > 0 {
@@ -33,6 +43,11 @@ func (fc Filecheck) initCharts() (*module.Charts, error) {
 				return nil, err
 			}
 		}
+		if fc.Dirs.FilesPattern != "" {
+			if err := charts.Add(dirNumOfMatchedFilesChart.Copy()); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if len(*charts) == 0 {
@@ -4,6 +4,7 @@ package haproxy
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/netdata/go.d.plugin/agent/module"
 )
@@ -93,6 +94,86 @@ var (
 	}
 )
 
+var chartsTmplServer = module.Charts{
+	chartTmplServerCurrentSessions.Copy(),
+	chartTmplServerCurrentQueue.Copy(),
+	chartTmplServerResponseTimeAverage.Copy(),
+	chartTmplServerWeight.Copy(),
+	chartTmplServerState.Copy(),
+	chartTmplServerCheckStatus.Copy(),
+}
+
+var (
+	chartTmplServerCurrentSessions = module.Chart{
+		ID:    "server_%s_backend_%s_current_sessions",
+		Title: "Server current number of active sessions",
+		Units: "sessions",
+		Fam:   "servers",
+		Ctx:   "haproxy.server_current_sessions",
+		Dims: module.Dims{
+			{ID: "server_%s_backend_%s_current_sessions", Name: "active"},
+		},
+	}
+	chartTmplServerCurrentQueue = module.Chart{
+		ID:    "server_%s_backend_%s_current_queue",
+		Title: "Server current number of queued requests",
+		Units: "requests",
+		Fam:   "servers",
+		Ctx:   "haproxy.server_current_queue",
+		Dims: module.Dims{
+			{ID: "server_%s_backend_%s_current_queue", Name: "queued"},
+		},
+	}
+	chartTmplServerResponseTimeAverage = module.Chart{
+		ID:    "server_%s_backend_%s_response_time_average",
+		Title: "Server average response time for last 1024 successful connections",
+		Units: "milliseconds",
+		Fam:   "servers",
+		Ctx:   "haproxy.server_response_time_average",
+		Dims: module.Dims{
+			{ID: "server_%s_backend_%s_response_time_average", Name: "time"},
+		},
+	}
+	chartTmplServerWeight = module.Chart{
+		ID:    "server_%s_backend_%s_weight",
+		Title: "Server effective weight",
+		Units: "weight",
+		Fam:   "servers",
+		Ctx:   "haproxy.server_weight",
+		Dims: module.Dims{
+			{ID: "server_%s_backend_%s_weight", Name: "weight"},
+		},
+	}
+	chartTmplServerState = module.Chart{
+		ID:    "server_%s_backend_%s_state",
+		Title: "Server state",
+		Units: "state",
+		Fam:   "servers",
+		Ctx:   "haproxy.server_state",
+		Dims: module.Dims{
+			{ID: "server_%s_backend_%s_state_up", Name: "up"},
+			{ID: "server_%s_backend_%s_state_down", Name: "down"},
+			{ID: "server_%s_backend_%s_state_maint", Name: "maint"},
+			{ID: "server_%s_backend_%s_state_drain", Name: "drain"},
+			{ID: "server_%s_backend_%s_state_nolb", Name: "nolb"},
+			{ID: "server_%s_backend_%s_state_no_check", Name: "no_check"},
+		},
+	}
+	chartTmplServerCheckStatus = module.Chart{
+		ID:    "server_%s_backend_%s_check_status",
+		Title: "Server last health check status",
+		Units: "status",
+		Fam:   "servers",
+		Ctx:   "haproxy.server_check_status",
+		Dims: module.Dims{
+			{ID: "server_%s_backend_%s_check_status_ok", Name: "ok"},
+			{ID: "server_%s_backend_%s_check_status_error", Name: "error"},
+			{ID: "server_%s_backend_%s_check_status_timeout", Name: "timeout"},
+			{ID: "server_%s_backend_%s_check_status_unknown", Name: "unknown"},
+		},
+	}
+)
+
 func newChartBackendHTTPResponses(proxy string) *module.Chart {
 	return newBackendChartFromTemplate(chartTemplateBackendHTTPResponses, proxy)
 }
@@ -110,3 +191,34 @@ func newBackendChartFromTemplate(tpl module.Chart, proxy string) *module.Chart {
 	}
 	return c
 }
+
+var forbiddenCharsReplacer = strings.NewReplacer(" ", "_", ".", "_")
+
+func (h *Haproxy) addServerCharts(backend, server string) {
+	charts := chartsTmplServer.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, forbiddenCharsReplacer.Replace(server), forbiddenCharsReplacer.Replace(backend))
+		chart.Labels = []module.Label{
+			{Key: "backend", Value: backend},
+			{Key: "server", Value: server},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, server, backend)
+		}
+	}
+
+	if err := h.Charts().Add(*charts...); err != nil {
+		h.Warning(err)
+	}
+}
+
+func (h *Haproxy) removeServerCharts(backend, server string) {
+	px := fmt.Sprintf("server_%s_backend_%s_", forbiddenCharsReplacer.Replace(server), forbiddenCharsReplacer.Replace(backend))
+	for _, chart := range *h.Charts() {
+		if strings.HasPrefix(chart.ID, px) {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
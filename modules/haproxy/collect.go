@@ -4,6 +4,7 @@ package haproxy
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/netdata/go.d.plugin/agent/module"
@@ -19,6 +20,21 @@ const (
 	metricBackendQueueTimeAverageSeconds    = "haproxy_backend_queue_time_average_seconds"
 	metricBackendBytesInTotal               = "haproxy_backend_bytes_in_total"
 	metricBackendBytesOutTotal              = "haproxy_backend_bytes_out_total"
+
+	metricServerCurrentSessions            = "haproxy_server_current_sessions"
+	metricServerCurrentQueue               = "haproxy_server_current_queue"
+	metricServerResponseTimeAverageSeconds = "haproxy_server_response_time_average_seconds"
+	metricServerWeight                     = "haproxy_server_weight"
+	metricServerStatus                     = "haproxy_server_status"
+	metricServerCheckStatus                = "haproxy_server_check_status"
+)
+
+type dataSource string
+
+const (
+	sourcePrometheus  dataSource = "prometheus"
+	sourceStatsCSV    dataSource = "stats csv"
+	sourceStatsSocket dataSource = "stats socket"
 )
 
 func isHaproxyMetrics(pms prometheus.Series) bool {
@@ -31,20 +47,88 @@ func isHaproxyMetrics(pms prometheus.Series) bool {
 }
 
 func (h *Haproxy) collect() (map[string]int64, error) {
-	pms, err := h.prom.ScrapeSeries()
+	if h.source == "" {
+		source, err := h.detectSource()
+		if err != nil {
+			return nil, err
+		}
+		h.Infof("using %s as the data source", source)
+		h.source = source
+	}
+
+	mx := make(map[string]int64)
+
+	var servers []serverStats
+	var err error
+
+	switch h.source {
+	case sourcePrometheus:
+		servers, err = h.collectPrometheus(mx)
+	default:
+		servers, err = h.collectStats(mx)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if h.validateMetrics && !isHaproxyMetrics(pms) {
-		return nil, errors.New("unexpected metrics (not HAProxy)")
+	h.collectServers(mx, servers)
+
+	return mx, nil
+}
+
+// detectSource probes the data sources: the stats socket if it is set, otherwise
+// the built-in Prometheus exporter and the CSV stats page if the exporter is not compiled in.
+func (h *Haproxy) detectSource() (dataSource, error) {
+	if h.newSocket != nil {
+		if _, err := h.scrapeStatsSocket(); err != nil {
+			return "", fmt.Errorf("stats socket '%s': %v", h.StatsSocket, err)
+		}
+		return sourceStatsSocket, nil
 	}
-	h.validateMetrics = false
 
-	mx := make(map[string]int64)
+	if !strings.HasSuffix(h.URL, statsCSVSuffix) {
+		pms, err := h.prom.ScrapeSeries()
+		if err == nil && isHaproxyMetrics(pms) {
+			return sourcePrometheus, nil
+		}
+		if err == nil {
+			err = errors.New("unexpected metrics (not HAProxy)")
+		}
+		h.Debugf("prometheus endpoint '%s': %v", h.URL, err)
+	}
+
+	if _, err := h.scrapeStatsCSV(); err != nil {
+		return "", fmt.Errorf("neither prometheus nor CSV stats are available at '%s': %v", h.URL, err)
+	}
+	return sourceStatsCSV, nil
+}
+
+func (h *Haproxy) collectPrometheus(mx map[string]int64) ([]serverStats, error) {
+	pms, err := h.prom.ScrapeSeries()
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make(map[serverKey]*serverStats)
+
 	for _, pm := range pms {
 		proxy := pm.Labels.Get("proxy")
-		if proxy == "" {
+		if proxy == "" || !h.isBackendSelected(proxy) {
+			continue
+		}
+
+		if strings.HasPrefix(pm.Name(), "haproxy_server_") {
+			name := pm.Labels.Get("server")
+			if name == "" {
+				continue
+			}
+			key := serverKey{backend: proxy, server: name}
+			srv, ok := servers[key]
+			if !ok {
+				srv = &serverStats{serverKey: key}
+				servers[key] = srv
+			}
+			srv.setPrometheusValue(pm)
 			continue
 		}
 
@@ -56,7 +140,16 @@ func (h *Haproxy) collect() (map[string]int64, error) {
 		mx[dimID(pm)] = int64(pm.Value * multiplier(pm))
 	}
 
-	return mx, nil
+	stats := make([]serverStats, 0, len(servers))
+	for _, srv := range servers {
+		stats = append(stats, *srv)
+	}
+
+	return stats, nil
+}
+
+func (h *Haproxy) isBackendSelected(proxy string) bool {
+	return h.backendSelector == nil || h.backendSelector.MatchString(proxy)
 }
 
 func (h *Haproxy) addProxyToCharts(proxy string) {
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package haproxy
+
+import (
+	"strings"
+
+	"github.com/netdata/go.d.plugin/pkg/prometheus"
+)
+
+type (
+	serverKey struct {
+		backend string
+		server  string
+	}
+	// serverStats holds a server values, the response time is in milliseconds.
+	serverStats struct {
+		serverKey
+		currentSessions int64
+		currentQueue    int64
+		responseTime    int64
+		weight          int64
+		state           string
+		checkStatus     string
+	}
+)
+
+var (
+	serverStates = []string{"up", "down", "maint", "drain", "nolb", "no_check"}
+	checkClasses = []string{"ok", "error", "timeout", "unknown"}
+
+	// haproxy_server_status values before HAProxy 2.4, since 2.4 the state is in the 'state' label.
+	serverStatusCodes = []string{"down", "up", "maint", "drain", "nolb"}
+	// haproxy_server_check_status values before HAProxy 2.4 (HCHK_STATUS_* enum),
+	// since 2.4 the status is in the 'state' label.
+	checkStatusCodes = []string{
+		"UNK", "INI", "STRT", "HANA", "SOCKERR", "L4OK", "L4TOUT", "L4CON", "L6OK", "L6TOUT",
+		"L6RSP", "L7TOUT", "L7RSP", "L7OK", "L7OKC", "L7STS", "PROCERR", "PROCTOUT", "PROCOK",
+	}
+)
+
+func (h *Haproxy) collectServers(mx map[string]int64, servers []serverStats) {
+	seen := make(map[serverKey]bool)
+
+	for _, srv := range servers {
+		seen[srv.serverKey] = true
+		if !h.servers[srv.serverKey] {
+			h.servers[srv.serverKey] = true
+			h.Debugf("new server '%s' in backend '%s': adding charts", srv.server, srv.backend)
+			h.addServerCharts(srv.backend, srv.server)
+		}
+
+		px := "server_" + srv.server + "_backend_" + srv.backend + "_"
+		mx[px+"current_sessions"] = srv.currentSessions
+		mx[px+"current_queue"] = srv.currentQueue
+		mx[px+"response_time_average"] = srv.responseTime
+		mx[px+"weight"] = srv.weight
+		for _, v := range serverStates {
+			mx[px+"state_"+v] = boolToInt(srv.state == v)
+		}
+		class := checkStatusClass(srv.checkStatus)
+		for _, v := range checkClasses {
+			mx[px+"check_status_"+v] = boolToInt(class == v)
+		}
+	}
+
+	for key := range h.servers {
+		if !seen[key] {
+			delete(h.servers, key)
+			h.Debugf("server '%s' in backend '%s' is gone: removing charts", key.server, key.backend)
+			h.removeServerCharts(key.backend, key.server)
+		}
+	}
+}
+
+func (s *serverStats) setPrometheusValue(pm prometheus.SeriesSample) {
+	switch pm.Name() {
+	case metricServerCurrentSessions:
+		s.currentSessions = int64(pm.Value)
+	case metricServerCurrentQueue:
+		s.currentQueue = int64(pm.Value)
+	case metricServerResponseTimeAverageSeconds:
+		s.responseTime = int64(pm.Value * 1000)
+	case metricServerWeight:
+		s.weight = int64(pm.Value)
+	case metricServerStatus:
+		if state := pm.Labels.Get("state"); state != "" {
+			if pm.Value == 1 {
+				s.state = serverState(state)
+			}
+		} else if i := int(pm.Value); i >= 0 && i < len(serverStatusCodes) {
+			s.state = serverStatusCodes[i]
+		}
+	case metricServerCheckStatus:
+		if state := pm.Labels.Get("state"); state != "" {
+			if pm.Value == 1 {
+				s.checkStatus = strings.TrimPrefix(state, "HCHK_STATUS_")
+			}
+		} else if i := int(pm.Value); i >= 0 && i < len(checkStatusCodes) {
+			s.checkStatus = checkStatusCodes[i]
+		}
+	}
+}
+
+func newServerStatsFromRow(backend string, row statsRow) serverStats {
+	return serverStats{
+		serverKey:       serverKey{backend: backend, server: row["svname"]},
+		currentSessions: parseStatsInt(row["scur"]),
+		currentQueue:    parseStatsInt(row["qcur"]),
+		responseTime:    parseStatsInt(row["rtime"]),
+		weight:          parseStatsInt(row["weight"]),
+		state:           serverState(row["status"]),
+		checkStatus:     row["check_status"],
+	}
+}
+
+// serverState returns the operational state of the 'status' field,
+// it can have a suffix during transitions ("UP 1/3", "MAINT (via b/s)").
+func serverState(status string) string {
+	status = strings.ToLower(status)
+	if status == "no check" {
+		return "no_check"
+	}
+	if i := strings.IndexByte(status, ' '); i > 0 {
+		status = status[:i]
+	}
+	return status
+}
+
+// checkStatusClass groups the health check status codes,
+// a '* ' prefix in the CSV stats means that the check is in progress.
+func checkStatusClass(status string) string {
+	status = strings.TrimPrefix(status, "* ")
+	switch {
+	case status == "":
+		return ""
+	case status == "UNK", status == "INI", status == "STRT", status == "START":
+		return "unknown"
+	case strings.HasSuffix(status, "TOUT"):
+		return "timeout"
+	case strings.HasSuffix(status, "OK"), strings.HasPrefix(status, "L7OK"):
+		return "ok"
+	default:
+		return "error"
+	}
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
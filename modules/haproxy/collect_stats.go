@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package haproxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+)
+
+const (
+	// statsCSVSuffix switches the HAProxy stats page to the CSV output.
+	statsCSVSuffix = ";csv"
+	// statsSocketCommand is the stats socket (runtime API) counterpart of the CSV stats page.
+	statsSocketCommand = "show stat\n"
+
+	// https://docs.haproxy.org/2.8/management.html#9.1 ('type' field)
+	statsTypeBackend = "1"
+	statsTypeServer  = "2"
+)
+
+// statsRow is a line of the CSV stats keyed by the header fields.
+type statsRow map[string]string
+
+func (h *Haproxy) collectStats(mx map[string]int64) ([]serverStats, error) {
+	var rows []statsRow
+	var err error
+
+	if h.source == sourceStatsSocket {
+		rows, err = h.scrapeStatsSocket()
+	} else {
+		rows, err = h.scrapeStatsCSV()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []serverStats
+
+	for _, row := range rows {
+		proxy := row["pxname"]
+		if proxy == "" || !h.isBackendSelected(proxy) {
+			continue
+		}
+
+		switch row["type"] {
+		case statsTypeBackend:
+			if !h.proxies[proxy] {
+				h.proxies[proxy] = true
+				h.addProxyToCharts(proxy)
+			}
+			collectBackendStatsRow(mx, proxy, row)
+		case statsTypeServer:
+			if row["svname"] != "" {
+				servers = append(servers, newServerStatsFromRow(proxy, row))
+			}
+		}
+	}
+
+	return servers, nil
+}
+
+// collectBackendStatsRow writes the backend CSV fields using the same keys as the Prometheus metrics.
+func collectBackendStatsRow(mx map[string]int64, proxy string, row statsRow) {
+	for field, metric := range backendStatsFields {
+		mx[proxyDimID(metric, proxy)] = parseStatsInt(row[field])
+	}
+	for _, code := range []string{"1xx", "2xx", "3xx", "4xx", "5xx", "other"} {
+		mx[proxyDimID("haproxy_backend_http_responses_"+code, proxy)] = parseStatsInt(row["hrsp_"+code])
+	}
+}
+
+// backendStatsFields maps the CSV fields to the Prometheus metrics, times are in milliseconds in both.
+var backendStatsFields = map[string]string{
+	"scur":  metricBackendCurrentSessions,
+	"stot":  metricBackendSessionsTotal,
+	"rtime": metricBackendResponseTimeAverageSeconds,
+	"qcur":  metricBackendCurrentQueue,
+	"qtime": metricBackendQueueTimeAverageSeconds,
+	"bin":   metricBackendBytesInTotal,
+	"bout":  metricBackendBytesOutTotal,
+}
+
+func (h *Haproxy) scrapeStatsCSV() ([]statsRow, error) {
+	req, err := web.NewHTTPRequest(h.Request)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(req.URL.Path, statsCSVSuffix) {
+		req.URL.Path += statsCSVSuffix
+		req.URL.RawPath = ""
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("'%s' returned HTTP status code: %d", req.URL, resp.StatusCode)
+	}
+
+	return parseStatsCSV(resp.Body)
+}
+
+func (h *Haproxy) scrapeStatsSocket() ([]statsRow, error) {
+	client := h.newSocket()
+
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Disconnect() }()
+
+	var buf bytes.Buffer
+	err := client.Command(statsSocketCommand, func(b []byte) bool {
+		// the response ends with an empty line
+		if len(b) == 0 {
+			return buf.Len() == 0
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStatsCSV(&buf)
+}
+
+// parseStatsCSV parses the 'show stat' output, the first line is the header: '# pxname,svname,...'.
+func parseStatsCSV(r io.Reader) ([]statsRow, error) {
+	br := bufio.NewReader(r)
+
+	header, err := br.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !strings.HasPrefix(header, "# pxname,") {
+		return nil, errors.New("unexpected response (not HAProxy CSV stats)")
+	}
+
+	fields := strings.Split(strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(header, "# ")), ","), ",")
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	var rows []statsRow
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(statsRow, len(fields))
+		for i, v := range record {
+			if i < len(fields) {
+				row[fields[i]] = v
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseStatsInt(s string) int64 {
+	v, _ := strconv.ParseInt(s, 10, 64)
+	return v
+}
+
+func closeBody(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
@@ -4,10 +4,13 @@ package haproxy
 
 import (
 	_ "embed"
+	"net/http"
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
+	"github.com/netdata/go.d.plugin/pkg/socket"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -34,14 +37,16 @@ func New() *Haproxy {
 			},
 		},
 
-		charts:          charts.Copy(),
-		proxies:         make(map[string]bool),
-		validateMetrics: true,
+		charts:  charts.Copy(),
+		proxies: make(map[string]bool),
+		servers: make(map[serverKey]bool),
 	}
 }
 
 type Config struct {
-	web.HTTP `yaml:",inline"`
+	web.HTTP        `yaml:",inline"`
+	StatsSocket     string `yaml:"stats_socket"`
+	BackendSelector string `yaml:"backend_selector"`
 }
 
 type Haproxy struct {
@@ -50,9 +55,16 @@ type Haproxy struct {
 
 	charts *module.Charts
 
-	prom            prometheus.Prometheus
-	validateMetrics bool
-	proxies         map[string]bool
+	// source is detected on the first data collection
+	source     dataSource
+	prom       prometheus.Prometheus
+	httpClient *http.Client
+	newSocket  func() socket.Client
+
+	backendSelector matcher.Matcher
+
+	proxies map[string]bool
+	servers map[serverKey]bool
 }
 
 func (h *Haproxy) Init() bool {
@@ -61,12 +73,25 @@ func (h *Haproxy) Init() bool {
 		return false
 	}
 
-	prom, err := h.initPrometheusClient()
+	if h.URL != "" {
+		httpClient, err := web.NewHTTPClient(h.Client)
+		if err != nil {
+			h.Errorf("HTTP client initialization: %v", err)
+			return false
+		}
+		h.httpClient = httpClient
+		h.prom = h.initPrometheusClient(httpClient)
+	}
+	if h.StatsSocket != "" {
+		h.newSocket = h.initStatsSocketClient
+	}
+
+	sr, err := h.initBackendSelector()
 	if err != nil {
-		h.Errorf("prometheus client initialization: %v", err)
+		h.Errorf("backend selector initialization: %v", err)
 		return false
 	}
-	h.prom = prom
+	h.backendSelector = sr
 
 	return true
 }
@@ -92,6 +117,8 @@ func (h *Haproxy) Collect() map[string]int64 {
 	return ms
 }
 
-func (Haproxy) Cleanup() {
-	// TODO: close http idle connections
+func (h *Haproxy) Cleanup() {
+	if h.httpClient != nil {
+		h.httpClient.CloseIdleConnections()
+	}
 }
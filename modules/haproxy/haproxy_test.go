@@ -3,11 +3,15 @@
 package haproxy
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/netdata/go.d.plugin/pkg/socket"
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
@@ -16,12 +20,16 @@ import (
 )
 
 var (
-	v2310Metrics, _ = os.ReadFile("testdata/v2.3.10/metrics.txt")
+	v2310Metrics, _  = os.ReadFile("testdata/v2.3.10/metrics.txt")
+	v2310StatsCSV, _ = os.ReadFile("testdata/v2.3.10/stats.csv")
+	v28xMetrics, _   = os.ReadFile("testdata/v2.8.x/metrics.txt")
 )
 
 func Test_Testdata(t *testing.T) {
 	for name, data := range map[string][]byte{
-		"v2310Metrics": v2310Metrics,
+		"v2310Metrics":  v2310Metrics,
+		"v2310StatsCSV": v2310StatsCSV,
+		"v28xMetrics":   v28xMetrics,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -45,6 +53,16 @@ func TestHaproxy_Init(t *testing.T) {
 				Request: web.Request{},
 			}},
 		},
+		"success on 'stats_socket' without 'url'": {
+			config: Config{StatsSocket: "/var/run/haproxy.sock"},
+		},
+		"fails on invalid 'backend_selector'": {
+			wantFail: true,
+			config: Config{
+				HTTP:            New().HTTP,
+				BackendSelector: "[",
+			},
+		},
 		"fails on invalid TLSCA": {
 			wantFail: true,
 			config: Config{
@@ -87,6 +105,22 @@ func TestHaproxy_Check(t *testing.T) {
 			wantFail: false,
 			prepare:  prepareCaseHaproxyV231Metrics,
 		},
+		"success on valid response v2.8.x": {
+			wantFail: false,
+			prepare:  prepareCaseHaproxyV28xMetrics,
+		},
+		"success on CSV stats v2.3.10": {
+			wantFail: false,
+			prepare:  prepareCaseHaproxyV2310StatsCSV,
+		},
+		"success on stats socket v2.3.10": {
+			wantFail: false,
+			prepare:  prepareCaseHaproxyV2310StatsSocket,
+		},
+		"fails on stats socket error": {
+			wantFail: true,
+			prepare:  prepareCaseStatsSocketError,
+		},
 		"fails on response with unexpected metrics (not HAProxy)": {
 			wantFail: true,
 			prepare:  prepareCaseNotHaproxyMetrics,
@@ -151,6 +185,293 @@ func TestHaproxy_Collect(t *testing.T) {
 				"haproxy_backend_sessions_proxy_proxy2":              4131723,
 			},
 		},
+		"success on valid response v2.8.x": {
+			prepare: prepareCaseHaproxyV28xMetrics,
+			wantCollected: map[string]int64{
+				"haproxy_backend_bytes_in_proxy_proxy1":              21057046294,
+				"haproxy_backend_bytes_in_proxy_proxy2":              2493759083896,
+				"haproxy_backend_bytes_out_proxy_proxy1":             41352782609,
+				"haproxy_backend_bytes_out_proxy_proxy2":             5131407558,
+				"haproxy_backend_current_queue_proxy_proxy1":         1,
+				"haproxy_backend_current_queue_proxy_proxy2":         1,
+				"haproxy_backend_current_sessions_proxy_proxy1":      1,
+				"haproxy_backend_current_sessions_proxy_proxy2":      1322,
+				"haproxy_backend_http_responses_1xx_proxy_proxy1":    1,
+				"haproxy_backend_http_responses_1xx_proxy_proxy2":    4130401,
+				"haproxy_backend_http_responses_2xx_proxy_proxy1":    21338013,
+				"haproxy_backend_http_responses_2xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_3xx_proxy_proxy1":    10004,
+				"haproxy_backend_http_responses_3xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_4xx_proxy_proxy1":    10170758,
+				"haproxy_backend_http_responses_4xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_5xx_proxy_proxy1":    3075,
+				"haproxy_backend_http_responses_5xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_other_proxy_proxy1":  5657,
+				"haproxy_backend_http_responses_other_proxy_proxy2":  1,
+				"haproxy_backend_queue_time_average_proxy_proxy1":    0,
+				"haproxy_backend_queue_time_average_proxy_proxy2":    0,
+				"haproxy_backend_response_time_average_proxy_proxy1": 52,
+				"haproxy_backend_response_time_average_proxy_proxy2": 1,
+				"haproxy_backend_sessions_proxy_proxy1":              31527507,
+				"haproxy_backend_sessions_proxy_proxy2":              4131723,
+				"server_srv1_backend_proxy1_check_status_error":      0,
+				"server_srv1_backend_proxy1_check_status_ok":         1,
+				"server_srv1_backend_proxy1_check_status_timeout":    0,
+				"server_srv1_backend_proxy1_check_status_unknown":    0,
+				"server_srv1_backend_proxy1_current_queue":           1,
+				"server_srv1_backend_proxy1_current_sessions":        2,
+				"server_srv1_backend_proxy1_response_time_average":   50,
+				"server_srv1_backend_proxy1_state_down":              0,
+				"server_srv1_backend_proxy1_state_drain":             0,
+				"server_srv1_backend_proxy1_state_maint":             0,
+				"server_srv1_backend_proxy1_state_no_check":          0,
+				"server_srv1_backend_proxy1_state_nolb":              0,
+				"server_srv1_backend_proxy1_state_up":                1,
+				"server_srv1_backend_proxy1_weight":                  100,
+				"server_srv1_backend_proxy2_check_status_error":      0,
+				"server_srv1_backend_proxy2_check_status_ok":         0,
+				"server_srv1_backend_proxy2_check_status_timeout":    1,
+				"server_srv1_backend_proxy2_check_status_unknown":    0,
+				"server_srv1_backend_proxy2_current_queue":           0,
+				"server_srv1_backend_proxy2_current_sessions":        661,
+				"server_srv1_backend_proxy2_response_time_average":   1,
+				"server_srv1_backend_proxy2_state_down":              0,
+				"server_srv1_backend_proxy2_state_drain":             0,
+				"server_srv1_backend_proxy2_state_maint":             1,
+				"server_srv1_backend_proxy2_state_no_check":          0,
+				"server_srv1_backend_proxy2_state_nolb":              0,
+				"server_srv1_backend_proxy2_state_up":                0,
+				"server_srv1_backend_proxy2_weight":                  0,
+				"server_srv2_backend_proxy1_check_status_error":      1,
+				"server_srv2_backend_proxy1_check_status_ok":         0,
+				"server_srv2_backend_proxy1_check_status_timeout":    0,
+				"server_srv2_backend_proxy1_check_status_unknown":    0,
+				"server_srv2_backend_proxy1_current_queue":           0,
+				"server_srv2_backend_proxy1_current_sessions":        0,
+				"server_srv2_backend_proxy1_response_time_average":   0,
+				"server_srv2_backend_proxy1_state_down":              1,
+				"server_srv2_backend_proxy1_state_drain":             0,
+				"server_srv2_backend_proxy1_state_maint":             0,
+				"server_srv2_backend_proxy1_state_no_check":          0,
+				"server_srv2_backend_proxy1_state_nolb":              0,
+				"server_srv2_backend_proxy1_state_up":                0,
+				"server_srv2_backend_proxy1_weight":                  100,
+				"server_srv2_backend_proxy2_check_status_error":      0,
+				"server_srv2_backend_proxy2_check_status_ok":         0,
+				"server_srv2_backend_proxy2_check_status_timeout":    0,
+				"server_srv2_backend_proxy2_check_status_unknown":    0,
+				"server_srv2_backend_proxy2_current_queue":           1,
+				"server_srv2_backend_proxy2_current_sessions":        661,
+				"server_srv2_backend_proxy2_response_time_average":   1,
+				"server_srv2_backend_proxy2_state_down":              0,
+				"server_srv2_backend_proxy2_state_drain":             0,
+				"server_srv2_backend_proxy2_state_maint":             0,
+				"server_srv2_backend_proxy2_state_no_check":          0,
+				"server_srv2_backend_proxy2_state_nolb":              0,
+				"server_srv2_backend_proxy2_state_up":                1,
+				"server_srv2_backend_proxy2_weight":                  1,
+			},
+		},
+		"success on CSV stats v2.3.10": {
+			prepare: prepareCaseHaproxyV2310StatsCSV,
+			wantCollected: map[string]int64{
+				"haproxy_backend_bytes_in_proxy_proxy1":              21057046294,
+				"haproxy_backend_bytes_in_proxy_proxy2":              2493759083896,
+				"haproxy_backend_bytes_in_proxy_stats":               0,
+				"haproxy_backend_bytes_out_proxy_proxy1":             41352782609,
+				"haproxy_backend_bytes_out_proxy_proxy2":             5131407558,
+				"haproxy_backend_bytes_out_proxy_stats":              0,
+				"haproxy_backend_current_queue_proxy_proxy1":         1,
+				"haproxy_backend_current_queue_proxy_proxy2":         1,
+				"haproxy_backend_current_queue_proxy_stats":          0,
+				"haproxy_backend_current_sessions_proxy_proxy1":      1,
+				"haproxy_backend_current_sessions_proxy_proxy2":      1322,
+				"haproxy_backend_current_sessions_proxy_stats":       0,
+				"haproxy_backend_http_responses_1xx_proxy_proxy1":    1,
+				"haproxy_backend_http_responses_1xx_proxy_proxy2":    4130401,
+				"haproxy_backend_http_responses_1xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_2xx_proxy_proxy1":    21338013,
+				"haproxy_backend_http_responses_2xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_2xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_3xx_proxy_proxy1":    10004,
+				"haproxy_backend_http_responses_3xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_3xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_4xx_proxy_proxy1":    10170758,
+				"haproxy_backend_http_responses_4xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_4xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_5xx_proxy_proxy1":    3075,
+				"haproxy_backend_http_responses_5xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_5xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_other_proxy_proxy1":  5657,
+				"haproxy_backend_http_responses_other_proxy_proxy2":  1,
+				"haproxy_backend_http_responses_other_proxy_stats":   0,
+				"haproxy_backend_queue_time_average_proxy_proxy1":    0,
+				"haproxy_backend_queue_time_average_proxy_proxy2":    0,
+				"haproxy_backend_queue_time_average_proxy_stats":     0,
+				"haproxy_backend_response_time_average_proxy_proxy1": 52,
+				"haproxy_backend_response_time_average_proxy_proxy2": 1,
+				"haproxy_backend_response_time_average_proxy_stats":  0,
+				"haproxy_backend_sessions_proxy_proxy1":              31527507,
+				"haproxy_backend_sessions_proxy_proxy2":              4131723,
+				"haproxy_backend_sessions_proxy_stats":               0,
+				"server_srv1_backend_proxy1_check_status_error":      0,
+				"server_srv1_backend_proxy1_check_status_ok":         1,
+				"server_srv1_backend_proxy1_check_status_timeout":    0,
+				"server_srv1_backend_proxy1_check_status_unknown":    0,
+				"server_srv1_backend_proxy1_current_queue":           1,
+				"server_srv1_backend_proxy1_current_sessions":        2,
+				"server_srv1_backend_proxy1_response_time_average":   50,
+				"server_srv1_backend_proxy1_state_down":              0,
+				"server_srv1_backend_proxy1_state_drain":             0,
+				"server_srv1_backend_proxy1_state_maint":             0,
+				"server_srv1_backend_proxy1_state_no_check":          0,
+				"server_srv1_backend_proxy1_state_nolb":              0,
+				"server_srv1_backend_proxy1_state_up":                1,
+				"server_srv1_backend_proxy1_weight":                  100,
+				"server_srv1_backend_proxy2_check_status_error":      0,
+				"server_srv1_backend_proxy2_check_status_ok":         0,
+				"server_srv1_backend_proxy2_check_status_timeout":    1,
+				"server_srv1_backend_proxy2_check_status_unknown":    0,
+				"server_srv1_backend_proxy2_current_queue":           0,
+				"server_srv1_backend_proxy2_current_sessions":        661,
+				"server_srv1_backend_proxy2_response_time_average":   1,
+				"server_srv1_backend_proxy2_state_down":              0,
+				"server_srv1_backend_proxy2_state_drain":             0,
+				"server_srv1_backend_proxy2_state_maint":             1,
+				"server_srv1_backend_proxy2_state_no_check":          0,
+				"server_srv1_backend_proxy2_state_nolb":              0,
+				"server_srv1_backend_proxy2_state_up":                0,
+				"server_srv1_backend_proxy2_weight":                  0,
+				"server_srv2_backend_proxy1_check_status_error":      1,
+				"server_srv2_backend_proxy1_check_status_ok":         0,
+				"server_srv2_backend_proxy1_check_status_timeout":    0,
+				"server_srv2_backend_proxy1_check_status_unknown":    0,
+				"server_srv2_backend_proxy1_current_queue":           0,
+				"server_srv2_backend_proxy1_current_sessions":        0,
+				"server_srv2_backend_proxy1_response_time_average":   0,
+				"server_srv2_backend_proxy1_state_down":              1,
+				"server_srv2_backend_proxy1_state_drain":             0,
+				"server_srv2_backend_proxy1_state_maint":             0,
+				"server_srv2_backend_proxy1_state_no_check":          0,
+				"server_srv2_backend_proxy1_state_nolb":              0,
+				"server_srv2_backend_proxy1_state_up":                0,
+				"server_srv2_backend_proxy1_weight":                  100,
+				"server_srv2_backend_proxy2_check_status_error":      0,
+				"server_srv2_backend_proxy2_check_status_ok":         0,
+				"server_srv2_backend_proxy2_check_status_timeout":    0,
+				"server_srv2_backend_proxy2_check_status_unknown":    0,
+				"server_srv2_backend_proxy2_current_queue":           1,
+				"server_srv2_backend_proxy2_current_sessions":        661,
+				"server_srv2_backend_proxy2_response_time_average":   1,
+				"server_srv2_backend_proxy2_state_down":              0,
+				"server_srv2_backend_proxy2_state_drain":             0,
+				"server_srv2_backend_proxy2_state_maint":             0,
+				"server_srv2_backend_proxy2_state_no_check":          1,
+				"server_srv2_backend_proxy2_state_nolb":              0,
+				"server_srv2_backend_proxy2_state_up":                0,
+				"server_srv2_backend_proxy2_weight":                  1,
+			},
+		},
+		"success on stats socket v2.3.10": {
+			prepare: prepareCaseHaproxyV2310StatsSocket,
+			wantCollected: map[string]int64{
+				"haproxy_backend_bytes_in_proxy_proxy1":              21057046294,
+				"haproxy_backend_bytes_in_proxy_proxy2":              2493759083896,
+				"haproxy_backend_bytes_in_proxy_stats":               0,
+				"haproxy_backend_bytes_out_proxy_proxy1":             41352782609,
+				"haproxy_backend_bytes_out_proxy_proxy2":             5131407558,
+				"haproxy_backend_bytes_out_proxy_stats":              0,
+				"haproxy_backend_current_queue_proxy_proxy1":         1,
+				"haproxy_backend_current_queue_proxy_proxy2":         1,
+				"haproxy_backend_current_queue_proxy_stats":          0,
+				"haproxy_backend_current_sessions_proxy_proxy1":      1,
+				"haproxy_backend_current_sessions_proxy_proxy2":      1322,
+				"haproxy_backend_current_sessions_proxy_stats":       0,
+				"haproxy_backend_http_responses_1xx_proxy_proxy1":    1,
+				"haproxy_backend_http_responses_1xx_proxy_proxy2":    4130401,
+				"haproxy_backend_http_responses_1xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_2xx_proxy_proxy1":    21338013,
+				"haproxy_backend_http_responses_2xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_2xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_3xx_proxy_proxy1":    10004,
+				"haproxy_backend_http_responses_3xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_3xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_4xx_proxy_proxy1":    10170758,
+				"haproxy_backend_http_responses_4xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_4xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_5xx_proxy_proxy1":    3075,
+				"haproxy_backend_http_responses_5xx_proxy_proxy2":    1,
+				"haproxy_backend_http_responses_5xx_proxy_stats":     0,
+				"haproxy_backend_http_responses_other_proxy_proxy1":  5657,
+				"haproxy_backend_http_responses_other_proxy_proxy2":  1,
+				"haproxy_backend_http_responses_other_proxy_stats":   0,
+				"haproxy_backend_queue_time_average_proxy_proxy1":    0,
+				"haproxy_backend_queue_time_average_proxy_proxy2":    0,
+				"haproxy_backend_queue_time_average_proxy_stats":     0,
+				"haproxy_backend_response_time_average_proxy_proxy1": 52,
+				"haproxy_backend_response_time_average_proxy_proxy2": 1,
+				"haproxy_backend_response_time_average_proxy_stats":  0,
+				"haproxy_backend_sessions_proxy_proxy1":              31527507,
+				"haproxy_backend_sessions_proxy_proxy2":              4131723,
+				"haproxy_backend_sessions_proxy_stats":               0,
+				"server_srv1_backend_proxy1_check_status_error":      0,
+				"server_srv1_backend_proxy1_check_status_ok":         1,
+				"server_srv1_backend_proxy1_check_status_timeout":    0,
+				"server_srv1_backend_proxy1_check_status_unknown":    0,
+				"server_srv1_backend_proxy1_current_queue":           1,
+				"server_srv1_backend_proxy1_current_sessions":        2,
+				"server_srv1_backend_proxy1_response_time_average":   50,
+				"server_srv1_backend_proxy1_state_down":              0,
+				"server_srv1_backend_proxy1_state_drain":             0,
+				"server_srv1_backend_proxy1_state_maint":             0,
+				"server_srv1_backend_proxy1_state_no_check":          0,
+				"server_srv1_backend_proxy1_state_nolb":              0,
+				"server_srv1_backend_proxy1_state_up":                1,
+				"server_srv1_backend_proxy1_weight":                  100,
+				"server_srv1_backend_proxy2_check_status_error":      0,
+				"server_srv1_backend_proxy2_check_status_ok":         0,
+				"server_srv1_backend_proxy2_check_status_timeout":    1,
+				"server_srv1_backend_proxy2_check_status_unknown":    0,
+				"server_srv1_backend_proxy2_current_queue":           0,
+				"server_srv1_backend_proxy2_current_sessions":        661,
+				"server_srv1_backend_proxy2_response_time_average":   1,
+				"server_srv1_backend_proxy2_state_down":              0,
+				"server_srv1_backend_proxy2_state_drain":             0,
+				"server_srv1_backend_proxy2_state_maint":             1,
+				"server_srv1_backend_proxy2_state_no_check":          0,
+				"server_srv1_backend_proxy2_state_nolb":              0,
+				"server_srv1_backend_proxy2_state_up":                0,
+				"server_srv1_backend_proxy2_weight":                  0,
+				"server_srv2_backend_proxy1_check_status_error":      1,
+				"server_srv2_backend_proxy1_check_status_ok":         0,
+				"server_srv2_backend_proxy1_check_status_timeout":    0,
+				"server_srv2_backend_proxy1_check_status_unknown":    0,
+				"server_srv2_backend_proxy1_current_queue":           0,
+				"server_srv2_backend_proxy1_current_sessions":        0,
+				"server_srv2_backend_proxy1_response_time_average":   0,
+				"server_srv2_backend_proxy1_state_down":              1,
+				"server_srv2_backend_proxy1_state_drain":             0,
+				"server_srv2_backend_proxy1_state_maint":             0,
+				"server_srv2_backend_proxy1_state_no_check":          0,
+				"server_srv2_backend_proxy1_state_nolb":              0,
+				"server_srv2_backend_proxy1_state_up":                0,
+				"server_srv2_backend_proxy1_weight":                  100,
+				"server_srv2_backend_proxy2_check_status_error":      0,
+				"server_srv2_backend_proxy2_check_status_ok":         0,
+				"server_srv2_backend_proxy2_check_status_timeout":    0,
+				"server_srv2_backend_proxy2_check_status_unknown":    0,
+				"server_srv2_backend_proxy2_current_queue":           1,
+				"server_srv2_backend_proxy2_current_sessions":        661,
+				"server_srv2_backend_proxy2_response_time_average":   1,
+				"server_srv2_backend_proxy2_state_down":              0,
+				"server_srv2_backend_proxy2_state_drain":             0,
+				"server_srv2_backend_proxy2_state_maint":             0,
+				"server_srv2_backend_proxy2_state_no_check":          1,
+				"server_srv2_backend_proxy2_state_nolb":              0,
+				"server_srv2_backend_proxy2_state_up":                0,
+				"server_srv2_backend_proxy2_weight":                  1,
+			},
+		},
 		"fails on response with unexpected metrics (not HAProxy)": {
 			prepare: prepareCaseNotHaproxyMetrics,
 		},
@@ -177,6 +498,54 @@ func TestHaproxy_Collect(t *testing.T) {
 	}
 }
 
+func TestHaproxy_Collect_BackendSelector(t *testing.T) {
+	h, cleanup := prepareCaseHaproxyV28xMetrics(t)
+	defer cleanup()
+
+	h.BackendSelector = "proxy2"
+	require.True(t, h.Init())
+
+	mx := h.Collect()
+	require.NotNil(t, mx)
+
+	for k := range mx {
+		assert.NotContainsf(t, k, "proxy1", "'%s' is collected for not selected backend", k)
+	}
+	assert.Equal(t, int64(1322), mx["haproxy_backend_current_sessions_proxy_proxy2"])
+	assert.Equal(t, int64(1), mx["server_srv1_backend_proxy2_state_maint"])
+	ensureCollectedHasAllChartsDimsVarsIDs(t, h, mx)
+}
+
+func TestHaproxy_Collect_ServerRemoved(t *testing.T) {
+	resp := v2310StatsCSV
+	h := New()
+	h.URL = ""
+	h.StatsSocket = "/var/run/haproxy.sock"
+	require.True(t, h.Init())
+	h.newSocket = func() socket.Client { return &mockSocket{resp: resp} }
+
+	require.NotNil(t, h.Collect())
+	chart := h.Charts().Get("server_srv2_backend_proxy1_state")
+	require.NotNil(t, chart)
+	assert.False(t, chart.Obsolete)
+
+	var lines [][]byte
+	for _, line := range bytes.Split(v2310StatsCSV, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("proxy1,srv2,")) {
+			lines = append(lines, line)
+		}
+	}
+	resp = bytes.Join(lines, []byte("\n"))
+
+	mx := h.Collect()
+	require.NotNil(t, mx)
+
+	assert.NotContains(t, mx, "server_srv2_backend_proxy1_current_sessions")
+	assert.Contains(t, mx, "server_srv1_backend_proxy1_current_sessions")
+	assert.True(t, chart.Obsolete)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, h, mx)
+}
+
 func prepareCaseHaproxyV231Metrics(t *testing.T) (*Haproxy, func()) {
 	t.Helper()
 	srv := httptest.NewServer(http.HandlerFunc(
@@ -190,6 +559,59 @@ func prepareCaseHaproxyV231Metrics(t *testing.T) (*Haproxy, func()) {
 	return h, srv.Close
 }
 
+func prepareCaseHaproxyV28xMetrics(t *testing.T) (*Haproxy, func()) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(v28xMetrics)
+		}))
+	h := New()
+	h.URL = srv.URL
+	require.True(t, h.Init())
+
+	return h, srv.Close
+}
+
+func prepareCaseHaproxyV2310StatsCSV(t *testing.T) (*Haproxy, func()) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// the Prometheus exporter is not compiled in, only the stats page is available
+			if r.URL.Path != "/stats;csv" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(v2310StatsCSV)
+		}))
+	h := New()
+	h.URL = srv.URL + "/stats"
+	require.True(t, h.Init())
+
+	return h, srv.Close
+}
+
+func prepareCaseHaproxyV2310StatsSocket(t *testing.T) (*Haproxy, func()) {
+	t.Helper()
+	h := New()
+	h.URL = ""
+	h.StatsSocket = "/var/run/haproxy.sock"
+	require.True(t, h.Init())
+	h.newSocket = func() socket.Client { return &mockSocket{resp: v2310StatsCSV} }
+
+	return h, func() {}
+}
+
+func prepareCaseStatsSocketError(t *testing.T) (*Haproxy, func()) {
+	t.Helper()
+	h := New()
+	h.URL = ""
+	h.StatsSocket = "/var/run/haproxy.sock"
+	require.True(t, h.Init())
+	h.newSocket = func() socket.Client { return &mockSocket{errOnConnect: true} }
+
+	return h, func() {}
+}
+
 func prepareCaseNotHaproxyMetrics(t *testing.T) (*Haproxy, func()) {
 	t.Helper()
 	srv := httptest.NewServer(http.HandlerFunc(
@@ -255,3 +677,29 @@ func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, h *Haproxy, ms map[str
 		}
 	}
 }
+
+type mockSocket struct {
+	resp         []byte
+	errOnConnect bool
+}
+
+func (m *mockSocket) Connect() error {
+	if m.errOnConnect {
+		return errors.New("mock.Connect() error")
+	}
+	return nil
+}
+
+func (m *mockSocket) Disconnect() error {
+	return nil
+}
+
+func (m *mockSocket) Command(command string, process socket.Processor) error {
+	if command != statsSocketCommand {
+		return errors.New("mock.Command() unexpected command")
+	}
+	sc := bufio.NewScanner(bytes.NewReader(m.resp))
+	for sc.Scan() && process(sc.Bytes()) {
+	}
+	return nil
+}
@@ -4,30 +4,46 @@ package haproxy
 
 import (
 	"errors"
+	"net/http"
 
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
 	"github.com/netdata/go.d.plugin/pkg/prometheus/selector"
+	"github.com/netdata/go.d.plugin/pkg/socket"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
-func (h Haproxy) validateConfig() error {
-	if h.URL == "" {
-		return errors.New("'url' is not set")
+func (h *Haproxy) validateConfig() error {
+	if h.URL == "" && h.StatsSocket == "" {
+		return errors.New("neither 'url' nor 'stats_socket' is set")
 	}
-	if _, err := web.NewHTTPRequest(h.Request); err != nil {
-		return err
+	if h.URL != "" {
+		if _, err := web.NewHTTPRequest(h.Request); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (h Haproxy) initPrometheusClient() (prometheus.Prometheus, error) {
-	httpClient, err := web.NewHTTPClient(h.Client)
-	if err != nil {
-		return nil, err
+func (h *Haproxy) initPrometheusClient(httpClient *http.Client) prometheus.Prometheus {
+	return prometheus.NewWithSelector(httpClient, h.Request, sr)
+}
+
+func (h *Haproxy) initStatsSocketClient() socket.Client {
+	return socket.New(socket.Config{
+		Address:        h.StatsSocket,
+		ConnectTimeout: h.Timeout.Duration,
+		ReadTimeout:    h.Timeout.Duration,
+		WriteTimeout:   h.Timeout.Duration,
+	})
+}
+
+func (h *Haproxy) initBackendSelector() (matcher.Matcher, error) {
+	if h.BackendSelector == "" {
+		return nil, nil
 	}
 
-	prom := prometheus.NewWithSelector(httpClient, h.Request, sr)
-	return prom, nil
+	return matcher.NewSimplePatternsMatcher(h.BackendSelector)
 }
 
 var sr, _ = selector.Expr{
@@ -40,5 +56,12 @@ var sr, _ = selector.Expr{
 		metricBackendSessionsTotal,
 		metricBackendCurrentSessions,
 		metricBackendBytesOutTotal,
+
+		metricServerCurrentSessions,
+		metricServerCurrentQueue,
+		metricServerResponseTimeAverageSeconds,
+		metricServerWeight,
+		metricServerStatus,
+		metricServerCheckStatus,
 	},
 }.Parse()
@@ -29,6 +29,10 @@ var responseTimeChart = module.Chart{
 	Priority: prioResponseTime,
 	Dims: module.Dims{
 		{ID: "time"},
+		{ID: "dns_lookup", Name: "dns"},
+		{ID: "connect"},
+		{ID: "tls_handshake", Name: "tls"},
+		{ID: "ttfb"},
 	},
 }
 
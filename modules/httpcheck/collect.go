@@ -25,32 +25,29 @@ const (
 )
 
 func (hc *HTTPCheck) collect() (map[string]int64, error) {
-	req, err := web.NewHTTPRequest(hc.Request)
-	if err != nil {
-		return nil, fmt.Errorf("error on creating HTTP requests to %s : %v", hc.Request.URL, err)
-	}
-
 	if hc.CookieFile != "" {
 		if err := hc.readCookieFile(); err != nil {
 			return nil, fmt.Errorf("error on reading cookie file '%s': %v", hc.CookieFile, err)
 		}
 	}
 
-	start := time.Now()
-	resp, err := hc.httpClient.Do(req)
-	dur := time.Since(start)
-
-	defer closeBody(resp)
-
 	var mx metrics
+	vars := make(stepVars)
 
-	if hc.isError(err, resp) {
-		hc.Debug(err)
-		hc.collectErrResponse(&mx, err)
-	} else {
-		mx.ResponseTime = durationToMs(dur)
-		hc.collectOKResponse(&mx, resp)
+	ok := true
+	for i, st := range hc.steps {
+		var err error
+		if ok, err = hc.doStep(&mx, st, vars, i == 0); err != nil {
+			return nil, err
+		}
+		if !ok {
+			if len(hc.steps) > 1 {
+				hc.Debugf("%s failed, skipping the rest of the steps", st.name)
+			}
+			break
+		}
 	}
+	mx.Status.Success = ok
 
 	if hc.metrics.Status != mx.Status {
 		mx.InState = hc.UpdateEvery
@@ -62,8 +59,47 @@ func (hc *HTTPCheck) collect() (map[string]int64, error) {
 	return stm.ToMap(mx), nil
 }
 
-func (hc *HTTPCheck) isError(err error, resp *http.Response) bool {
-	return err != nil && !(errors.Is(err, web.ErrRedirectAttempted) && hc.acceptedStatuses[resp.StatusCode])
+// doStep makes the step request and checks the response, it returns false if the step failed.
+// The response time and its breakdown are of the first step.
+func (hc *HTTPCheck) doStep(mx *metrics, st *step, vars stepVars, first bool) (bool, error) {
+	cfg := st.req
+	if !first {
+		// the job request is sent as is, there is nothing captured before it
+		cfg = vars.expand(cfg)
+	}
+
+	req, err := web.NewHTTPRequest(cfg)
+	if err != nil {
+		return false, fmt.Errorf("error on creating HTTP requests to %s : %v", st.req.URL, vars.redact(err.Error()))
+	}
+
+	var trace *requestTrace
+	if first {
+		trace = &requestTrace{}
+		req = trace.withTrace(req)
+	}
+
+	start := time.Now()
+	resp, err := hc.httpClient.Do(req)
+	dur := time.Since(start)
+
+	defer closeBody(resp)
+
+	if isError(err, resp, st) {
+		hc.Debugf("%s: %s", st.name, vars.redact(err.Error()))
+		hc.collectErrResponse(mx, err)
+		return false, nil
+	}
+
+	if first {
+		mx.ResponseTime = dur
+		trace.collect(mx, start)
+	}
+	return hc.collectOKResponse(mx, st, resp, vars, first), nil
+}
+
+func isError(err error, resp *http.Response, st *step) bool {
+	return err != nil && !(errors.Is(err, web.ErrRedirectAttempted) && st.acceptedStatuses[resp.StatusCode])
 }
 
 func (hc *HTTPCheck) collectErrResponse(mx *metrics, err error) {
@@ -79,39 +115,48 @@ func (hc *HTTPCheck) collectErrResponse(mx *metrics, err error) {
 	}
 }
 
-func (hc *HTTPCheck) collectOKResponse(mx *metrics, resp *http.Response) {
-	hc.Debugf("endpoint '%s' returned %d (%s) HTTP status code", hc.URL, resp.StatusCode, resp.Status)
+func (hc *HTTPCheck) collectOKResponse(mx *metrics, st *step, resp *http.Response, vars stepVars, first bool) bool {
+	// the URL is logged unexpanded, the captured values are not logged
+	hc.Debugf("%s: endpoint '%s' returned %d (%s) HTTP status code", st.name, st.req.URL, resp.StatusCode, resp.Status)
 
-	if !hc.acceptedStatuses[resp.StatusCode] {
+	if !st.acceptedStatuses[resp.StatusCode] {
 		mx.Status.BadStatusCode = true
-		return
+		return false
 	}
 
 	bs, err := io.ReadAll(resp.Body)
 	// golang net/http closes body on redirect
 	if err != nil && !errors.Is(err, io.EOF) && !strings.Contains(err.Error(), "read on closed response body") {
-		hc.Warningf("error on reading body : %v", err)
+		hc.Warningf("%s: error on reading body : %v", st.name, vars.redact(err.Error()))
 		mx.Status.BadContent = true
-		return
+		return false
 	}
 
-	mx.ResponseLength = len(bs)
+	if first {
+		mx.ResponseLength = len(bs)
+	}
 
-	if hc.reResponse != nil && !hc.reResponse.Match(bs) {
+	if st.reResponse != nil && !st.reResponse.Match(bs) {
 		mx.Status.BadContent = true
-		return
+		return false
 	}
 
-	if ok := hc.checkHeader(resp); !ok {
+	if ok := hc.checkHeader(st, resp); !ok {
 		mx.Status.BadHeader = true
-		return
+		return false
+	}
+
+	if err := st.capture(bs, vars); err != nil {
+		hc.Debugf("%s: %v", st.name, err)
+		mx.Status.BadContent = true
+		return false
 	}
 
-	mx.Status.Success = true
+	return true
 }
 
-func (hc *HTTPCheck) checkHeader(resp *http.Response) bool {
-	for _, m := range hc.headerMatch {
+func (hc *HTTPCheck) checkHeader(st *step, resp *http.Response) bool {
+	for _, m := range st.headerMatch {
 		value := resp.Header.Get(m.key)
 
 		var ok bool
@@ -183,7 +228,3 @@ func closeBody(resp *http.Response) {
 	_, _ = io.Copy(io.Discard, resp.Body)
 	_ = resp.Body.Close()
 }
-
-func durationToMs(duration time.Duration) int {
-	return int(duration) / (int(time.Millisecond) / int(time.Nanosecond))
-}
@@ -5,7 +5,6 @@ package httpcheck
 import (
 	_ "embed"
 	"net/http"
-	"regexp"
 	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/web"
@@ -36,7 +35,6 @@ func New() *HTTPCheck {
 			},
 			AcceptedStatuses: []int{200},
 		},
-		acceptedStatuses: make(map[int]bool),
 	}
 }
 
@@ -48,12 +46,28 @@ type (
 		ResponseMatch    string              `yaml:"response_match"`
 		CookieFile       string              `yaml:"cookie_file"`
 		HeaderMatch      []HeaderMatchConfig `yaml:"header_match"`
+		Capture          []CaptureConfig     `yaml:"capture"`
+		Steps            []StepConfig        `yaml:"steps"`
 	}
 	HeaderMatchConfig struct {
 		Exclude bool   `yaml:"exclude"`
 		Key     string `yaml:"key"`
 		Value   string `yaml:"value"`
 	}
+	// StepConfig is a request made after the job request, a failed step skips the rest.
+	StepConfig struct {
+		Name             string `yaml:"name"`
+		web.Request      `yaml:",inline"`
+		AcceptedStatuses []int           `yaml:"status_accepted"`
+		ResponseMatch    string          `yaml:"response_match"`
+		Capture          []CaptureConfig `yaml:"capture"`
+	}
+	// CaptureConfig saves a value of the response as a variable, the next steps refer to it as ${name}.
+	CaptureConfig struct {
+		Name     string `yaml:"name"`
+		Regexp   string `yaml:"regexp"`
+		JSONPath string `yaml:"json_path"`
+	}
 )
 
 type HTTPCheck struct {
@@ -64,9 +78,7 @@ type HTTPCheck struct {
 
 	charts *module.Charts
 
-	acceptedStatuses map[int]bool
-	reResponse       *regexp.Regexp
-	headerMatch      []headerMatch
+	steps []*step
 
 	cookieFileModTime time.Time
 
@@ -88,29 +100,21 @@ func (hc *HTTPCheck) Init() bool {
 	}
 	hc.httpClient = httpClient
 
-	re, err := hc.initResponseMatchRegexp()
+	steps, err := hc.initSteps()
 	if err != nil {
-		hc.Errorf("init response match regexp: %v", err)
+		hc.Errorf("init steps: %v", err)
 		return false
 	}
-	hc.reResponse = re
-
-	hm, err := hc.initHeaderMatch()
-	if err != nil {
-		hc.Errorf("init header match: %v", err)
-		return false
-	}
-	hc.headerMatch = hm
-
-	for _, v := range hc.AcceptedStatuses {
-		hc.acceptedStatuses[v] = true
-	}
+	hc.steps = steps
 
 	hc.Debugf("using URL %s", hc.URL)
 	hc.Debugf("using HTTP timeout %s", hc.Timeout.Duration)
 	hc.Debugf("using accepted HTTP statuses %v", hc.AcceptedStatuses)
-	if hc.reResponse != nil {
-		hc.Debugf("using response match regexp %s", hc.reResponse)
+	if hc.ResponseMatch != "" {
+		hc.Debugf("using response match regexp %s", hc.ResponseMatch)
+	}
+	if len(hc.Steps) > 0 {
+		hc.Debugf("using %d additional steps", len(hc.Steps))
 	}
 
 	return true
@@ -153,6 +153,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -160,6 +162,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       1,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"timeout case": {
@@ -168,6 +172,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        0,
 				"no_connection": 0,
@@ -175,6 +181,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       1,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"redirect success case": {
@@ -183,6 +191,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        0,
 				"no_connection": 0,
@@ -190,6 +200,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       1,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"redirect fail case": {
@@ -198,6 +210,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        0,
 				"no_connection": 0,
@@ -205,6 +219,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"bad status case": {
@@ -213,6 +229,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    1,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        0,
 				"no_connection": 0,
@@ -220,6 +238,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"bad content case": {
@@ -228,6 +248,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   1,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        17,
 				"no_connection": 0,
@@ -235,6 +257,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"no connection case": {
@@ -243,6 +267,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        0,
 				"no_connection": 1,
@@ -250,6 +276,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"header match include no value success case": {
@@ -263,6 +291,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -270,6 +300,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       1,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"header match include with value success case": {
@@ -283,6 +315,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -290,6 +324,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       1,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"header match include no value bad headers case": {
@@ -303,6 +339,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    1,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -310,6 +348,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"header match include with value bad headers case": {
@@ -323,6 +363,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    1,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -330,6 +372,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"header match exclude no value success case": {
@@ -343,6 +387,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -350,6 +396,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       1,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"header match exclude with value success case": {
@@ -363,6 +411,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -370,6 +420,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       1,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"header match exclude no value bad headers case": {
@@ -383,6 +435,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    1,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -390,6 +444,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"header match exclude with value bad headers case": {
@@ -403,6 +459,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    1,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        5,
 				"no_connection": 0,
@@ -410,6 +468,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       0,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 		"cookie auth case": {
@@ -418,6 +478,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"bad_content":   0,
 				"bad_header":    0,
 				"bad_status":    0,
+				"connect":       0,
+				"dns_lookup":    0,
 				"in_state":      2,
 				"length":        0,
 				"no_connection": 0,
@@ -425,6 +487,8 @@ func TestHTTPCheck_Collect(t *testing.T) {
 				"success":       1,
 				"time":          0,
 				"timeout":       0,
+				"tls_handshake": 0,
+				"ttfb":          0,
 			},
 		},
 	}
@@ -454,6 +518,183 @@ func TestHTTPCheck_Collect(t *testing.T) {
 	}
 }
 
+func TestHTTPCheck_Init_Steps(t *testing.T) {
+	tests := map[string]struct {
+		wantFail bool
+		steps    []StepConfig
+		capture  []CaptureConfig
+	}{
+		"captured variable": {
+			capture: []CaptureConfig{{Name: "token", JSONPath: "data.token"}},
+			steps: []StepConfig{
+				{Request: web.Request{URL: "http://127.0.0.1:38001/${token}"}},
+			},
+		},
+		"variable captured by a later step": {
+			wantFail: true,
+			steps: []StepConfig{
+				{Request: web.Request{URL: "http://127.0.0.1:38001/${token}"}},
+				{
+					Request: web.Request{URL: "http://127.0.0.1:38001"},
+					Capture: []CaptureConfig{{Name: "token", Regexp: "(.+)"}},
+				},
+			},
+		},
+		"step without url": {
+			wantFail: true,
+			steps:    []StepConfig{{Name: "api"}},
+		},
+		"capture with both regexp and json path": {
+			wantFail: true,
+			capture:  []CaptureConfig{{Name: "token", Regexp: "(.+)", JSONPath: "token"}},
+		},
+		"capture with bad name": {
+			wantFail: true,
+			capture:  []CaptureConfig{{Name: "a-token", Regexp: "(.+)"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			httpCheck := New()
+			httpCheck.URL = "http://127.0.0.1:38001/login"
+			httpCheck.Capture = test.capture
+			httpCheck.Steps = test.steps
+
+			if test.wantFail {
+				assert.False(t, httpCheck.Init())
+			} else {
+				assert.True(t, httpCheck.Init())
+			}
+		})
+	}
+}
+
+func TestHTTPCheck_Collect_Steps(t *testing.T) {
+	tests := map[string]struct {
+		login       string
+		capture     CaptureConfig
+		wantStatus  string
+		wantAPICall bool
+	}{
+		"token captured by json path": {
+			login:       `{"data":{"token":"s3cr3t"}}`,
+			capture:     CaptureConfig{Name: "token", JSONPath: "data.token"},
+			wantStatus:  "success",
+			wantAPICall: true,
+		},
+		"token captured by regexp": {
+			login:       `{"data":{"token":"s3cr3t"}}`,
+			capture:     CaptureConfig{Name: "token", Regexp: `"token":"([^"]+)"`},
+			wantStatus:  "success",
+			wantAPICall: true,
+		},
+		"wrong token": {
+			login:       `{"data":{"token":"expired"}}`,
+			capture:     CaptureConfig{Name: "token", JSONPath: "data.token"},
+			wantStatus:  "bad_status",
+			wantAPICall: true,
+		},
+		"nothing to capture": {
+			login:       `{"error":"bad credentials"}`,
+			capture:     CaptureConfig{Name: "token", JSONPath: "data.token"},
+			wantStatus:  "bad_content",
+			wantAPICall: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var apiCalled bool
+			srv := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/login":
+						if r.Method != http.MethodPost {
+							w.WriteHeader(http.StatusMethodNotAllowed)
+							return
+						}
+						_, _ = w.Write([]byte(test.login))
+					case "/api":
+						apiCalled = true
+						if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+							w.WriteHeader(http.StatusUnauthorized)
+							return
+						}
+						_, _ = w.Write([]byte("ok"))
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+				}))
+			defer srv.Close()
+
+			httpCheck := New()
+			httpCheck.URL = srv.URL + "/login"
+			httpCheck.Method = http.MethodPost
+			httpCheck.Capture = []CaptureConfig{test.capture}
+			httpCheck.Steps = []StepConfig{
+				{
+					Name: "api",
+					Request: web.Request{
+						URL:     srv.URL + "/api",
+						Headers: map[string]string{"Authorization": "Bearer ${token}"},
+					},
+					ResponseMatch: "^ok$",
+				},
+			}
+			require.True(t, httpCheck.Init())
+
+			mx := httpCheck.Collect()
+			require.NotNil(t, mx)
+
+			for _, dim := range []string{"success", "no_connection", "timeout", "redirect", "bad_content", "bad_status", "bad_header"} {
+				assert.Equalf(t, dim == test.wantStatus, mx[dim] == 1, "status dimension '%s'", dim)
+			}
+			assert.Equal(t, test.wantAPICall, apiCalled)
+			assert.Equal(t, int64(len(test.login)), mx["length"])
+		})
+	}
+}
+
+func TestStepVars_Redact(t *testing.T) {
+	vars := stepVars{"token": "a b/c", "id": "42", "empty": ""}
+
+	err := `Get "http://127.0.0.1/api/42?t=a+b%2Fc": dial tcp: connection refused, token 'a b/c'`
+
+	assert.Equal(t,
+		`Get "http://127.0.0.1/api/<redacted>?t=<redacted>": dial tcp: connection refused, token '<redacted>'`,
+		vars.redact(err),
+	)
+}
+
+func TestRequestTrace(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	var trace requestTrace
+	start := time.Now()
+	resp, err := srv.Client().Do(trace.withTrace(req))
+	require.NoError(t, err)
+	closeBody(resp)
+
+	assert.False(t, trace.connectDone.Before(trace.connectStart))
+	assert.False(t, trace.connectStart.IsZero())
+	assert.False(t, trace.tlsStart.IsZero())
+	assert.False(t, trace.tlsDone.Before(trace.tlsStart))
+	assert.False(t, trace.firstByte.IsZero())
+
+	var mx metrics
+	trace.collect(&mx, start)
+	assert.True(t, mx.TTFB > 0)
+	assert.True(t, mx.TTFB >= mx.Connect+mx.TLSHandshake)
+}
+
 func prepareSuccessCase() (*HTTPCheck, func()) {
 	httpCheck := New()
 	httpCheck.UpdateEvery = 1
@@ -576,9 +817,11 @@ func prepareCookieAuthCase() (*HTTPCheck, func()) {
 }
 
 func copyResponseTime(dst, src map[string]int64) {
-	if v, ok := src["time"]; ok {
-		if _, ok := dst["time"]; ok {
-			dst["time"] = v
+	for _, key := range []string{"time", "dns_lookup", "connect", "tls_handshake", "ttfb"} {
+		if v, ok := src[key]; ok {
+			if _, ok := dst[key]; ok {
+				dst[key] = v
+			}
 		}
 	}
 }
@@ -37,6 +37,111 @@ func (hc *HTTPCheck) initResponseMatchRegexp() (*regexp.Regexp, error) {
 	return regexp.Compile(hc.ResponseMatch)
 }
 
+// initSteps returns the job request as the first step followed by the configured steps.
+func (hc *HTTPCheck) initSteps() ([]*step, error) {
+	re, err := hc.initResponseMatchRegexp()
+	if err != nil {
+		return nil, fmt.Errorf("response match regexp: %v", err)
+	}
+	hm, err := hc.initHeaderMatch()
+	if err != nil {
+		return nil, fmt.Errorf("header match: %v", err)
+	}
+	captures, err := initCaptures(hc.Capture)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []*step{{
+		name:             "step 1",
+		req:              hc.Request,
+		acceptedStatuses: statusesSet(hc.AcceptedStatuses),
+		reResponse:       re,
+		headerMatch:      hm,
+		captures:         captures,
+	}}
+
+	captured := make(map[string]bool)
+	for _, c := range captures {
+		captured[c.name] = true
+	}
+
+	for i, cfg := range hc.Steps {
+		st := &step{name: cfg.Name, req: cfg.Request}
+		if st.name == "" {
+			st.name = fmt.Sprintf("step %d", i+2)
+		}
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("step '%s': 'url' not set", st.name)
+		}
+		for _, ref := range stepRefs(cfg.Request) {
+			if !captured[ref] {
+				return nil, fmt.Errorf("step '%s': variable '%s' is not captured by the previous steps", st.name, ref)
+			}
+		}
+		if st.req.ProxyUsername == "" && st.req.ProxyPassword == "" {
+			st.req.ProxyUsername, st.req.ProxyPassword = hc.ProxyUsername, hc.ProxyPassword
+		}
+
+		statuses := cfg.AcceptedStatuses
+		if len(statuses) == 0 {
+			statuses = []int{200}
+		}
+		st.acceptedStatuses = statusesSet(statuses)
+
+		if cfg.ResponseMatch != "" {
+			if st.reResponse, err = regexp.Compile(cfg.ResponseMatch); err != nil {
+				return nil, fmt.Errorf("step '%s': response match regexp: %v", st.name, err)
+			}
+		}
+		if st.captures, err = initCaptures(cfg.Capture); err != nil {
+			return nil, fmt.Errorf("step '%s': %v", st.name, err)
+		}
+		for _, c := range st.captures {
+			captured[c.name] = true
+		}
+
+		steps = append(steps, st)
+	}
+
+	return steps, nil
+}
+
+func initCaptures(cfgs []CaptureConfig) ([]capture, error) {
+	var captures []capture
+
+	for _, cfg := range cfgs {
+		if !reStepVarName.MatchString(cfg.Name) {
+			return nil, fmt.Errorf("capture: bad variable name '%s' (letters, digits and underscores are allowed)", cfg.Name)
+		}
+		if (cfg.Regexp == "") == (cfg.JSONPath == "") {
+			return nil, fmt.Errorf("capture '%s': either 'regexp' or 'json_path' must be set", cfg.Name)
+		}
+
+		c := capture{name: cfg.Name}
+		var err error
+		if cfg.Regexp != "" {
+			if c.re, err = regexp.Compile(cfg.Regexp); err != nil {
+				return nil, fmt.Errorf("capture '%s': %v", cfg.Name, err)
+			}
+		} else if c.jsonPath, err = parseJSONPath(cfg.JSONPath); err != nil {
+			return nil, fmt.Errorf("capture '%s': %v", cfg.Name, err)
+		}
+
+		captures = append(captures, c)
+	}
+
+	return captures, nil
+}
+
+func statusesSet(statuses []int) map[int]bool {
+	set := make(map[int]bool, len(statuses))
+	for _, v := range statuses {
+		set[v] = true
+	}
+	return set
+}
+
 func (hc *HTTPCheck) initHeaderMatch() ([]headerMatch, error) {
 	if len(hc.HeaderMatch) == 0 {
 		return nil, nil
@@ -2,11 +2,17 @@
 
 package httpcheck
 
+import "time"
+
 type metrics struct {
-	Status         status `stm:""`
-	InState        int    `stm:"in_state"`
-	ResponseTime   int    `stm:"time"`
-	ResponseLength int    `stm:"length"`
+	Status         status        `stm:""`
+	InState        int           `stm:"in_state"`
+	ResponseTime   time.Duration `stm:"time,ms"`
+	ResponseLength int           `stm:"length"`
+	DNSLookup      time.Duration `stm:"dns_lookup,ms"`
+	Connect        time.Duration `stm:"connect,ms"`
+	TLSHandshake   time.Duration `stm:"tls_handshake,ms"`
+	TTFB           time.Duration `stm:"ttfb,ms"`
 }
 
 type status struct {
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package httpcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+)
+
+// reStepVar matches a variable reference: ${name}.
+var reStepVar = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)}`)
+
+var reStepVarName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+type (
+	// step is a request of the check. The first step is the job request, the rest are the 'steps' in order.
+	step struct {
+		name             string
+		req              web.Request
+		acceptedStatuses map[int]bool
+		reResponse       *regexp.Regexp
+		headerMatch      []headerMatch
+		captures         []capture
+	}
+	capture struct {
+		name     string
+		re       *regexp.Regexp
+		jsonPath []string
+	}
+	// stepVars are the values captured during a collection, they are often secrets (tokens, session ids).
+	stepVars map[string]string
+)
+
+// expand substitutes the captured variables in the request URL, body, headers and credentials.
+func (v stepVars) expand(req web.Request) web.Request {
+	r := req.Copy()
+	r.URL = v.expandString(r.URL)
+	r.Body = v.expandString(r.Body)
+	r.Username = v.expandString(r.Username)
+	r.Password = v.expandString(r.Password)
+	for k, val := range r.Headers {
+		r.Headers[k] = v.expandString(val)
+	}
+	return r
+}
+
+func (v stepVars) expandString(s string) string {
+	return reStepVar.ReplaceAllStringFunc(s, func(ref string) string {
+		return v[reStepVar.FindStringSubmatch(ref)[1]]
+	})
+}
+
+// redact hides the captured values in s, including their URL escaped forms (net/http errors contain the request URL).
+func (v stepVars) redact(s string) string {
+	var values []string
+	for _, val := range v {
+		if val == "" {
+			continue
+		}
+		values = append(values, val, url.QueryEscape(val), url.PathEscape(val))
+	}
+	// the longest first, a value can be a part of another
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	for _, val := range values {
+		s = strings.ReplaceAll(s, val, "<redacted>")
+	}
+	return s
+}
+
+func (st *step) capture(body []byte, vars stepVars) error {
+	for _, c := range st.captures {
+		v, err := c.value(body)
+		if err != nil {
+			return fmt.Errorf("capture '%s': %v", c.name, err)
+		}
+		vars[c.name] = v
+	}
+	return nil
+}
+
+func (c capture) value(body []byte) (string, error) {
+	if c.re != nil {
+		m := c.re.FindSubmatch(body)
+		if m == nil {
+			return "", errors.New("regexp does not match the response")
+		}
+		// the first capture group if any, the whole match otherwise
+		if len(m) > 1 {
+			return string(m[1]), nil
+		}
+		return string(m[0]), nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", fmt.Errorf("error on decoding the response: %v", err)
+	}
+	for _, key := range c.jsonPath {
+		switch x := v.(type) {
+		case map[string]interface{}:
+			val, ok := x[key]
+			if !ok {
+				return "", fmt.Errorf("json path: key '%s' not found", key)
+			}
+			v = val
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(x) {
+				return "", fmt.Errorf("json path: bad array index '%s'", key)
+			}
+			v = x[i]
+		default:
+			return "", fmt.Errorf("json path: '%s' is not an object or array", key)
+		}
+	}
+
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	case nil:
+		return "", errors.New("json path: the value is null")
+	default:
+		bs, err := json.Marshal(x)
+		return string(bs), err
+	}
+}
+
+// parseJSONPath splits a dot separated path ('data.token', 'items.0.id'), the leading '$.' is optional.
+func parseJSONPath(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return nil, errors.New("empty json path")
+	}
+	keys := strings.Split(path, ".")
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("bad json path '%s'", path)
+		}
+	}
+	return keys, nil
+}
+
+// stepRefs returns the variables the request refers to.
+func stepRefs(req web.Request) []string {
+	values := []string{req.URL, req.Body, req.Username, req.Password}
+	for _, v := range req.Headers {
+		values = append(values, v)
+	}
+
+	var refs []string
+	for _, v := range values {
+		for _, m := range reStepVar.FindAllStringSubmatch(v, -1) {
+			refs = append(refs, m[1])
+		}
+	}
+	return refs
+}
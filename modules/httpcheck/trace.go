@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package httpcheck
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// requestTrace records the first occurrence of the request phases. The phases of a reused (keep-alive) connection
+// don't happen, they are zero. The dialer may still be connecting (a losing parallel dial) when the response is received.
+type requestTrace struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+func (t *requestTrace) withTrace(req *http.Request) *http.Request {
+	ct := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart: func(_, _ string) {
+			t.mark(&t.connectStart)
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.mark(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				t.mark(&t.tlsDone)
+			}
+		},
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), ct))
+}
+
+func (t *requestTrace) mark(v *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if v.IsZero() {
+		*v = time.Now()
+	}
+}
+
+func (t *requestTrace) collect(mx *metrics, start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	mx.DNSLookup = since(t.dnsStart, t.dnsDone)
+	mx.Connect = since(t.connectStart, t.connectDone)
+	mx.TLSHandshake = since(t.tlsStart, t.tlsDone)
+	mx.TTFB = since(start, t.firstByte)
+}
+
+func since(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -86,6 +86,14 @@ const (
 	prioShardingNodesCount
 	prioShardingShardedDatabasesCount
 	prioShardingShardedCollectionsCount
+	prioShardingCatalogCacheEntries
+	prioShardingCatalogCacheRefreshesRate
+	prioShardingCatalogCacheRefreshWaitTime
+	prioShardingCatalogCacheStaleConfigErrorsRate
+	prioShardingBalancerRoundsRate
+	prioShardingBalancerMigrationsRate
+	prioShardConnPoolConnections
+	prioShardConnPoolCreatedRate
 	prioShardChunks
 )
 
@@ -141,8 +149,14 @@ var chartsSharding = module.Charts{
 	chartShardingShardedCollectionsCount.Copy(),
 }
 
+var chartsShardingBalancer = module.Charts{
+	chartShardingBalancerRoundsRate.Copy(),
+	chartShardingBalancerMigrationsRate.Copy(),
+}
+
 var chartsTmplShardingShard = module.Charts{
-	chartTmplShardChunks.Copy(),
+	chartTmplShardConnPoolConnections.Copy(),
+	chartTmplShardConnPoolCreatedRate.Copy(),
 }
 
 var (
@@ -1022,6 +1036,104 @@ var (
 		},
 	}
 
+	chartShardingCatalogCacheEntries = module.Chart{
+		ID:       "sharding_catalog_cache_entries",
+		Title:    "Sharding catalog cache entries",
+		Units:    "entries",
+		Fam:      "sharding",
+		Ctx:      "mongodb.sharding_catalog_cache_entries",
+		Priority: prioShardingCatalogCacheEntries,
+		Dims: module.Dims{
+			{ID: "sharding_catalog_cache_database_entries", Name: "databases"},
+			{ID: "sharding_catalog_cache_collection_entries", Name: "collections"},
+		},
+	}
+	chartShardingCatalogCacheRefreshesRate = module.Chart{
+		ID:       "sharding_catalog_cache_refreshes_rate",
+		Title:    "Sharding catalog cache refreshes",
+		Units:    "refreshes/s",
+		Fam:      "sharding",
+		Ctx:      "mongodb.sharding_catalog_cache_refreshes_rate",
+		Priority: prioShardingCatalogCacheRefreshesRate,
+		Dims: module.Dims{
+			{ID: "sharding_catalog_cache_incremental_refreshes", Name: "incremental", Algo: module.Incremental},
+			{ID: "sharding_catalog_cache_full_refreshes", Name: "full", Algo: module.Incremental},
+			{ID: "sharding_catalog_cache_failed_refreshes", Name: "failed", Algo: module.Incremental},
+		},
+	}
+	chartShardingCatalogCacheRefreshWaitTime = module.Chart{
+		ID:       "sharding_catalog_cache_refresh_wait_time",
+		Title:    "Sharding catalog cache refresh wait time",
+		Units:    "milliseconds",
+		Fam:      "sharding",
+		Ctx:      "mongodb.sharding_catalog_cache_refresh_wait_time",
+		Priority: prioShardingCatalogCacheRefreshWaitTime,
+		Dims: module.Dims{
+			{ID: "sharding_catalog_cache_refresh_wait_time_micros", Name: "wait", Algo: module.Incremental, Div: 1000},
+		},
+	}
+	chartShardingCatalogCacheStaleConfigErrorsRate = module.Chart{
+		ID:       "sharding_catalog_cache_stale_config_errors_rate",
+		Title:    "Sharding catalog cache stale config errors",
+		Units:    "errors/s",
+		Fam:      "sharding",
+		Ctx:      "mongodb.sharding_catalog_cache_stale_config_errors_rate",
+		Priority: prioShardingCatalogCacheStaleConfigErrorsRate,
+		Dims: module.Dims{
+			{ID: "sharding_catalog_cache_stale_config_errors", Name: "stale_config", Algo: module.Incremental},
+		},
+	}
+
+	chartShardingBalancerRoundsRate = &module.Chart{
+		ID:       "sharding_balancer_rounds_rate",
+		Title:    "Sharding balancer rounds",
+		Units:    "rounds/s",
+		Fam:      "sharding",
+		Ctx:      "mongodb.sharding_balancer_rounds_rate",
+		Priority: prioShardingBalancerRoundsRate,
+		Dims: module.Dims{
+			{ID: "shard_balancer_rounds", Name: "rounds", Algo: module.Incremental},
+			{ID: "shard_balancer_rounds_failed", Name: "failed", Algo: module.Incremental},
+		},
+	}
+	chartShardingBalancerMigrationsRate = &module.Chart{
+		ID:       "sharding_balancer_migrations_rate",
+		Title:    "Sharding balancer chunk migrations",
+		Units:    "migrations/s",
+		Fam:      "sharding",
+		Ctx:      "mongodb.sharding_balancer_migrations_rate",
+		Priority: prioShardingBalancerMigrationsRate,
+		Dims: module.Dims{
+			{ID: "shard_balancer_chunks_moved", Name: "moved", Algo: module.Incremental},
+		},
+	}
+
+	chartTmplShardConnPoolConnections = &module.Chart{
+		ID:       chartPxShard + "%s_conn_pool_connections",
+		Title:    "Shard connection pool connections",
+		Units:    "connections",
+		Fam:      "sharding",
+		Ctx:      "mongodb.sharding_shard_conn_pool_connections",
+		Type:     module.Stacked,
+		Priority: prioShardConnPoolConnections,
+		Dims: module.Dims{
+			{ID: "shard_id_%s_conn_pool_in_use", Name: "in_use"},
+			{ID: "shard_id_%s_conn_pool_available", Name: "available"},
+			{ID: "shard_id_%s_conn_pool_refreshing", Name: "refreshing"},
+		},
+	}
+	chartTmplShardConnPoolCreatedRate = &module.Chart{
+		ID:       chartPxShard + "%s_conn_pool_created_rate",
+		Title:    "Shard connection pool created connections",
+		Units:    "connections/s",
+		Fam:      "sharding",
+		Ctx:      "mongodb.sharding_shard_conn_pool_created_rate",
+		Priority: prioShardConnPoolCreatedRate,
+		Dims: module.Dims{
+			{ID: "shard_id_%s_conn_pool_created", Name: "created", Algo: module.Incremental},
+		},
+	}
+
 	chartTmplShardChunks = &module.Chart{
 		ID:       chartPxShard + "%s_chunks",
 		Title:    "Shard chunks",
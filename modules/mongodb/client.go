@@ -14,6 +14,8 @@ import (
 
 const (
 	mongos = "mongos"
+	// 'msg' field of the 'hello' response on mongos
+	helloMsgMongos = "isdbgrid"
 )
 
 type mongoConn interface {
@@ -26,7 +28,10 @@ type mongoConn interface {
 	shardNodes() (*documentShardNodesResult, error)
 	shardDatabasesPartitioning() (*documentPartitionedResult, error)
 	shardCollectionsPartitioning() (*documentPartitionedResult, error)
-	shardChunks() (map[string]int64, error)
+	shardList() ([]documentShard, error)
+	shardChunks(timeout time.Duration) (map[string]int64, error)
+	balancerRounds(since time.Time, timeout time.Duration) ([]documentBalancerRound, error)
+	connPoolStats() (*documentConnPoolStats, error)
 	initClient(uri string, timeout time.Duration) error
 	close() error
 }
@@ -76,8 +81,10 @@ func (c *mongoClient) serverStatus() (*documentServerStatus, error) {
 	isReplSet := status.Repl != nil
 	c.replicaSetFlag = &isReplSet
 
-	isMongos := status.Process == mongos
-	c.mongosFlag = &isMongos
+	if c.mongosFlag == nil {
+		isMongos := status.Process == mongos
+		c.mongosFlag = &isMongos
+	}
 
 	return status, err
 }
@@ -200,10 +207,30 @@ func (c *mongoClient) shardCollectAggregation(collection string, aggr []bson.D)
 	return result, err
 }
 
-func (c *mongoClient) shardChunks() (map[string]int64, error) {
+func (c *mongoClient) shardList() ([]documentShard, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*c.timeout)
 	defer cancel()
 
+	cursor, err := c.client.Database("config").Collection("shards").Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var shards []documentShard
+	if err := cursor.All(ctx, &shards); err != nil {
+		return nil, err
+	}
+
+	return shards, nil
+}
+
+// shardChunks counts the chunks per shard, the aggregation is slow on large clusters and has its own timeout.
+func (c *mongoClient) shardChunks(timeout time.Duration) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*timeout)
+	defer cancel()
+
 	col := c.client.Database("config").Collection("chunks")
 
 	cursor, err := col.Aggregate(ctx, mongo.Pipeline{bson.D{{Key: "$sortByCount", Value: "$shard"}}})
@@ -235,6 +262,46 @@ func (c *mongoClient) shardChunks() (map[string]int64, error) {
 	return result, err
 }
 
+// balancerRounds returns the balancer rounds logged after 'since', oldest first.
+func (c *mongoClient) balancerRounds(since time.Time, timeout time.Duration) ([]documentBalancerRound, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*timeout)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "what", Value: "balancer.round"},
+		{Key: "time", Value: bson.D{{Key: "$gt", Value: since}}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}})
+
+	cursor, err := c.client.Database("config").Collection("actionlog").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var rounds []documentBalancerRound
+	if err := cursor.All(ctx, &rounds); err != nil {
+		return nil, err
+	}
+
+	return rounds, nil
+}
+
+func (c *mongoClient) connPoolStats() (*documentConnPoolStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*c.timeout)
+	defer cancel()
+
+	var stats documentConnPoolStats
+	cmd := bson.M{"connPoolStats": 1}
+
+	if err := c.client.Database("admin").RunCommand(ctx, cmd).Decode(&stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
 func (c *mongoClient) initClient(uri string, timeout time.Duration) error {
 	if c.client != nil {
 		return nil
@@ -262,10 +329,32 @@ func (c *mongoClient) initClient(uri string, timeout time.Duration) error {
 	}
 
 	c.client = client
+	c.detectProcessType()
 
 	return nil
 }
 
+// detectProcessType uses 'hello' (MongoDB 4.4.2+) or the legacy 'isMaster' to find out whether it is a mongos.
+// If both fail, the process type is taken from the serverStatus output.
+func (c *mongoClient) detectProcessType() {
+	for _, name := range []string{"hello", "isMaster"} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*c.timeout)
+
+		var resp struct {
+			Msg string `bson:"msg"`
+		}
+		err := c.client.Database("admin").RunCommand(ctx, bson.D{{Key: name, Value: 1}}).Decode(&resp)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		isMongos := resp.Msg == helloMsgMongos
+		c.mongosFlag = &isMongos
+		return
+	}
+}
+
 func (c *mongoClient) close() error {
 	if c.client == nil {
 		return nil
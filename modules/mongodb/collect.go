@@ -34,10 +34,3 @@ func (m *Mongo) collect() (map[string]int64, error) {
 
 	return mx, nil
 }
-
-func boolToInt(v bool) int64 {
-	if v {
-		return 1
-	}
-	return 0
-}
@@ -5,8 +5,10 @@ package mongo
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/stm"
 )
 
 // https://www.mongodb.com/docs/manual/reference/replica-states/#replica-set-member-states
@@ -23,6 +25,17 @@ var replicaSetMemberStates = map[string]int{
 	"removed":    10,
 }
 
+type replSetMemberMetrics struct {
+	ReplicationLag   time.Duration   `stm:"replication_lag,ms"`
+	States           map[string]bool `stm:"state"`
+	HealthStatusUp   bool            `stm:"health_status_up"`
+	HealthStatusDown bool            `stm:"health_status_down"`
+	// the remote members only
+	Uptime           *int64         `stm:"uptime"`
+	HeartbeatLatency *time.Duration `stm:"heartbeat_latency,ms"`
+	PingRTT          *int64         `stm:"ping_rtt"`
+}
+
 // TODO: deal with duplicates if we collect metrics from all cluster nodes
 // should we only collect ReplSetStatus (at least by default) from primary nodes? (db.runCommand( { isMaster: 1 } ))
 func (m *Mongo) collectReplSetStatus(mx map[string]int64) error {
@@ -38,23 +51,27 @@ func (m *Mongo) collectReplSetStatus(mx map[string]int64) error {
 
 		px := fmt.Sprintf("repl_set_member_%s_", member.Name)
 
-		mx[px+"replication_lag"] = s.Date.Sub(member.OptimeDate).Milliseconds()
-
+		mm := replSetMemberMetrics{
+			ReplicationLag:   s.Date.Sub(member.OptimeDate),
+			States:           make(map[string]bool, len(replicaSetMemberStates)),
+			HealthStatusUp:   member.Health == 1,
+			HealthStatusDown: member.Health == 0,
+		}
 		for k, v := range replicaSetMemberStates {
-			mx[px+"state_"+k] = boolToInt(member.State == v)
+			mm.States[k] = member.State == v
 		}
 
-		mx[px+"health_status_up"] = boolToInt(member.Health == 1)
-		mx[px+"health_status_down"] = boolToInt(member.Health == 0)
-
 		if member.Self == nil {
-			mx[px+"uptime"] = member.Uptime
+			mm.Uptime = &member.Uptime
 			if v := member.LastHeartbeatRecv; v != nil && !v.IsZero() {
-				mx[px+"heartbeat_latency"] = s.Date.Sub(*v).Milliseconds()
-			}
-			if v := member.PingMs; v != nil {
-				mx[px+"ping_rtt"] = *v
+				latency := s.Date.Sub(*v)
+				mm.HeartbeatLatency = &latency
 			}
+			mm.PingRTT = member.PingMs
+		}
+
+		for k, v := range stm.ToMap(mm) {
+			mx[px+k] = v
 		}
 	}
 
@@ -102,6 +102,14 @@ func (m *Mongo) addOptionalCharts(s *documentServerStatus) {
 			&chartTransactionsRecoverWithTokenCommitsDurationTime,
 		)
 	}
+	if s.ShardingStatistics != nil {
+		m.addOptionalChart(s.ShardingStatistics.CatalogCache,
+			&chartShardingCatalogCacheEntries,
+			&chartShardingCatalogCacheRefreshesRate,
+			&chartShardingCatalogCacheRefreshWaitTime,
+			&chartShardingCatalogCacheStaleConfigErrorsRate,
+		)
+	}
 	if s.Locks != nil {
 		m.addOptionalChart(s.Locks.Global, &chartGlobalLockAcquisitionsRate)
 		m.addOptionalChart(s.Locks.Database, &chartDatabaseLockAcquisitionsRate)
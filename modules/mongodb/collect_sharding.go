@@ -5,6 +5,7 @@ package mongo
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
 )
@@ -34,16 +35,15 @@ func (m *Mongo) collectSharding(mx map[string]int64) error {
 	mx["shard_collections_partitioned"] = collPart.Partitioned
 	mx["shard_collections_unpartitioned"] = collPart.UnPartitioned
 
-	chunksPerShard, err := m.conn.shardChunks()
+	shards, err := m.conn.shardList()
 	if err != nil {
 		return err
 	}
 
 	seen := make(map[string]bool)
 
-	for shard, count := range chunksPerShard {
-		seen[shard] = true
-		mx["shard_id_"+shard+"_chunks"] = count
+	for _, shard := range shards {
+		seen[shard.ID] = true
 	}
 
 	for id := range seen {
@@ -60,12 +60,112 @@ func (m *Mongo) collectSharding(mx map[string]int64) error {
 		}
 	}
 
+	if err := m.collectShardConnPoolStats(mx, shards); err != nil {
+		return err
+	}
+
+	if m.CollectChunks {
+		m.collectShardChunks(mx)
+		m.collectBalancerRounds(mx)
+	}
+
+	return nil
+}
+
+func (m *Mongo) collectShardConnPoolStats(mx map[string]int64, shards []documentShard) error {
+	stats, err := m.conn.connPoolStats()
+	if err != nil {
+		return err
+	}
+
+	for _, shard := range shards {
+		px := "shard_id_" + shard.ID + "_conn_pool_"
+		var v documentConnPoolHostStats
+
+		for _, host := range shardHosts(shard.Host) {
+			hs, ok := stats.Hosts[host]
+			if !ok {
+				continue
+			}
+			v.InUse += hs.InUse
+			v.Available += hs.Available
+			v.Created += hs.Created
+			v.Refreshing += hs.Refreshing
+		}
+
+		mx[px+"in_use"] = v.InUse
+		mx[px+"available"] = v.Available
+		mx[px+"created"] = v.Created
+		mx[px+"refreshing"] = v.Refreshing
+	}
+
 	return nil
 }
 
+// collectShardChunks is opt-in, a failure doesn't fail the sharding metrics collection.
+func (m *Mongo) collectShardChunks(mx map[string]int64) {
+	chunksPerShard, err := m.conn.shardChunks(m.ChunksTimeout)
+	if err != nil {
+		m.Warningf("couldn't collect shard chunks: %v", err)
+		return
+	}
+
+	for id := range m.shards {
+		mx["shard_id_"+id+"_chunks"] = chunksPerShard[id]
+	}
+}
+
+// balancerStats accumulates the balancer rounds, 'config.actionlog' is a capped collection.
+type balancerStats struct {
+	lastRound    time.Time
+	rounds       int64
+	roundsFailed int64
+	chunksMoved  int64
+}
+
+// collectBalancerRounds is opt-in, a failure doesn't fail the sharding metrics collection.
+func (m *Mongo) collectBalancerRounds(mx map[string]int64) {
+	rounds, err := m.conn.balancerRounds(m.balancer.lastRound, m.ChunksTimeout)
+	if err != nil {
+		m.Warningf("couldn't collect balancer rounds: %v", err)
+		return
+	}
+
+	m.addBalancerChartsOnce.Do(m.addBalancerCharts)
+
+	for _, r := range rounds {
+		m.balancer.rounds++
+		if r.Details.ErrorOccurred {
+			m.balancer.roundsFailed++
+		}
+		m.balancer.chunksMoved += r.Details.ChunksMoved
+		if r.Time.After(m.balancer.lastRound) {
+			m.balancer.lastRound = r.Time
+		}
+	}
+
+	mx["shard_balancer_rounds"] = m.balancer.rounds
+	mx["shard_balancer_rounds_failed"] = m.balancer.roundsFailed
+	mx["shard_balancer_chunks_moved"] = m.balancer.chunksMoved
+}
+
+// shardHosts returns the hosts of the 'config.shards' host string: <replica set>/<host:port>,<host:port>.
+func shardHosts(s string) []string {
+	if i := strings.IndexByte(s, '/'); i != -1 {
+		s = s[i+1:]
+	}
+	return strings.Split(s, ",")
+}
+
 func (m *Mongo) addShardCharts(id string) {
 	charts := chartsTmplShardingShard.Copy()
 
+	if m.CollectChunks {
+		if err := charts.Add(chartTmplShardChunks.Copy()); err != nil {
+			m.Warning(err)
+		}
+	}
+
 	for _, chart := range *charts {
 		chart.ID = fmt.Sprintf(chart.ID, id)
 		chart.Labels = []module.Label{
@@ -79,7 +179,6 @@ func (m *Mongo) addShardCharts(id string) {
 	if err := m.Charts().Add(*charts...); err != nil {
 		m.Warning(err)
 	}
-
 }
 
 func (m *Mongo) removeShardCharts(id string) {
@@ -100,3 +199,11 @@ func (m *Mongo) addShardingCharts() {
 		m.Warning(err)
 	}
 }
+
+func (m *Mongo) addBalancerCharts() {
+	charts := chartsShardingBalancer.Copy()
+
+	if err := m.Charts().Add(*charts...); err != nil {
+		m.Warning(err)
+	}
+}
@@ -21,6 +21,8 @@ type documentServerStatus struct {
 	Locks        *documentLocks          `bson:"locks" stm:"locks"`
 	WiredTiger   *documentWiredTiger     `bson:"wiredTiger" stm:"wiredtiger"`
 	Repl         interface{}             `bson:"repl"`
+
+	ShardingStatistics *documentShardingStatistics `bson:"shardingStatistics" stm:"sharding"`
 }
 
 type (
@@ -220,6 +222,20 @@ type (
 			PagesWrittenFromCache    int `bson:"pages written from cache" stm:"written_from_cache_pages"`
 		} `bson:"cache" stm:"cache"`
 	}
+	// https://www.mongodb.com/docs/manual/reference/command/serverStatus/#shardingstatistics
+	documentShardingStatistics struct {
+		CatalogCache *documentCatalogCache `bson:"catalogCache" stm:"catalog_cache"`
+	}
+	// The catalog cache has no hit/miss counters, a refresh is a cache miss or an invalidation.
+	documentCatalogCache struct {
+		NumDatabaseEntries               int64 `bson:"numDatabaseEntries" stm:"database_entries"`
+		NumCollectionEntries             int64 `bson:"numCollectionEntries" stm:"collection_entries"`
+		CountStaleConfigErrors           int64 `bson:"countStaleConfigErrors" stm:"stale_config_errors"`
+		TotalRefreshWaitTimeMicros       int64 `bson:"totalRefreshWaitTimeMicros" stm:"refresh_wait_time_micros"`
+		CountIncrementalRefreshesStarted int64 `bson:"countIncrementalRefreshesStarted" stm:"incremental_refreshes"`
+		CountFullRefreshesStarted        int64 `bson:"countFullRefreshesStarted" stm:"full_refreshes"`
+		CountFailedRefreshes             int64 `bson:"countFailedRefreshes" stm:"failed_refreshes"`
+	}
 )
 
 // https://www.mongodb.com/docs/manual/reference/command/dbStats/
@@ -274,3 +290,31 @@ type documentShardNodesResult struct {
 	ShardAware   int64
 	ShardUnaware int64
 }
+
+// https://www.mongodb.com/docs/manual/reference/config-database/#mongodb-data-config.shards
+type documentShard struct {
+	ID    string `bson:"_id"`
+	Host  string `bson:"host"` // <replica set>/<host:port>,<host:port>
+	State int64  `bson:"state"`
+}
+
+// https://www.mongodb.com/docs/manual/reference/command/connPoolStats/
+type documentConnPoolStats struct {
+	Hosts map[string]documentConnPoolHostStats `bson:"hosts"`
+}
+
+type documentConnPoolHostStats struct {
+	InUse      int64 `bson:"inUse"`
+	Available  int64 `bson:"available"`
+	Created    int64 `bson:"created"`
+	Refreshing int64 `bson:"refreshing"`
+}
+
+// https://www.mongodb.com/docs/manual/reference/config-database/#mongodb-data-config.actionlog
+type documentBalancerRound struct {
+	Time    time.Time `bson:"time"`
+	Details struct {
+		ChunksMoved   int64 `bson:"chunksMoved"`
+		ErrorOccurred bool  `bson:"errorOccured"` // sic
+	} `bson:"details"`
+}
@@ -10,6 +10,9 @@ func (m *Mongo) verifyConfig() error {
 	if m.URI == "" {
 		return errors.New("connection URI is empty")
 	}
+	if m.CollectChunks && m.ChunksTimeout <= 0 {
+		return errors.New("'chunks_timeout' must be positive when 'collect_chunks' is enabled")
+	}
 
 	return nil
 }
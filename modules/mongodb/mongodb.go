@@ -24,8 +24,9 @@ func init() {
 func New() *Mongo {
 	return &Mongo{
 		Config: Config{
-			Timeout: 2,
-			URI:     "mongodb://localhost:27017",
+			Timeout:       2,
+			ChunksTimeout: 10,
+			URI:           "mongodb://localhost:27017",
 			Databases: matcher.SimpleExpr{
 				Includes: []string{},
 				Excludes: []string{},
@@ -36,6 +37,7 @@ func New() *Mongo {
 
 		charts:                chartsServerStatus.Copy(),
 		addShardingChartsOnce: &sync.Once{},
+		addBalancerChartsOnce: &sync.Once{},
 
 		optionalCharts: make(map[string]bool),
 		replSetMembers: make(map[string]bool),
@@ -45,9 +47,11 @@ func New() *Mongo {
 }
 
 type Config struct {
-	URI       string             `yaml:"uri"`
-	Timeout   time.Duration      `yaml:"timeout"`
-	Databases matcher.SimpleExpr `yaml:"databases"`
+	URI           string             `yaml:"uri"`
+	Timeout       time.Duration      `yaml:"timeout"`
+	Databases     matcher.SimpleExpr `yaml:"databases"`
+	CollectChunks bool               `yaml:"collect_chunks"`
+	ChunksTimeout time.Duration      `yaml:"chunks_timeout"`
 }
 
 type Mongo struct {
@@ -61,11 +65,14 @@ type Mongo struct {
 	dbSelector matcher.Matcher
 
 	addShardingChartsOnce *sync.Once
+	addBalancerChartsOnce *sync.Once
 
 	optionalCharts map[string]bool
 	databases      map[string]bool
 	replSetMembers map[string]bool
 	shards         map[string]bool
+
+	balancer balancerStats
 }
 
 func (m *Mongo) Init() bool {
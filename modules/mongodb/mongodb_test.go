@@ -6,28 +6,36 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/netdata/go.d.plugin/pkg/matcher"
 )
 
 var (
-	dataV6MongodServerStatus, _ = os.ReadFile("testdata/v6.0.3/mongod-serverStatus.json")
-	dataV6MongosServerStatus, _ = os.ReadFile("testdata/v6.0.3/mongos-serverStatus.json")
-	dataV6DbStats, _            = os.ReadFile("testdata/v6.0.3/dbStats.json")
-	dataV6ReplSetGetStatus, _   = os.ReadFile("testdata/v6.0.3/replSetGetStatus.json")
+	dataV6MongodServerStatus, _  = os.ReadFile("testdata/v6.0.3/mongod-serverStatus.json")
+	dataV6MongosServerStatus, _  = os.ReadFile("testdata/v6.0.3/mongos-serverStatus.json")
+	dataV6DbStats, _             = os.ReadFile("testdata/v6.0.3/dbStats.json")
+	dataV6ReplSetGetStatus, _    = os.ReadFile("testdata/v6.0.3/replSetGetStatus.json")
+	dataV6MongosConfigShards, _  = os.ReadFile("testdata/v6.0.3/mongos-configShards.json")
+	dataV6MongosConnPoolStats, _ = os.ReadFile("testdata/v6.0.3/mongos-connPoolStats.json")
+	dataV6MongosActionlog, _     = os.ReadFile("testdata/v6.0.3/mongos-actionlog.json")
 )
 
 func Test_testDataIsValid(t *testing.T) {
 	for name, data := range map[string][]byte{
-		"dataV6MongodServerStatus": dataV6MongodServerStatus,
-		"dataV6MongosServerStatus": dataV6MongosServerStatus,
-		"dataV6DbStats":            dataV6DbStats,
-		"dataV6ReplSetGetStatus":   dataV6ReplSetGetStatus,
+		"dataV6MongodServerStatus":  dataV6MongodServerStatus,
+		"dataV6MongosServerStatus":  dataV6MongosServerStatus,
+		"dataV6DbStats":             dataV6DbStats,
+		"dataV6ReplSetGetStatus":    dataV6ReplSetGetStatus,
+		"dataV6MongosConfigShards":  dataV6MongosConfigShards,
+		"dataV6MongosConnPoolStats": dataV6MongosConnPoolStats,
+		"dataV6MongosActionlog":     dataV6MongosActionlog,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -48,6 +56,14 @@ func TestMongo_Init(t *testing.T) {
 				URI: "",
 			},
 		},
+		"fails on non-positive 'chunks_timeout' with 'collect_chunks'": {
+			wantFail: true,
+			config: Config{
+				URI:           "mongodb://localhost:27017",
+				CollectChunks: true,
+				ChunksTimeout: 0,
+			},
+		},
 		"fails on invalid database selector": {
 			wantFail: true,
 			config: Config{
@@ -526,10 +542,27 @@ func TestMongo_Collect(t *testing.T) {
 				"shard_collections_unpartitioned":                                1,
 				"shard_databases_partitioned":                                    1,
 				"shard_databases_unpartitioned":                                  1,
-				"shard_id_shard0_chunks":                                         1,
-				"shard_id_shard1_chunks":                                         1,
+				"shard_id_shard01_conn_pool_available":                           5,
+				"shard_id_shard01_conn_pool_created":                             18,
+				"shard_id_shard01_conn_pool_in_use":                              2,
+				"shard_id_shard01_conn_pool_refreshing":                          1,
+				"shard_id_shard02_conn_pool_available":                           6,
+				"shard_id_shard02_conn_pool_created":                             13,
+				"shard_id_shard02_conn_pool_in_use":                              1,
+				"shard_id_shard02_conn_pool_refreshing":                          0,
+				"shard_id_shard03_conn_pool_available":                           3,
+				"shard_id_shard03_conn_pool_created":                             17,
+				"shard_id_shard03_conn_pool_in_use":                              3,
+				"shard_id_shard03_conn_pool_refreshing":                          0,
 				"shard_nodes_aware":                                              1,
 				"shard_nodes_unaware":                                            1,
+				"sharding_catalog_cache_collection_entries":                      7,
+				"sharding_catalog_cache_database_entries":                        3,
+				"sharding_catalog_cache_failed_refreshes":                        1,
+				"sharding_catalog_cache_full_refreshes":                          9,
+				"sharding_catalog_cache_incremental_refreshes":                   41,
+				"sharding_catalog_cache_refresh_wait_time_micros":                184725,
+				"sharding_catalog_cache_stale_config_errors":                     12,
 				"tcmalloc_aggressive_memory_decommit":                            0,
 				"tcmalloc_central_cache_free_bytes":                              736960,
 				"tcmalloc_current_total_thread_cache_bytes":                      1638104,
@@ -599,6 +632,77 @@ func TestMongo_Collect(t *testing.T) {
 	}
 }
 
+func TestMongo_Collect_ShardingChunks(t *testing.T) {
+	mongo := prepareMongo()
+	defer mongo.Cleanup()
+	mongo.CollectChunks = true
+	mock := caseMongos()
+	mongo.conn = mock
+
+	require.True(t, mongo.Init())
+
+	mx := mongo.Collect()
+	require.NotNil(t, mx)
+
+	expected := map[string]int64{
+		"shard_id_shard01_chunks":      120,
+		"shard_id_shard02_chunks":      118,
+		"shard_id_shard03_chunks":      0,
+		"shard_balancer_rounds":        3,
+		"shard_balancer_rounds_failed": 1,
+		"shard_balancer_chunks_moved":  3,
+	}
+	for k, v := range expected {
+		assert.Equalf(t, v, mx[k], "metric '%s'", k)
+	}
+
+	for _, id := range []string{"shard01", "shard02", "shard03"} {
+		for _, px := range []string{"_chunks", "_conn_pool_connections", "_conn_pool_created_rate"} {
+			chart := mongo.Charts().Get(chartPxShard + id + px)
+			require.NotNilf(t, chart, "shard '%s' chart '%s'", id, px)
+			assert.Contains(t, chart.Labels, module.Label{Key: "shard_id", Value: id})
+		}
+	}
+	assert.True(t, mongo.Charts().Has(chartShardingBalancerMigrationsRate.ID))
+	assert.True(t, mongo.Charts().Has(chartShardingCatalogCacheRefreshesRate.ID))
+
+	// the balancer rounds are counted once
+	mock.removedShard = "shard03"
+	mx = mongo.Collect()
+	require.NotNil(t, mx)
+
+	assert.Equal(t, int64(3), mx["shard_balancer_rounds"])
+	assert.NotContains(t, mx, "shard_id_shard03_chunks")
+	assert.NotContains(t, mx, "shard_id_shard03_conn_pool_in_use")
+	for _, chart := range *mongo.Charts() {
+		if strings.HasPrefix(chart.ID, chartPxShard+"shard03_") {
+			assert.Truef(t, chart.Obsolete, "chart '%s' of the removed shard is not obsolete", chart.ID)
+		}
+	}
+
+	// the opt-in queries don't fail the collection
+	mock.errOnShardChunks = true
+	mock.errOnBalancerRounds = true
+	mx = mongo.Collect()
+	require.NotNil(t, mx)
+
+	assert.NotContains(t, mx, "shard_id_shard01_chunks")
+	assert.Contains(t, mx, "shard_id_shard01_conn_pool_in_use")
+}
+
+func TestMongo_Collect_ShardingChunksNotEnabled(t *testing.T) {
+	mongo := prepareMongo()
+	defer mongo.Cleanup()
+	mongo.conn = caseMongos()
+
+	require.True(t, mongo.Init())
+	require.NotNil(t, mongo.Collect())
+
+	assert.False(t, mongo.Charts().Has(chartPxShard+"shard01_chunks"))
+	assert.True(t, mongo.Charts().Has(chartPxShard+"shard01_conn_pool_connections"))
+	assert.False(t, mongo.Charts().Has(chartShardingBalancerRoundsRate.ID))
+}
+
 func prepareMongo() *Mongo {
 	m := New()
 	m.Databases = matcher.SimpleExpr{Includes: []string{"* *"}}
@@ -628,6 +732,10 @@ type mockMongoClient struct {
 	errOnShardDatabasesPartitioning   bool
 	errOnShardCollectionsPartitioning bool
 	errOnShardChunks                  bool
+	errOnShardList                    bool
+	errOnConnPoolStats                bool
+	errOnBalancerRounds               bool
+	removedShard                      string
 	errOnInitClient                   bool
 	clientInited                      bool
 	closeCalled                       bool
@@ -770,7 +878,33 @@ func (m *mockMongoClient) shardCollectionsPartitioning() (*documentPartitionedRe
 	}, nil
 }
 
-func (m *mockMongoClient) shardChunks() (map[string]int64, error) {
+func (m *mockMongoClient) shardList() ([]documentShard, error) {
+	if !m.clientInited {
+		return nil, errors.New("mock.shardList() error: mongo client not inited")
+	}
+	if !m.mongos {
+		return nil, errors.New("mock.shardList() error: should be called for mongos")
+	}
+	if m.errOnShardList {
+		return nil, errors.New("mock.shardList() error")
+	}
+
+	var shards []documentShard
+	if err := json.Unmarshal(dataV6MongosConfigShards, &shards); err != nil {
+		return nil, err
+	}
+
+	var res []documentShard
+	for _, shard := range shards {
+		if shard.ID != m.removedShard {
+			res = append(res, shard)
+		}
+	}
+
+	return res, nil
+}
+
+func (m *mockMongoClient) shardChunks(_ time.Duration) (map[string]int64, error) {
 	if !m.clientInited {
 		return nil, errors.New("mock.shardChunks() error: mongo client not inited")
 	}
@@ -784,12 +918,58 @@ func (m *mockMongoClient) shardChunks() (map[string]int64, error) {
 		return nil, errors.New("mock.shardChunks() error")
 	}
 
+	// shard03 has no chunks
 	return map[string]int64{
-		"shard0": 1,
-		"shard1": 1,
+		"shard01": 120,
+		"shard02": 118,
 	}, nil
 }
 
+func (m *mockMongoClient) balancerRounds(since time.Time, _ time.Duration) ([]documentBalancerRound, error) {
+	if !m.clientInited {
+		return nil, errors.New("mock.balancerRounds() error: mongo client not inited")
+	}
+	if !m.mongos {
+		return nil, errors.New("mock.balancerRounds() error: should be called for mongos")
+	}
+	if m.errOnBalancerRounds {
+		return nil, errors.New("mock.balancerRounds() error")
+	}
+
+	var rounds []documentBalancerRound
+	if err := json.Unmarshal(dataV6MongosActionlog, &rounds); err != nil {
+		return nil, err
+	}
+
+	var res []documentBalancerRound
+	for _, r := range rounds {
+		if r.Time.After(since) {
+			res = append(res, r)
+		}
+	}
+
+	return res, nil
+}
+
+func (m *mockMongoClient) connPoolStats() (*documentConnPoolStats, error) {
+	if !m.clientInited {
+		return nil, errors.New("mock.connPoolStats() error: mongo client not inited")
+	}
+	if !m.mongos {
+		return nil, errors.New("mock.connPoolStats() error: should be called for mongos")
+	}
+	if m.errOnConnPoolStats {
+		return nil, errors.New("mock.connPoolStats() error")
+	}
+
+	var stats documentConnPoolStats
+	if err := json.Unmarshal(dataV6MongosConnPoolStats, &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
 func (m *mockMongoClient) initClient(_ string, _ time.Duration) error {
 	if m.errOnInitClient {
 		return errors.New("mock.initClient() error")
@@ -72,6 +72,7 @@ const (
 	prioGaleraThreadCount
 	prioSlaveSecondsBehindMaster
 	prioSlaveSQLIOThreadRunningState
+	prioSlaveGTIDGap
 	prioUserStatsCPUTime
 	prioUserStatsRows
 	prioUserStatsCommands
@@ -82,6 +83,12 @@ const (
 	prioUserStatsConnections
 	prioUserStatsLostConnections
 	prioUserStatsDeniedConnections
+	prioPerfSchemaUserConnections
+	prioPerfSchemaUserConnectionsRate
+	prioPerfSchemaUserStatements
+	prioPerfSchemaUserRows
+	prioPerfSchemaTableIOOperations
+	prioPerfSchemaTableIOLatency
 )
 
 var baseCharts = module.Charts{
@@ -965,15 +972,34 @@ var (
 			{ID: "slave_io_running", Name: "io_running"},
 		},
 	}
+	chartSlaveGTIDGap = module.Chart{
+		ID:       "slave_gtid_gap",
+		Title:    "Retrieved but not Executed GTID Transactions",
+		Units:    "transactions",
+		Fam:      "slave",
+		Ctx:      "mysql.slave_gtid_gap",
+		Priority: prioSlaveGTIDGap,
+		Dims: module.Dims{
+			{ID: "slave_gtid_gap", Name: "gap"},
+		},
+	}
 )
 
-func newSlaveReplConnCharts(conn string) *module.Charts {
+func newSlaveReplConnCharts(conn string, hasGTID bool) *module.Charts {
+	cs := chartsSlaveReplication.Copy()
+	if hasGTID {
+		_ = cs.Add(chartSlaveGTIDGap.Copy())
+	}
+	if conn == "" {
+		return cs
+	}
+
 	orig := conn
 	conn = strings.ToLower(conn)
-	cs := chartsSlaveReplication.Copy()
 	for _, chart := range *cs {
 		chart.ID += "_" + conn
 		chart.Title += " Connection " + orig
+		chart.Fam += " " + conn
 		for _, dim := range chart.Dims {
 			dim.ID += "_" + conn
 		}
@@ -1172,18 +1198,168 @@ var (
 	}
 )
 
-func (m *MySQL) addSlaveReplicationConnCharts(conn string) {
-	var charts *module.Charts
-	if conn == "" {
-		charts = chartsSlaveReplication.Copy()
-	} else {
-		charts = newSlaveReplConnCharts(conn)
+func (m *MySQL) addSlaveReplicationConnCharts(conn string, hasGTID bool) {
+	if err := m.Charts().Add(*newSlaveReplConnCharts(conn, hasGTID)...); err != nil {
+		m.Warning(err)
+	}
+}
+
+func (m *MySQL) removeSlaveReplicationConnCharts(conn string) {
+	for _, tmpl := range []module.Chart{chartSlaveBehindSeconds, chartSlaveSQLIOThreadRunningState, chartSlaveGTIDGap} {
+		id := tmpl.ID
+		if conn != "" {
+			id += "_" + strings.ToLower(conn)
+		}
+		if chart := m.Charts().Get(id); chart != nil {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+var (
+	chartsTmplPerfSchemaUser = module.Charts{
+		chartTmplPerfSchemaUserConnections.Copy(),
+		chartTmplPerfSchemaUserConnectionsRate.Copy(),
+		chartTmplPerfSchemaUserStatements.Copy(),
+		chartTmplPerfSchemaUserRows.Copy(),
+	}
+
+	chartTmplPerfSchemaUserConnections = module.Chart{
+		ID:       "ps_user_%s_connections",
+		Title:    "User Current Connections",
+		Units:    "connections",
+		Fam:      "ps user connections",
+		Ctx:      "mysql.ps_user_connections",
+		Priority: prioPerfSchemaUserConnections,
+		Dims: module.Dims{
+			{ID: "ps_user_%s_current_connections", Name: "current"},
+		},
+	}
+	chartTmplPerfSchemaUserConnectionsRate = module.Chart{
+		ID:       "ps_user_%s_connections_rate",
+		Title:    "User Connections Rate",
+		Units:    "connections/s",
+		Fam:      "ps user connections",
+		Ctx:      "mysql.ps_user_connections_rate",
+		Priority: prioPerfSchemaUserConnectionsRate,
+		Dims: module.Dims{
+			{ID: "ps_user_%s_total_connections", Name: "connections", Algo: module.Incremental},
+		},
+	}
+	chartTmplPerfSchemaUserStatements = module.Chart{
+		ID:       "ps_user_%s_statements",
+		Title:    "User Statements",
+		Units:    "statements/s",
+		Fam:      "ps user statements",
+		Ctx:      "mysql.ps_user_statements",
+		Priority: prioPerfSchemaUserStatements,
+		Dims: module.Dims{
+			{ID: "ps_user_%s_statements", Name: "statements", Algo: module.Incremental},
+		},
+	}
+	chartTmplPerfSchemaUserRows = module.Chart{
+		ID:       "ps_user_%s_rows",
+		Title:    "User Rows Operations",
+		Units:    "rows/s",
+		Fam:      "ps user rows",
+		Ctx:      "mysql.ps_user_rows",
+		Priority: prioPerfSchemaUserRows,
+		Dims: module.Dims{
+			{ID: "ps_user_%s_rows_read", Name: "read", Algo: module.Incremental},
+			{ID: "ps_user_%s_rows_sent", Name: "sent", Algo: module.Incremental},
+			{ID: "ps_user_%s_rows_written", Name: "written", Algo: module.Incremental},
+		},
 	}
+)
+
+var (
+	chartsTmplPerfSchemaTable = module.Charts{
+		chartTmplPerfSchemaTableIOOperations.Copy(),
+		chartTmplPerfSchemaTableIOLatency.Copy(),
+	}
+
+	chartTmplPerfSchemaTableIOOperations = module.Chart{
+		ID:       "ps_table_%s_io_operations",
+		Title:    "Table I/O Operations",
+		Units:    "operations/s",
+		Fam:      "ps table io",
+		Ctx:      "mysql.ps_table_io_operations",
+		Priority: prioPerfSchemaTableIOOperations,
+		Dims: module.Dims{
+			{ID: "ps_table_%s_read_ops", Name: "read", Algo: module.Incremental},
+			{ID: "ps_table_%s_write_ops", Name: "write", Algo: module.Incremental},
+		},
+	}
+	chartTmplPerfSchemaTableIOLatency = module.Chart{
+		ID:       "ps_table_%s_io_latency",
+		Title:    "Table I/O Average Latency",
+		Units:    "microseconds",
+		Fam:      "ps table io",
+		Ctx:      "mysql.ps_table_io_latency",
+		Priority: prioPerfSchemaTableIOLatency,
+		Dims: module.Dims{
+			{ID: "ps_table_%s_read_latency", Name: "read"},
+			{ID: "ps_table_%s_write_latency", Name: "write"},
+		},
+	}
+)
+
+func (m *MySQL) addPerfSchemaUserCharts(user string) {
+	charts := chartsTmplPerfSchemaUser.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, strings.ToLower(user))
+		chart.Labels = []module.Label{
+			{Key: "user", Value: user},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, user)
+		}
+	}
+
+	if err := m.Charts().Add(*charts...); err != nil {
+		m.Warning(err)
+	}
+}
+
+func (m *MySQL) removePerfSchemaUserCharts(user string) {
+	m.removeChartsByTemplate(chartsTmplPerfSchemaUser, strings.ToLower(user))
+}
+
+func (m *MySQL) addPerfSchemaTableCharts(schema, table string) {
+	charts := chartsTmplPerfSchemaTable.Copy()
+	id := schema + "." + table
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, strings.ToLower(id))
+		chart.Labels = []module.Label{
+			{Key: "schema", Value: schema},
+			{Key: "table", Value: table},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, id)
+		}
+	}
+
 	if err := m.Charts().Add(*charts...); err != nil {
 		m.Warning(err)
 	}
 }
 
+func (m *MySQL) removePerfSchemaTableCharts(id string) {
+	m.removeChartsByTemplate(chartsTmplPerfSchemaTable, strings.ToLower(id))
+}
+
+func (m *MySQL) removeChartsByTemplate(tmpl module.Charts, id string) {
+	for _, t := range tmpl {
+		if chart := m.Charts().Get(fmt.Sprintf(t.ID, id)); chart != nil && !chart.Obsolete {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
 func (m *MySQL) addUserStatisticsCharts(user string) {
 	if m.isPercona {
 		if err := m.Charts().Add(*newPerconaUserStatisticsCharts(user)...); err != nil {
@@ -81,6 +81,14 @@ func (m *MySQL) collect() (map[string]int64, error) {
 		}
 	}
 
+	if !m.perfSchemaChecked {
+		m.perfSchemaChecked = true
+		m.checkPerformanceSchema()
+	}
+	if m.doPerfSchemaUserStatistics || m.doPerfSchemaTableIO {
+		m.collectPerformanceSchema(mx)
+	}
+
 	if err := m.collectProcessListStatistics(mx); err != nil {
 		m.Errorf("error on collecting process list statistics: %v", err)
 	}
@@ -145,7 +153,12 @@ func hasTableOpenCacheOverflowsMetrics(collected map[string]int64) bool {
 }
 
 func (m *MySQL) collectQuery(query string, assign func(column, value string, lineEnd bool)) (duration int64, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), m.Timeout.Duration)
+	return m.collectQueryContext(context.Background(), query, assign)
+}
+
+// collectQueryContext is like collectQuery, but the query is also cancelled when the parent context is done.
+func (m *MySQL) collectQueryContext(parent context.Context, query string, assign func(column, value string, lineEnd bool)) (duration int64, err error) {
+	ctx, cancel := context.WithTimeout(parent, m.Timeout.Duration)
 	defer cancel()
 
 	s := time.Now()
@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+)
+
+// https://dev.mysql.com/doc/refman/8.0/en/performance-schema-statement-summary-tables.html
+// https://dev.mysql.com/doc/refman/8.0/en/performance-schema-users-table.html
+const queryPerfSchemaUserStatistics = `
+SELECT
+  u.USER AS user,
+  u.CURRENT_CONNECTIONS AS current_connections,
+  u.TOTAL_CONNECTIONS AS total_connections,
+  s.statements,
+  s.rows_read,
+  s.rows_sent,
+  s.rows_written
+FROM
+  performance_schema.users u
+  JOIN (
+    SELECT
+      USER,
+      SUM(COUNT_STAR) AS statements,
+      SUM(SUM_ROWS_EXAMINED) AS rows_read,
+      SUM(SUM_ROWS_SENT) AS rows_sent,
+      SUM(SUM_ROWS_AFFECTED) AS rows_written
+    FROM
+      performance_schema.events_statements_summary_by_user_by_event_name
+    WHERE
+      USER IS NOT NULL
+    GROUP BY
+      USER
+  ) s ON s.USER = u.USER
+ORDER BY
+  s.statements DESC
+LIMIT %d;`
+
+// https://dev.mysql.com/doc/refman/8.0/en/performance-schema-table-wait-summary-tables.html#performance-schema-table-io-waits-summary-by-table-table
+const queryPerfSchemaTableIO = `
+SELECT
+  OBJECT_SCHEMA AS table_schema,
+  OBJECT_NAME AS table_name,
+  COUNT_READ AS count_read,
+  COUNT_WRITE AS count_write,
+  SUM_TIMER_READ AS sum_timer_read,
+  SUM_TIMER_WRITE AS sum_timer_write
+FROM
+  performance_schema.table_io_waits_summary_by_table
+WHERE
+  OBJECT_SCHEMA NOT IN ('mysql', 'performance_schema', 'information_schema', 'sys')
+ORDER BY
+  SUM_TIMER_WAIT DESC
+LIMIT %d;`
+
+const (
+	queryPerfSchemaCheckUserStatistics = "SELECT 1 FROM performance_schema.events_statements_summary_by_user_by_event_name LIMIT 1;"
+	queryPerfSchemaCheckTableIO        = "SELECT 1 FROM performance_schema.table_io_waits_summary_by_table LIMIT 1;"
+)
+
+// perfSchemaTableIO holds the counters of the previous collection, they are needed to calculate the latency.
+type perfSchemaTableIO struct {
+	countRead, countWrite int64
+	timerRead, timerWrite int64
+}
+
+// checkPerformanceSchema enables the performance_schema sections if the server supports them
+// and the user has SELECT privilege on the tables. A failed check disables the section only.
+func (m *MySQL) checkPerformanceSchema() {
+	if !m.CollectUserStatistics && !m.CollectTableIO {
+		return
+	}
+
+	// the statement summary tables are available since MySQL 5.6 and MariaDB 10.0
+	minVer := semver.Version{Major: 5, Minor: 6, Patch: 0}
+	if m.isMariaDB {
+		minVer = semver.Version{Major: 10, Minor: 0, Patch: 0}
+	}
+	if m.version.LT(minVer) {
+		m.Warningf("performance_schema statistics are not supported by the server version %s", m.version)
+		return
+	}
+	if m.varPerformanceSchema != "ON" {
+		m.Warning("performance_schema is disabled on the server, performance_schema statistics are not collected")
+		return
+	}
+
+	check := func(q string) bool {
+		m.Debugf("executing query: '%s'", q)
+		if _, err := m.collectQuery(q, func(_, _ string, _ bool) {}); err != nil {
+			m.Warningf("performance_schema is not accessible (%v), check the SELECT privilege", err)
+			return false
+		}
+		return true
+	}
+
+	m.doPerfSchemaUserStatistics = m.CollectUserStatistics && check(queryPerfSchemaCheckUserStatistics)
+	m.doPerfSchemaTableIO = m.CollectTableIO && check(queryPerfSchemaCheckTableIO)
+}
+
+func (m *MySQL) collectPerformanceSchema(mx map[string]int64) {
+	// the performance_schema queries can be slow on a busy server, they must not delay the next collection
+	ctx, cancel := context.WithTimeout(context.Background(), m.perfSchemaDeadline())
+	defer cancel()
+
+	if m.doPerfSchemaUserStatistics {
+		if err := m.collectPerfSchemaUserStatistics(ctx, mx); err != nil {
+			m.Warningf("error on collecting performance_schema user statistics: %v", err)
+			m.doPerfSchemaUserStatistics = errors.Is(err, context.DeadlineExceeded)
+		}
+	}
+	if m.doPerfSchemaTableIO {
+		if err := m.collectPerfSchemaTableIO(ctx, mx); err != nil {
+			m.Warningf("error on collecting performance_schema table I/O: %v", err)
+			m.doPerfSchemaTableIO = errors.Is(err, context.DeadlineExceeded)
+		}
+	}
+}
+
+// perfSchemaDeadline returns the time budget of the performance_schema queries, it is a half of update_every.
+func (m *MySQL) perfSchemaDeadline() time.Duration {
+	every := m.UpdateEvery
+	if every <= 0 {
+		every = 1
+	}
+	return time.Duration(every) * time.Second / 2
+}
+
+func (m *MySQL) collectPerfSchemaUserStatistics(ctx context.Context, mx map[string]int64) error {
+	q := fmt.Sprintf(queryPerfSchemaUserStatistics, m.TopN)
+	m.Debugf("executing query: '%s'", q)
+
+	seen := make(map[string]bool)
+	var px string
+
+	_, err := m.collectQueryContext(ctx, q, func(column, value string, _ bool) {
+		switch column {
+		case "user":
+			px = ""
+			if value == "" || strings.ContainsAny(value, " \t") {
+				return
+			}
+			seen[value] = true
+			px = "ps_user_" + value + "_"
+		case "current_connections", "total_connections", "statements", "rows_read", "rows_sent", "rows_written":
+			if px != "" {
+				mx[px+column] = parseInt(value)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for user := range seen {
+		if !m.collectedPerfSchemaUsers[user] {
+			m.collectedPerfSchemaUsers[user] = true
+			m.Debugf("new performance_schema user '%s': adding charts", user)
+			m.addPerfSchemaUserCharts(user)
+		}
+	}
+	for user := range m.collectedPerfSchemaUsers {
+		if !seen[user] {
+			delete(m.collectedPerfSchemaUsers, user)
+			m.Debugf("performance_schema user '%s' is out of the top %d: removing charts", user, m.TopN)
+			m.removePerfSchemaUserCharts(user)
+		}
+	}
+
+	return nil
+}
+
+func (m *MySQL) collectPerfSchemaTableIO(ctx context.Context, mx map[string]int64) error {
+	q := fmt.Sprintf(queryPerfSchemaTableIO, m.TopN)
+	m.Debugf("executing query: '%s'", q)
+
+	type tableIO struct {
+		schema, name string
+		perfSchemaTableIO
+	}
+	var tables []tableIO
+	var t tableIO
+
+	_, err := m.collectQueryContext(ctx, q, func(column, value string, lineEnd bool) {
+		switch column {
+		case "table_schema":
+			t.schema = value
+		case "table_name":
+			t.name = value
+		case "count_read":
+			t.countRead = parseInt(value)
+		case "count_write":
+			t.countWrite = parseInt(value)
+		case "sum_timer_read":
+			t.timerRead = parseInt(value)
+		case "sum_timer_write":
+			t.timerWrite = parseInt(value)
+		}
+		if lineEnd {
+			tables = append(tables, t)
+			t = tableIO{}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+
+	for _, t := range tables {
+		id := t.schema + "." + t.name
+		if t.schema == "" || t.name == "" || strings.ContainsAny(id, " \t") {
+			continue
+		}
+		seen[id] = true
+
+		prev, ok := m.collectedPerfSchemaTables[id]
+		if !ok {
+			prev = &perfSchemaTableIO{}
+			*prev = t.perfSchemaTableIO
+			m.collectedPerfSchemaTables[id] = prev
+			m.Debugf("new performance_schema table '%s': adding charts", id)
+			m.addPerfSchemaTableCharts(t.schema, t.name)
+		}
+
+		px := "ps_table_" + id + "_"
+		mx[px+"read_ops"] = t.countRead
+		mx[px+"write_ops"] = t.countWrite
+		mx[px+"read_latency"] = avgLatencyMicroseconds(t.timerRead-prev.timerRead, t.countRead-prev.countRead)
+		mx[px+"write_latency"] = avgLatencyMicroseconds(t.timerWrite-prev.timerWrite, t.countWrite-prev.countWrite)
+
+		*prev = t.perfSchemaTableIO
+	}
+
+	for id := range m.collectedPerfSchemaTables {
+		if !seen[id] {
+			delete(m.collectedPerfSchemaTables, id)
+			m.Debugf("performance_schema table '%s' is out of the top %d: removing charts", id, m.TopN)
+			m.removePerfSchemaTableCharts(id)
+		}
+	}
+
+	return nil
+}
+
+// avgLatencyMicroseconds returns the average latency of an operation, the timers are in picoseconds.
+func avgLatencyMicroseconds(timerDelta, countDelta int64) int64 {
+	// the counters are reset (TRUNCATE TABLE) or there were no operations
+	if timerDelta <= 0 || countDelta <= 0 {
+		return 0
+	}
+	return timerDelta / countDelta / 1e6
+}
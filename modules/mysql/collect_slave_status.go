@@ -3,9 +3,14 @@
 package mysql
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/blang/semver/v4"
+	"github.com/go-sql-driver/mysql"
 )
 
 const (
@@ -14,50 +19,126 @@ const (
 	queryShowAllSlavesStatus = "SHOW ALL SLAVES STATUS;"
 )
 
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html#error_er_parse_error
+const errCodeParseError = 1064
+
+type replicationChannel struct {
+	name         string
+	behindMaster *int64
+	sqlRunning   int64
+	ioRunning    int64
+	hasGTID      bool
+	retrievedSet string
+	executedSet  string
+}
+
 func (m *MySQL) collectSlaveStatus(mx map[string]int64) error {
+	q := m.slaveStatusQuery()
+	m.Debugf("executing query: '%s'", q)
+
+	channels, err := m.querySlaveStatus(q)
+	if q == queryShowReplicaStatus && isParseError(err) {
+		// the version is detected incorrectly (e.g. a proxy in front of the server), use the legacy statement
+		m.Debugf("'%s' is not supported, falling back to '%s'", q, queryShowSlaveStatus)
+		m.useLegacySlaveStatus = true
+		q = queryShowSlaveStatus
+		m.Debugf("executing query: '%s'", q)
+		channels, err = m.querySlaveStatus(q)
+	}
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+
+	for _, ch := range channels {
+		seen[ch.name] = true
+
+		if !m.collectedReplConns[ch.name] {
+			m.collectedReplConns[ch.name] = true
+			m.Debugf("new replication channel '%s': adding charts", ch.name)
+			m.addSlaveReplicationConnCharts(ch.name, ch.hasGTID)
+		}
+
+		s := strings.ToLower(slaveMetricSuffix(ch.name))
+		// NULL if the SQL thread or the I/O thread is not running, 0 would be misleading
+		if ch.behindMaster != nil {
+			mx["seconds_behind_master"+s] = *ch.behindMaster
+		}
+		mx["slave_sql_running"+s] = ch.sqlRunning
+		mx["slave_io_running"+s] = ch.ioRunning
+
+		if ch.hasGTID {
+			gap, err := gtidSetGap(ch.retrievedSet, ch.executedSet)
+			if err != nil {
+				m.Debugf("replication channel '%s': error on calculating GTID gap: %v", ch.name, err)
+				continue
+			}
+			mx["slave_gtid_gap"+s] = gap
+		}
+	}
+
+	for name := range m.collectedReplConns {
+		if !seen[name] {
+			delete(m.collectedReplConns, name)
+			m.Debugf("stale replication channel '%s': removing charts", name)
+			m.removeSlaveReplicationConnCharts(name)
+		}
+	}
+
+	return nil
+}
+
+func (m *MySQL) slaveStatusQuery() string {
 	// https://mariadb.com/docs/reference/es/sql-statements/SHOW_ALL_SLAVES_STATUS/
 	mariaDBMinVer := semver.Version{Major: 10, Minor: 2, Patch: 0}
 	mysqlMinVer := semver.Version{Major: 8, Minor: 0, Patch: 22}
-	var q string
-	if m.isMariaDB && m.version.GTE(mariaDBMinVer) {
-		q = queryShowAllSlavesStatus
-	} else if !m.isMariaDB && m.version.GTE(mysqlMinVer) {
-		q = queryShowReplicaStatus
-	} else {
-		q = queryShowSlaveStatus
+
+	switch {
+	case m.isMariaDB && m.version.GTE(mariaDBMinVer):
+		return queryShowAllSlavesStatus
+	case !m.isMariaDB && !m.useLegacySlaveStatus && m.version.GTE(mysqlMinVer):
+		// https://dev.mysql.com/doc/refman/8.0/en/show-replica-status.html
+		return queryShowReplicaStatus
+	default:
+		return queryShowSlaveStatus
 	}
-	m.Debugf("executing query: '%s'", q)
+}
 
-	v := struct {
-		name         string
-		behindMaster int64
-		sqlRunning   int64
-		ioRunning    int64
-	}{}
+func (m *MySQL) querySlaveStatus(q string) ([]replicationChannel, error) {
+	var channels []replicationChannel
+	var ch replicationChannel
 
 	_, err := m.collectQuery(q, func(column, value string, lineEnd bool) {
 		switch column {
 		case "Connection_name", "Channel_Name":
-			v.name = value
+			ch.name = value
 		case "Seconds_Behind_Master", "Seconds_Behind_Source":
-			v.behindMaster = parseInt(value)
+			if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+				ch.behindMaster = &v
+			}
 		case "Slave_SQL_Running", "Replica_SQL_Running":
-			v.sqlRunning = parseInt(convertSlaveSQLRunning(value))
+			ch.sqlRunning = parseInt(convertSlaveSQLRunning(value))
 		case "Slave_IO_Running", "Replica_IO_Running":
-			v.ioRunning = parseInt(convertSlaveIORunning(value))
+			ch.ioRunning = parseInt(convertSlaveIORunning(value))
+		case "Retrieved_Gtid_Set":
+			ch.hasGTID = true
+			ch.retrievedSet = value
+		case "Executed_Gtid_Set":
+			ch.executedSet = value
 		}
 		if lineEnd {
-			if !m.collectedReplConns[v.name] {
-				m.collectedReplConns[v.name] = true
-				m.addSlaveReplicationConnCharts(v.name)
-			}
-			s := strings.ToLower(slaveMetricSuffix(v.name))
-			mx["seconds_behind_master"+s] = v.behindMaster
-			mx["slave_sql_running"+s] = v.sqlRunning
-			mx["slave_io_running"+s] = v.ioRunning
+			channels = append(channels, ch)
+			ch = replicationChannel{}
 		}
 	})
-	return err
+
+	return channels, err
+}
+
+func isParseError(err error) bool {
+	var e *mysql.MySQLError
+	return errors.As(err, &e) && e.Number == errCodeParseError
 }
 
 func convertSlaveSQLRunning(value string) string {
@@ -85,3 +166,91 @@ func slaveMetricSuffix(conn string) string {
 	}
 	return "_" + conn
 }
+
+type gtidInterval struct {
+	start, end int64
+}
+
+// gtidSetGap returns the number of the retrieved transactions that are not executed yet.
+func gtidSetGap(retrieved, executed string) (int64, error) {
+	rs, err := parseGTIDSet(retrieved)
+	if err != nil {
+		return 0, fmt.Errorf("retrieved GTID set: %v", err)
+	}
+	es, err := parseGTIDSet(executed)
+	if err != nil {
+		return 0, fmt.Errorf("executed GTID set: %v", err)
+	}
+
+	var gap int64
+	for source, intervals := range rs {
+		for _, r := range intervals {
+			n := r.end - r.start + 1
+			for _, e := range es[source] {
+				if start, end := max(r.start, e.start), min(r.end, e.end); start <= end {
+					n -= end - start + 1
+				}
+			}
+			gap += n
+		}
+	}
+	return gap, nil
+}
+
+// parseGTIDSet parses a GTID set: 'uuid:1-5:7,uuid[:tag]:1-3' (https://dev.mysql.com/doc/refman/8.0/en/replication-gtids-concepts.html#replication-gtids-concepts-gtid-sets).
+// The result is keyed by the source (uuid or uuid:tag), the intervals of a source are expected to be disjoint.
+func parseGTIDSet(set string) (map[string][]gtidInterval, error) {
+	res := make(map[string][]gtidInterval)
+
+	// long sets are split into several lines
+	set = strings.Join(strings.Fields(set), "")
+
+	for _, elem := range strings.Split(set, ",") {
+		if elem == "" {
+			continue
+		}
+		parts := strings.Split(elem, ":")
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid element '%s'", elem)
+		}
+
+		uuid := strings.ToLower(parts[0])
+		source := uuid
+		for _, part := range parts[1:] {
+			if part == "" {
+				return nil, fmt.Errorf("invalid element '%s'", elem)
+			}
+			if !unicode.IsDigit(rune(part[0])) {
+				// tagged GTIDs (MySQL 8.3+)
+				source = uuid + ":" + strings.ToLower(part)
+				continue
+			}
+			iv, err := parseGTIDInterval(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid element '%s': %v", elem, err)
+			}
+			res[source] = append(res[source], iv)
+		}
+	}
+
+	return res, nil
+}
+
+func parseGTIDInterval(s string) (gtidInterval, error) {
+	from, to, ok := strings.Cut(s, "-")
+	start, err := strconv.ParseInt(from, 10, 64)
+	if err != nil {
+		return gtidInterval{}, err
+	}
+	if !ok {
+		return gtidInterval{start: start, end: start}, nil
+	}
+	end, err := strconv.ParseInt(to, 10, 64)
+	if err != nil {
+		return gtidInterval{}, err
+	}
+	if end < start {
+		return gtidInterval{}, fmt.Errorf("interval '%s': end < start", s)
+	}
+	return gtidInterval{start: start, end: end}, nil
+}
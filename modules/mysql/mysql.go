@@ -32,6 +32,7 @@ func New() *MySQL {
 		Config: Config{
 			DSN:     "root@tcp(localhost:3306)/",
 			Timeout: web.Duration{Duration: time.Second},
+			TopN:    10,
 		},
 
 		charts:                         baseCharts.Copy(),
@@ -46,6 +47,8 @@ func New() *MySQL {
 		doUserStatistics:               true,
 		collectedReplConns:             make(map[string]bool),
 		collectedUsers:                 make(map[string]bool),
+		collectedPerfSchemaUsers:       make(map[string]bool),
+		collectedPerfSchemaTables:      make(map[string]*perfSchemaTableIO),
 
 		recheckGlobalVarsEvery: time.Minute * 10,
 	}
@@ -56,6 +59,10 @@ type Config struct {
 	MyCNF       string       `yaml:"my.cnf"`
 	UpdateEvery int          `yaml:"update_every"`
 	Timeout     web.Duration `yaml:"timeout"`
+
+	CollectUserStatistics bool `yaml:"collect_user_statistics"`
+	CollectTableIO        bool `yaml:"collect_table_io"`
+	TopN                  int  `yaml:"top_n"`
 }
 
 type MySQL struct {
@@ -78,10 +85,17 @@ type MySQL struct {
 	addQCacheOnce                  *sync.Once
 	addTableOpenCacheOverflowsOnce *sync.Once
 
-	doSlaveStatus      bool
-	collectedReplConns map[string]bool
-	doUserStatistics   bool
-	collectedUsers     map[string]bool
+	doSlaveStatus        bool
+	useLegacySlaveStatus bool
+	collectedReplConns   map[string]bool
+	doUserStatistics     bool
+	collectedUsers       map[string]bool
+
+	perfSchemaChecked          bool
+	doPerfSchemaUserStatistics bool
+	doPerfSchemaTableIO        bool
+	collectedPerfSchemaUsers   map[string]bool
+	collectedPerfSchemaTables  map[string]*perfSchemaTableIO
 
 	recheckGlobalVarsTime    time.Time
 	recheckGlobalVarsEvery   time.Duration
@@ -107,6 +121,11 @@ func (m *MySQL) Init() bool {
 		return false
 	}
 
+	if (m.CollectUserStatistics || m.CollectTableIO) && m.TopN <= 0 {
+		m.Errorf("invalid top_n value: %d", m.TopN)
+		return false
+	}
+
 	cfg, err := mysql.ParseDSN(m.DSN)
 	if err != nil {
 		m.Errorf("error on parsing DSN: %v", err)
@@ -16,6 +16,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/blang/semver/v4"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -1407,6 +1408,8 @@ func TestMySQL_Collect(t *testing.T) {
 						"questions":                             15,
 						"seconds_behind_master_master1":         0,
 						"seconds_behind_master_master2":         0,
+						"slave_gtid_gap_master1":                0,
+						"slave_gtid_gap_master2":                0,
 						"select_full_join":                      0,
 						"select_full_range_join":                0,
 						"select_range":                          0,
@@ -1620,6 +1623,277 @@ func TestMySQL_Collect(t *testing.T) {
 	}
 }
 
+func TestMySQL_Collect_ReplicationChannels(t *testing.T) {
+	const (
+		uuid1 = "61221e31-1ef3-11ed-a56a-0242ac120002"
+		uuid2 = "6151d979-1ef3-11ed-a509-0242ac120003"
+	)
+	columns := []string{"Replica_IO_Running", "Replica_SQL_Running", "Seconds_Behind_Source", "Retrieved_Gtid_Set", "Executed_Gtid_Set", "Channel_Name"}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	my := New()
+	my.db = db
+	require.True(t, my.Init())
+
+	mockExpect(t, mock, queryShowVersion, dataMySQLV8030Version)
+	mockExpect(t, mock, queryShowGlobalStatus, dataMySQLV8030GlobalStatus)
+	mockExpect(t, mock, queryShowGlobalVariables, dataMySQLV8030GlobalVariables)
+	mock.ExpectQuery(queryShowReplicaStatus).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow("Yes", "Yes", "3", uuid1+":1-10", uuid1+":1-7,\n"+uuid2+":1-3", "master1").
+		AddRow("Yes", "Yes", "0", uuid2+":1-3", uuid1+":1-7,\n"+uuid2+":1-3", "master2"))
+	mockExpect(t, mock, queryShowProcessListPS, dataMySQLV8030ProcessList)
+
+	mx := my.Collect()
+	require.NotNil(t, mx)
+
+	assert.Equal(t, int64(3), mx["seconds_behind_master_master1"])
+	assert.Equal(t, int64(3), mx["slave_gtid_gap_master1"])
+	assert.Equal(t, int64(0), mx["slave_gtid_gap_master2"])
+	for _, id := range []string{"slave_behind_master1", "slave_thread_running_master1", "slave_gtid_gap_master1", "slave_gtid_gap_master2"} {
+		chart := my.Charts().Get(id)
+		require.NotNilf(t, chart, "chart '%s'", id)
+		assert.False(t, chart.Obsolete)
+	}
+	assert.Equal(t, "slave master1", my.Charts().Get("slave_behind_master1").Fam)
+
+	// the I/O thread of 'master1' is stopped, 'master2' is removed
+	mockExpect(t, mock, queryShowGlobalStatus, dataMySQLV8030GlobalStatus)
+	mockExpect(t, mock, queryShowGlobalVariables, dataMySQLV8030GlobalVariables)
+	mock.ExpectQuery(queryShowReplicaStatus).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow("No", "Yes", nil, uuid1+":1-10", uuid1+":1-10,"+uuid2+":1-3", "master1"))
+	mockExpect(t, mock, queryShowProcessListPS, dataMySQLV8030ProcessList)
+
+	mx = my.Collect()
+	require.NotNil(t, mx)
+
+	assert.NotContains(t, mx, "seconds_behind_master_master1")
+	assert.Equal(t, int64(0), mx["slave_io_running_master1"])
+	assert.Equal(t, int64(0), mx["slave_gtid_gap_master1"])
+	assert.NotContains(t, mx, "slave_sql_running_master2")
+	for _, id := range []string{"slave_behind_master2", "slave_thread_running_master2", "slave_gtid_gap_master2"} {
+		chart := my.Charts().Get(id)
+		require.NotNilf(t, chart, "chart '%s'", id)
+		assert.Truef(t, chart.Obsolete, "chart '%s' is not removed", id)
+	}
+	assert.False(t, my.Charts().Get("slave_behind_master1").Obsolete)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQL_Collect_ReplicaStatusFallback(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	my := New()
+	my.db = db
+	require.True(t, my.Init())
+
+	mockExpect(t, mock, queryShowVersion, dataMySQLV8030Version)
+	mockExpect(t, mock, queryShowGlobalStatus, dataMySQLV8030GlobalStatus)
+	mockExpect(t, mock, queryShowGlobalVariables, dataMySQLV8030GlobalVariables)
+	mock.ExpectQuery(queryShowReplicaStatus).WillReturnError(&mysql.MySQLError{Number: 1064, Message: "syntax error"})
+	mock.ExpectQuery(queryShowSlaveStatus).WillReturnRows(
+		sqlmock.NewRows([]string{"Slave_IO_Running", "Slave_SQL_Running", "Seconds_Behind_Master"}).AddRow("Yes", "Yes", "5"))
+	mockExpect(t, mock, queryShowProcessListPS, dataMySQLV8030ProcessList)
+
+	mx := my.Collect()
+	require.NotNil(t, mx)
+	assert.Equal(t, int64(5), mx["seconds_behind_master"])
+	assert.True(t, my.doSlaveStatus)
+	assert.Equal(t, queryShowSlaveStatus, my.slaveStatusQuery())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQL_Collect_PerformanceSchema(t *testing.T) {
+	userColumns := []string{"user", "current_connections", "total_connections", "statements", "rows_read", "rows_sent", "rows_written"}
+	tableColumns := []string{"table_schema", "table_name", "count_read", "count_write", "sum_timer_read", "sum_timer_write"}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	my := New()
+	my.db = db
+	my.CollectUserStatistics = true
+	my.CollectTableIO = true
+	my.TopN = 2
+	require.True(t, my.Init())
+
+	mockExpect(t, mock, queryShowVersion, dataMySQLV8030Version)
+	mockExpect(t, mock, queryShowGlobalStatus, dataMySQLV8030GlobalStatus)
+	mockExpect(t, mock, queryShowGlobalVariables, dataMySQLV8030GlobalVariables)
+	mockExpect(t, mock, queryShowReplicaStatus, nil)
+	mock.ExpectQuery(queryPerfSchemaCheckUserStatistics).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow("1"))
+	mock.ExpectQuery(queryPerfSchemaCheckTableIO).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow("1"))
+	mock.ExpectQuery(fmt.Sprintf(queryPerfSchemaUserStatistics, 2)).WillReturnRows(sqlmock.NewRows(userColumns).
+		AddRow("app", "5", "120", "1000", "50000", "900", "300").
+		AddRow("root", "1", "3", "40", "100", "40", "0"))
+	mock.ExpectQuery(fmt.Sprintf(queryPerfSchemaTableIO, 2)).WillReturnRows(sqlmock.NewRows(tableColumns).
+		AddRow("shop", "orders", "100", "10", "500000000", "200000000"))
+	mockExpect(t, mock, queryShowProcessListPS, dataMySQLV8030ProcessList)
+
+	mx := my.Collect()
+	require.NotNil(t, mx)
+
+	for id, want := range map[string]int64{
+		"ps_user_app_current_connections": 5,
+		"ps_user_app_total_connections":   120,
+		"ps_user_app_statements":          1000,
+		"ps_user_app_rows_read":           50000,
+		"ps_user_app_rows_sent":           900,
+		"ps_user_app_rows_written":        300,
+		"ps_user_root_statements":         40,
+		"ps_table_shop.orders_read_ops":   100,
+		"ps_table_shop.orders_write_ops":  10,
+		// no previous values
+		"ps_table_shop.orders_read_latency":  0,
+		"ps_table_shop.orders_write_latency": 0,
+	} {
+		v, ok := mx[id]
+		assert.Truef(t, ok, "metric '%s' not collected", id)
+		assert.Equalf(t, want, v, "metric '%s'", id)
+	}
+	chart := my.Charts().Get("ps_table_shop.orders_io_latency")
+	require.NotNil(t, chart)
+	assert.Equal(t, []module.Label{{Key: "schema", Value: "shop"}, {Key: "table", Value: "orders"}}, chart.Labels)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, my, mx)
+
+	// 'root' drops out of the top N, the table I/O query fails with a non-timeout error
+	mockExpect(t, mock, queryShowGlobalStatus, dataMySQLV8030GlobalStatus)
+	mockExpect(t, mock, queryShowGlobalVariables, dataMySQLV8030GlobalVariables)
+	mockExpect(t, mock, queryShowReplicaStatus, nil)
+	mock.ExpectQuery(fmt.Sprintf(queryPerfSchemaUserStatistics, 2)).WillReturnRows(sqlmock.NewRows(userColumns).
+		AddRow("app", "4", "130", "1100", "51000", "950", "310").
+		AddRow("backup", "1", "1", "500", "90000", "90000", "0"))
+	mock.ExpectQuery(fmt.Sprintf(queryPerfSchemaTableIO, 2)).WillReturnRows(sqlmock.NewRows(tableColumns).
+		AddRow("shop", "orders", "150", "20", "1500000000", "700000000"))
+	mockExpect(t, mock, queryShowProcessListPS, dataMySQLV8030ProcessList)
+
+	mx = my.Collect()
+	require.NotNil(t, mx)
+
+	// (1500000000-500000000)ps / 50 reads = 20us, (700000000-200000000)ps / 10 writes = 50us
+	assert.Equal(t, int64(20), mx["ps_table_shop.orders_read_latency"])
+	assert.Equal(t, int64(50), mx["ps_table_shop.orders_write_latency"])
+	assert.Equal(t, int64(500), mx["ps_user_backup_statements"])
+	assert.NotContains(t, mx, "ps_user_root_statements")
+	for _, id := range []string{"ps_user_root_connections", "ps_user_root_connections_rate", "ps_user_root_statements", "ps_user_root_rows"} {
+		chart := my.Charts().Get(id)
+		require.NotNilf(t, chart, "chart '%s'", id)
+		assert.Truef(t, chart.Obsolete, "chart '%s' is not removed", id)
+	}
+	assert.False(t, my.Charts().Get("ps_user_app_statements").Obsolete)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQL_Collect_PerformanceSchemaNoPrivileges(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	my := New()
+	my.db = db
+	my.CollectUserStatistics = true
+	my.CollectTableIO = true
+	require.True(t, my.Init())
+
+	mockExpect(t, mock, queryShowVersion, dataMySQLV8030Version)
+	mockExpect(t, mock, queryShowGlobalStatus, dataMySQLV8030GlobalStatus)
+	mockExpect(t, mock, queryShowGlobalVariables, dataMySQLV8030GlobalVariables)
+	mockExpect(t, mock, queryShowReplicaStatus, nil)
+	mock.ExpectQuery(queryPerfSchemaCheckUserStatistics).WillReturnError(&mysql.MySQLError{Number: 1142, Message: "SELECT command denied"})
+	mock.ExpectQuery(queryPerfSchemaCheckTableIO).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow("1"))
+	mock.ExpectQuery(fmt.Sprintf(queryPerfSchemaTableIO, 10)).WillReturnRows(
+		sqlmock.NewRows([]string{"table_schema", "table_name", "count_read", "count_write", "sum_timer_read", "sum_timer_write"}))
+	mockExpect(t, mock, queryShowProcessListPS, dataMySQLV8030ProcessList)
+
+	mx := my.Collect()
+	require.NotNil(t, mx)
+	assert.Contains(t, mx, "queries")
+	assert.False(t, my.doPerfSchemaUserStatistics)
+	assert.True(t, my.doPerfSchemaTableIO)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQL_Init_InvalidTopN(t *testing.T) {
+	my := New()
+	my.CollectTableIO = true
+	my.TopN = 0
+
+	assert.False(t, my.Init())
+}
+
+func TestGTIDSetGap(t *testing.T) {
+	const (
+		uuid1 = "61221e31-1ef3-11ed-a56a-0242ac120002"
+		uuid2 = "6151d979-1ef3-11ed-a509-0242ac120003"
+	)
+	tests := map[string]struct {
+		retrieved string
+		executed  string
+		want      int64
+		wantErr   bool
+	}{
+		"empty sets": {},
+		"no gap": {
+			retrieved: uuid1 + ":1-3",
+			executed:  uuid1 + ":1-3," + uuid2 + ":1-5",
+		},
+		"gap at the end": {
+			retrieved: uuid1 + ":1-10",
+			executed:  uuid1 + ":1-7",
+			want:      3,
+		},
+		"several intervals and sources": {
+			retrieved: uuid1 + ":1-10:15-20,\n" + uuid2 + ":1-5",
+			executed:  uuid1 + ":1-4:6-10:15," + uuid2 + ":1-2",
+			want:      1 + 5 + 3,
+		},
+		"nothing executed": {
+			retrieved: uuid1 + ":5",
+			want:      1,
+		},
+		"upper case uuid": {
+			retrieved: strings.ToUpper(uuid1) + ":1-2",
+			executed:  uuid1 + ":1",
+			want:      1,
+		},
+		"tagged gtids": {
+			retrieved: uuid1 + ":1-5:tag1:1-5",
+			executed:  uuid1 + ":1-5:tag1:1-2",
+			want:      3,
+		},
+		"invalid interval": {
+			retrieved: uuid1 + ":5-1",
+			wantErr:   true,
+		},
+		"invalid element": {
+			executed: uuid1,
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gap, err := gtidSetGap(test.retrieved, test.executed)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.want, gap)
+			}
+		})
+	}
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, mySQL *MySQL, collected map[string]int64) {
 	for _, chart := range *mySQL.Charts() {
 		if mySQL.isMariaDB {
@@ -1700,6 +1974,9 @@ func prepareMockRows(data []byte) (*sqlmock.Rows, error) {
 
 		values := make([]driver.Value, len(parts))
 		for i, v := range parts {
+			if v == "NULL" {
+				continue
+			}
 			values[i] = v
 		}
 		rows.AddRow(values...)
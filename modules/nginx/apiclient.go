@@ -4,6 +4,7 @@ package nginx
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -49,6 +50,9 @@ var (
 	reStatus = regexp.MustCompile(`^Active connections: ([0-9]+)\n[^\d]+([0-9]+) ([0-9]+) ([0-9]+) ?([0-9]+)?\nReading: ([0-9]+) Writing: ([0-9]+) Waiting: ([0-9]+)`)
 )
 
+// errVTSResponse is returned when the URL serves the nginx-module-vts JSON instead of the stub_status page.
+var errVTSResponse = errors.New("nginx-module-vts JSON response")
+
 func newAPIClient(client *http.Client, request web.Request) *apiClient {
 	return &apiClient{httpClient: client, request: request}
 }
@@ -70,9 +74,13 @@ func (a apiClient) getStubStatus() (*stubStatus, error) {
 		return nil, err
 	}
 
+	if isJSONResponse(resp) {
+		return nil, errVTSResponse
+	}
+
 	status, err := parseStubStatus(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error on parsing response : %v", err)
+		return nil, fmt.Errorf("error on parsing response (expected ngx_http_stub_status_module text or nginx-module-vts JSON): %v", err)
 	}
 
 	return status, nil
@@ -91,6 +99,10 @@ func (a apiClient) doRequestOK(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
+func isJSONResponse(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+}
+
 func closeBody(resp *http.Response) {
 	if resp != nil && resp.Body != nil {
 		_, _ = io.Copy(io.Discard, resp.Body)
@@ -3,15 +3,40 @@
 package nginx
 
 import (
+	"errors"
+
+	"github.com/netdata/go.d.plugin/modules/nginxvts"
 	"github.com/netdata/go.d.plugin/pkg/stm"
 )
 
 func (n *Nginx) collect() (map[string]int64, error) {
-	status, err := n.apiClient.getStubStatus()
+	if n.vts != nil {
+		return n.vts.Collect(), nil
+	}
 
+	status, err := n.apiClient.getStubStatus()
+	if errors.Is(err, errVTSResponse) {
+		return n.collectVTS()
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return stm.ToMap(status), nil
 }
+
+// collectVTS hands the job over to the nginxvts collector, the URL turned out to serve nginx-module-vts JSON.
+func (n *Nginx) collectVTS() (map[string]int64, error) {
+	vts := nginxvts.New()
+	vts.HTTP = n.HTTP
+	vts.Base = n.Base
+
+	if !vts.Init() {
+		return nil, errors.New("nginx-module-vts collector initialization failed")
+	}
+
+	n.Infof("'%s' is a nginx-module-vts endpoint, using nginxvts collector", n.URL)
+	n.vts = vts
+
+	return n.vts.Collect(), nil
+}
@@ -6,6 +6,7 @@ import (
 	_ "embed"
 	"time"
 
+	"github.com/netdata/go.d.plugin/modules/nginxvts"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
 	"github.com/netdata/go.d.plugin/agent/module"
@@ -53,10 +54,16 @@ type Nginx struct {
 	Config `yaml:",inline"`
 
 	apiClient *apiClient
+	// vts is set if the URL serves nginx-module-vts JSON instead of stub_status.
+	vts *nginxvts.NginxVTS
 }
 
 // Cleanup makes cleanup.
-func (Nginx) Cleanup() {}
+func (n *Nginx) Cleanup() {
+	if n.vts != nil {
+		n.vts.Cleanup()
+	}
+}
 
 // Init makes initialization.
 func (n *Nginx) Init() bool {
@@ -83,7 +90,12 @@ func (n *Nginx) Init() bool {
 func (n *Nginx) Check() bool { return len(n.Collect()) > 0 }
 
 // Charts creates Charts.
-func (Nginx) Charts() *Charts { return charts.Copy() }
+func (n *Nginx) Charts() *Charts {
+	if n.vts != nil {
+		return n.vts.Charts()
+	}
+	return charts.Copy()
+}
 
 // Collect collects metrics.
 func (n *Nginx) Collect() map[string]int64 {
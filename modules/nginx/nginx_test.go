@@ -3,9 +3,11 @@
 package nginx
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/netdata/go.d.plugin/agent/module"
@@ -16,6 +18,7 @@ import (
 var (
 	testStatusData, _        = os.ReadFile("testdata/status.txt")
 	testTengineStatusData, _ = os.ReadFile("testdata/tengine-status.txt")
+	testVTSData, _           = os.ReadFile("testdata/vts-v0.1.18.json")
 )
 
 func TestNginx_Cleanup(t *testing.T) { New().Cleanup() }
@@ -124,6 +127,9 @@ func TestNginx_InvalidData(t *testing.T) {
 	job.URL = ts.URL
 	require.True(t, job.Init())
 	assert.False(t, job.Check())
+
+	_, err := job.apiClient.getStubStatus()
+	assert.ErrorContains(t, err, "nginx-module-vts")
 }
 
 func TestNginx_404(t *testing.T) {
@@ -139,3 +145,64 @@ func TestNginx_404(t *testing.T) {
 	require.True(t, job.Init())
 	assert.False(t, job.Check())
 }
+
+func TestNginx_CollectUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "nginx-status.sock")
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	ts := httptest.NewUnstartedServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(testStatusData)
+			}))
+	_ = ts.Listener.Close()
+	ts.Listener = ln
+	ts.Start()
+	defer ts.Close()
+
+	job := New()
+	job.URL = "unix://" + socket
+	require.True(t, job.Init())
+	require.True(t, job.Check())
+
+	expected := map[string]int64{
+		"accepts":  36,
+		"active":   1,
+		"handled":  36,
+		"reading":  0,
+		"requests": 126,
+		"waiting":  0,
+		"writing":  1,
+	}
+
+	assert.Equal(t, expected, job.Collect())
+}
+
+func TestNginx_CollectVTS(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(testVTSData)
+			}))
+	defer ts.Close()
+
+	job := New()
+	job.URL = ts.URL
+	require.True(t, job.Init())
+	require.True(t, job.Check())
+	require.NotNil(t, job.vts)
+
+	mx := job.Collect()
+
+	assert.Equal(t, int64(2), mx["connections_active"])
+	assert.Equal(t, int64(17), mx["connections_requests"])
+	assert.Equal(t, int64(319), mx["uptime"])
+	for _, chart := range *job.Charts() {
+		for _, dim := range chart.Dims {
+			_, ok := mx[dim.ID]
+			assert.Truef(t, ok, "chart '%s' dim '%s': no dim in collected", chart.ID, dim.ID)
+		}
+	}
+}
@@ -72,6 +72,8 @@ const (
 	prioStreamUpstreamServerConnectionsRate
 	prioStreamUpstreamServerConnectionsCount
 
+	prioStreamUpstreamServerResponseTime
+
 	prioStreamUpstreamServerTrafficRate
 
 	prioResolverZoneRequestsRate
@@ -650,6 +652,7 @@ var (
 		streamUpstreamServerConnectionsRateChartTmpl.Copy(),
 		streamUpstreamServerTrafficRateChartTmpl.Copy(),
 		streamUpstreamServerConnectionsCountChartTmpl.Copy(),
+		streamUpstreamServerResponseTimeChartTmpl.Copy(),
 		streamUpstreamServerStateChartTmpl.Copy(),
 		streamUpstreamServerDowntimeChartTmpl.Copy(),
 	}
@@ -714,6 +717,19 @@ var (
 			{ID: "stream_upstream_%s_server_%s_zone_%s_active", Name: "active"},
 		},
 	}
+	streamUpstreamServerResponseTimeChartTmpl = module.Chart{
+		ID:       "stream_upstream_%s_server_%s_zone_%s_response_time",
+		Title:    "Stream Upstream Server average connect, first byte and response time",
+		Units:    "milliseconds",
+		Fam:      "stream upstream response time",
+		Ctx:      "nginxplus.stream_upstream_server_response_time",
+		Priority: prioStreamUpstreamServerResponseTime,
+		Dims: module.Dims{
+			{ID: "stream_upstream_%s_server_%s_zone_%s_connect_time", Name: "connect"},
+			{ID: "stream_upstream_%s_server_%s_zone_%s_first_byte_time", Name: "first_byte"},
+			{ID: "stream_upstream_%s_server_%s_zone_%s_response_time", Name: "response"},
+		},
+	}
 )
 
 var (
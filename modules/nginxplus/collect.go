@@ -229,6 +229,9 @@ func (n *NginxPlus) collectStreamUpstreams(mx map[string]int64, ms *nginxMetrics
 			px = fmt.Sprintf("stream_upstream_%s_server_%s_zone_%s_", name, peer.Server, upstream.Zone)
 			mx[px+"active"] = peer.Active
 			mx[px+"connections"] = peer.Connections
+			mx[px+"connect_time"] = peer.ConnectTime
+			mx[px+"first_byte_time"] = peer.FirstByteTime
+			mx[px+"response_time"] = peer.ResponseTime
 			mx[px+"state_up"] = boolToInt(peer.State == "up")
 			mx[px+"state_down"] = boolToInt(peer.State == "down")
 			mx[px+"state_unavail"] = boolToInt(peer.State == "unavail")
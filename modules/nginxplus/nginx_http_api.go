@@ -169,19 +169,22 @@ type (
 	}
 	nginxStreamUpstreams map[string]struct {
 		Peers []struct {
-			Id           int64  `json:"id"`
-			Server       string `json:"server"`
-			Name         string `json:"name"`
-			Backup       bool   `json:"backup"`
-			Weight       int64  `json:"weight"`
-			State        string `json:"state"`
-			Active       int64  `json:"active"`
-			Connections  int64  `json:"connections"`
-			Sent         int64  `json:"sent"`
-			Received     int64  `json:"received"`
-			Fails        int64  `json:"fails"`
-			Unavail      int64  `json:"unavail"`
-			HealthChecks struct {
+			Id            int64  `json:"id"`
+			Server        string `json:"server"`
+			Name          string `json:"name"`
+			Backup        bool   `json:"backup"`
+			Weight        int64  `json:"weight"`
+			State         string `json:"state"`
+			Active        int64  `json:"active"`
+			Connections   int64  `json:"connections"`
+			ConnectTime   int64  `json:"connect_time"`
+			FirstByteTime int64  `json:"first_byte_time"`
+			ResponseTime  int64  `json:"response_time"`
+			Sent          int64  `json:"sent"`
+			Received      int64  `json:"received"`
+			Fails         int64  `json:"fails"`
+			Unavail       int64  `json:"unavail"`
+			HealthChecks  struct {
 				Checks    int64 `json:"checks"`
 				Fails     int64 `json:"fails"`
 				Unhealthy int64 `json:"unhealthy"`
@@ -275,9 +275,12 @@ func TestNginxPlus_Collect(t *testing.T) {
 				"stream_server_zone_tcp_server_sessions_4xx":                                             0,
 				"stream_server_zone_tcp_server_sessions_5xx":                                             0,
 				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_active":          0,
-				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_bytes_received":  0,
-				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_bytes_sent":      0,
-				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_connections":     0,
+				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_bytes_received":  4475,
+				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_bytes_sent":      1290,
+				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_connections":     15,
+				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_connect_time":    1,
+				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_first_byte_time": 3,
+				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_response_time":   27,
 				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_downtime":        0,
 				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_state_checking":  0,
 				"stream_upstream_stream_backend_server_127.0.0.1:12346_zone_tcp_servers_state_down":      0,
@@ -288,6 +291,9 @@ func TestNginxPlus_Collect(t *testing.T) {
 				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_bytes_received":  0,
 				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_bytes_sent":      0,
 				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_connections":     0,
+				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_connect_time":    0,
+				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_first_byte_time": 0,
+				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_response_time":   0,
 				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_downtime":        0,
 				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_state_checking":  0,
 				"stream_upstream_stream_backend_server_127.0.0.1:12347_zone_tcp_servers_state_down":      0,
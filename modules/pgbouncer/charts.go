@@ -14,6 +14,7 @@ const (
 	prioDBClientConnections
 	prioDBServerConnections
 	prioDBServerConnectionsUtilization
+	prioDBServerPoolUtilization
 	prioDBClientsWaitTime
 	prioDBClientsWaitMaxTime
 	prioDBTransactions
@@ -48,6 +49,7 @@ var (
 		dbClientConnectionsTmpl.Copy(),
 
 		dbServerConnectionsUtilizationTmpl.Copy(),
+		dbServerPoolUtilizationTmpl.Copy(),
 		dbServerConnectionsTmpl.Copy(),
 
 		dbClientsWaitTimeChartTmpl.Copy(),
@@ -108,6 +110,18 @@ var (
 		},
 	}
 
+	dbServerPoolUtilizationTmpl = module.Chart{
+		ID:       "db_%s_server_pool_utilization",
+		Title:    "Database server connections pool utilization",
+		Units:    "percentage",
+		Fam:      "server connections limit",
+		Ctx:      "pgbouncer.db_server_pool_utilization",
+		Priority: prioDBServerPoolUtilization,
+		Dims: module.Dims{
+			{ID: "db_%s_sv_pool_utilization", Name: "used"},
+		},
+	}
+
 	dbClientsWaitTimeChartTmpl = module.Chart{
 		ID:       "db_%s_clients_wait_time",
 		Title:    "Database clients wait time",
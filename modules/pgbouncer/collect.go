@@ -97,7 +97,7 @@ func (p *PgBouncer) collectMetrics(mx map[string]int64) {
 		mx["db_"+name+"_avg_query_time"] = db.avgQueryTime
 
 		mx["db_"+name+"_total_wait_time"] = db.totalWaitTime
-		mx["db_"+name+"_maxwait"] = db.maxWait*1e6 + db.maxWaitUS
+		mx["db_"+name+"_maxwait"] = db.maxWait
 
 		mx["db_"+name+"_cl_active"] = db.clActive
 		mx["db_"+name+"_cl_waiting"] = db.clWaiting
@@ -114,6 +114,7 @@ func (p *PgBouncer) collectMetrics(mx map[string]int64) {
 		mx["db_"+name+"_total_sent"] = db.totalSent
 
 		mx["db_"+name+"_sv_conns_utilization"] = calcPercentage(db.currentConnections, db.maxConnections)
+		mx["db_"+name+"_sv_pool_utilization"] = calcPercentage(db.currentConnections, db.poolSize)
 	}
 
 	mx["cl_conns_utilization"] = calcPercentage(clientConns, p.maxClientConn)
@@ -131,6 +132,8 @@ func (p *PgBouncer) collectDatabases() error {
 			p.getDBMetrics(db).updated = true
 		case "database":
 			p.getDBMetrics(db).pgDBName = value
+		case "pool_size":
+			p.getDBMetrics(db).poolSize = parseInt(value)
 		case "max_connections":
 			p.getDBMetrics(db).maxConnections = parseInt(value)
 		case "current_connections":
@@ -181,16 +184,17 @@ func (p *PgBouncer) collectPools() error {
 
 	// an entry is made for each couple of (database, user).
 	var db string
+	var maxWait int64
 	return p.collectQuery(q, func(column, value string) {
 		switch column {
 		case "database":
-			db = value
+			db, maxWait = value, 0
 			p.getDBMetrics(db).updated = true
 		case "cl_active":
 			p.getDBMetrics(db).clActive += parseInt(value)
 		case "cl_waiting":
 			p.getDBMetrics(db).clWaiting += parseInt(value)
-		case "cl_cancel_req":
+		case "cl_cancel_req", "cl_active_cancel_req", "cl_waiting_cancel_req":
 			p.getDBMetrics(db).clCancelReq += parseInt(value)
 		case "sv_active":
 			p.getDBMetrics(db).svActive += parseInt(value)
@@ -203,9 +207,13 @@ func (p *PgBouncer) collectPools() error {
 		case "sv_login":
 			p.getDBMetrics(db).svLogin += parseInt(value)
 		case "maxwait":
-			p.getDBMetrics(db).maxWait += parseInt(value)
+			maxWait = parseInt(value) * 1e6
 		case "maxwait_us":
-			p.getDBMetrics(db).maxWaitUS += parseInt(value)
+			// the columns go in order: 'maxwait' (seconds part), 'maxwait_us' (microseconds part)
+			maxWait += parseInt(value)
+			if dbm := p.getDBMetrics(db); maxWait > dbm.maxWait {
+				dbm.maxWait = maxWait
+			}
 		}
 	})
 }
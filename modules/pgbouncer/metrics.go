@@ -15,6 +15,7 @@ type dbMetrics struct {
 	hasCharts bool
 
 	// command 'SHOW DATABASES;'
+	poolSize           int64
 	maxConnections     int64
 	currentConnections int64
 	paused             int64
@@ -36,12 +37,11 @@ type dbMetrics struct {
 	// https://github.com/pgbouncer/pgbouncer/blob/9a346b0e451d842d7202abc3eccf0ff5a66b2dd6/src/admin.c#L804
 	clActive    int64
 	clWaiting   int64
-	clCancelReq int64
+	clCancelReq int64 // v1.18+ splits it into 'cl_active_cancel_req' and 'cl_waiting_cancel_req'
 	svActive    int64
 	svIdle      int64
 	svUsed      int64
 	svTested    int64
 	svLogin     int64
-	maxWait     int64
-	maxWaitUS   int64 // v1.8+
+	maxWait     int64 // microseconds, the max among the database pools ('maxwait' + 'maxwait_us')
 }
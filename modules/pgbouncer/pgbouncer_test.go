@@ -24,6 +24,11 @@ var (
 	dataV1170Databases, _ = os.ReadFile("testdata/v1.17.0/databases.txt")
 	dataV1170Pools, _     = os.ReadFile("testdata/v1.17.0/pools.txt")
 	dataV1170Stats, _     = os.ReadFile("testdata/v1.17.0/stats.txt")
+	dataV1210Version, _   = os.ReadFile("testdata/v1.21.0/version.txt")
+	dataV1210Config, _    = os.ReadFile("testdata/v1.21.0/config.txt")
+	dataV1210Databases, _ = os.ReadFile("testdata/v1.21.0/databases.txt")
+	dataV1210Pools, _     = os.ReadFile("testdata/v1.21.0/pools.txt")
+	dataV1210Stats, _     = os.ReadFile("testdata/v1.21.0/stats.txt")
 )
 
 func Test_testDataIsValid(t *testing.T) {
@@ -34,6 +39,11 @@ func Test_testDataIsValid(t *testing.T) {
 		"dataV1170Databases": dataV1170Databases,
 		"dataV1170Pools":     dataV1170Pools,
 		"dataV1170Stats":     dataV1170Stats,
+		"dataV1210Version":   dataV1210Version,
+		"dataV1210Config":    dataV1210Config,
+		"dataV1210Databases": dataV1210Databases,
+		"dataV1210Pools":     dataV1210Pools,
+		"dataV1210Stats":     dataV1210Stats,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -162,6 +172,7 @@ func TestPgBouncer_Collect(t *testing.T) {
 						"db_myprod1_sv_conns_utilization":   0,
 						"db_myprod1_sv_idle":                5,
 						"db_myprod1_sv_login":               0,
+						"db_myprod1_sv_pool_utilization":    100,
 						"db_myprod1_sv_tested":              0,
 						"db_myprod1_sv_used":                0,
 						"db_myprod1_total_query_count":      12683170,
@@ -181,6 +192,7 @@ func TestPgBouncer_Collect(t *testing.T) {
 						"db_myprod2_sv_conns_utilization":   0,
 						"db_myprod2_sv_idle":                9,
 						"db_myprod2_sv_login":               0,
+						"db_myprod2_sv_pool_utilization":    100,
 						"db_myprod2_sv_tested":              0,
 						"db_myprod2_sv_used":                0,
 						"db_myprod2_total_query_count":      12538544,
@@ -200,6 +212,7 @@ func TestPgBouncer_Collect(t *testing.T) {
 						"db_pgbouncer_sv_conns_utilization": 0,
 						"db_pgbouncer_sv_idle":              0,
 						"db_pgbouncer_sv_login":             0,
+						"db_pgbouncer_sv_pool_utilization":  0,
 						"db_pgbouncer_sv_tested":            0,
 						"db_pgbouncer_sv_used":              0,
 						"db_pgbouncer_total_query_count":    45,
@@ -219,6 +232,7 @@ func TestPgBouncer_Collect(t *testing.T) {
 						"db_postgres_sv_conns_utilization":  0,
 						"db_postgres_sv_idle":               2,
 						"db_postgres_sv_login":              0,
+						"db_postgres_sv_pool_utilization":   100,
 						"db_postgres_sv_tested":             0,
 						"db_postgres_sv_used":               0,
 						"db_postgres_total_query_count":     25328823,
@@ -234,6 +248,123 @@ func TestPgBouncer_Collect(t *testing.T) {
 				},
 			},
 		},
+		"Success on all queries (v1.21.0)": {
+			{
+				prepareMock: func(t *testing.T, m sqlmock.Sqlmock) {
+					mockExpect(t, m, queryShowVersion, dataV1210Version)
+					mockExpect(t, m, queryShowConfig, dataV1210Config)
+					mockExpect(t, m, queryShowDatabases, dataV1210Databases)
+					mockExpect(t, m, queryShowStats, dataV1210Stats)
+					mockExpect(t, m, queryShowPools, dataV1210Pools)
+				},
+				check: func(t *testing.T, p *PgBouncer) {
+					mx := p.Collect()
+
+					expected := map[string]int64{
+						"cl_conns_utilization":              28,
+						"db_myprod1_avg_query_time":         1666,
+						"db_myprod1_avg_xact_time":          3000,
+						"db_myprod1_cl_active":              14,
+						"db_myprod1_cl_cancel_req":          2,
+						"db_myprod1_cl_waiting":             3,
+						"db_myprod1_maxwait":                1500000,
+						"db_myprod1_sv_active":              14,
+						"db_myprod1_sv_conns_utilization":   0,
+						"db_myprod1_sv_idle":                2,
+						"db_myprod1_sv_login":               0,
+						"db_myprod1_sv_pool_utilization":    80,
+						"db_myprod1_sv_tested":              0,
+						"db_myprod1_sv_used":                0,
+						"db_myprod1_total_query_count":      1500,
+						"db_myprod1_total_query_time":       2500000,
+						"db_myprod1_total_received":         50000,
+						"db_myprod1_total_sent":             120000,
+						"db_myprod1_total_wait_time":        400000,
+						"db_myprod1_total_xact_count":       1000,
+						"db_myprod1_total_xact_time":        3000000,
+						"db_myprod2_avg_query_time":         2000,
+						"db_myprod2_avg_xact_time":          2000,
+						"db_myprod2_cl_active":              8,
+						"db_myprod2_cl_cancel_req":          0,
+						"db_myprod2_cl_waiting":             0,
+						"db_myprod2_maxwait":                0,
+						"db_myprod2_sv_active":              8,
+						"db_myprod2_sv_conns_utilization":   0,
+						"db_myprod2_sv_idle":                2,
+						"db_myprod2_sv_login":               0,
+						"db_myprod2_sv_pool_utilization":    100,
+						"db_myprod2_sv_tested":              0,
+						"db_myprod2_sv_used":                0,
+						"db_myprod2_total_query_count":      700,
+						"db_myprod2_total_query_time":       1400000,
+						"db_myprod2_total_received":         30000,
+						"db_myprod2_total_sent":             90000,
+						"db_myprod2_total_wait_time":        0,
+						"db_myprod2_total_xact_count":       700,
+						"db_myprod2_total_xact_time":        1400000,
+						"db_pgbouncer_avg_query_time":       0,
+						"db_pgbouncer_avg_xact_time":        0,
+						"db_pgbouncer_cl_active":            1,
+						"db_pgbouncer_cl_cancel_req":        0,
+						"db_pgbouncer_cl_waiting":           0,
+						"db_pgbouncer_maxwait":              0,
+						"db_pgbouncer_sv_active":            0,
+						"db_pgbouncer_sv_conns_utilization": 0,
+						"db_pgbouncer_sv_idle":              0,
+						"db_pgbouncer_sv_login":             0,
+						"db_pgbouncer_sv_pool_utilization":  0,
+						"db_pgbouncer_sv_tested":            0,
+						"db_pgbouncer_sv_used":              0,
+						"db_pgbouncer_total_query_count":    5,
+						"db_pgbouncer_total_query_time":     0,
+						"db_pgbouncer_total_received":       0,
+						"db_pgbouncer_total_sent":           0,
+						"db_pgbouncer_total_wait_time":      0,
+						"db_pgbouncer_total_xact_count":     5,
+						"db_pgbouncer_total_xact_time":      0,
+					}
+
+					assert.Equal(t, expected, mx)
+				},
+			},
+		},
+		"Database removed at runtime": {
+			{
+				prepareMock: func(t *testing.T, m sqlmock.Sqlmock) {
+					mockExpect(t, m, queryShowVersion, dataV1170Version)
+					mockExpect(t, m, queryShowConfig, dataV1170Config)
+					mockExpect(t, m, queryShowDatabases, dataV1170Databases)
+					mockExpect(t, m, queryShowStats, dataV1170Stats)
+					mockExpect(t, m, queryShowPools, dataV1170Pools)
+				},
+				check: func(t *testing.T, p *PgBouncer) {
+					mx := p.Collect()
+
+					assert.Contains(t, mx, "db_postgres_sv_pool_utilization")
+					assert.Len(t, *p.Charts(), len(globalCharts)+len(dbChartsTmpl)*4)
+				},
+			},
+			{
+				prepareMock: func(t *testing.T, m sqlmock.Sqlmock) {
+					mockExpect(t, m, queryShowConfig, dataV1210Config)
+					mockExpect(t, m, queryShowDatabases, dataV1210Databases)
+					mockExpect(t, m, queryShowStats, dataV1210Stats)
+					mockExpect(t, m, queryShowPools, dataV1210Pools)
+				},
+				check: func(t *testing.T, p *PgBouncer) {
+					mx := p.Collect()
+
+					assert.NotContains(t, mx, "db_postgres_sv_pool_utilization")
+					for _, chart := range *p.Charts() {
+						if strings.HasPrefix(chart.ID, "db_postgres_") {
+							assert.Truef(t, chart.Obsolete, "chart '%s' is not obsolete", chart.ID)
+						} else {
+							assert.Falsef(t, chart.Obsolete, "chart '%s' is obsolete", chart.ID)
+						}
+					}
+				},
+			},
+		},
 		"Fail when querying version returns an error": {
 			{
 				prepareMock: func(t *testing.T, m sqlmock.Sqlmock) {
@@ -2,7 +2,12 @@
 
 package phpfpm
 
-import "github.com/netdata/go.d.plugin/agent/module"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
 
 type (
 	// Charts is an alias for module.Charts
@@ -82,3 +87,112 @@ var charts = Charts{
 		},
 	},
 }
+
+var poolChartsTmpl = Charts{
+	{
+		ID:    "pool_%s_processes",
+		Title: "Pool Processes",
+		Units: "processes",
+		Fam:   "pools",
+		Ctx:   "phpfpm.pool_processes",
+		Type:  module.Stacked,
+		Dims: Dims{
+			{ID: "pool_%s_active", Name: "active"},
+			{ID: "pool_%s_idle", Name: "idle"},
+		},
+	},
+	{
+		ID:    "pool_%s_requests",
+		Title: "Pool Requests",
+		Units: "requests/s",
+		Fam:   "pools",
+		Ctx:   "phpfpm.pool_requests",
+		Dims: Dims{
+			{ID: "pool_%s_requests", Name: "requests", Algo: module.Incremental},
+		},
+	},
+	{
+		ID:    "pool_%s_listen_queue",
+		Title: "Pool Listen Queue",
+		Units: "connections",
+		Fam:   "pools",
+		Ctx:   "phpfpm.pool_listen_queue",
+		Dims: Dims{
+			{ID: "pool_%s_listenQueue", Name: "queued"},
+		},
+	},
+	{
+		ID:    "pool_%s_performance",
+		Title: "Pool Performance",
+		Units: "status",
+		Fam:   "pools",
+		Ctx:   "phpfpm.pool_performance",
+		Dims: Dims{
+			{ID: "pool_%s_reached", Name: "max children reached"},
+			{ID: "pool_%s_slow", Name: "slow requests"},
+		},
+	},
+}
+
+var poolProcessesChartsTmpl = Charts{
+	{
+		ID:    "pool_%s_request_duration",
+		Title: "Pool Requests Duration Among All Idle Processes",
+		Units: "milliseconds",
+		Fam:   "pools",
+		Ctx:   "phpfpm.pool_request_duration",
+		Dims: Dims{
+			{ID: "pool_%s_minReqDur", Name: "min", Div: 1000},
+			{ID: "pool_%s_maxReqDur", Name: "max", Div: 1000},
+			{ID: "pool_%s_avgReqDur", Name: "avg", Div: 1000},
+		},
+	},
+	{
+		ID:    "pool_%s_request_mem",
+		Title: "Pool Last Request Memory Usage Among All Idle Processes",
+		Units: "KB",
+		Fam:   "pools",
+		Ctx:   "phpfpm.pool_request_mem",
+		Dims: Dims{
+			{ID: "pool_%s_minReqMem", Name: "min", Div: 1024},
+			{ID: "pool_%s_maxReqMem", Name: "max", Div: 1024},
+			{ID: "pool_%s_avgReqMem", Name: "avg", Div: 1024},
+		},
+	},
+}
+
+func (p *Phpfpm) addPoolCharts(pool string) {
+	p.addPoolChartsFromTmpl(poolChartsTmpl, pool)
+}
+
+func (p *Phpfpm) addPoolProcessesCharts(pool string) {
+	p.addPoolChartsFromTmpl(poolProcessesChartsTmpl, pool)
+}
+
+func (p *Phpfpm) addPoolChartsFromTmpl(tmpl Charts, pool string) {
+	charts := tmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, pool)
+		chart.Labels = []module.Label{
+			{Key: "pool", Value: pool},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, pool)
+		}
+	}
+
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
+func (p *Phpfpm) removePoolCharts(pool string) {
+	px := fmt.Sprintf("pool_%s_", pool)
+	for _, chart := range *p.Charts() {
+		if strings.HasPrefix(chart.ID, px) {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
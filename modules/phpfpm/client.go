@@ -18,13 +18,15 @@ import (
 
 type (
 	status struct {
-		Active    int64  `json:"active processes" stm:"active"`
-		MaxActive int64  `json:"max active processes" stm:"maxActive"`
-		Idle      int64  `json:"idle processes" stm:"idle"`
-		Requests  int64  `json:"accepted conn" stm:"requests"`
-		Reached   int64  `json:"max children reached" stm:"reached"`
-		Slow      int64  `json:"slow requests" stm:"slow"`
-		Processes []proc `json:"processes"`
+		Pool        string `json:"pool"`
+		Active      int64  `json:"active processes" stm:"active"`
+		MaxActive   int64  `json:"max active processes" stm:"maxActive"`
+		Idle        int64  `json:"idle processes" stm:"idle"`
+		Requests    int64  `json:"accepted conn" stm:"requests"`
+		Reached     int64  `json:"max children reached" stm:"reached"`
+		Slow        int64  `json:"slow requests" stm:"slow"`
+		ListenQueue int64  `json:"listen queue"`
+		Processes   []proc `json:"processes"`
 	}
 	proc struct {
 		PID      int64           `json:"pid"`
@@ -105,7 +107,7 @@ type socketClient struct {
 	env     map[string]string
 }
 
-func newSocketClient(socket string, timeout time.Duration, fcgiPath string) *socketClient {
+func newSocketClient(socket string, timeout time.Duration, fcgiPath string, full bool) *socketClient {
 	return &socketClient{
 		socket:  socket,
 		timeout: timeout,
@@ -114,7 +116,7 @@ func newSocketClient(socket string, timeout time.Duration, fcgiPath string) *soc
 			"SCRIPT_FILENAME": fcgiPath,
 			"SERVER_SOFTWARE": "go / fcgiclient ",
 			"REMOTE_ADDR":     "127.0.0.1",
-			"QUERY_STRING":    "json&full",
+			"QUERY_STRING":    statusQuery(full),
 			"REQUEST_METHOD":  "GET",
 			"CONTENT_TYPE":    "application/json",
 		},
@@ -145,13 +147,21 @@ func (c *socketClient) getStatus() (*status, error) {
 	return st, nil
 }
 
+// statusQuery returns the status page query string, 'full' adds the per process details.
+func statusQuery(full bool) string {
+	if full {
+		return "json&full"
+	}
+	return "json"
+}
+
 type tcpClient struct {
 	address string
 	timeout time.Duration
 	env     map[string]string
 }
 
-func newTcpClient(address string, timeout time.Duration, fcgiPath string) *tcpClient {
+func newTcpClient(address string, timeout time.Duration, fcgiPath string, full bool) *tcpClient {
 	return &tcpClient{
 		address: address,
 		timeout: timeout,
@@ -160,7 +170,7 @@ func newTcpClient(address string, timeout time.Duration, fcgiPath string) *tcpCl
 			"SCRIPT_FILENAME": fcgiPath,
 			"SERVER_SOFTWARE": "go / fcgiclient ",
 			"REMOTE_ADDR":     "127.0.0.1",
-			"QUERY_STRING":    "json&full",
+			"QUERY_STRING":    statusQuery(full),
 			"REQUEST_METHOD":  "GET",
 			"CONTENT_TYPE":    "application/json",
 		},
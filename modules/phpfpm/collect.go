@@ -9,6 +9,10 @@ import (
 )
 
 func (p *Phpfpm) collect() (map[string]int64, error) {
+	if len(p.poolPatterns) > 0 {
+		return p.collectPools()
+	}
+
 	st, err := p.client.getStatus()
 	if err != nil {
 		return nil, err
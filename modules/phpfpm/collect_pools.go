@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package phpfpm
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/netdata/go.d.plugin/pkg/stm"
+)
+
+type poolSource struct {
+	client client
+	name   string // the pool name reported by php-fpm
+	failed int    // consecutive failed collections
+}
+
+func (p *Phpfpm) collectPools() (map[string]int64, error) {
+	p.discoverPools()
+
+	sources := make([]string, 0, len(p.pools))
+	for src := range p.pools {
+		sources = append(sources, src)
+	}
+	sort.Strings(sources)
+
+	mx := make(map[string]int64)
+	seen := make(map[string]bool)
+
+	for _, src := range sources {
+		ps := p.pools[src]
+
+		st, err := ps.client.getStatus()
+		if err != nil {
+			// a pool that is restarting or is removed (the socket is gone) fails for some time
+			if ps.failed++; ps.failed > p.PoolAbsentCycles {
+				p.Debugf("pool '%s' (%s) failed for more than %d collections: removing charts", ps.name, src, p.PoolAbsentCycles)
+				delete(p.pools, src)
+				p.removePool(ps.name)
+			} else {
+				p.Warning(err)
+			}
+			continue
+		}
+		ps.failed = 0
+
+		name := st.Pool
+		if name == "" {
+			name = filepath.Base(src)
+		}
+		if seen[name] {
+			p.Warningf("pool '%s' (%s): a pool with the same name is already collected, skipping", name, src)
+			continue
+		}
+		seen[name] = true
+		if ps.name != "" && ps.name != name {
+			p.removePool(ps.name)
+		}
+		ps.name = name
+
+		p.collectPool(mx, name, st)
+	}
+
+	return mx, nil
+}
+
+func (p *Phpfpm) collectPool(mx map[string]int64, name string, st *status) {
+	if !p.chartedPools[name] {
+		p.chartedPools[name] = true
+		p.addPoolCharts(name)
+	}
+
+	pmx := stm.ToMap(st)
+	pmx["listenQueue"] = st.ListenQueue
+
+	if hasIdleProcesses(st.Processes) {
+		if !p.chartedPoolProcs[name] {
+			p.chartedPoolProcs[name] = true
+			p.addPoolProcessesCharts(name)
+		}
+		calcIdleProcessesRequestsDuration(pmx, st.Processes)
+		calcIdleProcessesLastRequestMemory(pmx, st.Processes)
+	}
+
+	px := "pool_" + name + "_"
+	for k, v := range pmx {
+		mx[px+k] = v
+	}
+}
+
+// discoverPools creates the clients for the new pools, the socket glob patterns are expanded on every collection.
+func (p *Phpfpm) discoverPools() {
+	for _, pattern := range p.poolPatterns {
+		sources := []string{pattern}
+		if isSocketPath(pattern) && isGlobPattern(pattern) {
+			sources, _ = filepath.Glob(pattern)
+		}
+
+		for _, src := range sources {
+			if _, ok := p.pools[src]; ok {
+				continue
+			}
+			c, err := p.newPoolClient(src)
+			if err != nil {
+				p.Warningf("pool '%s': %v", src, err)
+				continue
+			}
+			p.Debugf("new pool '%s'", src)
+			p.pools[src] = &poolSource{client: c}
+		}
+	}
+}
+
+func (p *Phpfpm) removePool(name string) {
+	if !p.chartedPools[name] {
+		return
+	}
+	delete(p.chartedPools, name)
+	delete(p.chartedPoolProcs, name)
+	p.removePoolCharts(name)
+}
@@ -65,6 +65,10 @@ func readStatus(data []string, s *status) error {
 		}
 
 		switch key {
+		case "pool":
+			s.Pool = val
+		case "listen queue":
+			s.ListenQueue = parseInt(val)
 		case "active processes":
 			s.Active = parseInt(val)
 		case "max active processes":
@@ -6,10 +6,84 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
+func (p Phpfpm) validateConfig() error {
+	if p.PoolAbsentCycles < 0 {
+		return errors.New("'pool_absent_cycles' can not be negative")
+	}
+	for _, pool := range p.Pools {
+		if pool == "" {
+			return errors.New("'pools' contains an empty entry")
+		}
+		if isSocketPath(pool) {
+			if _, err := filepath.Match(pool, ""); err != nil {
+				return fmt.Errorf("bad socket pattern '%s': %v", pool, err)
+			}
+		}
+	}
+	if isGlobPattern(p.Socket) {
+		if _, err := filepath.Match(p.Socket, ""); err != nil {
+			return fmt.Errorf("bad socket pattern '%s': %v", p.Socket, err)
+		}
+	}
+	return nil
+}
+
+// initPoolPatterns returns the pools to collect in the multiple pools mode,
+// the mode is enabled if 'pools' is set or 'socket' is a glob pattern.
+func (p Phpfpm) initPoolPatterns() []string {
+	var patterns []string
+	if isGlobPattern(p.Socket) {
+		patterns = append(patterns, p.Socket)
+	}
+	return append(patterns, p.Pools...)
+}
+
+func (p *Phpfpm) initPoolsHTTPClient(patterns []string) error {
+	for _, pattern := range patterns {
+		if isHTTPURL(pattern) {
+			c, err := web.NewHTTPClient(p.Client)
+			if err != nil {
+				return fmt.Errorf("create HTTP client: %v", err)
+			}
+			p.httpClient = c
+			return nil
+		}
+	}
+	return nil
+}
+
+// newPoolClient creates a client for a 'pools' entry: an HTTP URL, a unix socket path or a 'host:port' address.
+func (p Phpfpm) newPoolClient(source string) (client, error) {
+	switch {
+	case isHTTPURL(source):
+		req := p.Request.Copy()
+		req.URL = source
+		return newHTTPClient(p.httpClient, req)
+	case isSocketPath(source):
+		return newSocketClient(source, p.Timeout.Duration, p.FcgiPath, p.FullStatus), nil
+	default:
+		return newTcpClient(source, p.Timeout.Duration, p.FcgiPath, p.FullStatus), nil
+	}
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+func isSocketPath(s string) bool {
+	return strings.HasPrefix(s, "/")
+}
+
+func isGlobPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
 func (p Phpfpm) initClient() (client, error) {
 	if p.Socket != "" {
 		return p.initSocketClient()
@@ -40,12 +114,12 @@ func (p Phpfpm) initSocketClient() (*socketClient, error) {
 	p.Debugf("using socket client: %s", p.Socket)
 	p.Debugf("using timeout: %s", p.Timeout.Duration)
 	p.Debugf("using fcgi path: %s", p.FcgiPath)
-	return newSocketClient(p.Socket, p.Timeout.Duration, p.FcgiPath), nil
+	return newSocketClient(p.Socket, p.Timeout.Duration, p.FcgiPath, p.FullStatus), nil
 }
 
 func (p Phpfpm) initTcpClient() (*tcpClient, error) {
 	p.Debugf("using tcp client: %s", p.Address)
 	p.Debugf("using timeout: %s", p.Timeout.Duration)
 	p.Debugf("using fcgi path: %s", p.FcgiPath)
-	return newTcpClient(p.Address, p.Timeout.Duration, p.FcgiPath), nil
+	return newTcpClient(p.Address, p.Timeout.Duration, p.FcgiPath, p.FullStatus), nil
 }
@@ -4,6 +4,7 @@ package phpfpm
 
 import (
 	_ "embed"
+	"net/http"
 	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/web"
@@ -32,27 +33,60 @@ func New() *Phpfpm {
 					Timeout: web.Duration{Duration: time.Second},
 				},
 			},
-			FcgiPath: "/status",
+			FcgiPath:         "/status",
+			FullStatus:       true,
+			PoolAbsentCycles: 10,
 		},
+		charts:           charts.Copy(),
+		pools:            make(map[string]*poolSource),
+		chartedPools:     make(map[string]bool),
+		chartedPoolProcs: make(map[string]bool),
 	}
 }
 
 type (
 	Config struct {
-		web.HTTP `yaml:",inline"`
-		Socket   string `yaml:"socket"`
-		Address  string `yaml:"address"`
-		FcgiPath string `yaml:"fcgi_path"`
+		web.HTTP         `yaml:",inline"`
+		Socket           string   `yaml:"socket"`
+		Address          string   `yaml:"address"`
+		FcgiPath         string   `yaml:"fcgi_path"`
+		FullStatus       bool     `yaml:"full_status"`
+		Pools            []string `yaml:"pools"`
+		PoolAbsentCycles int      `yaml:"pool_absent_cycles"`
 	}
 	Phpfpm struct {
 		module.Base
 		Config `yaml:",inline"`
 
+		charts *Charts
+
 		client client
+
+		// multiple pools mode: 'pools' is set or 'socket' is a glob pattern
+		poolPatterns     []string
+		httpClient       *http.Client
+		pools            map[string]*poolSource // by socket path, address or URL
+		chartedPools     map[string]bool        // by pool name
+		chartedPoolProcs map[string]bool        // by pool name
 	}
 )
 
 func (p *Phpfpm) Init() bool {
+	if err := p.validateConfig(); err != nil {
+		p.Errorf("config validation: %v", err)
+		return false
+	}
+
+	if patterns := p.initPoolPatterns(); len(patterns) > 0 {
+		if err := p.initPoolsHTTPClient(patterns); err != nil {
+			p.Errorf("init pools: %v", err)
+			return false
+		}
+		p.poolPatterns = patterns
+		p.charts = &Charts{}
+		return true
+	}
+
 	c, err := p.initClient()
 	if err != nil {
 		p.Errorf("init client: %v", err)
@@ -66,8 +100,8 @@ func (p *Phpfpm) Check() bool {
 	return len(p.Collect()) > 0
 }
 
-func (Phpfpm) Charts() *Charts {
-	return charts.Copy()
+func (p *Phpfpm) Charts() *Charts {
+	return p.charts
 }
 
 func (p *Phpfpm) Collect() map[string]int64 {
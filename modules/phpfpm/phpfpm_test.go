@@ -3,9 +3,11 @@
 package phpfpm
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/netdata/go.d.plugin/agent/module"
@@ -44,6 +46,50 @@ func TestPhpfpm_Init(t *testing.T) {
 	assert.NotNil(t, job.client)
 }
 
+func TestPhpfpm_Init_Pools(t *testing.T) {
+	tests := map[string]struct {
+		config   func(p *Phpfpm)
+		wantFail bool
+	}{
+		"pools": {
+			config: func(p *Phpfpm) { p.Pools = []string{"/run/php/www.sock", "127.0.0.1:9000"} },
+		},
+		"socket glob pattern": {
+			config: func(p *Phpfpm) { p.Socket = "/run/php/php*-fpm.sock" },
+		},
+		"bad socket glob pattern": {
+			wantFail: true,
+			config:   func(p *Phpfpm) { p.Socket = "/run/php/[" },
+		},
+		"empty pool": {
+			wantFail: true,
+			config:   func(p *Phpfpm) { p.Pools = []string{""} },
+		},
+		"negative pool_absent_cycles": {
+			wantFail: true,
+			config: func(p *Phpfpm) {
+				p.Pools = []string{"/run/php/www.sock"}
+				p.PoolAbsentCycles = -1
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			job := New()
+			test.config(job)
+
+			if test.wantFail {
+				assert.False(t, job.Init())
+			} else {
+				require.True(t, job.Init())
+				assert.NotEmpty(t, job.poolPatterns)
+				assert.Nil(t, job.client)
+			}
+		})
+	}
+}
+
 func TestPhpfpm_Check(t *testing.T) {
 	ts := httptest.NewServer(
 		http.HandlerFunc(
@@ -274,6 +320,84 @@ func TestPhpfpm_CollectReturnsNothingWhenBadStatusCode(t *testing.T) {
 	assert.Len(t, got, 0)
 }
 
+func TestPhpfpm_CollectPools(t *testing.T) {
+	www := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(testStatusFullJSON)
+			}))
+	defer www.Close()
+	api := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(bytes.Replace(testStatusJSON, []byte(`"pool": "www"`), []byte(`"pool": "api"`), 1))
+			}))
+
+	job := New()
+	job.Pools = []string{www.URL + "/?json&full", api.URL + "/?json"}
+	job.PoolAbsentCycles = 1
+	require.True(t, job.Init())
+
+	got := job.Collect()
+
+	want := map[string]int64{
+		"pool_api_active":      1,
+		"pool_api_idle":        1,
+		"pool_api_listenQueue": 0,
+		"pool_api_maxActive":   1,
+		"pool_api_reached":     0,
+		"pool_api_requests":    21,
+		"pool_api_slow":        0,
+		"pool_www_active":      1,
+		"pool_www_avgReqDur":   459,
+		"pool_www_avgReqMem":   2095098,
+		"pool_www_idle":        1,
+		"pool_www_listenQueue": 0,
+		"pool_www_maxActive":   1,
+		"pool_www_maxReqDur":   919,
+		"pool_www_maxReqMem":   2097152,
+		"pool_www_minReqDur":   0,
+		"pool_www_minReqMem":   2093045,
+		"pool_www_reached":     0,
+		"pool_www_requests":    22,
+		"pool_www_slow":        0,
+	}
+	assert.Equal(t, want, got)
+	assert.Len(t, *job.Charts(), len(poolChartsTmpl)*2+len(poolProcessesChartsTmpl))
+	for _, chart := range *job.Charts() {
+		for _, dim := range chart.Dims {
+			_, ok := got[dim.ID]
+			assert.Truef(t, ok, "chart '%s' dim '%s': no dim in collected", chart.ID, dim.ID)
+		}
+	}
+
+	// the 'api' pool is gone, its charts are kept for 'pool_absent_cycles'
+	api.Close()
+
+	for i := 0; i < job.PoolAbsentCycles+1; i++ {
+		got = job.Collect()
+		require.NotNil(t, got)
+		assert.Contains(t, got, "pool_www_requests")
+		assert.NotContains(t, got, "pool_api_requests")
+	}
+
+	for _, chart := range *job.Charts() {
+		if strings.HasPrefix(chart.ID, "pool_api_") {
+			assert.Truef(t, chart.Obsolete, "chart '%s' is not obsolete", chart.ID)
+		} else {
+			assert.Falsef(t, chart.Obsolete, "chart '%s' is obsolete", chart.ID)
+		}
+	}
+}
+
+func Test_decodeText_PoolAndListenQueue(t *testing.T) {
+	var st status
+	require.NoError(t, decodeText(bytes.NewReader(testStatusFullText), &st))
+
+	assert.Equal(t, "www", st.Pool)
+	assert.Equal(t, int64(0), st.ListenQueue)
+}
+
 func TestPhpfpm_Cleanup(t *testing.T) {
 	New().Cleanup()
 }
@@ -12,6 +12,7 @@ import (
 const (
 	prioHostRTT = module.Priority + iota
 	prioHostStdDevRTT
+	prioHostRTTSpread
 	prioHostPingPacketLoss
 	prioHostPingPackets
 )
@@ -19,6 +20,7 @@ const (
 var hostChartsTmpl = module.Charts{
 	hostRTTChartTmpl.Copy(),
 	hostStdDevRTTChartTmpl.Copy(),
+	hostRTTSpreadChartTmpl.Copy(),
 	hostPacketLossChartTmpl.Copy(),
 	hostPacketsChartTmpl.Copy(),
 }
@@ -49,6 +51,17 @@ var (
 			{ID: "host_%s_std_dev_rtt", Name: "std_dev", Div: 1e3},
 		},
 	}
+	hostRTTSpreadChartTmpl = module.Chart{
+		ID:       "host_%s_rtt_spread",
+		Title:    "Ping round-trip time spread",
+		Units:    "milliseconds",
+		Fam:      "latency",
+		Ctx:      "ping.host_rtt_spread",
+		Priority: prioHostRTTSpread,
+		Dims: module.Dims{
+			{ID: "host_%s_rtt_spread", Name: "spread", Div: 1e3},
+		},
+	}
 )
 
 var hostPacketLossChartTmpl = module.Chart{
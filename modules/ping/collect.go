@@ -12,17 +12,19 @@ func (p *Ping) collect() (map[string]int64, error) {
 	mx := make(map[string]int64)
 	var wg sync.WaitGroup
 
-	for _, v := range p.Hosts {
+	for _, v := range p.targets {
 		wg.Add(1)
-		go func(v string) { defer wg.Done(); p.pingHost(v, mx, mu) }(v)
+		go func(v pingTarget) { defer wg.Done(); p.pingHost(v, mx, mu) }(v)
 	}
 	wg.Wait()
 
 	return mx, nil
 }
 
-func (p *Ping) pingHost(host string, mx map[string]int64, mu *sync.Mutex) {
-	stats, err := p.prober.ping(host)
+func (p *Ping) pingHost(target pingTarget, mx map[string]int64, mu *sync.Mutex) {
+	host := target.host
+
+	stats, err := p.prober.ping(host, target.conf)
 	if err != nil {
 		p.Error(err)
 		return
@@ -42,8 +44,9 @@ func (p *Ping) pingHost(host string, mx map[string]int64, mu *sync.Mutex) {
 		mx[px+"max_rtt"] = stats.MaxRtt.Microseconds()
 		mx[px+"avg_rtt"] = stats.AvgRtt.Microseconds()
 		mx[px+"std_dev_rtt"] = stats.StdDevRtt.Microseconds()
+		mx[px+"rtt_spread"] = (stats.MaxRtt - stats.MinRtt).Microseconds()
 	}
 	mx[px+"packets_recv"] = int64(stats.PacketsRecv)
 	mx[px+"packets_sent"] = int64(stats.PacketsSent)
-	mx[px+"packet_loss"] = int64(stats.PacketLoss * 1000)
+	mx[px+"packet_loss"] = int64(stats.PacketLoss() * 1000)
 }
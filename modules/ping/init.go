@@ -4,9 +4,16 @@ package ping
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
+const (
+	// minPacketSize is the timestamp and the tracker (UUID) the prober puts in every packet payload
+	minPacketSize = 8 + 16
+	maxPacketSize = 65507
+)
+
 func (p *Ping) validateConfig() error {
 	if len(p.Hosts) == 0 {
 		return errors.New("'hosts' can't be empty")
@@ -14,6 +21,30 @@ func (p *Ping) validateConfig() error {
 	if p.SendPackets <= 0 {
 		return errors.New("'send_packets' can't be <= 0")
 	}
+	if err := validateProbeOptions(p.PacketSize, p.TTL); err != nil {
+		return err
+	}
+	for i, h := range p.Hosts {
+		if h.Host == "" {
+			return fmt.Errorf("'hosts[%d].host' can't be empty", i)
+		}
+		if h.SendPackets < 0 {
+			return fmt.Errorf("host '%s': 'packets' can't be < 0", h.Host)
+		}
+		if err := validateProbeOptions(h.PacketSize, h.TTL); err != nil {
+			return fmt.Errorf("host '%s': %v", h.Host, err)
+		}
+	}
+	return nil
+}
+
+func validateProbeOptions(packetSize, ttl int) error {
+	if packetSize != 0 && (packetSize < minPacketSize || packetSize > maxPacketSize) {
+		return fmt.Errorf("'packet_size' must be in the range [%d, %d], got %d", minPacketSize, maxPacketSize, packetSize)
+	}
+	if ttl < 0 || ttl > 255 {
+		return fmt.Errorf("'ttl' must be in the range [1, 255], got %d", ttl)
+	}
 	return nil
 }
 
@@ -28,12 +59,38 @@ func (p *Ping) initProber() (prober, error) {
 	}
 
 	conf := pingProberConfig{
+		network:    p.Network,
 		privileged: p.Privileged,
-		packets:    p.SendPackets,
 		iface:      p.Interface,
-		interval:   p.Interval.Duration,
 		deadline:   deadline,
 	}
 
 	return p.newProber(conf, p.Logger), nil
 }
+
+// initTargets applies the per host overrides to the job probe options.
+func (p *Ping) initTargets() []pingTarget {
+	var targets []pingTarget
+	for _, h := range p.Hosts {
+		conf := probeConfig{
+			packets:  p.SendPackets,
+			interval: p.Interval.Duration,
+			size:     p.PacketSize,
+			ttl:      p.TTL,
+		}
+		if h.SendPackets > 0 {
+			conf.packets = h.SendPackets
+		}
+		if h.Interval.Duration > 0 {
+			conf.interval = h.Interval.Duration
+		}
+		if h.PacketSize > 0 {
+			conf.size = h.PacketSize
+		}
+		if h.TTL > 0 {
+			conf.ttl = h.TTL
+		}
+		targets = append(targets, pingTarget{host: h.Host, conf: conf})
+	}
+	return targets
+}
@@ -9,8 +9,6 @@ import (
 	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/netdata/go.d.plugin/logger"
 	"github.com/netdata/go.d.plugin/pkg/web"
-
-	probing "github.com/prometheus-community/pro-bing"
 )
 
 //go:embed "config_schema.json"
@@ -41,14 +39,37 @@ func New() *Ping {
 	}
 }
 
-type Config struct {
-	UpdateEvery int          `yaml:"update_every"`
-	Hosts       []string     `yaml:"hosts"`
-	Network     string       `yaml:"network"`
-	Privileged  bool         `yaml:"privileged"`
-	SendPackets int          `yaml:"packets"`
-	Interval    web.Duration `yaml:"interval"`
-	Interface   string       `yaml:"interface"`
+type (
+	Config struct {
+		UpdateEvery int          `yaml:"update_every"`
+		Hosts       []HostConfig `yaml:"hosts"`
+		Network     string       `yaml:"network"`
+		Privileged  bool         `yaml:"privileged"`
+		SendPackets int          `yaml:"packets"`
+		Interval    web.Duration `yaml:"interval"`
+		PacketSize  int          `yaml:"packet_size"`
+		TTL         int          `yaml:"ttl"`
+		Interface   string       `yaml:"interface"`
+	}
+	// HostConfig is a 'hosts' entry: either a host (a string) or an object that overrides the job probe options.
+	HostConfig struct {
+		Host        string       `yaml:"host"`
+		SendPackets int          `yaml:"packets"`
+		Interval    web.Duration `yaml:"interval"`
+		PacketSize  int          `yaml:"packet_size"`
+		TTL         int          `yaml:"ttl"`
+	}
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (h *HostConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var host string
+	if err := unmarshal(&host); err == nil {
+		*h = HostConfig{Host: host}
+		return nil
+	}
+	type plain HostConfig
+	return unmarshal((*plain)(h))
 }
 
 type (
@@ -58,13 +79,14 @@ type (
 
 		charts *module.Charts
 
-		hosts map[string]bool
+		hosts   map[string]bool
+		targets []pingTarget
 
 		newProber func(pingProberConfig, *logger.Logger) prober
 		prober    prober
 	}
 	prober interface {
-		ping(host string) (*probing.Statistics, error)
+		ping(host string, conf probeConfig) (*pingStats, error)
 	}
 )
 
@@ -82,6 +104,8 @@ func (p *Ping) Init() bool {
 	}
 	p.prober = pr
 
+	p.targets = p.initTargets()
+
 	return true
 }
 
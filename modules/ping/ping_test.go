@@ -4,14 +4,16 @@ package ping
 
 import (
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/netdata/go.d.plugin/logger"
+	"github.com/netdata/go.d.plugin/pkg/web"
 
-	probing "github.com/prometheus-community/pro-bing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestPing_Init(t *testing.T) {
@@ -27,7 +29,29 @@ func TestPing_Init(t *testing.T) {
 			wantFail: false,
 			config: Config{
 				SendPackets: 1,
-				Hosts:       []string{"192.0.2.0"},
+				Hosts:       []HostConfig{{Host: "192.0.2.0"}},
+			},
+		},
+		"fail when 'packet_size' is less than the minimum": {
+			wantFail: true,
+			config: Config{
+				SendPackets: 1,
+				PacketSize:  minPacketSize - 1,
+				Hosts:       []HostConfig{{Host: "192.0.2.0"}},
+			},
+		},
+		"fail when host 'ttl' is invalid": {
+			wantFail: true,
+			config: Config{
+				SendPackets: 1,
+				Hosts:       []HostConfig{{Host: "192.0.2.0", TTL: 256}},
+			},
+		},
+		"fail when host 'host' not set": {
+			wantFail: true,
+			config: Config{
+				SendPackets: 1,
+				Hosts:       []HostConfig{{PacketSize: 100}},
 			},
 		},
 	}
@@ -99,6 +123,7 @@ func TestPing_Collect(t *testing.T) {
 				"host_192.0.2.1_packets_recv":   5,
 				"host_192.0.2.1_packets_sent":   5,
 				"host_192.0.2.1_std_dev_rtt":    5000,
+				"host_192.0.2.1_rtt_spread":     10000,
 				"host_192.0.2.2_avg_rtt":        15000,
 				"host_192.0.2.2_max_rtt":        20000,
 				"host_192.0.2.2_min_rtt":        10000,
@@ -106,6 +131,7 @@ func TestPing_Collect(t *testing.T) {
 				"host_192.0.2.2_packets_recv":   5,
 				"host_192.0.2.2_packets_sent":   5,
 				"host_192.0.2.2_std_dev_rtt":    5000,
+				"host_192.0.2.2_rtt_spread":     10000,
 				"host_example.com_avg_rtt":      15000,
 				"host_example.com_max_rtt":      20000,
 				"host_example.com_min_rtt":      10000,
@@ -113,6 +139,7 @@ func TestPing_Collect(t *testing.T) {
 				"host_example.com_packets_recv": 5,
 				"host_example.com_packets_sent": 5,
 				"host_example.com_std_dev_rtt":  5000,
+				"host_example.com_rtt_spread":   10000,
 			},
 			wantNumCharts: 3 * len(hostChartsTmpl),
 		},
@@ -138,10 +165,127 @@ func TestPing_Collect(t *testing.T) {
 	}
 }
 
+func TestPing_Collect_PerHostOptions(t *testing.T) {
+	ping := New()
+	ping.UpdateEvery = 1
+	ping.PacketSize = 56
+	ping.Hosts = []HostConfig{
+		{Host: "192.0.2.1"},
+		{Host: "192.0.2.2", SendPackets: 10, Interval: web.Duration{Duration: time.Second}, PacketSize: 1400, TTL: 8},
+	}
+	mock := &mockProber{}
+	ping.newProber = func(_ pingProberConfig, _ *logger.Logger) prober { return mock }
+	require.True(t, ping.Init())
+
+	require.NotEmpty(t, ping.Collect())
+
+	assert.Equal(t, map[string]probeConfig{
+		"192.0.2.1": {packets: 5, interval: time.Millisecond * 100, size: 56},
+		"192.0.2.2": {packets: 10, interval: time.Second, size: 1400, ttl: 8},
+	}, mock.confs)
+}
+
+func TestHostConfig_UnmarshalYAML(t *testing.T) {
+	data := `
+hosts:
+  - 192.0.2.1
+  - host: 192.0.2.2
+    packets: 10
+    interval: 1s
+    packet_size: 1400
+    ttl: 8
+`
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal([]byte(data), &cfg))
+
+	assert.Equal(t, []HostConfig{
+		{Host: "192.0.2.1"},
+		{Host: "192.0.2.2", SendPackets: 10, Interval: web.Duration{Duration: time.Second}, PacketSize: 1400, TTL: 8},
+	}, cfg.Hosts)
+}
+
+func TestRTTAggregator_Stats(t *testing.T) {
+	type reply struct {
+		seq int
+		rtt time.Duration
+	}
+	ms := time.Millisecond
+
+	tests := map[string]struct {
+		sent      int
+		replies   []reply
+		wantStats pingStats
+	}{
+		"all replies in order": {
+			sent:    4,
+			replies: []reply{{0, 10 * ms}, {1, 20 * ms}, {2, 30 * ms}, {3, 40 * ms}},
+			wantStats: pingStats{
+				PacketsSent: 4, PacketsRecv: 4,
+				MinRtt: 10 * ms, MaxRtt: 40 * ms, AvgRtt: 25 * ms, StdDevRtt: 11180339,
+			},
+		},
+		"reordered replies": {
+			sent:    4,
+			replies: []reply{{3, 40 * ms}, {1, 20 * ms}, {0, 10 * ms}, {2, 30 * ms}},
+			wantStats: pingStats{
+				PacketsSent: 4, PacketsRecv: 4,
+				MinRtt: 10 * ms, MaxRtt: 40 * ms, AvgRtt: 25 * ms, StdDevRtt: 11180339,
+			},
+		},
+		"duplicated replies": {
+			sent:    4,
+			replies: []reply{{0, 10 * ms}, {0, 500 * ms}, {1, 20 * ms}, {2, 30 * ms}, {1, 1 * ms}, {3, 40 * ms}},
+			wantStats: pingStats{
+				PacketsSent: 4, PacketsRecv: 4, Duplicates: 2,
+				MinRtt: 10 * ms, MaxRtt: 40 * ms, AvgRtt: 25 * ms, StdDevRtt: 11180339,
+			},
+		},
+		"lost and unknown sequence replies": {
+			sent:    4,
+			replies: []reply{{1, 20 * ms}, {5, 1 * ms}, {3, 40 * ms}, {-1, 1 * ms}},
+			wantStats: pingStats{
+				PacketsSent: 4, PacketsRecv: 2,
+				MinRtt: 20 * ms, MaxRtt: 40 * ms, AvgRtt: 30 * ms, StdDevRtt: 10 * ms,
+			},
+		},
+		"RTTs deviating by seconds": {
+			sent:    2,
+			replies: []reply{{0, 1 * ms}, {1, 8001 * ms}},
+			wantStats: pingStats{
+				PacketsSent: 2, PacketsRecv: 2,
+				MinRtt: 1 * ms, MaxRtt: 8001 * ms, AvgRtt: 4001 * ms, StdDevRtt: 4000 * ms,
+			},
+		},
+		"no replies": {
+			sent:      4,
+			wantStats: pingStats{PacketsSent: 4},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			agg := newRTTAggregator()
+			for _, r := range test.replies {
+				agg.addReply(r.seq, r.rtt)
+			}
+
+			stats := agg.stats(test.sent)
+
+			assert.Equal(t, test.wantStats, *stats)
+		})
+	}
+}
+
+func TestPingStats_PacketLoss(t *testing.T) {
+	assert.Equal(t, 0.0, pingStats{}.PacketLoss())
+	assert.Equal(t, 25.0, pingStats{PacketsSent: 4, PacketsRecv: 3}.PacketLoss())
+	assert.Equal(t, 100.0, pingStats{PacketsSent: 4}.PacketLoss())
+}
+
 func casePingSuccess(t *testing.T) *Ping {
 	ping := New()
 	ping.UpdateEvery = 1
-	ping.Hosts = []string{"192.0.2.1", "192.0.2.2", "example.com"}
+	ping.Hosts = []HostConfig{{Host: "192.0.2.1"}, {Host: "192.0.2.2"}, {Host: "example.com"}}
 	ping.newProber = func(_ pingProberConfig, _ *logger.Logger) prober {
 		return &mockProber{}
 	}
@@ -152,7 +296,7 @@ func casePingSuccess(t *testing.T) *Ping {
 func casePingError(t *testing.T) *Ping {
 	ping := New()
 	ping.UpdateEvery = 1
-	ping.Hosts = []string{"192.0.2.1", "192.0.2.2", "example.com"}
+	ping.Hosts = []HostConfig{{Host: "192.0.2.1"}, {Host: "192.0.2.2"}, {Host: "example.com"}}
 	ping.newProber = func(_ pingProberConfig, _ *logger.Logger) prober {
 		return &mockProber{errOnPing: true}
 	}
@@ -162,24 +306,29 @@ func casePingError(t *testing.T) *Ping {
 
 type mockProber struct {
 	errOnPing bool
+	mu        sync.Mutex
+	confs     map[string]probeConfig
 }
 
-func (m *mockProber) ping(host string) (*probing.Statistics, error) {
+func (m *mockProber) ping(host string, conf probeConfig) (*pingStats, error) {
+	m.mu.Lock()
+	if m.confs == nil {
+		m.confs = make(map[string]probeConfig)
+	}
+	m.confs[host] = conf
+	m.mu.Unlock()
+
 	if m.errOnPing {
 		return nil, errors.New("mock.ping() error")
 	}
 
-	stats := probing.Statistics{
-		PacketsRecv:           5,
-		PacketsSent:           5,
-		PacketsRecvDuplicates: 0,
-		PacketLoss:            0,
-		Addr:                  host,
-		Rtts:                  nil,
-		MinRtt:                time.Millisecond * 10,
-		MaxRtt:                time.Millisecond * 20,
-		AvgRtt:                time.Millisecond * 15,
-		StdDevRtt:             time.Millisecond * 5,
+	stats := pingStats{
+		PacketsRecv: 5,
+		PacketsSent: 5,
+		MinRtt:      time.Millisecond * 10,
+		MaxRtt:      time.Millisecond * 20,
+		AvgRtt:      time.Millisecond * 15,
+		StdDevRtt:   time.Millisecond * 5,
 	}
 
 	return &stats, nil
@@ -27,9 +27,7 @@ func newPingProber(conf pingProberConfig, log *logger.Logger) prober {
 	return &pingProber{
 		network:    conf.network,
 		privileged: conf.privileged,
-		packets:    conf.packets,
 		source:     source,
-		interval:   conf.interval,
 		deadline:   conf.deadline,
 		Logger:     log,
 	}
@@ -38,24 +36,33 @@ func newPingProber(conf pingProberConfig, log *logger.Logger) prober {
 type pingProberConfig struct {
 	network    string
 	privileged bool
-	packets    int
 	iface      string
-	interval   time.Duration
 	deadline   time.Duration
 }
 
+// probeConfig is the per host part of the prober configuration, zero size and ttl mean the pro-bing defaults.
+type probeConfig struct {
+	packets  int
+	interval time.Duration
+	size     int
+	ttl      int
+}
+
+type pingTarget struct {
+	host string
+	conf probeConfig
+}
+
 type pingProber struct {
 	*logger.Logger
 
 	network    string
 	privileged bool
-	packets    int
 	source     string
-	interval   time.Duration
 	deadline   time.Duration
 }
 
-func (p *pingProber) ping(host string) (*probing.Statistics, error) {
+func (p *pingProber) ping(host string, conf probeConfig) (*pingStats, error) {
 	pr := probing.New(host)
 
 	pr.SetNetwork(p.network)
@@ -66,17 +73,29 @@ func (p *pingProber) ping(host string) (*probing.Statistics, error) {
 
 	pr.Source = p.source
 	pr.RecordRtts = false
-	pr.Interval = p.interval
-	pr.Count = p.packets
+	pr.Interval = conf.interval
+	pr.Count = conf.packets
 	pr.Timeout = p.deadline
+	// the payload size is the same for the raw ICMP and the unprivileged UDP ("ping" socket) modes
+	if conf.size > 0 {
+		pr.Size = conf.size
+	}
+	if conf.ttl > 0 {
+		pr.TTL = conf.ttl
+	}
 	pr.SetPrivileged(p.privileged)
 	pr.SetLogger(nil)
 
+	// the statistics are aggregated by the sequence number instead of using pro-bing ones: pro-bing accumulates
+	// the variance in nanoseconds squared (time.Duration), it overflows when RTTs deviate by more than ~3 seconds
+	agg := newRTTAggregator()
+	pr.OnRecv = func(pkt *probing.Packet) { agg.addReply(pkt.Seq, pkt.Rtt) }
+
 	if err := pr.Run(); err != nil {
 		return nil, fmt.Errorf("pinging host '%s' (ip %s): %v", pr.Addr(), pr.IPAddr(), err)
 	}
 
-	stats := pr.Statistics()
+	stats := agg.stats(pr.Statistics().PacketsSent)
 
 	p.Debugf("ping stats for host '%s' (ip '%s'): %+v", pr.Addr(), pr.IPAddr(), stats)
 
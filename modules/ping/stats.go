@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package ping
+
+import (
+	"math"
+	"time"
+)
+
+type pingStats struct {
+	PacketsSent int
+	PacketsRecv int
+	Duplicates  int
+	MinRtt      time.Duration
+	MaxRtt      time.Duration
+	AvgRtt      time.Duration
+	StdDevRtt   time.Duration // 'mdev' in the iputils ping output
+}
+
+// PacketLoss returns the packet loss percentage.
+func (s pingStats) PacketLoss() float64 {
+	if s.PacketsSent == 0 {
+		return 0
+	}
+	return float64(s.PacketsSent-s.PacketsRecv) / float64(s.PacketsSent) * 100
+}
+
+// rttAggregator keeps the first reply RTT of every sequence number: the replies order doesn't matter
+// and the duplicated replies are counted but don't affect the RTT statistics.
+type rttAggregator struct {
+	rtts       map[int]time.Duration
+	duplicates int
+}
+
+func newRTTAggregator() *rttAggregator {
+	return &rttAggregator{rtts: make(map[int]time.Duration)}
+}
+
+func (a *rttAggregator) addReply(seq int, rtt time.Duration) {
+	if _, ok := a.rtts[seq]; ok {
+		a.duplicates++
+		return
+	}
+	a.rtts[seq] = rtt
+}
+
+// stats returns the statistics of the replies to the 'sent' packets (sequence numbers [0, sent)),
+// the replies to unknown sequence numbers are ignored.
+func (a *rttAggregator) stats(sent int) *pingStats {
+	stats := &pingStats{PacketsSent: sent, Duplicates: a.duplicates}
+
+	var rtts []float64
+	for seq, rtt := range a.rtts {
+		if seq < 0 || seq >= sent {
+			continue
+		}
+		if len(rtts) == 0 || rtt < stats.MinRtt {
+			stats.MinRtt = rtt
+		}
+		if rtt > stats.MaxRtt {
+			stats.MaxRtt = rtt
+		}
+		rtts = append(rtts, float64(rtt))
+	}
+	if stats.PacketsRecv = len(rtts); stats.PacketsRecv == 0 {
+		return stats
+	}
+
+	var sum float64
+	for _, v := range rtts {
+		sum += v
+	}
+	avg := sum / float64(len(rtts))
+
+	var variance float64
+	for _, v := range rtts {
+		variance += (v - avg) * (v - avg)
+	}
+	variance /= float64(len(rtts))
+
+	stats.AvgRtt = time.Duration(avg)
+	stats.StdDevRtt = time.Duration(math.Sqrt(variance))
+
+	return stats
+}
@@ -13,6 +13,10 @@ const (
 	prioCheckStatus = module.Priority + iota
 	prioCheckInStatusDuration
 	prioCheckLatency
+
+	prioUDPCheckStatus
+	prioUDPCheckInStatusDuration
+	prioUDPCheckLatency
 )
 
 var chartsTmpl = module.Charts{
@@ -32,6 +36,7 @@ var checkStatusChartTmpl = module.Chart{
 		{ID: "port_%d_success", Name: "success"},
 		{ID: "port_%d_failed", Name: "failed"},
 		{ID: "port_%d_timeout", Name: "timeout"},
+		{ID: "port_%d_bad_response", Name: "bad_response"},
 	},
 }
 
@@ -59,8 +64,61 @@ var checkConnectionLatencyChartTmpl = module.Chart{
 	},
 }
 
+var udpChartsTmpl = module.Charts{
+	udpCheckStatusChartTmpl.Copy(),
+	udpCheckInStateDurationChartTmpl.Copy(),
+	udpCheckResponseLatencyChartTmpl.Copy(),
+}
+
+var udpCheckStatusChartTmpl = module.Chart{
+	ID:       "udp_port_%d_status",
+	Title:    "UDP Check Status",
+	Units:    "boolean",
+	Fam:      "status",
+	Ctx:      "portcheck.udp_status",
+	Priority: prioUDPCheckStatus,
+	Dims: module.Dims{
+		{ID: "udp_port_%d_open", Name: "open"},
+		{ID: "udp_port_%d_closed", Name: "closed"},
+		{ID: "udp_port_%d_filtered", Name: "filtered"},
+		{ID: "udp_port_%d_bad_response", Name: "bad_response"},
+	},
+}
+
+var udpCheckInStateDurationChartTmpl = module.Chart{
+	ID:       "udp_port_%d_current_state_duration",
+	Title:    "UDP Current State Duration",
+	Units:    "seconds",
+	Fam:      "status duration",
+	Ctx:      "portcheck.udp_state_duration",
+	Priority: prioUDPCheckInStatusDuration,
+	Dims: module.Dims{
+		{ID: "udp_port_%d_current_state_duration", Name: "time"},
+	},
+}
+
+var udpCheckResponseLatencyChartTmpl = module.Chart{
+	ID:       "udp_port_%d_response_latency",
+	Title:    "UDP Response Latency",
+	Units:    "ms",
+	Fam:      "latency",
+	Ctx:      "portcheck.udp_latency",
+	Priority: prioUDPCheckLatency,
+	Dims: module.Dims{
+		{ID: "udp_port_%d_latency", Name: "time"},
+	},
+}
+
 func newPortCharts(host string, port int) *module.Charts {
-	charts := chartsTmpl.Copy()
+	return newPortChartsFromTmpl(chartsTmpl, host, port)
+}
+
+func newUDPPortCharts(host string, port int) *module.Charts {
+	return newPortChartsFromTmpl(udpChartsTmpl, host, port)
+}
+
+func newPortChartsFromTmpl(tmpl module.Charts, host string, port int) *module.Charts {
+	charts := tmpl.Copy()
 	for _, chart := range *charts {
 		chart.Labels = []module.Label{
 			{Key: "host", Value: host},
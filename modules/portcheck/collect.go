@@ -3,19 +3,38 @@
 package portcheck
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"net"
 	"sync"
+	"syscall"
 	"time"
 )
 
 type checkState string
 
 const (
-	checkStateSuccess checkState = "success"
-	checkStateTimeout checkState = "timeout"
-	checkStateFailed  checkState = "failed"
+	checkStateSuccess     checkState = "success"
+	checkStateTimeout     checkState = "timeout"
+	checkStateFailed      checkState = "failed"
+	checkStateBadResponse checkState = "bad_response"
+
+	// UDP is connectionless: a response means the port is open, an ICMP port unreachable means it is closed,
+	// and no response means either filtered or the service ignores the payload (or the datagrams are lost).
+	udpStateOpen     checkState = "open"
+	udpStateClosed   checkState = "closed"
+	udpStateFiltered checkState = "filtered"
+)
+
+var (
+	tcpStates = []checkState{checkStateSuccess, checkStateTimeout, checkStateFailed, checkStateBadResponse}
+	udpStates = []checkState{udpStateOpen, udpStateClosed, udpStateFiltered, checkStateBadResponse}
 )
 
+// maxResponseSize limits the response read looking for the 'expect' string.
+const maxResponseSize = 4096
+
 func (pc *PortCheck) collect() (map[string]int64, error) {
 	wg := &sync.WaitGroup{}
 
@@ -23,22 +42,33 @@ func (pc *PortCheck) collect() (map[string]int64, error) {
 		wg.Add(1)
 		go func(p *port) { pc.checkPort(p); wg.Done() }(p)
 	}
+	for _, p := range pc.udpPorts {
+		wg.Add(1)
+		go func(p *port) { pc.checkUDPPort(p); wg.Done() }(p)
+	}
 	wg.Wait()
 
 	mx := make(map[string]int64)
 
 	for _, p := range pc.ports {
-		mx[fmt.Sprintf("port_%d_current_state_duration", p.number)] = int64(p.inState)
-		mx[fmt.Sprintf("port_%d_latency", p.number)] = int64(p.latency)
-		mx[fmt.Sprintf("port_%d_%s", p.number, checkStateSuccess)] = 0
-		mx[fmt.Sprintf("port_%d_%s", p.number, checkStateTimeout)] = 0
-		mx[fmt.Sprintf("port_%d_%s", p.number, checkStateFailed)] = 0
-		mx[fmt.Sprintf("port_%d_%s", p.number, p.state)] = 1
+		collectPort(mx, fmt.Sprintf("port_%d_", p.number), p, tcpStates)
+	}
+	for _, p := range pc.udpPorts {
+		collectPort(mx, fmt.Sprintf("udp_port_%d_", p.number), p, udpStates)
 	}
 
 	return mx, nil
 }
 
+func collectPort(mx map[string]int64, px string, p *port, states []checkState) {
+	mx[px+"current_state_duration"] = int64(p.inState)
+	mx[px+"latency"] = int64(p.latency)
+	for _, s := range states {
+		mx[px+string(s)] = 0
+	}
+	mx[px+string(p.state)] = 1
+}
+
 func (pc *PortCheck) checkPort(p *port) {
 	start := time.Now()
 	conn, err := pc.dial("tcp", fmt.Sprintf("%s:%d", pc.Host, p.number), pc.Timeout.Duration)
@@ -51,18 +81,99 @@ func (pc *PortCheck) checkPort(p *port) {
 	}()
 
 	if err != nil {
-		v, ok := err.(interface{ Timeout() bool })
-		if ok && v.Timeout() {
+		if isTimeout(err) {
 			pc.setPortState(p, checkStateTimeout)
 		} else {
 			pc.setPortState(p, checkStateFailed)
 		}
 		return
 	}
+
+	if p.send != "" || p.expect != "" {
+		if err := exchange(conn, p.send, p.expect, pc.Timeout.Duration); err != nil {
+			pc.Debugf("TCP port %d: %v", p.number, err)
+			pc.setPortState(p, checkStateBadResponse)
+			p.latency = durationToMs(dur)
+			return
+		}
+	}
+
 	pc.setPortState(p, checkStateSuccess)
 	p.latency = durationToMs(dur)
 }
 
+func (pc *PortCheck) checkUDPPort(p *port) {
+	start := time.Now()
+	conn, err := pc.dial("udp", fmt.Sprintf("%s:%d", pc.Host, p.number), pc.UDPTimeout.Duration)
+	if err != nil {
+		pc.Warningf("UDP port %d: %v", p.number, err)
+		pc.setPortState(p, udpStateFiltered)
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.SetDeadline(start.Add(pc.UDPTimeout.Duration))
+
+	buf := make([]byte, maxResponseSize)
+	var n int
+	// the ICMP port unreachable is reported as "connection refused" by the next operation on the connected socket
+	if _, err = conn.Write([]byte(p.send)); err == nil {
+		n, err = conn.Read(buf)
+	}
+	dur := time.Since(start)
+
+	switch {
+	case err == nil:
+		if p.expect != "" && !bytes.Contains(buf[:n], []byte(p.expect)) {
+			pc.Debugf("UDP port %d: expected '%s', got '%s'", p.number, p.expect, buf[:n])
+			pc.setPortState(p, checkStateBadResponse)
+		} else {
+			pc.setPortState(p, udpStateOpen)
+		}
+		p.latency = durationToMs(dur)
+	case isTimeout(err):
+		pc.setPortState(p, udpStateFiltered)
+	case errors.Is(err, syscall.ECONNREFUSED):
+		pc.setPortState(p, udpStateClosed)
+	default:
+		pc.Debugf("UDP port %d: %v", p.number, err)
+		pc.setPortState(p, udpStateClosed)
+	}
+}
+
+// exchange sends the data and reads the response until it contains the expected string.
+func exchange(conn net.Conn, send, expect string, timeout time.Duration) error {
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if send != "" {
+		if _, err := conn.Write([]byte(send)); err != nil {
+			return fmt.Errorf("error on sending data: %v", err)
+		}
+	}
+	if expect == "" {
+		return nil
+	}
+
+	var resp []byte
+	buf := make([]byte, 512)
+	for len(resp) < maxResponseSize {
+		n, err := conn.Read(buf)
+		resp = append(resp, buf[:n]...)
+		if bytes.Contains(resp, []byte(expect)) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("expected '%s', got '%s': %v", expect, resp, err)
+		}
+	}
+	return fmt.Errorf("expected '%s' in the first %d bytes of the response", expect, maxResponseSize)
+}
+
+func isTimeout(err error) bool {
+	v, ok := err.(interface{ Timeout() bool })
+	return ok && v.Timeout()
+}
+
 func (pc *PortCheck) setPortState(p *port, s checkState) {
 	if p.state != s {
 		p.inState = pc.UpdateEvery
@@ -4,6 +4,7 @@ package portcheck
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/netdata/go.d.plugin/agent/module"
 )
@@ -12,8 +13,20 @@ func (pc *PortCheck) validateConfig() error {
 	if pc.Host == "" {
 		return errors.New("'host' parameter not set")
 	}
-	if len(pc.Ports) == 0 {
-		return errors.New("'ports' parameter not set")
+	if len(pc.Ports) == 0 && len(pc.UDPPorts) == 0 {
+		return errors.New("'ports' or 'udp_ports' parameter not set")
+	}
+	for _, ports := range [][]PortConfig{pc.Ports, pc.UDPPorts} {
+		seen := make(map[int]bool)
+		for _, p := range ports {
+			if p.Port <= 0 || p.Port > 65535 {
+				return fmt.Errorf("invalid port number (%d)", p.Port)
+			}
+			if seen[p.Port] {
+				return fmt.Errorf("duplicate port (%d)", p.Port)
+			}
+			seen[p.Port] = true
+		}
 	}
 	return nil
 }
@@ -22,7 +35,12 @@ func (pc *PortCheck) initCharts() (*module.Charts, error) {
 	var charts module.Charts
 
 	for _, port := range pc.Ports {
-		if err := charts.Add(*newPortCharts(pc.Host, port)...); err != nil {
+		if err := charts.Add(*newPortCharts(pc.Host, port.Port)...); err != nil {
+			return nil, err
+		}
+	}
+	for _, port := range pc.UDPPorts {
+		if err := charts.Add(*newUDPPortCharts(pc.Host, port.Port)...); err != nil {
 			return nil, err
 		}
 	}
@@ -4,6 +4,7 @@ package portcheck
 
 import (
 	_ "embed"
+	"errors"
 	"net"
 	"time"
 
@@ -27,22 +28,52 @@ func init() {
 func New() *PortCheck {
 	return &PortCheck{
 		Config: Config{
-			Timeout: web.Duration{Duration: time.Second * 2},
+			Timeout:    web.Duration{Duration: time.Second * 2},
+			UDPTimeout: web.Duration{Duration: time.Second * 2},
 		},
 		dial: net.DialTimeout,
 	}
 }
 
-type Config struct {
-	Host    string       `yaml:"host"`
-	Ports   []int        `yaml:"ports"`
-	Timeout web.Duration `yaml:"timeout"`
+type (
+	Config struct {
+		Host       string       `yaml:"host"`
+		Ports      []PortConfig `yaml:"ports"`
+		UDPPorts   []PortConfig `yaml:"udp_ports"`
+		Timeout    web.Duration `yaml:"timeout"`
+		UDPTimeout web.Duration `yaml:"udp_timeout"`
+	}
+	// PortConfig is a 'ports'/'udp_ports' entry: either a port number or an object with
+	// the data to send after connecting and the string expected in the response.
+	PortConfig struct {
+		Port   int    `yaml:"port"`
+		Send   string `yaml:"send"`
+		Expect string `yaml:"expect"`
+	}
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (p *PortConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var number int
+	if err := unmarshal(&number); err == nil {
+		*p = PortConfig{Port: number}
+		return nil
+	}
+	// the decoding errors of the alias type mention it, they would confuse users
+	var object map[string]interface{}
+	if err := unmarshal(&object); err != nil {
+		return errors.New("port must be a number or an object with 'port'")
+	}
+	type plain PortConfig
+	return unmarshal((*plain)(p))
 }
 
 type dialFunc func(network, address string, timeout time.Duration) (net.Conn, error)
 
 type port struct {
 	number  int
+	send    string
+	expect  string
 	state   checkState
 	inState int
 	latency int
@@ -53,9 +84,10 @@ type PortCheck struct {
 	Config      `yaml:",inline"`
 	UpdateEvery int `yaml:"update_every"`
 
-	charts *module.Charts
-	dial   dialFunc
-	ports  []*port
+	charts   *module.Charts
+	dial     dialFunc
+	ports    []*port
+	udpPorts []*port
 }
 
 func (pc *PortCheck) Init() bool {
@@ -72,12 +104,17 @@ func (pc *PortCheck) Init() bool {
 	pc.charts = charts
 
 	for _, p := range pc.Ports {
-		pc.ports = append(pc.ports, &port{number: p})
+		pc.ports = append(pc.ports, &port{number: p.Port, send: p.Send, expect: p.Expect})
+	}
+	for _, p := range pc.UDPPorts {
+		pc.udpPorts = append(pc.udpPorts, &port{number: p.Port, send: p.Send, expect: p.Expect})
 	}
 
 	pc.Debugf("using host: %s", pc.Host)
 	pc.Debugf("using ports: %v", pc.Ports)
+	pc.Debugf("using UDP ports: %v", pc.UDPPorts)
 	pc.Debugf("using TCP connection timeout: %s", pc.Timeout)
+	pc.Debugf("using UDP response timeout: %s", pc.UDPTimeout)
 
 	return true
 }
@@ -4,6 +4,7 @@ package portcheck
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"strings"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestNew(t *testing.T) {
@@ -24,7 +26,7 @@ func TestPortCheck_Init(t *testing.T) {
 	job := New()
 
 	job.Host = "127.0.0.1"
-	job.Ports = []int{39001, 39002}
+	job.Ports = []PortConfig{{Port: 39001}, {Port: 39002}}
 	assert.True(t, job.Init())
 	assert.Len(t, job.ports, 2)
 }
@@ -34,7 +36,7 @@ func TestPortCheck_InitNG(t *testing.T) {
 	assert.False(t, job.Init())
 	job.Host = "127.0.0.1"
 	assert.False(t, job.Init())
-	job.Ports = []int{39001, 39002}
+	job.Ports = []PortConfig{{Port: 39001}, {Port: 39002}}
 	assert.True(t, job.Init())
 }
 
@@ -48,7 +50,7 @@ func TestPortCheck_Cleanup(t *testing.T) {
 
 func TestPortCheck_Charts(t *testing.T) {
 	job := New()
-	job.Ports = []int{1, 2}
+	job.Ports = []PortConfig{{Port: 1}, {Port: 2}}
 	job.Host = "localhost"
 	require.True(t, job.Init())
 	assert.Len(t, *job.Charts(), len(chartsTmpl)*len(job.Ports))
@@ -58,7 +60,7 @@ func TestPortCheck_Collect(t *testing.T) {
 	job := New()
 
 	job.Host = "127.0.0.1"
-	job.Ports = []int{39001, 39002}
+	job.Ports = []PortConfig{{Port: 39001}, {Port: 39002}}
 	job.UpdateEvery = 5
 	job.dial = testDial(nil)
 	require.True(t, job.Init())
@@ -78,11 +80,13 @@ func TestPortCheck_Collect(t *testing.T) {
 		"port_39001_latency":                0,
 		"port_39001_success":                1,
 		"port_39001_timeout":                0,
+		"port_39001_bad_response":           0,
 		"port_39002_current_state_duration": int64(job.UpdateEvery),
 		"port_39002_failed":                 0,
 		"port_39002_latency":                0,
 		"port_39002_success":                1,
 		"port_39002_timeout":                0,
+		"port_39002_bad_response":           0,
 	}
 	collected := job.Collect()
 	copyLatency(expected, collected)
@@ -95,11 +99,13 @@ func TestPortCheck_Collect(t *testing.T) {
 		"port_39001_latency":                0,
 		"port_39001_success":                1,
 		"port_39001_timeout":                0,
+		"port_39001_bad_response":           0,
 		"port_39002_current_state_duration": int64(job.UpdateEvery) * 2,
 		"port_39002_failed":                 0,
 		"port_39002_latency":                0,
 		"port_39002_success":                1,
 		"port_39002_timeout":                0,
+		"port_39002_bad_response":           0,
 	}
 	collected = job.Collect()
 	copyLatency(expected, collected)
@@ -114,11 +120,13 @@ func TestPortCheck_Collect(t *testing.T) {
 		"port_39001_latency":                0,
 		"port_39001_success":                0,
 		"port_39001_timeout":                0,
+		"port_39001_bad_response":           0,
 		"port_39002_current_state_duration": int64(job.UpdateEvery),
 		"port_39002_failed":                 1,
 		"port_39002_latency":                0,
 		"port_39002_success":                0,
 		"port_39002_timeout":                0,
+		"port_39002_bad_response":           0,
 	}
 	collected = job.Collect()
 	copyLatency(expected, collected)
@@ -133,11 +141,13 @@ func TestPortCheck_Collect(t *testing.T) {
 		"port_39001_latency":                0,
 		"port_39001_success":                0,
 		"port_39001_timeout":                1,
+		"port_39001_bad_response":           0,
 		"port_39002_current_state_duration": int64(job.UpdateEvery),
 		"port_39002_failed":                 0,
 		"port_39002_latency":                0,
 		"port_39002_success":                0,
 		"port_39002_timeout":                1,
+		"port_39002_bad_response":           0,
 	}
 	collected = job.Collect()
 	copyLatency(expected, collected)
@@ -145,6 +155,150 @@ func TestPortCheck_Collect(t *testing.T) {
 	assert.Equal(t, expected, collected)
 }
 
+func TestPortCheck_Collect_TCPExpect(t *testing.T) {
+	tests := map[string]struct {
+		port      PortConfig
+		wantState checkState
+	}{
+		"banner matches": {
+			port:      PortConfig{Port: 22, Expect: "SSH-2.0"},
+			wantState: checkStateSuccess,
+		},
+		"banner does not match": {
+			port:      PortConfig{Port: 22, Expect: "HTTP/1.1"},
+			wantState: checkStateBadResponse,
+		},
+		"response to the sent data matches": {
+			port:      PortConfig{Port: 22, Send: "PING\r\n", Expect: "+PONG"},
+			wantState: checkStateSuccess,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			job := New()
+			job.Host = "127.0.0.1"
+			job.Ports = []PortConfig{test.port}
+			job.UpdateEvery = 5
+			job.Timeout.Duration = time.Millisecond * 200
+			job.dial = testPipeDial(func(conn net.Conn) {
+				if test.port.Send == "" {
+					_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_8.9p1\r\n"))
+					return
+				}
+				buf := make([]byte, 64)
+				n, _ := conn.Read(buf)
+				if string(buf[:n]) == test.port.Send {
+					_, _ = conn.Write([]byte("+PONG\r\n"))
+				}
+			})
+			require.True(t, job.Init())
+
+			mx := job.Collect()
+
+			for _, state := range tcpStates {
+				want := int64(0)
+				if state == test.wantState {
+					want = 1
+				}
+				assert.Equalf(t, want, mx["port_22_"+string(state)], "state '%s'", state)
+			}
+		})
+	}
+}
+
+func TestPortCheck_Collect_UDP(t *testing.T) {
+	echo := func(b []byte) []byte { return append([]byte("echo "), b...) }
+	open := startUDPServer(t, echo)
+	badResponse := startUDPServer(t, echo)
+	filtered := startUDPServer(t, func([]byte) []byte { return nil })
+	closed := closedUDPPort(t)
+
+	job := New()
+	job.Host = "127.0.0.1"
+	job.UDPPorts = []PortConfig{
+		{Port: open, Send: "ping", Expect: "echo ping"},
+		{Port: badResponse, Send: "ping", Expect: "pong"},
+		{Port: filtered},
+		{Port: closed},
+	}
+	job.UpdateEvery = 5
+	job.UDPTimeout.Duration = time.Millisecond * 200
+	require.True(t, job.Init())
+	require.Len(t, *job.Charts(), len(udpChartsTmpl)*4)
+
+	mx := job.Collect()
+
+	for port, wantState := range map[int]checkState{
+		open:        udpStateOpen,
+		badResponse: checkStateBadResponse,
+		filtered:    udpStateFiltered,
+		closed:      udpStateClosed,
+	} {
+		for _, state := range udpStates {
+			want := int64(0)
+			if state == wantState {
+				want = 1
+			}
+			assert.Equalf(t, want, mx[fmt.Sprintf("udp_port_%d_%s", port, state)], "port %d state '%s'", port, state)
+		}
+	}
+}
+
+func TestPortConfig_UnmarshalYAML(t *testing.T) {
+	data := `
+ports:
+  - 80
+  - port: 22
+    expect: SSH-2.0
+udp_ports:
+  - port: 53
+    send: "\x00"
+`
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal([]byte(data), &cfg))
+
+	assert.Equal(t, []PortConfig{{Port: 80}, {Port: 22, Expect: "SSH-2.0"}}, cfg.Ports)
+	assert.Equal(t, []PortConfig{{Port: 53, Send: "\x00"}}, cfg.UDPPorts)
+}
+
+func startUDPServer(t *testing.T, respond func([]byte) []byte) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := respond(buf[:n]); resp != nil {
+				_, _ = conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func closedUDPPort(t *testing.T) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	require.NoError(t, conn.Close())
+	return port
+}
+
+func testPipeDial(serve func(conn net.Conn)) dialFunc {
+	return func(_, _ string, _ time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() { defer func() { _ = server.Close() }(); serve(server) }()
+		return client, nil
+	}
+}
+
 func testDial(err error) dialFunc {
 	return func(_, _ string, _ time.Duration) (net.Conn, error) { return &net.TCPConn{}, err }
 }
@@ -82,12 +82,21 @@ const (
 	prioReplicationAppWALLagSize
 	prioReplicationAppWALLagTime
 	prioReplicationSlotFilesCount
+	prioReplicationSlotWALLagSize
+	prioReplicationSlotStatus
 	prioDBConflictsRate
 	prioDBConflictsReasonRate
 
+	prioStatementExecTime
+	prioStatementCalls
+	prioStatementRows
+	prioStatementSharedBlocks
+
 	prioWALIORate
 	prioWALFilesCount
 	prioWALArchivingFilesCount
+	prioWALArchiverFilesRate
+	prioWALArchiverSinceTime
 
 	prioDatabasesCount
 	prioCatalogRelationsCount
@@ -124,6 +133,14 @@ var walFilesCharts = module.Charts{
 	walArchivingFilesCountChart.Copy(),
 }
 
+func (p *Postgres) addArchiverCharts() {
+	charts := archiverCharts.Copy()
+
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
 func (p *Postgres) addWALFilesCharts() {
 	charts := walFilesCharts.Copy()
 
@@ -302,6 +319,35 @@ var (
 		},
 	}
 
+	archiverCharts = module.Charts{
+		walArchiverFilesRateChart.Copy(),
+		walArchiverSinceTimeChart.Copy(),
+	}
+	walArchiverFilesRateChart = module.Chart{
+		ID:       "wal_archiver_files_rate",
+		Title:    "Write-Ahead Log archiver files",
+		Units:    "files/s",
+		Fam:      "wal",
+		Ctx:      "postgres.wal_archiver_files_rate",
+		Priority: prioWALArchiverFilesRate,
+		Dims: module.Dims{
+			{ID: "archiver_archived_count", Name: "archived", Algo: module.Incremental},
+			{ID: "archiver_failed_count", Name: "failed", Algo: module.Incremental},
+		},
+	}
+	walArchiverSinceTimeChart = module.Chart{
+		ID:       "wal_archiver_since_time",
+		Title:    "Write-Ahead Log archiver time since last event",
+		Units:    "seconds",
+		Fam:      "wal",
+		Ctx:      "postgres.wal_archiver_since_time",
+		Priority: prioWALArchiverSinceTime,
+		Dims: module.Dims{
+			{ID: "archiver_last_archived_ago", Name: "last_archived"},
+			{ID: "archiver_last_failed_ago", Name: "last_failed"},
+		},
+	}
+
 	autovacuumWorkersCountChart = module.Chart{
 		ID:       "autovacuum_workers_count",
 		Title:    "Autovacuum workers",
@@ -563,6 +609,8 @@ func (p *Postgres) removeReplicationStandbyAppCharts(app string) {
 var (
 	replicationSlotCharts = module.Charts{
 		replicationSlotFilesCountChartTmpl.Copy(),
+		replicationSlotWALLagChartTmpl.Copy(),
+		replicationSlotStatusChartTmpl.Copy(),
 	}
 	replicationSlotFilesCountChartTmpl = module.Chart{
 		ID:       "replication_slot_%s_files_count",
@@ -576,14 +624,40 @@ var (
 			{ID: "repl_slot_%s_replslot_files", Name: "pg_replslot_files"},
 		},
 	}
+	replicationSlotWALLagChartTmpl = module.Chart{
+		ID:       "replication_slot_%s_wal_lag_size",
+		Title:    "Replication slot WAL lag size",
+		Units:    "B",
+		Fam:      "replication",
+		Ctx:      "postgres.replication_slot_wal_lag_size",
+		Priority: prioReplicationSlotWALLagSize,
+		Dims: module.Dims{
+			{ID: "repl_slot_%s_restart_lsn_lag", Name: "restart_lsn"},
+			{ID: "repl_slot_%s_confirmed_flush_lsn_lag", Name: "confirmed_flush_lsn"},
+			{ID: "repl_slot_%s_safe_wal_size", Name: "safe_wal_size"},
+		},
+	}
+	replicationSlotStatusChartTmpl = module.Chart{
+		ID:       "replication_slot_%s_status",
+		Title:    "Replication slot status",
+		Units:    "status",
+		Fam:      "replication",
+		Ctx:      "postgres.replication_slot_status",
+		Priority: prioReplicationSlotStatus,
+		Dims: module.Dims{
+			{ID: "repl_slot_%s_active", Name: "active"},
+			{ID: "repl_slot_%s_inactive", Name: "inactive"},
+		},
+	}
 )
 
-func newReplicationSlotCharts(slot string) *module.Charts {
+func newReplicationSlotCharts(slot, slotType string) *module.Charts {
 	charts := replicationSlotCharts.Copy()
 	for _, c := range *charts {
 		c.ID = fmt.Sprintf(c.ID, slot)
 		c.Labels = []module.Label{
 			{Key: "slot", Value: slot},
+			{Key: "slot_type", Value: slotType},
 		}
 		for _, d := range c.Dims {
 			d.ID = fmt.Sprintf(d.ID, slot)
@@ -592,8 +666,22 @@ func newReplicationSlotCharts(slot string) *module.Charts {
 	return charts
 }
 
-func (p *Postgres) addNewReplicationSlotCharts(slot string) {
-	charts := newReplicationSlotCharts(slot)
+func (p *Postgres) addNewReplicationSlotCharts(slot *replSlotMetrics) {
+	charts := newReplicationSlotCharts(slot.name, slot.slotType)
+
+	if !p.isSuperUser() {
+		_ = charts.Remove(fmt.Sprintf(replicationSlotFilesCountChartTmpl.ID, slot.name))
+	}
+	if chart := charts.Get(fmt.Sprintf(replicationSlotWALLagChartTmpl.ID, slot.name)); chart != nil {
+		// 'confirmed_flush_lsn' is only set for logical slots
+		if slot.slotType != "logical" {
+			_ = chart.RemoveDim(fmt.Sprintf("repl_slot_%s_confirmed_flush_lsn_lag", slot.name))
+		}
+		if p.pgVersion < pgVersion13 {
+			_ = chart.RemoveDim(fmt.Sprintf("repl_slot_%s_safe_wal_size", slot.name))
+		}
+	}
+
 	if err := p.Charts().Add(*charts...); err != nil {
 		p.Warning(err)
 	}
@@ -1398,3 +1486,101 @@ func (p *Postgres) removeIndexCharts(idx *indexMetrics) {
 		}
 	}
 }
+
+var (
+	statementChartsTmpl = module.Charts{
+		statementExecTimeChartTmpl.Copy(),
+		statementCallsChartTmpl.Copy(),
+		statementRowsChartTmpl.Copy(),
+		statementSharedBlocksChartTmpl.Copy(),
+	}
+	statementExecTimeChartTmpl = module.Chart{
+		ID:       "statement_%s_exec_time",
+		Title:    "Statement execution time",
+		Units:    "milliseconds/s",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_exec_time",
+		Priority: prioStatementExecTime,
+		Dims: module.Dims{
+			{ID: "statement_%s_exec_time", Name: "exec_time", Algo: module.Incremental, Div: 1000},
+		},
+	}
+	statementCallsChartTmpl = module.Chart{
+		ID:       "statement_%s_calls",
+		Title:    "Statement calls",
+		Units:    "calls/s",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_calls",
+		Priority: prioStatementCalls,
+		Dims: module.Dims{
+			{ID: "statement_%s_calls", Name: "calls", Algo: module.Incremental},
+		},
+	}
+	statementRowsChartTmpl = module.Chart{
+		ID:       "statement_%s_rows",
+		Title:    "Statement rows",
+		Units:    "rows/s",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_rows",
+		Priority: prioStatementRows,
+		Dims: module.Dims{
+			{ID: "statement_%s_rows", Name: "rows", Algo: module.Incremental},
+		},
+	}
+	statementSharedBlocksChartTmpl = module.Chart{
+		ID:       "statement_%s_shared_blocks",
+		Title:    "Statement shared buffer blocks",
+		Units:    "blocks/s",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_shared_blocks",
+		Priority: prioStatementSharedBlocks,
+		Type:     module.Stacked,
+		Dims: module.Dims{
+			{ID: "statement_%s_shared_blks_hit", Name: "hit", Algo: module.Incremental},
+			{ID: "statement_%s_shared_blks_read", Name: "read", Algo: module.Incremental},
+		},
+	}
+)
+
+// statementLabelMaxLen is the maximum length of the query text label.
+const statementLabelMaxLen = 128
+
+func newStatementCharts(stmt *statementMetrics) *module.Charts {
+	charts := statementChartsTmpl.Copy()
+	for _, c := range *charts {
+		c.ID = fmt.Sprintf(c.ID, stmt.queryID)
+		c.Labels = []module.Label{
+			{Key: "queryid", Value: stmt.queryID},
+			{Key: "query", Value: statementLabel(stmt.query)},
+		}
+		for _, d := range c.Dims {
+			d.ID = fmt.Sprintf(d.ID, stmt.queryID)
+		}
+	}
+	return charts
+}
+
+func (p *Postgres) addNewStatementCharts(stmt *statementMetrics) {
+	charts := newStatementCharts(stmt)
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
+func (p *Postgres) removeStatementCharts(stmt *statementMetrics) {
+	prefix := fmt.Sprintf("statement_%s_", stmt.queryID)
+	for _, c := range *p.Charts() {
+		if strings.HasPrefix(c.ID, prefix) {
+			c.MarkRemove()
+			c.MarkNotCreated()
+		}
+	}
+}
+
+func statementLabel(query string) string {
+	s := strings.Join(strings.Fields(query), " ")
+	if r := []rune(s); len(r) > statementLabelMaxLen {
+		s = string(r[:statementLabelMaxLen-3]) + "..."
+	}
+	return s
+}
@@ -17,6 +17,7 @@ const (
 	pgVersion94 = 9_04_00
 	pgVersion10 = 10_00_00
 	pgVersion11 = 11_00_00
+	pgVersion13 = 13_00_00
 )
 
 func (p *Postgres) collect() (map[string]int64, error) {
@@ -70,6 +71,18 @@ func (p *Postgres) collect() (map[string]int64, error) {
 			return nil, fmt.Errorf("querying settings max locks held error: %v", err)
 		}
 		p.mx.maxLocksHeld = maxLocks
+
+		if p.CollectStatements {
+			ok, err := p.doQueryPgStatStatementsExists()
+			if err != nil {
+				return nil, fmt.Errorf("querying pg_stat_statements extension error: %v", err)
+			}
+			p.hasPgStatStatements = ok
+			if !ok && !p.warnNoPgStatStatements {
+				p.warnNoPgStatStatements = true
+				p.Warning("'collect_statements' is enabled, but the pg_stat_statements extension is not installed in the database, skipping statements collection")
+			}
+		}
 	}
 
 	p.resetMetrics()
@@ -81,6 +94,10 @@ func (p *Postgres) collect() (map[string]int64, error) {
 			p.addQueriesRunTimeHistogramChart()
 		})
 	}
+	if p.pgVersion >= pgVersion94 {
+		// need pg_stat_archiver
+		p.addArchiverChartsOnce.Do(p.addArchiverCharts)
+	}
 	if p.isSuperUser() {
 		p.addWALFilesChartsOnce.Do(p.addWALFilesCharts)
 	}
@@ -105,6 +122,13 @@ func (p *Postgres) collect() (map[string]int64, error) {
 	if err := p.doQueryIndexesMetrics(); err != nil {
 		return nil, err
 	}
+	if p.CollectStatements && p.hasPgStatStatements {
+		if err := p.doQueryStatementsMetrics(); err != nil {
+			// e.g. the library is not in 'shared_preload_libraries', recheck on the next settings check
+			p.Warningf("querying pg_stat_statements error: %v", err)
+			p.hasPgStatStatements = false
+		}
+	}
 
 	if now.Sub(p.doSlowTime) > p.doSlowEvery {
 		p.doSlowTime = now
@@ -187,6 +211,15 @@ func (p *Postgres) getDBMetrics(name string) *dbMetrics {
 	return db
 }
 
+func (p *Postgres) getStatementMetrics(queryID string) *statementMetrics {
+	m, ok := p.mx.stmts[queryID]
+	if !ok {
+		m = &statementMetrics{queryID: queryID}
+		p.mx.stmts[queryID] = m
+	}
+	return m
+}
+
 func (p *Postgres) getTableMetrics(name, db, schema string) *tableMetrics {
 	key := name + "_" + db + "_" + schema
 	m, ok := p.mx.tables[key]
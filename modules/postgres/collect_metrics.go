@@ -37,6 +37,16 @@ func (p *Postgres) collectMetrics(mx map[string]int64) {
 	mx["wal_written_files"] = p.mx.walWrittenFiles
 	mx["wal_archive_files_ready_count"] = p.mx.walArchiveFilesReady
 	mx["wal_archive_files_done_count"] = p.mx.walArchiveFilesDone
+	if p.pgVersion >= pgVersion94 {
+		mx["archiver_archived_count"] = p.mx.archiverArchivedCount
+		mx["archiver_failed_count"] = p.mx.archiverFailedCount
+		if p.mx.archiverLastArchivedAgo != -1 {
+			mx["archiver_last_archived_ago"] = p.mx.archiverLastArchivedAgo
+		}
+		if p.mx.archiverLastFailedAgo != -1 {
+			mx["archiver_last_failed_ago"] = p.mx.archiverLastFailedAgo
+		}
+	}
 	mx["catalog_relkind_r_count"] = p.mx.relkindOrdinaryTable
 	mx["catalog_relkind_i_count"] = p.mx.relkindIndex
 	mx["catalog_relkind_S_count"] = p.mx.relkindSequence
@@ -286,11 +296,45 @@ func (p *Postgres) collectMetrics(mx map[string]int64) {
 		}
 		if !m.hasCharts {
 			m.hasCharts = true
-			p.addNewReplicationSlotCharts(name)
+			p.addNewReplicationSlotCharts(m)
 		}
 		px := "repl_slot_" + m.name + "_"
-		mx[px+"replslot_wal_keep"] = m.walKeep
-		mx[px+"replslot_files"] = m.files
+		if p.isSuperUser() {
+			mx[px+"replslot_wal_keep"] = m.walKeep
+			mx[px+"replslot_files"] = m.files
+		}
+		if m.restartLSNLag != nil {
+			mx[px+"restart_lsn_lag"] = *m.restartLSNLag
+		}
+		if m.confirmedFlushLSNLag != nil {
+			mx[px+"confirmed_flush_lsn_lag"] = *m.confirmedFlushLSNLag
+		}
+		if m.safeWALSize != nil {
+			mx[px+"safe_wal_size"] = *m.safeWALSize
+		}
+		if m.active {
+			mx[px+"active"], mx[px+"inactive"] = 1, 0
+		} else {
+			mx[px+"active"], mx[px+"inactive"] = 0, 1
+		}
+	}
+
+	for name, m := range p.mx.stmts {
+		if !m.updated {
+			delete(p.mx.stmts, name)
+			p.removeStatementCharts(m)
+			continue
+		}
+		if !m.hasCharts {
+			m.hasCharts = true
+			p.addNewStatementCharts(m)
+		}
+		px := "statement_" + m.queryID + "_"
+		mx[px+"exec_time"] = m.execTime.total
+		mx[px+"calls"] = m.calls.total
+		mx[px+"rows"] = m.rows.total
+		mx[px+"shared_blks_hit"] = m.sharedBlksHit.total
+		mx[px+"shared_blks_read"] = m.sharedBlksRead.total
 	}
 }
 
@@ -364,4 +408,16 @@ func (p *Postgres) resetMetrics() {
 			hasCharts: m.hasCharts,
 		}
 	}
+	for name, m := range p.mx.stmts {
+		p.mx.stmts[name] = &statementMetrics{
+			queryID:        m.queryID,
+			query:          m.query,
+			hasCharts:      m.hasCharts,
+			calls:          m.calls,
+			execTime:       m.execTime,
+			rows:           m.rows,
+			sharedBlksHit:  m.sharedBlksHit,
+			sharedBlksRead: m.sharedBlksRead,
+		}
+	}
 }
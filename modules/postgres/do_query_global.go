@@ -39,6 +39,11 @@ func (p *Postgres) doQueryGlobalMetrics() error {
 			return fmt.Errorf("querying xact/query running time: %v", err)
 		}
 	}
+	if p.pgVersion >= pgVersion94 {
+		if err := p.doQueryArchiver(); err != nil {
+			return fmt.Errorf("querying archiver error: %v", err)
+		}
+	}
 
 	if !p.isSuperUser() {
 		return nil
@@ -179,6 +184,30 @@ func (p *Postgres) doQueryWALFiles() error {
 	})
 }
 
+func (p *Postgres) doQueryArchiver() error {
+	q := queryArchiver()
+
+	p.mx.archiverLastArchivedAgo, p.mx.archiverLastFailedAgo = -1, -1
+
+	return p.doQuery(q, func(column, value string, _ bool) {
+		switch column {
+		case "archived_count":
+			p.mx.archiverArchivedCount = parseInt(value)
+		case "failed_count":
+			p.mx.archiverFailedCount = parseInt(value)
+		case "last_archived_ago":
+			// NULL if nothing has been archived yet
+			if value != "" {
+				p.mx.archiverLastArchivedAgo = parseFloat(value)
+			}
+		case "last_failed_ago":
+			if value != "" {
+				p.mx.archiverLastFailedAgo = parseFloat(value)
+			}
+		}
+	})
+}
+
 func (p *Postgres) doQueryWALArchiveFiles() error {
 	q := queryWALArchiveFiles(p.pgVersion)
 
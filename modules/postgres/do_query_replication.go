@@ -23,6 +23,12 @@ func (p *Postgres) doQueryReplicationMetrics() error {
 		}
 	}
 
+	if p.pgVersion >= pgVersion10 {
+		if err := p.doQueryReplSlotLag(); err != nil {
+			return fmt.Errorf("querying replication slot lag error: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -92,3 +98,32 @@ func (p *Postgres) doQueryReplSlotFiles() error {
 		}
 	})
 }
+
+func (p *Postgres) doQueryReplSlotLag() error {
+	q := queryReplicationSlotLag(p.pgVersion)
+
+	var slot string
+	return p.doQuery(q, func(column, value string, _ bool) {
+		switch column {
+		case "slot_name":
+			slot = value
+			p.getReplSlotMetrics(slot).updated = true
+		case "slot_type":
+			p.getReplSlotMetrics(slot).slotType = value
+		case "active":
+			p.getReplSlotMetrics(slot).active = value == "true" || value == "t"
+		case "restart_lsn_lag":
+			if value != "" {
+				p.getReplSlotMetrics(slot).restartLSNLag = newInt(parseInt(value))
+			}
+		case "confirmed_flush_lsn_lag":
+			if value != "" {
+				p.getReplSlotMetrics(slot).confirmedFlushLSNLag = newInt(parseInt(value))
+			}
+		case "safe_wal_size":
+			if value != "" {
+				p.getReplSlotMetrics(slot).safeWALSize = newInt(parseInt(value))
+			}
+		}
+	})
+}
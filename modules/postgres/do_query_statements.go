@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+func (p *Postgres) doQueryStatementsMetrics() error {
+	if err := p.doQueryStatStatements(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Postgres) doQueryPgStatStatementsExists() (bool, error) {
+	q := queryPgStatStatementsExists()
+
+	var v bool
+	if err := p.doQueryRow(q, &v); err != nil {
+		return false, err
+	}
+
+	return v, nil
+}
+
+func (p *Postgres) doQueryStatStatements() error {
+	q := queryStatStatements(p.pgVersion, p.MaxStatements)
+
+	var stmt *statementMetrics
+	return p.doQuery(q, func(column, value string, _ bool) {
+		switch column {
+		case "queryid":
+			stmt = p.getStatementMetrics(value)
+			stmt.updated = true
+		case "query":
+			stmt.query = value
+		case "calls":
+			stmt.calls.update(parseInt(value))
+		case "total_exec_time_us":
+			stmt.execTime.update(parseInt(value))
+		case "rows":
+			stmt.rows.update(parseInt(value))
+		case "shared_blks_hit":
+			stmt.sharedBlksHit.update(parseInt(value))
+		case "shared_blks_read":
+			stmt.sharedBlksRead.update(parseInt(value))
+		}
+	})
+}
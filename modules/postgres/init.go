@@ -12,6 +12,9 @@ func (p *Postgres) validateConfig() error {
 	if p.DSN == "" {
 		return errors.New("DSN not set")
 	}
+	if p.CollectStatements && p.MaxStatements <= 0 {
+		return errors.New("'max_statements' must be positive when 'collect_statements' is enabled")
+	}
 	return nil
 }
 
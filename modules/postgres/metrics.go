@@ -11,6 +11,7 @@ type pgMetrics struct {
 	indexes   map[string]*indexMetrics
 	replApps  map[string]*replStandbyAppMetrics
 	replSlots map[string]*replSlotMetrics
+	stmts     map[string]*statementMetrics
 }
 
 type srvMetrics struct {
@@ -72,6 +73,11 @@ type srvMetrics struct {
 	walArchiveFilesReady int64
 	walArchiveFilesDone  int64
 
+	archiverArchivedCount   int64
+	archiverFailedCount     int64
+	archiverLastArchivedAgo int64
+	archiverLastFailedAgo   int64
+
 	autovacuumWorkersAnalyze       int64
 	autovacuumWorkersVacuumAnalyze int64
 	autovacuumWorkersVacuum        int64
@@ -149,8 +155,15 @@ type replSlotMetrics struct {
 	updated   bool
 	hasCharts bool
 
+	slotType string
+	active   bool
+
 	walKeep int64
 	files   int64
+
+	restartLSNLag        *int64
+	confirmedFlushLSNLag *int64 // only logical slots
+	safeWALSize          *int64 // v13+, NULL if 'max_slot_wal_keep_size' is -1
 }
 
 type tableMetrics struct {
@@ -226,6 +239,41 @@ type indexMetrics struct {
 	bloatSize     *int64 // need 'SELECT' access to the table
 	bloatSizePerc *int64 // need 'SELECT' access to the table
 }
+
+type statementMetrics struct {
+	queryID string
+	query   string
+
+	updated   bool
+	hasCharts bool
+
+	// pg_stat_statements counters, they go backwards after pg_stat_statements_reset()
+	calls          resetCounter
+	execTime       resetCounter // microseconds
+	rows           resetCounter
+	sharedBlksHit  resetCounter
+	sharedBlksRead resetCounter
+}
+
 type incDelta struct{ prev, last int64 }
 
 func (pc *incDelta) delta() int64 { return pc.last - pc.prev }
+
+// resetCounter accumulates the increments of a source counter, a decrease of the source value is a counter reset.
+type resetCounter struct {
+	last  int64
+	total int64
+	set   bool
+}
+
+func (rc *resetCounter) update(v int64) {
+	switch {
+	case !rc.set:
+		rc.total, rc.set = v, true
+	case v < rc.last:
+		rc.total += v
+	default:
+		rc.total += v - rc.last
+	}
+	rc.last = v
+}
@@ -38,6 +38,8 @@ func New() *Postgres {
 			// https://discord.com/channels/847502280503590932/1022693928874549368
 			MaxDBTables:  50,
 			MaxDBIndexes: 250,
+			// charts: 4 x statement.
+			MaxStatements: 10,
 		},
 		charts:  baseCharts.Copy(),
 		dbConns: make(map[string]*dbConn),
@@ -47,11 +49,13 @@ func New() *Postgres {
 			tables:    make(map[string]*tableMetrics),
 			replApps:  make(map[string]*replStandbyAppMetrics),
 			replSlots: make(map[string]*replSlotMetrics),
+			stmts:     make(map[string]*statementMetrics),
 		},
 		recheckSettingsEvery:              time.Minute * 30,
 		doSlowEvery:                       time.Minute * 5,
 		addXactQueryRunningTimeChartsOnce: &sync.Once{},
 		addWALFilesChartsOnce:             &sync.Once{},
+		addArchiverChartsOnce:             &sync.Once{},
 	}
 }
 
@@ -63,6 +67,8 @@ type Config struct {
 	QueryTimeHistogram []float64    `yaml:"query_time_histogram"`
 	MaxDBTables        int64        `yaml:"max_db_tables"`
 	MaxDBIndexes       int64        `yaml:"max_db_indexes"`
+	CollectStatements  bool         `yaml:"collect_statements"`
+	MaxStatements      int64        `yaml:"max_statements"`
 }
 
 type (
@@ -79,8 +85,12 @@ type (
 		pgIsInRecovery *bool
 		pgVersion      int
 
+		hasPgStatStatements    bool
+		warnNoPgStatStatements bool
+
 		addXactQueryRunningTimeChartsOnce *sync.Once
 		addWALFilesChartsOnce             *sync.Once
+		addArchiverChartsOnce             *sync.Once
 
 		dbSr matcher.Matcher
 
@@ -2,6 +2,8 @@
 
 package postgres
 
+import "fmt"
+
 func queryServerVersion() string {
 	return "SHOW server_version_num;"
 }
@@ -209,6 +211,16 @@ func queryWALArchiveFiles(version int) string {
 `
 }
 
+func queryArchiver() string {
+	return `
+SELECT archived_count,
+       failed_count,
+       EXTRACT(epoch from now() - last_archived_time) AS last_archived_ago,
+       EXTRACT(epoch from now() - last_failed_time)   AS last_failed_ago
+FROM pg_stat_archiver;
+`
+}
+
 func queryCatalogRelations() string {
 	// kind of same as
 	// https://github.com/netdata/netdata/blob/750810e1798e09cc6210e83594eb9ed4905f8f12/collectors/python.d.plugin/postgres/postgres.chart.py#L336-L354
@@ -429,6 +441,28 @@ GROUP BY
 `
 }
 
+func queryReplicationSlotLag(version int) string {
+	// 'safe_wal_size' was added in v13
+	safeWALSize := "NULL::BIGINT"
+	if version >= pgVersion13 {
+		safeWALSize = "safe_wal_size"
+	}
+
+	return fmt.Sprintf(`
+SELECT slot_name,
+       slot_type,
+       active,
+       pg_wal_lsn_diff(
+               CASE WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn() ELSE pg_current_wal_lsn() END,
+               restart_lsn)::BIGINT         AS restart_lsn_lag,
+       pg_wal_lsn_diff(
+               CASE WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn() ELSE pg_current_wal_lsn() END,
+               confirmed_flush_lsn)::BIGINT AS confirmed_flush_lsn_lag,
+       %s AS safe_wal_size
+FROM pg_replication_slots;
+`, safeWALSize)
+}
+
 func queryQueryableDatabaseList() string {
 	return `
 SELECT datname
@@ -755,3 +789,30 @@ ORDER BY nspname,
          st.attname;
 `
 }
+
+func queryPgStatStatementsExists() string {
+	return "SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements') AS installed;"
+}
+
+func queryStatStatements(version int, limit int64) string {
+	// 'total_time' was renamed to 'total_exec_time' in v13
+	totalTime := "total_exec_time"
+	if version < pgVersion13 {
+		totalTime = "total_time"
+	}
+
+	return fmt.Sprintf(`
+SELECT queryid,
+       left(min(query), 256)                    AS query,
+       sum(calls)                               AS calls,
+       (sum(%s) * 1000)::BIGINT                 AS total_exec_time_us,
+       sum(rows)                                AS rows,
+       sum(shared_blks_hit)                     AS shared_blks_hit,
+       sum(shared_blks_read)                    AS shared_blks_read
+FROM pg_stat_statements
+WHERE queryid IS NOT NULL
+GROUP BY queryid
+ORDER BY total_exec_time_us DESC
+LIMIT %d;
+`, totalTime, limit)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -32,12 +33,18 @@ func New() *AuthoritativeNS {
 					Timeout: web.Duration{Duration: time.Second},
 				},
 			},
+			RingsTopN: 10,
 		},
+		ringDims: make(map[string]map[string]bool),
+		zones:    make(map[string]*zoneState),
 	}
 }
 
 type Config struct {
-	web.HTTP `yaml:",inline"`
+	web.HTTP     `yaml:",inline"`
+	CollectRings bool   `yaml:"collect_rings"`
+	RingsTopN    int    `yaml:"rings_top_n"`
+	CollectZones string `yaml:"collect_zones"`
 }
 
 type AuthoritativeNS struct {
@@ -46,6 +53,11 @@ type AuthoritativeNS struct {
 
 	httpClient *http.Client
 	charts     *module.Charts
+
+	zoneMatcher matcher.Matcher
+
+	ringDims map[string]map[string]bool
+	zones    map[string]*zoneState
 }
 
 func (ns *AuthoritativeNS) Init() bool {
@@ -69,6 +81,13 @@ func (ns *AuthoritativeNS) Init() bool {
 	}
 	ns.charts = cs
 
+	m, err := ns.initZoneMatcher()
+	if err != nil {
+		ns.Errorf("init zone matcher: %v", err)
+		return false
+	}
+	ns.zoneMatcher = m
+
 	return true
 }
 
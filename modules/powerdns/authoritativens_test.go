@@ -16,14 +16,18 @@ import (
 )
 
 var (
-	v430statistics, _     = os.ReadFile("testdata/v4.3.0/statistics.json")
-	recursorStatistics, _ = os.ReadFile("testdata/recursor/statistics.json")
+	v430statistics, _      = os.ReadFile("testdata/v4.3.0/statistics.json")
+	v430statisticsRings, _ = os.ReadFile("testdata/v4.3.0/statistics-rings.json")
+	v430zones, _           = os.ReadFile("testdata/v4.3.0/zones.json")
+	recursorStatistics, _  = os.ReadFile("testdata/recursor/statistics.json")
 )
 
 func Test_testDataIsCorrectlyReadAndValid(t *testing.T) {
 	for name, data := range map[string][]byte{
-		"v430statistics":     v430statistics,
-		"recursorStatistics": recursorStatistics,
+		"v430statistics":      v430statistics,
+		"v430statisticsRings": v430statisticsRings,
+		"v430zones":           v430zones,
+		"recursorStatistics":  recursorStatistics,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -49,6 +53,21 @@ func TestRecursor_Init(t *testing.T) {
 				},
 			},
 		},
+		"fails on invalid rings_top_n": {
+			wantFail: true,
+			config: Config{
+				HTTP:         New().HTTP,
+				CollectRings: true,
+				RingsTopN:    0,
+			},
+		},
+		"fails on invalid collect_zones pattern": {
+			wantFail: true,
+			config: Config{
+				HTTP:         New().HTTP,
+				CollectZones: "[",
+			},
+		},
 		"fails on invalid TLSCA": {
 			wantFail: true,
 			config: Config{
@@ -248,6 +267,105 @@ func TestRecursor_Collect(t *testing.T) {
 	}
 }
 
+func TestAuthoritativeNS_Collect_Rings(t *testing.T) {
+	srv, rings := preparePowerDNSAuthoritativeNSRingsEndpoint()
+	defer srv.Close()
+
+	ns := New()
+	ns.URL = srv.URL
+	ns.CollectRings = true
+	ns.RingsTopN = 3
+	require.True(t, ns.Init())
+
+	collected := ns.Collect()
+	require.NotNil(t, collected)
+
+	for id, want := range map[string]int64{
+		"ring_queries_example.com/A":        120,
+		"ring_queries_example.com/MX":       45,
+		"ring_queries_www.example.com/AAAA": 45,
+		"ring_remotes_192.0.2.10":           150,
+		"ring_remotes_2001:db8::1":          60,
+	} {
+		assert.Equalf(t, want, collected[id], "metric '%s'", id)
+	}
+	assert.NotContains(t, collected, "ring_queries_mail.example.com/A")
+	assert.Len(t, ns.Charts().Get(chartTopQueriedNames.ID).Dims, 3)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, ns, collected)
+
+	// the entry that drops out of the top N is removed from the chart
+	*rings = []byte(`[{"name":"queries","type":"RingStatisticItem","value":[{"name":"mail.example.com/A","value":"500"},{"name":"example.com/A","value":"130"}]},{"name":"remotes","type":"RingStatisticItem","value":[]},{"name":"uptime","type":"StatisticItem","value":"10"}]`)
+
+	collected = ns.Collect()
+	require.NotNil(t, collected)
+
+	assert.Equal(t, int64(500), collected["ring_queries_mail.example.com/A"])
+	assert.Equal(t, int64(130), collected["ring_queries_example.com/A"])
+
+	removed := map[string]bool{}
+	for _, chart := range []string{chartTopQueriedNames.ID, chartTopRemotes.ID} {
+		for _, dim := range ns.Charts().Get(chart).Dims {
+			if dim.Obsolete {
+				removed[dim.ID] = true
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{
+		"ring_queries_example.com/MX":       true,
+		"ring_queries_www.example.com/AAAA": true,
+		"ring_remotes_192.0.2.10":           true,
+		"ring_remotes_2001:db8::1":          true,
+	}, removed)
+}
+
+func TestAuthoritativeNS_Collect_Zones(t *testing.T) {
+	srv, zones := preparePowerDNSAuthoritativeNSZonesEndpoint()
+	defer srv.Close()
+
+	ns := New()
+	ns.URL = srv.URL
+	ns.CollectZones = "example.*"
+	require.True(t, ns.Init())
+
+	collected := ns.Collect()
+	require.NotNil(t, collected)
+
+	for id, want := range map[string]int64{
+		"zone_example.com_serial_age":   0,
+		"zone_example.com_notified":     1,
+		"zone_example.com_not_notified": 0,
+		"zone_example.org_serial_age":   0,
+		"zone_example.org_notified":     0,
+		"zone_example.org_not_notified": 1,
+		"zone_example.net_serial_age":   0,
+	} {
+		v, ok := collected[id]
+		assert.Truef(t, ok, "metric '%s' not collected", id)
+		assert.Equalf(t, want, v, "metric '%s'", id)
+	}
+	assert.NotContains(t, collected, "zone_example.net_notified")
+	assert.NotContains(t, collected, "zone_internal.lan_serial_age")
+
+	assert.NotNil(t, ns.Charts().Get("zone_example_com_notify_status"))
+	assert.Nil(t, ns.Charts().Get("zone_example_net_notify_status"))
+	assert.Nil(t, ns.Charts().Get("zone_internal_lan_serial_age"))
+	ensureCollectedHasAllChartsDimsVarsIDs(t, ns, collected)
+
+	// the zone is deleted
+	*zones = []byte(`[{"name":"example.com.","kind":"Master","serial":2023031401,"notified_serial":2023031401}]`)
+
+	collected = ns.Collect()
+	require.NotNil(t, collected)
+
+	assert.NotContains(t, collected, "zone_example.org_serial_age")
+	for _, id := range []string{"zone_example_org_serial_age", "zone_example_org_notify_status", "zone_example_net_serial_age"} {
+		chart := ns.Charts().Get(id)
+		require.NotNilf(t, chart, "chart '%s'", id)
+		assert.Truef(t, chart.Obsolete, "chart '%s' is not removed", id)
+	}
+	assert.False(t, ns.Charts().Get("zone_example_com_serial_age").Obsolete)
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, ns *AuthoritativeNS, collected map[string]int64) {
 	for _, chart := range *ns.Charts() {
 		if chart.Obsolete {
@@ -321,6 +439,36 @@ func preparePowerDNSAuthoritativeNSEndpoint() *httptest.Server {
 		}))
 }
 
+func preparePowerDNSAuthoritativeNSRingsEndpoint() (*httptest.Server, *[]byte) {
+	rings := v430statisticsRings
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == urlPathLocalStatistics && r.URL.Query().Get("includerings") != "false":
+				_, _ = w.Write(rings)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	return srv, &rings
+}
+
+func preparePowerDNSAuthoritativeNSZonesEndpoint() (*httptest.Server, *[]byte) {
+	zones := v430zones
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case urlPathLocalStatistics:
+				_, _ = w.Write(v430statistics)
+			case urlPathLocalZones:
+				_, _ = w.Write(zones)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	return srv, &zones
+}
+
 func preparePowerDNSRecursorEndpoint() *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
@@ -2,7 +2,12 @@
 
 package powerdns
 
-import "github.com/netdata/go.d.plugin/agent/module"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
 
 var charts = module.Charts{
 	{
@@ -64,3 +69,87 @@ var charts = module.Charts{
 		},
 	},
 }
+
+var ringCharts = module.Charts{
+	chartTopQueriedNames.Copy(),
+	chartTopRemotes.Copy(),
+}
+
+var (
+	chartTopQueriedNames = module.Chart{
+		ID:    "top_queried_names",
+		Title: "Top queried names",
+		Units: "queries",
+		Fam:   "rings",
+		Ctx:   "powerdns.top_queried_names",
+	}
+	chartTopRemotes = module.Chart{
+		ID:    "top_remotes",
+		Title: "Top remotes",
+		Units: "queries",
+		Fam:   "rings",
+		Ctx:   "powerdns.top_remotes",
+	}
+)
+
+const chartPxZone = "zone_"
+
+var (
+	chartTmplZoneSerialAge = module.Chart{
+		ID:    chartPxZone + "%s_serial_age",
+		Title: "Zone serial age",
+		Units: "seconds",
+		Fam:   "zones",
+		Ctx:   "powerdns.zone_serial_age",
+		Dims: module.Dims{
+			{ID: "zone_%s_serial_age", Name: "serial_age"},
+		},
+	}
+	chartTmplZoneNotifyStatus = module.Chart{
+		ID:    chartPxZone + "%s_notify_status",
+		Title: "Zone NOTIFY status",
+		Units: "status",
+		Fam:   "zones",
+		Ctx:   "powerdns.zone_notify_status",
+		Dims: module.Dims{
+			{ID: "zone_%s_notified", Name: "notified"},
+			{ID: "zone_%s_not_notified", Name: "not_notified"},
+		},
+	}
+)
+
+func (ns *AuthoritativeNS) addZoneCharts(name, kind string) {
+	charts := module.Charts{chartTmplZoneSerialAge.Copy()}
+	if isNotifyingZone(kind) {
+		charts = append(charts, chartTmplZoneNotifyStatus.Copy())
+	}
+
+	for _, chart := range charts {
+		chart.ID = fmt.Sprintf(chart.ID, cleanChartID(name))
+		chart.Labels = []module.Label{
+			{Key: "zone", Value: name},
+			{Key: "zone_kind", Value: kind},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, name)
+		}
+	}
+
+	if err := ns.Charts().Add(charts...); err != nil {
+		ns.Warning(err)
+	}
+}
+
+func (ns *AuthoritativeNS) removeZoneCharts(name string) {
+	for _, tmpl := range []module.Chart{chartTmplZoneSerialAge, chartTmplZoneNotifyStatus} {
+		if chart := ns.Charts().Get(fmt.Sprintf(tmpl.ID, cleanChartID(name))); chart != nil {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+func cleanChartID(id string) string {
+	r := strings.NewReplacer(".", "_", " ", "_")
+	return strings.ToLower(r.Replace(id))
+}
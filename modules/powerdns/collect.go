@@ -8,29 +8,41 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
+	"net/url"
 
+	"github.com/netdata/go.d.plugin/modules/powerdns/statistics"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
 const (
 	urlPathLocalStatistics = "/api/v1/servers/localhost/statistics"
+	urlPathLocalZones      = "/api/v1/servers/localhost/zones"
 )
 
 func (ns *AuthoritativeNS) collect() (map[string]int64, error) {
-	statistics, err := ns.scrapeStatistics()
+	stats, err := ns.scrapeStatistics()
 	if err != nil {
 		return nil, err
 	}
 
 	collected := make(map[string]int64)
 
-	ns.collectStatistics(collected, statistics)
+	ns.collectStatistics(collected, stats)
 
 	if !isPowerDNSAuthoritativeNSMetrics(collected) {
 		return nil, errors.New("returned metrics aren't PowerDNS Authoritative Server metrics")
 	}
 
+	if ns.CollectRings {
+		ns.collectRings(collected, stats)
+	}
+
+	if ns.zoneMatcher != nil {
+		if err := ns.collectZones(collected); err != nil {
+			return collected, err
+		}
+	}
+
 	return collected, nil
 }
 
@@ -41,22 +53,16 @@ func isPowerDNSAuthoritativeNSMetrics(collected map[string]int64) bool {
 	return !ok1 && !ok2
 }
 
-func (ns *AuthoritativeNS) collectStatistics(collected map[string]int64, statistics statisticMetrics) {
-	for _, s := range statistics {
+func (ns *AuthoritativeNS) collectStatistics(collected map[string]int64, stats statistics.Statistics) {
+	for _, s := range stats {
 		// https://doc.powerdns.com/authoritative/http-api/statistics.html#statisticitem
-		if s.Type != "StatisticItem" {
+		if s.Type != statistics.TypeStatisticItem {
 			continue
 		}
 
-		value, ok := s.Value.(string)
-		if !ok {
-			ns.Debugf("%s value (%v) unexpected type: want=string, got=%T.", s.Name, s.Value, s.Value)
-			continue
-		}
-
-		v, err := strconv.ParseInt(value, 10, 64)
+		v, err := s.Int()
 		if err != nil {
-			ns.Debugf("%s value (%v) parse error: %v", s.Name, s.Value, err)
+			ns.Debugf("%s value (%s) parse error: %v", s.Name, s.Value, err)
 			continue
 		}
 
@@ -64,16 +70,20 @@ func (ns *AuthoritativeNS) collectStatistics(collected map[string]int64, statist
 	}
 }
 
-func (ns *AuthoritativeNS) scrapeStatistics() ([]statisticMetric, error) {
+func (ns *AuthoritativeNS) scrapeStatistics() (statistics.Statistics, error) {
 	req, _ := web.NewHTTPRequest(ns.Request)
 	req.URL.Path = urlPathLocalStatistics
+	if !ns.CollectRings {
+		// the rings can be large, don't transfer them if not needed
+		req.URL.RawQuery = url.Values{"includerings": []string{"false"}}.Encode()
+	}
 
-	var statistics statisticMetrics
-	if err := ns.doOKDecode(req, &statistics); err != nil {
+	var stats statistics.Statistics
+	if err := ns.doOKDecode(req, &stats); err != nil {
 		return nil, err
 	}
 
-	return statistics, nil
+	return stats, nil
 }
 
 func (ns *AuthoritativeNS) doOKDecode(req *http.Request, in interface{}) error {
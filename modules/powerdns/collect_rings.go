@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package powerdns
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/modules/powerdns/statistics"
+)
+
+// https://doc.powerdns.com/authoritative/http-api/statistics.html#ringstatisticitem
+const (
+	ringQueries = "queries"
+	ringRemotes = "remotes"
+)
+
+var ringChartIDs = map[string]string{
+	ringQueries: chartTopQueriedNames.ID,
+	ringRemotes: chartTopRemotes.ID,
+}
+
+// collectRings collects the top N entries of the rings.
+// The dimensions are recycled: an entry that drops out of the top N is removed from the chart.
+func (ns *AuthoritativeNS) collectRings(collected map[string]int64, stats statistics.Statistics) {
+	for _, s := range stats {
+		chartID, ok := ringChartIDs[s.Name]
+		if !ok || s.Type != statistics.TypeRingStatisticItem {
+			continue
+		}
+
+		entries, err := s.Entries()
+		if err != nil {
+			ns.Debugf("%s ring parse error: %v", s.Name, err)
+			continue
+		}
+
+		top := topRingEntries(entries, ns.RingsTopN)
+
+		seen := make(map[string]bool, len(top))
+		for _, e := range top {
+			id := ringDimID(s.Name, e.name)
+			seen[id] = true
+			collected[id] = e.value
+		}
+
+		ns.updateRingChart(s.Name, chartID, seen)
+	}
+}
+
+func (ns *AuthoritativeNS) updateRingChart(ring, chartID string, seen map[string]bool) {
+	chart := ns.Charts().Get(chartID)
+	if chart == nil {
+		return
+	}
+
+	dims, ok := ns.ringDims[ring]
+	if !ok {
+		dims = make(map[string]bool)
+		ns.ringDims[ring] = dims
+	}
+
+	var changed bool
+	for id := range seen {
+		if dims[id] {
+			continue
+		}
+		dims[id] = true
+		changed = true
+		name := id[len(ringDimID(ring, "")):]
+		if err := chart.AddDim(&module.Dim{ID: id, Name: name}); err != nil {
+			ns.Warning(err)
+		}
+	}
+	for id := range dims {
+		if seen[id] {
+			continue
+		}
+		delete(dims, id)
+		changed = true
+		if err := chart.MarkDimRemove(id, true); err != nil {
+			ns.Warning(err)
+		}
+	}
+	if changed {
+		chart.MarkNotCreated()
+	}
+}
+
+type ringEntry struct {
+	name  string
+	value int64
+}
+
+func topRingEntries(entries []statistics.Entry, n int) []ringEntry {
+	res := make([]ringEntry, 0, len(entries))
+	for _, e := range entries {
+		v, err := e.Int()
+		// the entry name is used in the dimension ID, it can't contain whitespace
+		if err != nil || e.Name == "" || strings.IndexFunc(e.Name, unicode.IsSpace) != -1 {
+			continue
+		}
+		res = append(res, ringEntry{name: e.Name, value: v})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].value != res[j].value {
+			return res[i].value > res[j].value
+		}
+		return res[i].name < res[j].name
+	})
+
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+func ringDimID(ring, name string) string {
+	return "ring_" + ring + "_" + name
+}
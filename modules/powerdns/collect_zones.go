@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package powerdns
+
+import (
+	"strings"
+	"time"
+
+	"github.com/netdata/go.d.plugin/pkg/web"
+)
+
+type zoneState struct {
+	serial          int64
+	serialChangedAt time.Time
+}
+
+func (ns *AuthoritativeNS) collectZones(collected map[string]int64) error {
+	zones, err := ns.scrapeZones()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+
+	for _, z := range zones {
+		name := strings.TrimSuffix(z.Name, ".")
+		if name == "" || seen[name] || !ns.zoneMatcher.MatchString(name) {
+			continue
+		}
+		seen[name] = true
+
+		st, ok := ns.zones[name]
+		if !ok {
+			st = &zoneState{serial: z.Serial, serialChangedAt: now}
+			ns.zones[name] = st
+			ns.Debugf("new zone '%s': adding charts", name)
+			ns.addZoneCharts(name, z.Kind)
+		}
+		if st.serial != z.Serial {
+			st.serial = z.Serial
+			st.serialChangedAt = now
+		}
+
+		px := "zone_" + name + "_"
+		collected[px+"serial_age"] = int64(now.Sub(st.serialChangedAt).Seconds())
+		if isNotifyingZone(z.Kind) {
+			// the secondaries are not notified about the current serial yet or the NOTIFY failed
+			collected[px+"notified"] = boolToInt(z.NotifiedSerial >= z.Serial)
+			collected[px+"not_notified"] = boolToInt(z.NotifiedSerial < z.Serial)
+		}
+	}
+
+	for name := range ns.zones {
+		if !seen[name] {
+			delete(ns.zones, name)
+			ns.Debugf("stale zone '%s': removing charts", name)
+			ns.removeZoneCharts(name)
+		}
+	}
+
+	return nil
+}
+
+func (ns *AuthoritativeNS) scrapeZones() ([]zone, error) {
+	req, _ := web.NewHTTPRequest(ns.Request)
+	req.URL.Path = urlPathLocalZones
+
+	var zones []zone
+	if err := ns.doOKDecode(req, &zones); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// isNotifyingZone reports whether the server sends NOTIFY messages for the zone kind.
+func isNotifyingZone(kind string) bool {
+	switch strings.ToLower(kind) {
+	case "master", "primary", "producer":
+		return true
+	}
+	return false
+}
+
+func boolToInt(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
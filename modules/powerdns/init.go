@@ -4,9 +4,11 @@ package powerdns
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -17,6 +19,9 @@ func (ns AuthoritativeNS) validateConfig() error {
 	if _, err := web.NewHTTPRequest(ns.Request); err != nil {
 		return err
 	}
+	if ns.CollectRings && ns.RingsTopN <= 0 {
+		return fmt.Errorf("invalid rings_top_n value: %d", ns.RingsTopN)
+	}
 	return nil
 }
 
@@ -25,5 +30,18 @@ func (ns AuthoritativeNS) initHTTPClient() (*http.Client, error) {
 }
 
 func (ns AuthoritativeNS) initCharts() (*module.Charts, error) {
-	return charts.Copy(), nil
+	cs := charts.Copy()
+	if ns.CollectRings {
+		if err := cs.Add(*ringCharts.Copy()...); err != nil {
+			return nil, err
+		}
+	}
+	return cs, nil
+}
+
+func (ns AuthoritativeNS) initZoneMatcher() (matcher.Matcher, error) {
+	if ns.CollectZones == "" {
+		return nil, nil
+	}
+	return matcher.NewGlobMatcher(ns.CollectZones)
 }
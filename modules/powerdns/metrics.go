@@ -2,12 +2,10 @@
 
 package powerdns
 
-// https://doc.powerdns.com/authoritative/http-api/statistics.html#objects
-type (
-	statisticMetrics []statisticMetric
-	statisticMetric  struct {
-		Name  string
-		Type  string
-		Value interface{}
-	}
-)
+// https://doc.powerdns.com/authoritative/http-api/zone.html#zone
+type zone struct {
+	Name           string `json:"name"`
+	Kind           string `json:"kind"`
+	Serial         int64  `json:"serial"`
+	NotifiedSerial int64  `json:"notified_serial"`
+}
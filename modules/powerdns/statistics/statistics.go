@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package statistics decodes the statistics objects returned by the PowerDNS Authoritative Server
+// and PowerDNS Recursor REST API ('/api/v1/servers/localhost/statistics').
+package statistics
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// https://doc.powerdns.com/authoritative/http-api/statistics.html#objects
+const (
+	TypeStatisticItem     = "StatisticItem"
+	TypeMapStatisticItem  = "MapStatisticItem"
+	TypeRingStatisticItem = "RingStatisticItem"
+)
+
+type (
+	Statistics []Metric
+	Metric     struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		// Value is a single value for StatisticItem and a list of entries for MapStatisticItem and RingStatisticItem.
+		Value json.RawMessage `json:"value"`
+	}
+	Entry struct {
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+	}
+)
+
+// Int returns the value of a StatisticItem.
+func (m Metric) Int() (int64, error) {
+	return ParseInt(m.Value)
+}
+
+// Entries returns the entries of a MapStatisticItem or RingStatisticItem.
+func (m Metric) Entries() ([]Entry, error) {
+	var entries []Entry
+	if err := json.Unmarshal(m.Value, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Int returns the value of the entry.
+func (e Entry) Int() (int64, error) {
+	return ParseInt(e.Value)
+}
+
+// ParseInt parses a JSON value as an integer.
+// The API returns the values as strings, but both strings and numbers are accepted.
+func ParseInt(raw json.RawMessage) (int64, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return 0, errors.New("empty value")
+	}
+
+	var s string
+	if raw[0] == '"' {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return 0, err
+		}
+	} else {
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return 0, fmt.Errorf("value (%s) is neither a string nor a number", raw)
+		}
+		s = n.String()
+	}
+
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return v, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("value (%s) is not a number", raw)
+	}
+	return int64(f), nil
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package statistics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInt(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		"string":          {input: `"123"`, want: 123},
+		"number":          {input: `123`, want: 123},
+		"negative number": {input: `-5`, want: -5},
+		"float string":    {input: `"1.9"`, want: 1},
+		"float number":    {input: `2.5e3`, want: 2500},
+		"with spaces":     {input: ` "7" `, want: 7},
+		"empty":           {input: ``, wantErr: true},
+		"empty string":    {input: `""`, wantErr: true},
+		"not a number":    {input: `"abc"`, wantErr: true},
+		"bool":            {input: `true`, wantErr: true},
+		"null":            {input: `null`, wantErr: true},
+		"list":            {input: `[]`, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v, err := ParseInt(json.RawMessage(test.input))
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.want, v)
+			}
+		})
+	}
+}
+
+func TestStatistics_Decode(t *testing.T) {
+	data := `[
+  {"name": "uptime", "type": "StatisticItem", "value": "1624"},
+  {"name": "fd-usage", "type": "StatisticItem", "value": 32},
+  {"name": "response-by-qtype", "type": "MapStatisticItem", "value": [{"name": "A", "value": "10"}, {"name": "AAAA", "value": 5}]}
+]`
+	var stats Statistics
+	require.NoError(t, json.Unmarshal([]byte(data), &stats))
+	require.Len(t, stats, 3)
+
+	v, err := stats[0].Int()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1624), v)
+
+	v, err = stats[1].Int()
+	require.NoError(t, err)
+	assert.Equal(t, int64(32), v)
+
+	_, err = stats[2].Int()
+	assert.Error(t, err)
+
+	entries, err := stats[2].Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "AAAA", entries[1].Name)
+	v, err = entries[1].Int()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), v)
+
+	_, err = stats[0].Entries()
+	assert.Error(t, err)
+}
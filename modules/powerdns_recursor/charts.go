@@ -44,6 +44,34 @@ var charts = module.Charts{
 			{ID: "answers-slow", Name: "slow", Algo: module.Incremental},
 		},
 	},
+	{
+		ID:    "auth4_answer_time",
+		Title: "Queries answered by authoritative servers over IPv4 within a time range",
+		Units: "queries/s",
+		Fam:   "performance",
+		Ctx:   "powerdns_recursor.auth4_answer_time",
+		Dims: module.Dims{
+			{ID: "auth4-answers0-1", Name: "0-1ms", Algo: module.Incremental},
+			{ID: "auth4-answers1-10", Name: "1-10ms", Algo: module.Incremental},
+			{ID: "auth4-answers10-100", Name: "10-100ms", Algo: module.Incremental},
+			{ID: "auth4-answers100-1000", Name: "100-1000ms", Algo: module.Incremental},
+			{ID: "auth4-answers-slow", Name: "slow", Algo: module.Incremental},
+		},
+	},
+	{
+		ID:    "auth6_answer_time",
+		Title: "Queries answered by authoritative servers over IPv6 within a time range",
+		Units: "queries/s",
+		Fam:   "performance",
+		Ctx:   "powerdns_recursor.auth6_answer_time",
+		Dims: module.Dims{
+			{ID: "auth6-answers0-1", Name: "0-1ms", Algo: module.Incremental},
+			{ID: "auth6-answers1-10", Name: "1-10ms", Algo: module.Incremental},
+			{ID: "auth6-answers10-100", Name: "10-100ms", Algo: module.Incremental},
+			{ID: "auth6-answers100-1000", Name: "100-1000ms", Algo: module.Incremental},
+			{ID: "auth6-answers-slow", Name: "slow", Algo: module.Incremental},
+		},
+	},
 	{
 		ID:    "timeouts",
 		Title: "Timeouts on outgoing UDP queries",
@@ -56,6 +84,26 @@ var charts = module.Charts{
 			{ID: "outgoing6-timeouts", Name: "ipv6", Algo: module.Incremental},
 		},
 	},
+	{
+		ID:    "throttling",
+		Title: "Throttled outgoing queries",
+		Units: "queries/s",
+		Fam:   "performance",
+		Ctx:   "powerdns_recursor.throttling",
+		Dims: module.Dims{
+			{ID: "throttled-out", Name: "throttled", Algo: module.Incremental},
+		},
+	},
+	{
+		ID:    "throttle_entries",
+		Title: "Throttle map entries",
+		Units: "entries",
+		Fam:   "performance",
+		Ctx:   "powerdns_recursor.throttle_entries",
+		Dims: module.Dims{
+			{ID: "throttle-entries", Name: "entries"},
+		},
+	},
 	{
 		ID:    "drops",
 		Title: "Drops",
@@ -83,6 +131,18 @@ var charts = module.Charts{
 			{ID: "packetcache-misses", Name: "packet-cache-misses", Algo: module.Incremental},
 		},
 	},
+	{
+		ID:    "packet_cache_hit_ratio",
+		Title: "Packet Cache Hit Ratio",
+		Units: "percentage",
+		Fam:   "cache",
+		Ctx:   "powerdns_recursor.packet_cache_hit_ratio",
+		Type:  module.Stacked,
+		Dims: module.Dims{
+			{ID: "packetcache-hits", Name: "hits", Algo: module.PercentOfIncremental},
+			{ID: "packetcache-misses", Name: "misses", Algo: module.PercentOfIncremental},
+		},
+	},
 	{
 		ID:    "cache_size",
 		Title: "Cache Size",
@@ -95,4 +155,27 @@ var charts = module.Charts{
 			{ID: "negcache-entries", Name: "negative-cache"},
 		},
 	},
+	{
+		ID:    "answers_rcode",
+		Title: "Answers by response code",
+		Units: "answers/s",
+		Fam:   "answers",
+		Ctx:   "powerdns_recursor.answers_rcode",
+		Dims: module.Dims{
+			{ID: "noerror-answers", Name: "noerror", Algo: module.Incremental},
+			{ID: "nxdomain-answers", Name: "nxdomain", Algo: module.Incremental},
+			{ID: "servfail-answers", Name: "servfail", Algo: module.Incremental},
+		},
+	},
+	chartAnswersByQType.Copy(),
+}
+
+// chartAnswersByQType has a dimension per query type, the dimensions are added on collection.
+var chartAnswersByQType = module.Chart{
+	ID:    "answers_qtype",
+	Title: "Answers by query type",
+	Units: "answers/s",
+	Fam:   "answers",
+	Ctx:   "powerdns_recursor.answers_qtype",
+	Type:  module.Stacked,
 }
@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
+	"strings"
+	"unicode"
 
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/modules/powerdns/statistics"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -18,19 +21,21 @@ const (
 )
 
 func (r *Recursor) collect() (map[string]int64, error) {
-	statistics, err := r.scrapeStatistics()
+	stats, err := r.scrapeStatistics()
 	if err != nil {
 		return nil, err
 	}
 
 	collected := make(map[string]int64)
 
-	r.collectStatistics(collected, statistics)
+	r.collectStatistics(collected, stats)
 
 	if !isPowerDNSRecursorMetrics(collected) {
 		return nil, errors.New("returned metrics aren't PowerDNS Recursor metrics")
 	}
 
+	r.collectResponseByQType(collected, stats)
+
 	return collected, nil
 }
 
@@ -41,39 +46,101 @@ func isPowerDNSRecursorMetrics(collected map[string]int64) bool {
 	return ok1 && ok2
 }
 
-func (r *Recursor) collectStatistics(collected map[string]int64, statistics statisticMetrics) {
-	for _, s := range statistics {
+func (r *Recursor) collectStatistics(collected map[string]int64, stats statistics.Statistics) {
+	for _, s := range stats {
 		// https://doc.powerdns.com/authoritative/http-api/statistics.html#statisticitem
-		if s.Type != "StatisticItem" {
+		if s.Type != statistics.TypeStatisticItem {
 			continue
 		}
 
-		value, ok := s.Value.(string)
-		if !ok {
-			r.Debugf("%s value (%v) unexpected type: want=string, got=%T.", s.Name, s.Value, s.Value)
+		v, err := s.Int()
+		if err != nil {
+			r.Debugf("%s value (%s) parse error: %v", s.Name, s.Value, err)
 			continue
 		}
 
-		v, err := strconv.ParseInt(value, 10, 64)
-		if err != nil {
-			r.Debugf("%s value (%v) parse error: %v", s.Name, s.Value, err)
+		collected[s.Name] = v
+	}
+}
+
+// collectResponseByQType collects the 'response-by-qtype' map, the dimensions are recycled:
+// a query type that is no longer reported is removed from the chart.
+func (r *Recursor) collectResponseByQType(collected map[string]int64, stats statistics.Statistics) {
+	seen := make(map[string]bool)
+
+	for _, s := range stats {
+		if s.Name != "response-by-qtype" || s.Type != statistics.TypeMapStatisticItem {
 			continue
 		}
 
-		collected[s.Name] = v
+		entries, err := s.Entries()
+		if err != nil {
+			r.Debugf("%s value parse error: %v", s.Name, err)
+			return
+		}
+
+		for _, e := range entries {
+			v, err := e.Int()
+			if err != nil || e.Name == "" || strings.IndexFunc(e.Name, unicode.IsSpace) != -1 {
+				r.Debugf("%s entry '%s' (%s) skipped", s.Name, e.Name, e.Value)
+				continue
+			}
+			id := qtypeDimID(e.Name)
+			seen[e.Name] = true
+			collected[id] = v
+		}
 	}
+
+	r.updateQTypeDims(seen)
+}
+
+func (r *Recursor) updateQTypeDims(seen map[string]bool) {
+	chart := r.Charts().Get(chartAnswersByQType.ID)
+	if chart == nil {
+		return
+	}
+
+	var changed bool
+	for qtype := range seen {
+		if r.qtypes[qtype] {
+			continue
+		}
+		r.qtypes[qtype] = true
+		changed = true
+		dim := &module.Dim{ID: qtypeDimID(qtype), Name: qtype, Algo: module.Incremental}
+		if err := chart.AddDim(dim); err != nil {
+			r.Warning(err)
+		}
+	}
+	for qtype := range r.qtypes {
+		if seen[qtype] {
+			continue
+		}
+		delete(r.qtypes, qtype)
+		changed = true
+		if err := chart.MarkDimRemove(qtypeDimID(qtype), true); err != nil {
+			r.Warning(err)
+		}
+	}
+	if changed {
+		chart.MarkNotCreated()
+	}
+}
+
+func qtypeDimID(qtype string) string {
+	return "response-by-qtype_" + qtype
 }
 
-func (r *Recursor) scrapeStatistics() ([]statisticMetric, error) {
+func (r *Recursor) scrapeStatistics() (statistics.Statistics, error) {
 	req, _ := web.NewHTTPRequest(r.Request)
 	req.URL.Path = urlPathLocalStatistics
 
-	var statistics statisticMetrics
-	if err := r.doOKDecode(req, &statistics); err != nil {
+	var stats statistics.Statistics
+	if err := r.doOKDecode(req, &stats); err != nil {
 		return nil, err
 	}
 
-	return statistics, nil
+	return stats, nil
 }
 
 func (r *Recursor) doOKDecode(req *http.Request, in interface{}) error {
@@ -6,13 +6,4 @@ package powerdns_recursor
 // https://docs.powerdns.com/recursor/performance.html#recursor-caches
 
 // PowerDNS Recursor documentation has no section about statistics objects,
-// fortunately authoritative has.
-// https://doc.powerdns.com/authoritative/http-api/statistics.html#objects
-type (
-	statisticMetrics []statisticMetric
-	statisticMetric  struct {
-		Name  string
-		Type  string
-		Value interface{}
-	}
-)
+// they are the same as the authoritative ones (see the powerdns/statistics package).
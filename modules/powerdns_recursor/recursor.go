@@ -33,6 +33,7 @@ func New() *Recursor {
 				},
 			},
 		},
+		qtypes: make(map[string]bool),
 	}
 }
 
@@ -46,6 +47,8 @@ type Recursor struct {
 
 	httpClient *http.Client
 	charts     *module.Charts
+
+	qtypes map[string]bool
 }
 
 func (r *Recursor) Init() bool {
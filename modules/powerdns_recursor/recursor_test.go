@@ -215,6 +215,9 @@ func TestRecursor_Collect(t *testing.T) {
 				"qname-min-fallback-success":    1,
 				"query-pipe-full-drops":         1,
 				"questions":                     1,
+				"response-by-qtype_A":           91,
+				"response-by-qtype_AAAA":        37,
+				"response-by-qtype_PTR":         4,
 				"real-memory-usage":             44773376,
 				"rebalanced-queries":            1,
 				"resource-limits":               1,
@@ -283,6 +286,55 @@ func TestRecursor_Collect(t *testing.T) {
 	}
 }
 
+func TestRecursor_Collect_ResponseByQType(t *testing.T) {
+	data := []byte(`[
+  {"name": "over-capacity-drops", "type": "StatisticItem", "value": "1"},
+  {"name": "tcp-questions", "type": "StatisticItem", "value": 2},
+  {"name": "response-by-qtype", "type": "MapStatisticItem", "value": [{"name": "A", "value": "10"}, {"name": "MX", "value": 3}]}
+]`)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(data)
+		}))
+	defer srv.Close()
+
+	rec := New()
+	rec.URL = srv.URL
+	require.True(t, rec.Init())
+
+	assert.Equal(t, map[string]int64{
+		"over-capacity-drops":  1,
+		"tcp-questions":        2,
+		"response-by-qtype_A":  10,
+		"response-by-qtype_MX": 3,
+	}, rec.Collect())
+
+	// the query type that is no longer reported is removed from the chart
+	data = []byte(`[
+  {"name": "over-capacity-drops", "type": "StatisticItem", "value": "1"},
+  {"name": "tcp-questions", "type": "StatisticItem", "value": "2"},
+  {"name": "response-by-qtype", "type": "MapStatisticItem", "value": [{"name": "A", "value": "12"}, {"name": "TXT", "value": "1"}]}
+]`)
+	assert.Equal(t, map[string]int64{
+		"over-capacity-drops":   1,
+		"tcp-questions":         2,
+		"response-by-qtype_A":   12,
+		"response-by-qtype_TXT": 1,
+	}, rec.Collect())
+
+	chart := rec.Charts().Get(chartAnswersByQType.ID)
+	require.NotNil(t, chart)
+	dims := make(map[string]bool)
+	for _, dim := range chart.Dims {
+		dims[dim.ID] = dim.Obsolete
+	}
+	assert.Equal(t, map[string]bool{
+		"response-by-qtype_A":   false,
+		"response-by-qtype_MX":  true,
+		"response-by-qtype_TXT": false,
+	}, dims)
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, rec *Recursor, collected map[string]int64) {
 	for _, chart := range *rec.Charts() {
 		if chart.Obsolete {
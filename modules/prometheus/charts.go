@@ -15,8 +15,59 @@ import (
 const (
 	prioDefault   = module.Priority
 	prioGORuntime = prioDefault + 10
+	prioScrape    = prioDefault + 20
 )
 
+// The scrape metadata IDs contain '-', it is not allowed in the metric names, so they can't clash with the metrics.
+const (
+	scrapeDurationID = "scrape-duration"
+	scrapeBodySizeID = "scrape-body_size"
+	scrapeSamplesID  = "scrape-samples"
+)
+
+func (p *Prometheus) addScrapeCharts() {
+	charts := module.Charts{
+		{
+			ID:       scrapeDurationID,
+			Title:    "Scrape duration",
+			Units:    "milliseconds",
+			Fam:      "scrape",
+			Ctx:      getChartContext(p.application(), "scrape_duration"),
+			Priority: prioScrape,
+			Dims: module.Dims{
+				{ID: scrapeDurationID, Name: "duration", Div: 1000},
+			},
+		},
+		{
+			ID:       scrapeBodySizeID,
+			Title:    "Scrape response body size",
+			Units:    "bytes",
+			Fam:      "scrape",
+			Ctx:      getChartContext(p.application(), "scrape_body_size"),
+			Type:     module.Area,
+			Priority: prioScrape + 1,
+			Dims: module.Dims{
+				{ID: scrapeBodySizeID, Name: "size"},
+			},
+		},
+		{
+			ID:       scrapeSamplesID,
+			Title:    "Scraped samples",
+			Units:    "samples",
+			Fam:      "scrape",
+			Ctx:      getChartContext(p.application(), "scrape_samples"),
+			Priority: prioScrape + 2,
+			Dims: module.Dims{
+				{ID: scrapeSamplesID, Name: "samples"},
+			},
+		},
+	}
+
+	if err := p.Charts().Add(charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
 func (p *Prometheus) addGaugeChart(id, name, help string, labels labels.Labels) {
 	units := getChartUnits(name)
 
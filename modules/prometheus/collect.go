@@ -72,9 +72,25 @@ func (p *Prometheus) collect() (map[string]int64, error) {
 		}
 	}
 
+	if len(mx) > 0 {
+		p.collectScrapeMeta(mx)
+	}
+
 	return mx, nil
 }
 
+func (p *Prometheus) collectScrapeMeta(mx map[string]int64) {
+	if !p.scrapeChartsAdded {
+		p.scrapeChartsAdded = true
+		p.addScrapeCharts()
+	}
+
+	meta := p.prom.LastScrape()
+	mx[scrapeDurationID] = meta.Duration.Microseconds()
+	mx[scrapeBodySizeID] = meta.BodySize
+	mx[scrapeSamplesID] = int64(meta.Samples)
+}
+
 func (p *Prometheus) collectGauge(mx map[string]int64, mf *prometheus.MetricFamily) {
 	for _, m := range mf.Metrics() {
 		if m.Gauge() == nil || math.IsNaN(m.Gauge().Value()) {
@@ -5,7 +5,6 @@ package prometheus
 import (
 	"errors"
 	"fmt"
-	"os"
 
 	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
@@ -26,23 +25,17 @@ func (p *Prometheus) initPrometheusClient() (prometheus.Prometheus, error) {
 	}
 
 	req := p.Request.Copy()
-	if p.BearerTokenFile != "" {
-		token, err := os.ReadFile(p.BearerTokenFile)
-		if err != nil {
-			return nil, fmt.Errorf("bearer token file: %v", err)
-		}
-		req.Headers["Authorization"] = "Bearer " + string(token)
-	}
 
 	sr, err := p.Selector.Parse()
 	if err != nil {
 		return nil, fmt.Errorf("parsing selector: %v", err)
 	}
 
-	if sr != nil {
-		return prometheus.NewWithSelector(httpClient, req, sr), nil
-	}
-	return prometheus.New(httpClient, req), nil
+	return prometheus.NewWithOptions(httpClient, req, prometheus.Options{
+		Selector:       sr,
+		SampleLimit:    p.MaxSamples,
+		PreferProtobuf: p.PreferProtobuf,
+	}), nil
 }
 
 func (p *Prometheus) initFallbackTypeMatcher(expr []string) (matcher.Matcher, error) {
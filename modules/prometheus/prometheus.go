@@ -43,16 +43,17 @@ func New() *Prometheus {
 }
 
 type Config struct {
-	web.HTTP        `yaml:",inline"`
-	Name            string `yaml:"name"`
-	Application     string `yaml:"app"`
-	BearerTokenFile string `yaml:"bearer_token_file"`
+	web.HTTP    `yaml:",inline"`
+	Name        string `yaml:"name"`
+	Application string `yaml:"app"`
 
 	Selector selector.Expr `yaml:"selector"`
 
 	ExpectedPrefix string `yaml:"expected_prefix"`
 	MaxTS          int    `yaml:"max_time_series"`
 	MaxTSPerMetric int    `yaml:"max_time_series_per_metric"`
+	MaxSamples     int    `yaml:"max_samples"`
+	PreferProtobuf bool   `yaml:"prefer_protobuf"`
 	FallbackType   struct {
 		Counter []string `yaml:"counter"`
 		Gauge   []string `yaml:"gauge"`
@@ -68,6 +69,8 @@ type Prometheus struct {
 	prom  prometheus.Prometheus
 	cache *cache
 
+	scrapeChartsAdded bool
+
 	fallbackType struct {
 		counter matcher.Matcher
 		gauge   matcher.Matcher
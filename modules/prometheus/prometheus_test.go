@@ -113,6 +113,23 @@ test_counter_no_meta_metric_1_total{label1="value2"} 11
 				return prom, srv.Close
 			},
 		},
+		"fail if the num of samples exceeds the limit": {
+			wantFail: true,
+			prepare: func() (prom *Prometheus, cleanup func()) {
+				srv := httptest.NewServer(http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						_, _ = w.Write([]byte(`
+test_counter_no_meta_metric_1_total{label1="value1"} 11
+test_counter_no_meta_metric_1_total{label1="value2"} 11
+`))
+					}))
+				prom = New()
+				prom.URL = srv.URL
+				prom.MaxSamples = 1
+
+				return prom, srv.Close
+			},
+		},
 		"fail if metrics have no expected prefix": {
 			wantFail: true,
 			prepare: func() (prom *Prometheus, cleanup func()) {
@@ -571,9 +588,16 @@ test_gauge_no_meta_metric_1{label1="value2"} 12
 						mx = prom.Collect()
 					}
 
+					require.Contains(t, mx, scrapeDurationID)
+					require.Contains(t, mx, scrapeSamplesID)
+					assert.Equal(t, int64(len(step.input)), mx[scrapeBodySizeID])
+					for _, id := range []string{scrapeDurationID, scrapeBodySizeID, scrapeSamplesID} {
+						delete(mx, id)
+					}
+
 					assert.Equal(t, step.wantCollected, mx)
 					removeObsoleteCharts(prom.Charts())
-					assert.Len(t, *prom.Charts(), step.wantCharts)
+					assert.Len(t, *prom.Charts(), step.wantCharts+3, "+3 scrape metadata charts")
 				})
 			}
 		})
@@ -4,9 +4,10 @@ package proxysql
 
 type (
 	cache struct {
-		commands map[string]*commandCache
-		users    map[string]*userCache
-		backends map[string]*backendCache
+		commands   map[string]*commandCache
+		users      map[string]*userCache
+		backends   map[string]*backendCache
+		queryRules map[string]*queryRuleCache
 	}
 	commandCache struct {
 		command            string
@@ -20,6 +21,10 @@ type (
 		hg, host, port     string
 		hasCharts, updated bool
 	}
+	queryRuleCache struct {
+		id, pattern        string
+		hasCharts, updated bool
+	}
 )
 
 func (c *cache) reset() {
@@ -32,6 +37,9 @@ func (c *cache) reset() {
 	for k, m := range c.backends {
 		c.backends[k] = &backendCache{hg: m.hg, host: m.host, port: m.port, hasCharts: m.hasCharts}
 	}
+	for k, m := range c.queryRules {
+		c.queryRules[k] = &queryRuleCache{id: m.id, pattern: m.pattern, hasCharts: m.hasCharts}
+	}
 }
 
 func (c *cache) getCommand(command string) *commandCache {
@@ -61,3 +69,12 @@ func (c *cache) getBackend(hg, host, port string) *backendCache {
 	}
 	return v
 }
+
+func (c *cache) getQueryRule(id string) *queryRuleCache {
+	v, ok := c.queryRules[id]
+	if !ok {
+		v = &queryRuleCache{id: id}
+		c.queryRules[id] = v
+	}
+	return v
+}
@@ -50,6 +50,7 @@ const (
 	prioBackendQueriesRateRate
 	prioBackendTraffic
 	prioBackendLatency
+	prioQueryRuleHits
 	prioUptime
 )
 
@@ -695,6 +696,7 @@ func newBackendCharts(hg, host, port string) *module.Charts {
 	for _, chart := range *charts {
 		chart.ID = fmt.Sprintf(chart.ID, backendID(hg, host, port))
 		chart.Labels = []module.Label{
+			{Key: "hostgroup", Value: hg},
 			{Key: "host", Value: host},
 			{Key: "port", Value: port},
 		}
@@ -724,3 +726,57 @@ func (p *ProxySQL) removeBackendCharts(hg, host, port string) {
 		}
 	}
 }
+
+var (
+	queryRuleChartsTmpl = module.Charts{
+		queryRuleHitsChartTmpl.Copy(),
+	}
+
+	queryRuleHitsChartTmpl = module.Chart{
+		ID:       "query_rule_%s_hits",
+		Title:    "Query rule hits",
+		Units:    "hits/s",
+		Fam:      "query rules",
+		Ctx:      "proxysql.query_rule_hits",
+		Priority: prioQueryRuleHits,
+		Dims: module.Dims{
+			{ID: "query_rule_%s_hits", Name: "hits", Algo: module.Incremental},
+		},
+	}
+)
+
+func newQueryRuleCharts(id, pattern string) *module.Charts {
+	charts := queryRuleChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, id)
+		chart.Labels = []module.Label{
+			{Key: "rule_id", Value: id},
+			{Key: "match_pattern", Value: pattern},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, id)
+		}
+	}
+
+	return charts
+}
+
+func (p *ProxySQL) addQueryRuleCharts(id, pattern string) {
+	charts := newQueryRuleCharts(id, pattern)
+
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
+func (p *ProxySQL) removeQueryRuleCharts(id string) {
+	prefix := "query_rule_" + id + "_"
+
+	for _, chart := range *p.Charts() {
+		if strings.HasPrefix(chart.ID, prefix) {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
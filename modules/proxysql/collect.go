@@ -18,8 +18,13 @@ const (
 	queryStatsMySQLCommandsCounters = "SELECT * FROM stats_mysql_commands_counters;"
 	queryStatsMySQLUsers            = "SELECT * FROM stats_mysql_users;"
 	queryStatsMySQLConnectionPool   = "SELECT * FROM stats_mysql_connection_pool;"
+	queryStatsMySQLQueryRules       = "SELECT rule_id, hits FROM stats_mysql_query_rules;"
+	queryRuntimeMySQLQueryRules     = "SELECT rule_id, match_digest, match_pattern FROM runtime_mysql_query_rules;"
 )
 
+// queryRulePatternMaxLen is the max length of the query rule 'match_pattern' label.
+const queryRulePatternMaxLen = 64
+
 func (p *ProxySQL) collect() (map[string]int64, error) {
 	if p.db == nil {
 		if err := p.openConnection(); err != nil {
@@ -52,8 +57,23 @@ func (p *ProxySQL) collect() (map[string]int64, error) {
 	if err := p.collectStatsMySQLUsers(mx); err != nil {
 		return nil, fmt.Errorf("error on collecting mysql users: %v", err)
 	}
-	if err := p.collectStatsMySQLConnectionPool(mx); err != nil {
-		return nil, fmt.Errorf("error on collecting mysql connection pool: %v", err)
+	if p.doConnectionPool {
+		if err := p.collectStatsMySQLConnectionPool(mx); err != nil {
+			if !isNoSuchTableError(err) {
+				return nil, fmt.Errorf("error on collecting mysql connection pool: %v", err)
+			}
+			p.Warningf("mysql connection pool stats are not available, disabling them: %v", err)
+			p.doConnectionPool = false
+		}
+	}
+	if p.doQueryRules {
+		if err := p.collectStatsMySQLQueryRules(mx); err != nil {
+			if !isNoSuchTableError(err) {
+				return nil, fmt.Errorf("error on collecting mysql query rules: %v", err)
+			}
+			p.Warningf("mysql query rules stats are not available, disabling them: %v", err)
+			p.doQueryRules = false
+		}
 	}
 
 	p.updateCharts()
@@ -150,10 +170,15 @@ func (p *ProxySQL) collectStatsMySQLConnectionPool(mx map[string]int64) error {
 
 	var hg, host, port string
 	var px string
+	var skip bool
 	return p.doQuery(q, func(column, value string, rowEnd bool) {
+		if skip && column != "hg" && column != "hostgroup" {
+			return
+		}
 		switch column {
 		case "hg", "hostgroup":
 			hg = value
+			skip = p.hostgroupSelector != nil && !p.hostgroupSelector.MatchString(hg)
 		case "srv_host":
 			host = value
 		case "srv_port":
@@ -161,16 +186,73 @@ func (p *ProxySQL) collectStatsMySQLConnectionPool(mx map[string]int64) error {
 			p.cache.getBackend(hg, host, port).updated = true
 			px = "backend_" + backendID(hg, host, port) + "_"
 		case "status":
-			mx[px+"status_ONLINE"] = boolToInt(value == "1")
-			mx[px+"status_SHUNNED"] = boolToInt(value == "2")
-			mx[px+"status_OFFLINE_SOFT"] = boolToInt(value == "3")
-			mx[px+"status_OFFLINE_HARD"] = boolToInt(value == "4")
+			status := backendStatus(value)
+			mx[px+"status_ONLINE"] = boolToInt(status == "ONLINE")
+			mx[px+"status_SHUNNED"] = boolToInt(status == "SHUNNED")
+			mx[px+"status_OFFLINE_SOFT"] = boolToInt(status == "OFFLINE_SOFT")
+			mx[px+"status_OFFLINE_HARD"] = boolToInt(status == "OFFLINE_HARD")
 		default:
 			mx[px+column] = parseInt(value)
 		}
 	})
 }
 
+func (p *ProxySQL) collectStatsMySQLQueryRules(mx map[string]int64) error {
+	// https://proxysql.com/documentation/stats-statistics/#stats_mysql_query_rules
+	q := queryStatsMySQLQueryRules
+	p.Debugf("executing query: '%s'", q)
+
+	var id string
+	var hasNew bool
+	err := p.doQuery(q, func(column, value string, rowEnd bool) {
+		switch column {
+		case "rule_id":
+			id = value
+			rule := p.cache.getQueryRule(id)
+			rule.updated = true
+			hasNew = hasNew || !rule.hasCharts
+		case "hits":
+			mx["query_rule_"+id+"_hits"] = parseInt(value)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if hasNew {
+		p.collectQueryRulesPatterns()
+	}
+	return nil
+}
+
+// collectQueryRulesPatterns sets the new rules patterns, they are used as a chart label only so the errors are not fatal.
+func (p *ProxySQL) collectQueryRulesPatterns() {
+	q := queryRuntimeMySQLQueryRules
+	p.Debugf("executing query: '%s'", q)
+
+	var id, digest string
+	err := p.doQuery(q, func(column, value string, rowEnd bool) {
+		switch column {
+		case "rule_id":
+			id = value
+		case "match_digest":
+			digest = value
+		case "match_pattern":
+			rule, ok := p.cache.queryRules[id]
+			if !ok || rule.hasCharts {
+				return
+			}
+			if value == "" {
+				value = digest
+			}
+			rule.pattern = truncate(value, queryRulePatternMaxLen)
+		}
+	})
+	if err != nil {
+		p.Debugf("error on querying mysql query rules patterns: %v", err)
+	}
+}
+
 func (p *ProxySQL) updateCharts() {
 	for k, m := range p.cache.commands {
 		if !m.updated {
@@ -205,6 +287,17 @@ func (p *ProxySQL) updateCharts() {
 			p.addBackendCharts(m.hg, m.host, m.port)
 		}
 	}
+	for k, m := range p.cache.queryRules {
+		if !m.updated {
+			delete(p.cache.queryRules, k)
+			p.removeQueryRuleCharts(m.id)
+			continue
+		}
+		if !m.hasCharts {
+			m.hasCharts = true
+			p.addQueryRuleCharts(m.id, m.pattern)
+		}
+	}
 }
 
 func (p *ProxySQL) openConnection() error {
@@ -301,6 +394,33 @@ func boolToInt(v bool) int64 {
 	return 0
 }
 
+// backendStatus returns the backend server status name, it is a number in some ProxySQL versions.
+func backendStatus(value string) string {
+	switch value {
+	case "1":
+		return "ONLINE"
+	case "2":
+		return "SHUNNED"
+	case "3":
+		return "OFFLINE_SOFT"
+	case "4":
+		return "OFFLINE_HARD"
+	}
+	return value
+}
+
+// isNoSuchTableError reports whether the table doesn't exist in the admin interface (old version or custom build).
+func isNoSuchTableError(err error) bool {
+	return strings.Contains(err.Error(), "no such table")
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
 func backendID(hg, host, port string) string {
 	hg = strings.ReplaceAll(strings.ToLower(hg), " ", "_")
 	host = strings.ReplaceAll(host, ".", "_")
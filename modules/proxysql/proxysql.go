@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -33,17 +34,21 @@ func New() *ProxySQL {
 		charts: baseCharts.Copy(),
 		once:   &sync.Once{},
 		cache: &cache{
-			commands: make(map[string]*commandCache),
-			users:    make(map[string]*userCache),
-			backends: make(map[string]*backendCache),
+			commands:   make(map[string]*commandCache),
+			users:      make(map[string]*userCache),
+			backends:   make(map[string]*backendCache),
+			queryRules: make(map[string]*queryRuleCache),
 		},
+		doConnectionPool: true,
+		doQueryRules:     true,
 	}
 }
 
 type Config struct {
-	DSN     string       `yaml:"dsn"`
-	MyCNF   string       `yaml:"my.cnf"`
-	Timeout web.Duration `yaml:"timeout"`
+	DSN               string       `yaml:"dsn"`
+	MyCNF             string       `yaml:"my.cnf"`
+	Timeout           web.Duration `yaml:"timeout"`
+	HostgroupSelector string       `yaml:"hostgroup_selector"`
 }
 
 type (
@@ -57,6 +62,11 @@ type (
 
 		once  *sync.Once
 		cache *cache
+
+		hostgroupSelector matcher.Matcher
+		// the tables can be missing depending on the ProxySQL version and build
+		doConnectionPool bool
+		doQueryRules     bool
 	}
 )
 
@@ -66,6 +76,15 @@ func (p *ProxySQL) Init() bool {
 		return false
 	}
 
+	if p.HostgroupSelector != "" {
+		m, err := matcher.NewSimplePatternsMatcher(p.HostgroupSelector)
+		if err != nil {
+			p.Errorf("error on creating hostgroup selector: %v", err)
+			return false
+		}
+		p.hostgroupSelector = m
+	}
+
 	p.Debugf("using DSN [%s]", p.DSN)
 	return true
 }
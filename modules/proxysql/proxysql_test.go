@@ -12,6 +12,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/netdata/go.d.plugin/agent/module"
+
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +26,8 @@ var (
 	dataV2010StatsMySQLCommandsCounters, _ = os.ReadFile("testdata/v2.0.10/stats_mysql_commands_counters.txt")
 	dataV2010StatsMySQLUsers, _            = os.ReadFile("testdata/v2.0.10/stats_mysql_users.txt")
 	dataV2010StatsMySQLConnectionPool, _   = os.ReadFile("testdata/v2.0.10/stats_mysql_connection_pool .txt")
+	dataV2010StatsMySQLQueryRules, _       = os.ReadFile("testdata/v2.0.10/stats_mysql_query_rules.txt")
+	dataV2010RuntimeMySQLQueryRules, _     = os.ReadFile("testdata/v2.0.10/runtime_mysql_query_rules.txt")
 )
 
 func Test_testDataIsValid(t *testing.T) {
@@ -34,6 +38,8 @@ func Test_testDataIsValid(t *testing.T) {
 		"dataV2010StatsMySQLCommandsCounters": dataV2010StatsMySQLCommandsCounters,
 		"dataV2010StatsMySQLUsers":            dataV2010StatsMySQLUsers,
 		"dataV2010StatsMySQLConnectionPool":   dataV2010StatsMySQLConnectionPool,
+		"dataV2010StatsMySQLQueryRules":       dataV2010StatsMySQLQueryRules,
+		"dataV2010RuntimeMySQLQueryRules":     dataV2010RuntimeMySQLQueryRules,
 	} {
 		require.NotNilf(t, data, name)
 		_, err := prepareMockRows(data)
@@ -117,6 +123,8 @@ func TestProxySQL_Check(t *testing.T) {
 				mockExpect(t, m, queryStatsMySQLCommandsCounters, dataV2010StatsMySQLCommandsCounters)
 				mockExpect(t, m, queryStatsMySQLUsers, dataV2010StatsMySQLUsers)
 				mockExpect(t, m, queryStatsMySQLConnectionPool, dataV2010StatsMySQLConnectionPool)
+				mockExpect(t, m, queryStatsMySQLQueryRules, dataV2010StatsMySQLQueryRules)
+				mockExpect(t, m, queryRuntimeMySQLQueryRules, dataV2010RuntimeMySQLQueryRules)
 			},
 		},
 		"fails when error on querying global stats": {
@@ -195,6 +203,8 @@ func TestProxySQL_Collect(t *testing.T) {
 					mockExpect(t, m, queryStatsMySQLCommandsCounters, dataV2010StatsMySQLCommandsCounters)
 					mockExpect(t, m, queryStatsMySQLUsers, dataV2010StatsMySQLUsers)
 					mockExpect(t, m, queryStatsMySQLConnectionPool, dataV2010StatsMySQLConnectionPool)
+					mockExpect(t, m, queryStatsMySQLQueryRules, dataV2010StatsMySQLQueryRules)
+					mockExpect(t, m, queryRuntimeMySQLQueryRules, dataV2010RuntimeMySQLQueryRules)
 				},
 				check: func(t *testing.T, my *ProxySQL) {
 					mx := my.Collect()
@@ -296,7 +306,7 @@ func TestProxySQL_Collect(t *testing.T) {
 						"backend_10_back001-db-master_6001_Queries":               8970367,
 						"backend_10_back001-db-master_6001_status_OFFLINE_HARD":   0,
 						"backend_10_back001-db-master_6001_status_OFFLINE_SOFT":   0,
-						"backend_10_back001-db-master_6001_status_ONLINE":         0,
+						"backend_10_back001-db-master_6001_status_ONLINE":         1,
 						"backend_10_back001-db-master_6001_status_SHUNNED":        0,
 						"backend_11_back001-db-master_6002_Bytes_data_recv":       2903,
 						"backend_11_back001-db-master_6002_Bytes_data_sent":       187675,
@@ -308,7 +318,7 @@ func TestProxySQL_Collect(t *testing.T) {
 						"backend_11_back001-db-master_6002_Queries":               69,
 						"backend_11_back001-db-master_6002_status_OFFLINE_HARD":   0,
 						"backend_11_back001-db-master_6002_status_OFFLINE_SOFT":   0,
-						"backend_11_back001-db-master_6002_status_ONLINE":         0,
+						"backend_11_back001-db-master_6002_status_ONLINE":         1,
 						"backend_11_back001-db-master_6002_status_SHUNNED":        0,
 						"backend_11_back001-db-reader_6003_Bytes_data_recv":       4994101,
 						"backend_11_back001-db-reader_6003_Bytes_data_sent":       163690013,
@@ -320,7 +330,7 @@ func TestProxySQL_Collect(t *testing.T) {
 						"backend_11_back001-db-reader_6003_Queries":               63488,
 						"backend_11_back001-db-reader_6003_status_OFFLINE_HARD":   0,
 						"backend_11_back001-db-reader_6003_status_OFFLINE_SOFT":   0,
-						"backend_11_back001-db-reader_6003_status_ONLINE":         0,
+						"backend_11_back001-db-reader_6003_status_ONLINE":         1,
 						"backend_11_back001-db-reader_6003_status_SHUNNED":        0,
 						"backend_20_back002-db-master_6004_Bytes_data_recv":       266034339,
 						"backend_20_back002-db-master_6004_Bytes_data_sent":       1086994186,
@@ -332,7 +342,7 @@ func TestProxySQL_Collect(t *testing.T) {
 						"backend_20_back002-db-master_6004_Queries":               849461,
 						"backend_20_back002-db-master_6004_status_OFFLINE_HARD":   0,
 						"backend_20_back002-db-master_6004_status_OFFLINE_SOFT":   0,
-						"backend_20_back002-db-master_6004_status_ONLINE":         0,
+						"backend_20_back002-db-master_6004_status_ONLINE":         1,
 						"backend_20_back002-db-master_6004_status_SHUNNED":        0,
 						"backend_21_back002-db-reader_6005_Bytes_data_recv":       984,
 						"backend_21_back002-db-reader_6005_Bytes_data_sent":       6992,
@@ -344,7 +354,7 @@ func TestProxySQL_Collect(t *testing.T) {
 						"backend_21_back002-db-reader_6005_Queries":               8,
 						"backend_21_back002-db-reader_6005_status_OFFLINE_HARD":   0,
 						"backend_21_back002-db-reader_6005_status_OFFLINE_SOFT":   0,
-						"backend_21_back002-db-reader_6005_status_ONLINE":         0,
+						"backend_21_back002-db-reader_6005_status_ONLINE":         1,
 						"backend_21_back002-db-reader_6005_status_SHUNNED":        0,
 						"backend_31_back003-db-master_6006_Bytes_data_recv":       81438709,
 						"backend_31_back003-db-master_6006_Bytes_data_sent":       712803,
@@ -356,7 +366,7 @@ func TestProxySQL_Collect(t *testing.T) {
 						"backend_31_back003-db-master_6006_Queries":               3276,
 						"backend_31_back003-db-master_6006_status_OFFLINE_HARD":   0,
 						"backend_31_back003-db-master_6006_status_OFFLINE_SOFT":   0,
-						"backend_31_back003-db-master_6006_status_ONLINE":         0,
+						"backend_31_back003-db-master_6006_status_ONLINE":         1,
 						"backend_31_back003-db-master_6006_status_SHUNNED":        0,
 						"backend_31_back003-db-reader_6007_Bytes_data_recv":       115810708275,
 						"backend_31_back003-db-reader_6007_Bytes_data_sent":       411900849,
@@ -368,7 +378,7 @@ func TestProxySQL_Collect(t *testing.T) {
 						"backend_31_back003-db-reader_6007_Queries":               2356904,
 						"backend_31_back003-db-reader_6007_status_OFFLINE_HARD":   0,
 						"backend_31_back003-db-reader_6007_status_OFFLINE_SOFT":   0,
-						"backend_31_back003-db-reader_6007_status_ONLINE":         0,
+						"backend_31_back003-db-reader_6007_status_ONLINE":         1,
 						"backend_31_back003-db-reader_6007_status_SHUNNED":        0,
 						"backend_lagging_during_query":                            8880,
 						"backend_offline_during_query":                            8,
@@ -1134,6 +1144,9 @@ func TestProxySQL_Collect(t *testing.T) {
 						"stack_memory_cluster_threads":                            0,
 						"stack_memory_mysql_threads":                              33554432,
 						"whitelisted_sqli_fingerprint":                            0,
+						"query_rule_1_hits":                                       8970367,
+						"query_rule_2_hits":                                       63488,
+						"query_rule_10_hits":                                      0,
 					}
 
 					require.Equal(t, expected, mx)
@@ -1165,6 +1178,115 @@ func TestProxySQL_Collect(t *testing.T) {
 	}
 }
 
+func TestProxySQL_Collect_QueryRules(t *testing.T) {
+	db, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+	require.NoError(t, err)
+	my := New()
+	my.db = db
+	defer func() { _ = db.Close() }()
+
+	require.True(t, my.Init())
+
+	mockExpectAll(t, mock)
+	require.NotNil(t, my.Collect())
+
+	wantPatterns := map[string]string{
+		"query_rule_1_hits":  "^SELECT .* FOR UPDATE$",
+		"query_rule_2_hits":  "^SELECT id, name, email, created_at, updated_at, last_login_at, ...",
+		"query_rule_10_hits": "^INSERT INTO audit_",
+	}
+	for id, pattern := range wantPatterns {
+		chart := my.Charts().Get(id)
+		require.NotNilf(t, chart, "chart '%s'", id)
+		assert.Equal(t, []module.Label{
+			{Key: "rule_id", Value: strings.TrimSuffix(strings.TrimPrefix(id, "query_rule_"), "_hits")},
+			{Key: "match_pattern", Value: pattern},
+		}, chart.Labels)
+	}
+
+	// the patterns are queried only when there are new rules
+	mockExpect(t, mock, queryStatsMySQLGlobal, dataV2010StatsMySQLGlobal)
+	mockExpect(t, mock, queryStatsMySQLMemoryMetrics, dataV2010StatsMemoryMetrics)
+	mockExpect(t, mock, queryStatsMySQLCommandsCounters, dataV2010StatsMySQLCommandsCounters)
+	mockExpect(t, mock, queryStatsMySQLUsers, dataV2010StatsMySQLUsers)
+	mockExpect(t, mock, queryStatsMySQLConnectionPool, dataV2010StatsMySQLConnectionPool)
+	mockExpect(t, mock, queryStatsMySQLQueryRules, dataV2010StatsMySQLQueryRules)
+	require.NotNil(t, my.Collect())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProxySQL_Collect_NoSuchTable(t *testing.T) {
+	db, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+	require.NoError(t, err)
+	my := New()
+	my.db = db
+	defer func() { _ = db.Close() }()
+
+	require.True(t, my.Init())
+
+	mockExpect(t, mock, queryVersion, dataV2010Version)
+	mockExpect(t, mock, queryStatsMySQLGlobal, dataV2010StatsMySQLGlobal)
+	mockExpect(t, mock, queryStatsMySQLMemoryMetrics, dataV2010StatsMemoryMetrics)
+	mockExpect(t, mock, queryStatsMySQLCommandsCounters, dataV2010StatsMySQLCommandsCounters)
+	mockExpect(t, mock, queryStatsMySQLUsers, dataV2010StatsMySQLUsers)
+	mock.ExpectQuery(queryStatsMySQLConnectionPool).WillReturnError(errors.New("no such table: stats_mysql_connection_pool"))
+	mock.ExpectQuery(queryStatsMySQLQueryRules).WillReturnError(errors.New("no such table: stats_mysql_query_rules"))
+
+	mx := my.Collect()
+
+	require.NotNil(t, mx)
+	assert.Contains(t, mx, "Client_Connections_connected")
+	assert.False(t, my.doConnectionPool)
+	assert.False(t, my.doQueryRules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProxySQL_Collect_HostgroupSelector(t *testing.T) {
+	db, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+	)
+	require.NoError(t, err)
+	my := New()
+	my.db = db
+	my.HostgroupSelector = "1*"
+	defer func() { _ = db.Close() }()
+
+	require.True(t, my.Init())
+
+	mockExpectAll(t, mock)
+	mx := my.Collect()
+	require.NotNil(t, mx)
+
+	var backends []string
+	for k := range mx {
+		if strings.HasPrefix(k, "backend_") && strings.HasSuffix(k, "_Queries") {
+			backends = append(backends, k)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"backend_10_back001-db-master_6001_Queries",
+		"backend_11_back001-db-master_6002_Queries",
+		"backend_11_back001-db-reader_6003_Queries",
+	}, backends)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func mockExpectAll(t *testing.T, mock sqlmock.Sqlmock) {
+	mockExpect(t, mock, queryVersion, dataV2010Version)
+	mockExpect(t, mock, queryStatsMySQLGlobal, dataV2010StatsMySQLGlobal)
+	mockExpect(t, mock, queryStatsMySQLMemoryMetrics, dataV2010StatsMemoryMetrics)
+	mockExpect(t, mock, queryStatsMySQLCommandsCounters, dataV2010StatsMySQLCommandsCounters)
+	mockExpect(t, mock, queryStatsMySQLUsers, dataV2010StatsMySQLUsers)
+	mockExpect(t, mock, queryStatsMySQLConnectionPool, dataV2010StatsMySQLConnectionPool)
+	mockExpect(t, mock, queryStatsMySQLQueryRules, dataV2010StatsMySQLQueryRules)
+	mockExpect(t, mock, queryRuntimeMySQLQueryRules, dataV2010RuntimeMySQLQueryRules)
+}
+
 func mustMockRows(t *testing.T, data []byte) *sqlmock.Rows {
 	rows, err := prepareMockRows(data)
 	require.NoError(t, err)
@@ -512,6 +512,14 @@ func (p *Pulsar) adjustCharts(pms prometheus.Series) {
 	}
 }
 
+// removeTopicCharts removes the per topic charts from the namespace charts templates.
+func (p *Pulsar) removeTopicCharts() {
+	for id := range p.topicChartsMapping {
+		p.removeNamespaceChart(id)
+		delete(p.topicChartsMapping, id)
+	}
+}
+
 func (p *Pulsar) removeSummaryChart(chartID string) {
 	if err := p.Charts().Remove(chartID); err != nil {
 		p.Warning(err)
@@ -519,6 +527,10 @@ func (p *Pulsar) removeSummaryChart(chartID string) {
 }
 
 func (p *Pulsar) removeNamespaceChart(chartID string) {
+	if !p.nsCharts.Has(chartID) {
+		// the topic charts are removed if 'topic_metrics' is disabled
+		return
+	}
 	if err := p.nsCharts.Remove(chartID); err != nil {
 		p.Warning(err)
 	}
@@ -655,6 +667,9 @@ func topicChartsMapping() map[string]string {
 }
 
 func extractTopicName(top topic) string {
+	if isOtherTopic(top) {
+		return "other"
+	}
 	// persistent://sample/ns1/demo-1 => p:demo-1
 	if idx := strings.LastIndexByte(top.name, '/'); idx > 0 {
 		return top.name[:1] + ":" + top.name[idx+1:]
@@ -4,6 +4,7 @@ package pulsar
 
 import (
 	"errors"
+	"sort"
 	"strings"
 
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
@@ -52,6 +53,8 @@ func (p *Pulsar) collectMetrics(pms prometheus.Series) map[string]float64 {
 
 func (p *Pulsar) collectBroker(mx map[string]float64, pms prometheus.Series) {
 	pms = findPulsarMetrics(pms)
+	topics := make(map[topic]bool)
+
 	for _, pm := range pms {
 		ns, top := newNamespace(pm), newTopic(pm)
 		if ns.name == "" {
@@ -64,14 +67,67 @@ func (p *Pulsar) collectBroker(mx map[string]float64, pms prometheus.Series) {
 		mx[pm.Name()] += value
 		mx[pm.Name()+"_"+ns.name] += value
 
-		if top.name == "" || !p.topicFilter.MatchString(top.name) {
+		if p.isTopicCollected(top) {
+			topics[top] = true
+		}
+	}
+	mx["pulsar_namespaces_count"] = float64(len(p.curCache.namespaces))
+
+	if len(topics) == 0 {
+		return
+	}
+
+	charted := p.selectChartedTopics(topics)
+
+	for _, pm := range pms {
+		top := newTopic(pm)
+		if !topics[top] {
 			continue
 		}
+		if !charted[top] {
+			top = newOtherTopic(top.namespace)
+		}
 
 		p.curCache.topics[top] = true
-		mx[pm.Name()+"_"+top.name] += value
+		mx[pm.Name()+"_"+top.name] += pm.Value * precision(pm.Name())
 	}
-	mx["pulsar_namespaces_count"] = float64(len(p.curCache.namespaces))
+}
+
+func (p *Pulsar) isTopicCollected(top topic) bool {
+	return p.TopicMetrics && top.name != "" && p.topicFilter.MatchString(top.name)
+}
+
+// selectChartedTopics returns up to 'max_topics' topics to chart individually.
+// Already charted topics keep their slot, the free slots are taken in the topic name order.
+func (p *Pulsar) selectChartedTopics(topics map[topic]bool) map[topic]bool {
+	if p.MaxTopics == 0 || len(topics) <= p.MaxTopics {
+		return topics
+	}
+
+	charted := make(map[topic]bool)
+
+	for top := range p.cache.topics {
+		if topics[top] && len(charted) < p.MaxTopics {
+			charted[top] = true
+		}
+	}
+
+	var candidates []topic
+	for top := range topics {
+		if !charted[top] {
+			candidates = append(candidates, top)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].name < candidates[j].name })
+
+	for _, top := range candidates {
+		if len(charted) >= p.MaxTopics {
+			break
+		}
+		charted[top] = true
+	}
+
+	return charted
 }
 
 func newNamespace(pm prometheus.SeriesSample) namespace {
@@ -87,6 +143,19 @@ func newTopic(pm prometheus.SeriesSample) topic {
 	}
 }
 
+// newOtherTopic returns the topic that aggregates the namespace topics over the 'max_topics' limit.
+// Topic names have the 'persistent://' or 'non-persistent://' prefix, so it doesn't clash with a real topic.
+func newOtherTopic(ns string) topic {
+	return topic{
+		namespace: ns,
+		name:      ns + otherTopicSuffix,
+	}
+}
+
+func isOtherTopic(top topic) bool {
+	return top.name == top.namespace+otherTopicSuffix
+}
+
 func findPulsarMetrics(pms prometheus.Series) prometheus.Series {
 	var ms prometheus.Series
 	for _, pm := range pms {
@@ -115,7 +184,10 @@ func findPulsarMetrics(pms prometheus.Series) prometheus.Series {
 }
 
 func isPulsarHistogram(pm prometheus.SeriesSample) bool {
-	s := pm.Name()
+	return isPulsarHistogramName(pm.Name())
+}
+
+func isPulsarHistogramName(s string) bool {
 	return strings.HasPrefix(s, "pulsar_storage_write_latency") || strings.HasPrefix(s, "pulsar_entry_size")
 }
 
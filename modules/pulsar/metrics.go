@@ -114,3 +114,30 @@ const (
 	metricPulsarReplicationThroughputOut = "pulsar_replication_throughput_out"
 	metricPulsarReplicationBacklog       = "pulsar_replication_backlog"
 )
+
+// collectedMetrics are the series kept by the scrape selector, the histograms are matched by prefix.
+var collectedMetrics = map[string]bool{
+	metricPulsarTopicsCount:                          true,
+	metricPulsarSubscriptionsCount:                   true,
+	metricPulsarProducersCount:                       true,
+	metricPulsarConsumersCount:                       true,
+	metricPulsarRateIn:                               true,
+	metricPulsarRateOut:                              true,
+	metricPulsarThroughputIn:                         true,
+	metricPulsarThroughputOut:                        true,
+	metricPulsarStorageSize:                          true,
+	metricPulsarStorageWriteRate:                     true,
+	metricPulsarStorageReadRate:                      true,
+	metricPulsarMsgBacklog:                           true,
+	metricPulsarSubscriptionDelayed:                  true,
+	metricPulsarSubscriptionMsgRateRedeliver:         true,
+	metricPulsarSubscriptionBlockedOnUnackedMessages: true,
+	metricPulsarReplicationRateIn:                    true,
+	metricPulsarReplicationRateOut:                   true,
+	metricPulsarReplicationThroughputIn:              true,
+	metricPulsarReplicationThroughputOut:             true,
+	metricPulsarReplicationBacklog:                   true,
+}
+
+// otherTopicSuffix is the suffix of the topic that aggregates the topics over the 'max_topics' limit.
+const otherTopicSuffix = "/_other"
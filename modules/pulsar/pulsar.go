@@ -10,8 +10,11 @@ import (
 
 	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
+	"github.com/netdata/go.d.plugin/pkg/prometheus/selector"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
+	"github.com/prometheus/prometheus/model/labels"
+
 	"github.com/netdata/go.d.plugin/agent/module"
 )
 
@@ -42,6 +45,8 @@ func New() *Pulsar {
 			Includes: nil,
 			Excludes: []string{"*"},
 		},
+		TopicMetrics: true,
+		MaxTopics:    100,
 	}
 	return &Pulsar{
 		Config:             config,
@@ -58,6 +63,10 @@ type (
 	Config struct {
 		web.HTTP   `yaml:",inline"`
 		TopicFiler matcher.SimpleExpr `yaml:"topic_filter"`
+		// TopicMetrics disables the per topic dimensions, the namespace charts are kept.
+		TopicMetrics bool `yaml:"topic_metrics"`
+		// MaxTopics limits the number of charted topics, the rest are aggregated into the namespace 'other' dimensions.
+		MaxTopics int `yaml:"max_topics"`
 	}
 
 	Pulsar struct {
@@ -93,6 +102,9 @@ func (p Pulsar) validateConfig() error {
 	if p.URL == "" {
 		return errors.New("URL is not set")
 	}
+	if p.MaxTopics < 0 {
+		return errors.New("'max_topics' can not be negative")
+	}
 	return nil
 }
 
@@ -102,10 +114,20 @@ func (p *Pulsar) initClient() error {
 		return err
 	}
 
-	p.prom = prometheus.New(client, p.Request)
+	p.prom = prometheus.NewWithSelector(client, p.Request, seriesSelector())
 	return nil
 }
 
+// seriesSelector drops the series the collector doesn't use before they are parsed,
+// the brokers expose a lot of per subscription, JVM and ZooKeeper client series.
+// The topic series are kept regardless of the topic filter, the namespace metrics are their sums.
+func seriesSelector() selector.Selector {
+	return selector.Func(func(lbs labels.Labels) bool {
+		name := lbs.Get(labels.MetricName)
+		return collectedMetrics[name] || isPulsarHistogramName(name)
+	})
+}
+
 func (p *Pulsar) initTopicFiler() error {
 	if p.TopicFiler.Empty() {
 		p.topicFilter = matcher.TRUE()
@@ -133,6 +155,9 @@ func (p *Pulsar) Init() bool {
 		p.Errorf("topic filer initialization: %v", err)
 		return false
 	}
+	if !p.TopicMetrics {
+		p.removeTopicCharts()
+	}
 	return true
 }
 
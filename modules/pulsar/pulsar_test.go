@@ -3,6 +3,7 @@
 package pulsar
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/netdata/go.d.plugin/pkg/matcher"
+	"github.com/netdata/go.d.plugin/pkg/prometheus"
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
@@ -57,6 +59,13 @@ func TestPulsar_Init(t *testing.T) {
 			config:   Config{HTTP: web.HTTP{Request: web.Request{URL: ""}}},
 			wantFail: true,
 		},
+		"negative max topics": {
+			config: Config{
+				HTTP:      web.HTTP{Request: web.Request{URL: "http://127.0.0.1:8080/metrics"}},
+				MaxTopics: -1,
+			},
+			wantFail: true,
+		},
 		"nonexistent TLS CA": {
 			config: Config{HTTP: web.HTTP{
 				Request: web.Request{URL: "http://127.0.0.1:8080/metric"},
@@ -203,6 +212,161 @@ func TestPulsar_Collect_RemoveAddNamespacesTopicsInRuntime(t *testing.T) {
 	}
 }
 
+func TestPulsar_Collect_TopicMetricsDisabled(t *testing.T) {
+	pulsar, srv := prepareClientServerStdV250Topics(t)
+	defer srv.Close()
+	pulsar.TopicMetrics = false
+	require.True(t, pulsar.Init())
+
+	collected := pulsar.Collect()
+
+	require.Equal(t, expectedStandaloneV250TopicsFiltered, collected)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, pulsar, collected)
+	for _, chart := range *pulsar.Charts() {
+		assert.Falsef(t, strings.HasPrefix(chart.ID, "topic_"), "unexpected topic chart '%s'", chart.ID)
+	}
+	assert.True(t, pulsar.Charts().Has("messages_rate_namespace_sample/dev"))
+}
+
+func TestPulsar_Collect_MaxTopics(t *testing.T) {
+	pulsar, srv := prepareClientServerStdV250Topics(t)
+	defer srv.Close()
+	pulsar.MaxTopics = 4
+
+	collected := pulsar.Collect()
+	require.NotNil(t, collected)
+
+	// the topics are charted in the name order: 3 'public/functions' topics and 'sample/dev/dev-1'
+	topics := expectedStandaloneV250Topics
+	for _, top := range []string{
+		"persistent://public/functions/assignments",
+		"persistent://public/functions/coordinate",
+		"persistent://public/functions/metadata",
+		"persistent://sample/dev/dev-1",
+	} {
+		assert.Equal(t, topics[metricPulsarRateIn+"_"+top], collected[metricPulsarRateIn+"_"+top])
+	}
+	for _, top := range []string{
+		"persistent://sample/dev/dev-2",
+		"persistent://sample/prod/prod-1",
+		"persistent://sample/prod/prod-2",
+	} {
+		assert.NotContains(t, collected, metricPulsarRateIn+"_"+top)
+	}
+
+	for metric := range collectedMetrics {
+		if _, ok := topics[metric+"_persistent://sample/dev/dev-2"]; !ok {
+			continue
+		}
+		assert.Equal(t,
+			topics[metric+"_persistent://sample/dev/dev-2"],
+			collected[metric+"_sample/dev/_other"], metric)
+		assert.Equal(t,
+			topics[metric+"_persistent://sample/prod/prod-1"]+topics[metric+"_persistent://sample/prod/prod-2"],
+			collected[metric+"_sample/prod/_other"], metric)
+	}
+	assert.NotContains(t, collected, metricPulsarRateIn+"_public/functions/_other")
+
+	chart := pulsar.Charts().Get("topic_messages_rate_in_namespace_sample/prod")
+	require.NotNil(t, chart)
+	require.Len(t, chart.Dims, 1)
+	assert.Equal(t, "other", chart.Dims[0].Name)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, pulsar, collected)
+}
+
+func BenchmarkPulsar_Collect(b *testing.B) {
+	const numTopics = 10000
+	data := genPulsarTopicsMetrics(numTopics)
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(data)
+		}))
+	defer srv.Close()
+
+	tests := map[string]func(p *Pulsar){
+		"all series": func(p *Pulsar) {
+			// no scrape selector, the pre parsing filtering baseline
+			p.prom = prometheus.New(http.DefaultClient, p.Request)
+		},
+		"selected series": func(p *Pulsar) {},
+		"selected series, no topics": func(p *Pulsar) {
+			p.TopicMetrics = false
+			p.removeTopicCharts()
+		},
+	}
+
+	for name, prepare := range tests {
+		b.Run(name, func(b *testing.B) {
+			pulsar := New()
+			pulsar.URL = srv.URL
+			pulsar.MaxTopics = 0
+			require.True(b, pulsar.Init())
+			prepare(pulsar)
+			require.NotNil(b, pulsar.Collect())
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = pulsar.Collect()
+			}
+		})
+	}
+}
+
+// genPulsarTopicsMetrics generates a topic level metrics page: the collected topic series,
+// the per subscription series and the series the collector doesn't use.
+func genPulsarTopicsMetrics(numTopics int) []byte {
+	var sb strings.Builder
+
+	sb.WriteString("# TYPE jvm_memory_bytes_used gauge\n")
+	sb.WriteString("jvm_memory_bytes_used{cluster=\"standalone\",area=\"heap\"} 2.4e+08\n")
+	sb.WriteString("# TYPE pulsar_topics_count gauge\n")
+	sb.WriteString("pulsar_topics_count{cluster=\"standalone\"} 0\n")
+
+	for i := 0; i < numTopics; i++ {
+		ns := fmt.Sprintf("tenant/ns-%d", i%100)
+		lbs := fmt.Sprintf(`cluster="standalone",namespace="%s",topic="persistent://%s/topic-%d"`, ns, ns, i)
+
+		for _, metric := range []string{
+			metricPulsarSubscriptionsCount,
+			metricPulsarProducersCount,
+			metricPulsarConsumersCount,
+			metricPulsarRateIn,
+			metricPulsarRateOut,
+			metricPulsarThroughputIn,
+			metricPulsarThroughputOut,
+			metricPulsarStorageSize,
+			metricPulsarMsgBacklog,
+			"pulsar_storage_backlog_size",
+			"pulsar_storage_offloaded_size",
+			"pulsar_in_bytes_total",
+			"pulsar_in_messages_total",
+		} {
+			sb.WriteString(fmt.Sprintf("%s{%s} %d\n", metric, lbs, i))
+		}
+		for _, le := range []string{"0_5", "1", "5", "10", "20", "50", "100", "200", "1000", "overflow"} {
+			sb.WriteString(fmt.Sprintf("pulsar_storage_write_latency_le_%s{%s} %d\n", le, lbs, i))
+		}
+		for _, metric := range []string{
+			metricPulsarSubscriptionDelayed,
+			metricPulsarSubscriptionMsgRateRedeliver,
+			metricPulsarSubscriptionBlockedOnUnackedMessages,
+			"pulsar_subscription_back_log",
+			"pulsar_subscription_msg_rate_out",
+			"pulsar_subscription_msg_throughput_out",
+			"pulsar_subscription_unacked_messages",
+		} {
+			sb.WriteString(fmt.Sprintf("%s{%s,subscription=\"sub-%d\"} %d\n", metric, lbs, i, i))
+		}
+	}
+	for i := 0; i < 100; i++ {
+		sb.WriteString(fmt.Sprintf("pulsar_topics_count{cluster=\"standalone\",namespace=\"tenant/ns-%d\"} %d\n", i, numTopics/100))
+	}
+
+	return []byte(sb.String())
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, pulsar *Pulsar, collected map[string]int64) {
 	for _, chart := range *pulsar.Charts() {
 		for _, dim := range chart.Dims {
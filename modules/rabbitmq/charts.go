@@ -31,6 +31,12 @@ const (
 
 	prioQueueMessagesCount
 	prioQueueMessagesRate
+	prioQueueConsumersCount
+	prioQueueMemoryUsage
+	prioQueueQuorumMembersCount
+	prioQueueQuorumLeaderStatus
+
+	prioExchangeMessagesRate
 )
 
 var baseCharts = module.Charts{
@@ -59,6 +65,17 @@ var chartsTmplVhost = module.Charts{
 var chartsTmplQueue = module.Charts{
 	chartTmplQueueMessagesCount.Copy(),
 	chartTmplQueueMessagesRate.Copy(),
+	chartTmplQueueConsumersCount.Copy(),
+	chartTmplQueueMemoryUsage.Copy(),
+}
+
+var chartsTmplQuorumQueue = module.Charts{
+	chartTmplQueueQuorumMembersCount.Copy(),
+	chartTmplQueueQuorumLeaderStatus.Copy(),
+}
+
+var chartsTmplExchange = module.Charts{
+	chartTmplExchangeMessagesRate.Copy(),
 }
 
 var (
@@ -298,6 +315,68 @@ var (
 			{ID: "queue_%s_vhost_%s_message_stats_return_unroutable", Name: "return_unroutable", Algo: module.Incremental},
 		},
 	}
+	chartTmplQueueConsumersCount = module.Chart{
+		ID:       "queue_%s_vhost_%s_consumers",
+		Title:    "Queue consumers",
+		Units:    "consumers",
+		Fam:      "queue consumers",
+		Ctx:      "rabbitmq.queue_consumers_count",
+		Priority: prioQueueConsumersCount,
+		Dims: module.Dims{
+			{ID: "queue_%s_vhost_%s_consumers", Name: "consumers"},
+		},
+	}
+	chartTmplQueueMemoryUsage = module.Chart{
+		ID:       "queue_%s_vhost_%s_memory",
+		Title:    "Queue memory usage",
+		Units:    "bytes",
+		Fam:      "queue memory",
+		Ctx:      "rabbitmq.queue_memory_usage",
+		Priority: prioQueueMemoryUsage,
+		Dims: module.Dims{
+			{ID: "queue_%s_vhost_%s_memory", Name: "used"},
+		},
+	}
+	chartTmplQueueQuorumMembersCount = module.Chart{
+		ID:       "queue_%s_vhost_%s_quorum_members",
+		Title:    "Quorum queue members",
+		Units:    "members",
+		Fam:      "queue quorum",
+		Ctx:      "rabbitmq.queue_quorum_members_count",
+		Priority: prioQueueQuorumMembersCount,
+		Dims: module.Dims{
+			{ID: "queue_%s_vhost_%s_quorum_members", Name: "members"},
+			{ID: "queue_%s_vhost_%s_quorum_online", Name: "online"},
+		},
+	}
+	chartTmplQueueQuorumLeaderStatus = module.Chart{
+		ID:       "queue_%s_vhost_%s_quorum_leader",
+		Title:    "Quorum queue leader",
+		Units:    "status",
+		Fam:      "queue quorum",
+		Ctx:      "rabbitmq.queue_quorum_leader_status",
+		Priority: prioQueueQuorumLeaderStatus,
+		Dims: module.Dims{
+			{ID: "queue_%s_vhost_%s_quorum_leader_local", Name: "local"},
+			{ID: "queue_%s_vhost_%s_quorum_leader_remote", Name: "remote"},
+			{ID: "queue_%s_vhost_%s_quorum_leader_none", Name: "none"},
+		},
+	}
+)
+
+var (
+	chartTmplExchangeMessagesRate = module.Chart{
+		ID:       "exchange_%s_vhost_%s_message_stats",
+		Title:    "Exchange messages rate",
+		Units:    "messages/s",
+		Fam:      "exchange messages",
+		Ctx:      "rabbitmq.exchange_messages_rate",
+		Priority: prioExchangeMessagesRate,
+		Dims: module.Dims{
+			{ID: "exchange_%s_vhost_%s_message_stats_publish_in", Name: "publish_in", Algo: module.Incremental},
+			{ID: "exchange_%s_vhost_%s_message_stats_publish_out", Name: "publish_out", Algo: module.Incremental},
+		},
+	}
 )
 
 func (r *RabbitMQ) addVhostCharts(name string) {
@@ -329,8 +408,14 @@ func (r *RabbitMQ) removeVhostCharts(vhost string) {
 }
 
 func (r *RabbitMQ) addQueueCharts(queue, vhost string) {
-	charts := chartsTmplQueue.Copy()
+	r.addQueueChartsFromTmpl(chartsTmplQueue.Copy(), queue, vhost)
+}
+
+func (r *RabbitMQ) addQuorumQueueCharts(queue, vhost string) {
+	r.addQueueChartsFromTmpl(chartsTmplQuorumQueue.Copy(), queue, vhost)
+}
 
+func (r *RabbitMQ) addQueueChartsFromTmpl(charts *module.Charts, queue, vhost string) {
 	for _, chart := range *charts {
 		chart.ID = fmt.Sprintf(chart.ID, forbiddenCharsReplacer.Replace(queue), forbiddenCharsReplacer.Replace(vhost))
 		chart.Labels = []module.Label{
@@ -357,4 +442,33 @@ func (r *RabbitMQ) removeQueueCharts(queue, vhost string) {
 	}
 }
 
+func (r *RabbitMQ) addExchangeCharts(exchange, vhost string) {
+	charts := chartsTmplExchange.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, forbiddenCharsReplacer.Replace(exchange), forbiddenCharsReplacer.Replace(vhost))
+		chart.Labels = []module.Label{
+			{Key: "exchange", Value: exchange},
+			{Key: "vhost", Value: vhost},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, exchange, vhost)
+		}
+	}
+
+	if err := r.Charts().Add(*charts...); err != nil {
+		r.Warning(err)
+	}
+}
+
+func (r *RabbitMQ) removeExchangeCharts(exchange, vhost string) {
+	px := fmt.Sprintf("exchange_%s_vhost_%s_", forbiddenCharsReplacer.Replace(exchange), forbiddenCharsReplacer.Replace(vhost))
+	for _, chart := range *r.Charts() {
+		if strings.HasPrefix(chart.ID, px) {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
 var forbiddenCharsReplacer = strings.NewReplacer(" ", "_", ".", "_")
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"path/filepath"
 
 	"github.com/netdata/go.d.plugin/pkg/stm"
@@ -39,6 +40,11 @@ func (r *RabbitMQ) collect() (map[string]int64, error) {
 			return mx, err
 		}
 	}
+	if r.CollectExchanges {
+		if err := r.collectExchangesStats(mx); err != nil {
+			return mx, err
+		}
+	}
 
 	return mx, nil
 }
@@ -111,46 +117,20 @@ func (r *RabbitMQ) collectVhostsStats(mx map[string]int64) error {
 	return nil
 }
 
-func (r *RabbitMQ) collectQueuesStats(mx map[string]int64) error {
-	var stats []queueStats
-	if err := r.doOKDecode(urlPathAPIQueues, &stats); err != nil {
-		return err
-	}
-
-	seen := make(map[string]queueCache)
-
-	for _, queue := range stats {
-		seen[queue.Name+"|"+queue.Vhost] = queueCache{name: queue.Name, vhost: queue.Vhost}
-		for k, v := range stm.ToMap(queue) {
-			mx[fmt.Sprintf("queue_%s_vhost_%s_%s", queue.Name, queue.Vhost, k)] = v
-		}
-	}
-
-	for key, queue := range seen {
-		if _, ok := r.queues[key]; !ok {
-			r.queues[key] = queue
-			r.Debugf("new queue name='%s', vhost='%s': creating charts", queue.name, queue.vhost)
-			r.addQueueCharts(queue.name, queue.vhost)
-		}
-	}
-	for key, queue := range r.queues {
-		if _, ok := seen[key]; !ok {
-			delete(r.queues, key)
-			r.Debugf("stale queue name='%s', vhost='%s': removing charts", queue.name, queue.vhost)
-			r.removeQueueCharts(queue.name, queue.vhost)
-		}
-	}
-
-	return nil
+func (r *RabbitMQ) doOKDecode(urlPath string, in interface{}) error {
+	return r.doOKDecodeQuery(urlPath, nil, in)
 }
 
-func (r *RabbitMQ) doOKDecode(urlPath string, in interface{}) error {
+func (r *RabbitMQ) doOKDecodeQuery(urlPath string, query url.Values, in interface{}) error {
 	req, err := web.NewHTTPRequest(r.Request.Copy())
 	if err != nil {
 		return fmt.Errorf("error on creating request: %v", err)
 	}
 
 	req.URL.Path = urlPath
+	if query != nil {
+		req.URL.RawQuery = query.Encode()
+	}
 
 	r.Debugf("doing HTTP %s to '%s'", req.Method, req.URL)
 	resp, err := r.httpClient.Do(req)
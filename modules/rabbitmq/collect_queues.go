@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rabbitmq
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/netdata/go.d.plugin/pkg/stm"
+)
+
+const urlPathAPIExchanges = "/api/exchanges"
+
+const (
+	// otherQueue aggregates the queues that are over the 'max_queues' limit.
+	// It has an empty vhost, so it doesn't clash with a real queue.
+	otherQueue = "_other"
+	// defaultExchange is the name of the nameless default exchange.
+	defaultExchange = "amq.default"
+)
+
+func (r *RabbitMQ) collectQueuesStats(mx map[string]int64) error {
+	var stats []queueStats
+	err := r.doOKDecodeAllPages(urlPathAPIQueues, func(data []byte) error {
+		var items []queueStats
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		stats = append(stats, items...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	queues := make(map[string]queueStats)
+	for _, queue := range stats {
+		if !r.isVhostSelected(queue.Vhost) || (r.queueSelector != nil && !r.queueSelector.MatchString(queue.Name)) {
+			continue
+		}
+		queues[queue.Name+"|"+queue.Vhost] = queue
+	}
+
+	charted := r.selectChartedQueues(queues)
+
+	seen := make(map[string]queueCache)
+	other := make(map[string]int64)
+
+	for key, queue := range queues {
+		if !charted[key] {
+			for k, v := range stm.ToMap(queue) {
+				other[k] += v
+			}
+			continue
+		}
+
+		seen[key] = queueCache{name: queue.Name, vhost: queue.Vhost, quorum: queue.Type == "quorum"}
+
+		px := fmt.Sprintf("queue_%s_vhost_%s_", queue.Name, queue.Vhost)
+		for k, v := range stm.ToMap(queue) {
+			mx[px+k] = v
+		}
+		if queue.Type == "quorum" {
+			mx[px+"quorum_members"] = int64(len(queue.Members))
+			mx[px+"quorum_online"] = int64(len(queue.Online))
+			mx[px+"quorum_leader_local"] = boolToInt(queue.Leader != "" && queue.Leader == r.nodeName)
+			mx[px+"quorum_leader_remote"] = boolToInt(queue.Leader != "" && queue.Leader != r.nodeName)
+			mx[px+"quorum_leader_none"] = boolToInt(queue.Leader == "")
+		}
+	}
+
+	if len(charted) < len(queues) {
+		seen[otherQueue+"|"] = queueCache{name: otherQueue}
+		px := fmt.Sprintf("queue_%s_vhost_%s_", otherQueue, "")
+		for k, v := range other {
+			mx[px+k] = v
+		}
+	}
+
+	for key, queue := range seen {
+		if _, ok := r.queues[key]; !ok {
+			r.queues[key] = queue
+			r.Debugf("new queue name='%s', vhost='%s': creating charts", queue.name, queue.vhost)
+			r.addQueueCharts(queue.name, queue.vhost)
+			if queue.quorum {
+				r.addQuorumQueueCharts(queue.name, queue.vhost)
+			}
+		}
+	}
+	for key, queue := range r.queues {
+		if _, ok := seen[key]; !ok {
+			delete(r.queues, key)
+			r.Debugf("stale queue name='%s', vhost='%s': removing charts", queue.name, queue.vhost)
+			r.removeQueueCharts(queue.name, queue.vhost)
+		}
+	}
+
+	return nil
+}
+
+// selectChartedQueues returns up to 'max_queues' queues to chart individually.
+// Already charted queues keep their slot, the free slots are taken by the queues with the most messages.
+func (r *RabbitMQ) selectChartedQueues(queues map[string]queueStats) map[string]bool {
+	charted := make(map[string]bool)
+
+	for key := range r.queues {
+		if _, ok := queues[key]; ok && len(charted) < r.MaxQueues {
+			charted[key] = true
+		}
+	}
+
+	var candidates []string
+	for key := range queues {
+		if !charted[key] {
+			candidates = append(candidates, key)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		mi, mj := queues[candidates[i]].Messages, queues[candidates[j]].Messages
+		if mi == mj {
+			return candidates[i] < candidates[j]
+		}
+		return mi > mj
+	})
+
+	for _, key := range candidates {
+		if len(charted) >= r.MaxQueues {
+			break
+		}
+		charted[key] = true
+	}
+
+	return charted
+}
+
+func (r *RabbitMQ) collectExchangesStats(mx map[string]int64) error {
+	var stats []exchangeStats
+	err := r.doOKDecodeAllPages(urlPathAPIExchanges, func(data []byte) error {
+		var items []exchangeStats
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		stats = append(stats, items...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]exchangeCache)
+
+	for _, exchange := range stats {
+		if !r.isVhostSelected(exchange.Vhost) {
+			continue
+		}
+		name := exchange.Name
+		if name == "" {
+			name = defaultExchange
+		}
+
+		seen[name+"|"+exchange.Vhost] = exchangeCache{name: name, vhost: exchange.Vhost}
+
+		px := fmt.Sprintf("exchange_%s_vhost_%s_", name, exchange.Vhost)
+		for k, v := range stm.ToMap(exchange) {
+			mx[px+k] = v
+		}
+	}
+
+	for key, exchange := range seen {
+		if _, ok := r.exchanges[key]; !ok {
+			r.exchanges[key] = exchange
+			r.Debugf("new exchange name='%s', vhost='%s': creating charts", exchange.name, exchange.vhost)
+			r.addExchangeCharts(exchange.name, exchange.vhost)
+		}
+	}
+	for key, exchange := range r.exchanges {
+		if _, ok := seen[key]; !ok {
+			delete(r.exchanges, key)
+			r.Debugf("stale exchange name='%s', vhost='%s': removing charts", exchange.name, exchange.vhost)
+			r.removeExchangeCharts(exchange.name, exchange.vhost)
+		}
+	}
+
+	return nil
+}
+
+func (r *RabbitMQ) isVhostSelected(vhost string) bool {
+	return r.vhostSelector == nil || r.vhostSelector.MatchString(vhost)
+}
+
+// doOKDecodeAllPages requests all pages of a list endpoint and passes every page items to decodeItems.
+// Zero 'page_size' disables the pagination, the whole list is requested at once.
+func (r *RabbitMQ) doOKDecodeAllPages(urlPath string, decodeItems func(data []byte) error) error {
+	if r.PageSize == 0 {
+		var data json.RawMessage
+		if err := r.doOKDecode(urlPath, &data); err != nil {
+			return err
+		}
+		return decodeItems(data)
+	}
+
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("page_size", strconv.Itoa(r.PageSize))
+
+		var resp pagedResponse
+		if err := r.doOKDecodeQuery(urlPath, query, &resp); err != nil {
+			return err
+		}
+		if err := decodeItems(resp.Items); err != nil {
+			return fmt.Errorf("error on decoding '%s' page %d: %v", urlPath, page, err)
+		}
+		if page >= resp.PageCount {
+			return nil
+		}
+	}
+}
+
+func boolToInt(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
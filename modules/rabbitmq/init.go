@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxPageSize is the management API page size limit.
+const maxPageSize = 500
+
+func (r *RabbitMQ) validateConfig() error {
+	if r.URL == "" {
+		return errors.New("'url' can not be empty")
+	}
+	if r.CollectQueues && r.MaxQueues <= 0 {
+		return errors.New("'max_queues' must be positive when 'collect_queues_metrics' is enabled")
+	}
+	if r.PageSize < 0 || r.PageSize > maxPageSize {
+		return fmt.Errorf("'page_size' must be between 0 and %d", maxPageSize)
+	}
+	return nil
+}
+
+func (r *RabbitMQ) initSelectors() error {
+	if !r.VhostSelector.Empty() {
+		m, err := r.VhostSelector.Parse()
+		if err != nil {
+			return fmt.Errorf("vhost selector: %v", err)
+		}
+		r.vhostSelector = m
+	}
+	if !r.QueueSelector.Empty() {
+		m, err := r.QueueSelector.Parse()
+		if err != nil {
+			return fmt.Errorf("queue selector: %v", err)
+		}
+		r.queueSelector = m
+	}
+	return nil
+}
@@ -2,6 +2,8 @@
 
 package rabbitmq
 
+import "encoding/json"
+
 // https://www.rabbitmq.com/monitoring.html#cluster-wide-metrics
 type overviewStats struct {
 	ObjectTotals struct {
@@ -62,7 +64,32 @@ type queueStats struct {
 	MessagesUnacknowledged int64        `json:"messages_unacknowledged" stm:"messages_unacknowledged"`
 	MessagesPagedOut       int64        `json:"messages_paged_out" stm:"messages_paged_out"`
 	MessagesPersistent     int64        `json:"messages_persistent" stm:"messages_persistent"`
+	Consumers              int64        `json:"consumers" stm:"consumers"`
+	Memory                 int64        `json:"memory" stm:"memory"`
 	MessageStats           messageStats `json:"message_stats" stm:"message_stats"`
+	// quorum queues only
+	// https://www.rabbitmq.com/quorum-queues.html#replication
+	Leader  string   `json:"leader"`
+	Members []string `json:"members"`
+	Online  []string `json:"online"`
+}
+
+// https://www.rabbitmq.com/exchanges.html
+type exchangeStats struct {
+	Name         string `json:"name"`
+	Vhost        string `json:"vhost"`
+	Type         string `json:"type"`
+	MessageStats struct {
+		PublishIn  int64 `json:"publish_in" stm:"publish_in"`
+		PublishOut int64 `json:"publish_out" stm:"publish_out"`
+	} `json:"message_stats" stm:"message_stats"`
+}
+
+// https://www.rabbitmq.com/management.html#pagination
+type pagedResponse struct {
+	Items     json.RawMessage `json:"items"`
+	Page      int             `json:"page"`
+	PageCount int             `json:"page_count"`
 }
 
 // https://rawcdn.githack.com/rabbitmq/rabbitmq-server/v3.11.5/deps/rabbitmq_management/priv/www/api/index.html
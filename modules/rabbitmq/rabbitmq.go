@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
@@ -34,17 +35,26 @@ func New() *RabbitMQ {
 					Timeout: web.Duration{Duration: time.Second},
 				},
 			},
-			CollectQueues: false,
+			CollectQueues:    false,
+			CollectExchanges: false,
+			MaxQueues:        100,
+			PageSize:         500,
 		},
-		charts: baseCharts.Copy(),
-		vhosts: make(map[string]bool),
-		queues: make(map[string]queueCache),
+		charts:    baseCharts.Copy(),
+		vhosts:    make(map[string]bool),
+		queues:    make(map[string]queueCache),
+		exchanges: make(map[string]exchangeCache),
 	}
 }
 
 type Config struct {
-	web.HTTP      `yaml:",inline"`
-	CollectQueues bool `yaml:"collect_queues_metrics"`
+	web.HTTP         `yaml:",inline"`
+	CollectQueues    bool               `yaml:"collect_queues_metrics"`
+	CollectExchanges bool               `yaml:"collect_exchanges_metrics"`
+	VhostSelector    matcher.SimpleExpr `yaml:"vhost_selector"`
+	QueueSelector    matcher.SimpleExpr `yaml:"queue_selector"`
+	MaxQueues        int                `yaml:"max_queues"`
+	PageSize         int                `yaml:"page_size"`
 }
 
 type (
@@ -58,17 +68,30 @@ type (
 
 		nodeName string
 
-		vhosts map[string]bool
-		queues map[string]queueCache
+		vhostSelector matcher.Matcher
+		queueSelector matcher.Matcher
+
+		vhosts    map[string]bool
+		queues    map[string]queueCache
+		exchanges map[string]exchangeCache
 	}
 	queueCache struct {
 		name, vhost string
+		quorum      bool
+	}
+	exchangeCache struct {
+		name, vhost string
 	}
 )
 
 func (r *RabbitMQ) Init() bool {
-	if r.URL == "" {
-		r.Error("'url' can not be empty")
+	if err := r.validateConfig(); err != nil {
+		r.Errorf("config validation: %v", err)
+		return false
+	}
+
+	if err := r.initSelectors(); err != nil {
+		r.Errorf("init selectors: %v", err)
 		return false
 	}
 
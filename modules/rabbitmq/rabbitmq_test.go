@@ -3,31 +3,37 @@
 package rabbitmq
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
 
 var (
-	testOverviewStats, _ = os.ReadFile("testdata/v3.11.5/api-overview.json")
-	testNodeStats, _     = os.ReadFile("testdata/v3.11.5/api-nodes-node.json")
-	testVhostsStats, _   = os.ReadFile("testdata/v3.11.5/api-vhosts.json")
-	testQueuesStats, _   = os.ReadFile("testdata/v3.11.5/api-queues.json")
+	testOverviewStats, _  = os.ReadFile("testdata/v3.11.5/api-overview.json")
+	testNodeStats, _      = os.ReadFile("testdata/v3.11.5/api-nodes-node.json")
+	testVhostsStats, _    = os.ReadFile("testdata/v3.11.5/api-vhosts.json")
+	testQueuesStats, _    = os.ReadFile("testdata/v3.11.5/api-queues.json")
+	testExchangesStats, _ = os.ReadFile("testdata/v3.11.5/api-exchanges.json")
 )
 
 func Test_testDataIsValid(t *testing.T) {
 	for name, data := range map[string][]byte{
-		"testOverviewStats": testOverviewStats,
-		"testNodeStats":     testNodeStats,
-		"testVhostsStats":   testVhostsStats,
-		"testQueuesStats":   testQueuesStats,
+		"testOverviewStats":  testOverviewStats,
+		"testNodeStats":      testNodeStats,
+		"testVhostsStats":    testVhostsStats,
+		"testQueuesStats":    testQueuesStats,
+		"testExchangesStats": testExchangesStats,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -42,6 +48,28 @@ func TestRabbitMQ_Init(t *testing.T) {
 			wantFail: false,
 			config:   New().Config,
 		},
+		"fail when max_queues is not positive": {
+			wantFail: true,
+			config: Config{
+				HTTP:          New().HTTP,
+				CollectQueues: true,
+				MaxQueues:     0,
+			},
+		},
+		"fail when page_size is over the limit": {
+			wantFail: true,
+			config: Config{
+				HTTP:     New().HTTP,
+				PageSize: maxPageSize + 1,
+			},
+		},
+		"fail when queue selector is invalid": {
+			wantFail: true,
+			config: Config{
+				HTTP:          New().HTTP,
+				QueueSelector: matcher.SimpleExpr{Includes: []string{"~ (invalid"}},
+			},
+		},
 		"fail when URL not set": {
 			wantFail: true,
 			config: Config{
@@ -113,16 +141,26 @@ func TestRabbitMQ_Collect(t *testing.T) {
 	}{
 		"success on valid response": {
 			prepare:    caseSuccessAllRequests,
-			wantCharts: len(baseCharts) + len(chartsTmplVhost)*3 + len(chartsTmplQueue)*4,
+			wantCharts: len(baseCharts) + len(chartsTmplVhost)*3 + len(chartsTmplQueue)*5 + len(chartsTmplQuorumQueue) + len(chartsTmplExchange)*5,
 			wantCollected: map[string]int64{
-				"churn_rates_channel_closed":      0,
-				"churn_rates_channel_created":     0,
-				"churn_rates_connection_closed":   0,
-				"churn_rates_connection_created":  0,
-				"churn_rates_queue_created":       6,
-				"churn_rates_queue_declared":      6,
-				"churn_rates_queue_deleted":       2,
-				"disk_free":                       189799186432,
+				"churn_rates_channel_closed":                                            0,
+				"churn_rates_channel_created":                                           0,
+				"churn_rates_connection_closed":                                         0,
+				"churn_rates_connection_created":                                        0,
+				"churn_rates_queue_created":                                             6,
+				"churn_rates_queue_declared":                                            6,
+				"churn_rates_queue_deleted":                                             2,
+				"disk_free":                                                             189799186432,
+				"exchange_amq.default_vhost_/_message_stats_publish_in":                 15,
+				"exchange_amq.default_vhost_/_message_stats_publish_out":                15,
+				"exchange_amq.direct_vhost_/_message_stats_publish_in":                  0,
+				"exchange_amq.direct_vhost_/_message_stats_publish_out":                 0,
+				"exchange_amq.fanout_vhost_/_message_stats_publish_in":                  0,
+				"exchange_amq.fanout_vhost_/_message_stats_publish_out":                 0,
+				"exchange_myFirstExchange_vhost_/_message_stats_publish_in":             120,
+				"exchange_myFirstExchange_vhost_/_message_stats_publish_out":            240,
+				"exchange_myFirstExchange_vhost_myFirstVhost_message_stats_publish_in":  7,
+				"exchange_myFirstExchange_vhost_myFirstVhost_message_stats_publish_out": 14,
 				"fd_total":                        1048576,
 				"fd_used":                         43,
 				"mem_limit":                       6713820774,
@@ -147,6 +185,8 @@ func TestRabbitMQ_Collect(t *testing.T) {
 				"proc_available":                  1048135,
 				"proc_total":                      1048576,
 				"proc_used":                       441,
+				"queue_MyFirstQueue_vhost_mySecondVhost_consumers":                       0,
+				"queue_MyFirstQueue_vhost_mySecondVhost_memory":                          55408,
 				"queue_MyFirstQueue_vhost_mySecondVhost_message_stats_ack":               0,
 				"queue_MyFirstQueue_vhost_mySecondVhost_message_stats_confirm":           0,
 				"queue_MyFirstQueue_vhost_mySecondVhost_message_stats_deliver":           0,
@@ -164,6 +204,8 @@ func TestRabbitMQ_Collect(t *testing.T) {
 				"queue_MyFirstQueue_vhost_mySecondVhost_messages_persistent":             1,
 				"queue_MyFirstQueue_vhost_mySecondVhost_messages_ready":                  1,
 				"queue_MyFirstQueue_vhost_mySecondVhost_messages_unacknowledged":         1,
+				"queue_myFirstQueue_vhost_/_consumers":                                   0,
+				"queue_myFirstQueue_vhost_/_memory":                                      55408,
 				"queue_myFirstQueue_vhost_/_message_stats_ack":                           0,
 				"queue_myFirstQueue_vhost_/_message_stats_confirm":                       0,
 				"queue_myFirstQueue_vhost_/_message_stats_deliver":                       0,
@@ -181,6 +223,8 @@ func TestRabbitMQ_Collect(t *testing.T) {
 				"queue_myFirstQueue_vhost_/_messages_persistent":                         1,
 				"queue_myFirstQueue_vhost_/_messages_ready":                              1,
 				"queue_myFirstQueue_vhost_/_messages_unacknowledged":                     1,
+				"queue_myFirstQueue_vhost_myFirstVhost_consumers":                        0,
+				"queue_myFirstQueue_vhost_myFirstVhost_memory":                           55408,
 				"queue_myFirstQueue_vhost_myFirstVhost_message_stats_ack":                0,
 				"queue_myFirstQueue_vhost_myFirstVhost_message_stats_confirm":            0,
 				"queue_myFirstQueue_vhost_myFirstVhost_message_stats_deliver":            0,
@@ -198,6 +242,32 @@ func TestRabbitMQ_Collect(t *testing.T) {
 				"queue_myFirstQueue_vhost_myFirstVhost_messages_persistent":              1,
 				"queue_myFirstQueue_vhost_myFirstVhost_messages_ready":                   1,
 				"queue_myFirstQueue_vhost_myFirstVhost_messages_unacknowledged":          1,
+				"queue_myQuorumQueue_vhost_/_consumers":                                  2,
+				"queue_myQuorumQueue_vhost_/_memory":                                     142752,
+				"queue_myQuorumQueue_vhost_/_message_stats_ack":                          10,
+				"queue_myQuorumQueue_vhost_/_message_stats_confirm":                      0,
+				"queue_myQuorumQueue_vhost_/_message_stats_deliver":                      12,
+				"queue_myQuorumQueue_vhost_/_message_stats_deliver_get":                  12,
+				"queue_myQuorumQueue_vhost_/_message_stats_deliver_no_ack":               0,
+				"queue_myQuorumQueue_vhost_/_message_stats_get":                          0,
+				"queue_myQuorumQueue_vhost_/_message_stats_get_no_ack":                   0,
+				"queue_myQuorumQueue_vhost_/_message_stats_publish":                      15,
+				"queue_myQuorumQueue_vhost_/_message_stats_publish_in":                   0,
+				"queue_myQuorumQueue_vhost_/_message_stats_publish_out":                  0,
+				"queue_myQuorumQueue_vhost_/_message_stats_redeliver":                    2,
+				"queue_myQuorumQueue_vhost_/_message_stats_return_unroutable":            0,
+				"queue_myQuorumQueue_vhost_/_messages":                                   3,
+				"queue_myQuorumQueue_vhost_/_messages_paged_out":                         0,
+				"queue_myQuorumQueue_vhost_/_messages_persistent":                        3,
+				"queue_myQuorumQueue_vhost_/_messages_ready":                             2,
+				"queue_myQuorumQueue_vhost_/_messages_unacknowledged":                    1,
+				"queue_myQuorumQueue_vhost_/_quorum_leader_local":                        1,
+				"queue_myQuorumQueue_vhost_/_quorum_leader_none":                         0,
+				"queue_myQuorumQueue_vhost_/_quorum_leader_remote":                       0,
+				"queue_myQuorumQueue_vhost_/_quorum_members":                             3,
+				"queue_myQuorumQueue_vhost_/_quorum_online":                              2,
+				"queue_mySecondQueue_vhost_/_consumers":                                  0,
+				"queue_mySecondQueue_vhost_/_memory":                                     55408,
 				"queue_mySecondQueue_vhost_/_message_stats_ack":                          0,
 				"queue_mySecondQueue_vhost_/_message_stats_confirm":                      0,
 				"queue_mySecondQueue_vhost_/_message_stats_deliver":                      0,
@@ -295,11 +365,127 @@ func TestRabbitMQ_Collect(t *testing.T) {
 	}
 }
 
+func TestRabbitMQ_Collect_QueuesLimitAndSelectors(t *testing.T) {
+	tests := map[string]struct {
+		prepare      func(r *RabbitMQ)
+		wantQueues   []string
+		wantNoQueues []string
+		wantOther    int64
+	}{
+		"max_queues keeps the queues with the most messages": {
+			prepare: func(r *RabbitMQ) {
+				r.MaxQueues = 2
+			},
+			wantQueues:   []string{"queue_myQuorumQueue_vhost_/_", "queue_MyFirstQueue_vhost_mySecondVhost_"},
+			wantNoQueues: []string{"queue_myFirstQueue_vhost_/_", "queue_mySecondQueue_vhost_/_", "queue_myFirstQueue_vhost_myFirstVhost_"},
+			wantOther:    3,
+		},
+		"vhost selector": {
+			prepare: func(r *RabbitMQ) {
+				r.VhostSelector = matcher.SimpleExpr{Includes: []string{"* myFirstVhost"}}
+			},
+			wantQueues:   []string{"queue_myFirstQueue_vhost_myFirstVhost_"},
+			wantNoQueues: []string{"queue_myFirstQueue_vhost_/_", "queue_myQuorumQueue_vhost_/_", "queue_MyFirstQueue_vhost_mySecondVhost_"},
+		},
+		"queue selector": {
+			prepare: func(r *RabbitMQ) {
+				r.QueueSelector = matcher.SimpleExpr{Excludes: []string{"~ ^my"}}
+			},
+			wantQueues:   []string{"queue_MyFirstQueue_vhost_mySecondVhost_"},
+			wantNoQueues: []string{"queue_myFirstQueue_vhost_/_", "queue_myQuorumQueue_vhost_/_", "queue_mySecondQueue_vhost_/_"},
+		},
+		"no pagination": {
+			prepare: func(r *RabbitMQ) {
+				r.PageSize = 0
+			},
+			wantQueues: []string{"queue_myFirstQueue_vhost_/_", "queue_myQuorumQueue_vhost_/_", "queue_MyFirstQueue_vhost_mySecondVhost_"},
+		},
+		"pagination with small pages": {
+			prepare: func(r *RabbitMQ) {
+				r.PageSize = 2
+			},
+			wantQueues: []string{"queue_myFirstQueue_vhost_/_", "queue_myQuorumQueue_vhost_/_", "queue_MyFirstQueue_vhost_mySecondVhost_"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rabbit, cleanup := caseSuccessAllRequests()
+			defer cleanup()
+			test.prepare(rabbit)
+			require.True(t, rabbit.Init())
+
+			mx := rabbit.Collect()
+			require.NotNil(t, mx)
+
+			for _, px := range test.wantQueues {
+				assert.Containsf(t, mx, px+"messages", "queue '%s'", px)
+			}
+			for _, px := range test.wantNoQueues {
+				assert.NotContainsf(t, mx, px+"messages", "queue '%s'", px)
+			}
+			if test.wantOther > 0 {
+				assert.Equal(t, test.wantOther, mx["queue__other_vhost__messages"])
+				assert.True(t, rabbit.Charts().Has("queue__other_vhost__message_count"))
+			} else {
+				assert.NotContains(t, mx, "queue__other_vhost__messages")
+			}
+		})
+	}
+}
+
+func TestRabbitMQ_Collect_QueueDeleted(t *testing.T) {
+	queues := testQueuesStats
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case urlPathAPIOverview:
+				_, _ = w.Write(testOverviewStats)
+			case filepath.Join(urlPathAPINodes, "rabbit@localhost"):
+				_, _ = w.Write(testNodeStats)
+			case urlPathAPIVhosts:
+				_, _ = w.Write(testVhostsStats)
+			case urlPathAPIQueues:
+				writePage(w, r, queues)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer srv.Close()
+
+	rabbit := New()
+	rabbit.URL = srv.URL
+	rabbit.CollectQueues = true
+	require.True(t, rabbit.Init())
+
+	require.NotNil(t, rabbit.Collect())
+	require.True(t, rabbit.Charts().Has("queue_myQuorumQueue_vhost_/_quorum_members"))
+
+	// the quorum queue is deleted
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal(testQueuesStats, &items))
+	items = items[:len(items)-1]
+	bs, err := json.Marshal(items)
+	require.NoError(t, err)
+	queues = bs
+
+	mx := rabbit.Collect()
+	require.NotNil(t, mx)
+	assert.NotContains(t, mx, "queue_myQuorumQueue_vhost_/_messages")
+
+	for _, chart := range *rabbit.Charts() {
+		if strings.HasPrefix(chart.ID, "queue_myQuorumQueue_") {
+			assert.Truef(t, chart.Obsolete, "chart '%s' is not obsolete", chart.ID)
+		}
+	}
+}
+
 func caseSuccessAllRequests() (*RabbitMQ, func()) {
 	srv := prepareRabbitMQEndpoint()
 	rabbit := New()
 	rabbit.URL = srv.URL
 	rabbit.CollectQueues = true
+	rabbit.CollectExchanges = true
 
 	return rabbit, srv.Close
 }
@@ -338,10 +524,44 @@ func prepareRabbitMQEndpoint() *httptest.Server {
 				case urlPathAPIVhosts:
 					_, _ = w.Write(testVhostsStats)
 				case urlPathAPIQueues:
-					_, _ = w.Write(testQueuesStats)
+					writePage(w, r, testQueuesStats)
+				case urlPathAPIExchanges:
+					writePage(w, r, testExchangesStats)
 				default:
 					w.WriteHeader(404)
 				}
 			}))
 	return srv
 }
+
+// writePage writes the requested page of the list the same way as the management API does.
+func writePage(w http.ResponseWriter, r *http.Request, data []byte) {
+	if r.URL.Query().Get("page") == "" {
+		_, _ = w.Write(data)
+		return
+	}
+
+	var items []json.RawMessage
+	_ = json.Unmarshal(data, &items)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	pageCount := (len(items) + size - 1) / size
+	if page < 1 || (pageCount > 0 && page > pageCount) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	start, end := (page-1)*size, page*size
+	if end > len(items) {
+		end = len(items)
+	}
+
+	bs, _ := json.Marshal(map[string]any{
+		"items":      items[start:end],
+		"page":       page,
+		"page_count": pageCount,
+		"page_size":  size,
+	})
+	_, _ = w.Write(bs)
+}
@@ -2,7 +2,12 @@
 
 package redis
 
-import "github.com/netdata/go.d.plugin/agent/module"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
 
 const (
 	prioConnections = module.Priority + iota
@@ -23,6 +28,13 @@ const (
 	prioMasterLastIOSinceTime
 	prioMasterLinkDownSinceTime
 
+	prioClusterState
+	prioClusterSlots
+	prioClusterNodes
+	prioClusterEpoch
+	prioClusterNodeLinkState
+	prioClusterNodeRole
+
 	prioPersistenceRDBChanges
 	prioPersistenceRDBBgSaveNow
 	prioPersistenceRDBBgSaveHealth
@@ -33,6 +45,11 @@ const (
 	prioCommandsUsec
 	prioCommandsUsecPerSec
 
+	prioLatencyEventsLatest
+	prioCommandLatencyP50
+	prioCommandLatencyP99
+	prioCommandLatencyP999
+
 	prioKeyExpiration
 	prioKeys
 	prioExpiresKeys
@@ -368,6 +385,210 @@ var (
 	}
 )
 
+var (
+	clusterCharts = module.Charts{
+		chartClusterState.Copy(),
+		chartClusterSlots.Copy(),
+		chartClusterNodes.Copy(),
+		chartClusterEpoch.Copy(),
+	}
+	chartClusterState = module.Chart{
+		ID:       "cluster_state",
+		Title:    "Cluster state",
+		Units:    "state",
+		Fam:      "cluster",
+		Ctx:      "redis.cluster_state",
+		Priority: prioClusterState,
+		Dims: module.Dims{
+			{ID: "cluster_state_ok", Name: "ok"},
+			{ID: "cluster_state_fail", Name: "fail"},
+		},
+	}
+	chartClusterSlots = module.Chart{
+		ID:       "cluster_slots",
+		Title:    "Cluster hash slots",
+		Units:    "slots",
+		Fam:      "cluster",
+		Ctx:      "redis.cluster_slots",
+		Priority: prioClusterSlots,
+		Dims: module.Dims{
+			{ID: "cluster_slots_assigned", Name: "assigned"},
+			{ID: "cluster_slots_ok", Name: "ok"},
+			{ID: "cluster_slots_pfail", Name: "pfail"},
+			{ID: "cluster_slots_fail", Name: "fail"},
+		},
+	}
+	chartClusterNodes = module.Chart{
+		ID:       "cluster_nodes",
+		Title:    "Cluster nodes",
+		Units:    "nodes",
+		Fam:      "cluster",
+		Ctx:      "redis.cluster_nodes",
+		Priority: prioClusterNodes,
+		Dims: module.Dims{
+			{ID: "cluster_known_nodes", Name: "known"},
+			{ID: "cluster_size", Name: "serving_slots"},
+		},
+	}
+	chartClusterEpoch = module.Chart{
+		ID:       "cluster_epoch",
+		Title:    "Cluster epoch",
+		Units:    "epoch",
+		Fam:      "cluster",
+		Ctx:      "redis.cluster_epoch",
+		Priority: prioClusterEpoch,
+		Dims: module.Dims{
+			{ID: "cluster_current_epoch", Name: "current"},
+			{ID: "cluster_my_epoch", Name: "my"},
+		},
+	}
+)
+
+var (
+	clusterNodeChartsTmpl = module.Charts{
+		clusterNodeLinkStateChartTmpl.Copy(),
+		clusterNodeRoleChartTmpl.Copy(),
+	}
+	clusterNodeLinkStateChartTmpl = module.Chart{
+		ID:       "cluster_node_%s_link_state",
+		Title:    "Cluster node link state",
+		Units:    "state",
+		Fam:      "cluster",
+		Ctx:      "redis.cluster_node_link_state",
+		Priority: prioClusterNodeLinkState,
+		Dims: module.Dims{
+			{ID: "cluster_node_%s_link_state_connected", Name: "connected"},
+			{ID: "cluster_node_%s_link_state_disconnected", Name: "disconnected"},
+		},
+	}
+	clusterNodeRoleChartTmpl = module.Chart{
+		ID:       "cluster_node_%s_role",
+		Title:    "Cluster node role",
+		Units:    "role",
+		Fam:      "cluster",
+		Ctx:      "redis.cluster_node_role",
+		Priority: prioClusterNodeRole,
+		Dims: module.Dims{
+			{ID: "cluster_node_%s_role_master", Name: "master"},
+			{ID: "cluster_node_%s_role_replica", Name: "replica"},
+		},
+	}
+)
+
+func (r *Redis) addClusterCharts() {
+	if err := r.Charts().Add(*clusterCharts.Copy()...); err != nil {
+		r.Warning(err)
+	}
+}
+
+func (r *Redis) addClusterNodeCharts(node clusterNode) {
+	charts := clusterNodeChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, node.id)
+		chart.Labels = []module.Label{
+			{Key: "node_id", Value: node.id},
+			{Key: "node_address", Value: node.address},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, node.id)
+		}
+	}
+
+	if err := r.Charts().Add(*charts...); err != nil {
+		r.Warning(err)
+	}
+}
+
+func (r *Redis) removeClusterNodeCharts(id string) {
+	px := fmt.Sprintf("cluster_node_%s_", id)
+
+	for _, chart := range *r.Charts() {
+		if strings.HasPrefix(chart.ID, px) {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+var (
+	chartLatencyEventsLatest = module.Chart{
+		ID:       "latency_events_latest",
+		Title:    "Latest latency spike per event",
+		Units:    "milliseconds",
+		Fam:      "latency",
+		Ctx:      "redis.latency_events_latest",
+		Priority: prioLatencyEventsLatest,
+	}
+	latencyCommandsCharts = module.Charts{
+		chartCommandLatencyP50.Copy(),
+		chartCommandLatencyP99.Copy(),
+		chartCommandLatencyP999.Copy(),
+	}
+	chartCommandLatencyP50 = module.Chart{
+		ID:       "command_latency_p50",
+		Title:    "Command latency 50th percentile",
+		Units:    "milliseconds",
+		Fam:      "latency",
+		Ctx:      "redis.command_latency_p50",
+		Priority: prioCommandLatencyP50,
+	}
+	chartCommandLatencyP99 = module.Chart{
+		ID:       "command_latency_p99",
+		Title:    "Command latency 99th percentile",
+		Units:    "milliseconds",
+		Fam:      "latency",
+		Ctx:      "redis.command_latency_p99",
+		Priority: prioCommandLatencyP99,
+	}
+	chartCommandLatencyP999 = module.Chart{
+		ID:       "command_latency_p999",
+		Title:    "Command latency 99.9th percentile",
+		Units:    "milliseconds",
+		Fam:      "latency",
+		Ctx:      "redis.command_latency_p999",
+		Priority: prioCommandLatencyP999,
+	}
+)
+
+var latencyPercentileNames = []string{"p50", "p99", "p999"}
+
+func (r *Redis) addLatencyEventsChart() {
+	if err := r.Charts().Add(chartLatencyEventsLatest.Copy()); err != nil {
+		r.Warning(err)
+	}
+}
+
+func (r *Redis) addLatencyEventDim(event string) {
+	r.addDimToChart(chartLatencyEventsLatest.ID, &module.Dim{
+		ID:   "latency_event_" + event,
+		Name: event,
+		Div:  precision,
+	})
+}
+
+func (r *Redis) addLatencyCommandsCharts() {
+	if err := r.Charts().Add(*latencyCommandsCharts.Copy()...); err != nil {
+		r.Warning(err)
+	}
+}
+
+func (r *Redis) addLatencyCommandDims(cmd string) {
+	for _, p := range latencyPercentileNames {
+		r.addDimToChart("command_latency_"+p, &module.Dim{
+			ID:   "latency_cmd_" + cmd + "_" + p,
+			Name: strings.ToUpper(cmd),
+			Div:  precision,
+		})
+	}
+}
+
+func (r *Redis) removeLatencyCommandDims(cmd string) {
+	for _, p := range latencyPercentileNames {
+		r.removeDimFromChart("command_latency_"+p, "latency_cmd_"+cmd+"_"+p)
+	}
+}
+
 var (
 	chartUptime = module.Chart{
 		ID:       "uptime",
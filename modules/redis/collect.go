@@ -17,6 +17,9 @@ const precision = 1000 // float values multiplier and dimensions divisor
 func (r *Redis) collect() (map[string]int64, error) {
 	info, err := r.rdb.Info(context.Background(), "all").Result()
 	if err != nil {
+		if isRedirectError(err) {
+			return nil, fmt.Errorf("%v (redirects are not followed, a job monitors a single node)", err)
+		}
 		return nil, err
 	}
 
@@ -35,11 +38,32 @@ func (r *Redis) collect() (map[string]int64, error) {
 
 	mx := make(map[string]int64)
 	r.collectInfo(mx, info)
+
+	if mx["cluster_enabled"] == 1 {
+		if err := r.collectCluster(mx); err != nil {
+			r.Warning(err)
+		}
+		// cluster nodes only have db0, 'INFO keyspace' is empty if the node has no keys
+		if !has(mx, "db0_keys") {
+			r.collectInfoKeyspaceProperty(mx, "db0", "keys=0,expires=0,avg_ttl=0")
+		}
+	}
+
+	if r.CollectLatency {
+		r.collectLatency(mx, info)
+	}
+
 	r.collectPingLatency(mx)
 
 	return mx, nil
 }
 
+// isRedirectError reports whether the error is a cluster redirection ('MOVED' or 'ASK').
+func isRedirectError(err error) bool {
+	s := err.Error()
+	return strings.HasPrefix(s, "MOVED ") || strings.HasPrefix(s, "ASK ")
+}
+
 // redis_version:6.0.9
 var reVersion = regexp.MustCompile(`([a-z]+)_version:(\d+\.\d+\.\d+)`)
 
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxClusterNodes limits the number of the cluster nodes with per-node charts.
+const maxClusterNodes = 64
+
+func (r *Redis) collectCluster(mx map[string]int64) error {
+	// https://redis.io/commands/cluster-info/
+	info, err := r.rdb.ClusterInfo(context.Background()).Result()
+	if err != nil {
+		return fmt.Errorf("error on CLUSTER INFO: %v", err)
+	}
+
+	r.addClusterChartsOnce.Do(r.addClusterCharts)
+	r.collectClusterInfo(mx, info)
+
+	// https://redis.io/commands/cluster-nodes/
+	nodes, err := r.rdb.ClusterNodes(context.Background()).Result()
+	if err != nil {
+		return fmt.Errorf("error on CLUSTER NODES: %v", err)
+	}
+
+	r.collectClusterNodes(mx, nodes)
+
+	return nil
+}
+
+func (r *Redis) collectClusterInfo(mx map[string]int64, info string) {
+	for sc := bufio.NewScanner(strings.NewReader(info)); sc.Scan(); {
+		field, value, ok := parseProperty(strings.TrimSpace(sc.Text()))
+		if !ok {
+			continue
+		}
+
+		switch field {
+		case "cluster_state":
+			mx["cluster_state_ok"] = boolToInt(value == "ok")
+			mx["cluster_state_fail"] = boolToInt(value == "fail")
+		case "cluster_slots_assigned",
+			"cluster_slots_ok",
+			"cluster_slots_pfail",
+			"cluster_slots_fail",
+			"cluster_known_nodes",
+			"cluster_size",
+			"cluster_current_epoch",
+			"cluster_my_epoch":
+			collectNumericValue(mx, field, value)
+		}
+	}
+}
+
+type clusterNode struct {
+	id        string
+	address   string
+	master    bool
+	connected bool
+}
+
+func (r *Redis) collectClusterNodes(mx map[string]int64, nodes string) {
+	seen := make(map[string]bool)
+
+	for _, node := range parseClusterNodes(nodes) {
+		if len(seen) >= maxClusterNodes {
+			r.Debugf("the number of cluster nodes exceeds the limit (%d), skipping the rest", maxClusterNodes)
+			break
+		}
+		seen[node.id] = true
+
+		if !r.collectedClusterNodes[node.id] {
+			r.collectedClusterNodes[node.id] = true
+			r.addClusterNodeCharts(node)
+		}
+
+		px := "cluster_node_" + node.id + "_"
+		mx[px+"link_state_connected"] = boolToInt(node.connected)
+		mx[px+"link_state_disconnected"] = boolToInt(!node.connected)
+		mx[px+"role_master"] = boolToInt(node.master)
+		mx[px+"role_replica"] = boolToInt(!node.master)
+	}
+
+	for id := range r.collectedClusterNodes {
+		if !seen[id] {
+			delete(r.collectedClusterNodes, id)
+			r.removeClusterNodeCharts(id)
+		}
+	}
+}
+
+// parseClusterNodes parses the CLUSTER NODES output, the line format is:
+// <id> <ip:port@cport[,hostname]> <flags> <master> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot> ...
+func parseClusterNodes(nodes string) []clusterNode {
+	var res []clusterNode
+
+	for sc := bufio.NewScanner(strings.NewReader(nodes)); sc.Scan(); {
+		parts := strings.Fields(sc.Text())
+		if len(parts) < 8 {
+			continue
+		}
+
+		node := clusterNode{
+			id:        parts[0],
+			address:   parts[1],
+			connected: parts[7] == "connected",
+		}
+		if i := strings.IndexAny(node.address, "@,"); i != -1 {
+			node.address = node.address[:i]
+		}
+
+		var skip bool
+		for _, flag := range strings.Split(parts[2], ",") {
+			switch flag {
+			case "master":
+				node.master = true
+			case "handshake", "noaddr":
+				// the node is not a part of the cluster yet, or its address is unknown
+				skip = true
+			}
+		}
+		if skip {
+			continue
+		}
+
+		res = append(res, node)
+	}
+
+	return res
+}
@@ -21,6 +21,7 @@ const (
 	infoSectionCPU          = "# CPU"
 	infoSectionRepl         = "# Replication"
 	infoSectionKeyspace     = "# Keyspace"
+	infoSectionLatencystats = "# Latencystats"
 )
 
 var infoSections = map[string]struct{}{
@@ -222,6 +223,18 @@ func (r *Redis) addDimToChart(chartID string, dim *module.Dim) {
 	chart.MarkNotCreated()
 }
 
+func (r *Redis) removeDimFromChart(chartID, dimID string) {
+	chart := r.Charts().Get(chartID)
+	if chart == nil {
+		return
+	}
+	if err := chart.MarkDimRemove(dimID, true); err != nil {
+		r.Warning(err)
+		return
+	}
+	chart.MarkNotCreated()
+}
+
 func (r *Redis) addAOFCharts() {
 	err := r.Charts().Add(chartPersistenceAOFSize.Copy())
 	if err != nil {
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/netdata/go.d.plugin/pkg/stm"
+
+	"github.com/blang/semver/v4"
+)
+
+// latencyCommandGracePeriod is how long a command that is out of the top keeps its dimensions.
+const latencyCommandGracePeriod = time.Minute * 5
+
+// 'INFO latencystats' is available since Redis 7.0
+var redisVersionLatencyStats = semver.Version{Major: 7}
+
+// The latency values are stored in milliseconds multiplied by precision.
+type (
+	latencyMetrics struct {
+		// LATENCY LATEST reports milliseconds
+		Events map[string]int64 `stm:"latency_event,1000,1"`
+		// INFO latencystats reports microseconds
+		Commands map[string]latencyPercentiles `stm:"latency_cmd"`
+	}
+	latencyPercentiles struct {
+		P50  float64 `stm:"p50"`
+		P99  float64 `stm:"p99"`
+		P999 float64 `stm:"p999"`
+	}
+)
+
+func (r *Redis) collectLatency(mx map[string]int64, info string) {
+	lm := latencyMetrics{
+		Events:   make(map[string]int64),
+		Commands: make(map[string]latencyPercentiles),
+	}
+
+	if r.doLatencyLatest {
+		if err := r.collectLatencyLatest(lm.Events); err != nil {
+			if isCommandDisabledError(err) {
+				r.Warningf("LATENCY command is not allowed (%v), latency events are not collected", err)
+				r.doLatencyLatest = false
+			} else {
+				r.Warningf("error on LATENCY LATEST: %v", err)
+			}
+		}
+	}
+
+	if r.version != nil && r.version.GTE(redisVersionLatencyStats) {
+		r.collectLatencyStats(lm.Commands, mx, info)
+	}
+
+	for k, v := range stm.ToMap(lm) {
+		mx[k] = v
+	}
+}
+
+func (r *Redis) collectLatencyLatest(events map[string]int64) error {
+	// https://redis.io/commands/latency-latest/
+	resp, err := r.rdb.Do(context.Background(), "LATENCY", "LATEST").Slice()
+	if err != nil {
+		return err
+	}
+
+	r.addLatencyEventsChartOnce.Do(r.addLatencyEventsChart)
+
+	for _, v := range resp {
+		// event name, unix timestamp of the latest spike, latest and all-time max latency in milliseconds
+		entry, ok := v.([]interface{})
+		if !ok || len(entry) < 4 {
+			continue
+		}
+		name, ok1 := entry[0].(string)
+		latest, ok2 := entry[2].(int64)
+		if !ok1 || !ok2 || name == "" {
+			continue
+		}
+
+		if !r.collectedLatencyEvents[name] {
+			r.collectedLatencyEvents[name] = true
+			r.addLatencyEventDim(name)
+		}
+		events[name] = latest
+	}
+
+	return nil
+}
+
+func (r *Redis) collectLatencyStats(commands map[string]latencyPercentiles, mx map[string]int64, info string) {
+	stats := parseLatencyStats(info)
+	if len(stats) == 0 {
+		return
+	}
+
+	r.addLatencyCommandsChartsOnce.Do(r.addLatencyCommandsCharts)
+
+	cmds := make([]string, 0, len(stats))
+	for cmd := range stats {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		ci, cj := mx["cmd_"+cmds[i]+"_calls"], mx["cmd_"+cmds[j]+"_calls"]
+		if ci == cj {
+			return cmds[i] < cmds[j]
+		}
+		return ci > cj
+	})
+	if len(cmds) > r.MaxLatencyCommands {
+		cmds = cmds[:r.MaxLatencyCommands]
+	}
+
+	now := time.Now()
+	for _, cmd := range cmds {
+		if _, ok := r.collectedLatencyCommands[cmd]; !ok {
+			r.addLatencyCommandDims(cmd)
+		}
+		r.collectedLatencyCommands[cmd] = now
+		commands[cmd] = stats[cmd]
+	}
+
+	for cmd, lastSeen := range r.collectedLatencyCommands {
+		if now.Sub(lastSeen) > latencyCommandGracePeriod {
+			delete(r.collectedLatencyCommands, cmd)
+			r.Debugf("command '%s' is out of the latency stats top %d: removing dimensions", cmd, r.MaxLatencyCommands)
+			r.removeLatencyCommandDims(cmd)
+		}
+	}
+}
+
+// parseLatencyStats parses the 'Latencystats' section of the INFO output, the property format is:
+// latency_percentiles_usec_<command>:p50=<usec>,p99=<usec>,p99.9=<usec>
+func parseLatencyStats(info string) map[string]latencyPercentiles {
+	stats := make(map[string]latencyPercentiles)
+
+	var inSection bool
+	for sc := bufio.NewScanner(strings.NewReader(info)); sc.Scan(); {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "#") {
+			inSection = line == infoSectionLatencystats
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		field, value, ok := parseProperty(line)
+		if !ok || !strings.HasPrefix(field, "latency_percentiles_usec_") {
+			continue
+		}
+		cmd := strings.TrimPrefix(field, "latency_percentiles_usec_")
+
+		var p latencyPercentiles
+		for _, kv := range strings.Split(value, ",") {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				continue
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			switch k {
+			case "p50":
+				p.P50 = f
+			case "p99":
+				p.P99 = f
+			case "p99.9":
+				p.P999 = f
+			}
+		}
+		stats[cmd] = p
+	}
+
+	return stats
+}
+
+// isCommandDisabledError reports whether the command is denied by ACL or is not available (renamed, old server).
+func isCommandDisabledError(err error) bool {
+	s := err.Error()
+	return strings.HasPrefix(s, "NOPERM") || strings.HasPrefix(s, "ERR unknown command")
+}
@@ -15,6 +15,9 @@ func (r *Redis) validateConfig() error {
 	if r.Address == "" {
 		return errors.New("'address' not set")
 	}
+	if r.CollectLatency && r.MaxLatencyCommands <= 0 {
+		return errors.New("'max_latency_commands' must be positive when 'collect_latency' is enabled")
+	}
 	return nil
 }
 
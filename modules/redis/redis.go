@@ -30,26 +30,36 @@ func init() {
 func New() *Redis {
 	return &Redis{
 		Config: Config{
-			Address:     "redis://@localhost:6379",
-			Timeout:     web.Duration{Duration: time.Second},
-			PingSamples: 5,
+			Address:            "redis://@localhost:6379",
+			Timeout:            web.Duration{Duration: time.Second},
+			PingSamples:        5,
+			MaxLatencyCommands: 10,
 		},
 
-		addAOFChartsOnce:       &sync.Once{},
-		addReplSlaveChartsOnce: &sync.Once{},
-		pingSummary:            metrics.NewSummary(),
-		collectedCommands:      make(map[string]bool),
-		collectedDbs:           make(map[string]bool),
+		addAOFChartsOnce:             &sync.Once{},
+		addReplSlaveChartsOnce:       &sync.Once{},
+		addClusterChartsOnce:         &sync.Once{},
+		addLatencyEventsChartOnce:    &sync.Once{},
+		addLatencyCommandsChartsOnce: &sync.Once{},
+		doLatencyLatest:              true,
+		pingSummary:                  metrics.NewSummary(),
+		collectedCommands:            make(map[string]bool),
+		collectedDbs:                 make(map[string]bool),
+		collectedClusterNodes:        make(map[string]bool),
+		collectedLatencyEvents:       make(map[string]bool),
+		collectedLatencyCommands:     make(map[string]time.Time),
 	}
 }
 
 type Config struct {
-	Address          string       `yaml:"address"`
-	Password         string       `yaml:"password"`
-	Username         string       `yaml:"username"`
-	Timeout          web.Duration `yaml:"timeout"`
-	PingSamples      int          `yaml:"ping_samples"`
-	tlscfg.TLSConfig `yaml:",inline"`
+	Address            string       `yaml:"address"`
+	Password           string       `yaml:"password"`
+	Username           string       `yaml:"username"`
+	Timeout            web.Duration `yaml:"timeout"`
+	PingSamples        int          `yaml:"ping_samples"`
+	CollectLatency     bool         `yaml:"collect_latency"`
+	MaxLatencyCommands int          `yaml:"max_latency_commands"`
+	tlscfg.TLSConfig   `yaml:",inline"`
 }
 
 type (
@@ -66,15 +76,26 @@ type (
 
 		addAOFChartsOnce       *sync.Once
 		addReplSlaveChartsOnce *sync.Once
+		addClusterChartsOnce   *sync.Once
+
+		addLatencyEventsChartOnce    *sync.Once
+		addLatencyCommandsChartsOnce *sync.Once
+		doLatencyLatest              bool
 
 		pingSummary metrics.Summary
 
-		collectedCommands map[string]bool
-		collectedDbs      map[string]bool
+		collectedCommands        map[string]bool
+		collectedDbs             map[string]bool
+		collectedClusterNodes    map[string]bool
+		collectedLatencyEvents   map[string]bool
+		collectedLatencyCommands map[string]time.Time
 	}
 	redisClient interface {
 		Info(ctx context.Context, section ...string) *redis.StringCmd
 		Ping(context.Context) *redis.StatusCmd
+		ClusterInfo(context.Context) *redis.StringCmd
+		ClusterNodes(context.Context) *redis.StringCmd
+		Do(ctx context.Context, args ...interface{}) *redis.Cmd
 		Close() error
 	}
 )
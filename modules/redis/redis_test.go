@@ -8,7 +8,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 
 	"github.com/go-redis/redis/v8"
@@ -19,12 +21,28 @@ import (
 var (
 	pikaInfoAll, _ = os.ReadFile("testdata/pika/info_all.txt")
 	v609InfoAll, _ = os.ReadFile("testdata/v6.0.9/info_all.txt")
+
+	v7011InfoAll, _ = os.ReadFile("testdata/v7.0.11/info_all.txt")
+
+	v7011ClusterInfoAll, _              = os.ReadFile("testdata/v7.0.11-cluster/info_all.txt")
+	v7011ClusterClusterInfo, _          = os.ReadFile("testdata/v7.0.11-cluster/cluster_info.txt")
+	v7011ClusterClusterNodes, _         = os.ReadFile("testdata/v7.0.11-cluster/cluster_nodes.txt")
+	v7011ClusterClusterInfoFailover, _  = os.ReadFile("testdata/v7.0.11-cluster/cluster_info-failover.txt")
+	v7011ClusterClusterNodesFailover, _ = os.ReadFile("testdata/v7.0.11-cluster/cluster_nodes-failover.txt")
 )
 
 func Test_Testdata(t *testing.T) {
 	for name, data := range map[string][]byte{
 		"pikaInfoAll": pikaInfoAll,
 		"v609InfoAll": v609InfoAll,
+
+		"v7011InfoAll": v7011InfoAll,
+
+		"v7011ClusterInfoAll":              v7011ClusterInfoAll,
+		"v7011ClusterClusterInfo":          v7011ClusterClusterInfo,
+		"v7011ClusterClusterNodes":         v7011ClusterClusterNodes,
+		"v7011ClusterClusterInfoFailover":  v7011ClusterClusterInfoFailover,
+		"v7011ClusterClusterNodesFailover": v7011ClusterClusterNodesFailover,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -50,6 +68,14 @@ func TestRedis_Init(t *testing.T) {
 			wantFail: true,
 			config:   Config{Address: "127.0.0.1:6379"},
 		},
+		"fails on non-positive 'max_latency_commands' with 'collect_latency'": {
+			wantFail: true,
+			config: Config{
+				Address:            "redis://127.0.0.1:6379",
+				CollectLatency:     true,
+				MaxLatencyCommands: 0,
+			},
+		},
 		"fails on invalid TLSCA": {
 			wantFail: true,
 			config: Config{
@@ -89,6 +115,10 @@ func TestRedis_Check(t *testing.T) {
 			wantFail: true,
 			prepare:  prepareRedisWithPikaMetrics,
 		},
+		"fails on MOVED redirect": {
+			wantFail: true,
+			prepare:  prepareRedisMovedOnInfo,
+		},
 	}
 
 	for name, test := range tests {
@@ -306,6 +336,212 @@ func TestRedis_Collect(t *testing.T) {
 	}
 }
 
+func TestRedis_Collect_Cluster(t *testing.T) {
+	rdb := New()
+	require.True(t, rdb.Init())
+	m := &mockRedisClient{
+		result:       v7011ClusterInfoAll,
+		clusterInfo:  v7011ClusterClusterInfo,
+		clusterNodes: v7011ClusterClusterNodes,
+	}
+	rdb.rdb = m
+
+	mx := rdb.Collect()
+	require.NotNil(t, mx)
+
+	expected := map[string]int64{
+		"cluster_state_ok":       1,
+		"cluster_state_fail":     0,
+		"cluster_slots_assigned": 16384,
+		"cluster_slots_ok":       16384,
+		"cluster_slots_pfail":    0,
+		"cluster_slots_fail":     0,
+		"cluster_known_nodes":    6,
+		"cluster_size":           3,
+		"cluster_current_epoch":  6,
+		"cluster_my_epoch":       1,
+	}
+	for k, v := range expected {
+		assert.Equalf(t, v, mx[k], "metric '%s'", k)
+	}
+
+	// masters: e7d1, 67ed, 292f; replicas: 07c3, 6ec2, 824f
+	assert.Len(t, rdb.collectedClusterNodes, 6)
+	assert.Equal(t, int64(1), mx["cluster_node_67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1_role_master"])
+	assert.Equal(t, int64(1), mx["cluster_node_824fe116063bc5fcf9f4ffd895bc17aee7731ac3_role_replica"])
+	assert.Equal(t, int64(1), mx["cluster_node_67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1_link_state_connected"])
+
+	chart := rdb.Charts().Get("cluster_node_67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1_link_state")
+	require.NotNil(t, chart)
+	assert.Contains(t, chart.Labels, module.Label{Key: "node_address", Value: "172.18.0.3:6379"})
+
+	// the cluster node has no keys, 'INFO keyspace' is empty
+	assert.Equal(t, int64(0), mx["db0_keys"])
+	assert.Equal(t, int64(0), mx["db0_expires_keys"])
+	ensureCollectedHasAllChartsDimsVarsIDs(t, rdb, mx)
+	ensureCollectedDbsAddedToCharts(t, rdb)
+
+	// a master fails, its replica is promoted, another replica is removed
+	m.clusterInfo = v7011ClusterClusterInfoFailover
+	m.clusterNodes = v7011ClusterClusterNodesFailover
+
+	mx = rdb.Collect()
+	require.NotNil(t, mx)
+
+	assert.Equal(t, int64(5461), mx["cluster_slots_pfail"])
+	assert.Equal(t, int64(7), mx["cluster_current_epoch"])
+	// the node in the handshake state is skipped
+	assert.Len(t, rdb.collectedClusterNodes, 5)
+	assert.Equal(t, int64(1), mx["cluster_node_67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1_link_state_disconnected"])
+	assert.Equal(t, int64(1), mx["cluster_node_824fe116063bc5fcf9f4ffd895bc17aee7731ac3_role_master"])
+
+	for _, chart := range *rdb.Charts() {
+		if strings.HasPrefix(chart.ID, "cluster_node_6ec23923021cf3ffec47632106199cb7f496ce01_") {
+			assert.Truef(t, chart.Obsolete, "chart '%s' of the removed node is not obsolete", chart.ID)
+		}
+	}
+	ensureCollectedHasAllChartsDimsVarsIDs(t, rdb, mx)
+}
+
+func TestRedis_Collect_NotCluster(t *testing.T) {
+	rdb := prepareRedisV609(t)
+
+	require.NotNil(t, rdb.Collect())
+
+	assert.False(t, rdb.rdb.(*mockRedisClient).calledCluster)
+	assert.False(t, rdb.Charts().Has(chartClusterState.ID))
+}
+
+func TestRedis_Collect_Latency(t *testing.T) {
+	rdb := New()
+	rdb.CollectLatency = true
+	rdb.MaxLatencyCommands = 3
+	require.True(t, rdb.Init())
+	rdb.rdb = &mockRedisClient{
+		result: v7011InfoAll,
+		latencyLatest: []interface{}{
+			[]interface{}{"command", int64(1690000000), int64(250), int64(1000)},
+			[]interface{}{"fast-command", int64(1690000010), int64(2), int64(5)},
+		},
+	}
+
+	mx := rdb.Collect()
+	require.NotNil(t, mx)
+
+	expected := map[string]int64{
+		"latency_event_command":      250000,
+		"latency_event_fast-command": 2000,
+		// the top commands by calls: info, ping, config|get
+		"latency_cmd_info_p50":        270,
+		"latency_cmd_info_p99":        485,
+		"latency_cmd_info_p999":       1011,
+		"latency_cmd_ping_p50":        14,
+		"latency_cmd_ping_p99":        24,
+		"latency_cmd_ping_p999":       24,
+		"latency_cmd_config|get_p50":  27,
+		"latency_cmd_config|get_p99":  31,
+		"latency_cmd_config|get_p999": 31,
+	}
+	for k, v := range expected {
+		assert.Equalf(t, v, mx[k], "metric '%s'", k)
+	}
+	assert.NotContains(t, mx, "latency_cmd_set_p50")
+
+	events := rdb.Charts().Get(chartLatencyEventsLatest.ID)
+	require.NotNil(t, events)
+	assert.Len(t, events.Dims, 2)
+	for _, id := range []string{chartCommandLatencyP50.ID, chartCommandLatencyP99.ID, chartCommandLatencyP999.ID} {
+		chart := rdb.Charts().Get(id)
+		require.NotNilf(t, chart, "'%s' chart is not in charts", id)
+		assert.Lenf(t, chart.Dims, 3, "'%s' chart unexpected number of dimensions", id)
+	}
+	ensureCollectedHasAllChartsDimsVarsIDs(t, rdb, mx)
+
+	// 'config|get' is out of the top, its dimensions are kept during the grace period
+	rdb.MaxLatencyCommands = 2
+	require.NotNil(t, rdb.Collect())
+
+	chart := rdb.Charts().Get(chartCommandLatencyP50.ID)
+	require.NotNil(t, chart)
+	assert.False(t, chart.GetDim("latency_cmd_config|get_p50").Obsolete)
+
+	rdb.collectedLatencyCommands["config|get"] = time.Now().Add(-latencyCommandGracePeriod * 2)
+	require.NotNil(t, rdb.Collect())
+
+	assert.NotContains(t, rdb.collectedLatencyCommands, "config|get")
+	assert.True(t, chart.GetDim("latency_cmd_config|get_p50").Obsolete)
+	assert.False(t, chart.GetDim("latency_cmd_ping_p50").Obsolete)
+}
+
+func TestRedis_Collect_LatencyNotAllowed(t *testing.T) {
+	tests := map[string]struct {
+		err           error
+		wantDisabled  bool
+		wantCallCount int
+	}{
+		"disabled by ACL": {
+			err:           errors.New("NOPERM this user has no permissions to run the 'latency|latest' command"),
+			wantDisabled:  true,
+			wantCallCount: 1,
+		},
+		"renamed command": {
+			err:           errors.New("ERR unknown command 'LATENCY', with args beginning with: 'LATEST'"),
+			wantDisabled:  true,
+			wantCallCount: 1,
+		},
+		"other error": {
+			err:           errors.New("i/o timeout"),
+			wantCallCount: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rdb := New()
+			rdb.CollectLatency = true
+			require.True(t, rdb.Init())
+			m := &mockRedisClient{result: v7011InfoAll, errLatency: test.err}
+			rdb.rdb = m
+
+			require.NotNil(t, rdb.Collect())
+			require.NotNil(t, rdb.Collect())
+
+			assert.Equal(t, test.wantCallCount, m.calledLatency)
+			assert.Equal(t, test.wantDisabled, !rdb.doLatencyLatest)
+			assert.False(t, rdb.Charts().Has(chartLatencyEventsLatest.ID))
+			// the percentiles don't depend on the LATENCY command
+			assert.True(t, rdb.Charts().Has(chartCommandLatencyP50.ID))
+		})
+	}
+}
+
+func TestRedis_Collect_LatencyNotEnabled(t *testing.T) {
+	rdb := New()
+	require.True(t, rdb.Init())
+	m := &mockRedisClient{result: v7011InfoAll}
+	rdb.rdb = m
+
+	mx := rdb.Collect()
+	require.NotNil(t, mx)
+
+	assert.Zero(t, m.calledLatency)
+	assert.NotContains(t, mx, "latency_cmd_info_p50")
+	assert.False(t, rdb.Charts().Has(chartCommandLatencyP50.ID))
+}
+
+func TestRedis_Collect_LatencyBeforeV7(t *testing.T) {
+	rdb := New()
+	rdb.CollectLatency = true
+	require.True(t, rdb.Init())
+	rdb.rdb = &mockRedisClient{result: v609InfoAll}
+
+	mx := rdb.Collect()
+	require.NotNil(t, mx)
+
+	assert.True(t, rdb.Charts().Has(chartLatencyEventsLatest.ID))
+	assert.False(t, rdb.Charts().Has(chartCommandLatencyP50.ID))
+}
+
 func prepareRedisV609(t *testing.T) *Redis {
 	rdb := New()
 	require.True(t, rdb.Init())
@@ -324,6 +560,15 @@ func prepareRedisErrorOnInfo(t *testing.T) *Redis {
 	return rdb
 }
 
+func prepareRedisMovedOnInfo(t *testing.T) *Redis {
+	rdb := New()
+	require.True(t, rdb.Init())
+	rdb.rdb = &mockRedisClient{
+		errInfo: errors.New("MOVED 3999 127.0.0.1:6381"),
+	}
+	return rdb
+}
+
 func prepareRedisWithPikaMetrics(t *testing.T) *Redis {
 	rdb := New()
 	require.True(t, rdb.Init())
@@ -388,15 +633,25 @@ func copyTimeRelatedMetrics(dst, src map[string]int64) {
 }
 
 type mockRedisClient struct {
-	errOnInfo   bool
-	result      []byte
-	calledClose bool
+	errOnInfo     bool
+	errInfo       error
+	result        []byte
+	clusterInfo   []byte
+	clusterNodes  []byte
+	latencyLatest []interface{}
+	errLatency    error
+	calledClose   bool
+	calledCluster bool
+	calledLatency int
 }
 
 func (m *mockRedisClient) Info(_ context.Context, _ ...string) (cmd *redis.StringCmd) {
-	if m.errOnInfo {
+	switch {
+	case m.errInfo != nil:
+		cmd = redis.NewStringResult("", m.errInfo)
+	case m.errOnInfo:
 		cmd = redis.NewStringResult("", errors.New("error on Info"))
-	} else {
+	default:
 		cmd = redis.NewStringResult(string(m.result), nil)
 	}
 	return cmd
@@ -406,6 +661,30 @@ func (m *mockRedisClient) Ping(_ context.Context) (cmd *redis.StatusCmd) {
 	return redis.NewStatusResult("PONG", nil)
 }
 
+func (m *mockRedisClient) ClusterInfo(_ context.Context) *redis.StringCmd {
+	m.calledCluster = true
+	if m.clusterInfo == nil {
+		return redis.NewStringResult("", errors.New("ERR This instance has cluster support disabled"))
+	}
+	return redis.NewStringResult(string(m.clusterInfo), nil)
+}
+
+func (m *mockRedisClient) ClusterNodes(_ context.Context) *redis.StringCmd {
+	m.calledCluster = true
+	if m.clusterNodes == nil {
+		return redis.NewStringResult("", errors.New("ERR This instance has cluster support disabled"))
+	}
+	return redis.NewStringResult(string(m.clusterNodes), nil)
+}
+
+func (m *mockRedisClient) Do(_ context.Context, _ ...interface{}) *redis.Cmd {
+	m.calledLatency++
+	if m.errLatency != nil {
+		return redis.NewCmdResult(nil, m.errLatency)
+	}
+	return redis.NewCmdResult(m.latencyLatest, nil)
+}
+
 func (m *mockRedisClient) Close() error {
 	m.calledClose = true
 	return nil
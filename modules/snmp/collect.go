@@ -3,6 +3,9 @@
 package snmp
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/gosnmp/gosnmp"
 )
 
@@ -13,6 +16,10 @@ func (s *SNMP) collect() (map[string]int64, error) {
 		return nil, err
 	}
 
+	if err := s.collectTables(collected); err != nil {
+		return nil, err
+	}
+
 	return collected, nil
 }
 
@@ -24,7 +31,7 @@ func (s *SNMP) collectOIDs(collected map[string]int64) error {
 
 		oids := s.oids[i:end]
 		resp, err := s.snmpClient.Get(oids)
-		if err != nil {
+		if err = checkResponse(resp, err); err != nil {
 			s.Errorf("cannot get SNMP data: %v", err)
 			return err
 		}
@@ -34,22 +41,67 @@ func (s *SNMP) collectOIDs(collected map[string]int64) error {
 				continue
 			}
 
-			switch v := resp.Variables[i]; v.Type {
-			case gosnmp.Boolean,
-				gosnmp.Counter32,
-				gosnmp.Counter64,
-				gosnmp.Gauge32,
-				gosnmp.TimeTicks,
-				gosnmp.Uinteger32,
-				gosnmp.OpaqueFloat,
-				gosnmp.OpaqueDouble,
-				gosnmp.Integer:
-				collected[oid] = gosnmp.ToBigInt(v.Value).Int64()
-			default:
-				s.Debugf("skipping OID '%s' (unsupported type '%s')", oid, v.Type)
+			if v, ok := pduInt64(resp.Variables[i]); ok {
+				collected[oid] = v + s.offsets[oid]
+			} else {
+				s.Debugf("skipping OID '%s' (unsupported type '%s')", oid, resp.Variables[i].Type)
 			}
 		}
 	}
 
 	return nil
 }
+
+// usmStats counters, an SNMPv3 agent responds with a Report PDU with one of them when it rejects the request.
+// https://datatracker.ietf.org/doc/html/rfc3414#section-5
+var usmStatsErrors = map[string]string{
+	".1.3.6.1.6.3.15.1.1.1.0": "unsupported security level (usmStatsUnsupportedSecLevels), check 'user.level'",
+	".1.3.6.1.6.3.15.1.1.2.0": "message not in time window (usmStatsNotInTimeWindows)",
+	".1.3.6.1.6.3.15.1.1.3.0": "unknown user name (usmStatsUnknownUserNames), check 'user.name'",
+	".1.3.6.1.6.3.15.1.1.4.0": "unknown engine ID (usmStatsUnknownEngineIDs)",
+	".1.3.6.1.6.3.15.1.1.5.0": "wrong digest (usmStatsWrongDigests), check 'user.auth_proto' and 'user.auth_key'",
+	".1.3.6.1.6.3.15.1.1.6.0": "decryption error (usmStatsDecryptionErrors), check 'user.priv_proto' and 'user.priv_key'",
+}
+
+// checkResponse makes the SNMPv3 authentication and privacy failures distinguishable from the network errors:
+// gosnmp returns them as plain errors (e.g. "wrong digest") or, depending on the request, as a Report PDU.
+func checkResponse(resp *gosnmp.SnmpPacket, err error) error {
+	switch {
+	case errors.Is(err, gosnmp.ErrWrongDigest):
+		return fmt.Errorf("SNMPv3 authentication failed: %s", usmStatsErrors[".1.3.6.1.6.3.15.1.1.5.0"])
+	case errors.Is(err, gosnmp.ErrDecryption):
+		return fmt.Errorf("SNMPv3 authentication failed: %s", usmStatsErrors[".1.3.6.1.6.3.15.1.1.6.0"])
+	case errors.Is(err, gosnmp.ErrUnknownUsername):
+		return fmt.Errorf("SNMPv3 authentication failed: %s", usmStatsErrors[".1.3.6.1.6.3.15.1.1.3.0"])
+	case errors.Is(err, gosnmp.ErrUnknownSecurityLevel):
+		return fmt.Errorf("SNMPv3 authentication failed: %s", usmStatsErrors[".1.3.6.1.6.3.15.1.1.1.0"])
+	case err != nil:
+		return err
+	case resp == nil:
+		return errors.New("empty response")
+	case resp.PDUType == gosnmp.Report && len(resp.Variables) > 0:
+		name := resp.Variables[0].Name
+		if msg, ok := usmStatsErrors[name]; ok {
+			return fmt.Errorf("SNMPv3 authentication failed: %s", msg)
+		}
+		return fmt.Errorf("agent responded with a report (%s)", name)
+	}
+	return nil
+}
+
+func pduInt64(pdu gosnmp.SnmpPDU) (int64, bool) {
+	switch pdu.Type {
+	case gosnmp.Boolean,
+		gosnmp.Counter32,
+		gosnmp.Counter64,
+		gosnmp.Gauge32,
+		gosnmp.TimeTicks,
+		gosnmp.Uinteger32,
+		gosnmp.OpaqueFloat,
+		gosnmp.OpaqueDouble,
+		gosnmp.Integer:
+		return gosnmp.ToBigInt(pdu.Value).Int64(), true
+	default:
+		return 0, false
+	}
+}
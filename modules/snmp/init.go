@@ -5,6 +5,7 @@ package snmp
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gosnmp/gosnmp"
@@ -13,21 +14,38 @@ import (
 var newSNMPClient = gosnmp.NewHandler
 
 func (s SNMP) validateConfig() error {
-	if len(s.ChartsInput) == 0 {
-		return errors.New("'charts' are required but not set")
+	if len(s.ChartsInput) == 0 && len(s.Tables) == 0 {
+		return errors.New("'charts' or 'tables' are required but not set")
+	}
+	for i, t := range s.Tables {
+		if t.ID == "" {
+			return fmt.Errorf("'tables[%d].id' is required but not set", i)
+		}
+		if t.OID == "" {
+			return fmt.Errorf("table '%s': 'oid' is required but not set", t.ID)
+		}
+		if len(t.Charts) == 0 {
+			return fmt.Errorf("table '%s': 'charts' are required but not set", t.ID)
+		}
 	}
 
 	if s.Options.Version == gosnmp.Version3.String() {
 		if s.User.Name == "" {
 			return errors.New("'user.name' is required when using SNMPv3 but not set")
 		}
-		if _, err := parseSNMPv3SecurityLevel(s.User.SecurityLevel); err != nil {
+		level, err := parseSNMPv3SecurityLevel(s.User.SecurityLevel)
+		if err != nil {
 			return err
 		}
-		if _, err := parseSNMPv3AuthProtocol(s.User.AuthProto); err != nil {
+		auth, err := parseSNMPv3AuthProtocol(s.User.AuthProto)
+		if err != nil {
 			return err
 		}
-		if _, err := parseSNMPv3PrivProtocol(s.User.PrivProto); err != nil {
+		priv, err := parseSNMPv3PrivProtocol(s.User.PrivProto)
+		if err != nil {
+			return err
+		}
+		if err := validateSNMPv3Security(level, auth, priv, s.User.AuthKey, s.User.PrivKey); err != nil {
 			return err
 		}
 	}
@@ -35,6 +53,33 @@ func (s SNMP) validateConfig() error {
 	return nil
 }
 
+// validateSNMPv3Security checks the combinations the agent would reject with a usmStats report
+// (or silently ignore) at the first request.
+func validateSNMPv3Security(level gosnmp.SnmpV3MsgFlags, auth gosnmp.SnmpV3AuthProtocol, priv gosnmp.SnmpV3PrivProtocol, authKey, privKey string) error {
+	// RFC 3414 (11.2): the pass phrases must be at least 8 characters long
+	const minKeyLen = 8
+
+	if level == gosnmp.NoAuthNoPriv {
+		return nil
+	}
+	if auth == gosnmp.NoAuth {
+		return errors.New("'user.auth_proto' is required when 'user.level' is 'authNoPriv' or 'authPriv' but not set")
+	}
+	if len(authKey) < minKeyLen {
+		return fmt.Errorf("'user.auth_key' must be at least %d characters long", minKeyLen)
+	}
+	if level != gosnmp.AuthPriv {
+		return nil
+	}
+	if priv == gosnmp.NoPriv {
+		return errors.New("'user.priv_proto' is required when 'user.level' is 'authPriv' but not set")
+	}
+	if len(privKey) < minKeyLen {
+		return fmt.Errorf("'user.priv_key' must be at least %d characters long", minKeyLen)
+	}
+	return nil
+}
+
 func (s SNMP) initSNMPClient() (gosnmp.Handler, error) {
 	client := newSNMPClient()
 
@@ -81,6 +126,7 @@ func (s SNMP) initSNMPClient() (gosnmp.Handler, error) {
 	case gosnmp.Version3:
 		client.SetVersion(gosnmp.Version3)
 		client.SetSecurityModel(gosnmp.UserSecurityModel)
+		client.SetContextName(s.User.ContextName)
 		client.SetMsgFlags(safeParseSNMPv3SecurityLevel(s.User.SecurityLevel))
 		client.SetSecurityParameters(&gosnmp.UsmSecurityParameters{
 			UserName:                 s.User.Name,
@@ -105,6 +151,71 @@ func (s SNMP) initOIDs() (oids []string) {
 	return oids
 }
 
+// initOffsets returns the 'offset' of the configured OIDs, 'multiply_range' OIDs included.
+func (s SNMP) initOffsets() map[string]int64 {
+	offsets := make(map[string]int64)
+	for _, cfg := range s.ChartsInput {
+		for _, dim := range cfg.Dimensions {
+			if dim.Offset == 0 {
+				continue
+			}
+			oid := strings.TrimPrefix(dim.OID, ".")
+			if len(cfg.IndexRange) != 2 {
+				offsets[oid] = dim.Offset
+				continue
+			}
+			for i := cfg.IndexRange[0]; i <= cfg.IndexRange[1]; i++ {
+				offsets[fmt.Sprintf("%s.%d", oid, i)] = dim.Offset
+			}
+		}
+	}
+	return offsets
+}
+
+func (s SNMP) initTables() ([]*table, error) {
+	var tables []*table
+	for _, cfg := range s.Tables {
+		t := &table{
+			TableConfig: cfg,
+			oid:         strings.Trim(cfg.OID, "."),
+			rows:        make(map[string]*tableRow),
+		}
+		if t.LabelName == "" {
+			t.LabelName = "index"
+		}
+		if !cfg.Selector.Empty() {
+			m, err := cfg.Selector.Parse()
+			if err != nil {
+				return nil, fmt.Errorf("table '%s' selector: %v", cfg.ID, err)
+			}
+			t.selector = m
+		}
+
+		seen := make(map[int]bool)
+		if cfg.LabelColumn > 0 {
+			t.labelOID = t.columnOID(cfg.LabelColumn)
+			t.columns = append(t.columns, tableColumn{num: cfg.LabelColumn, oid: t.labelOID})
+			seen[cfg.LabelColumn] = true
+		}
+		for _, chart := range cfg.Charts {
+			if len(chart.Dimensions) == 0 {
+				return nil, fmt.Errorf("table '%s' chart '%s': 'dimensions' are required but not set", cfg.ID, chart.ID)
+			}
+			for _, dim := range chart.Dimensions {
+				if dim.Column <= 0 {
+					return nil, fmt.Errorf("table '%s' chart '%s': invalid dimension column (%d)", cfg.ID, chart.ID, dim.Column)
+				}
+				if !seen[dim.Column] {
+					seen[dim.Column] = true
+					t.columns = append(t.columns, tableColumn{num: dim.Column, oid: t.columnOID(dim.Column)})
+				}
+			}
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
 func parseSNMPVersion(version string) (gosnmp.SnmpVersion, error) {
 	switch version {
 	case "0", "1":
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 
 	"github.com/gosnmp/gosnmp"
 )
@@ -21,6 +22,7 @@ const (
 	defaultRetries     = 1
 	defaultTimeout     = defaultUpdateEvery
 	defaultMaxOIDs     = 60
+	defaultMaxReps     = 25
 )
 
 //go:embed "config_schema.json"
@@ -47,6 +49,7 @@ func New() *SNMP {
 				Timeout: defaultUpdateEvery,
 				Version: defaultVersion.String(),
 				MaxOIDs: defaultMaxOIDs,
+				MaxReps: defaultMaxReps,
 			},
 		},
 	}
@@ -60,6 +63,7 @@ type (
 		User        User          `yaml:"user"`
 		Options     Options       `yaml:"options"`
 		ChartsInput []ChartConfig `yaml:"charts"`
+		Tables      []TableConfig `yaml:"tables"`
 	}
 	User struct {
 		Name          string `yaml:"name"`
@@ -68,6 +72,7 @@ type (
 		AuthKey       string `yaml:"auth_key"`
 		PrivProto     string `yaml:"priv_proto"`
 		PrivKey       string `yaml:"priv_key"`
+		ContextName   string `yaml:"context_name"`
 	}
 	Options struct {
 		Port    int    `yaml:"port"`
@@ -75,6 +80,7 @@ type (
 		Timeout int    `yaml:"timeout"`
 		Version string `yaml:"version"`
 		MaxOIDs int    `yaml:"max_request_size"`
+		MaxReps int    `yaml:"max_repetitions"`
 	}
 	ChartConfig struct {
 		ID         string            `yaml:"id"`
@@ -92,6 +98,31 @@ type (
 		Algorithm  string `yaml:"algorithm"`
 		Multiplier int    `yaml:"multiplier"`
 		Divisor    int    `yaml:"divisor"`
+		Offset     int64  `yaml:"offset"`
+	}
+	TableConfig struct {
+		ID          string             `yaml:"id"`
+		OID         string             `yaml:"oid"`
+		LabelColumn int                `yaml:"label_column"`
+		LabelName   string             `yaml:"label_name"`
+		Selector    matcher.SimpleExpr `yaml:"selector"`
+		Charts      []TableChartConfig `yaml:"charts"`
+	}
+	TableChartConfig struct {
+		ID         string                 `yaml:"id"`
+		Title      string                 `yaml:"title"`
+		Units      string                 `yaml:"units"`
+		Family     string                 `yaml:"family"`
+		Type       string                 `yaml:"type"`
+		Priority   int                    `yaml:"priority"`
+		Dimensions []TableDimensionConfig `yaml:"dimensions"`
+	}
+	TableDimensionConfig struct {
+		Column     int    `yaml:"column"`
+		Name       string `yaml:"name"`
+		Algorithm  string `yaml:"algorithm"`
+		Multiplier int    `yaml:"multiplier"`
+		Divisor    int    `yaml:"divisor"`
 	}
 )
 
@@ -102,6 +133,8 @@ type SNMP struct {
 	charts     *module.Charts
 	snmpClient gosnmp.Handler
 	oids       []string
+	offsets    map[string]int64
+	tables     []*table
 }
 
 func (s *SNMP) Init() bool {
@@ -134,6 +167,14 @@ func (s *SNMP) Init() bool {
 	s.charts = charts
 
 	s.oids = s.initOIDs()
+	s.offsets = s.initOffsets()
+
+	tables, err := s.initTables()
+	if err != nil {
+		s.Errorf("tables initialization: %v", err)
+		return false
+	}
+	s.tables = tables
 
 	return true
 }
@@ -172,6 +213,9 @@ func snmpClientConnInfo(c gosnmp.Handler) string {
 		info.WriteString(fmt.Sprintf(",community=%s", c.Community()))
 	case gosnmp.Version3:
 		info.WriteString(fmt.Sprintf(",security_level=%d,%s", c.MsgFlags(), c.SecurityParameters().Description()))
+		if c.ContextName() != "" {
+			info.WriteString(fmt.Sprintf(",context_name=%s", c.ContextName()))
+		}
 	}
 	return info.String()
 }
@@ -5,6 +5,8 @@ package snmp
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/gosnmp/gosnmp"
 	snmpmock "github.com/gosnmp/gosnmp/mocks"
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -40,6 +43,33 @@ func TestSNMP_Init(t *testing.T) {
 				return snmp
 			},
 		},
+		"fail when table 'oid' not set": {
+			wantFail: true,
+			prepareSNMP: func() *SNMP {
+				snmp := New()
+				snmp.Config = prepareConfigWithTable(prepareV2Config)
+				snmp.Tables[0].OID = ""
+				return snmp
+			},
+		},
+		"fail when table dimension 'column' not set": {
+			wantFail: true,
+			prepareSNMP: func() *SNMP {
+				snmp := New()
+				snmp.Config = prepareConfigWithTable(prepareV2Config)
+				snmp.Tables[0].Charts[0].Dimensions[0].Column = 0
+				return snmp
+			},
+		},
+		"success when only 'tables' set": {
+			wantFail: false,
+			prepareSNMP: func() *SNMP {
+				snmp := New()
+				snmp.Config = prepareConfigWithTable(prepareV2Config)
+				snmp.ChartsInput = nil
+				return snmp
+			},
+		},
 		"fail when using SNMPv3 but 'user.name' not set": {
 			wantFail: true,
 			prepareSNMP: func() *SNMP {
@@ -76,6 +106,55 @@ func TestSNMP_Init(t *testing.T) {
 				return snmp
 			},
 		},
+		"fail when using SNMPv3 'authPriv' but 'user.priv_proto' is none": {
+			wantFail: true,
+			prepareSNMP: func() *SNMP {
+				snmp := New()
+				snmp.Config = prepareV3Config()
+				snmp.User.PrivProto = "none"
+				return snmp
+			},
+		},
+		"fail when using SNMPv3 'authNoPriv' but 'user.auth_proto' is none": {
+			wantFail: true,
+			prepareSNMP: func() *SNMP {
+				snmp := New()
+				snmp.Config = prepareV3Config()
+				snmp.User.SecurityLevel = "authNoPriv"
+				snmp.User.AuthProto = "none"
+				return snmp
+			},
+		},
+		"fail when using SNMPv3 but 'user.auth_key' is too short": {
+			wantFail: true,
+			prepareSNMP: func() *SNMP {
+				snmp := New()
+				snmp.Config = prepareV3Config()
+				snmp.User.AuthKey = "key"
+				return snmp
+			},
+		},
+		"success when using SNMPv3 'authNoPriv' without 'user.priv_proto'": {
+			wantFail: false,
+			prepareSNMP: func() *SNMP {
+				snmp := New()
+				snmp.Config = prepareV3Config()
+				snmp.User.SecurityLevel = "authNoPriv"
+				snmp.User.PrivProto = ""
+				snmp.User.PrivKey = ""
+				return snmp
+			},
+		},
+		"success when using SNMPv3 with AES-256 (Cisco) and 'user.context_name'": {
+			wantFail: false,
+			prepareSNMP: func() *SNMP {
+				snmp := New()
+				snmp.Config = prepareV3Config()
+				snmp.User.PrivProto = "aes256c"
+				snmp.User.ContextName = "vlan-100"
+				return snmp
+			},
+		},
 		"success when using SNMPv1 with valid config": {
 			wantFail: false,
 			prepareSNMP: func() *SNMP {
@@ -182,6 +261,33 @@ func TestSNMP_Check(t *testing.T) {
 				return snmp
 			},
 		},
+		"fail when SNMPv3 authentication fails": {
+			wantFail: true,
+			prepareSNMP: func(m *snmpmock.MockHandler) *SNMP {
+				snmp := New()
+				snmp.Config = prepareV2Config()
+
+				m.EXPECT().Get(gomock.Any()).Return(nil, gosnmp.ErrWrongDigest).Times(1)
+
+				return snmp
+			},
+		},
+		"fail when the agent responds with a usmStats report": {
+			wantFail: true,
+			prepareSNMP: func(m *snmpmock.MockHandler) *SNMP {
+				snmp := New()
+				snmp.Config = prepareV2Config()
+
+				m.EXPECT().Get(gomock.Any()).Return(&gosnmp.SnmpPacket{
+					PDUType: gosnmp.Report,
+					Variables: []gosnmp.SnmpPDU{
+						{Name: ".1.3.6.1.6.3.15.1.1.5.0", Value: 1, Type: gosnmp.Counter32},
+					},
+				}, nil).Times(1)
+
+				return snmp
+			},
+		},
 		"fail when all OIDs type is unsupported": {
 			wantFail: true,
 			prepareSNMP: func(m *snmpmock.MockHandler) *SNMP {
@@ -280,6 +386,30 @@ func TestSNMP_Collect(t *testing.T) {
 				"1.3.6.1.2.1.2.2.1.10.1": 30,
 			},
 		},
+		"success when collecting with 'offset'": {
+			prepareSNMP: func(m *snmpmock.MockHandler) *SNMP {
+				snmp := New()
+				snmp.Config = prepareConfigWithIndexRange(prepareV2Config, 0, 1)
+				snmp.ChartsInput[0].Dimensions[0].Offset = -2732
+
+				m.EXPECT().Get(gomock.Any()).Return(&gosnmp.SnmpPacket{
+					Variables: []gosnmp.SnmpPDU{
+						{Value: 2982, Type: gosnmp.Integer},
+						{Value: 20, Type: gosnmp.Gauge32},
+						{Value: 3032, Type: gosnmp.Integer},
+						{Value: 40, Type: gosnmp.Gauge32},
+					},
+				}, nil).Times(1)
+
+				return snmp
+			},
+			wantCollected: map[string]int64{
+				"1.3.6.1.2.1.2.2.1.10.0": 250,
+				"1.3.6.1.2.1.2.2.1.16.0": 20,
+				"1.3.6.1.2.1.2.2.1.10.1": 300,
+				"1.3.6.1.2.1.2.2.1.16.1": 40,
+			},
+		},
 		"fails when collecting unsupported type": {
 			prepareSNMP: func(m *snmpmock.MockHandler) *SNMP {
 				snmp := New()
@@ -402,6 +532,218 @@ func TestSNMP_Charts(t *testing.T) {
 	}
 }
 
+func TestCheckResponse(t *testing.T) {
+	tests := map[string]struct {
+		resp    *gosnmp.SnmpPacket
+		err     error
+		wantErr string
+	}{
+		"wrong digest error": {
+			err:     gosnmp.ErrWrongDigest,
+			wantErr: "SNMPv3 authentication failed: wrong digest (usmStatsWrongDigests)",
+		},
+		"decryption error": {
+			err:     gosnmp.ErrDecryption,
+			wantErr: "SNMPv3 authentication failed: decryption error (usmStatsDecryptionErrors)",
+		},
+		"unknown user name report": {
+			resp: &gosnmp.SnmpPacket{
+				PDUType:   gosnmp.Report,
+				Variables: []gosnmp.SnmpPDU{{Name: ".1.3.6.1.6.3.15.1.1.3.0", Type: gosnmp.Counter32, Value: 1}},
+			},
+			wantErr: "SNMPv3 authentication failed: unknown user name (usmStatsUnknownUserNames)",
+		},
+		"network error": {
+			err:     errors.New("request timeout (after 1 retries)"),
+			wantErr: "request timeout",
+		},
+		"response": {
+			resp: &gosnmp.SnmpPacket{PDUType: gosnmp.GetResponse},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkResponse(test.resp, test.err)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSNMP_Collect_Tables(t *testing.T) {
+	tests := map[string]struct {
+		prepareConfig func() Config
+		prepareAgent  func(m *snmpmock.MockHandler, agent *mockAgent)
+	}{
+		"SNMPv2 (GETBULK)": {
+			prepareConfig: func() Config { return prepareConfigWithTable(prepareV2Config) },
+			prepareAgent: func(m *snmpmock.MockHandler, agent *mockAgent) {
+				m.EXPECT().GetBulk(gomock.Any(), uint8(0), uint32(2)).DoAndReturn(agent.getBulk).MinTimes(1)
+			},
+		},
+		"SNMPv1 (GETNEXT)": {
+			prepareConfig: func() Config { return prepareConfigWithTable(prepareV1Config) },
+			prepareAgent: func(m *snmpmock.MockHandler, agent *mockAgent) {
+				m.EXPECT().GetNext(gomock.Any()).DoAndReturn(agent.getNext).MinTimes(1)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockSNMP, cleanup := mockInit(t)
+			defer cleanup()
+
+			newSNMPClient = func() gosnmp.Handler { return mockSNMP }
+			defaultMockExpects(mockSNMP)
+
+			agent := &mockAgent{pdus: prepareIfXTable("lo", "eth0", "eth1")}
+			test.prepareAgent(mockSNMP, agent)
+
+			snmp := New()
+			snmp.Config = test.prepareConfig()
+			snmp.ChartsInput = nil
+			require.True(t, snmp.Init())
+
+			expected := map[string]int64{
+				"if_eth0_6":  2060,
+				"if_eth0_10": 2100,
+				"if_eth1_6":  3060,
+				"if_eth1_10": 3100,
+			}
+			assert.Equal(t, expected, snmp.Collect())
+
+			require.Len(t, *snmp.Charts(), 2)
+			chart := snmp.Charts().Get("if_traffic_eth0")
+			require.NotNil(t, chart)
+			assert.Equal(t, "snmp.if_traffic", chart.Ctx)
+			assert.Equal(t, []module.Label{{Key: "interface", Value: "eth0"}}, chart.Labels)
+
+			// eth1 is gone, eth2 is new
+			agent.pdus = prepareIfXTable("lo", "eth0", "", "eth2")
+			mx := snmp.Collect()
+			assert.Contains(t, mx, "if_eth2_6")
+			assert.NotContains(t, mx, "if_eth1_6")
+
+			require.Len(t, *snmp.Charts(), 3)
+			assert.True(t, snmp.Charts().Get("if_traffic_eth1").Obsolete)
+			assert.False(t, snmp.Charts().Get("if_traffic_eth2").Obsolete)
+		})
+	}
+}
+
+func TestSNMP_Collect_TablesCounter32Wrap(t *testing.T) {
+	mockSNMP, cleanup := mockInit(t)
+	defer cleanup()
+
+	newSNMPClient = func() gosnmp.Handler { return mockSNMP }
+	defaultMockExpects(mockSNMP)
+
+	const ifInOctets = "1.3.6.1.2.1.2.2.1.10"
+	agent := &mockAgent{}
+	mockSNMP.EXPECT().GetBulk(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(agent.getBulk).AnyTimes()
+
+	snmp := New()
+	snmp.Config = prepareV2Config()
+	snmp.ChartsInput = nil
+	snmp.Tables = []TableConfig{
+		{
+			ID:  "if",
+			OID: "1.3.6.1.2.1.2.2",
+			Charts: []TableChartConfig{
+				{ID: "traffic", Dimensions: []TableDimensionConfig{{Column: 10, Name: "in", Algorithm: "incremental"}}},
+			},
+		},
+	}
+	require.True(t, snmp.Init())
+
+	for _, v := range []struct {
+		value    uint
+		expected int64
+	}{
+		{value: math.MaxUint32 - 5, expected: math.MaxUint32 - 5},
+		{value: 10, expected: math.MaxUint32 + 11},
+		{value: 20, expected: math.MaxUint32 + 21},
+	} {
+		agent.pdus = []gosnmp.SnmpPDU{{Name: "." + ifInOctets + ".1", Type: gosnmp.Counter32, Value: v.value}}
+		assert.Equal(t, map[string]int64{"if_1_10": v.expected}, snmp.Collect())
+	}
+}
+
+// mockAgent serves GETNEXT and GETBULK requests from the sorted PDUs like an SNMP agent.
+type mockAgent struct {
+	pdus []gosnmp.SnmpPDU
+}
+
+func (a *mockAgent) getNext(oids []string) (*gosnmp.SnmpPacket, error) {
+	return a.getBulk(oids, 0, 1)
+}
+
+func (a *mockAgent) getBulk(oids []string, _ uint8, maxReps uint32) (*gosnmp.SnmpPacket, error) {
+	var vars []gosnmp.SnmpPDU
+	next := append([]string(nil), oids...)
+	for i := 0; i < int(maxReps); i++ {
+		for j, oid := range next {
+			pdu := a.successor(oid)
+			next[j] = strings.TrimPrefix(pdu.Name, ".")
+			vars = append(vars, pdu)
+		}
+	}
+	return &gosnmp.SnmpPacket{Variables: vars}, nil
+}
+
+func (a *mockAgent) successor(oid string) gosnmp.SnmpPDU {
+	for _, pdu := range a.pdus {
+		if compareOIDs(strings.TrimPrefix(pdu.Name, "."), oid) > 0 {
+			return pdu
+		}
+	}
+	return gosnmp.SnmpPDU{Name: "." + oid, Type: gosnmp.EndOfMibView}
+}
+
+func compareOIDs(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x - y
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// prepareIfXTable returns the ifXTable ifName, ifHCInOctets and ifHCOutOctets columns, the interface index
+// is the position in the names (starting from 1), an empty name skips the index.
+func prepareIfXTable(names ...string) []gosnmp.SnmpPDU {
+	const ifXEntry = ".1.3.6.1.2.1.31.1.1.1"
+	var pdus []gosnmp.SnmpPDU
+	for _, column := range []int{1, 6, 10} {
+		for i, name := range names {
+			if name == "" {
+				continue
+			}
+			pdu := gosnmp.SnmpPDU{Name: fmt.Sprintf("%s.%d.%d", ifXEntry, column, i+1)}
+			switch column {
+			case 1:
+				pdu.Type, pdu.Value = gosnmp.OctetString, []byte(name)
+			default:
+				pdu.Type, pdu.Value = gosnmp.Counter64, uint64((i+1)*1000+column*10)
+			}
+			pdus = append(pdus, pdu)
+		}
+	}
+	// ifCounterDiscontinuityTime.0, the next object after the table
+	pdus = append(pdus, gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.31.1.5.0", Type: gosnmp.TimeTicks, Value: uint32(0)})
+	return pdus
+}
+
 func mockInit(t *testing.T) (*snmpmock.MockHandler, func()) {
 	mockCtl := gomock.NewController(t)
 	cleanup := func() { mockCtl.Finish() }
@@ -429,6 +771,8 @@ func defaultMockExpects(m *snmpmock.MockHandler) {
 	m.EXPECT().SetSecurityModel(gomock.Any()).AnyTimes()
 	m.EXPECT().SetMsgFlags(gomock.Any()).AnyTimes()
 	m.EXPECT().SetSecurityParameters(gomock.Any()).AnyTimes()
+	m.EXPECT().SetContextName(gomock.Any()).AnyTimes()
+	m.EXPECT().ContextName().AnyTimes()
 	m.EXPECT().Connect().Return(nil).AnyTimes()
 }
 
@@ -443,6 +787,33 @@ func prepareConfigWithIndexRange(p func() Config, start, end int) Config {
 	return cfg
 }
 
+func prepareConfigWithTable(p func() Config) Config {
+	cfg := p()
+	cfg.Options.MaxReps = 2
+	cfg.Tables = []TableConfig{
+		{
+			ID:          "if",
+			OID:         "1.3.6.1.2.1.31.1.1",
+			LabelColumn: 1,
+			LabelName:   "interface",
+			Selector:    matcher.SimpleExpr{Excludes: []string{"* lo"}},
+			Charts: []TableChartConfig{
+				{
+					ID:    "traffic",
+					Title: "Interface Traffic",
+					Units: "kilobits/s",
+					Type:  module.Area.String(),
+					Dimensions: []TableDimensionConfig{
+						{Column: 6, Name: "in", Algorithm: module.Incremental.String(), Multiplier: 8, Divisor: 1000},
+						{Column: 10, Name: "out", Algorithm: module.Incremental.String(), Multiplier: -8, Divisor: 1000},
+					},
+				},
+			},
+		},
+	}
+	return cfg
+}
+
 func prepareV3Config() Config {
 	cfg := prepareV2Config()
 	cfg.Options.Version = gosnmp.Version3.String()
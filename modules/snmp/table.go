@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package snmp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+type (
+	table struct {
+		TableConfig
+		oid      string // without the leading dot
+		labelOID string
+		columns  []tableColumn
+		selector matcher.Matcher
+		rows     map[string]*tableRow // by the row label
+	}
+	tableColumn struct {
+		num int
+		oid string
+	}
+	tableRow struct {
+		index    string
+		counters map[int]*counter32 // by the column number
+	}
+	// counter32 extends a Counter32 to 64 bits, e.g. ifInOctets wraps every ~34 seconds at 1 Gbit/s.
+	counter32 struct {
+		last  uint32
+		value int64
+	}
+)
+
+// columnOID returns the OID of the table column, the table entry (the row definition) is always the first child of the table.
+func (t *table) columnOID(column int) string {
+	return fmt.Sprintf("%s.1.%d", t.oid, column)
+}
+
+func (t *table) chartID(chart TableChartConfig, label string) string {
+	return fmt.Sprintf("%s_%s_%s", t.ID, chart.ID, cleanTableLabel(label))
+}
+
+func (t *table) dimID(label string, column int) string {
+	return fmt.Sprintf("%s_%s_%d", t.ID, cleanTableLabel(label), column)
+}
+
+// update returns the counter value as if it was never wrapped, the uint32 subtraction gives the increase across a wrap.
+// A counter reset (a device reboot) is seen as a wrap too.
+func (c *counter32) update(v uint32) int64 {
+	c.value += int64(v - c.last)
+	c.last = v
+	return c.value
+}
+
+func (s *SNMP) collectTables(mx map[string]int64) error {
+	for _, t := range s.tables {
+		if err := s.collectTable(mx, t); err != nil {
+			return fmt.Errorf("table '%s': %v", t.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *SNMP) collectTable(mx map[string]int64, t *table) error {
+	oids := make([]string, 0, len(t.columns))
+	for _, col := range t.columns {
+		oids = append(oids, col.oid)
+	}
+
+	values, err := s.walkColumns(oids)
+	if err != nil {
+		return err
+	}
+
+	// the rows are grouped by the index, the OID suffix after the column OID (e.g. ifIndex)
+	var indexes []string
+	rows := make(map[string]map[int]gosnmp.SnmpPDU)
+	for _, col := range t.columns {
+		for _, pdu := range values[col.oid] {
+			idx := strings.TrimPrefix(pdu.Name, col.oid+".")
+			if _, ok := rows[idx]; !ok {
+				indexes = append(indexes, idx)
+				rows[idx] = make(map[int]gosnmp.SnmpPDU)
+			}
+			rows[idx][col.num] = pdu
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, idx := range indexes {
+		label := idx
+		if pdu, ok := rows[idx][t.LabelColumn]; ok && t.labelOID != "" {
+			if v := pduString(pdu); v != "" {
+				label = v
+			}
+		}
+		if t.selector != nil && !t.selector.MatchString(label) {
+			continue
+		}
+		if seen[label] {
+			s.Debugf("table '%s': skipping row '%s' (label '%s' is not unique)", t.ID, idx, label)
+			continue
+		}
+		seen[label] = true
+
+		row, ok := t.rows[label]
+		if !ok {
+			row = &tableRow{}
+			t.rows[label] = row
+			s.Debugf("table '%s': new row '%s' (index '%s'): adding charts", t.ID, label, idx)
+			s.addTableRowCharts(t, label)
+		}
+		if row.index != idx {
+			// the agent renumbered the rows (e.g. ifIndex after a reboot), the counters start over
+			row.index = idx
+			row.counters = make(map[int]*counter32)
+		}
+
+		for num, pdu := range rows[idx] {
+			if pdu.Type == gosnmp.Counter32 {
+				v := uint32(gosnmp.ToBigInt(pdu.Value).Uint64())
+				c, ok := row.counters[num]
+				if !ok {
+					c = &counter32{last: v, value: int64(v)}
+					row.counters[num] = c
+				}
+				mx[t.dimID(label, num)] = c.update(v)
+				continue
+			}
+			if v, ok := pduInt64(pdu); ok {
+				mx[t.dimID(label, num)] = v
+			}
+		}
+	}
+
+	for label := range t.rows {
+		if !seen[label] {
+			delete(t.rows, label)
+			s.Debugf("table '%s': row '%s' is gone: removing charts", t.ID, label)
+			s.removeTableRowCharts(t, label)
+		}
+	}
+
+	return nil
+}
+
+// walkColumns walks the table columns side by side: every GETBULK request asks for the next 'max_repetitions' rows
+// of up to 'max_request_size' columns. The walk of a column ends when the agent returns an OID outside of it.
+// SNMPv1 has no GETBULK, GETNEXT (a row per request) is used instead.
+func (s *SNMP) walkColumns(columns []string) (map[string][]gosnmp.SnmpPDU, error) {
+	values := make(map[string][]gosnmp.SnmpPDU, len(columns))
+	next := make(map[string]string, len(columns))
+	for _, col := range columns {
+		next[col] = col
+	}
+
+	maxOIDs := s.Options.MaxOIDs
+	if maxOIDs < 1 {
+		maxOIDs = defaultMaxOIDs
+	}
+
+	pending := columns
+	for len(pending) > 0 {
+		batch := pending
+		if len(batch) > maxOIDs {
+			batch = batch[:maxOIDs]
+		}
+
+		oids := make([]string, 0, len(batch))
+		for _, col := range batch {
+			oids = append(oids, next[col])
+		}
+
+		pdus, err := s.getNextRows(oids)
+		if err != nil {
+			return nil, err
+		}
+
+		// the response variables are row by row: the successors of all the requested OIDs, then their successors, etc.
+		done := make(map[string]bool)
+		for i, pdu := range pdus {
+			col := batch[i%len(batch)]
+			if done[col] {
+				continue
+			}
+			name := strings.TrimPrefix(pdu.Name, ".")
+			if isEndOfWalk(pdu) || !strings.HasPrefix(name, col+".") || name == next[col] {
+				done[col] = true
+				continue
+			}
+			pdu.Name = name
+			values[col] = append(values[col], pdu)
+			next[col] = name
+		}
+
+		var rest []string
+		for _, col := range batch {
+			// a response without variables would repeat forever
+			if !done[col] && len(pdus) > 0 {
+				rest = append(rest, col)
+			}
+		}
+		pending = append(rest, pending[len(batch):]...)
+	}
+
+	return values, nil
+}
+
+func (s *SNMP) getNextRows(oids []string) ([]gosnmp.SnmpPDU, error) {
+	var resp *gosnmp.SnmpPacket
+	var err error
+
+	if ver, _ := parseSNMPVersion(s.Options.Version); ver == gosnmp.Version1 {
+		resp, err = s.snmpClient.GetNext(oids)
+	} else {
+		maxReps := s.Options.MaxReps
+		if maxReps < 1 {
+			maxReps = defaultMaxReps
+		}
+		resp, err = s.snmpClient.GetBulk(oids, 0, uint32(maxReps))
+	}
+	if err := checkResponse(resp, err); err != nil {
+		return nil, err
+	}
+	if resp.Error != gosnmp.NoError {
+		return nil, fmt.Errorf("agent returned error '%s' (index %d)", resp.Error, resp.ErrorIndex)
+	}
+
+	return resp.Variables, nil
+}
+
+func (s *SNMP) addTableRowCharts(t *table, label string) {
+	for _, cfg := range t.Charts {
+		chart, err := newTableChart(t, cfg, label)
+		if err != nil {
+			s.Warning(err)
+			continue
+		}
+		if err := s.charts.Add(chart); err != nil {
+			s.Warning(err)
+		}
+	}
+}
+
+func (s *SNMP) removeTableRowCharts(t *table, label string) {
+	for _, cfg := range t.Charts {
+		if chart := s.charts.Get(t.chartID(cfg, label)); chart != nil {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+func newTableChart(t *table, cfg TableChartConfig, label string) (*module.Chart, error) {
+	chartCfg := ChartConfig{
+		ID:       t.chartID(cfg, label),
+		Title:    cfg.Title,
+		Units:    cfg.Units,
+		Family:   cfg.Family,
+		Type:     cfg.Type,
+		Priority: cfg.Priority,
+	}
+	for _, dim := range cfg.Dimensions {
+		chartCfg.Dimensions = append(chartCfg.Dimensions, DimensionConfig{
+			OID:        t.dimID(label, dim.Column),
+			Name:       dim.Name,
+			Algorithm:  dim.Algorithm,
+			Multiplier: dim.Multiplier,
+			Divisor:    dim.Divisor,
+		})
+	}
+
+	chart, err := newChart(chartCfg)
+	if err != nil {
+		return nil, err
+	}
+	chart.Ctx = fmt.Sprintf("snmp.%s_%s", t.ID, cfg.ID)
+	chart.Labels = []module.Label{
+		{Key: t.LabelName, Value: label},
+	}
+
+	return chart, nil
+}
+
+func isEndOfWalk(pdu gosnmp.SnmpPDU) bool {
+	switch pdu.Type {
+	case gosnmp.EndOfMibView, gosnmp.NoSuchObject, gosnmp.NoSuchInstance:
+		return true
+	}
+	return false
+}
+
+// pduString returns the value of the label column: usually an OctetString (ifName, ifDescr), but any type is allowed.
+func pduString(pdu gosnmp.SnmpPDU) string {
+	switch v := pdu.Value.(type) {
+	case []byte:
+		return strings.TrimSpace(strings.TrimRight(string(v), "\x00"))
+	case string:
+		return v
+	}
+	if v, ok := pduInt64(pdu); ok {
+		return fmt.Sprintf("%d", v)
+	}
+	return ""
+}
+
+func cleanTableLabel(label string) string {
+	r := strings.NewReplacer(" ", "_", ".", "_")
+	return r.Replace(label)
+}
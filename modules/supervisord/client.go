@@ -3,10 +3,7 @@
 package supervisord
 
 import (
-	"context"
-	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -25,15 +22,8 @@ func newSupervisorRPCClient(serverURL *url.URL, httpClient *http.Client) (superv
 		c.HttpClient = httpClient
 		return &supervisorRPCClient{client: c}, nil
 	case "unix":
+		// the HTTP client connects to the socket, see web.Client UnixSocket
 		c := xmlrpc.NewClient("http://unix/RPC2")
-		t, ok := httpClient.Transport.(*http.Transport)
-		if !ok {
-			return nil, errors.New("unexpected HTTP client transport")
-		}
-		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
-			d := net.Dialer{Timeout: httpClient.Timeout}
-			return d.DialContext(ctx, "unix", serverURL.Path)
-		}
 		c.HttpClient = httpClient
 		return &supervisorRPCClient{client: c}, nil
 	default:
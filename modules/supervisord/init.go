@@ -22,7 +22,11 @@ func (s Supervisord) initSupervisorClient() (supervisorClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse 'url': %v (%s)", err, s.URL)
 	}
-	httpClient, err := web.NewHTTPClient(s.Client)
+	client := s.Client
+	if u.Scheme == "unix" {
+		client.UnixSocket = u.Path
+	}
+	httpClient, err := web.NewHTTPClient(client)
 	if err != nil {
 		return nil, fmt.Errorf("create HTTP client: %v", err)
 	}
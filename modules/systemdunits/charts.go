@@ -26,6 +26,12 @@ const (
 	prioTimerUnitState
 	prioScopeUnitState
 	prioSliceUnitState
+
+	prioUnitCPUUsage
+	prioUnitMemoryUsage
+	prioUnitTasks
+	prioUnitIPTraffic
+	prioServiceUnitRestarts
 )
 
 var prioMap = map[string]int{
@@ -74,3 +80,110 @@ func (s *SystemdUnits) addUnitToCharts(name, typ string) {
 		s.Warning(err)
 	}
 }
+
+var unitResourcesChartsTmpl = module.Charts{
+	unitCPUUsageChartTmpl.Copy(),
+	unitMemoryUsageChartTmpl.Copy(),
+	unitTasksChartTmpl.Copy(),
+	unitIPTrafficChartTmpl.Copy(),
+	serviceUnitRestartsChartTmpl.Copy(),
+}
+
+var (
+	unitCPUUsageChartTmpl = module.Chart{
+		ID:       "unit_%s_%s_cpu_usage",
+		Title:    "Unit CPU Usage",
+		Units:    "percentage",
+		Fam:      "%s units resources",
+		Ctx:      "systemd.unit_cpu_usage",
+		Priority: prioUnitCPUUsage,
+		Dims: module.Dims{
+			// CPUUsageNSec is the total CPU time in nanoseconds
+			{ID: "unit_%s_%s_cpu_usage_nsec", Name: "cpu", Algo: module.Incremental, Div: 1e7},
+		},
+	}
+	unitMemoryUsageChartTmpl = module.Chart{
+		ID:       "unit_%s_%s_memory_usage",
+		Title:    "Unit Memory Usage",
+		Units:    "B",
+		Fam:      "%s units resources",
+		Ctx:      "systemd.unit_memory_usage",
+		Priority: prioUnitMemoryUsage,
+		Dims: module.Dims{
+			{ID: "unit_%s_%s_memory_current", Name: "current"},
+		},
+	}
+	unitTasksChartTmpl = module.Chart{
+		ID:       "unit_%s_%s_tasks",
+		Title:    "Unit Tasks",
+		Units:    "tasks",
+		Fam:      "%s units resources",
+		Ctx:      "systemd.unit_tasks",
+		Priority: prioUnitTasks,
+		Dims: module.Dims{
+			{ID: "unit_%s_%s_tasks_current", Name: "current"},
+		},
+	}
+	unitIPTrafficChartTmpl = module.Chart{
+		ID:       "unit_%s_%s_ip_traffic",
+		Title:    "Unit IP Traffic",
+		Units:    "B/s",
+		Fam:      "%s units resources",
+		Ctx:      "systemd.unit_ip_traffic",
+		Priority: prioUnitIPTraffic,
+		Type:     module.Area,
+		Dims: module.Dims{
+			{ID: "unit_%s_%s_ip_ingress_bytes", Name: "ingress", Algo: module.Incremental},
+			{ID: "unit_%s_%s_ip_egress_bytes", Name: "egress", Algo: module.Incremental, Mul: -1},
+		},
+	}
+	serviceUnitRestartsChartTmpl = module.Chart{
+		ID:       "unit_%s_%s_restarts",
+		Title:    "Service Unit Restarts",
+		Units:    "restarts/s",
+		Fam:      "%s units resources",
+		Ctx:      "systemd.service_unit_restarts",
+		Priority: prioServiceUnitRestarts,
+		Dims: module.Dims{
+			{ID: "unit_%s_%s_restarts", Name: "restarts", Algo: module.Incremental},
+		},
+	}
+)
+
+func newUnitResourcesCharts(name, typ string) *module.Charts {
+	charts := unitResourcesChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, name, typ)
+		chart.Fam = fmt.Sprintf(chart.Fam, typ)
+		chart.Labels = []module.Label{
+			{Key: "unit_name", Value: name},
+			{Key: "unit_type", Value: typ},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, name, typ)
+		}
+	}
+	return charts
+}
+
+// addUnitResourcesCharts adds the charts of the properties the unit has (the accounting can be disabled).
+func (s *SystemdUnits) addUnitResourcesCharts(name, typ string, mx map[string]int64) {
+	for _, chart := range *newUnitResourcesCharts(name, typ) {
+		if s.Charts().Has(chart.ID) || !hasAllDims(chart, mx) {
+			continue
+		}
+		if err := s.Charts().Add(chart); err != nil {
+			s.Warning(err)
+		}
+	}
+}
+
+func hasAllDims(chart *module.Chart, mx map[string]int64) bool {
+	for _, dim := range chart.Dims {
+		if _, ok := mx[dim.ID]; !ok {
+			return false
+		}
+	}
+	return true
+}
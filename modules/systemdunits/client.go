@@ -19,6 +19,7 @@ type systemdConnection interface {
 	GetManagerProperty(string) (string, error)
 	ListUnitsContext(ctx context.Context) ([]dbus.UnitStatus, error)
 	ListUnitsByPatternsContext(ctx context.Context, states []string, patterns []string) ([]dbus.UnitStatus, error)
+	GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]interface{}, error)
 }
 
 type systemdDBusClient struct{}
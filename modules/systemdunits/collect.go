@@ -81,6 +81,10 @@ func (s *SystemdUnits) collect() (map[string]int64, error) {
 	mx := make(map[string]int64)
 	s.collectUnitsStates(mx, units)
 
+	if s.CollectUnitResources {
+		s.collectUnitsResources(mx, conn, units)
+	}
+
 	return mx, nil
 }
 
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build linux
+// +build linux
+
+package systemdunits
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html
+// The units that have a control group, the resource accounting properties are in the unit type interface.
+var resourcesUnitTypes = map[string]bool{
+	unitTypeService: true,
+	unitTypeSocket:  true,
+	unitTypeMount:   true,
+	unitTypeSwap:    true,
+	unitTypeScope:   true,
+	unitTypeSlice:   true,
+}
+
+var unitResourcesProperties = []struct {
+	name   string
+	metric string
+}{
+	{name: "CPUUsageNSec", metric: "cpu_usage_nsec"},
+	{name: "MemoryCurrent", metric: "memory_current"},
+	{name: "TasksCurrent", metric: "tasks_current"},
+	{name: "IPIngressBytes", metric: "ip_ingress_bytes"},
+	{name: "IPEgressBytes", metric: "ip_egress_bytes"},
+	{name: "NRestarts", metric: "restarts"},
+}
+
+func (s *SystemdUnits) collectUnitsResources(mx map[string]int64, conn systemdConnection, units []dbus.UnitStatus) {
+	var queue []dbus.UnitStatus
+	for _, unit := range units {
+		_, typ := extractUnitNameType(unit.Name)
+		// inactive units have no control group
+		if resourcesUnitTypes[typ] && unit.ActiveState != unitStateInactive {
+			queue = append(queue, unit)
+		}
+	}
+	if len(queue) == 0 {
+		return
+	}
+
+	start := s.resourcesNext % len(queue)
+	s.resourcesNext = 0
+
+	budgetCtx := context.Background()
+	if budget := s.UnitResourcesTimeBudget.Duration; budget > 0 {
+		var cancel context.CancelFunc
+		budgetCtx, cancel = context.WithTimeout(budgetCtx, budget)
+		defer cancel()
+	}
+
+	for i := 0; i < len(queue); i++ {
+		idx := (start + i) % len(queue)
+
+		err := s.collectUnitResources(budgetCtx, mx, conn, queue[idx])
+		if err != nil {
+			s.Debugf("units resources: %v", err)
+		}
+
+		if budgetCtx.Err() != nil && i < len(queue)-1 {
+			s.Debugf("units resources: time budget (%s) exceeded, got %d/%d units", s.UnitResourcesTimeBudget, i+1, len(queue))
+			s.resourcesNext = idx + 1
+			if err != nil {
+				// the call was interrupted
+				s.resourcesNext = idx
+			}
+			return
+		}
+	}
+}
+
+func (s *SystemdUnits) collectUnitResources(budgetCtx context.Context, mx map[string]int64, conn systemdConnection, unit dbus.UnitStatus) error {
+	name, typ := extractUnitNameType(cleanUnitName(unit.Name))
+	if name == "" || typ == "" {
+		return nil
+	}
+
+	// the call is bounded by the timeout and the rest of the budget
+	ctx, cancel := context.WithTimeout(budgetCtx, s.Timeout.Duration)
+	defer cancel()
+
+	// one GetAll call for all the properties of the unit type interface
+	props, err := conn.GetUnitTypePropertiesContext(ctx, unit.Name, cases.Title(language.English).String(typ))
+	if err != nil {
+		return fmt.Errorf("error on getting '%s' properties: %w", unit.Name, err)
+	}
+
+	for _, p := range unitResourcesProperties {
+		if v, ok := propertyValue(props[p.name]); ok {
+			mx[fmt.Sprintf("unit_%s_%s_%s", name, typ, p.metric)] = v
+		}
+	}
+
+	s.addUnitResourcesCharts(name, typ, mx)
+
+	return nil
+}
+
+// propertyValue returns the unsigned property value. The max value of the type means the value is not set
+// (the accounting is disabled or the unit has no control group).
+func propertyValue(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case uint64:
+		if v == math.MaxUint64 || v > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case uint32:
+		if v == math.MaxUint32 {
+			return 0, false
+		}
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -33,7 +33,8 @@ func New() *SystemdUnits {
 			Include: []string{
 				"*.service",
 			},
-			Timeout: web.Duration{Duration: time.Second * 2},
+			Timeout:                 web.Duration{Duration: time.Second * 2},
+			UnitResourcesTimeBudget: web.Duration{Duration: time.Second * 5},
 		},
 
 		charts: &module.Charts{},
@@ -43,8 +44,10 @@ func New() *SystemdUnits {
 }
 
 type Config struct {
-	Include []string     `yaml:"include"`
-	Timeout web.Duration `yaml:"timeout"`
+	Include                 []string     `yaml:"include"`
+	Timeout                 web.Duration `yaml:"timeout"`
+	CollectUnitResources    bool         `yaml:"collect_unit_resources"`
+	UnitResourcesTimeBudget web.Duration `yaml:"unit_resources_time_budget"`
 }
 
 type SystemdUnits struct {
@@ -58,6 +61,10 @@ type SystemdUnits struct {
 	units          map[string]bool
 	sr             matcher.Matcher
 
+	// the index of the unit to get the resources properties of first, the units the time budget wasn't
+	// enough for are the first in the next collection
+	resourcesNext int
+
 	charts *module.Charts
 }
 
@@ -9,10 +9,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/web"
 
 	"github.com/coreos/go-systemd/v22/dbus"
 	"github.com/stretchr/testify/assert"
@@ -713,6 +717,73 @@ func TestSystemdUnits_connectionReuse(t *testing.T) {
 	assert.Equal(t, 1, client.connectCalls)
 }
 
+func TestSystemdUnits_Collect_UnitResources(t *testing.T) {
+	systemd := New()
+	systemd.Include = []string{"user*", "systemd-fsck-root.service"}
+	systemd.CollectUnitResources = true
+	client := prepareOKClient(230)
+	systemd.client = client
+	require.True(t, systemd.Init())
+
+	collected := systemd.Collect()
+
+	expected := map[string]int64{
+		"unit_user-runtime-dir@1000_service_cpu_usage_nsec":   48017000,
+		"unit_user-runtime-dir@1000_service_memory_current":   1560576,
+		"unit_user-runtime-dir@1000_service_tasks_current":    1,
+		"unit_user-runtime-dir@1000_service_ip_ingress_bytes": 2048,
+		"unit_user-runtime-dir@1000_service_ip_egress_bytes":  1024,
+		"unit_user-runtime-dir@1000_service_restarts":         2,
+		"unit_user@1000_service_cpu_usage_nsec":               1304517000,
+		"unit_user@1000_service_memory_current":               84975616,
+		"unit_user@1000_service_tasks_current":                25,
+		"unit_user@1000_service_restarts":                     0,
+		"unit_user_slice_cpu_usage_nsec":                      6537911000,
+		"unit_user_slice_memory_current":                      2315878400,
+		"unit_user_slice_tasks_current":                       481,
+	}
+	for k, v := range expected {
+		assert.Equalf(t, v, collected[k], "metric '%s'", k)
+	}
+	for k := range collected {
+		if _, ok := expected[k]; !ok {
+			assert.Containsf(t, k, "_state_", "unexpected metric '%s'", k)
+		}
+	}
+	// inactive units have no control group
+	assert.NotContains(t, client.conn.(*mockConn).propsCalls, "systemd-fsck-root.service")
+
+	assert.True(t, systemd.Charts().Has("unit_user-runtime-dir@1000_service_ip_traffic"))
+	assert.False(t, systemd.Charts().Has("unit_user@1000_service_ip_traffic"), "IP accounting is disabled")
+	assert.False(t, systemd.Charts().Has("unit_user_slice_restarts"), "no restarts for slices")
+	ensureCollectedHasAllChartsDimsVarsIDs(t, systemd, collected)
+}
+
+func TestSystemdUnits_Collect_UnitResourcesTimeBudget(t *testing.T) {
+	systemd := New()
+	systemd.Include = []string{"user*"}
+	systemd.CollectUnitResources = true
+	systemd.UnitResourcesTimeBudget = web.Duration{Duration: time.Millisecond * 150}
+	client := prepareOKClient(230)
+	client.conn.(*mockConn).propsDelay = time.Millisecond * 100
+	systemd.client = client
+	require.True(t, systemd.Init())
+
+	// the budget is enough for one unit, the next collection continues with the next unit
+	collected := systemd.Collect()
+	assert.Contains(t, collected, "unit_user-runtime-dir@1000_service_memory_current")
+	assert.NotContains(t, collected, "unit_user@1000_service_memory_current")
+	assert.NotContains(t, collected, "unit_user_slice_memory_current")
+
+	collected = systemd.Collect()
+	assert.NotContains(t, collected, "unit_user-runtime-dir@1000_service_memory_current")
+	assert.Contains(t, collected, "unit_user@1000_service_memory_current")
+	assert.NotContains(t, collected, "unit_user_slice_memory_current")
+
+	collected = systemd.Collect()
+	assert.Contains(t, collected, "unit_user_slice_memory_current")
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, sd *SystemdUnits, collected map[string]int64) {
 	for _, chart := range *sd.Charts() {
 		if chart.Obsolete {
@@ -734,6 +805,7 @@ func prepareOKClient(ver int) *mockClient {
 		conn: &mockConn{
 			version: ver,
 			units:   mockSystemdUnits,
+			props:   mockSystemdUnitsProperties,
 		},
 	}
 }
@@ -784,6 +856,9 @@ type mockConn struct {
 	errOnGetManagerProperty bool
 	errOnListUnits          bool
 	closeCalled             bool
+	props                   map[string]map[string]interface{}
+	propsDelay              time.Duration
+	propsCalls              []string
 }
 
 func (m *mockConn) Close() {
@@ -836,6 +911,58 @@ func (m *mockConn) ListUnitsByPatternsContext(_ context.Context, _ []string, ps
 	return units, nil
 }
 
+func (m *mockConn) GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]interface{}, error) {
+	m.propsCalls = append(m.propsCalls, unit)
+
+	if !strings.HasSuffix(unit, "."+strings.ToLower(unitType)) {
+		return nil, fmt.Errorf("'GetUnitTypeProperties' unit '%s' has no '%s' interface", unit, unitType)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(m.propsDelay):
+	}
+
+	props, ok := m.props[unit]
+	if !ok {
+		// the accounting is disabled
+		props = map[string]interface{}{
+			"CPUUsageNSec":  uint64(math.MaxUint64),
+			"MemoryCurrent": uint64(math.MaxUint64),
+			"TasksCurrent":  uint64(math.MaxUint64),
+		}
+	}
+	return props, nil
+}
+
+var mockSystemdUnitsProperties = map[string]map[string]interface{}{
+	`user-runtime-dir@1000.service`: {
+		"Type":           "oneshot",
+		"CPUUsageNSec":   uint64(48017000),
+		"MemoryCurrent":  uint64(1560576),
+		"TasksCurrent":   uint64(1),
+		"IPIngressBytes": uint64(2048),
+		"IPEgressBytes":  uint64(1024),
+		"NRestarts":      uint32(2),
+	},
+	`user@1000.service`: {
+		"Type":           "notify",
+		"CPUUsageNSec":   uint64(1304517000),
+		"MemoryCurrent":  uint64(84975616),
+		"TasksCurrent":   uint64(25),
+		"IPIngressBytes": uint64(math.MaxUint64),
+		"IPEgressBytes":  uint64(math.MaxUint64),
+		"NRestarts":      uint32(0),
+	},
+	`user.slice`: {
+		"CPUUsageNSec":   uint64(6537911000),
+		"MemoryCurrent":  uint64(2315878400),
+		"TasksCurrent":   uint64(481),
+		"IPIngressBytes": uint64(math.MaxUint64),
+		"IPEgressBytes":  uint64(math.MaxUint64),
+	},
+}
+
 var mockSystemdUnits = []dbus.UnitStatus{
 	{Name: `proc-sys-fs-binfmt_misc.automount`, LoadState: "loaded", ActiveState: "active"},
 	{Name: `dev-nvme0n1.device`, LoadState: "loaded", ActiveState: "active"},
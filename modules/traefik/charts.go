@@ -71,3 +71,186 @@ func newEntrypointChart(tmpl module.Chart, entrypoint, proto string) *module.Cha
 	}
 	return chart
 }
+
+var chartsTmplRouter = module.Charts{
+	{
+		ID:    "router_%s_requests",
+		Title: "Processed HTTP requests by router",
+		Units: "requests/s",
+		Fam:   "routers",
+		Ctx:   "traefik.router_requests",
+		Type:  module.Stacked,
+		Dims: module.Dims{
+			{ID: "router_%s_requests_1xx", Name: "1xx", Algo: module.Incremental},
+			{ID: "router_%s_requests_2xx", Name: "2xx", Algo: module.Incremental},
+			{ID: "router_%s_requests_3xx", Name: "3xx", Algo: module.Incremental},
+			{ID: "router_%s_requests_4xx", Name: "4xx", Algo: module.Incremental},
+			{ID: "router_%s_requests_5xx", Name: "5xx", Algo: module.Incremental},
+		},
+	},
+	{
+		ID:    "router_%s_request_duration",
+		Title: "Average HTTP request processing time by router",
+		Units: "milliseconds",
+		Fam:   "routers",
+		Ctx:   "traefik.router_request_duration_average",
+		Type:  module.Stacked,
+		Dims: module.Dims{
+			{ID: "router_%s_request_duration_average_1xx", Name: "1xx"},
+			{ID: "router_%s_request_duration_average_2xx", Name: "2xx"},
+			{ID: "router_%s_request_duration_average_3xx", Name: "3xx"},
+			{ID: "router_%s_request_duration_average_4xx", Name: "4xx"},
+			{ID: "router_%s_request_duration_average_5xx", Name: "5xx"},
+		},
+	},
+}
+
+var chartTmplRouterOpenConnections = module.Chart{
+	ID:    "router_%s_open_connections",
+	Title: "Open connections by router",
+	Units: "connections",
+	Fam:   "routers",
+	Ctx:   "traefik.router_open_connections",
+	Dims: module.Dims{
+		{ID: "router_%s_open_connections", Name: "open"},
+	},
+}
+
+var chartsTmplService = module.Charts{
+	{
+		ID:    "service_%s_requests",
+		Title: "Processed HTTP requests by service",
+		Units: "requests/s",
+		Fam:   "services",
+		Ctx:   "traefik.service_requests",
+		Type:  module.Stacked,
+		Dims: module.Dims{
+			{ID: "service_%s_requests_1xx", Name: "1xx", Algo: module.Incremental},
+			{ID: "service_%s_requests_2xx", Name: "2xx", Algo: module.Incremental},
+			{ID: "service_%s_requests_3xx", Name: "3xx", Algo: module.Incremental},
+			{ID: "service_%s_requests_4xx", Name: "4xx", Algo: module.Incremental},
+			{ID: "service_%s_requests_5xx", Name: "5xx", Algo: module.Incremental},
+		},
+	},
+	{
+		ID:    "service_%s_request_duration",
+		Title: "Average HTTP request processing time by service",
+		Units: "milliseconds",
+		Fam:   "services",
+		Ctx:   "traefik.service_request_duration_average",
+		Type:  module.Stacked,
+		Dims: module.Dims{
+			{ID: "service_%s_request_duration_average_1xx", Name: "1xx"},
+			{ID: "service_%s_request_duration_average_2xx", Name: "2xx"},
+			{ID: "service_%s_request_duration_average_3xx", Name: "3xx"},
+			{ID: "service_%s_request_duration_average_4xx", Name: "4xx"},
+			{ID: "service_%s_request_duration_average_5xx", Name: "5xx"},
+		},
+	},
+}
+
+var chartTmplServiceOpenConnections = module.Chart{
+	ID:    "service_%s_open_connections",
+	Title: "Open connections by service",
+	Units: "connections",
+	Fam:   "services",
+	Ctx:   "traefik.service_open_connections",
+	Dims: module.Dims{
+		{ID: "service_%s_open_connections", Name: "open"},
+	},
+}
+
+var chartTmplTLSCertTimeUntilExpiration = module.Chart{
+	ID:    "tls_cert_%s_time_until_expiration",
+	Title: "TLS certificate time until expiration",
+	Units: "seconds",
+	Fam:   "tls",
+	Ctx:   "traefik.tls_cert_time_until_expiration",
+	Dims: module.Dims{
+		{ID: "tls_cert_%s_time_until_expiration", Name: "time"},
+	},
+}
+
+func (t *Traefik) addHTTPObjectCharts(kind, name string) {
+	tmpl, _ := httpObjectChartsTmpl(kind)
+	charts := tmpl.Copy()
+
+	for _, chart := range *charts {
+		setHTTPObjectChart(chart, kind, name)
+	}
+
+	if err := t.Charts().Add(*charts...); err != nil {
+		t.Warning(err)
+	}
+}
+
+func (t *Traefik) addHTTPObjectOpenConnectionsChart(kind, name string) {
+	_, tmpl := httpObjectChartsTmpl(kind)
+	chart := tmpl.Copy()
+
+	setHTTPObjectChart(chart, kind, name)
+
+	if err := t.Charts().Add(chart); err != nil {
+		t.Warning(err)
+	}
+}
+
+func (t *Traefik) removeHTTPObjectCharts(kind, name string) {
+	charts, openConn := httpObjectChartsTmpl(kind)
+
+	ids := map[string]bool{fmt.Sprintf(openConn.ID, name): true}
+	for _, tmpl := range *charts {
+		ids[fmt.Sprintf(tmpl.ID, name)] = true
+	}
+
+	for _, chart := range *t.Charts() {
+		if ids[chart.ID] {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
+
+func httpObjectChartsTmpl(kind string) (*module.Charts, *module.Chart) {
+	if kind == serviceMetrics.kind {
+		return &chartsTmplService, &chartTmplServiceOpenConnections
+	}
+	return &chartsTmplRouter, &chartTmplRouterOpenConnections
+}
+
+func setHTTPObjectChart(chart *module.Chart, kind, name string) {
+	chart.ID = fmt.Sprintf(chart.ID, name)
+	chart.Labels = []module.Label{
+		{Key: kind, Value: name},
+	}
+	for _, d := range chart.Dims {
+		d.ID = fmt.Sprintf(d.ID, name)
+	}
+}
+
+func (t *Traefik) addTLSCertChart(serial, cn, sans string) {
+	chart := chartTmplTLSCertTimeUntilExpiration.Copy()
+	chart.ID = fmt.Sprintf(chart.ID, serial)
+	chart.Labels = []module.Label{
+		{Key: "cn", Value: cn},
+		{Key: "sans", Value: sans},
+		{Key: "serial", Value: serial},
+	}
+	for _, d := range chart.Dims {
+		d.ID = fmt.Sprintf(d.ID, serial)
+	}
+
+	if err := t.Charts().Add(chart); err != nil {
+		t.Warning(err)
+	}
+}
+
+func (t *Traefik) removeTLSCertChart(serial string) {
+	id := fmt.Sprintf(chartTmplTLSCertTimeUntilExpiration.ID, serial)
+	for _, chart := range *t.Charts() {
+		if chart.ID == id {
+			chart.MarkRemove()
+			chart.MarkNotCreated()
+		}
+	}
+}
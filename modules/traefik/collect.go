@@ -49,6 +49,9 @@ func (t *Traefik) collect() (map[string]int64, error) {
 	t.collectEntrypointRequestDuration(mx, pms)
 	t.collectEntrypointOpenConnections(mx, pms)
 	t.updateCodeClassMetrics(mx)
+	t.collectRouters(mx, pms)
+	t.collectServices(mx, pms)
+	t.collectTLSCerts(mx, pms)
 
 	return mx, nil
 }
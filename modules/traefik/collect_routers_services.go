@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package traefik
+
+import (
+	"sort"
+	"time"
+
+	"github.com/netdata/go.d.plugin/pkg/matcher"
+	"github.com/netdata/go.d.plugin/pkg/prometheus"
+)
+
+const (
+	metricRouterRequestsTotal               = "traefik_router_requests_total"
+	metricRouterRequestDurationSecondsSum   = "traefik_router_request_duration_seconds_sum"
+	metricRouterRequestDurationSecondsCount = "traefik_router_request_duration_seconds_count"
+	metricRouterOpenConnections             = "traefik_router_open_connections"
+
+	metricServiceRequestsTotal               = "traefik_service_requests_total"
+	metricServiceRequestDurationSecondsSum   = "traefik_service_request_duration_seconds_sum"
+	metricServiceRequestDurationSecondsCount = "traefik_service_request_duration_seconds_count"
+	metricServiceOpenConnections             = "traefik_service_open_connections"
+
+	metricTLSCertsNotAfter = "traefik_tls_certs_not_after"
+)
+
+// otherHTTPObject aggregates the routers (services) that are over the 'max_routers' ('max_services') limit.
+// Traefik names them 'name@provider', so it doesn't clash with a real router (service).
+const otherHTTPObject = "_other"
+
+// httpObjectMetrics are the metric families of a router or a service, they have the same layout.
+type httpObjectMetrics struct {
+	kind                 string // the name label and the chart/dimension ID prefix
+	requestsTotal        string
+	requestDurationSum   string
+	requestDurationCount string
+	openConnections      string
+}
+
+func (m httpObjectMetrics) names() []string {
+	return []string{m.requestsTotal, m.requestDurationSum, m.requestDurationCount, m.openConnections}
+}
+
+var (
+	routerMetrics = httpObjectMetrics{
+		kind:                 "router",
+		requestsTotal:        metricRouterRequestsTotal,
+		requestDurationSum:   metricRouterRequestDurationSecondsSum,
+		requestDurationCount: metricRouterRequestDurationSecondsCount,
+		openConnections:      metricRouterOpenConnections,
+	}
+	serviceMetrics = httpObjectMetrics{
+		kind:                 "service",
+		requestsTotal:        metricServiceRequestsTotal,
+		requestDurationSum:   metricServiceRequestDurationSecondsSum,
+		requestDurationCount: metricServiceRequestDurationSecondsCount,
+		openConnections:      metricServiceOpenConnections,
+	}
+)
+
+type httpObjectStats struct {
+	requests        map[string]float64 // by the code class
+	reqDur          map[string]reqDurSample
+	openConnections float64
+	hasOpenConn     bool
+}
+
+func newHTTPObjectStats() *httpObjectStats {
+	return &httpObjectStats{
+		requests: make(map[string]float64),
+		reqDur:   make(map[string]reqDurSample),
+	}
+}
+
+func (s *httpObjectStats) merge(other *httpObjectStats) {
+	for class, v := range other.requests {
+		s.requests[class] += v
+	}
+	for class, v := range other.reqDur {
+		d := s.reqDur[class]
+		d.reqs, d.secs = d.reqs+v.reqs, d.secs+v.secs
+		s.reqDur[class] = d
+	}
+	s.openConnections += other.openConnections
+	s.hasOpenConn = s.hasOpenConn || other.hasOpenConn
+}
+
+func (s *httpObjectStats) totalRequests() (v float64) {
+	for _, n := range s.requests {
+		v += n
+	}
+	return v
+}
+
+func (t *Traefik) collectRouters(mx map[string]int64, pms prometheus.Series) {
+	if t.routerSelector == nil {
+		return
+	}
+	t.collectHTTPObjects(mx, pms, routerMetrics, t.routerSelector, t.MaxRouters, t.cache.routers)
+}
+
+func (t *Traefik) collectServices(mx map[string]int64, pms prometheus.Series) {
+	if t.serviceSelector == nil {
+		return
+	}
+	t.collectHTTPObjects(mx, pms, serviceMetrics, t.serviceSelector, t.MaxServices, t.cache.services)
+}
+
+func (t *Traefik) collectHTTPObjects(mx map[string]int64, pms prometheus.Series, m httpObjectMetrics,
+	sr matcher.Matcher, limit int, cache map[string]*cacheHTTPObject) {
+
+	stats := make(map[string]*httpObjectStats)
+
+	for _, pm := range pms.FindByNames(m.names()...) {
+		name := pm.Labels.Get(m.kind)
+		if name == "" || !sr.MatchString(name) {
+			continue
+		}
+
+		st, ok := stats[name]
+		if !ok {
+			st = newHTTPObjectStats()
+			stats[name] = st
+		}
+
+		if pm.Name() == m.openConnections {
+			st.openConnections += pm.Value
+			st.hasOpenConn = true
+			continue
+		}
+
+		codeClass := getCodeClass(pm.Labels.Get("code"))
+		if codeClass == "" {
+			continue
+		}
+		switch pm.Name() {
+		case m.requestsTotal:
+			st.requests[codeClass] += pm.Value
+		case m.requestDurationSum:
+			d := st.reqDur[codeClass]
+			d.secs += pm.Value
+			st.reqDur[codeClass] = d
+		case m.requestDurationCount:
+			d := st.reqDur[codeClass]
+			d.reqs += pm.Value
+			st.reqDur[codeClass] = d
+		}
+	}
+
+	charted := selectChartedHTTPObjects(stats, cache, limit)
+
+	if len(charted) < len(stats) {
+		other := newHTTPObjectStats()
+		for name, st := range stats {
+			if !charted[name] {
+				other.merge(st)
+			}
+		}
+		stats[otherHTTPObject] = other
+		charted[otherHTTPObject] = true
+	}
+
+	for name := range charted {
+		st := stats[name]
+
+		obj, ok := cache[name]
+		if !ok {
+			obj = &cacheHTTPObject{name: name, reqDurPrev: make(map[string]reqDurSample)}
+			cache[name] = obj
+			t.Debugf("new %s '%s': creating charts", m.kind, name)
+			t.addHTTPObjectCharts(m.kind, name)
+		}
+		if st.hasOpenConn && !obj.hasOpenConn {
+			obj.hasOpenConn = true
+			t.addHTTPObjectOpenConnectionsChart(m.kind, name)
+		}
+
+		px := m.kind + "_" + name + "_"
+
+		for _, codeClass := range httpRespCodeClasses {
+			mx[px+"requests_"+codeClass] = int64(st.requests[codeClass])
+
+			// the '_other' members change, a negative delta is reported as zero
+			cur := st.reqDur[codeClass]
+			prev, seen := obj.reqDurPrev[codeClass]
+			obj.reqDurPrev[codeClass] = cur
+
+			secs, reqs := cur.secs-prev.secs, cur.reqs-prev.reqs
+			key := px + "request_duration_average_" + codeClass
+			if !seen || secs <= 0 || reqs <= 0 {
+				mx[key] = 0
+			} else {
+				mx[key] = int64(secs * 1000 / reqs)
+			}
+		}
+
+		if obj.hasOpenConn {
+			mx[px+"open_connections"] = int64(st.openConnections)
+		}
+	}
+
+	// the routers (services) disappear after the dynamic configuration reload
+	for name := range cache {
+		if !charted[name] {
+			delete(cache, name)
+			t.Debugf("stale %s '%s': removing charts", m.kind, name)
+			t.removeHTTPObjectCharts(m.kind, name)
+		}
+	}
+}
+
+// selectChartedHTTPObjects returns up to 'limit' routers (services) to chart individually.
+// Already charted ones keep their slot, the free slots are taken by the ones with the most requests.
+func selectChartedHTTPObjects(stats map[string]*httpObjectStats, cache map[string]*cacheHTTPObject, limit int) map[string]bool {
+	charted := make(map[string]bool)
+
+	for name := range cache {
+		if _, ok := stats[name]; ok && len(charted) < limit {
+			charted[name] = true
+		}
+	}
+
+	var candidates []string
+	for name := range stats {
+		if !charted[name] {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		ri, rj := stats[candidates[i]].totalRequests(), stats[candidates[j]].totalRequests()
+		if ri == rj {
+			return candidates[i] < candidates[j]
+		}
+		return ri > rj
+	})
+
+	for _, name := range candidates {
+		if len(charted) >= limit {
+			break
+		}
+		charted[name] = true
+	}
+
+	return charted
+}
+
+func (t *Traefik) collectTLSCerts(mx map[string]int64, pms prometheus.Series) {
+	seen := make(map[string]bool)
+
+	for _, pm := range pms.FindByName(metricTLSCertsNotAfter) {
+		serial := pm.Labels.Get("serial")
+		if serial == "" {
+			continue
+		}
+		seen[serial] = true
+
+		if !t.cache.tlsCerts[serial] {
+			t.cache.tlsCerts[serial] = true
+			t.addTLSCertChart(serial, pm.Labels.Get("cn"), pm.Labels.Get("sans"))
+		}
+
+		// the value is the 'NotAfter' unix timestamp
+		notAfter := time.Unix(int64(pm.Value), 0)
+		mx["tls_cert_"+serial+"_time_until_expiration"] = int64(time.Until(notAfter).Seconds())
+	}
+
+	for serial := range t.cache.tlsCerts {
+		if !seen[serial] {
+			delete(t.cache.tlsCerts, serial)
+			t.removeTLSCertChart(serial)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package traefik
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
 	"github.com/netdata/go.d.plugin/pkg/prometheus/selector"
@@ -14,6 +15,30 @@ func (t Traefik) validateConfig() error {
 	if t.URL == "" {
 		return errors.New("'url' is not set")
 	}
+	if !t.RouterSelector.Empty() && t.MaxRouters <= 0 {
+		return errors.New("'max_routers' must be positive when 'router_selector' is set")
+	}
+	if !t.ServiceSelector.Empty() && t.MaxServices <= 0 {
+		return errors.New("'max_services' must be positive when 'service_selector' is set")
+	}
+	return nil
+}
+
+func (t *Traefik) initSelectors() error {
+	if !t.RouterSelector.Empty() {
+		m, err := t.RouterSelector.Parse()
+		if err != nil {
+			return fmt.Errorf("router selector: %v", err)
+		}
+		t.routerSelector = m
+	}
+	if !t.ServiceSelector.Empty() {
+		m, err := t.ServiceSelector.Parse()
+		if err != nil {
+			return fmt.Errorf("service selector: %v", err)
+		}
+		t.serviceSelector = m
+	}
 	return nil
 }
 
@@ -23,15 +48,28 @@ func (t Traefik) initPrometheusClient() (prometheus.Prometheus, error) {
 		return nil, err
 	}
 
+	// the per router and per service metrics are not scraped unless they are enabled
+	expr := selector.Expr{
+		Allow: []string{
+			metricEntrypointRequestDurationSecondsSum,
+			metricEntrypointRequestDurationSecondsCount,
+			metricEntrypointRequestsTotal,
+			metricEntrypointOpenConnections,
+			metricTLSCertsNotAfter,
+		},
+	}
+	if t.routerSelector != nil {
+		expr.Allow = append(expr.Allow, routerMetrics.names()...)
+	}
+	if t.serviceSelector != nil {
+		expr.Allow = append(expr.Allow, serviceMetrics.names()...)
+	}
+
+	sr, err := expr.Parse()
+	if err != nil {
+		return nil, err
+	}
+
 	prom := prometheus.NewWithSelector(httpClient, t.Request, sr)
 	return prom, nil
 }
-
-var sr, _ = selector.Expr{
-	Allow: []string{
-		metricEntrypointRequestDurationSecondsSum,
-		metricEntrypointRequestDurationSecondsCount,
-		metricEntrypointRequestsTotal,
-		metricEntrypointOpenConnections,
-	},
-}.Parse()
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/prometheus"
 	"github.com/netdata/go.d.plugin/pkg/web"
 )
@@ -32,18 +33,27 @@ func New() *Traefik {
 					Timeout: web.Duration{Duration: time.Second},
 				},
 			},
+			MaxRouters:  100,
+			MaxServices: 100,
 		},
 
 		charts:       &module.Charts{},
 		checkMetrics: true,
 		cache: &cache{
 			entrypoints: make(map[string]*cacheEntrypoint),
+			routers:     make(map[string]*cacheHTTPObject),
+			services:    make(map[string]*cacheHTTPObject),
+			tlsCerts:    make(map[string]bool),
 		},
 	}
 }
 
 type Config struct {
-	web.HTTP `yaml:",inline"`
+	web.HTTP        `yaml:",inline"`
+	RouterSelector  matcher.SimpleExpr `yaml:"router_selector"`
+	ServiceSelector matcher.SimpleExpr `yaml:"service_selector"`
+	MaxRouters      int                `yaml:"max_routers"`
+	MaxServices     int                `yaml:"max_services"`
 }
 
 type (
@@ -51,13 +61,18 @@ type (
 		module.Base
 		Config `yaml:",inline"`
 
-		prom         prometheus.Prometheus
-		charts       *module.Charts
-		checkMetrics bool
-		cache        *cache
+		prom            prometheus.Prometheus
+		routerSelector  matcher.Matcher
+		serviceSelector matcher.Matcher
+		charts          *module.Charts
+		checkMetrics    bool
+		cache           *cache
 	}
 	cache struct {
 		entrypoints map[string]*cacheEntrypoint
+		routers     map[string]*cacheHTTPObject
+		services    map[string]*cacheHTTPObject
+		tlsCerts    map[string]bool
 	}
 	cacheEntrypoint struct {
 		name, proto     string
@@ -71,6 +86,13 @@ type (
 		prev, cur struct{ reqs, secs float64 }
 		seen      bool
 	}
+	// cacheHTTPObject is a charted router or service.
+	cacheHTTPObject struct {
+		name        string
+		hasOpenConn bool
+		reqDurPrev  map[string]reqDurSample
+	}
+	reqDurSample struct{ reqs, secs float64 }
 )
 
 func (t *Traefik) Init() bool {
@@ -79,6 +101,11 @@ func (t *Traefik) Init() bool {
 		return false
 	}
 
+	if err := t.initSelectors(); err != nil {
+		t.Errorf("selectors initialization: %v", err)
+		return false
+	}
+
 	prom, err := t.initPrometheusClient()
 	if err != nil {
 		t.Errorf("prometheus client initialization: %v", err)
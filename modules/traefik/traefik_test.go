@@ -6,8 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/matcher"
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 	"github.com/netdata/go.d.plugin/pkg/web"
 
@@ -16,12 +20,14 @@ import (
 )
 
 var (
-	v221Metrics, _ = os.ReadFile("testdata/v2.2.1/metrics.txt")
+	v221Metrics, _  = os.ReadFile("testdata/v2.2.1/metrics.txt")
+	v2104Metrics, _ = os.ReadFile("testdata/v2.10.4/metrics.txt")
 )
 
 func Test_Testdata(t *testing.T) {
 	for name, data := range map[string][]byte{
-		"v2.2.1_Metrics": v221Metrics,
+		"v2.2.1_Metrics":  v221Metrics,
+		"v2.10.4_Metrics": v2104Metrics,
 	} {
 		require.NotNilf(t, data, name)
 	}
@@ -45,6 +51,23 @@ func TestTraefik_Init(t *testing.T) {
 				Request: web.Request{},
 			}},
 		},
+		"fails on non-positive 'max_routers'": {
+			wantFail: true,
+			config: func() Config {
+				cfg := New().Config
+				cfg.RouterSelector = matcher.SimpleExpr{Includes: []string{"* *"}}
+				cfg.MaxRouters = 0
+				return cfg
+			}(),
+		},
+		"fails on invalid 'service_selector'": {
+			wantFail: true,
+			config: func() Config {
+				cfg := New().Config
+				cfg.ServiceSelector = matcher.SimpleExpr{Includes: []string{"~ (invalid"}}
+				return cfg
+			}(),
+		},
 		"fails on invalid TLSCA": {
 			wantFail: true,
 			config: Config{
@@ -247,6 +270,118 @@ func TestTraefik_Collect(t *testing.T) {
 	}
 }
 
+func TestTraefik_Collect_RoutersServicesTLSCerts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(v2104Metrics)
+		}))
+	defer srv.Close()
+
+	tk := New()
+	tk.URL = srv.URL
+	tk.RouterSelector = matcher.SimpleExpr{Includes: []string{"* *"}}
+	tk.ServiceSelector = matcher.SimpleExpr{Excludes: []string{"* *@internal"}}
+	tk.MaxRouters = 2
+	require.True(t, tk.Init())
+
+	_ = tk.Collect()
+	mx := tk.Collect()
+	require.NotNil(t, mx)
+
+	expected := map[string]int64{
+		"entrypoint_requests_websecure_http_2xx":         1520,
+		"entrypoint_requests_websecure_http_4xx":         31,
+		"entrypoint_open_connections_websecure_http_GET": 5,
+		// the routers with the most requests
+		"router_websecure-whoami@docker_requests_2xx":                 1200,
+		"router_websecure-whoami@docker_requests_5xx":                 4,
+		"router_websecure-whoami@docker_request_duration_average_2xx": 0,
+		"router_websecure-whoami@docker_open_connections":             4,
+		"router_dashboard@internal_requests_2xx":                      316,
+		"router_dashboard@internal_open_connections":                  1,
+		// the rest of the routers
+		"router__other_requests_2xx":                         0,
+		"router__other_requests_4xx":                         31,
+		"router__other_request_duration_average_4xx":         0,
+		"service_whoami@docker_requests_2xx":                 1200,
+		"service_whoami@docker_requests_5xx":                 4,
+		"service_whoami@docker_request_duration_average_5xx": 0,
+		"service_whoami@docker_open_connections":             4,
+	}
+	for k, v := range expected {
+		assert.Equalf(t, v, mx[k], "metric '%s'", k)
+	}
+	assert.NotContains(t, mx, "router_api@internal_requests_4xx")
+	assert.NotContains(t, mx, "router__other_open_connections")
+	assert.NotContains(t, mx, "service_dashboard@internal_requests_2xx")
+	assert.NotContains(t, mx, "service__other_requests_2xx")
+
+	assert.InDelta(t, time.Until(time.Unix(1.9e9, 0)).Seconds(),
+		mx["tls_cert_3fd5a1c7e0b58d1a2f6e7c9b4d1e0a3f2b4c6d8e_time_until_expiration"], 60)
+	assert.InDelta(t, time.Until(time.Unix(2.0e9, 0)).Seconds(),
+		mx["tls_cert_118331936240573862733458154357914564203_time_until_expiration"], 60)
+
+	chart := tk.Charts().Get("router_websecure-whoami@docker_requests")
+	require.NotNil(t, chart)
+	assert.Equal(t, []module.Label{{Key: "router", Value: "websecure-whoami@docker"}}, chart.Labels)
+	assert.True(t, tk.Charts().Has("router_websecure-whoami@docker_open_connections"))
+	assert.False(t, tk.Charts().Has("router__other_open_connections"))
+	assert.True(t, tk.Charts().Has("service_whoami@docker_request_duration"))
+	chart = tk.Charts().Get("tls_cert_3fd5a1c7e0b58d1a2f6e7c9b4d1e0a3f2b4c6d8e_time_until_expiration")
+	require.NotNil(t, chart)
+	assert.Equal(t, []module.Label{
+		{Key: "cn", Value: "whoami.example.com"},
+		{Key: "sans", Value: "whoami.example.com"},
+		{Key: "serial", Value: "3fd5a1c7e0b58d1a2f6e7c9b4d1e0a3f2b4c6d8e"},
+	}, chart.Labels)
+
+	ensureCollectedHasAllChartsDimsVarsIDs(t, tk, mx)
+}
+
+func TestTraefik_Collect_RoutersRemovedOnReload(t *testing.T) {
+	var num int
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			num++
+			if num == 1 {
+				_, _ = w.Write(v2104Metrics)
+				return
+			}
+			// the dynamic configuration is reloaded: only the dashboard router is left, the cert is renewed
+			_, _ = w.Write([]byte(`
+traefik_router_requests_total{code="200",method="GET",protocol="http",router="dashboard@internal",service="dashboard@internal"} 320
+traefik_router_request_duration_seconds_sum{code="200",method="GET",protocol="http",router="dashboard@internal",service="dashboard@internal"} 3.75
+traefik_router_request_duration_seconds_count{code="200",method="GET",protocol="http",router="dashboard@internal",service="dashboard@internal"} 320
+traefik_tls_certs_not_after{cn="whoami.example.com",sans="whoami.example.com",serial="4a1c"} 1.95e+09
+`))
+		}))
+	defer srv.Close()
+
+	tk := New()
+	tk.URL = srv.URL
+	tk.RouterSelector = matcher.SimpleExpr{Includes: []string{"* *"}}
+	tk.MaxRouters = 2
+	require.True(t, tk.Init())
+
+	require.NotNil(t, tk.Collect())
+	mx := tk.Collect()
+	require.NotNil(t, mx)
+
+	assert.Equal(t, int64(125), mx["router_dashboard@internal_request_duration_average_2xx"])
+
+	for _, chart := range *tk.Charts() {
+		switch {
+		case strings.HasPrefix(chart.ID, "router_dashboard@internal_"),
+			chart.ID == "tls_cert_4a1c_time_until_expiration":
+			assert.Falsef(t, chart.Obsolete, "chart '%s' is removed", chart.ID)
+		case strings.HasPrefix(chart.ID, "router_"), strings.HasPrefix(chart.ID, "tls_cert_"):
+			assert.Truef(t, chart.Obsolete, "chart '%s' is not removed", chart.ID)
+		}
+	}
+	assert.Len(t, tk.cache.routers, 1)
+	assert.Len(t, tk.cache.tlsCerts, 1)
+}
+
 func prepareCaseTraefikV221Metrics(t *testing.T) (*Traefik, func()) {
 	t.Helper()
 	srv := httptest.NewServer(http.HandlerFunc(
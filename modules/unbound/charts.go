@@ -43,6 +43,7 @@ const (
 	prioCacheExpired
 	prioZeroTTL
 	prioCacheCount
+	prioCacheCollisions
 
 	prioReqListUsage
 	prioReqListCurUsage
@@ -109,8 +110,11 @@ func convertTotalChartToThread(chart *Chart, thread string, priority int) {
 		chart.Title,
 	)
 	chart.Fam = thread + "_stats"
-	chart.Ctx = "thread_" + chart.Ctx
+	chart.Ctx = strings.Replace(chart.Ctx, "unbound.", "unbound.thread_", 1)
 	chart.Priority = priority
+	chart.Labels = []module.Label{
+		{Key: "thread", Value: strings.TrimPrefix(thread, "thread")},
+	}
 	for _, dim := range chart.Dims {
 		dim.ID = strings.Replace(dim.ID, "total", thread, 1)
 	}
@@ -363,6 +367,18 @@ var (
 			{ID: "dnscrypt_shared_secret.cache.count", Name: "shared_secret"},
 		},
 	}
+	cacheCollisionsChart = Chart{
+		ID:       "cache_max_collisions",
+		Title:    "Cache Hash Table Max Collisions",
+		Units:    "collisions",
+		Fam:      "cache",
+		Ctx:      "unbound.cache_max_collisions",
+		Priority: prioCacheCollisions,
+		Dims: Dims{
+			{ID: "msg.cache.max_collisions", Name: "msg"},
+			{ID: "rrset.cache.max_collisions", Name: "rrset"},
+		},
+	}
 	queryTypeChart = Chart{
 		ID:       "queries_by_type",
 		Title:    "Queries By Type",
@@ -442,6 +458,13 @@ func (u *Unbound) updateCharts() {
 		u.extChartsCreated = true
 	}
 
+	if u.curCache.cacheCollisions && !u.collisionsCreated {
+		if err := u.Charts().Add(cacheCollisionsChart.Copy()); err != nil {
+			u.Warningf("add cache collisions chart: %v", err)
+		}
+		u.collisionsCreated = true
+	}
+
 	for v := range u.curCache.queryType {
 		if !u.cache.queryType[v] {
 			u.cache.queryType[v] = true
@@ -14,23 +14,74 @@ import (
 // https://docs.menandmice.com/display/MM/Unbound+request-list+demystified (request lists explanation)
 
 func (u *Unbound) collect() (map[string]int64, error) {
+	// the charts depend on the mode, it is detected before the first successful collection
+	if !u.cumulativeDetected {
+		u.detectCumulative()
+	}
+
 	stats, err := u.scrapeUnboundStats()
 	if err != nil {
 		return nil, err
 	}
+	u.cumulativeDetected = true
 
 	mx := u.collectStats(stats)
 	u.updateCharts()
 	return mx, nil
 }
 
+// detectCumulative sets the statistics mode to the 'statistics-cumulative' value unbound is running with,
+// the configuration file may be not readable or be changed after unbound started.
+func (u *Unbound) detectCumulative() {
+	const command = "UBCT1 get_option statistics-cumulative"
+
+	output, err := u.send(command)
+	if err != nil {
+		u.Debugf("cumulative mode detection: %v", err)
+		return
+	}
+	u.cumulativeDetected = true
+
+	var cumulative bool
+	switch output[0] {
+	case "yes":
+		cumulative = true
+	case "no":
+	default:
+		// error <descriptive text possible>
+		u.Debugf("cumulative mode detection: command '%s': '%s'", command, output[0])
+		return
+	}
+
+	if cumulative != u.Cumulative {
+		u.Infof("changing 'cumulative_stats': %v => %v (reported by unbound)", u.Cumulative, cumulative)
+		u.Cumulative = cumulative
+		u.charts = charts(u.Cumulative)
+	}
+}
+
 func (u *Unbound) scrapeUnboundStats() ([]entry, error) {
-	var output []string
 	var command = "UBCT1 stats"
 	if u.Cumulative {
 		command = "UBCT1 stats_noreset"
 	}
 
+	output, err := u.send(command)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output) == 1 {
+		// 	in case of error the first line of the response is: error <descriptive text possible> \n
+		return nil, fmt.Errorf("command '%s': '%s'", command, output[0])
+	}
+	return parseStatsOutput(output)
+}
+
+// send sends the command, unbound closes the remote control connection after the response.
+func (u *Unbound) send(command string) ([]string, error) {
+	var output []string
+
 	if err := u.client.Connect(); err != nil {
 		return nil, fmt.Errorf("failed to connect: %v", err)
 	}
@@ -44,14 +95,10 @@ func (u *Unbound) scrapeUnboundStats() ([]entry, error) {
 		return nil, fmt.Errorf("send command '%s': %w", command, err)
 	}
 
-	switch len(output) {
-	case 0:
+	if len(output) == 0 {
 		return nil, fmt.Errorf("command '%s': empty resopnse", command)
-	case 1:
-		// 	in case of error the first line of the response is: error <descriptive text possible> \n
-		return nil, fmt.Errorf("command '%s': '%s'", command, output[0])
 	}
-	return parseStatsOutput(output)
+	return output, nil
 }
 
 func (u *Unbound) collectStats(stats []entry) map[string]int64 {
@@ -132,6 +179,9 @@ func (u *Unbound) processStats(stats []entry, mul float64) map[string]int64 {
 		case e.hasPrefix("num.answer.rcode"):
 			v := extractAnswerRCode(e.key)
 			u.curCache.answerRCode[v] = true
+		case e.hasSuffix(".cache.max_collisions"):
+			// unbound v1.19.1+
+			u.curCache.cacheCollisions = true
 		}
 		mx[e.key] = int64(e.value)
 	}
@@ -202,6 +252,8 @@ type collectCache struct {
 	queryClass  map[string]bool
 	queryOpCode map[string]bool
 	answerRCode map[string]bool
+
+	cacheCollisions bool
 }
 
 func (c *collectCache) clear() {
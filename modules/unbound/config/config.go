@@ -16,6 +16,7 @@ type UnboundConfig struct {
 	useCert    string // control-use-cert
 	keyFile    string // control-key-file
 	certFile   string // control-cert-file
+	serverCert string // server-cert-file
 }
 
 func (c UnboundConfig) String() string {
@@ -27,10 +28,11 @@ func (c UnboundConfig) String() string {
 		`"control-port": '%s', `,
 		`"control-user-cert": '%s', `,
 		`"control-key-file": '%s', `,
-		`"control-cert-file": '%s'`,
+		`"control-cert-file": '%s', `,
+		`"server-cert-file": '%s'`,
 		"]",
 	}, "")
-	return fmt.Sprintf(format, c.cumulative, c.enable, c.iface, c.port, c.useCert, c.keyFile, c.certFile)
+	return fmt.Sprintf(format, c.cumulative, c.enable, c.iface, c.port, c.useCert, c.keyFile, c.certFile, c.serverCert)
 }
 
 func (c UnboundConfig) Empty() bool                      { return c == UnboundConfig{} }
@@ -41,6 +43,7 @@ func (c UnboundConfig) ControlPort() (string, bool)      { return c.port, c.port
 func (c UnboundConfig) ControlUseCert() (bool, bool)     { return c.useCert == "yes", c.useCert != "" }
 func (c UnboundConfig) ControlKeyFile() (string, bool)   { return c.keyFile, c.keyFile != "" }
 func (c UnboundConfig) ControlCertFile() (string, bool)  { return c.certFile, c.certFile != "" }
+func (c UnboundConfig) ServerCertFile() (string, bool)   { return c.serverCert, c.serverCert != "" }
 
 func fromOptions(options []option) *UnboundConfig {
 	cfg := &UnboundConfig{}
@@ -61,6 +64,8 @@ func fromOptions(options []option) *UnboundConfig {
 			cfg.keyFile = opt.value
 		case optCertFile:
 			cfg.certFile = opt.value
+		case optServerCertFile:
+			cfg.serverCert = opt.value
 		}
 	}
 	return cfg
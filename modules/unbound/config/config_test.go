@@ -170,3 +170,25 @@ func TestUnboundConfig_ControlCertFile(t *testing.T) {
 		})
 	}
 }
+
+func TestUnboundConfig_ServerCertFile(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantValue string
+		wantOK    bool
+	}{
+		{input: "/etc/unbound/unbound_server.pem", wantValue: "/etc/unbound/unbound_server.pem", wantOK: true},
+		{input: "", wantValue: "", wantOK: false},
+		{input: "some value", wantValue: "some value", wantOK: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			cfg := UnboundConfig{serverCert: test.input}
+
+			v, ok := cfg.ServerCertFile()
+			assert.Equal(t, test.wantValue, v)
+			assert.Equal(t, test.wantOK, ok)
+		})
+	}
+}
@@ -24,6 +24,7 @@ const (
 	optUseCert         = "control-use-cert"
 	optKeyFile         = "control-key-file"
 	optCertFile        = "control-cert-file"
+	optServerCertFile  = "server-cert-file"
 )
 
 func isOptionUsed(opt option) bool {
@@ -36,7 +37,8 @@ func isOptionUsed(opt option) bool {
 		optPort,
 		optUseCert,
 		optKeyFile,
-		optCertFile:
+		optCertFile,
+		optServerCertFile:
 		return true
 	}
 	return false
@@ -25,6 +25,7 @@ func TestParse(t *testing.T) {
 				useCert:    "yes",
 				keyFile:    "/etc/unbound/unbound_control_2.key",
 				certFile:   "/etc/unbound/unbound_control_2.pem",
+				serverCert: "/etc/unbound/unbound_server_2.pem",
 			},
 		},
 		"valid include-toplevel": {
@@ -37,6 +38,7 @@ func TestParse(t *testing.T) {
 				useCert:    "yes",
 				keyFile:    "/etc/unbound/unbound_control_2.key",
 				certFile:   "/etc/unbound/unbound_control_2.pem",
+				serverCert: "/etc/unbound/unbound_server_2.pem",
 			},
 		},
 		"valid glob include": {
@@ -49,6 +51,7 @@ func TestParse(t *testing.T) {
 				useCert:    "yes",
 				keyFile:    "/etc/unbound/unbound_control_2.key",
 				certFile:   "/etc/unbound/unbound_control_2.pem",
+				serverCert: "/etc/unbound/unbound_server_2.pem",
 			},
 		},
 		"non existent glob include": {
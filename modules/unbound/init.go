@@ -57,6 +57,11 @@ func (u *Unbound) applyConfig(cfg *config.UnboundConfig) {
 		u.Debugf("changing 'tls_cert': '%s' => '%s'", u.TLSCert, certFile)
 		u.TLSCert = certFile
 	}
+	// the server certificate is not needed (and may be not readable) if it is not verified
+	if serverCertFile, ok := cfg.ServerCertFile(); ok && !u.InsecureSkipVerify && serverCertFile != u.TLSCA {
+		u.Debugf("changing 'tls_ca': '%s' => '%s'", u.TLSCA, serverCertFile)
+		u.TLSCA = serverCertFile
+	}
 	if iface, ok := cfg.ControlInterface(); ok && adjustControlInterface(iface) != u.Address {
 		address := adjustControlInterface(iface)
 		u.Debugf("changing 'address': '%s' => '%s'", u.Address, address)
@@ -83,6 +88,14 @@ func (u *Unbound) initClient() (err error) {
 		if tlsCfg, err = tlscfg.NewTLSConfig(u.TLSConfig); err != nil {
 			return err
 		}
+		if verify := tlsCfg.VerifyConnection; verify != nil {
+			// unbound-control-setup creates the self-signed server certificate with the 'unbound' CN and no SANs,
+			// unbound-control verifies the chain against 'server-cert-file' and doesn't check the host name.
+			tlsCfg.VerifyConnection = func(cs tls.ConnectionState) error {
+				cs.ServerName = ""
+				return verify(cs)
+			}
+		}
 	}
 
 	u.client = socket.New(socket.Config{
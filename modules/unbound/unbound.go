@@ -61,8 +61,10 @@ type (
 		cache    collectCache
 		curCache collectCache
 
-		prevCacheMiss    float64 // needed for cumulative mode
-		extChartsCreated bool
+		prevCacheMiss      float64 // needed for cumulative mode
+		cumulativeDetected bool
+		extChartsCreated   bool
+		collisionsCreated  bool
 
 		charts *module.Charts
 	}
@@ -5,11 +5,22 @@ package unbound
 import (
 	"bufio"
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/socket"
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
@@ -78,6 +89,15 @@ func TestUnbound_Init_SetEverythingFromUnboundConf(t *testing.T) {
 	assert.Equal(t, expectedConfig, unbound.Config)
 }
 
+func TestUnbound_Init_ServerCertFromUnboundConf(t *testing.T) {
+	unbound := New()
+	unbound.ConfPath = "testdata/unbound.conf"
+	unbound.InsecureSkipVerify = false
+
+	assert.True(t, unbound.Init())
+	assert.Equal(t, "/etc/unbound/unbound_server_other.pem", unbound.TLSCA)
+}
+
 func TestUnbound_Init_DisabledInUnboundConf(t *testing.T) {
 	unbound := nonTLSUnbound()
 	unbound.ConfPath = "testdata/unbound_disabled.conf"
@@ -235,9 +255,120 @@ func TestUnbound_Collect_ErrorOnParseBadSyntax(t *testing.T) {
 	assert.Nil(t, unbound.Collect())
 }
 
+func TestUnbound_Collect_DetectCumulative(t *testing.T) {
+	unbound := nonTLSUnbound()
+	unbound.Cumulative = false
+	require.True(t, unbound.Init())
+	unbound.client = mockUnboundClient{data: lifeCycleCumulativeData1, cumulative: "yes"}
+
+	assert.Equal(t, expectedCumulative1, unbound.Collect())
+	assert.True(t, unbound.Cumulative)
+	assert.Equal(t, module.Incremental, unbound.Charts().Get(queriesChart.ID).Dims[0].Algo)
+}
+
+func TestUnbound_Collect_ThreadChartsLabels(t *testing.T) {
+	unbound := nonTLSUnbound()
+	require.True(t, unbound.Init())
+	unbound.client = mockUnboundClient{data: extStatsData, err: false}
+
+	require.NotNil(t, unbound.Collect())
+
+	chart := unbound.Charts().Get("thread1_" + queriesChart.ID)
+	require.NotNil(t, chart)
+	assert.Equal(t, "unbound.thread_queries", chart.Ctx)
+	assert.Equal(t, []module.Label{{Key: "thread", Value: "1"}}, chart.Labels)
+}
+
+func TestUnbound_Collect_TLSServerCertificate(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, serverKey := newSelfSignedCert(t, "unbound")
+	clientCert, clientKey := newSelfSignedCert(t, "unbound-control")
+	otherCert, _ := newSelfSignedCert(t, "unbound")
+	writeFile := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0600))
+		return path
+	}
+
+	cert, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAnyClientCert,
+	})
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			if _, err := bufio.NewReader(conn).ReadString('\n'); err == nil {
+				_, _ = conn.Write(commonStatsData)
+			}
+			_ = conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	tests := map[string]struct {
+		serverCert string
+		wantFail   bool
+	}{
+		"server certificate":       {serverCert: writeFile("unbound_server.pem", serverCert)},
+		"other server certificate": {serverCert: writeFile("other_server.pem", otherCert), wantFail: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			unbound := New()
+			unbound.ConfPath = ""
+			// the certificate has no SANs, the host name is not checked
+			unbound.Address = net.JoinHostPort("localhost", port)
+			unbound.TLSCA = test.serverCert
+			unbound.TLSCert = writeFile("unbound_control.pem", clientCert)
+			unbound.TLSKey = writeFile("unbound_control.key", clientKey)
+			unbound.InsecureSkipVerify = false
+			require.True(t, unbound.Init())
+
+			if test.wantFail {
+				assert.Nil(t, unbound.Collect())
+			} else {
+				assert.Equal(t, expectedCommon, unbound.Collect())
+			}
+		})
+	}
+}
+
+// newSelfSignedCert creates the certificate the way unbound-control-setup does: the CN only, no SANs.
+func newSelfSignedCert(t *testing.T, cn string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
 type mockUnboundClient struct {
 	data []byte
 	err  bool
+	// 'get_option statistics-cumulative' response, unbound doesn't know the option if empty
+	cumulative string
 }
 
 func (m mockUnboundClient) Connect() error {
@@ -248,10 +379,18 @@ func (m mockUnboundClient) Disconnect() error {
 	return nil
 }
 
-func (m mockUnboundClient) Command(_ string, process socket.Processor) error {
+func (m mockUnboundClient) Command(command string, process socket.Processor) error {
 	if m.err {
 		return errors.New("mock send error")
 	}
+	if strings.HasPrefix(command, "UBCT1 get_option statistics-cumulative") {
+		if m.cumulative == "" {
+			process([]byte("error unknown option"))
+		} else {
+			process([]byte(m.cumulative))
+		}
+		return nil
+	}
 	s := bufio.NewScanner(bytes.NewReader(m.data))
 	for s.Scan() {
 		process(s.Bytes())
@@ -399,6 +538,8 @@ var (
 		"dnscrypt_shared_secret.cache.count":         0,
 		"infra.cache.count":                          205,
 		"key.cache.count":                            9,
+		"msg.cache.max_collisions":                   2,
+		"rrset.cache.max_collisions":                 3,
 		"mem.cache.dnscrypt_nonce":                   0,
 		"mem.cache.dnscrypt_shared_secret":           0,
 		"mem.cache.message":                          90357,
@@ -22,6 +22,7 @@ const (
 	prioVmNetworkPackets
 	prioVmNetworkDrops
 	prioVmOverallStatus
+	prioVmTriggeredAlarms
 	prioVmSystemUptime
 
 	prioHostCPUUtilization
@@ -35,7 +36,19 @@ const (
 	prioHostNetworkDrops
 	prioHostNetworkErrors
 	prioHostOverallStatus
+	prioHostTriggeredAlarms
 	prioHostSystemUptime
+
+	prioDatastoreSpaceUtilization
+	prioDatastoreSpaceUsage
+	prioDatastoreIOPS
+	prioDatastoreIO
+	prioDatastoreLatency
+	prioDatastoreOverallStatus
+
+	prioResourcePoolCPUUsage
+	prioResourcePoolMemoryUsage
+	prioResourcePoolOverallStatus
 )
 
 var (
@@ -200,6 +213,21 @@ var (
 		},
 	}
 
+	// added only if alarms collection is enabled
+	vmTriggeredAlarmsChartTmpl = module.Chart{
+		ID:       "%s_triggered_alarms",
+		Title:    "Virtual Machine triggered alarms",
+		Units:    "alarms",
+		Fam:      "vms status",
+		Ctx:      "vsphere.vm_triggered_alarms",
+		Priority: prioVmTriggeredAlarms,
+		Dims: module.Dims{
+			{ID: "%s_alarms.triggered.red", Name: "red"},
+			{ID: "%s_alarms.triggered.yellow", Name: "yellow"},
+			{ID: "%s_alarms.acknowledged", Name: "acknowledged"},
+		},
+	}
+
 	vmSystemUptimeChartTmpl = module.Chart{
 		ID:       "%s_system_uptime",
 		Title:    "Virtual Machine system uptime",
@@ -373,6 +401,20 @@ var (
 			{ID: "%s_overall.status.gray", Name: "gray"},
 		},
 	}
+	// added only if alarms collection is enabled
+	hostTriggeredAlarmsChartTmpl = module.Chart{
+		ID:       "%s_triggered_alarms",
+		Title:    "ESXi Host triggered alarms",
+		Units:    "alarms",
+		Fam:      "hosts status",
+		Ctx:      "vsphere.host_triggered_alarms",
+		Priority: prioHostTriggeredAlarms,
+		Dims: module.Dims{
+			{ID: "%s_alarms.triggered.red", Name: "red"},
+			{ID: "%s_alarms.triggered.yellow", Name: "yellow"},
+			{ID: "%s_alarms.acknowledged", Name: "acknowledged"},
+		},
+	}
 	hostSystemUptimeChartTmpl = module.Chart{
 		ID:       "%s_system_uptime",
 		Title:    "ESXi Host system uptime",
@@ -386,6 +428,145 @@ var (
 	}
 )
 
+var (
+	datastoreChartsTmpl = module.Charts{
+		datastoreSpaceUtilizationChartTmpl.Copy(),
+		datastoreSpaceUsageChartTmpl.Copy(),
+
+		datastoreIOPSChartTmpl.Copy(),
+		datastoreIOChartTmpl.Copy(),
+		datastoreLatencyChartTmpl.Copy(),
+
+		datastoreOverallStatusChartTmpl.Copy(),
+	}
+	datastoreSpaceUtilizationChartTmpl = module.Chart{
+		ID:       "%s_space_utilization",
+		Title:    "Datastore space utilization",
+		Units:    "percentage",
+		Fam:      "datastores space",
+		Ctx:      "vsphere.datastore_space_utilization",
+		Priority: prioDatastoreSpaceUtilization,
+		Dims: module.Dims{
+			{ID: "%s_space.utilization", Name: "used", Div: 100},
+		},
+	}
+	datastoreSpaceUsageChartTmpl = module.Chart{
+		ID:       "%s_space_usage",
+		Title:    "Datastore space usage",
+		Units:    "bytes",
+		Fam:      "datastores space",
+		Ctx:      "vsphere.datastore_space_usage",
+		Type:     module.Stacked,
+		Priority: prioDatastoreSpaceUsage,
+		Dims: module.Dims{
+			{ID: "%s_space.free", Name: "free"},
+			{ID: "%s_space.used", Name: "used"},
+		},
+	}
+	datastoreIOPSChartTmpl = module.Chart{
+		ID:       "%s_iops",
+		Title:    "Datastore IOPS",
+		Units:    "operations/s",
+		Fam:      "datastores io",
+		Ctx:      "vsphere.datastore_iops",
+		Priority: prioDatastoreIOPS,
+		Dims: module.Dims{
+			{ID: "%s_datastore.numberReadAveraged.average", Name: "read"},
+			{ID: "%s_datastore.numberWriteAveraged.average", Name: "write", Mul: -1},
+		},
+	}
+	datastoreIOChartTmpl = module.Chart{
+		ID:       "%s_io",
+		Title:    "Datastore IO",
+		Units:    "KiB/s",
+		Fam:      "datastores io",
+		Ctx:      "vsphere.datastore_io",
+		Type:     module.Area,
+		Priority: prioDatastoreIO,
+		Dims: module.Dims{
+			{ID: "%s_datastore.read.average", Name: "read"},
+			{ID: "%s_datastore.write.average", Name: "write", Mul: -1},
+		},
+	}
+	datastoreLatencyChartTmpl = module.Chart{
+		ID:       "%s_latency",
+		Title:    "Datastore latency",
+		Units:    "milliseconds",
+		Fam:      "datastores io",
+		Ctx:      "vsphere.datastore_latency",
+		Priority: prioDatastoreLatency,
+		Dims: module.Dims{
+			{ID: "%s_datastore.totalReadLatency.average", Name: "read"},
+			{ID: "%s_datastore.totalWriteLatency.average", Name: "write"},
+		},
+	}
+	datastoreOverallStatusChartTmpl = module.Chart{
+		ID:       "%s_overall_status",
+		Title:    "Datastore overall alarm status",
+		Units:    "status",
+		Fam:      "datastores status",
+		Ctx:      "vsphere.datastore_overall_status",
+		Priority: prioDatastoreOverallStatus,
+		Dims: module.Dims{
+			{ID: "%s_overall.status.green", Name: "green"},
+			{ID: "%s_overall.status.red", Name: "red"},
+			{ID: "%s_overall.status.yellow", Name: "yellow"},
+			{ID: "%s_overall.status.gray", Name: "gray"},
+		},
+	}
+)
+
+var (
+	resourcePoolChartsTmpl = module.Charts{
+		resourcePoolCPUUsageChartTmpl.Copy(),
+		resourcePoolMemUsageChartTmpl.Copy(),
+
+		resourcePoolOverallStatusChartTmpl.Copy(),
+	}
+	resourcePoolCPUUsageChartTmpl = module.Chart{
+		ID:       "%s_cpu_usage",
+		Title:    "Resource pool CPU usage",
+		Units:    "MHz",
+		Fam:      "resource pools cpu",
+		Ctx:      "vsphere.resource_pool_cpu_usage",
+		Priority: prioResourcePoolCPUUsage,
+		Dims: module.Dims{
+			{ID: "%s_cpu.usagemhz.average", Name: "used"},
+		},
+	}
+	resourcePoolMemUsageChartTmpl = module.Chart{
+		ID:       "%s_mem_usage",
+		Title:    "Resource pool memory usage",
+		Units:    "KiB",
+		Fam:      "resource pools mem",
+		Ctx:      "vsphere.resource_pool_mem_usage",
+		Priority: prioResourcePoolMemoryUsage,
+		Dims: module.Dims{
+			{ID: "%s_mem.granted.average", Name: "granted"},
+			{ID: "%s_mem.consumed.average", Name: "consumed"},
+			{ID: "%s_mem.active.average", Name: "active"},
+		},
+	}
+	resourcePoolOverallStatusChartTmpl = module.Chart{
+		ID:       "%s_overall_status",
+		Title:    "Resource pool overall alarm status",
+		Units:    "status",
+		Fam:      "resource pools status",
+		Ctx:      "vsphere.resource_pool_overall_status",
+		Priority: prioResourcePoolOverallStatus,
+		Dims: module.Dims{
+			{ID: "%s_overall.status.green", Name: "green"},
+			{ID: "%s_overall.status.red", Name: "red"},
+			{ID: "%s_overall.status.yellow", Name: "yellow"},
+			{ID: "%s_overall.status.gray", Name: "gray"},
+		},
+	}
+
+	// added only if the pool has a limit
+	resourcePoolCPULimitDimTmpl = module.Dim{ID: "%s_cpu.limit", Name: "limit"}
+	resourcePoolMemLimitDimTmpl = module.Dim{ID: "%s_mem.limit", Name: "limit"}
+)
+
 const failedUpdatesLimit = 10
 
 func (vs *VSphere) updateCharts() {
@@ -403,7 +584,7 @@ func (vs *VSphere) updateCharts() {
 		}
 
 		vs.charted[id] = true
-		charts := newHostCharts(host)
+		charts := newHostCharts(host, vs.CollectAlarms)
 		if err := vs.Charts().Add(*charts...); err != nil {
 			vs.Error(err)
 		}
@@ -423,15 +604,58 @@ func (vs *VSphere) updateCharts() {
 		}
 
 		vs.charted[id] = true
-		charts := newVMCHarts(vm)
+		charts := newVMCHarts(vm, vs.CollectAlarms)
+		if err := vs.Charts().Add(*charts...); err != nil {
+			vs.Error(err)
+		}
+	}
+
+	for id, fails := range vs.discoveredDatastores {
+		if fails >= failedUpdatesLimit {
+			vs.removeFromCharts(id)
+			delete(vs.charted, id)
+			delete(vs.discoveredDatastores, id)
+			continue
+		}
+
+		ds := vs.resources.Datastores.Get(id)
+		if ds == nil || vs.charted[id] || fails != 0 {
+			continue
+		}
+
+		vs.charted[id] = true
+		charts := newDatastoreCharts(ds)
+		if err := vs.Charts().Add(*charts...); err != nil {
+			vs.Error(err)
+		}
+	}
+
+	for id, fails := range vs.discoveredResourcePools {
+		if fails >= failedUpdatesLimit {
+			vs.removeFromCharts(id)
+			delete(vs.charted, id)
+			delete(vs.discoveredResourcePools, id)
+			continue
+		}
+
+		rp := vs.resources.ResourcePools.Get(id)
+		if rp == nil || vs.charted[id] || fails != 0 {
+			continue
+		}
+
+		vs.charted[id] = true
+		charts := newResourcePoolCharts(rp)
 		if err := vs.Charts().Add(*charts...); err != nil {
 			vs.Error(err)
 		}
 	}
 }
 
-func newVMCHarts(vm *rs.VM) *module.Charts {
+func newVMCHarts(vm *rs.VM, collectAlarms bool) *module.Charts {
 	charts := vmChartsTmpl.Copy()
+	if collectAlarms {
+		_ = charts.Add(vmTriggeredAlarmsChartTmpl.Copy())
+	}
 
 	for _, chart := range *charts {
 		chart.ID = fmt.Sprintf(chart.ID, vm.ID)
@@ -456,8 +680,11 @@ func getVMClusterName(vm *rs.VM) string {
 	return vm.Hier.Cluster.Name
 }
 
-func newHostCharts(host *rs.Host) *module.Charts {
+func newHostCharts(host *rs.Host, collectAlarms bool) *module.Charts {
 	charts := hostChartsTmpl.Copy()
+	if collectAlarms {
+		_ = charts.Add(hostTriggeredAlarmsChartTmpl.Copy())
+	}
 
 	for _, chart := range *charts {
 		chart.ID = fmt.Sprintf(chart.ID, host.ID)
@@ -482,9 +709,54 @@ func getHostClusterName(host *rs.Host) string {
 	return host.Hier.Cluster.Name
 }
 
-func (vs *VSphere) removeFromCharts(prefix string) {
+func newDatastoreCharts(ds *rs.Datastore) *module.Charts {
+	charts := datastoreChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		chart.ID = fmt.Sprintf(chart.ID, ds.ID)
+		chart.Labels = []module.Label{
+			{Key: "datacenter", Value: ds.Hier.DC.Name},
+			{Key: "datastore", Value: ds.Name},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, ds.ID)
+		}
+	}
+
+	return charts
+}
+
+func newResourcePoolCharts(rp *rs.ResourcePool) *module.Charts {
+	charts := resourcePoolChartsTmpl.Copy()
+
+	for _, chart := range *charts {
+		switch {
+		case chart.ID == resourcePoolCPUUsageChartTmpl.ID && rp.CPULimit >= 0:
+			dim := resourcePoolCPULimitDimTmpl
+			chart.Dims = append(chart.Dims, &dim)
+		case chart.ID == resourcePoolMemUsageChartTmpl.ID && rp.MemoryLimit >= 0:
+			dim := resourcePoolMemLimitDimTmpl
+			chart.Dims = append(chart.Dims, &dim)
+		}
+
+		chart.ID = fmt.Sprintf(chart.ID, rp.ID)
+		chart.Labels = []module.Label{
+			{Key: "datacenter", Value: rp.Hier.DC.Name},
+			{Key: "cluster", Value: rp.Hier.Cluster.Name},
+			{Key: "resource_pool", Value: rp.Name},
+		}
+		for _, dim := range chart.Dims {
+			dim.ID = fmt.Sprintf(dim.ID, rp.ID)
+		}
+	}
+
+	return charts
+}
+
+func (vs *VSphere) removeFromCharts(id string) {
 	for _, c := range *vs.Charts() {
-		if strings.HasPrefix(c.ID, prefix) {
+		// chart ids are '<id>_<name>', 'host-1' is a prefix of 'host-10'
+		if strings.HasPrefix(c.ID, id+"_") {
 			c.MarkRemove()
 			c.MarkNotCreated()
 		}
@@ -26,6 +26,8 @@ const (
 	computeResource = "ComputeResource"
 	hostSystem      = "HostSystem"
 	virtualMachine  = "VirtualMachine"
+	datastore       = "Datastore"
+	resourcePool    = "ResourcePool"
 
 	maxIdleConnections = 32
 )
@@ -175,6 +177,41 @@ func (c *Client) VirtualMachines(pathSet ...string) (vms []mo.VirtualMachine, er
 	return
 }
 
+// DatastoresAndResourcePools retrieves both object types in a single RetrieveProperties call.
+func (c *Client) DatastoresAndResourcePools(dsPathSet, rpPathSet []string) ([]mo.Datastore, []mo.ResourcePool, error) {
+	rpSpec := types.PropertySpec{Type: resourcePool, PathSet: rpPathSet}
+	if len(rpPathSet) == 0 {
+		rpSpec.All = types.NewBool(true)
+	}
+
+	var content []types.ObjectContent
+	err := c.root.Retrieve(context.Background(), []string{datastore}, dsPathSet, &content, rpSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dsContent, rpContent []types.ObjectContent
+	for _, oc := range content {
+		// subtypes (e.g. VirtualApp) are not collected
+		switch oc.Obj.Type {
+		case datastore:
+			dsContent = append(dsContent, oc)
+		case resourcePool:
+			rpContent = append(rpContent, oc)
+		}
+	}
+
+	var dss []mo.Datastore
+	if err := mo.LoadObjectContent(dsContent, &dss); err != nil {
+		return nil, nil, err
+	}
+	var rps []mo.ResourcePool
+	if err := mo.LoadObjectContent(rpContent, &rps); err != nil {
+		return nil, nil, err
+	}
+	return dss, rps, nil
+}
+
 func (c *Client) CounterInfoByName() (map[string]*types.PerfCounterInfo, error) {
 	return c.perf.CounterInfoByName(context.Background())
 }
@@ -122,6 +122,27 @@ func TestClient_VirtualMachines(t *testing.T) {
 	assert.NotEmpty(t, vms)
 }
 
+func TestClient_DatastoresAndResourcePools(t *testing.T) {
+	client, teardown := prepareClient(t)
+	defer teardown()
+
+	dss, rps, err := client.DatastoresAndResourcePools(
+		[]string{"name", "summary.capacity"},
+		[]string{"name", "owner"},
+	)
+	assert.NoError(t, err)
+	require.NotEmpty(t, dss)
+	require.NotEmpty(t, rps)
+	for _, ds := range dss {
+		assert.NotEmpty(t, ds.Name)
+		assert.True(t, ds.Summary.Capacity > 0)
+	}
+	for _, rp := range rps {
+		assert.NotEmpty(t, rp.Name)
+		assert.NotEmpty(t, rp.Owner.Value)
+	}
+}
+
 func TestClient_PerformanceMetrics(t *testing.T) {
 	client, teardown := prepareClient(t)
 	defer teardown()
@@ -33,6 +33,9 @@ func (vs *VSphere) collect() (map[string]int64, error) {
 		return nil, err
 	}
 
+	vs.collectDatastores(mx)
+	vs.collectResourcePools(mx)
+
 	vs.updateCharts()
 
 	vs.Debugf("metrics collected, process took %s", time.Since(t))
@@ -64,6 +67,9 @@ func (vs *VSphere) collectHostsMetrics(mx map[string]int64, metrics []performanc
 		if host := vs.resources.Hosts.Get(metric.Entity.Value); host != nil {
 			vs.discoveredHosts[host.ID] = 0
 			writeHostMetrics(mx, host, metric.Value)
+			if vs.CollectAlarms {
+				writeTriggeredAlarms(mx, host.ID, host.TriggeredAlarms)
+			}
 		}
 	}
 }
@@ -105,6 +111,9 @@ func (vs *VSphere) collectVMsMetrics(mx map[string]int64, metrics []performance.
 	for _, metric := range metrics {
 		if vm := vs.resources.VMs.Get(metric.Entity.Value); vm != nil {
 			writeVMMetrics(mx, vm, metric.Value)
+			if vs.CollectAlarms {
+				writeTriggeredAlarms(mx, vm.ID, vm.TriggeredAlarms)
+			}
 			vs.discoveredVMs[vm.ID] = 0
 		}
 	}
@@ -124,6 +133,103 @@ func writeVMMetrics(mx map[string]int64, vm *rs.VM, metrics []performance.Metric
 	}
 }
 
+// writeTriggeredAlarms writes the alarms counts, they are refreshed on discovery.
+func writeTriggeredAlarms(mx map[string]int64, id string, alarms rs.AlarmCounts) {
+	mx[id+"_alarms.triggered.red"] = alarms.Red
+	mx[id+"_alarms.triggered.yellow"] = alarms.Yellow
+	mx[id+"_alarms.acknowledged"] = alarms.Acknowledged
+}
+
+// collectDatastores writes the capacity (refreshed on discovery) of every discovered datastore,
+// the performance metrics are optional: they depend on the vCenter statistics level.
+func (vs *VSphere) collectDatastores(mx map[string]int64) {
+	if len(vs.resources.Datastores) == 0 {
+		return
+	}
+	for id := range vs.discoveredDatastores {
+		vs.discoveredDatastores[id]++
+	}
+
+	for _, ds := range vs.resources.Datastores {
+		vs.discoveredDatastores[ds.ID] = 0
+		writeDatastoreSpace(mx, ds)
+	}
+
+	metrics := vs.ScrapeDatastores(vs.resources.Datastores)
+	if len(metrics) == 0 {
+		vs.Debug("no datastores performance metrics")
+		return
+	}
+	for _, metric := range metrics {
+		if ds := vs.resources.Datastores.Get(metric.Entity.Value); ds != nil {
+			writeEntityMetrics(mx, ds.ID, metric.Value)
+		}
+	}
+}
+
+func writeDatastoreSpace(mx map[string]int64, ds *rs.Datastore) {
+	used := ds.Capacity - ds.FreeSpace
+	mx[ds.ID+"_space.used"] = used
+	mx[ds.ID+"_space.free"] = ds.FreeSpace
+	if ds.Capacity > 0 {
+		mx[ds.ID+"_space.utilization"] = used * 10000 / ds.Capacity
+	} else {
+		mx[ds.ID+"_space.utilization"] = 0
+	}
+	for _, v := range overallStatuses {
+		mx[fmt.Sprintf("%s_overall.status.%s", ds.ID, v)] = boolToInt(ds.OverallStatus == v)
+	}
+}
+
+// collectResourcePools writes the limits (refreshed on discovery) of every discovered resource pool
+// and the usage from the performance manager.
+func (vs *VSphere) collectResourcePools(mx map[string]int64) {
+	if len(vs.resources.ResourcePools) == 0 {
+		return
+	}
+	for id := range vs.discoveredResourcePools {
+		vs.discoveredResourcePools[id]++
+	}
+
+	for _, rp := range vs.resources.ResourcePools {
+		vs.discoveredResourcePools[rp.ID] = 0
+		writeResourcePoolLimits(mx, rp)
+	}
+
+	metrics := vs.ScrapeResourcePools(vs.resources.ResourcePools)
+	if len(metrics) == 0 {
+		vs.Debug("no resource pools performance metrics")
+		return
+	}
+	for _, metric := range metrics {
+		if rp := vs.resources.ResourcePools.Get(metric.Entity.Value); rp != nil {
+			writeEntityMetrics(mx, rp.ID, metric.Value)
+		}
+	}
+}
+
+func writeResourcePoolLimits(mx map[string]int64, rp *rs.ResourcePool) {
+	if rp.CPULimit >= 0 {
+		mx[rp.ID+"_cpu.limit"] = rp.CPULimit
+	}
+	if rp.MemoryLimit >= 0 {
+		// MB => KiB, the same units as the memory usage counters
+		mx[rp.ID+"_mem.limit"] = rp.MemoryLimit * 1024
+	}
+	for _, v := range overallStatuses {
+		mx[fmt.Sprintf("%s_overall.status.%s", rp.ID, v)] = boolToInt(rp.OverallStatus == v)
+	}
+}
+
+func writeEntityMetrics(mx map[string]int64, id string, metrics []performance.MetricSeries) {
+	for _, metric := range metrics {
+		if len(metric.Value) == 0 || metric.Value[0] == -1 {
+			continue
+		}
+		mx[fmt.Sprintf("%s_%s", id, metric.Name)] = metric.Value[0]
+	}
+}
+
 func boolToInt(v bool) int64 {
 	if v {
 		return 1
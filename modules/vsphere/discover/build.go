@@ -8,6 +8,7 @@ import (
 	rs "github.com/netdata/go.d.plugin/modules/vsphere/resources"
 
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 func (d Discoverer) build(raw *resources) *rs.Resources {
@@ -21,8 +22,11 @@ func (d Discoverer) build(raw *resources) *rs.Resources {
 	fixClustersParentID(&res)
 	res.Hosts = d.buildHosts(raw.hosts)
 	res.VMs = d.buildVMs(raw.vms)
+	res.Datastores = d.buildDatastores(raw.dss)
+	res.ResourcePools = d.buildResourcePools(raw.rps)
 
-	d.Infof("discovering : building : built %d/%d dcs, %d/%d folders, %d/%d clusters, %d/%d hosts, %d/%d vms, process took %s",
+	d.Infof("discovering : building : built %d/%d dcs, %d/%d folders, %d/%d clusters, %d/%d hosts, %d/%d vms, "+
+		"%d/%d datastores, %d/%d resource pools, process took %s",
 		len(res.DataCenters),
 		len(raw.dcs),
 		len(res.Folders),
@@ -33,6 +37,10 @@ func (d Discoverer) build(raw *resources) *rs.Resources {
 		len(raw.hosts),
 		len(res.VMs),
 		len(raw.vms),
+		len(res.Datastores),
+		len(raw.dss),
+		len(res.ResourcePools),
+		len(raw.rps),
 		time.Since(t),
 	)
 	return &res
@@ -139,11 +147,12 @@ func newHost(raw mo.HostSystem) *rs.Host {
 	// 192.168.0.202 host-28 domain-c52
 	// 192.168.0.203 host-33 domain-c52
 	return &rs.Host{
-		Name:          raw.Name,
-		ID:            raw.Reference().Value,
-		ParentID:      raw.Parent.Value,
-		OverallStatus: string(raw.Summary.OverallStatus),
-		Ref:           raw.Reference(),
+		Name:            raw.Name,
+		ID:              raw.Reference().Value,
+		ParentID:        raw.Parent.Value,
+		OverallStatus:   string(raw.Summary.OverallStatus),
+		TriggeredAlarms: countTriggeredAlarms(raw.Reference(), raw.TriggeredAlarmState),
+		Ref:             raw.Reference(),
 	}
 }
 
@@ -171,10 +180,100 @@ func (d Discoverer) buildVMs(raw []mo.VirtualMachine) rs.VMs {
 func newVM(raw mo.VirtualMachine) *rs.VM {
 	// deb91 vm-25 group-v3 host-22
 	return &rs.VM{
+		Name:            raw.Name,
+		ID:              raw.Reference().Value,
+		ParentID:        raw.Runtime.Host.Value,
+		OverallStatus:   string(raw.Summary.OverallStatus),
+		TriggeredAlarms: countTriggeredAlarms(raw.Reference(), raw.TriggeredAlarmState),
+		Ref:             raw.Reference(),
+	}
+}
+
+func countTriggeredAlarms(ref types.ManagedObjectReference, states []types.AlarmState) rs.AlarmCounts {
+	var counts rs.AlarmCounts
+	for _, st := range states {
+		// the entity state includes the alarms triggered on its descendants (e.g. a host VMs)
+		if st.Entity != ref {
+			continue
+		}
+		if st.Acknowledged != nil && *st.Acknowledged {
+			counts.Acknowledged++
+			continue
+		}
+		switch st.OverallStatus {
+		case types.ManagedEntityStatusRed:
+			counts.Red++
+		case types.ManagedEntityStatusYellow:
+			counts.Yellow++
+		}
+	}
+	return counts
+}
+
+func (d Discoverer) buildDatastores(raw []mo.Datastore) rs.Datastores {
+	var num int
+	dss := make(rs.Datastores)
+	for _, ds := range raw {
+		// capacity and free space are not valid for an inaccessible datastore
+		if !ds.Summary.Accessible {
+			num++
+			continue
+		}
+		dss.Put(newDatastore(ds))
+	}
+	if num > 0 {
+		d.Infof("discovering : building : removed %d datastores (not accessible)", num)
+	}
+	return dss
+}
+
+func newDatastore(raw mo.Datastore) *rs.Datastore {
+	// LocalDS_0 datastore-54 group-s5
+	var parentID string
+	if raw.Parent != nil {
+		parentID = raw.Parent.Value
+	}
+	return &rs.Datastore{
+		Name:          raw.Name,
+		ID:            raw.Reference().Value,
+		ParentID:      parentID,
+		OverallStatus: string(raw.OverallStatus),
+		Capacity:      raw.Summary.Capacity,
+		FreeSpace:     raw.Summary.FreeSpace,
+		Ref:           raw.Reference(),
+	}
+}
+
+func (Discoverer) buildResourcePools(raw []mo.ResourcePool) rs.ResourcePools {
+	rps := make(rs.ResourcePools)
+	for _, rp := range raw {
+		rps.Put(newResourcePool(rp))
+	}
+	return rps
+}
+
+func newResourcePool(raw mo.ResourcePool) *rs.ResourcePool {
+	// Resources resgroup-26 domain-c27 domain-c27
+	// Pool1 resgroup-52 resgroup-26 domain-c27
+	var parentID string
+	if raw.Parent != nil {
+		parentID = raw.Parent.Value
+	}
+	return &rs.ResourcePool{
 		Name:          raw.Name,
 		ID:            raw.Reference().Value,
-		ParentID:      raw.Runtime.Host.Value,
-		OverallStatus: string(raw.Summary.OverallStatus),
+		ParentID:      parentID,
+		OwnerID:       raw.Owner.Value,
+		OverallStatus: string(raw.OverallStatus),
+		CPULimit:      allocationLimit(raw.Config.CpuAllocation.Limit),
+		MemoryLimit:   allocationLimit(raw.Config.MemoryAllocation.Limit),
 		Ref:           raw.Reference(),
 	}
 }
+
+func allocationLimit(limit *int64) int64 {
+	if limit == nil {
+		return -1
+	}
+	return *limit
+}
@@ -21,6 +21,7 @@ type Client interface {
 	ComputeResources(pathSet ...string) ([]mo.ComputeResource, error)
 	Hosts(pathSet ...string) ([]mo.HostSystem, error)
 	VirtualMachines(pathSet ...string) ([]mo.VirtualMachine, error)
+	DatastoresAndResourcePools(dsPathSet, rpPathSet []string) ([]mo.Datastore, []mo.ResourcePool, error)
 
 	CounterInfoByName() (map[string]*types.PerfCounterInfo, error)
 }
@@ -36,6 +37,10 @@ type Discoverer struct {
 	Client
 	match.HostMatcher
 	match.VMMatcher
+	match.DatastoreMatcher
+	match.ResourcePoolMatcher
+	// CollectAlarms adds the triggered alarms to the hosts and VMs properties.
+	CollectAlarms bool
 }
 
 type resources struct {
@@ -44,6 +49,8 @@ type resources struct {
 	clusters []mo.ComputeResource
 	hosts    []mo.HostSystem
 	vms      []mo.VirtualMachine
+	dss      []mo.Datastore
+	rps      []mo.ResourcePool
 }
 
 func (d Discoverer) Discover() (*rs.Resources, error) {
@@ -63,9 +70,12 @@ func (d Discoverer) Discover() (*rs.Resources, error) {
 
 	numH := len(res.Hosts)
 	numV := len(res.VMs)
+	numDS := len(res.Datastores)
+	numRP := len(res.ResourcePools)
 	removed := d.removeUnmatched(res)
-	if removed == (numH + numV) {
-		return nil, fmt.Errorf("all resoursces were filtered (%d hosts, %d vms)", numH, numV)
+	if removed == (numH + numV + numDS + numRP) {
+		return nil, fmt.Errorf("all resoursces were filtered (%d hosts, %d vms, %d datastores, %d resource pools)",
+			numH, numV, numDS, numRP)
 	}
 
 	err = d.collectMetricLists(res)
@@ -73,11 +83,15 @@ func (d Discoverer) Discover() (*rs.Resources, error) {
 		return nil, fmt.Errorf("collecting metric lists : %v", err)
 	}
 
-	d.Infof("discovering : discovered %d/%d hosts, %d/%d vms, the whole process took %s",
+	d.Infof("discovering : discovered %d/%d hosts, %d/%d vms, %d/%d datastores, %d/%d resource pools, the whole process took %s",
 		len(res.Hosts),
 		len(raw.hosts),
 		len(res.VMs),
 		len(raw.vms),
+		len(res.Datastores),
+		len(raw.dss),
+		len(res.ResourcePools),
+		len(raw.rps),
 		time.Since(startTime))
 
 	return res, nil
@@ -90,6 +104,10 @@ var (
 	clusterPathSet    = []string{"name", "parent"}
 	hostPathSet       = []string{"name", "parent", "runtime.powerState", "summary.overallStatus"}
 	vmPathSet         = []string{"name", "runtime.host", "runtime.powerState", "summary.overallStatus"}
+	datastorePathSet  = []string{"name", "parent", "overallStatus", "summary.accessible", "summary.capacity", "summary.freeSpace"}
+	rpPathSet         = []string{"name", "parent", "owner", "overallStatus", "config.cpuAllocation.limit", "config.memoryAllocation.limit"}
+
+	triggeredAlarmStatePath = "triggeredAlarmState"
 )
 
 func (d Discoverer) discover() (*resources, error) {
@@ -117,35 +135,53 @@ func (d Discoverer) discover() (*resources, error) {
 	}
 	d.Debugf("discovering : found %d clusters, process took %s", len(clusters), time.Since(t))
 
+	hps, vps := hostPathSet, vmPathSet
+	if d.CollectAlarms {
+		hps = append(append([]string{}, hostPathSet...), triggeredAlarmStatePath)
+		vps = append(append([]string{}, vmPathSet...), triggeredAlarmStatePath)
+	}
+
 	t = time.Now()
-	hosts, err := d.Hosts(hostPathSet...)
+	hosts, err := d.Hosts(hps...)
 	if err != nil {
 		return nil, err
 	}
 	d.Debugf("discovering : found %d hosts, process took %s", len(hosts), time.Since(t))
 
 	t = time.Now()
-	vms, err := d.VirtualMachines(vmPathSet...)
+	vms, err := d.VirtualMachines(vps...)
 	if err != nil {
 		return nil, err
 	}
 	d.Debugf("discovering : found %d vms, process took %s", len(hosts), time.Since(t))
 
+	t = time.Now()
+	// both are fetched in a single round trip
+	dss, rps, err := d.DatastoresAndResourcePools(datastorePathSet, rpPathSet)
+	if err != nil {
+		return nil, err
+	}
+	d.Debugf("discovering : found %d datastores, %d resource pools, process took %s", len(dss), len(rps), time.Since(t))
+
 	raw := resources{
 		dcs:      datacenters,
 		folders:  folders,
 		clusters: clusters,
 		hosts:    hosts,
 		vms:      vms,
+		dss:      dss,
+		rps:      rps,
 	}
 
-	d.Infof("discovering : found %d dcs, %d folders, %d clusters (%d dummy), %d hosts, %d vms, process took %s",
+	d.Infof("discovering : found %d dcs, %d folders, %d clusters (%d dummy), %d hosts, %d vms, %d datastores, %d resource pools, process took %s",
 		len(raw.dcs),
 		len(raw.folders),
 		len(clusters),
 		numOfDummyClusters(clusters),
 		len(raw.hosts),
 		len(raw.vms),
+		len(raw.dss),
+		len(raw.rps),
 		time.Since(start),
 	)
 
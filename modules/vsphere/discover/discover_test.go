@@ -29,6 +29,8 @@ func TestDiscoverer_Discover(t *testing.T) {
 	assert.True(t, len(res.Clusters) > 0)
 	assert.True(t, len(res.Hosts) > 0)
 	assert.True(t, len(res.VMs) > 0)
+	assert.True(t, len(res.Datastores) > 0)
+	assert.True(t, len(res.ResourcePools) > 0)
 	assert.True(t, isHierarchySet(res))
 	assert.True(t, isMetricListsCollected(res))
 }
@@ -47,6 +49,8 @@ func TestDiscoverer_discover(t *testing.T) {
 	assert.Lenf(t, raw.clusters, count.Cluster+dummyClusters, "clusters")
 	assert.Lenf(t, raw.hosts, count.Host, "hosts")
 	assert.Lenf(t, raw.vms, count.Machine, "hosts")
+	assert.Lenf(t, raw.dss, count.Datastore, "datastores")
+	assert.Lenf(t, raw.rps, count.Pool, "resource pools")
 }
 
 func TestDiscoverer_build(t *testing.T) {
@@ -63,6 +67,8 @@ func TestDiscoverer_build(t *testing.T) {
 	assert.Lenf(t, res.Clusters, len(raw.clusters), "clusters")
 	assert.Lenf(t, res.Hosts, len(raw.hosts), "hosts")
 	assert.Lenf(t, res.VMs, len(raw.vms), "hosts")
+	assert.Lenf(t, res.Datastores, len(raw.dss), "datastores")
+	assert.Lenf(t, res.ResourcePools, len(raw.rps), "resource pools")
 }
 
 func TestDiscoverer_setHierarchy(t *testing.T) {
@@ -85,14 +91,19 @@ func TestDiscoverer_removeUnmatched(t *testing.T) {
 
 	d.HostMatcher = falseHostMatcher{}
 	d.VMMatcher = falseVMMatcher{}
+	d.DatastoreMatcher = falseDatastoreMatcher{}
+	d.ResourcePoolMatcher = falseResourcePoolMatcher{}
 	raw, err := d.discover()
 	require.NoError(t, err)
 	res := d.build(raw)
 
 	numVMs, numHosts := len(res.VMs), len(res.Hosts)
-	assert.Equal(t, numVMs+numHosts, d.removeUnmatched(res))
+	numDSs, numRPs := len(res.Datastores), len(res.ResourcePools)
+	assert.Equal(t, numVMs+numHosts+numDSs+numRPs, d.removeUnmatched(res))
 	assert.Lenf(t, res.Hosts, 0, "hosts")
 	assert.Lenf(t, res.VMs, 0, "vms")
+	assert.Lenf(t, res.Datastores, 0, "datastores")
+	assert.Lenf(t, res.ResourcePools, 0, "resource pools")
 }
 
 func TestDiscoverer_collectMetricLists(t *testing.T) {
@@ -131,6 +142,8 @@ func newClient(t *testing.T, vCenterURL *url.URL) *client.Client {
 
 func createSim(t *testing.T) (*simulator.Model, *simulator.Server) {
 	model := simulator.VPX()
+	model.Datastore = 2
+	model.Pool = 2
 	err := model.Create()
 	require.NoError(t, err)
 	model.Service.TLS = new(tls.Config)
@@ -153,6 +166,16 @@ func isHierarchySet(res *rs.Resources) bool {
 			return false
 		}
 	}
+	for _, ds := range res.Datastores {
+		if !ds.Hier.IsSet() {
+			return false
+		}
+	}
+	for _, rp := range res.ResourcePools {
+		if !rp.Hier.IsSet() {
+			return false
+		}
+	}
 	return true
 }
 
@@ -167,6 +190,16 @@ func isMetricListsCollected(res *rs.Resources) bool {
 			return false
 		}
 	}
+	for _, ds := range res.Datastores {
+		if ds.MetricList == nil {
+			return false
+		}
+	}
+	for _, rp := range res.ResourcePools {
+		if rp.MetricList == nil {
+			return false
+		}
+	}
 	return true
 }
 
@@ -177,3 +210,11 @@ func (falseHostMatcher) Match(*rs.Host) bool { return false }
 type falseVMMatcher struct{}
 
 func (falseVMMatcher) Match(*rs.VM) bool { return false }
+
+type falseDatastoreMatcher struct{}
+
+func (falseDatastoreMatcher) Match(*rs.Datastore) bool { return false }
+
+type falseResourcePoolMatcher struct{}
+
+func (falseResourcePoolMatcher) Match(*rs.ResourcePool) bool { return false }
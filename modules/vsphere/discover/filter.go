@@ -22,17 +22,38 @@ func (d Discoverer) matchVM(vm *rs.VM) bool {
 	return d.VMMatcher.Match(vm)
 }
 
+func (d Discoverer) matchDatastore(ds *rs.Datastore) bool {
+	if d.DatastoreMatcher == nil {
+		return true
+	}
+	return d.DatastoreMatcher.Match(ds)
+}
+
+func (d Discoverer) matchResourcePool(rp *rs.ResourcePool) bool {
+	if d.ResourcePoolMatcher == nil {
+		return true
+	}
+	return d.ResourcePoolMatcher.Match(rp)
+}
+
 func (d Discoverer) removeUnmatched(res *rs.Resources) (removed int) {
 	d.Debug("discovering : filtering : starting filtering resources process")
 	t := time.Now()
 	numH, numV := len(res.Hosts), len(res.VMs)
+	numDS, numRP := len(res.Datastores), len(res.ResourcePools)
 	removed += d.removeUnmatchedHosts(res.Hosts)
 	removed += d.removeUnmatchedVMs(res.VMs)
-	d.Infof("discovering : filtering : filtered %d/%d hosts, %d/%d vms, process took %s",
+	removed += d.removeUnmatchedDatastores(res.Datastores)
+	removed += d.removeUnmatchedResourcePools(res.ResourcePools)
+	d.Infof("discovering : filtering : filtered %d/%d hosts, %d/%d vms, %d/%d datastores, %d/%d resource pools, process took %s",
 		numH-len(res.Hosts),
 		numH,
 		numV-len(res.VMs),
 		numV,
+		numDS-len(res.Datastores),
+		numDS,
+		numRP-len(res.ResourcePools),
+		numRP,
 		time.Since(t))
 	return
 }
@@ -58,3 +79,25 @@ func (d Discoverer) removeUnmatchedVMs(vms rs.VMs) (removed int) {
 	d.Debugf("discovering : filtering : removed %d unmatched vms", removed)
 	return removed
 }
+
+func (d Discoverer) removeUnmatchedDatastores(dss rs.Datastores) (removed int) {
+	for _, v := range dss {
+		if !d.matchDatastore(v) {
+			removed++
+			dss.Remove(v.ID)
+		}
+	}
+	d.Debugf("discovering : filtering : removed %d unmatched datastores", removed)
+	return removed
+}
+
+func (d Discoverer) removeUnmatchedResourcePools(rps rs.ResourcePools) (removed int) {
+	for _, v := range rps {
+		if !d.matchResourcePool(v) {
+			removed++
+			rps.Remove(v.ID)
+		}
+	}
+	d.Debugf("discovering : filtering : removed %d unmatched resource pools", removed)
+	return removed
+}
@@ -15,12 +15,16 @@ func (d Discoverer) setHierarchy(res *rs.Resources) error {
 	c := d.setClustersHierarchy(res)
 	h := d.setHostsHierarchy(res)
 	v := d.setVMsHierarchy(res)
+	ds := d.setDatastoresHierarchy(res)
+	rp := d.setResourcePoolsHierarchy(res)
 
 	// notSet := len(res.Clusters) + len(res.Hosts) + len(res.VMs) - (c + h + v)
-	d.Infof("discovering : hierarchy : set %d/%d clusters, %d/%d hosts, %d/%d vms, process took %s",
+	d.Infof("discovering : hierarchy : set %d/%d clusters, %d/%d hosts, %d/%d vms, %d/%d datastores, %d/%d resource pools, process took %s",
 		c, len(res.Clusters),
 		h, len(res.Hosts),
 		v, len(res.VMs),
+		ds, len(res.Datastores),
+		rp, len(res.ResourcePools),
 		time.Since(t),
 	)
 
@@ -54,6 +58,24 @@ func (d Discoverer) setVMsHierarchy(res *rs.Resources) (set int) {
 	return set
 }
 
+func (d Discoverer) setDatastoresHierarchy(res *rs.Resources) (set int) {
+	for _, ds := range res.Datastores {
+		if setDatastoreHierarchy(ds, res) {
+			set++
+		}
+	}
+	return set
+}
+
+func (d Discoverer) setResourcePoolsHierarchy(res *rs.Resources) (set int) {
+	for _, rp := range res.ResourcePools {
+		if setResourcePoolHierarchy(rp, res) {
+			set++
+		}
+	}
+	return set
+}
+
 func setClusterHierarchy(cluster *rs.Cluster, res *rs.Resources) bool {
 	dc := res.DataCenters.Get(cluster.ParentID)
 	if dc == nil {
@@ -98,3 +120,28 @@ func setVMHierarchy(vm *rs.VM, res *rs.Resources) bool {
 	vm.Hier.DC.Set(dc.ID, dc.Name)
 	return vm.Hier.IsSet()
 }
+
+func setDatastoreHierarchy(ds *rs.Datastore, res *rs.Resources) bool {
+	// datastore parent is the datacenter datastore folder or a nested folder
+	dc := res.DataCenters.Get(findClusterDcID(ds.ParentID, res.Folders))
+	if dc == nil {
+		return false
+	}
+	ds.Hier.DC.Set(dc.ID, dc.Name)
+	return ds.Hier.IsSet()
+}
+
+func setResourcePoolHierarchy(rp *rs.ResourcePool, res *rs.Resources) bool {
+	cr := res.Clusters.Get(rp.OwnerID)
+	if cr == nil {
+		return false
+	}
+	rp.Hier.Cluster.Set(cr.ID, cr.Name)
+
+	dc := res.DataCenters.Get(cr.ParentID)
+	if dc == nil {
+		return false
+	}
+	rp.Hier.DC.Set(dc.ID, dc.Name)
+	return rp.Hier.IsSet()
+}
@@ -28,12 +28,24 @@ func (d Discoverer) collectMetricLists(res *rs.Resources) error {
 	for _, v := range res.VMs {
 		v.MetricList = vmML
 	}
+	dsML := simpleDatastoreMetricList(perfCounters)
+	for _, ds := range res.Datastores {
+		ds.MetricList = dsML
+	}
+	rpML := simpleResourcePoolMetricList(perfCounters)
+	for _, rp := range res.ResourcePools {
+		rp.MetricList = rpML
+	}
 
-	d.Infof("discovering : metric lists : collected metric lists for %d/%d hosts, %d/%d vms, process took %s",
+	d.Infof("discovering : metric lists : collected metric lists for %d/%d hosts, %d/%d vms, %d/%d datastores, %d/%d resource pools, process took %s",
 		len(res.Hosts),
 		len(res.Hosts),
 		len(res.VMs),
 		len(res.VMs),
+		len(res.Datastores),
+		len(res.Datastores),
+		len(res.ResourcePools),
+		len(res.ResourcePools),
 		time.Since(t),
 	)
 
@@ -48,6 +60,14 @@ func simpleVMMetricList(pci map[string]*types.PerfCounterInfo) performance.Metri
 	return simpleMetricList(vmMetrics, pci)
 }
 
+func simpleDatastoreMetricList(pci map[string]*types.PerfCounterInfo) performance.MetricList {
+	return simpleMetricList(datastoreMetrics, pci)
+}
+
+func simpleResourcePoolMetricList(pci map[string]*types.PerfCounterInfo) performance.MetricList {
+	return simpleMetricList(resourcePoolMetrics, pci)
+}
+
 func simpleMetricList(metrics []string, pci map[string]*types.PerfCounterInfo) performance.MetricList {
 	sort.Strings(metrics)
 
@@ -132,4 +152,22 @@ var (
 
 		"sys.uptime.latest",
 	}
+
+	// datastores and resource pools have no real-time statistics, only the historical (5 minutes) ones
+	datastoreMetrics = []string{
+		"datastore.numberReadAveraged.average",
+		"datastore.numberWriteAveraged.average",
+		"datastore.read.average",
+		"datastore.write.average",
+		"datastore.totalReadLatency.average",
+		"datastore.totalWriteLatency.average",
+	}
+
+	resourcePoolMetrics = []string{
+		"cpu.usagemhz.average",
+
+		"mem.consumed.average",
+		"mem.active.average",
+		"mem.granted.average",
+	}
 )
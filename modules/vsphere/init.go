@@ -39,6 +39,7 @@ func (vs *VSphere) initClient() (*client.Client, error) {
 func (vs *VSphere) initDiscoverer(c *client.Client) error {
 	d := discover.New(c)
 	d.Logger = vs.Logger
+	d.CollectAlarms = vs.CollectAlarms
 
 	hm, err := vs.HostsInclude.Parse()
 	if err != nil {
@@ -54,6 +55,20 @@ func (vs *VSphere) initDiscoverer(c *client.Client) error {
 	if vmm != nil {
 		d.VMMatcher = vmm
 	}
+	dsm, err := vs.DatastoresInclude.Parse()
+	if err != nil {
+		return err
+	}
+	if dsm != nil {
+		d.DatastoreMatcher = dsm
+	}
+	rpm, err := vs.ResourcePoolsInclude.Parse()
+	if err != nil {
+		return err
+	}
+	if rpm != nil {
+		d.ResourcePoolMatcher = rpm
+	}
 
 	vs.discoverer = d
 	return nil
@@ -18,6 +18,14 @@ type VMMatcher interface {
 	Match(*rs.VM) bool
 }
 
+type DatastoreMatcher interface {
+	Match(*rs.Datastore) bool
+}
+
+type ResourcePoolMatcher interface {
+	Match(*rs.ResourcePool) bool
+}
+
 type (
 	hostDCMatcher      struct{ m matcher.Matcher }
 	hostClusterMatcher struct{ m matcher.Matcher }
@@ -30,6 +38,16 @@ type (
 	orVMMatcher        struct{ lhs, rhs VMMatcher }
 	andHostMatcher     struct{ lhs, rhs HostMatcher }
 	andVMMatcher       struct{ lhs, rhs VMMatcher }
+
+	datastoreDCMatcher        struct{ m matcher.Matcher }
+	datastoreDatastoreMatcher struct{ m matcher.Matcher }
+	rpDCMatcher               struct{ m matcher.Matcher }
+	rpClusterMatcher          struct{ m matcher.Matcher }
+	rpResourcePoolMatcher     struct{ m matcher.Matcher }
+	orDatastoreMatcher        struct{ lhs, rhs DatastoreMatcher }
+	orResourcePoolMatcher     struct{ lhs, rhs ResourcePoolMatcher }
+	andDatastoreMatcher       struct{ lhs, rhs DatastoreMatcher }
+	andResourcePoolMatcher    struct{ lhs, rhs ResourcePoolMatcher }
 )
 
 func (m hostDCMatcher) Match(host *rs.Host) bool      { return m.m.MatchString(host.Hier.DC.Name) }
@@ -44,6 +62,22 @@ func (m orVMMatcher) Match(vm *rs.VM) bool            { return m.lhs.Match(vm) |
 func (m andHostMatcher) Match(host *rs.Host) bool     { return m.lhs.Match(host) && m.rhs.Match(host) }
 func (m andVMMatcher) Match(vm *rs.VM) bool           { return m.lhs.Match(vm) && m.rhs.Match(vm) }
 
+func (m datastoreDCMatcher) Match(ds *rs.Datastore) bool        { return m.m.MatchString(ds.Hier.DC.Name) }
+func (m datastoreDatastoreMatcher) Match(ds *rs.Datastore) bool { return m.m.MatchString(ds.Name) }
+func (m rpDCMatcher) Match(rp *rs.ResourcePool) bool            { return m.m.MatchString(rp.Hier.DC.Name) }
+func (m rpClusterMatcher) Match(rp *rs.ResourcePool) bool {
+	return m.m.MatchString(rp.Hier.Cluster.Name)
+}
+func (m rpResourcePoolMatcher) Match(rp *rs.ResourcePool) bool { return m.m.MatchString(rp.Name) }
+func (m orDatastoreMatcher) Match(ds *rs.Datastore) bool       { return m.lhs.Match(ds) || m.rhs.Match(ds) }
+func (m orResourcePoolMatcher) Match(rp *rs.ResourcePool) bool {
+	return m.lhs.Match(rp) || m.rhs.Match(rp)
+}
+func (m andDatastoreMatcher) Match(ds *rs.Datastore) bool { return m.lhs.Match(ds) && m.rhs.Match(ds) }
+func (m andResourcePoolMatcher) Match(rp *rs.ResourcePool) bool {
+	return m.lhs.Match(rp) && m.rhs.Match(rp)
+}
+
 func newAndHostMatcher(lhs, rhs HostMatcher, others ...HostMatcher) andHostMatcher {
 	m := andHostMatcher{lhs: lhs, rhs: rhs}
 	switch len(others) {
@@ -84,9 +118,51 @@ func newOrVMMatcher(lhs, rhs VMMatcher, others ...VMMatcher) orVMMatcher {
 	}
 }
 
+func newAndDatastoreMatcher(lhs, rhs DatastoreMatcher, others ...DatastoreMatcher) andDatastoreMatcher {
+	m := andDatastoreMatcher{lhs: lhs, rhs: rhs}
+	switch len(others) {
+	case 0:
+		return m
+	default:
+		return newAndDatastoreMatcher(m, others[0], others[1:]...)
+	}
+}
+
+func newAndResourcePoolMatcher(lhs, rhs ResourcePoolMatcher, others ...ResourcePoolMatcher) andResourcePoolMatcher {
+	m := andResourcePoolMatcher{lhs: lhs, rhs: rhs}
+	switch len(others) {
+	case 0:
+		return m
+	default:
+		return newAndResourcePoolMatcher(m, others[0], others[1:]...)
+	}
+}
+
+func newOrDatastoreMatcher(lhs, rhs DatastoreMatcher, others ...DatastoreMatcher) orDatastoreMatcher {
+	m := orDatastoreMatcher{lhs: lhs, rhs: rhs}
+	switch len(others) {
+	case 0:
+		return m
+	default:
+		return newOrDatastoreMatcher(m, others[0], others[1:]...)
+	}
+}
+
+func newOrResourcePoolMatcher(lhs, rhs ResourcePoolMatcher, others ...ResourcePoolMatcher) orResourcePoolMatcher {
+	m := orResourcePoolMatcher{lhs: lhs, rhs: rhs}
+	switch len(others) {
+	case 0:
+		return m
+	default:
+		return newOrResourcePoolMatcher(m, others[0], others[1:]...)
+	}
+}
+
 type (
-	VMIncludes   []string
-	HostIncludes []string
+	VMIncludes           []string
+	HostIncludes         []string
+	DatastoreIncludes    []string
+	ResourcePoolIncludes []string
 )
 
 func (vi VMIncludes) Parse() (VMMatcher, error) {
@@ -135,6 +211,52 @@ func (hi HostIncludes) Parse() (HostMatcher, error) {
 	}
 }
 
+func (di DatastoreIncludes) Parse() (DatastoreMatcher, error) {
+	var ms []DatastoreMatcher
+	for _, v := range di {
+		m, err := parseDatastoreInclude(v)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			continue
+		}
+		ms = append(ms, m)
+	}
+
+	switch len(ms) {
+	case 0:
+		return nil, nil
+	case 1:
+		return ms[0], nil
+	default:
+		return newOrDatastoreMatcher(ms[0], ms[1], ms[2:]...), nil
+	}
+}
+
+func (ri ResourcePoolIncludes) Parse() (ResourcePoolMatcher, error) {
+	var ms []ResourcePoolMatcher
+	for _, v := range ri {
+		m, err := parseResourcePoolInclude(v)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			continue
+		}
+		ms = append(ms, m)
+	}
+
+	switch len(ms) {
+	case 0:
+		return nil, nil
+	case 1:
+		return ms[0], nil
+	default:
+		return newOrResourcePoolMatcher(ms[0], ms[1], ms[2:]...), nil
+	}
+}
+
 const (
 	datacenterIdx = iota
 	clusterIdx
@@ -142,6 +264,11 @@ const (
 	vmIdx
 )
 
+const (
+	datastoreIdx    = 1 // /dc/datastore
+	resourcePoolIdx = 2 // /dc/cluster/pool, nested pools are matched by name
+)
+
 func cleanInclude(include string) string {
 	return strings.Trim(include, "/")
 }
@@ -216,6 +343,72 @@ func parseVMInclude(include string) (VMMatcher, error) {
 	}
 }
 
+func parseDatastoreInclude(include string) (DatastoreMatcher, error) {
+	if !isIncludeFormatValid(include) {
+		return nil, fmt.Errorf("bad include format: %s", include)
+	}
+
+	include = cleanInclude(include)
+	parts := strings.Split(include, "/") // /dc/datastoreIdx
+	var ms []DatastoreMatcher
+
+	for i, v := range parts {
+		m, err := parseSubInclude(v)
+		if err != nil {
+			return nil, err
+		}
+		switch i {
+		case datacenterIdx:
+			ms = append(ms, datastoreDCMatcher{m})
+		case datastoreIdx:
+			ms = append(ms, datastoreDatastoreMatcher{m})
+		}
+	}
+
+	switch len(ms) {
+	case 0:
+		return nil, nil
+	case 1:
+		return ms[0], nil
+	default:
+		return newAndDatastoreMatcher(ms[0], ms[1], ms[2:]...), nil
+	}
+}
+
+func parseResourcePoolInclude(include string) (ResourcePoolMatcher, error) {
+	if !isIncludeFormatValid(include) {
+		return nil, fmt.Errorf("bad include format: %s", include)
+	}
+
+	include = cleanInclude(include)
+	parts := strings.Split(include, "/") // /dc/clusterIdx/resourcePoolIdx
+	var ms []ResourcePoolMatcher
+
+	for i, v := range parts {
+		m, err := parseSubInclude(v)
+		if err != nil {
+			return nil, err
+		}
+		switch i {
+		case datacenterIdx:
+			ms = append(ms, rpDCMatcher{m})
+		case clusterIdx:
+			ms = append(ms, rpClusterMatcher{m})
+		case resourcePoolIdx:
+			ms = append(ms, rpResourcePoolMatcher{m})
+		}
+	}
+
+	switch len(ms) {
+	case 0:
+		return nil, nil
+	case 1:
+		return ms[0], nil
+	default:
+		return newAndResourcePoolMatcher(ms[0], ms[1], ms[2:]...), nil
+	}
+}
+
 func parseSubInclude(sub string) (matcher.Matcher, error) {
 	sub = strings.TrimSpace(sub)
 	if sub == "" || sub == "!*" {
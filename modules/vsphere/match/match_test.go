@@ -277,6 +277,96 @@ func TestVMIncludes_Parse(t *testing.T) {
 	}
 }
 
+func TestDatastoreIncludes_Parse(t *testing.T) {
+	tests := map[string]struct {
+		valid    bool
+		expected DatastoreMatcher
+	}{
+		"":      {valid: false},
+		"*/DS1": {valid: false},
+		"/*":    {valid: true, expected: datastoreDCMatcher{matcher.TRUE()}},
+		"/!*":   {valid: true, expected: datastoreDCMatcher{matcher.FALSE()}},
+		"/*/DS*/*": {
+			valid: true,
+			expected: andDatastoreMatcher{
+				lhs: datastoreDCMatcher{matcher.TRUE()},
+				rhs: datastoreDatastoreMatcher{mustSP("DS*")},
+			},
+		},
+		"[/DC1*,/DC2*/DS*]": {
+			valid: true,
+			expected: orDatastoreMatcher{
+				lhs: datastoreDCMatcher{mustSP("DC1*")},
+				rhs: andDatastoreMatcher{
+					lhs: datastoreDCMatcher{mustSP("DC2*")},
+					rhs: datastoreDatastoreMatcher{mustSP("DS*")},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			includes := prepareIncludes(name)
+			m, err := DatastoreIncludes(includes).Parse()
+
+			if !test.valid {
+				assert.Error(t, err)
+			} else {
+				assert.Equal(t, test.expected, m)
+			}
+		})
+	}
+}
+
+func TestResourcePoolIncludes_Parse(t *testing.T) {
+	tests := map[string]struct {
+		valid    bool
+		expected ResourcePoolMatcher
+	}{
+		"":         {valid: false},
+		"*/C1/RP1": {valid: false},
+		"/*":       {valid: true, expected: rpDCMatcher{matcher.TRUE()}},
+		"/!*":      {valid: true, expected: rpDCMatcher{matcher.FALSE()}},
+		"/*/C1*/*": {
+			valid: true,
+			expected: andResourcePoolMatcher{
+				lhs: andResourcePoolMatcher{
+					lhs: rpDCMatcher{matcher.TRUE()},
+					rhs: rpClusterMatcher{mustSP("C1*")},
+				},
+				rhs: rpResourcePoolMatcher{matcher.TRUE()},
+			},
+		},
+		"[/DC1*,/*/*/!Resources *]": {
+			valid: true,
+			expected: orResourcePoolMatcher{
+				lhs: rpDCMatcher{mustSP("DC1*")},
+				rhs: andResourcePoolMatcher{
+					lhs: andResourcePoolMatcher{
+						lhs: rpDCMatcher{matcher.TRUE()},
+						rhs: rpClusterMatcher{matcher.TRUE()},
+					},
+					rhs: rpResourcePoolMatcher{mustSP("!Resources *")},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			includes := prepareIncludes(name)
+			m, err := ResourcePoolIncludes(includes).Parse()
+
+			if !test.valid {
+				assert.Error(t, err)
+			} else {
+				assert.Equal(t, test.expected, m)
+			}
+		})
+	}
+}
+
 func prepareIncludes(include string) []string {
 	trimmed := strings.Trim(include, "[]")
 	return strings.Split(trimmed, ",")
@@ -44,11 +44,13 @@ Virtual Datacenter Architecture Representation (partial).
 */
 
 type Resources struct {
-	DataCenters DataCenters
-	Folders     Folders
-	Clusters    Clusters
-	Hosts       Hosts
-	VMs         VMs
+	DataCenters   DataCenters
+	Folders       Folders
+	Clusters      Clusters
+	Hosts         Hosts
+	VMs           VMs
+	Datastores    Datastores
+	ResourcePools ResourcePools
 }
 
 type (
@@ -67,6 +69,13 @@ type (
 		ID, Name string
 	}
 
+	// AlarmCounts is the number of the alarms triggered on the entity itself.
+	AlarmCounts struct {
+		Red          int64 // not acknowledged
+		Yellow       int64 // not acknowledged
+		Acknowledged int64
+	}
+
 	ClusterHierarchy struct {
 		DC HierarchyValue
 	}
@@ -82,27 +91,61 @@ type (
 		Cluster HierarchyValue
 	}
 	Host struct {
+		Name            string
+		ID              string
+		ParentID        string
+		Hier            HostHierarchy
+		OverallStatus   string
+		TriggeredAlarms AlarmCounts
+		MetricList      performance.MetricList
+		Ref             types.ManagedObjectReference
+	}
+
+	VMHierarchy struct {
+		DC      HierarchyValue
+		Cluster HierarchyValue
+		Host    HierarchyValue
+	}
+
+	VM struct {
+		Name            string
+		ID              string
+		ParentID        string
+		Hier            VMHierarchy
+		OverallStatus   string
+		TriggeredAlarms AlarmCounts
+		MetricList      performance.MetricList
+		Ref             types.ManagedObjectReference
+	}
+
+	DatastoreHierarchy struct {
+		DC HierarchyValue
+	}
+	Datastore struct {
 		Name          string
 		ID            string
 		ParentID      string
-		Hier          HostHierarchy
+		Hier          DatastoreHierarchy
 		OverallStatus string
+		Capacity      int64 // bytes
+		FreeSpace     int64 // bytes
 		MetricList    performance.MetricList
 		Ref           types.ManagedObjectReference
 	}
 
-	VMHierarchy struct {
+	ResourcePoolHierarchy struct {
 		DC      HierarchyValue
 		Cluster HierarchyValue
-		Host    HierarchyValue
 	}
-
-	VM struct {
+	ResourcePool struct {
 		Name          string
 		ID            string
 		ParentID      string
-		Hier          VMHierarchy
+		OwnerID       string // the cluster (compute resource) the pool belongs to
+		Hier          ResourcePoolHierarchy
 		OverallStatus string
+		CPULimit      int64 // MHz, -1 if unlimited
+		MemoryLimit   int64 // MB, -1 if unlimited
 		MetricList    performance.MetricList
 		Ref           types.ManagedObjectReference
 	}
@@ -111,27 +154,37 @@ type (
 func (v HierarchyValue) IsSet() bool          { return v.ID != "" && v.Name != "" }
 func (v *HierarchyValue) Set(id, name string) { v.ID = id; v.Name = name }
 
-func (h ClusterHierarchy) IsSet() bool { return h.DC.IsSet() }
-func (h HostHierarchy) IsSet() bool    { return h.DC.IsSet() && h.Cluster.IsSet() }
-func (h VMHierarchy) IsSet() bool      { return h.DC.IsSet() && h.Cluster.IsSet() && h.Host.IsSet() }
+func (h ClusterHierarchy) IsSet() bool      { return h.DC.IsSet() }
+func (h HostHierarchy) IsSet() bool         { return h.DC.IsSet() && h.Cluster.IsSet() }
+func (h VMHierarchy) IsSet() bool           { return h.DC.IsSet() && h.Cluster.IsSet() && h.Host.IsSet() }
+func (h DatastoreHierarchy) IsSet() bool    { return h.DC.IsSet() }
+func (h ResourcePoolHierarchy) IsSet() bool { return h.DC.IsSet() && h.Cluster.IsSet() }
 
 type (
-	DataCenters map[string]*Datacenter
-	Folders     map[string]*Folder
-	Clusters    map[string]*Cluster
-	Hosts       map[string]*Host
-	VMs         map[string]*VM
+	DataCenters   map[string]*Datacenter
+	Folders       map[string]*Folder
+	Clusters      map[string]*Cluster
+	Hosts         map[string]*Host
+	VMs           map[string]*VM
+	Datastores    map[string]*Datastore
+	ResourcePools map[string]*ResourcePool
 )
 
-func (dcs DataCenters) Put(dc *Datacenter)        { dcs[dc.ID] = dc }
-func (dcs DataCenters) Get(id string) *Datacenter { return dcs[id] }
-func (fs Folders) Put(folder *Folder)             { fs[folder.ID] = folder }
-func (fs Folders) Get(id string) *Folder          { return fs[id] }
-func (cs Clusters) Put(cluster *Cluster)          { cs[cluster.ID] = cluster }
-func (cs Clusters) Get(id string) *Cluster        { return cs[id] }
-func (hs Hosts) Put(host *Host)                   { hs[host.ID] = host }
-func (hs Hosts) Remove(id string)                 { delete(hs, id) }
-func (hs Hosts) Get(id string) *Host              { return hs[id] }
-func (vs VMs) Put(vm *VM)                         { vs[vm.ID] = vm }
-func (vs VMs) Remove(id string)                   { delete(vs, id) }
-func (vs VMs) Get(id string) *VM                  { return vs[id] }
+func (dcs DataCenters) Put(dc *Datacenter)           { dcs[dc.ID] = dc }
+func (dcs DataCenters) Get(id string) *Datacenter    { return dcs[id] }
+func (fs Folders) Put(folder *Folder)                { fs[folder.ID] = folder }
+func (fs Folders) Get(id string) *Folder             { return fs[id] }
+func (cs Clusters) Put(cluster *Cluster)             { cs[cluster.ID] = cluster }
+func (cs Clusters) Get(id string) *Cluster           { return cs[id] }
+func (hs Hosts) Put(host *Host)                      { hs[host.ID] = host }
+func (hs Hosts) Remove(id string)                    { delete(hs, id) }
+func (hs Hosts) Get(id string) *Host                 { return hs[id] }
+func (vs VMs) Put(vm *VM)                            { vs[vm.ID] = vm }
+func (vs VMs) Remove(id string)                      { delete(vs, id) }
+func (vs VMs) Get(id string) *VM                     { return vs[id] }
+func (ds Datastores) Put(d *Datastore)               { ds[d.ID] = d }
+func (ds Datastores) Remove(id string)               { delete(ds, id) }
+func (ds Datastores) Get(id string) *Datastore       { return ds[id] }
+func (ps ResourcePools) Put(p *ResourcePool)         { ps[p.ID] = p }
+func (ps ResourcePools) Remove(id string)            { delete(ps, id) }
+func (ps ResourcePools) Get(id string) *ResourcePool { return ps[id] }
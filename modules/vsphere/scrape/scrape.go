@@ -67,6 +67,30 @@ func (c Scraper) ScrapeVMs(vms rs.VMs) []performance.EntityMetric {
 	return ms
 }
 
+func (c Scraper) ScrapeDatastores(dss rs.Datastores) []performance.EntityMetric {
+	t := time.Now()
+	pqs := newDatastoresPerfQuerySpecs(dss)
+	ms := c.scrapeMetrics(pqs)
+	c.Debugf("scraping : scraped metrics for %d/%d datastores, process took %s",
+		len(ms),
+		len(dss),
+		time.Since(t),
+	)
+	return ms
+}
+
+func (c Scraper) ScrapeResourcePools(rps rs.ResourcePools) []performance.EntityMetric {
+	t := time.Now()
+	pqs := newResourcePoolsPerfQuerySpecs(rps)
+	ms := c.scrapeMetrics(pqs)
+	c.Debugf("scraping : scraped metrics for %d/%d resource pools, process took %s",
+		len(ms),
+		len(rps),
+		time.Since(t),
+	)
+	return ms
+}
+
 func (c Scraper) scrapeMetrics(pqs []types.PerfQuerySpec) []performance.EntityMetric {
 	tc := newThrottledCaller(5)
 	var ms []performance.EntityMetric
@@ -112,6 +136,8 @@ const (
 	pqsMaxSample  = 1
 	pqsIntervalID = 20
 	pqsFormat     = "normal"
+	// datastores and resource pools have no real-time (20s) statistics, the shortest historical interval is 5 minutes
+	pqsHistoricalIntervalID = 300
 )
 
 func newHostsPerfQuerySpecs(hosts rs.Hosts) []types.PerfQuerySpec {
@@ -144,6 +170,36 @@ func newVMsPerfQuerySpecs(vms rs.VMs) []types.PerfQuerySpec {
 	return pqs
 }
 
+func newDatastoresPerfQuerySpecs(dss rs.Datastores) []types.PerfQuerySpec {
+	pqs := make([]types.PerfQuerySpec, 0, len(dss))
+	for _, ds := range dss {
+		pq := types.PerfQuerySpec{
+			Entity:     ds.Ref,
+			MaxSample:  pqsMaxSample,
+			MetricId:   ds.MetricList,
+			IntervalId: pqsHistoricalIntervalID,
+			Format:     pqsFormat,
+		}
+		pqs = append(pqs, pq)
+	}
+	return pqs
+}
+
+func newResourcePoolsPerfQuerySpecs(rps rs.ResourcePools) []types.PerfQuerySpec {
+	pqs := make([]types.PerfQuerySpec, 0, len(rps))
+	for _, rp := range rps {
+		pq := types.PerfQuerySpec{
+			Entity:     rp.Ref,
+			MaxSample:  pqsMaxSample,
+			MetricId:   rp.MetricList,
+			IntervalId: pqsHistoricalIntervalID,
+			Format:     pqsFormat,
+		}
+		pqs = append(pqs, pq)
+	}
+	return pqs
+}
+
 func parseVersion(version string) (major, minor int, err error) {
 	parts := strings.Split(version, ".")
 	if len(parts) < 2 {
@@ -37,6 +37,22 @@ func TestScraper_ScrapeHosts(t *testing.T) {
 	assert.Len(t, metrics, len(res.Hosts))
 }
 
+func TestScraper_ScrapeDatastores(t *testing.T) {
+	s, res, teardown := prepareScraper(t)
+	defer teardown()
+
+	metrics := s.ScrapeDatastores(res.Datastores)
+	assert.Len(t, metrics, len(res.Datastores))
+}
+
+func TestScraper_ScrapeResourcePools(t *testing.T) {
+	s, res, teardown := prepareScraper(t)
+	defer teardown()
+
+	metrics := s.ScrapeResourcePools(res.ResourcePools)
+	assert.Len(t, metrics, len(res.ResourcePools))
+}
+
 func prepareScraper(t *testing.T) (s *Scraper, res *rs.Resources, teardown func()) {
 	model, srv := createSim(t)
 	teardown = func() { model.Remove(); srv.Close() }
@@ -63,6 +79,8 @@ func newClient(t *testing.T, vCenterURL *url.URL) *client.Client {
 
 func createSim(t *testing.T) (*simulator.Model, *simulator.Server) {
 	model := simulator.VPX()
+	model.Datastore = 2
+	model.Pool = 2
 	err := model.Create()
 	require.NoError(t, err)
 	model.Service.TLS = new(tls.Config)
@@ -35,26 +35,33 @@ func New() *VSphere {
 				Timeout: web.Duration{Duration: time.Second * 20},
 			},
 		},
-		DiscoveryInterval: web.Duration{Duration: time.Minute * 5},
-		HostsInclude:      []string{"/*"},
-		VMsInclude:        []string{"/*"},
+		DiscoveryInterval:    web.Duration{Duration: time.Minute * 5},
+		HostsInclude:         []string{"/*"},
+		VMsInclude:           []string{"/*"},
+		DatastoresInclude:    []string{"/*"},
+		ResourcePoolsInclude: []string{"/*"},
 	}
 
 	return &VSphere{
-		collectionLock:  new(sync.RWMutex),
-		Config:          config,
-		charts:          &module.Charts{},
-		discoveredHosts: make(map[string]int),
-		discoveredVMs:   make(map[string]int),
-		charted:         make(map[string]bool),
+		collectionLock:          new(sync.RWMutex),
+		Config:                  config,
+		charts:                  &module.Charts{},
+		discoveredHosts:         make(map[string]int),
+		discoveredVMs:           make(map[string]int),
+		discoveredDatastores:    make(map[string]int),
+		discoveredResourcePools: make(map[string]int),
+		charted:                 make(map[string]bool),
 	}
 }
 
 type Config struct {
-	web.HTTP          `yaml:",inline"`
-	DiscoveryInterval web.Duration       `yaml:"discovery_interval"`
-	HostsInclude      match.HostIncludes `yaml:"host_include"`
-	VMsInclude        match.VMIncludes   `yaml:"vm_include"`
+	web.HTTP             `yaml:",inline"`
+	DiscoveryInterval    web.Duration               `yaml:"discovery_interval"`
+	HostsInclude         match.HostIncludes         `yaml:"host_include"`
+	VMsInclude           match.VMIncludes           `yaml:"vm_include"`
+	DatastoresInclude    match.DatastoreIncludes    `yaml:"datastore_include"`
+	ResourcePoolsInclude match.ResourcePoolIncludes `yaml:"resource_pool_include"`
+	CollectAlarms        bool                       `yaml:"collect_alarms"`
 }
 
 type (
@@ -66,13 +73,15 @@ type (
 		discoverer
 		scraper
 
-		collectionLock  *sync.RWMutex
-		resources       *rs.Resources
-		discoveryTask   *task
-		discoveredHosts map[string]int
-		discoveredVMs   map[string]int
-		charted         map[string]bool
-		charts          *module.Charts
+		collectionLock          *sync.RWMutex
+		resources               *rs.Resources
+		discoveryTask           *task
+		discoveredHosts         map[string]int
+		discoveredVMs           map[string]int
+		discoveredDatastores    map[string]int
+		discoveredResourcePools map[string]int
+		charted                 map[string]bool
+		charts                  *module.Charts
 	}
 	discoverer interface {
 		Discover() (*rs.Resources, error)
@@ -80,6 +89,8 @@ type (
 	scraper interface {
 		ScrapeHosts(rs.Hosts) []performance.EntityMetric
 		ScrapeVMs(rs.VMs) []performance.EntityMetric
+		ScrapeDatastores(rs.Datastores) []performance.EntityMetric
+		ScrapeResourcePools(rs.ResourcePools) []performance.EntityMetric
 	}
 )
 
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vmware/govmomi/performance"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 func TestNew(t *testing.T) {
@@ -87,6 +88,16 @@ func TestVSphere_Init_ReturnsFalseIfInvalidHostVMIncludeFormat(t *testing.T) {
 
 	vSphere.VMsInclude = match.VMIncludes{"invalid"}
 	assert.False(t, vSphere.Init())
+
+	vSphere.VMsInclude = vSphere.VMsInclude[:0]
+
+	vSphere.DatastoresInclude = match.DatastoreIncludes{"invalid"}
+	assert.False(t, vSphere.Init())
+
+	vSphere.DatastoresInclude = vSphere.DatastoresInclude[:0]
+
+	vSphere.ResourcePoolsInclude = match.ResourcePoolIncludes{"invalid"}
+	assert.False(t, vSphere.Init())
 }
 
 func TestVSphere_Check(t *testing.T) {
@@ -122,198 +133,306 @@ func TestVSphere_Collect(t *testing.T) {
 	vSphere.scraper = mockScraper{vSphere.scraper}
 
 	expected := map[string]int64{
-		"host-21_cpu.usage.average":           100,
-		"host-21_disk.maxTotalLatency.latest": 100,
-		"host-21_disk.read.average":           100,
-		"host-21_disk.write.average":          100,
-		"host-21_mem.active.average":          100,
-		"host-21_mem.consumed.average":        100,
-		"host-21_mem.granted.average":         100,
-		"host-21_mem.shared.average":          100,
-		"host-21_mem.sharedcommon.average":    100,
-		"host-21_mem.swapinRate.average":      100,
-		"host-21_mem.swapoutRate.average":     100,
-		"host-21_mem.usage.average":           100,
-		"host-21_net.bytesRx.average":         100,
-		"host-21_net.bytesTx.average":         100,
-		"host-21_net.droppedRx.summation":     100,
-		"host-21_net.droppedTx.summation":     100,
-		"host-21_net.errorsRx.summation":      100,
-		"host-21_net.errorsTx.summation":      100,
-		"host-21_net.packetsRx.summation":     100,
-		"host-21_net.packetsTx.summation":     100,
-		"host-21_overall.status.gray":         1,
-		"host-21_overall.status.green":        0,
-		"host-21_overall.status.red":          0,
-		"host-21_overall.status.yellow":       0,
-		"host-21_sys.uptime.latest":           100,
-		"host-34_cpu.usage.average":           100,
-		"host-34_disk.maxTotalLatency.latest": 100,
-		"host-34_disk.read.average":           100,
-		"host-34_disk.write.average":          100,
-		"host-34_mem.active.average":          100,
-		"host-34_mem.consumed.average":        100,
-		"host-34_mem.granted.average":         100,
-		"host-34_mem.shared.average":          100,
-		"host-34_mem.sharedcommon.average":    100,
-		"host-34_mem.swapinRate.average":      100,
-		"host-34_mem.swapoutRate.average":     100,
-		"host-34_mem.usage.average":           100,
-		"host-34_net.bytesRx.average":         100,
-		"host-34_net.bytesTx.average":         100,
-		"host-34_net.droppedRx.summation":     100,
-		"host-34_net.droppedTx.summation":     100,
-		"host-34_net.errorsRx.summation":      100,
-		"host-34_net.errorsTx.summation":      100,
-		"host-34_net.packetsRx.summation":     100,
-		"host-34_net.packetsTx.summation":     100,
-		"host-34_overall.status.gray":         1,
-		"host-34_overall.status.green":        0,
-		"host-34_overall.status.red":          0,
-		"host-34_overall.status.yellow":       0,
-		"host-34_sys.uptime.latest":           100,
-		"host-42_cpu.usage.average":           100,
-		"host-42_disk.maxTotalLatency.latest": 100,
-		"host-42_disk.read.average":           100,
-		"host-42_disk.write.average":          100,
-		"host-42_mem.active.average":          100,
-		"host-42_mem.consumed.average":        100,
-		"host-42_mem.granted.average":         100,
-		"host-42_mem.shared.average":          100,
-		"host-42_mem.sharedcommon.average":    100,
-		"host-42_mem.swapinRate.average":      100,
-		"host-42_mem.swapoutRate.average":     100,
-		"host-42_mem.usage.average":           100,
-		"host-42_net.bytesRx.average":         100,
-		"host-42_net.bytesTx.average":         100,
-		"host-42_net.droppedRx.summation":     100,
-		"host-42_net.droppedTx.summation":     100,
-		"host-42_net.errorsRx.summation":      100,
-		"host-42_net.errorsTx.summation":      100,
-		"host-42_net.packetsRx.summation":     100,
-		"host-42_net.packetsTx.summation":     100,
-		"host-42_overall.status.gray":         1,
-		"host-42_overall.status.green":        0,
-		"host-42_overall.status.red":          0,
-		"host-42_overall.status.yellow":       0,
-		"host-42_sys.uptime.latest":           100,
-		"host-50_cpu.usage.average":           100,
-		"host-50_disk.maxTotalLatency.latest": 100,
-		"host-50_disk.read.average":           100,
-		"host-50_disk.write.average":          100,
-		"host-50_mem.active.average":          100,
-		"host-50_mem.consumed.average":        100,
-		"host-50_mem.granted.average":         100,
-		"host-50_mem.shared.average":          100,
-		"host-50_mem.sharedcommon.average":    100,
-		"host-50_mem.swapinRate.average":      100,
-		"host-50_mem.swapoutRate.average":     100,
-		"host-50_mem.usage.average":           100,
-		"host-50_net.bytesRx.average":         100,
-		"host-50_net.bytesTx.average":         100,
-		"host-50_net.droppedRx.summation":     100,
-		"host-50_net.droppedTx.summation":     100,
-		"host-50_net.errorsRx.summation":      100,
-		"host-50_net.errorsTx.summation":      100,
-		"host-50_net.packetsRx.summation":     100,
-		"host-50_net.packetsTx.summation":     100,
-		"host-50_overall.status.gray":         1,
-		"host-50_overall.status.green":        0,
-		"host-50_overall.status.red":          0,
-		"host-50_overall.status.yellow":       0,
-		"host-50_sys.uptime.latest":           100,
-		"vm-55_cpu.usage.average":             200,
-		"vm-55_disk.maxTotalLatency.latest":   200,
-		"vm-55_disk.read.average":             200,
-		"vm-55_disk.write.average":            200,
-		"vm-55_mem.active.average":            200,
-		"vm-55_mem.consumed.average":          200,
-		"vm-55_mem.granted.average":           200,
-		"vm-55_mem.shared.average":            200,
-		"vm-55_mem.swapinRate.average":        200,
-		"vm-55_mem.swapoutRate.average":       200,
-		"vm-55_mem.swapped.average":           200,
-		"vm-55_mem.usage.average":             200,
-		"vm-55_net.bytesRx.average":           200,
-		"vm-55_net.bytesTx.average":           200,
-		"vm-55_net.droppedRx.summation":       200,
-		"vm-55_net.droppedTx.summation":       200,
-		"vm-55_net.packetsRx.summation":       200,
-		"vm-55_net.packetsTx.summation":       200,
-		"vm-55_overall.status.gray":           0,
-		"vm-55_overall.status.green":          1,
-		"vm-55_overall.status.red":            0,
-		"vm-55_overall.status.yellow":         0,
-		"vm-55_sys.uptime.latest":             200,
-		"vm-58_cpu.usage.average":             200,
-		"vm-58_disk.maxTotalLatency.latest":   200,
-		"vm-58_disk.read.average":             200,
-		"vm-58_disk.write.average":            200,
-		"vm-58_mem.active.average":            200,
-		"vm-58_mem.consumed.average":          200,
-		"vm-58_mem.granted.average":           200,
-		"vm-58_mem.shared.average":            200,
-		"vm-58_mem.swapinRate.average":        200,
-		"vm-58_mem.swapoutRate.average":       200,
-		"vm-58_mem.swapped.average":           200,
-		"vm-58_mem.usage.average":             200,
-		"vm-58_net.bytesRx.average":           200,
-		"vm-58_net.bytesTx.average":           200,
-		"vm-58_net.droppedRx.summation":       200,
-		"vm-58_net.droppedTx.summation":       200,
-		"vm-58_net.packetsRx.summation":       200,
-		"vm-58_net.packetsTx.summation":       200,
-		"vm-58_overall.status.gray":           0,
-		"vm-58_overall.status.green":          1,
-		"vm-58_overall.status.red":            0,
-		"vm-58_overall.status.yellow":         0,
-		"vm-58_sys.uptime.latest":             200,
-		"vm-61_cpu.usage.average":             200,
-		"vm-61_disk.maxTotalLatency.latest":   200,
-		"vm-61_disk.read.average":             200,
-		"vm-61_disk.write.average":            200,
-		"vm-61_mem.active.average":            200,
-		"vm-61_mem.consumed.average":          200,
-		"vm-61_mem.granted.average":           200,
-		"vm-61_mem.shared.average":            200,
-		"vm-61_mem.swapinRate.average":        200,
-		"vm-61_mem.swapoutRate.average":       200,
-		"vm-61_mem.swapped.average":           200,
-		"vm-61_mem.usage.average":             200,
-		"vm-61_net.bytesRx.average":           200,
-		"vm-61_net.bytesTx.average":           200,
-		"vm-61_net.droppedRx.summation":       200,
-		"vm-61_net.droppedTx.summation":       200,
-		"vm-61_net.packetsRx.summation":       200,
-		"vm-61_net.packetsTx.summation":       200,
-		"vm-61_overall.status.gray":           0,
-		"vm-61_overall.status.green":          1,
-		"vm-61_overall.status.red":            0,
-		"vm-61_overall.status.yellow":         0,
-		"vm-61_sys.uptime.latest":             200,
-		"vm-64_cpu.usage.average":             200,
-		"vm-64_disk.maxTotalLatency.latest":   200,
-		"vm-64_disk.read.average":             200,
-		"vm-64_disk.write.average":            200,
-		"vm-64_mem.active.average":            200,
-		"vm-64_mem.consumed.average":          200,
-		"vm-64_mem.granted.average":           200,
-		"vm-64_mem.shared.average":            200,
-		"vm-64_mem.swapinRate.average":        200,
-		"vm-64_mem.swapoutRate.average":       200,
-		"vm-64_mem.swapped.average":           200,
-		"vm-64_mem.usage.average":             200,
-		"vm-64_net.bytesRx.average":           200,
-		"vm-64_net.bytesTx.average":           200,
-		"vm-64_net.droppedRx.summation":       200,
-		"vm-64_net.droppedTx.summation":       200,
-		"vm-64_net.packetsRx.summation":       200,
-		"vm-64_net.packetsTx.summation":       200,
-		"vm-64_overall.status.gray":           0,
-		"vm-64_overall.status.green":          1,
-		"vm-64_overall.status.red":            0,
-		"vm-64_overall.status.yellow":         0,
-		"vm-64_sys.uptime.latest":             200,
+		"datastore-54_datastore.numberReadAveraged.average":  300,
+		"datastore-54_datastore.numberWriteAveraged.average": 300,
+		"datastore-54_datastore.read.average":                300,
+		"datastore-54_datastore.totalReadLatency.average":    300,
+		"datastore-54_datastore.totalWriteLatency.average":   300,
+		"datastore-54_datastore.write.average":               300,
+		"datastore-54_overall.status.gray":                   0,
+		"datastore-54_overall.status.green":                  1,
+		"datastore-54_overall.status.red":                    0,
+		"datastore-54_overall.status.yellow":                 0,
+		"datastore-54_space.free":                            10930691768320,
+		"datastore-54_space.used":                            64424509440,
+		"datastore-54_space.utilization":                     58,
+		"datastore-56_datastore.numberReadAveraged.average":  300,
+		"datastore-56_datastore.numberWriteAveraged.average": 300,
+		"datastore-56_datastore.read.average":                300,
+		"datastore-56_datastore.totalReadLatency.average":    300,
+		"datastore-56_datastore.totalWriteLatency.average":   300,
+		"datastore-56_datastore.write.average":               300,
+		"datastore-56_overall.status.gray":                   0,
+		"datastore-56_overall.status.green":                  1,
+		"datastore-56_overall.status.red":                    0,
+		"datastore-56_overall.status.yellow":                 0,
+		"datastore-56_space.free":                            10995116277760,
+		"datastore-56_space.used":                            0,
+		"datastore-56_space.utilization":                     0,
+		"host-21_cpu.usage.average":                          100,
+		"host-21_disk.maxTotalLatency.latest":                100,
+		"host-21_disk.read.average":                          100,
+		"host-21_disk.write.average":                         100,
+		"host-21_mem.active.average":                         100,
+		"host-21_mem.consumed.average":                       100,
+		"host-21_mem.granted.average":                        100,
+		"host-21_mem.shared.average":                         100,
+		"host-21_mem.sharedcommon.average":                   100,
+		"host-21_mem.swapinRate.average":                     100,
+		"host-21_mem.swapoutRate.average":                    100,
+		"host-21_mem.usage.average":                          100,
+		"host-21_net.bytesRx.average":                        100,
+		"host-21_net.bytesTx.average":                        100,
+		"host-21_net.droppedRx.summation":                    100,
+		"host-21_net.droppedTx.summation":                    100,
+		"host-21_net.errorsRx.summation":                     100,
+		"host-21_net.errorsTx.summation":                     100,
+		"host-21_net.packetsRx.summation":                    100,
+		"host-21_net.packetsTx.summation":                    100,
+		"host-21_overall.status.gray":                        1,
+		"host-21_overall.status.green":                       0,
+		"host-21_overall.status.red":                         0,
+		"host-21_overall.status.yellow":                      0,
+		"host-21_sys.uptime.latest":                          100,
+		"host-34_cpu.usage.average":                          100,
+		"host-34_disk.maxTotalLatency.latest":                100,
+		"host-34_disk.read.average":                          100,
+		"host-34_disk.write.average":                         100,
+		"host-34_mem.active.average":                         100,
+		"host-34_mem.consumed.average":                       100,
+		"host-34_mem.granted.average":                        100,
+		"host-34_mem.shared.average":                         100,
+		"host-34_mem.sharedcommon.average":                   100,
+		"host-34_mem.swapinRate.average":                     100,
+		"host-34_mem.swapoutRate.average":                    100,
+		"host-34_mem.usage.average":                          100,
+		"host-34_net.bytesRx.average":                        100,
+		"host-34_net.bytesTx.average":                        100,
+		"host-34_net.droppedRx.summation":                    100,
+		"host-34_net.droppedTx.summation":                    100,
+		"host-34_net.errorsRx.summation":                     100,
+		"host-34_net.errorsTx.summation":                     100,
+		"host-34_net.packetsRx.summation":                    100,
+		"host-34_net.packetsTx.summation":                    100,
+		"host-34_overall.status.gray":                        1,
+		"host-34_overall.status.green":                       0,
+		"host-34_overall.status.red":                         0,
+		"host-34_overall.status.yellow":                      0,
+		"host-34_sys.uptime.latest":                          100,
+		"host-42_cpu.usage.average":                          100,
+		"host-42_disk.maxTotalLatency.latest":                100,
+		"host-42_disk.read.average":                          100,
+		"host-42_disk.write.average":                         100,
+		"host-42_mem.active.average":                         100,
+		"host-42_mem.consumed.average":                       100,
+		"host-42_mem.granted.average":                        100,
+		"host-42_mem.shared.average":                         100,
+		"host-42_mem.sharedcommon.average":                   100,
+		"host-42_mem.swapinRate.average":                     100,
+		"host-42_mem.swapoutRate.average":                    100,
+		"host-42_mem.usage.average":                          100,
+		"host-42_net.bytesRx.average":                        100,
+		"host-42_net.bytesTx.average":                        100,
+		"host-42_net.droppedRx.summation":                    100,
+		"host-42_net.droppedTx.summation":                    100,
+		"host-42_net.errorsRx.summation":                     100,
+		"host-42_net.errorsTx.summation":                     100,
+		"host-42_net.packetsRx.summation":                    100,
+		"host-42_net.packetsTx.summation":                    100,
+		"host-42_overall.status.gray":                        1,
+		"host-42_overall.status.green":                       0,
+		"host-42_overall.status.red":                         0,
+		"host-42_overall.status.yellow":                      0,
+		"host-42_sys.uptime.latest":                          100,
+		"host-50_cpu.usage.average":                          100,
+		"host-50_disk.maxTotalLatency.latest":                100,
+		"host-50_disk.read.average":                          100,
+		"host-50_disk.write.average":                         100,
+		"host-50_mem.active.average":                         100,
+		"host-50_mem.consumed.average":                       100,
+		"host-50_mem.granted.average":                        100,
+		"host-50_mem.shared.average":                         100,
+		"host-50_mem.sharedcommon.average":                   100,
+		"host-50_mem.swapinRate.average":                     100,
+		"host-50_mem.swapoutRate.average":                    100,
+		"host-50_mem.usage.average":                          100,
+		"host-50_net.bytesRx.average":                        100,
+		"host-50_net.bytesTx.average":                        100,
+		"host-50_net.droppedRx.summation":                    100,
+		"host-50_net.droppedTx.summation":                    100,
+		"host-50_net.errorsRx.summation":                     100,
+		"host-50_net.errorsTx.summation":                     100,
+		"host-50_net.packetsRx.summation":                    100,
+		"host-50_net.packetsTx.summation":                    100,
+		"host-50_overall.status.gray":                        1,
+		"host-50_overall.status.green":                       0,
+		"host-50_overall.status.red":                         0,
+		"host-50_overall.status.yellow":                      0,
+		"host-50_sys.uptime.latest":                          100,
+		"resgroup-22_cpu.limit":                              4121,
+		"resgroup-22_cpu.usagemhz.average":                   400,
+		"resgroup-22_mem.active.average":                     400,
+		"resgroup-22_mem.consumed.average":                   400,
+		"resgroup-22_mem.granted.average":                    400,
+		"resgroup-22_mem.limit":                              984064,
+		"resgroup-22_overall.status.gray":                    0,
+		"resgroup-22_overall.status.green":                   1,
+		"resgroup-22_overall.status.red":                     0,
+		"resgroup-22_overall.status.yellow":                  0,
+		"resgroup-26_cpu.limit":                              4121,
+		"resgroup-26_cpu.usagemhz.average":                   400,
+		"resgroup-26_mem.active.average":                     400,
+		"resgroup-26_mem.consumed.average":                   400,
+		"resgroup-26_mem.granted.average":                    400,
+		"resgroup-26_mem.limit":                              984064,
+		"resgroup-26_overall.status.gray":                    0,
+		"resgroup-26_overall.status.green":                   1,
+		"resgroup-26_overall.status.red":                     0,
+		"resgroup-26_overall.status.yellow":                  0,
+		"resgroup-52_cpu.usagemhz.average":                   400,
+		"resgroup-52_mem.active.average":                     400,
+		"resgroup-52_mem.consumed.average":                   400,
+		"resgroup-52_mem.granted.average":                    400,
+		"resgroup-52_overall.status.gray":                    0,
+		"resgroup-52_overall.status.green":                   1,
+		"resgroup-52_overall.status.red":                     0,
+		"resgroup-52_overall.status.yellow":                  0,
+		"resgroup-53_cpu.usagemhz.average":                   400,
+		"resgroup-53_mem.active.average":                     400,
+		"resgroup-53_mem.consumed.average":                   400,
+		"resgroup-53_mem.granted.average":                    400,
+		"resgroup-53_overall.status.gray":                    0,
+		"resgroup-53_overall.status.green":                   1,
+		"resgroup-53_overall.status.red":                     0,
+		"resgroup-53_overall.status.yellow":                  0,
+		"vm-59_cpu.usage.average":                            200,
+		"vm-59_disk.maxTotalLatency.latest":                  200,
+		"vm-59_disk.read.average":                            200,
+		"vm-59_disk.write.average":                           200,
+		"vm-59_mem.active.average":                           200,
+		"vm-59_mem.consumed.average":                         200,
+		"vm-59_mem.granted.average":                          200,
+		"vm-59_mem.shared.average":                           200,
+		"vm-59_mem.swapinRate.average":                       200,
+		"vm-59_mem.swapoutRate.average":                      200,
+		"vm-59_mem.swapped.average":                          200,
+		"vm-59_mem.usage.average":                            200,
+		"vm-59_net.bytesRx.average":                          200,
+		"vm-59_net.bytesTx.average":                          200,
+		"vm-59_net.droppedRx.summation":                      200,
+		"vm-59_net.droppedTx.summation":                      200,
+		"vm-59_net.packetsRx.summation":                      200,
+		"vm-59_net.packetsTx.summation":                      200,
+		"vm-59_overall.status.gray":                          0,
+		"vm-59_overall.status.green":                         1,
+		"vm-59_overall.status.red":                           0,
+		"vm-59_overall.status.yellow":                        0,
+		"vm-59_sys.uptime.latest":                            200,
+		"vm-62_cpu.usage.average":                            200,
+		"vm-62_disk.maxTotalLatency.latest":                  200,
+		"vm-62_disk.read.average":                            200,
+		"vm-62_disk.write.average":                           200,
+		"vm-62_mem.active.average":                           200,
+		"vm-62_mem.consumed.average":                         200,
+		"vm-62_mem.granted.average":                          200,
+		"vm-62_mem.shared.average":                           200,
+		"vm-62_mem.swapinRate.average":                       200,
+		"vm-62_mem.swapoutRate.average":                      200,
+		"vm-62_mem.swapped.average":                          200,
+		"vm-62_mem.usage.average":                            200,
+		"vm-62_net.bytesRx.average":                          200,
+		"vm-62_net.bytesTx.average":                          200,
+		"vm-62_net.droppedRx.summation":                      200,
+		"vm-62_net.droppedTx.summation":                      200,
+		"vm-62_net.packetsRx.summation":                      200,
+		"vm-62_net.packetsTx.summation":                      200,
+		"vm-62_overall.status.gray":                          0,
+		"vm-62_overall.status.green":                         1,
+		"vm-62_overall.status.red":                           0,
+		"vm-62_overall.status.yellow":                        0,
+		"vm-62_sys.uptime.latest":                            200,
+		"vm-65_cpu.usage.average":                            200,
+		"vm-65_disk.maxTotalLatency.latest":                  200,
+		"vm-65_disk.read.average":                            200,
+		"vm-65_disk.write.average":                           200,
+		"vm-65_mem.active.average":                           200,
+		"vm-65_mem.consumed.average":                         200,
+		"vm-65_mem.granted.average":                          200,
+		"vm-65_mem.shared.average":                           200,
+		"vm-65_mem.swapinRate.average":                       200,
+		"vm-65_mem.swapoutRate.average":                      200,
+		"vm-65_mem.swapped.average":                          200,
+		"vm-65_mem.usage.average":                            200,
+		"vm-65_net.bytesRx.average":                          200,
+		"vm-65_net.bytesTx.average":                          200,
+		"vm-65_net.droppedRx.summation":                      200,
+		"vm-65_net.droppedTx.summation":                      200,
+		"vm-65_net.packetsRx.summation":                      200,
+		"vm-65_net.packetsTx.summation":                      200,
+		"vm-65_overall.status.gray":                          0,
+		"vm-65_overall.status.green":                         1,
+		"vm-65_overall.status.red":                           0,
+		"vm-65_overall.status.yellow":                        0,
+		"vm-65_sys.uptime.latest":                            200,
+		"vm-68_cpu.usage.average":                            200,
+		"vm-68_disk.maxTotalLatency.latest":                  200,
+		"vm-68_disk.read.average":                            200,
+		"vm-68_disk.write.average":                           200,
+		"vm-68_mem.active.average":                           200,
+		"vm-68_mem.consumed.average":                         200,
+		"vm-68_mem.granted.average":                          200,
+		"vm-68_mem.shared.average":                           200,
+		"vm-68_mem.swapinRate.average":                       200,
+		"vm-68_mem.swapoutRate.average":                      200,
+		"vm-68_mem.swapped.average":                          200,
+		"vm-68_mem.usage.average":                            200,
+		"vm-68_net.bytesRx.average":                          200,
+		"vm-68_net.bytesTx.average":                          200,
+		"vm-68_net.droppedRx.summation":                      200,
+		"vm-68_net.droppedTx.summation":                      200,
+		"vm-68_net.packetsRx.summation":                      200,
+		"vm-68_net.packetsTx.summation":                      200,
+		"vm-68_overall.status.gray":                          0,
+		"vm-68_overall.status.green":                         1,
+		"vm-68_overall.status.red":                           0,
+		"vm-68_overall.status.yellow":                        0,
+		"vm-68_sys.uptime.latest":                            200,
+		"vm-71_cpu.usage.average":                            200,
+		"vm-71_disk.maxTotalLatency.latest":                  200,
+		"vm-71_disk.read.average":                            200,
+		"vm-71_disk.write.average":                           200,
+		"vm-71_mem.active.average":                           200,
+		"vm-71_mem.consumed.average":                         200,
+		"vm-71_mem.granted.average":                          200,
+		"vm-71_mem.shared.average":                           200,
+		"vm-71_mem.swapinRate.average":                       200,
+		"vm-71_mem.swapoutRate.average":                      200,
+		"vm-71_mem.swapped.average":                          200,
+		"vm-71_mem.usage.average":                            200,
+		"vm-71_net.bytesRx.average":                          200,
+		"vm-71_net.bytesTx.average":                          200,
+		"vm-71_net.droppedRx.summation":                      200,
+		"vm-71_net.droppedTx.summation":                      200,
+		"vm-71_net.packetsRx.summation":                      200,
+		"vm-71_net.packetsTx.summation":                      200,
+		"vm-71_overall.status.gray":                          0,
+		"vm-71_overall.status.green":                         1,
+		"vm-71_overall.status.red":                           0,
+		"vm-71_overall.status.yellow":                        0,
+		"vm-71_sys.uptime.latest":                            200,
+		"vm-74_cpu.usage.average":                            200,
+		"vm-74_disk.maxTotalLatency.latest":                  200,
+		"vm-74_disk.read.average":                            200,
+		"vm-74_disk.write.average":                           200,
+		"vm-74_mem.active.average":                           200,
+		"vm-74_mem.consumed.average":                         200,
+		"vm-74_mem.granted.average":                          200,
+		"vm-74_mem.shared.average":                           200,
+		"vm-74_mem.swapinRate.average":                       200,
+		"vm-74_mem.swapoutRate.average":                      200,
+		"vm-74_mem.swapped.average":                          200,
+		"vm-74_mem.usage.average":                            200,
+		"vm-74_net.bytesRx.average":                          200,
+		"vm-74_net.bytesTx.average":                          200,
+		"vm-74_net.droppedRx.summation":                      200,
+		"vm-74_net.droppedTx.summation":                      200,
+		"vm-74_net.packetsRx.summation":                      200,
+		"vm-74_net.packetsTx.summation":                      200,
+		"vm-74_overall.status.gray":                          0,
+		"vm-74_overall.status.green":                         1,
+		"vm-74_overall.status.red":                           0,
+		"vm-74_overall.status.yellow":                        0,
+		"vm-74_sys.uptime.latest":                            200,
 	}
 
 	collected := vSphere.Collect()
@@ -322,9 +441,54 @@ func TestVSphere_Collect(t *testing.T) {
 	count := model.Count()
 	assert.Len(t, vSphere.discoveredHosts, count.Host)
 	assert.Len(t, vSphere.discoveredVMs, count.Machine)
-	assert.Len(t, vSphere.charted, count.Host+count.Machine)
+	assert.Len(t, vSphere.discoveredDatastores, count.Datastore)
+	assert.Len(t, vSphere.discoveredResourcePools, count.Pool)
+	assert.Len(t, vSphere.charted, count.Host+count.Machine+count.Datastore+count.Pool)
+
+	assert.Len(t, *vSphere.Charts(), numOfExpectedCharts(count))
+	ensureCollectedHasAllChartsDimsVarsIDs(t, vSphere, collected)
+
+	// the root pools have limits
+	assert.Len(t, vSphere.Charts().Get("resgroup-26_cpu_usage").Dims, 2)
+	assert.Len(t, vSphere.Charts().Get("resgroup-52_cpu_usage").Dims, 1)
+}
+
+func TestVSphere_Collect_TriggeredAlarms(t *testing.T) {
+	vSphere, _, teardown := prepareVSphereSim(t)
+	defer teardown()
+
+	vSphere.CollectAlarms = true
+	require.True(t, vSphere.Init())
+	vSphere.scraper = mockScraper{vSphere.scraper}
+
+	hostRef := types.ManagedObjectReference{Type: "HostSystem", Value: "host-21"}
+	vmRef := types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-59"}
+	setTriggeredAlarms(hostRef, []types.AlarmState{
+		{Key: "alarm-1.host-21", Entity: hostRef, OverallStatus: types.ManagedEntityStatusRed},
+		{Key: "alarm-2.host-21", Entity: hostRef, OverallStatus: types.ManagedEntityStatusRed, Acknowledged: types.NewBool(true)},
+		{Key: "alarm-3.host-21", Entity: hostRef, OverallStatus: types.ManagedEntityStatusYellow},
+		// triggered on a descendant
+		{Key: "alarm-4.vm-59", Entity: vmRef, OverallStatus: types.ManagedEntityStatusRed},
+	})
+	setTriggeredAlarms(vmRef, []types.AlarmState{
+		{Key: "alarm-4.vm-59", Entity: vmRef, OverallStatus: types.ManagedEntityStatusRed},
+	})
+
+	// alarms are refreshed on discovery
+	require.NoError(t, vSphere.discoverOnce())
+
+	collected := vSphere.Collect()
+
+	assert.Equal(t, int64(1), collected["host-21_alarms.triggered.red"])
+	assert.Equal(t, int64(1), collected["host-21_alarms.triggered.yellow"])
+	assert.Equal(t, int64(1), collected["host-21_alarms.acknowledged"])
+	assert.Equal(t, int64(1), collected["vm-59_alarms.triggered.red"])
+	assert.Equal(t, int64(0), collected["vm-59_alarms.triggered.yellow"])
+	assert.Equal(t, int64(0), collected["vm-59_alarms.acknowledged"])
+	assert.Equal(t, int64(0), collected["host-34_alarms.triggered.red"])
 
-	assert.Len(t, *vSphere.Charts(), count.Host*len(hostChartsTmpl)+count.Machine*len(vmChartsTmpl))
+	assert.True(t, vSphere.Charts().Has("host-21_triggered_alarms"))
+	assert.True(t, vSphere.Charts().Has("vm-59_triggered_alarms"))
 	ensureCollectedHasAllChartsDimsVarsIDs(t, vSphere, collected)
 }
 
@@ -336,9 +500,13 @@ func TestVSphere_Collect_RemoveHostsVMsInRuntime(t *testing.T) {
 	require.True(t, vSphere.Check())
 
 	okHostID := "host-50"
-	okVMID := "vm-64"
+	okVMID := "vm-74"
+	okDatastoreID := "datastore-56"
+	okResourcePoolID := "resgroup-52"
 	vSphere.discoverer.(*discover.Discoverer).HostMatcher = mockHostMatcher{okHostID}
 	vSphere.discoverer.(*discover.Discoverer).VMMatcher = mockVMMatcher{okVMID}
+	vSphere.discoverer.(*discover.Discoverer).DatastoreMatcher = mockDatastoreMatcher{okDatastoreID}
+	vSphere.discoverer.(*discover.Discoverer).ResourcePoolMatcher = mockResourcePoolMatcher{okResourcePoolID}
 
 	require.NoError(t, vSphere.discoverOnce())
 
@@ -366,16 +534,35 @@ func TestVSphere_Collect_RemoveHostsVMsInRuntime(t *testing.T) {
 
 	}
 
+	for id, fails := range vSphere.discoveredDatastores {
+		if id == okDatastoreID {
+			assert.Equal(t, 0, fails)
+		} else {
+			assert.Equal(t, numOfRuns, fails)
+		}
+	}
+
+	for id, fails := range vSphere.discoveredResourcePools {
+		if id == okResourcePoolID {
+			assert.Equal(t, 0, fails)
+		} else {
+			assert.Equal(t, numOfRuns, fails)
+		}
+	}
+
 	for i := numOfRuns; i < failedUpdatesLimit; i++ {
 		vSphere.Collect()
 	}
 
 	assert.Len(t, vSphere.discoveredHosts, 1)
 	assert.Len(t, vSphere.discoveredVMs, 1)
-	assert.Len(t, vSphere.charted, 2)
+	assert.Len(t, vSphere.discoveredDatastores, 1)
+	assert.Len(t, vSphere.discoveredResourcePools, 1)
+	assert.Len(t, vSphere.charted, 4)
 
 	for _, c := range *vSphere.Charts() {
-		if strings.HasPrefix(c.ID, okHostID) || strings.HasPrefix(c.ID, okVMID) {
+		if strings.HasPrefix(c.ID, okHostID+"_") || strings.HasPrefix(c.ID, okVMID+"_") ||
+			strings.HasPrefix(c.ID, okDatastoreID+"_") || strings.HasPrefix(c.ID, okResourcePoolID+"_") {
 			assert.False(t, c.Obsolete)
 		} else {
 			assert.True(t, c.Obsolete)
@@ -402,8 +589,17 @@ func TestVSphere_Collect_Run(t *testing.T) {
 	count := model.Count()
 	assert.Len(t, vSphere.discoveredHosts, count.Host)
 	assert.Len(t, vSphere.discoveredVMs, count.Machine)
-	assert.Len(t, vSphere.charted, count.Host+count.Machine)
-	assert.Len(t, *vSphere.charts, count.Host*len(hostChartsTmpl)+count.Machine*len(vmChartsTmpl))
+	assert.Len(t, vSphere.discoveredDatastores, count.Datastore)
+	assert.Len(t, vSphere.discoveredResourcePools, count.Pool)
+	assert.Len(t, vSphere.charted, count.Host+count.Machine+count.Datastore+count.Pool)
+	assert.Len(t, *vSphere.charts, numOfExpectedCharts(count))
+}
+
+func numOfExpectedCharts(count simulator.Model) int {
+	return count.Host*len(hostChartsTmpl) +
+		count.Machine*len(vmChartsTmpl) +
+		count.Datastore*len(datastoreChartsTmpl) +
+		count.Pool*len(resourcePoolChartsTmpl)
 }
 
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, vSphere *VSphere, collected map[string]int64) {
@@ -434,6 +630,8 @@ func prepareVSphereSim(t *testing.T) (vSphere *VSphere, model *simulator.Model,
 
 func createSim(t *testing.T) (*simulator.Model, *simulator.Server) {
 	model := simulator.VPX()
+	model.Datastore = 2
+	model.Pool = 2
 	err := model.Create()
 	require.NoError(t, err)
 	model.Service.TLS = new(tls.Config)
@@ -453,6 +651,20 @@ func (s mockScraper) ScrapeVMs(vms rs.VMs) []performance.EntityMetric {
 	return populateMetrics(ms, 200)
 }
 
+func (s mockScraper) ScrapeDatastores(dss rs.Datastores) []performance.EntityMetric {
+	ms := s.scraper.ScrapeDatastores(dss)
+	return populateMetrics(ms, 300)
+}
+func (s mockScraper) ScrapeResourcePools(rps rs.ResourcePools) []performance.EntityMetric {
+	ms := s.scraper.ScrapeResourcePools(rps)
+	return populateMetrics(ms, 400)
+}
+
+func setTriggeredAlarms(ref types.ManagedObjectReference, states []types.AlarmState) {
+	obj := simulator.Map.Get(ref)
+	simulator.Map.Update(obj, []types.PropertyChange{{Name: "triggeredAlarmState", Val: states}})
+}
+
 func populateMetrics(ms []performance.EntityMetric, value int64) []performance.EntityMetric {
 	for i := range ms {
 		for ii := range ms[i].Value {
@@ -472,3 +684,9 @@ type mockVMMatcher struct{ name string }
 
 func (m mockHostMatcher) Match(host *rs.Host) bool { return m.name == host.ID }
 func (m mockVMMatcher) Match(vm *rs.VM) bool       { return m.name == vm.ID }
+
+type mockDatastoreMatcher struct{ name string }
+type mockResourcePoolMatcher struct{ name string }
+
+func (m mockDatastoreMatcher) Match(ds *rs.Datastore) bool       { return m.name == ds.ID }
+func (m mockResourcePoolMatcher) Match(rp *rs.ResourcePool) bool { return m.name == rp.ID }
@@ -42,6 +42,7 @@ import (
 | $bytes_sent             | %O        | Bytes sent to a client, including request and headers.
 | $body_bytes_sent        | %B (%b)   | Bytes sent to a client, not counting the response header.
 | $request_time           | %D        | Request processing time.
+| -                       | -         | Request processing time in milliseconds (request_time_ms, IIS time-taken).
 | $upstream_response_time | -         | Time spent on receiving the response from the upstream server.
 | $ssl_protocol           | -         | Protocol of an established SSL connection.
 | $ssl_cipher             | -         | String of ciphers used for an established SSL connection.
@@ -138,6 +139,8 @@ func (l *logLine) Assign(field string, value string) (err error) {
 		err = l.assignRespSize(value)
 	case "request_time", "D":
 		err = l.assignReqProcTime(value)
+	case "request_time_ms":
+		err = l.assignReqProcTimeMs(value)
 	case "upstream_response_time":
 		err = l.assignUpsRespTime(value)
 	case "ssl_protocol":
@@ -326,6 +329,19 @@ func (l *logLine) assignReqProcTime(time string) error {
 	return nil
 }
 
+func (l *logLine) assignReqProcTimeMs(time string) error {
+	if time == hyphen {
+		return nil
+	}
+	v, err := strconv.ParseFloat(time, 64)
+	if err != nil || !isTimeValid(v) {
+		return fmt.Errorf("assign '%s': %w", time, errBadReqProcTime)
+	}
+	// convert to microseconds
+	l.reqProcTime = v * 1e3
+	return nil
+}
+
 func isUpstreamTimeSeparator(r rune) bool { return r == ',' || r == ':' }
 
 func (l *logLine) assignUpsRespTime(time string) error {
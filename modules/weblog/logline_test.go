@@ -281,6 +281,21 @@ func TestLogLine_Assign(t *testing.T) {
 				{input: "number", wantLine: emptyLogLine, wantErr: errBadReqProcTime},
 			},
 		},
+		{
+			name: "Request Processing Time (milliseconds)",
+			fields: []string{
+				"request_time_ms",
+			},
+			cases: []subTest{
+				{input: "100", wantLine: logLine{web: web{reqProcTime: 100000}}},
+				{input: "100.222", wantLine: logLine{web: web{reqProcTime: 100222}}},
+				{input: "0", wantLine: logLine{web: web{reqProcTime: 0}}},
+				{input: emptyStr, wantLine: emptyLogLine},
+				{input: hyphen, wantLine: emptyLogLine},
+				{input: "-1", wantLine: emptyLogLine, wantErr: errBadReqProcTime},
+				{input: "number", wantLine: emptyLogLine, wantErr: errBadReqProcTime},
+			},
+		},
 		{
 			name: "Upstream Response Time",
 			fields: []string{
@@ -647,7 +662,7 @@ func prepareLogLine(field string, template logLine) logLine {
 		line.reqSize = template.reqSize
 	case "bytes_sent", "body_bytes_sent", "b", "O", "B":
 		line.respSize = template.respSize
-	case "request_time", "D":
+	case "request_time", "D", "request_time_ms":
 		line.reqProcTime = template.reqProcTime
 	case "upstream_response_time":
 		line.upsRespTime = template.upsRespTime
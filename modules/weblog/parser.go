@@ -3,8 +3,11 @@
 package weblog
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"regexp"
 	"strings"
 
@@ -92,10 +95,48 @@ func (w *WebLog) newParser(record []byte) (logs.Parser, error) {
 		w.Debugf("config: %+v", w.Parser.RegExp)
 	case logs.TypeJSON:
 		w.Debugf("config: %+v", w.Parser.JSON)
+	case logs.TypeW3C:
+		if w.Parser.W3C.Fields == "" {
+			fields, err := findLastW3CFieldsDirective(w.file.CurrentFilename())
+			if err != nil {
+				return nil, fmt.Errorf("can't find w3c fields directive (%s): %v", w.file.CurrentFilename(), err)
+			}
+			w.Parser.W3C.Fields = fields
+		}
+		w.Debugf("config: %+v", w.Parser.W3C)
 	}
 	return logs.NewParser(w.Parser, w.file)
 }
 
+// findLastW3CFieldsDirective returns the last '#Fields:' directive in the file.
+// The reader is at the end of the file, so the parser doesn't see the directives written before.
+func findLastW3CFieldsDirective(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	var fields string
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadString('\n')
+		if logs.IsW3CFieldsDirective(line) {
+			fields = strings.TrimSpace(line)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if fields == "" {
+		return "", errors.New("no '#Fields:' directive")
+	}
+	return fields, nil
+}
+
 func (w *WebLog) guessParser(record []byte) (logs.Parser, error) {
 	w.Debug("starting log type auto-detection")
 	if reLTSV.Match(record) {
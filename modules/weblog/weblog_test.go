@@ -709,6 +709,42 @@ func TestWebLog_IISLogs(t *testing.T) {
 	assert.Equal(t, expected, mx)
 }
 
+func TestWebLog_IISLogs_W3CParser(t *testing.T) {
+	weblog := prepareWebLogCollectIISW3C(t)
+
+	assert.Equal(t,
+		"#Fields: date time s-ip cs-method cs-uri-stem cs-uri-query s-port cs-username c-ip cs(User-Agent) cs(Referer) sc-status sc-substatus sc-win32-status time-taken",
+		weblog.Parser.W3C.Fields,
+	)
+
+	mx := weblog.Collect()
+
+	// the directives are not counted as unmatched lines, time-taken is in milliseconds
+	expected := map[string]int64{
+		"requests":            152,
+		"req_unmatched":       0,
+		"req_ipv4":            38,
+		"req_ipv6":            114,
+		"req_method_GET":      152,
+		"req_port_80":         152,
+		"req_vhost_127.0.0.1": 38,
+		"req_vhost_::1":       114,
+		"resp_code_200":       99,
+		"resp_code_304":       11,
+		"resp_code_404":       42,
+		"req_proc_time_count": 152,
+		"req_proc_time_sum":   799000,
+		"req_proc_time_min":   0,
+		"req_proc_time_max":   256000,
+		"req_proc_time_avg":   5256,
+		"uniq_ipv4":           1,
+		"uniq_ipv6":           1,
+	}
+	for k, v := range expected {
+		assert.Equalf(t, v, mx[k], "metric '%s'", k)
+	}
+}
+
 func testCharts(t *testing.T, w *WebLog, mx map[string]int64) {
 	testVhostChart(t, w)
 	testPortChart(t, w)
@@ -1434,6 +1470,23 @@ func prepareWebLogCollectIISFields(t *testing.T) *WebLog {
 	return weblog
 }
 
+func prepareWebLogCollectIISW3C(t *testing.T) *WebLog {
+	t.Helper()
+	weblog := New()
+	weblog.Config.Parser.LogType = logs.TypeW3C
+	weblog.Config.Path = "testdata/u_ex221107.log"
+	weblog.Config.GroupRespCodes = false
+
+	require.True(t, weblog.Init())
+	require.True(t, weblog.Check())
+	defer weblog.Cleanup()
+
+	p, err := logs.NewW3CParser(weblog.Parser.W3C, bytes.NewReader(testIISLog))
+	require.NoError(t, err)
+	weblog.parser = p
+	return weblog
+}
+
 // generateLogs is used to populate 'testdata/full.log'
 //func generateLogs(w io.Writer, num int) error {
 //	var (
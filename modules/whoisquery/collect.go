@@ -5,11 +5,17 @@ package whoisquery
 import "fmt"
 
 func (w *WhoisQuery) collect() (map[string]int64, error) {
-	remainingTime, err := w.prov.remainingTime()
+	remainingTime, source, err := w.prov.remainingTime()
 	if err != nil {
 		return nil, fmt.Errorf("%v (source: %s)", err, w.Source)
 	}
 
+	if source != w.expirationSource {
+		w.Debugf("'%s' expiration date source: '%s'", w.Source, source)
+		w.expirationSource = source
+		w.updateExpirationSourceLabel(source)
+	}
+
 	mx := make(map[string]int64)
 	w.collectExpiration(mx, remainingTime)
 
@@ -21,3 +27,18 @@ func (w *WhoisQuery) collectExpiration(mx map[string]int64, remainingTime float6
 	mx["days_until_expiration_warning"] = w.DaysUntilWarn
 	mx["days_until_expiration_critical"] = w.DaysUntilCrit
 }
+
+// updateExpirationSourceLabel sets the lookup the expiration date is from, the charts are redefined to update the label.
+func (w *WhoisQuery) updateExpirationSourceLabel(source string) {
+	if w.charts == nil {
+		return
+	}
+	for _, chart := range *w.charts {
+		for i, l := range chart.Labels {
+			if l.Key == "expiration_source" {
+				chart.Labels[i].Value = source
+				chart.MarkNotCreated()
+			}
+		}
+	}
+}
@@ -16,7 +16,7 @@ func (w *WhoisQuery) validateConfig() error {
 }
 
 func (w *WhoisQuery) initProvider() (provider, error) {
-	return newProvider(w.Config)
+	return newProvider(w.Config, w.Logger)
 }
 
 func (w *WhoisQuery) initCharts() *module.Charts {
@@ -25,6 +25,7 @@ func (w *WhoisQuery) initCharts() *module.Charts {
 	for _, chart := range *charts {
 		chart.Labels = []module.Label{
 			{Key: "domain", Value: w.Source},
+			{Key: "expiration_source", Value: ""},
 		}
 	}
 
@@ -3,56 +3,172 @@
 package whoisquery
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/netdata/go.d.plugin/logger"
+	"github.com/netdata/go.d.plugin/pkg/web"
+
 	"github.com/araddon/dateparse"
 	"github.com/likexian/whois"
 	whoisparser "github.com/likexian/whois-parser"
 )
 
+const (
+	lookupAuto  = "auto"
+	lookupRDAP  = "rdap"
+	lookupWHOIS = "whois"
+)
+
 type provider interface {
-	remainingTime() (float64, error)
+	// remainingTime returns the seconds until the domain expiration and the lookup the expiration date is from.
+	remainingTime() (float64, string, error)
+}
+
+type dateLookup interface {
+	name() string
+	expirationDate() (time.Time, error)
 }
 
 type fromNet struct {
+	*logger.Logger
+	domainAddress string
+	lookups       []dateLookup // in the order of preference
+}
+
+type fromWHOIS struct {
 	domainAddress string
 	client        *whois.Client
+	minInterval   time.Duration
 }
 
-func newProvider(config Config) (provider, error) {
-	domain := config.Source
-	client := whois.NewClient()
-	client.SetTimeout(config.Timeout.Duration)
+func newProvider(config Config, log *logger.Logger) (provider, error) {
+	domain := strings.ToLower(strings.TrimSuffix(config.Source, "."))
+
+	var lookups []dateLookup
+
+	if config.Lookup == lookupAuto || config.Lookup == lookupRDAP {
+		httpClient, err := web.NewHTTPClient(web.Client{Timeout: config.Timeout})
+		if err != nil {
+			return nil, fmt.Errorf("create RDAP HTTP client: %v", err)
+		}
+		lookups = append(lookups, &fromRDAP{
+			domainAddress: domain,
+			bootstrapURL:  strings.TrimSuffix(config.RDAPBootstrapURL, "/"),
+			httpClient:    httpClient,
+			minInterval:   config.RegistryMinInterval.Duration,
+		})
+	}
+	if config.Lookup == lookupAuto || config.Lookup == lookupWHOIS {
+		client := whois.NewClient()
+		client.SetTimeout(config.Timeout.Duration)
+		lookups = append(lookups, &fromWHOIS{
+			domainAddress: domain,
+			client:        client,
+			minInterval:   config.RegistryMinInterval.Duration,
+		})
+	}
+	if len(lookups) == 0 {
+		return nil, fmt.Errorf("unknown lookup '%s' (allowed: '%s', '%s', '%s')", config.Lookup, lookupAuto, lookupRDAP, lookupWHOIS)
+	}
 
 	return &fromNet{
+		Logger:        log,
 		domainAddress: domain,
-		client:        client,
+		lookups:       lookups,
 	}, nil
 }
 
-func (f *fromNet) remainingTime() (float64, error) {
+// remainingTime asks all the lookups. The registry (RDAP) and the WHOIS data can disagree, e.g. WHOIS shows
+// the registrar expiration date that can be later than the registry one, the earlier date is reported.
+func (f *fromNet) remainingTime() (float64, string, error) {
+	var date time.Time
+	var source string
+	var errs []string
+
+	for _, l := range f.lookups {
+		v, err := l.expirationDate()
+		if err != nil {
+			f.Debugf("%s lookup of '%s': %v", l.name(), f.domainAddress, err)
+			errs = append(errs, fmt.Sprintf("%s: %v", l.name(), err))
+			continue
+		}
+		if source != "" && !v.Equal(date) {
+			f.Debugf("'%s' expiration dates differ: %s (%s) and %s (%s)", f.domainAddress, source, date, l.name(), v)
+		}
+		if source == "" || v.Before(date) {
+			date, source = v, l.name()
+		}
+	}
+
+	if source == "" {
+		return 0, "", errors.New(strings.Join(errs, "; "))
+	}
+	return time.Until(date).Seconds(), source, nil
+}
+
+func (f *fromWHOIS) name() string { return lookupWHOIS }
+
+func (f *fromWHOIS) expirationDate() (time.Time, error) {
+	registries.wait(lookupWHOIS+":"+domainTLD(f.domainAddress), f.minInterval)
+
 	raw, err := f.client.Whois(f.domainAddress)
 	if err != nil {
-		return 0, err
+		return time.Time{}, err
 	}
 
 	result, err := whoisparser.Parse(raw)
 	if err != nil {
-		return 0, err
+		return time.Time{}, err
+	}
+	if result.Domain == nil || result.Domain.ExpirationDate == "" {
+		return time.Time{}, errors.New("no expiration date in the response")
 	}
 
 	// https://community.netdata.cloud/t/whois-query-monitor-cannot-parse-expiration-time/3485
 	if strings.Contains(result.Domain.ExpirationDate, " ") {
 		if v, err := time.Parse("2006.01.02 15:04:05", result.Domain.ExpirationDate); err == nil {
-			return time.Until(v).Seconds(), nil
+			return v, nil
 		}
 	}
 
-	expire, err := dateparse.ParseAny(result.Domain.ExpirationDate)
-	if err != nil {
-		return 0, err
+	return dateparse.ParseAny(result.Domain.ExpirationDate)
+}
+
+// registries spaces the lookups of all the jobs to the same registry, the registries rate limit the queries
+// and block the clients that exceed the limits.
+var registries = &registryLimiter{next: make(map[string]time.Time)}
+
+type registryLimiter struct {
+	mu   sync.Mutex
+	next map[string]time.Time // the earliest time of the next lookup by the registry
+}
+
+// wait blocks until the registry can be queried again. The slots are reserved in the order of the calls.
+func (l *registryLimiter) wait(registry string, interval time.Duration) {
+	if interval <= 0 {
+		return
 	}
 
-	return time.Until(expire).Seconds(), nil
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[registry]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[registry] = at.Add(interval)
+	l.mu.Unlock()
+
+	time.Sleep(at.Sub(now))
+}
+
+// domainTLD returns the top level domain, a registry is responsible for it.
+func domainTLD(domain string) string {
+	if i := strings.LastIndexByte(domain, '.'); i >= 0 {
+		return domain[i+1:]
+	}
+	return domain
 }
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package whoisquery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+const (
+	rdapEventExpiration          = "expiration"
+	rdapEventRegistrarExpiration = "registrar expiration"
+)
+
+// fromRDAP looks up the domain using the Registration Data Access Protocol (RFC 9083, formerly RFC 7483).
+// The bootstrap service (rdap.org) redirects the query to the RDAP server of the domain registry.
+type fromRDAP struct {
+	domainAddress string
+	bootstrapURL  string
+	httpClient    *http.Client
+	minInterval   time.Duration
+}
+
+type (
+	rdapDomain struct {
+		ObjectClassName string      `json:"objectClassName"`
+		LDHName         string      `json:"ldhName"`
+		Events          []rdapEvent `json:"events"`
+		Links           []rdapLink  `json:"links"`
+	}
+	rdapEvent struct {
+		EventAction string `json:"eventAction"`
+		EventDate   string `json:"eventDate"`
+	}
+	rdapLink struct {
+		Rel  string `json:"rel"`
+		Href string `json:"href"`
+		Type string `json:"type"`
+	}
+)
+
+func (f *fromRDAP) name() string { return lookupRDAP }
+
+func (f *fromRDAP) expirationDate() (time.Time, error) {
+	registries.wait(lookupRDAP+":"+domainTLD(f.domainAddress), f.minInterval)
+
+	domain, err := f.query(f.bootstrapURL + "/domain/" + f.domainAddress)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if v, ok, err := domain.expiration(); ok || err != nil {
+		return v, err
+	}
+
+	// thin registries don't publish the expiration date, the registrar RDAP server does
+	for _, link := range domain.registrarLinks() {
+		registrar, err := f.query(link)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("registrar: %v", err)
+		}
+		if v, ok, err := registrar.expiration(); ok || err != nil {
+			return v, err
+		}
+	}
+
+	return time.Time{}, errors.New("no expiration event in the response")
+}
+
+func (f *fromRDAP) query(url string) (*rdapDomain, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error on request to '%s': %v", url, err)
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// the bootstrap service answers 404 for the TLDs without RDAP too
+		return nil, fmt.Errorf("'%s' returned HTTP status code: %d (no RDAP service for the TLD or the domain is not registered)", url, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("'%s' returned HTTP status code: %d", url, resp.StatusCode)
+	}
+
+	var domain rdapDomain
+	if err := json.NewDecoder(resp.Body).Decode(&domain); err != nil {
+		return nil, fmt.Errorf("error on decoding response from '%s': %v", url, err)
+	}
+	if domain.ObjectClassName != "" && domain.ObjectClassName != "domain" {
+		return nil, fmt.Errorf("'%s' returned '%s' object, expected 'domain'", url, domain.ObjectClassName)
+	}
+
+	return &domain, nil
+}
+
+// expiration returns the date of the 'expiration' event, the 'registrar expiration' event date if there is none.
+func (d *rdapDomain) expiration() (time.Time, bool, error) {
+	for _, action := range []string{rdapEventExpiration, rdapEventRegistrarExpiration} {
+		for _, e := range d.Events {
+			if !strings.EqualFold(e.EventAction, action) {
+				continue
+			}
+			v, err := parseRDAPDate(e.EventDate)
+			if err != nil {
+				return time.Time{}, true, fmt.Errorf("bad '%s' event date '%s': %v", action, e.EventDate, err)
+			}
+			return v, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+// registrarLinks returns the links to the registrar RDAP server, they are 'related' RDAP links.
+func (d *rdapDomain) registrarLinks() []string {
+	var links []string
+	for _, l := range d.Links {
+		if l.Rel == "related" && strings.HasPrefix(l.Type, "application/rdap+json") && l.Href != "" {
+			links = append(links, l.Href)
+		}
+	}
+	return links
+}
+
+// parseRDAPDate parses the RFC 3339 date, some ccTLD registries use other formats (UTC is assumed if there is no zone).
+func parseRDAPDate(s string) (time.Time, error) {
+	if v, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return v, nil
+	}
+	return dateparse.ParseIn(s, time.UTC)
+}
+
+func closeBody(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
@@ -26,18 +26,24 @@ func init() {
 func New() *WhoisQuery {
 	return &WhoisQuery{
 		Config: Config{
-			Timeout:       web.Duration{Duration: time.Second * 5},
-			DaysUntilWarn: 90,
-			DaysUntilCrit: 30,
+			Timeout:             web.Duration{Duration: time.Second * 5},
+			DaysUntilWarn:       90,
+			DaysUntilCrit:       30,
+			Lookup:              lookupAuto,
+			RDAPBootstrapURL:    "https://rdap.org",
+			RegistryMinInterval: web.Duration{Duration: time.Second},
 		},
 	}
 }
 
 type Config struct {
-	Source        string
-	Timeout       web.Duration `yaml:"timeout"`
-	DaysUntilWarn int64        `yaml:"days_until_expiration_warning"`
-	DaysUntilCrit int64        `yaml:"days_until_expiration_critical"`
+	Source              string
+	Timeout             web.Duration `yaml:"timeout"`
+	DaysUntilWarn       int64        `yaml:"days_until_expiration_warning"`
+	DaysUntilCrit       int64        `yaml:"days_until_expiration_critical"`
+	Lookup              string       `yaml:"lookup"`
+	RDAPBootstrapURL    string       `yaml:"rdap_bootstrap_url"`
+	RegistryMinInterval web.Duration `yaml:"registry_min_interval"`
 }
 
 type WhoisQuery struct {
@@ -47,6 +53,8 @@ type WhoisQuery struct {
 	charts *module.Charts
 
 	prov provider
+
+	expirationSource string
 }
 
 func (w *WhoisQuery) Init() bool {
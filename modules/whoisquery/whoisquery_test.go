@@ -4,12 +4,41 @@ package whoisquery
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/netdata/go.d.plugin/agent/module"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var (
+	dataRDAPCom, _         = os.ReadFile("testdata/rdap/example.com.json")
+	dataRDAPDev, _         = os.ReadFile("testdata/rdap/example.dev.json")
+	dataRDAPUk, _          = os.ReadFile("testdata/rdap/example.uk.json")
+	dataRDAPBr, _          = os.ReadFile("testdata/rdap/example.br.json")
+	dataRDAPIo, _          = os.ReadFile("testdata/rdap/example.io.json")
+	dataRDAPIoRegistrar, _ = os.ReadFile("testdata/rdap/example.io-registrar.json")
+)
+
+func Test_testDataIsValid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"dataRDAPCom":         dataRDAPCom,
+		"dataRDAPDev":         dataRDAPDev,
+		"dataRDAPUk":          dataRDAPUk,
+		"dataRDAPBr":          dataRDAPBr,
+		"dataRDAPIo":          dataRDAPIo,
+		"dataRDAPIoRegistrar": dataRDAPIoRegistrar,
+	} {
+		require.NotNilf(t, data, name)
+	}
+}
+
 func TestWhoisQuery_Cleanup(t *testing.T) {
 	New().Cleanup()
 }
@@ -37,11 +66,18 @@ func TestWhoisQuery_Init(t *testing.T) {
 			config: Config{Source: ""},
 			err:    true,
 		},
+		"unknown lookup": {
+			config: Config{Source: "example.org", Lookup: "dns"},
+			err:    true,
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			whoisquery := New()
+			if test.config.Lookup == "" {
+				test.config.Lookup = whoisquery.Lookup
+			}
 			whoisquery.Config = test.config
 
 			if test.err {
@@ -115,14 +151,212 @@ func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, whoisquery *WhoisQuery
 	}
 }
 
+func TestWhoisQuery_Collect_ExpirationSourceLabel(t *testing.T) {
+	whoisquery := New()
+	whoisquery.Source = "example.com"
+	require.True(t, whoisquery.Init())
+
+	prov := &mockProvider{remTime: 12345, source: lookupRDAP}
+	whoisquery.prov = prov
+
+	for _, source := range []string{lookupRDAP, lookupWHOIS} {
+		prov.source = source
+		require.NotNil(t, whoisquery.Collect())
+
+		for _, chart := range *whoisquery.Charts() {
+			assert.Contains(t, chart.Labels, module.Label{Key: "expiration_source", Value: source})
+		}
+	}
+}
+
+func TestFromRDAP_ExpirationDate(t *testing.T) {
+	tests := map[string]struct {
+		domain   string
+		wantDate time.Time
+		wantErr  bool
+	}{
+		"com (RFC 3339)": {
+			domain:   "example.com",
+			wantDate: time.Date(2025, 8, 13, 4, 0, 0, 0, time.UTC),
+		},
+		"dev (fractional seconds)": {
+			domain:   "example.dev",
+			wantDate: time.Date(2025, 2, 28, 16, 0, 14, 187000000, time.UTC),
+		},
+		"uk (time zone offset)": {
+			domain:   "example.uk",
+			wantDate: time.Date(2025, 6, 9, 23, 0, 0, 0, time.UTC),
+		},
+		"br (not RFC 3339)": {
+			domain:   "example.br",
+			wantDate: time.Date(2025, 3, 26, 12, 0, 0, 0, time.UTC),
+		},
+		"io (registrar expiration)": {
+			domain:   "example.io",
+			wantDate: time.Date(2025, 7, 1, 9, 15, 10, 0, time.UTC),
+		},
+		"no RDAP service": {
+			domain:  "example.de",
+			wantErr: true,
+		},
+	}
+
+	srv := newRDAPServer(t)
+	defer srv.Close()
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rdap := &fromRDAP{
+				domainAddress: test.domain,
+				bootstrapURL:  srv.URL,
+				httpClient:    srv.Client(),
+			}
+
+			date, err := rdap.expirationDate()
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, test.wantDate.Equal(date), "want %s, got %s", test.wantDate, date)
+			}
+		})
+	}
+}
+
+func TestFromNet_RemainingTime(t *testing.T) {
+	now := time.Now()
+	rdap := &mockLookup{lookup: lookupRDAP, date: now.Add(time.Hour * 24 * 10)}
+	whois := &mockLookup{lookup: lookupWHOIS, date: now.Add(time.Hour * 24 * 20)}
+
+	tests := map[string]struct {
+		lookups    []dateLookup
+		prepare    func()
+		wantDays   int
+		wantSource string
+		wantErr    bool
+	}{
+		"both, rdap is earlier": {
+			lookups:    []dateLookup{rdap, whois},
+			wantDays:   9,
+			wantSource: lookupRDAP,
+		},
+		"both, whois is earlier": {
+			lookups: []dateLookup{rdap, whois},
+			prepare: func() {
+				whois.date = now.Add(time.Hour * 24 * 5)
+			},
+			wantDays:   4,
+			wantSource: lookupWHOIS,
+		},
+		"rdap fails": {
+			lookups: []dateLookup{rdap, whois},
+			prepare: func() {
+				rdap.err = true
+			},
+			wantDays:   19,
+			wantSource: lookupWHOIS,
+		},
+		"both fail": {
+			lookups: []dateLookup{rdap, whois},
+			prepare: func() {
+				rdap.err, whois.err = true, true
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rdap.date, rdap.err = now.Add(time.Hour*24*10), false
+			whois.date, whois.err = now.Add(time.Hour*24*20), false
+			if test.prepare != nil {
+				test.prepare()
+			}
+
+			f := &fromNet{Logger: New().Logger, domainAddress: "example.com", lookups: test.lookups}
+			remaining, source, err := f.remainingTime()
+
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantSource, source)
+			assert.Equal(t, test.wantDays, int(remaining/86400))
+		})
+	}
+}
+
+func TestRegistryLimiter_Wait(t *testing.T) {
+	l := &registryLimiter{next: make(map[string]time.Time)}
+	interval := time.Millisecond * 100
+
+	start := time.Now()
+	l.wait("rdap:com", interval)
+	l.wait("rdap:org", interval)
+	assert.Less(t, time.Since(start), interval)
+
+	l.wait("rdap:com", interval)
+	assert.GreaterOrEqual(t, time.Since(start), interval)
+}
+
+func newRDAPServer(t *testing.T) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") != "application/rdap+json" {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			var data []byte
+			switch r.URL.Path {
+			case "/domain/example.com":
+				data = dataRDAPCom
+			case "/domain/example.dev":
+				data = dataRDAPDev
+			case "/domain/example.uk":
+				data = dataRDAPUk
+			case "/domain/example.br":
+				data = dataRDAPBr
+			case "/domain/example.io":
+				data = []byte(strings.ReplaceAll(string(dataRDAPIo), "{{registrar}}", srv.URL+"/registrar"))
+			case "/registrar/domain/example.io":
+				data = dataRDAPIoRegistrar
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/rdap+json")
+			_, _ = w.Write(data)
+		}))
+	return srv
+}
+
 type mockProvider struct {
 	remTime float64
+	source  string
 	err     bool
 }
 
-func (m mockProvider) remainingTime() (float64, error) {
+func (m mockProvider) remainingTime() (float64, string, error) {
+	if m.err {
+		return 0, "", errors.New("mock remaining time error")
+	}
+	return m.remTime, m.source, nil
+}
+
+type mockLookup struct {
+	lookup string
+	date   time.Time
+	err    bool
+}
+
+func (m *mockLookup) name() string { return m.lookup }
+
+func (m *mockLookup) expirationDate() (time.Time, error) {
 	if m.err {
-		return 0, errors.New("mock remaining time error")
+		return time.Time{}, errors.New("mock lookup error")
 	}
-	return m.remTime, nil
+	return m.date, nil
 }
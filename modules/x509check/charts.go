@@ -2,14 +2,21 @@
 
 package x509check
 
-import "github.com/netdata/go.d.plugin/agent/module"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/netdata/go.d.plugin/agent/module"
+)
 
 var (
 	baseCharts = module.Charts{
 		timeUntilExpirationChart.Copy(),
+		chainValidityChart.Copy(),
 	}
 	withRevocationCharts = module.Charts{
 		timeUntilExpirationChart.Copy(),
+		chainValidityChart.Copy(),
 		revocationStatusChart.Copy(),
 	}
 
@@ -22,12 +29,25 @@ var (
 		Opts:  module.Opts{StoreFirst: true},
 		Dims: module.Dims{
 			{ID: "expiry"},
+			{ID: "chain_expiry"},
 		},
 		Vars: module.Vars{
 			{ID: "days_until_expiration_warning"},
 			{ID: "days_until_expiration_critical"},
 		},
 	}
+	chainValidityChart = module.Chart{
+		ID:    "chain_validity",
+		Title: "Certificate Chain Validity",
+		Units: "boolean",
+		Fam:   "validity",
+		Ctx:   "x509check.chain_validity",
+		Opts:  module.Opts{StoreFirst: true},
+		Dims: module.Dims{
+			{ID: "chain_valid", Name: "valid"},
+			{ID: "not_yet_valid"},
+		},
+	}
 	revocationStatusChart = module.Chart{
 		ID:    "revocation_status",
 		Title: "Revocation Status",
@@ -40,3 +60,48 @@ var (
 		},
 	}
 )
+
+// newCharts returns the certificate charts, the chain is not verified if 'tls_skip_verify' is set.
+func newCharts(withRevocation, withChainVerify bool) *module.Charts {
+	charts := baseCharts.Copy()
+	if withRevocation {
+		charts = withRevocationCharts.Copy()
+	}
+	if !withChainVerify {
+		_ = charts.Get(chainValidityChart.ID).RemoveDim("chain_valid")
+	}
+	return charts
+}
+
+func newServerNameCharts(name, san, source string, withRevocation, withChainVerify bool) *module.Charts {
+	charts := newCharts(withRevocation, withChainVerify)
+
+	px := serverNamePrefix(name)
+	for _, chart := range *charts {
+		chart.ID = px + chart.ID
+		chart.Labels = []module.Label{
+			{Key: "source", Value: source},
+			{Key: "server_name", Value: name},
+			{Key: "san", Value: san},
+		}
+		// the names are kept, the alerts refer to them
+		for _, dim := range chart.Dims {
+			if dim.Name == "" {
+				dim.Name = dim.ID
+			}
+			dim.ID = px + dim.ID
+		}
+		for _, v := range chart.Vars {
+			if v.Name == "" {
+				v.Name = v.ID
+			}
+			v.ID = px + v.ID
+		}
+	}
+
+	return charts
+}
+
+func serverNamePrefix(name string) string {
+	return fmt.Sprintf("server_name_%s_", strings.ReplaceAll(name, ".", "_"))
+}
@@ -4,49 +4,193 @@ package x509check
 
 import (
 	"crypto/x509"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cloudflare/cfssl/revoke"
+	"golang.org/x/crypto/ocsp"
 )
 
 func (x *X509Check) collect() (map[string]int64, error) {
-	certs, err := x.prov.certificates()
+	if len(x.serverNames) > 0 {
+		return x.collectServerNames()
+	}
+
+	chain, err := x.prov.certificates()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(certs) == 0 {
+	if len(chain.certs) == 0 {
 		return nil, fmt.Errorf("no certificate was provided by '%s'", x.Config.Source)
 	}
 
 	mx := make(map[string]int64)
 
-	x.collectExpiration(mx, certs)
-	if x.CheckRevocation {
-		x.collectRevocation(mx, certs)
+	x.collectChain(mx, "", chain)
+
+	return mx, nil
+}
+
+func (x *X509Check) collectServerNames() (map[string]int64, error) {
+	mx := make(map[string]int64)
+
+	for _, sn := range x.serverNames {
+		chain, err := sn.prov.certificates()
+		if err != nil {
+			x.Warningf("server name '%s': %v", sn.name, err)
+			continue
+		}
+		if len(chain.certs) == 0 {
+			x.Warningf("server name '%s': no certificate was provided by '%s'", sn.name, x.Source)
+			continue
+		}
+
+		if !sn.charted {
+			sn.charted = true
+			san := leafSAN(chain.certs[0])
+			x.Debugf("server name '%s': adding charts (SAN '%s')", sn.name, san)
+			if err := x.charts.Add(*newServerNameCharts(sn.name, san, x.Source, x.CheckRevocation, !x.InsecureSkipVerify)...); err != nil {
+				x.Warning(err)
+			}
+		}
+
+		x.collectChain(mx, serverNamePrefix(sn.name), chain)
 	}
 
+	if len(mx) == 0 {
+		return nil, errors.New("no certificates were provided for the server names")
+	}
 	return mx, nil
 }
 
-func (x *X509Check) collectExpiration(mx map[string]int64, certs []*x509.Certificate) {
+func (x *X509Check) collectChain(mx map[string]int64, px string, chain *certChain) {
+	x.collectExpiration(mx, px, chain.certs)
+	x.collectValidity(mx, px, chain)
+	if x.CheckRevocation {
+		x.collectRevocation(mx, px, chain)
+	}
+}
+
+func (x *X509Check) collectExpiration(mx map[string]int64, px string, certs []*x509.Certificate) {
 	expiry := time.Until(certs[0].NotAfter).Seconds()
-	mx["expiry"] = int64(expiry)
-	mx["days_until_expiration_warning"] = x.DaysUntilWarn
-	mx["days_until_expiration_critical"] = x.DaysUntilCritical
+	mx[px+"expiry"] = int64(expiry)
 
+	// an intermediate certificate may expire before the leaf
+	chainExpiry := expiry
+	for _, cert := range certs[1:] {
+		if v := time.Until(cert.NotAfter).Seconds(); v < chainExpiry {
+			chainExpiry = v
+		}
+	}
+	mx[px+"chain_expiry"] = int64(chainExpiry)
+
+	mx[px+"days_until_expiration_warning"] = x.DaysUntilWarn
+	mx[px+"days_until_expiration_critical"] = x.DaysUntilCritical
 }
 
-func (x *X509Check) collectRevocation(mx map[string]int64, certs []*x509.Certificate) {
-	rev, ok, err := revoke.VerifyCertificateError(certs[0])
+func (x *X509Check) collectValidity(mx map[string]int64, px string, chain *certChain) {
+	now := time.Now()
+
+	var notYetValid bool
+	for _, cert := range chain.certs {
+		if cert.NotBefore.After(now) {
+			x.Debugf("certificate '%s' is not valid before %s", cert.Subject, cert.NotBefore)
+			notYetValid = true
+		}
+	}
+	mx[px+"not_yet_valid"] = boolToInt(notYetValid)
+
+	if x.InsecureSkipVerify {
+		return
+	}
+
+	err := x.verifyChain(chain, now)
+	if err != nil {
+		x.Debugf("chain verification: %v", err)
+	}
+	mx[px+"chain_valid"] = boolToInt(err == nil)
+}
+
+// verifyChain checks that the chain builds to the configured (or the system) roots and that the leaf certificate
+// is valid for the server name (SNI or the source host). The source sent intermediates are used.
+func (x *X509Check) verifyChain(chain *certChain, now time.Time) error {
+	certs := chain.certs
+	opts := x509.VerifyOptions{
+		DNSName:       chain.serverName,
+		Roots:         x.roots,
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   now,
+		// any purpose, the file source can be a client certificate
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(opts)
+	return err
+}
+
+// collectRevocation prefers the stapled OCSP response, the OCSP responder or the CRL is requested if there is no
+// (usable) staple.
+func (x *X509Check) collectRevocation(mx map[string]int64, px string, chain *certChain) {
+	if revoked, ok := x.checkOCSPStaple(chain); ok {
+		mx[px+"revoked"] = boolToInt(revoked)
+		return
+	}
+
+	rev, ok, err := revoke.VerifyCertificateError(chain.certs[0])
 	if err != nil {
 		x.Debug(err)
 	}
 	switch {
 	case ok && rev:
-		mx["revoked"] = 1
+		mx[px+"revoked"] = 1
 	case ok && !rev:
-		mx["revoked"] = 0
+		mx[px+"revoked"] = 0
+	}
+}
+
+func (x *X509Check) checkOCSPStaple(chain *certChain) (revoked bool, ok bool) {
+	if len(chain.ocspStaple) == 0 || len(chain.certs) < 2 {
+		return false, false
+	}
+
+	// the signature is checked against the issuer, the next certificate in the chain
+	resp, err := ocsp.ParseResponseForCert(chain.ocspStaple, chain.certs[0], chain.certs[1])
+	if err != nil {
+		x.Debugf("stapled OCSP response: %v", err)
+		return false, false
+	}
+	if !resp.NextUpdate.IsZero() && resp.NextUpdate.Before(time.Now()) {
+		x.Debugf("stapled OCSP response is stale (next update %s)", resp.NextUpdate)
+		return false, false
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		return false, true
+	case ocsp.Revoked:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// leafSAN returns the DNS names of the certificate, the common name if there are none.
+func leafSAN(cert *x509.Certificate) string {
+	if len(cert.DNSNames) == 0 {
+		return cert.Subject.CommonName
+	}
+	return strings.Join(cert.DNSNames, ",")
+}
+
+func boolToInt(v bool) int64 {
+	if v {
+		return 1
 	}
+	return 0
 }
@@ -3,9 +3,12 @@
 package x509check
 
 import (
+	"crypto/x509"
 	"errors"
+	"fmt"
 
 	"github.com/netdata/go.d.plugin/agent/module"
+	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 )
 
 func (x *X509Check) validateConfig() error {
@@ -16,17 +19,56 @@ func (x *X509Check) validateConfig() error {
 }
 
 func (x *X509Check) initProvider() (provider, error) {
-	return newProvider(x.Config)
+	return newProvider(x.Config, "")
+}
+
+// initRoots returns the configured CA certificates the chain is verified against.
+func (x *X509Check) initRoots() (*x509.CertPool, error) {
+	tlsCfg, err := tlscfg.NewTLSConfig(x.TLSConfig)
+	if err != nil || tlsCfg == nil {
+		return nil, err
+	}
+	return tlsCfg.RootCAs, nil
+}
+
+func (x *X509Check) initServerNames() ([]*serverNameCheck, error) {
+	if len(x.Names) == 0 {
+		return nil, nil
+	}
+	if _, ok := x.prov.(*fromFile); ok {
+		return nil, errors.New("'names' are not supported for the file source")
+	}
+
+	var checks []*serverNameCheck
+	seen := make(map[string]bool)
+
+	for _, name := range x.Names {
+		if name == "" {
+			return nil, errors.New("empty server name")
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate server name '%s'", name)
+		}
+		seen[name] = true
+
+		prov, err := newProvider(x.Config, name)
+		if err != nil {
+			return nil, fmt.Errorf("server name '%s': %v", name, err)
+		}
+		checks = append(checks, &serverNameCheck{name: name, prov: prov})
+	}
+
+	return checks, nil
 }
 
 func (x *X509Check) initCharts() *module.Charts {
-	var charts *module.Charts
-	if x.CheckRevocation {
-		charts = withRevocationCharts.Copy()
-	} else {
-		charts = baseCharts.Copy()
+	// the server names charts are added on the first collection, they are labeled by the leaf certificate SAN
+	if len(x.serverNames) > 0 {
+		return &module.Charts{}
 	}
 
+	charts := newCharts(x.CheckRevocation, !x.InsecureSkipVerify)
+
 	for _, chart := range *charts {
 		chart.Labels = []module.Label{
 			{Key: "source", Value: x.Source},
@@ -34,5 +76,4 @@ func (x *X509Check) initCharts() *module.Charts {
 	}
 
 	return charts
-
 }
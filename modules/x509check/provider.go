@@ -17,7 +17,14 @@ import (
 )
 
 type provider interface {
-	certificates() ([]*x509.Certificate, error)
+	certificates() (*certChain, error)
+}
+
+// certChain is the presented certificates chain, the leaf certificate is the first.
+type certChain struct {
+	certs      []*x509.Certificate
+	ocspStaple []byte // the OCSP response stapled to the TLS handshake
+	serverName string // the name the leaf certificate is verified for, empty for the file source
 }
 
 type fromFile struct {
@@ -36,7 +43,9 @@ type fromSMTP struct {
 	timeout   time.Duration
 }
 
-func newProvider(config Config) (provider, error) {
+// newProvider returns the certificates provider of the source, the server name is sent in the TLS handshake (SNI),
+// the source host is used if it is empty.
+func newProvider(config Config, serverName string) (provider, error) {
 	sourceURL, err := url.Parse(config.Source)
 	if err != nil {
 		return nil, fmt.Errorf("source parse: %v", err)
@@ -51,6 +60,12 @@ func newProvider(config Config) (provider, error) {
 		tlsCfg = &tls.Config{}
 	}
 	tlsCfg.ServerName = sourceURL.Hostname()
+	if serverName != "" {
+		tlsCfg.ServerName = serverName
+	}
+	// the chain is inspected even if it is not valid, it is verified (including the server name) in verifyChain
+	tlsCfg.InsecureSkipVerify = true
+	tlsCfg.VerifyConnection = nil
 
 	switch sourceURL.Scheme {
 	case "file":
@@ -68,26 +83,36 @@ func newProvider(config Config) (provider, error) {
 	}
 }
 
-func (f fromFile) certificates() ([]*x509.Certificate, error) {
+// certificates reads the file certificates in order, a chain file has the leaf certificate first.
+func (f fromFile) certificates() (*certChain, error) {
 	content, err := os.ReadFile(f.path)
 	if err != nil {
 		return nil, fmt.Errorf("error on reading '%s': %v", f.path, err)
 	}
 
-	block, _ := pem.Decode(content)
-	if block == nil {
-		return nil, fmt.Errorf("error on decoding '%s': %v", f.path, err)
+	var chain certChain
+	for {
+		var block *pem.Block
+		if block, content = pem.Decode(content); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error on parsing certificate '%s': %v", f.path, err)
+		}
+		chain.certs = append(chain.certs, cert)
 	}
-
-	cert, err := x509.ParseCertificate(block.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("error on parsing certificate '%s': %v", f.path, err)
+	if len(chain.certs) == 0 {
+		return nil, fmt.Errorf("error on decoding '%s': no PEM encoded certificates", f.path)
 	}
 
-	return []*x509.Certificate{cert}, nil
+	return &chain, nil
 }
 
-func (f fromNet) certificates() ([]*x509.Certificate, error) {
+func (f fromNet) certificates() (*certChain, error) {
 	ipConn, err := net.DialTimeout(f.url.Scheme, f.url.Host, f.timeout)
 	if err != nil {
 		return nil, fmt.Errorf("error on dial to '%s': %v", f.url, err)
@@ -100,11 +125,15 @@ func (f fromNet) certificates() ([]*x509.Certificate, error) {
 		return nil, fmt.Errorf("error on SSL handshake with '%s': %v", f.url, err)
 	}
 
-	certs := conn.ConnectionState().PeerCertificates
-	return certs, nil
+	state := conn.ConnectionState()
+	return &certChain{
+		certs:      state.PeerCertificates,
+		ocspStaple: state.OCSPResponse,
+		serverName: f.tlsConfig.ServerName,
+	}, nil
 }
 
-func (f fromSMTP) certificates() ([]*x509.Certificate, error) {
+func (f fromSMTP) certificates() (*certChain, error) {
 	ipConn, err := net.DialTimeout(f.url.Scheme, f.url.Host, f.timeout)
 	if err != nil {
 		return nil, fmt.Errorf("error on dial to '%s': %v", f.url, err)
@@ -127,5 +156,9 @@ func (f fromSMTP) certificates() ([]*x509.Certificate, error) {
 	if !ok {
 		return nil, fmt.Errorf("startTLS didn't succeed")
 	}
-	return conn.PeerCertificates, nil
+	return &certChain{
+		certs:      conn.PeerCertificates,
+		ocspStaple: conn.OCSPResponse,
+		serverName: f.tlsConfig.ServerName,
+	}, nil
 }
@@ -3,6 +3,7 @@
 package x509check
 
 import (
+	"crypto/x509"
 	_ "embed"
 	"time"
 
@@ -41,9 +42,10 @@ type Config struct {
 	Source            string
 	Timeout           web.Duration
 	tlscfg.TLSConfig  `yaml:",inline"`
-	DaysUntilWarn     int64 `yaml:"days_until_expiration_warning"`
-	DaysUntilCritical int64 `yaml:"days_until_expiration_critical"`
-	CheckRevocation   bool  `yaml:"check_revocation_status"`
+	DaysUntilWarn     int64    `yaml:"days_until_expiration_warning"`
+	DaysUntilCritical int64    `yaml:"days_until_expiration_critical"`
+	CheckRevocation   bool     `yaml:"check_revocation_status"`
+	Names             []string `yaml:"names"`
 }
 
 type X509Check struct {
@@ -51,6 +53,16 @@ type X509Check struct {
 	Config `yaml:",inline"`
 	charts *module.Charts
 	prov   provider
+	roots  *x509.CertPool // nil means the system roots
+
+	serverNames []*serverNameCheck
+}
+
+// serverNameCheck checks the certificates the source presents for the server name (SNI).
+type serverNameCheck struct {
+	name    string
+	prov    provider
+	charted bool
 }
 
 func (x *X509Check) Init() bool {
@@ -66,6 +78,20 @@ func (x *X509Check) Init() bool {
 	}
 	x.prov = prov
 
+	roots, err := x.initRoots()
+	if err != nil {
+		x.Errorf("init roots: %v", err)
+		return false
+	}
+	x.roots = roots
+
+	serverNames, err := x.initServerNames()
+	if err != nil {
+		x.Errorf("init server names: %v", err)
+		return false
+	}
+	x.serverNames = serverNames
+
 	x.charts = x.initCharts()
 
 	return true
@@ -3,12 +3,26 @@
 package x509check
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/netdata/go.d.plugin/agent/module"
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 
+	"golang.org/x/crypto/ocsp"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -131,6 +145,244 @@ func TestX509Check_Collect_ReturnsNilOnZeroCertificates(t *testing.T) {
 	assert.Nil(t, mx)
 }
 
+func TestX509Check_Collect_Chain(t *testing.T) {
+	root, inter, leaf := newTestChain(t, time.Now().Add(-time.Hour))
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+
+	tests := map[string]struct {
+		certs         []*x509.Certificate
+		serverName    string
+		roots         *x509.CertPool
+		wantValid     int64
+		wantNotYet    int64
+		wantChainDays int64
+	}{
+		"valid chain": {
+			certs:         []*x509.Certificate{leaf.cert, inter.cert},
+			serverName:    "example.com",
+			roots:         roots,
+			wantValid:     1,
+			wantChainDays: 9,
+		},
+		"valid chain, file source": {
+			certs:         []*x509.Certificate{leaf.cert, inter.cert},
+			roots:         roots,
+			wantValid:     1,
+			wantChainDays: 9,
+		},
+		"certificate for another host": {
+			certs:         []*x509.Certificate{leaf.cert, inter.cert},
+			serverName:    "example.org",
+			roots:         roots,
+			wantValid:     0,
+			wantChainDays: 9,
+		},
+		"unknown root": {
+			certs:         []*x509.Certificate{leaf.cert, inter.cert},
+			roots:         x509.NewCertPool(),
+			wantValid:     0,
+			wantChainDays: 9,
+		},
+		"missing intermediate": {
+			certs:         []*x509.Certificate{leaf.cert},
+			roots:         roots,
+			wantValid:     0,
+			wantChainDays: 29,
+		},
+		"not yet valid leaf": {
+			certs: func() []*x509.Certificate {
+				_, inter, leaf := newTestChain(t, time.Now().Add(time.Hour))
+				return []*x509.Certificate{leaf.cert, inter.cert}
+			}(),
+			roots:         roots,
+			wantValid:     0,
+			wantNotYet:    1,
+			wantChainDays: 9,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			x509Check := New()
+			x509Check.Source = "https://example.com"
+			require.True(t, x509Check.Init())
+			x509Check.prov = &mockProvider{certs: test.certs, serverName: test.serverName}
+			x509Check.roots = test.roots
+
+			mx := x509Check.Collect()
+			require.NotNil(t, mx)
+
+			assert.Equal(t, test.wantValid, mx["chain_valid"])
+			assert.Equal(t, test.wantNotYet, mx["not_yet_valid"])
+			assert.Equal(t, int64(29), mx["expiry"]/86400)
+			assert.Equal(t, test.wantChainDays, mx["chain_expiry"]/86400)
+		})
+	}
+}
+
+func TestX509Check_Collect_SkipVerify(t *testing.T) {
+	_, inter, leaf := newTestChain(t, time.Now().Add(-time.Hour))
+
+	x509Check := New()
+	x509Check.Source = "https://example.com"
+	x509Check.InsecureSkipVerify = true
+	require.True(t, x509Check.Init())
+	x509Check.prov = &mockProvider{certs: []*x509.Certificate{leaf.cert, inter.cert}, serverName: "example.org"}
+	x509Check.roots = x509.NewCertPool()
+
+	mx := x509Check.Collect()
+	require.NotNil(t, mx)
+
+	assert.NotContains(t, mx, "chain_valid")
+	assert.False(t, x509Check.Charts().Get("chain_validity").HasDim("chain_valid"))
+	ensureCollectedHasAllChartsDimsVarsIDs(t, x509Check, mx)
+}
+
+func TestX509Check_Collect_ServerNameVerification(t *testing.T) {
+	root, inter, leaf := newTestChain(t, time.Now().Add(-time.Hour))
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{leaf.cert.Raw, inter.cert.Raw},
+			PrivateKey:  leaf.key,
+		}},
+	})
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.cert.Raw}), 0644))
+
+	x509Check := New()
+	x509Check.Source = "https://" + ln.Addr().String()
+	x509Check.TLSConfig.TLSCA = caFile
+	x509Check.Names = []string{"example.com", "example.org"}
+	require.True(t, x509Check.Init())
+
+	mx := x509Check.Collect()
+	require.NotNil(t, mx)
+
+	assert.Equal(t, int64(1), mx["server_name_example_com_chain_valid"])
+	assert.Equal(t, int64(0), mx["server_name_example_org_chain_valid"], "the certificate is not valid for the name")
+}
+
+func TestX509Check_Collect_OCSPStaple(t *testing.T) {
+	_, inter, leaf := newTestChain(t, time.Now().Add(-time.Hour))
+
+	tests := map[string]struct {
+		status      int
+		nextUpdate  time.Time
+		wantRevoked int64
+		wantOK      bool
+	}{
+		"good": {
+			status:      ocsp.Good,
+			nextUpdate:  time.Now().Add(time.Hour),
+			wantRevoked: 0,
+			wantOK:      true,
+		},
+		"revoked": {
+			status:      ocsp.Revoked,
+			nextUpdate:  time.Now().Add(time.Hour),
+			wantRevoked: 1,
+			wantOK:      true,
+		},
+		"stale": {
+			status:     ocsp.Revoked,
+			nextUpdate: time.Now().Add(-time.Minute),
+			wantOK:     false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			staple, err := ocsp.CreateResponse(inter.cert, inter.cert, ocsp.Response{
+				Status:       test.status,
+				SerialNumber: leaf.cert.SerialNumber,
+				ThisUpdate:   time.Now().Add(-time.Hour),
+				NextUpdate:   test.nextUpdate,
+				RevokedAt:    time.Now().Add(-time.Minute),
+			}, inter.key)
+			require.NoError(t, err)
+
+			x509Check := New()
+			chain := &certChain{certs: []*x509.Certificate{leaf.cert, inter.cert}, ocspStaple: staple}
+
+			revoked, ok := x509Check.checkOCSPStaple(chain)
+			require.Equal(t, test.wantOK, ok)
+			if ok {
+				mx := make(map[string]int64)
+				x509Check.collectRevocation(mx, "", chain)
+				assert.Equal(t, test.wantRevoked, boolToInt(revoked))
+				assert.Equal(t, test.wantRevoked, mx["revoked"])
+			}
+		})
+	}
+}
+
+func TestX509Check_Collect_ServerNames(t *testing.T) {
+	_, inter, leaf := newTestChain(t, time.Now().Add(-time.Hour))
+
+	x509Check := New()
+	x509Check.Source = "https://127.0.0.1:443"
+	x509Check.Names = []string{"example.com", "example.org"}
+	require.True(t, x509Check.Init())
+	require.Len(t, x509Check.serverNames, 2)
+	require.Empty(t, *x509Check.Charts())
+
+	x509Check.serverNames[0].prov = &mockProvider{certs: []*x509.Certificate{leaf.cert, inter.cert}}
+	x509Check.serverNames[1].prov = &mockProvider{err: true}
+
+	mx := x509Check.Collect()
+	require.NotNil(t, mx)
+
+	assert.Len(t, *x509Check.Charts(), len(baseCharts))
+	for _, chart := range *x509Check.Charts() {
+		assert.True(t, strings.HasPrefix(chart.ID, "server_name_example_com_"), chart.ID)
+		assert.Contains(t, chart.Labels, module.Label{Key: "san", Value: "example.com,www.example.com"})
+		assert.Contains(t, chart.Labels, module.Label{Key: "server_name", Value: "example.com"})
+	}
+	ensureCollectedHasAllChartsDimsVarsIDs(t, x509Check, mx)
+	assert.NotContains(t, mx, "server_name_example_org_expiry")
+}
+
+func TestX509Check_Init_ServerNamesFileSource(t *testing.T) {
+	x509Check := New()
+	x509Check.Source = "file:///home/me/cert.pem"
+	x509Check.Names = []string{"example.com"}
+
+	assert.False(t, x509Check.Init())
+}
+
+func TestFromFile_Chain(t *testing.T) {
+	_, inter, leaf := newTestChain(t, time.Now().Add(-time.Hour))
+
+	var content []byte
+	for _, cert := range []*x509.Certificate{leaf.cert, inter.cert} {
+		content = append(content, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	path := filepath.Join(t.TempDir(), "chain.pem")
+	require.NoError(t, os.WriteFile(path, content, 0644))
+
+	chain, err := fromFile{path: path}.certificates()
+	require.NoError(t, err)
+	require.Len(t, chain.certs, 2)
+	assert.Equal(t, leaf.cert.Raw, chain.certs[0].Raw)
+	assert.Equal(t, inter.cert.Raw, chain.certs[1].Raw)
+}
+
 func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, x509Check *X509Check, collected map[string]int64) {
 	for _, chart := range *x509Check.Charts() {
 		for _, dim := range chart.Dims {
@@ -145,13 +397,72 @@ func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, x509Check *X509Check,
 }
 
 type mockProvider struct {
-	certs []*x509.Certificate
-	err   bool
+	certs      []*x509.Certificate
+	ocspStaple []byte
+	serverName string
+	err        bool
 }
 
-func (m mockProvider) certificates() ([]*x509.Certificate, error) {
+func (m mockProvider) certificates() (*certChain, error) {
 	if m.err {
 		return nil, errors.New("mock certificates error")
 	}
-	return m.certs, nil
+	return &certChain{certs: m.certs, ocspStaple: m.ocspStaple, serverName: m.serverName}, nil
+}
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert returns a certificate signed by the parent, self-signed if the parent is nil.
+func newTestCert(t *testing.T, tmpl *x509.Certificate, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCert{cert: cert, key: key}
+}
+
+// newTestChain returns the root, the intermediate and the leaf for 'example.com', the intermediate expires first.
+func newTestChain(t *testing.T, leafNotBefore time.Time) (root, inter, leaf *testCert) {
+	now := time.Now()
+	root = newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour * 24 * 365),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	inter = newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "Test Intermediate"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour * 24 * 10),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, root)
+	leaf = newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		NotBefore:    leafNotBefore,
+		NotAfter:     now.Add(time.Hour * 24 * 30),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, inter)
+
+	return root, inter, leaf
 }
@@ -109,3 +109,132 @@ var charts = Charts{
 		},
 	},
 }
+
+// optionalCharts depend on the ZooKeeper version, the server mode (leader only metrics) and the allowed
+// four letter word commands, they are added once their metrics are collected.
+var optionalCharts = Charts{
+	{
+		ID:    "uptime",
+		Title: "Uptime",
+		Units: "seconds",
+		Fam:   "uptime",
+		Ctx:   "zookeeper.uptime",
+		Dims: Dims{
+			{ID: "uptime", Name: "uptime", Div: 1000},
+		},
+	},
+	{
+		ID:    "liveness",
+		Title: "Liveness (ruok)",
+		Units: "status",
+		Fam:   "server state",
+		Ctx:   "zookeeper.liveness",
+		Dims: Dims{
+			{ID: "ruok", Name: "imok"},
+		},
+	},
+	{
+		ID:    "server_mode",
+		Title: "Server Mode",
+		Units: "mode",
+		Fam:   "server state",
+		Ctx:   "zookeeper.server_mode",
+		Dims: Dims{
+			{ID: "server_mode_leader", Name: "leader"},
+			{ID: "server_mode_follower", Name: "follower"},
+			{ID: "server_mode_observer", Name: "observer"},
+			{ID: "server_mode_standalone", Name: "standalone"},
+			{ID: "server_mode_read_only", Name: "read_only"},
+		},
+	},
+	{
+		ID:    "zxid_epoch",
+		Title: "Last Processed Zxid Epoch",
+		Units: "epoch",
+		Fam:   "ensemble",
+		Ctx:   "zookeeper.zxid_epoch",
+		Dims: Dims{
+			{ID: "zxid_epoch", Name: "epoch"},
+		},
+	},
+	{
+		ID:    "zxid_counter",
+		Title: "Last Processed Zxid Counter",
+		Units: "transactions",
+		Fam:   "ensemble",
+		Ctx:   "zookeeper.zxid_counter",
+		Dims: Dims{
+			{ID: "zxid_counter", Name: "counter"},
+		},
+	},
+	{
+		ID:    "quorum_size",
+		Title: "Quorum Size",
+		Units: "servers",
+		Fam:   "ensemble",
+		Ctx:   "zookeeper.quorum_size",
+		Dims: Dims{
+			{ID: "quorum_size", Name: "quorum"},
+		},
+	},
+	{
+		ID:    "synced_followers",
+		Title: "Synced Followers",
+		Units: "servers",
+		Fam:   "ensemble",
+		Ctx:   "zookeeper.synced_followers",
+		Dims: Dims{
+			{ID: "synced_followers", Name: "followers"},
+			{ID: "synced_non_voting_followers", Name: "non_voting_followers"},
+			{ID: "synced_observers", Name: "observers"},
+		},
+	},
+	{
+		ID:    "pending_syncs",
+		Title: "Pending Syncs",
+		Units: "syncs",
+		Fam:   "ensemble",
+		Ctx:   "zookeeper.pending_syncs",
+		Dims: Dims{
+			{ID: "pending_syncs", Name: "pending"},
+		},
+	},
+	{
+		ID:    "proposal_size",
+		Title: "Proposal Size",
+		Units: "bytes",
+		Fam:   "ensemble",
+		Ctx:   "zookeeper.proposal_size",
+		Dims: Dims{
+			{ID: "last_proposal_size", Name: "last"},
+			{ID: "min_proposal_size", Name: "min"},
+			{ID: "max_proposal_size", Name: "max"},
+		},
+	},
+}
+
+// addOptionalCharts adds the optional charts with the dimensions that have values,
+// the leader only charts appear when the server becomes the leader.
+func (z *Zookeeper) addOptionalCharts(mx map[string]int64) {
+	for _, tmpl := range optionalCharts {
+		if z.charts.Has(tmpl.ID) {
+			continue
+		}
+
+		chart := tmpl.Copy()
+		dims := chart.Dims
+		chart.Dims = nil
+		for _, dim := range dims {
+			if _, ok := mx[dim.ID]; ok {
+				chart.Dims = append(chart.Dims, dim)
+			}
+		}
+		if len(chart.Dims) == 0 {
+			continue
+		}
+
+		if err := z.charts.Add(chart); err != nil {
+			z.Warning(err)
+		}
+	}
+}
@@ -8,8 +8,40 @@ import (
 	"strings"
 )
 
+var serverModes = []string{"leader", "follower", "observer", "standalone", "read-only"}
+
 func (z *Zookeeper) collect() (map[string]int64, error) {
-	return z.collectMntr()
+	var mx map[string]int64
+	var err error
+
+	if z.httpClient != nil {
+		if mx, err = z.collectAdminServer(); err != nil {
+			z.Warningf("AdminServer: %v, falling back to the four letter word commands", err)
+		}
+	}
+	if mx == nil {
+		if mx, err = z.collectFourLetterWords(); err != nil {
+			return nil, err
+		}
+	}
+
+	z.addOptionalCharts(mx)
+
+	return mx, nil
+}
+
+func (z *Zookeeper) collectFourLetterWords() (map[string]int64, error) {
+	mx, err := z.collectMntr()
+	if err != nil {
+		return nil, err
+	}
+	if z.doSrvr {
+		z.collectSrvr(mx)
+	}
+	if z.doRuok {
+		z.collectRuok(mx)
+	}
+	return mx, nil
 }
 
 func (z *Zookeeper) collectMntr() (map[string]int64, error) {
@@ -22,7 +54,9 @@ func (z *Zookeeper) collectMntr() (map[string]int64, error) {
 	case 0:
 		return nil, fmt.Errorf("'%s' command returned empty response", command)
 	case 1:
-		// mntr is not executed because it is not in the whitelist.
+		if isNotInWhitelist(lines) {
+			return nil, errNotInWhitelist(command)
+		}
 		return nil, fmt.Errorf("'%s' command returned bad response: %s", command, lines[0])
 	}
 
@@ -32,31 +66,98 @@ func (z *Zookeeper) collectMntr() (map[string]int64, error) {
 		if len(parts) != 2 || !strings.HasPrefix(parts[0], "zk_") {
 			continue
 		}
+		collectMonitorValue(mx, strings.TrimPrefix(parts[0], "zk_"), parts[1])
+	}
 
-		key, value := strings.TrimPrefix(parts[0], "zk_"), parts[1]
-		switch key {
-		case "version":
-		case "server_state":
-			mx[key] = convertServerState(value)
-		case "min_latency", "avg_latency", "max_latency":
-			v, err := strconv.ParseFloat(value, 64)
-			if err != nil {
-				continue
-			}
-			mx[key] = int64(v * 1000)
-		default:
-			v, err := strconv.ParseFloat(value, 64)
+	if len(mx) == 0 {
+		return nil, fmt.Errorf("'%s' command: failed to parse response", command)
+	}
+	return mx, nil
+}
+
+// collectMonitorValue writes a 'mntr' (without the 'zk_' prefix) or AdminServer 'monitor' value,
+// both transports share the keys and the charts.
+func collectMonitorValue(mx map[string]int64, key, value string) {
+	switch key {
+	case "version":
+	case "server_state":
+		mx[key] = convertServerState(value)
+		collectServerMode(mx, value)
+	case "min_latency", "avg_latency", "max_latency":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return
+		}
+		mx[key] = int64(v * 1000)
+	default:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return
+		}
+		mx[key] = int64(v)
+	}
+}
+
+// collectSrvr parses the 'Zxid' and 'Mode' lines of the 'srvr' response.
+func (z *Zookeeper) collectSrvr(mx map[string]int64) {
+	const command = "srvr"
+	lines, err := z.fetch(command)
+	if err != nil {
+		z.Warningf("'%s' command: %v", command, err)
+		return
+	}
+	if isNotInWhitelist(lines) {
+		z.Warning(errNotInWhitelist(command))
+		z.doSrvr = false
+		return
+	}
+
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch name {
+		case "Zxid":
+			v, err := strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 64)
 			if err != nil {
 				continue
 			}
-			mx[key] = int64(v)
+			collectZxid(mx, v)
+		case "Mode":
+			collectServerMode(mx, value)
 		}
 	}
+}
 
-	if len(mx) == 0 {
-		return nil, fmt.Errorf("'%s' command: failed to parse response", command)
+// collectRuok checks the server liveness, it answers 'imok' if it is running and doesn't respond at all otherwise.
+func (z *Zookeeper) collectRuok(mx map[string]int64) {
+	const command = "ruok"
+	lines, err := z.fetch(command)
+	if err != nil {
+		z.Debugf("'%s' command: %v", command, err)
+	}
+	if isNotInWhitelist(lines) {
+		z.Warning(errNotInWhitelist(command))
+		z.doRuok = false
+		return
+	}
+
+	mx["ruok"] = boolToInt(len(lines) > 0 && strings.TrimSpace(lines[0]) == "imok")
+}
+
+// collectZxid splits the last processed zxid: the high 32 bits are the leader epoch, the low 32 bits are the transaction counter.
+func collectZxid(mx map[string]int64, zxid uint64) {
+	mx["zxid_epoch"] = int64(zxid >> 32)
+	mx["zxid_counter"] = int64(zxid & 0xffffffff)
+}
+
+func collectServerMode(mx map[string]int64, mode string) {
+	for _, v := range serverModes {
+		mx["server_mode_"+strings.ReplaceAll(v, "-", "_")] = boolToInt(v == mode)
 	}
-	return mx, nil
 }
 
 func convertServerState(state string) int64 {
@@ -73,3 +174,21 @@ func convertServerState(state string) int64 {
 		return 4
 	}
 }
+
+// isNotInWhitelist reports whether the response is "<command> is not executed because it is not in the whitelist.",
+// since ZooKeeper 3.5 only 'srvr' is allowed by default.
+func isNotInWhitelist(lines []string) bool {
+	return len(lines) == 1 && strings.HasSuffix(strings.TrimSpace(lines[0]), "is not in the whitelist.")
+}
+
+func errNotInWhitelist(command string) error {
+	return fmt.Errorf("'%s' command is not allowed by the server, add it to the '4lw.commands.whitelist' "+
+		"property in zoo.cfg (e.g. '4lw.commands.whitelist=mntr,srvr,ruok') and restart ZooKeeper", command)
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package zookeeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// collectAdminServer pulls the AdminServer 'monitor' command, it has the same fields as 'mntr' without the 'zk_' prefix.
+func (z *Zookeeper) collectAdminServer() (map[string]int64, error) {
+	const command = "monitor"
+
+	var monitor map[string]interface{}
+	if err := z.doAdminCommand(command, &monitor); err != nil {
+		return nil, err
+	}
+
+	mx := make(map[string]int64)
+	for key, value := range monitor {
+		if !collectedZKKeys["zk_"+key] {
+			continue
+		}
+		switch v := value.(type) {
+		case float64:
+			collectMonitorValue(mx, key, strconv.FormatFloat(v, 'f', -1, 64))
+		case string:
+			collectMonitorValue(mx, key, v)
+		}
+	}
+	if len(mx) == 0 {
+		return nil, fmt.Errorf("'%s' command: no metrics in the response (ZooKeeper version < 3.6?)", command)
+	}
+
+	var stats struct {
+		ServerStats struct {
+			LastProcessedZxid *uint64 `json:"last_processed_zxid"`
+		} `json:"server_stats"`
+	}
+	if err := z.doAdminCommand("server_stats", &stats); err != nil {
+		z.Debug(err)
+	} else if stats.ServerStats.LastProcessedZxid != nil {
+		collectZxid(mx, *stats.ServerStats.LastProcessedZxid)
+	}
+
+	var ruok struct {
+		Error *string `json:"error"`
+	}
+	err := z.doAdminCommand("ruok", &ruok)
+	mx["ruok"] = boolToInt(err == nil && ruok.Error == nil)
+
+	return mx, nil
+}
+
+func (z *Zookeeper) doAdminCommand(command string, dst interface{}) error {
+	u := strings.TrimSuffix(z.URL, "/") + "/" + command
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("error on creating request to '%s': %v", u, err)
+	}
+
+	resp, err := z.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error on request to '%s': %v", u, err)
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("'%s' returned HTTP status code: %d", u, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("error on decoding response from '%s': %v", u, err)
+	}
+	return nil
+}
+
+func closeBody(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
@@ -56,18 +56,27 @@ func unsafeString(b []byte) string {
 }
 
 var collectedZKKeys = map[string]bool{
-	"zk_num_alive_connections":      true,
-	"zk_outstanding_requests":       true,
-	"zk_min_latency":                true,
-	"zk_avg_latency":                true,
-	"zk_max_latency":                true,
-	"zk_packets_received":           true,
-	"zk_packets_sent":               true,
-	"zk_open_file_descriptor_count": true,
-	"zk_max_file_descriptor_count":  true,
-	"zk_znode_count":                true,
-	"zk_ephemerals_count":           true,
-	"zk_watch_count":                true,
-	"zk_approximate_data_size":      true,
-	"zk_server_state":               true,
+	"zk_num_alive_connections":       true,
+	"zk_outstanding_requests":        true,
+	"zk_min_latency":                 true,
+	"zk_avg_latency":                 true,
+	"zk_max_latency":                 true,
+	"zk_packets_received":            true,
+	"zk_packets_sent":                true,
+	"zk_open_file_descriptor_count":  true,
+	"zk_max_file_descriptor_count":   true,
+	"zk_znode_count":                 true,
+	"zk_ephemerals_count":            true,
+	"zk_watch_count":                 true,
+	"zk_approximate_data_size":       true,
+	"zk_server_state":                true,
+	"zk_uptime":                      true,
+	"zk_quorum_size":                 true,
+	"zk_synced_followers":            true,
+	"zk_synced_non_voting_followers": true,
+	"zk_synced_observers":            true,
+	"zk_pending_syncs":               true,
+	"zk_last_proposal_size":          true,
+	"zk_min_proposal_size":           true,
+	"zk_max_proposal_size":           true,
 }
@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	_ "embed"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/socket"
@@ -27,7 +28,10 @@ func init() {
 
 // Config is the Zookeeper module configuration.
 type Config struct {
-	Address          string
+	Address string
+	// URL is the AdminServer commands endpoint (ZooKeeper 3.6+), e.g. 'http://127.0.0.1:8080/commands'.
+	// If set, the metrics are pulled from the 'monitor' command, the four letter words are the fallback.
+	URL              string       `yaml:"url"`
 	Timeout          web.Duration `yaml:"timeout"`
 	UseTLS           bool         `yaml:"use_tls"`
 	tlscfg.TLSConfig `yaml:",inline"`
@@ -40,7 +44,12 @@ func New() *Zookeeper {
 		Timeout: web.Duration{Duration: time.Second},
 		UseTLS:  false,
 	}
-	return &Zookeeper{Config: config}
+	return &Zookeeper{
+		Config: config,
+		charts: charts.Copy(),
+		doSrvr: true,
+		doRuok: true,
+	}
 }
 
 type fetcher interface {
@@ -52,6 +61,13 @@ type Zookeeper struct {
 	module.Base
 	fetcher
 	Config `yaml:",inline"`
+
+	charts     *Charts
+	httpClient *http.Client
+
+	// srvr and ruok are disabled if they are not in the server '4lw.commands.whitelist'
+	doSrvr bool
+	doRuok bool
 }
 
 // Cleanup makes cleanup.
@@ -77,6 +93,17 @@ func (z *Zookeeper) createZookeeperFetcher() (err error) {
 	return nil
 }
 
+func (z *Zookeeper) createAdminServerClient() (err error) {
+	z.httpClient, err = web.NewHTTPClient(web.Client{
+		Timeout:   z.Timeout,
+		TLSConfig: z.TLSConfig,
+	})
+	if err != nil {
+		return fmt.Errorf("error on creating AdminServer http client : %v", err)
+	}
+	return nil
+}
+
 // Init makes initialization.
 func (z *Zookeeper) Init() bool {
 	err := z.createZookeeperFetcher()
@@ -85,6 +112,13 @@ func (z *Zookeeper) Init() bool {
 		return false
 	}
 
+	if z.URL != "" {
+		if err := z.createAdminServerClient(); err != nil {
+			z.Error(err)
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -94,8 +128,8 @@ func (z *Zookeeper) Check() bool {
 }
 
 // Charts creates Charts.
-func (Zookeeper) Charts() *Charts {
-	return charts.Copy()
+func (z *Zookeeper) Charts() *Charts {
+	return z.charts
 }
 
 // Collect collects metrics.
@@ -6,6 +6,8 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -16,11 +18,19 @@ import (
 var (
 	testMntrData, _               = os.ReadFile("testdata/mntr.txt")
 	testMntrNotInWhiteListData, _ = os.ReadFile("testdata/mntr_notinwhitelist.txt")
+	testMntrLeaderData, _         = os.ReadFile("testdata/mntr_leader.txt")
+	testSrvrData, _               = os.ReadFile("testdata/srvr.txt")
+	testAdminMonitorData, _       = os.ReadFile("testdata/admin_monitor.json")
+	testAdminServerStatsData, _   = os.ReadFile("testdata/admin_server_stats.json")
 )
 
 func Test_testDataLoad(t *testing.T) {
 	assert.NotNil(t, testMntrData)
 	assert.NotNil(t, testMntrNotInWhiteListData)
+	assert.NotNil(t, testMntrLeaderData)
+	assert.NotNil(t, testSrvrData)
+	assert.NotNil(t, testAdminMonitorData)
+	assert.NotNil(t, testAdminServerStatsData)
 }
 
 func TestNew(t *testing.T) {
@@ -44,6 +54,14 @@ func TestZookeeper_InitErrorOnCreatingTLSConfig(t *testing.T) {
 	assert.False(t, job.Init())
 }
 
+func TestZookeeper_InitAdminServer(t *testing.T) {
+	job := New()
+	job.URL = "http://127.0.0.1:8080/commands"
+
+	assert.True(t, job.Init())
+	assert.NotNil(t, job.httpClient)
+}
+
 func TestZookeeper_Check(t *testing.T) {
 	job := New()
 	require.True(t, job.Init())
@@ -71,7 +89,7 @@ func TestZookeeper_Cleanup(t *testing.T) {
 func TestZookeeper_Collect(t *testing.T) {
 	job := New()
 	require.True(t, job.Init())
-	job.fetcher = &mockZookeeperFetcher{data: testMntrData}
+	job.fetcher = &mockZookeeperFetcher{data: testMntrData, srvr: []byte("Zxid: 0x2\nMode: standalone\n"), ruok: []byte("imok")}
 
 	expected := map[string]int64{
 		"approximate_data_size":      44,
@@ -85,9 +103,18 @@ func TestZookeeper_Collect(t *testing.T) {
 		"outstanding_requests":       0,
 		"packets_received":           92,
 		"packets_sent":               182,
+		"ruok":                       1,
 		"server_state":               4,
+		"server_mode_follower":       0,
+		"server_mode_leader":         0,
+		"server_mode_observer":       0,
+		"server_mode_read_only":      0,
+		"server_mode_standalone":     1,
+		"uptime":                     27595191,
 		"watch_count":                0,
 		"znode_count":                5,
+		"zxid_counter":               2,
+		"zxid_epoch":                 0,
 	}
 
 	collected := job.Collect()
@@ -96,12 +123,102 @@ func TestZookeeper_Collect(t *testing.T) {
 	ensureCollectedHasAllChartsDimsVarsIDs(t, job, collected)
 }
 
+func TestZookeeper_CollectLeader(t *testing.T) {
+	job := New()
+	require.True(t, job.Init())
+	job.fetcher = &mockZookeeperFetcher{data: testMntrLeaderData, srvr: testSrvrData, ruok: []byte("imok")}
+
+	collected := job.Collect()
+
+	assert.Equal(t, expectedLeaderMetrics(), collected)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, job, collected)
+	for _, id := range []string{"uptime", "liveness", "server_mode", "zxid_epoch", "zxid_counter",
+		"quorum_size", "synced_followers", "pending_syncs", "proposal_size"} {
+		assert.Truef(t, job.Charts().Has(id), "chart '%s' is not added", id)
+	}
+}
+
+func TestZookeeper_CollectRuokNoResponse(t *testing.T) {
+	job := New()
+	require.True(t, job.Init())
+	job.fetcher = &mockZookeeperFetcher{data: testMntrData, srvr: testSrvrData}
+
+	collected := job.Collect()
+
+	require.NotNil(t, collected)
+	assert.Equal(t, int64(0), collected["ruok"])
+	assert.True(t, job.doRuok)
+}
+
+func TestZookeeper_CollectSrvrRuokNotInWhiteList(t *testing.T) {
+	job := New()
+	require.True(t, job.Init())
+	job.fetcher = &mockZookeeperFetcher{
+		data: testMntrData,
+		srvr: []byte("srvr is not executed because it is not in the whitelist."),
+		ruok: []byte("ruok is not executed because it is not in the whitelist."),
+	}
+
+	collected := job.Collect()
+
+	require.NotNil(t, collected)
+	assert.False(t, job.doSrvr)
+	assert.False(t, job.doRuok)
+	assert.NotContains(t, collected, "ruok")
+	assert.NotContains(t, collected, "zxid_epoch")
+	assert.False(t, job.Charts().Has("liveness"))
+}
+
 func TestZookeeper_CollectMntrNotInWhiteList(t *testing.T) {
 	job := New()
 	require.True(t, job.Init())
 	job.fetcher = &mockZookeeperFetcher{data: testMntrNotInWhiteListData}
 
 	assert.Nil(t, job.Collect())
+
+	_, err := job.collect()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "4lw.commands.whitelist")
+}
+
+func TestZookeeper_CollectAdminServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/commands/monitor":
+			_, _ = w.Write(testAdminMonitorData)
+		case "/commands/server_stats":
+			_, _ = w.Write(testAdminServerStatsData)
+		case "/commands/ruok":
+			_, _ = w.Write([]byte(`{"command":"ruok","error":null}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	job := New()
+	job.URL = srv.URL + "/commands/"
+	require.True(t, job.Init())
+	job.fetcher = &mockZookeeperFetcher{err: true}
+
+	collected := job.Collect()
+
+	assert.Equal(t, expectedLeaderMetrics(), collected)
+	ensureCollectedHasAllChartsDimsVarsIDs(t, job, collected)
+}
+
+func TestZookeeper_CollectAdminServerFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	job := New()
+	job.URL = srv.URL + "/commands"
+	require.True(t, job.Init())
+	job.fetcher = &mockZookeeperFetcher{data: testMntrLeaderData, srvr: testSrvrData, ruok: []byte("imok")}
+
+	assert.Equal(t, expectedLeaderMetrics(), job.Collect())
 }
 
 func TestZookeeper_CollectMntrEmptyResponse(t *testing.T) {
@@ -144,18 +261,64 @@ func ensureCollectedHasAllChartsDimsVarsIDs(t *testing.T, zk *Zookeeper, collect
 	}
 }
 
+func expectedLeaderMetrics() map[string]int64 {
+	return map[string]int64{
+		"approximate_data_size":       180,
+		"avg_latency":                 500,
+		"ephemerals_count":            2,
+		"last_proposal_size":          92,
+		"max_file_descriptor_count":   1048576,
+		"max_latency":                 12000,
+		"max_proposal_size":           1024,
+		"min_latency":                 0,
+		"min_proposal_size":           36,
+		"num_alive_connections":       3,
+		"open_file_descriptor_count":  71,
+		"outstanding_requests":        0,
+		"packets_received":            4096,
+		"packets_sent":                5120,
+		"pending_syncs":               0,
+		"quorum_size":                 3,
+		"ruok":                        1,
+		"server_mode_follower":        0,
+		"server_mode_leader":          1,
+		"server_mode_observer":        0,
+		"server_mode_read_only":       0,
+		"server_mode_standalone":      0,
+		"server_state":                1,
+		"synced_followers":            2,
+		"synced_non_voting_followers": 0,
+		"synced_observers":            0,
+		"uptime":                      3600512,
+		"watch_count":                 4,
+		"znode_count":                 9,
+		"zxid_counter":                42,
+		"zxid_epoch":                  3,
+	}
+}
+
 type mockZookeeperFetcher struct {
-	data []byte
+	data []byte // mntr
+	srvr []byte
+	ruok []byte
 	err  bool
 }
 
-func (m mockZookeeperFetcher) fetch(_ string) ([]string, error) {
+func (m mockZookeeperFetcher) fetch(command string) ([]string, error) {
 	if m.err {
 		return nil, errors.New("mock fetch error")
 	}
 
+	data := m.data
+	switch command {
+	case "srvr":
+		data = m.srvr
+	case "ruok":
+		data = m.ruok
+	}
+
 	var lines []string
-	s := bufio.NewScanner(bytes.NewReader(m.data))
+	s := bufio.NewScanner(bytes.NewReader(data))
 	for s.Scan() {
 		if !isZKLine(s.Bytes()) || isMntrLineOK(s.Bytes()) {
 			lines = append(lines, s.Text())
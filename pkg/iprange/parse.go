@@ -4,6 +4,7 @@ package iprange
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
@@ -25,10 +26,14 @@ func ParseRanges(s string) ([]Range, error) {
 	}
 
 	var ranges []Range
-	for _, v := range parts {
+	for i, v := range parts {
+		if strings.HasPrefix(v, excludePrefix) {
+			return nil, fmt.Errorf("token #%d '%s': exclusions are not supported here", i+1, v)
+		}
+
 		r, err := ParseRange(v)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("token #%d: %v", i+1, err)
 		}
 
 		if r != nil {
@@ -38,6 +43,33 @@ func ParseRanges(s string) ([]Range, error) {
 	return ranges, nil
 }
 
+// ParseSet parses s as a space separated list of IP Ranges, returning the result and an error if any.
+// The IP Range format is the same as in ParseRanges, the '!' prefix excludes the IP Range from the set,
+// e.g. "10.0.0.0/16 !10.0.5.0/24 !10.0.0.1".
+func ParseSet(s string) (*Set, error) {
+	var include, exclude []Range
+	for i, v := range strings.Fields(s) {
+		isExclude := strings.HasPrefix(v, excludePrefix)
+
+		r, err := ParseRange(strings.TrimPrefix(v, excludePrefix))
+		if err != nil {
+			return nil, fmt.Errorf("token #%d: %v", i+1, err)
+		}
+		if r == nil {
+			return nil, fmt.Errorf("token #%d '%s': empty ip range", i+1, v)
+		}
+
+		if isExclude {
+			exclude = append(exclude, r)
+		} else {
+			include = append(include, r)
+		}
+	}
+	return NewSet(include, exclude), nil
+}
+
+const excludePrefix = "!"
+
 var (
 	reRange      = regexp.MustCompile("^[0-9a-f.:-]+$")           // addr | addr-addr
 	reCIDR       = regexp.MustCompile("^[0-9a-f.:]+/[0-9]{1,3}$") // addr/prefix_length
@@ -51,42 +83,60 @@ var (
 // or IPv6 CIDR ("2001:db8::/64") form.
 // IPv4 CIDR, IPv4 subnet mask and IPv6 CIDR ranges don't include network and broadcast addresses.
 func ParseRange(s string) (Range, error) {
-	s = strings.ToLower(s)
-	if s == "" {
+	v := strings.ToLower(s)
+	if v == "" {
 		return nil, nil
 	}
 
 	var r Range
+	var err error
 	switch {
-	case reRange.MatchString(s):
-		r = parseRange(s)
-	case reCIDR.MatchString(s):
-		r = parseCIDR(s)
-	case reSubnetMask.MatchString(s):
-		r = parseSubnetMask(s)
+	case reRange.MatchString(v):
+		r, err = parseRange(v)
+	case reCIDR.MatchString(v):
+		r, err = parseCIDR(v)
+	case reSubnetMask.MatchString(v):
+		r, err = parseSubnetMask(v)
+	default:
+		err = errors.New("invalid syntax")
 	}
 
-	if r == nil {
-		return nil, fmt.Errorf("ip range (%s) invalid syntax", s)
+	if err != nil {
+		return nil, fmt.Errorf("ip range '%s': %v", s, err)
 	}
 	return r, nil
 }
 
-func parseRange(s string) Range {
+func parseRange(s string) (Range, error) {
 	var start, end net.IP
 	if idx := strings.IndexByte(s, '-'); idx != -1 {
-		start, end = net.ParseIP(s[:idx]), net.ParseIP(s[idx+1:])
+		if start = net.ParseIP(s[:idx]); start == nil {
+			return nil, fmt.Errorf("invalid start address '%s'", s[:idx])
+		}
+		if end = net.ParseIP(s[idx+1:]); end == nil {
+			return nil, fmt.Errorf("invalid end address '%s'", s[idx+1:])
+		}
 	} else {
-		start, end = net.ParseIP(s), net.ParseIP(s)
+		if start = net.ParseIP(s); start == nil {
+			return nil, fmt.Errorf("invalid address '%s'", s)
+		}
+		end = start
 	}
 
-	return New(start, end)
+	if isV4IP(start) != isV4IP(end) {
+		return nil, errors.New("start and end addresses have different address families")
+	}
+	r := New(start, end)
+	if r == nil {
+		return nil, errors.New("start address is greater than end address")
+	}
+	return r, nil
 }
 
-func parseCIDR(s string) Range {
+func parseCIDR(s string) (Range, error) {
 	ip, network, err := net.ParseCIDR(s)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("invalid CIDR: %v", err)
 	}
 
 	start, end := cidr.AddressRange(network)
@@ -100,22 +150,22 @@ func parseCIDR(s string) Range {
 	return parseRange(fmt.Sprintf("%s-%s", start, end))
 }
 
-func parseSubnetMask(s string) Range {
+func parseSubnetMask(s string) (Range, error) {
 	idx := strings.LastIndexByte(s, '/')
 	if idx == -1 {
-		return nil
+		return nil, errors.New("missing subnet mask")
 	}
 
 	address, mask := s[:idx], s[idx+1:]
 
 	ip := net.ParseIP(mask).To4()
 	if ip == nil {
-		return nil
+		return nil, fmt.Errorf("invalid subnet mask '%s'", mask)
 	}
 
 	prefixLen, bits := net.IPv4Mask(ip[0], ip[1], ip[2], ip[3]).Size()
 	if prefixLen+bits == 0 {
-		return nil
+		return nil, fmt.Errorf("not canonical subnet mask '%s'", mask)
 	}
 
 	return parseCIDR(fmt.Sprintf("%s/%d", address, prefixLen))
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"math/big"
+	"net"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// Set is a set of IP addresses: the included IP Ranges minus the excluded ones.
+// The ranges are stored as sorted non-overlapping intervals, the set is never materialized.
+type Set struct {
+	intervals []interval
+}
+
+type interval struct {
+	start netip.Addr
+	end   netip.Addr
+}
+
+// NewSet returns a new Set that contains the addresses of the include ranges that are not in the exclude ranges.
+func NewSet(include, exclude []Range) *Set {
+	return &Set{intervals: subtractIntervals(toIntervals(include), toIntervals(exclude))}
+}
+
+// String returns the string form of the set.
+func (s *Set) String() string {
+	var b strings.Builder
+	for _, iv := range s.intervals {
+		b.WriteString(iv.start.String() + "-" + iv.end.String() + " ")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Size reports the number of IP addresses in the set.
+func (s *Set) Size() *big.Int {
+	size := big.NewInt(0)
+	for _, iv := range s.intervals {
+		size.Add(size, iv.size())
+	}
+	return size
+}
+
+// Contains reports whether the set includes IP.
+func (s *Set) Contains(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+
+	i := sort.Search(len(s.intervals), func(i int) bool { return s.intervals[i].end.Compare(addr) >= 0 })
+	return i < len(s.intervals) && s.intervals[i].start.Compare(addr) <= 0
+}
+
+// Iterator returns an iterator over the IP addresses of the set in ascending order.
+func (s *Set) Iterator() *Iterator {
+	return &Iterator{intervals: s.intervals}
+}
+
+// Iterator iterates over the IP addresses of a Set, it doesn't allocate the addresses upfront.
+type Iterator struct {
+	intervals []interval
+	idx       int
+	cur       netip.Addr
+}
+
+// Next advances the iterator to the next IP address, it returns false when the iteration is over.
+func (it *Iterator) Next() bool {
+	if it.cur.IsValid() {
+		if it.cur != it.intervals[it.idx].end {
+			it.cur = it.cur.Next()
+			return true
+		}
+		it.idx++
+	}
+	if it.idx >= len(it.intervals) {
+		it.cur = netip.Addr{}
+		return false
+	}
+	it.cur = it.intervals[it.idx].start
+	return true
+}
+
+// IP returns the current IP address.
+func (it *Iterator) IP() net.IP {
+	if !it.cur.IsValid() {
+		return nil
+	}
+	return it.cur.AsSlice()
+}
+
+func (iv interval) size() *big.Int {
+	start, end := iv.start.As16(), iv.end.As16()
+	size := big.NewInt(0).SetBytes(end[:])
+	size.Sub(size, big.NewInt(0).SetBytes(start[:]))
+	return size.Add(size, big.NewInt(1))
+}
+
+func toIntervals(ranges []Range) []interval {
+	ivs := make([]interval, 0, len(ranges))
+	for _, r := range ranges {
+		var start, end net.IP
+		switch v := r.(type) {
+		case v4Range:
+			start, end = v.start, v.end
+		case v6Range:
+			start, end = v.start, v.end
+		default:
+			continue
+		}
+		s, ok1 := netip.AddrFromSlice(start)
+		e, ok2 := netip.AddrFromSlice(end)
+		if ok1 && ok2 {
+			ivs = append(ivs, interval{start: s.Unmap(), end: e.Unmap()})
+		}
+	}
+	return mergeIntervals(ivs)
+}
+
+// mergeIntervals sorts the intervals and merges the overlapping and adjacent ones.
+func mergeIntervals(ivs []interval) []interval {
+	if len(ivs) == 0 {
+		return nil
+	}
+	sort.Slice(ivs, func(i, j int) bool { return ivs[i].start.Less(ivs[j].start) })
+
+	merged := ivs[:1]
+	for _, iv := range ivs[1:] {
+		last := &merged[len(merged)-1]
+		if next := last.end.Next(); iv.start.Compare(last.end) <= 0 || next == iv.start {
+			if last.end.Less(iv.end) {
+				last.end = iv.end
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// subtractIntervals returns the include intervals minus the exclude intervals, both must be merged.
+func subtractIntervals(include, exclude []interval) []interval {
+	var res []interval
+	for _, in := range include {
+		start := in.start
+		for _, ex := range exclude {
+			if ex.end.Less(start) || in.end.Less(ex.start) {
+				continue
+			}
+			if start.Less(ex.start) {
+				res = append(res, interval{start: start, end: ex.start.Prev()})
+			}
+			if !ex.end.Less(in.end) {
+				start = netip.Addr{}
+				break
+			}
+			start = ex.end.Next()
+		}
+		if start.IsValid() {
+			res = append(res, interval{start: start, end: in.end})
+		}
+	}
+	return res
+}
@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSet(t *testing.T) {
+	tests := map[string]struct {
+		input      string
+		wantString string
+		wantSize   *big.Int
+		wantErr    bool
+	}{
+		"empty": {
+			input:    "",
+			wantSize: big.NewInt(0),
+		},
+		"only include": {
+			input:      "192.0.2.0-192.0.2.10 2001:db8::-2001:db8::10",
+			wantString: "192.0.2.0-192.0.2.10 2001:db8::-2001:db8::10",
+			wantSize:   big.NewInt(11 + 17),
+		},
+		"overlapping include": {
+			input:      "192.0.2.0-192.0.2.10 192.0.2.5-192.0.2.20 192.0.2.21",
+			wantString: "192.0.2.0-192.0.2.21",
+			wantSize:   big.NewInt(22),
+		},
+		"exclude subnet and address": {
+			input:      "10.0.0.0/16 !10.0.5.0/24 !10.0.0.1",
+			wantString: "10.0.0.2-10.0.5.0 10.0.5.255-10.0.255.254",
+			wantSize:   big.NewInt(65534 - 254 - 1),
+		},
+		"exclude everything": {
+			input:    "192.0.2.0/24 !192.0.2.0-192.0.2.255",
+			wantSize: big.NewInt(0),
+		},
+		"exclude other family": {
+			input:      "192.0.2.0-192.0.2.10 !2001:db8::/64",
+			wantString: "192.0.2.0-192.0.2.10",
+			wantSize:   big.NewInt(11),
+		},
+		"only exclude": {
+			input:    "!192.0.2.0/24",
+			wantSize: big.NewInt(0),
+		},
+		"v6 exclude (CIDR ranges do not include network and broadcast addresses)": {
+			input:      "2001:db8::/64 !2001:db8::/65",
+			wantString: "2001:db8::7fff:ffff:ffff:ffff-2001:db8::ffff:ffff:ffff:fffe",
+			wantSize:   big.NewInt(0).Lsh(big.NewInt(1), 63),
+		},
+		"invalid include": {
+			input:   "192.0.2.0/24 192.0.2.",
+			wantErr: true,
+		},
+		"invalid exclude": {
+			input:   "192.0.2.0/24 !192.0.2.",
+			wantErr: true,
+		},
+		"empty exclude": {
+			input:   "192.0.2.0/24 !",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := ParseSet(test.input)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, s)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.wantString, s.String())
+				assert.Equal(t, test.wantSize, s.Size())
+			}
+		})
+	}
+}
+
+func TestParseSet_ErrorPointsAtToken(t *testing.T) {
+	_, err := ParseSet("10.0.0.0/16 !10.0.5.0/24 !10.0.0.300")
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "token #3")
+	assert.Contains(t, err.Error(), "'10.0.0.300'")
+}
+
+func TestSet_Contains(t *testing.T) {
+	s, err := ParseSet("10.0.0.0/16 !10.0.5.0/24 !10.0.0.1 2001:db8::/120 !2001:db8::10-2001:db8::20")
+	require.NoError(t, err)
+
+	tests := map[string]bool{
+		"10.0.0.2":            true,
+		"10.0.4.255":          true,
+		"10.0.5.1":            false,
+		"10.0.0.1":            false,
+		"10.0.6.1":            true,
+		"10.1.0.1":            false,
+		"::ffff:10.0.6.1":     true,
+		"2001:db8::f":         true,
+		"2001:db8::15":        false,
+		"2001:db8::21":        true,
+		"2001:db8::1:0":       false,
+		"2001:db8::ffff:0:10": false,
+	}
+
+	for ip, want := range tests {
+		t.Run(ip, func(t *testing.T) {
+			assert.Equal(t, want, s.Contains(net.ParseIP(ip)))
+		})
+	}
+}
+
+func TestSet_Iterator(t *testing.T) {
+	s, err := ParseSet("192.0.2.0-192.0.2.3 !192.0.2.1 2001:db8::-2001:db8::1")
+	require.NoError(t, err)
+
+	var ips []string
+	it := s.Iterator()
+	for it.Next() {
+		ips = append(ips, it.IP().String())
+	}
+
+	assert.Equal(t, []string{"192.0.2.0", "192.0.2.2", "192.0.2.3", "2001:db8::", "2001:db8::1"}, ips)
+	assert.False(t, it.Next())
+	assert.Nil(t, it.IP())
+}
+
+func TestSet_Iterator_HugeRange(t *testing.T) {
+	s, err := ParseSet("2001:db8::/32 !2001:db8::2")
+	require.NoError(t, err)
+
+	it := s.Iterator()
+	var ips []string
+	for i := 0; i < 3 && it.Next(); i++ {
+		ips = append(ips, it.IP().String())
+	}
+
+	assert.Equal(t, []string{"2001:db8::1", "2001:db8::3", "2001:db8::4"}, ips)
+}
+
+// TestSet_BruteForce compares the set against a brute-force implementation on random small ranges.
+func TestSet_BruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	formats := map[string]string{
+		"v4": "192.0.2.%d",
+		"v6": "2001:db8::%x",
+	}
+
+	for name, format := range formats {
+		t.Run(name, func(t *testing.T) {
+			randRange := func() (string, [2]int) {
+				a, b := rnd.Intn(256), rnd.Intn(256)
+				if a > b {
+					a, b = b, a
+				}
+				return fmt.Sprintf(format+"-"+format, a, b), [2]int{a, b}
+			}
+
+			for i := 0; i < 200; i++ {
+				var expr string
+				var include, exclude [][2]int
+
+				for j, n := 0, 1+rnd.Intn(4); j < n; j++ {
+					r, bounds := randRange()
+					expr += " " + r
+					include = append(include, bounds)
+				}
+				for j, n := 0, rnd.Intn(4); j < n; j++ {
+					r, bounds := randRange()
+					expr += " !" + r
+					exclude = append(exclude, bounds)
+				}
+
+				set := make(map[int]bool)
+				for _, r := range include {
+					for v := r[0]; v <= r[1]; v++ {
+						set[v] = true
+					}
+				}
+				for _, r := range exclude {
+					for v := r[0]; v <= r[1]; v++ {
+						delete(set, v)
+					}
+				}
+
+				s, err := ParseSet(expr)
+				require.NoError(t, err, expr)
+
+				var want []string
+				for v := 0; v < 256; v++ {
+					ip := net.ParseIP(fmt.Sprintf(format, v))
+					require.Equalf(t, set[v], s.Contains(ip), "expr '%s', ip %s", expr, ip)
+					if set[v] {
+						want = append(want, ip.String())
+					}
+				}
+
+				var got []string
+				for it := s.Iterator(); it.Next(); {
+					got = append(got, it.IP().String())
+				}
+
+				require.Equalf(t, want, got, "expr '%s'", expr)
+				require.Equalf(t, big.NewInt(int64(len(set))), s.Size(), "expr '%s'", expr)
+			}
+		})
+	}
+}
+
+func BenchmarkSet_Iterator(b *testing.B) {
+	s, err := ParseSet("10.0.0.0/16 !10.0.5.0/24")
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for it := s.Iterator(); it.Next(); {
+		}
+	}
+}
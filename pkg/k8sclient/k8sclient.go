@@ -20,7 +20,17 @@ const (
 	defaultUserAgent = "Netdata/k8s-client"
 )
 
+// RateLimits configures the client-side throttling. Zero values mean client-go defaults.
+type RateLimits struct {
+	QPS   float32
+	Burst int
+}
+
 func New(userAgent string) (kubernetes.Interface, error) {
+	return NewWithRateLimits(userAgent, RateLimits{})
+}
+
+func NewWithRateLimits(userAgent string, limits RateLimits) (kubernetes.Interface, error) {
 	if userAgent == "" {
 		userAgent = defaultUserAgent
 	}
@@ -29,24 +39,25 @@ func New(userAgent string) (kubernetes.Interface, error) {
 	case os.Getenv(EnvFakeClient) != "":
 		return fake.NewSimpleClientset(), nil
 	case os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != "":
-		return newInCluster(userAgent)
+		return newInCluster(userAgent, limits)
 	default:
-		return newOutOfCluster(userAgent)
+		return newOutOfCluster(userAgent, limits)
 	}
 }
 
-func newInCluster(userAgent string) (*kubernetes.Clientset, error) {
+func newInCluster(userAgent string, limits RateLimits) (*kubernetes.Clientset, error) {
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		return nil, err
 	}
 
 	config.UserAgent = userAgent
+	applyRateLimits(config, limits)
 
 	return kubernetes.NewForConfig(config)
 }
 
-func newOutOfCluster(userAgent string) (*kubernetes.Clientset, error) {
+func newOutOfCluster(userAgent string, limits RateLimits) (*kubernetes.Clientset, error) {
 	home := homeDir()
 	if home == "" {
 		return nil, errors.New("couldn't find home directory")
@@ -59,10 +70,20 @@ func newOutOfCluster(userAgent string) (*kubernetes.Clientset, error) {
 	}
 
 	config.UserAgent = userAgent
+	applyRateLimits(config, limits)
 
 	return kubernetes.NewForConfig(config)
 }
 
+func applyRateLimits(config *rest.Config, limits RateLimits) {
+	if limits.QPS > 0 {
+		config.QPS = limits.QPS
+	}
+	if limits.Burst > 0 {
+		config.Burst = limits.Burst
+	}
+}
+
 func homeDir() string {
 	if h := os.Getenv("HOME"); h != "" {
 		return h
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/netdata/go.d.plugin/logger"
 )
@@ -21,9 +22,8 @@ var (
 	ErrNoMatchedFile = errors.New("no matched files")
 )
 
-// Reader is a log rotate aware Reader
-// TODO: better reopen algorithm
-// TODO: handle truncate
+// Reader is a log rotate aware Reader.
+// It handles the file truncation (copytruncate) and the file recreation (rename/remove and create).
 type Reader struct {
 	file          *os.File
 	path          string
@@ -70,16 +70,17 @@ func (r *Reader) open() error {
 		r.log.Debugf("couldn't find log file, used path: '%s', exclude_path: '%s'", r.path, r.excludePath)
 		return ErrNoMatchedFile
 	}
+	return r.openFile(path, io.SeekEnd)
+}
+
+func (r *Reader) openFile(path string, whence int) error {
 	r.log.Debug("open log file: ", path)
 	file, err := os.Open(path)
 	if err != nil {
 		return err
 	}
-	stat, err := file.Stat()
-	if err != nil {
-		return err
-	}
-	if _, err = file.Seek(stat.Size(), io.SeekStart); err != nil {
+	if _, err = file.Seek(0, whence); err != nil {
+		_ = file.Close()
 		return err
 	}
 	r.file = file
@@ -91,7 +92,14 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 	if err != nil {
 		switch err {
 		case io.EOF:
-			err = r.handleEOFErr()
+			var moved bool
+			if moved, err = r.handleEOFErr(); moved {
+				// the file is truncated or a new file is opened, no need to wait for the next read
+				n, err = r.file.Read(p)
+				if err == nil {
+					r.continuousEOF = 0
+				}
+			}
 		case os.ErrInvalid: // r.file is nil after Close
 			err = r.handleInvalidArgErr()
 		}
@@ -101,22 +109,43 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 	return
 }
 
-func (r *Reader) handleEOFErr() (err error) {
+// handleEOFErr checks whether the file is truncated or recreated and reports if the read position has changed.
+func (r *Reader) handleEOFErr() (moved bool, err error) {
 	err = io.EOF
 	r.eofCounter++
 	r.continuousEOF++
+
+	if r.isTruncated() {
+		r.log.Debugf("log file '%s' is truncated, read from the start", r.file.Name())
+		if _, err2 := r.file.Seek(0, io.SeekStart); err2 != nil {
+			return false, err2
+		}
+		return true, err
+	}
+
+	if r.isRecreated() {
+		name := r.file.Name()
+		r.log.Debugf("log file '%s' is recreated, reopen", name)
+		_ = r.Close()
+		if err2 := r.openFile(name, io.SeekStart); err2 != nil {
+			return false, err2
+		}
+		return true, err
+	}
+
 	if r.eofCounter < maxEOF || r.continuousEOF < 2 {
-		return err
+		return false, err
 	}
-	if err2 := r.reopen(); err2 != nil {
-		err = err2
+	moved, err2 := r.reopen()
+	if err2 != nil {
+		return false, err2
 	}
-	return err
+	return moved, err
 }
 
 func (r *Reader) handleInvalidArgErr() (err error) {
 	err = io.EOF
-	if err2 := r.reopen(); err2 != nil {
+	if _, err2 := r.reopen(); err2 != nil {
 		err = err2
 	}
 	return err
@@ -133,10 +162,62 @@ func (r *Reader) Close() (err error) {
 	return
 }
 
-func (r *Reader) reopen() error {
+// reopen looks for the log file using the path pattern and opens it if it is not the current file.
+// A new file is read from the start, the current file is kept if there is no matched file (rotated, but not yet created).
+func (r *Reader) reopen() (moved bool, err error) {
 	r.log.Debugf("reopen, look for: %s", r.path)
+	r.eofCounter = 0
+
+	if r.file == nil {
+		return false, r.open()
+	}
+
+	path := r.findFile()
+	if path == "" {
+		r.log.Debugf("couldn't find log file, used path: '%s', exclude_path: '%s', keep reading '%s'",
+			r.path, r.excludePath, r.file.Name())
+		return false, nil
+	}
+	if r.isCurrentFile(path) {
+		return false, nil
+	}
+
 	_ = r.Close()
-	return r.open()
+	if err := r.openFile(path, io.SeekStart); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isTruncated reports whether the current file size is less than the read offset.
+func (r *Reader) isTruncated() bool {
+	offset, err := r.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false
+	}
+	fi, err := r.file.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Size() < offset
+}
+
+// isRecreated reports whether the current file name points to another file (inode).
+// A missing file is not considered recreated, the current file is kept until the new one appears.
+func (r *Reader) isRecreated() bool {
+	return !r.isCurrentFile(r.file.Name())
+}
+
+func (r *Reader) isCurrentFile(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	cur, err := r.file.Stat()
+	if err != nil {
+		return false
+	}
+	return os.SameFile(fi, cur)
 }
 
 func (r *Reader) findFile() string {
@@ -165,13 +246,13 @@ func (f finder) find(path, exclude string) string {
 }
 
 func (f finder) filter(files []string, exclude string) []string {
-	if exclude == "" {
-		return files
-	}
-
 	fs := make([]string, 0, len(files))
 	for _, file := range files {
-		if ok, _ := filepath.Match(exclude, file); ok {
+		// the rotated files are often compressed, they are never tailed
+		if isCompressed(file) {
+			continue
+		}
+		if ok, _ := filepath.Match(exclude, file); exclude != "" && ok {
 			continue
 		}
 		fs = append(fs, file)
@@ -191,3 +272,14 @@ func (f finder) findLastFile(files []string) string {
 	}
 	return ""
 }
+
+var compressedExts = []string{".gz", ".bz2", ".xz", ".zst", ".lz4", ".zip", ".Z"}
+
+func isCompressed(file string) bool {
+	for _, ext := range compressedExts {
+		if strings.HasSuffix(file, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -44,9 +44,10 @@ func TestReader_Read_HandleFileRotation(t *testing.T) {
 	rotateFile(t, filename)
 	appendLogs(t, filename, time.Millisecond*10, numLogs)
 
+	// the new file is read from the start
 	n, err := r.readUntilEOFTimes(maxEOF)
 	assert.Equal(t, io.EOF, err)
-	assert.Equal(t, 0, n)
+	assert.Equal(t, numLogs, n)
 
 	appendLogs(t, filename, time.Millisecond*10, numLogs)
 	n, err = r.readUntilEOF()
@@ -54,6 +55,61 @@ func TestReader_Read_HandleFileRotation(t *testing.T) {
 	assert.Equal(t, numLogs, n)
 }
 
+func TestReader_Read_HandleCopyTruncate(t *testing.T) {
+	reader, teardown := prepareTestReader(t)
+	defer teardown()
+
+	r := testReader{bufio.NewReader(reader)}
+	filename := reader.CurrentFilename()
+	numLogs := 5
+
+	appendLogs(t, filename, 0, numLogs*2)
+	n, err := r.readUntilEOF()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, numLogs*2, n)
+
+	copyTruncateFile(t, filename)
+	defer func() { _ = os.Remove(filename + ".1") }()
+	appendLogs(t, filename, 0, numLogs)
+
+	n, err = r.readUntilEOF()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, numLogs, n)
+
+	appendLogs(t, filename, 0, numLogs)
+	n, err = r.readUntilEOF()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, numLogs, n)
+}
+
+func TestReader_Read_HandleRenameAndCreate(t *testing.T) {
+	reader, teardown := prepareTestReader(t)
+	defer teardown()
+
+	r := testReader{bufio.NewReader(reader)}
+	filename := reader.CurrentFilename()
+	numLogs := 5
+
+	// the lines written to the renamed file before the new file is created are not lost
+	require.NoError(t, os.Rename(filename, filename+".1"))
+	defer func() { _ = os.Remove(filename + ".1") }()
+	appendLogs(t, filename+".1", 0, numLogs)
+
+	n, err := r.readUntilEOF()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, numLogs, n)
+
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	_ = f.Close()
+	appendLogs(t, filename, 0, numLogs)
+
+	n, err = r.readUntilEOF()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, numLogs, n)
+	assert.Equal(t, filename, reader.CurrentFilename())
+}
+
 func TestReader_Read_HandleFileRotationWithDelay(t *testing.T) {
 	reader, teardown := prepareTestReader(t)
 	defer teardown()
@@ -62,9 +118,9 @@ func TestReader_Read_HandleFileRotationWithDelay(t *testing.T) {
 	filename := reader.CurrentFilename()
 	_ = os.Remove(filename)
 
-	// trigger reopen first time
+	// trigger reopen first time, the removed file is kept
 	n, err := r.readUntilEOFTimes(maxEOF)
-	assert.Equal(t, ErrNoMatchedFile, err)
+	assert.Equal(t, io.EOF, err)
 	assert.Equal(t, 0, n)
 
 	f, err := os.Create(filename)
@@ -83,6 +139,31 @@ func TestReader_Read_HandleFileRotationWithDelay(t *testing.T) {
 	assert.Equal(t, numLogs, n)
 }
 
+func TestFinder_Find(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"access.log", "access.log.1", "access.log.2.gz", "access.log.3.zst"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+
+	tests := map[string]struct {
+		path    string
+		exclude string
+		want    string
+	}{
+		"skip compressed":    {path: filepath.Join(dir, "access.log*"), want: filepath.Join(dir, "access.log.1")},
+		"with exclude":       {path: filepath.Join(dir, "access.log*"), exclude: filepath.Join(dir, "*.1"), want: filepath.Join(dir, "access.log")},
+		"only compressed":    {path: filepath.Join(dir, "access.log.*.*")},
+		"no match":           {path: filepath.Join(dir, "error.log")},
+		"exclude everything": {path: filepath.Join(dir, "access.log*"), exclude: filepath.Join(dir, "*")},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, find(test.path, test.exclude))
+		})
+	}
+}
+
 func TestReader_Close(t *testing.T) {
 	reader, teardown := prepareTestReader(t)
 	defer teardown()
@@ -229,6 +310,14 @@ func rotateFile(t *testing.T, filename string) {
 	_ = f.Close()
 }
 
+func copyTruncateFile(t *testing.T, filename string) {
+	t.Helper()
+	bs, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename+".1", bs, 0644))
+	require.NoError(t, os.Truncate(filename, 0))
+}
+
 func appendLogs(t *testing.T, filename string, interval time.Duration, numOfLogs int) {
 	t.Helper()
 	base := filepath.Base(filename)
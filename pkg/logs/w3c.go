@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// W3C Extended Log File Format: https://www.w3.org/TR/WD-logfile.html
+// IIS: https://learn.microsoft.com/en-us/windows/win32/http/w3c-logging
+
+const w3cFieldsDirective = "#Fields:"
+
+type (
+	W3CConfig struct {
+		// Fields is the initial fields directive, it is used until the first '#Fields:' directive is read.
+		Fields  string            `yaml:"fields"`
+		Mapping map[string]string `yaml:"mapping"`
+	}
+
+	W3CParser struct {
+		r       *bufio.Reader
+		mapping map[string]string
+		format  *w3cFormat
+	}
+
+	w3cFormat struct {
+		raw    string
+		comma  rune
+		fields []string
+	}
+)
+
+// w3cMapping maps the W3C (IIS) field names to the web log field names.
+var w3cMapping = map[string]string{
+	"s-ip":        "host",
+	"cs-host":     "host",
+	"s-port":      "server_port",
+	"c-ip":        "remote_addr",
+	"cs-method":   "request_method",
+	"cs-uri-stem": "request_uri",
+	"cs-version":  "server_protocol",
+	"sc-status":   "status",
+	"cs-bytes":    "request_length",
+	"sc-bytes":    "bytes_sent",
+	"time-taken":  "request_time_ms",
+}
+
+func NewW3CParser(config W3CConfig, in io.Reader) (*W3CParser, error) {
+	p := &W3CParser{
+		r:       bufio.NewReader(in),
+		mapping: config.Mapping,
+	}
+	if config.Fields != "" {
+		format, err := newW3CFormat(config.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("bad w3c fields '%s': %v", config.Fields, err)
+		}
+		p.format = format
+	}
+	return p, nil
+}
+
+// IsW3CFieldsDirective reports whether the line is the '#Fields:' directive.
+func IsW3CFieldsDirective(line string) bool {
+	return strings.HasPrefix(line, w3cFieldsDirective)
+}
+
+// ReadLine reads the next log line, the directives are applied and skipped.
+func (p *W3CParser) ReadLine(line LogLine) error {
+	for {
+		row, err := p.r.ReadSlice('\n')
+		if err != nil && len(row) == 0 {
+			return err
+		}
+		row = bytes.TrimRight(row, "\r\n")
+		if len(row) > 0 && row[0] == '#' {
+			if err := p.applyDirective(row); err != nil {
+				return err
+			}
+			continue
+		}
+		return p.Parse(row, line)
+	}
+}
+
+func (p *W3CParser) Parse(row []byte, line LogLine) error {
+	if len(row) > 0 && row[0] == '#' {
+		return p.applyDirective(row)
+	}
+	if p.format == nil {
+		return &ParseError{msg: "w3c parse: no '#Fields:' directive"}
+	}
+
+	r := csv.NewReader(bytes.NewReader(row))
+	r.Comma = p.format.comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	record, err := r.Read()
+	if err != nil {
+		return &ParseError{msg: fmt.Sprintf("w3c parse: %v", err), err: err}
+	}
+	if len(record) != len(p.format.fields) {
+		return &ParseError{msg: "w3c parse: unmatched line"}
+	}
+
+	for i, name := range p.format.fields {
+		if err := line.Assign(p.fieldName(name), record[i]); err != nil {
+			return &ParseError{msg: fmt.Sprintf("w3c parse: %v", err), err: err}
+		}
+	}
+	return nil
+}
+
+func (p *W3CParser) Info() string {
+	if p.format == nil {
+		return "w3c: no fields"
+	}
+	return fmt.Sprintf("w3c: %s", p.format.raw)
+}
+
+func (p *W3CParser) applyDirective(row []byte) error {
+	if !IsW3CFieldsDirective(string(row)) {
+		// #Software, #Version, #Date, etc.
+		return nil
+	}
+	format, err := newW3CFormat(string(row))
+	if err != nil {
+		return &ParseError{msg: fmt.Sprintf("w3c parse: bad fields directive: %v", err), err: err}
+	}
+	p.format = format
+	return nil
+}
+
+func (p *W3CParser) fieldName(name string) string {
+	if v, ok := p.mapping[name]; ok {
+		return v
+	}
+	if v, ok := w3cMapping[strings.ToLower(name)]; ok {
+		return v
+	}
+	return name
+}
+
+func newW3CFormat(directive string) (*w3cFormat, error) {
+	raw := strings.TrimSpace(strings.TrimPrefix(directive, w3cFieldsDirective))
+
+	// IIS separates the fields by spaces, Exchange by commas
+	comma := ' '
+	if strings.Contains(raw, ",") && !strings.Contains(raw, " ") {
+		comma = ','
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, name := range strings.FieldsFunc(raw, func(r rune) bool { return r == comma }) {
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate field: %s", name)
+		}
+		seen[name] = true
+		fields = append(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("zero fields")
+	}
+
+	return &w3cFormat{raw: raw, comma: comma, fields: fields}, nil
+}
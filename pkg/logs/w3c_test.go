@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package logs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testW3CIISLog, _      = os.ReadFile("testdata/w3c-iis.log")
+	testW3CExchangeLog, _ = os.ReadFile("testdata/w3c-exchange.log")
+)
+
+func Test_readW3CTestData(t *testing.T) {
+	assert.NotNil(t, testW3CIISLog)
+	assert.NotNil(t, testW3CExchangeLog)
+}
+
+func TestNewW3CParser(t *testing.T) {
+	tests := map[string]struct {
+		config  W3CConfig
+		wantErr bool
+	}{
+		"empty config": {
+			config: W3CConfig{},
+		},
+		"with fields": {
+			config: W3CConfig{Fields: "#Fields: date time c-ip sc-status"},
+		},
+		"with fields without directive": {
+			config: W3CConfig{Fields: "date time c-ip sc-status"},
+		},
+		"with mappings": {
+			config: W3CConfig{Mapping: map[string]string{"from_field_1": "to_field_1"}},
+		},
+		"with empty fields directive": {
+			config:  W3CConfig{Fields: "#Fields:"},
+			wantErr: true,
+		},
+		"with duplicate fields": {
+			config:  W3CConfig{Fields: "#Fields: date c-ip c-ip"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := NewW3CParser(test.config, nil)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, p)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, p)
+			}
+		})
+	}
+}
+
+func TestW3CParser_ReadLine(t *testing.T) {
+	tests := map[string]struct {
+		config    W3CConfig
+		input     []byte
+		wantLines []map[string]string
+	}{
+		"IIS": {
+			input: testW3CIISLog,
+			wantLines: []map[string]string{
+				{
+					"date": "2023-03-14", "time": "09:12:40", "host": "10.0.0.5", "request_method": "GET",
+					"request_uri": "/", "cs-uri-query": "-", "server_port": "80", "cs-username": "-",
+					"remote_addr": "192.168.10.21", "cs(User-Agent)": "Mozilla/5.0+(Windows+NT+10.0;+Win64;+x64)+AppleWebKit/537.36+(KHTML,+like+Gecko)+Chrome/111.0.0.0+Safari/537.36",
+					"cs(Referer)": "-", "status": "200", "sc-substatus": "0", "sc-win32-status": "0", "request_time_ms": "31",
+				},
+				{
+					"date": "2023-03-14", "time": "09:12:41", "host": "10.0.0.5", "request_method": "GET",
+					"request_uri": "/iisstart.png", "cs-uri-query": "-", "server_port": "80", "cs-username": "-",
+					"remote_addr": "192.168.10.21", "cs(User-Agent)": "Mozilla/5.0+(Windows+NT+10.0;+Win64;+x64)+AppleWebKit/537.36+(KHTML,+like+Gecko)+Chrome/111.0.0.0+Safari/537.36",
+					"cs(Referer)": "http://10.0.0.5/", "status": "200", "sc-substatus": "0", "sc-win32-status": "0", "request_time_ms": "4",
+				},
+				{
+					"date": "2023-03-14", "time": "09:12:41", "host": "10.0.0.5", "request_method": "GET",
+					"request_uri": "/favicon.ico", "cs-uri-query": "-", "server_port": "80", "cs-username": "-",
+					"remote_addr": "192.168.10.21", "cs(User-Agent)": "Mozilla/5.0+(Windows+NT+10.0;+Win64;+x64)+AppleWebKit/537.36+(KHTML,+like+Gecko)+Chrome/111.0.0.0+Safari/537.36",
+					"cs(Referer)": "http://10.0.0.5/", "status": "404", "sc-substatus": "0", "sc-win32-status": "2", "request_time_ms": "2",
+				},
+				{
+					"date": "2023-03-14", "time": "09:13:05", "host": "fe80::4d1:9a2b:3c4d:5e6f", "request_method": "POST",
+					"request_uri": "/api/orders", "cs-uri-query": "id=42", "server_port": "443", "cs-username": `CORP\jdoe`,
+					"remote_addr": "fe80::1c2b:3a4d:5e6f:7a8b", "cs(User-Agent)": "curl/7.83.1",
+					"cs(Referer)": "-", "status": "500", "sc-substatus": "0", "sc-win32-status": "0", "request_time_ms": "1290",
+				},
+				// the fields directive has changed
+				{
+					"date": "2023-03-14", "time": "10:00:02", "host": "www.example.com", "request_method": "GET",
+					"request_uri": "/status", "cs-uri-query": "-", "server_port": "80", "cs-username": "-",
+					"remote_addr": "192.168.10.30", "server_protocol": "HTTP/1.1", "cs(User-Agent)": "Go-http-client/1.1",
+					"status": "200", "sc-substatus": "0", "sc-win32-status": "0", "bytes_sent": "1021", "request_length": "112",
+					"request_time_ms": "15",
+				},
+				{
+					"date": "2023-03-14", "time": "10:00:03", "host": "-", "request_method": "GET",
+					"request_uri": "/status", "cs-uri-query": "-", "server_port": "80", "cs-username": "-",
+					"remote_addr": "192.168.10.30", "server_protocol": "HTTP/1.1", "cs(User-Agent)": "Go-http-client/1.1",
+					"status": "304", "sc-substatus": "0", "sc-win32-status": "0", "bytes_sent": "180", "request_length": "140",
+					"request_time_ms": "0",
+				},
+			},
+		},
+		"Exchange": {
+			config: W3CConfig{
+				Mapping: map[string]string{
+					"UrlHost":          "host",
+					"UrlStem":          "request_uri",
+					"ClientIpAddress":  "remote_addr",
+					"HttpStatus":       "status",
+					"Method":           "request_method",
+					"RequestBytes":     "request_length",
+					"ResponseBytes":    "bytes_sent",
+					"TotalRequestTime": "request_time_ms",
+				},
+			},
+			input: testW3CExchangeLog,
+			wantLines: []map[string]string{
+				{
+					"DateTime": "2023-03-14T09:00:01.024Z", "RequestId": "5b7e2c1a-9d3f-4e8b-a6c2-1f0e9d8c7b6a", "Protocol": "Ews",
+					"host": "mail.example.com", "request_uri": "/EWS/Exchange.asmx", "AuthenticatedUser": `EXAMPLE\jdoe`,
+					"UserAgent":   "Microsoft Office/16.0 (Windows NT 10.0; Microsoft Outlook 16.0.16130; Pro)",
+					"remote_addr": "10.1.2.3", "ServerHostName": "EXCH01", "status": "200", "request_method": "POST",
+					"request_length": "1834", "bytes_sent": "5621", "request_time_ms": "47", "UrlQuery": "",
+				},
+				{
+					"DateTime": "2023-03-14T09:00:02.512Z", "RequestId": "0c4d3e2f-1a2b-4c5d-8e9f-a0b1c2d3e4f5", "Protocol": "Owa",
+					"host": "mail.example.com", "request_uri": "/owa/service.svc", "AuthenticatedUser": `EXAMPLE\asmith`,
+					"UserAgent":   "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.3 Safari/605.1.15",
+					"remote_addr": "10.1.2.17", "ServerHostName": "EXCH01", "status": "200", "request_method": "POST",
+					"request_length": "962", "bytes_sent": "2210", "request_time_ms": "118", "UrlQuery": "?action=GetFolder&app=Mail",
+				},
+				{
+					"DateTime": "2023-03-14T09:00:03.770Z", "RequestId": "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a", "Protocol": "Autodiscover",
+					"host": "autodiscover.example.com", "request_uri": "/Autodiscover/Autodiscover.xml", "AuthenticatedUser": "",
+					"UserAgent":   "Microsoft Office/16.0 (Windows NT 10.0; MAPI 16.0.16130; Pro)",
+					"remote_addr": "10.1.2.3", "ServerHostName": "EXCH01", "status": "401", "request_method": "POST",
+					"request_length": "0", "bytes_sent": "0", "request_time_ms": "3", "UrlQuery": "",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := NewW3CParser(test.config, bytes.NewReader(test.input))
+			require.NoError(t, err)
+
+			var lines []map[string]string
+			for {
+				line := newLogLine()
+				err := p.ReadLine(line)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				lines = append(lines, line.assigned)
+			}
+
+			assert.Equal(t, test.wantLines, lines)
+		})
+	}
+}
+
+func TestW3CParser_ReadLine_Errors(t *testing.T) {
+	tests := map[string]struct {
+		config       W3CConfig
+		input        string
+		wantParseErr bool
+	}{
+		"no fields directive": {
+			input:        "2023-03-14 09:12:40 10.0.0.5 200\n",
+			wantParseErr: true,
+		},
+		"fields number mismatch": {
+			input:        "#Fields: date time s-ip sc-status\n2023-03-14 09:12:40 10.0.0.5\n",
+			wantParseErr: true,
+		},
+		"bad fields directive": {
+			input:        "#Fields: date date\n2023-03-14 2023-03-14\n",
+			wantParseErr: true,
+		},
+		"error on assigning": {
+			input:        "#Fields: date ERR\n2023-03-14 1\n",
+			wantParseErr: true,
+		},
+		"only directives": {
+			input: "#Software: Microsoft Internet Information Services 10.0\n#Fields: date time\n",
+		},
+		"empty input": {
+			input: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := NewW3CParser(test.config, strings.NewReader(test.input))
+			require.NoError(t, err)
+
+			err = p.ReadLine(newLogLine())
+
+			require.Error(t, err)
+			if test.wantParseErr {
+				assert.True(t, IsParseError(err))
+			} else {
+				assert.Equal(t, io.EOF, err)
+			}
+		})
+	}
+}
+
+func TestW3CParser_Parse(t *testing.T) {
+	p, err := NewW3CParser(W3CConfig{Fields: "#Fields: date time c-ip sc-status time-taken"}, nil)
+	require.NoError(t, err)
+
+	line := newLogLine()
+	require.NoError(t, p.Parse([]byte("2023-03-14 09:12:40 192.168.10.21 200 31"), line))
+	assert.Equal(t, map[string]string{
+		"date":            "2023-03-14",
+		"time":            "09:12:40",
+		"remote_addr":     "192.168.10.21",
+		"status":          "200",
+		"request_time_ms": "31",
+	}, line.assigned)
+
+	// a fields directive changes the format
+	require.NoError(t, p.Parse([]byte("#Fields: c-ip sc-status"), newLogLine()))
+
+	line = newLogLine()
+	require.NoError(t, p.Parse([]byte("192.168.10.21 404"), line))
+	assert.Equal(t, map[string]string{
+		"remote_addr": "192.168.10.21",
+		"status":      "404",
+	}, line.assigned)
+	assert.Equal(t, "w3c: c-ip sc-status", p.Info())
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package matcher
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// cidrMatcher implements Matcher, it parses the value as an IP address and matches if any of the prefixes contains it.
+type cidrMatcher []netip.Prefix
+
+var errEmptyCIDRExpr = errors.New("empty cidr expression")
+
+// NewCIDRMatcher creates a new matcher with cidr format.
+// The expression is a comma separated list of CIDR prefixes, e.g. "10.0.0.0/8,192.168.0.0/16".
+// An IP address without a prefix length matches only itself.
+func NewCIDRMatcher(expr string) (Matcher, error) {
+	var m cidrMatcher
+
+	for _, v := range strings.FieldsFunc(expr, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		var prefix netip.Prefix
+		var err error
+		if strings.Contains(v, "/") {
+			prefix, err = netip.ParsePrefix(v)
+		} else {
+			var addr netip.Addr
+			if addr, err = netip.ParseAddr(v); err == nil {
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bad cidr '%s': %v", v, err)
+		}
+		m = append(m, prefix.Masked())
+	}
+	if len(m) == 0 {
+		return nil, errEmptyCIDRExpr
+	}
+	return m, nil
+}
+
+// Match matches.
+func (m cidrMatcher) Match(b []byte) bool {
+	return m.MatchString(string(b))
+}
+
+// MatchString matches.
+func (m cidrMatcher) MatchString(line string) bool {
+	addr, err := netip.ParseAddr(line)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range m {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package matcher
+
+import (
+	"net/netip"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCIDRMatcher(t *testing.T) {
+	cases := []struct {
+		expr    string
+		matcher Matcher
+	}{
+		{"", nil},
+		{" , ", nil},
+		{"abc", nil},
+		{"10.0.0.0/33", nil},
+		{"10.0.0.0/8,abc", nil},
+		{"10.0.0.0/8", cidrMatcher{netip.MustParsePrefix("10.0.0.0/8")}},
+		{"10.1.2.3/8", cidrMatcher{netip.MustParsePrefix("10.0.0.0/8")}},
+		{"10.0.0.0/8,192.168.0.0/16", cidrMatcher{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("192.168.0.0/16"),
+		}},
+		{"10.0.0.0/8, 192.168.0.0/16", cidrMatcher{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("192.168.0.0/16"),
+		}},
+		{"127.0.0.1,::1", cidrMatcher{
+			netip.MustParsePrefix("127.0.0.1/32"),
+			netip.MustParsePrefix("::1/128"),
+		}},
+		{"fd00::/8", cidrMatcher{netip.MustParsePrefix("fd00::/8")}},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			m, err := NewCIDRMatcher(c.expr)
+			if c.matcher != nil {
+				assert.NoError(t, err)
+				assert.Equal(t, c.matcher, m)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestCIDRMatcher_MatchString(t *testing.T) {
+	cases := []struct {
+		expected bool
+		expr     string
+		line     string
+	}{
+		{true, "10.0.0.0/8,192.168.0.0/16", "10.1.2.3"},
+		{true, "10.0.0.0/8,192.168.0.0/16", "192.168.1.1"},
+		{false, "10.0.0.0/8,192.168.0.0/16", "192.169.1.1"},
+		{false, "10.0.0.0/8,192.168.0.0/16", "11.0.0.1"},
+		{true, "10.0.0.0/8", "::ffff:10.0.0.1"},
+		{true, "127.0.0.1", "127.0.0.1"},
+		{false, "127.0.0.1", "127.0.0.2"},
+		{true, "fd00::/8", "fd12:3456::1"},
+		{false, "fd00::/8", "fe80::1"},
+		{false, "10.0.0.0/8", ""},
+		{false, "10.0.0.0/8", "abc"},
+		{false, "10.0.0.0/8", "10.0.0.1:8080"},
+		{false, "10.0.0.0/8", "10.0.0.0/8"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expr+"_"+c.line, func(t *testing.T) {
+			m, err := NewCIDRMatcher(c.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, m.Match([]byte(c.line)))
+			assert.Equal(t, c.expected, m.MatchString(c.line))
+		})
+	}
+}
+
+func BenchmarkCIDR_MatchString(b *testing.B) {
+	benchmarks := []struct {
+		expr   string
+		regexp string
+		test   string
+	}{
+		{"10.0.0.0/8", `^10\.\d{1,3}\.\d{1,3}\.\d{1,3}$`, "10.1.2.3"},
+		{"10.0.0.0/8,192.168.0.0/16", `^(10\.\d{1,3}|192\.168)\.\d{1,3}\.\d{1,3}$`, "192.168.1.1"},
+		{"10.0.0.0/8,192.168.0.0/16", `^(10\.\d{1,3}|192\.168)\.\d{1,3}\.\d{1,3}$`, "172.16.1.1"},
+		{"10.0.0.0/8,192.168.0.0/16", `^(10\.\d{1,3}|192\.168)\.\d{1,3}\.\d{1,3}$`, "abc"},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.expr+"_"+bm.test+"_cidr", func(b *testing.B) {
+			m, _ := NewCIDRMatcher(bm.expr)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.MatchString(bm.test)
+			}
+		})
+		b.Run(bm.expr+"_"+bm.test+"_regexp", func(b *testing.B) {
+			m := regexp.MustCompile(bm.regexp)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.MatchString(bm.test)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ Supported Format
 	glob
 	regexp
 	simple patterns
+	number
+	cidr
 
 The string matcher reports whether the given value equals to the string ( use == ).
 
@@ -36,5 +38,14 @@ The RegExp syntax is described at https://golang.org/pkg/regexp/syntax/.
 The simple patterns matcher reports whether the given value matches the simple patterns.
 The simple patterns is a custom format used in netdata,
 it's syntax is described at https://docs.netdata.cloud/libnetdata/simple_pattern/.
+
+The number matcher parses the given value as a number and reports whether it satisfies all the conditions.
+The conditions are space separated, e.g. ">=500 <600". The operators are >=, >, <=, <, =, == and !=,
+a number without an operator means equality.
+
+The cidr matcher parses the given value as an IP address and reports whether any of the prefixes contains it.
+The prefixes are comma separated, e.g. "10.0.0.0/8,192.168.0.0/16".
+
+The values that are not a number (number matcher) or an IP address (cidr matcher) do not match.
 */
 package matcher
@@ -31,6 +31,10 @@ const (
 	// FmtSimplePattern is a simple pattern match format
 	// https://docs.netdata.cloud/libnetdata/simple_pattern/
 	FmtSimplePattern Format = "simple_patterns"
+	// FmtNumber is a numeric comparison match format.
+	FmtNumber Format = "number"
+	// FmtCIDR is an IP address within CIDR prefixes match format.
+	FmtCIDR Format = "cidr"
 
 	// Separator is a separator between match format and expression.
 	Separator = ":"
@@ -71,6 +75,10 @@ func New(format Format, expr string) (Matcher, error) {
 		return NewRegExpMatcher(expr)
 	case FmtSimplePattern:
 		return NewSimplePatternsMatcher(expr)
+	case FmtNumber:
+		return NewNumberMatcher(expr)
+	case FmtCIDR:
+		return NewCIDRMatcher(expr)
 	default:
 		return nil, fmt.Errorf("unsupported matcher format: '%s'", format)
 	}
@@ -93,7 +101,7 @@ func New(format Format, expr string) (Matcher, error) {
 // Long Syntax
 //
 //	<line>      ::= [ <not> ] <format> <separator> <expr>
-//	<format>    ::= [ 'string' | 'glob' | 'regexp' | 'simple_patterns' ]
+//	<format>    ::= [ 'string' | 'glob' | 'regexp' | 'simple_patterns' | 'number' | 'cidr' ]
 //	<not>       ::= '!'
 //	                  negative expression
 //	<separator> ::= ':'
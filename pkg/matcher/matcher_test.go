@@ -4,6 +4,7 @@ package matcher
 
 import (
 	"log"
+	"net/netip"
 	"reflect"
 	"regexp"
 	"testing"
@@ -93,6 +94,20 @@ func TestParse(t *testing.T) {
 		{true, `simple_patterns: !foo`, simplePatternsMatcher{
 			{stringFullMatcher("foo"), false},
 		}},
+
+		{false, "number:", nil},
+		{false, "number:abc", nil},
+		{true, "number:>=500 <600", numberMatcher{{">=", 500}, {"<", 600}}},
+		{true, "!number:200", Not(numberMatcher{{"=", 200}})},
+
+		{false, "cidr:", nil},
+		{false, "cidr:abc", nil},
+		{true, "cidr:10.0.0.0/8,192.168.0.0/16", cidrMatcher{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("192.168.0.0/16"),
+		}},
+		{true, "cidr:fd00::/8", cidrMatcher{netip.MustParsePrefix("fd00::/8")}},
+		{true, "!cidr:10.0.0.0/8", Not(cidrMatcher{netip.MustParsePrefix("10.0.0.0/8")})},
 	}
 	for _, test := range tests {
 		t.Run(test.line, func(t *testing.T) {
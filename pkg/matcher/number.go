@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package matcher
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// numberMatcher implements Matcher, it parses the value as a number and matches if all the conditions hold.
+	numberMatcher []numberCondition
+
+	numberCondition struct {
+		op    string
+		value float64
+	}
+)
+
+var errEmptyNumberExpr = errors.New("empty number expression")
+
+// numberOps are ordered so that a two characters operator is checked before its one character prefix.
+var numberOps = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// NewNumberMatcher creates a new matcher with number format.
+// The expression is a space separated list of conditions, e.g. ">=500 <600".
+// A condition is an operator (>=, >, <=, <, =, ==, !=) followed by a number, a bare number means "=".
+func NewNumberMatcher(expr string) (Matcher, error) {
+	var m numberMatcher
+
+	for _, cond := range strings.Fields(expr) {
+		op := "="
+		for _, v := range numberOps {
+			if strings.HasPrefix(cond, v) {
+				op = v
+				cond = cond[len(v):]
+				break
+			}
+		}
+		if op == "==" {
+			op = "="
+		}
+
+		v, err := strconv.ParseFloat(cond, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number condition '%s%s': %v", op, cond, err)
+		}
+		m = append(m, numberCondition{op: op, value: v})
+	}
+	if len(m) == 0 {
+		return nil, errEmptyNumberExpr
+	}
+	return m, nil
+}
+
+// Match matches.
+func (m numberMatcher) Match(b []byte) bool {
+	return m.MatchString(string(b))
+}
+
+// MatchString matches.
+func (m numberMatcher) MatchString(line string) bool {
+	v, err := strconv.ParseFloat(line, 64)
+	if err != nil {
+		return false
+	}
+	for _, cond := range m {
+		if !cond.match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c numberCondition) match(v float64) bool {
+	switch c.op {
+	case ">=":
+		return v >= c.value
+	case ">":
+		return v > c.value
+	case "<=":
+		return v <= c.value
+	case "<":
+		return v < c.value
+	case "!=":
+		return v != c.value
+	default:
+		return v == c.value
+	}
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package matcher
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNumberMatcher(t *testing.T) {
+	cases := []struct {
+		expr    string
+		matcher Matcher
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"abc", nil},
+		{">=", nil},
+		{">= 500", nil},
+		{"=>500", nil},
+		{"500", numberMatcher{{"=", 500}}},
+		{"=500", numberMatcher{{"=", 500}}},
+		{"==500", numberMatcher{{"=", 500}}},
+		{"!=500", numberMatcher{{"!=", 500}}},
+		{">=500 <600", numberMatcher{{">=", 500}, {"<", 600}}},
+		{">0.5 <=-1e3", numberMatcher{{">", 0.5}, {"<=", -1000}}},
+	}
+	for _, c := range cases {
+		t.Run(c.expr, func(t *testing.T) {
+			m, err := NewNumberMatcher(c.expr)
+			if c.matcher != nil {
+				assert.NoError(t, err)
+				assert.Equal(t, c.matcher, m)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNumberMatcher_MatchString(t *testing.T) {
+	cases := []struct {
+		expected bool
+		expr     string
+		line     string
+	}{
+		{true, ">=500 <600", "500"},
+		{true, ">=500 <600", "599"},
+		{true, ">=500 <600", "550.5"},
+		{false, ">=500 <600", "499"},
+		{false, ">=500 <600", "600"},
+		{true, "404", "404"},
+		{true, "404", "404.0"},
+		{false, "404", "403"},
+		{true, "!=200", "404"},
+		{false, "!=200", "200"},
+		{true, "<=0", "-1"},
+		{true, ">0.5", "1e3"},
+		{false, ">=500", ""},
+		{false, ">=500", "abc"},
+		{false, ">=500", "500 "},
+		{false, "!=200", "abc"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expr+"_"+c.line, func(t *testing.T) {
+			m, err := NewNumberMatcher(c.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, m.Match([]byte(c.line)))
+			assert.Equal(t, c.expected, m.MatchString(c.line))
+		})
+	}
+}
+
+func BenchmarkNumber_MatchString(b *testing.B) {
+	benchmarks := []struct {
+		expr   string
+		regexp string
+		test   string
+	}{
+		{"404", "^404$", "404"},
+		{">=500 <600", "^5[0-9][0-9]$", "503"},
+		{">=500 <600", "^5[0-9][0-9]$", "200"},
+		{">=500 <600", "^5[0-9][0-9]$", "abc"},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.expr+"_"+bm.test+"_number", func(b *testing.B) {
+			m, _ := NewNumberMatcher(bm.expr)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.MatchString(bm.test)
+			}
+		})
+		b.Run(bm.expr+"_"+bm.test+"_regexp", func(b *testing.B) {
+			m := regexp.MustCompile(bm.regexp)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.MatchString(bm.test)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/prometheus/selector"
 	"github.com/netdata/go.d.plugin/pkg/web"
@@ -24,6 +25,30 @@ type (
 		ScrapeSeries() (Series, error)
 		Scrape() (MetricFamilies, error)
 		HTTPClient() *http.Client
+		// LastScrape returns the metadata of the last scrape
+		LastScrape() ScrapeMeta
+	}
+
+	// Options are the optional Prometheus instance settings.
+	Options struct {
+		// Selector filters the scraped series.
+		Selector selector.Selector
+		// SampleLimit is the maximum number of the samples (after filtering) per scrape, 0 means no limit.
+		// Parsing is aborted with SampleLimitError if it is exceeded.
+		SampleLimit int
+		// PreferProtobuf makes the client request the protobuf exposition format, the text format is used
+		// if the server doesn't support it. Native histograms are exposed only in the protobuf format.
+		PreferProtobuf bool
+	}
+
+	// ScrapeMeta is the scrape metadata.
+	ScrapeMeta struct {
+		// Duration is the time spent fetching and parsing.
+		Duration time.Duration
+		// BodySize is the (decompressed) response body size in bytes.
+		BodySize int64
+		// Samples is the number of the parsed samples.
+		Samples int
 	}
 
 	prometheus struct {
@@ -33,36 +58,52 @@ type (
 
 		sr selector.Selector
 
-		parser promTextParser
+		parser         promTextParser
+		preferProtobuf bool
 
 		buf     *bytes.Buffer
 		gzipr   *gzip.Reader
 		bodyBuf *bufio.Reader
+
+		meta ScrapeMeta
 	}
 )
 
+// SampleLimitError is returned when the number of the scraped samples exceeds the sample limit.
+type SampleLimitError struct {
+	Limit int
+}
+
+func (e *SampleLimitError) Error() string {
+	return fmt.Sprintf("sample limit exceeded: the scrape has more than %d samples", e.Limit)
+}
+
 const (
-	acceptHeader    = `text/plain;version=0.0.4;q=1,*/*;q=0.1`
-	userAgentHeader = `netdata/go.d.plugin`
+	acceptHeader         = `text/plain;version=0.0.4;q=1,*/*;q=0.1`
+	acceptHeaderProtobuf = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3,*/*;q=0.1`
+	userAgentHeader      = `netdata/go.d.plugin`
 )
 
 // New creates a Prometheus instance.
 func New(client *http.Client, request web.Request) Prometheus {
-	return &prometheus{
-		client:  client,
-		request: request,
-		buf:     bytes.NewBuffer(make([]byte, 0, 16000)),
-	}
+	return NewWithOptions(client, request, Options{})
 }
 
 // NewWithSelector creates a Prometheus instance with the selector.
 func NewWithSelector(client *http.Client, request web.Request, sr selector.Selector) Prometheus {
+	return NewWithOptions(client, request, Options{Selector: sr})
+}
+
+// NewWithOptions creates a Prometheus instance with the options.
+func NewWithOptions(client *http.Client, request web.Request, opts Options) Prometheus {
 	p := &prometheus{
 		client:  client,
 		request: request,
-		sr:      sr,
+		sr:      opts.Selector,
 		buf:     bytes.NewBuffer(make([]byte, 0, 16000)),
-		parser:  promTextParser{sr: sr},
+		parser:  promTextParser{sr: opts.Selector, sampleLimit: opts.SampleLimit},
+
+		preferProtobuf: opts.PreferProtobuf,
 	}
 
 	if v, err := url.Parse(request.URL); err == nil && v.Scheme == "file" {
@@ -76,53 +117,91 @@ func (p *prometheus) HTTPClient() *http.Client {
 	return p.client
 }
 
+func (p *prometheus) LastScrape() ScrapeMeta {
+	return p.meta
+}
+
 // ScrapeSeries scrapes metrics, parses and sorts
 func (p *prometheus) ScrapeSeries() (Series, error) {
-	p.buf.Reset()
+	defer p.startScrape()()
 
-	if err := p.fetch(p.buf); err != nil {
+	protobuf, err := p.fetch(p.buf)
+	if err != nil {
 		return nil, err
 	}
+	p.meta.BodySize = int64(p.buf.Len())
+
+	var series Series
+	if protobuf {
+		series, err = p.parser.parseProtobufToSeries(p.buf.Bytes())
+	} else {
+		series, err = p.parser.parseToSeries(p.buf.Bytes())
+	}
+	p.meta.Samples = p.parser.samples
 
-	return p.parser.parseToSeries(p.buf.Bytes())
+	return series, err
 }
 
 func (p *prometheus) Scrape() (MetricFamilies, error) {
-	p.buf.Reset()
+	defer p.startScrape()()
 
-	if err := p.fetch(p.buf); err != nil {
+	protobuf, err := p.fetch(p.buf)
+	if err != nil {
 		return nil, err
 	}
+	p.meta.BodySize = int64(p.buf.Len())
 
-	return p.parser.parseToMetricFamilies(p.buf.Bytes())
+	var mfs MetricFamilies
+	if protobuf {
+		mfs, err = p.parser.parseProtobufToMetricFamilies(p.buf.Bytes())
+	} else {
+		mfs, err = p.parser.parseToMetricFamilies(p.buf.Bytes())
+	}
+	p.meta.Samples = p.parser.samples
+
+	return mfs, err
+}
+
+// startScrape resets the scrape state, the returned function records the scrape duration.
+func (p *prometheus) startScrape() func() {
+	p.buf.Reset()
+	p.meta = ScrapeMeta{}
+	now := time.Now()
+
+	return func() { p.meta.Duration = time.Since(now) }
 }
 
-func (p *prometheus) fetch(w io.Writer) error {
+// fetch writes the response body to w, it returns whether the body is in the protobuf format.
+func (p *prometheus) fetch(w io.Writer) (bool, error) {
 	// TODO: should be a separate text file prom client
 	if p.filepath != "" {
 		f, err := os.Open(p.filepath)
 		if err != nil {
-			return err
+			return false, err
 		}
 		defer f.Close()
 
 		_, err = io.Copy(w, f)
 
-		return err
+		return false, err
 	}
 
 	req, err := web.NewHTTPRequest(p.request)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	req.Header.Add("Accept", acceptHeader)
+	if p.preferProtobuf {
+		req.Header.Add("Accept", acceptHeaderProtobuf)
+	} else {
+		req.Header.Add("Accept", acceptHeader)
+	}
 	req.Header.Add("Accept-Encoding", "gzip")
 	req.Header.Set("User-Agent", userAgentHeader)
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	defer func() {
@@ -131,19 +210,21 @@ func (p *prometheus) fetch(w io.Writer) error {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("server '%s' returned HTTP status code %d (%s)", req.URL, resp.StatusCode, resp.Status)
+		return false, fmt.Errorf("server '%s' returned HTTP status code %d (%s)", req.URL, resp.StatusCode, resp.Status)
 	}
 
+	protobuf := p.preferProtobuf && isProtobufContentType(resp.Header.Get("Content-Type"))
+
 	if resp.Header.Get("Content-Encoding") != "gzip" {
 		_, err = io.Copy(w, resp.Body)
-		return err
+		return protobuf, err
 	}
 
 	if p.gzipr == nil {
 		p.bodyBuf = bufio.NewReader(resp.Body)
 		p.gzipr, err = gzip.NewReader(p.bodyBuf)
 		if err != nil {
-			return err
+			return false, err
 		}
 	} else {
 		p.bodyBuf.Reset(resp.Body)
@@ -153,5 +234,5 @@ func (p *prometheus) fetch(w io.Writer) error {
 	_, err = io.Copy(w, p.gzipr)
 	_ = p.gzipr.Close()
 
-	return err
+	return protobuf, err
 }
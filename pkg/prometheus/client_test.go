@@ -5,6 +5,7 @@ package prometheus
 import (
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -111,6 +112,189 @@ func TestPrometheusGzip(t *testing.T) {
 	}
 }
 
+func TestPrometheusBodySizeLimit(t *testing.T) {
+	for _, encoding := range []string{"", "gzip"} {
+		t.Run("encoding "+encoding, func(t *testing.T) {
+			tsMux := http.NewServeMux()
+			tsMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+				if encoding == "" {
+					_, _ = w.Write(testData)
+					return
+				}
+				w.Header().Set("Content-Encoding", encoding)
+				ww := gzip.NewWriter(w)
+				_, _ = ww.Write(testData)
+				_ = ww.Close()
+			})
+			ts := httptest.NewServer(tsMux)
+			defer ts.Close()
+
+			client, err := web.NewHTTPClient(web.Client{BodySizeLimit: int64(len(testData) / 2)})
+			require.NoError(t, err)
+
+			prom := New(client, web.Request{URL: ts.URL + "/metrics"})
+			_, err = prom.ScrapeSeries()
+
+			var limitErr *web.BodySizeLimitError
+			assert.ErrorAs(t, err, &limitErr)
+		})
+	}
+}
+
+func TestPrometheusSampleLimit(t *testing.T) {
+	const numSeries = 1_000_100
+
+	var body bytes.Buffer
+	body.WriteString("# TYPE test_metric gauge\n")
+	for i := 0; i < numSeries; i++ {
+		_, _ = fmt.Fprintf(&body, "test_metric{id=\"%d\"} %d\n", i, i)
+	}
+
+	tsMux := http.NewServeMux()
+	tsMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body.Bytes())
+	})
+	ts := httptest.NewServer(tsMux)
+	defer ts.Close()
+
+	tests := map[string]struct {
+		limit       int
+		wantErr     bool
+		wantSamples int
+	}{
+		"limit exceeded": {
+			limit:       1_000_000,
+			wantErr:     true,
+			wantSamples: 1_000_000,
+		},
+		"limit not exceeded": {
+			limit:       numSeries,
+			wantSamples: numSeries,
+		},
+		"no limit": {
+			wantSamples: numSeries,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prom := NewWithOptions(http.DefaultClient, web.Request{URL: ts.URL + "/metrics"}, Options{SampleLimit: test.limit})
+
+			for _, scrape := range []func() error{
+				func() error { _, err := prom.ScrapeSeries(); return err },
+				func() error { _, err := prom.Scrape(); return err },
+			} {
+				err := scrape()
+
+				if test.wantErr {
+					var limitErr *SampleLimitError
+					require.ErrorAs(t, err, &limitErr)
+					assert.Equal(t, test.limit, limitErr.Limit)
+				} else {
+					require.NoError(t, err)
+				}
+				meta := prom.LastScrape()
+				assert.Equal(t, test.wantSamples, meta.Samples)
+				assert.Equal(t, int64(body.Len()), meta.BodySize)
+				assert.True(t, meta.Duration > 0)
+			}
+		})
+	}
+}
+
+func TestPrometheusLastScrape(t *testing.T) {
+	tests := map[string]struct {
+		encoding string
+	}{
+		"plain": {},
+		"gzip":  {encoding: "gzip"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tsMux := http.NewServeMux()
+			tsMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+				if test.encoding == "" || !strings.Contains(r.Header.Get("Accept-Encoding"), test.encoding) {
+					_, _ = w.Write(testData)
+					return
+				}
+				w.Header().Set("Content-Encoding", test.encoding)
+				ww := gzip.NewWriter(w)
+				_, _ = ww.Write(testData)
+				_ = ww.Close()
+			})
+			ts := httptest.NewServer(tsMux)
+			defer ts.Close()
+
+			for _, client := range []*http.Client{http.DefaultClient, newTestHTTPClient(t)} {
+				prom := New(client, web.Request{URL: ts.URL + "/metrics"})
+
+				res, err := prom.ScrapeSeries()
+				require.NoError(t, err)
+				verifyTestData(t, res)
+
+				meta := prom.LastScrape()
+				assert.Equal(t, len(res), meta.Samples)
+				assert.Equal(t, int64(len(testData)), meta.BodySize)
+				assert.True(t, meta.Duration > 0)
+			}
+		})
+	}
+}
+
+func newTestHTTPClient(t *testing.T) *http.Client {
+	client, err := web.NewHTTPClient(web.Client{})
+	require.NoError(t, err)
+	return client
+}
+
+func TestPrometheusPreferProtobuf(t *testing.T) {
+	tests := map[string]struct {
+		preferProtobuf    bool
+		serverNoProtobuf  bool
+		wantNativeSummary bool
+	}{
+		"prefer protobuf": {
+			preferProtobuf:    true,
+			wantNativeSummary: true,
+		},
+		"prefer protobuf, server supports only text": {
+			preferProtobuf:   true,
+			serverNoProtobuf: true,
+		},
+		"text": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tsMux := http.NewServeMux()
+			tsMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+				if !test.serverNoProtobuf && strings.HasPrefix(r.Header.Get("Accept"), "application/vnd.google.protobuf") {
+					w.Header().Set("Content-Type", "application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited")
+					_, _ = w.Write(dataNativeHistograms1PB)
+					return
+				}
+				w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+				_, _ = w.Write(dataNativeHistograms1Txt)
+			})
+			ts := httptest.NewServer(tsMux)
+			defer ts.Close()
+
+			prom := NewWithOptions(newTestHTTPClient(t), web.Request{URL: ts.URL + "/metrics"}, Options{PreferProtobuf: test.preferProtobuf})
+
+			mfs, err := prom.Scrape()
+			require.NoError(t, err)
+
+			require.NotNil(t, mfs.GetCounter("test_counter_total"))
+			if test.wantNativeSummary {
+				assert.NotNil(t, mfs.GetSummary(nativeHistogramName))
+			} else {
+				assert.NotNil(t, mfs.GetHistogram(nativeHistogramName))
+			}
+		})
+	}
+}
+
 func TestPrometheusReadFromFile(t *testing.T) {
 	req := web.Request{URL: "file://testdata/testdata.txt"}
 	prom := NewWithSelector(http.DefaultClient, req, nil)
@@ -30,6 +30,11 @@ type promTextParser struct {
 
 	sr selector.Selector
 
+	protobuf protobufParser
+
+	sampleLimit int
+	samples     int
+
 	currMF     *MetricFamily
 	currSeries labels.Labels
 
@@ -46,9 +51,18 @@ type promTextParser struct {
 }
 
 func (p *promTextParser) parseToSeries(text []byte) (Series, error) {
+	return p.parseSeries(textparse.NewPromParser(text))
+}
+
+func (p *promTextParser) parseProtobufToSeries(data []byte) (Series, error) {
+	p.protobuf.reset(data)
+	return p.parseSeries(&p.protobuf)
+}
+
+func (p *promTextParser) parseSeries(parser textparse.Parser) (Series, error) {
 	p.series.Reset()
+	p.samples = 0
 
-	parser := textparse.NewPromParser(text)
 	for {
 		entry, err := parser.Next()
 		if err != nil {
@@ -70,6 +84,9 @@ func (p *promTextParser) parseToSeries(text []byte) (Series, error) {
 			if p.sr != nil && !p.sr.Matches(p.currSeries) {
 				continue
 			}
+			if err := p.addSample(); err != nil {
+				return nil, err
+			}
 
 			_, _, val := parser.Series()
 			p.series.Add(SeriesSample{Labels: copyLabels(p.currSeries), Value: val})
@@ -84,9 +101,17 @@ func (p *promTextParser) parseToSeries(text []byte) (Series, error) {
 var reSpace = regexp.MustCompile(`\s+`)
 
 func (p *promTextParser) parseToMetricFamilies(text []byte) (MetricFamilies, error) {
+	return p.parseMetricFamilies(textparse.NewPromParser(text))
+}
+
+func (p *promTextParser) parseProtobufToMetricFamilies(data []byte) (MetricFamilies, error) {
+	p.protobuf.reset(data)
+	return p.parseMetricFamilies(&p.protobuf)
+}
+
+func (p *promTextParser) parseMetricFamilies(parser textparse.Parser) (MetricFamilies, error) {
 	p.reset()
 
-	parser := textparse.NewPromParser(text)
 	for {
 		entry, err := parser.Next()
 		if err != nil {
@@ -120,6 +145,9 @@ func (p *promTextParser) parseToMetricFamilies(text []byte) (MetricFamilies, err
 			if p.sr != nil && !p.sr.Matches(p.currSeries) {
 				continue
 			}
+			if err := p.addSample(); err != nil {
+				return nil, err
+			}
 
 			p.setMetricFamilyBySeries()
 
@@ -149,6 +177,14 @@ func (p *promTextParser) parseToMetricFamilies(text []byte) (MetricFamilies, err
 	return p.metrics, nil
 }
 
+func (p *promTextParser) addSample() error {
+	if p.sampleLimit > 0 && p.samples == p.sampleLimit {
+		return &SampleLimitError{Limit: p.sampleLimit}
+	}
+	p.samples++
+	return nil
+}
+
 func (p *promTextParser) setMetricFamilyByName(name string) {
 	mf, ok := p.metrics[name]
 	if !ok {
@@ -368,6 +404,7 @@ func (p *promTextParser) addHistogram(value float64) {
 
 func (p *promTextParser) reset() {
 	p.currMF = nil
+	p.samples = 0
 	p.currSeries = p.currSeries[:0]
 
 	if p.metrics == nil {
@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package prometheus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+)
+
+// nativeHistogramQuantiles are the quantiles a native histogram is converted to.
+var nativeHistogramQuantiles = []float64{0.5, 0.75, 0.9, 0.95, 0.99}
+
+// protobufParser is textparse.Parser for the delimited protobuf exposition format. The metric families are
+// flattened into the same samples the text format has.
+//
+// Native histograms have no text format representation. A native histogram (no classic buckets)
+// is converted into a summary with the nativeHistogramQuantiles quantiles, they are calculated from
+// the observations since the previous scrape, so the parser is expected to be reused across scrapes.
+type protobufParser struct {
+	data []byte
+
+	mf      dto.MetricFamily
+	entries []protobufEntry
+	curr    protobufEntry
+
+	nativeHistograms map[uint64]*nativeHistogram
+	seen             map[uint64]bool
+}
+
+type protobufEntry struct {
+	entry textparse.Entry
+	typ   textparse.MetricType
+	lbs   labels.Labels
+	value float64
+}
+
+func (p *protobufParser) reset(data []byte) {
+	p.data = data
+	p.entries = p.entries[:0]
+
+	if p.nativeHistograms == nil {
+		p.nativeHistograms = make(map[uint64]*nativeHistogram)
+	}
+	if p.seen == nil {
+		p.seen = make(map[uint64]bool)
+	}
+	for k := range p.seen {
+		delete(p.seen, k)
+	}
+}
+
+func (p *protobufParser) Next() (textparse.Entry, error) {
+	for len(p.entries) == 0 {
+		if len(p.data) == 0 {
+			// the native histograms that are gone are forgotten
+			for k := range p.nativeHistograms {
+				if !p.seen[k] {
+					delete(p.nativeHistograms, k)
+				}
+			}
+			return textparse.EntryInvalid, io.EOF
+		}
+		if err := p.nextMetricFamily(); err != nil {
+			p.data = nil
+			return textparse.EntryInvalid, err
+		}
+	}
+
+	p.curr, p.entries = p.entries[0], p.entries[1:]
+
+	return p.curr.entry, nil
+}
+
+func (p *protobufParser) Series() ([]byte, *int64, float64) {
+	return nil, nil, p.curr.value
+}
+
+func (p *protobufParser) Help() ([]byte, []byte) {
+	return []byte(p.mf.GetName()), []byte(p.mf.GetHelp())
+}
+
+func (p *protobufParser) Type() ([]byte, textparse.MetricType) {
+	return []byte(p.mf.GetName()), p.curr.typ
+}
+
+func (p *protobufParser) Unit() ([]byte, []byte) {
+	return []byte(p.mf.GetName()), nil
+}
+
+func (p *protobufParser) Comment() []byte {
+	return nil
+}
+
+func (p *protobufParser) Metric(l *labels.Labels) string {
+	*l = append((*l)[:0], p.curr.lbs...)
+	return ""
+}
+
+func (p *protobufParser) Exemplar(*exemplar.Exemplar) bool {
+	return false
+}
+
+func (p *protobufParser) nextMetricFamily() error {
+	size, n := binary.Uvarint(p.data)
+	if n <= 0 || size > uint64(len(p.data)-n) {
+		return errors.New("protobuf: invalid message length")
+	}
+
+	p.mf.Reset()
+	if err := proto.Unmarshal(p.data[n:n+int(size)], &p.mf); err != nil {
+		return fmt.Errorf("protobuf: %v", err)
+	}
+	p.data = p.data[n+int(size):]
+
+	name := p.mf.GetName()
+	if name == "" {
+		return errors.New("protobuf: metric family without name")
+	}
+
+	typ := textparse.MetricTypeUnknown
+	switch p.mf.GetType() {
+	case dto.MetricType_COUNTER:
+		typ = textparse.MetricTypeCounter
+	case dto.MetricType_GAUGE:
+		typ = textparse.MetricTypeGauge
+	case dto.MetricType_SUMMARY:
+		typ = textparse.MetricTypeSummary
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		typ = textparse.MetricTypeHistogram
+		if len(p.mf.GetMetric()) > 0 && isNativeHistogram(p.mf.GetMetric()[0].GetHistogram()) {
+			typ = textparse.MetricTypeSummary
+		}
+	}
+
+	if p.mf.GetHelp() != "" {
+		p.entries = append(p.entries, protobufEntry{entry: textparse.EntryHelp})
+	}
+	p.entries = append(p.entries, protobufEntry{entry: textparse.EntryType, typ: typ})
+
+	for _, m := range p.mf.GetMetric() {
+		switch p.mf.GetType() {
+		case dto.MetricType_COUNTER:
+			p.addSample(name, m, "", "", m.GetCounter().GetValue())
+		case dto.MetricType_GAUGE:
+			p.addSample(name, m, "", "", m.GetGauge().GetValue())
+		case dto.MetricType_UNTYPED:
+			p.addSample(name, m, "", "", m.GetUntyped().GetValue())
+		case dto.MetricType_SUMMARY:
+			s := m.GetSummary()
+			for _, q := range s.GetQuantile() {
+				p.addSample(name, m, quantileLabel, formatFloat(q.GetQuantile()), q.GetValue())
+			}
+			p.addSample(name+sumSuffix, m, "", "", s.GetSampleSum())
+			p.addSample(name+countSuffix, m, "", "", float64(s.GetSampleCount()))
+		case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+			if typ == textparse.MetricTypeSummary {
+				p.addNativeHistogram(name, m)
+				continue
+			}
+			h := m.GetHistogram()
+			count := histogramCount(h)
+			var hasInf bool
+			for _, b := range h.GetBucket() {
+				v := b.GetCumulativeCountFloat()
+				if v == 0 {
+					v = float64(b.GetCumulativeCount())
+				}
+				hasInf = hasInf || math.IsInf(b.GetUpperBound(), 1)
+				p.addSample(name+bucketSuffix, m, bucketLabel, formatFloat(b.GetUpperBound()), v)
+			}
+			if !hasInf {
+				p.addSample(name+bucketSuffix, m, bucketLabel, "+Inf", count)
+			}
+			p.addSample(name+sumSuffix, m, "", "", h.GetSampleSum())
+			p.addSample(name+countSuffix, m, "", "", count)
+		}
+	}
+
+	return nil
+}
+
+func (p *protobufParser) addNativeHistogram(name string, m *dto.Metric) {
+	h := m.GetHistogram()
+	curr := newNativeHistogram(h)
+
+	hash := p.labels(name, m, "", "").Hash()
+	p.seen[hash] = true
+	prev := p.nativeHistograms[hash]
+	p.nativeHistograms[hash] = curr
+
+	delta := curr.sub(prev)
+	for _, q := range nativeHistogramQuantiles {
+		p.addSample(name, m, quantileLabel, formatFloat(q), delta.quantile(q))
+	}
+	p.addSample(name+sumSuffix, m, "", "", h.GetSampleSum())
+	p.addSample(name+countSuffix, m, "", "", histogramCount(h))
+}
+
+func (p *protobufParser) addSample(name string, m *dto.Metric, lblName, lblValue string, value float64) {
+	p.entries = append(p.entries, protobufEntry{
+		entry: textparse.EntrySeries,
+		lbs:   p.labels(name, m, lblName, lblValue),
+		value: value,
+	})
+}
+
+func (p *protobufParser) labels(name string, m *dto.Metric, lblName, lblValue string) labels.Labels {
+	lbs := make(labels.Labels, 0, len(m.GetLabel())+2)
+	lbs = append(lbs, labels.Label{Name: labels.MetricName, Value: name})
+	for _, l := range m.GetLabel() {
+		lbs = append(lbs, labels.Label{Name: l.GetName(), Value: l.GetValue()})
+	}
+	if lblName != "" {
+		lbs = append(lbs, labels.Label{Name: lblName, Value: lblValue})
+	}
+	sort.Sort(lbs)
+	return lbs
+}
+
+func isNativeHistogram(h *dto.Histogram) bool {
+	if h == nil || len(h.GetBucket()) > 0 {
+		return false
+	}
+	return h.GetSchema() != 0 || h.GetZeroThreshold() > 0 ||
+		len(h.GetPositiveSpan()) > 0 || len(h.GetNegativeSpan()) > 0
+}
+
+func histogramCount(h *dto.Histogram) float64 {
+	if v := h.GetSampleCountFloat(); v > 0 {
+		return v
+	}
+	return float64(h.GetSampleCount())
+}
+
+// nativeHistogram is a native histogram with the bucket counts (not cumulative) by the bucket index.
+type nativeHistogram struct {
+	schema        int32
+	zeroThreshold float64
+	zeroCount     float64
+	positive      map[int32]float64
+	negative      map[int32]float64
+}
+
+func newNativeHistogram(h *dto.Histogram) *nativeHistogram {
+	nh := &nativeHistogram{
+		schema:        h.GetSchema(),
+		zeroThreshold: h.GetZeroThreshold(),
+		zeroCount:     h.GetZeroCountFloat(),
+		positive:      nativeBuckets(h.GetPositiveSpan(), h.GetPositiveDelta(), h.GetPositiveCount()),
+		negative:      nativeBuckets(h.GetNegativeSpan(), h.GetNegativeDelta(), h.GetNegativeCount()),
+	}
+	if nh.zeroCount == 0 {
+		nh.zeroCount = float64(h.GetZeroCount())
+	}
+	return nh
+}
+
+// nativeBuckets decodes the buckets. The first span offset is the first bucket index, the next spans offsets
+// are relative to the previous span end. The integer counts are deltas to the previous bucket count.
+func nativeBuckets(spans []*dto.BucketSpan, deltas []int64, counts []float64) map[int32]float64 {
+	buckets := make(map[int32]float64)
+
+	var idx int32
+	var i int
+	var count int64
+	for n, span := range spans {
+		if n == 0 {
+			idx = span.GetOffset()
+		} else {
+			idx += span.GetOffset()
+		}
+		for j := uint32(0); j < span.GetLength(); j, idx, i = j+1, idx+1, i+1 {
+			switch {
+			case i < len(deltas):
+				count += deltas[i]
+				buckets[idx] = float64(count)
+			case i < len(counts):
+				buckets[idx] = counts[i]
+			}
+		}
+	}
+
+	return buckets
+}
+
+// sub returns the histogram of the observations made since prev. It is the histogram itself
+// if it is not comparable with prev (it was reset, the schema has changed, etc.).
+func (h *nativeHistogram) sub(prev *nativeHistogram) *nativeHistogram {
+	if prev == nil || prev.schema != h.schema || prev.zeroThreshold != h.zeroThreshold || h.zeroCount < prev.zeroCount {
+		return h
+	}
+
+	delta := &nativeHistogram{
+		schema:        h.schema,
+		zeroThreshold: h.zeroThreshold,
+		zeroCount:     h.zeroCount - prev.zeroCount,
+		positive:      make(map[int32]float64, len(h.positive)),
+		negative:      make(map[int32]float64, len(h.negative)),
+	}
+	for _, v := range []struct{ curr, prev, delta map[int32]float64 }{
+		{h.positive, prev.positive, delta.positive},
+		{h.negative, prev.negative, delta.negative},
+	} {
+		for idx, count := range v.curr {
+			if count < v.prev[idx] {
+				return h
+			}
+			v.delta[idx] = count - v.prev[idx]
+		}
+	}
+
+	return delta
+}
+
+// quantile estimates the quantile, the values are assumed to be distributed evenly within a bucket.
+// It is NaN if there are no observations.
+func (h *nativeHistogram) quantile(q float64) float64 {
+	type bucket struct{ lower, upper, count float64 }
+
+	buckets := make([]bucket, 0, len(h.negative)+len(h.positive)+1)
+
+	// the bigger a negative bucket index, the lower the values
+	for _, idx := range sortedKeys(h.negative, true) {
+		buckets = append(buckets, bucket{lower: -h.upperBound(idx), upper: -h.upperBound(idx - 1), count: h.negative[idx]})
+	}
+	buckets = append(buckets, bucket{lower: -h.zeroThreshold, upper: h.zeroThreshold, count: h.zeroCount})
+	for _, idx := range sortedKeys(h.positive, false) {
+		buckets = append(buckets, bucket{lower: h.upperBound(idx - 1), upper: h.upperBound(idx), count: h.positive[idx]})
+	}
+
+	var total float64
+	for _, b := range buckets {
+		total += b.count
+	}
+	if total == 0 {
+		return math.NaN()
+	}
+
+	rank := q * total
+	var acc float64
+	for _, b := range buckets {
+		if b.count == 0 {
+			continue
+		}
+		if acc+b.count >= rank {
+			return b.lower + (b.upper-b.lower)*(rank-acc)/b.count
+		}
+		acc += b.count
+	}
+
+	return buckets[len(buckets)-1].upper
+}
+
+// upperBound returns the bucket upper bound, the bucket boundaries are the powers of 2^(2^-schema).
+func (h *nativeHistogram) upperBound(idx int32) float64 {
+	return math.Pow(2, float64(idx)*math.Pow(2, -float64(h.schema)))
+}
+
+func sortedKeys(m map[int32]float64, desc bool) []int32 {
+	keys := make([]int32, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if desc {
+			return keys[i] > keys[j]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func isProtobufContentType(contentType string) bool {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/vnd.google.protobuf" &&
+		params["proto"] == "io.prometheus.client.MetricFamily" && params["encoding"] == "delimited"
+}
@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package prometheus
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The fixtures are captured from a client_golang (v1.19) exporter in both formats. The exporter has
+// a native histogram (no classic buckets), a histogram with both classic and native buckets and the other types.
+// Between the captures 500 observations (x10 bigger) are made to the histograms and the summary.
+var (
+	dataNativeHistograms1PB, _  = os.ReadFile("testdata/native-histograms-1.pb")
+	dataNativeHistograms1Txt, _ = os.ReadFile("testdata/native-histograms-1.txt")
+	dataNativeHistograms2PB, _  = os.ReadFile("testdata/native-histograms-2.pb")
+	dataNativeHistograms2Txt, _ = os.ReadFile("testdata/native-histograms-2.txt")
+)
+
+const nativeHistogramName = "test_native_histogram_seconds"
+
+func Test_testParseProtobufDataIsValid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"dataNativeHistograms1PB":  dataNativeHistograms1PB,
+		"dataNativeHistograms1Txt": dataNativeHistograms1Txt,
+		"dataNativeHistograms2PB":  dataNativeHistograms2PB,
+		"dataNativeHistograms2Txt": dataNativeHistograms2Txt,
+	} {
+		require.NotNilf(t, data, name)
+	}
+}
+
+func TestPromTextParser_parseProtobufToMetricFamilies(t *testing.T) {
+	tests := map[string]struct {
+		pb, txt []byte
+	}{
+		"first capture":  {pb: dataNativeHistograms1PB, txt: dataNativeHistograms1Txt},
+		"second capture": {pb: dataNativeHistograms2PB, txt: dataNativeHistograms2Txt},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var pbParser, txtParser promTextParser
+
+			mfs, err := pbParser.parseProtobufToMetricFamilies(test.pb)
+			require.NoError(t, err)
+			wantMFs, err := txtParser.parseToMetricFamilies(test.txt)
+			require.NoError(t, err)
+
+			require.Equal(t, wantMFs.Len(), mfs.Len())
+			for name, want := range wantMFs {
+				if name == nativeHistogramName {
+					continue
+				}
+				assert.Equalf(t, want, mfs.Get(name), "metric family '%s'", name)
+			}
+
+			native := mfs.GetSummary(nativeHistogramName)
+			require.NotNil(t, native, "the native histogram is converted into a summary")
+			require.Len(t, native.Metrics(), 2)
+			for i, m := range native.Metrics() {
+				want := wantMFs.GetHistogram(nativeHistogramName).Metrics()[i]
+				assert.Equal(t, want.Labels(), m.Labels())
+				assert.Equal(t, want.Histogram().Sum(), m.Summary().Sum())
+				assert.Equal(t, want.Histogram().Count(), m.Summary().Count())
+				assert.Len(t, m.Summary().Quantiles(), len(nativeHistogramQuantiles))
+			}
+		})
+	}
+}
+
+func TestPromTextParser_parseProtobufToMetricFamilies_NativeHistogramQuantiles(t *testing.T) {
+	// the exact quantiles of the observations, the estimation error is within the bucket width (factor 1.1)
+	type want struct {
+		method   string
+		quantile float64
+		value    float64
+	}
+	tests := []struct {
+		data []byte
+		want []want
+	}{
+		{
+			data: dataNativeHistograms1PB,
+			want: []want{
+				{method: "GET", quantile: 0.5, value: 0.3398},
+				{method: "GET", quantile: 0.9, value: 1.1677},
+				{method: "POST", quantile: 0.5, value: 1.0411},
+				{method: "POST", quantile: 0.9, value: 3.4101},
+			},
+		},
+		{
+			// the quantiles are calculated from the observations made since the previous scrape
+			data: dataNativeHistograms2PB,
+			want: []want{
+				{method: "GET", quantile: 0.5, value: 3.9551},
+				{method: "GET", quantile: 0.9, value: 11.2426},
+				{method: "POST", quantile: 0.5, value: math.NaN()},
+				{method: "POST", quantile: 0.9, value: math.NaN()},
+			},
+		},
+	}
+
+	var p promTextParser
+
+	for i, test := range tests {
+		mfs, err := p.parseProtobufToMetricFamilies(test.data)
+		require.NoError(t, err)
+
+		for _, w := range test.want {
+			v := getNativeHistogramQuantile(t, mfs, w.method, w.quantile)
+			if math.IsNaN(w.value) {
+				assert.Truef(t, math.IsNaN(v), "scrape %d %s quantile %v: no new observations", i+1, w.method, w.quantile)
+			} else {
+				assert.InEpsilonf(t, w.value, v, 0.05, "scrape %d %s quantile %v", i+1, w.method, w.quantile)
+			}
+		}
+	}
+}
+
+func TestPromTextParser_parseProtobufToSeries(t *testing.T) {
+	var pbParser, txtParser promTextParser
+
+	series, err := pbParser.parseProtobufToSeries(dataNativeHistograms1PB)
+	require.NoError(t, err)
+	wantSeries, err := txtParser.parseToSeries(dataNativeHistograms1Txt)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, withoutMetric(wantSeries, nativeHistogramName), withoutMetric(series, nativeHistogramName))
+
+	// 2 label sets: the quantiles, sum and count
+	assert.Len(t, series.FindByName(nativeHistogramName), 2*len(nativeHistogramQuantiles))
+	assert.Len(t, series.FindByName(nativeHistogramName+sumSuffix), 2)
+	assert.Len(t, series.FindByName(nativeHistogramName+countSuffix), 2)
+}
+
+func TestPromTextParser_parseProtobufToSeries_Malformed(t *testing.T) {
+	tests := map[string][]byte{
+		"not protobuf":      dataNativeHistograms1Txt,
+		"truncated message": dataNativeHistograms1PB[:len(dataNativeHistograms1PB)-10],
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			var p promTextParser
+
+			_, err := p.parseProtobufToSeries(data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIsProtobufContentType(t *testing.T) {
+	tests := map[string]bool{
+		"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited": true,
+		"application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited":   true,
+		"application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=text":      false,
+		"text/plain; version=0.0.4; charset=utf-8":                                                     false,
+		"": false,
+	}
+
+	for contentType, want := range tests {
+		assert.Equalf(t, want, isProtobufContentType(contentType), contentType)
+	}
+}
+
+func getNativeHistogramQuantile(t *testing.T, mfs MetricFamilies, method string, quantile float64) float64 {
+	mf := mfs.GetSummary(nativeHistogramName)
+	require.NotNil(t, mf)
+
+	for _, m := range mf.Metrics() {
+		if m.Labels().Get("method") != method {
+			continue
+		}
+		for _, q := range m.Summary().Quantiles() {
+			if q.Quantile() == quantile {
+				return q.Value()
+			}
+		}
+	}
+	require.Failf(t, "quantile not found", "method '%s' quantile %v", method, quantile)
+	return 0
+}
+
+func withoutMetric(series Series, name string) Series {
+	var res Series
+	for _, s := range series {
+		switch s.Name() {
+		case name, name + bucketSuffix, name + sumSuffix, name + countSuffix:
+		default:
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+var _ textparse.Parser = (*protobufParser)(nil)
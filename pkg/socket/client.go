@@ -14,7 +14,12 @@ import (
 // type (IP, TCP, UDP, UNIX), a network address (IP/domain:port),
 // a timeout and a TLS config. It supports both IPv4 and IPv6 address
 // and reuses connection where possible.
+// The TLS client sessions are cached, so reconnects resume the session instead of a full handshake.
 func New(config Config) *Socket {
+	if config.TLSConf != nil && config.TLSConf.ClientSessionCache == nil {
+		config.TLSConf = config.TLSConf.Clone()
+		config.TLSConf.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
 	return &Socket{
 		Config: config,
 		conn:   nil,
@@ -40,7 +45,7 @@ func (s *Socket) Connect() (err error) {
 		d.Timeout = s.ConnectTimeout
 		s.conn, err = tls.DialWithDialer(&d, network, address, s.TLSConf)
 	}
-	return err
+	return wrapTimeoutErr("connect", err)
 }
 
 // Disconnect closes the connection.
@@ -56,17 +61,18 @@ func (s *Socket) Disconnect() (err error) {
 // Command writes the command string to the connection and passed the
 // response bytes line by line to the process function. It uses the
 // timeout value from the Socket config and returns read, write and
-// timeout errors if any. If a timeout occurs during the processing
-// of the responses this function will stop processing and return a
-// timeout error.
+// timeout errors if any. The read deadline is reset before every line,
+// so the read timeout limits the wait for a line, not the whole response.
+// If a timeout occurs during the processing of the responses this function
+// will stop processing and return a *TimeoutError.
 func (s *Socket) Command(command string, process Processor) error {
 	if s.conn == nil {
 		return errors.New("cannot send command on nil connection")
 	}
 	if err := write(command, s.conn, s.WriteTimeout); err != nil {
-		return err
+		return wrapTimeoutErr("write", err)
 	}
-	return read(s.conn, process, s.ReadTimeout)
+	return wrapTimeoutErr("read", read(s.conn, process, s.ReadTimeout))
 }
 
 func write(command string, writer net.Conn, timeout time.Duration) error {
@@ -87,11 +93,22 @@ func read(reader net.Conn, process Processor, timeout time.Duration) error {
 	if reader == nil {
 		return errors.New("attempt to read on nil connection")
 	}
-	if err := reader.SetReadDeadline(time.Now().Add(timeout)); err != nil {
-		return err
-	}
 	scanner := bufio.NewScanner(reader)
-	for scanner.Scan() && process(scanner.Bytes()) {
+	for {
+		if err := reader.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+		if !scanner.Scan() || !process(scanner.Bytes()) {
+			break
+		}
 	}
 	return scanner.Err()
 }
+
+func wrapTimeoutErr(op string, err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &TimeoutError{Op: op, Err: err}
+	}
+	return err
+}
@@ -161,3 +161,83 @@ func Test_clientEmptyProcessFunc(t *testing.T) {
 	err := sock.Command("ping\n", nil)
 	require.Error(t, err, "nil process func should return an error")
 }
+
+func Test_clientReadDeadlineIsResetBetweenLines(t *testing.T) {
+	srv := &slowTCPServer{delay: defaultTimeout / 2, rowsNumResp: 5}
+	require.NoError(t, srv.Run())
+	defer func() { _ = srv.Close() }()
+
+	cfg := tcpConfig
+	cfg.Address = srv.Addr()
+	sock := New(cfg)
+	require.NoError(t, sock.Connect())
+	defer func() { _ = sock.Disconnect() }()
+
+	// the whole response takes longer than the read timeout, every line is within it
+	var lines int
+	err := sock.Command("ping\n", func(bytes []byte) bool {
+		lines++
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, lines)
+}
+
+func Test_clientReadTimeoutError(t *testing.T) {
+	srv := &slowTCPServer{delay: defaultTimeout * 3, rowsNumResp: 1}
+	require.NoError(t, srv.Run())
+	defer func() { _ = srv.Close() }()
+
+	cfg := tcpConfig
+	cfg.Address = srv.Addr()
+	sock := New(cfg)
+	require.NoError(t, sock.Connect())
+	defer func() { _ = sock.Disconnect() }()
+
+	err := sock.Command("ping\n", func([]byte) bool { return true })
+	require.Error(t, err)
+	assert.True(t, IsTimeout(err))
+
+	var timeoutErr *TimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, "read", timeoutErr.Op)
+}
+
+func Test_clientNotTimeoutError(t *testing.T) {
+	srv := &slowTCPServer{}
+	require.NoError(t, srv.Run())
+	addr := srv.Addr()
+	require.NoError(t, srv.Close())
+
+	cfg := tcpConfig
+	cfg.Address = addr
+	err := New(cfg).Connect()
+	require.Error(t, err)
+	assert.False(t, IsTimeout(err))
+}
+
+func Test_clientTLSSessionReuse(t *testing.T) {
+	srv := &tlsServer{rowsNumResp: 1}
+	require.NoError(t, srv.Run())
+	defer func() { _ = srv.Close() }()
+
+	cfg := tcpTlsConfig
+	cfg.Address = srv.Addr()
+	cfg.TLSConf = &tls.Config{InsecureSkipVerify: true}
+	sock := New(cfg)
+	assert.Nil(t, cfg.TLSConf.ClientSessionCache, "the passed TLS config must not be modified")
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, sock.Connect())
+		err := sock.Command("ping\n", func(bytes []byte) bool {
+			assert.Equal(t, "pong", string(bytes))
+			return true
+		})
+		require.NoError(t, err)
+
+		conn, ok := sock.conn.(*tls.Conn)
+		require.True(t, ok)
+		assert.Equalf(t, i > 0, conn.ConnectionState().DidResume, "connection #%d", i+1)
+		require.NoError(t, sock.Disconnect())
+	}
+}
@@ -4,8 +4,15 @@ package socket
 
 import (
 	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
+	"math/big"
 	"net"
 	"os"
 	"strings"
@@ -137,3 +144,117 @@ func (u *unixServer) handleConnection(conn net.Conn) {
 		_ = rw.Flush()
 	}
 }
+
+// slowTCPServer responds with rowsNumResp lines, sleeping delay before every line.
+type slowTCPServer struct {
+	server      net.Listener
+	delay       time.Duration
+	rowsNumResp int
+}
+
+func (t *slowTCPServer) Run() (err error) {
+	t.server, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return
+	}
+	go t.handleConnections()
+	return nil
+}
+
+func (t *slowTCPServer) Addr() string {
+	return t.server.Addr().String()
+}
+
+func (t *slowTCPServer) Close() (err error) {
+	return t.server.Close()
+}
+
+func (t *slowTCPServer) handleConnections() {
+	for {
+		conn, err := t.server.Accept()
+		if err != nil {
+			return
+		}
+		go t.handleConnection(conn)
+	}
+}
+
+func (t *slowTCPServer) handleConnection(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+		return
+	}
+	for i := 0; i < t.rowsNumResp; i++ {
+		time.Sleep(t.delay)
+		if _, err := conn.Write([]byte("pong\n")); err != nil {
+			return
+		}
+	}
+}
+
+// tlsServer responds with rowsNumResp lines over TLS, it uses a self-signed certificate.
+type tlsServer struct {
+	server      net.Listener
+	rowsNumResp int
+}
+
+func (t *tlsServer) Run() (err error) {
+	cert, err := newSelfSignedCert()
+	if err != nil {
+		return err
+	}
+	t.server, err = tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		return
+	}
+	go t.handleConnections()
+	return nil
+}
+
+func (t *tlsServer) Addr() string {
+	return t.server.Addr().String()
+}
+
+func (t *tlsServer) Close() (err error) {
+	return t.server.Close()
+}
+
+func (t *tlsServer) handleConnections() {
+	for {
+		conn, err := t.server.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer func() { _ = conn.Close() }()
+			_ = conn.SetDeadline(time.Now().Add(time.Second))
+
+			rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+			if _, err := rw.ReadString('\n'); err != nil {
+				return
+			}
+			_, _ = rw.WriteString(strings.Repeat("pong\n", t.rowsNumResp))
+			_ = rw.Flush()
+		}()
+	}
+}
+
+func newSelfSignedCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
@@ -4,6 +4,8 @@ package socket
 
 import (
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -39,3 +41,23 @@ type Config struct {
 	WriteTimeout   time.Duration
 	TLSConf        *tls.Config
 }
+
+// TimeoutError is returned when a socket operation (connect, read or write)
+// exceeds its deadline. It allows the callers to distinguish a slow endpoint from a down one.
+type TimeoutError struct {
+	Op  string
+	Err error
+}
+
+func (e *TimeoutError) Error() string { return fmt.Sprintf("socket %s timeout: %v", e.Op, e.Err) }
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// Timeout reports whether the error is a timeout, it is always true.
+func (e *TimeoutError) Timeout() bool { return true }
+
+// IsTimeout reports whether err is a socket operation timeout.
+func IsTimeout(err error) bool {
+	var v *TimeoutError
+	return errors.As(err, &v)
+}
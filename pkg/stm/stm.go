@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -26,12 +27,14 @@ func ToMap(s ...interface{}) map[string]int64 {
 	rv := map[string]int64{}
 	for _, v := range s {
 		value := reflect.Indirect(reflect.ValueOf(v))
-		toMap(value, rv, "", 1, 1)
+		toMap(value, rv, "", 1, 1, time.Nanosecond)
 	}
 	return rv
 }
 
-func toMap(value reflect.Value, rv map[string]int64, key string, mul, div int) {
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func toMap(value reflect.Value, rv map[string]int64, key string, mul, div int, unit time.Duration) {
 	if !value.IsValid() {
 		log.Panicf("value is not valid key=%s", key)
 	}
@@ -42,15 +45,19 @@ func toMap(value reflect.Value, rv map[string]int64, key string, mul, div int) {
 			return
 		}
 	}
+	if value.Type() == durationType {
+		convertDuration(value, rv, key, mul, div, unit)
+		return
+	}
 	switch value.Kind() {
 	case reflect.Ptr:
-		convertPtr(value, rv, key, mul, div)
+		convertPtr(value, rv, key, mul, div, unit)
 	case reflect.Struct:
 		convertStruct(value, rv, key)
 	case reflect.Array, reflect.Slice:
-		convertArraySlice(value, rv, key, mul, div)
+		convertArraySlice(value, rv, key, mul, div, unit)
 	case reflect.Map:
-		convertMap(value, rv, key, mul, div)
+		convertMap(value, rv, key, mul, div, unit)
 	case reflect.Bool:
 		convertBool(value, rv, key)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -58,15 +65,15 @@ func toMap(value reflect.Value, rv map[string]int64, key string, mul, div int) {
 	case reflect.Float32, reflect.Float64:
 		convertFloat(value, rv, key, mul, div)
 	case reflect.Interface:
-		convertInterface(value, rv, key, mul, div)
+		convertInterface(value, rv, key, mul, div, unit)
 	default:
 		log.Panicf("unsupported data type: %v", value.Kind())
 	}
 }
 
-func convertPtr(value reflect.Value, rv map[string]int64, key string, mul, div int) {
+func convertPtr(value reflect.Value, rv map[string]int64, key string, mul, div int, unit time.Duration) {
 	if !value.IsNil() {
-		toMap(value.Elem(), rv, key, mul, div)
+		toMap(value.Elem(), rv, key, mul, div, unit)
 	}
 }
 
@@ -83,23 +90,23 @@ func convertStruct(value reflect.Value, rv map[string]int64, key string) {
 			continue
 		}
 		value := value.Field(i)
-		prefix, mul, div := parseTag(tag)
-		toMap(value, rv, joinPrefix(key, prefix), mul, div)
+		prefix, mul, div, unit := parseTag(tag)
+		toMap(value, rv, joinPrefix(key, prefix), mul, div, unit)
 	}
 }
 
-func convertMap(value reflect.Value, rv map[string]int64, key string, mul, div int) {
+func convertMap(value reflect.Value, rv map[string]int64, key string, mul, div int, unit time.Duration) {
 	if value.IsNil() {
 		log.Panicf("value is nil key=%s", key)
 	}
 	for _, k := range value.MapKeys() {
-		toMap(value.MapIndex(k), rv, joinPrefix(key, k.String()), mul, div)
+		toMap(value.MapIndex(k), rv, joinPrefix(key, k.String()), mul, div, unit)
 	}
 }
 
-func convertArraySlice(value reflect.Value, rv map[string]int64, key string, mul, div int) {
+func convertArraySlice(value reflect.Value, rv map[string]int64, key string, mul, div int, unit time.Duration) {
 	for i := 0; i < value.Len(); i++ {
-		toMap(value.Index(i), rv, key, mul, div)
+		toMap(value.Index(i), rv, key, mul, div, unit)
 	}
 }
 
@@ -130,9 +137,17 @@ func convertFloat(value reflect.Value, rv map[string]int64, key string, mul, div
 	rv[key] = int64(floatVal * float64(mul) / float64(div))
 }
 
-func convertInterface(value reflect.Value, rv map[string]int64, key string, mul, div int) {
+func convertDuration(value reflect.Value, rv map[string]int64, key string, mul, div int, unit time.Duration) {
+	if _, ok := rv[key]; ok {
+		log.Panic("duplicate key: ", key)
+	}
+	durVal := value.Int()
+	rv[key] = durVal * int64(mul) / (int64(div) * int64(unit))
+}
+
+func convertInterface(value reflect.Value, rv map[string]int64, key string, mul, div int, unit time.Duration) {
 	fv := reflect.ValueOf(value.Interface())
-	toMap(fv, rv, key, mul, div)
+	toMap(fv, rv, key, mul, div, unit)
 }
 
 func joinPrefix(prefix, key string) string {
@@ -145,11 +160,28 @@ func joinPrefix(prefix, key string) string {
 	return prefix + "_" + key
 }
 
-func parseTag(tag string) (prefix string, mul int, div int) {
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+func parseTag(tag string) (prefix string, mul int, div int, unit time.Duration) {
 	tokens := strings.Split(tag, ",")
 	mul = 1
 	div = 1
+	unit = time.Nanosecond
 	var err error
+	if len(tokens) > 1 {
+		if v, ok := durationUnits[tokens[len(tokens)-1]]; ok {
+			unit = v
+			tokens = tokens[:len(tokens)-1]
+		}
+	}
 	switch len(tokens) {
 	case 3:
 		div, err = strconv.Atoi(tokens[2])
@@ -4,6 +4,7 @@ package stm_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/netdata/go.d.plugin/pkg/stm"
 
@@ -351,6 +352,156 @@ func TestToMap_bool(t *testing.T) {
 	)
 }
 
+func TestToMap_boolPtr(t *testing.T) {
+	yes, no := true, false
+	s := struct {
+		A *bool `stm:"a"`
+		B *bool `stm:"b"`
+		C *bool `stm:"c"`
+	}{
+		A: &yes,
+		B: &no,
+		C: nil,
+	}
+
+	expected := map[string]int64{
+		"a": 1,
+		"b": 0,
+	}
+
+	assert.EqualValuesf(t, expected, stm.ToMap(s), "value test")
+	assert.EqualValuesf(t, expected, stm.ToMap(&s), "ptr test")
+}
+
+func TestToMap_duration(t *testing.T) {
+	dur := time.Second * 3
+	s := struct {
+		NS     time.Duration  `stm:"ns"`
+		US     time.Duration  `stm:"us,us"`
+		MS     time.Duration  `stm:"ms,ms"`
+		S      time.Duration  `stm:"s,s"`
+		M      time.Duration  `stm:"m,m"`
+		H      time.Duration  `stm:"h,h"`
+		MulS   time.Duration  `stm:"mul_s,1000,s"`
+		MulDiv time.Duration  `stm:"mul_div_ms,10,2,ms"`
+		Neg    time.Duration  `stm:"neg,ms"`
+		Ptr    *time.Duration `stm:"ptr,ms"`
+		Nil    *time.Duration `stm:"nil,ms"`
+	}{
+		NS:     time.Millisecond,
+		US:     time.Millisecond,
+		MS:     time.Millisecond*1500 + time.Microsecond*999,
+		S:      time.Minute,
+		M:      time.Hour,
+		H:      time.Hour*24 + time.Minute*59,
+		MulS:   time.Millisecond * 1234,
+		MulDiv: time.Millisecond * 3,
+		Neg:    -time.Millisecond*2 - time.Microsecond*500,
+		Ptr:    &dur,
+		Nil:    nil,
+	}
+
+	expected := map[string]int64{
+		"ns":         1_000_000,
+		"us":         1000,
+		"ms":         1500,
+		"s":          60,
+		"m":          60,
+		"h":          24,
+		"mul_s":      1234,
+		"mul_div_ms": 15,
+		"neg":        -2,
+		"ptr":        3000,
+	}
+
+	assert.EqualValuesf(t, expected, stm.ToMap(s), "value test")
+	assert.EqualValuesf(t, expected, stm.ToMap(&s), "ptr test")
+}
+
+func TestToMap_durationCollections(t *testing.T) {
+	s := struct {
+		Map   map[string]time.Duration `stm:"map,s"`
+		Slice []time.Duration          `stm:"slice,ms"`
+		Iface interface{}              `stm:"iface,ms"`
+	}{
+		Map: map[string]time.Duration{
+			"a": time.Second,
+			"b": time.Minute,
+		},
+		Slice: []time.Duration{time.Second},
+		Iface: time.Second * 2,
+	}
+
+	expected := map[string]int64{
+		"map_a": 1,
+		"map_b": 60,
+		"slice": 1000,
+		"iface": 2000,
+	}
+
+	assert.EqualValuesf(t, expected, stm.ToMap(s), "value test")
+	assert.EqualValuesf(t, expected, stm.ToMap(&s), "ptr test")
+}
+
+func TestToMap_mapOfStructs(t *testing.T) {
+	type member struct {
+		Uptime  time.Duration   `stm:"uptime,s"`
+		Healthy bool            `stm:"healthy"`
+		Lag     *time.Duration  `stm:"lag,ms"`
+		States  map[string]bool `stm:"state"`
+	}
+	lag := time.Millisecond * 20
+	s := struct {
+		Members    map[string]member  `stm:"member"`
+		MembersPtr map[string]*member `stm:"member_ptr"`
+	}{
+		Members: map[string]member{
+			"a": {Uptime: time.Minute, Healthy: true, Lag: &lag, States: map[string]bool{"primary": true, "secondary": false}},
+			"b": {Uptime: time.Second, Healthy: false, States: map[string]bool{}},
+		},
+		MembersPtr: map[string]*member{
+			"c": {Uptime: time.Hour, Healthy: true, States: map[string]bool{}},
+			"d": nil,
+		},
+	}
+
+	expected := map[string]int64{
+		"member_a_uptime":          60,
+		"member_a_healthy":         1,
+		"member_a_lag":             20,
+		"member_a_state_primary":   1,
+		"member_a_state_secondary": 0,
+		"member_b_uptime":          1,
+		"member_b_healthy":         0,
+		"member_ptr_c_uptime":      3600,
+		"member_ptr_c_healthy":     1,
+	}
+
+	assert.EqualValuesf(t, expected, stm.ToMap(s), "value test")
+	assert.EqualValuesf(t, expected, stm.ToMap(&s), "ptr test")
+}
+
+func TestToMap_floatMulDiv(t *testing.T) {
+	// the unit is applied only to durations, the other kinds are converted as before
+	s := struct {
+		F  float64 `stm:"f,1000"`
+		FD float64 `stm:"fd,1000,3"`
+		I  int     `stm:"i,ms"`
+	}{
+		F:  1.23456,
+		FD: 1.5,
+		I:  1000,
+	}
+
+	expected := map[string]int64{
+		"f":  1234,
+		"fd": 500,
+		"i":  1000,
+	}
+
+	assert.EqualValuesf(t, expected, stm.ToMap(s), "value test")
+}
+
 func TestToMap_ArraySlice(t *testing.T) {
 	s := [4]interface{}{
 		map[string]int{
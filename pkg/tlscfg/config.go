@@ -5,6 +5,7 @@ package tlscfg
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
 )
@@ -20,13 +21,22 @@ type TLSConfig struct {
 	// TLSKey specifies tls key file.
 	TLSKey string `yaml:"tls_key"`
 
+	// TLSKeyPassword specifies the password of the encrypted tls key.
+	TLSKeyPassword string `yaml:"tls_key_password"`
+
+	// TLSP12 specifies the PKCS#12 bundle (.p12, .pfx) with the tls certificate and key, it is used instead of TLSCert and TLSKey.
+	TLSP12 string `yaml:"tls_p12"`
+
+	// TLSP12Password specifies the password of the PKCS#12 bundle.
+	TLSP12Password string `yaml:"tls_p12_password"`
+
 	// InsecureSkipVerify controls whether a client verifies the server's certificate chain and host name.
 	InsecureSkipVerify bool `yaml:"tls_skip_verify"`
 }
 
 // NewTLSConfig creates a tls.Config, may be nil without an error if TLS is not configured.
 func NewTLSConfig(cfg TLSConfig) (*tls.Config, error) {
-	if cfg.TLSCA == "" && cfg.TLSKey == "" && cfg.TLSCert == "" && !cfg.InsecureSkipVerify {
+	if cfg.TLSCA == "" && cfg.TLSKey == "" && cfg.TLSCert == "" && cfg.TLSP12 == "" && !cfg.InsecureSkipVerify {
 		return nil, nil
 	}
 
@@ -35,20 +45,36 @@ func NewTLSConfig(cfg TLSConfig) (*tls.Config, error) {
 		Renegotiation:      tls.RenegotiateNever,
 	}
 
+	// the certificates are short-lived if they are issued by cert-manager, Vault, etc.
+	// The files are reloaded when they change.
+
 	if cfg.TLSCA != "" {
-		pool, err := loadCertPool([]string{cfg.TLSCA})
-		if err != nil {
+		if err := setReloadingRootCAs(tlsConfig, cfg.TLSCA); err != nil {
 			return nil, err
 		}
-		tlsConfig.RootCAs = pool
 	}
 
-	if cfg.TLSCert != "" && cfg.TLSKey != "" {
-		cert, err := loadCertificate(cfg.TLSCert, cfg.TLSKey)
-		if err != nil {
-			return nil, err
-		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
+	if cfg.TLSP12 != "" && (cfg.TLSCert != "" || cfg.TLSKey != "") {
+		return nil, errors.New("'tls_p12' and 'tls_cert'/'tls_key' are mutually exclusive")
+	}
+
+	var err error
+	switch {
+	case cfg.TLSP12 != "":
+		err = setReloadingClientCertificate(tlsConfig, []string{cfg.TLSP12}, func() (tls.Certificate, error) {
+			return loadPKCS12Certificate(cfg.TLSP12, cfg.TLSP12Password)
+		})
+	case cfg.TLSCert != "" && cfg.TLSKey != "" && cfg.TLSKeyPassword != "":
+		err = setReloadingClientCertificate(tlsConfig, []string{cfg.TLSCert, cfg.TLSKey}, func() (tls.Certificate, error) {
+			return loadEncryptedCertificate(cfg.TLSCert, cfg.TLSKey, cfg.TLSKeyPassword)
+		})
+	case cfg.TLSCert != "" && cfg.TLSKey != "":
+		err = setReloadingClientCertificate(tlsConfig, []string{cfg.TLSCert, cfg.TLSKey}, func() (tls.Certificate, error) {
+			return loadCertificate(cfg.TLSCert, cfg.TLSKey)
+		})
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return tlsConfig, nil
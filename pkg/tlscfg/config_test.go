@@ -2,9 +2,325 @@
 
 package tlscfg
 
-import "testing"
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
 
-// TODO:
-func TestNewClientTLSConfig(t *testing.T) {
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/pbkdf2"
+)
 
+func TestNewTLSConfig_ClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certPEM, key := ca.issue(t, "client")
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: marshalPKCS8(t, key)})
+	legacyKeyPEM := encryptLegacyPEM(t, key, "secret")
+	pkcs8KeyPEM := encryptPKCS8PEM(t, key, "secret")
+
+	tests := map[string]struct {
+		cfg          TLSConfig
+		wantErr      bool
+		wantPassword bool
+	}{
+		"not encrypted key": {
+			cfg: TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "key.pem", keyPEM)},
+		},
+		"not encrypted key, password is ignored": {
+			cfg: TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "key.pem", keyPEM), TLSKeyPassword: "secret"},
+		},
+		"legacy encrypted key": {
+			cfg: TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "legacy.pem", legacyKeyPEM), TLSKeyPassword: "secret"},
+		},
+		"legacy encrypted key, wrong password": {
+			cfg:          TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "legacy.pem", legacyKeyPEM), TLSKeyPassword: "wrong"},
+			wantErr:      true,
+			wantPassword: true,
+		},
+		"PKCS#8 encrypted key": {
+			cfg: TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "pkcs8.pem", pkcs8KeyPEM), TLSKeyPassword: "secret"},
+		},
+		"PKCS#8 encrypted key, wrong password": {
+			cfg:          TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "pkcs8.pem", pkcs8KeyPEM), TLSKeyPassword: "wrong"},
+			wantErr:      true,
+			wantPassword: true,
+		},
+		"PKCS#8 encrypted key, no password": {
+			cfg:     TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "pkcs8.pem", pkcs8KeyPEM)},
+			wantErr: true,
+		},
+		"malformed key": {
+			cfg:     TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "bad.pem", []byte("not a key")), TLSKeyPassword: "secret"},
+			wantErr: true,
+		},
+		"p12 and key are mutually exclusive": {
+			cfg:     TLSConfig{TLSCert: certFile, TLSKey: writeFile(t, dir, "key.pem", keyPEM), TLSP12: "client.p12"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.cfg.TLSCA = writeFile(t, dir, "ca.pem", ca.certPEM)
+
+			tlsConfig, err := NewTLSConfig(test.cfg)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, test.wantPassword, errorIsIncorrectPassword(err), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assertHandshake(t, ca, tlsConfig)
+		})
+	}
+}
+
+func TestNewTLSConfig_PKCS12(t *testing.T) {
+	if _, err := exec.LookPath("openssl"); err != nil {
+		t.Skip("openssl is required to create a PKCS#12 bundle")
+	}
+
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certPEM, key := ca.issue(t, "client")
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: marshalPKCS8(t, key)}))
+	caFile := writeFile(t, dir, "ca.pem", ca.certPEM)
+
+	p12File := filepath.Join(dir, "client.p12")
+	out, err := exec.Command("openssl", "pkcs12", "-export",
+		"-in", certFile, "-inkey", keyFile, "-certfile", caFile, "-out", p12File, "-passout", "pass:secret",
+		"-certpbe", "PBE-SHA1-3DES", "-keypbe", "PBE-SHA1-3DES", "-macalg", "sha1",
+	).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	aesP12File := filepath.Join(dir, "client_aes.p12")
+	out, err = exec.Command("openssl", "pkcs12", "-export",
+		"-in", certFile, "-inkey", keyFile, "-out", aesP12File, "-passout", "pass:secret",
+		"-certpbe", "AES-256-CBC", "-keypbe", "AES-256-CBC", "-macalg", "sha256",
+	).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	tests := map[string]struct {
+		cfg             TLSConfig
+		wantErr         bool
+		wantPassword    bool
+		wantUnsupported bool
+	}{
+		"valid bundle": {
+			cfg: TLSConfig{TLSP12: p12File, TLSP12Password: "secret"},
+		},
+		"modern encryption bundle": {
+			cfg:             TLSConfig{TLSP12: aesP12File, TLSP12Password: "secret"},
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+		"wrong password": {
+			cfg:          TLSConfig{TLSP12: p12File, TLSP12Password: "wrong"},
+			wantErr:      true,
+			wantPassword: true,
+		},
+		"malformed bundle": {
+			cfg:     TLSConfig{TLSP12: certFile, TLSP12Password: "secret"},
+			wantErr: true,
+		},
+		"not existing bundle": {
+			cfg:     TLSConfig{TLSP12: filepath.Join(dir, "not_exist.p12"), TLSP12Password: "secret"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.cfg.TLSCA = caFile
+
+			tlsConfig, err := NewTLSConfig(test.cfg)
+
+			if test.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, test.wantPassword, errorIsIncorrectPassword(err), err.Error())
+				assert.Equal(t, test.wantUnsupported, errors.Is(err, ErrUnsupportedPKCS12), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, tlsConfig.Certificates, 1)
+			assert.Len(t, tlsConfig.Certificates[0].Certificate, 2, "the chain is loaded")
+			assertHandshake(t, ca, tlsConfig)
+		})
+	}
+}
+
+func errorIsIncorrectPassword(err error) bool {
+	return errors.Is(err, ErrIncorrectPassword)
+}
+
+// assertHandshake connects to a server that requires a client certificate issued by the CA.
+func assertHandshake(t *testing.T, ca *testCA, clientConfig *tls.Config) {
+	assert.NoError(t, handshake(t, ca, clientConfig))
+}
+
+func handshake(t *testing.T, ca *testCA, clientConfig *tls.Config) error {
+	serverCertPEM, serverKey := ca.issue(t, "127.0.0.1")
+	serverCert, err := tls.X509KeyPair(serverCertPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: marshalPKCS8(t, serverKey)}))
+	require.NoError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    ca.pool,
+	})
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	errCh := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		errCh <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), clientConfig)
+	if err != nil {
+		_ = ln.Close()
+		<-errCh
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	return <-errCh
+}
+
+type testCA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	pool    *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &testCA{cert: cert, key: key, certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pool: pool}
+}
+
+func (ca *testCA) issue(t *testing.T, name string) ([]byte, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(name); ip != nil {
+		tmpl.IPAddresses = []net.IP{ip}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key
+}
+
+func marshalPKCS8(t *testing.T, key any) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return der
+}
+
+func encryptLegacyPEM(t *testing.T, key *rsa.PrivateKey, password string) []byte {
+	block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), []byte(password), x509.PEMCipherAES256)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(block)
+}
+
+// encryptPKCS8PEM encrypts the key the way 'openssl pkcs8 -topk8 -v2 aes-256-cbc -v2prf hmacWithSHA256' does.
+func encryptPKCS8PEM(t *testing.T, key any, password string) []byte {
+	salt, iv := make([]byte, 8), make([]byte, aes.BlockSize)
+	_, _ = rand.Read(salt)
+	_, _ = rand.Read(iv)
+	iterations := 2048
+
+	data := marshalPKCS8(t, key)
+	n := aes.BlockSize - len(data)%aes.BlockSize
+	for i := 0; i < n; i++ {
+		data = append(data, byte(n))
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(password), salt, iterations, 32, sha256.New))
+	require.NoError(t, err)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	require.NoError(t, err)
+	ivParams, err := asn1.Marshal(iv)
+	require.NoError(t, err)
+	pbes2, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParams}},
+	})
+	require.NoError(t, err)
+	der, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algo:          pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: pbes2}},
+		EncryptedData: data,
+	})
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
 }
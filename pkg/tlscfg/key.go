@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package tlscfg
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// ErrIncorrectPassword is returned when an encrypted private key or a PKCS#12 bundle can't be decrypted with the password.
+var ErrIncorrectPassword = errors.New("incorrect password")
+
+// ErrUnsupportedPKCS12 is returned when a PKCS#12 bundle uses the encryption that can't be decoded (PBES2, AES).
+var ErrUnsupportedPKCS12 = errors.New("unsupported PKCS#12 encryption, re-export with -legacy")
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+
+	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3   = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+type (
+	// RFC 5208
+	encryptedPrivateKeyInfo struct {
+		Algo          pkix.AlgorithmIdentifier
+		EncryptedData []byte
+	}
+	// RFC 8018
+	pbes2Params struct {
+		KeyDerivationFunc pkix.AlgorithmIdentifier
+		EncryptionScheme  pkix.AlgorithmIdentifier
+	}
+	pbkdf2Params struct {
+		Salt           []byte
+		IterationCount int
+		KeyLength      int                      `asn1:"optional"`
+		PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+	}
+)
+
+func loadEncryptedCertificate(certFile, keyFile, password string) (tls.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("could not read certificate %q: %v", certFile, err)
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("could not read key %q: %v", keyFile, err)
+	}
+
+	keyPEM, err = decryptPrivateKeyPEM(keyPEM, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("could not decrypt key %q: %w", keyFile, err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("could not load keypair %s:%s: %v", certFile, keyFile, err)
+	}
+	return cert, nil
+}
+
+// decryptPrivateKeyPEM decrypts the first private key in the PEM data. Both the legacy OpenSSL encryption
+// ('Proc-Type: 4,ENCRYPTED') and the PKCS#8 PBES2 encryption ('ENCRYPTED PRIVATE KEY') are supported.
+// A not encrypted key is returned as is.
+func decryptPrivateKeyPEM(data []byte, password string) ([]byte, error) {
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			return nil, errors.New("no private key found")
+		}
+
+		switch {
+		case block.Type == "ENCRYPTED PRIVATE KEY":
+			der, err := decryptPKCS8(block.Bytes, []byte(password))
+			if err != nil {
+				return nil, err
+			}
+			return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+		case x509.IsEncryptedPEMBlock(block):
+			// the legacy encryption is deprecated as insecure, but the keys are still around
+			der, err := x509.DecryptPEMBlock(block, []byte(password))
+			if err != nil {
+				if errors.Is(err, x509.IncorrectPasswordError) {
+					return nil, ErrIncorrectPassword
+				}
+				return nil, err
+			}
+			return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			return pem.EncodeToMemory(block), nil
+		}
+	}
+}
+
+func decryptPKCS8(der, password []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("malformed encrypted private key: %v", err)
+	}
+	if !info.Algo.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption algorithm %s (only PBES2 is supported)", info.Algo.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algo.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("malformed PBES2 parameters: %v", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function %s (only PBKDF2 is supported)", params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("malformed PBKDF2 parameters: %v", err)
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(kdf.PRF.Algorithm) == 0, kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 PRF %s", kdf.PRF.Algorithm)
+	}
+
+	var keyLen int
+	var newCipher func([]byte) (cipher.Block, error)
+	switch scheme := params.EncryptionScheme.Algorithm; {
+	case scheme.Equal(oidAES128CBC):
+		keyLen, newCipher = 16, aes.NewCipher
+	case scheme.Equal(oidAES192CBC):
+		keyLen, newCipher = 24, aes.NewCipher
+	case scheme.Equal(oidAES256CBC):
+		keyLen, newCipher = 32, aes.NewCipher
+	case scheme.Equal(oidDESEDE3):
+		keyLen, newCipher = 24, des.NewTripleDESCipher
+	default:
+		return nil, fmt.Errorf("unsupported encryption scheme %s", scheme)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("malformed encryption scheme parameters: %v", err)
+	}
+
+	key := pbkdf2.Key(password, kdf.Salt, kdf.IterationCount, keyLen, prf)
+	block, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(info.EncryptedData) == 0 || len(info.EncryptedData)%block.BlockSize() != 0 {
+		return nil, errors.New("malformed encrypted private key: invalid data length")
+	}
+
+	data := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data, info.EncryptedData)
+
+	// a wrong password gives garbage: an invalid padding or not a private key
+	n := int(data[len(data)-1])
+	if n == 0 || n > block.BlockSize() || !bytes.Equal(data[len(data)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, ErrIncorrectPassword
+	}
+	data = data[:len(data)-n]
+	if _, err := x509.ParsePKCS8PrivateKey(data); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	return data, nil
+}
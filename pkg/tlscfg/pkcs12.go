@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package tlscfg
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// loadPKCS12Certificate loads the client certificate, its private key and the chain certificates from the PKCS#12 bundle.
+// Only the legacy bundle encryption (3DES, RC2) is supported, see 'openssl pkcs12 -legacy'.
+func loadPKCS12Certificate(file, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("could not read PKCS#12 bundle %q: %v", file, err)
+	}
+
+	blocks, err := pkcs12.ToPEM(data, password)
+	if err != nil {
+		if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+			return tls.Certificate{}, fmt.Errorf("could not decrypt PKCS#12 bundle %q: %w", file, ErrIncorrectPassword)
+		}
+		// OpenSSL 3 exports bundles with PBES2 (AES-256-CBC) by default
+		var notImplemented pkcs12.NotImplementedError
+		if errors.As(err, &notImplemented) {
+			return tls.Certificate{}, fmt.Errorf("could not decode PKCS#12 bundle %q: %w (%v)", file, ErrUnsupportedPKCS12, err)
+		}
+		return tls.Certificate{}, fmt.Errorf("malformed PKCS#12 bundle %q: %v", file, err)
+	}
+
+	var key *pem.Block
+	var certs []*pem.Block
+	for _, block := range blocks {
+		switch {
+		case block.Type == "CERTIFICATE":
+			certs = append(certs, block)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY") && key == nil:
+			key = block
+		}
+	}
+	if key == nil || len(certs) == 0 {
+		return tls.Certificate{}, fmt.Errorf("malformed PKCS#12 bundle %q: no certificate or private key", file)
+	}
+
+	// the leaf certificate goes first, it has the same 'localKeyId' as the key
+	var certPEM []byte
+	for i, cert := range certs {
+		if id := key.Headers["localKeyId"]; id != "" && cert.Headers["localKeyId"] == id {
+			certs[0], certs[i] = certs[i], certs[0]
+			break
+		}
+	}
+	for _, cert := range certs {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: cert.Type, Bytes: cert.Bytes})...)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: key.Type, Bytes: key.Bytes}))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("malformed PKCS#12 bundle %q: %v", file, err)
+	}
+	return cert, nil
+}
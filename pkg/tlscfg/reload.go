@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package tlscfg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/netdata/go.d.plugin/logger"
+)
+
+// reloadCheckInterval is the minimum interval between the checks whether the files are changed.
+var reloadCheckInterval = time.Second * 10
+
+// fileReloader caches the value loaded from the files, it is reloaded when the files modification time or size change.
+// If reloading fails the previously loaded value is used.
+type fileReloader[T any] struct {
+	files []string
+	load  func() (T, error)
+
+	mux     sync.Mutex
+	value   T
+	stats   []fileStat
+	checked time.Time
+	failed  bool
+}
+
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+func newFileReloader[T any](files []string, load func() (T, error)) (*fileReloader[T], error) {
+	r := &fileReloader[T]{files: files, load: load, checked: time.Now()}
+
+	r.stats = r.statFiles()
+	v, err := load()
+	if err != nil {
+		return nil, err
+	}
+	r.value = v
+
+	return r, nil
+}
+
+func (r *fileReloader[T]) get() T {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if time.Since(r.checked) < reloadCheckInterval {
+		return r.value
+	}
+	r.checked = time.Now()
+
+	stats := r.statFiles()
+	if stats != nil && equalStats(stats, r.stats) {
+		return r.value
+	}
+
+	v, err := r.load()
+	if err != nil {
+		// the files might be in the middle of being replaced, it is retried on the next check
+		if !r.failed {
+			logger.Warningf("tls: reloading %s: %v, using the previously loaded", strings.Join(r.files, ", "), err)
+		}
+		r.failed = true
+		return r.value
+	}
+	if r.failed {
+		logger.Infof("tls: reloaded %s", strings.Join(r.files, ", "))
+	}
+
+	r.value, r.stats, r.failed = v, stats, false
+
+	return r.value
+}
+
+func (r *fileReloader[T]) statFiles() []fileStat {
+	stats := make([]fileStat, 0, len(r.files))
+	for _, file := range r.files {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return nil
+		}
+		stats = append(stats, fileStat{modTime: fi.ModTime(), size: fi.Size()})
+	}
+	return stats
+}
+
+func equalStats(a, b []fileStat) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
+
+// setReloadingClientCertificate makes the config use the client certificate reloaded when the files change.
+// Certificates is set to the certificate loaded at creation.
+func setReloadingClientCertificate(tlsConfig *tls.Config, files []string, load func() (tls.Certificate, error)) error {
+	r, err := newFileReloader(files, load)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig.Certificates = []tls.Certificate{r.value}
+	tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		cert := r.get()
+		return &cert, nil
+	}
+
+	return nil
+}
+
+// setReloadingRootCAs makes the config verify the server certificate using the CA bundle reloaded when the file changes.
+// The standard verification can't be used because RootCAs can't be changed, it is done in VerifyConnection instead.
+// The host name is taken from SNI, it isn't sent for IP addresses, so only the certificate chain is verified for them.
+// RootCAs is set to the pool loaded at creation.
+func setReloadingRootCAs(tlsConfig *tls.Config, caFile string) error {
+	r, err := newFileReloader([]string{caFile}, func() (*x509.CertPool, error) { return loadCertPool([]string{caFile}) })
+	if err != nil {
+		return err
+	}
+
+	tlsConfig.RootCAs = r.value
+	if tlsConfig.InsecureSkipVerify {
+		return nil
+	}
+
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tls: server didn't provide a certificate")
+		}
+		opts := x509.VerifyOptions{
+			Roots:         r.get(),
+			DNSName:       cs.ServerName,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+
+	return nil
+}
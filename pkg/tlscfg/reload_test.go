@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package tlscfg
+
+import (
+	"encoding/pem"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSConfig_Reload(t *testing.T) {
+	defer func(v time.Duration) { reloadCheckInterval = v }(reloadCheckInterval)
+	reloadCheckInterval = 0
+
+	dir := t.TempDir()
+	oldCA, newCA := newTestCA(t), newTestCA(t)
+
+	caFile := writeFile(t, dir, "ca.pem", oldCA.certPEM)
+	certPEM, key := oldCA.issue(t, "client")
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: marshalPKCS8(t, key)}))
+
+	tlsConfig, err := NewTLSConfig(TLSConfig{TLSCA: caFile, TLSCert: certFile, TLSKey: keyFile})
+	require.NoError(t, err)
+
+	assertHandshake(t, oldCA, tlsConfig)
+	assert.Error(t, handshake(t, newCA, tlsConfig), "the server certificate is issued by an unknown CA")
+
+	// rotate the CA and the client certificate
+	certPEM, key = newCA.issue(t, "client")
+	rewriteFile(t, caFile, newCA.certPEM)
+	rewriteFile(t, certFile, certPEM)
+	rewriteFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: marshalPKCS8(t, key)}))
+
+	assertHandshake(t, newCA, tlsConfig)
+	assert.Error(t, handshake(t, oldCA, tlsConfig), "the old CA is not trusted after the reload")
+
+	// the previously loaded files are used if reloading fails
+	rewriteFile(t, caFile, []byte("not a certificate"))
+	rewriteFile(t, certFile, []byte("not a certificate"))
+
+	assertHandshake(t, newCA, tlsConfig)
+}
+
+func TestNewTLSConfig_ReloadCheckInterval(t *testing.T) {
+	defer func(v time.Duration) { reloadCheckInterval = v }(reloadCheckInterval)
+	reloadCheckInterval = time.Hour
+
+	dir := t.TempDir()
+	oldCA, newCA := newTestCA(t), newTestCA(t)
+
+	caFile := writeFile(t, dir, "ca.pem", oldCA.certPEM)
+	certPEM, key := oldCA.issue(t, "client")
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: marshalPKCS8(t, key)}))
+
+	tlsConfig, err := NewTLSConfig(TLSConfig{TLSCA: caFile, TLSCert: certFile, TLSKey: keyFile})
+	require.NoError(t, err)
+
+	rewriteFile(t, caFile, newCA.certPEM)
+
+	assertHandshake(t, oldCA, tlsConfig)
+}
+
+// rewriteFile replaces the file content, the modification time is moved forward
+// to not depend on the file system timestamps resolution.
+func rewriteFile(t *testing.T, path string, data []byte) {
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+
+	mtime := fi.ModTime().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+}
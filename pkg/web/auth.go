@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuth2 is the configuration of the OAuth2 client credentials flow.
+// The token is obtained from the token endpoint and cached until it is about to expire.
+type OAuth2 struct {
+	// TokenURL specifies the token endpoint URL.
+	TokenURL string `yaml:"token_url"`
+
+	// ClientID specifies the client ID.
+	ClientID string `yaml:"client_id"`
+
+	// ClientSecret specifies the client secret.
+	ClientSecret string `yaml:"client_secret"`
+
+	// Scopes specifies the requested permissions.
+	Scopes []string `yaml:"scopes"`
+
+	// Audience specifies the 'audience' parameter of the token request, some providers require it.
+	Audience string `yaml:"audience"`
+}
+
+func (o OAuth2) isSet() bool {
+	return o.TokenURL != "" || o.ClientID != "" || o.ClientSecret != ""
+}
+
+// authTransport sets the 'Authorization: Bearer' request header.
+type authTransport struct {
+	base            http.RoundTripper
+	bearerToken     string
+	bearerTokenFile string
+	oauth2          *oauth2TokenSource
+}
+
+func newAuthTransport(base http.RoundTripper, tokenTransport *http.Transport, cfg Client) (*authTransport, error) {
+	var n int
+	for _, set := range []bool{cfg.BearerToken != "", cfg.BearerTokenFile != "", cfg.OAuth2.isSet()} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		return nil, errors.New("only one of 'bearer_token', 'bearer_token_file' and the OAuth2 client credentials can be set")
+	}
+
+	t := &authTransport{
+		base:            base,
+		bearerToken:     cfg.BearerToken,
+		bearerTokenFile: cfg.BearerTokenFile,
+	}
+
+	if t.bearerTokenFile != "" {
+		if _, err := readBearerTokenFile(t.bearerTokenFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.OAuth2.isSet() {
+		if cfg.OAuth2.TokenURL == "" || cfg.OAuth2.ClientID == "" {
+			return nil, errors.New("oauth2: 'token_url' and 'client_id' are required")
+		}
+		t.oauth2 = newOAuth2TokenSource(cfg.OAuth2, &http.Client{Transport: tokenTransport, Timeout: cfg.Timeout.Duration})
+	}
+
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper. A request rejected with 401 is retried once
+// with a new OAuth2 token, the cached one might have been revoked.
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.token(req.Context(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withBearerToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || t.oauth2 == nil {
+		return resp, err
+	}
+	// the body can't be sent again without GetBody
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainBody)
+	_ = resp.Body.Close()
+
+	if token, err = t.token(req.Context(), true); err != nil {
+		return nil, err
+	}
+
+	r := withBearerToken(req, token)
+	if req.GetBody != nil {
+		if r.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(r)
+}
+
+func (t *authTransport) token(ctx context.Context, refresh bool) (string, error) {
+	switch {
+	case t.oauth2 != nil:
+		return t.oauth2.token(ctx, refresh)
+	case t.bearerTokenFile != "":
+		// the file is read every time, the token might be rotated (e.g. a Kubernetes service account token)
+		return readBearerTokenFile(t.bearerTokenFile)
+	default:
+		return t.bearerToken, nil
+	}
+}
+
+func withBearerToken(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func readBearerTokenFile(path string) (string, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("bearer token file: %v", err)
+	}
+	token := strings.TrimSpace(string(bs))
+	if token == "" {
+		return "", fmt.Errorf("bearer token file '%s' is empty", path)
+	}
+	return token, nil
+}
+
+type oauth2TokenSource struct {
+	cfg    clientcredentials.Config
+	client *http.Client
+
+	mux    sync.Mutex
+	cached *oauth2.Token
+}
+
+func newOAuth2TokenSource(cfg OAuth2, client *http.Client) *oauth2TokenSource {
+	s := &oauth2TokenSource{
+		cfg: clientcredentials.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			TokenURL:     cfg.TokenURL,
+			Scopes:       cfg.Scopes,
+		},
+		client: client,
+	}
+	if cfg.Audience != "" {
+		s.cfg.EndpointParams = map[string][]string{"audience": {cfg.Audience}}
+	}
+	return s
+}
+
+// token returns the cached token, a new one is obtained if the cached one expires in less than 10 seconds.
+func (s *oauth2TokenSource) token(ctx context.Context, refresh bool) (string, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !refresh && s.cached.Valid() {
+		return s.cached.AccessToken, nil
+	}
+
+	tok, err := s.cfg.Token(context.WithValue(ctx, oauth2.HTTPClient, s.client))
+	if err != nil {
+		return "", fmt.Errorf("oauth2: obtaining token from '%s': %v", s.cfg.TokenURL, err)
+	}
+	s.cached = tok
+
+	return tok.AccessToken, nil
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_BearerToken(t *testing.T) {
+	var auth atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.Store(r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token1\n"), 0600))
+
+	client, err := NewHTTPClient(Client{BearerToken: "secret"})
+	require.NoError(t, err)
+	doRequest(t, client, srv.URL, http.StatusOK)
+	assert.Equal(t, "Bearer secret", auth.Load())
+
+	client, err = NewHTTPClient(Client{BearerTokenFile: tokenFile})
+	require.NoError(t, err)
+	doRequest(t, client, srv.URL, http.StatusOK)
+	assert.Equal(t, "Bearer token1", auth.Load())
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token2\n"), 0600))
+	doRequest(t, client, srv.URL, http.StatusOK)
+	assert.Equal(t, "Bearer token2", auth.Load(), "the rotated token is used")
+}
+
+func TestNewHTTPClient_OAuth2(t *testing.T) {
+	tests := map[string]struct {
+		expiresIn     int
+		revokeFirst   bool
+		requests      int
+		wantStatus    int
+		wantTokenReqs int64
+	}{
+		"the token is cached": {
+			expiresIn:     3600,
+			requests:      3,
+			wantStatus:    http.StatusOK,
+			wantTokenReqs: 1,
+		},
+		"the token is refreshed before expiry": {
+			expiresIn:     5,
+			requests:      3,
+			wantStatus:    http.StatusOK,
+			wantTokenReqs: 3,
+		},
+		"401 is retried with a new token": {
+			expiresIn:     3600,
+			revokeFirst:   true,
+			requests:      2,
+			wantStatus:    http.StatusOK,
+			wantTokenReqs: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tokenReqs atomic.Int64
+			tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, pass, _ := r.BasicAuth()
+				if user != "id" || pass != "secret" || r.FormValue("grant_type") != "client_credentials" ||
+					r.FormValue("scope") != "read write" || r.FormValue("audience") != "api" {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+					return
+				}
+				n := tokenReqs.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"access_token":"token%d","token_type":"bearer","expires_in":%d}`, n, test.expiresIn)
+			}))
+			defer tokenSrv.Close()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") == "" || (test.revokeFirst && r.Header.Get("Authorization") == "Bearer token1") {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			}))
+			defer srv.Close()
+
+			client, err := NewHTTPClient(Client{OAuth2: OAuth2{
+				TokenURL:     tokenSrv.URL,
+				ClientID:     "id",
+				ClientSecret: "secret",
+				Scopes:       []string{"read", "write"},
+				Audience:     "api",
+			}})
+			require.NoError(t, err)
+
+			for i := 0; i < test.requests; i++ {
+				doRequest(t, client, srv.URL, test.wantStatus)
+			}
+			assert.Equal(t, test.wantTokenReqs, tokenReqs.Load())
+		})
+	}
+}
+
+func TestNewHTTPClient_OAuth2TokenError(t *testing.T) {
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	defer tokenSrv.Close()
+
+	client, err := NewHTTPClient(Client{OAuth2: OAuth2{TokenURL: tokenSrv.URL, ClientID: "id", ClientSecret: "wrong"}})
+	require.NoError(t, err)
+
+	_, err = client.Get("http://127.0.0.1:1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth2: obtaining token from '"+tokenSrv.URL+"'")
+	assert.Contains(t, err.Error(), "invalid_client")
+}
+
+func TestNewHTTPClient_AuthInvalidConfig(t *testing.T) {
+	tests := map[string]Client{
+		"bearer token and oauth2":    {BearerToken: "token", OAuth2: OAuth2{TokenURL: "http://127.0.0.1", ClientID: "id"}},
+		"bearer token and file":      {BearerToken: "token", BearerTokenFile: "/etc/token"},
+		"oauth2 without token url":   {OAuth2: OAuth2{ClientID: "id", ClientSecret: "secret"}},
+		"oauth2 without client id":   {OAuth2: OAuth2{TokenURL: "http://127.0.0.1"}},
+		"bearer token file not read": {BearerTokenFile: filepath.Join(t.TempDir(), "not_exist")},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewHTTPClient(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func doRequest(t *testing.T, client *http.Client, url string, wantStatus int) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, wantStatus, resp.StatusCode)
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"net/url"
 
 	"github.com/netdata/go.d.plugin/pkg/tlscfg"
 )
@@ -27,10 +26,62 @@ type Client struct {
 	// Default (zero value) is std http package default policy (stop after 10 consecutive requests).
 	NotFollowRedirect bool `yaml:"not_follow_redirects"`
 
-	// ProxyURL specifies the URL of the proxy to use. An empty string means use the environment variables
-	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY (or the lowercase versions thereof) to get the URL.
+	// ProxyURL specifies the URL of the proxy to use. An empty string or 'environment' means use the environment
+	// variables HTTP_PROXY, HTTPS_PROXY and NO_PROXY (or the lowercase versions thereof) to get the URL.
 	ProxyURL string `yaml:"proxy_url"`
 
+	// NoProxy specifies the hosts that are accessed directly: IP addresses, CIDR blocks and domain names
+	// (a domain name matches its subdomains too). It is applied to both the configured and environment proxies.
+	NoProxy []string `yaml:"no_proxy"`
+
+	// Retries specifies the number of times a failed request is retried. Default (zero value) is no retries.
+	// Only idempotent requests are retried unless RetryNonIdempotent is set. The retries are done
+	// within Timeout, they don't extend it.
+	Retries int `yaml:"retries"`
+
+	// RetryBackoff specifies the delay before the first retry, it is doubled for every next retry.
+	// A 429 response 'Retry-After' header overrides it.
+	RetryBackoff Duration `yaml:"retry_backoff"`
+
+	// RetryOn specifies the failures to retry: 'connection_error', '5xx' and '429'. Default (zero value) is all of them.
+	RetryOn []string `yaml:"retry_on"`
+
+	// RetryNonIdempotent allows to retry non-idempotent (e.g. POST) requests.
+	RetryNonIdempotent bool `yaml:"retry_non_idempotent"`
+
+	// BearerToken specifies the token sent in the 'Authorization: Bearer' request header.
+	BearerToken string `yaml:"bearer_token"`
+
+	// BearerTokenFile specifies the file to read the bearer token from. It is read for every request.
+	BearerTokenFile string `yaml:"bearer_token_file"`
+
+	// OAuth2 specifies the OAuth2 client credentials flow configuration, the obtained token is sent as the bearer token.
+	OAuth2 `yaml:",inline"`
+
+	// UnixSocket specifies the unix socket to connect to instead of the request URL host. The socket can also be set
+	// in the request URL: 'unix:///path/to/socket:/request/path'. TLS options can't be used with unix sockets.
+	UnixSocket string `yaml:"unix_socket"`
+
+	// BodySizeLimit specifies the response body size limit in bytes, reading more returns BodySizeLimitError.
+	// The limit is applied to the decompressed gzip and deflate encoded bodies.
+	// Default (zero value) is DefaultBodySizeLimit, a negative value means no limit.
+	BodySizeLimit int64 `yaml:"body_size_limit"`
+
+	// KeepAlive specifies whether the connections are kept open and reused between requests.
+	// Default (nil) is enabled.
+	KeepAlive *bool `yaml:"keep_alive"`
+
+	// MaxIdleConnsPerHost specifies the maximum idle (keep-alive) connections to keep per host.
+	// Default (zero value) is http.DefaultMaxIdleConnsPerHost.
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host"`
+
+	// DisableHTTP2 disables HTTP/2, the client uses HTTP/1.1 only. Can't be used with ForceHTTP2.
+	DisableHTTP2 bool `yaml:"disable_http2"`
+
+	// ForceHTTP2 makes the client use HTTP/2: negotiated over TLS for 'https' URLs and h2c (HTTP/2 with prior
+	// knowledge) for 'http' URLs. Default (zero value) is HTTP/1.1.
+	ForceHTTP2 bool `yaml:"force_http2"`
+
 	// TLSConfig specifies the TLS configuration.
 	tlscfg.TLSConfig `yaml:",inline"`
 }
@@ -42,26 +93,64 @@ func NewHTTPClient(cfg Client) (*http.Client, error) {
 		return nil, fmt.Errorf("error on creating TLS config: %v", err)
 	}
 
-	if cfg.ProxyURL != "" {
-		if _, err := url.Parse(cfg.ProxyURL); err != nil {
-			return nil, fmt.Errorf("error on parsing proxy URL '%s': %v", cfg.ProxyURL, err)
-		}
+	if cfg.UnixSocket != "" && tlsConfig != nil {
+		return nil, errUnixSocketTLS
+	}
+
+	proxy, err := newProxyFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxIdleConnsPerHost < 0 {
+		return nil, fmt.Errorf("invalid max_idle_conns_per_host value: %d", cfg.MaxIdleConnsPerHost)
 	}
 
 	d := &net.Dialer{Timeout: cfg.Timeout.Duration}
 
+	// every client has its own transport, the connection pool is not shared between jobs
 	transport := &http.Transport{
-		Proxy:               proxyFunc(cfg.ProxyURL),
+		Proxy:               proxy,
 		TLSClientConfig:     tlsConfig,
-		DialContext:         d.DialContext,
+		DialContext:         newDialContext(d, cfg.UnixSocket, tlsConfig != nil),
 		TLSHandshakeTimeout: cfg.Timeout.Duration,
+		DisableKeepAlives:   cfg.KeepAlive != nil && !*cfg.KeepAlive,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
 	}
 
-	return &http.Client{
+	if err := configureHTTP2(transport, cfg); err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
 		Timeout:       cfg.Timeout.Duration,
 		Transport:     transport,
 		CheckRedirect: redirectFunc(cfg.NotFollowRedirect),
-	}, nil
+	}
+
+	switch {
+	case cfg.BodySizeLimit == 0:
+		client.Transport = &limitTransport{base: client.Transport, limit: DefaultBodySizeLimit}
+	case cfg.BodySizeLimit > 0:
+		client.Transport = &limitTransport{base: client.Transport, limit: cfg.BodySizeLimit}
+	}
+
+	if cfg.BearerToken != "" || cfg.BearerTokenFile != "" || cfg.OAuth2.isSet() {
+		if client.Transport, err = newAuthTransport(client.Transport, transport, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Retries < 0 {
+		return nil, fmt.Errorf("invalid retries value: %d", cfg.Retries)
+	}
+	if cfg.Retries > 0 {
+		if client.Transport, err = newRetryTransport(client.Transport, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
 }
 
 func redirectFunc(notFollowRedirect bool) func(req *http.Request, via []*http.Request) error {
@@ -70,11 +159,3 @@ func redirectFunc(notFollowRedirect bool) func(req *http.Request, via []*http.Re
 	}
 	return func(_ *http.Request, _ []*http.Request) error { return ErrRedirectAttempted }
 }
-
-func proxyFunc(rawProxyURL string) func(r *http.Request) (*url.URL, error) {
-	if rawProxyURL == "" {
-		return http.ProxyFromEnvironment
-	}
-	proxyURL, _ := url.Parse(rawProxyURL)
-	return http.ProxyURL(proxyURL)
-}
@@ -3,11 +3,17 @@
 package web
 
 import (
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewHTTPClient(t *testing.T) {
@@ -21,3 +27,126 @@ func TestNewHTTPClient(t *testing.T) {
 	assert.Equal(t, time.Second*5, client.Timeout)
 	assert.NotNil(t, client.CheckRedirect)
 }
+
+func TestNewHTTPClient_ConnectionReuse(t *testing.T) {
+	const numRequests = 10
+
+	tests := map[string]struct {
+		client       Client
+		wantNewConns int
+	}{
+		"default keeps connections alive": {
+			client:       Client{},
+			wantNewConns: 1,
+		},
+		"keep_alive enabled": {
+			client:       Client{KeepAlive: boolPtr(true)},
+			wantNewConns: 1,
+		},
+		"keep_alive disabled": {
+			client:       Client{KeepAlive: boolPtr(false)},
+			wantNewConns: numRequests,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv, newConns := newConnCountingServer(t, 0)
+			defer srv.Close()
+
+			client, err := NewHTTPClient(test.client)
+			require.NoError(t, err)
+
+			for i := 0; i < numRequests; i++ {
+				doTestRequest(t, client, srv.URL)
+			}
+
+			assert.Equal(t, int64(test.wantNewConns), newConns.Load())
+		})
+	}
+}
+
+func TestNewHTTPClient_MaxIdleConnsPerHost(t *testing.T) {
+	const numParallel = 8
+
+	tests := map[string]struct {
+		maxIdle      int
+		wantNewConns int
+	}{
+		"default": {
+			maxIdle:      0,
+			wantNewConns: numParallel + numParallel - http.DefaultMaxIdleConnsPerHost,
+		},
+		"max_idle_conns_per_host": {
+			maxIdle:      numParallel,
+			wantNewConns: numParallel,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// the server waits for all the parallel requests, so every request has its own connection
+			srv, newConns := newConnCountingServer(t, numParallel)
+			defer srv.Close()
+
+			client, err := NewHTTPClient(Client{MaxIdleConnsPerHost: test.maxIdle})
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				var wg sync.WaitGroup
+				for j := 0; j < numParallel; j++ {
+					wg.Add(1)
+					go func() { defer wg.Done(); doTestRequest(t, client, srv.URL) }()
+				}
+				wg.Wait()
+			}
+
+			assert.Equal(t, int64(test.wantNewConns), newConns.Load())
+		})
+	}
+}
+
+func TestNewHTTPClient_InvalidMaxIdleConnsPerHost(t *testing.T) {
+	_, err := NewHTTPClient(Client{MaxIdleConnsPerHost: -1})
+	assert.Error(t, err)
+}
+
+// newConnCountingServer returns a server that counts the new connections.
+// If barrier is set, the handler waits until the barrier number of requests are in flight.
+func newConnCountingServer(t *testing.T, barrier int) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var newConns, inFlight atomic.Int64
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if barrier > 0 {
+			mu.Lock()
+			if inFlight.Add(1)%int64(barrier) == 0 {
+				cond.Broadcast()
+			} else {
+				cond.Wait()
+			}
+			mu.Unlock()
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	srv.Start()
+	return srv, &newConns
+}
+
+func doTestRequest(t *testing.T, client *http.Client, url string) {
+	resp, err := client.Get(url)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+func boolPtr(v bool) *bool { return &v }
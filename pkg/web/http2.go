@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+)
+
+var errHTTP2Conflict = errors.New("'disable_http2' and 'force_http2' are mutually exclusive")
+
+// configureHTTP2 sets up the transport HTTP/2 support.
+// The transport has a custom DialContext, so it uses HTTP/1.1 unless HTTP/2 is forced.
+func configureHTTP2(t *http.Transport, cfg Client) error {
+	switch {
+	case cfg.DisableHTTP2 && cfg.ForceHTTP2:
+		return errHTTP2Conflict
+	case cfg.DisableHTTP2:
+		// a non-nil empty map disables HTTP/2 regardless of the GODEBUG settings
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	case cfg.ForceHTTP2:
+		t.ForceAttemptHTTP2 = true
+
+		dial := t.DialContext
+		h2c := &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx, network, addr)
+			},
+		}
+		t.RegisterProtocol("http", h2c)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/pkg/tlscfg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestNewHTTPClient_HTTP2(t *testing.T) {
+	protoHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(r.Proto)) })
+
+	tlsSrv := httptest.NewUnstartedServer(protoHandler)
+	tlsSrv.EnableHTTP2 = true
+	tlsSrv.StartTLS()
+	defer tlsSrv.Close()
+
+	h2cSrv := httptest.NewServer(h2c.NewHandler(protoHandler, &http2.Server{}))
+	defer h2cSrv.Close()
+
+	tests := map[string]struct {
+		client    Client
+		url       string
+		wantProto string
+		wantErr   bool
+	}{
+		"https: default": {
+			url:       tlsSrv.URL,
+			wantProto: "HTTP/1.1",
+		},
+		"https: force_http2": {
+			client:    Client{ForceHTTP2: true},
+			url:       tlsSrv.URL,
+			wantProto: "HTTP/2.0",
+		},
+		"https: disable_http2": {
+			client:    Client{DisableHTTP2: true},
+			url:       tlsSrv.URL,
+			wantProto: "HTTP/1.1",
+		},
+		"http: default": {
+			url:       h2cSrv.URL,
+			wantProto: "HTTP/1.1",
+		},
+		"http: force_http2 (h2c)": {
+			client:    Client{ForceHTTP2: true},
+			url:       h2cSrv.URL,
+			wantProto: "HTTP/2.0",
+		},
+		"both force_http2 and disable_http2": {
+			client:  Client{ForceHTTP2: true, DisableHTTP2: true},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.client.TLSConfig = tlscfg.TLSConfig{InsecureSkipVerify: true}
+			client, err := NewHTTPClient(test.client)
+
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			resp, err := client.Get(test.url)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.wantProto, resp.Proto)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultBodySizeLimit is the response body size limit used if Client.BodySizeLimit is not set.
+const DefaultBodySizeLimit = 100 << 20
+
+// BodySizeLimitError is returned when reading a response body that exceeds the body size limit.
+type BodySizeLimitError struct {
+	Limit int64
+}
+
+func (e *BodySizeLimitError) Error() string {
+	return fmt.Sprintf("response too large: the body exceeds the size limit (%d bytes)", e.Limit)
+}
+
+// limitTransport limits the response body size. It decompresses gzip and deflate encoded bodies,
+// the limit is applied to the decompressed body.
+type limitTransport struct {
+	base  http.RoundTripper
+	limit int64
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body := &limitedBody{body: resp.Body, r: resp.Body, limit: t.limit}
+
+	switch encoding := strings.ToLower(resp.Header.Get("Content-Encoding")); encoding {
+	case "gzip", "deflate":
+		body.encoding = encoding
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+
+	resp.Body = body
+	return resp, nil
+}
+
+type limitedBody struct {
+	body     io.ReadCloser
+	r        io.Reader
+	encoding string
+	zr       io.ReadCloser
+	limit    int64
+	read     int64
+	err      error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.encoding != "" && b.zr == nil {
+		if b.zr, b.err = newDecompressor(b.encoding, b.body); b.err != nil {
+			return 0, b.err
+		}
+		b.r = b.zr
+	}
+
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		b.err = &BodySizeLimitError{Limit: b.limit}
+		return n - int(b.read-b.limit), b.err
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	if b.zr != nil {
+		_ = b.zr.Close()
+	}
+	return b.body.Close()
+}
+
+func newDecompressor(encoding string, r io.Reader) (io.ReadCloser, error) {
+	if encoding == "gzip" {
+		return gzip.NewReader(r)
+	}
+
+	// 'deflate' is supposed to be zlib wrapped, but some servers send raw deflate
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
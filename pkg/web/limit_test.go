@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_BodySizeLimit(t *testing.T) {
+	tests := map[string]struct {
+		limit     int64
+		bodySize  int
+		encoding  string
+		wantLimit int64
+	}{
+		"under the limit":                 {limit: 1024, bodySize: 1024},
+		"over the limit":                  {limit: 1024, bodySize: 1025, wantLimit: 1024},
+		"gzip, under the limit":           {limit: 1024, bodySize: 1024, encoding: "gzip"},
+		"gzip bomb":                       {limit: 1024, bodySize: 10 << 20, encoding: "gzip", wantLimit: 1024},
+		"deflate (zlib), under the limit": {limit: 1024, bodySize: 1000, encoding: "deflate"},
+		"deflate (zlib) bomb":             {limit: 1024, bodySize: 10 << 20, encoding: "deflate", wantLimit: 1024},
+		"deflate (raw), under the limit":  {limit: 1024, bodySize: 1000, encoding: "deflate-raw"},
+		"deflate (raw) bomb":              {limit: 1024, bodySize: 10 << 20, encoding: "deflate-raw", wantLimit: 1024},
+		"default limit":                   {bodySize: 1 << 20},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := bytes.Repeat([]byte("0"), test.bodySize)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.encoding == "" {
+					_, _ = w.Write(data)
+					return
+				}
+				w.Header().Set("Content-Encoding", strings.TrimSuffix(test.encoding, "-raw"))
+				_, _ = w.Write(compress(t, test.encoding, data))
+			}))
+			defer srv.Close()
+
+			client, err := NewHTTPClient(Client{BodySizeLimit: test.limit})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+			// the body is decompressed by the client even if the caller asks for the compressed one
+			req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			body, err := io.ReadAll(resp.Body)
+
+			if test.wantLimit > 0 {
+				var limitErr *BodySizeLimitError
+				require.True(t, errors.As(err, &limitErr), "want BodySizeLimitError, got %v", err)
+				assert.Equal(t, test.wantLimit, limitErr.Limit)
+				assert.Len(t, body, int(test.wantLimit))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, data, body)
+			assert.Empty(t, resp.Header.Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestNewHTTPClient_NoBodySizeLimit(t *testing.T) {
+	client, err := NewHTTPClient(Client{BodySizeLimit: -1})
+	require.NoError(t, err)
+	assert.IsType(t, (*http.Transport)(nil), client.Transport)
+
+	client, err = NewHTTPClient(Client{})
+	require.NoError(t, err)
+	require.IsType(t, (*limitTransport)(nil), client.Transport)
+	assert.Equal(t, int64(DefaultBodySizeLimit), client.Transport.(*limitTransport).limit)
+}
+
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	default:
+		fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		require.NoError(t, err)
+		w = fw
+	}
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
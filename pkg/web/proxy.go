@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyFromEnvironment is the 'proxy_url' value to get the proxy URL from the environment variables.
+const ProxyFromEnvironment = "environment"
+
+// envProxyFunc is replaced in tests, http.ProxyFromEnvironment reads the environment once and never proxies loopback requests.
+var envProxyFunc = http.ProxyFromEnvironment
+
+func newProxyFunc(cfg Client) (func(*http.Request) (*url.URL, error), error) {
+	proxy := envProxyFunc
+	if cfg.ProxyURL != "" && cfg.ProxyURL != ProxyFromEnvironment {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("error on parsing proxy URL '%s': %v", cfg.ProxyURL, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	noProxy, err := newNoProxyMatcher(cfg.NoProxy)
+	if err != nil {
+		return nil, fmt.Errorf("error on parsing no proxy list: %v", err)
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if cfg.UnixSocket != "" || isUnixSocketHost(req.URL.Hostname()) || noProxy.match(req.URL.Hostname()) {
+			return nil, nil
+		}
+		proxyURL, err := proxy(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		return withProxyAuth(proxyURL, req), nil
+	}, nil
+}
+
+// withProxyAuth returns the proxy URL with the request 'Proxy-Authorization' basic credentials
+// (see proxy_username and proxy_password), they take precedence over the proxy URL credentials.
+// The transport uses them for the CONNECT request to the proxy, the request header alone
+// is not sent to the proxy when tunneling.
+func withProxyAuth(proxyURL *url.URL, req *http.Request) *url.URL {
+	v := req.Header.Get("Proxy-Authorization")
+	if v == "" {
+		return proxyURL
+	}
+	username, password, ok := (&http.Request{Header: http.Header{"Authorization": {v}}}).BasicAuth()
+	if !ok {
+		return proxyURL
+	}
+	u := *proxyURL
+	u.User = url.UserPassword(username, password)
+	return &u
+}
+
+type noProxyMatcher struct {
+	all     bool
+	ips     []net.IP
+	nets    []*net.IPNet
+	domains []string
+}
+
+// newNoProxyMatcher parses the hosts that should not be proxied: IP addresses, CIDR blocks and domain names.
+// A domain name matches the domain and its subdomains, '*' matches all the hosts.
+func newNoProxyMatcher(hosts []string) (*noProxyMatcher, error) {
+	var m noProxyMatcher
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		switch {
+		case host == "":
+		case host == "*":
+			m.all = true
+		case strings.Contains(host, "/"):
+			_, ipNet, err := net.ParseCIDR(host)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR '%s': %v", host, err)
+			}
+			m.nets = append(m.nets, ipNet)
+		case net.ParseIP(strings.Trim(host, "[]")) != nil:
+			m.ips = append(m.ips, net.ParseIP(strings.Trim(host, "[]")))
+		default:
+			m.domains = append(m.domains, strings.TrimPrefix(strings.TrimPrefix(host, "*"), "."))
+		}
+	}
+	return &m, nil
+}
+
+func (m *noProxyMatcher) match(host string) bool {
+	if m.all {
+		return true
+	}
+	host = strings.ToLower(host)
+	if ip := net.ParseIP(host); ip != nil {
+		for _, v := range m.ips {
+			if v.Equal(ip) {
+				return true
+			}
+		}
+		for _, v := range m.nets {
+			if v.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	host = strings.TrimSuffix(host, ".")
+	for _, domain := range m.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/pkg/tlscfg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("ok")) }))
+	defer target.Close()
+	tlsTarget := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("ok")) }))
+	defer tlsTarget.Close()
+
+	proxy := newTestProxy()
+	defer proxy.Close()
+
+	targetPort := target.URL[strings.LastIndex(target.URL, ":")+1:]
+
+	tests := map[string]struct {
+		client       Client
+		request      Request
+		envProxy     bool
+		wantProxied  bool
+		wantAuth     string
+		wantCONNECT  bool
+		wantErrOnNew bool
+	}{
+		"proxy url": {
+			client:      Client{ProxyURL: proxy.URL},
+			request:     Request{URL: target.URL},
+			wantProxied: true,
+		},
+		"proxy url, no proxy CIDR": {
+			client:  Client{ProxyURL: proxy.URL, NoProxy: []string{"10.0.0.0/8", "127.0.0.0/8"}},
+			request: Request{URL: target.URL},
+		},
+		"proxy url, no proxy IP": {
+			client:  Client{ProxyURL: proxy.URL, NoProxy: []string{"127.0.0.1"}},
+			request: Request{URL: target.URL},
+		},
+		"proxy url, no proxy domain": {
+			client:  Client{ProxyURL: proxy.URL, NoProxy: []string{"localhost"}},
+			request: Request{URL: "http://localhost:" + targetPort},
+		},
+		"proxy url, no proxy domain doesn't match": {
+			client:      Client{ProxyURL: proxy.URL, NoProxy: []string{"localhost"}},
+			request:     Request{URL: target.URL},
+			wantProxied: true,
+		},
+		"proxy url, proxy auth": {
+			client:      Client{ProxyURL: proxy.URL},
+			request:     Request{URL: target.URL, ProxyUsername: "user", ProxyPassword: "pass"},
+			wantProxied: true,
+			wantAuth:    "Basic dXNlcjpwYXNz",
+		},
+		"proxy url, proxy auth, https target": {
+			client:      Client{ProxyURL: proxy.URL, TLSConfig: tlscfg.TLSConfig{InsecureSkipVerify: true}},
+			request:     Request{URL: tlsTarget.URL, ProxyUsername: "user", ProxyPassword: "pass"},
+			wantProxied: true,
+			wantCONNECT: true,
+			wantAuth:    "Basic dXNlcjpwYXNz",
+		},
+		"proxy url with credentials": {
+			client:      Client{ProxyURL: strings.Replace(proxy.URL, "http://", "http://admin:secret@", 1)},
+			request:     Request{URL: target.URL},
+			wantProxied: true,
+			wantAuth:    "Basic YWRtaW46c2VjcmV0",
+		},
+		"proxy url with credentials, proxy auth": {
+			client: Client{
+				ProxyURL:  strings.Replace(proxy.URL, "http://", "http://admin:secret@", 1),
+				TLSConfig: tlscfg.TLSConfig{InsecureSkipVerify: true},
+			},
+			request:     Request{URL: tlsTarget.URL, ProxyUsername: "user", ProxyPassword: "pass"},
+			wantProxied: true,
+			wantCONNECT: true,
+			wantAuth:    "Basic dXNlcjpwYXNz",
+		},
+		"environment": {
+			client:      Client{ProxyURL: ProxyFromEnvironment},
+			request:     Request{URL: target.URL},
+			envProxy:    true,
+			wantProxied: true,
+		},
+		"environment, no proxy": {
+			client:   Client{ProxyURL: ProxyFromEnvironment, NoProxy: []string{"127.0.0.1/32"}},
+			request:  Request{URL: target.URL},
+			envProxy: true,
+		},
+		"environment, proxy not set": {
+			client:  Client{ProxyURL: ProxyFromEnvironment},
+			request: Request{URL: target.URL},
+		},
+		"invalid no proxy CIDR": {
+			client:       Client{ProxyURL: proxy.URL, NoProxy: []string{"127.0.0.0/33"}},
+			wantErrOnNew: true,
+		},
+		"invalid proxy url": {
+			client:       Client{ProxyURL: "http://127.0.0.1:%zz"},
+			wantErrOnNew: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func(f func(*http.Request) (*url.URL, error)) { envProxyFunc = f }(envProxyFunc)
+			envProxyFunc = func(*http.Request) (*url.URL, error) { return nil, nil }
+			if test.envProxy {
+				envProxyFunc = func(*http.Request) (*url.URL, error) { return url.Parse(proxy.URL) }
+			}
+			proxy.reset()
+
+			client, err := NewHTTPClient(test.client)
+			if test.wantErrOnNew {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req, err := NewHTTPRequest(test.request)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			assert.Equal(t, "ok", string(body))
+
+			requests := proxy.requests()
+			if !test.wantProxied {
+				assert.Empty(t, requests)
+				return
+			}
+			require.Len(t, requests, 1)
+			assert.Equal(t, test.wantCONNECT, requests[0].method == http.MethodConnect)
+			assert.Equal(t, test.wantAuth, requests[0].auth)
+		})
+	}
+}
+
+func TestNoProxyMatcher(t *testing.T) {
+	m, err := newNoProxyMatcher([]string{"10.0.0.0/8", "192.168.1.1", "::1", "example.com", ".corp.local", "*.internal"})
+	require.NoError(t, err)
+
+	tests := map[string]bool{
+		"10.20.30.40":      true,
+		"11.0.0.1":         false,
+		"192.168.1.1":      true,
+		"192.168.1.2":      false,
+		"::1":              true,
+		"example.com":      true,
+		"EXAMPLE.com":      true,
+		"api.example.com":  true,
+		"notexample.com":   false,
+		"corp.local":       true,
+		"host.corp.local":  true,
+		"host.internal":    true,
+		"internal.example": false,
+	}
+
+	for host, want := range tests {
+		assert.Equalf(t, want, m.match(host), "host '%s'", host)
+	}
+
+	all, err := newNoProxyMatcher([]string{"*"})
+	require.NoError(t, err)
+	assert.True(t, all.match("example.com"))
+}
+
+type proxyRequest struct {
+	method string
+	host   string
+	auth   string
+}
+
+type testProxy struct {
+	*httptest.Server
+	mux  sync.Mutex
+	reqs []proxyRequest
+}
+
+// newTestProxy returns a forward proxy that records the requests that traversed it.
+func newTestProxy() *testProxy {
+	p := &testProxy{}
+	p.Server = httptest.NewServer(http.HandlerFunc(p.serveHTTP))
+	return p
+}
+
+func (p *testProxy) reset() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.reqs = nil
+}
+
+func (p *testProxy) requests() []proxyRequest {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return append([]proxyRequest(nil), p.reqs...)
+}
+
+func (p *testProxy) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mux.Lock()
+	p.reqs = append(p.reqs, proxyRequest{method: r.Method, host: r.Host, auth: r.Header.Get("Proxy-Authorization")})
+	p.mux.Unlock()
+
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+
+	req, err := http.NewRequest(r.Method, r.URL.String(), r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := (&http.Transport{}).RoundTrip(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+func (p *testProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	dst, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		_ = dst.Close()
+		return
+	}
+	_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+	go func() { defer func() { _ = dst.Close() }(); _, _ = io.Copy(dst, conn) }()
+	go func() { defer func() { _ = conn.Close() }(); _, _ = io.Copy(conn, dst) }()
+}
@@ -13,7 +13,7 @@ import (
 // This structure is not intended to be used directly as part of a module's configuration.
 // Supported configuration file formats: YAML.
 type Request struct {
-	// URL specifies the URL to access.
+	// URL specifies the URL to access. 'unix:///path/to/socket:/request/path' accesses the path over the unix socket.
 	URL string `yaml:"url"`
 
 	// Body specifies the HTTP request body to be sent by the client.
@@ -57,10 +57,20 @@ func NewHTTPRequest(cfg Request) (*http.Request, error) {
 		body = strings.NewReader(cfg.Body)
 	}
 
-	req, err := http.NewRequest(cfg.Method, cfg.URL, body)
+	rawURL := cfg.URL
+	httpURL, isUnix := unixSocketHTTPURL(rawURL)
+	if isUnix {
+		rawURL = httpURL
+	}
+
+	req, err := http.NewRequest(cfg.Method, rawURL, body)
 	if err != nil {
 		return nil, err
 	}
+	if isUnix {
+		// the URL host is the encoded socket path, it is not sent
+		req.Host = "localhost"
+	}
 
 	if cfg.Username != "" || cfg.Password != "" {
 		req.SetBasicAuth(cfg.Username, cfg.Password)
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// The retryable conditions, see Client.RetryOn.
+const (
+	RetryOnConnectionError = "connection_error"
+	RetryOn5xx             = "5xx"
+	RetryOn429             = "429"
+)
+
+const (
+	defaultRetryBackoff = time.Millisecond * 100
+	maxDrainBody        = 4096
+)
+
+type retryTransport struct {
+	base           http.RoundTripper
+	retries        int
+	backoff        time.Duration
+	onConnErr      bool
+	on5xx          bool
+	on429          bool
+	nonIdempotent  bool
+	retriesCounter atomic.Int64
+}
+
+func newRetryTransport(base http.RoundTripper, cfg Client) (*retryTransport, error) {
+	t := &retryTransport{
+		base:          base,
+		retries:       cfg.Retries,
+		backoff:       cfg.RetryBackoff.Duration,
+		nonIdempotent: cfg.RetryNonIdempotent,
+	}
+	if t.backoff <= 0 {
+		t.backoff = defaultRetryBackoff
+	}
+
+	retryOn := cfg.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = []string{RetryOnConnectionError, RetryOn5xx, RetryOn429}
+	}
+	for _, v := range retryOn {
+		switch v {
+		case RetryOnConnectionError:
+			t.onConnErr = true
+		case RetryOn5xx:
+			t.on5xx = true
+		case RetryOn429:
+			t.on429 = true
+		default:
+			return nil, fmt.Errorf("unknown retry condition '%s' (supported: %s, %s, %s)",
+				v, RetryOnConnectionError, RetryOn5xx, RetryOn429)
+		}
+	}
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper. The retries share the request context,
+// so they never extend the client timeout.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.canRetry(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+
+		if attempt == t.retries {
+			return resp, err
+		}
+		delay, ok := t.retryDelay(req, resp, err, attempt)
+		if !ok {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.CopyN(io.Discard, resp.Body, maxDrainBody)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		t.retriesCounter.Add(1)
+	}
+}
+
+func (t *retryTransport) canRetry(req *http.Request) bool {
+	// the body can't be sent again without GetBody
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	return t.nonIdempotent || isIdempotent(req.Method)
+}
+
+// retryDelay returns the delay before the next attempt and whether the request should be retried.
+// A retry that can't be done within the request deadline is not done.
+func (t *retryTransport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	delay := t.backoff << attempt
+
+	switch {
+	case err != nil:
+		if !t.onConnErr || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if !t.on429 {
+			return 0, false
+		}
+		if v, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			delay = v
+		}
+	case resp.StatusCode >= 500 && resp.StatusCode <= 599:
+		if !t.on5xx {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) <= delay {
+		return 0, false
+	}
+	return delay, true
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if tm, err := http.ParseTime(v); err == nil {
+		return max(time.Until(tm), 0), true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// RetriesPerformed returns the number of the retries done by the client created by NewHTTPClient.
+// It is zero if the retries are not enabled.
+func RetriesPerformed(client *http.Client) int64 {
+	if client == nil {
+		return 0
+	}
+	if t, ok := client.Transport.(*retryTransport); ok {
+		return t.retriesCounter.Load()
+	}
+	return 0
+}
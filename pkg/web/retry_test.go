@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_Retries(t *testing.T) {
+	tests := map[string]struct {
+		client      Client
+		request     Request
+		failures    int
+		failStatus  int
+		retryAfter  string
+		wantStatus  int
+		wantCalls   int64
+		wantRetries int64
+	}{
+		"no retries": {
+			client:     Client{},
+			failures:   1,
+			failStatus: http.StatusBadGateway,
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  1,
+		},
+		"5xx, succeeds after retries": {
+			client:      Client{Retries: 3},
+			failures:    2,
+			failStatus:  http.StatusBadGateway,
+			wantStatus:  http.StatusOK,
+			wantCalls:   3,
+			wantRetries: 2,
+		},
+		"5xx, retries exhausted": {
+			client:      Client{Retries: 2},
+			failures:    5,
+			failStatus:  http.StatusServiceUnavailable,
+			wantStatus:  http.StatusServiceUnavailable,
+			wantCalls:   3,
+			wantRetries: 2,
+		},
+		"5xx, not a retryable condition": {
+			client:     Client{Retries: 3, RetryOn: []string{RetryOnConnectionError}},
+			failures:   1,
+			failStatus: http.StatusBadGateway,
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  1,
+		},
+		"4xx is not retried": {
+			client:     Client{Retries: 3},
+			failures:   1,
+			failStatus: http.StatusNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+		"429, honors Retry-After": {
+			client:      Client{Retries: 1, RetryBackoff: Duration{Duration: time.Hour}},
+			failures:    1,
+			failStatus:  http.StatusTooManyRequests,
+			retryAfter:  "0",
+			wantStatus:  http.StatusOK,
+			wantCalls:   2,
+			wantRetries: 1,
+		},
+		"429, Retry-After exceeds the timeout": {
+			client:     Client{Retries: 1, Timeout: Duration{Duration: time.Second}},
+			failures:   1,
+			failStatus: http.StatusTooManyRequests,
+			retryAfter: "120",
+			wantStatus: http.StatusTooManyRequests,
+			wantCalls:  1,
+		},
+		"POST is not retried": {
+			client:     Client{Retries: 3},
+			request:    Request{Method: http.MethodPost, Body: `{"key":"value"}`},
+			failures:   1,
+			failStatus: http.StatusBadGateway,
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  1,
+		},
+		"POST is retried if allowed": {
+			client:      Client{Retries: 3, RetryNonIdempotent: true},
+			request:     Request{Method: http.MethodPost, Body: `{"key":"value"}`},
+			failures:    1,
+			failStatus:  http.StatusBadGateway,
+			wantStatus:  http.StatusOK,
+			wantCalls:   2,
+			wantRetries: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := calls.Add(1)
+				if r.Method == http.MethodPost {
+					var body [64]byte
+					k, _ := r.Body.Read(body[:])
+					if string(body[:k]) != test.request.Body {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+				}
+				if n <= int64(test.failures) {
+					if test.retryAfter != "" {
+						w.Header().Set("Retry-After", test.retryAfter)
+					}
+					w.WriteHeader(test.failStatus)
+					return
+				}
+			}))
+			defer srv.Close()
+
+			if test.client.RetryBackoff.Duration == 0 {
+				test.client.RetryBackoff.Duration = time.Millisecond
+			}
+			client, err := NewHTTPClient(test.client)
+			require.NoError(t, err)
+
+			test.request.URL = srv.URL
+			req, err := NewHTTPRequest(test.request)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, test.wantStatus, resp.StatusCode)
+			assert.Equal(t, test.wantCalls, calls.Load())
+			assert.Equal(t, test.wantRetries, RetriesPerformed(client))
+		})
+	}
+}
+
+func TestNewHTTPClient_RetriesConnectionError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	// the first connection is reset, the next ones are served
+	var conns atomic.Int64
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}),
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew && conns.Add(1) == 1 {
+				_ = conn.(*net.TCPConn).SetLinger(0)
+				_ = conn.Close()
+			}
+		},
+	}
+	go func() { _ = srv.Serve(ln) }()
+	defer func() { _ = srv.Close() }()
+
+	client, err := NewHTTPClient(Client{Retries: 2, RetryBackoff: Duration{Duration: time.Millisecond}})
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://" + addr)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(1), RetriesPerformed(client))
+}
+
+func TestNewHTTPClient_RetriesWithinTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(time.Millisecond * 100)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client, err := NewHTTPClient(Client{
+		Timeout:      Duration{Duration: time.Millisecond * 500},
+		Retries:      100,
+		RetryBackoff: Duration{Duration: time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	resp, err := client.Get(srv.URL)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+
+	assert.Less(t, time.Since(now), time.Millisecond*800, "the retries extend the timeout")
+	assert.Less(t, RetriesPerformed(client), int64(10))
+}
+
+func TestNewHTTPClient_RetriesInvalidConfig(t *testing.T) {
+	_, err := NewHTTPClient(Client{Retries: -1})
+	assert.Error(t, err)
+
+	_, err = NewHTTPClient(Client{Retries: 1, RetryOn: []string{"4xx"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+)
+
+const (
+	unixSocketScheme = "unix://"
+	// unixSocketHostSuffix marks the request URL hosts that are the encoded unix socket paths.
+	// Every socket gets its own host, so the transport doesn't share connections between sockets.
+	unixSocketHostSuffix = ".unix-socket"
+)
+
+var errUnixSocketTLS = errors.New("TLS options are not supported for unix socket connections")
+
+// unixSocketHTTPURL converts 'unix:///path/to/socket:/request/path' into the HTTP URL to use for the request,
+// the URL host is the encoded socket path.
+func unixSocketHTTPURL(rawURL string) (string, bool) {
+	if !strings.HasPrefix(rawURL, unixSocketScheme) {
+		return "", false
+	}
+
+	socket, path := strings.TrimPrefix(rawURL, unixSocketScheme), "/"
+	if i := strings.Index(socket, ":/"); i != -1 {
+		socket, path = socket[:i], socket[i+1:]
+	}
+	if socket == "" {
+		return "", false
+	}
+
+	return "http://" + hex.EncodeToString([]byte(socket)) + unixSocketHostSuffix + path, true
+}
+
+// unixSocketFromAddr returns the socket path encoded in the dialed address by unixSocketHTTPURL.
+func unixSocketFromAddr(addr string) (string, bool) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || !strings.HasSuffix(host, unixSocketHostSuffix) {
+		return "", false
+	}
+	bs, err := hex.DecodeString(strings.TrimSuffix(host, unixSocketHostSuffix))
+	if err != nil {
+		return "", false
+	}
+	return string(bs), true
+}
+
+func isUnixSocketHost(host string) bool {
+	return strings.HasSuffix(host, unixSocketHostSuffix)
+}
+
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// newDialContext returns the transport DialContext that connects to the unix socket if it is set in the client
+// config or in the request URL, and to the address otherwise.
+func newDialContext(d *net.Dialer, unixSocket string, tls bool) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		socket := unixSocket
+		if socket == "" {
+			socket, _ = unixSocketFromAddr(addr)
+		}
+		if socket == "" {
+			return d.DialContext(ctx, network, addr)
+		}
+		if tls {
+			return nil, errUnixSocketTLS
+		}
+		return d.DialContext(ctx, "unix", socket)
+	}
+}
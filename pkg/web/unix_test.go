@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package web
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/netdata/go.d.plugin/pkg/tlscfg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_UnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "web")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	socket1 := newTestUnixServer(t, filepath.Join(dir, "1.sock"), "server1")
+	socket2 := newTestUnixServer(t, filepath.Join(dir, "2.sock"), "server2")
+
+	tests := map[string]struct {
+		client   Client
+		request  Request
+		wantBody string
+		wantErr  bool
+	}{
+		"socket in the URL": {
+			request:  Request{URL: "unix://" + socket1 + ":/v1.43/info?all=1"},
+			wantBody: "server1 localhost /v1.43/info?all=1",
+		},
+		"socket in the URL, another socket": {
+			request:  Request{URL: "unix://" + socket2 + ":/v1.43/info"},
+			wantBody: "server2 localhost /v1.43/info",
+		},
+		"socket in the URL, no path": {
+			request:  Request{URL: "unix://" + socket1},
+			wantBody: "server1 localhost /",
+		},
+		"socket in the URL, proxy is ignored": {
+			client:   Client{ProxyURL: "http://127.0.0.1:1"},
+			request:  Request{URL: "unix://" + socket1 + ":/info"},
+			wantBody: "server1 localhost /info",
+		},
+		"socket in the URL, TLS options": {
+			client:  Client{TLSConfig: tlscfg.TLSConfig{InsecureSkipVerify: true}},
+			request: Request{URL: "unix://" + socket1 + ":/info"},
+			wantErr: true,
+		},
+		"socket in the config": {
+			client:   Client{UnixSocket: socket2},
+			request:  Request{URL: "http://docker/v1.43/info"},
+			wantBody: "server2 docker /v1.43/info",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewHTTPClient(test.client)
+			require.NoError(t, err)
+
+			req, err := NewHTTPRequest(test.request)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantBody, string(body))
+		})
+	}
+}
+
+func TestNewHTTPClient_UnixSocketTLS(t *testing.T) {
+	_, err := NewHTTPClient(Client{UnixSocket: "/run/docker.sock", TLSConfig: tlscfg.TLSConfig{InsecureSkipVerify: true}})
+	assert.ErrorIs(t, err, errUnixSocketTLS)
+}
+
+func newTestUnixServer(t *testing.T, socket, name string) string {
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(name + " " + r.Host + " " + r.URL.RequestURI()))
+	}))
+	_ = srv.Listener.Close()
+	srv.Listener = ln
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	return socket
+}